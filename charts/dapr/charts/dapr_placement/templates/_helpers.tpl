@@ -32,8 +32,21 @@ Create chart name and version as used by the chart label.
 {{- end -}}
 
 {{/*
-Create initial cluster peer list.
+Create the name of the stateful set and service of a placement shard.
+*/}}
+{{- define "dapr_placement.shardname" -}}
+{{- if gt .shardCount 1 -}}
+{{- printf "dapr-placement-server-shard-%d" .shardID -}}
+{{- else -}}
+{{- print "dapr-placement-server" -}}
+{{- end -}}
+{{- end -}}
+
+{{/*
+Create initial cluster peer list of the placement cluster of the given name.
 */}}
 {{- define "dapr_placement.initialcluster" -}}
-{{- print "dapr-placement-server-0=dapr-placement-server-0.dapr-placement-server." .Release.Namespace ".svc" .Values.global.dnsSuffix ":" .Values.ports.raftRPCPort ",dapr-placement-server-1=dapr-placement-server-1.dapr-placement-server." .Release.Namespace ".svc" .Values.global.dnsSuffix ":" .Values.ports.raftRPCPort ",dapr-placement-server-2=dapr-placement-server-2.dapr-placement-server." .Release.Namespace ".svc" .Values.global.dnsSuffix ":" .Values.ports.raftRPCPort -}}
+{{- $root := .root -}}
+{{- $name := .name -}}
+{{- print $name "-0=" $name "-0." $name "." $root.Release.Namespace ".svc" $root.Values.global.dnsSuffix ":" $root.Values.ports.raftRPCPort "," $name "-1=" $name "-1." $name "." $root.Release.Namespace ".svc" $root.Values.global.dnsSuffix ":" $root.Values.ports.raftRPCPort "," $name "-2=" $name "-2." $name "." $root.Release.Namespace ".svc" $root.Values.global.dnsSuffix ":" $root.Values.ports.raftRPCPort -}}
 {{- end -}}
@@ -0,0 +1,22 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package crds embeds the CustomResourceDefinitions of the chart, so they can be installed without Helm.
+package crds
+
+import "embed"
+
+// FS holds the CustomResourceDefinitions, one per YAML file.
+//
+//go:embed *.yaml
+var FS embed.FS
@@ -30,3 +30,42 @@ Create chart name and version as used by the chart label.
 {{- define "k8s_operator.chart" -}}
 {{- printf "%s-%s" .Chart.Name .Chart.Version | replace "+" "_" | trunc 63 | trimSuffix "-" -}}
 {{- end -}}
+
+{{/*
+Suffix of the names of the cluster-scoped resources of the control plane. They're suffixed by the namespace of the
+control plane when it watches some namespaces, so the control planes of a cluster don't share them.
+*/}}
+{{- define "dapr.clusterScopedSuffix" -}}
+{{- if .Values.global.watchNamespaces -}}
+-{{ .Release.Namespace }}
+{{- end -}}
+{{- end -}}
+
+{{/*
+Namespaces watched by the control plane, along with its own namespace.
+*/}}
+{{- define "dapr.watchedNamespaces" -}}
+{{- $namespaces := list .Release.Namespace -}}
+{{- range .Values.global.watchNamespaces -}}
+{{- if ne . $.Release.Namespace -}}
+{{- $namespaces = append $namespaces . -}}
+{{- end -}}
+{{- end -}}
+{{- join "," $namespaces -}}
+{{- end -}}
+
+{{/*
+Namespace selector of the webhooks of the control plane, which only admit the resources of the watched namespaces.
+*/}}
+{{- define "dapr.webhookNamespaceSelector" -}}
+{{- if .Values.global.watchNamespaces -}}
+namespaceSelector:
+  matchExpressions:
+  - key: kubernetes.io/metadata.name
+    operator: In
+    values:
+{{- range splitList "," (include "dapr.watchedNamespaces" .) }}
+    - {{ . }}
+{{- end }}
+{{- end -}}
+{{- end -}}
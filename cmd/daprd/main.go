@@ -37,9 +37,9 @@ import (
 	"github.com/dapr/components-contrib/secretstores/hashicorp/vault"
 	secretstore_kubernetes "github.com/dapr/components-contrib/secretstores/kubernetes"
 	secretstore_env "github.com/dapr/components-contrib/secretstores/local/env"
-	secretstore_file "github.com/dapr/components-contrib/secretstores/local/file"
 
 	secretstores_loader "github.com/dapr/dapr/pkg/components/secretstores"
+	secretstores_localfile "github.com/dapr/dapr/pkg/components/secretstores/localfile"
 
 	// State Stores.
 	"github.com/dapr/components-contrib/state"
@@ -90,6 +90,8 @@ import (
 	nr_mdns "github.com/dapr/components-contrib/nameresolution/mdns"
 
 	nr_loader "github.com/dapr/dapr/pkg/components/nameresolution"
+	nr_consulhealth "github.com/dapr/dapr/pkg/components/nameresolution/consulhealth"
+	nr_dnssrv "github.com/dapr/dapr/pkg/components/nameresolution/dnssrv"
 
 	// Bindings.
 	"github.com/dapr/components-contrib/bindings"
@@ -119,7 +121,6 @@ import (
 	"github.com/dapr/components-contrib/bindings/influx"
 	"github.com/dapr/components-contrib/bindings/kafka"
 	"github.com/dapr/components-contrib/bindings/kubernetes"
-	"github.com/dapr/components-contrib/bindings/localstorage"
 	"github.com/dapr/components-contrib/bindings/mqtt"
 	"github.com/dapr/components-contrib/bindings/mysql"
 	"github.com/dapr/components-contrib/bindings/postgres"
@@ -135,6 +136,7 @@ import (
 	bindings_zeebe_jobworker "github.com/dapr/components-contrib/bindings/zeebe/jobworker"
 
 	bindings_loader "github.com/dapr/dapr/pkg/components/bindings"
+	bindings_localstorage "github.com/dapr/dapr/pkg/components/bindings/localstorage"
 
 	// HTTP Middleware.
 
@@ -148,6 +150,8 @@ import (
 
 	http_middleware_loader "github.com/dapr/dapr/pkg/components/middleware/http"
 	http_middleware "github.com/dapr/dapr/pkg/middleware/http"
+	"github.com/dapr/dapr/pkg/middleware/http/tokenrelay"
+	"github.com/dapr/dapr/pkg/middleware/http/transform"
 
 	"github.com/dapr/components-contrib/configuration"
 	configuration_redis "github.com/dapr/components-contrib/configuration/redis"
@@ -188,7 +192,7 @@ func main() {
 				return gcp_secretmanager.NewSecreteManager(logContrib)
 			}),
 			secretstores_loader.New("local.file", func() secretstores.SecretStore {
-				return secretstore_file.NewLocalSecretStore(logContrib)
+				return secretstores_localfile.NewLocalFileSecretStore(logContrib)
 			}),
 			secretstores_loader.New("local.env", func() secretstores.SecretStore {
 				return secretstore_env.NewEnvSecretStore(logContrib)
@@ -310,6 +314,12 @@ func main() {
 			nr_loader.New("consul", func() nr.Resolver {
 				return nr_consul.NewResolver(logContrib)
 			}),
+			nr_loader.New("consul.health", func() nr.Resolver {
+				return nr_consulhealth.NewResolver(logContrib)
+			}),
+			nr_loader.New("dns.srv", func() nr.Resolver {
+				return nr_dnssrv.NewResolver(logContrib)
+			}),
 		),
 		runtime.WithInputBindings(
 			bindings_loader.NewInput("aws.sqs", func() bindings.InputBinding {
@@ -435,7 +445,7 @@ func main() {
 				return kafka.NewKafka(logContrib)
 			}),
 			bindings_loader.NewOutput("localstorage", func() bindings.OutputBinding {
-				return localstorage.NewLocalStorage(logContrib)
+				return bindings_localstorage.NewLocalStorage(logContrib)
 			}),
 			bindings_loader.NewOutput("mqtt", func() bindings.OutputBinding {
 				return mqtt.NewMQTT(logContrib)
@@ -502,6 +512,12 @@ func main() {
 			http_middleware_loader.New("sentinel", func(metadata middleware.Metadata) (http_middleware.Middleware, error) {
 				return sentinel.NewMiddleware(log).GetHandler(metadata)
 			}),
+			http_middleware_loader.New("transform", func(metadata middleware.Metadata) (http_middleware.Middleware, error) {
+				return transform.NewTransformMiddleware(log).GetHandler(metadata)
+			}),
+			http_middleware_loader.New("tokenrelay", func(metadata middleware.Metadata) (http_middleware.Middleware, error) {
+				return tokenrelay.NewTokenRelayMiddleware(log).GetHandler(metadata)
+			}),
 		),
 	)
 	if err != nil {
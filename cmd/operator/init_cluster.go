@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/dapr/dapr/pkg/operator/bootstrap"
+	"github.com/dapr/dapr/pkg/version"
+)
+
+const (
+	initClusterCommand = "init-cluster"
+
+	defaultNamespace = "dapr-system"
+)
+
+// initCluster installs or updates the CRDs, the webhooks and the RBAC of the control plane without Helm.
+func initCluster(args []string) error {
+	flags := flag.NewFlagSet(initClusterCommand, flag.ExitOnError)
+	opts := bootstrap.Options{Version: version.Version()}
+	var injectorFailurePolicy, validationFailurePolicy, watchNamespaces string
+	var timeout time.Duration
+
+	namespace := os.Getenv("NAMESPACE")
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	flags.StringVar(&opts.Namespace, "namespace", namespace, "Namespace of the control plane")
+	flags.BoolVar(&opts.Force, "force", false, "Skip the version skew checks of the installed resources")
+	flags.StringVar(&injectorFailurePolicy, "injector-failure-policy", string(admissionregistrationv1.Ignore), "Failure policy of the sidecar injector webhook, Ignore or Fail")
+	flags.BoolVar(&opts.ValidationWebhook, "validation-webhook", true, "Install the webhook validating the Dapr resources")
+	flags.StringVar(&validationFailurePolicy, "validation-failure-policy", string(admissionregistrationv1.Ignore), "Failure policy of the validating webhook, Ignore or Fail")
+	flags.StringVar(&watchNamespaces, "watch-namespaces", "", "Comma separated namespaces watched by the control plane, which is granted roles in these namespaces only. All namespaces are watched if empty")
+	flags.DurationVar(&timeout, "timeout", time.Minute, "Timeout of the installation")
+	flags.Parse(args)
+
+	for _, policy := range []string{injectorFailurePolicy, validationFailurePolicy} {
+		if policy != string(admissionregistrationv1.Ignore) && policy != string(admissionregistrationv1.Fail) {
+			return errors.Errorf("invalid webhook failure policy %s, must be Ignore or Fail", policy)
+		}
+	}
+	opts.InjectorFailurePolicy = admissionregistrationv1.FailurePolicyType(injectorFailurePolicy)
+	opts.ValidationFailurePolicy = admissionregistrationv1.FailurePolicyType(validationFailurePolicy)
+	if watchNamespaces != "" {
+		opts.WatchNamespaces = strings.Split(watchNamespaces, ",")
+	}
+
+	conf, err := ctrl.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "unable to get controller runtime configuration")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return bootstrap.InitClusterForConfig(ctx, conf, opts)
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/dapr/dapr/pkg/metrics"
 	"github.com/dapr/dapr/pkg/operator"
 	"github.com/dapr/dapr/pkg/operator/monitoring"
+	"github.com/dapr/dapr/pkg/operator/validation"
 	"github.com/dapr/dapr/pkg/signals"
 	"github.com/dapr/dapr/pkg/version"
 )
@@ -33,6 +34,8 @@ var (
 	config                string
 	certChainPath         string
 	disableLeaderElection bool
+	watchNamespaces       string
+	validationMode        string
 )
 
 const (
@@ -43,12 +46,19 @@ const (
 )
 
 func main() {
+	if flag.Arg(0) == initClusterCommand {
+		if err := initCluster(flag.Args()[1:]); err != nil {
+			log.Fatalf("unable to initialize the cluster: %s", err)
+		}
+		return
+	}
+
 	log.Infof("starting Dapr Operator -- version %s -- commit %s", version.Version(), version.Commit())
 
 	ctx := signals.Context()
-	go operator.NewOperator(config, certChainPath, !disableLeaderElection).Run(ctx)
+	go operator.NewOperator(config, certChainPath, !disableLeaderElection, watchNamespaces).Run(ctx)
 	// The webhooks use their own controller context and stops on SIGTERM and SIGINT.
-	go operator.RunWebhooks(!disableLeaderElection)
+	go operator.RunWebhooks(!disableLeaderElection, validationMode)
 
 	<-ctx.Done() // Wait for SIGTERM and SIGINT.
 
@@ -73,6 +83,8 @@ func init() {
 	flag.StringVar(&certChainPath, "certchain", defaultCredentialsPath, "Path to the credentials directory holding the cert chain")
 
 	flag.BoolVar(&disableLeaderElection, "disable-leader-election", false, "Disable leader election for controller manager. ")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "", "Comma separated namespaces watched by the operator, along with its own namespace. All namespaces are watched if empty")
+	flag.StringVar(&validationMode, "validation-mode", validation.ModeWarn, "Mode of the validating webhook: warn admits the invalid resources with warnings, deny rejects them")
 
 	flag.Parse()
 
@@ -83,6 +95,11 @@ func init() {
 		log.Infof("log level set to: %s", loggerOptions.OutputLevel)
 	}
 
+	// The subcommands don't serve metrics.
+	if flag.NArg() > 0 {
+		return
+	}
+
 	// Initialize dapr metrics exporter
 	if err := metricsExporter.Init(); err != nil {
 		log.Fatal(err)
@@ -46,6 +46,10 @@ type config struct {
 
 	replicationFactor int
 
+	// Placement table sharding configurations
+	shardID    int
+	shardCount int
+
 	// Log and metrics configurations
 	loggerOptions   logger.Options
 	metricsExporter metrics.Exporter
@@ -75,6 +79,8 @@ func newConfig() *config {
 	flag.StringVar(&cfg.certChainPath, "certchain", cfg.certChainPath, "Path to the credentials directory holding the cert chain")
 	flag.BoolVar(&cfg.tlsEnabled, "tls-enabled", cfg.tlsEnabled, "Should TLS be enabled for the placement gRPC server")
 	flag.IntVar(&cfg.replicationFactor, "replicationFactor", defaultReplicationFactor, "sets the replication factor for actor distribution on vnodes")
+	flag.IntVar(&cfg.shardID, "shard-id", 0, "sets the shard of the actor placement tables owned by this placement cluster")
+	flag.IntVar(&cfg.shardCount, "shard-count", 1, "sets the number of placement clusters the actor placement tables are sharded across by actor type")
 
 	cfg.loggerOptions = logger.DefaultOptions()
 	cfg.loggerOptions.AttachCmdFlags(flag.StringVar, flag.BoolVar)
@@ -70,6 +70,13 @@ func main() {
 	// Start Placement gRPC server.
 	hashing.SetReplicationFactor(cfg.replicationFactor)
 	apiServer := placement.NewPlacementService(raftServer)
+	if cfg.shardCount > 1 {
+		if cfg.shardID < 0 || cfg.shardID >= cfg.shardCount {
+			log.Fatalf("shard id %d is out of range for %d shards", cfg.shardID, cfg.shardCount)
+		}
+		apiServer.SetShard(cfg.shardID, cfg.shardCount)
+		log.Infof("placement tables sharded by actor type, shard %d of %d", cfg.shardID, cfg.shardCount)
+	}
 	var certChain *credentials.CertChain
 	if cfg.tlsEnabled {
 		certChain = loadCertChains(cfg.certChainPath)
@@ -18,11 +18,13 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/dapr/kit/logger"
 
+	"github.com/dapr/dapr/pkg/audit"
 	"github.com/dapr/dapr/pkg/credentials"
 	"github.com/dapr/dapr/pkg/fswatcher"
 	"github.com/dapr/dapr/pkg/health"
@@ -48,6 +50,17 @@ func main() {
 	configName := flag.String("config", defaultDaprSystemConfigName, "Path to config file, or name of a configuration object")
 	credsPath := flag.String("issuer-credentials", defaultCredentialsPath, "Path to the credentials directory holding the issuer data")
 	trustDomain := flag.String("trust-domain", "localhost", "The CA trust domain")
+	caStore := flag.String("ca-store", "", "The external CA store signing the issuer cert: vault, awspca or certmanager. The issuer cert is self signed if empty")
+	issuerCertTTL := flag.Duration("issuer-cert-ttl", 0, "The lifetime of the issuer cert requested from the CA store")
+	vaultAddress := flag.String("vault-address", "", "The address of the Vault server")
+	vaultTokenPath := flag.String("vault-token-path", "", "Path to the file holding the Vault token")
+	vaultPKIPath := flag.String("vault-pki-path", "", "The mount path of the Vault PKI secrets engine")
+	awsPCAArn := flag.String("aws-pca-arn", "", "The ARN of the AWS Private CA")
+	awsRegion := flag.String("aws-region", "", "The AWS region of the AWS Private CA")
+	certManagerIssuerName := flag.String("cert-manager-issuer-name", "", "The name of the cert-manager issuer")
+	certManagerIssuerKind := flag.String("cert-manager-issuer-kind", "", "The kind of the cert-manager issuer")
+	certManagerIssuerGroup := flag.String("cert-manager-issuer-group", "", "The group of the cert-manager issuer")
+	watchNamespaces := flag.String("watch-namespaces", "", "Comma separated namespaces of the pods issued certificates, along with the namespace of sentry. Certificates are issued to all namespaces if empty")
 
 	loggerOptions := logger.DefaultOptions()
 	loggerOptions.AttachCmdFlags(flag.StringVar, flag.BoolVar)
@@ -90,6 +103,26 @@ func main() {
 	config.IssuerKeyPath = issuerKeyPath
 	config.RootCertPath = rootCertPath
 	config.TrustDomain = *trustDomain
+	config.CAStore = *caStore
+	if *issuerCertTTL > 0 {
+		config.IssuerCertTTL = *issuerCertTTL
+	}
+	config.Vault.Address = *vaultAddress
+	config.Vault.TokenPath = *vaultTokenPath
+	config.Vault.PKIPath = *vaultPKIPath
+	config.AWSPCA.CAArn = *awsPCAArn
+	config.AWSPCA.Region = *awsRegion
+	config.CertManager.IssuerName = *certManagerIssuerName
+	config.CertManager.IssuerKind = *certManagerIssuerKind
+	config.CertManager.IssuerGroup = *certManagerIssuerGroup
+	if *watchNamespaces != "" {
+		config.WatchNamespaces = strings.Split(*watchNamespaces, ",")
+	}
+
+	if err = audit.Default.Init(audit.SourceSentry, "", config.Audit, nil); err != nil {
+		log.Errorf("failed to initialize audit log: %s", err)
+	}
+	defer audit.Default.Close()
 
 	watchDir := filepath.Dir(config.IssuerCertPath)
 
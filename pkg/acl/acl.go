@@ -18,6 +18,8 @@ import (
 	"encoding/asn1"
 	"errors"
 	"fmt"
+	"net/url"
+	"regexp"
 	"strings"
 
 	"github.com/PuerkitoBio/purell"
@@ -26,6 +28,7 @@ import (
 
 	"github.com/dapr/kit/logger"
 
+	"github.com/dapr/dapr/pkg/audit"
 	"github.com/dapr/dapr/pkg/config"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
 	commonv1pb "github.com/dapr/dapr/pkg/proto/common/v1"
@@ -47,11 +50,21 @@ func ParseAccessControlSpec(accessControlSpec config.AccessControlSpec, protocol
 	accessControlList.PolicySpec = make(map[string]config.AccessControlListPolicySpec)
 	accessControlList.DefaultAction = strings.ToLower(accessControlSpec.DefaultAction)
 
+	accessControlList.TrustedGateways = accessControlSpec.TrustedGateways
 	accessControlList.TrustDomain = accessControlSpec.TrustDomain
 	if accessControlSpec.TrustDomain == "" {
 		accessControlList.TrustDomain = config.DefaultTrustDomain
 	}
 
+	switch strings.ToLower(accessControlSpec.Mode) {
+	case "", config.AccessControlModeEnforce:
+	case config.AccessControlModeAudit:
+		log.Warnf("Access control policies are in audit mode. Requests denied by the policies are logged but not rejected")
+		accessControlList.AuditMode = true
+	default:
+		return nil, fmt.Errorf("invalid access control mode: %s", accessControlSpec.Mode)
+	}
+
 	accessControlList.DefaultAction = accessControlSpec.DefaultAction
 	if accessControlSpec.DefaultAction == "" {
 		if accessControlSpec.AppPolicies == nil || len(accessControlSpec.AppPolicies) > 0 {
@@ -88,9 +101,21 @@ func ParseAccessControlSpec(accessControlSpec config.AccessControlSpec, protocol
 		}
 
 		operationPolicy := make(map[string]config.AccessControlListOperationAction)
+		var patternOperationPolicy []config.AccessControlListPatternOperationAction
 
 		// Iterate over all the operations and create a map for fast lookup
 		for _, appPolicy := range appPolicySpec.AppOperationActions {
+			match := strings.ToLower(appPolicy.Match)
+			if (match != "" && match != config.OperationMatchPrefix) || len(appPolicy.Query) > 0 {
+				// Operations matched with a pattern or conditioned on the query string can't be looked up by prefix.
+				patternOperation, err := getPatternOperationAction(appPolicy, match, protocol)
+				if err != nil {
+					return nil, fmt.Errorf("invalid access control spec. invalid operation %s for app %s: %w", appPolicy.Operation, appPolicySpec.AppName, err)
+				}
+				patternOperationPolicy = append(patternOperationPolicy, patternOperation)
+				continue
+			}
+
 			// The operation name might be specified as /invoke/*
 			// Store the prefix as the key and use the remainder as post fix for faster lookups
 			// Also, prepend "/" in case it is missing in the operation name
@@ -121,11 +146,12 @@ func ParseAccessControlSpec(accessControlSpec config.AccessControlSpec, protocol
 			operationPolicy[operationPrefix] = operationActions
 		}
 		aclPolicySpec := config.AccessControlListPolicySpec{
-			AppName:             appPolicySpec.AppName,
-			DefaultAction:       appPolicySpec.DefaultAction,
-			TrustDomain:         appPolicySpec.TrustDomain,
-			Namespace:           appPolicySpec.Namespace,
-			AppOperationActions: operationPolicy,
+			AppName:                 appPolicySpec.AppName,
+			DefaultAction:           appPolicySpec.DefaultAction,
+			TrustDomain:             appPolicySpec.TrustDomain,
+			Namespace:               appPolicySpec.Namespace,
+			AppOperationActions:     operationPolicy,
+			PatternOperationActions: patternOperationPolicy,
 		}
 
 		// The policy spec can have the same appID which belongs to different namespaces
@@ -238,13 +264,37 @@ func normalizeOperation(operation string) (string, error) {
 	return s, nil
 }
 
-func ApplyAccessControlPolicies(ctx context.Context, operation string, httpVerb commonv1pb.HTTPExtension_Verb, appProtocol string, acl *config.AccessControlList) (bool, string) {
+func ApplyAccessControlPolicies(ctx context.Context, operation string, httpVerb commonv1pb.HTTPExtension_Verb, query string, appProtocol string, acl *config.AccessControlList) (bool, string) {
 	// Apply access control list filter
 	spiffeID, err := GetAndParseSpiffeID(ctx)
 	if err != nil {
 		// Apply the default action
 		log.Debugf("error while reading spiffe id from client cert: %v. applying default global policy action", err.Error())
 	}
+	return ApplyAccessControlPoliciesToCaller(spiffeID, operation, httpVerb, query, appProtocol, acl)
+}
+
+// GetCallerSpiffeID returns the SPIFFE ID of the caller the access control policies apply to.
+// A gateway forwarding an invocation from a remote cluster asserts the SPIFFE ID of the original caller, which is only
+// accepted when the gateway is one of the trusted gateways of the access control list. Otherwise the SPIFFE ID of the
+// peer applies, so that the gateway can't be used to reach the apps with its own identity.
+func GetCallerSpiffeID(ctx context.Context, asserted string, acl *config.AccessControlList) (*config.SpiffeID, error) {
+	spiffeID, err := GetAndParseSpiffeID(ctx)
+	if err != nil || asserted == "" || acl == nil {
+		return spiffeID, err
+	}
+
+	for _, gateway := range acl.TrustedGateways {
+		if gateway == spiffeID.String() {
+			return parseSpiffeID(asserted)
+		}
+	}
+	return spiffeID, nil
+}
+
+// ApplyAccessControlPoliciesToCaller applies the access control policies to an operation invoked by the caller with
+// the given SPIFFE ID. A nil SPIFFE ID gets the default global policy action.
+func ApplyAccessControlPoliciesToCaller(spiffeID *config.SpiffeID, operation string, httpVerb commonv1pb.HTTPExtension_Verb, query string, appProtocol string, acl *config.AccessControlList) (bool, string) {
 	var appID, trustDomain, namespace string
 	if spiffeID != nil {
 		appID = spiffeID.AppID
@@ -252,7 +302,7 @@ func ApplyAccessControlPolicies(ctx context.Context, operation string, httpVerb
 		trustDomain = spiffeID.TrustDomain
 	}
 
-	operation, err = normalizeOperation(operation)
+	operation, err := normalizeOperation(operation)
 	var errMessage string
 
 	if err != nil {
@@ -261,11 +311,31 @@ func ApplyAccessControlPolicies(ctx context.Context, operation string, httpVerb
 		return false, errMessage
 	}
 
-	action, actionPolicy := IsOperationAllowedByAccessControlPolicy(spiffeID, appID, operation, httpVerb, appProtocol, acl)
+	action, actionPolicy := IsOperationAllowedByAccessControlPolicy(spiffeID, appID, operation, httpVerb, query, appProtocol, acl)
 	emitACLMetrics(actionPolicy, appID, trustDomain, namespace, operation, httpVerb.String(), action)
 
 	if !action {
 		errMessage = fmt.Sprintf("access control policy has denied access to appid: %s operation: %s verb: %s", appID, operation, httpVerb)
+		reason := "denied by the access control policy"
+		if acl.AuditMode {
+			reason = "denied by the access control policy in audit mode"
+		}
+		subject := appID
+		if spiffeID != nil {
+			subject = spiffeID.String()
+		}
+		audit.Default.Emit(audit.Event{
+			Type:     audit.EventAccessDenied,
+			Subject:  subject,
+			Resource: fmt.Sprintf("%s %s", httpVerb, operation),
+			Reason:   reason,
+		})
+
+		if acl.AuditMode {
+			// The denial is only reported, the request is let through.
+			log.Warnf("audit mode: %s", errMessage)
+			return true, ""
+		}
 		log.Debugf(errMessage)
 	}
 
@@ -291,7 +361,7 @@ func emitACLMetrics(actionPolicy, appID, trustDomain, namespace, operation, verb
 }
 
 // IsOperationAllowedByAccessControlPolicy determines if access control policies allow the operation on the target app.
-func IsOperationAllowedByAccessControlPolicy(spiffeID *config.SpiffeID, srcAppID string, inputOperation string, httpVerb commonv1pb.HTTPExtension_Verb, appProtocol string, accessControlList *config.AccessControlList) (bool, string) {
+func IsOperationAllowedByAccessControlPolicy(spiffeID *config.SpiffeID, srcAppID string, inputOperation string, httpVerb commonv1pb.HTTPExtension_Verb, query string, appProtocol string, accessControlList *config.AccessControlList) (bool, string) {
 	if accessControlList == nil {
 		// No access control list is provided. Do nothing
 		return isActionAllowed(config.AllowAccess), ""
@@ -342,6 +412,19 @@ func IsOperationAllowedByAccessControlPolicy(spiffeID *config.SpiffeID, srcAppID
 		inputOperation = "/" + inputOperation
 	}
 
+	// Operations matched with a pattern or query conditions take precedence, in the order they are specified.
+	// The operations which don't list the verb of the request are skipped, so another operation with the same
+	// pattern may list it.
+	for _, patternPolicy := range appPolicy.PatternOperationActions {
+		if !isVerbListed(patternPolicy.VerbAction, httpVerb, appProtocol) {
+			continue
+		}
+		if patternPolicy.Pattern.MatchString(inputOperation) && isQueryMatched(patternPolicy.Query, query) {
+			action = getOperationAction(patternPolicy.VerbAction, patternPolicy.OperationAction, appPolicy.DefaultAction, action, httpVerb, appProtocol)
+			return isActionAllowed(action), actionPolicy
+		}
+	}
+
 	inputOperationPrefix, inputOperationPostfix := getOperationPrefixAndPostfix(inputOperation)
 
 	// If HTTP, make case-insensitive
@@ -367,30 +450,154 @@ func IsOperationAllowedByAccessControlPolicy(spiffeID *config.SpiffeID, srcAppID
 		}
 
 		// Operation prefix and postfix match. Now check the operation specific policy
-		if appProtocol == config.HTTPProtocol {
-			if httpVerb != commonv1pb.HTTPExtension_NONE {
-				verbAction, found := operationPolicy.VerbAction[httpVerb.String()]
+		action = getOperationAction(operationPolicy.VerbAction, operationPolicy.OperationAction, appPolicy.DefaultAction, action, httpVerb, appProtocol)
+	}
+
+	return isActionAllowed(action), actionPolicy
+}
+
+// getOperationAction returns the action of an operation matched by the access control policy of an app.
+func getOperationAction(verbActions map[string]string, operationAction, appDefaultAction, action string, httpVerb commonv1pb.HTTPExtension_Verb, appProtocol string) string {
+	if appProtocol == config.HTTPProtocol {
+		if httpVerb != commonv1pb.HTTPExtension_NONE {
+			verbAction, found := verbActions[httpVerb.String()]
+			if found {
+				// An action for a specific verb is matched
+				action = verbAction
+			} else {
+				verbAction, found = verbActions["*"]
 				if found {
-					// An action for a specific verb is matched
+					// The verb matched the wildcard "*"
 					action = verbAction
-				} else {
-					verbAction, found = operationPolicy.VerbAction["*"]
-					if found {
-						// The verb matched the wildcard "*"
-						action = verbAction
-					}
 				}
-			} else {
-				// No matching verb found in the operation specific policies.
-				action = appPolicy.DefaultAction
 			}
-		} else if appProtocol == config.GRPCProtocol {
-			// No http verb match is needed.
-			action = operationPolicy.OperationAction
+		} else {
+			// No matching verb found in the operation specific policies.
+			action = appDefaultAction
 		}
+	} else if appProtocol == config.GRPCProtocol {
+		// No http verb match is needed.
+		action = operationAction
 	}
+	return action
+}
 
-	return isActionAllowed(action), actionPolicy
+// isVerbListed returns true if the verb of an HTTP request is listed by the verb actions of an operation, or if the
+// request has no verb to match.
+func isVerbListed(verbActions map[string]string, httpVerb commonv1pb.HTTPExtension_Verb, appProtocol string) bool {
+	if appProtocol != config.HTTPProtocol || httpVerb == commonv1pb.HTTPExtension_NONE {
+		return true
+	}
+	_, found := verbActions[httpVerb.String()]
+	if !found {
+		_, found = verbActions["*"]
+	}
+	return found
+}
+
+// isQueryMatched returns true if the query string satisfies all the conditions.
+// A condition with the value "*" only requires the parameter to be present.
+func isQueryMatched(conditions map[string]string, query string) bool {
+	if len(conditions) == 0 {
+		return true
+	}
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return false
+	}
+
+	for key, expected := range conditions {
+		actual, ok := values[key]
+		if !ok {
+			return false
+		}
+		if expected == "*" {
+			continue
+		}
+
+		found := false
+		for _, v := range actual {
+			if v == expected {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// getPatternOperationAction compiles the pattern of an operation that can't be stored for a lookup by prefix.
+func getPatternOperationAction(appPolicy config.AppOperation, match, protocol string) (config.AccessControlListPatternOperationAction, error) {
+	operation := appPolicy.Operation
+	if match != config.OperationMatchRegex && !strings.HasPrefix(operation, "/") {
+		operation = "/" + operation
+	}
+
+	var expr string
+	switch match {
+	case "", config.OperationMatchPrefix:
+		if strings.Contains(operation, "/*") {
+			expr = "^" + regexp.QuoteMeta(strings.ReplaceAll(operation, "/*", "")) + ".*$"
+		} else {
+			expr = "^" + regexp.QuoteMeta(operation) + "$"
+		}
+	case config.OperationMatchGlob:
+		expr = globToRegexp(operation)
+	case config.OperationMatchRegex:
+		expr = operation
+	default:
+		return config.AccessControlListPatternOperationAction{}, fmt.Errorf("unknown match type %s", appPolicy.Match)
+	}
+
+	// Operations are case-insensitive for http
+	if protocol == config.HTTPProtocol {
+		expr = "(?i)" + expr
+	}
+
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return config.AccessControlListPatternOperationAction{}, err
+	}
+
+	verbActions := make(map[string]string)
+	for _, verb := range appPolicy.HTTPVerb {
+		verbActions[verb] = appPolicy.Action
+	}
+
+	return config.AccessControlListPatternOperationAction{
+		Pattern:         pattern,
+		Query:           appPolicy.Query,
+		VerbAction:      verbActions,
+		OperationAction: appPolicy.Action,
+	}, nil
+}
+
+// globToRegexp converts a glob pattern to a regular expression.
+// "*" matches any sequence of characters within a path segment, "**" matches across segments and "?" matches a single character.
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				sb.WriteString(".*")
+				i++
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return sb.String()
 }
 
 func isActionAllowed(action string) bool {
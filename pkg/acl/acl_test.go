@@ -14,9 +14,21 @@ limitations under the License.
 package acl
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 
 	"github.com/dapr/dapr/pkg/config"
 	"github.com/dapr/dapr/pkg/proto/common/v1"
@@ -370,7 +382,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns1",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op1", common.HTTPExtension_POST, config.HTTPProtocol, nil)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op1", common.HTTPExtension_POST, "", config.HTTPProtocol, nil)
 		// Action = Allow the operation since no ACL is defined
 		assert.True(t, isAllowed)
 	})
@@ -383,7 +395,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns1",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op1", common.HTTPExtension_POST, config.HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op1", common.HTTPExtension_POST, "", config.HTTPProtocol, accessControlList)
 		// Action = Default global action
 		assert.False(t, isAllowed)
 	})
@@ -396,7 +408,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns1",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op1", common.HTTPExtension_POST, config.HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op1", common.HTTPExtension_POST, "", config.HTTPProtocol, accessControlList)
 		// Action = Ignore policy and apply global default action
 		assert.False(t, isAllowed)
 	})
@@ -409,7 +421,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "abcd",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op1", common.HTTPExtension_POST, config.HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op1", common.HTTPExtension_POST, "", config.HTTPProtocol, accessControlList)
 		// Action = Ignore policy and apply global default action
 		assert.False(t, isAllowed)
 	})
@@ -417,7 +429,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 	t.Run("test when spiffe id is nil", func(t *testing.T) {
 		srcAppID := app1
 		accessControlList, _ := initializeAccessControlList(config.HTTPProtocol)
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(nil, srcAppID, "op1", common.HTTPExtension_POST, config.HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(nil, srcAppID, "op1", common.HTTPExtension_POST, "", config.HTTPProtocol, accessControlList)
 		// Action = Default global action
 		assert.False(t, isAllowed)
 	})
@@ -425,7 +437,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 	t.Run("test when src app id is empty", func(t *testing.T) {
 		srcAppID := ""
 		accessControlList, _ := initializeAccessControlList(config.HTTPProtocol)
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(nil, srcAppID, "op1", common.HTTPExtension_POST, config.HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(nil, srcAppID, "op1", common.HTTPExtension_POST, "", config.HTTPProtocol, accessControlList)
 		// Action = Default global action
 		assert.False(t, isAllowed)
 	})
@@ -438,7 +450,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns1",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "opX", common.HTTPExtension_POST, config.HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "opX", common.HTTPExtension_POST, "", config.HTTPProtocol, accessControlList)
 		// Action = Ignore policy and apply default action for app
 		assert.True(t, isAllowed)
 	})
@@ -451,7 +463,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns1",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "Op2", common.HTTPExtension_POST, config.HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "Op2", common.HTTPExtension_POST, "", config.HTTPProtocol, accessControlList)
 		// Action = Ignore policy and apply default action for app
 		assert.False(t, isAllowed)
 	})
@@ -464,7 +476,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns2",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op4", common.HTTPExtension_PUT, config.HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op4", common.HTTPExtension_PUT, "", config.HTTPProtocol, accessControlList)
 		// Action = Default action for the specific app
 		assert.False(t, isAllowed)
 	})
@@ -477,7 +489,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns1",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op5", common.HTTPExtension_PUT, config.HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op5", common.HTTPExtension_PUT, "", config.HTTPProtocol, accessControlList)
 		// Action = Global Default action
 		assert.False(t, isAllowed)
 	})
@@ -490,7 +502,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns1",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op2", common.HTTPExtension_PUT, config.HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op2", common.HTTPExtension_PUT, "", config.HTTPProtocol, accessControlList)
 		// Action = Default action for the specific verb
 		assert.False(t, isAllowed)
 	})
@@ -503,7 +515,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns2",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op4", common.HTTPExtension_POST, config.HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op4", common.HTTPExtension_POST, "", config.HTTPProtocol, accessControlList)
 		// Action = Default action for the specific verb
 		assert.True(t, isAllowed)
 	})
@@ -516,7 +528,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns2",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "/op4", common.HTTPExtension_POST, config.HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "/op4", common.HTTPExtension_POST, "", config.HTTPProtocol, accessControlList)
 		// Action = Default action for the specific verb
 		assert.True(t, isAllowed)
 	})
@@ -529,7 +541,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns2",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op4", common.HTTPExtension_NONE, config.HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op4", common.HTTPExtension_NONE, "", config.HTTPProtocol, accessControlList)
 		// Action = Default action for the app
 		assert.False(t, isAllowed)
 	})
@@ -542,7 +554,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns2",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "/op3/a", common.HTTPExtension_PUT, config.HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "/op3/a", common.HTTPExtension_PUT, "", config.HTTPProtocol, accessControlList)
 		// Action = Default action for the specific verb
 		assert.True(t, isAllowed)
 	})
@@ -555,7 +567,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns2",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "/OP4", common.HTTPExtension_NONE, config.GRPCProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "/OP4", common.HTTPExtension_NONE, "", config.GRPCProtocol, accessControlList)
 		// Action = Default action for the specific verb
 		assert.False(t, isAllowed)
 	})
@@ -568,7 +580,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns2",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "/op3/b/b", common.HTTPExtension_PUT, config.HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "/op3/b/b", common.HTTPExtension_PUT, "", config.HTTPProtocol, accessControlList)
 		// Action = Default action for the app
 		assert.False(t, isAllowed)
 	})
@@ -581,7 +593,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns2",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "/op3/a/b", common.HTTPExtension_PUT, config.HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "/op3/a/b", common.HTTPExtension_PUT, "", config.HTTPProtocol, accessControlList)
 		// Action = Default action for the app
 		assert.True(t, isAllowed)
 	})
@@ -594,12 +606,178 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns2",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op4", common.HTTPExtension_NONE, config.GRPCProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op4", common.HTTPExtension_NONE, "", config.GRPCProtocol, accessControlList)
 		// Action = Default action for the app
 		assert.True(t, isAllowed)
 	})
 }
 
+func TestPatternOperationPolicies(t *testing.T) {
+	spiffeID := config.SpiffeID{
+		TrustDomain: "public",
+		Namespace:   "ns1",
+		AppID:       app1,
+	}
+
+	newACL := func(t *testing.T, protocol string, operations ...config.AppOperation) *config.AccessControlList {
+		accessControlList, err := ParseAccessControlSpec(config.AccessControlSpec{
+			DefaultAction: config.DenyAccess,
+			AppPolicies: []config.AppPolicySpec{
+				{
+					AppName:             app1,
+					DefaultAction:       config.DenyAccess,
+					TrustDomain:         "public",
+					Namespace:           "ns1",
+					AppOperationActions: operations,
+				},
+			},
+		}, protocol)
+		assert.NoError(t, err)
+		return accessControlList
+	}
+
+	t.Run("glob operation", func(t *testing.T) {
+		accessControlList := newACL(t, config.HTTPProtocol, config.AppOperation{
+			Operation: "/orders/*/items",
+			Match:     config.OperationMatchGlob,
+			HTTPVerb:  []string{"GET"},
+			Action:    config.AllowAccess,
+		})
+
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, app1, "/orders/1/items", common.HTTPExtension_GET, "", config.HTTPProtocol, accessControlList)
+		assert.True(t, isAllowed)
+		isAllowed, _ = IsOperationAllowedByAccessControlPolicy(&spiffeID, app1, "/Orders/1/Items", common.HTTPExtension_GET, "", config.HTTPProtocol, accessControlList)
+		assert.True(t, isAllowed)
+		isAllowed, _ = IsOperationAllowedByAccessControlPolicy(&spiffeID, app1, "/orders/1/2/items", common.HTTPExtension_GET, "", config.HTTPProtocol, accessControlList)
+		assert.False(t, isAllowed)
+		isAllowed, _ = IsOperationAllowedByAccessControlPolicy(&spiffeID, app1, "/orders/1/items", common.HTTPExtension_DELETE, "", config.HTTPProtocol, accessControlList)
+		assert.False(t, isAllowed)
+	})
+
+	t.Run("double star glob operation", func(t *testing.T) {
+		accessControlList := newACL(t, config.GRPCProtocol, config.AppOperation{
+			Operation: "/orders/**",
+			Match:     config.OperationMatchGlob,
+			Action:    config.AllowAccess,
+		})
+
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, app1, "/orders/1/2/items", common.HTTPExtension_NONE, "", config.GRPCProtocol, accessControlList)
+		assert.True(t, isAllowed)
+		isAllowed, _ = IsOperationAllowedByAccessControlPolicy(&spiffeID, app1, "/customers/1", common.HTTPExtension_NONE, "", config.GRPCProtocol, accessControlList)
+		assert.False(t, isAllowed)
+	})
+
+	t.Run("regex operation takes precedence over prefix operation", func(t *testing.T) {
+		accessControlList := newACL(t, config.HTTPProtocol,
+			config.AppOperation{
+				Operation: "/admin/*",
+				HTTPVerb:  []string{"*"},
+				Action:    config.AllowAccess,
+			},
+			config.AppOperation{
+				Operation: `^/admin/users/[0-9]+$`,
+				Match:     config.OperationMatchRegex,
+				HTTPVerb:  []string{"*"},
+				Action:    config.DenyAccess,
+			})
+
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, app1, "/admin/users/42", common.HTTPExtension_POST, "", config.HTTPProtocol, accessControlList)
+		assert.False(t, isAllowed)
+		isAllowed, _ = IsOperationAllowedByAccessControlPolicy(&spiffeID, app1, "/admin/users/me", common.HTTPExtension_POST, "", config.HTTPProtocol, accessControlList)
+		assert.True(t, isAllowed)
+	})
+
+	t.Run("operations with the same pattern for different verbs", func(t *testing.T) {
+		accessControlList := newACL(t, config.HTTPProtocol,
+			config.AppOperation{
+				Operation: "/orders/*",
+				Match:     config.OperationMatchGlob,
+				HTTPVerb:  []string{"GET"},
+				Action:    config.AllowAccess,
+			},
+			config.AppOperation{
+				Operation: "/orders/*",
+				Match:     config.OperationMatchGlob,
+				HTTPVerb:  []string{"POST"},
+				Action:    config.AllowAccess,
+			})
+
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, app1, "/orders/1", common.HTTPExtension_GET, "", config.HTTPProtocol, accessControlList)
+		assert.True(t, isAllowed)
+		isAllowed, _ = IsOperationAllowedByAccessControlPolicy(&spiffeID, app1, "/orders/1", common.HTTPExtension_POST, "", config.HTTPProtocol, accessControlList)
+		assert.True(t, isAllowed)
+		isAllowed, _ = IsOperationAllowedByAccessControlPolicy(&spiffeID, app1, "/orders/1", common.HTTPExtension_DELETE, "", config.HTTPProtocol, accessControlList)
+		assert.False(t, isAllowed)
+	})
+
+	t.Run("query conditions", func(t *testing.T) {
+		accessControlList := newACL(t, config.HTTPProtocol, config.AppOperation{
+			Operation: "/reports",
+			HTTPVerb:  []string{"GET"},
+			Action:    config.AllowAccess,
+			Query: map[string]string{
+				"format": "csv",
+				"tenant": "*",
+			},
+		})
+
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, app1, "/reports", common.HTTPExtension_GET, "format=csv&tenant=a", config.HTTPProtocol, accessControlList)
+		assert.True(t, isAllowed)
+		isAllowed, _ = IsOperationAllowedByAccessControlPolicy(&spiffeID, app1, "/reports", common.HTTPExtension_GET, "format=pdf&tenant=a", config.HTTPProtocol, accessControlList)
+		assert.False(t, isAllowed)
+		isAllowed, _ = IsOperationAllowedByAccessControlPolicy(&spiffeID, app1, "/reports", common.HTTPExtension_GET, "format=csv", config.HTTPProtocol, accessControlList)
+		assert.False(t, isAllowed)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		for _, op := range []config.AppOperation{
+			{Operation: "/orders/(", Match: config.OperationMatchRegex, Action: config.AllowAccess},
+			{Operation: "/orders", Match: "exact", Action: config.AllowAccess},
+		} {
+			_, err := ParseAccessControlSpec(config.AccessControlSpec{
+				AppPolicies: []config.AppPolicySpec{
+					{AppName: app1, TrustDomain: "public", Namespace: "ns1", AppOperationActions: []config.AppOperation{op}},
+				},
+			}, config.HTTPProtocol)
+			assert.Error(t, err)
+		}
+	})
+}
+
+func TestAuditMode(t *testing.T) {
+	t.Run("parse audit mode", func(t *testing.T) {
+		accessControlList, err := ParseAccessControlSpec(config.AccessControlSpec{
+			DefaultAction: config.DenyAccess,
+			Mode:          "Audit",
+		}, config.HTTPProtocol)
+		assert.NoError(t, err)
+		assert.True(t, accessControlList.AuditMode)
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		_, err := ParseAccessControlSpec(config.AccessControlSpec{
+			DefaultAction: config.DenyAccess,
+			Mode:          "dryrun",
+		}, config.HTTPProtocol)
+		assert.Error(t, err)
+	})
+
+	t.Run("denied request is allowed in audit mode", func(t *testing.T) {
+		accessControlList := &config.AccessControlList{
+			DefaultAction: config.DenyAccess,
+			AuditMode:     true,
+		}
+		isAllowed, errMsg := ApplyAccessControlPolicies(context.Background(), "op1", common.HTTPExtension_POST, "", config.HTTPProtocol, accessControlList)
+		assert.True(t, isAllowed)
+		assert.Empty(t, errMsg)
+
+		accessControlList.AuditMode = false
+		isAllowed, errMsg = ApplyAccessControlPolicies(context.Background(), "op1", common.HTTPExtension_POST, "", config.HTTPProtocol, accessControlList)
+		assert.False(t, isAllowed)
+		assert.NotEmpty(t, errMsg)
+	})
+}
+
 func TestGetOperationPrefixAndPostfix(t *testing.T) {
 	t.Run("test when operation single post fix exists", func(t *testing.T) {
 		operation := "/invoke/*"
@@ -687,3 +865,57 @@ func TestNormalizeOperation(t *testing.T) {
 		assert.Equal(t, "/path1/path2/path3", p)
 	})
 }
+
+func spiffeIDContext(t *testing.T, spiffeID string) context.Context {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	uri, err := url.Parse(spiffeID)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		URIs:         []*url.URL{uri},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}},
+	})
+}
+
+func TestGetCallerSpiffeID(t *testing.T) {
+	const (
+		gateway = "spiffe://cluster.local/ns/dapr-system/gateway"
+		caller  = "spiffe://east/ns/ns2/caller"
+	)
+	acl := &config.AccessControlList{TrustedGateways: []string{gateway}}
+
+	t.Run("trusted gateway asserts the caller", func(t *testing.T) {
+		id, err := GetCallerSpiffeID(spiffeIDContext(t, gateway), caller, acl)
+		require.NoError(t, err)
+		assert.Equal(t, caller, id.String())
+	})
+
+	t.Run("assertion of another peer is ignored", func(t *testing.T) {
+		id, err := GetCallerSpiffeID(spiffeIDContext(t, "spiffe://cluster.local/ns/default/app1"), caller, acl)
+		require.NoError(t, err)
+		assert.Equal(t, "spiffe://cluster.local/ns/default/app1", id.String())
+	})
+
+	t.Run("gateway without assertion", func(t *testing.T) {
+		id, err := GetCallerSpiffeID(spiffeIDContext(t, gateway), "", acl)
+		require.NoError(t, err)
+		assert.Equal(t, gateway, id.String())
+	})
+
+	t.Run("no trusted gateways", func(t *testing.T) {
+		id, err := GetCallerSpiffeID(spiffeIDContext(t, gateway), caller, &config.AccessControlList{})
+		require.NoError(t, err)
+		assert.Equal(t, gateway, id.String())
+	})
+}
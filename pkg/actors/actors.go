@@ -80,13 +80,15 @@ type Actors interface {
 	DeleteTimer(ctx context.Context, req *DeleteTimerRequest) error
 	IsActorHosted(ctx context.Context, req *ActorHostedRequest) bool
 	GetActiveActorsCount(ctx context.Context) []ActiveActorsCount
+	GetHostedActorTypes() []string
+	IsPlacementConnected() bool
 }
 
 type actorsRuntime struct {
 	appChannel               channel.AppChannel
 	store                    state.Store
 	transactionalStore       state.TransactionalStore
-	placement                *internal.ActorPlacement
+	placement                internal.PlacementService
 	grpcConnectionFn         func(ctx context.Context, address, id string, namespace string, skipTLS, recreateIfExists, enableSSL bool, customOpts ...grpc.DialOption) (*grpc.ClientConn, error)
 	config                   Config
 	actorsTable              *sync.Map
@@ -202,11 +204,19 @@ func (a *actorsRuntime) Init() error {
 	}
 	appHealthFn := func() bool { return a.appHealthy.Load() }
 
-	a.placement = internal.NewActorPlacement(
-		a.config.PlacementAddresses, a.certChain,
-		a.config.AppID, hostname, a.config.HostedActorTypes,
-		appHealthFn,
-		afterTableUpdateFn)
+	if len(a.config.PlacementShards) > 1 {
+		a.placement = internal.NewShardedActorPlacement(
+			a.config.PlacementShards, a.certChain,
+			a.config.AppID, hostname, a.config.HostedActorTypes,
+			appHealthFn,
+			afterTableUpdateFn)
+	} else {
+		a.placement = internal.NewActorPlacement(
+			a.config.PlacementAddresses, a.certChain,
+			a.config.AppID, hostname, a.config.HostedActorTypes,
+			appHealthFn,
+			afterTableUpdateFn)
+	}
 
 	go a.placement.Start()
 	a.startDeactivationTicker(a.config.ActorDeactivationScanInterval, a.config.ActorIdleTimeout)
@@ -308,9 +318,9 @@ func (a *actorsRuntime) startDeactivationTicker(interval, actorIdleTimeout time.
 }
 
 func (a *actorsRuntime) Call(ctx context.Context, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
-	a.placement.WaitUntilPlacementTableIsReady()
-
 	actor := req.Actor()
+	a.placement.WaitUntilPlacementTableIsReady(actor.GetActorType())
+
 	targetActorAddress, appID := a.placement.LookupActor(actor.GetActorType(), actor.GetActorId())
 	if targetActorAddress == "" {
 		return nil, errors.Errorf("error finding address for actor type %s with id %s", actor.GetActorType(), actor.GetActorId())
@@ -453,10 +463,12 @@ func (a *actorsRuntime) GetState(ctx context.Context, req *GetStateRequest) (*St
 	metadata := map[string]string{metadataPartitionKey: partitionKey}
 
 	key := a.constructActorStateKey(req.ActorType, req.ActorID, req.Key)
+	op := diag.StartStateOperation(ctx, a.tracingSpec, a.config.StateStoreName, "get", 1)
 	resp, err := a.store.Get(&state.GetRequest{
 		Key:      key,
 		Metadata: metadata,
 	})
+	op.End(err)
 	if err != nil {
 		return nil, err
 	}
@@ -511,10 +523,12 @@ func (a *actorsRuntime) TransactionalStateOperation(ctx context.Context, req *Tr
 		}
 	}
 
+	op := diag.StartStateOperation(ctx, a.tracingSpec, a.config.StateStoreName, "transaction", len(operations))
 	err := a.transactionalStore.Multi(&state.TransactionalStateRequest{
 		Operations: operations,
 		Metadata:   metadata,
 	})
+	op.End(err)
 	return err
 }
 
@@ -665,9 +679,11 @@ func (a *actorsRuntime) getReminderTrack(actorKey, name string) (*ReminderTrack,
 		return nil, errors.New("actors: state store does not exist or incorrectly configured")
 	}
 
+	op := diag.StartStateOperation(context.Background(), a.tracingSpec, a.config.StateStoreName, "get", 1)
 	resp, err := a.store.Get(&state.GetRequest{
 		Key: constructCompositeKey(actorKey, name),
 	})
+	op.End(err)
 	if err != nil {
 		return nil, err
 	}
@@ -689,10 +705,12 @@ func (a *actorsRuntime) updateReminderTrack(actorKey, name string, repetition in
 		RepetitionLeft: repetition,
 	}
 
+	op := diag.StartStateOperation(context.Background(), a.tracingSpec, a.config.StateStoreName, "set", 1)
 	err := a.store.Set(&state.SetRequest{
 		Key:   constructCompositeKey(actorKey, name),
 		Value: track,
 	})
+	op.End(err)
 	return err
 }
 
@@ -1490,7 +1508,8 @@ func (a *actorsRuntime) saveRemindersInPartition(ctx context.Context, stateKey s
 	// Even when data is not partitioned, the save operation is the same.
 	// The only difference is stateKey.
 	log.Debugf("saving %d reminders in %s ...", len(reminders), stateKey)
-	return a.store.Set(&state.SetRequest{
+	op := diag.StartStateOperation(ctx, a.tracingSpec, a.config.StateStoreName, "set", 1)
+	err := a.store.Set(&state.SetRequest{
 		Key:      stateKey,
 		Value:    reminders,
 		ETag:     etag,
@@ -1499,6 +1518,8 @@ func (a *actorsRuntime) saveRemindersInPartition(ctx context.Context, stateKey s
 			Concurrency: state.FirstWrite,
 		},
 	})
+	op.End(err)
+	return err
 }
 
 func (a *actorsRuntime) DeleteReminder(ctx context.Context, req *DeleteReminderRequest) error {
@@ -1725,6 +1746,16 @@ func (a *actorsRuntime) GetActiveActorsCount(ctx context.Context) []ActiveActors
 	return activeActorsCount
 }
 
+// GetHostedActorTypes returns the actor types hosted by the app, registered with the placement service.
+func (a *actorsRuntime) GetHostedActorTypes() []string {
+	return append([]string(nil), a.config.HostedActorTypes...)
+}
+
+// IsPlacementConnected returns true if the actor runtime is connected to the placement service.
+func (a *actorsRuntime) IsPlacementConnected() bool {
+	return a.placement != nil && a.placement.IsConnected()
+}
+
 // Stop closes all network connections and resources used in actor runtime.
 func (a *actorsRuntime) Stop() {
 	if a.placement != nil {
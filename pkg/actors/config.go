@@ -24,6 +24,7 @@ type Config struct {
 	HostAddress                   string
 	AppID                         string
 	PlacementAddresses            []string
+	PlacementShards               [][]string
 	HostedActorTypes              []string
 	Port                          int
 	HeartbeatInterval             time.Duration
@@ -34,6 +35,7 @@ type Config struct {
 	Namespace                     string
 	Reentrancy                    app_config.ReentrancyConfig
 	RemindersStoragePartitions    int
+	StateStoreName                string
 }
 
 const (
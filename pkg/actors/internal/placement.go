@@ -193,6 +193,7 @@ func (p *ActorPlacement) Start() {
 					p.serverIndex.Store((p.serverIndex.Load() + 1) % int32(len(p.serverAddr)))
 				} else {
 					log.Debugf("disconnected from placement: %v", err)
+					diag.DefaultEventLog.Record(diag.RuntimeEventPlacementDisconnected, "disconnected from placement %s: %v", p.serverAddr[p.serverIndex.Load()], err)
 				}
 
 				newStream, newConn := p.establishStreamConn()
@@ -287,6 +288,13 @@ func (p *ActorPlacement) Start() {
 	}()
 }
 
+// IsConnected returns true if the stream to the placement service is connected.
+func (p *ActorPlacement) IsConnected() bool {
+	p.streamConnectedCond.L.Lock()
+	defer p.streamConnectedCond.L.Unlock()
+	return p.streamConnAlive
+}
+
 // Stop shuts down server stream gracefully.
 func (p *ActorPlacement) Stop() {
 	// CAS to avoid stop more than once.
@@ -452,8 +460,9 @@ func (p *ActorPlacement) updatePlacements(in *v1pb.PlacementTables) {
 	log.Infof("placement tables updated, version: %s", in.GetVersion())
 }
 
-// WaitUntilPlacementTableIsReady waits until placement table is until table lock is unlocked.
-func (p *ActorPlacement) WaitUntilPlacementTableIsReady() {
+// WaitUntilPlacementTableIsReady waits until placement table is until table lock is unlocked. The tables of all
+// actor types are locked together, so the actor type doesn't matter.
+func (p *ActorPlacement) WaitUntilPlacementTableIsReady(actorType string) {
 	if p.tableIsBlocked.Load() {
 		<-p.unblockSignal
 	}
@@ -81,6 +81,7 @@ func TestPlacementStream_RoundRobin(t *testing.T) {
 		time.Sleep(statusReportHeartbeatInterval * 3)
 		assert.Equal(t, leaderServer[0], testPlacement.serverIndex.Load())
 		assert.True(t, testSrv[testPlacement.serverIndex.Load()].recvCount.Load() >= 2)
+		assert.True(t, testPlacement.IsConnected())
 	})
 
 	t.Run("shutdown leader and find the next leader", func(t *testing.T) {
@@ -206,7 +207,7 @@ func TestWaitUntilPlacementTableIsReady(t *testing.T) {
 	asserted := atomic.Bool{}
 	asserted.Store(false)
 	go func() {
-		testPlacement.WaitUntilPlacementTableIsReady()
+		testPlacement.WaitUntilPlacementTableIsReady("actorOne")
 		asserted.Store(true)
 	}()
 
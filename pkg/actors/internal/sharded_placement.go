@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	dapr_credentials "github.com/dapr/dapr/pkg/credentials"
+	"github.com/dapr/dapr/pkg/placement/hashing"
+)
+
+// PlacementService is the placement client used by the actor runtime to register its actor types and to
+// look up the hosts of the actors.
+type PlacementService interface {
+	Start()
+	Stop()
+	IsConnected() bool
+	WaitUntilPlacementTableIsReady(actorType string)
+	LookupActor(actorType, actorID string) (string, string)
+}
+
+// ShardedActorPlacement connects to placement services whose tables are sharded by actor type. Every shard
+// is an independent placement cluster owning the tables of the actor types hashed to it, so the runtime
+// registers each of its actor types to the owning shard only and looks the actors up in the owning shard.
+type ShardedActorPlacement struct {
+	shards []*ActorPlacement
+}
+
+// NewShardedActorPlacement initializes ShardedActorPlacement for the actor service. shardAddrs holds the
+// placement addresses of each shard, in shard order.
+func NewShardedActorPlacement(
+	shardAddrs [][]string, clientCert *dapr_credentials.CertChain,
+	appID, runtimeHostName string, actorTypes []string,
+	appHealthFn func() bool,
+	afterTableUpdateFn func()) *ShardedActorPlacement {
+	shardedActorTypes := make([][]string, len(shardAddrs))
+	for _, actorType := range actorTypes {
+		shard := hashing.ShardOf(actorType, len(shardAddrs))
+		shardedActorTypes[shard] = append(shardedActorTypes[shard], actorType)
+	}
+
+	shards := make([]*ActorPlacement, 0, len(shardAddrs))
+	for i, addr := range shardAddrs {
+		shards = append(shards, NewActorPlacement(addr, clientCert, appID, runtimeHostName, shardedActorTypes[i], appHealthFn, afterTableUpdateFn))
+	}
+	return &ShardedActorPlacement{shards: shards}
+}
+
+// Start connects to the placement services of all shards.
+func (p *ShardedActorPlacement) Start() {
+	for _, s := range p.shards {
+		go s.Start()
+	}
+}
+
+// Stop disconnects from the placement services of all shards.
+func (p *ShardedActorPlacement) Stop() {
+	for _, s := range p.shards {
+		s.Stop()
+	}
+}
+
+// IsConnected returns true if the streams to the placement services of all shards are connected.
+func (p *ShardedActorPlacement) IsConnected() bool {
+	for _, s := range p.shards {
+		if !s.IsConnected() {
+			return false
+		}
+	}
+	return true
+}
+
+// WaitUntilPlacementTableIsReady waits until the placement table of the shard owning the actor type is unlocked.
+// The locks of the other shards don't affect the actor type.
+func (p *ShardedActorPlacement) WaitUntilPlacementTableIsReady(actorType string) {
+	p.shardOf(actorType).WaitUntilPlacementTableIsReady(actorType)
+}
+
+// LookupActor resolves to actor service instance address using the consistent hashing table of the shard
+// owning the actor type.
+func (p *ShardedActorPlacement) LookupActor(actorType, actorID string) (string, string) {
+	return p.shardOf(actorType).LookupActor(actorType, actorID)
+}
+
+func (p *ShardedActorPlacement) shardOf(actorType string) *ActorPlacement {
+	return p.shards[hashing.ShardOf(actorType, len(p.shards))]
+}
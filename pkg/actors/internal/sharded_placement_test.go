@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+
+	"github.com/dapr/dapr/pkg/placement/hashing"
+	placementv1pb "github.com/dapr/dapr/pkg/proto/placement/v1"
+)
+
+func TestNewShardedActorPlacement(t *testing.T) {
+	actorTypes := []string{}
+	for i := 0; i < 20; i++ {
+		actorTypes = append(actorTypes, fmt.Sprintf("actorType%d", i))
+	}
+
+	testPlacement := NewShardedActorPlacement(
+		[][]string{{"placement0:50005"}, {"placement1:50005"}, {"placement2:50005"}}, nil,
+		"testAppID", "127.0.0.1:1000", actorTypes,
+		func() bool { return true }, func() {})
+
+	assert.Len(t, testPlacement.shards, 3)
+	registered := 0
+	for i, s := range testPlacement.shards {
+		assert.Equal(t, []string{fmt.Sprintf("dns:///placement%d:50005", i)}, s.serverAddr)
+		for _, actorType := range s.actorTypes {
+			assert.Equal(t, i, hashing.ShardOf(actorType, 3))
+		}
+		registered += len(s.actorTypes)
+	}
+	assert.Equal(t, len(actorTypes), registered)
+}
+
+func TestShardedLookupActor(t *testing.T) {
+	testPlacement := NewShardedActorPlacement(
+		[][]string{{}, {}}, nil,
+		"testAppID", "127.0.0.1:1000", []string{"actorOne", "actorTwo"},
+		func() bool { return true }, func() {})
+
+	hashing.SetReplicationFactor(10)
+	for _, actorType := range []string{"actorOne", "actorTwo"} {
+		shard := testPlacement.shards[hashing.ShardOf(actorType, 2)]
+		h := hashing.NewConsistentHash()
+		h.Add("127.0.0.1:100"+actorType, "app"+actorType, 0)
+		shard.placementTables.Entries[actorType] = h
+	}
+
+	for _, actorType := range []string{"actorOne", "actorTwo"} {
+		name, appID := testPlacement.LookupActor(actorType, "id0")
+		assert.Equal(t, "127.0.0.1:100"+actorType, name)
+		assert.Equal(t, "app"+actorType, appID)
+	}
+
+	name, appID := testPlacement.LookupActor("nonExistingActorType", "id0")
+	assert.Empty(t, name)
+	assert.Empty(t, appID)
+}
+
+func TestShardedWaitUntilPlacementTableIsReady(t *testing.T) {
+	testPlacement := NewShardedActorPlacement(
+		[][]string{{}, {}}, nil,
+		"testAppID", "127.0.0.1:1000", []string{"actorOne", "actorTwo"},
+		func() bool { return true }, func() {})
+
+	locked := hashing.ShardOf("actorOne", 2)
+	var other string
+	for i := 0; other == ""; i++ {
+		if actorType := fmt.Sprintf("actorType%d", i); hashing.ShardOf(actorType, 2) != locked {
+			other = actorType
+		}
+	}
+	testPlacement.shards[locked].onPlacementOrder(&placementv1pb.PlacementOrder{Operation: "lock"})
+
+	// The tables of the other shard aren't locked.
+	testPlacement.WaitUntilPlacementTableIsReady(other)
+
+	ready := atomic.Bool{}
+	go func() {
+		testPlacement.WaitUntilPlacementTableIsReady("actorOne")
+		ready.Store(true)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	assert.False(t, ready.Load())
+
+	testPlacement.shards[locked].onPlacementOrder(&placementv1pb.PlacementOrder{Operation: "unlock"})
+
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, ready.Load())
+}
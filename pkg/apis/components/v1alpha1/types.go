@@ -20,9 +20,21 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// ComponentInitialized is the condition type of a component initialized by all the sidecars loading it.
+	ComponentInitialized = "Initialized"
+	// ComponentHealthy is the condition type of a component healthy in all the sidecars which initialized it.
+	ComponentHealthy = "Healthy"
+
+	// InitModeEager initializes a component when daprd starts. It is the default init mode.
+	InitModeEager = "eager"
+	// InitModeLazy initializes a component on its first use. Only output bindings support it.
+	InitModeLazy = "lazy"
+)
+
 // +genclient
-// +genclient:noStatus
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 
 // Component describes an Dapr component type.
 type Component struct {
@@ -35,6 +47,8 @@ type Component struct {
 	Auth `json:"auth,omitempty"`
 	// +optional
 	Scopes []string `json:"scopes,omitempty"`
+	// +optional
+	Status ComponentStatus `json:"status,omitempty"`
 }
 
 // ComponentSpec is the spec for a component.
@@ -46,6 +60,12 @@ type ComponentSpec struct {
 	Metadata     []MetadataItem `json:"metadata"`
 	// +optional
 	InitTimeout string `json:"initTimeout"`
+	// DependsOn names the components initialized before this component when daprd starts.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// InitMode is either eager, the default, or lazy to initialize the component on its first use.
+	// +optional
+	InitMode string `json:"initMode,omitempty"`
 }
 
 // MetadataItem is a name/value pair for a metadata.
@@ -63,6 +83,31 @@ type SecretKeyRef struct {
 	Key  string `json:"key"`
 }
 
+// ComponentStatus is the status of a component, as reported by the sidecars loading it.
+type ComponentStatus struct {
+	// Conditions are the Initialized and Healthy conditions of the component across the sidecars loading it.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Consumers is the number of sidecars which initialized the component.
+	// +optional
+	Consumers int32 `json:"consumers"`
+	// Pods are the statuses reported by the sidecars loading the component, sorted by pod name.
+	// +optional
+	Pods []ComponentPodStatus `json:"pods,omitempty"`
+}
+
+// ComponentPodStatus is the status of a component in the sidecar of a pod.
+type ComponentPodStatus struct {
+	Name        string `json:"name"`
+	Initialized bool   `json:"initialized"`
+	Healthy     bool   `json:"healthy"`
+	// Pending is true while the component, initialized on its first use, isn't used by the sidecar yet.
+	// +optional
+	Pending bool `json:"pending,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // Auth represents authentication details for the component.
 type Auth struct {
 	SecretStore string `json:"secretStore"`
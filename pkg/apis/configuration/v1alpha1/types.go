@@ -38,6 +38,8 @@ type ConfigurationSpec struct {
 	// +optional
 	HTTPPipelineSpec PipelineSpec `json:"httpPipeline,omitempty"`
 	// +optional
+	AppHTTPPipelineSpec PipelineSpec `json:"appHttpPipeline,omitempty"`
+	// +optional
 	TracingSpec TracingSpec `json:"tracing,omitempty"`
 	// +kubebuilder:default={enabled:true}
 	MetricSpec MetricSpec `json:"metric,omitempty"`
@@ -53,18 +55,397 @@ type ConfigurationSpec struct {
 	Features []FeatureSpec `json:"features,omitempty"`
 	// +optional
 	APISpec APISpec `json:"api,omitempty"`
+	// +optional
+	AccessLogSpec AccessLogSpec `json:"accessLog,omitempty"`
+	// +optional
+	LoggingSpec LoggingSpec `json:"logging,omitempty"`
+	// +optional
+	ProfilingSpec ProfilingSpec `json:"profiling,omitempty"`
+	// +optional
+	AuditSpec AuditSpec `json:"audit,omitempty"`
+	// +optional
+	SidecarSpec SidecarSpec `json:"sidecar,omitempty"`
+	// +optional
+	ResiliencySpec ResiliencySpec `json:"resiliency,omitempty"`
+	// +optional
+	GatewaySpec GatewaySpec `json:"gateway,omitempty"`
+	// +optional
+	CompressionSpec CompressionSpec `json:"compression,omitempty"`
+	// +optional
+	ChaosSpec ChaosSpec `json:"chaos,omitempty"`
+}
+
+// ChaosSpec defines the faults injected in the operations of the Dapr sidecars to test the resiliency policies.
+type ChaosSpec struct {
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// +optional
+	Faults []FaultSpec `json:"faults,omitempty"`
+}
+
+// FaultSpec defines a fault injected in a percentage of the invocation, state or pubsub operations on a target.
+type FaultSpec struct {
+	Operation string `json:"operation"`
+	// +optional
+	Target     string  `json:"target,omitempty"`
+	Percentage float64 `json:"percentage"`
+	// +optional
+	Delay string `json:"delay,omitempty"`
+	// +optional
+	Error string `json:"error,omitempty"`
+	// +optional
+	Abort bool `json:"abort,omitempty"`
+}
+
+// CompressionSpec defines the compression of the payloads sent to other Dapr sidecars.
+type CompressionSpec struct {
+	// +optional
+	Algorithm string `json:"algorithm,omitempty"`
+	// +optional
+	MinSize int `json:"minSize,omitempty"`
+}
+
+// GatewaySpec defines the remote clusters that service invocation is routed to through a Dapr gateway.
+type GatewaySpec struct {
+	// +optional
+	Clusters []RemoteClusterSpec `json:"clusters,omitempty"`
+}
+
+// RemoteClusterSpec defines the Dapr gateway of a remote cluster and the app ids hosted in it.
+type RemoteClusterSpec struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	AppID   string `json:"appId"`
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// +optional
+	Apps []string `json:"apps,omitempty"`
+}
+
+// ResiliencySpec defines the named resiliency policies and the targets they apply to.
+type ResiliencySpec struct {
+	// +optional
+	Policies ResiliencyPolicies `json:"policies,omitempty"`
+	// +optional
+	Targets ResiliencyTargets `json:"targets,omitempty"`
+}
+
+// ResiliencyPolicies defines the named resiliency policies.
+type ResiliencyPolicies struct {
+	// +optional
+	Timeouts map[string]string `json:"timeouts,omitempty"`
+	// +optional
+	Retries map[string]RetryPolicySpec `json:"retries,omitempty"`
+	// +optional
+	Hedging map[string]HedgingPolicySpec `json:"hedging,omitempty"`
+	// +optional
+	Mirroring map[string]MirroringPolicySpec `json:"mirroring,omitempty"`
+	// +optional
+	CircuitBreakers map[string]CircuitBreakerPolicySpec `json:"circuitBreakers,omitempty"`
+	// +optional
+	Concurrency map[string]ConcurrencyPolicySpec `json:"concurrency,omitempty"`
+	// +optional
+	RetryBudgets map[string]RetryBudgetSpec `json:"retryBudgets,omitempty"`
+	// +optional
+	Fallbacks map[string]FallbackPolicySpec `json:"fallbacks,omitempty"`
+}
+
+// RetryPolicySpec defines a retry policy with a constant interval between attempts.
+type RetryPolicySpec struct {
+	Interval   string `json:"interval"`
+	MaxRetries int    `json:"maxRetries"`
+}
+
+// HedgingPolicySpec defines a hedged request policy.
+type HedgingPolicySpec struct {
+	Delay       string `json:"delay"`
+	MaxAttempts int    `json:"maxAttempts"`
+}
+
+// MirroringPolicySpec defines a request mirroring policy.
+type MirroringPolicySpec struct {
+	AppID      string  `json:"appId"`
+	Percentage float64 `json:"percentage"`
+}
+
+// CircuitBreakerPolicySpec defines a circuit breaker policy.
+type CircuitBreakerPolicySpec struct {
+	MaxConsecutiveFailures int    `json:"maxConsecutiveFailures"`
+	Timeout                string `json:"timeout"`
+}
+
+// ConcurrencyPolicySpec defines an adaptive concurrency policy.
+type ConcurrencyPolicySpec struct {
+	// +optional
+	InitialLimit int `json:"initialLimit,omitempty"`
+	// +optional
+	MinLimit int `json:"minLimit,omitempty"`
+	// +optional
+	MaxLimit         int    `json:"maxLimit,omitempty"`
+	LatencyThreshold string `json:"latencyThreshold"`
+	// +optional
+	BackoffRatio float64 `json:"backoffRatio,omitempty"`
+}
+
+// RetryBudgetSpec defines a retry budget shared by the retries to a target.
+type RetryBudgetSpec struct {
+	Ratio float64 `json:"ratio"`
+	// +optional
+	MinRetriesPerSecond int `json:"minRetriesPerSecond,omitempty"`
+	// +optional
+	TTL string `json:"ttl,omitempty"`
+}
+
+// FallbackPolicySpec defines the fallback of the calls to a target failing once their retries are exhausted.
+type FallbackPolicySpec struct {
+	// +optional
+	AppID string `json:"appId,omitempty"`
+	// +optional
+	Component string `json:"component,omitempty"`
+	// +optional
+	Response *StaticResponseSpec `json:"response,omitempty"`
+}
+
+// StaticResponseSpec defines a static response returned by a fallback.
+type StaticResponseSpec struct {
+	// +optional
+	StatusCode int `json:"statusCode,omitempty"`
+	// +optional
+	ContentType string `json:"contentType,omitempty"`
+	// +optional
+	Body string `json:"body,omitempty"`
+}
+
+// ResiliencyTargets maps targets to the names of the policies applied to them.
+type ResiliencyTargets struct {
+	// +optional
+	Apps map[string]AppResiliencyTarget `json:"apps,omitempty"`
+	// +optional
+	Components map[string]ComponentResiliencyTarget `json:"components,omitempty"`
+}
+
+// AppResiliencyTarget defines the policies applied to service invocations of an app.
+type AppResiliencyTarget struct {
+	// +optional
+	Timeout string `json:"timeout,omitempty"`
+	// +optional
+	Hedging string `json:"hedging,omitempty"`
+	// +optional
+	Mirroring string `json:"mirroring,omitempty"`
+	// +optional
+	CircuitBreaker string `json:"circuitBreaker,omitempty"`
+	// +optional
+	Concurrency string `json:"concurrency,omitempty"`
+	// +optional
+	RetryBudget string `json:"retryBudget,omitempty"`
+	// +optional
+	Fallback string `json:"fallback,omitempty"`
+	// +optional
+	Routes map[string]RouteResiliencyTarget `json:"routes,omitempty"`
+}
+
+// RouteResiliencyTarget defines the policies applied to service invocations of a method of an app.
+type RouteResiliencyTarget struct {
+	// +optional
+	Timeout string `json:"timeout,omitempty"`
+	// +optional
+	Hedging string `json:"hedging,omitempty"`
+}
+
+// ComponentResiliencyTarget defines the policies applied to a component.
+type ComponentResiliencyTarget struct {
+	// +optional
+	Retry string `json:"retry,omitempty"`
+	// +optional
+	Concurrency string `json:"concurrency,omitempty"`
+	// +optional
+	RetryBudget string `json:"retryBudget,omitempty"`
+	// +optional
+	Fallback string `json:"fallback,omitempty"`
+	// +optional
+	Topics map[string]TopicResiliencyTarget `json:"topics,omitempty"`
+}
+
+// TopicResiliencyTarget defines the policies applied to the delivery of the events of a topic to the app.
+type TopicResiliencyTarget struct {
+	// +optional
+	Retry string `json:"retry,omitempty"`
+}
+
+// SidecarSpec defines the defaults of the Dapr sidecars injected in the pods of the namespace of the configuration.
+// The annotations of the pods take precedence over the defaults.
+type SidecarSpec struct {
+	// +optional
+	Image string `json:"image,omitempty"`
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+	// +optional
+	Env []SidecarEnvVar `json:"env,omitempty"`
+	// +optional
+	Resources SidecarResourcesSpec `json:"resources,omitempty"`
+}
+
+// SidecarEnvVar is an environment variable of the Dapr sidecar.
+type SidecarEnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// SidecarResourcesSpec defines the resource requests and limits of the Dapr sidecar.
+type SidecarResourcesSpec struct {
+	// +optional
+	CPURequest string `json:"cpuRequest,omitempty"`
+	// +optional
+	CPULimit string `json:"cpuLimit,omitempty"`
+	// +optional
+	MemoryRequest string `json:"memoryRequest,omitempty"`
+	// +optional
+	MemoryLimit string `json:"memoryLimit,omitempty"`
+}
+
+// AuditSpec defines the audit log of the administrative and security-relevant events.
+type AuditSpec struct {
+	Enabled bool `json:"enabled"`
+	// +optional
+	Sinks []AuditSinkSpec `json:"sinks,omitempty"`
+}
+
+// AuditSinkSpec defines a sink of the audit events.
+type AuditSinkSpec struct {
+	Type string `json:"type"`
+	// +optional
+	Output string `json:"output,omitempty"`
+	// +optional
+	PubsubName string `json:"pubsubName,omitempty"`
+	// +optional
+	Topic string `json:"topic,omitempty"`
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// ProfilingSpec defines the continuous profiling of the sidecar.
+type ProfilingSpec struct {
+	EndpointAddress string `json:"endpointAddress"`
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+	// +optional
+	Interval string `json:"interval,omitempty"`
+	// +optional
+	Profiles []string `json:"profiles,omitempty"`
+}
+
+// LoggingSpec defines the output levels of the logs of the sidecar.
+type LoggingSpec struct {
+	// +optional
+	Level string `json:"level,omitempty"`
+	// +optional
+	Scopes map[string]string `json:"scopes,omitempty"`
+}
+
+// AccessLogSpec defines the access log of the APIs of the sidecar and of the invocations of the app.
+type AccessLogSpec struct {
+	Enabled bool `json:"enabled"`
+	// +optional
+	Fields []string `json:"fields,omitempty"`
+	// +optional
+	SamplingRate string `json:"samplingRate,omitempty"`
+	// +optional
+	Output string `json:"output,omitempty"`
 }
 
 // APISpec describes the configuration for Dapr APIs.
 type APISpec struct {
 	Allowed []APIAccessRule `json:"allowed,omitempty"`
+	// +optional
+	Denied []APIAccessRule `json:"denied,omitempty"`
+	// +optional
+	Authentication APIAuthenticationSpec `json:"authentication,omitempty"`
+	// +optional
+	Policy APIPolicySpec `json:"policy,omitempty"`
+	// +optional
+	GRPC APIGRPCSpec `json:"grpc,omitempty"`
+	// +optional
+	HTTP APIHTTPSpec `json:"http,omitempty"`
+}
+
+// APIGRPCSpec describes the optional services served on the port of the gRPC API.
+type APIGRPCSpec struct {
+	// +optional
+	Reflection bool `json:"reflection,omitempty"`
+	// +optional
+	Health bool `json:"health,omitempty"`
+}
+
+// APIHTTPSpec describes the handling of the requests and responses of the HTTP API.
+type APIHTTPSpec struct {
+	// +optional
+	Compression APIHTTPCompressionSpec `json:"compression,omitempty"`
+	// +optional
+	MaxRequestBodySize APIHTTPMaxRequestBodySizeSpec `json:"maxRequestBodySize,omitempty"`
+}
+
+// APIHTTPCompressionSpec describes the compression of the responses of the HTTP API.
+type APIHTTPCompressionSpec struct {
+	// +optional
+	Algorithms []string `json:"algorithms,omitempty"`
+	// +optional
+	MinSize int `json:"minSize,omitempty"`
+}
+
+// APIHTTPMaxRequestBodySizeSpec sets the maximum size in MB of the request bodies of the invocation, publish and
+// state APIs.
+type APIHTTPMaxRequestBodySizeSpec struct {
+	// +optional
+	Invocation int `json:"invocation,omitempty"`
+	// +optional
+	Publish int `json:"publish,omitempty"`
+	// +optional
+	State int `json:"state,omitempty"`
 }
 
-// APIAccessRule describes an access rule for allowing a Dapr API to be enabled and accessible by an app.
+// APIPolicySpec describes the policies authorizing the calls of the building block APIs.
+type APIPolicySpec struct {
+	// +optional
+	DefaultAction string `json:"defaultAction,omitempty"`
+	// +optional
+	Rules []APIPolicyRule `json:"rules,omitempty"`
+}
+
+// APIPolicyRule is a policy applying its action to the calls matching its CEL condition.
+type APIPolicyRule struct {
+	Name      string `json:"name"`
+	Condition string `json:"condition"`
+	Action    string `json:"action"`
+}
+
+// APIAuthenticationSpec describes how the requests to the Dapr APIs are authenticated, in addition to the api token.
+// The authentications are cumulative: when the api token is set as well, the requests must hold both the api token
+// and a valid bearer token.
+type APIAuthenticationSpec struct {
+	// +optional
+	OIDC *OIDCSpec `json:"oidc,omitempty"`
+}
+
+// OIDCSpec describes the OIDC issuer whose JWT bearer tokens are accepted by the Dapr APIs.
+type OIDCSpec struct {
+	Issuer string `json:"issuer"`
+	// +optional
+	JWKSURL   string   `json:"jwksURL,omitempty"`
+	Audiences []string `json:"audiences"`
+}
+
+// APIAccessRule describes an access rule for allowing a Dapr API to be enabled and accessible by an app,
+// or for denying the access to it.
 type APIAccessRule struct {
 	Name     string `json:"name"`
 	Version  string `json:"version"`
 	Protocol string `json:"protocol"`
+	// +optional
+	Operations []string `json:"operations,omitempty"`
+	// +optional
+	Resources []string `json:"resources,omitempty"`
 }
 
 // NameResolutionSpec is the spec for name resolution configuration.
@@ -76,7 +457,12 @@ type NameResolutionSpec struct {
 
 // SecretsSpec is the spec for secrets configuration.
 type SecretsSpec struct {
-	Scopes []SecretsScope `json:"scopes"`
+	// +optional
+	Scopes []SecretsScope `json:"scopes,omitempty"`
+	// +optional
+	DefaultStore string `json:"defaultStore,omitempty"`
+	// +optional
+	DenyUnresolvedRefs bool `json:"denyUnresolvedRefs,omitempty"`
 }
 
 // SecretsScope defines the scope for secrets.
@@ -88,10 +474,27 @@ type SecretsScope struct {
 	AllowedSecrets []string `json:"allowedSecrets,omitempty"`
 	// +optional
 	DeniedSecrets []string `json:"deniedSecrets,omitempty"`
+	// +optional
+	AllowedSecretPatterns []string `json:"allowedSecretPatterns,omitempty"`
+	// +optional
+	DeniedSecretPatterns []string `json:"deniedSecretPatterns,omitempty"`
+	// +optional
+	AllowedOperations []string `json:"allowedOperations,omitempty"`
 }
 
 // PipelineSpec defines the middleware pipeline.
 type PipelineSpec struct {
+	// +optional
+	Handlers []HandlerSpec `json:"handlers"`
+	// +optional
+	Routes []PipelineRouteSpec `json:"routes,omitempty"`
+}
+
+// PipelineRouteSpec defines the middleware pipeline of the requests matching a path and methods.
+type PipelineRouteSpec struct {
+	Path string `json:"path"`
+	// +optional
+	Methods  []string      `json:"methods,omitempty"`
 	Handlers []HandlerSpec `json:"handlers"`
 }
 
@@ -109,6 +512,19 @@ type MTLSSpec struct {
 	WorkloadCertTTL string `json:"workloadCertTTL"`
 	// +optional
 	AllowedClockSkew string `json:"allowedClockSkew"`
+	// +optional
+	Federation []TrustDomainFederation `json:"federation,omitempty"`
+}
+
+// TrustDomainFederation defines the trust anchors of a remote trust domain.
+type TrustDomainFederation struct {
+	TrustDomain string `json:"trustDomain"`
+	// +optional
+	TrustAnchors string `json:"trustAnchors,omitempty"`
+	// +optional
+	TrustAnchorsFile string `json:"trustAnchorsFile,omitempty"`
+	// +optional
+	BundleEndpoint string `json:"bundleEndpoint,omitempty"`
 }
 
 // SelectorSpec selects target services to which the handler is to be applied.
@@ -124,8 +540,21 @@ type SelectorField struct {
 
 // TracingSpec defines distributed tracing configuration.
 type TracingSpec struct {
-	SamplingRate string     `json:"samplingRate"`
-	Zipkin       ZipkinSpec `json:"zipkin"`
+	SamplingRate string `json:"samplingRate"`
+	// +optional
+	SamplingRules []TracingSamplingRule `json:"samplingRules,omitempty"`
+	Zipkin        ZipkinSpec            `json:"zipkin"`
+}
+
+// TracingSamplingRule defines the sampling rate of the requests matching the rule.
+type TracingSamplingRule struct {
+	// +optional
+	Path string `json:"path,omitempty"`
+	// +optional
+	AppID string `json:"appId,omitempty"`
+	// +optional
+	Errors       bool   `json:"errors,omitempty"`
+	SamplingRate string `json:"samplingRate"`
 }
 
 // ZipkinSpec defines Zipkin trace configurations.
@@ -136,6 +565,53 @@ type ZipkinSpec struct {
 // MetricSpec defines metrics configuration.
 type MetricSpec struct {
 	Enabled bool `json:"enabled"`
+	// +optional
+	OTLP OTLPMetricSpec `json:"otlp,omitempty"`
+	// +optional
+	Rules []MetricsRule `json:"rules,omitempty"`
+	// +optional
+	Histograms []MetricHistogram `json:"histograms,omitempty"`
+	// +optional
+	Exemplars bool `json:"exemplars,omitempty"`
+}
+
+// MetricHistogram defines the buckets of the histograms of a group of metrics.
+type MetricHistogram struct {
+	Group   string    `json:"group"`
+	Buckets []float64 `json:"buckets"`
+}
+
+// MetricsRule defines the rule of a metric.
+type MetricsRule struct {
+	Name string `json:"name"`
+	// +optional
+	Drop bool `json:"drop,omitempty"`
+	// +optional
+	Labels []MetricLabel `json:"labels,omitempty"`
+}
+
+// MetricLabel defines the rule of a label of a metric.
+type MetricLabel struct {
+	Name string `json:"name"`
+	// +optional
+	Drop bool `json:"drop,omitempty"`
+	// +optional
+	Buckets []MetricLabelBucket `json:"buckets,omitempty"`
+}
+
+// MetricLabelBucket groups the values of a label matching Regex under Value.
+type MetricLabelBucket struct {
+	Value string `json:"value"`
+	Regex string `json:"regex"`
+}
+
+// OTLPMetricSpec defines the OTLP metrics exporter configurations.
+type OTLPMetricSpec struct {
+	EndpointAddress string `json:"endpointAddress"`
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+	// +optional
+	PushInterval string `json:"pushInterval,omitempty"`
 }
 
 // AppPolicySpec defines the policy data structure for each app.
@@ -157,6 +633,10 @@ type AppOperationAction struct {
 	// +optional
 	HTTPVerb []string `json:"httpVerb" yaml:"httpVerb"`
 	Action   string   `json:"action" yaml:"action"`
+	// +optional
+	Match string `json:"match,omitempty" yaml:"match,omitempty"`
+	// +optional
+	Query map[string]string `json:"query,omitempty" yaml:"query,omitempty"`
 }
 
 // AccessControlSpec is the spec object in ConfigurationSpec.
@@ -166,7 +646,11 @@ type AccessControlSpec struct {
 	// +optional
 	TrustDomain string `json:"trustDomain" yaml:"trustDomain"`
 	// +optional
+	Mode string `json:"mode,omitempty" yaml:"mode,omitempty"`
+	// +optional
 	AppPolicies []AppPolicySpec `json:"policies" yaml:"policies"`
+	// +optional
+	TrustedGateways []string `json:"trustedGateways,omitempty" yaml:"trustedGateways,omitempty"`
 }
 
 // FeatureSpec defines the features that are enabled/disabled.
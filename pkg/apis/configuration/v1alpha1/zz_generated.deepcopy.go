@@ -28,6 +28,16 @@ import (
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIAccessRule) DeepCopyInto(out *APIAccessRule) {
 	*out = *in
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIAccessRule.
@@ -40,287 +50,1108 @@ func (in *APIAccessRule) DeepCopy() *APIAccessRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIAuthenticationSpec) DeepCopyInto(out *APIAuthenticationSpec) {
+	*out = *in
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OIDCSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIAuthenticationSpec.
+func (in *APIAuthenticationSpec) DeepCopy() *APIAuthenticationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIAuthenticationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIGRPCSpec) DeepCopyInto(out *APIGRPCSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIGRPCSpec.
+func (in *APIGRPCSpec) DeepCopy() *APIGRPCSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIGRPCSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIHTTPCompressionSpec) DeepCopyInto(out *APIHTTPCompressionSpec) {
+	*out = *in
+	if in.Algorithms != nil {
+		in, out := &in.Algorithms, &out.Algorithms
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIHTTPCompressionSpec.
+func (in *APIHTTPCompressionSpec) DeepCopy() *APIHTTPCompressionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIHTTPCompressionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIHTTPMaxRequestBodySizeSpec) DeepCopyInto(out *APIHTTPMaxRequestBodySizeSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIHTTPMaxRequestBodySizeSpec.
+func (in *APIHTTPMaxRequestBodySizeSpec) DeepCopy() *APIHTTPMaxRequestBodySizeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIHTTPMaxRequestBodySizeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIHTTPSpec) DeepCopyInto(out *APIHTTPSpec) {
+	*out = *in
+	in.Compression.DeepCopyInto(&out.Compression)
+	out.MaxRequestBodySize = in.MaxRequestBodySize
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIHTTPSpec.
+func (in *APIHTTPSpec) DeepCopy() *APIHTTPSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIHTTPSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIPolicyRule) DeepCopyInto(out *APIPolicyRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIPolicyRule.
+func (in *APIPolicyRule) DeepCopy() *APIPolicyRule {
+	if in == nil {
+		return nil
+	}
+	out := new(APIPolicyRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIPolicySpec) DeepCopyInto(out *APIPolicySpec) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]APIPolicyRule, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIPolicySpec.
+func (in *APIPolicySpec) DeepCopy() *APIPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APISpec) DeepCopyInto(out *APISpec) {
 	*out = *in
 	if in.Allowed != nil {
 		in, out := &in.Allowed, &out.Allowed
 		*out = make([]APIAccessRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Denied != nil {
+		in, out := &in.Denied, &out.Denied
+		*out = make([]APIAccessRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Authentication.DeepCopyInto(&out.Authentication)
+	in.Policy.DeepCopyInto(&out.Policy)
+	out.GRPC = in.GRPC
+	in.HTTP.DeepCopyInto(&out.HTTP)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APISpec.
+func (in *APISpec) DeepCopy() *APISpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APISpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessControlSpec) DeepCopyInto(out *AccessControlSpec) {
+	*out = *in
+	if in.AppPolicies != nil {
+		in, out := &in.AppPolicies, &out.AppPolicies
+		*out = make([]AppPolicySpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TrustedGateways != nil {
+		in, out := &in.TrustedGateways, &out.TrustedGateways
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessControlSpec.
+func (in *AccessControlSpec) DeepCopy() *AccessControlSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessControlSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessLogSpec) DeepCopyInto(out *AccessLogSpec) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessLogSpec.
+func (in *AccessLogSpec) DeepCopy() *AccessLogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessLogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppOperationAction) DeepCopyInto(out *AppOperationAction) {
+	*out = *in
+	if in.HTTPVerb != nil {
+		in, out := &in.HTTPVerb, &out.HTTPVerb
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Query != nil {
+		in, out := &in.Query, &out.Query
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppOperationAction.
+func (in *AppOperationAction) DeepCopy() *AppOperationAction {
+	if in == nil {
+		return nil
+	}
+	out := new(AppOperationAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppPolicySpec) DeepCopyInto(out *AppPolicySpec) {
+	*out = *in
+	if in.AppOperationActions != nil {
+		in, out := &in.AppOperationActions, &out.AppOperationActions
+		*out = make([]AppOperationAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppPolicySpec.
+func (in *AppPolicySpec) DeepCopy() *AppPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AppPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppResiliencyTarget) DeepCopyInto(out *AppResiliencyTarget) {
+	*out = *in
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make(map[string]RouteResiliencyTarget, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppResiliencyTarget.
+func (in *AppResiliencyTarget) DeepCopy() *AppResiliencyTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(AppResiliencyTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditSinkSpec) DeepCopyInto(out *AuditSinkSpec) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditSinkSpec.
+func (in *AuditSinkSpec) DeepCopy() *AuditSinkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditSinkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditSpec) DeepCopyInto(out *AuditSpec) {
+	*out = *in
+	if in.Sinks != nil {
+		in, out := &in.Sinks, &out.Sinks
+		*out = make([]AuditSinkSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditSpec.
+func (in *AuditSpec) DeepCopy() *AuditSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChaosSpec) DeepCopyInto(out *ChaosSpec) {
+	*out = *in
+	if in.Faults != nil {
+		in, out := &in.Faults, &out.Faults
+		*out = make([]FaultSpec, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APISpec.
-func (in *APISpec) DeepCopy() *APISpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChaosSpec.
+func (in *ChaosSpec) DeepCopy() *ChaosSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChaosSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CircuitBreakerPolicySpec) DeepCopyInto(out *CircuitBreakerPolicySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CircuitBreakerPolicySpec.
+func (in *CircuitBreakerPolicySpec) DeepCopy() *CircuitBreakerPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CircuitBreakerPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentResiliencyTarget) DeepCopyInto(out *ComponentResiliencyTarget) {
+	*out = *in
+	if in.Topics != nil {
+		in, out := &in.Topics, &out.Topics
+		*out = make(map[string]TopicResiliencyTarget, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentResiliencyTarget.
+func (in *ComponentResiliencyTarget) DeepCopy() *ComponentResiliencyTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentResiliencyTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompressionSpec) DeepCopyInto(out *CompressionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompressionSpec.
+func (in *CompressionSpec) DeepCopy() *CompressionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CompressionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConcurrencyPolicySpec) DeepCopyInto(out *ConcurrencyPolicySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConcurrencyPolicySpec.
+func (in *ConcurrencyPolicySpec) DeepCopy() *ConcurrencyPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConcurrencyPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Configuration) DeepCopyInto(out *Configuration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Configuration.
+func (in *Configuration) DeepCopy() *Configuration {
+	if in == nil {
+		return nil
+	}
+	out := new(Configuration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Configuration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationList) DeepCopyInto(out *ConfigurationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Configuration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationList.
+func (in *ConfigurationList) DeepCopy() *ConfigurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ConfigurationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationSpec) DeepCopyInto(out *ConfigurationSpec) {
+	*out = *in
+	in.HTTPPipelineSpec.DeepCopyInto(&out.HTTPPipelineSpec)
+	in.AppHTTPPipelineSpec.DeepCopyInto(&out.AppHTTPPipelineSpec)
+	in.TracingSpec.DeepCopyInto(&out.TracingSpec)
+	in.MetricSpec.DeepCopyInto(&out.MetricSpec)
+	in.MTLSSpec.DeepCopyInto(&out.MTLSSpec)
+	in.Secrets.DeepCopyInto(&out.Secrets)
+	in.AccessControlSpec.DeepCopyInto(&out.AccessControlSpec)
+	in.NameResolutionSpec.DeepCopyInto(&out.NameResolutionSpec)
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = make([]FeatureSpec, len(*in))
+		copy(*out, *in)
+	}
+	in.APISpec.DeepCopyInto(&out.APISpec)
+	in.AccessLogSpec.DeepCopyInto(&out.AccessLogSpec)
+	in.LoggingSpec.DeepCopyInto(&out.LoggingSpec)
+	in.ProfilingSpec.DeepCopyInto(&out.ProfilingSpec)
+	in.AuditSpec.DeepCopyInto(&out.AuditSpec)
+	in.SidecarSpec.DeepCopyInto(&out.SidecarSpec)
+	in.ResiliencySpec.DeepCopyInto(&out.ResiliencySpec)
+	in.GatewaySpec.DeepCopyInto(&out.GatewaySpec)
+	out.CompressionSpec = in.CompressionSpec
+	in.ChaosSpec.DeepCopyInto(&out.ChaosSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationSpec.
+func (in *ConfigurationSpec) DeepCopy() *ConfigurationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DynamicValue) DeepCopyInto(out *DynamicValue) {
+	*out = *in
+	in.JSON.DeepCopyInto(&out.JSON)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicValue.
+func (in *DynamicValue) DeepCopy() *DynamicValue {
+	if in == nil {
+		return nil
+	}
+	out := new(DynamicValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FallbackPolicySpec) DeepCopyInto(out *FallbackPolicySpec) {
+	*out = *in
+	if in.Response != nil {
+		in, out := &in.Response, &out.Response
+		*out = new(StaticResponseSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FallbackPolicySpec.
+func (in *FallbackPolicySpec) DeepCopy() *FallbackPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FallbackPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FaultSpec) DeepCopyInto(out *FaultSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FaultSpec.
+func (in *FaultSpec) DeepCopy() *FaultSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FaultSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeatureSpec) DeepCopyInto(out *FeatureSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureSpec.
+func (in *FeatureSpec) DeepCopy() *FeatureSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FeatureSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]RemoteClusterSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewaySpec.
+func (in *GatewaySpec) DeepCopy() *GatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HandlerSpec) DeepCopyInto(out *HandlerSpec) {
+	*out = *in
+	in.SelectorSpec.DeepCopyInto(&out.SelectorSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HandlerSpec.
+func (in *HandlerSpec) DeepCopy() *HandlerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HandlerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HedgingPolicySpec) DeepCopyInto(out *HedgingPolicySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HedgingPolicySpec.
+func (in *HedgingPolicySpec) DeepCopy() *HedgingPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HedgingPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoggingSpec) DeepCopyInto(out *LoggingSpec) {
+	*out = *in
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingSpec.
+func (in *LoggingSpec) DeepCopy() *LoggingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoggingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MTLSSpec) DeepCopyInto(out *MTLSSpec) {
+	*out = *in
+	if in.Federation != nil {
+		in, out := &in.Federation, &out.Federation
+		*out = make([]TrustDomainFederation, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MTLSSpec.
+func (in *MTLSSpec) DeepCopy() *MTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricHistogram) DeepCopyInto(out *MetricHistogram) {
+	*out = *in
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = make([]float64, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricHistogram.
+func (in *MetricHistogram) DeepCopy() *MetricHistogram {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricHistogram)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricLabel) DeepCopyInto(out *MetricLabel) {
+	*out = *in
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = make([]MetricLabelBucket, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricLabel.
+func (in *MetricLabel) DeepCopy() *MetricLabel {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricLabel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricLabelBucket) DeepCopyInto(out *MetricLabelBucket) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricLabelBucket.
+func (in *MetricLabelBucket) DeepCopy() *MetricLabelBucket {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricLabelBucket)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricSpec) DeepCopyInto(out *MetricSpec) {
+	*out = *in
+	in.OTLP.DeepCopyInto(&out.OTLP)
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]MetricsRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Histograms != nil {
+		in, out := &in.Histograms, &out.Histograms
+		*out = make([]MetricHistogram, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricSpec.
+func (in *MetricSpec) DeepCopy() *MetricSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsRule) DeepCopyInto(out *MetricsRule) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]MetricLabel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsRule.
+func (in *MetricsRule) DeepCopy() *MetricsRule {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirroringPolicySpec) DeepCopyInto(out *MirroringPolicySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirroringPolicySpec.
+func (in *MirroringPolicySpec) DeepCopy() *MirroringPolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(APISpec)
+	out := new(MirroringPolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AccessControlSpec) DeepCopyInto(out *AccessControlSpec) {
+func (in *NameResolutionSpec) DeepCopyInto(out *NameResolutionSpec) {
 	*out = *in
-	if in.AppPolicies != nil {
-		in, out := &in.AppPolicies, &out.AppPolicies
-		*out = make([]AppPolicySpec, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.Configuration.DeepCopyInto(&out.Configuration)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessControlSpec.
-func (in *AccessControlSpec) DeepCopy() *AccessControlSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NameResolutionSpec.
+func (in *NameResolutionSpec) DeepCopy() *NameResolutionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(AccessControlSpec)
+	out := new(NameResolutionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AppOperationAction) DeepCopyInto(out *AppOperationAction) {
+func (in *OIDCSpec) DeepCopyInto(out *OIDCSpec) {
 	*out = *in
-	if in.HTTPVerb != nil {
-		in, out := &in.HTTPVerb, &out.HTTPVerb
+	if in.Audiences != nil {
+		in, out := &in.Audiences, &out.Audiences
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppOperationAction.
-func (in *AppOperationAction) DeepCopy() *AppOperationAction {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCSpec.
+func (in *OIDCSpec) DeepCopy() *OIDCSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(AppOperationAction)
+	out := new(OIDCSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AppPolicySpec) DeepCopyInto(out *AppPolicySpec) {
+func (in *OTLPMetricSpec) DeepCopyInto(out *OTLPMetricSpec) {
 	*out = *in
-	if in.AppOperationActions != nil {
-		in, out := &in.AppOperationActions, &out.AppOperationActions
-		*out = make([]AppOperationAction, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppPolicySpec.
-func (in *AppPolicySpec) DeepCopy() *AppPolicySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OTLPMetricSpec.
+func (in *OTLPMetricSpec) DeepCopy() *OTLPMetricSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(AppPolicySpec)
+	out := new(OTLPMetricSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Configuration) DeepCopyInto(out *Configuration) {
+func (in *PipelineRouteSpec) DeepCopyInto(out *PipelineRouteSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Methods != nil {
+		in, out := &in.Methods, &out.Methods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Handlers != nil {
+		in, out := &in.Handlers, &out.Handlers
+		*out = make([]HandlerSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Configuration.
-func (in *Configuration) DeepCopy() *Configuration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineRouteSpec.
+func (in *PipelineRouteSpec) DeepCopy() *PipelineRouteSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Configuration)
+	out := new(PipelineRouteSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Configuration) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConfigurationList) DeepCopyInto(out *ConfigurationList) {
+func (in *PipelineSpec) DeepCopyInto(out *PipelineSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Configuration, len(*in))
+	if in.Handlers != nil {
+		in, out := &in.Handlers, &out.Handlers
+		*out = make([]HandlerSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]PipelineRouteSpec, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationList.
-func (in *ConfigurationList) DeepCopy() *ConfigurationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineSpec.
+func (in *PipelineSpec) DeepCopy() *PipelineSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ConfigurationList)
+	out := new(PipelineSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ConfigurationList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConfigurationSpec) DeepCopyInto(out *ConfigurationSpec) {
+func (in *ProfilingSpec) DeepCopyInto(out *ProfilingSpec) {
 	*out = *in
-	in.HTTPPipelineSpec.DeepCopyInto(&out.HTTPPipelineSpec)
-	out.TracingSpec = in.TracingSpec
-	out.MetricSpec = in.MetricSpec
-	out.MTLSSpec = in.MTLSSpec
-	in.Secrets.DeepCopyInto(&out.Secrets)
-	in.AccessControlSpec.DeepCopyInto(&out.AccessControlSpec)
-	in.NameResolutionSpec.DeepCopyInto(&out.NameResolutionSpec)
-	if in.Features != nil {
-		in, out := &in.Features, &out.Features
-		*out = make([]FeatureSpec, len(*in))
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	in.APISpec.DeepCopyInto(&out.APISpec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationSpec.
-func (in *ConfigurationSpec) DeepCopy() *ConfigurationSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfilingSpec.
+func (in *ProfilingSpec) DeepCopy() *ProfilingSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ConfigurationSpec)
+	out := new(ProfilingSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DynamicValue) DeepCopyInto(out *DynamicValue) {
+func (in *RemoteClusterSpec) DeepCopyInto(out *RemoteClusterSpec) {
 	*out = *in
-	in.JSON.DeepCopyInto(&out.JSON)
+	if in.Apps != nil {
+		in, out := &in.Apps, &out.Apps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicValue.
-func (in *DynamicValue) DeepCopy() *DynamicValue {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterSpec.
+func (in *RemoteClusterSpec) DeepCopy() *RemoteClusterSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DynamicValue)
+	out := new(RemoteClusterSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FeatureSpec) DeepCopyInto(out *FeatureSpec) {
+func (in *ResiliencyPolicies) DeepCopyInto(out *ResiliencyPolicies) {
 	*out = *in
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = make(map[string]RetryPolicySpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Hedging != nil {
+		in, out := &in.Hedging, &out.Hedging
+		*out = make(map[string]HedgingPolicySpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Mirroring != nil {
+		in, out := &in.Mirroring, &out.Mirroring
+		*out = make(map[string]MirroringPolicySpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CircuitBreakers != nil {
+		in, out := &in.CircuitBreakers, &out.CircuitBreakers
+		*out = make(map[string]CircuitBreakerPolicySpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = make(map[string]ConcurrencyPolicySpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RetryBudgets != nil {
+		in, out := &in.RetryBudgets, &out.RetryBudgets
+		*out = make(map[string]RetryBudgetSpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Fallbacks != nil {
+		in, out := &in.Fallbacks, &out.Fallbacks
+		*out = make(map[string]FallbackPolicySpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureSpec.
-func (in *FeatureSpec) DeepCopy() *FeatureSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResiliencyPolicies.
+func (in *ResiliencyPolicies) DeepCopy() *ResiliencyPolicies {
 	if in == nil {
 		return nil
 	}
-	out := new(FeatureSpec)
+	out := new(ResiliencyPolicies)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HandlerSpec) DeepCopyInto(out *HandlerSpec) {
+func (in *ResiliencySpec) DeepCopyInto(out *ResiliencySpec) {
 	*out = *in
-	in.SelectorSpec.DeepCopyInto(&out.SelectorSpec)
+	in.Policies.DeepCopyInto(&out.Policies)
+	in.Targets.DeepCopyInto(&out.Targets)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HandlerSpec.
-func (in *HandlerSpec) DeepCopy() *HandlerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResiliencySpec.
+func (in *ResiliencySpec) DeepCopy() *ResiliencySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HandlerSpec)
+	out := new(ResiliencySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MTLSSpec) DeepCopyInto(out *MTLSSpec) {
+func (in *ResiliencyTargets) DeepCopyInto(out *ResiliencyTargets) {
 	*out = *in
+	if in.Apps != nil {
+		in, out := &in.Apps, &out.Apps
+		*out = make(map[string]AppResiliencyTarget, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make(map[string]ComponentResiliencyTarget, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MTLSSpec.
-func (in *MTLSSpec) DeepCopy() *MTLSSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResiliencyTargets.
+func (in *ResiliencyTargets) DeepCopy() *ResiliencyTargets {
 	if in == nil {
 		return nil
 	}
-	out := new(MTLSSpec)
+	out := new(ResiliencyTargets)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MetricSpec) DeepCopyInto(out *MetricSpec) {
+func (in *RetryBudgetSpec) DeepCopyInto(out *RetryBudgetSpec) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricSpec.
-func (in *MetricSpec) DeepCopy() *MetricSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryBudgetSpec.
+func (in *RetryBudgetSpec) DeepCopy() *RetryBudgetSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MetricSpec)
+	out := new(RetryBudgetSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NameResolutionSpec) DeepCopyInto(out *NameResolutionSpec) {
+func (in *RetryPolicySpec) DeepCopyInto(out *RetryPolicySpec) {
 	*out = *in
-	in.Configuration.DeepCopyInto(&out.Configuration)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NameResolutionSpec.
-func (in *NameResolutionSpec) DeepCopy() *NameResolutionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicySpec.
+func (in *RetryPolicySpec) DeepCopy() *RetryPolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NameResolutionSpec)
+	out := new(RetryPolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PipelineSpec) DeepCopyInto(out *PipelineSpec) {
+func (in *RouteResiliencyTarget) DeepCopyInto(out *RouteResiliencyTarget) {
 	*out = *in
-	if in.Handlers != nil {
-		in, out := &in.Handlers, &out.Handlers
-		*out = make([]HandlerSpec, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineSpec.
-func (in *PipelineSpec) DeepCopy() *PipelineSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteResiliencyTarget.
+func (in *RouteResiliencyTarget) DeepCopy() *RouteResiliencyTarget {
 	if in == nil {
 		return nil
 	}
-	out := new(PipelineSpec)
+	out := new(RouteResiliencyTarget)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -338,6 +1169,21 @@ func (in *SecretsScope) DeepCopyInto(out *SecretsScope) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AllowedSecretPatterns != nil {
+		in, out := &in.AllowedSecretPatterns, &out.AllowedSecretPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedSecretPatterns != nil {
+		in, out := &in.DeniedSecretPatterns, &out.DeniedSecretPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedOperations != nil {
+		in, out := &in.AllowedOperations, &out.AllowedOperations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsScope.
@@ -407,9 +1253,110 @@ func (in *SelectorSpec) DeepCopy() *SelectorSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarEnvVar) DeepCopyInto(out *SidecarEnvVar) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarEnvVar.
+func (in *SidecarEnvVar) DeepCopy() *SidecarEnvVar {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarEnvVar)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarResourcesSpec) DeepCopyInto(out *SidecarResourcesSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarResourcesSpec.
+func (in *SidecarResourcesSpec) DeepCopy() *SidecarResourcesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarResourcesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarSpec) DeepCopyInto(out *SidecarSpec) {
+	*out = *in
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]SidecarEnvVar, len(*in))
+		copy(*out, *in)
+	}
+	out.Resources = in.Resources
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSpec.
+func (in *SidecarSpec) DeepCopy() *SidecarSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaticResponseSpec) DeepCopyInto(out *StaticResponseSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaticResponseSpec.
+func (in *StaticResponseSpec) DeepCopy() *StaticResponseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StaticResponseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopicResiliencyTarget) DeepCopyInto(out *TopicResiliencyTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopicResiliencyTarget.
+func (in *TopicResiliencyTarget) DeepCopy() *TopicResiliencyTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(TopicResiliencyTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TracingSamplingRule) DeepCopyInto(out *TracingSamplingRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TracingSamplingRule.
+func (in *TracingSamplingRule) DeepCopy() *TracingSamplingRule {
+	if in == nil {
+		return nil
+	}
+	out := new(TracingSamplingRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TracingSpec) DeepCopyInto(out *TracingSpec) {
 	*out = *in
+	if in.SamplingRules != nil {
+		in, out := &in.SamplingRules, &out.SamplingRules
+		*out = make([]TracingSamplingRule, len(*in))
+		copy(*out, *in)
+	}
 	out.Zipkin = in.Zipkin
 }
 
@@ -423,6 +1370,21 @@ func (in *TracingSpec) DeepCopy() *TracingSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustDomainFederation) DeepCopyInto(out *TrustDomainFederation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustDomainFederation.
+func (in *TrustDomainFederation) DeepCopy() *TrustDomainFederation {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustDomainFederation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ZipkinSpec) DeepCopyInto(out *ZipkinSpec) {
 	*out = *in
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// InstanceReconciled is the phase of an instance whose settings were applied to the control plane.
+	InstanceReconciled = "Reconciled"
+	// InstanceFailed is the phase of an instance which can't be applied, e.g. because of an invalid spec.
+	InstanceFailed = "Failed"
+)
+
+// The control plane services of an instance.
+const (
+	ServiceOperator        = "operator"
+	ServicePlacement       = "placement"
+	ServiceSentry          = "sentry"
+	ServiceSidecarInjector = "sidecarInjector"
+)
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// DaprInstance declares the settings of the Dapr control plane of its namespace. The operator applies them to the
+// deployments of the control plane services and to the Dapr system configuration.
+type DaprInstance struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              DaprInstanceSpec `json:"spec"`
+	// +optional
+	Status DaprInstanceStatus `json:"status,omitempty"`
+}
+
+// DaprInstanceSpec is the spec of an instance. Unset settings are left unchanged.
+type DaprInstanceSpec struct {
+	// MTLSEnabled enables mTLS in the Dapr system configuration.
+	// +optional
+	MTLSEnabled *bool `json:"mtlsEnabled,omitempty"`
+	// TrustDomain is the trust domain of the workload certificates issued by sentry.
+	// +optional
+	TrustDomain string `json:"trustDomain,omitempty"`
+	// HA runs the control plane services with several replicas.
+	// +optional
+	HA *HASpec `json:"ha,omitempty"`
+	// LogLevel is the log level of the control plane services.
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+	// Services override the settings of the control plane services, by service name: operator, placement, sentry
+	// or sidecarInjector.
+	// +optional
+	Services map[string]ControlPlaneServiceSpec `json:"services,omitempty"`
+}
+
+// HASpec is the high availability mode of the control plane.
+type HASpec struct {
+	Enabled bool `json:"enabled"`
+	// Replicas is the number of replicas of the control plane services in high availability mode, 3 by default.
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+}
+
+// ControlPlaneServiceSpec overrides the settings of a control plane service.
+type ControlPlaneServiceSpec struct {
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+}
+
+// DaprInstanceStatus is the status of an instance.
+type DaprInstanceStatus struct {
+	// ObservedGeneration is the generation of the spec last applied to the control plane.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DaprInstanceList is a list of Dapr instances.
+type DaprInstanceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []DaprInstance `json:"items"`
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// RolloutProgressing is the phase of a rollout updating the deployments step by step.
+	RolloutProgressing = "Progressing"
+	// RolloutPaused is the phase of a rollout paused by its spec.
+	RolloutPaused = "Paused"
+	// RolloutCompleted is the phase of a rollout which updated the deployments of all its steps.
+	RolloutCompleted = "Completed"
+	// RolloutRolledBack is the phase of a rollout whose deployments were reverted to their previous sidecar image.
+	RolloutRolledBack = "RolledBack"
+	// RolloutFailed is the phase of a rollout which can't proceed, e.g. because of an invalid spec.
+	RolloutFailed = "Failed"
+)
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// DaprRollout rolls out a sidecar image to the Dapr enabled deployments of its namespace, a percentage of the
+// deployments at a time.
+type DaprRollout struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              DaprRolloutSpec `json:"spec"`
+	// +optional
+	Status DaprRolloutStatus `json:"status,omitempty"`
+}
+
+// DaprRolloutSpec is the spec of a rollout.
+type DaprRolloutSpec struct {
+	// SidecarImage is the image of the sidecar rolled out.
+	SidecarImage string `json:"sidecarImage"`
+	// Selector selects the deployments of the rollout among the Dapr enabled deployments of the namespace.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Steps are the percentages of the deployments running the sidecar image after each step, e.g. 10, 50, 100.
+	Steps []int32 `json:"steps"`
+	// StepInterval is the duration each step is observed before the next one, 10m by default.
+	// +optional
+	StepInterval string `json:"stepInterval,omitempty"`
+	// Analysis rolls back the deployments when their error rate exceeds the threshold after a step.
+	// +optional
+	Analysis *RolloutAnalysis `json:"analysis,omitempty"`
+	// Paused holds the rollout at its current step.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+	// Rollback reverts the updated deployments to their previous sidecar image.
+	// +optional
+	Rollback bool `json:"rollback,omitempty"`
+}
+
+// RolloutAnalysis checks the error rate of the updated deployments from the metrics of their sidecars.
+type RolloutAnalysis struct {
+	// PrometheusAddress is the address of the Prometheus server scraping the metrics of the sidecars.
+	PrometheusAddress string `json:"prometheusAddress"`
+	// Query returns the error rate of the app IDs matched by the $APP_IDS regular expression. It defaults to the rate of
+	// the 5xx responses of the HTTP API of the sidecars.
+	// +optional
+	Query string `json:"query,omitempty"`
+	// MaxErrorRate is the highest error rate, between 0 and 1, the updated deployments can have.
+	MaxErrorRate string `json:"maxErrorRate"`
+}
+
+// DaprRolloutStatus is the status of a rollout.
+type DaprRolloutStatus struct {
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// Step is the number of steps completed.
+	// +optional
+	Step int32 `json:"step,omitempty"`
+	// PreviousImages are the sidecar images of the updated deployments before the rollout, by deployment name.
+	// Empty images are the default image of the injector.
+	// +optional
+	PreviousImages map[string]string `json:"previousImages,omitempty"`
+	// +optional
+	LastStepTime *metav1.Time `json:"lastStepTime,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DaprRolloutList is a list of Dapr rollouts.
+type DaprRolloutList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []DaprRollout `json:"items"`
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apphealth
+
+import (
+	"sync/atomic"
+
+	"github.com/dapr/kit/logger"
+)
+
+var log = logger.NewLogger("dapr.apphealth")
+
+// AppHealth holds the health of the app as reported by the app health checks.
+// A nil AppHealth reports the app as healthy.
+type AppHealth struct {
+	healthy int32
+}
+
+// New returns a new AppHealth reporting the app as healthy until a health check reports otherwise.
+func New() *AppHealth {
+	return &AppHealth{
+		healthy: 1,
+	}
+}
+
+// IsHealthy returns true if the app is healthy.
+func (h *AppHealth) IsHealthy() bool {
+	if h == nil {
+		return true
+	}
+	return atomic.LoadInt32(&h.healthy) == 1
+}
+
+// SetHealthy sets the health of the app.
+func (h *AppHealth) SetHealthy(healthy bool) {
+	var v int32
+	if healthy {
+		v = 1
+	}
+
+	if old := atomic.SwapInt32(&h.healthy, v); old != v {
+		if healthy {
+			log.Info("app is healthy, resuming delivery of invocations and events")
+		} else {
+			log.Warn("app is unhealthy, suspending delivery of invocations and events")
+		}
+	}
+}
+
+// Watch updates the health of the app with the results emitted by a health check.
+func (h *AppHealth) Watch(ch <-chan bool) {
+	go func() {
+		for healthy := range ch {
+			h.SetHealthy(healthy)
+		}
+	}()
+}
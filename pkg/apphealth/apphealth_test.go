@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apphealth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppHealth(t *testing.T) {
+	t.Run("healthy by default", func(t *testing.T) {
+		assert.True(t, New().IsHealthy())
+	})
+
+	t.Run("nil app health is healthy", func(t *testing.T) {
+		var h *AppHealth
+		assert.True(t, h.IsHealthy())
+	})
+
+	t.Run("set healthy", func(t *testing.T) {
+		h := New()
+		h.SetHealthy(false)
+		assert.False(t, h.IsHealthy())
+		h.SetHealthy(true)
+		assert.True(t, h.IsHealthy())
+	})
+
+	t.Run("watch health check", func(t *testing.T) {
+		h := New()
+		ch := make(chan bool)
+		h.Watch(ch)
+
+		ch <- false
+		assert.Eventually(t, func() bool { return !h.IsHealthy() }, time.Second, 10*time.Millisecond)
+		ch <- true
+		assert.Eventually(t, h.IsHealthy, time.Second, 10*time.Millisecond)
+		close(ch)
+	})
+}
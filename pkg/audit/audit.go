@@ -0,0 +1,234 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/kit/logger"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+var log = logger.NewLogger("dapr.audit")
+
+// Types of the audit events.
+const (
+	// EventAuthenticationFailed is emitted when a request is rejected because its api token or bearer token is
+	// missing or invalid.
+	EventAuthenticationFailed = "AuthenticationFailed"
+	// EventAccessDenied is emitted when a request is rejected by an access control list, an API access rule or an
+	// API policy.
+	EventAccessDenied = "AccessDenied"
+	// EventComponentUpdated is emitted when a component is created or updated.
+	EventComponentUpdated = "ComponentUpdated"
+	// EventCertificateIssued is emitted when sentry signs a workload certificate.
+	EventCertificateIssued = "CertificateIssued"
+	// EventCertificateDenied is emitted when sentry rejects a certificate signing request.
+	EventCertificateDenied = "CertificateDenied"
+)
+
+// Sources of the audit events.
+const (
+	SourceDaprd    = "daprd"
+	SourceOperator = "operator"
+	SourceSentry   = "sentry"
+)
+
+// Types of the audit sinks.
+const (
+	SinkFile   = "file"
+	SinkPubsub = "pubsub"
+	SinkOTLP   = "otlp"
+)
+
+// eventQueueSize is the number of events buffered for the sinks. Events are dropped when the sinks don't keep up,
+// so that emitting an event never blocks the request being audited.
+const eventQueueSize = 1024
+
+// Event is an administrative or security-relevant event.
+type Event struct {
+	Time time.Time `json:"time"`
+	Type string    `json:"type"`
+	// Source is the process emitting the event: daprd, operator or sentry.
+	Source string `json:"source"`
+	// ID identifies the emitting process, e.g. the app id of daprd.
+	ID string `json:"id,omitempty"`
+	// Subject is the identity at the origin of the event, e.g. the caller of a request.
+	Subject string `json:"subject,omitempty"`
+	// Resource is the target of the event, e.g. the method called or the component updated.
+	Resource string `json:"resource,omitempty"`
+	// Reason explains the outcome of the event.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Sink writes the audit events.
+type Sink interface {
+	Write(events []Event) error
+	Close() error
+}
+
+// PublishFunc publishes an audit event to a topic of a pubsub.
+type PublishFunc func(pubsubName, topic string, data []byte) error
+
+// Auditor emits the audit events of a process to its sinks.
+type Auditor struct {
+	source string
+	id     string
+	sinks  []Sink
+
+	lock    sync.RWMutex
+	enabled bool
+	queue   chan Event
+	done    chan struct{}
+}
+
+// Default is the auditor of the process. Events are discarded until it's initialized.
+var Default = New()
+
+// New returns a new auditor.
+func New() *Auditor {
+	return &Auditor{}
+}
+
+// Init enables the auditor with the sinks of the spec. publish is used by the pubsub sinks, which are supported
+// only when it's set.
+func (a *Auditor) Init(source, id string, spec config.AuditSpec, publish PublishFunc) error {
+	if !spec.Enabled {
+		return nil
+	}
+
+	sinks := make([]Sink, 0, len(spec.Sinks))
+	for _, s := range spec.Sinks {
+		sink, err := newSink(source, s, publish)
+		if err != nil {
+			for _, created := range sinks {
+				created.Close()
+			}
+			return err
+		}
+		sinks = append(sinks, sink)
+	}
+	if len(sinks) == 0 {
+		return errors.New("audit is enabled without sinks")
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.source = source
+	a.id = id
+	a.sinks = sinks
+	a.queue = make(chan Event, eventQueueSize)
+	a.done = make(chan struct{})
+	a.enabled = true
+	go a.run(a.queue, a.done)
+	return nil
+}
+
+func newSink(source string, spec config.AuditSinkSpec, publish PublishFunc) (Sink, error) {
+	switch spec.Type {
+	case SinkFile:
+		return newFileSink(spec.Output)
+	case SinkPubsub:
+		if publish == nil {
+			return nil, errors.Errorf("the %s audit sink is not supported by %s", SinkPubsub, source)
+		}
+		if spec.PubsubName == "" || spec.Topic == "" {
+			return nil, errors.Errorf("the %s audit sink requires a pubsub name and a topic", SinkPubsub)
+		}
+		return &pubsubSink{pubsubName: spec.PubsubName, topic: spec.Topic, publish: publish}, nil
+	case SinkOTLP:
+		return newOTLPSink(source, spec.Endpoint, spec.Headers)
+	default:
+		return nil, errors.Errorf("unknown audit sink type %q", spec.Type)
+	}
+}
+
+// IsEnabled returns true if the auditor is initialized.
+func (a *Auditor) IsEnabled() bool {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return a.enabled
+}
+
+// Emit queues an event for the sinks. The event is dropped when the queue is full.
+func (a *Auditor) Emit(event Event) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	if !a.enabled {
+		return
+	}
+
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	event.Source = a.source
+	event.ID = a.id
+
+	select {
+	case a.queue <- event:
+	default:
+		log.Warnf("audit event queue is full, dropped %s event", event.Type)
+	}
+}
+
+// Close writes the queued events and closes the sinks.
+func (a *Auditor) Close() {
+	a.lock.Lock()
+	if !a.enabled {
+		a.lock.Unlock()
+		return
+	}
+	a.enabled = false
+	close(a.queue)
+	a.lock.Unlock()
+
+	<-a.done
+	for _, s := range a.sinks {
+		if err := s.Close(); err != nil {
+			log.Warnf("error closing audit sink: %s", err)
+		}
+	}
+}
+
+// run writes the queued events to the sinks, batching the events queued while writing.
+func (a *Auditor) run(queue chan Event, done chan struct{}) {
+	defer close(done)
+
+	for event := range queue {
+		batch := []Event{event}
+	drain:
+		for len(batch) < eventQueueSize {
+			select {
+			case e, ok := <-queue:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, e)
+			default:
+				break drain
+			}
+		}
+
+		for _, s := range a.sinks {
+			if err := s.Write(batch); err != nil {
+				log.Warnf("error writing %d audit events: %s", len(batch), err)
+			}
+		}
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func readEvents(t *testing.T, path string) []Event {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestInit(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		a := New()
+		assert.NoError(t, a.Init(SourceDaprd, "app", config.AuditSpec{}, nil))
+		assert.False(t, a.IsEnabled())
+	})
+
+	t.Run("no sinks", func(t *testing.T) {
+		a := New()
+		assert.Error(t, a.Init(SourceDaprd, "app", config.AuditSpec{Enabled: true}, nil))
+	})
+
+	t.Run("unknown sink", func(t *testing.T) {
+		a := New()
+		err := a.Init(SourceDaprd, "app", config.AuditSpec{
+			Enabled: true,
+			Sinks:   []config.AuditSinkSpec{{Type: "syslog"}},
+		}, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("pubsub sink without publisher", func(t *testing.T) {
+		a := New()
+		err := a.Init(SourceSentry, "", config.AuditSpec{
+			Enabled: true,
+			Sinks:   []config.AuditSinkSpec{{Type: SinkPubsub, PubsubName: "pubsub", Topic: "audit"}},
+		}, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("pubsub sink without topic", func(t *testing.T) {
+		a := New()
+		err := a.Init(SourceDaprd, "app", config.AuditSpec{
+			Enabled: true,
+			Sinks:   []config.AuditSinkSpec{{Type: SinkPubsub, PubsubName: "pubsub"}},
+		}, func(pubsubName, topic string, data []byte) error { return nil })
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid otlp endpoint", func(t *testing.T) {
+		a := New()
+		err := a.Init(SourceDaprd, "app", config.AuditSpec{
+			Enabled: true,
+			Sinks:   []config.AuditSinkSpec{{Type: SinkOTLP, Endpoint: "collector"}},
+		}, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestEmit(t *testing.T) {
+	t.Run("not initialized", func(t *testing.T) {
+		a := New()
+		a.Emit(Event{Type: EventAccessDenied})
+		a.Close()
+	})
+
+	t.Run("file sink", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		a := New()
+		require.NoError(t, a.Init(SourceDaprd, "myapp", config.AuditSpec{
+			Enabled: true,
+			Sinks:   []config.AuditSinkSpec{{Type: SinkFile, Output: path}},
+		}, nil))
+
+		a.Emit(Event{Type: EventAuthenticationFailed, Resource: "GET /v1.0/state/store", Reason: "api token missing"})
+		a.Emit(Event{Type: EventAccessDenied, Subject: "checkout", Resource: "GetState orders"})
+		a.Close()
+
+		events := readEvents(t, path)
+		require.Len(t, events, 2)
+		assert.Equal(t, EventAuthenticationFailed, events[0].Type)
+		assert.Equal(t, SourceDaprd, events[0].Source)
+		assert.Equal(t, "myapp", events[0].ID)
+		assert.Equal(t, "api token missing", events[0].Reason)
+		assert.False(t, events[0].Time.IsZero())
+		assert.Equal(t, "checkout", events[1].Subject)
+
+		// Events emitted after close are discarded.
+		a.Emit(Event{Type: EventAccessDenied})
+		assert.Len(t, readEvents(t, path), 2)
+	})
+
+	t.Run("pubsub sink", func(t *testing.T) {
+		var lock sync.Mutex
+		var published []Event
+		publish := func(pubsubName, topic string, data []byte) error {
+			assert.Equal(t, "pubsub", pubsubName)
+			assert.Equal(t, "audit", topic)
+
+			var e Event
+			require.NoError(t, json.Unmarshal(data, &e))
+			lock.Lock()
+			published = append(published, e)
+			lock.Unlock()
+			return nil
+		}
+
+		a := New()
+		require.NoError(t, a.Init(SourceDaprd, "myapp", config.AuditSpec{
+			Enabled: true,
+			Sinks:   []config.AuditSinkSpec{{Type: SinkPubsub, PubsubName: "pubsub", Topic: "audit"}},
+		}, publish))
+		a.Emit(Event{Type: EventComponentUpdated, Resource: "statestore (state.redis)"})
+		a.Close()
+
+		require.Len(t, published, 1)
+		assert.Equal(t, "statestore (state.redis)", published[0].Resource)
+	})
+
+	t.Run("otlp sink", func(t *testing.T) {
+		var req otlpLogsRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, otlpLogsPath, r.URL.Path)
+			assert.Equal(t, "secret", r.Header.Get("x-api-key"))
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		}))
+		defer server.Close()
+
+		a := New()
+		require.NoError(t, a.Init(SourceSentry, "", config.AuditSpec{
+			Enabled: true,
+			Sinks: []config.AuditSinkSpec{{
+				Type:     SinkOTLP,
+				Endpoint: server.URL,
+				Headers:  map[string]string{"x-api-key": "secret"},
+			}},
+		}, nil))
+		a.Emit(Event{Type: EventCertificateIssued, Subject: "default/myapp", Resource: "public"})
+		a.Close()
+
+		require.Len(t, req.ResourceLogs, 1)
+		assert.Equal(t, SourceSentry, req.ResourceLogs[0].Resource.Attributes[0].Value.StringValue)
+		records := req.ResourceLogs[0].ScopeLogs[0].LogRecords
+		require.Len(t, records, 1)
+		assert.Equal(t, EventCertificateIssued, records[0].Body.StringValue)
+		assert.Contains(t, records[0].Attributes, otlpAttribute{Key: "event.subject", Value: otlpStringValue{StringValue: "default/myapp"}})
+	})
+}
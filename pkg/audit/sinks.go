@@ -0,0 +1,238 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+)
+
+const (
+	// otlpLogsPath is the path of the logs service of OTLP/HTTP collectors.
+	otlpLogsPath = "/v1/logs"
+	otlpTimeout  = 10 * time.Second
+	// otlpSeverityInfo is the INFO severity number of OTLP log records.
+	otlpSeverityInfo = 9
+)
+
+// fileSink appends the events to a file as JSON lines, or writes them to stdout.
+type fileSink struct {
+	out io.Writer
+}
+
+func newFileSink(output string) (*fileSink, error) {
+	if output == "" {
+		return &fileSink{out: os.Stdout}, nil
+	}
+
+	f, err := os.OpenFile(output, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open audit log output")
+	}
+	return &fileSink{out: f}, nil
+}
+
+func (s *fileSink) Write(events []Event) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	_, err := s.out.Write(buf.Bytes())
+	return err
+}
+
+func (s *fileSink) Close() error {
+	if f, ok := s.out.(*os.File); ok && f != os.Stdout {
+		return f.Close()
+	}
+	return nil
+}
+
+// pubsubSink publishes each event to a topic.
+type pubsubSink struct {
+	pubsubName string
+	topic      string
+	publish    PublishFunc
+}
+
+func (s *pubsubSink) Write(events []Event) error {
+	var errs error
+	for _, e := range events {
+		b, err := json.Marshal(e)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		if err = s.publish(s.pubsubName, s.topic, b); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs
+}
+
+func (s *pubsubSink) Close() error {
+	return nil
+}
+
+// otlpSink exports the events as log records to an OpenTelemetry collector with OTLP/HTTP.
+type otlpSink struct {
+	endpoint    string
+	headers     map[string]string
+	serviceName string
+	client      *http.Client
+}
+
+func newOTLPSink(serviceName, endpoint string, headers map[string]string) (*otlpSink, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, errors.Errorf("invalid OTLP endpoint address %q", endpoint)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = otlpLogsPath
+	}
+
+	return &otlpSink{
+		endpoint:    u.String(),
+		headers:     headers,
+		serviceName: serviceName,
+		client:      &http.Client{Timeout: otlpTimeout},
+	}, nil
+}
+
+func (s *otlpSink) Write(events []Event) error {
+	b, err := json.Marshal(s.newRequest(events))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to export audit events")
+	}
+	defer resp.Body.Close()
+	// The body is drained so that the connection is reused.
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("failed to export audit events: status %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+func (s *otlpSink) Close() error {
+	return nil
+}
+
+// The types below are the JSON encoding of the OTLP logs service request.
+type (
+	otlpLogsRequest struct {
+		ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+	}
+
+	otlpResourceLogs struct {
+		Resource  otlpResource    `json:"resource"`
+		ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+	}
+
+	otlpResource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	}
+
+	otlpScopeLogs struct {
+		Scope      otlpScope       `json:"scope"`
+		LogRecords []otlpLogRecord `json:"logRecords"`
+	}
+
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+
+	otlpLogRecord struct {
+		TimeUnixNano   string          `json:"timeUnixNano"`
+		SeverityNumber int             `json:"severityNumber"`
+		SeverityText   string          `json:"severityText"`
+		Body           otlpStringValue `json:"body"`
+		Attributes     []otlpAttribute `json:"attributes"`
+	}
+
+	otlpAttribute struct {
+		Key   string          `json:"key"`
+		Value otlpStringValue `json:"value"`
+	}
+
+	otlpStringValue struct {
+		StringValue string `json:"stringValue"`
+	}
+)
+
+func (s *otlpSink) newRequest(events []Event) *otlpLogsRequest {
+	scope := otlpScopeLogs{
+		Scope:      otlpScope{Name: "dapr.audit"},
+		LogRecords: make([]otlpLogRecord, 0, len(events)),
+	}
+	for _, e := range events {
+		attributes := []otlpAttribute{}
+		for _, kv := range [][2]string{
+			{"event.type", e.Type},
+			{"event.source", e.Source},
+			{"event.id", e.ID},
+			{"event.subject", e.Subject},
+			{"event.resource", e.Resource},
+			{"event.reason", e.Reason},
+		} {
+			if kv[1] != "" {
+				attributes = append(attributes, otlpAttribute{Key: kv[0], Value: otlpStringValue{StringValue: kv[1]}})
+			}
+		}
+
+		scope.LogRecords = append(scope.LogRecords, otlpLogRecord{
+			TimeUnixNano:   strconv.FormatInt(e.Time.UnixNano(), 10),
+			SeverityNumber: otlpSeverityInfo,
+			SeverityText:   "INFO",
+			Body:           otlpStringValue{StringValue: e.Type},
+			Attributes:     attributes,
+		})
+	}
+
+	var attributes []otlpAttribute
+	if s.serviceName != "" {
+		attributes = append(attributes, otlpAttribute{Key: "service.name", Value: otlpStringValue{StringValue: s.serviceName}})
+	}
+	return &otlpLogsRequest{
+		ResourceLogs: []otlpResourceLogs{{
+			Resource:  otlpResource{Attributes: attributes},
+			ScopeLogs: []otlpScopeLogs{scope},
+		}},
+	}
+}
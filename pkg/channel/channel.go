@@ -15,6 +15,7 @@ package channel
 
 import (
 	"context"
+	"time"
 
 	"github.com/dapr/dapr/pkg/config"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
@@ -25,6 +26,18 @@ const (
 	DefaultChannelAddress = "127.0.0.1"
 )
 
+// ConnectionPoolConfig holds the settings of the connections opened to user code.
+// Zero values keep the defaults of the underlying client.
+type ConnectionPoolConfig struct {
+	// MaxConns is the maximum number of connections opened to the app. Requests exceeding it wait for
+	// a pooled connection to be released instead of dialing a new one.
+	MaxConns int
+	// MaxIdleConnDuration is the duration after which idle connections are closed.
+	MaxIdleConnDuration time.Duration
+	// KeepAliveInterval is the interval of TCP keep-alive probes for HTTP apps and of HTTP/2 pings for gRPC apps.
+	KeepAliveInterval time.Duration
+}
+
 // AppChannel is an abstraction over communications with user code.
 type AppChannel interface {
 	GetBaseAddress() string
@@ -16,6 +16,7 @@ package grpc
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -24,6 +25,7 @@ import (
 
 	"github.com/dapr/dapr/pkg/channel"
 	"github.com/dapr/dapr/pkg/config"
+	diag "github.com/dapr/dapr/pkg/diagnostics"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
 	internalv1pb "github.com/dapr/dapr/pkg/proto/internals/v1"
 	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
@@ -57,6 +59,13 @@ func CreateLocalChannel(port, maxConcurrency int, conn *grpc.ClientConn, spec co
 	return c
 }
 
+// CreateUnixSocketChannel creates a gRPC channel with user code listening on a unix domain socket.
+func CreateUnixSocketChannel(socket string, maxConcurrency int, conn *grpc.ClientConn, spec config.TracingSpec, maxRequestBodySize int, readBufferSize int) *Channel {
+	c := CreateLocalChannel(0, maxConcurrency, conn, spec, maxRequestBodySize, readBufferSize)
+	c.baseAddress = "unix://" + socket
+	return c
+}
+
 // GetBaseAddress returns the application base address.
 func (g *Channel) GetBaseAddress() string {
 	return g.baseAddress
@@ -99,7 +108,7 @@ func (g *Channel) invokeMethodV1(ctx context.Context, req *invokev1.InvokeMethod
 	}
 
 	// Prepare gRPC Metadata
-	ctx = metadata.NewOutgoingContext(context.Background(), grpcMetadata)
+	grpcCtx := metadata.NewOutgoingContext(context.Background(), grpcMetadata)
 
 	var header, trailer metadata.MD
 
@@ -107,7 +116,9 @@ func (g *Channel) invokeMethodV1(ctx context.Context, req *invokev1.InvokeMethod
 	opts = append(opts, grpc.Header(&header), grpc.Trailer(&trailer),
 		grpc.MaxCallSendMsgSize(g.maxRequestBodySize*1024*1024), grpc.MaxCallRecvMsgSize(g.maxRequestBodySize*1024*1024))
 
-	resp, err := clientV1.OnInvoke(ctx, req.Message(), opts...)
+	start := time.Now()
+	resp, err := clientV1.OnInvoke(grpcCtx, req.Message(), opts...)
+	elapsed := time.Since(start)
 
 	if g.ch != nil {
 		<-g.ch
@@ -125,5 +136,21 @@ func (g *Channel) invokeMethodV1(ctx context.Context, req *invokev1.InvokeMethod
 
 	rsp.WithHeaders(header).WithTrailers(trailer)
 
+	var respSize int64
+	if resp != nil {
+		respSize = int64(len(resp.GetData().GetValue()))
+	}
+	diag.DefaultAccessLog.Log(ctx, diag.AccessLogRecord{
+		Source:        diag.AccessLogSourceApp,
+		Protocol:      "grpc",
+		Method:        req.Message().GetHttpExtension().GetVerb().String(),
+		Path:          req.Message().GetMethod(),
+		Status:        codes.Code(rsp.Status().Code).String(),
+		Latency:       elapsed,
+		RequestBytes:  int64(len(req.Message().GetData().GetValue())),
+		ResponseBytes: respSize,
+		RemoteAddr:    g.baseAddress,
+	})
+
 	return rsp.WithMessage(resp), nil
 }
@@ -17,6 +17,7 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 	"time"
@@ -34,6 +35,7 @@ import (
 	diag "github.com/dapr/dapr/pkg/diagnostics"
 	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	http_middleware "github.com/dapr/dapr/pkg/middleware/http"
 	commonv1pb "github.com/dapr/dapr/pkg/proto/common/v1"
 	internalv1pb "github.com/dapr/dapr/pkg/proto/internals/v1"
 	auth "github.com/dapr/dapr/pkg/runtime/security"
@@ -46,6 +48,12 @@ const (
 	httpsScheme    = "https"
 
 	appConfigEndpoint = "dapr/config"
+
+	// maxConnWaitTimeout is how long a request waits for a pooled connection when the max number of connections is reached.
+	maxConnWaitTimeout = 30 * time.Second
+	// clientErrorUserValueKey is the key of the error of the HTTP client in the user values of the requests sent
+	// through the middleware pipeline.
+	clientErrorUserValueKey = "daprClientError"
 )
 
 // Channel is an HTTP implementation of an AppChannel.
@@ -57,11 +65,13 @@ type Channel struct {
 	appHeaderToken      string
 	json                jsoniter.API
 	maxResponseBodySize int
+	pipelineHandler     fasthttp.RequestHandler
 }
 
-// CreateLocalChannel creates an HTTP AppChannel
+// CreateLocalChannel creates an HTTP AppChannel.
+// The middleware of the pipeline are applied to every request sent to the app.
 // nolint:gosec
-func CreateLocalChannel(port, maxConcurrency int, spec config.TracingSpec, sslEnabled bool, maxRequestBodySize int, readBufferSize int) (channel.AppChannel, error) {
+func CreateLocalChannel(port, maxConcurrency int, spec config.TracingSpec, sslEnabled bool, maxRequestBodySize int, readBufferSize int, pool channel.ConnectionPoolConfig, pipeline http_middleware.Pipeline) (channel.AppChannel, error) {
 	scheme := httpScheme
 	if sslEnabled {
 		scheme = httpsScheme
@@ -86,10 +96,49 @@ func CreateLocalChannel(port, maxConcurrency int, spec config.TracingSpec, sslEn
 		c.client.TLSConfig = &tls.Config{InsecureSkipVerify: true}
 	}
 
+	if pool.MaxConns > 0 {
+		c.client.MaxConnsPerHost = pool.MaxConns
+		c.client.MaxConnWaitTimeout = maxConnWaitTimeout
+	}
+
+	if pool.MaxIdleConnDuration > 0 {
+		c.client.MaxIdleConnDuration = pool.MaxIdleConnDuration
+	}
+
+	if pool.KeepAliveInterval > 0 {
+		dialer := &net.Dialer{KeepAlive: pool.KeepAliveInterval}
+		c.client.Dial = func(addr string) (net.Conn, error) {
+			return dialer.Dial("tcp", addr)
+		}
+	}
+
 	if maxConcurrency > 0 {
 		c.ch = make(chan int, maxConcurrency)
 	}
 
+	if len(pipeline.Handlers) > 0 {
+		c.pipelineHandler = pipeline.Apply(func(ctx *fasthttp.RequestCtx) {
+			if err := c.client.Do(&ctx.Request, &ctx.Response); err != nil {
+				ctx.SetUserValue(clientErrorUserValueKey, err)
+			}
+		})
+	}
+
+	return c, nil
+}
+
+// CreateUnixSocketChannel creates an HTTP AppChannel to an app listening on a unix domain socket.
+func CreateUnixSocketChannel(socket string, maxConcurrency int, spec config.TracingSpec, maxRequestBodySize int, readBufferSize int, pool channel.ConnectionPoolConfig, pipeline http_middleware.Pipeline) (channel.AppChannel, error) {
+	ch, err := CreateLocalChannel(0, maxConcurrency, spec, false, maxRequestBodySize, readBufferSize, pool, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	c := ch.(*Channel)
+	c.baseAddress = fmt.Sprintf("%s://%s", httpScheme, channel.DefaultChannelAddress)
+	c.client.Dial = func(string) (net.Conn, error) {
+		return net.Dial("unix", socket)
+	}
 	return c, nil
 }
 
@@ -165,16 +214,18 @@ func (h *Channel) invokeMethodV1(ctx context.Context, req *invokev1.InvokeMethod
 
 	// Send request to user application
 	resp := fasthttp.AcquireResponse()
-	err := h.client.Do(channelReq, resp)
+	err := h.do(channelReq, resp)
 	defer func() {
 		fasthttp.ReleaseRequest(channelReq)
 		fasthttp.ReleaseResponse(resp)
 	}()
 
-	elapsedMs := float64(time.Since(startRequest) / time.Millisecond)
+	elapsed := time.Since(startRequest)
+	elapsedMs := float64(elapsed) / float64(time.Millisecond)
 
 	if err != nil {
 		diag.DefaultHTTPMonitoring.ClientRequestCompleted(ctx, verb, req.Message().GetMethod(), strconv.Itoa(nethttp.StatusInternalServerError), int64(resp.Header.ContentLength()), elapsedMs)
+		h.logAccess(ctx, req, verb, strconv.Itoa(nethttp.StatusInternalServerError), 0, elapsed)
 		return nil, err
 	}
 
@@ -184,10 +235,41 @@ func (h *Channel) invokeMethodV1(ctx context.Context, req *invokev1.InvokeMethod
 
 	rsp := h.parseChannelResponse(req, resp)
 	diag.DefaultHTTPMonitoring.ClientRequestCompleted(ctx, verb, req.Message().GetMethod(), strconv.Itoa(int(rsp.Status().Code)), int64(resp.Header.ContentLength()), elapsedMs)
+	h.logAccess(ctx, req, verb, strconv.Itoa(int(rsp.Status().Code)), int64(len(resp.Body())), elapsed)
 
 	return rsp, nil
 }
 
+// do sends a request to the app, through the middleware pipeline of the channel when there is one.
+func (h *Channel) do(req *fasthttp.Request, resp *fasthttp.Response) error {
+	if h.pipelineHandler == nil {
+		return h.client.Do(req, resp)
+	}
+
+	var ctx fasthttp.RequestCtx
+	ctx.Init(req, nil, nil)
+	h.pipelineHandler(&ctx)
+	ctx.Response.CopyTo(resp)
+	if err, ok := ctx.UserValue(clientErrorUserValueKey).(error); ok {
+		return err
+	}
+	return nil
+}
+
+func (h *Channel) logAccess(ctx context.Context, req *invokev1.InvokeMethodRequest, verb, status string, respSize int64, elapsed time.Duration) {
+	diag.DefaultAccessLog.Log(ctx, diag.AccessLogRecord{
+		Source:        diag.AccessLogSourceApp,
+		Protocol:      "http",
+		Method:        verb,
+		Path:          req.Message().GetMethod(),
+		Status:        status,
+		Latency:       elapsed,
+		RequestBytes:  int64(len(req.Message().Data.GetValue())),
+		ResponseBytes: respSize,
+		RemoteAddr:    h.baseAddress,
+	})
+}
+
 func (h *Channel) constructRequest(ctx context.Context, req *invokev1.InvokeMethodRequest) *fasthttp.Request {
 	channelReq := fasthttp.AcquireRequest()
 
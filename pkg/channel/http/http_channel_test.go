@@ -17,16 +17,21 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp"
 
+	"github.com/dapr/dapr/pkg/channel"
 	"github.com/dapr/dapr/pkg/config"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	http_middleware "github.com/dapr/dapr/pkg/middleware/http"
 )
 
 type testConcurrencyHandler struct {
@@ -329,7 +334,7 @@ func TestAppToken(t *testing.T) {
 
 func TestCreateChannel(t *testing.T) {
 	t.Run("ssl scheme", func(t *testing.T) {
-		ch, err := CreateLocalChannel(3000, 0, config.TracingSpec{}, true, 4, 4)
+		ch, err := CreateLocalChannel(3000, 0, config.TracingSpec{}, true, 4, 4, channel.ConnectionPoolConfig{}, http_middleware.Pipeline{})
 		assert.NoError(t, err)
 
 		b := ch.GetBaseAddress()
@@ -337,10 +342,127 @@ func TestCreateChannel(t *testing.T) {
 	})
 
 	t.Run("non-ssl scheme", func(t *testing.T) {
-		ch, err := CreateLocalChannel(3000, 0, config.TracingSpec{}, false, 4, 4)
+		ch, err := CreateLocalChannel(3000, 0, config.TracingSpec{}, false, 4, 4, channel.ConnectionPoolConfig{}, http_middleware.Pipeline{})
 		assert.NoError(t, err)
 
 		b := ch.GetBaseAddress()
 		assert.Equal(t, b, "http://127.0.0.1:3000")
 	})
+	t.Run("connection pool", func(t *testing.T) {
+		pool := channel.ConnectionPoolConfig{
+			MaxConns:            100,
+			MaxIdleConnDuration: 30 * time.Second,
+			KeepAliveInterval:   15 * time.Second,
+		}
+		ch, err := CreateLocalChannel(3000, 0, config.TracingSpec{}, false, 4, 4, pool, http_middleware.Pipeline{})
+		assert.NoError(t, err)
+
+		client := ch.(*Channel).client
+		assert.Equal(t, 100, client.MaxConnsPerHost)
+		assert.Equal(t, maxConnWaitTimeout, client.MaxConnWaitTimeout)
+		assert.Equal(t, 30*time.Second, client.MaxIdleConnDuration)
+		assert.NotNil(t, client.Dial)
+	})
+
+	t.Run("unix domain socket", func(t *testing.T) {
+		socket := filepath.Join(t.TempDir(), "app.socket")
+		listener, err := net.Listen("unix", socket)
+		assert.NoError(t, err)
+		server := &http.Server{Handler: &testContentTypeHandler{}}
+		go server.Serve(listener)
+		defer server.Close()
+
+		ch, err := CreateUnixSocketChannel(socket, 0, config.TracingSpec{}, 4, 4, channel.ConnectionPoolConfig{}, http_middleware.Pipeline{})
+		assert.NoError(t, err)
+		assert.Equal(t, "http://127.0.0.1", ch.GetBaseAddress())
+
+		req := invokev1.NewInvokeMethodRequest("method")
+		req.WithRawData([]byte{}, "text/plain")
+		req.WithHTTPExtension(http.MethodPost, "")
+		resp, err := ch.InvokeMethod(context.Background(), req)
+		assert.NoError(t, err)
+		_, body := resp.RawData()
+		assert.Equal(t, "text/plain", string(body))
+	})
+
+	t.Run("default connection pool", func(t *testing.T) {
+		ch, err := CreateLocalChannel(3000, 0, config.TracingSpec{}, false, 4, 4, channel.ConnectionPoolConfig{}, http_middleware.Pipeline{})
+		assert.NoError(t, err)
+
+		client := ch.(*Channel).client
+		assert.Equal(t, 1000000, client.MaxConnsPerHost)
+		assert.Zero(t, client.MaxConnWaitTimeout)
+		assert.Nil(t, client.Dial)
+	})
+}
+
+func TestAppPipeline(t *testing.T) {
+	testServer := httptest.NewServer(&testHandlerHeaders{})
+	defer testServer.Close()
+
+	newRequest := func() *invokev1.InvokeMethodRequest {
+		req := invokev1.NewInvokeMethodRequest("method")
+		req.WithRawData([]byte{}, "text/plain")
+		req.WithHTTPExtension(http.MethodPost, "")
+		return req
+	}
+
+	t.Run("middleware applied to the requests sent to the app", func(t *testing.T) {
+		pipeline := http_middleware.Pipeline{
+			Handlers: []http_middleware.Middleware{
+				func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+					return func(ctx *fasthttp.RequestCtx) {
+						ctx.Request.Header.Set("X-Internal-Auth", "secret")
+						next(ctx)
+					}
+				},
+			},
+		}
+		ch, err := CreateLocalChannel(3000, 0, config.TracingSpec{}, false, 4, 4, channel.ConnectionPoolConfig{}, pipeline)
+		assert.NoError(t, err)
+		ch.(*Channel).baseAddress = testServer.URL
+
+		resp, err := ch.InvokeMethod(context.Background(), newRequest())
+		assert.NoError(t, err)
+
+		var actual map[string]string
+		_, body := resp.RawData()
+		assert.NoError(t, json.Unmarshal(body, &actual))
+		assert.Equal(t, "secret", actual["X-Internal-Auth"])
+	})
+
+	t.Run("middleware responding without calling the app", func(t *testing.T) {
+		pipeline := http_middleware.Pipeline{
+			Handlers: []http_middleware.Middleware{
+				func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+					return func(ctx *fasthttp.RequestCtx) {
+						ctx.SetStatusCode(fasthttp.StatusForbidden)
+					}
+				},
+			},
+		}
+		ch, err := CreateLocalChannel(3000, 0, config.TracingSpec{}, false, 4, 4, channel.ConnectionPoolConfig{}, pipeline)
+		assert.NoError(t, err)
+		ch.(*Channel).baseAddress = testServer.URL
+
+		resp, err := ch.InvokeMethod(context.Background(), newRequest())
+		assert.NoError(t, err)
+		assert.Equal(t, int32(fasthttp.StatusForbidden), resp.Status().Code)
+	})
+
+	t.Run("errors of the client", func(t *testing.T) {
+		pipeline := http_middleware.Pipeline{
+			Handlers: []http_middleware.Middleware{
+				func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+					return next
+				},
+			},
+		}
+		ch, err := CreateLocalChannel(3000, 0, config.TracingSpec{}, false, 4, 4, channel.ConnectionPoolConfig{}, pipeline)
+		assert.NoError(t, err)
+		ch.(*Channel).baseAddress = "http://127.0.0.1:0"
+
+		_, err = ch.InvokeMethod(context.Background(), newRequest())
+		assert.Error(t, err)
+	})
 }
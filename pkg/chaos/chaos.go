@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package chaos injects faults in the operations of the sidecar to test the resiliency policies.
+package chaos
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/kit/logger"
+)
+
+var log = logger.NewLogger("dapr.chaos")
+
+// Operation is a kind of operation faults are injected in.
+type Operation string
+
+const (
+	// Invocation is a service invocation, whose target is the invoked app.
+	Invocation Operation = "invocation"
+	// State is an operation on a state store.
+	State Operation = "state"
+	// PubSub is the publication of an event to a pubsub, or the delivery of an event of a pubsub to the app.
+	PubSub Operation = "pubsub"
+)
+
+// ErrAborted is returned for the operations aborted by a fault.
+var ErrAborted = errors.New("operation aborted by fault injection")
+
+// Fault is a parsed fault.
+type Fault struct {
+	Operation  Operation
+	Target     string
+	Percentage float64
+	Delay      time.Duration
+	Error      string
+	Abort      bool
+}
+
+// Injector injects faults in the operations. The faults can be replaced with Update while they're injected.
+type Injector struct {
+	lock   sync.RWMutex
+	faults []*Fault
+	random func() float64
+}
+
+// NewInjector returns an injector of the faults of spec.
+func NewInjector(spec config.ChaosSpec) (*Injector, error) {
+	i := &Injector{random: rand.Float64}
+	if err := i.Update(spec); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+var defaultInjector = &Injector{random: rand.Float64}
+
+// Configure replaces the faults injected by Inject with the ones of spec.
+func Configure(spec config.ChaosSpec) error {
+	return defaultInjector.Update(spec)
+}
+
+// Inject injects the faults configured with Configure in an operation on target.
+func Inject(ctx context.Context, op Operation, target string) error {
+	return defaultInjector.Inject(ctx, op, target)
+}
+
+// Update replaces the injected faults with the ones of spec. The current faults are kept if spec is invalid.
+func (i *Injector) Update(spec config.ChaosSpec) error {
+	var faults []*Fault
+	if spec.Enabled {
+		for n, fs := range spec.Faults {
+			f, err := parseFault(fs)
+			if err != nil {
+				return errors.Wrapf(err, "invalid fault %d", n)
+			}
+			faults = append(faults, f)
+		}
+		log.Warnf("fault injection is enabled with %d faults", len(faults))
+	}
+
+	i.lock.Lock()
+	i.faults = faults
+	i.lock.Unlock()
+	return nil
+}
+
+// Inject injects the faults matching an operation on target, each in Percentage percent of the operations. The
+// faults are injected in order, so the delays of the injected faults add up until a fault fails the operation.
+// It returns the error of the fault, ErrAborted if the fault aborts the operation, or the error of ctx if it's done
+// while the operation is delayed.
+func (i *Injector) Inject(ctx context.Context, op Operation, target string) error {
+	i.lock.RLock()
+	faults := i.faults
+	i.lock.RUnlock()
+
+	for _, f := range faults {
+		if f.Operation != op || (f.Target != "" && f.Target != target) {
+			continue
+		}
+		if i.random()*100 >= f.Percentage {
+			continue
+		}
+
+		log.Debugf("injecting fault in %s operation on %s", op, target)
+		if f.Delay > 0 {
+			select {
+			case <-time.After(f.Delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if f.Abort {
+			return ErrAborted
+		}
+		if f.Error != "" {
+			return errors.New(f.Error)
+		}
+	}
+	return nil
+}
+
+func parseFault(spec config.FaultSpec) (*Fault, error) {
+	op := Operation(spec.Operation)
+	if op != Invocation && op != State && op != PubSub {
+		return nil, errors.Errorf("unknown operation %s", spec.Operation)
+	}
+	if spec.Percentage <= 0 || spec.Percentage > 100 {
+		return nil, errors.New("percentage must be greater than 0 and at most 100")
+	}
+
+	f := &Fault{
+		Operation:  op,
+		Target:     spec.Target,
+		Percentage: spec.Percentage,
+		Error:      spec.Error,
+		Abort:      spec.Abort,
+	}
+	if spec.Delay != "" {
+		delay, err := time.ParseDuration(spec.Delay)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid delay")
+		}
+		if delay <= 0 {
+			return nil, errors.New("delay must be greater than zero")
+		}
+		f.Delay = delay
+	}
+	if f.Delay == 0 && f.Error == "" && !f.Abort {
+		return nil, errors.New("one of delay, error and abort is required")
+	}
+	return f, nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func TestInject(t *testing.T) {
+	i, err := NewInjector(config.ChaosSpec{
+		Enabled: true,
+		Faults: []config.FaultSpec{
+			{Operation: "invocation", Target: "app1", Percentage: 50, Abort: true},
+			{Operation: "state", Percentage: 100, Error: "store is down"},
+			{Operation: "pubsub", Target: "pubsub1", Percentage: 100, Delay: "50ms"},
+		},
+	})
+	assert.NoError(t, err)
+	random := 0.2
+	i.random = func() float64 { return random }
+
+	assert.Equal(t, ErrAborted, i.Inject(context.Background(), Invocation, "app1"))
+	assert.NoError(t, i.Inject(context.Background(), Invocation, "app2"))
+	assert.EqualError(t, i.Inject(context.Background(), State, "statestore"), "store is down")
+
+	start := time.Now()
+	assert.NoError(t, i.Inject(context.Background(), PubSub, "pubsub1"))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	t.Run("operations outside the percentage", func(t *testing.T) {
+		random = 0.7
+		assert.NoError(t, i.Inject(context.Background(), Invocation, "app1"))
+	})
+
+	t.Run("delay interrupted by the context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.Equal(t, context.Canceled, i.Inject(ctx, PubSub, "pubsub1"))
+	})
+
+	t.Run("all the matching faults are injected", func(t *testing.T) {
+		i, err := NewInjector(config.ChaosSpec{
+			Enabled: true,
+			Faults: []config.FaultSpec{
+				{Operation: "state", Percentage: 10, Abort: true},
+				{Operation: "state", Target: "statestore", Percentage: 100, Delay: "20ms"},
+				{Operation: "state", Percentage: 100, Delay: "20ms"},
+				{Operation: "state", Percentage: 100, Error: "store is down"},
+			},
+		})
+		assert.NoError(t, err)
+		i.random = func() float64 { return 0.5 }
+
+		start := time.Now()
+		assert.EqualError(t, i.Inject(context.Background(), State, "statestore"), "store is down")
+		assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.NoError(t, i.Update(config.ChaosSpec{
+			Faults: []config.FaultSpec{{Operation: "state", Percentage: 100, Abort: true}},
+		}))
+		assert.NoError(t, i.Inject(context.Background(), State, "statestore"))
+	})
+
+	t.Run("invalid faults", func(t *testing.T) {
+		for _, f := range []config.FaultSpec{
+			{Operation: "actors", Percentage: 100, Abort: true},
+			{Operation: "state", Percentage: 0, Abort: true},
+			{Operation: "state", Percentage: 100},
+			{Operation: "state", Percentage: 100, Delay: "soon"},
+		} {
+			_, err := NewInjector(config.ChaosSpec{Enabled: true, Faults: []config.FaultSpec{f}})
+			assert.Error(t, err)
+		}
+	})
+}
@@ -0,0 +1,33 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bindings
+
+import (
+	"github.com/dapr/components-contrib/bindings"
+)
+
+type (
+	// BulkOutputBinding is implemented by output bindings which can send several requests to their backend in a
+	// single round trip, e.g. a multi-row insert.
+	BulkOutputBinding interface {
+		// BulkInvoke returns one result per request, in the order of the requests.
+		BulkInvoke(reqs []*bindings.InvokeRequest) ([]BulkInvokeResult, error)
+	}
+
+	// BulkInvokeResult is the result of one of the requests of a bulk invocation.
+	BulkInvokeResult struct {
+		Response *bindings.InvokeResponse
+		Error    error
+	}
+)
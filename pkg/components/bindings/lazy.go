@@ -0,0 +1,210 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bindings
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/dapr/components-contrib/bindings"
+)
+
+// LazyInitError is returned by the invocations of a lazily initialized output binding which fails to initialize.
+type LazyInitError struct {
+	Name string
+	Err  error
+}
+
+func (e *LazyInitError) Error() string {
+	return fmt.Sprintf("output binding %s failed to initialize: %s", e.Name, e.Err)
+}
+
+func (e *LazyInitError) Unwrap() error {
+	return e.Err
+}
+
+// LazyOutputBinding is an output binding initialized on its first invocation rather than when daprd starts, so that
+// the first invocation pays the cost of the initialization. Init only records the metadata of the binding. A failed
+// initialization is retried by the first invocation after its backoff, the invocations in between failing with its
+// error.
+type LazyOutputBinding struct {
+	name     string
+	binding  bindings.OutputBinding
+	metadata bindings.Metadata
+	onInit   func(err error)
+	backOff  backoff.BackOff
+	// initLock serializes the initialization attempts, so that lock only guards the state of the binding and
+	// isn't held while the binding initializes.
+	initLock    sync.Mutex
+	lock        sync.Mutex
+	initialized bool
+	initErr     error
+	retryAt     time.Time
+}
+
+// NewLazyOutputBinding returns an output binding initializing the given binding on its first invocation. onInit is
+// called with the result of each initialization attempt, and backOff gives the delays between the attempts.
+func NewLazyOutputBinding(name string, binding bindings.OutputBinding, backOff backoff.BackOff, onInit func(err error)) *LazyOutputBinding {
+	return &LazyOutputBinding{
+		name:    name,
+		binding: binding,
+		onInit:  onInit,
+		backOff: backOff,
+	}
+}
+
+// NewLazyInitBackOff returns the backoff between the initialization attempts of a lazily initialized output binding,
+// which grows up to a minute and never stops.
+func NewLazyInitBackOff() backoff.BackOff {
+	bo := backoff.NewExponentialBackOff()
+	bo.MaxInterval = time.Minute
+	bo.MaxElapsedTime = 0
+	return bo
+}
+
+// Init records the metadata the binding is initialized with on its first invocation.
+func (b *LazyOutputBinding) Init(metadata bindings.Metadata) error {
+	b.metadata = metadata
+	return nil
+}
+
+// Initialized returns true once the binding is initialized.
+func (b *LazyOutputBinding) Initialized() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.initialized
+}
+
+// InitResult returns whether the binding is initialized and, if it isn't, the error of its last initialization
+// attempt. Neither is set while the binding wasn't invoked yet.
+func (b *LazyOutputBinding) InitResult() (bool, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.initialized, b.initErr
+}
+
+// initState returns whether the initialization is done, and its error while it can't be retried yet.
+func (b *LazyOutputBinding) initState() (bool, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.initialized {
+		return true, nil
+	}
+	if b.initErr != nil && time.Now().Before(b.retryAt) {
+		return true, b.initErr
+	}
+	return false, nil
+}
+
+// Initialize initializes the binding if it isn't yet, unless its last initialization failed within its backoff.
+// It returns a *LazyInitError if the binding isn't initialized.
+func (b *LazyOutputBinding) Initialize() error {
+	if done, err := b.initState(); done {
+		return err
+	}
+
+	b.initLock.Lock()
+	defer b.initLock.Unlock()
+	// Another invocation may have initialized the binding while this one was waiting.
+	if done, err := b.initState(); done {
+		return err
+	}
+
+	err := b.binding.Init(b.metadata)
+
+	// The result is recorded before onInit is called, so InitResult agrees with the result onInit is called with.
+	b.lock.Lock()
+	if err != nil {
+		delay := b.backOff.NextBackOff()
+		if delay == backoff.Stop {
+			delay = 0
+		}
+		b.initErr = &LazyInitError{Name: b.name, Err: err}
+		b.retryAt = time.Now().Add(delay)
+	} else {
+		b.initialized = true
+		b.initErr = nil
+	}
+	initErr := b.initErr
+	b.lock.Unlock()
+
+	if b.onInit != nil {
+		b.onInit(err)
+	}
+	return initErr
+}
+
+// Operations returns the operations of the binding, which don't require it to be initialized.
+func (b *LazyOutputBinding) Operations() []bindings.OperationKind {
+	return b.binding.Operations()
+}
+
+// DescribeOperations describes the operations of the binding, which don't require it to be initialized.
+func (b *LazyOutputBinding) DescribeOperations() []OperationDescription {
+	return DescribeOperations(b.binding)
+}
+
+// Invoke initializes the binding if needed and invokes it.
+func (b *LazyOutputBinding) Invoke(req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	if err := b.Initialize(); err != nil {
+		return nil, err
+	}
+	return b.binding.Invoke(req)
+}
+
+// InvokeStream initializes the binding if needed and invokes it, streaming its response if it supports it.
+func (b *LazyOutputBinding) InvokeStream(req *bindings.InvokeRequest) (*StreamingInvokeResponse, error) {
+	if err := b.Initialize(); err != nil {
+		return nil, err
+	}
+	if streaming, ok := b.binding.(StreamingOutputBinding); ok {
+		return streaming.InvokeStream(req)
+	}
+	resp, err := b.binding.Invoke(req)
+	if err != nil {
+		return nil, err
+	}
+	return NewStreamingInvokeResponse(resp), nil
+}
+
+// BulkInvoke initializes the binding if needed and invokes it with the requests, at once if it supports it.
+func (b *LazyOutputBinding) BulkInvoke(reqs []*bindings.InvokeRequest) ([]BulkInvokeResult, error) {
+	if err := b.Initialize(); err != nil {
+		return nil, err
+	}
+	if bulk, ok := b.binding.(BulkOutputBinding); ok {
+		return bulk.BulkInvoke(reqs)
+	}
+	results := make([]BulkInvokeResult, len(reqs))
+	for i, req := range reqs {
+		resp, err := b.binding.Invoke(req)
+		results[i] = BulkInvokeResult{Response: resp, Error: err}
+	}
+	return results, nil
+}
+
+// Close closes the binding if it was initialized.
+func (b *LazyOutputBinding) Close() error {
+	if !b.Initialized() {
+		return nil
+	}
+	if closer, ok := b.binding.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
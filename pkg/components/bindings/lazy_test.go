@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bindings_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	b "github.com/dapr/components-contrib/bindings"
+
+	"github.com/dapr/dapr/pkg/components/bindings"
+)
+
+type mockLazyOutputBinding struct {
+	mockOperationsOutputBinding
+
+	initErrs []error
+	inits    int
+	metadata b.Metadata
+	closed   bool
+}
+
+func (m *mockLazyOutputBinding) Init(metadata b.Metadata) error {
+	m.inits++
+	m.metadata = metadata
+	if len(m.initErrs) > 0 {
+		err := m.initErrs[0]
+		m.initErrs = m.initErrs[1:]
+		return err
+	}
+	return nil
+}
+
+func (m *mockLazyOutputBinding) Invoke(req *b.InvokeRequest) (*b.InvokeResponse, error) {
+	return &b.InvokeResponse{Data: req.Data}, nil
+}
+
+func (m *mockLazyOutputBinding) Close() error {
+	m.closed = true
+	return nil
+}
+
+func TestLazyOutputBinding(t *testing.T) {
+	metadata := b.Metadata{Name: "lazy", Properties: map[string]string{"url": "http://localhost"}}
+
+	t.Run("binding is initialized on its first invocation", func(t *testing.T) {
+		mock := &mockLazyOutputBinding{}
+		var results []error
+		lazy := bindings.NewLazyOutputBinding("lazy", mock, &backoff.ZeroBackOff{}, func(err error) {
+			results = append(results, err)
+		})
+
+		require.NoError(t, lazy.Init(metadata))
+		assert.Equal(t, 0, mock.inits)
+		assert.False(t, lazy.Initialized())
+		assert.Equal(t, []b.OperationKind{b.CreateOperation, b.DeleteOperation}, lazy.Operations())
+
+		resp, err := lazy.Invoke(&b.InvokeRequest{Data: []byte("a"), Operation: b.CreateOperation})
+		require.NoError(t, err)
+		assert.Equal(t, []byte("a"), resp.Data)
+		assert.Equal(t, metadata, mock.metadata)
+		assert.True(t, lazy.Initialized())
+
+		_, err = lazy.Invoke(&b.InvokeRequest{Data: []byte("b"), Operation: b.CreateOperation})
+		require.NoError(t, err)
+		assert.Equal(t, 1, mock.inits)
+		assert.Equal(t, []error{nil}, results)
+	})
+
+	t.Run("failed initialization is retried by the next invocation", func(t *testing.T) {
+		initErr := errors.New("connection refused")
+		mock := &mockLazyOutputBinding{initErrs: []error{initErr}}
+		var results []error
+		lazy := bindings.NewLazyOutputBinding("lazy", mock, &backoff.ZeroBackOff{}, func(err error) {
+			results = append(results, err)
+		})
+		require.NoError(t, lazy.Init(metadata))
+
+		_, err := lazy.Invoke(&b.InvokeRequest{Operation: b.CreateOperation})
+		var lazyErr *bindings.LazyInitError
+		require.True(t, errors.As(err, &lazyErr))
+		assert.Equal(t, "lazy", lazyErr.Name)
+		assert.Same(t, initErr, lazyErr.Err)
+		assert.EqualError(t, err, "output binding lazy failed to initialize: connection refused")
+		assert.False(t, lazy.Initialized())
+
+		_, err = lazy.Invoke(&b.InvokeRequest{Operation: b.CreateOperation})
+		require.NoError(t, err)
+		assert.Equal(t, 2, mock.inits)
+		assert.Equal(t, []error{initErr, nil}, results)
+	})
+
+	t.Run("failed initialization is not retried within its backoff", func(t *testing.T) {
+		initErr := errors.New("connection refused")
+		mock := &mockLazyOutputBinding{initErrs: []error{initErr}}
+		lazy := bindings.NewLazyOutputBinding("lazy", mock, backoff.NewConstantBackOff(time.Hour), nil)
+		require.NoError(t, lazy.Init(metadata))
+
+		_, err := lazy.Invoke(&b.InvokeRequest{Operation: b.CreateOperation})
+		require.Error(t, err)
+		_, err = lazy.Invoke(&b.InvokeRequest{Operation: b.CreateOperation})
+		var lazyErr *bindings.LazyInitError
+		require.True(t, errors.As(err, &lazyErr))
+		assert.Same(t, initErr, lazyErr.Err)
+		assert.Equal(t, 1, mock.inits)
+		assert.False(t, lazy.Initialized())
+	})
+
+	t.Run("bulk and streaming invocations initialize the binding", func(t *testing.T) {
+		mock := &mockLazyOutputBinding{}
+		lazy := bindings.NewLazyOutputBinding("lazy", mock, &backoff.ZeroBackOff{}, nil)
+		require.NoError(t, lazy.Init(metadata))
+
+		results, err := lazy.BulkInvoke([]*b.InvokeRequest{{Data: []byte("a")}, {Data: []byte("b")}})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, []byte("b"), results[1].Response.Data)
+
+		resp, err := lazy.InvokeStream(&b.InvokeRequest{Data: []byte("c")})
+		require.NoError(t, err)
+		assert.Equal(t, 1, resp.Size)
+		assert.Equal(t, 1, mock.inits)
+	})
+
+	t.Run("binding is closed only if initialized", func(t *testing.T) {
+		mock := &mockLazyOutputBinding{}
+		lazy := bindings.NewLazyOutputBinding("lazy", mock, &backoff.ZeroBackOff{}, nil)
+		require.NoError(t, lazy.Init(metadata))
+
+		require.NoError(t, lazy.Close())
+		assert.False(t, mock.closed)
+
+		_, err := lazy.Invoke(&b.InvokeRequest{Operation: b.CreateOperation})
+		require.NoError(t, err)
+		require.NoError(t, lazy.Close())
+		assert.True(t, mock.closed)
+	})
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localstorage
+
+import (
+	"os"
+	"sync"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/pkg/errors"
+
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/components-contrib/bindings/localstorage"
+	"github.com/dapr/kit/logger"
+
+	bindings_loader "github.com/dapr/dapr/pkg/components/bindings"
+)
+
+// Metadata keys matching the ones of the components-contrib binding.
+const (
+	rootPathKey = "rootPath"
+	fileNameKey = "fileName"
+)
+
+// bulkConcurrency is the maximum number of files a bulk invocation handles at once.
+const bulkConcurrency = 8
+
+// LocalStorage is the localstorage output binding of components-contrib, streaming the files it gets instead of
+// reading them in memory.
+type LocalStorage struct {
+	*localstorage.LocalStorage
+
+	rootPath string
+}
+
+// NewLocalStorage creates a streaming localstorage output binding.
+func NewLocalStorage(logger logger.Logger) bindings.OutputBinding {
+	return &LocalStorage{
+		LocalStorage: localstorage.NewLocalStorage(logger),
+	}
+}
+
+// Init initializes the components-contrib binding, which creates the root path.
+func (ls *LocalStorage) Init(metadata bindings.Metadata) error {
+	if err := ls.LocalStorage.Init(metadata); err != nil {
+		return err
+	}
+	ls.rootPath = metadata.Properties[rootPathKey]
+	return nil
+}
+
+// InvokeStream streams the file of a get operation. The other operations are invoked on the components-contrib
+// binding.
+func (ls *LocalStorage) InvokeStream(req *bindings.InvokeRequest) (*bindings_loader.StreamingInvokeResponse, error) {
+	if req.Operation != bindings.GetOperation {
+		resp, err := ls.Invoke(req)
+		if err != nil {
+			return nil, err
+		}
+		return bindings_loader.NewStreamingInvokeResponse(resp), nil
+	}
+
+	path, err := securejoin.SecureJoin(ls.rootPath, req.Metadata[fileNameKey])
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if fi.IsDir() {
+		f.Close()
+		return nil, errors.Errorf("unable to get %s as it is a directory", req.Metadata[fileNameKey])
+	}
+
+	return &bindings_loader.StreamingInvokeResponse{
+		Data: f,
+		Size: int(fi.Size()),
+	}, nil
+}
+
+// BulkInvoke handles the requests concurrently, writing several files at once.
+func (ls *LocalStorage) BulkInvoke(reqs []*bindings.InvokeRequest) ([]bindings_loader.BulkInvokeResult, error) {
+	results := make([]bindings_loader.BulkInvokeResult, len(reqs))
+	sem := make(chan struct{}, bulkConcurrency)
+	var wg sync.WaitGroup
+	for i, req := range reqs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, req *bindings.InvokeRequest) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			resp, err := ls.Invoke(req)
+			results[i] = bindings_loader.BulkInvokeResult{Response: resp, Error: err}
+		}(i, req)
+	}
+	wg.Wait()
+	return results, nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localstorage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/kit/logger"
+
+	bindings_loader "github.com/dapr/dapr/pkg/components/bindings"
+)
+
+func TestInvokeStream(t *testing.T) {
+	binding := NewLocalStorage(logger.NewLogger("test"))
+	err := binding.Init(bindings.Metadata{Properties: map[string]string{rootPathKey: t.TempDir()}})
+	require.NoError(t, err)
+	streaming, ok := binding.(bindings_loader.StreamingOutputBinding)
+	require.True(t, ok)
+
+	_, err = binding.Invoke(&bindings.InvokeRequest{
+		Operation: bindings.CreateOperation,
+		Data:      []byte("hello world"),
+		Metadata:  map[string]string{fileNameKey: "dir/file.txt"},
+	})
+	require.NoError(t, err)
+
+	t.Run("get streams the file", func(t *testing.T) {
+		resp, err := streaming.InvokeStream(&bindings.InvokeRequest{
+			Operation: bindings.GetOperation,
+			Metadata:  map[string]string{fileNameKey: "dir/file.txt"},
+		})
+		require.NoError(t, err)
+		defer resp.Data.Close()
+
+		b, err := ioutil.ReadAll(resp.Data)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello world", string(b))
+		assert.Equal(t, len("hello world"), resp.Size)
+	})
+
+	t.Run("file names are resolved within the root path", func(t *testing.T) {
+		resp, err := streaming.InvokeStream(&bindings.InvokeRequest{
+			Operation: bindings.GetOperation,
+			Metadata:  map[string]string{fileNameKey: "../../dir/file.txt"},
+		})
+		require.NoError(t, err)
+		defer resp.Data.Close()
+
+		b, err := ioutil.ReadAll(resp.Data)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello world", string(b))
+	})
+
+	t.Run("get of a directory", func(t *testing.T) {
+		_, err := streaming.InvokeStream(&bindings.InvokeRequest{
+			Operation: bindings.GetOperation,
+			Metadata:  map[string]string{fileNameKey: "dir"},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("other operations are not streamed", func(t *testing.T) {
+		resp, err := streaming.InvokeStream(&bindings.InvokeRequest{
+			Operation: bindings.ListOperation,
+			Metadata:  map[string]string{fileNameKey: "dir"},
+		})
+		require.NoError(t, err)
+		b, err := ioutil.ReadAll(resp.Data)
+		assert.NoError(t, err)
+		assert.Contains(t, string(b), "file.txt")
+	})
+}
+
+func TestBulkInvoke(t *testing.T) {
+	binding := NewLocalStorage(logger.NewLogger("test"))
+	err := binding.Init(bindings.Metadata{Properties: map[string]string{rootPathKey: t.TempDir()}})
+	require.NoError(t, err)
+	bulk, ok := binding.(bindings_loader.BulkOutputBinding)
+	require.True(t, ok)
+
+	reqs := make([]*bindings.InvokeRequest, 20)
+	for i := range reqs {
+		reqs[i] = &bindings.InvokeRequest{
+			Operation: bindings.CreateOperation,
+			Data:      []byte(fmt.Sprintf("file %d", i)),
+			Metadata:  map[string]string{fileNameKey: fmt.Sprintf("file%d.txt", i)},
+		}
+	}
+	reqs[3].Operation = "unknown"
+
+	results, err := bulk.BulkInvoke(reqs)
+	require.NoError(t, err)
+	require.Len(t, results, len(reqs))
+	for i, r := range results {
+		if i == 3 {
+			assert.Error(t, r.Error)
+			continue
+		}
+		require.NoError(t, r.Error)
+
+		resp, err := binding.Invoke(&bindings.InvokeRequest{
+			Operation: bindings.GetOperation,
+			Metadata:  map[string]string{fileNameKey: fmt.Sprintf("file%d.txt", i)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("file %d", i), string(resp.Data))
+	}
+}
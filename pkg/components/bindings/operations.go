@@ -0,0 +1,49 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bindings
+
+import (
+	"github.com/dapr/components-contrib/bindings"
+)
+
+type (
+	// OperationsDescriber is implemented by output bindings which describe the operations they support.
+	OperationsDescriber interface {
+		DescribeOperations() []OperationDescription
+	}
+
+	// OperationDescription describes an operation supported by an output binding.
+	OperationDescription struct {
+		Name string `json:"name"`
+		// Metadata lists the request metadata keys the operation expects.
+		Metadata []string `json:"metadata,omitempty"`
+		// Schema is a hint of the format of the request data, e.g. a JSON schema.
+		Schema string `json:"schema,omitempty"`
+	}
+)
+
+// DescribeOperations returns the operations supported by the output binding.
+// Only the names of the operations are returned for bindings which don't describe their operations.
+func DescribeOperations(binding bindings.OutputBinding) []OperationDescription {
+	if describer, ok := binding.(OperationsDescriber); ok {
+		return describer.DescribeOperations()
+	}
+
+	ops := binding.Operations()
+	descriptions := make([]OperationDescription, 0, len(ops))
+	for _, o := range ops {
+		descriptions = append(descriptions, OperationDescription{Name: string(o)})
+	}
+	return descriptions
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bindings_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	b "github.com/dapr/components-contrib/bindings"
+
+	"github.com/dapr/dapr/pkg/components/bindings"
+)
+
+type mockDescribedOutputBinding struct {
+	mockOutputBinding
+}
+
+func (m *mockDescribedOutputBinding) DescribeOperations() []bindings.OperationDescription {
+	return []bindings.OperationDescription{
+		{Name: "get", Metadata: []string{"key"}},
+	}
+}
+
+func TestDescribeOperations(t *testing.T) {
+	t.Run("binding describing its operations", func(t *testing.T) {
+		ops := bindings.DescribeOperations(&mockDescribedOutputBinding{})
+		assert.Equal(t, []bindings.OperationDescription{{Name: "get", Metadata: []string{"key"}}}, ops)
+	})
+
+	t.Run("binding not describing its operations", func(t *testing.T) {
+		ops := bindings.DescribeOperations(&mockOperationsOutputBinding{})
+		assert.Equal(t, []bindings.OperationDescription{{Name: "create"}, {Name: "delete"}}, ops)
+	})
+}
+
+type mockOperationsOutputBinding struct {
+	mockOutputBinding
+}
+
+func (m *mockOperationsOutputBinding) Operations() []b.OperationKind {
+	return []b.OperationKind{b.CreateOperation, b.DeleteOperation}
+}
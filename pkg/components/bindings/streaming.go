@@ -0,0 +1,51 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bindings
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+
+	"github.com/dapr/components-contrib/bindings"
+)
+
+type (
+	// StreamingOutputBinding is implemented by output bindings which can return large responses,
+	// e.g. blob downloads, without reading them in memory first.
+	StreamingOutputBinding interface {
+		InvokeStream(req *bindings.InvokeRequest) (*StreamingInvokeResponse, error)
+	}
+
+	// StreamingInvokeResponse is the response of an output binding invocation with a streamed body.
+	// The caller is responsible for closing Data.
+	StreamingInvokeResponse struct {
+		Data     io.ReadCloser
+		Metadata map[string]string
+		// Size is the length of Data in bytes, or -1 if unknown.
+		Size int
+	}
+)
+
+// NewStreamingInvokeResponse wraps a buffered output binding response into a StreamingInvokeResponse.
+func NewStreamingInvokeResponse(resp *bindings.InvokeResponse) *StreamingInvokeResponse {
+	if resp == nil {
+		return nil
+	}
+	return &StreamingInvokeResponse{
+		Data:     ioutil.NopCloser(bytes.NewReader(resp.Data)),
+		Metadata: resp.Metadata,
+		Size:     len(resp.Data),
+	}
+}
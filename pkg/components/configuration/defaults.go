@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configuration
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/dapr/components-contrib/configuration"
+	"github.com/dapr/kit/logger"
+)
+
+var log = logger.NewLogger("dapr.runtime.configuration")
+
+// initRetryInterval is the minimum time between the attempts to initialize an unreachable store.
+const initRetryInterval = 10 * time.Second
+
+// DefaultingStore overlays the items of a configuration store on local defaults.
+// The defaults are served for the keys missing from the store, and for all the keys while the store is unreachable,
+// including when it can't be initialized at startup.
+type DefaultingStore struct {
+	name     string
+	store    configuration.Store
+	defaults map[string]string
+
+	lock        sync.Mutex
+	metadata    configuration.Metadata
+	initialized bool
+	lastInit    time.Time
+	now         func() time.Time
+}
+
+// NewDefaultingStore returns a store overlaying store on defaults.
+func NewDefaultingStore(name string, store configuration.Store, defaults map[string]string) *DefaultingStore {
+	return &DefaultingStore{
+		name:     name,
+		store:    store,
+		defaults: defaults,
+		now:      time.Now,
+	}
+}
+
+// LoadDefaults reads the default configuration items at path. The path is either a YAML or JSON file
+// mapping keys to values, or a directory with a file per key, such as a mounted ConfigMap.
+func LoadDefaults(path string) (map[string]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := map[string]string{}
+	if !info.IsDir() {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err = yaml.Unmarshal(b, &defaults); err != nil {
+			return nil, errors.Wrapf(err, "error parsing configuration defaults %s", path)
+		}
+		return defaults, nil
+	}
+
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		// Mounted ConfigMaps contain hidden entries pointing to the data of their current version.
+		if strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		file := filepath.Join(path, f.Name())
+		if info, err = os.Stat(file); err != nil || info.IsDir() {
+			continue
+		}
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		defaults[f.Name()] = string(b)
+	}
+	return defaults, nil
+}
+
+// Init initializes the store. Failures are logged and the initialization is retried on later requests,
+// the defaults being served meanwhile.
+func (d *DefaultingStore) Init(metadata configuration.Metadata) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.metadata = metadata
+	d.tryInit()
+	return nil
+}
+
+// tryInit must be called with the lock held.
+func (d *DefaultingStore) tryInit() bool {
+	if d.initialized {
+		return true
+	}
+	if !d.lastInit.IsZero() && d.now().Sub(d.lastInit) < initRetryInterval {
+		return false
+	}
+
+	d.lastInit = d.now()
+	if err := d.store.Init(d.metadata); err != nil {
+		log.Warnf("configuration store %s is unreachable, serving its defaults: %s", d.name, err)
+		return false
+	}
+	d.initialized = true
+	return true
+}
+
+func (d *DefaultingStore) ready() bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.tryInit()
+}
+
+// Get returns the items of the store, completed with the defaults of the missing keys.
+func (d *DefaultingStore) Get(ctx context.Context, req *configuration.GetRequest) (*configuration.GetResponse, error) {
+	resp := &configuration.GetResponse{}
+	if d.ready() {
+		storeResp, err := d.store.Get(ctx, req)
+		if err != nil {
+			log.Warnf("failed to get configuration from store %s, serving its defaults: %s", d.name, err)
+		} else if storeResp != nil {
+			resp.Items = storeResp.Items
+		}
+	}
+
+	found := make(map[string]bool, len(resp.Items))
+	for _, item := range resp.Items {
+		found[item.Key] = true
+	}
+
+	keys := req.Keys
+	if len(keys) == 0 {
+		for k := range d.defaults {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+	}
+	for _, k := range keys {
+		if v, ok := d.defaults[k]; ok && !found[k] {
+			resp.Items = append(resp.Items, &configuration.Item{Key: k, Value: v})
+		}
+	}
+	return resp, nil
+}
+
+// Subscribe subscribes to the updates of the store, which can't be done while the store is unreachable.
+func (d *DefaultingStore) Subscribe(ctx context.Context, req *configuration.SubscribeRequest, handler configuration.UpdateHandler) error {
+	if !d.ready() {
+		return errors.Errorf("configuration store %s is unreachable", d.name)
+	}
+	return d.store.Subscribe(ctx, req, handler)
+}
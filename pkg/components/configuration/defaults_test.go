@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configuration
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/components-contrib/configuration"
+)
+
+type unreachableStore struct {
+	mockStore
+	reachable bool
+	inits     int
+}
+
+func (u *unreachableStore) Init(metadata configuration.Metadata) error {
+	u.inits++
+	if !u.reachable {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (u *unreachableStore) Subscribe(ctx context.Context, req *configuration.SubscribeRequest, handler configuration.UpdateHandler) error {
+	return nil
+}
+
+func TestLoadDefaults(t *testing.T) {
+	dir, err := ioutil.TempDir("", "defaults")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	t.Run("file", func(t *testing.T) {
+		file := filepath.Join(dir, "defaults.yaml")
+		assert.NoError(t, ioutil.WriteFile(file, []byte("key1: val1\nkey2: val2\n"), 0o600))
+		defaults, err := LoadDefaults(file)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"key1": "val1", "key2": "val2"}, defaults)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		file := filepath.Join(dir, "invalid.yaml")
+		assert.NoError(t, ioutil.WriteFile(file, []byte("- val1"), 0o600))
+		_, err := LoadDefaults(file)
+		assert.Error(t, err)
+	})
+
+	t.Run("configmap directory", func(t *testing.T) {
+		configMap := filepath.Join(dir, "configmap")
+		assert.NoError(t, os.MkdirAll(filepath.Join(configMap, "..data"), 0o700))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(configMap, "..data", "key1"), []byte("val1"), 0o600))
+		assert.NoError(t, os.Symlink(filepath.Join("..data", "key1"), filepath.Join(configMap, "key1")))
+		defaults, err := LoadDefaults(configMap)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"key1": "val1"}, defaults)
+	})
+
+	t.Run("missing path", func(t *testing.T) {
+		_, err := LoadDefaults(filepath.Join(dir, "missing"))
+		assert.Error(t, err)
+	})
+}
+
+func TestDefaultingStore(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	inner := &unreachableStore{
+		mockStore: mockStore{items: []*configuration.Item{{Key: "key1", Value: "remote1", Version: "1"}}},
+	}
+	store := NewDefaultingStore("store1", inner, map[string]string{"key1": "default1", "key2": "default2"})
+	store.now = func() time.Time { return now }
+
+	get := func(keys ...string) map[string]string {
+		resp, err := store.Get(context.Background(), &configuration.GetRequest{Keys: keys})
+		assert.NoError(t, err)
+		values := map[string]string{}
+		for _, item := range resp.Items {
+			values[item.Key] = item.Value
+		}
+		return values
+	}
+
+	t.Run("unreachable store serves the defaults", func(t *testing.T) {
+		assert.NoError(t, store.Init(configuration.Metadata{}))
+		assert.Equal(t, map[string]string{"key1": "default1", "key2": "default2"}, get())
+		assert.Error(t, store.Subscribe(context.Background(), &configuration.SubscribeRequest{}, nil))
+		assert.Equal(t, 1, inner.inits)
+	})
+
+	t.Run("initialization is retried after an interval", func(t *testing.T) {
+		inner.reachable = true
+		get()
+		assert.Equal(t, 1, inner.inits)
+
+		now = now.Add(initRetryInterval)
+		assert.Equal(t, map[string]string{"key1": "remote1", "key2": "default2"}, get())
+		assert.Equal(t, 2, inner.inits)
+		assert.NoError(t, store.Subscribe(context.Background(), &configuration.SubscribeRequest{}, nil))
+	})
+
+	t.Run("only the defaults of the requested keys are served", func(t *testing.T) {
+		assert.Equal(t, map[string]string{"key2": "default2"}, get("key2"))
+		assert.Equal(t, map[string]string{}, get("key3"))
+	})
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configuration
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dapr/components-contrib/configuration"
+)
+
+// ReplayingStore is implemented by configuration stores which keep the history of their items,
+// so that every change missed by a subscriber can be replayed rather than only the latest values.
+type ReplayingStore interface {
+	// ChangesSince returns the changes of the items made after versions, oldest first.
+	// Empty keys means all the items.
+	ChangesSince(ctx context.Context, keys []string, versions map[string]string, metadata map[string]string) ([]*configuration.Item, error)
+}
+
+// MatchesKey returns true if key is one of keys or starts with one of prefixes.
+// Empty keys and prefixes match all the keys.
+func MatchesKey(key string, keys, prefixes []string) bool {
+	if len(keys) == 0 && len(prefixes) == 0 {
+		return true
+	}
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Replay returns the items of keys and prefixes changed since versions, the last versions seen by a subscriber.
+// Stores which implement ReplayingStore replay every change; others return the current items whose versions differ.
+func Replay(ctx context.Context, store configuration.Store, keys, prefixes []string, versions map[string]string, metadata map[string]string) ([]*configuration.Item, error) {
+	// Prefixes can't be passed to the stores, so all the items are fetched and filtered.
+	fetchKeys := keys
+	if len(prefixes) > 0 {
+		fetchKeys = nil
+	}
+
+	var items []*configuration.Item
+	if replayer, ok := store.(ReplayingStore); ok {
+		changes, err := replayer.ChangesSince(ctx, fetchKeys, versions, metadata)
+		if err != nil {
+			return nil, err
+		}
+		items = changes
+	} else {
+		resp, err := store.Get(ctx, &configuration.GetRequest{Keys: fetchKeys, Metadata: metadata})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range resp.Items {
+			if version, ok := versions[item.Key]; ok && version == item.Version {
+				continue
+			}
+			items = append(items, item)
+		}
+	}
+
+	return FilterItems(items, keys, prefixes), nil
+}
+
+// FilterItems returns the items matching keys and prefixes.
+func FilterItems(items []*configuration.Item, keys, prefixes []string) []*configuration.Item {
+	filtered := make([]*configuration.Item, 0, len(items))
+	for _, item := range items {
+		if MatchesKey(item.Key, keys, prefixes) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
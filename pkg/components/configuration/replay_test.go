@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configuration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/components-contrib/configuration"
+)
+
+type mockStore struct {
+	configuration.Store
+	items []*configuration.Item
+}
+
+func (m *mockStore) Get(ctx context.Context, req *configuration.GetRequest) (*configuration.GetResponse, error) {
+	return &configuration.GetResponse{Items: FilterItems(m.items, req.Keys, nil)}, nil
+}
+
+type mockReplayingStore struct {
+	mockStore
+	history []*configuration.Item
+}
+
+func (m *mockReplayingStore) ChangesSince(ctx context.Context, keys []string, versions map[string]string, metadata map[string]string) ([]*configuration.Item, error) {
+	return FilterItems(m.history, keys, nil), nil
+}
+
+func TestMatchesKey(t *testing.T) {
+	assert.True(t, MatchesKey("key1", nil, nil))
+	assert.True(t, MatchesKey("key1", []string{"key1"}, nil))
+	assert.False(t, MatchesKey("key10", []string{"key1"}, nil))
+	assert.True(t, MatchesKey("app.key1", []string{"key1"}, []string{"app."}))
+	assert.False(t, MatchesKey("other.key1", []string{"key1"}, []string{"app."}))
+}
+
+func TestReplay(t *testing.T) {
+	items := []*configuration.Item{
+		{Key: "app.key1", Value: "val1", Version: "2"},
+		{Key: "app.key2", Value: "val2", Version: "1"},
+		{Key: "other.key3", Value: "val3", Version: "1"},
+	}
+
+	t.Run("stores which can't replay return the changed items", func(t *testing.T) {
+		store := &mockStore{items: items}
+		replayed, err := Replay(context.Background(), store, nil, []string{"app."}, map[string]string{"app.key1": "1", "app.key2": "1"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []*configuration.Item{items[0]}, replayed)
+	})
+
+	t.Run("items unknown to the subscriber are returned", func(t *testing.T) {
+		store := &mockStore{items: items}
+		replayed, err := Replay(context.Background(), store, []string{"other.key3"}, nil, map[string]string{"app.key1": "2"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []*configuration.Item{items[2]}, replayed)
+	})
+
+	t.Run("stores which can replay return every change", func(t *testing.T) {
+		store := &mockReplayingStore{
+			mockStore: mockStore{items: items},
+			history: []*configuration.Item{
+				{Key: "app.key1", Value: "val0", Version: "1"},
+				{Key: "other.key3", Value: "val3", Version: "1"},
+				{Key: "app.key1", Value: "val1", Version: "2"},
+			},
+		}
+		replayed, err := Replay(context.Background(), store, nil, []string{"app."}, map[string]string{"app.key1": "0"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []*configuration.Item{store.history[0], store.history[2]}, replayed)
+	})
+}
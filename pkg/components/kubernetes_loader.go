@@ -36,24 +36,28 @@ const (
 
 // KubernetesComponents loads components in a kubernetes environment.
 type KubernetesComponents struct {
-	config    config.KubernetesConfig
-	client    operatorv1pb.OperatorClient
-	namespace string
+	config             config.KubernetesConfig
+	client             operatorv1pb.OperatorClient
+	namespace          string
+	defaultSecretStore string
 }
 
-// NewKubernetesComponents returns a new kubernetes loader.
-func NewKubernetesComponents(configuration config.KubernetesConfig, namespace string, operatorClient operatorv1pb.OperatorClient) *KubernetesComponents {
+// NewKubernetesComponents returns a new kubernetes loader. The secret references of the components without a secret
+// store are resolved with the given default secret store.
+func NewKubernetesComponents(configuration config.KubernetesConfig, namespace, defaultSecretStore string, operatorClient operatorv1pb.OperatorClient) *KubernetesComponents {
 	return &KubernetesComponents{
-		config:    configuration,
-		client:    operatorClient,
-		namespace: namespace,
+		config:             configuration,
+		client:             operatorClient,
+		namespace:          namespace,
+		defaultSecretStore: defaultSecretStore,
 	}
 }
 
 // LoadComponents returns components from a given control plane address.
 func (k *KubernetesComponents) LoadComponents() ([]components_v1alpha1.Component, error) {
 	resp, err := k.client.ListComponents(context.Background(), &operatorv1pb.ListComponentsRequest{
-		Namespace: k.namespace,
+		Namespace:          k.namespace,
+		DefaultSecretStore: k.defaultSecretStore,
 	}, grpc_retry.WithMax(operatorMaxRetries), grpc_retry.WithPerRetryTimeout(operatorCallTimeout))
 	if err != nil {
 		return nil, err
@@ -0,0 +1,269 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consulhealth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	consul "github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	nr "github.com/dapr/components-contrib/nameresolution"
+	nr_consul "github.com/dapr/components-contrib/nameresolution/consul"
+	"github.com/dapr/kit/config"
+	"github.com/dapr/kit/logger"
+
+	nr_loader "github.com/dapr/dapr/pkg/components/nameresolution"
+)
+
+// defaultDaprPortMetaKey matches the meta key used by the components-contrib consul resolver on registration.
+const defaultDaprPortMetaKey = "DAPR_PORT"
+
+// Meta keys reporting the locality of an instance, matching the environment variables set on the sidecar and the
+// properties passed to the resolver.
+const (
+	nodeMetaKey = "DAPR_NODE_NAME"
+	zoneMetaKey = "DAPR_ZONE"
+)
+
+type healthClient interface {
+	Service(service, tag string, passingOnly bool, q *consul.QueryOptions) ([]*consul.ServiceEntry, *consul.QueryMeta, error)
+}
+
+type clientConfig struct {
+	Address    string
+	Scheme     string
+	Datacenter string
+	Token      string
+}
+
+type queryOptions struct {
+	Datacenter string
+	UseCache   bool
+	Token      string
+}
+
+type resolverConfig struct {
+	Client          *clientConfig
+	QueryOptions    *queryOptions
+	Tag             string
+	DaprPortMetaKey string
+}
+
+// resolver is a consul resolver that reports the health status and weight of every instance
+// of an app. Registration and single address resolution are delegated to the components-contrib resolver.
+type resolver struct {
+	nr.Resolver
+
+	logger       logger.Logger
+	health       healthClient
+	tag          string
+	queryOptions *consul.QueryOptions
+	daprPortKey  string
+}
+
+// NewResolver creates a health-aware Consul name resolver.
+func NewResolver(logger logger.Logger) nr.Resolver {
+	return &resolver{
+		Resolver: nr_consul.NewResolver(logger),
+		logger:   logger,
+	}
+}
+
+// Init registers the app with Consul and creates the health client used to resolve endpoints.
+// The node and zone of this instance are added to the meta of its registration.
+func (r *resolver) Init(metadata nr.Metadata) error {
+	configuration, err := withLocalityMeta(metadata.Configuration, metadata.Properties[nodeMetaKey], metadata.Properties[zoneMetaKey])
+	if err != nil {
+		return err
+	}
+	metadata.Configuration = configuration
+	if err = r.Resolver.Init(metadata); err != nil {
+		return err
+	}
+
+	cfg, err := parseConfig(metadata.Configuration)
+	if err != nil {
+		return err
+	}
+
+	clientCfg := consul.DefaultConfig()
+	if cfg.Client != nil {
+		if cfg.Client.Address != "" {
+			clientCfg.Address = cfg.Client.Address
+		}
+		if cfg.Client.Scheme != "" {
+			clientCfg.Scheme = cfg.Client.Scheme
+		}
+		clientCfg.Datacenter = cfg.Client.Datacenter
+		clientCfg.Token = cfg.Client.Token
+	}
+	client, err := consul.NewClient(clientCfg)
+	if err != nil {
+		return fmt.Errorf("failed to init consul client: %w", err)
+	}
+
+	r.health = client.Health()
+	r.applyConfig(cfg)
+	return nil
+}
+
+func (r *resolver) applyConfig(cfg resolverConfig) {
+	r.tag = cfg.Tag
+	r.daprPortKey = cfg.DaprPortMetaKey
+	if r.daprPortKey == "" {
+		r.daprPortKey = defaultDaprPortMetaKey
+	}
+	if cfg.QueryOptions != nil {
+		r.queryOptions = &consul.QueryOptions{
+			Datacenter: cfg.QueryOptions.Datacenter,
+			UseCache:   cfg.QueryOptions.UseCache,
+			Token:      cfg.QueryOptions.Token,
+		}
+	}
+}
+
+// ResolveEndpoints returns every registered instance of an app along with its aggregated health status.
+// Instances in warning state are considered healthy and use their warning weight.
+// The locality of an instance is read from the DAPR_NODE_NAME and DAPR_ZONE meta of its registration.
+func (r *resolver) ResolveEndpoints(req nr.ResolveRequest) ([]nr_loader.Endpoint, error) {
+	entries, _, err := r.health.Service(req.ID, r.tag, false, r.queryOptions)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query consul services")
+	}
+
+	endpoints := make([]nr_loader.Endpoint, 0, len(entries))
+	for _, entry := range entries {
+		address, ok := r.entryAddress(entry)
+		if !ok {
+			r.logger.Debugf("skipping consul service %s without address or %s meta", entry.Service.ID, r.daprPortKey)
+			continue
+		}
+
+		endpoint := nr_loader.Endpoint{
+			Address: address,
+			Weight:  nr_loader.DefaultEndpointWeight,
+			Node:    entry.Service.Meta[nodeMetaKey],
+			Zone:    entry.Service.Meta[zoneMetaKey],
+		}
+		switch entry.Checks.AggregatedStatus() {
+		case consul.HealthPassing:
+			endpoint.Healthy = true
+			if entry.Service.Weights.Passing > 0 {
+				endpoint.Weight = entry.Service.Weights.Passing
+			}
+		case consul.HealthWarning:
+			endpoint.Healthy = true
+			if entry.Service.Weights.Warning > 0 {
+				endpoint.Weight = entry.Service.Weights.Warning
+			}
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	if len(endpoints) == 0 {
+		return nil, errors.Errorf("no services found with AppID:%s", req.ID)
+	}
+	return endpoints, nil
+}
+
+func (r *resolver) entryAddress(entry *consul.ServiceEntry) (string, bool) {
+	if entry.Service == nil {
+		return "", false
+	}
+	port, ok := entry.Service.Meta[r.daprPortKey]
+	if !ok {
+		return "", false
+	}
+	host := entry.Service.Address
+	if host == "" && entry.Node != nil {
+		host = entry.Node.Address
+	}
+	if host == "" {
+		return "", false
+	}
+	if _, err := strconv.Atoi(port); err != nil {
+		return "", false
+	}
+	return net.JoinHostPort(host, port), true
+}
+
+// withLocalityMeta returns the configuration with the node and zone of this instance added to the meta of its
+// registration. The meta set in the configuration takes precedence.
+func withLocalityMeta(rawConfig interface{}, node, zone string) (interface{}, error) {
+	if node == "" && zone == "" {
+		return rawConfig, nil
+	}
+
+	cfg := map[string]interface{}{}
+	if rawConfig != nil {
+		normalized, err := config.Normalize(rawConfig)
+		if err != nil {
+			return nil, err
+		}
+		// Normalize copies the maps of the configuration.
+		m, ok := normalized.(map[string]interface{})
+		if !ok {
+			// Invalid configurations are reported by the components-contrib resolver.
+			return rawConfig, nil
+		}
+		cfg = m
+	}
+
+	metaKey := "meta"
+	meta := map[string]interface{}{}
+	for k, v := range cfg {
+		if !strings.EqualFold(k, metaKey) {
+			continue
+		}
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return rawConfig, nil
+		}
+		metaKey, meta = k, m
+	}
+	if _, ok := meta[nodeMetaKey]; !ok && node != "" {
+		meta[nodeMetaKey] = node
+	}
+	if _, ok := meta[zoneMetaKey]; !ok && zone != "" {
+		meta[zoneMetaKey] = zone
+	}
+	cfg[metaKey] = meta
+	return cfg, nil
+}
+
+func parseConfig(rawConfig interface{}) (resolverConfig, error) {
+	cfg := resolverConfig{}
+	if rawConfig == nil {
+		return cfg, nil
+	}
+
+	normalized, err := config.Normalize(rawConfig)
+	if err != nil {
+		return cfg, err
+	}
+	b, err := json.Marshal(normalized)
+	if err != nil {
+		return cfg, fmt.Errorf("error serializing consul configuration: %w", err)
+	}
+	// Unknown fields are validated by the components-contrib resolver.
+	if err = json.Unmarshal(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("error deserializing consul configuration: %w", err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consulhealth
+
+import (
+	"errors"
+	"testing"
+
+	consul "github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+
+	nr "github.com/dapr/components-contrib/nameresolution"
+	"github.com/dapr/kit/logger"
+)
+
+type mockHealth struct {
+	entries []*consul.ServiceEntry
+	err     error
+}
+
+func (m *mockHealth) Service(service, tag string, passingOnly bool, q *consul.QueryOptions) ([]*consul.ServiceEntry, *consul.QueryMeta, error) {
+	return m.entries, nil, m.err
+}
+
+func serviceEntry(address, port, status string, passingWeight int) *consul.ServiceEntry {
+	return &consul.ServiceEntry{
+		Node: &consul.Node{Address: "node"},
+		Service: &consul.AgentService{
+			ID:      address,
+			Address: address,
+			Meta:    map[string]string{defaultDaprPortMetaKey: port},
+			Weights: consul.AgentWeights{Passing: passingWeight, Warning: 1},
+		},
+		Checks: consul.HealthChecks{{Status: status}},
+	}
+}
+
+func newTestResolver(health healthClient) *resolver {
+	r := &resolver{
+		logger: logger.NewLogger("test"),
+		health: health,
+	}
+	r.applyConfig(resolverConfig{})
+	return r
+}
+
+func TestResolveEndpoints(t *testing.T) {
+	r := newTestResolver(&mockHealth{
+		entries: []*consul.ServiceEntry{
+			serviceEntry("10.0.0.1", "50002", consul.HealthPassing, 5),
+			serviceEntry("10.0.0.2", "50002", consul.HealthCritical, 5),
+			serviceEntry("10.0.0.3", "50002", consul.HealthWarning, 5),
+			serviceEntry("10.0.0.4", "notaport", consul.HealthPassing, 5),
+		},
+	})
+
+	endpoints, err := r.ResolveEndpoints(nr.ResolveRequest{ID: "myapp"})
+	assert.NoError(t, err)
+	assert.Len(t, endpoints, 3)
+
+	assert.Equal(t, "10.0.0.1:50002", endpoints[0].Address)
+	assert.True(t, endpoints[0].Healthy)
+	assert.Equal(t, 5, endpoints[0].Weight)
+
+	assert.Equal(t, "10.0.0.2:50002", endpoints[1].Address)
+	assert.False(t, endpoints[1].Healthy)
+
+	assert.True(t, endpoints[2].Healthy)
+	assert.Equal(t, 1, endpoints[2].Weight)
+}
+
+func TestResolveEndpointsLocality(t *testing.T) {
+	entry := serviceEntry("10.0.0.1", "50002", consul.HealthPassing, 1)
+	entry.Service.Meta[nodeMetaKey] = "node1"
+	entry.Service.Meta[zoneMetaKey] = "zone1"
+	r := newTestResolver(&mockHealth{
+		entries: []*consul.ServiceEntry{entry},
+	})
+
+	endpoints, err := r.ResolveEndpoints(nr.ResolveRequest{ID: "myapp"})
+	assert.NoError(t, err)
+	assert.Len(t, endpoints, 1)
+	assert.Equal(t, "node1", endpoints[0].Node)
+	assert.Equal(t, "zone1", endpoints[0].Zone)
+}
+
+func TestWithLocalityMeta(t *testing.T) {
+	t.Run("no locality", func(t *testing.T) {
+		rawConfig := map[string]interface{}{"selfRegister": true}
+		cfg, err := withLocalityMeta(rawConfig, "", "")
+		assert.NoError(t, err)
+		assert.Equal(t, rawConfig, cfg)
+	})
+
+	t.Run("no configuration", func(t *testing.T) {
+		cfg, err := withLocalityMeta(nil, "node1", "zone1")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"meta": map[string]interface{}{nodeMetaKey: "node1", zoneMetaKey: "zone1"},
+		}, cfg)
+	})
+
+	t.Run("configured meta takes precedence", func(t *testing.T) {
+		rawConfig := map[string]interface{}{
+			"selfRegister": true,
+			"Meta":         map[string]interface{}{zoneMetaKey: "zone2", "version": "1"},
+		}
+		cfg, err := withLocalityMeta(rawConfig, "node1", "zone1")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"selfRegister": true,
+			"Meta":         map[string]interface{}{nodeMetaKey: "node1", zoneMetaKey: "zone2", "version": "1"},
+		}, cfg)
+		// the configuration of the caller is not modified.
+		assert.Equal(t, map[string]interface{}{zoneMetaKey: "zone2", "version": "1"}, rawConfig["Meta"])
+	})
+}
+
+func TestResolveEndpointsErrors(t *testing.T) {
+	t.Run("query error", func(t *testing.T) {
+		r := newTestResolver(&mockHealth{err: errors.New("unreachable")})
+		_, err := r.ResolveEndpoints(nr.ResolveRequest{ID: "myapp"})
+		assert.Error(t, err)
+	})
+
+	t.Run("no services", func(t *testing.T) {
+		r := newTestResolver(&mockHealth{})
+		_, err := r.ResolveEndpoints(nr.ResolveRequest{ID: "myapp"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnssrv
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	nr "github.com/dapr/components-contrib/nameresolution"
+	"github.com/dapr/kit/config"
+	"github.com/dapr/kit/logger"
+
+	nr_loader "github.com/dapr/dapr/pkg/components/nameresolution"
+)
+
+const (
+	// defaultService is the name of the sidecar internal gRPC port, as published by the injected Kubernetes service.
+	defaultService  = "dapr-internal"
+	defaultProtocol = "tcp"
+	defaultHost     = "{appid}-dapr.{namespace}.svc.cluster.local"
+
+	appIDPlaceholder     = "{appid}"
+	namespacePlaceholder = "{namespace}"
+)
+
+type lookupSRVFn func(service, proto, name string) (string, []*net.SRV, error)
+
+type resolverConfig struct {
+	Service  string `json:"service"`
+	Protocol string `json:"protocol"`
+	Host     string `json:"host"`
+}
+
+type resolver struct {
+	config    resolverConfig
+	logger    logger.Logger
+	lookupSRV lookupSRVFn
+}
+
+// NewResolver creates a DNS SRV name resolver.
+func NewResolver(logger logger.Logger) nr.Resolver {
+	return newResolver(logger, net.LookupSRV)
+}
+
+func newResolver(logger logger.Logger, lookupSRV lookupSRVFn) *resolver {
+	return &resolver{
+		logger:    logger,
+		lookupSRV: lookupSRV,
+	}
+}
+
+// Init parses the resolver configuration.
+func (r *resolver) Init(metadata nr.Metadata) error {
+	cfg, err := parseConfig(metadata.Configuration)
+	if err != nil {
+		return err
+	}
+	r.config = cfg
+	return nil
+}
+
+// ResolveID resolves an app id to the address of the first SRV target with the lowest priority.
+func (r *resolver) ResolveID(req nr.ResolveRequest) (string, error) {
+	endpoints, err := r.ResolveEndpoints(req)
+	if err != nil {
+		return "", err
+	}
+	return endpoints[0].Address, nil
+}
+
+// ResolveEndpoints returns every SRV target published for an app id that shares the lowest priority.
+// SRV records carry no health information; DNS is expected to only publish ready targets.
+func (r *resolver) ResolveEndpoints(req nr.ResolveRequest) ([]nr_loader.Endpoint, error) {
+	name := r.hostName(req)
+	_, records, err := r.lookupSRV(r.config.Service, r.config.Protocol, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to lookup SRV records for %s", name)
+	}
+	if len(records) == 0 {
+		return nil, errors.Errorf("no SRV records found for app id %s", req.ID)
+	}
+
+	// net.LookupSRV sorts records by priority, so the first record holds the lowest one.
+	priority := records[0].Priority
+	endpoints := make([]nr_loader.Endpoint, 0, len(records))
+	for _, rec := range records {
+		if rec.Priority != priority {
+			break
+		}
+		weight := int(rec.Weight)
+		if weight == 0 {
+			weight = nr_loader.DefaultEndpointWeight
+		}
+		endpoints = append(endpoints, nr_loader.Endpoint{
+			Address: net.JoinHostPort(strings.TrimSuffix(rec.Target, "."), strconv.Itoa(int(rec.Port))),
+			Weight:  weight,
+			Healthy: true,
+		})
+	}
+	return endpoints, nil
+}
+
+func (r *resolver) hostName(req nr.ResolveRequest) string {
+	namespace := req.Namespace
+	if namespace == "" {
+		namespace = nr.DefaultNamespace
+	}
+	host := strings.ReplaceAll(r.config.Host, appIDPlaceholder, req.ID)
+	return strings.ReplaceAll(host, namespacePlaceholder, namespace)
+}
+
+func parseConfig(rawConfig interface{}) (resolverConfig, error) {
+	cfg := resolverConfig{}
+	if rawConfig != nil {
+		normalized, err := config.Normalize(rawConfig)
+		if err != nil {
+			return cfg, err
+		}
+		b, err := json.Marshal(normalized)
+		if err != nil {
+			return cfg, fmt.Errorf("error serializing dns srv configuration: %w", err)
+		}
+		if err = json.Unmarshal(b, &cfg); err != nil {
+			return cfg, fmt.Errorf("error deserializing dns srv configuration: %w", err)
+		}
+	}
+
+	if cfg.Service == "" {
+		cfg.Service = defaultService
+	}
+	if cfg.Protocol == "" {
+		cfg.Protocol = defaultProtocol
+	}
+	if cfg.Host == "" {
+		cfg.Host = defaultHost
+	}
+	return cfg, nil
+}
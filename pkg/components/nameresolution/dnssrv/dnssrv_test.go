@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnssrv
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nr "github.com/dapr/components-contrib/nameresolution"
+	"github.com/dapr/kit/logger"
+)
+
+func TestResolveEndpoints(t *testing.T) {
+	var queried string
+	lookup := func(service, proto, name string) (string, []*net.SRV, error) {
+		queried = "_" + service + "._" + proto + "." + name
+		return "", []*net.SRV{
+			{Target: "10.0.0.1.", Port: 50002, Priority: 1, Weight: 10},
+			{Target: "10.0.0.2.", Port: 50002, Priority: 1, Weight: 0},
+			{Target: "10.0.0.3.", Port: 50002, Priority: 2, Weight: 10},
+		}, nil
+	}
+
+	r := newResolver(logger.NewLogger("test"), lookup)
+	require.NoError(t, r.Init(nr.Metadata{}))
+
+	endpoints, err := r.ResolveEndpoints(nr.ResolveRequest{ID: "myapp", Namespace: "ns1"})
+	require.NoError(t, err)
+	assert.Equal(t, "_dapr-internal._tcp.myapp-dapr.ns1.svc.cluster.local", queried)
+	require.Len(t, endpoints, 2)
+	assert.Equal(t, "10.0.0.1:50002", endpoints[0].Address)
+	assert.Equal(t, 10, endpoints[0].Weight)
+	assert.Equal(t, 1, endpoints[1].Weight)
+	assert.True(t, endpoints[1].Healthy)
+
+	address, err := r.ResolveID(nr.ResolveRequest{ID: "myapp", Namespace: "ns1"})
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1:50002", address)
+}
+
+func TestResolveEndpointsErrors(t *testing.T) {
+	t.Run("lookup error", func(t *testing.T) {
+		r := newResolver(logger.NewLogger("test"), func(service, proto, name string) (string, []*net.SRV, error) {
+			return "", nil, errors.New("no such host")
+		})
+		require.NoError(t, r.Init(nr.Metadata{}))
+
+		_, err := r.ResolveID(nr.ResolveRequest{ID: "myapp"})
+		assert.Error(t, err)
+	})
+
+	t.Run("no records", func(t *testing.T) {
+		r := newResolver(logger.NewLogger("test"), func(service, proto, name string) (string, []*net.SRV, error) {
+			return "", nil, nil
+		})
+		require.NoError(t, r.Init(nr.Metadata{}))
+
+		_, err := r.ResolveEndpoints(nr.ResolveRequest{ID: "myapp"})
+		assert.Error(t, err)
+	})
+}
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := parseConfig(map[interface{}]interface{}{
+		"service": "grpc",
+		"host":    "{appid}.{namespace}.example.com",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "grpc", cfg.Service)
+	assert.Equal(t, defaultProtocol, cfg.Protocol)
+	assert.Equal(t, "{appid}.{namespace}.example.com", cfg.Host)
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nameresolution
+
+import (
+	nr "github.com/dapr/components-contrib/nameresolution"
+)
+
+// DefaultEndpointWeight is the weight assigned to endpoints whose resolver does not report one.
+const DefaultEndpointWeight = 1
+
+// Endpoint is a single addressable instance of an app.
+// Node and Zone are empty when the resolver does not report the locality of the instance.
+type Endpoint struct {
+	Address string
+	Weight  int
+	Healthy bool
+	Node    string
+	Zone    string
+}
+
+// EndpointResolver is implemented by name resolvers that can return every
+// known instance of an app, together with its weight and health status,
+// instead of a single address.
+type EndpointResolver interface {
+	nr.Resolver
+	ResolveEndpoints(req nr.ResolveRequest) ([]Endpoint, error)
+}
+
+// HealthyEndpoints returns the healthy subset of the given endpoints.
+func HealthyEndpoints(endpoints []Endpoint) []Endpoint {
+	healthy := make([]Endpoint, 0, len(endpoints))
+	for _, e := range endpoints {
+		if e.Healthy {
+			healthy = append(healthy, e)
+		}
+	}
+	return healthy
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstores
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dapr/components-contrib/secretstores"
+
+	diag "github.com/dapr/dapr/pkg/diagnostics"
+)
+
+type (
+	// Cache is implemented by secret stores caching the secrets they return.
+	Cache interface {
+		// Invalidate removes a secret from the cache, or all the secrets if key is empty.
+		Invalidate(key string)
+	}
+
+	// CachingSecretStore caches the responses of a secret store for a TTL.
+	// Requests with metadata, e.g. for a specific version of a secret, are not cached.
+	CachingSecretStore struct {
+		secretstores.SecretStore
+		name string
+		ttl  time.Duration
+		now  func() time.Time
+
+		lock    sync.RWMutex
+		secrets map[string]cachedSecret
+		bulk    *cachedBulkSecrets
+
+		onInvalidate []func(key string)
+	}
+
+	cachedSecret struct {
+		resp   secretstores.GetSecretResponse
+		expiry time.Time
+	}
+
+	cachedBulkSecrets struct {
+		resp   secretstores.BulkGetSecretResponse
+		expiry time.Time
+	}
+)
+
+// NewCachingSecretStore returns the secret store named name with its responses cached for ttl.
+func NewCachingSecretStore(name string, store secretstores.SecretStore, ttl time.Duration) *CachingSecretStore {
+	return &CachingSecretStore{
+		SecretStore: store,
+		name:        name,
+		ttl:         ttl,
+		now:         time.Now,
+		secrets:     map[string]cachedSecret{},
+	}
+}
+
+// GetSecret returns the secret from the cache, or from the secret store if it isn't cached or has expired.
+func (c *CachingSecretStore) GetSecret(req secretstores.GetSecretRequest) (secretstores.GetSecretResponse, error) {
+	if len(req.Metadata) > 0 {
+		return c.SecretStore.GetSecret(req)
+	}
+
+	now := c.now()
+	c.lock.RLock()
+	cached, ok := c.secrets[req.Name]
+	c.lock.RUnlock()
+	if ok && now.Before(cached.expiry) {
+		diag.DefaultMonitoring.SecretCacheHit(c.name)
+		return cached.resp, nil
+	}
+	diag.DefaultMonitoring.SecretCacheMiss(c.name)
+
+	resp, err := c.SecretStore.GetSecret(req)
+	if err != nil {
+		return resp, err
+	}
+
+	c.lock.Lock()
+	c.secrets[req.Name] = cachedSecret{resp: resp, expiry: now.Add(c.ttl)}
+	c.lock.Unlock()
+	return resp, nil
+}
+
+// BulkGetSecret returns all the secrets from the cache, or from the secret store if they aren't cached or have expired.
+func (c *CachingSecretStore) BulkGetSecret(req secretstores.BulkGetSecretRequest) (secretstores.BulkGetSecretResponse, error) {
+	if len(req.Metadata) > 0 {
+		return c.SecretStore.BulkGetSecret(req)
+	}
+
+	now := c.now()
+	c.lock.RLock()
+	cached := c.bulk
+	c.lock.RUnlock()
+	if cached != nil && now.Before(cached.expiry) {
+		diag.DefaultMonitoring.SecretCacheHit(c.name)
+		return cached.resp, nil
+	}
+	diag.DefaultMonitoring.SecretCacheMiss(c.name)
+
+	resp, err := c.SecretStore.BulkGetSecret(req)
+	if err != nil {
+		return resp, err
+	}
+
+	c.lock.Lock()
+	c.bulk = &cachedBulkSecrets{resp: resp, expiry: now.Add(c.ttl)}
+	c.lock.Unlock()
+	return resp, nil
+}
+
+// Invalidate removes a secret from the cache, or all the secrets if key is empty.
+// The bulk response is always removed since it may contain the secret.
+func (c *CachingSecretStore) Invalidate(key string) {
+	c.lock.Lock()
+	if key == "" {
+		c.secrets = map[string]cachedSecret{}
+	} else {
+		delete(c.secrets, key)
+	}
+	c.bulk = nil
+	onInvalidate := c.onInvalidate
+	c.lock.Unlock()
+
+	for _, fn := range onInvalidate {
+		fn(key)
+	}
+}
+
+// OnInvalidate registers fn to be called with the key of Invalidate, so that the secrets cached from the secret
+// store elsewhere are invalidated too.
+func (c *CachingSecretStore) OnInvalidate(fn func(key string)) {
+	c.lock.Lock()
+	c.onInvalidate = append(c.onInvalidate, fn)
+	c.lock.Unlock()
+}
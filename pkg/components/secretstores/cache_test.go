@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstores
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/components-contrib/secretstores"
+)
+
+type countingSecretStore struct {
+	secretstores.SecretStore
+	gets     int
+	bulkGets int
+	value    string
+}
+
+func (c *countingSecretStore) GetSecret(req secretstores.GetSecretRequest) (secretstores.GetSecretResponse, error) {
+	c.gets++
+	if req.Name == "missing" {
+		return secretstores.GetSecretResponse{}, errors.New("secret not found")
+	}
+	return secretstores.GetSecretResponse{Data: map[string]string{req.Name: c.value}}, nil
+}
+
+func (c *countingSecretStore) BulkGetSecret(req secretstores.BulkGetSecretRequest) (secretstores.BulkGetSecretResponse, error) {
+	c.bulkGets++
+	return secretstores.BulkGetSecretResponse{Data: map[string]map[string]string{"key1": {"key1": c.value}}}, nil
+}
+
+func TestCachingSecretStore(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &countingSecretStore{value: "v1"}
+	cache := NewCachingSecretStore("store1", store, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	get := func(name string, metadata map[string]string) string {
+		resp, err := cache.GetSecret(secretstores.GetSecretRequest{Name: name, Metadata: metadata})
+		assert.NoError(t, err)
+		return resp.Data[name]
+	}
+
+	t.Run("secrets are cached until they expire", func(t *testing.T) {
+		assert.Equal(t, "v1", get("key1", nil))
+		store.value = "v2"
+		assert.Equal(t, "v1", get("key1", nil))
+		assert.Equal(t, 1, store.gets)
+
+		now = now.Add(time.Minute)
+		assert.Equal(t, "v2", get("key1", nil))
+		assert.Equal(t, 2, store.gets)
+	})
+
+	t.Run("requests with metadata are not cached", func(t *testing.T) {
+		store.gets = 0
+		assert.Equal(t, "v2", get("key1", map[string]string{"version_id": "2"}))
+		assert.Equal(t, 1, store.gets)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		store.gets = 0
+		_, err := cache.GetSecret(secretstores.GetSecretRequest{Name: "missing"})
+		assert.Error(t, err)
+		_, err = cache.GetSecret(secretstores.GetSecretRequest{Name: "missing"})
+		assert.Error(t, err)
+		assert.Equal(t, 2, store.gets)
+	})
+
+	t.Run("bulk responses are cached", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			resp, err := cache.BulkGetSecret(secretstores.BulkGetSecretRequest{})
+			assert.NoError(t, err)
+			assert.Equal(t, "v2", resp.Data["key1"]["key1"])
+		}
+		assert.Equal(t, 1, store.bulkGets)
+	})
+
+	t.Run("invalidate a secret", func(t *testing.T) {
+		get("key2", nil)
+		store.gets = 0
+		get("key1", nil)
+		get("key2", nil)
+		assert.Equal(t, 0, store.gets)
+
+		cache.Invalidate("key1")
+		get("key1", nil)
+		get("key2", nil)
+		assert.Equal(t, 1, store.gets)
+
+		_, err := cache.BulkGetSecret(secretstores.BulkGetSecretRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, store.bulkGets)
+	})
+
+	t.Run("invalidate all the secrets", func(t *testing.T) {
+		store.gets = 0
+		cache.Invalidate("")
+		get("key1", nil)
+		get("key2", nil)
+		assert.Equal(t, 2, store.gets)
+	})
+
+	t.Run("invalidation hooks are called", func(t *testing.T) {
+		var keys []string
+		cache.OnInvalidate(func(key string) {
+			keys = append(keys, key)
+		})
+		cache.Invalidate("key1")
+		cache.Invalidate("")
+		assert.Equal(t, []string{"key1", ""}, keys)
+	})
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localfile
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+
+	"github.com/dapr/components-contrib/secretstores"
+	"github.com/dapr/components-contrib/secretstores/local/file"
+	"github.com/dapr/kit/logger"
+
+	secretstores_loader "github.com/dapr/dapr/pkg/components/secretstores"
+	"github.com/dapr/dapr/pkg/fswatcher"
+)
+
+// secretsFileKey is the metadata key of the components-contrib secret store.
+const secretsFileKey = "secretsFile"
+
+// LocalFileSecretStore is the local file secret store of components-contrib, reloading the secrets file when it
+// changes and notifying the rotations of the watched secrets.
+type LocalFileSecretStore struct {
+	logger   logger.Logger
+	metadata secretstores.Metadata
+
+	lock  sync.RWMutex
+	store secretstores.SecretStore
+}
+
+// NewLocalFileSecretStore creates a watching local file secret store.
+func NewLocalFileSecretStore(logger logger.Logger) secretstores.SecretStore {
+	return &LocalFileSecretStore{logger: logger}
+}
+
+// Init reads the secrets file.
+func (s *LocalFileSecretStore) Init(metadata secretstores.Metadata) error {
+	s.metadata = metadata
+	return s.reload()
+}
+
+// GetSecret returns the secret from the last read of the secrets file.
+func (s *LocalFileSecretStore) GetSecret(req secretstores.GetSecretRequest) (secretstores.GetSecretResponse, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.store.GetSecret(req)
+}
+
+// BulkGetSecret returns all the secrets from the last read of the secrets file.
+func (s *LocalFileSecretStore) BulkGetSecret(req secretstores.BulkGetSecretRequest) (secretstores.BulkGetSecretResponse, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.store.BulkGetSecret(req)
+}
+
+// Watch reloads the secrets file when it changes and calls handler with the secrets of keys which changed, until
+// ctx is done.
+func (s *LocalFileSecretStore) Watch(ctx context.Context, keys []string, metadata map[string]string, handler secretstores_loader.SecretUpdateHandler) error {
+	versions := map[string]string{}
+	check := func(notify bool) {
+		for _, key := range keys {
+			resp, err := s.GetSecret(secretstores.GetSecretRequest{Name: key, Metadata: metadata})
+			if err != nil {
+				s.logger.Debugf("failed to get secret %s: %s", key, err)
+				continue
+			}
+			version := secretstores_loader.Digest(resp.Data)
+			if previous, ok := versions[key]; ok && previous == version {
+				continue
+			}
+			versions[key] = version
+			if notify {
+				handler(secretstores_loader.SecretUpdate{Key: key, Data: resp.Data, Version: version})
+			}
+		}
+	}
+	check(false)
+
+	// the directory is watched since editors and kubernetes replace files instead of writing them.
+	dir := filepath.Dir(s.metadata.Properties[secretsFileKey])
+	eventCh := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := fswatcher.Watch(ctx, dir, eventCh); err != nil {
+			s.logger.Errorf("failed to watch secrets file: %s", err)
+		}
+	}()
+	go func() {
+		for {
+			select {
+			case <-eventCh:
+				if err := s.reload(); err != nil {
+					s.logger.Errorf("failed to reload secrets file: %s", err)
+					continue
+				}
+				check(true)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (s *LocalFileSecretStore) reload() error {
+	store := file.NewLocalSecretStore(s.logger)
+	if err := store.Init(s.metadata); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	s.store = store
+	s.lock.Unlock()
+	return nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localfile
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/components-contrib/secretstores"
+	"github.com/dapr/kit/logger"
+
+	secretstores_loader "github.com/dapr/dapr/pkg/components/secretstores"
+)
+
+func TestWatch(t *testing.T) {
+	secretsFile := filepath.Join(t.TempDir(), "secrets.json")
+	require.NoError(t, ioutil.WriteFile(secretsFile, []byte(`{"key1": "v1", "key2": "v1"}`), 0o600))
+
+	store := NewLocalFileSecretStore(logger.NewLogger("test"))
+	err := store.Init(secretstores.Metadata{Properties: map[string]string{secretsFileKey: secretsFile}})
+	require.NoError(t, err)
+	watcher, ok := store.(secretstores_loader.WatchingSecretStore)
+	require.True(t, ok)
+
+	updates := make(chan secretstores_loader.SecretUpdate, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err = watcher.Watch(ctx, []string{"key1", "key2"}, nil, func(update secretstores_loader.SecretUpdate) {
+		updates <- update
+	})
+	require.NoError(t, err)
+
+	// give time to the watcher to start.
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, ioutil.WriteFile(secretsFile, []byte(`{"key1": "v2", "key2": "v1"}`), 0o600))
+
+	select {
+	case update := <-updates:
+		assert.Equal(t, "key1", update.Key)
+		assert.Equal(t, "v2", update.Data["key1"])
+		assert.NotEmpty(t, update.Version)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timeout waiting for the rotation")
+	}
+
+	resp, err := store.GetSecret(secretstores.GetSecretRequest{Name: "key1"})
+	require.NoError(t, err)
+	assert.Equal(t, "v2", resp.Data["key1"])
+
+	// key2 didn't change.
+	select {
+	case update := <-updates:
+		assert.Fail(t, "unexpected update", update)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstores
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/dapr/components-contrib/secretstores"
+)
+
+// Cryptographic operations of transit engines.
+const (
+	OperationEncrypt = "encrypt"
+	OperationDecrypt = "decrypt"
+	OperationSign    = "sign"
+	OperationVerify  = "verify"
+)
+
+type (
+	// CryptoRequest is a cryptographic operation with a key of a secret store.
+	CryptoRequest struct {
+		// KeyName is the name of the key in the secret store.
+		KeyName string
+		// Data is the plaintext to encrypt, the ciphertext to decrypt, or the message to sign or verify.
+		Data []byte
+		// Signature is the signature to verify.
+		Signature []byte
+		// Algorithm is the algorithm of the operation, or empty for the default of the key.
+		Algorithm string
+		Metadata  map[string]string
+	}
+
+	// CryptoResponse is the result of a cryptographic operation.
+	CryptoResponse struct {
+		// Data is the ciphertext, the plaintext or the signature.
+		Data []byte
+		// Valid is the result of a verification.
+		Valid bool
+	}
+
+	// TransitSecretStore is implemented by secret stores with transit engines, e.g. Vault transit or cloud KMS,
+	// which perform cryptographic operations with their keys so that the keys are never distributed to the apps.
+	TransitSecretStore interface {
+		Encrypt(req CryptoRequest) (CryptoResponse, error)
+		Decrypt(req CryptoRequest) (CryptoResponse, error)
+		Sign(req CryptoRequest) (CryptoResponse, error)
+		Verify(req CryptoRequest) (CryptoResponse, error)
+	}
+)
+
+// GetTransitSecretStore returns the transit engine of store, if it has one.
+func GetTransitSecretStore(store secretstores.SecretStore) (TransitSecretStore, bool) {
+	// The cache doesn't apply to cryptographic operations.
+	if cache, ok := store.(*CachingSecretStore); ok {
+		store = cache.SecretStore
+	}
+	transit, ok := store.(TransitSecretStore)
+	return transit, ok
+}
+
+// Crypto performs the cryptographic operation with the transit engine.
+func Crypto(transit TransitSecretStore, operation string, req CryptoRequest) (CryptoResponse, error) {
+	var fn func(CryptoRequest) (CryptoResponse, error)
+	switch operation {
+	case OperationEncrypt:
+		fn = transit.Encrypt
+	case OperationDecrypt:
+		fn = transit.Decrypt
+	case OperationSign:
+		fn = transit.Sign
+	case OperationVerify:
+		fn = transit.Verify
+	default:
+		return CryptoResponse{}, errors.Errorf("unknown cryptographic operation %s", operation)
+	}
+	return fn(req)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstores
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/components-contrib/secretstores"
+)
+
+type operationTransitStore struct {
+	secretstores.SecretStore
+}
+
+func (o *operationTransitStore) Encrypt(req CryptoRequest) (CryptoResponse, error) {
+	return CryptoResponse{Data: []byte(OperationEncrypt)}, nil
+}
+
+func (o *operationTransitStore) Decrypt(req CryptoRequest) (CryptoResponse, error) {
+	return CryptoResponse{Data: []byte(OperationDecrypt)}, nil
+}
+
+func (o *operationTransitStore) Sign(req CryptoRequest) (CryptoResponse, error) {
+	return CryptoResponse{Data: []byte(OperationSign)}, nil
+}
+
+func (o *operationTransitStore) Verify(req CryptoRequest) (CryptoResponse, error) {
+	return CryptoResponse{Data: []byte(OperationVerify), Valid: true}, nil
+}
+
+func TestGetTransitSecretStore(t *testing.T) {
+	store := &operationTransitStore{}
+
+	transit, ok := GetTransitSecretStore(store)
+	assert.True(t, ok)
+	assert.Equal(t, store, transit)
+
+	transit, ok = GetTransitSecretStore(NewCachingSecretStore("store1", store, time.Minute))
+	assert.True(t, ok)
+	assert.Equal(t, store, transit)
+
+	_, ok = GetTransitSecretStore(&countingSecretStore{})
+	assert.False(t, ok)
+}
+
+func TestCrypto(t *testing.T) {
+	store := &operationTransitStore{}
+	for _, operation := range []string{OperationEncrypt, OperationDecrypt, OperationSign, OperationVerify} {
+		resp, err := Crypto(store, operation, CryptoRequest{KeyName: "key1"})
+		assert.NoError(t, err)
+		assert.Equal(t, operation, string(resp.Data))
+	}
+
+	_, err := Crypto(store, "wrap", CryptoRequest{KeyName: "key1"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstores
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"time"
+
+	"github.com/dapr/components-contrib/secretstores"
+	"github.com/dapr/kit/logger"
+)
+
+var log = logger.NewLogger("dapr.runtime.secretstores")
+
+// DefaultWatchInterval is the interval at which the secrets of stores which can't notify rotations are polled.
+const DefaultWatchInterval = time.Minute
+
+type (
+	// SecretUpdate is a new version of a watched secret.
+	SecretUpdate struct {
+		Key     string            `json:"key"`
+		Data    map[string]string `json:"data"`
+		Version string            `json:"version"`
+	}
+
+	// SecretUpdateHandler is called with the new versions of watched secrets.
+	SecretUpdateHandler func(update SecretUpdate)
+
+	// WatchingSecretStore is implemented by secret stores which notify the rotations of their secrets,
+	// e.g. through the events of the cloud provider.
+	WatchingSecretStore interface {
+		// Watch calls handler with each new version of the secrets until ctx is done.
+		Watch(ctx context.Context, keys []string, metadata map[string]string, handler SecretUpdateHandler) error
+	}
+)
+
+// Watch calls handler with the new versions of the secrets of keys until ctx is done.
+// Stores which don't implement WatchingSecretStore are polled at interval, and the versions of their secrets are
+// digests of their values. Cached secrets are invalidated when they are rotated.
+func Watch(ctx context.Context, store secretstores.SecretStore, keys []string, metadata map[string]string, interval time.Duration, handler SecretUpdateHandler) error {
+	// the store is read behind its cache, which would hide rotations until they expire.
+	cache, cached := store.(*CachingSecretStore)
+	if cached {
+		store = cache.SecretStore
+	}
+	notify := func(update SecretUpdate) {
+		if cached {
+			cache.Invalidate(update.Key)
+		}
+		handler(update)
+	}
+
+	if watcher, ok := store.(WatchingSecretStore); ok {
+		return watcher.Watch(ctx, keys, metadata, notify)
+	}
+
+	versions := map[string]string{}
+	poll := func(rotated bool) {
+		for _, key := range keys {
+			resp, err := store.GetSecret(secretstores.GetSecretRequest{Name: key, Metadata: metadata})
+			if err != nil {
+				log.Debugf("failed to poll secret %s: %s", key, err)
+				continue
+			}
+			version := Digest(resp.Data)
+			if previous, ok := versions[key]; ok && previous == version {
+				continue
+			}
+			versions[key] = version
+			if rotated {
+				notify(SecretUpdate{Key: key, Data: resp.Data, Version: version})
+			}
+		}
+	}
+
+	poll(false)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				poll(true)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Digest returns a version of the data of a secret, for stores which don't version their secrets.
+func Digest(data map[string]string) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(data[k]))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstores
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/components-contrib/secretstores"
+)
+
+type rotatingSecretStore struct {
+	secretstores.SecretStore
+	lock  sync.Mutex
+	value string
+}
+
+func (r *rotatingSecretStore) rotate(value string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.value = value
+}
+
+func (r *rotatingSecretStore) GetSecret(req secretstores.GetSecretRequest) (secretstores.GetSecretResponse, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return secretstores.GetSecretResponse{Data: map[string]string{req.Name: r.value}}, nil
+}
+
+type watchingSecretStore struct {
+	secretstores.SecretStore
+	keys []string
+}
+
+func (w *watchingSecretStore) Watch(ctx context.Context, keys []string, metadata map[string]string, handler SecretUpdateHandler) error {
+	w.keys = keys
+	handler(SecretUpdate{Key: keys[0], Version: "2"})
+	return nil
+}
+
+func TestWatch(t *testing.T) {
+	t.Run("polls stores which can't watch", func(t *testing.T) {
+		store := &rotatingSecretStore{value: "v1"}
+		updates := make(chan SecretUpdate, 10)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		err := Watch(ctx, store, []string{"key1"}, nil, 10*time.Millisecond, func(update SecretUpdate) {
+			updates <- update
+		})
+		assert.NoError(t, err)
+
+		// the current versions are not notified.
+		select {
+		case update := <-updates:
+			assert.Fail(t, "unexpected update", update)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		store.rotate("v2")
+		select {
+		case update := <-updates:
+			assert.Equal(t, "key1", update.Key)
+			assert.Equal(t, "v2", update.Data["key1"])
+			assert.NotEmpty(t, update.Version)
+		case <-time.After(time.Second):
+			assert.Fail(t, "timeout waiting for the rotation")
+		}
+	})
+
+	t.Run("cached secrets are invalidated only when rotated", func(t *testing.T) {
+		store := &rotatingSecretStore{value: "v1"}
+		cache := NewCachingSecretStore("store1", store, time.Hour)
+		updates := make(chan SecretUpdate, 10)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		get := func() string {
+			resp, err := cache.GetSecret(secretstores.GetSecretRequest{Name: "key1"})
+			assert.NoError(t, err)
+			return resp.Data["key1"]
+		}
+		assert.Equal(t, "v1", get())
+
+		var invalidations int32
+		cache.OnInvalidate(func(key string) {
+			atomic.AddInt32(&invalidations, 1)
+		})
+		err := Watch(ctx, cache, []string{"key1"}, nil, 10*time.Millisecond, func(update SecretUpdate) {
+			updates <- update
+		})
+		assert.NoError(t, err)
+
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, int32(0), atomic.LoadInt32(&invalidations))
+
+		store.rotate("v2")
+		select {
+		case update := <-updates:
+			assert.Equal(t, "v2", update.Data["key1"])
+		case <-time.After(time.Second):
+			assert.Fail(t, "timeout waiting for the rotation")
+		}
+		assert.Equal(t, int32(1), atomic.LoadInt32(&invalidations))
+		assert.Equal(t, "v2", get())
+	})
+
+	t.Run("delegates to stores which can watch", func(t *testing.T) {
+		store := &watchingSecretStore{}
+		var updates []SecretUpdate
+		err := Watch(context.Background(), store, []string{"key1"}, nil, time.Minute, func(update SecretUpdate) {
+			updates = append(updates, update)
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"key1"}, store.keys)
+		assert.Equal(t, []SecretUpdate{{Key: "key1", Version: "2"}}, updates)
+	})
+}
+
+func TestDigest(t *testing.T) {
+	assert.Equal(t, Digest(map[string]string{"a": "1", "b": "2"}), Digest(map[string]string{"b": "2", "a": "1"}))
+	assert.NotEqual(t, Digest(map[string]string{"a": "1"}), Digest(map[string]string{"a": "2"}))
+	assert.NotEqual(t, Digest(map[string]string{"a": "1b"}), Digest(map[string]string{"a1": "b"}))
+}
@@ -16,6 +16,8 @@ package components
 import (
 	"bufio"
 	"bytes"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -25,6 +27,7 @@ import (
 
 	components_v1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
 	config "github.com/dapr/dapr/pkg/config/modes"
+	"github.com/dapr/dapr/pkg/encryption"
 )
 
 const (
@@ -32,6 +35,9 @@ const (
 	componentKind = "Component"
 )
 
+// ErrDecryptComponents is returned when an encrypted components file can't be decrypted.
+var ErrDecryptComponents = errors.New("error decrypting components file")
+
 // StandaloneComponents loads components in a standalone mode environment.
 type StandaloneComponents struct {
 	config config.StandaloneConfig
@@ -45,8 +51,41 @@ func NewStandaloneComponents(configuration config.StandaloneConfig) *StandaloneC
 }
 
 // LoadComponents loads dapr components from a given directory.
+// When a shared components directory is configured, its components are loaded first and the components of the
+// components directory override the shared components with the same name.
 func (s *StandaloneComponents) LoadComponents() ([]components_v1alpha1.Component, error) {
-	files, err := os.ReadDir(s.config.ComponentsPath)
+	if s.config.SharedComponentsPath == "" {
+		return s.loadComponentsFromDir(s.config.ComponentsPath)
+	}
+
+	shared, err := s.loadComponentsFromDir(s.config.SharedComponentsPath)
+	if err != nil {
+		return nil, err
+	}
+	if s.config.ComponentsPath == "" {
+		return shared, nil
+	}
+	list, err := s.loadComponentsFromDir(s.config.ComponentsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	overridden := make(map[string]bool, len(list))
+	for _, comp := range list {
+		overridden[comp.Name] = true
+	}
+	for _, comp := range shared {
+		if overridden[comp.Name] {
+			log.Infof("component %s of %s is overridden by %s", comp.Name, s.config.SharedComponentsPath, s.config.ComponentsPath)
+			continue
+		}
+		list = append(list, comp)
+	}
+	return list, nil
+}
+
+func (s *StandaloneComponents) loadComponentsFromDir(dir string) ([]components_v1alpha1.Component, error) {
+	files, err := os.ReadDir(dir)
 	if err != nil {
 		return nil, err
 	}
@@ -55,7 +94,10 @@ func (s *StandaloneComponents) LoadComponents() ([]components_v1alpha1.Component
 
 	for _, file := range files {
 		if !file.IsDir() && s.isYaml(file.Name()) {
-			components := s.loadComponentsFromFile(file.Name())
+			components, err := s.loadComponentsFromPath(filepath.Join(dir, file.Name()))
+			if err != nil {
+				return nil, err
+			}
 			if len(components) > 0 {
 				list = append(list, components...)
 			}
@@ -65,26 +107,60 @@ func (s *StandaloneComponents) LoadComponents() ([]components_v1alpha1.Component
 	return list, nil
 }
 
-func (s *StandaloneComponents) loadComponentsFromFile(filename string) []components_v1alpha1.Component {
+func (s *StandaloneComponents) loadComponentsFromFile(filename string) ([]components_v1alpha1.Component, error) {
+	return s.loadComponentsFromPath(filepath.Join(s.config.ComponentsPath, filename))
+}
+
+// loadComponentsFromPath loads the components of a file. The errors reading or parsing the file are logged, but an
+// encrypted file which can't be decrypted is an error, so that the sidecar doesn't start without its components.
+func (s *StandaloneComponents) loadComponentsFromPath(path string) ([]components_v1alpha1.Component, error) {
 	var errors []error
 
 	components := []components_v1alpha1.Component{}
-	path := filepath.Join(s.config.ComponentsPath, filename)
 
 	b, err := os.ReadFile(path)
 	if err != nil {
 		log.Warnf("daprd load components error when reading file %s : %s", path, err)
-		return components
+		return components, nil
+	}
+
+	if s.isEncrypted(path) {
+		b, err = s.decrypt(path, b)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %s", ErrDecryptComponents, path, err)
+		}
 	}
+
 	components, errors = s.decodeYaml(b)
 	for _, err := range errors {
 		log.Warnf("daprd load components error when parsing components yaml resource in %s : %s", path, err)
 	}
-	return components
+	return components, nil
 }
 
-// isYaml checks whether the file is yaml or not.
+// decrypt decrypts an encrypted components file with the key of its encryption configured in the environment.
+func (s *StandaloneComponents) decrypt(path string, b []byte) ([]byte, error) {
+	if strings.ToLower(filepath.Ext(path)) == encryption.AgeManifestExtension {
+		identities, err := encryption.ManifestAgeIdentities()
+		if err != nil {
+			return nil, err
+		}
+		return encryption.DecryptAgeManifest(b, identities)
+	}
+
+	key, ok := encryption.ManifestKey()
+	if !ok {
+		return nil, fmt.Errorf("%s is not set", encryption.ManifestKeyEnvVar)
+	}
+	return encryption.DecryptManifest(b, key)
+}
+
+// isYaml checks whether the file is yaml or not, encrypted yaml files included.
 func (s *StandaloneComponents) isYaml(fileName string) bool {
+	if s.isEncrypted(fileName) {
+		fileName = strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	}
+
 	extension := strings.ToLower(filepath.Ext(fileName))
 	if extension == ".yaml" || extension == ".yml" {
 		return true
@@ -92,6 +168,12 @@ func (s *StandaloneComponents) isYaml(fileName string) bool {
 	return false
 }
 
+// isEncrypted checks whether the file is encrypted with the manifest encryption key or with age.
+func (s *StandaloneComponents) isEncrypted(fileName string) bool {
+	extension := strings.ToLower(filepath.Ext(fileName))
+	return extension == encryption.EncryptedManifestExtension || extension == encryption.AgeManifestExtension
+}
+
 // decodeYaml decodes the yaml document.
 func (s *StandaloneComponents) decodeYaml(b []byte) ([]components_v1alpha1.Component, []error) {
 	list := []components_v1alpha1.Component{}
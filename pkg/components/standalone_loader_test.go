@@ -7,8 +7,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	config "github.com/dapr/dapr/pkg/config/modes"
+	"github.com/dapr/dapr/pkg/encryption"
 )
 
 const configPrefix = "."
@@ -41,7 +43,8 @@ spec:
 		err := writeTempConfig(filename, yaml)
 		// defer os.Remove(filename)
 		assert.Nil(t, err)
-		components := request.loadComponentsFromFile(filename)
+		components, err := request.loadComponentsFromFile(filename)
+		assert.NoError(t, err)
 		assert.Len(t, components, 1)
 	})
 
@@ -56,14 +59,90 @@ name: statestore`
 		err := writeTempConfig(filename, yaml)
 		defer os.Remove(filename)
 		assert.Nil(t, err)
-		components := request.loadComponentsFromFile(filename)
+		components, err := request.loadComponentsFromFile(filename)
+		assert.NoError(t, err)
 		assert.Len(t, components, 0)
 	})
 
+	t.Run("encrypted yaml content", func(t *testing.T) {
+		key := encryption.Key{Key: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"}
+		t.Setenv(encryption.ManifestKeyEnvVar, key.Key)
+
+		filename := "test-component-encrypted.yaml.enc"
+		yaml := `
+apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: statestore
+spec:
+  type: state.couchbase
+  metadata:
+  - name: connectionString
+    value: secret
+`
+		encrypted, err := encryption.EncryptManifest([]byte(yaml), key)
+		assert.NoError(t, err)
+		err = writeTempConfig(filename, string(encrypted))
+		defer os.Remove(filename)
+		assert.Nil(t, err)
+
+		components, err := request.loadComponentsFromFile(filename)
+		assert.NoError(t, err)
+		assert.Len(t, components, 1)
+		assert.Equal(t, "secret", components[0].Spec.Metadata[0].Value.String())
+
+		t.Setenv(encryption.ManifestKeyEnvVar, "")
+		_, err = request.loadComponentsFromFile(filename)
+		assert.ErrorIs(t, err, ErrDecryptComponents)
+
+		t.Setenv(encryption.ManifestKeyEnvVar, "abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789")
+		_, err = request.loadComponentsFromFile(filename)
+		assert.ErrorIs(t, err, ErrDecryptComponents)
+	})
+
+	t.Run("age encrypted yaml content", func(t *testing.T) {
+		identity, err := encryption.NewAgeIdentity()
+		require.NoError(t, err)
+		t.Setenv(encryption.ManifestAgeIdentityEnvVar, identity.String())
+
+		filename := "test-component-encrypted.yaml.age"
+		yaml := `
+apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: statestore
+spec:
+  type: state.couchbase
+  metadata:
+  - name: connectionString
+    value: secret
+`
+		encrypted, err := encryption.EncryptAgeManifest([]byte(yaml), identity.Recipient())
+		assert.NoError(t, err)
+		err = writeTempConfig(filename, string(encrypted))
+		defer os.Remove(filename)
+		assert.Nil(t, err)
+
+		components, err := request.loadComponentsFromFile(filename)
+		assert.NoError(t, err)
+		assert.Len(t, components, 1)
+		assert.Equal(t, "secret", components[0].Spec.Metadata[0].Value.String())
+
+		other, err := encryption.NewAgeIdentity()
+		require.NoError(t, err)
+		t.Setenv(encryption.ManifestAgeIdentityEnvVar, other.String())
+		_, err = request.loadComponentsFromFile(filename)
+		assert.ErrorIs(t, err, ErrDecryptComponents)
+
+		_, err = request.LoadComponents()
+		assert.ErrorIs(t, err, ErrDecryptComponents)
+	})
+
 	t.Run("load components file not exist", func(t *testing.T) {
 		filename := "test-component-no-exist.yaml"
 
-		components := request.loadComponentsFromFile(filename)
+		components, err := request.loadComponentsFromFile(filename)
+		assert.NoError(t, err)
 		assert.Len(t, components, 0)
 	})
 }
@@ -79,9 +158,11 @@ func TestIsYaml(t *testing.T) {
 	assert.True(t, request.isYaml("test.YAML"))
 	assert.True(t, request.isYaml("test.yml"))
 	assert.True(t, request.isYaml("test.YML"))
+	assert.True(t, request.isYaml("test.yaml.enc"))
 	assert.False(t, request.isYaml("test.md"))
 	assert.False(t, request.isYaml("test.txt"))
 	assert.False(t, request.isYaml("test.sh"))
+	assert.False(t, request.isYaml("test.enc"))
 }
 
 func TestStandaloneDecodeValidYaml(t *testing.T) {
@@ -283,3 +364,39 @@ spec:
 	assert.Equal(t, "prop3", components[1].Spec.Metadata[0].Name)
 	assert.Equal(t, "value3", components[1].Spec.Metadata[0].Value.String())
 }
+
+func TestLoadSharedComponents(t *testing.T) {
+	component := func(name, componentType string) string {
+		return `
+apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: ` + name + `
+spec:
+  type: ` + componentType + `
+`
+	}
+	shared := t.TempDir()
+	app := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(shared, "statestore.yaml"), []byte(component("statestore", "state.redis")), fs.FileMode(0644)))
+	assert.NoError(t, os.WriteFile(filepath.Join(shared, "pubsub.yaml"), []byte(component("pubsub", "pubsub.redis")), fs.FileMode(0644)))
+	assert.NoError(t, os.WriteFile(filepath.Join(app, "statestore.yaml"), []byte(component("statestore", "state.in-memory")), fs.FileMode(0644)))
+
+	t.Run("app components override shared components", func(t *testing.T) {
+		loader := NewStandaloneComponents(config.StandaloneConfig{ComponentsPath: app, SharedComponentsPath: shared})
+		components, err := loader.LoadComponents()
+		assert.NoError(t, err)
+		types := map[string]string{}
+		for _, c := range components {
+			types[c.Name] = c.Spec.Type
+		}
+		assert.Equal(t, map[string]string{"statestore": "state.in-memory", "pubsub": "pubsub.redis"}, types)
+	})
+
+	t.Run("shared components only", func(t *testing.T) {
+		loader := NewStandaloneComponents(config.StandaloneConfig{SharedComponentsPath: shared})
+		components, err := loader.LoadComponents()
+		assert.NoError(t, err)
+		assert.Len(t, components, 2)
+	})
+}
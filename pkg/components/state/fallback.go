@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/dapr/pkg/chaos"
+	"github.com/dapr/dapr/pkg/encryption"
+	"github.com/dapr/dapr/pkg/resiliency"
+)
+
+// GetWithFallback gets the state of key from the store named storeName. If the store fails and policy is set, the
+// state is got from the fallback store of the policy instead, or the static response of the policy is returned.
+// It returns the name of the store the state was got from, which is empty for a static response.
+// The faults injected in the state operations on the store and the rejections of the concurrency limiter of the store,
+// if any, fail the get like the errors of the store.
+func GetWithFallback(ctx context.Context, stores map[string]state.Store, storeName, key, appID string, req *state.GetRequest, limiter *resiliency.ConcurrencyLimiter, policy *resiliency.FallbackPolicy) (*state.GetResponse, string, error) {
+	var resp *state.GetResponse
+	err := chaos.Inject(ctx, chaos.State, storeName)
+	if err == nil {
+		var release func(error)
+		if release, err = limiter.Acquire(); err == nil {
+			resp, err = stores[storeName].Get(req)
+			release(err)
+		}
+	}
+	if !canFallBack(policy, err) {
+		return resp, storeName, err
+	}
+
+	if policy.Response != nil {
+		return &state.GetResponse{Data: policy.Response.Body}, "", nil
+	}
+
+	store, ok := stores[policy.Component]
+	if !ok {
+		return nil, storeName, errors.Wrapf(err, "fallback state store %s not found", policy.Component)
+	}
+	k, kerr := GetModifiedStateKey(key, policy.Component, appID)
+	if kerr != nil {
+		return nil, storeName, kerr
+	}
+	fallbackReq := *req
+	fallbackReq.Key = k
+	resp, err = store.Get(&fallbackReq)
+	return resp, policy.Component, err
+}
+
+// SetFallback saves the states of reqs, whose save in the store named storeName failed with err, in the fallback store
+// of policy instead, under the keys of the fallback store. It returns err if the save doesn't fall back: the static
+// responses of fallback policies don't apply to writes, and neither do the fallbacks from or to encrypted stores,
+// since the values are encrypted for the store named storeName.
+func SetFallback(stores map[string]state.Store, storeName, appID string, reqs []state.SetRequest, policy *resiliency.FallbackPolicy, err error) error {
+	store, err := writeFallbackStore(stores, storeName, policy, err)
+	if store == nil {
+		return err
+	}
+
+	fallbackReqs := make([]state.SetRequest, len(reqs))
+	for i, req := range reqs {
+		k, kerr := GetModifiedStateKey(GetOriginalStateKey(req.Key), policy.Component, appID)
+		if kerr != nil {
+			return kerr
+		}
+		fallbackReqs[i] = req
+		fallbackReqs[i].Key = k
+	}
+	return store.BulkSet(fallbackReqs)
+}
+
+// DeleteFallback deletes the states of reqs, whose deletion from the store named storeName failed with err, from the
+// fallback store of policy instead, like SetFallback saves them.
+func DeleteFallback(stores map[string]state.Store, storeName, appID string, reqs []state.DeleteRequest, policy *resiliency.FallbackPolicy, err error) error {
+	store, err := writeFallbackStore(stores, storeName, policy, err)
+	if store == nil {
+		return err
+	}
+
+	fallbackReqs := make([]state.DeleteRequest, len(reqs))
+	for i, req := range reqs {
+		k, kerr := GetModifiedStateKey(GetOriginalStateKey(req.Key), policy.Component, appID)
+		if kerr != nil {
+			return kerr
+		}
+		fallbackReqs[i] = req
+		fallbackReqs[i].Key = k
+	}
+	return store.BulkDelete(fallbackReqs)
+}
+
+// canFallBack returns whether a state operation failing with err falls back on policy. The operations rejected by the
+// store for their ETag are failures of the request rather than of the store, and don't fall back.
+func canFallBack(policy *resiliency.FallbackPolicy, err error) bool {
+	if err == nil || policy == nil {
+		return false
+	}
+	var etagErr *state.ETagError
+	return !errors.As(err, &etagErr)
+}
+
+// writeFallbackStore returns the fallback store of a write on the store named storeName failing with err, or nil and
+// the error to return if the write doesn't fall back.
+func writeFallbackStore(stores map[string]state.Store, storeName string, policy *resiliency.FallbackPolicy, err error) (state.Store, error) {
+	if !canFallBack(policy, err) || policy.Component == "" ||
+		encryption.EncryptedStateStore(storeName) || encryption.EncryptedStateStore(policy.Component) {
+		return nil, err
+	}
+	store, ok := stores[policy.Component]
+	if !ok {
+		return nil, errors.Wrapf(err, "fallback state store %s not found", policy.Component)
+	}
+	return store, nil
+}
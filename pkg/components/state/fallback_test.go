@@ -0,0 +1,158 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/dapr/pkg/resiliency"
+)
+
+type fakeStore struct {
+	state.Store
+	data map[string][]byte
+	err  error
+}
+
+func (s fakeStore) Get(req *state.GetRequest) (*state.GetResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &state.GetResponse{Data: s.data[req.Key]}, nil
+}
+
+func (s fakeStore) BulkSet(reqs []state.SetRequest) error {
+	if s.err != nil {
+		return s.err
+	}
+	for _, req := range reqs {
+		s.data[req.Key] = req.Value.([]byte)
+	}
+	return nil
+}
+
+func (s fakeStore) BulkDelete(reqs []state.DeleteRequest) error {
+	if s.err != nil {
+		return s.err
+	}
+	for _, req := range reqs {
+		delete(s.data, req.Key)
+	}
+	return nil
+}
+
+func TestGetWithFallback(t *testing.T) {
+	stores := map[string]state.Store{
+		"store1": fakeStore{err: errors.New("unavailable")},
+		"store4": fakeStore{data: map[string][]byte{"store4||" + key: []byte("value")}},
+	}
+	req := &state.GetRequest{Key: key}
+
+	t.Run("no fallback", func(t *testing.T) {
+		_, storeName, err := GetWithFallback(context.Background(), stores, "store1", key, "app1", req, nil, nil)
+		assert.Error(t, err)
+		assert.Equal(t, "store1", storeName)
+	})
+
+	t.Run("fallback store", func(t *testing.T) {
+		resp, storeName, err := GetWithFallback(context.Background(), stores, "store1", key, "app1", req, nil, &resiliency.FallbackPolicy{Component: "store4"})
+		assert.NoError(t, err)
+		assert.Equal(t, "store4", storeName)
+		assert.Equal(t, []byte("value"), resp.Data)
+		assert.Equal(t, key, req.Key)
+	})
+
+	t.Run("static response", func(t *testing.T) {
+		resp, storeName, err := GetWithFallback(context.Background(), stores, "store1", key, "app1", req, nil, &resiliency.FallbackPolicy{
+			Response: &resiliency.StaticResponse{Body: []byte("{}")},
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, storeName)
+		assert.Equal(t, []byte("{}"), resp.Data)
+	})
+
+	t.Run("concurrency limit exceeded", func(t *testing.T) {
+		r, err := resiliency.FromConfiguration(config.ResiliencySpec{
+			Policies: config.ResiliencyPolicies{
+				Concurrency: map[string]config.ConcurrencyPolicySpec{
+					"single": {LatencyThreshold: "1s", InitialLimit: 1, MaxLimit: 1},
+				},
+			},
+			Targets: config.ResiliencyTargets{
+				Components: map[string]config.ComponentResiliencyTarget{
+					"store4": {Concurrency: "single"},
+				},
+			},
+		})
+		assert.NoError(t, err)
+		limiter := r.ComponentConcurrencyLimiter("store4")
+		release, err := limiter.Acquire()
+		assert.NoError(t, err)
+		defer release(nil)
+
+		_, _, err = GetWithFallback(context.Background(), stores, "store4", key, "app1", &state.GetRequest{Key: "store4||" + key}, limiter, nil)
+		assert.True(t, errors.Is(err, resiliency.ErrConcurrencyLimitExceeded))
+
+		resp, storeName, err := GetWithFallback(context.Background(), stores, "store4", key, "app1", &state.GetRequest{Key: "store4||" + key}, limiter, &resiliency.FallbackPolicy{
+			Response: &resiliency.StaticResponse{Body: []byte("{}")},
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, storeName)
+		assert.Equal(t, []byte("{}"), resp.Data)
+	})
+
+	t.Run("unknown fallback store", func(t *testing.T) {
+		_, _, err := GetWithFallback(context.Background(), stores, "store1", key, "app1", req, nil, &resiliency.FallbackPolicy{Component: "store9"})
+		assert.Error(t, err)
+	})
+}
+
+func TestWriteFallbacks(t *testing.T) {
+	stores := map[string]state.Store{
+		"store1": fakeStore{err: errors.New("unavailable")},
+		"store4": fakeStore{data: map[string][]byte{}},
+	}
+	policy := &resiliency.FallbackPolicy{Component: "store4"}
+	storeErr := errors.New("unavailable")
+
+	t.Run("set falls back on the fallback store", func(t *testing.T) {
+		err := SetFallback(stores, "store1", "app1", []state.SetRequest{{Key: key, Value: []byte("value")}}, policy, storeErr)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("value"), stores["store4"].(fakeStore).data["store4||"+key])
+	})
+
+	t.Run("delete falls back on the fallback store", func(t *testing.T) {
+		err := DeleteFallback(stores, "store1", "app1", []state.DeleteRequest{{Key: key}}, policy, storeErr)
+		assert.NoError(t, err)
+		assert.NotContains(t, stores["store4"].(fakeStore).data, "store4||"+key)
+	})
+
+	t.Run("successful writes don't fall back", func(t *testing.T) {
+		err := SetFallback(stores, "store1", "app1", []state.SetRequest{{Key: key, Value: []byte("value")}}, policy, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, stores["store4"].(fakeStore).data)
+	})
+
+	t.Run("etag mismatches don't fall back", func(t *testing.T) {
+		etagErr := state.NewETagError(state.ETagMismatch, storeErr)
+		err := SetFallback(stores, "store1", "app1", []state.SetRequest{{Key: key, Value: []byte("value")}}, policy, etagErr)
+		assert.Equal(t, etagErr, err)
+		assert.Empty(t, stores["store4"].(fakeStore).data)
+	})
+
+	t.Run("static responses don't apply to writes", func(t *testing.T) {
+		err := DeleteFallback(stores, "store1", "app1", []state.DeleteRequest{{Key: key}}, &resiliency.FallbackPolicy{
+			Response: &resiliency.StaticResponse{Body: []byte("{}")},
+		}, storeErr)
+		assert.Equal(t, storeErr, err)
+	})
+
+	t.Run("unknown fallback store", func(t *testing.T) {
+		err := SetFallback(stores, "store1", "app1", []state.SetRequest{{Key: key, Value: []byte("value")}}, &resiliency.FallbackPolicy{Component: "store9"}, storeErr)
+		assert.Error(t, err)
+	})
+}
@@ -17,8 +17,10 @@ import (
 	"context"
 	"encoding/json"
 	"os"
+	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
@@ -48,6 +50,25 @@ const (
 	StateEncryption     Feature = "State.Encryption"
 )
 
+// Access control modes. In audit mode, requests denied by the access control policies are logged but not rejected.
+const (
+	AccessControlModeEnforce = "enforce"
+	AccessControlModeAudit   = "audit"
+)
+
+// Operations on secrets which can be allowed in a secrets scope.
+const (
+	SecretOperationGet     = "get"
+	SecretOperationBulkGet = "bulkGet"
+)
+
+// Matching modes of the operation name of an access control policy.
+const (
+	OperationMatchPrefix = "prefix"
+	OperationMatchGlob   = "glob"
+	OperationMatchRegex  = "regex"
+)
+
 type Feature string
 
 // Configuration is an internal (and duplicate) representation of Dapr's Configuration CRD.
@@ -61,9 +82,11 @@ type Configuration struct {
 
 // AccessControlList is an in-memory access control list config for fast lookup.
 type AccessControlList struct {
-	DefaultAction string
-	TrustDomain   string
-	PolicySpec    map[string]AccessControlListPolicySpec
+	DefaultAction   string
+	TrustDomain     string
+	AuditMode       bool
+	TrustedGateways []string
+	PolicySpec      map[string]AccessControlListPolicySpec
 }
 
 // AccessControlListPolicySpec is an in-memory access control list config per app for fast lookup.
@@ -73,6 +96,17 @@ type AccessControlListPolicySpec struct {
 	TrustDomain         string
 	Namespace           string
 	AppOperationActions map[string]AccessControlListOperationAction
+	// PatternOperationActions holds the operations matched with a glob or regex pattern, or with query conditions.
+	// They are evaluated in the order they are specified, before the operations in AppOperationActions.
+	PatternOperationActions []AccessControlListPatternOperationAction
+}
+
+// AccessControlListPatternOperationAction is an in-memory access control list config for an operation matched with a pattern.
+type AccessControlListPatternOperationAction struct {
+	Pattern         *regexp.Regexp
+	Query           map[string]string
+	VerbAction      map[string]string
+	OperationAction string
 }
 
 // AccessControlListOperationAction is an in-memory access control list config per operation for fast lookup.
@@ -83,19 +117,58 @@ type AccessControlListOperationAction struct {
 }
 
 type ConfigurationSpec struct {
-	HTTPPipelineSpec   PipelineSpec       `json:"httpPipeline,omitempty" yaml:"httpPipeline,omitempty"`
-	TracingSpec        TracingSpec        `json:"tracing,omitempty" yaml:"tracing,omitempty"`
-	MTLSSpec           MTLSSpec           `json:"mtls,omitempty" yaml:"mtls,omitempty"`
-	MetricSpec         MetricSpec         `json:"metric,omitempty" yaml:"metric,omitempty"`
-	Secrets            SecretsSpec        `json:"secrets,omitempty" yaml:"secrets,omitempty"`
-	AccessControlSpec  AccessControlSpec  `json:"accessControl,omitempty" yaml:"accessControl,omitempty"`
-	NameResolutionSpec NameResolutionSpec `json:"nameResolution,omitempty" yaml:"nameResolution,omitempty"`
-	Features           []FeatureSpec      `json:"features,omitempty" yaml:"features,omitempty"`
-	APISpec            APISpec            `json:"api,omitempty" yaml:"api,omitempty"`
+	HTTPPipelineSpec    PipelineSpec       `json:"httpPipeline,omitempty" yaml:"httpPipeline,omitempty"`
+	AppHTTPPipelineSpec PipelineSpec       `json:"appHttpPipeline,omitempty" yaml:"appHttpPipeline,omitempty"`
+	TracingSpec         TracingSpec        `json:"tracing,omitempty" yaml:"tracing,omitempty"`
+	MTLSSpec            MTLSSpec           `json:"mtls,omitempty" yaml:"mtls,omitempty"`
+	MetricSpec          MetricSpec         `json:"metric,omitempty" yaml:"metric,omitempty"`
+	Secrets             SecretsSpec        `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+	AccessControlSpec   AccessControlSpec  `json:"accessControl,omitempty" yaml:"accessControl,omitempty"`
+	NameResolutionSpec  NameResolutionSpec `json:"nameResolution,omitempty" yaml:"nameResolution,omitempty"`
+	Features            []FeatureSpec      `json:"features,omitempty" yaml:"features,omitempty"`
+	APISpec             APISpec            `json:"api,omitempty" yaml:"api,omitempty"`
+	ResiliencySpec      ResiliencySpec     `json:"resiliency,omitempty" yaml:"resiliency,omitempty"`
+	GatewaySpec         GatewaySpec        `json:"gateway,omitempty" yaml:"gateway,omitempty"`
+	CompressionSpec     CompressionSpec    `json:"compression,omitempty" yaml:"compression,omitempty"`
+	AccessLogSpec       AccessLogSpec      `json:"accessLog,omitempty" yaml:"accessLog,omitempty"`
+	LoggingSpec         LoggingSpec        `json:"logging,omitempty" yaml:"logging,omitempty"`
+	ProfilingSpec       ProfilingSpec      `json:"profiling,omitempty" yaml:"profiling,omitempty"`
+	AuditSpec           AuditSpec          `json:"audit,omitempty" yaml:"audit,omitempty"`
+	SidecarSpec         SidecarSpec        `json:"sidecar,omitempty" yaml:"sidecar,omitempty"`
+	ChaosSpec           ChaosSpec          `json:"chaos,omitempty" yaml:"chaos,omitempty"`
+}
+
+// SidecarSpec defines the defaults of the Dapr sidecars injected in the pods of the namespace of the configuration.
+// The annotations of the pods take precedence over the defaults.
+type SidecarSpec struct {
+	Image     string               `json:"image,omitempty" yaml:"image,omitempty"`
+	LogLevel  string               `json:"logLevel,omitempty" yaml:"logLevel,omitempty"`
+	Env       []SidecarEnvVar      `json:"env,omitempty" yaml:"env,omitempty"`
+	Resources SidecarResourcesSpec `json:"resources,omitempty" yaml:"resources,omitempty"`
+}
+
+// SidecarEnvVar is an environment variable of the Dapr sidecar.
+type SidecarEnvVar struct {
+	Name  string `json:"name" yaml:"name"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// SidecarResourcesSpec defines the resource requests and limits of the Dapr sidecar.
+type SidecarResourcesSpec struct {
+	CPURequest    string `json:"cpuRequest,omitempty" yaml:"cpuRequest,omitempty"`
+	CPULimit      string `json:"cpuLimit,omitempty" yaml:"cpuLimit,omitempty"`
+	MemoryRequest string `json:"memoryRequest,omitempty" yaml:"memoryRequest,omitempty"`
+	MemoryLimit   string `json:"memoryLimit,omitempty" yaml:"memoryLimit,omitempty"`
 }
 
 type SecretsSpec struct {
 	Scopes []SecretsScope `json:"scopes"`
+	// DefaultStore is the secret store resolving the secret references of the components which don't set
+	// auth.secretStore.
+	DefaultStore string `json:"defaultStore,omitempty" yaml:"defaultStore,omitempty"`
+	// DenyUnresolvedRefs fails the components whose secret references can't be resolved, instead of loading them
+	// without the secret values.
+	DenyUnresolvedRefs bool `json:"denyUnresolvedRefs,omitempty" yaml:"denyUnresolvedRefs,omitempty"`
 }
 
 // SecretsScope defines the scope for secrets.
@@ -104,22 +177,272 @@ type SecretsScope struct {
 	StoreName      string   `json:"storeName" yaml:"storeName"`
 	AllowedSecrets []string `json:"allowedSecrets,omitempty" yaml:"allowedSecrets,omitempty"`
 	DeniedSecrets  []string `json:"deniedSecrets,omitempty" yaml:"deniedSecrets,omitempty"`
+	// AllowedSecretPatterns and DeniedSecretPatterns are regular expressions matched against the secret keys, in
+	// addition to the keys of AllowedSecrets and DeniedSecrets. They match anywhere in the keys unless anchored with
+	// ^ and $, so e.g. ^app1- is needed for app1- not to match other-app1-password.
+	AllowedSecretPatterns []string `json:"allowedSecretPatterns,omitempty" yaml:"allowedSecretPatterns,omitempty"`
+	DeniedSecretPatterns  []string `json:"deniedSecretPatterns,omitempty" yaml:"deniedSecretPatterns,omitempty"`
+	// AllowedOperations restricts the operations on the secret store, e.g. to get but not bulkGet.
+	// All the operations are allowed if it's empty.
+	AllowedOperations []string `json:"allowedOperations,omitempty" yaml:"allowedOperations,omitempty"`
 }
 
 type PipelineSpec struct {
 	Handlers []HandlerSpec `json:"handlers" yaml:"handlers"`
+	// Routes are the middleware chains applied after the handlers to the requests matching their path and methods.
+	// The chain of the first matching route is applied.
+	Routes []PipelineRouteSpec `json:"routes,omitempty" yaml:"routes,omitempty"`
+}
+
+// PipelineRouteSpec is the middleware chain of the requests matching a path and methods.
+// The path is a glob pattern matching the leading segments of the request paths, e.g. /v1.0/invoke/*.
+// Any method matches if Methods is empty.
+type PipelineRouteSpec struct {
+	Path     string        `json:"path" yaml:"path"`
+	Methods  []string      `json:"methods,omitempty" yaml:"methods,omitempty"`
+	Handlers []HandlerSpec `json:"handlers" yaml:"handlers"`
 }
 
 // APISpec describes the configuration for Dapr APIs.
 type APISpec struct {
-	Allowed []APIAccessRule `json:"allowed,omitempty"`
+	Allowed        []APIAccessRule       `json:"allowed,omitempty"`
+	Denied         []APIAccessRule       `json:"denied,omitempty"`
+	Authentication APIAuthenticationSpec `json:"authentication,omitempty"`
+	Policy         APIPolicySpec         `json:"policy,omitempty"`
+	GRPC           APIGRPCSpec           `json:"grpc,omitempty"`
+	HTTP           APIHTTPSpec           `json:"http,omitempty"`
+}
+
+// APIGRPCSpec describes the optional services served on the port of the gRPC API, next to the Dapr service.
+type APIGRPCSpec struct {
+	// Reflection enables the gRPC server reflection service, used by tools such as grpcurl to list the services.
+	Reflection bool `json:"reflection,omitempty"`
+	// Health enables the grpc.health.v1 health service, reporting the Dapr service as serving.
+	Health bool `json:"health,omitempty"`
+}
+
+// APIHTTPSpec describes the handling of the requests and responses of the HTTP API.
+type APIHTTPSpec struct {
+	Compression        APIHTTPCompressionSpec        `json:"compression,omitempty"`
+	MaxRequestBodySize APIHTTPMaxRequestBodySizeSpec `json:"maxRequestBodySize,omitempty"`
+}
+
+// APIHTTPCompressionSpec describes the compression of the responses of the HTTP API, negotiated with the
+// Accept-Encoding header of each request.
+type APIHTTPCompressionSpec struct {
+	// Algorithms lists the encodings the responses can be compressed with, among zstd, br and gzip, in the order
+	// preferred by the sidecar when the client accepts several of them equally. Compression is disabled when empty.
+	Algorithms []string `json:"algorithms,omitempty"`
+	// MinSize is the size in bytes under which responses are sent uncompressed. Defaults to 1024.
+	MinSize int `json:"minSize,omitempty"`
+}
+
+// APIHTTPMaxRequestBodySizeSpec sets the maximum size in MB of the request bodies of the invocation, publish and
+// state APIs, overriding the dapr-http-max-request-size flag for their routes so that the limit of one API does not
+// apply to the others. A zero value keeps the limit of the flag.
+type APIHTTPMaxRequestBodySizeSpec struct {
+	Invocation int `json:"invocation,omitempty"`
+	Publish    int `json:"publish,omitempty"`
+	State      int `json:"state,omitempty"`
+}
+
+// APIPolicySpec describes the policies authorizing the calls of the building block APIs, before the components
+// are invoked.
+type APIPolicySpec struct {
+	// DefaultAction is the action applied to the calls matched by no rule, allow or deny. Calls are allowed by default.
+	DefaultAction string          `json:"defaultAction,omitempty"`
+	Rules         []APIPolicyRule `json:"rules,omitempty"`
+}
+
+// APIPolicyRule is a policy applying its action to the calls matching its condition.
+type APIPolicyRule struct {
+	Name string `json:"name"`
+	// Condition is a CEL expression on the variables caller, api, operation, component, resource and protocol
+	// of the call. The operation is the same for both protocols, e.g. get, save, bulkGet, transaction or publish.
+	// The condition is evaluated on each key of the bulk and the transaction requests.
+	Condition string `json:"condition"`
+	// Action is allow or deny.
+	Action string `json:"action"`
+}
+
+// APIAuthenticationSpec describes how the requests to the Dapr APIs are authenticated, in addition to the api token.
+// The authentications are cumulative: when the api token is set as well, the requests must hold both the api token
+// and a valid bearer token.
+type APIAuthenticationSpec struct {
+	OIDC *OIDCSpec `json:"oidc,omitempty"`
+}
+
+// OIDCSpec describes the OIDC issuer whose JWT bearer tokens are accepted by the Dapr APIs.
+type OIDCSpec struct {
+	// Issuer is the URL of the issuer, used to discover its JWKS and checked against the iss claim of the tokens.
+	Issuer string `json:"issuer"`
+	// JWKSURL overrides the URL of the JWKS of the issuer, skipping the discovery.
+	JWKSURL string `json:"jwksURL,omitempty"`
+	// Audiences are the accepted audiences, one of which the aud claim of the tokens must contain.
+	Audiences []string `json:"audiences"`
 }
 
-// APIAccessRule describes an access rule for allowing a Dapr API to be enabled and accessible by an app.
+// APIAccessRule describes an access rule for allowing a Dapr API to be enabled and accessible by an app,
+// or for denying the access to it.
 type APIAccessRule struct {
 	Name     string `json:"name"`
 	Version  string `json:"version"`
 	Protocol string `json:"protocol"`
+	// Operations restricts the rule to the HTTP methods, or the gRPC methods, of the API, e.g. DELETE or DeleteState.
+	Operations []string `json:"operations,omitempty"`
+	// Resources restricts the rule to the components whose name matches one of the patterns, e.g. the state stores
+	// or the pubsubs. The patterns use the syntax of path.Match, e.g. orders-*.
+	Resources []string `json:"resources,omitempty"`
+}
+
+// ResiliencySpec describes the named resiliency policies and the targets they apply to.
+type ResiliencySpec struct {
+	Policies ResiliencyPolicies `json:"policies,omitempty" yaml:"policies,omitempty"`
+	Targets  ResiliencyTargets  `json:"targets,omitempty" yaml:"targets,omitempty"`
+}
+
+// ResiliencyPolicies holds the named resiliency policies.
+// Timeouts are durations such as "5s".
+type ResiliencyPolicies struct {
+	Timeouts        map[string]string                   `json:"timeouts,omitempty" yaml:"timeouts,omitempty"`
+	Retries         map[string]RetryPolicySpec          `json:"retries,omitempty" yaml:"retries,omitempty"`
+	Hedging         map[string]HedgingPolicySpec        `json:"hedging,omitempty" yaml:"hedging,omitempty"`
+	Mirroring       map[string]MirroringPolicySpec      `json:"mirroring,omitempty" yaml:"mirroring,omitempty"`
+	CircuitBreakers map[string]CircuitBreakerPolicySpec `json:"circuitBreakers,omitempty" yaml:"circuitBreakers,omitempty"`
+	Concurrency     map[string]ConcurrencyPolicySpec    `json:"concurrency,omitempty" yaml:"concurrency,omitempty"`
+	RetryBudgets    map[string]RetryBudgetSpec          `json:"retryBudgets,omitempty" yaml:"retryBudgets,omitempty"`
+	Fallbacks       map[string]FallbackPolicySpec       `json:"fallbacks,omitempty" yaml:"fallbacks,omitempty"`
+}
+
+// RetryPolicySpec describes a retry policy with a constant interval between attempts.
+// MaxRetries is the number of retries after the first attempt.
+type RetryPolicySpec struct {
+	Interval   string `json:"interval" yaml:"interval"`
+	MaxRetries int    `json:"maxRetries" yaml:"maxRetries"`
+}
+
+// HedgingPolicySpec describes a hedged request policy.
+// After Delay elapses without a response, another attempt is sent to a different instance
+// of the target, up to MaxAttempts concurrent attempts. The first successful response wins.
+type HedgingPolicySpec struct {
+	Delay       string `json:"delay" yaml:"delay"`
+	MaxAttempts int    `json:"maxAttempts" yaml:"maxAttempts"`
+}
+
+// MirroringPolicySpec describes a request mirroring policy.
+// Percentage percent of the invocations are also sent to the shadow AppID, whose responses are discarded.
+type MirroringPolicySpec struct {
+	AppID      string  `json:"appId" yaml:"appId"`
+	Percentage float64 `json:"percentage" yaml:"percentage"`
+}
+
+// CircuitBreakerPolicySpec describes a circuit breaker policy.
+// The circuit breaker opens after MaxConsecutiveFailures failed invocations, and lets an invocation through to
+// probe the target once Timeout elapsed, e.g. "30s".
+type CircuitBreakerPolicySpec struct {
+	MaxConsecutiveFailures int    `json:"maxConsecutiveFailures" yaml:"maxConsecutiveFailures"`
+	Timeout                string `json:"timeout" yaml:"timeout"`
+}
+
+// ConcurrencyPolicySpec describes an adaptive concurrency policy.
+// The limit of in-flight calls to the target starts at InitialLimit and stays between MinLimit and MaxLimit. It
+// increases additively while the calls complete within LatencyThreshold, e.g. "200ms", and is multiplied by
+// BackoffRatio on failed or slower calls.
+type ConcurrencyPolicySpec struct {
+	InitialLimit     int     `json:"initialLimit,omitempty" yaml:"initialLimit,omitempty"`
+	MinLimit         int     `json:"minLimit,omitempty" yaml:"minLimit,omitempty"`
+	MaxLimit         int     `json:"maxLimit,omitempty" yaml:"maxLimit,omitempty"`
+	LatencyThreshold string  `json:"latencyThreshold" yaml:"latencyThreshold"`
+	BackoffRatio     float64 `json:"backoffRatio,omitempty" yaml:"backoffRatio,omitempty"`
+}
+
+// RetryBudgetSpec describes a retry budget shared by the retries to a target.
+// The retries are capped to Ratio times the requests to the target over TTL, e.g. "10s", plus MinRetriesPerSecond.
+// MinRetriesPerSecond defaults to 10 and TTL to 10s.
+type RetryBudgetSpec struct {
+	Ratio               float64 `json:"ratio" yaml:"ratio"`
+	MinRetriesPerSecond int     `json:"minRetriesPerSecond,omitempty" yaml:"minRetriesPerSecond,omitempty"`
+	TTL                 string  `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+}
+
+// FallbackPolicySpec describes the fallback of the calls to a target failing once their retries are exhausted.
+// Exactly one of AppID, Component and Response is set: the calls are sent to another app, to another component of
+// the same type, e.g. a secondary state store, or answered with a static response.
+type FallbackPolicySpec struct {
+	AppID     string              `json:"appId,omitempty" yaml:"appId,omitempty"`
+	Component string              `json:"component,omitempty" yaml:"component,omitempty"`
+	Response  *StaticResponseSpec `json:"response,omitempty" yaml:"response,omitempty"`
+}
+
+// StaticResponseSpec describes a static response returned by a fallback.
+// StatusCode defaults to 200 and ContentType to text/plain.
+type StaticResponseSpec struct {
+	StatusCode  int    `json:"statusCode,omitempty" yaml:"statusCode,omitempty"`
+	ContentType string `json:"contentType,omitempty" yaml:"contentType,omitempty"`
+	Body        string `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// ResiliencyTargets maps targets to the names of the policies applied to them.
+type ResiliencyTargets struct {
+	Apps       map[string]AppResiliencyTarget       `json:"apps,omitempty" yaml:"apps,omitempty"`
+	Components map[string]ComponentResiliencyTarget `json:"components,omitempty" yaml:"components,omitempty"`
+}
+
+// AppResiliencyTarget holds the policies applied to service invocations of an app.
+// Routes maps the invoked methods to the policies overriding the ones of the app for them. The methods can be
+// patterns with the syntax of path.Match, e.g. orders/*, the exact methods being matched first.
+type AppResiliencyTarget struct {
+	Timeout        string                           `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Hedging        string                           `json:"hedging,omitempty" yaml:"hedging,omitempty"`
+	Mirroring      string                           `json:"mirroring,omitempty" yaml:"mirroring,omitempty"`
+	CircuitBreaker string                           `json:"circuitBreaker,omitempty" yaml:"circuitBreaker,omitempty"`
+	Concurrency    string                           `json:"concurrency,omitempty" yaml:"concurrency,omitempty"`
+	RetryBudget    string                           `json:"retryBudget,omitempty" yaml:"retryBudget,omitempty"`
+	Fallback       string                           `json:"fallback,omitempty" yaml:"fallback,omitempty"`
+	Routes         map[string]RouteResiliencyTarget `json:"routes,omitempty" yaml:"routes,omitempty"`
+}
+
+// RouteResiliencyTarget holds the policies applied to service invocations of a method of an app.
+type RouteResiliencyTarget struct {
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Hedging string `json:"hedging,omitempty" yaml:"hedging,omitempty"`
+}
+
+// ComponentResiliencyTarget holds the policies applied to a component.
+// Topics maps the topics of a pubsub to the policies overriding the ones of the pubsub for the delivery of their
+// events to the app.
+type ComponentResiliencyTarget struct {
+	Retry       string                           `json:"retry,omitempty" yaml:"retry,omitempty"`
+	Concurrency string                           `json:"concurrency,omitempty" yaml:"concurrency,omitempty"`
+	RetryBudget string                           `json:"retryBudget,omitempty" yaml:"retryBudget,omitempty"`
+	Fallback    string                           `json:"fallback,omitempty" yaml:"fallback,omitempty"`
+	Topics      map[string]TopicResiliencyTarget `json:"topics,omitempty" yaml:"topics,omitempty"`
+}
+
+// TopicResiliencyTarget holds the policies applied to the delivery of the events of a topic to the app.
+type TopicResiliencyTarget struct {
+	Retry string `json:"retry,omitempty" yaml:"retry,omitempty"`
+}
+
+// ChaosSpec describes the faults injected in the operations of the sidecar to test the resiliency policies, e.g. in
+// staging environments. No fault is injected unless Enabled is true.
+type ChaosSpec struct {
+	Enabled bool        `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Faults  []FaultSpec `json:"faults,omitempty" yaml:"faults,omitempty"`
+}
+
+// FaultSpec describes a fault injected in Percentage percent of the operations of a kind, invocation, state or
+// pubsub, on Target: an app ID for the invocations, a component name otherwise. The fault applies to all the targets
+// when Target is empty.
+// The fault delays the operations by Delay, e.g. "500ms", then fails them with Error, or aborts them as if the target
+// was unreachable when Abort is true.
+type FaultSpec struct {
+	Operation  string  `json:"operation" yaml:"operation"`
+	Target     string  `json:"target,omitempty" yaml:"target,omitempty"`
+	Percentage float64 `json:"percentage" yaml:"percentage"`
+	Delay      string  `json:"delay,omitempty" yaml:"delay,omitempty"`
+	Error      string  `json:"error,omitempty" yaml:"error,omitempty"`
+	Abort      bool    `json:"abort,omitempty" yaml:"abort,omitempty"`
 }
 
 type HandlerSpec struct {
@@ -139,9 +462,22 @@ type SelectorField struct {
 }
 
 type TracingSpec struct {
-	SamplingRate string     `json:"samplingRate" yaml:"samplingRate"`
-	Stdout       bool       `json:"stdout" yaml:"stdout"`
-	Zipkin       ZipkinSpec `json:"zipkin" yaml:"zipkin"`
+	SamplingRate  string                `json:"samplingRate" yaml:"samplingRate"`
+	SamplingRules []TracingSamplingRule `json:"samplingRules,omitempty" yaml:"samplingRules,omitempty"`
+	Stdout        bool                  `json:"stdout" yaml:"stdout"`
+	Zipkin        ZipkinSpec            `json:"zipkin" yaml:"zipkin"`
+}
+
+// TracingSamplingRule defines the sampling rate of the requests matching the rule, instead of the sampling rate
+// of the tracing spec. The first matching rule applies.
+type TracingSamplingRule struct {
+	// Path is a regular expression matching the path of the HTTP requests or the full method of the gRPC calls.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	// AppID is the id of the app which is invoked.
+	AppID string `json:"appId,omitempty" yaml:"appId,omitempty"`
+	// Errors applies the rule to the failed requests which were not sampled when they started.
+	Errors       bool   `json:"errors,omitempty" yaml:"errors,omitempty"`
+	SamplingRate string `json:"samplingRate" yaml:"samplingRate"`
 }
 
 // ZipkinSpec defines Zipkin trace configurations.
@@ -152,6 +488,56 @@ type ZipkinSpec struct {
 // MetricSpec configuration for metrics.
 type MetricSpec struct {
 	Enabled bool `json:"enabled" yaml:"enabled"`
+	// OTLP pushes the metrics to an OpenTelemetry collector, in addition to the Prometheus endpoint.
+	OTLP OTLPMetricSpec `json:"otlp,omitempty" yaml:"otlp,omitempty"`
+	// Rules drop metrics or reduce the cardinality of their labels.
+	Rules []MetricsRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+	// Histograms override the bucket boundaries of the histograms of groups of metrics.
+	Histograms []MetricHistogram `json:"histograms,omitempty" yaml:"histograms,omitempty"`
+	// Exemplars attaches the sampled traces of the measurements to the buckets of the histograms.
+	Exemplars bool `json:"exemplars,omitempty" yaml:"exemplars,omitempty"`
+}
+
+// MetricHistogram defines the buckets of the histograms of a group of metrics.
+type MetricHistogram struct {
+	// Group is the prefix of the names of the metrics, e.g. http/server or grpc.io/client.
+	// The longest matching group applies.
+	Group string `json:"group" yaml:"group"`
+	// Buckets are the increasing upper bounds of the buckets, in the unit of the metrics, e.g. ms for latencies.
+	Buckets []float64 `json:"buckets" yaml:"buckets"`
+}
+
+// MetricsRule defines the rule of a metric.
+type MetricsRule struct {
+	// Name is the name of the metric, e.g. http/server/request_count.
+	Name string `json:"name" yaml:"name"`
+	// Drop drops the metric.
+	Drop   bool          `json:"drop,omitempty" yaml:"drop,omitempty"`
+	Labels []MetricLabel `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+// MetricLabel defines the rule of a label of a metric.
+type MetricLabel struct {
+	Name string `json:"name" yaml:"name"`
+	// Drop removes the label from the metric.
+	Drop bool `json:"drop,omitempty" yaml:"drop,omitempty"`
+	// Buckets replace the values of the label matching their regex by their value. The first matching bucket applies.
+	Buckets []MetricLabelBucket `json:"buckets,omitempty" yaml:"buckets,omitempty"`
+}
+
+// MetricLabelBucket groups the values of a label matching Regex under Value, e.g. /v1.0/invoke/orders/{id}.
+type MetricLabelBucket struct {
+	Value string `json:"value" yaml:"value"`
+	Regex string `json:"regex" yaml:"regex"`
+}
+
+// OTLPMetricSpec defines the OTLP metrics exporter configurations.
+type OTLPMetricSpec struct {
+	// EndpointAddress is the URL of the OTLP/HTTP receiver of the collector, e.g. http://otel-collector:4318.
+	EndpointAddress string            `json:"endpointAddress" yaml:"endpointAddress"`
+	Headers         map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// PushInterval is the interval between exports, e.g. 30s.
+	PushInterval string `json:"pushInterval,omitempty" yaml:"pushInterval,omitempty"`
 }
 
 // AppPolicySpec defines the policy data structure for each app.
@@ -165,16 +551,22 @@ type AppPolicySpec struct {
 
 // AppOperation defines the data structure for each app operation.
 type AppOperation struct {
-	Operation string   `json:"name" yaml:"name"`
-	HTTPVerb  []string `json:"httpVerb" yaml:"httpVerb"`
-	Action    string   `json:"action" yaml:"action"`
+	Operation string            `json:"name" yaml:"name"`
+	HTTPVerb  []string          `json:"httpVerb" yaml:"httpVerb"`
+	Action    string            `json:"action" yaml:"action"`
+	Match     string            `json:"match,omitempty" yaml:"match,omitempty"`
+	Query     map[string]string `json:"query,omitempty" yaml:"query,omitempty"`
 }
 
 // AccessControlSpec is the spec object in ConfigurationSpec.
 type AccessControlSpec struct {
 	DefaultAction string          `json:"defaultAction" yaml:"defaultAction"`
 	TrustDomain   string          `json:"trustDomain" yaml:"trustDomain"`
+	Mode          string          `json:"mode,omitempty" yaml:"mode,omitempty"`
 	AppPolicies   []AppPolicySpec `json:"policies" yaml:"policies"`
+	// TrustedGateways holds the SPIFFE IDs of the gateways of this cluster, whose invocations forwarded from remote
+	// clusters are checked against the SPIFFE ID of the original caller.
+	TrustedGateways []string `json:"trustedGateways,omitempty" yaml:"trustedGateways,omitempty"`
 }
 
 type NameResolutionSpec struct {
@@ -184,9 +576,93 @@ type NameResolutionSpec struct {
 }
 
 type MTLSSpec struct {
-	Enabled          bool   `json:"enabled" yaml:"enabled"`
-	WorkloadCertTTL  string `json:"workloadCertTTL" yaml:"workloadCertTTL"`
-	AllowedClockSkew string `json:"allowedClockSkew" yaml:"allowedClockSkew"`
+	Enabled          bool                    `json:"enabled" yaml:"enabled"`
+	WorkloadCertTTL  string                  `json:"workloadCertTTL" yaml:"workloadCertTTL"`
+	AllowedClockSkew string                  `json:"allowedClockSkew" yaml:"allowedClockSkew"`
+	Federation       []TrustDomainFederation `json:"federation,omitempty" yaml:"federation,omitempty"`
+}
+
+// TrustDomainFederation defines the trust anchors of a remote trust domain that workloads of this cluster trust.
+// BundleEndpoint is the https URL of the SPIFFE bundle endpoint of the trust domain, whose trust anchors are
+// refreshed periodically, e.g. for trust domains of other organizations rotating their CA independently.
+type TrustDomainFederation struct {
+	TrustDomain      string `json:"trustDomain" yaml:"trustDomain"`
+	TrustAnchors     string `json:"trustAnchors,omitempty" yaml:"trustAnchors,omitempty"`
+	TrustAnchorsFile string `json:"trustAnchorsFile,omitempty" yaml:"trustAnchorsFile,omitempty"`
+	BundleEndpoint   string `json:"bundleEndpoint,omitempty" yaml:"bundleEndpoint,omitempty"`
+}
+
+// GatewaySpec defines the remote clusters that service invocation is routed to through a Dapr gateway.
+type GatewaySpec struct {
+	Clusters []RemoteClusterSpec `json:"clusters,omitempty" yaml:"clusters,omitempty"`
+}
+
+// RemoteClusterSpec defines the Dapr gateway of a remote cluster and the app ids hosted in it.
+type RemoteClusterSpec struct {
+	Name      string   `json:"name" yaml:"name"`
+	Address   string   `json:"address" yaml:"address"`
+	AppID     string   `json:"appId" yaml:"appId"`
+	Namespace string   `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Apps      []string `json:"apps,omitempty" yaml:"apps,omitempty"`
+}
+
+// CompressionSpec defines the compression of the payloads sent to other Dapr sidecars.
+// Payloads smaller than MinSize bytes are sent uncompressed.
+type CompressionSpec struct {
+	Algorithm string `json:"algorithm,omitempty" yaml:"algorithm,omitempty"`
+	MinSize   int    `json:"minSize,omitempty" yaml:"minSize,omitempty"`
+}
+
+// AccessLogSpec defines the access log of the APIs of the sidecar and of the invocations of the app.
+type AccessLogSpec struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Fields are the fields of the records, all the fields by default.
+	Fields []string `json:"fields,omitempty" yaml:"fields,omitempty"`
+	// SamplingRate is the ratio of the requests logged, between 0 and 1. All the requests are logged by default.
+	SamplingRate string `json:"samplingRate,omitempty" yaml:"samplingRate,omitempty"`
+	// Output is the file the records are appended to, stdout by default.
+	Output string `json:"output,omitempty" yaml:"output,omitempty"`
+}
+
+// LoggingSpec defines the output levels of the logs of the sidecar, which are applied without restart when changed.
+type LoggingSpec struct {
+	// Level is the output level of all the loggers.
+	Level string `json:"level,omitempty" yaml:"level,omitempty"`
+	// Scopes are the output levels of single loggers, e.g. runtime.actor, applied after Level.
+	Scopes map[string]string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+}
+
+// ProfilingSpec defines the continuous profiling of the sidecar, whose profiles are pushed periodically to a profiling
+// server, e.g. Pyroscope.
+type ProfilingSpec struct {
+	// EndpointAddress is the URL of the ingestion API of the profiling server, e.g. http://pyroscope:4040/ingest.
+	EndpointAddress string            `json:"endpointAddress" yaml:"endpointAddress"`
+	Headers         map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// Interval is the duration of the CPU profiles and the interval between exports, e.g. 30s. 10s by default.
+	Interval string `json:"interval,omitempty" yaml:"interval,omitempty"`
+	// Profiles are the types of the profiles pushed: cpu, heap, goroutine, mutex and block. cpu and heap by default.
+	Profiles []string `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+}
+
+// AuditSpec defines the audit log of the administrative and security-relevant events of daprd, the operator and
+// sentry.
+type AuditSpec struct {
+	Enabled bool            `json:"enabled" yaml:"enabled"`
+	Sinks   []AuditSinkSpec `json:"sinks,omitempty" yaml:"sinks,omitempty"`
+}
+
+// AuditSinkSpec defines a sink of the audit events.
+type AuditSinkSpec struct {
+	// Type is file, pubsub or otlp. The pubsub sink is only supported by daprd.
+	Type string `json:"type" yaml:"type"`
+	// Output is the file the events of the file sink are appended to, stdout by default.
+	Output string `json:"output,omitempty" yaml:"output,omitempty"`
+	// PubsubName and Topic are the topic the events of the pubsub sink are published to.
+	PubsubName string `json:"pubsubName,omitempty" yaml:"pubsubName,omitempty"`
+	Topic      string `json:"topic,omitempty" yaml:"topic,omitempty"`
+	// Endpoint is the URL of the OTLP/HTTP receiver of the otlp sink, e.g. http://otel-collector:4318.
+	Endpoint string            `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
 }
 
 // SpiffeID represents the separated fields in a spiffe id.
@@ -196,6 +672,11 @@ type SpiffeID struct {
 	AppID       string
 }
 
+// String returns the spiffe id in the spiffe://<trust-domain>/ns/<namespace>/<app-id> form.
+func (s *SpiffeID) String() string {
+	return SpiffeIDPrefix + s.TrustDomain + "/ns/" + s.Namespace + "/" + s.AppID
+}
+
 // FeatureSpec defines which preview features are enabled.
 type FeatureSpec struct {
 	Name    Feature `json:"name" yaml:"name"`
@@ -260,8 +741,13 @@ func LoadKubernetesConfiguration(config, namespace string, operatorClient operat
 	if resp.GetConfiguration() == nil {
 		return nil, errors.Errorf("configuration %s not found", config)
 	}
+	return ParseKubernetesConfiguration(resp.GetConfiguration())
+}
+
+// ParseKubernetesConfiguration parses a configuration from the JSON of its Kubernetes resource.
+func ParseKubernetesConfiguration(b []byte) (*Configuration, error) {
 	conf := LoadDefaultConfiguration()
-	err = json.Unmarshal(resp.GetConfiguration(), conf)
+	err := json.Unmarshal(b, conf)
 	if err != nil {
 		return nil, err
 	}
@@ -288,6 +774,16 @@ func sortAndValidateSecretsConfiguration(conf *Configuration) error {
 			!strings.EqualFold(scope.DefaultAccess, DenyAccess) {
 			return errors.Errorf("defaultAccess %q can be either allow or deny", scope.DefaultAccess)
 		}
+		for _, op := range scope.AllowedOperations {
+			if !strings.EqualFold(op, SecretOperationGet) && !strings.EqualFold(op, SecretOperationBulkGet) {
+				return errors.Errorf("allowedOperations %q can be either get or bulkGet", op)
+			}
+		}
+		for _, pattern := range append(scope.AllowedSecretPatterns, scope.DeniedSecretPatterns...) {
+			if _, err := getSecretPattern(pattern); err != nil {
+				return errors.Wrapf(err, "invalid secret pattern %q for storeName %q", pattern, scope.StoreName)
+			}
+		}
 		set.Insert(scope.StoreName)
 
 		// modify scope
@@ -308,13 +804,13 @@ func (c SecretsScope) IsSecretAllowed(key string) bool {
 	}
 
 	// If the allowedSecrets list is not empty then check if the access is specifically allowed for this key.
-	if len(c.AllowedSecrets) != 0 {
-		return containsKey(c.AllowedSecrets, key)
+	if len(c.AllowedSecrets) != 0 || len(c.AllowedSecretPatterns) != 0 {
+		return containsKey(c.AllowedSecrets, key) || matchesSecretPattern(c.AllowedSecretPatterns, key)
 	}
 
 	// Check key in deny list if deny list is present for the secret store.
 	// If the specific key is denied, then alone deny access.
-	if deny := containsKey(c.DeniedSecrets, key); deny {
+	if deny := containsKey(c.DeniedSecrets, key) || matchesSecretPattern(c.DeniedSecretPatterns, key); deny {
 		return !deny
 	}
 
@@ -322,6 +818,44 @@ func (c SecretsScope) IsSecretAllowed(key string) bool {
 	return access == AllowAccess
 }
 
+// IsOperationAllowed checks if an operation on the secret store, get or bulkGet, is allowed.
+func (c SecretsScope) IsOperationAllowed(operation string) bool {
+	if len(c.AllowedOperations) == 0 {
+		return true
+	}
+	for _, op := range c.AllowedOperations {
+		if strings.EqualFold(op, operation) {
+			return true
+		}
+	}
+	return false
+}
+
+// secretPatterns caches the compiled secret patterns since scopes are checked on every secret request.
+var secretPatterns sync.Map
+
+func getSecretPattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := secretPatterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	secretPatterns.Store(pattern, re)
+	return re, nil
+}
+
+func matchesSecretPattern(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		// Invalid patterns are rejected when the configuration is loaded.
+		if re, err := getSecretPattern(pattern); err == nil && re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
 // Runs Binary Search on a sorted list of strings to find a key.
 func containsKey(s []string, key string) bool {
 	index := sort.SearchStrings(s, key)
@@ -14,11 +14,15 @@ limitations under the License.
 package config
 
 import (
+	"encoding/json"
 	"os"
 	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/dapr/pkg/apis/configuration/v1alpha1"
 )
 
 func TestLoadStandaloneConfiguration(t *testing.T) {
@@ -198,6 +202,55 @@ func TestSortAndValidateSecretsConfigration(t *testing.T) {
 			},
 			errorExpected: false,
 		},
+		{
+			name: "invalid secret pattern",
+			config: Configuration{
+				Spec: ConfigurationSpec{
+					Secrets: SecretsSpec{
+						Scopes: []SecretsScope{
+							{
+								StoreName:            "testStore",
+								DeniedSecretPatterns: []string{"^db-(.*$"},
+							},
+						},
+					},
+				},
+			},
+			errorExpected: true,
+		},
+		{
+			name: "invalid allowed operation",
+			config: Configuration{
+				Spec: ConfigurationSpec{
+					Secrets: SecretsSpec{
+						Scopes: []SecretsScope{
+							{
+								StoreName:         "testStore",
+								AllowedOperations: []string{"get", "list"},
+							},
+						},
+					},
+				},
+			},
+			errorExpected: true,
+		},
+		{
+			name: "secret patterns and allowed operations",
+			config: Configuration{
+				Spec: ConfigurationSpec{
+					Secrets: SecretsSpec{
+						Scopes: []SecretsScope{
+							{
+								StoreName:             "testStore",
+								AllowedSecretPatterns: []string{"^app1-.*$"},
+								AllowedOperations:     []string{"Get", "bulkGet"},
+							},
+						},
+					},
+				},
+			},
+			errorExpected: false,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -287,6 +340,66 @@ func TestIsSecretAllowed(t *testing.T) {
 			secretKey:      "key1",
 			expectedResult: false,
 		},
+		{
+			name: "default deny with allow secret patterns",
+			scope: SecretsScope{
+				StoreName:             "testName",
+				DefaultAccess:         DenyAccess,
+				AllowedSecretPatterns: []string{"^app1-.*$"},
+			},
+			secretKey:      "app1-password",
+			expectedResult: true,
+		},
+		{
+			name: "allow secret patterns and specific allow secrets",
+			scope: SecretsScope{
+				StoreName:             "testName",
+				AllowedSecrets:        []string{"key1"},
+				AllowedSecretPatterns: []string{"^app1-.*$"},
+			},
+			secretKey:      "key1",
+			expectedResult: true,
+		},
+		{
+			name: "key not matching allow secret patterns",
+			scope: SecretsScope{
+				StoreName:             "testName",
+				DefaultAccess:         AllowAccess,
+				AllowedSecretPatterns: []string{"^app1-.*$"},
+			},
+			secretKey:      "app2-password",
+			expectedResult: false,
+		},
+		{
+			name: "default allow with deny secret patterns",
+			scope: SecretsScope{
+				StoreName:            "testName",
+				DefaultAccess:        AllowAccess,
+				DeniedSecretPatterns: []string{"^admin-", "-root$"},
+			},
+			secretKey:      "db-root",
+			expectedResult: false,
+		},
+		{
+			name: "key not matching deny secret patterns",
+			scope: SecretsScope{
+				StoreName:            "testName",
+				DefaultAccess:        AllowAccess,
+				DeniedSecretPatterns: []string{"^admin-", "-root$"},
+			},
+			secretKey:      "db-user",
+			expectedResult: true,
+		},
+		{
+			name: "anchored secret pattern doesn't match inside the key",
+			scope: SecretsScope{
+				StoreName:             "testName",
+				DefaultAccess:         DenyAccess,
+				AllowedSecretPatterns: []string{"^app1-"},
+			},
+			secretKey:      "other-app1-password",
+			expectedResult: false,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -295,6 +408,20 @@ func TestIsSecretAllowed(t *testing.T) {
 	}
 }
 
+func TestIsOperationAllowed(t *testing.T) {
+	t.Run("all operations allowed by default", func(t *testing.T) {
+		scope := SecretsScope{StoreName: "testName"}
+		assert.True(t, scope.IsOperationAllowed(SecretOperationGet))
+		assert.True(t, scope.IsOperationAllowed(SecretOperationBulkGet))
+	})
+
+	t.Run("only allowed operations", func(t *testing.T) {
+		scope := SecretsScope{StoreName: "testName", AllowedOperations: []string{"GET"}}
+		assert.True(t, scope.IsOperationAllowed(SecretOperationGet))
+		assert.False(t, scope.IsOperationAllowed(SecretOperationBulkGet))
+	})
+}
+
 func TestContainsKey(t *testing.T) {
 	s := []string{"a", "b", "c", "z"}
 	assert.False(t, containsKey(s, "h"), "unexpected result")
@@ -364,3 +491,122 @@ func TestMTLSSpecForStandAlone(t *testing.T) {
 		assert.Equal(t, "1h", config.Spec.MTLSSpec.AllowedClockSkew)
 	})
 }
+
+func TestParseKubernetesConfiguration(t *testing.T) {
+	t.Run("resiliency policies", func(t *testing.T) {
+		crd := v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				ResiliencySpec: v1alpha1.ResiliencySpec{
+					Policies: v1alpha1.ResiliencyPolicies{
+						Timeouts:        map[string]string{"fast": "1s"},
+						Retries:         map[string]v1alpha1.RetryPolicySpec{"retry3": {Interval: "100ms", MaxRetries: 3}},
+						Hedging:         map[string]v1alpha1.HedgingPolicySpec{"hedge": {Delay: "50ms", MaxAttempts: 2}},
+						Mirroring:       map[string]v1alpha1.MirroringPolicySpec{"shadow": {AppID: "app2", Percentage: 12.5}},
+						CircuitBreakers: map[string]v1alpha1.CircuitBreakerPolicySpec{"cb": {MaxConsecutiveFailures: 5, Timeout: "30s"}},
+						Concurrency:     map[string]v1alpha1.ConcurrencyPolicySpec{"aimd": {InitialLimit: 10, MinLimit: 1, MaxLimit: 100, LatencyThreshold: "200ms", BackoffRatio: 0.9}},
+						RetryBudgets:    map[string]v1alpha1.RetryBudgetSpec{"budget": {Ratio: 0.2, MinRetriesPerSecond: 5, TTL: "10s"}},
+						Fallbacks: map[string]v1alpha1.FallbackPolicySpec{
+							"static": {Response: &v1alpha1.StaticResponseSpec{StatusCode: 503, ContentType: "application/json", Body: "{}"}},
+							"backup": {Component: "statestore-backup"},
+						},
+					},
+					Targets: v1alpha1.ResiliencyTargets{
+						Apps: map[string]v1alpha1.AppResiliencyTarget{
+							"app1": {
+								Timeout:        "fast",
+								Hedging:        "hedge",
+								Mirroring:      "shadow",
+								CircuitBreaker: "cb",
+								Concurrency:    "aimd",
+								RetryBudget:    "budget",
+								Fallback:       "static",
+								Routes:         map[string]v1alpha1.RouteResiliencyTarget{"orders/*": {Timeout: "fast"}},
+							},
+						},
+						Components: map[string]v1alpha1.ComponentResiliencyTarget{
+							"statestore": {
+								Retry:    "retry3",
+								Fallback: "backup",
+								Topics:   map[string]v1alpha1.TopicResiliencyTarget{"orders": {Retry: "retry3"}},
+							},
+						},
+					},
+				},
+			},
+		}
+		b, err := json.Marshal(crd)
+		require.NoError(t, err)
+
+		conf, err := ParseKubernetesConfiguration(b)
+		require.NoError(t, err)
+
+		policies := conf.Spec.ResiliencySpec.Policies
+		assert.Equal(t, map[string]string{"fast": "1s"}, policies.Timeouts)
+		assert.Equal(t, RetryPolicySpec{Interval: "100ms", MaxRetries: 3}, policies.Retries["retry3"])
+		assert.Equal(t, HedgingPolicySpec{Delay: "50ms", MaxAttempts: 2}, policies.Hedging["hedge"])
+		assert.Equal(t, MirroringPolicySpec{AppID: "app2", Percentage: 12.5}, policies.Mirroring["shadow"])
+		assert.Equal(t, CircuitBreakerPolicySpec{MaxConsecutiveFailures: 5, Timeout: "30s"}, policies.CircuitBreakers["cb"])
+		assert.Equal(t, ConcurrencyPolicySpec{InitialLimit: 10, MinLimit: 1, MaxLimit: 100, LatencyThreshold: "200ms", BackoffRatio: 0.9}, policies.Concurrency["aimd"])
+		assert.Equal(t, RetryBudgetSpec{Ratio: 0.2, MinRetriesPerSecond: 5, TTL: "10s"}, policies.RetryBudgets["budget"])
+		assert.Equal(t, &StaticResponseSpec{StatusCode: 503, ContentType: "application/json", Body: "{}"}, policies.Fallbacks["static"].Response)
+		assert.Equal(t, "statestore-backup", policies.Fallbacks["backup"].Component)
+
+		targets := conf.Spec.ResiliencySpec.Targets
+		assert.Equal(t, AppResiliencyTarget{
+			Timeout:        "fast",
+			Hedging:        "hedge",
+			Mirroring:      "shadow",
+			CircuitBreaker: "cb",
+			Concurrency:    "aimd",
+			RetryBudget:    "budget",
+			Fallback:       "static",
+			Routes:         map[string]RouteResiliencyTarget{"orders/*": {Timeout: "fast"}},
+		}, targets.Apps["app1"])
+		assert.Equal(t, ComponentResiliencyTarget{
+			Retry:    "retry3",
+			Fallback: "backup",
+			Topics:   map[string]TopicResiliencyTarget{"orders": {Retry: "retry3"}},
+		}, targets.Components["statestore"])
+	})
+
+	t.Run("gateway", func(t *testing.T) {
+		crd := v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				GatewaySpec: v1alpha1.GatewaySpec{
+					Clusters: []v1alpha1.RemoteClusterSpec{
+						{Name: "east", Address: "gateway.east:50002", AppID: "gateway", Namespace: "dapr-system", Apps: []string{"app1"}},
+					},
+				},
+				AccessControlSpec: v1alpha1.AccessControlSpec{
+					TrustedGateways: []string{"spiffe://cluster.local/ns/dapr-system/gateway"},
+				},
+			},
+		}
+		b, err := json.Marshal(crd)
+		require.NoError(t, err)
+
+		conf, err := ParseKubernetesConfiguration(b)
+		require.NoError(t, err)
+		assert.Equal(t, []RemoteClusterSpec{
+			{Name: "east", Address: "gateway.east:50002", AppID: "gateway", Namespace: "dapr-system", Apps: []string{"app1"}},
+		}, conf.Spec.GatewaySpec.Clusters)
+		assert.Equal(t, []string{"spiffe://cluster.local/ns/dapr-system/gateway"}, conf.Spec.AccessControlSpec.TrustedGateways)
+	})
+
+	t.Run("compression", func(t *testing.T) {
+		crd := v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				CompressionSpec: v1alpha1.CompressionSpec{
+					Algorithm: "zstd",
+					MinSize:   1024,
+				},
+			},
+		}
+		b, err := json.Marshal(crd)
+		require.NoError(t, err)
+
+		conf, err := ParseKubernetesConfiguration(b)
+		require.NoError(t, err)
+		assert.Equal(t, CompressionSpec{Algorithm: "zstd", MinSize: 1024}, conf.Spec.CompressionSpec)
+	})
+}
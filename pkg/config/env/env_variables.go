@@ -17,4 +17,10 @@ const (
 	AppPort string = "APP_PORT"
 	// AppID is the ID of the application.
 	AppID string = "APP_ID"
+	// NodeName is the name of the node the instance runs on.
+	NodeName string = "DAPR_NODE_NAME"
+	// PodName is the name of the pod the instance runs in.
+	PodName string = "DAPR_POD_NAME"
+	// Zone is the zone the instance runs in.
+	Zone string = "DAPR_ZONE"
 )
@@ -16,4 +16,7 @@ package config
 // StandaloneConfig is the configuration for standalone mode.
 type StandaloneConfig struct {
 	ComponentsPath string
+	// SharedComponentsPath is a directory of components shared by the apps of a run profile.
+	// Components of ComponentsPath override the shared components with the same name.
+	SharedComponentsPath string
 }
@@ -0,0 +1,113 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TLS modes of the servers of the operational endpoints, such as metrics and profiling.
+const (
+	// ServerTLSDisabled serves plaintext connections.
+	ServerTLSDisabled = "disabled"
+	// ServerTLSEnabled serves TLS connections.
+	ServerTLSEnabled = "tls"
+	// ServerTLSMutual serves TLS connections of clients presenting a certificate signed by the trust anchors.
+	ServerTLSMutual = "mtls"
+)
+
+// CertificateSource returns the certificate presented by a server and the trust anchors verifying the client
+// certificates.
+type CertificateSource func() (*tls.Certificate, *x509.CertPool, error)
+
+// ServerTLSConfig returns the TLS config of a server in the given TLS mode, or nil when TLS is disabled.
+// The certificate is requested from the source on every handshake, so that rotated certificates are served.
+func ServerTLSConfig(mode string, source CertificateSource) (*tls.Config, error) {
+	switch mode {
+	case "", ServerTLSDisabled:
+		return nil, nil
+	case ServerTLSEnabled, ServerTLSMutual:
+	default:
+		return nil, errors.Errorf("invalid TLS mode %q, supported modes are %s, %s and %s", mode, ServerTLSDisabled, ServerTLSEnabled, ServerTLSMutual)
+	}
+	if source == nil {
+		return nil, errors.Errorf("TLS mode %s requires a certificate", mode)
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, trustAnchors, err := source()
+			if err != nil {
+				return nil, err
+			}
+
+			config := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+			}
+			if mode == ServerTLSMutual {
+				config.ClientAuth = tls.RequireAndVerifyClientCert
+				config.ClientCAs = trustAnchors
+			}
+			return config, nil
+		},
+	}, nil
+}
+
+// FileCertificateSource returns a source of the certificate, key and trust anchors of a credentials directory.
+// The files are loaded again when the certificate is modified.
+func FileCertificateSource(creds TLSCredentials) CertificateSource {
+	var (
+		lock         sync.Mutex
+		modTime      time.Time
+		cert         *tls.Certificate
+		trustAnchors *x509.CertPool
+	)
+
+	return func() (*tls.Certificate, *x509.CertPool, error) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		info, err := os.Stat(creds.CertPath())
+		if err != nil {
+			return nil, nil, err
+		}
+		if cert != nil && info.ModTime().Equal(modTime) {
+			return cert, trustAnchors, nil
+		}
+
+		chain, err := LoadFromDisk(creds.RootCertPath(), creds.CertPath(), creds.KeyPath())
+		if err != nil {
+			return nil, nil, err
+		}
+		pair, err := tls.X509KeyPair(chain.Cert, chain.Key)
+		if err != nil {
+			return nil, nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(chain.RootCA) {
+			return nil, nil, errors.Errorf("failed to load the trust anchors of %s", creds.RootCertPath())
+		}
+
+		modTime, cert, trustAnchors = info.ModTime(), &pair, pool
+		return cert, trustAnchors, nil
+	}
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPem []byte
+	keyPem  []byte
+}
+
+// newTestCert returns a certificate signed by the parent, or a self signed CA certificate when parent is nil.
+func newTestCert(t *testing.T, name string, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPem:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}),
+	}
+}
+
+func (c *testCert) tlsCertificate(t *testing.T) *tls.Certificate {
+	cert, err := tls.X509KeyPair(c.certPem, c.keyPem)
+	require.NoError(t, err)
+	return &cert
+}
+
+// serveTLS serves HTTP requests with the TLS config and returns the address of the server.
+func serveTLS(t *testing.T, config *tls.Config) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})}
+	go server.Serve(tls.NewListener(listener, config))
+	t.Cleanup(func() { server.Close() })
+	return "https://" + listener.Addr().String()
+}
+
+func TestServerTLSConfig(t *testing.T) {
+	ca := newTestCert(t, "ca", nil)
+	server := newTestCert(t, "server", ca)
+	client := newTestCert(t, "client", ca)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	source := func() (*tls.Certificate, *x509.CertPool, error) {
+		return server.tlsCertificate(t), roots, nil
+	}
+
+	newClient := func(cert *testCert) *http.Client {
+		config := &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+		if cert != nil {
+			config.Certificates = []tls.Certificate{*cert.tlsCertificate(t)}
+		}
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		config, err := ServerTLSConfig(ServerTLSDisabled, source)
+		assert.NoError(t, err)
+		assert.Nil(t, config)
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		_, err := ServerTLSConfig("strict", source)
+		assert.Error(t, err)
+	})
+
+	t.Run("no certificate source", func(t *testing.T) {
+		_, err := ServerTLSConfig(ServerTLSEnabled, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("tls", func(t *testing.T) {
+		config, err := ServerTLSConfig(ServerTLSEnabled, source)
+		require.NoError(t, err)
+		address := serveTLS(t, config)
+
+		resp, err := newClient(nil).Get(address)
+		require.NoError(t, err)
+		resp.Body.Close()
+	})
+
+	t.Run("mtls", func(t *testing.T) {
+		config, err := ServerTLSConfig(ServerTLSMutual, source)
+		require.NoError(t, err)
+		address := serveTLS(t, config)
+
+		resp, err := newClient(client).Get(address)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		_, err = newClient(nil).Get(address)
+		assert.Error(t, err)
+
+		other := newTestCert(t, "client", newTestCert(t, "other", nil))
+		_, err = newClient(other).Get(address)
+		assert.Error(t, err)
+	})
+}
+
+func TestFileCertificateSource(t *testing.T) {
+	dir := t.TempDir()
+	creds := NewTLSCredentials(dir)
+	writeCredentials := func(ca, cert *testCert) {
+		require.NoError(t, os.WriteFile(creds.RootCertPath(), ca.certPem, 0o600))
+		require.NoError(t, os.WriteFile(creds.CertPath(), cert.certPem, 0o600))
+		require.NoError(t, os.WriteFile(creds.KeyPath(), cert.keyPem, 0o600))
+	}
+
+	source := FileCertificateSource(creds)
+
+	t.Run("missing credentials", func(t *testing.T) {
+		_, _, err := source()
+		assert.Error(t, err)
+	})
+
+	t.Run("load and reload credentials", func(t *testing.T) {
+		ca := newTestCert(t, "ca", nil)
+		writeCredentials(ca, newTestCert(t, "server", ca))
+
+		cert, roots, err := source()
+		require.NoError(t, err)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		require.NoError(t, err)
+		_, err = leaf.Verify(x509.VerifyOptions{Roots: roots})
+		assert.NoError(t, err)
+
+		again, _, err := source()
+		require.NoError(t, err)
+		assert.Same(t, cert, again)
+
+		rotated := newTestCert(t, "rotated", ca)
+		writeCredentials(ca, rotated)
+		modTime := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(filepath.Join(dir, IssuerCertFilename), modTime, modTime))
+
+		cert, _, err = source()
+		require.NoError(t, err)
+		assert.Equal(t, rotated.tlsCertificate(t).Certificate, cert.Certificate)
+	})
+}
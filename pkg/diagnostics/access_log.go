@@ -0,0 +1,334 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dapr/dapr/pkg/config"
+	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
+)
+
+// Fields of the access log records.
+const (
+	AccessLogFieldTime          = "time"
+	AccessLogFieldAppID         = "appId"
+	AccessLogFieldSource        = "source"
+	AccessLogFieldProtocol      = "protocol"
+	AccessLogFieldMethod        = "method"
+	AccessLogFieldPath          = "path"
+	AccessLogFieldStatus        = "status"
+	AccessLogFieldLatency       = "latencyMs"
+	AccessLogFieldRequestBytes  = "requestBytes"
+	AccessLogFieldResponseBytes = "responseBytes"
+	AccessLogFieldRemoteAddr    = "remoteAddr"
+	AccessLogFieldTraceID       = "traceId"
+)
+
+// Sources of the access log records.
+const (
+	// AccessLogSourceAPI is the source of the requests served by the APIs of the sidecar.
+	AccessLogSourceAPI = "api"
+	// AccessLogSourceApp is the source of the requests sent to the app through the app channel.
+	AccessLogSourceApp = "app"
+)
+
+var accessLogFields = []string{
+	AccessLogFieldTime,
+	AccessLogFieldAppID,
+	AccessLogFieldSource,
+	AccessLogFieldProtocol,
+	AccessLogFieldMethod,
+	AccessLogFieldPath,
+	AccessLogFieldStatus,
+	AccessLogFieldLatency,
+	AccessLogFieldRequestBytes,
+	AccessLogFieldResponseBytes,
+	AccessLogFieldRemoteAddr,
+	AccessLogFieldTraceID,
+}
+
+// AccessLogRecord is a request logged in the access log.
+type AccessLogRecord struct {
+	Source        string
+	Protocol      string
+	Method        string
+	Path          string
+	Status        string
+	Latency       time.Duration
+	RequestBytes  int64
+	ResponseBytes int64
+	RemoteAddr    string
+}
+
+// DefaultAccessLog holds the access log of the APIs of the sidecar and of the invocations of the app.
+var DefaultAccessLog = newAccessLog()
+
+type accessLog struct {
+	appID        string
+	enabled      bool
+	fields       []string
+	samplingRate float64
+
+	lock sync.Mutex
+	out  io.Writer
+}
+
+func newAccessLog() *accessLog {
+	return &accessLog{
+		out: os.Stdout,
+	}
+}
+
+// Init enables the access log with the given spec.
+func (a *accessLog) Init(appID string, spec config.AccessLogSpec) error {
+	if !spec.Enabled {
+		return nil
+	}
+
+	fields := accessLogFields
+	if len(spec.Fields) > 0 {
+		for _, f := range spec.Fields {
+			if !isAccessLogField(f) {
+				return errors.Errorf("unknown access log field %s", f)
+			}
+		}
+		fields = spec.Fields
+	}
+
+	samplingRate := 1.0
+	if spec.SamplingRate != "" {
+		rate, err := strconv.ParseFloat(spec.SamplingRate, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return errors.Errorf("invalid access log sampling rate %s: must be between 0 and 1", spec.SamplingRate)
+		}
+		samplingRate = rate
+	}
+
+	var out io.Writer = os.Stdout
+	if spec.Output != "" {
+		f, err := os.OpenFile(spec.Output, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return errors.Wrap(err, "failed to open access log output")
+		}
+		out = f
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.appID = appID
+	a.fields = fields
+	a.samplingRate = samplingRate
+	a.out = out
+	a.enabled = true
+	return nil
+}
+
+func isAccessLogField(field string) bool {
+	for _, f := range accessLogFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEnabled returns true if the access log is enabled.
+func (a *accessLog) IsEnabled() bool {
+	return a.enabled
+}
+
+// sampled returns true if a request must be logged.
+func (a *accessLog) sampled() bool {
+	if !a.enabled {
+		return false
+	}
+	return a.samplingRate >= 1 || rand.Float64() < a.samplingRate // nolint:gosec
+}
+
+// Log writes the record of a request completed with the given context, when sampled.
+func (a *accessLog) Log(ctx context.Context, record AccessLogRecord) {
+	if !a.sampled() {
+		return
+	}
+	a.write(ctx, time.Now(), record)
+}
+
+func (a *accessLog) write(ctx context.Context, now time.Time, record AccessLogRecord) {
+	values := make(map[string]interface{}, len(a.fields))
+	for _, f := range a.fields {
+		switch f {
+		case AccessLogFieldTime:
+			values[f] = now.UTC().Format(time.RFC3339Nano)
+		case AccessLogFieldAppID:
+			values[f] = a.appID
+		case AccessLogFieldSource:
+			values[f] = record.Source
+		case AccessLogFieldProtocol:
+			values[f] = record.Protocol
+		case AccessLogFieldMethod:
+			values[f] = record.Method
+		case AccessLogFieldPath:
+			values[f] = record.Path
+		case AccessLogFieldStatus:
+			values[f] = record.Status
+		case AccessLogFieldLatency:
+			values[f] = float64(record.Latency) / float64(time.Millisecond)
+		case AccessLogFieldRequestBytes:
+			values[f] = record.RequestBytes
+		case AccessLogFieldResponseBytes:
+			values[f] = record.ResponseBytes
+		case AccessLogFieldRemoteAddr:
+			values[f] = record.RemoteAddr
+		case AccessLogFieldTraceID:
+			if span := diag_utils.SpanFromContext(ctx); span != nil {
+				values[f] = span.SpanContext().TraceID.String()
+			} else if sc, ok := SpanContextFromIncomingGRPCMetadata(ctx); ok {
+				// The gRPC access log is chained before the tracing interceptor, so the trace is the one of the caller.
+				values[f] = sc.TraceID.String()
+			} else {
+				values[f] = ""
+			}
+		}
+	}
+
+	b, err := json.Marshal(values)
+	if err != nil {
+		return
+	}
+	b = append(b, '\n')
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.out.Write(b)
+}
+
+// FastHTTPMiddleware logs the requests served by the HTTP APIs.
+func (a *accessLog) FastHTTPMiddleware(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		if !a.sampled() {
+			next(ctx)
+			return
+		}
+
+		reqContentSize := ctx.Request.Header.ContentLength()
+		if reqContentSize < 0 {
+			reqContentSize = 0
+		}
+
+		start := time.Now()
+		next(ctx)
+
+		// The body of a streamed response isn't read, which would buffer it whole. Its size is its content length, if known.
+		var respSize int64
+		if ctx.Response.IsBodyStream() {
+			if contentLength := ctx.Response.Header.ContentLength(); contentLength > 0 {
+				respSize = int64(contentLength)
+			}
+		} else {
+			respSize = int64(len(ctx.Response.Body()))
+		}
+		a.write(ctx, time.Now(), AccessLogRecord{
+			Source:        AccessLogSourceAPI,
+			Protocol:      "http",
+			Method:        string(ctx.Method()),
+			Path:          string(ctx.Path()),
+			Status:        strconv.Itoa(ctx.Response.StatusCode()),
+			Latency:       time.Since(start),
+			RequestBytes:  int64(reqContentSize),
+			ResponseBytes: respSize,
+			RemoteAddr:    ctx.RemoteAddr().String(),
+		})
+	}
+}
+
+// UnaryServerInterceptor logs the unary RPCs served by the gRPC APIs.
+func (a *accessLog) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !a.sampled() {
+			return handler(ctx, req)
+		}
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		var respSize int64
+		if err == nil {
+			respSize = int64(messageSize(resp))
+		}
+		a.write(ctx, time.Now(), AccessLogRecord{
+			Source:        AccessLogSourceAPI,
+			Protocol:      "grpc",
+			Method:        info.FullMethod,
+			Status:        status.Code(err).String(),
+			Latency:       time.Since(start),
+			RequestBytes:  int64(messageSize(req)),
+			ResponseBytes: respSize,
+			RemoteAddr:    peerAddr(ctx),
+		})
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor logs the streams served by the gRPC APIs.
+func (a *accessLog) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !a.sampled() {
+			return handler(srv, ss)
+		}
+
+		start := time.Now()
+		err := handler(srv, ss)
+
+		ctx := ss.Context()
+		a.write(ctx, time.Now(), AccessLogRecord{
+			Source:     AccessLogSourceAPI,
+			Protocol:   "grpc",
+			Method:     info.FullMethod,
+			Status:     status.Code(err).String(),
+			Latency:    time.Since(start),
+			RemoteAddr: peerAddr(ctx),
+		})
+		return err
+	}
+}
+
+func messageSize(m interface{}) int {
+	if msg, ok := m.(proto.Message); ok {
+		return proto.Size(msg)
+	}
+	return 0
+}
+
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
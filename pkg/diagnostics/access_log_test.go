@@ -0,0 +1,206 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"go.opencensus.io/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func newTestAccessLog(t *testing.T, spec config.AccessLogSpec) (*accessLog, *bytes.Buffer) {
+	spec.Enabled = true
+	a := newAccessLog()
+	assert.NoError(t, a.Init("fakeID", spec))
+	buf := &bytes.Buffer{}
+	a.out = buf
+	return a, buf
+}
+
+func readAccessLogRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var records []map[string]interface{}
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var r map[string]interface{}
+		assert.NoError(t, dec.Decode(&r))
+		records = append(records, r)
+	}
+	return records
+}
+
+func TestAccessLogInit(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		a := newAccessLog()
+		assert.NoError(t, a.Init("fakeID", config.AccessLogSpec{}))
+		assert.False(t, a.IsEnabled())
+	})
+
+	t.Run("all fields and requests by default", func(t *testing.T) {
+		a := newAccessLog()
+		assert.NoError(t, a.Init("fakeID", config.AccessLogSpec{Enabled: true}))
+		assert.True(t, a.IsEnabled())
+		assert.Equal(t, accessLogFields, a.fields)
+		assert.Equal(t, 1.0, a.samplingRate)
+		assert.Equal(t, os.Stdout, a.out)
+	})
+
+	t.Run("invalid specs", func(t *testing.T) {
+		for _, spec := range []config.AccessLogSpec{
+			{Enabled: true, Fields: []string{"path", "headers"}},
+			{Enabled: true, SamplingRate: "all"},
+			{Enabled: true, SamplingRate: "1.5"},
+			{Enabled: true, Output: filepath.Join(t.TempDir(), "missing", "access.log")},
+		} {
+			a := newAccessLog()
+			assert.Error(t, a.Init("fakeID", spec), spec)
+			assert.False(t, a.IsEnabled())
+		}
+	})
+
+	t.Run("records are appended to the output file", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "access.log")
+		a := newAccessLog()
+		assert.NoError(t, a.Init("fakeID", config.AccessLogSpec{Enabled: true, Fields: []string{"method"}, Output: output}))
+		a.Log(context.Background(), AccessLogRecord{Method: "GET"})
+		a.Log(context.Background(), AccessLogRecord{Method: "POST"})
+
+		b, err := os.ReadFile(output)
+		assert.NoError(t, err)
+		assert.Equal(t, "{\"method\":\"GET\"}\n{\"method\":\"POST\"}\n", string(b))
+	})
+}
+
+func TestAccessLogRecords(t *testing.T) {
+	t.Run("only the configured fields are written", func(t *testing.T) {
+		a, buf := newTestAccessLog(t, config.AccessLogSpec{Fields: []string{"appId", "source", "status", "traceId"}})
+		ctx, span := trace.StartSpan(context.Background(), "test", trace.WithSampler(trace.AlwaysSample()))
+		defer span.End()
+
+		a.Log(ctx, AccessLogRecord{Source: AccessLogSourceApp, Method: "mymethod", Status: "200"})
+		assert.Equal(t, []map[string]interface{}{{
+			"appId":   "fakeID",
+			"source":  "app",
+			"status":  "200",
+			"traceId": span.SpanContext().TraceID.String(),
+		}}, readAccessLogRecords(t, buf))
+	})
+
+	t.Run("requests are sampled", func(t *testing.T) {
+		a, buf := newTestAccessLog(t, config.AccessLogSpec{SamplingRate: "0"})
+		a.Log(context.Background(), AccessLogRecord{Method: "GET"})
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("nothing is written when disabled", func(t *testing.T) {
+		a := newAccessLog()
+		buf := &bytes.Buffer{}
+		a.out = buf
+		a.Log(context.Background(), AccessLogRecord{Method: "GET"})
+		assert.Empty(t, buf.String())
+	})
+}
+
+func TestAccessLogFastHTTPMiddleware(t *testing.T) {
+	a, buf := newTestAccessLog(t, config.AccessLogSpec{})
+	handler := a.FastHTTPMiddleware(func(ctx *fasthttp.RequestCtx) {
+		time.Sleep(10 * time.Millisecond)
+		ctx.Response.SetStatusCode(fasthttp.StatusCreated)
+		ctx.Response.SetBodyRaw([]byte("fake_responseDaprBody"))
+	})
+
+	handler(fakeFastHTTPRequestCtx("fake_requestDaprBody"))
+
+	records := readAccessLogRecords(t, buf)
+	assert.Len(t, records, 1)
+	r := records[0]
+	assert.Equal(t, "fakeID", r["appId"])
+	assert.Equal(t, "api", r["source"])
+	assert.Equal(t, "http", r["protocol"])
+	assert.Equal(t, "POST", r["method"])
+	assert.Equal(t, "/invoke/method/testmethod", r["path"])
+	assert.Equal(t, "201", r["status"])
+	assert.Equal(t, float64(len("fake_requestDaprBody")), r["requestBytes"])
+	assert.Equal(t, float64(len("fake_responseDaprBody")), r["responseBytes"])
+	assert.Equal(t, "1.2.3.4:6789", r["remoteAddr"])
+	assert.Equal(t, "", r["traceId"])
+	assert.GreaterOrEqual(t, r["latencyMs"], 10.0)
+	assert.NotEmpty(t, r["time"])
+}
+
+func TestAccessLogFastHTTPMiddlewareStreamedResponse(t *testing.T) {
+	a, buf := newTestAccessLog(t, config.AccessLogSpec{})
+	handler := a.FastHTTPMiddleware(func(ctx *fasthttp.RequestCtx) {
+		ctx.Response.SetBodyStream(bytes.NewReader([]byte("fake_responseDaprBody")), len("fake_responseDaprBody"))
+	})
+
+	ctx := fakeFastHTTPRequestCtx("fake_requestDaprBody")
+	handler(ctx)
+
+	// the streamed body isn't buffered by the middleware.
+	assert.True(t, ctx.Response.IsBodyStream())
+	records := readAccessLogRecords(t, buf)
+	assert.Len(t, records, 1)
+	assert.Equal(t, float64(len("fake_responseDaprBody")), records[0]["responseBytes"])
+}
+
+func TestAccessLogUnaryServerInterceptor(t *testing.T) {
+	a, buf := newTestAccessLog(t, config.AccessLogSpec{Fields: []string{"protocol", "method", "status", "requestBytes", "responseBytes", "remoteAddr"}})
+	interceptor := a.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/dapr.proto.runtime.v1.Dapr/GetState"}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("1.2.3.4"), Port: 6789}})
+	req := wrapperspb.String("request")
+
+	t.Run("completed RPC", func(t *testing.T) {
+		resp := wrapperspb.String("response body")
+		_, err := interceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return resp, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []map[string]interface{}{{
+			"protocol":      "grpc",
+			"method":        "/dapr.proto.runtime.v1.Dapr/GetState",
+			"status":        "OK",
+			"requestBytes":  float64(messageSize(req)),
+			"responseBytes": float64(messageSize(resp)),
+			"remoteAddr":    "1.2.3.4:6789",
+		}}, readAccessLogRecords(t, buf))
+	})
+
+	t.Run("failed RPC", func(t *testing.T) {
+		_, err := interceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "not found")
+		})
+		assert.Error(t, err)
+		records := readAccessLogRecords(t, buf)
+		assert.Len(t, records, 1)
+		assert.Equal(t, "NotFound", records[0]["status"])
+		assert.Equal(t, float64(0), records[0]["responseBytes"])
+	})
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opencensus.io/trace"
+
+	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/dapr/pkg/config"
+	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
+	"github.com/dapr/dapr/pkg/drain"
+)
+
+// componentTypeKey is a tag key for the type of a component, e.g. state or bindings.
+var componentTypeKey = tag.MustNewKey("component_type")
+
+// Reasons of the failures of the operations on components.
+const (
+	componentFailReasonTimeout      = "timeout"
+	componentFailReasonCanceled     = "canceled"
+	componentFailReasonETagMismatch = "etag_mismatch"
+	componentFailReasonETagInvalid  = "etag_invalid"
+	componentFailReasonError        = "error"
+)
+
+// componentMetrics holds the metrics of the operations on the component instances.
+type componentMetrics struct {
+	operationLatency *stats.Float64Measure
+	operationFailed  *stats.Int64Measure
+	inFlight         *stats.Int64Measure
+
+	// inFlightCounts are the numbers of operations in progress by component.
+	inFlightCounts sync.Map
+
+	appID   string
+	enabled bool
+}
+
+// newComponentMetrics returns componentMetrics instance with default component metric stats.
+func newComponentMetrics() *componentMetrics {
+	return &componentMetrics{
+		operationLatency: stats.Float64(
+			"component/operation/latency",
+			"The latency of the operations on a component.",
+			stats.UnitMilliseconds),
+		operationFailed: stats.Int64(
+			"component/operation/failed_total",
+			"The number of failed operations on a component.",
+			stats.UnitDimensionless),
+		inFlight: stats.Int64(
+			"component/operation/in_flight",
+			"The number of operations in progress on a component, which shows the saturation of its connections.",
+			stats.UnitDimensionless),
+
+		enabled: false,
+	}
+}
+
+// Init registers the component metrics views.
+func (c *componentMetrics) Init(appID string) error {
+	c.appID = appID
+	c.enabled = true
+
+	tags := []tag.Key{appIDKey, componentKey, componentTypeKey, operationKey}
+	return registerViews(
+		diag_utils.NewMeasureView(c.operationLatency, tags, defaultLatencyDistribution),
+		diag_utils.NewMeasureView(c.operationFailed, append(tags, failReasonKey), view.Count()),
+		diag_utils.NewMeasureView(c.inFlight, []tag.Key{appIDKey, componentKey, componentTypeKey}, view.LastValue()),
+	)
+}
+
+// operationStarted records an operation in progress on a component.
+func (c *componentMetrics) operationStarted(ctx context.Context, componentType, componentName string) {
+	if c.enabled {
+		c.recordInFlight(ctx, componentType, componentName, 1)
+	}
+}
+
+// operationCompleted records the latency of an operation on a component, and its failure if err is not nil.
+func (c *componentMetrics) operationCompleted(ctx context.Context, componentType, componentName, operation string, err error, elapsed float64) {
+	if c.enabled {
+		c.recordInFlight(ctx, componentType, componentName, -1)
+		recordWithTags(
+			ctx,
+			diag_utils.WithTags(appIDKey, c.appID, componentKey, componentName, componentTypeKey, componentType, operationKey, operation),
+			c.operationLatency.M(elapsed))
+		if err != nil {
+			recordWithTags(
+				ctx,
+				diag_utils.WithTags(appIDKey, c.appID, componentKey, componentName, componentTypeKey, componentType, operationKey, operation, failReasonKey, componentFailReason(err)),
+				c.operationFailed.M(1))
+		}
+	}
+}
+
+func (c *componentMetrics) recordInFlight(ctx context.Context, componentType, componentName string, delta int64) {
+	count, _ := c.inFlightCounts.LoadOrStore(componentType+"/"+componentName, new(int64))
+	recordWithTags(
+		ctx,
+		diag_utils.WithTags(appIDKey, c.appID, componentKey, componentName, componentTypeKey, componentType),
+		c.inFlight.M(atomic.AddInt64(count.(*int64), delta)))
+}
+
+// componentFailReason returns the reason of the failure of an operation on a component.
+func componentFailReason(err error) string {
+	var etagErr *state.ETagError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return componentFailReasonTimeout
+	case errors.Is(err, context.Canceled):
+		return componentFailReasonCanceled
+	case errors.As(err, &etagErr):
+		if etagErr.Kind() == state.ETagMismatch {
+			return componentFailReasonETagMismatch
+		}
+		return componentFailReasonETagInvalid
+	default:
+		return componentFailReasonError
+	}
+}
+
+// ComponentOperation is an operation on a component instance, which is traced and measured from its start until End
+// is called.
+type ComponentOperation struct {
+	span          *trace.Span
+	componentType string
+	componentName string
+	operation     string
+	start         time.Time
+	// done ends the tracking of the operation, which holds the reload of the component.
+	done func()
+}
+
+// StartStateOperation starts an operation on a state store, with the number of keys of the operation.
+func StartStateOperation(ctx context.Context, spec config.TracingSpec, storeName, operation string, keyCount int) *ComponentOperation {
+	return startComponentOperation(ctx, spec, stateBuildingBlockType, storeName, operation, keyCount)
+}
+
+// StartPubsubOperation starts an operation on a pubsub, e.g. the publishing or the delivery of an event.
+func StartPubsubOperation(ctx context.Context, spec config.TracingSpec, pubsubName, operation string) *ComponentOperation {
+	return startComponentOperation(ctx, spec, pubsubBuildingBlockType, pubsubName, operation, 0)
+}
+
+// StartBindingOperation starts an invocation of an output binding.
+func StartBindingOperation(ctx context.Context, spec config.TracingSpec, bindingName, operation string) *ComponentOperation {
+	return startComponentOperation(ctx, spec, bindingBuildingBlockType, bindingName, operation, 0)
+}
+
+func startComponentOperation(ctx context.Context, spec config.TracingSpec, componentType, componentName, operation string, keyCount int) *ComponentOperation {
+	op := &ComponentOperation{
+		span:          startComponentSpan(ctx, spec, componentType, componentName, operation, keyCount),
+		componentType: componentType,
+		componentName: componentName,
+		operation:     operation,
+		start:         time.Now(),
+		done:          drain.Components.Track(componentName),
+	}
+	DefaultComponentMonitoring.operationStarted(op.context(), componentType, componentName)
+	return op
+}
+
+// Span returns the span of the operation, or nil when tracing is disabled.
+func (o *ComponentOperation) Span() *trace.Span {
+	return o.span
+}
+
+// End ends the operation with its error if any.
+func (o *ComponentOperation) End(err error) {
+	o.done()
+	endComponentSpan(o.span, err)
+	elapsed := float64(time.Since(o.start)) / float64(time.Millisecond)
+	DefaultComponentMonitoring.operationCompleted(o.context(), o.componentType, o.componentName, o.operation, err, elapsed)
+}
+
+// context returns the context the metrics of the operation are recorded with, which holds its span for the exemplars.
+func (o *ComponentOperation) context() context.Context {
+	ctx := context.Background()
+	if o.span != nil {
+		ctx = trace.NewContext(ctx, o.span)
+	}
+	return ctx
+}
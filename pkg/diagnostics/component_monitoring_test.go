@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func componentsTestViews(t *testing.T) *componentMetrics {
+	t.Helper()
+
+	m := newComponentMetrics()
+	assert.NoError(t, m.Init("testAppId"))
+	t.Cleanup(func() {
+		view.Unregister(
+			view.Find("component/operation/latency"),
+			view.Find("component/operation/failed_total"),
+			view.Find("component/operation/in_flight"))
+	})
+	return m
+}
+
+func TestComponentOperation(t *testing.T) {
+	defaultMonitoring := DefaultComponentMonitoring
+	DefaultComponentMonitoring = componentsTestViews(t)
+	defer func() { DefaultComponentMonitoring = defaultMonitoring }()
+	spec := config.TracingSpec{SamplingRate: "0"}
+
+	op := StartStateOperation(context.Background(), spec, "statestore", "get", 1)
+	inFlight, err := view.RetrieveData("component/operation/in_flight")
+	assert.NoError(t, err)
+	assert.Len(t, inFlight, 1)
+	assert.Equal(t, float64(1), inFlight[0].Data.(*view.LastValueData).Value)
+	op.End(nil)
+
+	StartBindingOperation(context.Background(), spec, "kafka", "create").End(errors.New("broker failure"))
+	StartPubsubOperation(context.Background(), spec, "pubsub", "deliver").End(nil)
+
+	t.Run("latency by component and operation", func(t *testing.T) {
+		rows, err := view.RetrieveData("component/operation/latency")
+		assert.NoError(t, err)
+		assert.Len(t, rows, 3)
+		for _, row := range rows {
+			assert.Contains(t, row.Tags, tag.Tag{Key: appIDKey, Value: "testAppId"})
+			assert.Equal(t, int64(1), row.Data.(*view.DistributionData).Count)
+		}
+	})
+
+	t.Run("failures by reason", func(t *testing.T) {
+		rows, err := view.RetrieveData("component/operation/failed_total")
+		assert.NoError(t, err)
+		assert.Len(t, rows, 1)
+		assert.Contains(t, rows[0].Tags, tag.Tag{Key: componentKey, Value: "kafka"})
+		assert.Contains(t, rows[0].Tags, tag.Tag{Key: componentTypeKey, Value: "bindings"})
+		assert.Contains(t, rows[0].Tags, tag.Tag{Key: operationKey, Value: "create"})
+		assert.Contains(t, rows[0].Tags, tag.Tag{Key: failReasonKey, Value: "error"})
+	})
+
+	t.Run("no operation in flight once ended", func(t *testing.T) {
+		rows, err := view.RetrieveData("component/operation/in_flight")
+		assert.NoError(t, err)
+		for _, row := range rows {
+			assert.Equal(t, float64(0), row.Data.(*view.LastValueData).Value)
+		}
+	})
+}
+
+func TestComponentFailReason(t *testing.T) {
+	assert.Equal(t, "timeout", componentFailReason(errors.Wrap(context.DeadlineExceeded, "get")))
+	assert.Equal(t, "canceled", componentFailReason(context.Canceled))
+	assert.Equal(t, "etag_mismatch", componentFailReason(state.NewETagError(state.ETagMismatch, errors.New("conflict"))))
+	assert.Equal(t, "etag_invalid", componentFailReason(state.NewETagError(state.ETagInvalid, nil)))
+	assert.Equal(t, "error", componentFailReason(errors.New("failure")))
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Types of the runtime events.
+const (
+	RuntimeEventComponentInitFailed       = "ComponentInitFailed"
+	RuntimeEventPlacementDisconnected     = "PlacementDisconnected"
+	RuntimeEventCertificateRotated        = "CertificateRotated"
+	RuntimeEventCertificateRotationFailed = "CertificateRotationFailed"
+	RuntimeEventSubscriptionFailed        = "SubscriptionFailed"
+)
+
+const (
+	defaultEventLogSize = 256
+	// eventSubscriberBuffer is the number of events buffered for a subscriber. Events are dropped for the subscribers
+	// which don't keep up, so that recording an event never blocks the runtime.
+	eventSubscriberBuffer = 64
+)
+
+// RuntimeEvent is a significant event of the runtime kept in the event log.
+type RuntimeEvent struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Message string    `json:"message"`
+}
+
+// EventLog is a bounded in-memory log of the significant events of the runtime, kept for the analysis of incidents
+// regardless of the retention of the logs. The oldest events are dropped once the log is full.
+type EventLog struct {
+	lock        sync.RWMutex
+	events      []RuntimeEvent
+	next        int
+	full        bool
+	subscribers map[chan RuntimeEvent]struct{}
+}
+
+// DefaultEventLog holds the significant events of the runtime.
+var DefaultEventLog = newEventLog(defaultEventLogSize)
+
+func newEventLog(size int) *EventLog {
+	return &EventLog{
+		events:      make([]RuntimeEvent, size),
+		subscribers: map[chan RuntimeEvent]struct{}{},
+	}
+}
+
+// Record adds an event of the given type to the log and sends it to the subscribers.
+func (l *EventLog) Record(eventType string, format string, args ...interface{}) {
+	event := RuntimeEvent{
+		Time:    time.Now().UTC(),
+		Type:    eventType,
+		Message: fmt.Sprintf(format, args...),
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.events[l.next] = event
+	l.next = (l.next + 1) % len(l.events)
+	l.full = l.full || l.next == 0
+
+	for ch := range l.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Events returns the events of the log, oldest first.
+func (l *EventLog) Events() []RuntimeEvent {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	return l.eventsLocked()
+}
+
+func (l *EventLog) eventsLocked() []RuntimeEvent {
+	if !l.full {
+		events := make([]RuntimeEvent, l.next)
+		copy(events, l.events[:l.next])
+		return events
+	}
+
+	events := make([]RuntimeEvent, 0, len(l.events))
+	events = append(events, l.events[l.next:]...)
+	return append(events, l.events[:l.next]...)
+}
+
+// Subscribe returns the events of the log and a channel receiving the events recorded afterwards, until the
+// returned cancel function is called.
+func (l *EventLog) Subscribe() ([]RuntimeEvent, <-chan RuntimeEvent, func()) {
+	ch := make(chan RuntimeEvent, eventSubscriberBuffer)
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.subscribers[ch] = struct{}{}
+	cancel := func() {
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		delete(l.subscribers, ch)
+	}
+	return l.eventsLocked(), ch, cancel
+}
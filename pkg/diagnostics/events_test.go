@@ -0,0 +1,72 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventLog(t *testing.T) {
+	t.Run("events are returned oldest first", func(t *testing.T) {
+		l := newEventLog(3)
+		assert.Empty(t, l.Events())
+
+		l.Record(RuntimeEventComponentInitFailed, "component %s failed", "statestore")
+		l.Record(RuntimeEventPlacementDisconnected, "disconnected")
+		events := l.Events()
+		assert.Len(t, events, 2)
+		assert.Equal(t, RuntimeEventComponentInitFailed, events[0].Type)
+		assert.Equal(t, "component statestore failed", events[0].Message)
+		assert.False(t, events[0].Time.IsZero())
+		assert.Equal(t, RuntimeEventPlacementDisconnected, events[1].Type)
+	})
+
+	t.Run("oldest events are dropped once full", func(t *testing.T) {
+		l := newEventLog(3)
+		for i := 0; i < 5; i++ {
+			l.Record(RuntimeEventSubscriptionFailed, "%d", i)
+		}
+		messages := []string{}
+		for _, e := range l.Events() {
+			messages = append(messages, e.Message)
+		}
+		assert.Equal(t, []string{"2", "3", "4"}, messages)
+	})
+
+	t.Run("subscribers receive the recorded events", func(t *testing.T) {
+		l := newEventLog(3)
+		l.Record(RuntimeEventCertificateRotated, "before")
+
+		events, ch, cancel := l.Subscribe()
+		assert.Len(t, events, 1)
+		l.Record(RuntimeEventCertificateRotated, "after")
+		assert.Equal(t, "after", (<-ch).Message)
+
+		cancel()
+		l.Record(RuntimeEventCertificateRotated, "canceled")
+		assert.Len(t, ch, 0)
+	})
+
+	t.Run("slow subscribers don't block", func(t *testing.T) {
+		l := newEventLog(3)
+		_, ch, cancel := l.Subscribe()
+		defer cancel()
+		for i := 0; i < eventSubscriberBuffer+1; i++ {
+			l.Record(RuntimeEventSubscriptionFailed, "%d", i)
+		}
+		assert.Len(t, ch, eventSubscriberBuffer)
+	})
+}
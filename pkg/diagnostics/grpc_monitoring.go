@@ -97,7 +97,7 @@ func (g *grpcMetrics) Init(appID string) error {
 	g.appID = appID
 	g.enabled = true
 
-	return view.Register(
+	return registerViews(
 		diag_utils.NewMeasureView(g.serverReceivedBytes, []tag.Key{appIDKey, KeyServerMethod}, defaultSizeDistribution),
 		diag_utils.NewMeasureView(g.serverSentBytes, []tag.Key{appIDKey, KeyServerMethod}, defaultSizeDistribution),
 		diag_utils.NewMeasureView(g.serverLatency, []tag.Key{appIDKey, KeyServerMethod}, defaultLatencyDistribution),
@@ -115,7 +115,7 @@ func (g *grpcMetrics) IsEnabled() bool {
 
 func (g *grpcMetrics) ServerRequestReceived(ctx context.Context, method string, contentSize int64) time.Time {
 	if g.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			ctx,
 			diag_utils.WithTags(appIDKey, g.appID, KeyServerMethod, method),
 			g.serverReceivedBytes.M(contentSize))
@@ -126,16 +126,16 @@ func (g *grpcMetrics) ServerRequestReceived(ctx context.Context, method string,
 
 func (g *grpcMetrics) ServerRequestSent(ctx context.Context, method, status string, contentSize int64, start time.Time) {
 	if g.enabled {
-		elapsed := float64(time.Since(start) / time.Millisecond)
-		stats.RecordWithTags(
+		elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+		recordWithTags(
 			ctx,
 			diag_utils.WithTags(appIDKey, g.appID, KeyServerMethod, method, KeyServerStatus, status),
 			g.serverCompletedRpcs.M(1))
-		stats.RecordWithTags(
+		recordWithTags(
 			ctx,
 			diag_utils.WithTags(appIDKey, g.appID, KeyServerMethod, method),
 			g.serverSentBytes.M(contentSize))
-		stats.RecordWithTags(
+		recordWithTags(
 			ctx,
 			diag_utils.WithTags(appIDKey, g.appID, KeyServerMethod, method, KeyServerStatus, status),
 			g.serverLatency.M(elapsed))
@@ -144,7 +144,7 @@ func (g *grpcMetrics) ServerRequestSent(ctx context.Context, method, status stri
 
 func (g *grpcMetrics) ClientRequestSent(ctx context.Context, method string, contentSize int64) time.Time {
 	if g.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			ctx,
 			diag_utils.WithTags(appIDKey, g.appID, KeyClientMethod, method),
 			g.clientSentBytes.M(contentSize))
@@ -155,16 +155,16 @@ func (g *grpcMetrics) ClientRequestSent(ctx context.Context, method string, cont
 
 func (g *grpcMetrics) ClientRequestReceived(ctx context.Context, method, status string, contentSize int64, start time.Time) {
 	if g.enabled {
-		elapsed := float64(time.Since(start) / time.Millisecond)
-		stats.RecordWithTags(
+		elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+		recordWithTags(
 			ctx,
 			diag_utils.WithTags(appIDKey, g.appID, KeyClientMethod, method, KeyClientStatus, status),
 			g.clientCompletedRpcs.M(1))
-		stats.RecordWithTags(
+		recordWithTags(
 			ctx,
 			diag_utils.WithTags(appIDKey, g.appID, KeyClientMethod, method, KeyClientStatus, status),
 			g.clientRoundtripLatency.M(elapsed))
-		stats.RecordWithTags(
+		recordWithTags(
 			ctx, diag_utils.WithTags(appIDKey, g.appID),
 			g.clientReceivedBytes.M(contentSize))
 	}
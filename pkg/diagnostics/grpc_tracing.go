@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"github.com/pkg/errors"
@@ -28,7 +29,6 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/dapr/dapr/pkg/config"
-	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
 	internalv1pb "github.com/dapr/dapr/pkg/proto/internals/v1"
 	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
 )
@@ -36,15 +36,18 @@ import (
 const (
 	grpcTraceContextKey = "grpc-trace-bin"
 	GRPCProxyAppIDKey   = "dapr-app-id"
+
+	invokeServiceMethod = "/dapr.proto.runtime.v1.Dapr/InvokeService"
+	publishEventMethod  = "/dapr.proto.runtime.v1.Dapr/PublishEvent"
 )
 
 // GRPCTraceUnaryServerInterceptor sets the trace context or starts the trace client span based on request.
 func GRPCTraceUnaryServerInterceptor(appID string, spec config.TracingSpec) grpc.UnaryServerInterceptor {
-	sampler := diag_utils.TraceSampler(spec.SamplingRate)
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		var (
 			span             *trace.Span
-			spanKind         trace.StartOption
+			spanKind         int
+			targetID         string
 			prefixedMetadata map[string]string
 			reqSpanAttr      map[string]string
 		)
@@ -53,13 +56,19 @@ func GRPCTraceUnaryServerInterceptor(appID string, spec config.TracingSpec) grpc
 		// so that it needs to handle separately.
 		if isInternalCalls(info.FullMethod) {
 			// For dapr.proto.internals package, this generates ServerSpan.
-			spanKind = trace.WithSpanKind(trace.SpanKindServer)
+			spanKind = trace.SpanKindServer
+			targetID = appID
 		} else {
 			// For dapr.proto.runtime package, this generates ClientSpan.
-			spanKind = trace.WithSpanKind(trace.SpanKindClient)
+			spanKind = trace.SpanKindClient
+			if r, ok := req.(*runtimev1pb.InvokeServiceRequest); ok {
+				targetID = r.GetId()
+			}
 		}
 
-		ctx, span = trace.StartSpanWithRemoteParent(ctx, info.FullMethod, sc, sampler, spanKind)
+		sampler := requestSampler(spec, info.FullMethod, targetID)
+		start := time.Now()
+		ctx, span = trace.StartSpanWithRemoteParent(ctx, info.FullMethod, sc, sampler, trace.WithSpanKind(spanKind))
 
 		isSampled := span.SpanContext().IsSampled()
 
@@ -71,6 +80,14 @@ func GRPCTraceUnaryServerInterceptor(appID string, spec config.TracingSpec) grpc
 
 		resp, err := handler(ctx, req)
 
+		if !isSampled && err != nil && sampleFailedRequest(info.FullMethod, targetID) {
+			span.End()
+			ctx, span = startFailedRequestSpan(ctx, info.FullMethod, sc, span, spanKind, start)
+			isSampled = true
+			prefixedMetadata = userDefinedMetadata(ctx)
+			reqSpanAttr = spanAttributesMapFromGRPC(appID, req, info.FullMethod)
+		}
+
 		if isSampled {
 			// Populates dapr- prefixed header first
 			for key, value := range reqSpanAttr {
@@ -85,11 +102,17 @@ func GRPCTraceUnaryServerInterceptor(appID string, spec config.TracingSpec) grpc
 		}
 
 		// Add grpc-trace-bin header for all non-invocation api's
-		if info.FullMethod != "/dapr.proto.runtime.v1.Dapr/InvokeService" {
+		if info.FullMethod != invokeServiceMethod {
 			traceContextBinary := propagation.Binary(span.SpanContext())
 			grpc.SetHeader(ctx, metadata.Pairs(grpcTraceContextKey, string(traceContextBinary)))
 		}
 
+		// The apps correlate their logs with the invocations and the events published through the W3C trace context
+		// of the sidecar.
+		if info.FullMethod == invokeServiceMethod || info.FullMethod == publishEventMethod {
+			grpc.SetHeader(ctx, spanContextToW3CGRPCMetadata(span.SpanContext()))
+		}
+
 		UpdateSpanStatusFromGRPCError(span, err)
 		span.End()
 
@@ -97,6 +120,15 @@ func GRPCTraceUnaryServerInterceptor(appID string, spec config.TracingSpec) grpc
 	}
 }
 
+// spanContextToW3CGRPCMetadata returns the traceparent and tracestate metadata of a span context.
+func spanContextToW3CGRPCMetadata(sc trace.SpanContext) metadata.MD {
+	md := metadata.Pairs(traceparentHeader, SpanContextToW3CString(sc))
+	if ts := TraceStateToW3CString(sc); ts != "" {
+		md.Set(tracestateHeader, ts)
+	}
+	return md
+}
+
 // GRPCTraceStreamServerInterceptor sets the trace context or starts the trace client span based on request.
 func GRPCTraceStreamServerInterceptor(appID string, spec config.TracingSpec) grpc.StreamServerInterceptor {
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
@@ -114,20 +146,26 @@ func GRPCTraceStreamServerInterceptor(appID string, spec config.TracingSpec) grp
 		targetID := vals[0]
 		wrapped := grpc_middleware.WrapServerStream(ss)
 		sc, _ := SpanContextFromIncomingGRPCMetadata(ctx)
-		sampler := diag_utils.TraceSampler(spec.SamplingRate)
+		sampler := requestSampler(spec, spanName, targetID)
 
-		var spanKind trace.StartOption
+		var spanKind int
 
 		if appID == targetID {
-			spanKind = trace.WithSpanKind(trace.SpanKindServer)
+			spanKind = trace.SpanKindServer
 		} else {
-			spanKind = trace.WithSpanKind(trace.SpanKindClient)
+			spanKind = trace.SpanKindClient
 		}
 
-		ctx, span = trace.StartSpanWithRemoteParent(ctx, spanName, sc, sampler, spanKind)
+		start := time.Now()
+		ctx, span = trace.StartSpanWithRemoteParent(ctx, spanName, sc, sampler, trace.WithSpanKind(spanKind))
 		wrapped.WrappedContext = ctx
 		err := handler(srv, wrapped)
 
+		if !span.SpanContext().IsSampled() && err != nil && sampleFailedRequest(spanName, targetID) {
+			span.End()
+			ctx, span = startFailedRequestSpan(ctx, spanName, sc, span, spanKind, start)
+		}
+
 		addSpanMetadataAndUpdateStatus(ctx, span, info.FullMethod, appID, nil, true)
 
 		UpdateSpanStatusFromGRPCError(span, err)
@@ -185,6 +185,52 @@ func TestGRPCTraceUnaryServerInterceptor(t *testing.T) {
 		assert.NotEmpty(t, fmt.Sprintf("%x", sc.TraceID[:]))
 		assert.NotEmpty(t, fmt.Sprintf("%x", sc.SpanID[:]))
 	})
+
+	t.Run("PublishEvent response has the W3C trace context", func(t *testing.T) {
+		fakeInfo := &grpc.UnaryServerInfo{
+			FullMethod: "/dapr.proto.runtime.v1.Dapr/PublishEvent",
+		}
+		fakeReq := &runtimev1pb.PublishEventRequest{
+			PubsubName: "pubsub",
+			Topic:      "topic",
+		}
+		stream := &fakeServerTransportStream{}
+		streamCtx := grpc.NewContextWithServerTransportStream(metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs(traceparentHeader, testTraceParent)), stream)
+
+		var span *trace.Span
+		assertHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			span = diag_utils.SpanFromContext(ctx)
+			return nil, nil
+		}
+
+		interceptor(streamCtx, fakeReq, fakeInfo, assertHandler)
+
+		sc := span.SpanContext()
+		assert.Equal(t, []string{SpanContextToW3CString(sc)}, stream.header.Get(traceparentHeader))
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", fmt.Sprintf("%x", sc.TraceID[:]))
+	})
+}
+
+type fakeServerTransportStream struct {
+	header metadata.MD
+}
+
+func (s *fakeServerTransportStream) Method() string {
+	return ""
+}
+
+func (s *fakeServerTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *fakeServerTransportStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *fakeServerTransportStream) SetTrailer(md metadata.MD) error {
+	return nil
 }
 
 func TestSpanContextSerialization(t *testing.T) {
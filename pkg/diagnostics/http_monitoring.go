@@ -108,11 +108,11 @@ func (h *httpMetrics) IsEnabled() bool {
 
 func (h *httpMetrics) ServerRequestReceived(ctx context.Context, method, path string, contentSize int64) {
 	if h.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			ctx,
 			diag_utils.WithTags(appIDKey, h.appID, httpPathKey, path, httpMethodKey, method),
 			h.serverRequestCount.M(1))
-		stats.RecordWithTags(
+		recordWithTags(
 			ctx, diag_utils.WithTags(appIDKey, h.appID),
 			h.serverRequestBytes.M(contentSize))
 	}
@@ -120,15 +120,15 @@ func (h *httpMetrics) ServerRequestReceived(ctx context.Context, method, path st
 
 func (h *httpMetrics) ServerRequestCompleted(ctx context.Context, method, path, status string, contentSize int64, elapsed float64) {
 	if h.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			ctx,
 			diag_utils.WithTags(appIDKey, h.appID, httpPathKey, path, httpMethodKey, method, httpStatusCodeKey, status),
 			h.serverResponseCount.M(1))
-		stats.RecordWithTags(
+		recordWithTags(
 			ctx,
 			diag_utils.WithTags(appIDKey, h.appID, httpPathKey, path, httpMethodKey, method, httpStatusCodeKey, status),
 			h.serverLatency.M(elapsed))
-		stats.RecordWithTags(
+		recordWithTags(
 			ctx, diag_utils.WithTags(appIDKey, h.appID),
 			h.serverResponseBytes.M(contentSize))
 	}
@@ -136,7 +136,7 @@ func (h *httpMetrics) ServerRequestCompleted(ctx context.Context, method, path,
 
 func (h *httpMetrics) ClientRequestStarted(ctx context.Context, method, path string, contentSize int64) {
 	if h.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			ctx,
 			diag_utils.WithTags(appIDKey, h.appID, httpPathKey, h.convertPathToMetricLabel(path), httpMethodKey, method),
 			h.clientSentBytes.M(contentSize))
@@ -145,15 +145,15 @@ func (h *httpMetrics) ClientRequestStarted(ctx context.Context, method, path str
 
 func (h *httpMetrics) ClientRequestCompleted(ctx context.Context, method, path, status string, contentSize int64, elapsed float64) {
 	if h.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			ctx,
 			diag_utils.WithTags(appIDKey, h.appID, httpPathKey, h.convertPathToMetricLabel(path), httpMethodKey, method, httpStatusCodeKey, status),
 			h.clientCompletedCount.M(1))
-		stats.RecordWithTags(
+		recordWithTags(
 			ctx,
 			diag_utils.WithTags(appIDKey, h.appID, httpPathKey, h.convertPathToMetricLabel(path), httpMethodKey, method, httpStatusCodeKey, status),
 			h.clientRoundtripLatency.M(elapsed))
-		stats.RecordWithTags(
+		recordWithTags(
 			ctx, diag_utils.WithTags(appIDKey, h.appID),
 			h.clientReceivedBytes.M(contentSize))
 	}
@@ -164,7 +164,7 @@ func (h *httpMetrics) Init(appID string) error {
 	h.enabled = true
 
 	tags := []tag.Key{appIDKey}
-	return view.Register(
+	return registerViews(
 		diag_utils.NewMeasureView(h.serverRequestCount, []tag.Key{appIDKey, httpPathKey, httpMethodKey}, view.Count()),
 		diag_utils.NewMeasureView(h.serverRequestBytes, tags, defaultSizeDistribution),
 		diag_utils.NewMeasureView(h.serverResponseBytes, tags, defaultSizeDistribution),
@@ -195,8 +195,16 @@ func (h *httpMetrics) FastHTTPMiddleware(next fasthttp.RequestHandler) fasthttp.
 		next(ctx)
 
 		status := strconv.Itoa(ctx.Response.StatusCode())
-		elapsed := float64(time.Since(start) / time.Millisecond)
-		respSize := int64(len(ctx.Response.Body()))
+		elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+		// The body of a streamed response isn't read, which would buffer it whole. Its size is its content length, if known.
+		var respSize int64
+		if ctx.Response.IsBodyStream() {
+			if contentLength := ctx.Response.Header.ContentLength(); contentLength > 0 {
+				respSize = int64(contentLength)
+			}
+		} else {
+			respSize = int64(len(ctx.Response.Body()))
+		}
 		h.ServerRequestCompleted(ctx, method, path, status, respSize, elapsed)
 	}
 }
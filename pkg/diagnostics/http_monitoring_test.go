@@ -1,6 +1,7 @@
 package diagnostics
 
 import (
+	"bufio"
 	"net"
 	"testing"
 	"time"
@@ -59,6 +60,25 @@ func TestFastHTTPMiddleware(t *testing.T) {
 	assert.True(t, (rows[0].Data).(*view.DistributionData).Min >= 100.0)
 }
 
+func TestFastHTTPMiddlewareStreamedResponse(t *testing.T) {
+	testRequestCtx := fakeFastHTTPRequestCtx("fake_requestDaprBody")
+
+	fakeHandler := func(ctx *fasthttp.RequestCtx) {
+		ctx.Response.SetBodyStreamWriter(func(w *bufio.Writer) {
+			w.WriteString("fake_responseDaprBody")
+		})
+	}
+
+	testHTTP := newHTTPMetrics()
+	testHTTP.Init("fakeID")
+
+	handler := testHTTP.FastHTTPMiddleware(fakeHandler)
+	handler(testRequestCtx)
+
+	// the streamed body isn't buffered by the middleware.
+	assert.True(t, testRequestCtx.Response.IsBodyStream())
+}
+
 func TestFastHTTPMiddlewareWhenMetricsDisabled(t *testing.T) {
 	requestBody := "fake_requestDaprBody"
 	responseBody := "fake_responseDaprBody"
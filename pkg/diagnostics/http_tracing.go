@@ -20,6 +20,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/valyala/fasthttp"
 	"go.opencensus.io/trace"
@@ -52,9 +53,19 @@ func HTTPTraceMiddleware(next fasthttp.RequestHandler, appID string, spec config
 			return
 		}
 
-		ctx, span := startTracingClientSpanFromHTTPContext(ctx, path, spec)
+		targetID := invokedAppIDFromHTTPContext(ctx, path)
+		samplePath := samplingPath(path, targetID)
+		start := time.Now()
+		ctx, span := startTracingClientSpanFromHTTPContext(ctx, path, samplePath, targetID, spec)
 		next(ctx)
 
+		if !span.SpanContext().IsSampled() && ctx.Response.StatusCode() >= fasthttp.StatusBadRequest && sampleFailedRequest(samplePath, targetID) {
+			span.End()
+			sc, _ := SpanContextFromRequest(&ctx.Request)
+			_, span = startFailedRequestSpan(ctx, path, sc, span, trace.SpanKindClient, start)
+			diag_utils.SpanToFastHTTPContext(ctx, span)
+		}
+
 		// Add span attributes only if it is sampled, which reduced the perf impact.
 		if span.SpanContext().TraceOptions.IsSampled() {
 			AddAttributesToSpan(span, userDefinedHTTPHeaders(ctx))
@@ -67,8 +78,13 @@ func HTTPTraceMiddleware(next fasthttp.RequestHandler, appID string, spec config
 			}
 		}
 
-		// Check if response has traceparent header and add if absent
-		if ctx.Response.Header.Peek(traceparentHeader) == nil {
+		if targetID != "" || isPublishRequest(path) {
+			// The apps correlate their logs with the invocations and the events published through the W3C trace
+			// context of the sidecar, which replaces the trace context of the response of the invoked app.
+			ctx.Response.Header.Del(tracestateHeader)
+			SpanContextToHTTPHeaders(span.SpanContext(), ctx.Response.Header.Set)
+		} else if ctx.Response.Header.Peek(traceparentHeader) == nil {
+			// Check if response has traceparent header and add if absent
 			span = diag_utils.SpanFromContext(ctx)
 			SpanContextToHTTPHeaders(span.SpanContext(), ctx.Response.Header.Set)
 		}
@@ -93,9 +109,28 @@ func userDefinedHTTPHeaders(reqCtx *fasthttp.RequestCtx) map[string]string {
 	return m
 }
 
-func startTracingClientSpanFromHTTPContext(ctx *fasthttp.RequestCtx, spanName string, spec config.TracingSpec) (*fasthttp.RequestCtx, *trace.Span) {
+// invokedAppIDFromHTTPContext returns the id of the app invoked by the request, from its path or its dapr-app-id
+// header, or an empty string.
+func invokedAppIDFromHTTPContext(ctx *fasthttp.RequestCtx, path string) string {
+	// example : path /v1.0/invoke/myapp/method/mymethod
+	if tokens := strings.SplitN(path, "/", 5); len(tokens) >= 4 && tokens[2] == "invoke" {
+		return tokens[3]
+	}
+	return string(ctx.Request.Header.Peek(GRPCProxyAppIDKey))
+}
+
+// samplingPath returns the path the sampling rules match a request with. The invocations addressed by the dapr-app-id
+// header, whose path is the path of the method, are matched as invocations through the invoke API.
+func samplingPath(path, appID string) string {
+	if appID == "" || strings.HasPrefix(strings.TrimPrefix(path, "/"), "v1.0") {
+		return path
+	}
+	return "/v1.0/invoke/" + appID + "/method" + path
+}
+
+func startTracingClientSpanFromHTTPContext(ctx *fasthttp.RequestCtx, spanName, samplePath, appID string, spec config.TracingSpec) (*fasthttp.RequestCtx, *trace.Span) {
 	sc, _ := SpanContextFromRequest(&ctx.Request)
-	probSamplerOption := diag_utils.TraceSampler(spec.SamplingRate)
+	probSamplerOption := requestSampler(spec, samplePath, appID)
 	kindOption := trace.WithSpanKind(trace.SpanKindClient)
 
 	_, span := trace.StartSpanWithRemoteParent(ctx, spanName, sc, kindOption, probSamplerOption)
@@ -120,6 +155,12 @@ func isHealthzRequest(name string) bool {
 	return strings.Contains(name, "/healthz")
 }
 
+func isPublishRequest(path string) bool {
+	// example : path /v1.0/publish/pubsub/topic
+	tokens := strings.SplitN(path, "/", 4)
+	return len(tokens) >= 3 && tokens[2] == "publish"
+}
+
 // UpdateSpanStatusFromHTTPStatus updates trace span status based on response code.
 func UpdateSpanStatusFromHTTPStatus(span *trace.Span, code int) {
 	if span != nil {
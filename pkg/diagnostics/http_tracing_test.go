@@ -383,6 +383,24 @@ func TestHTTPTraceMiddleware(t *testing.T) {
 		assert.NotEqual(t, testRequestCtx.Response.Header.Peek(traceparentHeader), []byte(SpanContextToW3CString(sc)))
 	})
 
+	t.Run("traceparent given in invoke and publish responses is replaced", func(t *testing.T) {
+		for _, path := range []string{"/v1.0/invoke/callee/method/method1", "/v1.0/publish/pubsub/topic"} {
+			testRequestCtx := newTraceFastHTTPRequestCtx(
+				requestBody, path,
+				map[string]string{},
+				map[string]string{
+					traceparentHeader: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+					tracestateHeader:  "xyz=t61pCWkhMzZ",
+				},
+			)
+			handler(testRequestCtx)
+			span := diag_utils.SpanFromContext(testRequestCtx)
+			sc := span.SpanContext()
+			assert.Equal(t, SpanContextToW3CString(sc), string(testRequestCtx.Response.Header.Peek(traceparentHeader)), path)
+			assert.Nil(t, testRequestCtx.Response.Header.Peek(tracestateHeader), path)
+		}
+	})
+
 	t.Run("path is /v1.0/invoke/*", func(t *testing.T) {
 		testRequestCtx := newTraceFastHTTPRequestCtx(
 			requestBody, "/v1.0/invoke/callee/method/method1",
@@ -404,6 +422,37 @@ func TestHTTPTraceMiddleware(t *testing.T) {
 	})
 }
 
+func TestHTTPTraceMiddlewareSamplingRules(t *testing.T) {
+	defer SetTracingSamplingRules(nil)
+	assert.NoError(t, SetTracingSamplingRules([]config.TracingSamplingRule{
+		{Errors: true, SamplingRate: "1"},
+		{Path: "^/v1.0/invoke/", AppID: "orders", SamplingRate: "1"},
+	}))
+
+	fakeHandler := func(ctx *fasthttp.RequestCtx) {
+		if strings.HasSuffix(string(ctx.Path()), "/fail") {
+			ctx.Response.SetStatusCode(fasthttp.StatusInternalServerError)
+		}
+	}
+	handler := HTTPTraceMiddleware(fakeHandler, "fakeAppID", config.TracingSpec{SamplingRate: "0"})
+	sampled := func(path string, headers map[string]string) bool {
+		testRequestCtx := newTraceFastHTTPRequestCtx("", path, headers, map[string]string{})
+		handler(testRequestCtx)
+		return diag_utils.SpanFromContext(testRequestCtx).SpanContext().IsSampled()
+	}
+
+	t.Run("requests to the app of a rule are sampled", func(t *testing.T) {
+		assert.True(t, sampled("/v1.0/invoke/orders/method/create", nil))
+		assert.True(t, sampled("/create", map[string]string{"dapr-app-id": "orders"}))
+		assert.False(t, sampled("/v1.0/invoke/payments/method/create", nil))
+	})
+
+	t.Run("failed requests are sampled", func(t *testing.T) {
+		assert.False(t, sampled("/v1.0/state/statestore", nil))
+		assert.True(t, sampled("/v1.0/state/statestore/fail", nil))
+	})
+}
+
 func newTraceFastHTTPRequestCtx(expectedBody, expectedRequestURI string, expectedRequestHeader map[string]string, expectedResponseHeader map[string]string) *fasthttp.RequestCtx {
 	expectedMethod := fasthttp.MethodPost
 	expectedTransferEncoding := "encoding"
@@ -18,6 +18,8 @@ import (
 
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
+
+	"github.com/dapr/dapr/pkg/config"
 )
 
 // appIDKey is a tag key for App ID.
@@ -33,10 +35,20 @@ var (
 	DefaultGRPCMonitoring = newGRPCMetrics()
 	// DefaultHTTPMonitoring holds default HTTP monitoring handlers and middlewares.
 	DefaultHTTPMonitoring = newHTTPMetrics()
+	// DefaultComponentMonitoring holds the metrics of the operations on components.
+	DefaultComponentMonitoring = newComponentMetrics()
 )
 
-// InitMetrics initializes metrics.
-func InitMetrics(appID string) error {
+// InitMetrics initializes metrics with the rules, histograms and exemplars of spec.
+func InitMetrics(appID string, spec config.MetricSpec) error {
+	if err := setMetricsRules(spec.Rules); err != nil {
+		return err
+	}
+	if err := setHistograms(spec.Histograms); err != nil {
+		return err
+	}
+	exemplarsEnabled = spec.Exemplars
+
 	if err := DefaultMonitoring.Init(appID); err != nil {
 		return err
 	}
@@ -49,6 +61,10 @@ func InitMetrics(appID string) error {
 		return err
 	}
 
+	if err := DefaultComponentMonitoring.Init(appID); err != nil {
+		return err
+	}
+
 	// Set reporting period of views
 	view.SetReportingPeriod(DefaultReportingPeriod)
 
@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/metric/metricdata"
+
+	"github.com/dapr/dapr/pkg/config"
+	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
+)
+
+var (
+	// histograms are the bucket boundaries of the histograms by group of metrics. Like the metrics rules, they are
+	// set before the views are registered.
+	histograms map[string][]float64
+	// exemplarsEnabled attaches the sampled spans to the measurements.
+	exemplarsEnabled bool
+)
+
+// setHistograms validates the bucket boundaries of the histograms of the views registered afterwards.
+func setHistograms(specs []config.MetricHistogram) error {
+	groups := make(map[string][]float64, len(specs))
+	for _, h := range specs {
+		if h.Group == "" {
+			return errors.New("histogram has no group")
+		}
+		if len(h.Buckets) == 0 {
+			return errors.Errorf("histogram of group %s has no buckets", h.Group)
+		}
+		for i, b := range h.Buckets {
+			if b <= 0 || (i > 0 && b <= h.Buckets[i-1]) {
+				return errors.Errorf("buckets of histogram of group %s must be positive and increasing", h.Group)
+			}
+		}
+		groups[h.Group] = h.Buckets
+	}
+	histograms = groups
+	return nil
+}
+
+// histogramBuckets returns the bucket boundaries of the longest group matching the metric, or nil.
+func histogramBuckets(name string) []float64 {
+	var group string
+	var buckets []float64
+	for g, b := range histograms {
+		if strings.HasPrefix(name, g) && len(g) > len(group) {
+			group, buckets = g, b
+		}
+	}
+	return buckets
+}
+
+// exemplarAttachments returns the attachments of the span sampled in ctx, which are kept as exemplars by the
+// histograms.
+func exemplarAttachments(ctx context.Context) metricdata.Attachments {
+	if !exemplarsEnabled {
+		return nil
+	}
+	span := diag_utils.SpanFromContext(ctx)
+	if span == nil {
+		return nil
+	}
+	sc := span.SpanContext()
+	if !sc.IsSampled() {
+		return nil
+	}
+	return metricdata.Attachments{metricdata.AttachmentKeySpanContext: sc}
+}
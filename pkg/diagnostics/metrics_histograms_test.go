@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/trace"
+
+	"github.com/dapr/dapr/pkg/config"
+	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
+)
+
+func TestSetHistograms(t *testing.T) {
+	defer setHistograms(nil)
+
+	for _, h := range []config.MetricHistogram{
+		{Buckets: []float64{1}},
+		{Group: "http"},
+		{Group: "http", Buckets: []float64{0, 1}},
+		{Group: "http", Buckets: []float64{1, 1}},
+	} {
+		assert.Error(t, setHistograms([]config.MetricHistogram{h}), h)
+	}
+
+	assert.NoError(t, setHistograms([]config.MetricHistogram{
+		{Group: "http", Buckets: []float64{1, 10}},
+		{Group: "http/server", Buckets: []float64{0.1, 0.5, 1}},
+	}))
+	assert.Equal(t, []float64{0.1, 0.5, 1}, histogramBuckets("http/server/latency"))
+	assert.Equal(t, []float64{1, 10}, histogramBuckets("http/client/roundtrip_latency"))
+	assert.Nil(t, histogramBuckets("grpc.io/server/server_latency"))
+}
+
+func TestHistogramsAndExemplars(t *testing.T) {
+	defer func() {
+		setHistograms(nil)
+		exemplarsEnabled = false
+	}()
+
+	assert.NoError(t, setHistograms([]config.MetricHistogram{{Group: "test/histograms", Buckets: []float64{0.1, 0.5, 1}}}))
+	exemplarsEnabled = true
+
+	latency := stats.Float64("test/histograms/latency", "", stats.UnitMilliseconds)
+	count := stats.Int64("test/histograms/count", "", stats.UnitDimensionless)
+	assert.NoError(t, registerViews(
+		diag_utils.NewMeasureView(latency, nil, defaultLatencyDistribution),
+		diag_utils.NewMeasureView(count, nil, view.Count()),
+	))
+	defer view.Unregister(view.Find("test/histograms/latency"), view.Find("test/histograms/count"))
+
+	t.Run("histograms use the buckets of their group", func(t *testing.T) {
+		assert.Equal(t, []float64{0.1, 0.5, 1}, view.Find("test/histograms/latency").Aggregation.Buckets)
+		assert.Equal(t, view.AggTypeCount, view.Find("test/histograms/count").Aggregation.Type)
+	})
+
+	t.Run("sampled spans are attached as exemplars", func(t *testing.T) {
+		ctx, span := trace.StartSpan(context.Background(), "test", trace.WithSampler(trace.AlwaysSample()))
+		defer span.End()
+		assert.NoError(t, recordWithTags(ctx, nil, latency.M(0.3)))
+		assert.NoError(t, recordWithTags(context.Background(), nil, latency.M(0.05)))
+
+		rows, err := view.RetrieveData("test/histograms/latency")
+		assert.NoError(t, err)
+		assert.Len(t, rows, 1)
+		data := rows[0].Data.(*view.DistributionData)
+		assert.Equal(t, []int64{1, 1, 0, 0}, data.CountPerBucket)
+		assert.Nil(t, data.ExemplarsPerBucket[0])
+		assert.Equal(t, span.SpanContext(), data.ExemplarsPerBucket[1].Attachments[metricdata.AttachmentKeySpanContext])
+	})
+}
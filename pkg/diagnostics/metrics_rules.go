@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+// metricRule is the compiled rule of a metric.
+type metricRule struct {
+	drop       bool
+	dropLabels map[string]bool
+	buckets    map[tag.Key][]labelBucket
+}
+
+type labelBucket struct {
+	value string
+	regex *regexp.Regexp
+}
+
+// metricsRules are the rules of the metrics by name. They are set before the views are registered and are
+// read-only afterwards.
+var metricsRules map[string]*metricRule
+
+// setMetricsRules compiles the rules applied to the views registered and the measurements recorded afterwards.
+func setMetricsRules(rules []config.MetricsRule) error {
+	compiled := make(map[string]*metricRule, len(rules))
+	for _, r := range rules {
+		if r.Name == "" {
+			return errors.New("metrics rule has no name")
+		}
+		rule := &metricRule{
+			drop:       r.Drop,
+			dropLabels: map[string]bool{},
+			buckets:    map[tag.Key][]labelBucket{},
+		}
+		for _, l := range r.Labels {
+			if l.Drop {
+				rule.dropLabels[l.Name] = true
+				continue
+			}
+			key, err := tag.NewKey(l.Name)
+			if err != nil {
+				return errors.Wrapf(err, "invalid label %s of metric %s", l.Name, r.Name)
+			}
+			for _, b := range l.Buckets {
+				regex, err := regexp.Compile(b.Regex)
+				if err != nil {
+					return errors.Wrapf(err, "invalid regex of label %s of metric %s", l.Name, r.Name)
+				}
+				rule.buckets[key] = append(rule.buckets[key], labelBucket{value: b.Value, regex: regex})
+			}
+		}
+		compiled[r.Name] = rule
+	}
+	metricsRules = compiled
+	return nil
+}
+
+// registerViews registers the views which are not dropped by the metrics rules, without their dropped labels and
+// with the buckets of their histogram group.
+func registerViews(views ...*view.View) error {
+	registered := make([]*view.View, 0, len(views))
+	for _, v := range views {
+		if v.Aggregation != nil && v.Aggregation.Type == view.AggTypeDistribution {
+			if buckets := histogramBuckets(v.Name); buckets != nil {
+				v.Aggregation = view.Distribution(buckets...)
+			}
+		}
+
+		rule := metricsRules[v.Name]
+		if rule == nil {
+			registered = append(registered, v)
+			continue
+		}
+		if rule.drop {
+			continue
+		}
+
+		keys := make([]tag.Key, 0, len(v.TagKeys))
+		for _, k := range v.TagKeys {
+			if !rule.dropLabels[k.Name()] {
+				keys = append(keys, k)
+			}
+		}
+		v.TagKeys = keys
+		registered = append(registered, v)
+	}
+	return view.Register(registered...)
+}
+
+// recordWithTags records the measurement with the tags, after the values of the labels of its metric are replaced
+// by their buckets. The sampled span of ctx is attached as exemplar if enabled.
+func recordWithTags(ctx context.Context, mutators []tag.Mutator, measurement stats.Measurement) error {
+	options := []stats.Options{stats.WithMeasurements(measurement)}
+	// Read before ctx is wrapped, which hides the span of fasthttp contexts.
+	if attachments := exemplarAttachments(ctx); attachments != nil {
+		options = append(options, stats.WithAttachments(attachments))
+	}
+
+	rule := metricsRules[measurement.Measure().Name()]
+	if rule == nil || len(rule.buckets) == 0 {
+		return stats.RecordWithOptions(ctx, append(options, stats.WithTags(mutators...))...)
+	}
+
+	ctx, err := tag.New(ctx, mutators...)
+	if err != nil {
+		return err
+	}
+	tags := tag.FromContext(ctx)
+	var bucketed []tag.Mutator
+	for key, buckets := range rule.buckets {
+		value, ok := tags.Value(key)
+		if !ok {
+			continue
+		}
+		for _, b := range buckets {
+			if b.regex.MatchString(value) {
+				bucketed = append(bucketed, tag.Upsert(key, b.value))
+				break
+			}
+		}
+	}
+	return stats.RecordWithOptions(ctx, append(options, stats.WithTags(bucketed...))...)
+}
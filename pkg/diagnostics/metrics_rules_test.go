@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"github.com/dapr/dapr/pkg/config"
+	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
+)
+
+func TestSetMetricsRules(t *testing.T) {
+	defer setMetricsRules(nil)
+
+	assert.Error(t, setMetricsRules([]config.MetricsRule{{Labels: []config.MetricLabel{{Name: "path", Drop: true}}}}))
+	assert.Error(t, setMetricsRules([]config.MetricsRule{{
+		Name:   "test/rules/invalid",
+		Labels: []config.MetricLabel{{Name: "path", Buckets: []config.MetricLabelBucket{{Value: "/orders", Regex: "/orders/("}}}},
+	}}))
+	assert.NoError(t, setMetricsRules([]config.MetricsRule{{
+		Name:   "test/rules/valid",
+		Labels: []config.MetricLabel{{Name: "path", Buckets: []config.MetricLabelBucket{{Value: "/orders/{id}", Regex: "^/orders/"}}}},
+	}}))
+	assert.Len(t, metricsRules["test/rules/valid"].buckets, 1)
+}
+
+func TestMetricsRules(t *testing.T) {
+	defer setMetricsRules(nil)
+
+	methodKey := tag.MustNewKey("method")
+	pathKey := tag.MustNewKey("path")
+	dropped := stats.Int64("test/rules/dropped", "", stats.UnitDimensionless)
+	withoutPath := stats.Int64("test/rules/without_path", "", stats.UnitDimensionless)
+	bucketed := stats.Int64("test/rules/bucketed", "", stats.UnitDimensionless)
+
+	assert.NoError(t, setMetricsRules([]config.MetricsRule{
+		{Name: "test/rules/dropped", Drop: true},
+		{Name: "test/rules/without_path", Labels: []config.MetricLabel{{Name: "path", Drop: true}}},
+		{Name: "test/rules/bucketed", Labels: []config.MetricLabel{{Name: "path", Buckets: []config.MetricLabelBucket{
+			{Value: "/v1.0/invoke/orders/{id}", Regex: "^/v1.0/invoke/orders/[0-9]+$"},
+			{Value: "/v1.0/invoke/{other}", Regex: "^/v1.0/invoke/"},
+		}}}},
+	}))
+
+	keys := []tag.Key{methodKey, pathKey}
+	assert.NoError(t, registerViews(
+		diag_utils.NewMeasureView(dropped, keys, view.Count()),
+		diag_utils.NewMeasureView(withoutPath, keys, view.Count()),
+		diag_utils.NewMeasureView(bucketed, keys, view.Count()),
+	))
+	defer view.Unregister(view.Find("test/rules/without_path"), view.Find("test/rules/bucketed"))
+
+	ctx := context.Background()
+	for _, path := range []string{"/v1.0/invoke/orders/1", "/v1.0/invoke/orders/2", "/v1.0/invoke/payments/1", "/v1.0/state/store"} {
+		for _, m := range []*stats.Int64Measure{dropped, withoutPath, bucketed} {
+			assert.NoError(t, recordWithTags(ctx, diag_utils.WithTags(methodKey, "GET", pathKey, path), m.M(1)))
+		}
+	}
+
+	t.Run("dropped metrics are not registered", func(t *testing.T) {
+		assert.Nil(t, view.Find("test/rules/dropped"))
+	})
+
+	t.Run("dropped labels are removed", func(t *testing.T) {
+		rows, err := view.RetrieveData("test/rules/without_path")
+		assert.NoError(t, err)
+		assert.Len(t, rows, 1)
+		assert.Equal(t, []tag.Tag{{Key: methodKey, Value: "GET"}}, rows[0].Tags)
+		assert.Equal(t, int64(4), rows[0].Data.(*view.CountData).Value)
+	})
+
+	t.Run("label values are bucketed", func(t *testing.T) {
+		rows, err := view.RetrieveData("test/rules/bucketed")
+		assert.NoError(t, err)
+		counts := map[string]int64{}
+		for _, row := range rows {
+			for _, tag := range row.Tags {
+				if tag.Key == pathKey {
+					counts[tag.Value] = row.Data.(*view.CountData).Value
+				}
+			}
+		}
+		assert.Equal(t, map[string]int64{
+			"/v1.0/invoke/orders/{id}": 2,
+			"/v1.0/invoke/{other}":     1,
+			"/v1.0/state/store":        1,
+		}, counts)
+	})
+}
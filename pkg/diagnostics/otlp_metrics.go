@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/metric/metricexport"
+	ocresource "go.opencensus.io/resource"
+	"go.opentelemetry.io/otel/bridge/opencensus"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+)
+
+const (
+	// otlpMetricsPath is the path of the metrics service of OTLP/HTTP collectors.
+	otlpMetricsPath = "/v1/metrics"
+	otlpTimeout     = 10 * time.Second
+	// serviceNameLabel is the resource attribute identifying the exporting process.
+	serviceNameLabel = "service.name"
+)
+
+// OTLPMetricsOptions configures the export of metrics to an OpenTelemetry collector.
+type OTLPMetricsOptions struct {
+	// EndpointAddress is the URL of the OTLP/HTTP receiver of the collector, e.g. http://otel-collector:4318.
+	EndpointAddress string
+	// Headers are added to the export requests, e.g. for authentication.
+	Headers map[string]string
+	// PushInterval is the interval between exports, DefaultReportingPeriod by default.
+	PushInterval time.Duration
+	// ServiceName identifies the exporting process in the resource of the metrics.
+	ServiceName string
+}
+
+// OTLPMetricsExporter pushes the metrics of all the registered views to an OpenTelemetry collector with the OTLP/HTTP
+// exporter of OpenTelemetry, for collectors which don't scrape the Prometheus endpoint.
+type OTLPMetricsExporter struct {
+	client   otlpmetric.Client
+	resource *ocresource.Resource
+	reader   *metricexport.IntervalReader
+
+	exporter *otlpmetric.Exporter
+	bridge   metricexport.Exporter
+}
+
+// NewOTLPMetricsExporter returns a new exporter of metrics to the collector of opts.
+func NewOTLPMetricsExporter(opts OTLPMetricsOptions) (*OTLPMetricsExporter, error) {
+	u, err := url.Parse(opts.EndpointAddress)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.Errorf("invalid OTLP endpoint address %q", opts.EndpointAddress)
+	}
+	path := u.Path
+	if path == "" || path == "/" {
+		path = otlpMetricsPath
+	}
+
+	clientOpts := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithEndpoint(u.Host),
+		otlpmetrichttp.WithURLPath(path),
+		otlpmetrichttp.WithTimeout(otlpTimeout),
+	}
+	if len(opts.Headers) > 0 {
+		clientOpts = append(clientOpts, otlpmetrichttp.WithHeaders(opts.Headers))
+	}
+	if u.Scheme == "http" {
+		clientOpts = append(clientOpts, otlpmetrichttp.WithInsecure())
+	}
+
+	e := &OTLPMetricsExporter{
+		client: otlpmetrichttp.NewClient(clientOpts...),
+	}
+	if opts.ServiceName != "" {
+		e.resource = &ocresource.Resource{Labels: map[string]string{serviceNameLabel: opts.ServiceName}}
+	}
+	e.reader, err = metricexport.NewIntervalReader(metricexport.NewReader(), e)
+	if err != nil {
+		return nil, err
+	}
+	e.reader.ReportingInterval = DefaultReportingPeriod
+	if opts.PushInterval > 0 {
+		e.reader.ReportingInterval = opts.PushInterval
+	}
+	return e, nil
+}
+
+// Start starts the OTLP exporter and pushing the metrics periodically.
+func (e *OTLPMetricsExporter) Start() error {
+	exporter, err := otlpmetric.New(context.Background(), e.client)
+	if err != nil {
+		return errors.Wrap(err, "failed to start OTLP exporter")
+	}
+	e.exporter = exporter
+	e.bridge = opencensus.NewMetricExporter(exporter)
+	return e.reader.Start()
+}
+
+// Stop pushes the last metrics and stops the exporter.
+func (e *OTLPMetricsExporter) Stop() {
+	e.reader.Stop()
+	if e.exporter != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), otlpTimeout)
+		defer cancel()
+		e.exporter.Shutdown(ctx)
+	}
+}
+
+// ExportMetrics pushes OpenCensus metrics to the collector through the OpenTelemetry bridge.
+// It implements metricexport.Exporter.
+func (e *OTLPMetricsExporter) ExportMetrics(ctx context.Context, metrics []*metricdata.Metric) error {
+	if len(metrics) == 0 || e.bridge == nil {
+		return nil
+	}
+
+	// The bridge takes the resource of the exported metrics from the metrics, which views don't set.
+	if e.resource != nil {
+		withResource := make([]*metricdata.Metric, len(metrics))
+		for i, m := range metrics {
+			copied := *m
+			copied.Resource = e.resource
+			withResource[i] = &copied
+		}
+		metrics = withResource
+	}
+	return e.bridge.ExportMetrics(ctx, metrics)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/metric/metricdata"
+)
+
+func TestNewOTLPMetricsExporter(t *testing.T) {
+	for _, address := range []string{"", "otel-collector:4318", "http://", "grpc://otel-collector:4317"} {
+		_, err := NewOTLPMetricsExporter(OTLPMetricsOptions{EndpointAddress: address})
+		assert.Error(t, err, address)
+	}
+
+	e, err := NewOTLPMetricsExporter(OTLPMetricsOptions{EndpointAddress: "http://otel-collector:4318"})
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultReportingPeriod, e.reader.ReportingInterval)
+	assert.Nil(t, e.resource)
+
+	e, err = NewOTLPMetricsExporter(OTLPMetricsOptions{EndpointAddress: "https://collector/otlp/v1/metrics", PushInterval: 10 * time.Second, ServiceName: "app1"})
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Second, e.reader.ReportingInterval)
+	assert.Equal(t, "app1", e.resource.Labels[serviceNameLabel])
+}
+
+func TestOTLPExportMetrics(t *testing.T) {
+	var path string
+	var headers http.Header
+	var body []byte
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		headers = r.Header
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	e, err := NewOTLPMetricsExporter(OTLPMetricsOptions{
+		EndpointAddress: server.URL,
+		Headers:         map[string]string{"Authorization": "Bearer token"},
+		ServiceName:     "app1",
+		PushInterval:    time.Hour,
+	})
+	require.NoError(t, err)
+	require.NoError(t, e.Start())
+	defer e.Stop()
+
+	metrics := []*metricdata.Metric{
+		{
+			Descriptor: metricdata.Descriptor{
+				Name:      "runtime/component/loaded",
+				Type:      metricdata.TypeCumulativeInt64,
+				LabelKeys: []metricdata.LabelKey{{Key: "app_id"}},
+			},
+			TimeSeries: []*metricdata.TimeSeries{{
+				LabelValues: []metricdata.LabelValue{metricdata.NewLabelValue("app1")},
+				StartTime:   time.Unix(100, 0),
+				Points:      []metricdata.Point{metricdata.NewInt64Point(time.Unix(160, 0), 3)},
+			}},
+		},
+	}
+
+	t.Run("metrics are pushed", func(t *testing.T) {
+		assert.NoError(t, e.ExportMetrics(context.Background(), metrics))
+		assert.Equal(t, otlpMetricsPath, path)
+		assert.Equal(t, "Bearer token", headers.Get("Authorization"))
+		assert.Equal(t, "application/x-protobuf", headers.Get("Content-Type"))
+		assert.Contains(t, string(body), "app1")
+		// the metrics of the caller are not modified.
+		assert.Nil(t, metrics[0].Resource)
+	})
+
+	t.Run("collector errors are returned", func(t *testing.T) {
+		status = http.StatusBadRequest
+		assert.Error(t, e.ExportMetrics(context.Background(), metrics))
+	})
+
+	t.Run("nothing is pushed without metrics", func(t *testing.T) {
+		body = nil
+		assert.NoError(t, e.ExportMetrics(context.Background(), nil))
+		assert.Nil(t, body)
+	})
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	// ProfileCPU is the profile of the CPU usage, sampled during the whole interval between exports.
+	ProfileCPU = "cpu"
+	// ProfileHeap is the profile of the memory allocations of the live objects.
+	ProfileHeap = "heap"
+	// ProfileGoroutine is the profile of the stack traces of all the goroutines.
+	ProfileGoroutine = "goroutine"
+	// ProfileMutex is the profile of the holders of contended mutexes.
+	ProfileMutex = "mutex"
+	// ProfileBlock is the profile of the stack traces that led to blocking on synchronization primitives.
+	ProfileBlock = "block"
+
+	defaultProfilingInterval = 10 * time.Second
+	profilingTimeout         = 10 * time.Second
+	// cpuProfileSampleRate is the sampling frequency in Hz of the CPU profiles of the Go runtime.
+	cpuProfileSampleRate = 100
+	// mutexProfileFraction samples 1 in 5 of the contention events of the mutex profile.
+	mutexProfileFraction = 5
+	// blockProfileRate samples 1 blocking event per millisecond spent blocked in the block profile.
+	blockProfileRate = int(time.Millisecond)
+)
+
+var profilingLog = logger.NewLogger("dapr.runtime.profiling")
+
+// ProfilingOptions configures the continuous export of the profiles of the sidecar.
+type ProfilingOptions struct {
+	// EndpointAddress is the URL of the ingestion API of the profiling server, e.g. http://pyroscope:4040/ingest.
+	EndpointAddress string
+	// Headers are added to the export requests, e.g. for authentication.
+	Headers map[string]string
+	// Interval is the duration of the CPU profiles and the interval between exports, 10s by default.
+	Interval time.Duration
+	// Profiles are the types of the profiles exported, the CPU and heap profiles by default.
+	Profiles []string
+	// AppID identifies the profiles of the sidecar, which are named <app id>.<profile type>.
+	AppID string
+}
+
+// ProfilesExporter pushes the profiles of the sidecar periodically to a profiling server, e.g. Pyroscope, so that
+// production sidecars are profiled without exposing the profiling port.
+type ProfilesExporter struct {
+	endpoint string
+	headers  map[string]string
+	interval time.Duration
+	profiles []string
+	appID    string
+	client   *http.Client
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewProfilesExporter returns a new exporter of profiles to the profiling server of opts.
+func NewProfilesExporter(opts ProfilingOptions) (*ProfilesExporter, error) {
+	u, err := url.Parse(opts.EndpointAddress)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, errors.Errorf("invalid profiling endpoint address %q", opts.EndpointAddress)
+	}
+
+	profiles := opts.Profiles
+	if len(profiles) == 0 {
+		profiles = []string{ProfileCPU, ProfileHeap}
+	}
+	for _, p := range profiles {
+		switch p {
+		case ProfileCPU, ProfileHeap, ProfileGoroutine, ProfileMutex, ProfileBlock:
+		default:
+			return nil, errors.Errorf("invalid profile type %q", p)
+		}
+	}
+
+	e := &ProfilesExporter{
+		endpoint: u.String(),
+		headers:  opts.Headers,
+		interval: defaultProfilingInterval,
+		profiles: profiles,
+		appID:    opts.AppID,
+		client:   &http.Client{Timeout: profilingTimeout},
+		stopCh:   make(chan struct{}),
+	}
+	if opts.Interval > 0 {
+		e.interval = opts.Interval
+	}
+	return e, nil
+}
+
+// Start starts pushing the profiles periodically.
+func (e *ProfilesExporter) Start() {
+	// The mutex and block profiles are empty unless their sampling is enabled.
+	if e.enabled(ProfileMutex) {
+		runtime.SetMutexProfileFraction(mutexProfileFraction)
+	}
+	if e.enabled(ProfileBlock) {
+		runtime.SetBlockProfileRate(blockProfileRate)
+	}
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		running := true
+		for running {
+			running = e.collect()
+		}
+	}()
+}
+
+// Stop pushes the profiles of the current interval and stops the exporter. It can be called more than once.
+func (e *ProfilesExporter) Stop() {
+	e.stopOnce.Do(func() {
+		close(e.stopCh)
+	})
+	e.wg.Wait()
+}
+
+// collect profiles the sidecar during an interval then pushes the profiles. It returns false once stopped.
+func (e *ProfilesExporter) collect() bool {
+	from := time.Now()
+	var cpu *bytes.Buffer
+	if e.enabled(ProfileCPU) {
+		cpu = &bytes.Buffer{}
+		// The CPU profile can't be started while it is collected from the profiling port.
+		if err := pprof.StartCPUProfile(cpu); err != nil {
+			profilingLog.Debugf("skipping CPU profile: %s", err)
+			cpu = nil
+		}
+	}
+
+	running := true
+	select {
+	case <-time.After(e.interval):
+	case <-e.stopCh:
+		running = false
+	}
+
+	if cpu != nil {
+		pprof.StopCPUProfile()
+	}
+	until := time.Now()
+
+	for _, p := range e.profiles {
+		var profile *bytes.Buffer
+		if p == ProfileCPU {
+			profile = cpu
+		} else if lookup := pprof.Lookup(p); lookup != nil {
+			profile = &bytes.Buffer{}
+			if err := lookup.WriteTo(profile, 0); err != nil {
+				profilingLog.Warnf("failed to collect %s profile: %s", p, err)
+				continue
+			}
+		}
+		if profile == nil {
+			continue
+		}
+		if err := e.push(p, profile.Bytes(), from, until); err != nil {
+			profilingLog.Warnf("failed to export %s profile: %s", p, err)
+		}
+	}
+	return running
+}
+
+func (e *ProfilesExporter) enabled(profileType string) bool {
+	for _, p := range e.profiles {
+		if p == profileType {
+			return true
+		}
+	}
+	return false
+}
+
+// push sends a profile in the pprof format to the ingestion API of the profiling server.
+func (e *ProfilesExporter) push(profileType string, profile []byte, from, until time.Time) error {
+	u, _ := url.Parse(e.endpoint)
+	q := u.Query()
+	q.Set("name", e.appID+"."+profileType)
+	q.Set("from", strconv.FormatInt(from.Unix(), 10))
+	q.Set("until", strconv.FormatInt(until.Unix(), 10))
+	q.Set("format", "pprof")
+	q.Set("spyName", "gospy")
+	if profileType == ProfileCPU {
+		q.Set("sampleRate", strconv.Itoa(cpuProfileSampleRate))
+	}
+	u.RawQuery = q.Encode()
+
+	ctx, cancel := context.WithTimeout(context.Background(), profilingTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(profile))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// The body is drained so that the connection is reused.
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("status %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
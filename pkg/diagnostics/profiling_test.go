@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProfilesExporter(t *testing.T) {
+	_, err := NewProfilesExporter(ProfilingOptions{EndpointAddress: "pyroscope:4040"})
+	assert.Error(t, err)
+	_, err = NewProfilesExporter(ProfilingOptions{EndpointAddress: "http://pyroscope:4040/ingest", Profiles: []string{"threads"}})
+	assert.Error(t, err)
+
+	e, err := NewProfilesExporter(ProfilingOptions{EndpointAddress: "http://pyroscope:4040/ingest"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{ProfileCPU, ProfileHeap}, e.profiles)
+	assert.Equal(t, defaultProfilingInterval, e.interval)
+}
+
+func TestProfilesExporter(t *testing.T) {
+	var lock sync.Mutex
+	pushed := map[string]*http.Request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.NotEmpty(t, body)
+		lock.Lock()
+		pushed[r.URL.Query().Get("name")] = r
+		lock.Unlock()
+	}))
+	defer server.Close()
+
+	e, err := NewProfilesExporter(ProfilingOptions{
+		EndpointAddress: server.URL + "/ingest",
+		Headers:         map[string]string{"Authorization": "Bearer token"},
+		Interval:        time.Hour,
+		Profiles:        []string{ProfileHeap, ProfileGoroutine},
+		AppID:           "testAppId",
+	})
+	assert.NoError(t, err)
+	e.Start()
+	// The profiles of the current interval are pushed when the exporter stops.
+	e.Stop()
+	assert.NotPanics(t, e.Stop)
+
+	assert.Len(t, pushed, 2)
+	for _, name := range []string{"testAppId.heap", "testAppId.goroutine"} {
+		r := pushed[name]
+		if assert.NotNil(t, r, name) {
+			assert.Equal(t, "/ingest", r.URL.Path)
+			assert.Equal(t, "pprof", r.URL.Query().Get("format"))
+			assert.NotEmpty(t, r.URL.Query().Get("from"))
+			assert.NotEmpty(t, r.URL.Query().Get("until"))
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		}
+	}
+}
@@ -2,6 +2,7 @@ package diagnostics
 
 import (
 	"context"
+	"time"
 
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
@@ -19,6 +20,23 @@ var (
 	trustDomainKey  = tag.MustNewKey("trustDomain")
 	namespaceKey    = tag.MustNewKey("namespace")
 	policyActionKey = tag.MustNewKey("policyAction")
+	topicKey        = tag.MustNewKey("topic")
+	outcomeKey      = tag.MustNewKey("outcome")
+	tokenKey        = tag.MustNewKey("token")
+)
+
+// Outcomes of the pubsub events received and published by the runtime.
+const (
+	// PubsubOutcomeSuccess is the outcome of the events processed by the app or published successfully.
+	PubsubOutcomeSuccess = "success"
+	// PubsubOutcomeRetry is the outcome of the events left with the broker to be redelivered.
+	PubsubOutcomeRetry = "retry"
+	// PubsubOutcomeDrop is the outcome of the events acknowledged without being processed, e.g. expired.
+	PubsubOutcomeDrop = "drop"
+	// PubsubOutcomeDeadLetter is the outcome of the events published to a dead-letter topic by the runtime.
+	PubsubOutcomeDeadLetter = "deadletter"
+	// PubsubOutcomeFailed is the outcome of the events which failed to be published.
+	PubsubOutcomeFailed = "failed"
 )
 
 // serviceMetrics holds dapr runtime metric monitoring methods.
@@ -33,6 +51,8 @@ type serviceMetrics struct {
 	mtlsInitFailed                *stats.Int64Measure
 	mtlsWorkloadCertRotated       *stats.Int64Measure
 	mtlsWorkloadCertRotatedFailed *stats.Int64Measure
+	mtlsWorkloadCertExpiry        *stats.Int64Measure
+	mtlsIssuerCertExpiry          *stats.Int64Measure
 
 	// Actor metrics
 	actorStatusReportTotal       *stats.Int64Measure
@@ -49,6 +69,19 @@ type serviceMetrics struct {
 	appPolicyActionBlocked    *stats.Int64Measure
 	globalPolicyActionBlocked *stats.Int64Measure
 
+	// Secret cache metrics
+	secretCacheHits   *stats.Int64Measure
+	secretCacheMisses *stats.Int64Measure
+
+	// Pubsub metrics
+	pubsubIngressCount   *stats.Int64Measure
+	pubsubIngressLatency *stats.Float64Measure
+	pubsubEgressCount    *stats.Int64Measure
+
+	// API token metrics
+	apiTokenAuthenticated *stats.Int64Measure
+	apiTokenRejected      *stats.Int64Measure
+
 	appID   string
 	ctx     context.Context
 	enabled bool
@@ -88,6 +121,14 @@ func newServiceMetrics() *serviceMetrics {
 			"runtime/mtls/workload_cert_rotated_fail_total",
 			"The number of the failed workload certificate rotations.",
 			stats.UnitDimensionless),
+		mtlsWorkloadCertExpiry: stats.Int64(
+			"runtime/mtls/workload_cert_expiry_timestamp",
+			"The unix timestamp, in seconds, when the current workload certificate will expire.",
+			stats.UnitDimensionless),
+		mtlsIssuerCertExpiry: stats.Int64(
+			"runtime/mtls/issuer_cert_expiry_timestamp",
+			"The unix timestamp, in seconds, when the issuer certificate of the workload certificate will expire.",
+			stats.UnitDimensionless),
 
 		// Actor
 		actorStatusReportTotal: stats.Int64(
@@ -137,6 +178,40 @@ func newServiceMetrics() *serviceMetrics {
 			"The number of requests blocked by the global action specified in the access control policy.",
 			stats.UnitDimensionless),
 
+		// Secret cache
+		secretCacheHits: stats.Int64(
+			"runtime/secret/cache_hit_total",
+			"The number of secret requests served from the secret cache.",
+			stats.UnitDimensionless),
+		secretCacheMisses: stats.Int64(
+			"runtime/secret/cache_miss_total",
+			"The number of secret requests sent to the secret store because the secret wasn't cached.",
+			stats.UnitDimensionless),
+
+		// Pubsub
+		pubsubIngressCount: stats.Int64(
+			"runtime/pubsub/ingress/count",
+			"The number of events received from a topic, by outcome of their delivery to the app.",
+			stats.UnitDimensionless),
+		pubsubIngressLatency: stats.Float64(
+			"runtime/pubsub/ingress/latency",
+			"The end to end latency of the delivery of the events, from the time of their CloudEvent.",
+			stats.UnitMilliseconds),
+		pubsubEgressCount: stats.Int64(
+			"runtime/pubsub/egress/count",
+			"The number of events published to a topic, by outcome.",
+			stats.UnitDimensionless),
+
+		// API token
+		apiTokenAuthenticated: stats.Int64(
+			"runtime/api_token/authenticated_total",
+			"The number of requests authenticated by each api token, identified by the prefix of the token hash.",
+			stats.UnitDimensionless),
+		apiTokenRejected: stats.Int64(
+			"runtime/api_token/rejected_total",
+			"The number of requests rejected because of a missing or invalid api token.",
+			stats.UnitDimensionless),
+
 		// TODO: use the correct context for each request
 		ctx:     context.Background(),
 		enabled: false,
@@ -147,7 +222,12 @@ func newServiceMetrics() *serviceMetrics {
 func (s *serviceMetrics) Init(appID string) error {
 	s.appID = appID
 	s.enabled = true
-	return view.Register(
+	return registerViews(s.views()...)
+}
+
+// views returns the views of the metrics.
+func (s *serviceMetrics) views() []*view.View {
+	return []*view.View{
 		diag_utils.NewMeasureView(s.componentLoaded, []tag.Key{appIDKey}, view.Count()),
 		diag_utils.NewMeasureView(s.componentInitCompleted, []tag.Key{appIDKey, componentKey}, view.Count()),
 		diag_utils.NewMeasureView(s.componentInitFailed, []tag.Key{appIDKey, componentKey, failReasonKey}, view.Count()),
@@ -156,6 +236,8 @@ func (s *serviceMetrics) Init(appID string) error {
 		diag_utils.NewMeasureView(s.mtlsInitFailed, []tag.Key{appIDKey, failReasonKey}, view.Count()),
 		diag_utils.NewMeasureView(s.mtlsWorkloadCertRotated, []tag.Key{appIDKey}, view.Count()),
 		diag_utils.NewMeasureView(s.mtlsWorkloadCertRotatedFailed, []tag.Key{appIDKey, failReasonKey}, view.Count()),
+		diag_utils.NewMeasureView(s.mtlsWorkloadCertExpiry, []tag.Key{appIDKey}, view.LastValue()),
+		diag_utils.NewMeasureView(s.mtlsIssuerCertExpiry, []tag.Key{appIDKey}, view.LastValue()),
 
 		diag_utils.NewMeasureView(s.actorStatusReportTotal, []tag.Key{appIDKey, actorTypeKey, operationKey}, view.Count()),
 		diag_utils.NewMeasureView(s.actorStatusReportFailedTotal, []tag.Key{appIDKey, actorTypeKey, operationKey, failReasonKey}, view.Count()),
@@ -169,20 +251,30 @@ func (s *serviceMetrics) Init(appID string) error {
 		diag_utils.NewMeasureView(s.globalPolicyActionAllowed, []tag.Key{appIDKey, trustDomainKey, namespaceKey, operationKey, httpMethodKey, policyActionKey}, view.LastValue()),
 		diag_utils.NewMeasureView(s.appPolicyActionBlocked, []tag.Key{appIDKey, trustDomainKey, namespaceKey, operationKey, httpMethodKey, policyActionKey}, view.LastValue()),
 		diag_utils.NewMeasureView(s.globalPolicyActionBlocked, []tag.Key{appIDKey, trustDomainKey, namespaceKey, operationKey, httpMethodKey, policyActionKey}, view.LastValue()),
-	)
+
+		diag_utils.NewMeasureView(s.secretCacheHits, []tag.Key{appIDKey, componentKey}, view.Count()),
+		diag_utils.NewMeasureView(s.secretCacheMisses, []tag.Key{appIDKey, componentKey}, view.Count()),
+
+		diag_utils.NewMeasureView(s.pubsubIngressCount, []tag.Key{appIDKey, componentKey, topicKey, outcomeKey}, view.Count()),
+		diag_utils.NewMeasureView(s.pubsubIngressLatency, []tag.Key{appIDKey, componentKey, topicKey, outcomeKey}, defaultLatencyDistribution),
+		diag_utils.NewMeasureView(s.pubsubEgressCount, []tag.Key{appIDKey, componentKey, topicKey, outcomeKey}, view.Count()),
+
+		diag_utils.NewMeasureView(s.apiTokenAuthenticated, []tag.Key{appIDKey, tokenKey}, view.Count()),
+		diag_utils.NewMeasureView(s.apiTokenRejected, []tag.Key{appIDKey, failReasonKey}, view.Count()),
+	}
 }
 
 // ComponentLoaded records metric when component is loaded successfully.
 func (s *serviceMetrics) ComponentLoaded() {
 	if s.enabled {
-		stats.RecordWithTags(s.ctx, diag_utils.WithTags(appIDKey, s.appID), s.componentLoaded.M(1))
+		recordWithTags(s.ctx, diag_utils.WithTags(appIDKey, s.appID), s.componentLoaded.M(1))
 	}
 }
 
 // ComponentInitialized records metric when component is initialized.
 func (s *serviceMetrics) ComponentInitialized(component string) {
 	if s.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			s.ctx,
 			diag_utils.WithTags(appIDKey, s.appID, componentKey, component),
 			s.componentInitCompleted.M(1))
@@ -192,7 +284,7 @@ func (s *serviceMetrics) ComponentInitialized(component string) {
 // ComponentInitFailed records metric when component initialization is failed.
 func (s *serviceMetrics) ComponentInitFailed(component string, reason string) {
 	if s.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			s.ctx,
 			diag_utils.WithTags(appIDKey, s.appID, componentKey, component, failReasonKey, reason),
 			s.componentInitFailed.M(1))
@@ -202,14 +294,14 @@ func (s *serviceMetrics) ComponentInitFailed(component string, reason string) {
 // MTLSInitCompleted records metric when component is initialized.
 func (s *serviceMetrics) MTLSInitCompleted() {
 	if s.enabled {
-		stats.RecordWithTags(s.ctx, diag_utils.WithTags(appIDKey, s.appID), s.mtlsInitCompleted.M(1))
+		recordWithTags(s.ctx, diag_utils.WithTags(appIDKey, s.appID), s.mtlsInitCompleted.M(1))
 	}
 }
 
 // MTLSInitFailed records metric when component initialization is failed.
 func (s *serviceMetrics) MTLSInitFailed(reason string) {
 	if s.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			s.ctx, diag_utils.WithTags(appIDKey, s.appID, failReasonKey, reason),
 			s.mtlsInitFailed.M(1))
 	}
@@ -218,23 +310,37 @@ func (s *serviceMetrics) MTLSInitFailed(reason string) {
 // MTLSWorkLoadCertRotationCompleted records metric when workload certificate rotation is succeeded.
 func (s *serviceMetrics) MTLSWorkLoadCertRotationCompleted() {
 	if s.enabled {
-		stats.RecordWithTags(s.ctx, diag_utils.WithTags(appIDKey, s.appID), s.mtlsWorkloadCertRotated.M(1))
+		recordWithTags(s.ctx, diag_utils.WithTags(appIDKey, s.appID), s.mtlsWorkloadCertRotated.M(1))
 	}
 }
 
 // MTLSWorkLoadCertRotationFailed records metric when workload certificate rotation is failed.
 func (s *serviceMetrics) MTLSWorkLoadCertRotationFailed(reason string) {
 	if s.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			s.ctx, diag_utils.WithTags(appIDKey, s.appID, failReasonKey, reason),
 			s.mtlsWorkloadCertRotatedFailed.M(1))
 	}
 }
 
+// MTLSWorkloadCertExpiry records the expiry of the current workload certificate.
+func (s *serviceMetrics) MTLSWorkloadCertExpiry(expiry time.Time) {
+	if s.enabled {
+		recordWithTags(s.ctx, diag_utils.WithTags(appIDKey, s.appID), s.mtlsWorkloadCertExpiry.M(expiry.Unix()))
+	}
+}
+
+// MTLSIssuerCertExpiry records the expiry of the issuer certificate of the current workload certificate.
+func (s *serviceMetrics) MTLSIssuerCertExpiry(expiry time.Time) {
+	if s.enabled {
+		recordWithTags(s.ctx, diag_utils.WithTags(appIDKey, s.appID), s.mtlsIssuerCertExpiry.M(expiry.Unix()))
+	}
+}
+
 // ActorStatusReported records metrics when status is reported to placement service.
 func (s *serviceMetrics) ActorStatusReported(operation string) {
 	if s.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			s.ctx, diag_utils.WithTags(appIDKey, s.appID, operationKey, operation),
 			s.actorStatusReportTotal.M(1))
 	}
@@ -243,7 +349,7 @@ func (s *serviceMetrics) ActorStatusReported(operation string) {
 // ActorStatusReportFailed records metrics when status report to placement service is failed.
 func (s *serviceMetrics) ActorStatusReportFailed(operation string, reason string) {
 	if s.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			s.ctx, diag_utils.WithTags(appIDKey, s.appID, operationKey, operation, failReasonKey, reason),
 			s.actorStatusReportFailedTotal.M(1))
 	}
@@ -252,7 +358,7 @@ func (s *serviceMetrics) ActorStatusReportFailed(operation string, reason string
 // ActorPlacementTableOperationReceived records metric when runtime receives table operation.
 func (s *serviceMetrics) ActorPlacementTableOperationReceived(operation string) {
 	if s.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			s.ctx, diag_utils.WithTags(appIDKey, s.appID, operationKey, operation),
 			s.actorTableOperationRecvTotal.M(1))
 	}
@@ -261,7 +367,7 @@ func (s *serviceMetrics) ActorPlacementTableOperationReceived(operation string)
 // ActorRebalanced records metric when actors are drained.
 func (s *serviceMetrics) ActorRebalanced(actorType string) {
 	if s.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			s.ctx,
 			diag_utils.WithTags(appIDKey, s.appID, actorTypeKey, actorType),
 			s.actorRebalancedTotal.M(1))
@@ -271,7 +377,7 @@ func (s *serviceMetrics) ActorRebalanced(actorType string) {
 // ActorDeactivated records metric when actor is deactivated.
 func (s *serviceMetrics) ActorDeactivated(actorType string) {
 	if s.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			s.ctx,
 			diag_utils.WithTags(appIDKey, s.appID, actorTypeKey, actorType),
 			s.actorDeactivationTotal.M(1))
@@ -281,7 +387,7 @@ func (s *serviceMetrics) ActorDeactivated(actorType string) {
 // ActorDeactivationFailed records metric when actor deactivation is failed.
 func (s *serviceMetrics) ActorDeactivationFailed(actorType, reason string) {
 	if s.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			s.ctx,
 			diag_utils.WithTags(appIDKey, s.appID, actorTypeKey, actorType, failReasonKey, reason),
 			s.actorDeactivationFailedTotal.M(1))
@@ -291,7 +397,7 @@ func (s *serviceMetrics) ActorDeactivationFailed(actorType, reason string) {
 // ReportActorPendingCalls records the current pending actor locks.
 func (s *serviceMetrics) ReportActorPendingCalls(actorType string, pendingLocks int32) {
 	if s.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			s.ctx,
 			diag_utils.WithTags(appIDKey, s.appID, actorTypeKey, actorType),
 			s.actorPendingCalls.M(int64(pendingLocks)))
@@ -301,7 +407,7 @@ func (s *serviceMetrics) ReportActorPendingCalls(actorType string, pendingLocks
 // RequestAllowedByAppAction records the requests allowed due to a match with the action specified in the access control policy for the app.
 func (s *serviceMetrics) RequestAllowedByAppAction(appID, trustDomain, namespace, operation, httpverb string, policyAction bool) {
 	if s.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			s.ctx,
 			diag_utils.WithTags(
 				appIDKey, appID,
@@ -317,7 +423,7 @@ func (s *serviceMetrics) RequestAllowedByAppAction(appID, trustDomain, namespace
 // RequestBlockedByAppAction records the requests blocked due to a match with the action specified in the access control policy for the app.
 func (s *serviceMetrics) RequestBlockedByAppAction(appID, trustDomain, namespace, operation, httpverb string, policyAction bool) {
 	if s.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			s.ctx,
 			diag_utils.WithTags(
 				appIDKey, appID,
@@ -333,7 +439,7 @@ func (s *serviceMetrics) RequestBlockedByAppAction(appID, trustDomain, namespace
 // RequestAllowedByGlobalAction records the requests allowed due to a match with the global action in the access control policy.
 func (s *serviceMetrics) RequestAllowedByGlobalAction(appID, trustDomain, namespace, operation, httpverb string, policyAction bool) {
 	if s.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			s.ctx,
 			diag_utils.WithTags(
 				appIDKey, appID,
@@ -349,7 +455,7 @@ func (s *serviceMetrics) RequestAllowedByGlobalAction(appID, trustDomain, namesp
 // RequestBlockedByGlobalAction records the requests blocked due to a match with the global action in the access control policy.
 func (s *serviceMetrics) RequestBlockedByGlobalAction(appID, trustDomain, namespace, operation, httpverb string, policyAction bool) {
 	if s.enabled {
-		stats.RecordWithTags(
+		recordWithTags(
 			s.ctx,
 			diag_utils.WithTags(
 				appIDKey, appID,
@@ -361,3 +467,73 @@ func (s *serviceMetrics) RequestBlockedByGlobalAction(appID, trustDomain, namesp
 			s.globalPolicyActionBlocked.M(1))
 	}
 }
+
+// SecretCacheHit records a secret request served from the cache of the secret store.
+func (s *serviceMetrics) SecretCacheHit(store string) {
+	if s.enabled {
+		recordWithTags(
+			s.ctx,
+			diag_utils.WithTags(appIDKey, s.appID, componentKey, store),
+			s.secretCacheHits.M(1))
+	}
+}
+
+// SecretCacheMiss records a secret request sent to the secret store because the secret wasn't cached.
+func (s *serviceMetrics) SecretCacheMiss(store string) {
+	if s.enabled {
+		recordWithTags(
+			s.ctx,
+			diag_utils.WithTags(appIDKey, s.appID, componentKey, store),
+			s.secretCacheMisses.M(1))
+	}
+}
+
+// PubsubIngressEvent records an event received from the topic of a pubsub with the outcome of its delivery.
+func (s *serviceMetrics) PubsubIngressEvent(pubsubName, topic, outcome string) {
+	if s.enabled {
+		recordWithTags(
+			s.ctx,
+			diag_utils.WithTags(appIDKey, s.appID, componentKey, pubsubName, topicKey, topic, outcomeKey, outcome),
+			s.pubsubIngressCount.M(1))
+	}
+}
+
+// PubsubIngressLatency records the end to end latency of the delivery of an event, in milliseconds.
+func (s *serviceMetrics) PubsubIngressLatency(pubsubName, topic, outcome string, elapsed float64) {
+	if s.enabled {
+		recordWithTags(
+			s.ctx,
+			diag_utils.WithTags(appIDKey, s.appID, componentKey, pubsubName, topicKey, topic, outcomeKey, outcome),
+			s.pubsubIngressLatency.M(elapsed))
+	}
+}
+
+// PubsubEgressEvent records an event published to the topic of a pubsub with its outcome.
+func (s *serviceMetrics) PubsubEgressEvent(pubsubName, topic, outcome string) {
+	if s.enabled {
+		recordWithTags(
+			s.ctx,
+			diag_utils.WithTags(appIDKey, s.appID, componentKey, pubsubName, topicKey, topic, outcomeKey, outcome),
+			s.pubsubEgressCount.M(1))
+	}
+}
+
+// APITokenAuthenticated records a request authenticated by the api token with the given identifier.
+func (s *serviceMetrics) APITokenAuthenticated(tokenID string) {
+	if s.enabled {
+		recordWithTags(
+			s.ctx,
+			diag_utils.WithTags(appIDKey, s.appID, tokenKey, tokenID),
+			s.apiTokenAuthenticated.M(1))
+	}
+}
+
+// APITokenRejected records a request rejected because of a missing or invalid api token.
+func (s *serviceMetrics) APITokenRejected(reason string) {
+	if s.enabled {
+		recordWithTags(
+			s.ctx,
+			diag_utils.WithTags(appIDKey, s.appID, failReasonKey, reason),
+			s.apiTokenRejected.M(1))
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+func servicesTestViews(t *testing.T) *serviceMetrics {
+	t.Helper()
+
+	s := newServiceMetrics()
+	assert.NoError(t, s.Init("testAppId"))
+	t.Cleanup(func() {
+		for _, v := range s.views() {
+			if registered := view.Find(v.Name); registered != nil {
+				view.Unregister(registered)
+			}
+		}
+	})
+	return s
+}
+
+func TestPubsubMetrics(t *testing.T) {
+	s := servicesTestViews(t)
+
+	s.PubsubIngressEvent("pubsub", "orders", PubsubOutcomeSuccess)
+	s.PubsubIngressEvent("pubsub", "orders", PubsubOutcomeRetry)
+	s.PubsubIngressEvent("pubsub", "orders", PubsubOutcomeRetry)
+	s.PubsubIngressLatency("pubsub", "orders", PubsubOutcomeSuccess, 12)
+	s.PubsubEgressEvent("pubsub", "payments", PubsubOutcomeDeadLetter)
+
+	t.Run("ingress by topic and outcome", func(t *testing.T) {
+		rows, err := view.RetrieveData("runtime/pubsub/ingress/count")
+		assert.NoError(t, err)
+		assert.Len(t, rows, 2)
+		counts := map[string]int64{}
+		for _, row := range rows {
+			assert.Contains(t, row.Tags, tag.Tag{Key: componentKey, Value: "pubsub"})
+			assert.Contains(t, row.Tags, tag.Tag{Key: topicKey, Value: "orders"})
+			for _, tg := range row.Tags {
+				if tg.Key == outcomeKey {
+					counts[tg.Value] = row.Data.(*view.CountData).Value
+				}
+			}
+		}
+		assert.Equal(t, map[string]int64{PubsubOutcomeSuccess: 1, PubsubOutcomeRetry: 2}, counts)
+	})
+
+	t.Run("end to end latency", func(t *testing.T) {
+		rows, err := view.RetrieveData("runtime/pubsub/ingress/latency")
+		assert.NoError(t, err)
+		assert.Len(t, rows, 1)
+		assert.Equal(t, float64(12), rows[0].Data.(*view.DistributionData).Mean)
+	})
+
+	t.Run("egress by topic and outcome", func(t *testing.T) {
+		rows, err := view.RetrieveData("runtime/pubsub/egress/count")
+		assert.NoError(t, err)
+		assert.Len(t, rows, 1)
+		assert.Contains(t, rows[0].Tags, tag.Tag{Key: topicKey, Value: "payments"})
+		assert.Contains(t, rows[0].Tags, tag.Tag{Key: outcomeKey, Value: PubsubOutcomeDeadLetter})
+	})
+}
+
+func TestMTLSCertExpiryMetrics(t *testing.T) {
+	s := servicesTestViews(t)
+
+	expiry := time.Now().Add(time.Hour)
+	s.MTLSWorkloadCertExpiry(expiry.Add(-time.Minute))
+	s.MTLSWorkloadCertExpiry(expiry)
+	s.MTLSIssuerCertExpiry(expiry.Add(time.Hour))
+
+	rows, err := view.RetrieveData("runtime/mtls/workload_cert_expiry_timestamp")
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, float64(expiry.Unix()), rows[0].Data.(*view.LastValueData).Value)
+
+	rows, err = view.RetrieveData("runtime/mtls/issuer_cert_expiry_timestamp")
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, float64(expiry.Add(time.Hour).Unix()), rows[0].Data.(*view.LastValueData).Value)
+}
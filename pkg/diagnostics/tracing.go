@@ -28,7 +28,7 @@ import (
 	// We currently don't depend on the Otel SDK since it has not GAed.
 	// This package, however, only contains the conventions from the Otel Spec,
 	// which we do depend on.
-	"go.opentelemetry.io/otel/semconv"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 )
 
 const (
@@ -66,6 +66,9 @@ const (
 	daprAPIInvokeMethod               = "dapr.invoke_method"
 	daprAPIActorTypeID                = "dapr.actor"
 
+	daprComponentOperationSpanAttributeKey = "dapr.component.operation"
+	daprComponentKeyCountSpanAttributeKey  = "dapr.component.key_count"
+
 	daprAPIHTTPSpanAttrValue = "http"
 	daprAPIGRPCSpanAttrValue = "grpc"
 
@@ -79,7 +82,7 @@ const (
 )
 
 // Effectively const, but isn't a const from upstream.
-var messagingDestinationTopicKind = semconv.MessagingDestinationKindKeyTopic.Value.AsString()
+var messagingDestinationTopicKind = semconv.MessagingDestinationKindTopic.Value.AsString()
 
 // SpanContextToW3CString returns the SpanContext string representation.
 func SpanContextToW3CString(sc trace.SpanContext) string {
@@ -234,12 +237,69 @@ func ConstructSubscriptionSpanAttributes(topic string) map[string]string {
 }
 
 // StartInternalCallbackSpan starts trace span for internal callback such as input bindings and pubsub subscription.
+// The sampling rules apply to the span name.
 func StartInternalCallbackSpan(ctx context.Context, spanName string, parent trace.SpanContext, spec config.TracingSpec) (context.Context, *trace.Span) {
-	traceEnabled := diag_utils.IsTracingEnabled(spec.SamplingRate)
-	if !traceEnabled {
+	if !IsTracingEnabled(spec) {
 		return ctx, nil
 	}
 
-	sampler := diag_utils.TraceSampler(spec.SamplingRate)
+	sampler := requestSampler(spec, spanName, "")
 	return trace.StartSpanWithRemoteParent(ctx, spanName, parent, sampler, trace.WithSpanKind(trace.SpanKindServer))
 }
+
+// startComponentSpan starts a client span for an operation on a component, with the number of keys of the
+// operation if any. It returns nil when tracing is disabled.
+func startComponentSpan(ctx context.Context, spec config.TracingSpec, componentType, componentName, operation string, keyCount int) *trace.Span {
+	if !IsTracingEnabled(spec) {
+		return nil
+	}
+
+	var parent trace.SpanContext
+	if span := diag_utils.SpanFromContext(ctx); span != nil {
+		parent = span.SpanContext()
+	}
+
+	sampler := diag_utils.TraceSampler(spec.SamplingRate)
+	_, span := trace.StartSpanWithRemoteParent(ctx, fmt.Sprintf("%s/%s", componentType, operation), parent, sampler, trace.WithSpanKind(trace.SpanKindClient))
+	span.AddAttributes(
+		trace.StringAttribute(dbSystemSpanAttributeKey, componentType),
+		trace.StringAttribute(dbNameSpanAttributeKey, componentName),
+		trace.StringAttribute(daprComponentOperationSpanAttributeKey, operation))
+	if keyCount > 0 {
+		span.AddAttributes(trace.Int64Attribute(daprComponentKeyCountSpanAttributeKey, int64(keyCount)))
+	}
+	return span
+}
+
+// endComponentSpan ends a span started for an operation on a component, with the error of the operation if any.
+func endComponentSpan(span *trace.Span, err error) {
+	if span == nil {
+		return
+	}
+	if err != nil {
+		span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+	}
+	span.End()
+}
+
+// SpanContextToBindingMetadata returns the metadata of an output binding request with the W3C trace context of the
+// span, for the bindings which propagate it. The metadata is copied rather than modified.
+func SpanContextToBindingMetadata(span *trace.Span, md map[string]string) map[string]string {
+	if span == nil {
+		return md
+	}
+
+	sc := span.SpanContext()
+	if sc == (trace.SpanContext{}) {
+		return md
+	}
+	metadata := make(map[string]string, len(md)+2)
+	for k, v := range md {
+		metadata[k] = v
+	}
+	metadata[traceparentHeader] = SpanContextToW3CString(sc)
+	if sc.Tracestate != nil {
+		metadata[tracestateHeader] = TraceStateToW3CString(sc)
+	}
+	return metadata
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+
+	"github.com/dapr/dapr/pkg/config"
+	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
+)
+
+// failedRequestStartTimeAttributeKey is the attribute of the span of a failed request sampled by an errors rule,
+// which holds the start time of the request until the span is exported.
+const failedRequestStartTimeAttributeKey = "dapr.internal.request_start_time"
+
+// samplingRule is a sampling rule of the tracing spec, with its path regular expression compiled.
+type samplingRule struct {
+	path   *regexp.Regexp
+	appID  string
+	errors bool
+	rate   float64
+}
+
+// samplingRules are the sampling rules of the tracing spec, evaluated in order. Like the metrics rules, they are
+// set before the servers start.
+var samplingRules []samplingRule
+
+// SetTracingSamplingRules validates and sets the sampling rules of the requests traced by the HTTP and gRPC
+// servers.
+func SetTracingSamplingRules(rules []config.TracingSamplingRule) error {
+	compiled := make([]samplingRule, 0, len(rules))
+	for i, r := range rules {
+		if r.Path == "" && r.AppID == "" && !r.Errors {
+			return errors.Errorf("sampling rule %d matches no requests: set a path, an app id or errors", i)
+		}
+		rate, err := strconv.ParseFloat(r.SamplingRate, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return errors.Errorf("sampling rule %d has an invalid sampling rate %q: must be between 0 and 1", i, r.SamplingRate)
+		}
+		rule := samplingRule{appID: r.AppID, errors: r.Errors, rate: rate}
+		if r.Path != "" {
+			if rule.path, err = regexp.Compile(r.Path); err != nil {
+				return errors.Wrapf(err, "sampling rule %d has an invalid path", i)
+			}
+		}
+		compiled = append(compiled, rule)
+	}
+	samplingRules = compiled
+	return nil
+}
+
+// IsTracingEnabled returns false if the sampling rate of the spec and the sampling rates of all the sampling rules
+// set with SetTracingSamplingRules are explicitly set to 0.
+func IsTracingEnabled(spec config.TracingSpec) bool {
+	if diag_utils.IsTracingEnabled(spec.SamplingRate) {
+		return true
+	}
+	for _, r := range samplingRules {
+		if r.rate > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (r samplingRule) matches(path, appID string) bool {
+	return (r.path == nil || r.path.MatchString(path)) && (r.appID == "" || r.appID == appID)
+}
+
+// requestSampler returns the sampler of the first sampling rule matching the request, or the sampler of the sampling
+// rate of the spec. path is the path of the HTTP request or the full method of the gRPC call, and appID the id of the
+// invoked app if any.
+func requestSampler(spec config.TracingSpec, path, appID string) trace.StartOption {
+	for _, r := range samplingRules {
+		if !r.errors && r.matches(path, appID) {
+			return trace.WithSampler(trace.ProbabilitySampler(r.rate))
+		}
+	}
+	return diag_utils.TraceSampler(spec.SamplingRate)
+}
+
+// sampleFailedRequest returns true if a failed request whose span was not sampled is traced by the first errors
+// sampling rule matching it.
+func sampleFailedRequest(path, appID string) bool {
+	for _, r := range samplingRules {
+		if r.errors && r.matches(path, appID) {
+			return r.rate >= 1 || rand.Float64() < r.rate // nolint:gosec
+		}
+	}
+	return false
+}
+
+// startFailedRequestSpan starts a sampled span for a failed request in place of its span, which was not sampled.
+// The span has the remote parent of the request if any, or is a root span of the trace of the request. Its start
+// time is replaced with the start time of the request when it is exported, see ExportFailedRequestSpans.
+func startFailedRequestSpan(ctx context.Context, name string, parent trace.SpanContext, span *trace.Span, kind int, start time.Time) (context.Context, *trace.Span) {
+	if parent == (trace.SpanContext{}) {
+		parent.TraceID = span.SpanContext().TraceID
+	}
+	ctx, failed := trace.StartSpanWithRemoteParent(ctx, name, parent, trace.WithSampler(trace.AlwaysSample()), trace.WithSpanKind(kind))
+	failed.AddAttributes(trace.Int64Attribute(failedRequestStartTimeAttributeKey, start.UnixNano()))
+	return ctx, failed
+}
+
+// failedRequestSpanExporter is a trace exporter which restores the start time of the spans of the failed requests.
+type failedRequestSpanExporter struct {
+	trace.Exporter
+}
+
+// ExportFailedRequestSpans wraps a trace exporter so that the spans of the failed requests sampled by the errors
+// sampling rules have the duration of the request, instead of starting when the request failed.
+func ExportFailedRequestSpans(exporter trace.Exporter) trace.Exporter {
+	return failedRequestSpanExporter{Exporter: exporter}
+}
+
+// ExportSpan exports the span, with the start time of the request if it is the span of a failed request.
+func (e failedRequestSpanExporter) ExportSpan(sd *trace.SpanData) {
+	start, ok := sd.Attributes[failedRequestStartTimeAttributeKey].(int64)
+	if !ok {
+		e.Exporter.ExportSpan(sd)
+		return
+	}
+
+	// The span data is shared with the other exporters, so it is copied.
+	restored := *sd
+	restored.StartTime = time.Unix(0, start)
+	restored.Attributes = make(map[string]interface{}, len(sd.Attributes)-1)
+	for k, v := range sd.Attributes {
+		if k != failedRequestStartTimeAttributeKey {
+			restored.Attributes[k] = v
+		}
+	}
+	e.Exporter.ExportSpan(&restored)
+}
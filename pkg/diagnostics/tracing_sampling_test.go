@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/trace"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func TestSetTracingSamplingRules(t *testing.T) {
+	defer SetTracingSamplingRules(nil)
+
+	assert.Error(t, SetTracingSamplingRules([]config.TracingSamplingRule{{SamplingRate: "1"}}))
+	assert.Error(t, SetTracingSamplingRules([]config.TracingSamplingRule{{Path: "^/v1.0/invoke/", SamplingRate: "1.5"}}))
+	assert.Error(t, SetTracingSamplingRules([]config.TracingSamplingRule{{Path: "^/v1.0/invoke/", SamplingRate: "all"}}))
+	assert.Error(t, SetTracingSamplingRules([]config.TracingSamplingRule{{Path: "^/v1.0/(", SamplingRate: "1"}}))
+	assert.NoError(t, SetTracingSamplingRules([]config.TracingSamplingRule{
+		{Errors: true, SamplingRate: "1"},
+		{Path: "^/v1.0/invoke/", SamplingRate: "0.01"},
+		{AppID: "orders", SamplingRate: "1"},
+	}))
+	assert.Len(t, samplingRules, 3)
+}
+
+func TestSamplingRules(t *testing.T) {
+	defer SetTracingSamplingRules(nil)
+
+	assert.NoError(t, SetTracingSamplingRules([]config.TracingSamplingRule{
+		{Errors: true, Path: "^/v1.0/state/", SamplingRate: "1"},
+		{Path: "^/v1.0/state/", AppID: "orders", SamplingRate: "1"},
+		{Path: "^/v1.0/invoke/", SamplingRate: "0"},
+	}))
+	spec := config.TracingSpec{SamplingRate: "1"}
+	sampled := func(path, appID string) bool {
+		_, span := trace.StartSpan(context.Background(), "test", requestSampler(spec, path, appID))
+		defer span.End()
+		return span.SpanContext().IsSampled()
+	}
+
+	t.Run("first matching rule applies", func(t *testing.T) {
+		assert.False(t, sampled("/v1.0/invoke/orders/method/create", "orders"))
+		assert.True(t, sampled("/v1.0/state/statestore", "orders"))
+	})
+
+	t.Run("sampling rate of the spec applies without matching rule", func(t *testing.T) {
+		assert.True(t, sampled("/v1.0/publish/pubsub/topic", ""))
+		spec.SamplingRate = "0"
+		assert.False(t, sampled("/v1.0/state/statestore", "payments"))
+	})
+
+	t.Run("errors rules apply to failed requests only", func(t *testing.T) {
+		assert.True(t, sampleFailedRequest("/v1.0/state/statestore", ""))
+		assert.False(t, sampleFailedRequest("/v1.0/invoke/orders/method/create", "orders"))
+	})
+
+	t.Run("failed request span is sampled in the trace of the request", func(t *testing.T) {
+		_, span := trace.StartSpan(context.Background(), "test", trace.WithSampler(trace.NeverSample()))
+		span.End()
+		_, failed := startFailedRequestSpan(context.Background(), "test", trace.SpanContext{}, span, trace.SpanKindClient, time.Now())
+		defer failed.End()
+		assert.True(t, failed.SpanContext().IsSampled())
+		assert.Equal(t, span.SpanContext().TraceID, failed.SpanContext().TraceID)
+	})
+
+	t.Run("internal callback spans follow the sampling rules", func(t *testing.T) {
+		assert.NoError(t, SetTracingSamplingRules([]config.TracingSamplingRule{{Path: "^pubsub/", SamplingRate: "1"}}))
+		_, span := StartInternalCallbackSpan(context.Background(), "pubsub/orders", trace.SpanContext{}, config.TracingSpec{SamplingRate: "0"})
+		assert.NotNil(t, span)
+		defer span.End()
+		assert.True(t, span.SpanContext().IsSampled())
+	})
+}
+
+type recordingExporter struct {
+	spans []*trace.SpanData
+}
+
+func (e *recordingExporter) ExportSpan(sd *trace.SpanData) {
+	e.spans = append(e.spans, sd)
+}
+
+func TestExportFailedRequestSpans(t *testing.T) {
+	recorder := &recordingExporter{}
+	exporter := ExportFailedRequestSpans(recorder)
+	start := time.Now().Add(-time.Second)
+
+	exporter.ExportSpan(&trace.SpanData{StartTime: start.Add(time.Millisecond), Attributes: map[string]interface{}{"key": "value"}})
+	exporter.ExportSpan(&trace.SpanData{
+		StartTime: start.Add(time.Second),
+		Attributes: map[string]interface{}{
+			"key":                              "value",
+			failedRequestStartTimeAttributeKey: start.UnixNano(),
+		},
+	})
+
+	assert.Len(t, recorder.spans, 2)
+	assert.Equal(t, start.Add(time.Millisecond), recorder.spans[0].StartTime)
+	assert.True(t, start.Equal(recorder.spans[1].StartTime), "the span of a failed request starts with the request")
+	assert.Equal(t, map[string]interface{}{"key": "value"}, recorder.spans[1].Attributes)
+}
+
+func TestIsTracingEnabled(t *testing.T) {
+	defer SetTracingSamplingRules(nil)
+
+	assert.True(t, IsTracingEnabled(config.TracingSpec{SamplingRate: "0.5"}))
+	assert.False(t, IsTracingEnabled(config.TracingSpec{SamplingRate: "0"}))
+	assert.NoError(t, SetTracingSamplingRules([]config.TracingSamplingRule{{Errors: true, SamplingRate: "1"}}))
+	assert.True(t, IsTracingEnabled(config.TracingSpec{SamplingRate: "0"}))
+}
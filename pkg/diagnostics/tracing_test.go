@@ -14,11 +14,15 @@ limitations under the License.
 package diagnostics
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"go.opencensus.io/trace"
 	"go.opencensus.io/trace/tracestate"
+
+	"github.com/dapr/dapr/pkg/config"
 )
 
 func TestSpanContextToW3CString(t *testing.T) {
@@ -71,3 +75,91 @@ func TestOtelConventionStrings(t *testing.T) {
 	assert.Equal(t, "rpc.service", gRPCServiceSpanAttributeKey)
 	assert.Equal(t, "net.peer.name", netPeerNameSpanAttributeKey)
 }
+
+type spanRecorder struct {
+	spans []*trace.SpanData
+}
+
+func (r *spanRecorder) ExportSpan(sd *trace.SpanData) {
+	r.spans = append(r.spans, sd)
+}
+
+func TestStartComponentSpans(t *testing.T) {
+	recorder := &spanRecorder{}
+	trace.RegisterExporter(recorder)
+	defer trace.UnregisterExporter(recorder)
+	spec := config.TracingSpec{SamplingRate: "1"}
+
+	t.Run("state span is a child of the span of the request", func(t *testing.T) {
+		recorder.spans = nil
+		ctx, parent := trace.StartSpan(context.Background(), "request", trace.WithSampler(trace.AlwaysSample()))
+		span := startComponentSpan(ctx, spec, stateBuildingBlockType, "statestore", "bulkGet", 3)
+		endComponentSpan(span, errors.New("store failure"))
+		parent.End()
+
+		assert.Len(t, recorder.spans, 2)
+		sd := recorder.spans[0]
+		assert.Equal(t, "state/bulkGet", sd.Name)
+		assert.Equal(t, trace.SpanKindClient, sd.SpanKind)
+		assert.Equal(t, parent.SpanContext().TraceID, sd.TraceID)
+		assert.Equal(t, parent.SpanContext().SpanID, sd.ParentSpanID)
+		assert.Equal(t, map[string]interface{}{
+			"db.system":                "state",
+			"db.name":                  "statestore",
+			"dapr.component.operation": "bulkGet",
+			"dapr.component.key_count": int64(3),
+		}, sd.Attributes)
+		assert.Equal(t, trace.Status{Code: trace.StatusCodeUnknown, Message: "store failure"}, sd.Status)
+	})
+
+	t.Run("binding span", func(t *testing.T) {
+		recorder.spans = nil
+		span := startComponentSpan(context.Background(), spec, bindingBuildingBlockType, "kafka", "create", 0)
+		endComponentSpan(span, nil)
+
+		assert.Len(t, recorder.spans, 1)
+		sd := recorder.spans[0]
+		assert.Equal(t, "bindings/create", sd.Name)
+		assert.Equal(t, map[string]interface{}{
+			"db.system":                "bindings",
+			"db.name":                  "kafka",
+			"dapr.component.operation": "create",
+		}, sd.Attributes)
+		assert.Equal(t, int32(trace.StatusCodeOK), sd.Status.Code)
+	})
+
+	t.Run("no span when tracing is disabled", func(t *testing.T) {
+		span := startComponentSpan(context.Background(), config.TracingSpec{SamplingRate: "0"}, stateBuildingBlockType, "statestore", "get", 1)
+		assert.Nil(t, span)
+		endComponentSpan(span, nil)
+	})
+}
+
+func TestSpanContextToBindingMetadata(t *testing.T) {
+	t.Run("no span", func(t *testing.T) {
+		assert.Nil(t, SpanContextToBindingMetadata(nil, nil))
+		assert.Equal(t, map[string]string{"key": "value"}, SpanContextToBindingMetadata(nil, map[string]string{"key": "value"}))
+	})
+
+	t.Run("trace context is added", func(t *testing.T) {
+		ts, _ := tracestate.New(nil, tracestate.Entry{Key: "key", Value: "value"})
+		parent := trace.SpanContext{
+			TraceID:      trace.TraceID{75, 249, 47, 53, 119, 179, 77, 166, 163, 206, 146, 157, 14, 14, 71, 54},
+			SpanID:       trace.SpanID{0, 240, 103, 170, 11, 169, 2, 183},
+			TraceOptions: trace.TraceOptions(1),
+			Tracestate:   ts,
+		}
+		_, span := trace.StartSpanWithRemoteParent(context.Background(), "binding", parent)
+		defer span.End()
+
+		original := map[string]string{"key": "value"}
+		md := SpanContextToBindingMetadata(span, original)
+		assert.Equal(t, map[string]string{
+			"key":         "value",
+			"traceparent": SpanContextToW3CString(span.SpanContext()),
+			"tracestate":  "key=value",
+		}, md)
+		// the metadata of the caller is not modified.
+		assert.Equal(t, map[string]string{"key": "value"}, original)
+	})
+}
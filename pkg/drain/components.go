@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import "sync"
+
+// ComponentTracker counts the operations in flight on the instances of the components, so the previous instance of a
+// reloaded component is closed once the operations it's serving completed.
+type ComponentTracker struct {
+	lock        sync.Mutex
+	generations map[string]*generation
+}
+
+// generation counts the operations in flight on an instance of a component.
+type generation struct {
+	inFlight int
+	retired  bool
+	// drained is closed once the generation is retired and its operations completed.
+	drained chan struct{}
+}
+
+// NewComponentTracker returns a tracker without operations in flight.
+func NewComponentTracker() *ComponentTracker {
+	return &ComponentTracker{
+		generations: map[string]*generation{},
+	}
+}
+
+// Components is the tracker of the operations on the components of the sidecar.
+var Components = NewComponentTracker()
+
+// Track starts tracking an operation on the current instance of the named component, and returns the function to call
+// once it completes.
+func (t *ComponentTracker) Track(name string) func() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	g := t.generations[name]
+	if g == nil {
+		g = &generation{drained: make(chan struct{})}
+		t.generations[name] = g
+	}
+	g.inFlight++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.lock.Lock()
+			defer t.lock.Unlock()
+
+			g.inFlight--
+			if g.retired && g.inFlight == 0 {
+				close(g.drained)
+			}
+		})
+	}
+}
+
+// Retire tracks the operations started from now on the new instance of the named component, and returns a channel
+// closed once the operations in flight on the previous instance completed.
+func (t *ComponentTracker) Retire(name string) <-chan struct{} {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	g := t.generations[name]
+	delete(t.generations, name)
+	if g == nil {
+		g = &generation{drained: make(chan struct{})}
+	}
+	g.retired = true
+	if g.inFlight == 0 {
+		close(g.drained)
+	}
+	return g.drained
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestComponentTracker(t *testing.T) {
+	t.Run("retire without operations", func(t *testing.T) {
+		tracker := NewComponentTracker()
+		assert.True(t, isClosed(tracker.Retire("store")))
+	})
+
+	t.Run("retire waits for the operations in flight", func(t *testing.T) {
+		tracker := NewComponentTracker()
+		first := tracker.Track("store")
+		second := tracker.Track("store")
+		other := tracker.Track("other")
+		defer other()
+
+		drained := tracker.Retire("store")
+		assert.False(t, isClosed(drained))
+
+		// The operations on the new instance don't hold the previous one.
+		next := tracker.Track("store")
+		defer next()
+
+		first()
+		// Calling done twice does not count the operation twice.
+		first()
+		assert.False(t, isClosed(drained))
+		second()
+		assert.True(t, isClosed(drained))
+	})
+
+	t.Run("retire twice", func(t *testing.T) {
+		tracker := NewComponentTracker()
+		done := tracker.Track("store")
+		drained := tracker.Retire("store")
+		assert.True(t, isClosed(tracker.Retire("store")))
+		done()
+		assert.True(t, isClosed(drained))
+	})
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package drain tracks the work in flight in the sidecar, so that a graceful shutdown stops accepting new work and
+// waits for the work in flight to complete before the sidecar exits.
+package drain
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Kind is a kind of work tracked by the tracker.
+type Kind string
+
+const (
+	// Invocation is a service invocation, received from another sidecar or sent by the app.
+	Invocation Kind = "invocation"
+	// PubSub is the delivery of an event of a topic to the app.
+	PubSub Kind = "pubsub"
+	// Actor is a call to an actor, received from another sidecar or sent by the app.
+	Actor Kind = "actor"
+)
+
+const (
+	// StatusDraining is the status of a drain waiting for the work in flight.
+	StatusDraining = "draining"
+	// StatusDrained is the status of a drain once no work is in flight.
+	StatusDrained = "drained"
+	// StatusTimeout is the status of a drain which timed out with work still in flight.
+	StatusTimeout = "timeout"
+)
+
+// Progress is the progress of a drain.
+type Progress struct {
+	Status   string       `json:"status"`
+	InFlight map[Kind]int `json:"inFlight"`
+	Elapsed  string       `json:"elapsed"`
+}
+
+// Tracker counts the work in flight by kind. Once draining, it rejects the new work started with Begin.
+type Tracker struct {
+	lock     sync.Mutex
+	draining bool
+	inFlight map[Kind]int
+	// changed is closed and replaced whenever the work in flight completes.
+	changed chan struct{}
+}
+
+// NewTracker returns a tracker without work in flight.
+func NewTracker() *Tracker {
+	return &Tracker{
+		inFlight: map[Kind]int{},
+		changed:  make(chan struct{}),
+	}
+}
+
+// Default is the tracker of the work of the sidecar.
+var Default = NewTracker()
+
+// Begin starts tracking new work of the given kind, and returns the function to call once it completes.
+// It returns false, and does not track the work, when the tracker is draining.
+func (t *Tracker) Begin(kind Kind) (func(), bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.draining {
+		return nil, false
+	}
+	return t.start(kind), true
+}
+
+// Track starts tracking work of the given kind even when the tracker is draining, e.g. the calls the app makes to
+// complete the work in flight, and returns the function to call once it completes.
+func (t *Tracker) Track(kind Kind) func() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.start(kind)
+}
+
+func (t *Tracker) start(kind Kind) func() {
+	t.inFlight[kind]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.lock.Lock()
+			defer t.lock.Unlock()
+
+			t.inFlight[kind]--
+			close(t.changed)
+			t.changed = make(chan struct{})
+		})
+	}
+}
+
+// IsDraining returns true once Drain was called.
+func (t *Tracker) IsDraining() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.draining
+}
+
+// Drain stops accepting new work and waits for the work in flight to complete, until the timeout or ctx is done.
+// The progress is reported to report, if not nil, every interval and when the drain completes.
+func (t *Tracker) Drain(ctx context.Context, timeout, interval time.Duration, report func(Progress)) Progress {
+	start := time.Now()
+	t.lock.Lock()
+	t.draining = true
+	t.lock.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		inFlight, changed := t.snapshot()
+		progress := Progress{
+			Status:   StatusDraining,
+			InFlight: inFlight,
+			Elapsed:  time.Since(start).Round(time.Millisecond).String(),
+		}
+		if total(inFlight) == 0 {
+			progress.Status = StatusDrained
+			if report != nil {
+				report(progress)
+			}
+			return progress
+		}
+
+		select {
+		case <-changed:
+			continue
+		case <-ticker.C:
+			if report != nil {
+				report(progress)
+			}
+		case <-ctx.Done():
+			progress.Status = StatusTimeout
+			if report != nil {
+				report(progress)
+			}
+			return progress
+		}
+	}
+}
+
+// snapshot returns a copy of the counts of the work in flight, and the channel closed when they change.
+func (t *Tracker) snapshot() (map[Kind]int, <-chan struct{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	inFlight := make(map[Kind]int, 3)
+	for _, k := range []Kind{Invocation, PubSub, Actor} {
+		inFlight[k] = t.inFlight[k]
+	}
+	return inFlight, t.changed
+}
+
+func total(inFlight map[Kind]int) int {
+	n := 0
+	for _, v := range inFlight {
+		n += v
+	}
+	return n
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBegin(t *testing.T) {
+	tracker := NewTracker()
+
+	done, ok := tracker.Begin(Invocation)
+	require.True(t, ok)
+	inFlight, _ := tracker.snapshot()
+	assert.Equal(t, 1, inFlight[Invocation])
+
+	done()
+	// Calling done twice does not count the work twice.
+	done()
+	inFlight, _ = tracker.snapshot()
+	assert.Equal(t, 0, inFlight[Invocation])
+}
+
+func TestDrain(t *testing.T) {
+	t.Run("nothing in flight", func(t *testing.T) {
+		tracker := NewTracker()
+		progress := tracker.Drain(context.Background(), time.Second, time.Second, nil)
+		assert.Equal(t, StatusDrained, progress.Status)
+		assert.True(t, tracker.IsDraining())
+	})
+
+	t.Run("waits for the work in flight", func(t *testing.T) {
+		tracker := NewTracker()
+		invocationDone, _ := tracker.Begin(Invocation)
+		pubsubDone, _ := tracker.Begin(PubSub)
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			invocationDone()
+			time.Sleep(50 * time.Millisecond)
+			pubsubDone()
+		}()
+
+		var reports []Progress
+		progress := tracker.Drain(context.Background(), 5*time.Second, 20*time.Millisecond, func(p Progress) {
+			reports = append(reports, p)
+		})
+		assert.Equal(t, StatusDrained, progress.Status)
+		assert.Equal(t, map[Kind]int{Invocation: 0, PubSub: 0, Actor: 0}, progress.InFlight)
+		require.True(t, len(reports) > 1)
+		assert.Equal(t, StatusDraining, reports[0].Status)
+		assert.Equal(t, StatusDrained, reports[len(reports)-1].Status)
+	})
+
+	t.Run("rejects new work", func(t *testing.T) {
+		tracker := NewTracker()
+		tracker.Drain(context.Background(), time.Second, time.Second, nil)
+
+		_, ok := tracker.Begin(PubSub)
+		assert.False(t, ok)
+
+		done := tracker.Track(Invocation)
+		inFlight, _ := tracker.snapshot()
+		assert.Equal(t, 1, inFlight[Invocation])
+		done()
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		tracker := NewTracker()
+		done, _ := tracker.Begin(Actor)
+		defer done()
+
+		progress := tracker.Drain(context.Background(), 50*time.Millisecond, time.Second, nil)
+		assert.Equal(t, StatusTimeout, progress.Status)
+		assert.Equal(t, 1, progress.InFlight[Actor])
+	})
+}
@@ -0,0 +1,459 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+// The manifests encrypted with age (https://age-encryption.org) to X25519 recipients can be decrypted at load time,
+// so that they're produced with the age tooling:
+//
+//	age-keygen -o key.txt
+//	age -r <recipient of key.txt> -o statestore.yaml.age statestore.yaml
+//
+// The identity, i.e. the content of key.txt, is read from the environment, from a file, or from its ciphertext
+// encrypted with an AWS KMS key, e.g. with aws kms encrypt --key-id <key> --plaintext fileb://key.txt.
+const (
+	// AgeManifestExtension is the file extension of the manifests encrypted with age, e.g. statestore.yaml.age.
+	AgeManifestExtension = ".age"
+	// ManifestAgeIdentityEnvVar is the environment variable holding the age identities of the encrypted manifests.
+	ManifestAgeIdentityEnvVar = "DAPR_MANIFEST_AGE_IDENTITY"
+	// ManifestAgeIdentityFileEnvVar is the environment variable holding the path of the file of the age identities.
+	ManifestAgeIdentityFileEnvVar = "DAPR_MANIFEST_AGE_IDENTITY_FILE"
+	// ManifestAgeIdentityKMSEnvVar is the environment variable holding the base64 encoded ciphertext of the age
+	// identities, encrypted with an AWS KMS key. The AWS credentials and region are loaded from the environment.
+	ManifestAgeIdentityKMSEnvVar = "DAPR_MANIFEST_AGE_IDENTITY_KMS"
+
+	ageIntro          = "age-encryption.org/v1"
+	ageStanzaPrefix   = "->"
+	ageFooterPrefix   = "---"
+	ageX25519Type     = "X25519"
+	ageX25519Label    = "age-encryption.org/v1/X25519"
+	ageIdentityPrefix = "AGE-SECRET-KEY-"
+	ageRecipientHRP   = "age"
+	ageFileKeySize    = 16
+	ageNonceSize      = 16
+	ageChunkSize      = 64 * 1024
+	ageColumnsPerLine = 64
+)
+
+var ageBase64 = base64.RawStdEncoding.Strict()
+
+// kmsDecrypt decrypts a ciphertext with AWS KMS.
+var kmsDecrypt = func(ciphertext []byte) ([]byte, error) {
+	sess, err := session.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	out, err := kms.New(sess).Decrypt(&kms.DecryptInput{CiphertextBlob: ciphertext})
+	if err != nil {
+		return nil, err
+	}
+	return out.Plaintext, nil
+}
+
+// AgeIdentity is an age X25519 identity, decrypting the manifests encrypted to its recipient.
+type AgeIdentity struct {
+	secretKey []byte
+	publicKey []byte
+}
+
+// ParseAgeIdentities parses the age identities of a key file, one AGE-SECRET-KEY-1 identity per line.
+// The empty lines and the comments starting with # are skipped.
+func ParseAgeIdentities(keys string) ([]AgeIdentity, error) {
+	var identities []AgeIdentity
+	for _, line := range strings.Split(keys, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		hrp, key, err := bech32Decode(line)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid age identity")
+		}
+		if !strings.EqualFold(hrp, ageIdentityPrefix) || len(key) != curve25519.ScalarSize {
+			return nil, errors.New("invalid age identity: not an X25519 identity")
+		}
+		publicKey, err := curve25519.X25519(key, curve25519.Basepoint)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid age identity")
+		}
+		identities = append(identities, AgeIdentity{secretKey: key, publicKey: publicKey})
+	}
+	if len(identities) == 0 {
+		return nil, errors.New("no age identity found")
+	}
+	return identities, nil
+}
+
+// NewAgeIdentity generates a new age X25519 identity.
+func NewAgeIdentity() (AgeIdentity, error) {
+	secretKey := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(secretKey); err != nil {
+		return AgeIdentity{}, err
+	}
+	publicKey, err := curve25519.X25519(secretKey, curve25519.Basepoint)
+	if err != nil {
+		return AgeIdentity{}, err
+	}
+	return AgeIdentity{secretKey: secretKey, publicKey: publicKey}, nil
+}
+
+// String returns the AGE-SECRET-KEY-1 encoding of the identity.
+func (i AgeIdentity) String() string {
+	return strings.ToUpper(bech32Encode(ageIdentityPrefix, i.secretKey))
+}
+
+// Recipient returns the age1 recipient encrypting the manifests for the identity.
+func (i AgeIdentity) Recipient() string {
+	return bech32Encode(ageRecipientHRP, i.publicKey)
+}
+
+// ManifestAgeIdentities returns the age identities of the encrypted manifests configured in the environment.
+func ManifestAgeIdentities() ([]AgeIdentity, error) {
+	if keys := os.Getenv(ManifestAgeIdentityEnvVar); strings.TrimSpace(keys) != "" {
+		return ParseAgeIdentities(keys)
+	}
+
+	if path := os.Getenv(ManifestAgeIdentityFileEnvVar); path != "" {
+		keys, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading the age identity file")
+		}
+		return ParseAgeIdentities(string(keys))
+	}
+
+	if encoded := strings.TrimSpace(os.Getenv(ManifestAgeIdentityKMSEnvVar)); encoded != "" {
+		ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error decoding %s", ManifestAgeIdentityKMSEnvVar)
+		}
+		keys, err := kmsDecrypt(ciphertext)
+		if err != nil {
+			return nil, errors.Wrap(err, "error decrypting the age identity with AWS KMS")
+		}
+		return ParseAgeIdentities(string(keys))
+	}
+
+	return nil, errors.Errorf("none of %s, %s or %s is set", ManifestAgeIdentityEnvVar, ManifestAgeIdentityFileEnvVar, ManifestAgeIdentityKMSEnvVar)
+}
+
+// ageStanza is a recipient stanza of the header of an age file.
+type ageStanza struct {
+	args []string
+	body []byte
+}
+
+// EncryptAgeManifest encrypts a manifest with age for the X25519 recipients, e.g. age1...
+func EncryptAgeManifest(manifest []byte, recipients ...string) ([]byte, error) {
+	if len(recipients) == 0 {
+		return nil, errors.New("no age recipient")
+	}
+
+	fileKey := make([]byte, ageFileKeySize)
+	if _, err := rand.Read(fileKey); err != nil {
+		return nil, err
+	}
+
+	stanzas := make([]ageStanza, 0, len(recipients))
+	for _, r := range recipients {
+		hrp, publicKey, err := bech32Decode(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid age recipient %s", r)
+		}
+		if hrp != ageRecipientHRP || len(publicKey) != curve25519.PointSize {
+			return nil, errors.Errorf("invalid age recipient %s: not an X25519 recipient", r)
+		}
+		stanza, err := ageWrapFileKey(fileKey, publicKey)
+		if err != nil {
+			return nil, err
+		}
+		stanzas = append(stanzas, stanza)
+	}
+
+	var buf bytes.Buffer
+	header := ageHeaderWithoutMAC(stanzas)
+	mac, err := ageHeaderMAC(fileKey, header)
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(header)
+	buf.WriteString(" " + ageBase64.EncodeToString(mac) + "\n")
+
+	nonce := make([]byte, ageNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	buf.Write(nonce)
+
+	aead, err := chacha20poly1305.New(ageDeriveKey(fileKey, nonce, "payload"))
+	if err != nil {
+		return nil, err
+	}
+	chunkNonce := make([]byte, chacha20poly1305.NonceSize)
+	for i := 0; ; i++ {
+		end := (i + 1) * ageChunkSize
+		last := end >= len(manifest)
+		if last {
+			end = len(manifest)
+		}
+		ageSetChunkNonce(chunkNonce, uint64(i), last)
+		buf.Write(aead.Seal(nil, chunkNonce, manifest[i*ageChunkSize:end], nil))
+		if last {
+			break
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// DecryptAgeManifest decrypts a manifest encrypted with age with one of the identities.
+func DecryptAgeManifest(encrypted []byte, identities []AgeIdentity) ([]byte, error) {
+	stanzas, header, mac, payload, err := parseAgeHeader(encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	var fileKey []byte
+	for _, s := range stanzas {
+		for _, i := range identities {
+			if fileKey, err = i.unwrapFileKey(s); err != nil {
+				return nil, err
+			}
+			if fileKey != nil {
+				break
+			}
+		}
+		if fileKey != nil {
+			break
+		}
+	}
+	if fileKey == nil {
+		return nil, errors.New("error decrypting manifest: no identity matches a recipient of the file")
+	}
+
+	expectedMAC, err := ageHeaderMAC(fileKey, header)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(mac, expectedMAC) {
+		return nil, errors.New("error decrypting manifest: invalid header mac")
+	}
+
+	if len(payload) < ageNonceSize {
+		return nil, errors.New("error decrypting manifest: invalid ciphertext")
+	}
+	aead, err := chacha20poly1305.New(ageDeriveKey(fileKey, payload[:ageNonceSize], "payload"))
+	if err != nil {
+		return nil, err
+	}
+	payload = payload[ageNonceSize:]
+
+	manifest := make([]byte, 0, len(payload))
+	chunkNonce := make([]byte, chacha20poly1305.NonceSize)
+	encryptedChunkSize := ageChunkSize + aead.Overhead()
+	for i := 0; ; i++ {
+		last := len(payload) <= encryptedChunkSize
+		chunk := payload
+		if !last {
+			chunk = payload[:encryptedChunkSize]
+		}
+		if last && len(chunk) == aead.Overhead() && i > 0 {
+			return nil, errors.New("error decrypting manifest: empty last chunk")
+		}
+
+		ageSetChunkNonce(chunkNonce, uint64(i), last)
+		manifest, err = aead.Open(manifest, chunkNonce, chunk, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "error decrypting manifest")
+		}
+		if last {
+			return manifest, nil
+		}
+		payload = payload[encryptedChunkSize:]
+	}
+}
+
+// unwrapFileKey returns the file key wrapped in the stanza, or nil when the stanza isn't for the identity.
+func (i AgeIdentity) unwrapFileKey(s ageStanza) ([]byte, error) {
+	if len(s.args) != 2 || s.args[0] != ageX25519Type {
+		return nil, nil
+	}
+	share, err := ageBase64.DecodeString(s.args[1])
+	if err != nil || len(share) != curve25519.PointSize {
+		return nil, errors.New("error decrypting manifest: invalid X25519 stanza")
+	}
+	if len(s.body) != ageFileKeySize+chacha20poly1305.Overhead {
+		return nil, errors.New("error decrypting manifest: invalid X25519 stanza")
+	}
+
+	sharedSecret, err := curve25519.X25519(i.secretKey, share)
+	if err != nil {
+		return nil, errors.Wrap(err, "error decrypting manifest")
+	}
+	aead, err := chacha20poly1305.New(ageDeriveKey(sharedSecret, append(append([]byte{}, share...), i.publicKey...), ageX25519Label))
+	if err != nil {
+		return nil, err
+	}
+	fileKey, err := aead.Open(nil, make([]byte, chacha20poly1305.NonceSize), s.body, nil)
+	if err != nil {
+		// The stanza is for another recipient.
+		return nil, nil
+	}
+	return fileKey, nil
+}
+
+func ageWrapFileKey(fileKey, publicKey []byte) (ageStanza, error) {
+	ephemeral := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(ephemeral); err != nil {
+		return ageStanza{}, err
+	}
+	share, err := curve25519.X25519(ephemeral, curve25519.Basepoint)
+	if err != nil {
+		return ageStanza{}, err
+	}
+	sharedSecret, err := curve25519.X25519(ephemeral, publicKey)
+	if err != nil {
+		return ageStanza{}, err
+	}
+
+	salt := append(append([]byte{}, share...), publicKey...)
+	aead, err := chacha20poly1305.New(ageDeriveKey(sharedSecret, salt, ageX25519Label))
+	if err != nil {
+		return ageStanza{}, err
+	}
+	return ageStanza{
+		args: []string{ageX25519Type, ageBase64.EncodeToString(share)},
+		body: aead.Seal(nil, make([]byte, chacha20poly1305.NonceSize), fileKey, nil),
+	}, nil
+}
+
+// parseAgeHeader parses the header of an age file, returning its stanzas, the header up to the mac, the mac and the
+// payload following the header.
+func parseAgeHeader(encrypted []byte) ([]ageStanza, []byte, []byte, []byte, error) {
+	errInvalid := errors.New("error decrypting manifest: invalid age header")
+	rest := encrypted
+	nextLine := func() (string, bool) {
+		i := bytes.IndexByte(rest, '\n')
+		if i < 0 {
+			return "", false
+		}
+		line := string(rest[:i])
+		rest = rest[i+1:]
+		return line, true
+	}
+
+	if line, ok := nextLine(); !ok || line != ageIntro {
+		return nil, nil, nil, nil, errInvalid
+	}
+
+	var stanzas []ageStanza
+	for {
+		headerLen := len(encrypted) - len(rest)
+		line, ok := nextLine()
+		if !ok {
+			return nil, nil, nil, nil, errInvalid
+		}
+
+		if strings.HasPrefix(line, ageFooterPrefix+" ") {
+			mac, err := ageBase64.DecodeString(strings.TrimPrefix(line, ageFooterPrefix+" "))
+			if err != nil {
+				return nil, nil, nil, nil, errInvalid
+			}
+			return stanzas, encrypted[:headerLen+len(ageFooterPrefix)], mac, rest, nil
+		}
+
+		args := strings.Split(line, " ")
+		if len(args) < 2 || args[0] != ageStanzaPrefix {
+			return nil, nil, nil, nil, errInvalid
+		}
+		stanza := ageStanza{args: args[1:]}
+		for {
+			line, ok := nextLine()
+			if !ok || len(line) > ageColumnsPerLine {
+				return nil, nil, nil, nil, errInvalid
+			}
+			b, err := ageBase64.DecodeString(line)
+			if err != nil {
+				return nil, nil, nil, nil, errInvalid
+			}
+			stanza.body = append(stanza.body, b...)
+			if len(line) < ageColumnsPerLine {
+				break
+			}
+		}
+		stanzas = append(stanzas, stanza)
+	}
+}
+
+func ageHeaderWithoutMAC(stanzas []ageStanza) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(ageIntro + "\n")
+	for _, s := range stanzas {
+		buf.WriteString(ageStanzaPrefix + " " + strings.Join(s.args, " ") + "\n")
+		body := ageBase64.EncodeToString(s.body)
+		for len(body) >= ageColumnsPerLine {
+			buf.WriteString(body[:ageColumnsPerLine] + "\n")
+			body = body[ageColumnsPerLine:]
+		}
+		buf.WriteString(body + "\n")
+	}
+	buf.WriteString(ageFooterPrefix)
+	return buf.Bytes()
+}
+
+func ageHeaderMAC(fileKey, header []byte) ([]byte, error) {
+	h := hmac.New(sha256.New, ageDeriveKey(fileKey, nil, "header"))
+	if _, err := h.Write(header); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+func ageDeriveKey(secret, salt []byte, info string) []byte {
+	key := make([]byte, chacha20poly1305.KeySize)
+	// Reading less than 255 blocks of the hash from the HKDF reader can't fail.
+	_, _ = io.ReadFull(hkdf.New(sha256.New, secret, salt, []byte(info)), key)
+	return key
+}
+
+// ageSetChunkNonce sets the nonce of a chunk of the payload: the big endian counter of the chunk followed by the
+// flag of the last chunk.
+func ageSetChunkNonce(nonce []byte, counter uint64, last bool) {
+	for i := range nonce {
+		nonce[i] = 0
+	}
+	for i := len(nonce) - 2; i >= 0 && counter > 0; i-- {
+		nonce[i] = byte(counter)
+		counter >>= 8
+	}
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+}
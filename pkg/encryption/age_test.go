@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAgeIdentity(t *testing.T) (string, AgeIdentity) {
+	identity, err := NewAgeIdentity()
+	require.NoError(t, err)
+	return identity.String(), identity
+}
+
+func TestBech32(t *testing.T) {
+	recipient := "age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"
+	hrp, data, err := bech32Decode(recipient)
+	require.NoError(t, err)
+	assert.Equal(t, "age", hrp)
+	assert.Len(t, data, 32)
+	assert.Equal(t, recipient, bech32Encode(hrp, data))
+
+	_, _, err = bech32Decode(recipient[:len(recipient)-1] + "q")
+	assert.Error(t, err)
+	_, _, err = bech32Decode("Age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p")
+	assert.Error(t, err)
+}
+
+func TestParseAgeIdentities(t *testing.T) {
+	key, identity := newAgeIdentity(t)
+
+	identities, err := ParseAgeIdentities("# created: 2021-12-01T10:00:00Z\n# public key: " + identity.Recipient() + "\n" + key + "\n")
+	require.NoError(t, err)
+	assert.Len(t, identities, 1)
+	assert.Equal(t, identity.Recipient(), identities[0].Recipient())
+	assert.True(t, strings.HasPrefix(key, "AGE-SECRET-KEY-1"))
+
+	_, err = ParseAgeIdentities("# no key\n")
+	assert.Error(t, err)
+	_, err = ParseAgeIdentities(identity.Recipient())
+	assert.Error(t, err)
+}
+
+func TestAgeManifest(t *testing.T) {
+	_, identity := newAgeIdentity(t)
+	_, other := newAgeIdentity(t)
+
+	for _, size := range []int{0, 100, ageChunkSize, 2*ageChunkSize + 1} {
+		manifest := make([]byte, size)
+		_, err := rand.Read(manifest)
+		require.NoError(t, err)
+
+		encrypted, err := EncryptAgeManifest(manifest, other.Recipient(), identity.Recipient())
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(encrypted), ageIntro+"\n-> X25519 "))
+
+		decrypted, err := DecryptAgeManifest(encrypted, []AgeIdentity{identity})
+		require.NoError(t, err)
+		assert.Equal(t, manifest, decrypted)
+
+		encrypted[len(encrypted)-1] ^= 1
+		_, err = DecryptAgeManifest(encrypted, []AgeIdentity{identity})
+		assert.Error(t, err)
+	}
+
+	t.Run("no matching identity", func(t *testing.T) {
+		encrypted, err := EncryptAgeManifest([]byte("kind: Component"), other.Recipient())
+		require.NoError(t, err)
+		_, err = DecryptAgeManifest(encrypted, []AgeIdentity{identity})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		_, err := DecryptAgeManifest([]byte("kind: Component"), []AgeIdentity{identity})
+		assert.Error(t, err)
+	})
+}
+
+func TestManifestAgeIdentities(t *testing.T) {
+	key, identity := newAgeIdentity(t)
+
+	t.Run("not set", func(t *testing.T) {
+		_, err := ManifestAgeIdentities()
+		assert.Error(t, err)
+	})
+
+	t.Run("from the environment", func(t *testing.T) {
+		t.Setenv(ManifestAgeIdentityEnvVar, key)
+		identities, err := ManifestAgeIdentities()
+		require.NoError(t, err)
+		assert.Equal(t, identity.Recipient(), identities[0].Recipient())
+	})
+
+	t.Run("from a file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "key.txt")
+		require.NoError(t, os.WriteFile(path, []byte(key+"\n"), 0o600))
+		t.Setenv(ManifestAgeIdentityFileEnvVar, path)
+		identities, err := ManifestAgeIdentities()
+		require.NoError(t, err)
+		assert.Equal(t, identity.Recipient(), identities[0].Recipient())
+	})
+
+	t.Run("from AWS KMS", func(t *testing.T) {
+		defer func(f func([]byte) ([]byte, error)) {
+			kmsDecrypt = f
+		}(kmsDecrypt)
+		kmsDecrypt = func(ciphertext []byte) ([]byte, error) {
+			assert.Equal(t, "ciphertext", string(ciphertext))
+			return []byte(key), nil
+		}
+
+		t.Setenv(ManifestAgeIdentityKMSEnvVar, base64.StdEncoding.EncodeToString([]byte("ciphertext")))
+		identities, err := ManifestAgeIdentities()
+		require.NoError(t, err)
+		assert.Equal(t, identity.Recipient(), identities[0].Recipient())
+	})
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The bech32 encoding (BIP 173) of the age identities and recipients, without the length limit of the BIP.
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+var bech32Generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= bech32Generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	h := []byte(strings.ToLower(hrp))
+	values := make([]byte, 0, len(h)*2+1)
+	for _, c := range h {
+		values = append(values, c>>5)
+	}
+	values = append(values, 0)
+	for _, c := range h {
+		values = append(values, c&31)
+	}
+	return values
+}
+
+// bech32ConvertBits regroups the bits of the data from groups of fromBits to groups of toBits.
+func bech32ConvertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var (
+		acc  uint32
+		bits uint
+		out  []byte
+	)
+	maxv := uint32(1)<<toBits - 1
+	for _, v := range data {
+		if uint32(v)>>fromBits != 0 {
+			return nil, errors.New("invalid data range")
+		}
+		acc = acc<<fromBits | uint32(v)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(toBits-bits)&maxv))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxv != 0 {
+		return nil, errors.New("invalid padding")
+	}
+	return out, nil
+}
+
+func bech32Encode(hrp string, data []byte) string {
+	values, _ := bech32ConvertBits(data, 8, 5, true)
+	polymod := bech32Polymod(append(append(bech32HRPExpand(hrp), values...), 0, 0, 0, 0, 0, 0)) ^ 1
+
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(hrp))
+	sb.WriteByte('1')
+	for _, v := range values {
+		sb.WriteByte(bech32Charset[v])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32Charset[(polymod>>uint(5*(5-i)))&31])
+	}
+	return sb.String()
+}
+
+// bech32Decode returns the human readable part and the data of a bech32 string.
+func bech32Decode(s string) (string, []byte, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, errors.New("mixed case")
+	}
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 1 || pos+7 > len(s) {
+		return "", nil, errors.New("separator '1' at invalid position")
+	}
+
+	hrp := s[:pos]
+	for _, c := range hrp {
+		if c < 33 || c > 126 {
+			return "", nil, errors.New("invalid character in human readable part")
+		}
+	}
+	lower := strings.ToLower(s)
+	values := make([]byte, 0, len(s)-pos-1)
+	for _, c := range lower[pos+1:] {
+		v := strings.IndexRune(bech32Charset, c)
+		if v < 0 {
+			return "", nil, errors.New("invalid character in data part")
+		}
+		values = append(values, byte(v))
+	}
+	if bech32Polymod(append(bech32HRPExpand(hrp), values...)) != 1 {
+		return "", nil, errors.New("invalid checksum")
+	}
+
+	data, err := bech32ConvertBits(values[:len(values)-6], 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+	return hrp, data, nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	b64 "encoding/base64"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ManifestKeyEnvVar is the environment variable holding the hex encoded AES key of the encrypted manifests.
+	ManifestKeyEnvVar = "DAPR_MANIFEST_ENCRYPTION_KEY"
+	// EncryptedManifestExtension is the file extension of the encrypted manifests, e.g. statestore.yaml.enc.
+	EncryptedManifestExtension = ".enc"
+)
+
+// ManifestKey returns the key of the encrypted manifests set in the environment.
+func ManifestKey() (Key, bool) {
+	key := strings.TrimSpace(os.Getenv(ManifestKeyEnvVar))
+	if key == "" {
+		return Key{}, false
+	}
+	return Key{Key: key, Name: ManifestKeyEnvVar}, true
+}
+
+// EncryptManifest encrypts a manifest with AES-GCM, returning the base64 encoded nonce and ciphertext.
+func EncryptManifest(manifest []byte, key Key) ([]byte, error) {
+	gcm, err := createCipher(key, AES256Algorithm)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid manifest encryption key")
+	}
+	key.gcm = gcm
+
+	enc, err := encrypt(manifest, key, AES256Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(b64.StdEncoding.EncodeToString(enc)), nil
+}
+
+// DecryptManifest decrypts a manifest encrypted with EncryptManifest.
+func DecryptManifest(encrypted []byte, key Key) ([]byte, error) {
+	gcm, err := createCipher(key, AES256Algorithm)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid manifest encryption key")
+	}
+
+	enc, err := b64.StdEncoding.DecodeString(strings.TrimSpace(string(encrypted)))
+	if err != nil {
+		return nil, errors.Wrap(err, "error decoding encrypted manifest")
+	}
+	nsize := gcm.NonceSize()
+	if len(enc) < nsize {
+		return nil, errors.New("error decrypting manifest: invalid ciphertext")
+	}
+
+	manifest, err := gcm.Open(nil, enc[:nsize], enc[nsize:], nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "error decrypting manifest")
+	}
+	return manifest, nil
+}
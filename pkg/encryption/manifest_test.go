@@ -0,0 +1,81 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestManifestKey(t *testing.T) Key {
+	bytes := make([]byte, 32)
+	_, err := rand.Read(bytes)
+	require.NoError(t, err)
+	return Key{Key: hex.EncodeToString(bytes)}
+}
+
+func TestManifestKey(t *testing.T) {
+	t.Run("key set", func(t *testing.T) {
+		t.Setenv(ManifestKeyEnvVar, " abcd\n")
+		key, ok := ManifestKey()
+		assert.True(t, ok)
+		assert.Equal(t, "abcd", key.Key)
+	})
+
+	t.Run("key not set", func(t *testing.T) {
+		t.Setenv(ManifestKeyEnvVar, "")
+		_, ok := ManifestKey()
+		assert.False(t, ok)
+	})
+}
+
+func TestEncryptDecryptManifest(t *testing.T) {
+	key := newTestManifestKey(t)
+	manifest := []byte("kind: Component\n")
+
+	t.Run("round trip", func(t *testing.T) {
+		encrypted, err := EncryptManifest(manifest, key)
+		require.NoError(t, err)
+		assert.NotContains(t, string(encrypted), "Component")
+
+		decrypted, err := DecryptManifest(append(encrypted, '\n'), key)
+		assert.NoError(t, err)
+		assert.Equal(t, manifest, decrypted)
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		encrypted, err := EncryptManifest(manifest, key)
+		require.NoError(t, err)
+
+		_, err = DecryptManifest(encrypted, newTestManifestKey(t))
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		_, err := EncryptManifest(manifest, Key{Key: "not-hex"})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid ciphertext", func(t *testing.T) {
+		_, err := DecryptManifest([]byte("YWJj"), key)
+		assert.Error(t, err)
+
+		_, err = DecryptManifest([]byte("not base64"), key)
+		assert.Error(t, err)
+	})
+}
@@ -17,7 +17,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/dapr/components-contrib/configuration"
@@ -27,7 +29,6 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/dapr/components-contrib/bindings"
@@ -38,24 +39,35 @@ import (
 	"github.com/dapr/dapr/pkg/acl"
 	"github.com/dapr/dapr/pkg/actors"
 	components_v1alpha "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	"github.com/dapr/dapr/pkg/apphealth"
 	"github.com/dapr/dapr/pkg/channel"
+	"github.com/dapr/dapr/pkg/chaos"
+	bindings_loader "github.com/dapr/dapr/pkg/components/bindings"
+	configuration_loader "github.com/dapr/dapr/pkg/components/configuration"
+	secretstores_loader "github.com/dapr/dapr/pkg/components/secretstores"
 	state_loader "github.com/dapr/dapr/pkg/components/state"
 	"github.com/dapr/dapr/pkg/concurrency"
 	"github.com/dapr/dapr/pkg/config"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
 	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
+	"github.com/dapr/dapr/pkg/drain"
 	"github.com/dapr/dapr/pkg/encryption"
+	"github.com/dapr/dapr/pkg/grpc/compression"
 	"github.com/dapr/dapr/pkg/messages"
 	"github.com/dapr/dapr/pkg/messaging"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
 	commonv1pb "github.com/dapr/dapr/pkg/proto/common/v1"
 	internalv1pb "github.com/dapr/dapr/pkg/proto/internals/v1"
 	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
+	"github.com/dapr/dapr/pkg/resiliency"
+	runtime_metadata "github.com/dapr/dapr/pkg/runtime/metadata"
 	runtime_pubsub "github.com/dapr/dapr/pkg/runtime/pubsub"
 )
 
 const (
 	daprHTTPStatusHeader = "dapr-http-status"
+	// bindingStreamChunkSize is the size of the chunks of streamed output binding responses.
+	bindingStreamChunkSize = 64 * 1024
 )
 
 // API is the gRPC interface for the Dapr gRPC API. It implements both the internal and external proto definitions.
@@ -68,10 +80,17 @@ type API interface {
 	PublishEvent(ctx context.Context, in *runtimev1pb.PublishEventRequest) (*emptypb.Empty, error)
 	InvokeService(ctx context.Context, in *runtimev1pb.InvokeServiceRequest) (*commonv1pb.InvokeResponse, error)
 	InvokeBinding(ctx context.Context, in *runtimev1pb.InvokeBindingRequest) (*runtimev1pb.InvokeBindingResponse, error)
+	InvokeBindingStreamAlpha1(in *runtimev1pb.InvokeBindingRequest, stream runtimev1pb.Dapr_InvokeBindingStreamAlpha1Server) error
+	InvokeBindingBulkAlpha1(ctx context.Context, in *runtimev1pb.InvokeBindingBulkRequest) (*runtimev1pb.InvokeBindingBulkResponse, error)
 	GetState(ctx context.Context, in *runtimev1pb.GetStateRequest) (*runtimev1pb.GetStateResponse, error)
 	GetBulkState(ctx context.Context, in *runtimev1pb.GetBulkStateRequest) (*runtimev1pb.GetBulkStateResponse, error)
 	GetSecret(ctx context.Context, in *runtimev1pb.GetSecretRequest) (*runtimev1pb.GetSecretResponse, error)
 	GetBulkSecret(ctx context.Context, in *runtimev1pb.GetBulkSecretRequest) (*runtimev1pb.GetBulkSecretResponse, error)
+	SubscribeSecretsAlpha1(in *runtimev1pb.SubscribeSecretsRequest, stream runtimev1pb.Dapr_SubscribeSecretsAlpha1Server) error
+	EncryptAlpha1(ctx context.Context, in *runtimev1pb.CryptoRequest) (*runtimev1pb.CryptoResponse, error)
+	DecryptAlpha1(ctx context.Context, in *runtimev1pb.CryptoRequest) (*runtimev1pb.CryptoResponse, error)
+	SignAlpha1(ctx context.Context, in *runtimev1pb.CryptoRequest) (*runtimev1pb.CryptoResponse, error)
+	VerifyAlpha1(ctx context.Context, in *runtimev1pb.CryptoRequest) (*runtimev1pb.CryptoResponse, error)
 	GetConfigurationAlpha1(ctx context.Context, in *runtimev1pb.GetConfigurationRequest) (*runtimev1pb.GetConfigurationResponse, error)
 	SubscribeConfigurationAlpha1(request *runtimev1pb.SubscribeConfigurationRequest, configurationServer runtimev1pb.Dapr_SubscribeConfigurationAlpha1Server) error
 	SaveState(ctx context.Context, in *runtimev1pb.SaveStateRequest) (*emptypb.Empty, error)
@@ -82,6 +101,7 @@ type API interface {
 	SetAppChannel(appChannel channel.AppChannel)
 	SetDirectMessaging(directMessaging messaging.DirectMessaging)
 	SetActorRuntime(actor actors.Actors)
+	SetAppHealth(appHealth *apphealth.AppHealth)
 	RegisterActorTimer(ctx context.Context, in *runtimev1pb.RegisterActorTimerRequest) (*emptypb.Empty, error)
 	UnregisterActorTimer(ctx context.Context, in *runtimev1pb.UnregisterActorTimerRequest) (*emptypb.Empty, error)
 	RegisterActorReminder(ctx context.Context, in *runtimev1pb.RegisterActorReminderRequest) (*emptypb.Empty, error)
@@ -99,25 +119,29 @@ type API interface {
 }
 
 type api struct {
-	actor                      actors.Actors
-	directMessaging            messaging.DirectMessaging
-	appChannel                 channel.AppChannel
-	stateStores                map[string]state.Store
-	transactionalStateStores   map[string]state.TransactionalStore
-	secretStores               map[string]secretstores.SecretStore
-	secretsConfiguration       map[string]config.SecretsScope
-	configurationStores        map[string]configuration.Store
-	configurationSubscribe     map[string]bool
-	configurationSubscribeLock sync.Mutex
-	pubsubAdapter              runtime_pubsub.Adapter
-	id                         string
-	sendToOutputBindingFn      func(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error)
-	tracingSpec                config.TracingSpec
-	accessControlList          *config.AccessControlList
-	appProtocol                string
-	extendedMetadata           sync.Map
-	components                 []components_v1alpha.Component
-	shutdown                   func()
+	actor                       actors.Actors
+	directMessaging             messaging.DirectMessaging
+	appChannel                  channel.AppChannel
+	appHealth                   *apphealth.AppHealth
+	stateStores                 map[string]state.Store
+	transactionalStateStores    map[string]state.TransactionalStore
+	secretStores                map[string]secretstores.SecretStore
+	secretsConfiguration        map[string]config.SecretsScope
+	configurationStores         map[string]configuration.Store
+	pubsubAdapter               runtime_pubsub.Adapter
+	id                          string
+	sendToOutputBindingFn       func(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error)
+	sendToOutputBindingStreamFn func(name string, req *bindings.InvokeRequest) (*bindings_loader.StreamingInvokeResponse, error)
+	sendToOutputBindingBulkFn   func(name string, reqs []*bindings.InvokeRequest) ([]bindings_loader.BulkInvokeResult, error)
+	tracingSpec                 config.TracingSpec
+	accessControlList           *config.AccessControlList
+	appProtocol                 string
+	enableGateway               bool
+	extendedMetadata            sync.Map
+	getComponentsFn             func() []components_v1alpha.Component
+	getMetadataDetailsFn        func() runtime_metadata.Details
+	resiliency                  *resiliency.Resiliency
+	shutdown                    func()
 }
 
 // NewAPI returns a new gRPC API.
@@ -131,10 +155,15 @@ func NewAPI(
 	directMessaging messaging.DirectMessaging,
 	actor actors.Actors,
 	sendToOutputBindingFn func(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error),
+	sendToOutputBindingStreamFn func(name string, req *bindings.InvokeRequest) (*bindings_loader.StreamingInvokeResponse, error),
+	sendToOutputBindingBulkFn func(name string, reqs []*bindings.InvokeRequest) ([]bindings_loader.BulkInvokeResult, error),
 	tracingSpec config.TracingSpec,
 	accessControlList *config.AccessControlList,
 	appProtocol string,
+	enableGateway bool,
 	getComponentsFn func() []components_v1alpha.Component,
+	getMetadataDetailsFn func() runtime_metadata.Details,
+	resiliency *resiliency.Resiliency,
 	shutdown func()) API {
 	transactionalStateStores := map[string]state.TransactionalStore{}
 	for key, store := range stateStores {
@@ -144,72 +173,156 @@ func NewAPI(
 	}
 
 	return &api{
-		directMessaging:          directMessaging,
-		actor:                    actor,
-		id:                       appID,
-		appChannel:               appChannel,
-		pubsubAdapter:            pubsubAdapter,
-		stateStores:              stateStores,
-		transactionalStateStores: transactionalStateStores,
-		secretStores:             secretStores,
-		configurationStores:      configurationStores,
-		secretsConfiguration:     secretsConfiguration,
-		sendToOutputBindingFn:    sendToOutputBindingFn,
-		tracingSpec:              tracingSpec,
-		accessControlList:        accessControlList,
-		appProtocol:              appProtocol,
-		shutdown:                 shutdown,
-		configurationSubscribe:   map[string]bool{},
+		directMessaging:             directMessaging,
+		actor:                       actor,
+		id:                          appID,
+		appChannel:                  appChannel,
+		pubsubAdapter:               pubsubAdapter,
+		stateStores:                 stateStores,
+		transactionalStateStores:    transactionalStateStores,
+		secretStores:                secretStores,
+		configurationStores:         configurationStores,
+		secretsConfiguration:        secretsConfiguration,
+		sendToOutputBindingFn:       sendToOutputBindingFn,
+		sendToOutputBindingStreamFn: sendToOutputBindingStreamFn,
+		sendToOutputBindingBulkFn:   sendToOutputBindingBulkFn,
+		tracingSpec:                 tracingSpec,
+		accessControlList:           accessControlList,
+		appProtocol:                 appProtocol,
+		enableGateway:               enableGateway,
+		getComponentsFn:             getComponentsFn,
+		getMetadataDetailsFn:        getMetadataDetailsFn,
+		resiliency:                  resiliency,
+		shutdown:                    shutdown,
 	}
 }
 
 // CallLocal is used for internal dapr to dapr calls. It is invoked by another Dapr instance with a request to the local app.
 func (a *api) CallLocal(ctx context.Context, in *internalv1pb.InternalInvokeRequest) (*internalv1pb.InternalInvokeResponse, error) {
-	if a.appChannel == nil {
-		return nil, status.Error(codes.Internal, messages.ErrChannelNotFound)
+	// Advertise the compressors this sidecar accepts so that the calling sidecar can compress the next requests.
+	grpc.SetHeader(ctx, metadata.Pairs(invokev1.AcceptEncodingHeader, strings.Join(compression.Supported(), ",")))
+
+	done, ok := drain.Default.Begin(drain.Invocation)
+	if !ok {
+		return nil, messages.Status(codes.Unavailable, messages.ErrDraining)
+	}
+	defer done()
+
+	if a.appChannel == nil && !a.enableGateway {
+		return nil, messages.Status(codes.Internal, messages.ErrChannelNotFound)
 	}
 
 	req, err := invokev1.InternalInvokeRequest(in)
 	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, messages.ErrInternalInvokeRequest, err.Error())
+		return nil, messages.Status(codes.InvalidArgument, messages.ErrInternalInvokeRequest, err.Error())
 	}
 
 	if a.accessControlList != nil {
 		// An access control policy has been specified for the app. Apply the policies.
 		operation := req.Message().Method
 		var httpVerb commonv1pb.HTTPExtension_Verb
-		// Get the http verb in case the application protocol is http
+		var query string
+		// Get the http verb and query string in case the application protocol is http
 		if a.appProtocol == config.HTTPProtocol && req.Metadata() != nil && len(req.Metadata()) > 0 {
 			httpExt := req.Message().GetHttpExtension()
 			if httpExt != nil {
 				httpVerb = httpExt.GetVerb()
+				query = httpExt.GetQuerystring()
 			}
 		}
-		callAllowed, errMsg := acl.ApplyAccessControlPolicies(ctx, operation, httpVerb, a.appProtocol, a.accessControlList)
+		callerID, err := acl.GetCallerSpiffeID(ctx, callerSpiffeID(req), a.accessControlList)
+		if err != nil {
+			// Apply the default action
+			apiServerLogger.Debugf("error getting the spiffe id of the caller: %s. applying default global policy action", err)
+		}
+		callAllowed, errMsg := acl.ApplyAccessControlPoliciesToCaller(callerID, operation, httpVerb, query, a.appProtocol, a.accessControlList)
 
 		if !callAllowed {
-			return nil, status.Errorf(codes.PermissionDenied, errMsg)
+			return nil, messages.ReasonStatus(codes.PermissionDenied, "ERR_PERMISSION_DENIED", errMsg)
+		}
+	}
+
+	if targetAppID, ok := a.gatewayTarget(req); ok {
+		// The request was sent from a remote cluster through this gateway. Forward it to the target app along with the
+		// identity of the caller, which the access control policies of the target app apply to instead of the identity
+		// of this gateway. Without it, the request can't be forwarded.
+		callerID, err := acl.GetAndParseSpiffeID(ctx)
+		if err != nil {
+			return nil, messages.Status(codes.PermissionDenied, messages.ErrGatewayCallerIdentity, targetAppID, err)
+		}
+		req.Metadata()[invokev1.CallerSpiffeIDHeader] = &internalv1pb.ListStringValue{
+			Values: []string{callerID.String()},
+		}
+
+		resp, err := a.directMessaging.Invoke(ctx, targetAppID, req)
+		if err != nil {
+			return nil, messages.Status(codes.Internal, messages.ErrDirectInvoke, targetAppID, err)
 		}
+		return resp.Proto(), nil
+	}
+
+	if a.appChannel == nil {
+		return nil, messages.Status(codes.Internal, messages.ErrChannelNotFound)
+	}
+
+	if !a.appHealth.IsHealthy() {
+		// Fail fast instead of letting the caller wait on an app that is not able to serve the request.
+		statusCode := int32(codes.Unavailable)
+		if a.appProtocol != config.GRPCProtocol {
+			statusCode = int32(invokev1.HTTPStatusFromCode(codes.Unavailable))
+		}
+		return invokev1.NewInvokeMethodResponse(statusCode, messages.ErrAppUnhealthy, nil).Proto(), nil
 	}
 
 	resp, err := a.appChannel.InvokeMethod(ctx, req)
 	if err != nil {
-		err = status.Errorf(codes.Internal, messages.ErrChannelInvoke, err)
+		err = messages.Status(codes.Internal, messages.ErrChannelInvoke, err)
 		return nil, err
 	}
 	return resp.Proto(), err
 }
 
+// gatewayTarget returns the app id a request received by a gateway must be forwarded to.
+func (a *api) gatewayTarget(req *invokev1.InvokeMethodRequest) (string, bool) {
+	if !a.enableGateway {
+		return "", false
+	}
+	v, ok := req.Metadata()[invokev1.DestinationIDHeader]
+	if !ok || len(v.GetValues()) == 0 {
+		return "", false
+	}
+	target := v.GetValues()[0]
+	if target == "" || target == a.id {
+		return "", false
+	}
+	return target, true
+}
+
+// callerSpiffeID returns the SPIFFE ID of the original caller asserted by the gateway forwarding a request.
+func callerSpiffeID(req *invokev1.InvokeMethodRequest) string {
+	v, ok := req.Metadata()[invokev1.CallerSpiffeIDHeader]
+	if !ok || len(v.GetValues()) == 0 {
+		return ""
+	}
+	return v.GetValues()[0]
+}
+
 // CallActor invokes a virtual actor.
 func (a *api) CallActor(ctx context.Context, in *internalv1pb.InternalInvokeRequest) (*internalv1pb.InternalInvokeResponse, error) {
+	done, ok := drain.Default.Begin(drain.Actor)
+	if !ok {
+		return nil, messages.Status(codes.Unavailable, messages.ErrDraining)
+	}
+	defer done()
+
 	req, err := invokev1.InternalInvokeRequest(in)
 	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, messages.ErrInternalInvokeRequest, err.Error())
+		return nil, messages.Status(codes.InvalidArgument, messages.ErrInternalInvokeRequest, err.Error())
 	}
 
 	resp, err := a.actor.Call(ctx, req)
 	if err != nil {
-		err = status.Errorf(codes.Internal, messages.ErrActorInvoke, err)
+		err = messages.Status(codes.Internal, messages.ErrActorInvoke, err)
 		return nil, err
 	}
 	return resp.Proto(), nil
@@ -217,35 +330,35 @@ func (a *api) CallActor(ctx context.Context, in *internalv1pb.InternalInvokeRequ
 
 func (a *api) PublishEvent(ctx context.Context, in *runtimev1pb.PublishEventRequest) (*emptypb.Empty, error) {
 	if a.pubsubAdapter == nil {
-		err := status.Error(codes.FailedPrecondition, messages.ErrPubsubNotConfigured)
+		err := messages.Status(codes.FailedPrecondition, messages.ErrPubsubNotConfigured)
 		apiServerLogger.Debug(err)
 		return &emptypb.Empty{}, err
 	}
 
 	pubsubName := in.PubsubName
 	if pubsubName == "" {
-		err := status.Error(codes.InvalidArgument, messages.ErrPubsubEmpty)
+		err := messages.Status(codes.InvalidArgument, messages.ErrPubsubEmpty)
 		apiServerLogger.Debug(err)
 		return &emptypb.Empty{}, err
 	}
 
 	thepubsub := a.pubsubAdapter.GetPubSub(pubsubName)
 	if thepubsub == nil {
-		err := status.Errorf(codes.InvalidArgument, messages.ErrPubsubNotFound, pubsubName)
+		err := messages.Status(codes.InvalidArgument, messages.ErrPubsubNotFound, pubsubName)
 		apiServerLogger.Debug(err)
 		return &emptypb.Empty{}, err
 	}
 
 	topic := in.Topic
 	if topic == "" {
-		err := status.Errorf(codes.InvalidArgument, messages.ErrTopicEmpty, pubsubName)
+		err := messages.Status(codes.InvalidArgument, messages.ErrTopicEmpty, pubsubName)
 		apiServerLogger.Debug(err)
 		return &emptypb.Empty{}, err
 	}
 
 	rawPayload, metaErr := contrib_metadata.IsRawPayload(in.Metadata)
 	if metaErr != nil {
-		err := status.Errorf(codes.InvalidArgument, messages.ErrMetadataGet, metaErr.Error())
+		err := messages.Status(codes.InvalidArgument, messages.ErrMetadataGet, metaErr.Error())
 		apiServerLogger.Debug(err)
 		return &emptypb.Empty{}, err
 	}
@@ -274,7 +387,7 @@ func (a *api) PublishEvent(ctx context.Context, in *runtimev1pb.PublishEventRequ
 			Pubsub:          in.PubsubName,
 		})
 		if err != nil {
-			err = status.Errorf(codes.InvalidArgument, messages.ErrPubsubCloudEventCreation, err.Error())
+			err = messages.Status(codes.InvalidArgument, messages.ErrPubsubCloudEventCreation, err.Error())
 			apiServerLogger.Debug(err)
 			return &emptypb.Empty{}, err
 		}
@@ -284,7 +397,7 @@ func (a *api) PublishEvent(ctx context.Context, in *runtimev1pb.PublishEventRequ
 
 		data, err = jsoniter.ConfigFastest.Marshal(envelope)
 		if err != nil {
-			err = status.Errorf(codes.InvalidArgument, messages.ErrPubsubCloudEventsSer, topic, pubsubName, err.Error())
+			err = messages.Status(codes.InvalidArgument, messages.ErrPubsubCloudEventsSer, topic, pubsubName, err.Error())
 			apiServerLogger.Debug(err)
 			return &emptypb.Empty{}, err
 		}
@@ -297,15 +410,15 @@ func (a *api) PublishEvent(ctx context.Context, in *runtimev1pb.PublishEventRequ
 		Metadata:   in.Metadata,
 	}
 
-	err := a.pubsubAdapter.Publish(&req)
+	err := a.pubsubAdapter.Publish(ctx, &req)
 	if err != nil {
-		nerr := status.Errorf(codes.Internal, messages.ErrPubsubPublishMessage, topic, pubsubName, err.Error())
+		nerr := messages.Status(codes.Internal, messages.ErrPubsubPublishMessage, topic, pubsubName, err.Error())
 		if errors.As(err, &runtime_pubsub.NotAllowedError{}) {
-			nerr = status.Errorf(codes.PermissionDenied, err.Error())
+			nerr = messages.ReasonStatus(codes.PermissionDenied, "ERR_PUBSUB_FORBIDDEN", err.Error())
 		}
 
 		if errors.As(err, &runtime_pubsub.NotFoundError{}) {
-			nerr = status.Errorf(codes.NotFound, err.Error())
+			nerr = messages.ReasonStatus(codes.NotFound, "ERR_PUBSUB_NOT_FOUND", err.Error())
 		}
 		apiServerLogger.Debug(nerr)
 		return &emptypb.Empty{}, nerr
@@ -321,12 +434,13 @@ func (a *api) InvokeService(ctx context.Context, in *runtimev1pb.InvokeServiceRe
 	}
 
 	if a.directMessaging == nil {
-		return nil, status.Errorf(codes.Internal, messages.ErrDirectInvokeNotReady)
+		return nil, messages.Status(codes.Internal, messages.ErrDirectInvokeNotReady)
 	}
 
+	defer drain.Default.Track(drain.Invocation)()
 	resp, err := a.directMessaging.Invoke(ctx, in.Id, req)
 	if err != nil {
-		err = status.Errorf(codes.Internal, messages.ErrDirectInvoke, in.Id, err)
+		err = messages.Status(codes.Internal, messages.ErrDirectInvoke, in.Id, err)
 		return nil, err
 	}
 
@@ -352,6 +466,16 @@ func (a *api) InvokeService(ctx context.Context, in *runtimev1pb.InvokeServiceRe
 	return resp.Message(), respError
 }
 
+// outputBindingStatus returns the gRPC error of a failed invocation of an output binding. The failed
+// initializations of the lazily initialized bindings have their own reason.
+func outputBindingStatus(name string, err error) error {
+	var initErr *bindings_loader.LazyInitError
+	if errors.As(err, &initErr) {
+		return messages.Status(codes.Unavailable, messages.ErrOutputBindingInit, name, initErr.Err.Error())
+	}
+	return messages.Status(codes.Internal, messages.ErrInvokeOutputBinding, name, err.Error())
+}
+
 func (a *api) InvokeBinding(ctx context.Context, in *runtimev1pb.InvokeBindingRequest) (*runtimev1pb.InvokeBindingResponse, error) {
 	req := &bindings.InvokeRequest{
 		Metadata:  in.Metadata,
@@ -361,10 +485,15 @@ func (a *api) InvokeBinding(ctx context.Context, in *runtimev1pb.InvokeBindingRe
 		req.Data = in.Data
 	}
 
+	op := diag.StartBindingOperation(ctx, a.tracingSpec, in.Name, in.Operation)
+	// pass the trace context to output binding in metadata
+	req.Metadata = diag.SpanContextToBindingMetadata(op.Span(), req.Metadata)
+
 	r := &runtimev1pb.InvokeBindingResponse{}
 	resp, err := a.sendToOutputBindingFn(in.Name, req)
+	op.End(err)
 	if err != nil {
-		err = status.Errorf(codes.Internal, messages.ErrInvokeOutputBinding, in.Name, err.Error())
+		err = outputBindingStatus(in.Name, err)
 		apiServerLogger.Debug(err)
 		return r, err
 	}
@@ -376,6 +505,91 @@ func (a *api) InvokeBinding(ctx context.Context, in *runtimev1pb.InvokeBindingRe
 	return r, nil
 }
 
+func (a *api) InvokeBindingStreamAlpha1(in *runtimev1pb.InvokeBindingRequest, stream runtimev1pb.Dapr_InvokeBindingStreamAlpha1Server) error {
+	req := &bindings.InvokeRequest{
+		Data:      in.Data,
+		Metadata:  in.Metadata,
+		Operation: bindings.OperationKind(in.Operation),
+	}
+
+	op := diag.StartBindingOperation(stream.Context(), a.tracingSpec, in.Name, in.Operation)
+	req.Metadata = diag.SpanContextToBindingMetadata(op.Span(), req.Metadata)
+
+	resp, err := a.sendToOutputBindingStreamFn(in.Name, req)
+	op.End(err)
+	if err != nil {
+		err = outputBindingStatus(in.Name, err)
+		apiServerLogger.Debug(err)
+		return err
+	}
+	if resp == nil {
+		return stream.Send(&runtimev1pb.InvokeBindingResponse{})
+	}
+	defer resp.Data.Close()
+
+	// the metadata is only sent with the first chunk, which is sent even if the response has no data.
+	md := resp.Metadata
+	sent := false
+	buf := make([]byte, bindingStreamChunkSize)
+	for {
+		n, rErr := resp.Data.Read(buf)
+		if n > 0 || (rErr == io.EOF && !sent) {
+			chunk := &runtimev1pb.InvokeBindingResponse{
+				Data:     append([]byte(nil), buf[:n]...),
+				Metadata: md,
+			}
+			if err = stream.Send(chunk); err != nil {
+				return err
+			}
+			md = nil
+			sent = true
+		}
+		if rErr == io.EOF {
+			return nil
+		}
+		if rErr != nil {
+			err = messages.Status(codes.Internal, messages.ErrInvokeOutputBinding, in.Name, rErr.Error())
+			apiServerLogger.Debug(err)
+			return err
+		}
+	}
+}
+
+func (a *api) InvokeBindingBulkAlpha1(ctx context.Context, in *runtimev1pb.InvokeBindingBulkRequest) (*runtimev1pb.InvokeBindingBulkResponse, error) {
+	op := diag.StartBindingOperation(ctx, a.tracingSpec, in.Name, "bulk")
+	reqs := make([]*bindings.InvokeRequest, len(in.Requests))
+	for i, r := range in.Requests {
+		reqs[i] = &bindings.InvokeRequest{
+			Data:      r.Data,
+			Metadata:  diag.SpanContextToBindingMetadata(op.Span(), r.Metadata),
+			Operation: bindings.OperationKind(r.Operation),
+		}
+	}
+
+	results, err := a.sendToOutputBindingBulkFn(in.Name, reqs)
+	op.End(err)
+	if err != nil {
+		err = outputBindingStatus(in.Name, err)
+		apiServerLogger.Debug(err)
+		return &runtimev1pb.InvokeBindingBulkResponse{}, err
+	}
+
+	resp := &runtimev1pb.InvokeBindingBulkResponse{
+		Results: make([]*runtimev1pb.InvokeBindingBulkResponseItem, len(results)),
+	}
+	for i, r := range results {
+		item := &runtimev1pb.InvokeBindingBulkResponseItem{}
+		if r.Error != nil {
+			item.Error = r.Error.Error()
+		} else if r.Response != nil {
+			item.Data = r.Response.Data
+			item.Metadata = r.Response.Metadata
+		}
+		resp.Results[i] = item
+	}
+	return resp, nil
+}
+
 func (a *api) GetBulkState(ctx context.Context, in *runtimev1pb.GetBulkStateRequest) (*runtimev1pb.GetBulkStateResponse, error) {
 	store, err := a.getStateStore(in.StoreName)
 	if err != nil {
@@ -401,10 +615,19 @@ func (a *api) GetBulkState(ctx context.Context, in *runtimev1pb.GetBulkStateRequ
 		}
 		reqs[i] = r
 	}
-	bulkGet, responses, err := store.BulkGet(reqs)
+	op := diag.StartStateOperation(ctx, a.tracingSpec, in.StoreName, "bulkGet", len(reqs))
+	var bulkGet bool
+	var responses []state.BulkGetResponse
+	if err = chaos.Inject(ctx, chaos.State, in.StoreName); err == nil {
+		err = a.resiliency.ComponentOperation(in.StoreName, func() (err error) {
+			bulkGet, responses, err = store.BulkGet(reqs)
+			return err
+		})
+	}
 
 	// if store supports bulk get
-	if bulkGet {
+	if bulkGet || err != nil {
+		op.End(err)
 		if err != nil {
 			return bulkResp, err
 		}
@@ -428,7 +651,11 @@ func (a *api) GetBulkState(ctx context.Context, in *runtimev1pb.GetBulkStateRequ
 	for i := 0; i < n; i++ {
 		fn := func(param interface{}) {
 			req := param.(*state.GetRequest)
-			r, err := store.Get(req)
+			var r *state.GetResponse
+			err := a.resiliency.ComponentOperation(in.StoreName, func() (err error) {
+				r, err = store.Get(req)
+				return err
+			})
 			item := &runtimev1pb.BulkStateItem{
 				Key: state_loader.GetOriginalStateKey(req.Key),
 			}
@@ -444,6 +671,7 @@ func (a *api) GetBulkState(ctx context.Context, in *runtimev1pb.GetBulkStateRequ
 		limiter.Execute(fn, &reqs[i])
 	}
 	limiter.Wait()
+	op.End(nil)
 	// collect result
 	resultLen := len(resultCh)
 	for i := 0; i < resultLen; i++ {
@@ -468,18 +696,17 @@ func (a *api) GetBulkState(ctx context.Context, in *runtimev1pb.GetBulkStateRequ
 
 func (a *api) getStateStore(name string) (state.Store, error) {
 	if a.stateStores == nil || len(a.stateStores) == 0 {
-		return nil, status.Error(codes.FailedPrecondition, messages.ErrStateStoresNotConfigured)
+		return nil, messages.Status(codes.FailedPrecondition, messages.ErrStateStoresNotConfigured)
 	}
 
 	if a.stateStores[name] == nil {
-		return nil, status.Errorf(codes.InvalidArgument, messages.ErrStateStoreNotFound, name)
+		return nil, messages.Status(codes.InvalidArgument, messages.ErrStateStoreNotFound, name)
 	}
 	return a.stateStores[name], nil
 }
 
 func (a *api) GetState(ctx context.Context, in *runtimev1pb.GetStateRequest) (*runtimev1pb.GetStateResponse, error) {
-	store, err := a.getStateStore(in.StoreName)
-	if err != nil {
+	if _, err := a.getStateStore(in.StoreName); err != nil {
 		apiServerLogger.Debug(err)
 		return &runtimev1pb.GetStateResponse{}, err
 	}
@@ -495,17 +722,19 @@ func (a *api) GetState(ctx context.Context, in *runtimev1pb.GetStateRequest) (*r
 		},
 	}
 
-	getResponse, err := store.Get(&req)
+	op := diag.StartStateOperation(ctx, a.tracingSpec, in.StoreName, "get", 1)
+	getResponse, answeredBy, err := state_loader.GetWithFallback(ctx, a.stateStores, in.StoreName, in.Key, a.id, &req, a.resiliency.ComponentConcurrencyLimiter(in.StoreName), a.resiliency.ComponentFallback(in.StoreName))
+	op.End(err)
 	if err != nil {
-		err = status.Errorf(codes.Internal, messages.ErrStateGet, in.Key, in.StoreName, err.Error())
+		err = messages.Status(codes.Internal, messages.ErrStateGet, in.Key, in.StoreName, err.Error())
 		apiServerLogger.Debug(err)
 		return &runtimev1pb.GetStateResponse{}, err
 	}
 
-	if encryption.EncryptedStateStore(in.StoreName) {
-		val, err := encryption.TryDecryptValue(in.StoreName, getResponse.Data)
+	if answeredBy != "" && encryption.EncryptedStateStore(answeredBy) {
+		val, err := encryption.TryDecryptValue(answeredBy, getResponse.Data)
 		if err != nil {
-			err = status.Errorf(codes.Internal, messages.ErrStateGet, in.Key, in.StoreName, err.Error())
+			err = messages.Status(codes.Internal, messages.ErrStateGet, in.Key, in.StoreName, err.Error())
 			apiServerLogger.Debug(err)
 			return &runtimev1pb.GetStateResponse{}, err
 		}
@@ -562,7 +791,14 @@ func (a *api) SaveState(ctx context.Context, in *runtimev1pb.SaveStateRequest) (
 		reqs = append(reqs, req)
 	}
 
-	err = store.BulkSet(reqs)
+	op := diag.StartStateOperation(ctx, a.tracingSpec, in.StoreName, "set", len(reqs))
+	if err = chaos.Inject(ctx, chaos.State, in.StoreName); err == nil {
+		err = a.resiliency.ComponentOperation(in.StoreName, func() error {
+			return store.BulkSet(reqs)
+		})
+	}
+	err = state_loader.SetFallback(a.stateStores, in.StoreName, a.id, reqs, a.resiliency.ComponentFallback(in.StoreName), err)
+	op.End(err)
 	if err != nil {
 		err = a.stateErrorResponse(err, messages.ErrStateSave, in.StoreName, err.Error())
 		apiServerLogger.Debug(err)
@@ -582,28 +818,36 @@ func (a *api) QueryStateAlpha1(ctx context.Context, in *runtimev1pb.QueryStateRe
 
 	querier, ok := store.(state.Querier)
 	if !ok {
-		err = status.Errorf(codes.Unimplemented, messages.ErrNotFound, "Query")
+		err = messages.Status(codes.Unimplemented, messages.ErrNotFound, "Query")
 		apiServerLogger.Debug(err)
 		return ret, err
 	}
 
 	if encryption.EncryptedStateStore(in.StoreName) {
-		err = status.Errorf(codes.Aborted, messages.ErrStateQuery, in.GetStoreName(), "cannot query encrypted store")
+		err = messages.Status(codes.Aborted, messages.ErrStateQuery, in.GetStoreName(), "cannot query encrypted store")
 		apiServerLogger.Debug(err)
 		return ret, err
 	}
 
 	var req state.QueryRequest
 	if err = jsoniter.Unmarshal([]byte(in.GetQuery()), &req.Query); err != nil {
-		err = status.Errorf(codes.InvalidArgument, messages.ErrMalformedRequest, err.Error())
+		err = messages.Status(codes.InvalidArgument, messages.ErrMalformedRequest, err.Error())
 		apiServerLogger.Debug(err)
 		return ret, err
 	}
 	req.Metadata = in.GetMetadata()
 
-	resp, err := querier.Query(&req)
+	op := diag.StartStateOperation(ctx, a.tracingSpec, in.StoreName, "query", 0)
+	var resp *state.QueryResponse
+	if err = chaos.Inject(ctx, chaos.State, in.StoreName); err == nil {
+		err = a.resiliency.ComponentOperation(in.StoreName, func() (err error) {
+			resp, err = querier.Query(&req)
+			return err
+		})
+	}
+	op.End(err)
 	if err != nil {
-		err = status.Errorf(codes.Internal, messages.ErrStateQuery, in.GetStoreName(), err.Error())
+		err = messages.Status(codes.Internal, messages.ErrStateQuery, in.GetStoreName(), err.Error())
 		apiServerLogger.Debug(err)
 		return ret, err
 	}
@@ -629,16 +873,16 @@ func (a *api) QueryStateAlpha1(ctx context.Context, in *runtimev1pb.QueryStateRe
 func (a *api) stateErrorResponse(err error, format string, args ...interface{}) error {
 	e, ok := err.(*state.ETagError)
 	if !ok {
-		return status.Errorf(codes.Internal, format, args...)
+		return messages.Status(codes.Internal, format, args...)
 	}
 	switch e.Kind() {
 	case state.ETagMismatch:
-		return status.Errorf(codes.Aborted, format, args...)
+		return messages.Status(codes.Aborted, format, args...)
 	case state.ETagInvalid:
-		return status.Errorf(codes.InvalidArgument, format, args...)
+		return messages.Status(codes.InvalidArgument, format, args...)
 	}
 
-	return status.Errorf(codes.Internal, format, args...)
+	return messages.Status(codes.Internal, format, args...)
 }
 
 func (a *api) DeleteState(ctx context.Context, in *runtimev1pb.DeleteStateRequest) (*emptypb.Empty, error) {
@@ -666,7 +910,14 @@ func (a *api) DeleteState(ctx context.Context, in *runtimev1pb.DeleteStateReques
 		}
 	}
 
-	err = store.Delete(&req)
+	op := diag.StartStateOperation(ctx, a.tracingSpec, in.StoreName, "delete", 1)
+	if err = chaos.Inject(ctx, chaos.State, in.StoreName); err == nil {
+		err = a.resiliency.ComponentOperation(in.StoreName, func() error {
+			return store.Delete(&req)
+		})
+	}
+	err = state_loader.DeleteFallback(a.stateStores, in.StoreName, a.id, []state.DeleteRequest{req}, a.resiliency.ComponentFallback(in.StoreName), err)
+	op.End(err)
 	if err != nil {
 		err = a.stateErrorResponse(err, messages.ErrStateDelete, in.Key, err.Error())
 		apiServerLogger.Debug(err)
@@ -703,7 +954,14 @@ func (a *api) DeleteBulkState(ctx context.Context, in *runtimev1pb.DeleteBulkSta
 		}
 		reqs = append(reqs, req)
 	}
-	err = store.BulkDelete(reqs)
+	op := diag.StartStateOperation(ctx, a.tracingSpec, in.StoreName, "delete", len(reqs))
+	if err = chaos.Inject(ctx, chaos.State, in.StoreName); err == nil {
+		err = a.resiliency.ComponentOperation(in.StoreName, func() error {
+			return store.BulkDelete(reqs)
+		})
+	}
+	err = state_loader.DeleteFallback(a.stateStores, in.StoreName, a.id, reqs, a.resiliency.ComponentFallback(in.StoreName), err)
+	op.End(err)
 	if err != nil {
 		apiServerLogger.Debug(err)
 		return &emptypb.Empty{}, err
@@ -713,7 +971,7 @@ func (a *api) DeleteBulkState(ctx context.Context, in *runtimev1pb.DeleteBulkSta
 
 func (a *api) GetSecret(ctx context.Context, in *runtimev1pb.GetSecretRequest) (*runtimev1pb.GetSecretResponse, error) {
 	if a.secretStores == nil || len(a.secretStores) == 0 {
-		err := status.Error(codes.FailedPrecondition, messages.ErrSecretStoreNotConfigured)
+		err := messages.Status(codes.FailedPrecondition, messages.ErrSecretStoreNotConfigured)
 		apiServerLogger.Debug(err)
 		return &runtimev1pb.GetSecretResponse{}, err
 	}
@@ -721,13 +979,19 @@ func (a *api) GetSecret(ctx context.Context, in *runtimev1pb.GetSecretRequest) (
 	secretStoreName := in.StoreName
 
 	if a.secretStores[secretStoreName] == nil {
-		err := status.Errorf(codes.InvalidArgument, messages.ErrSecretStoreNotFound, secretStoreName)
+		err := messages.Status(codes.InvalidArgument, messages.ErrSecretStoreNotFound, secretStoreName)
+		apiServerLogger.Debug(err)
+		return &runtimev1pb.GetSecretResponse{}, err
+	}
+
+	if !a.isSecretOperationAllowed(secretStoreName, config.SecretOperationGet) {
+		err := messages.Status(codes.PermissionDenied, messages.ErrSecretOperationDenied, config.SecretOperationGet, secretStoreName)
 		apiServerLogger.Debug(err)
 		return &runtimev1pb.GetSecretResponse{}, err
 	}
 
 	if !a.isSecretAllowed(in.StoreName, in.Key) {
-		err := status.Errorf(codes.PermissionDenied, messages.ErrPermissionDenied, in.Key, in.StoreName)
+		err := messages.Status(codes.PermissionDenied, messages.ErrPermissionDenied, in.Key, in.StoreName)
 		apiServerLogger.Debug(err)
 		return &runtimev1pb.GetSecretResponse{}, err
 	}
@@ -737,9 +1001,11 @@ func (a *api) GetSecret(ctx context.Context, in *runtimev1pb.GetSecretRequest) (
 		Metadata: in.Metadata,
 	}
 
+	done := drain.Components.Track(secretStoreName)
 	getResponse, err := a.secretStores[secretStoreName].GetSecret(req)
+	done()
 	if err != nil {
-		err = status.Errorf(codes.Internal, messages.ErrSecretGet, req.Name, secretStoreName, err.Error())
+		err = messages.Status(codes.Internal, messages.ErrSecretGet, req.Name, secretStoreName, err.Error())
 		apiServerLogger.Debug(err)
 		return &runtimev1pb.GetSecretResponse{}, err
 	}
@@ -753,7 +1019,7 @@ func (a *api) GetSecret(ctx context.Context, in *runtimev1pb.GetSecretRequest) (
 
 func (a *api) GetBulkSecret(ctx context.Context, in *runtimev1pb.GetBulkSecretRequest) (*runtimev1pb.GetBulkSecretResponse, error) {
 	if a.secretStores == nil || len(a.secretStores) == 0 {
-		err := status.Error(codes.FailedPrecondition, messages.ErrSecretStoreNotConfigured)
+		err := messages.Status(codes.FailedPrecondition, messages.ErrSecretStoreNotConfigured)
 		apiServerLogger.Debug(err)
 		return &runtimev1pb.GetBulkSecretResponse{}, err
 	}
@@ -761,7 +1027,13 @@ func (a *api) GetBulkSecret(ctx context.Context, in *runtimev1pb.GetBulkSecretRe
 	secretStoreName := in.StoreName
 
 	if a.secretStores[secretStoreName] == nil {
-		err := status.Errorf(codes.InvalidArgument, messages.ErrSecretStoreNotFound, secretStoreName)
+		err := messages.Status(codes.InvalidArgument, messages.ErrSecretStoreNotFound, secretStoreName)
+		apiServerLogger.Debug(err)
+		return &runtimev1pb.GetBulkSecretResponse{}, err
+	}
+
+	if !a.isSecretOperationAllowed(secretStoreName, config.SecretOperationBulkGet) {
+		err := messages.Status(codes.PermissionDenied, messages.ErrSecretOperationDenied, config.SecretOperationBulkGet, secretStoreName)
 		apiServerLogger.Debug(err)
 		return &runtimev1pb.GetBulkSecretResponse{}, err
 	}
@@ -770,9 +1042,11 @@ func (a *api) GetBulkSecret(ctx context.Context, in *runtimev1pb.GetBulkSecretRe
 		Metadata: in.Metadata,
 	}
 
+	done := drain.Components.Track(secretStoreName)
 	getResponse, err := a.secretStores[secretStoreName].BulkGetSecret(req)
+	done()
 	if err != nil {
-		err = status.Errorf(codes.Internal, messages.ErrBulkSecretGet, secretStoreName, err.Error())
+		err = messages.Status(codes.Internal, messages.ErrBulkSecretGet, secretStoreName, err.Error())
 		apiServerLogger.Debug(err)
 		return &runtimev1pb.GetBulkSecretResponse{}, err
 	}
@@ -796,6 +1070,58 @@ func (a *api) GetBulkSecret(ctx context.Context, in *runtimev1pb.GetBulkSecretRe
 	return response, nil
 }
 
+// SubscribeSecretsAlpha1 streams the new versions of the secrets of the request until the client cancels the stream.
+func (a *api) SubscribeSecretsAlpha1(in *runtimev1pb.SubscribeSecretsRequest, stream runtimev1pb.Dapr_SubscribeSecretsAlpha1Server) error {
+	if a.secretStores == nil || len(a.secretStores) == 0 {
+		err := messages.Status(codes.FailedPrecondition, messages.ErrSecretStoreNotConfigured)
+		apiServerLogger.Debug(err)
+		return err
+	}
+
+	store := a.secretStores[in.StoreName]
+	if store == nil {
+		err := messages.Status(codes.InvalidArgument, messages.ErrSecretStoreNotFound, in.StoreName)
+		apiServerLogger.Debug(err)
+		return err
+	}
+
+	if !a.isSecretOperationAllowed(in.StoreName, config.SecretOperationGet) {
+		err := messages.Status(codes.PermissionDenied, messages.ErrSecretOperationDenied, config.SecretOperationGet, in.StoreName)
+		apiServerLogger.Debug(err)
+		return err
+	}
+
+	for _, key := range in.Keys {
+		if !a.isSecretAllowed(in.StoreName, key) {
+			err := messages.Status(codes.PermissionDenied, messages.ErrPermissionDenied, key, in.StoreName)
+			apiServerLogger.Debug(err)
+			return err
+		}
+	}
+
+	var sendLock sync.Mutex
+	ctx := stream.Context()
+	err := secretstores_loader.Watch(ctx, store, in.Keys, in.Metadata, secretstores_loader.DefaultWatchInterval, func(update secretstores_loader.SecretUpdate) {
+		sendLock.Lock()
+		defer sendLock.Unlock()
+		if err := stream.Send(&runtimev1pb.SubscribeSecretsResponse{
+			Key:     update.Key,
+			Data:    update.Data,
+			Version: update.Version,
+		}); err != nil {
+			apiServerLogger.Debug(err)
+		}
+	})
+	if err != nil {
+		err = messages.Status(codes.Internal, messages.ErrSecretSubscribe, in.Keys, in.StoreName, err)
+		apiServerLogger.Debug(err)
+		return err
+	}
+
+	<-ctx.Done()
+	return nil
+}
+
 func extractEtag(req *commonv1pb.StateItem) (bool, string) {
 	if req.Etag != nil {
 		return true, req.Etag.Value
@@ -805,7 +1131,7 @@ func extractEtag(req *commonv1pb.StateItem) (bool, string) {
 
 func (a *api) ExecuteStateTransaction(ctx context.Context, in *runtimev1pb.ExecuteStateTransactionRequest) (*emptypb.Empty, error) {
 	if a.stateStores == nil || len(a.stateStores) == 0 {
-		err := status.Error(codes.FailedPrecondition, messages.ErrStateStoresNotConfigured)
+		err := messages.Status(codes.FailedPrecondition, messages.ErrStateStoresNotConfigured)
 		apiServerLogger.Debug(err)
 		return &emptypb.Empty{}, err
 	}
@@ -813,14 +1139,14 @@ func (a *api) ExecuteStateTransaction(ctx context.Context, in *runtimev1pb.Execu
 	storeName := in.StoreName
 
 	if a.stateStores[storeName] == nil {
-		err := status.Errorf(codes.InvalidArgument, messages.ErrStateStoreNotFound, storeName)
+		err := messages.Status(codes.InvalidArgument, messages.ErrStateStoreNotFound, storeName)
 		apiServerLogger.Debug(err)
 		return &emptypb.Empty{}, err
 	}
 
 	transactionalStore, ok := a.transactionalStateStores[storeName]
 	if !ok {
-		err := status.Errorf(codes.Unimplemented, messages.ErrStateStoreNotSupported, storeName)
+		err := messages.Status(codes.Unimplemented, messages.ErrStateStoreNotSupported, storeName)
 		apiServerLogger.Debug(err)
 		return &emptypb.Empty{}, err
 	}
@@ -883,7 +1209,7 @@ func (a *api) ExecuteStateTransaction(ctx context.Context, in *runtimev1pb.Execu
 			}
 
 		default:
-			err := status.Errorf(codes.Unimplemented, messages.ErrNotSupportedStateOperation, inputReq.OperationType)
+			err := messages.Status(codes.Unimplemented, messages.ErrNotSupportedStateOperation, inputReq.OperationType)
 			apiServerLogger.Debug(err)
 			return &emptypb.Empty{}, err
 		}
@@ -898,7 +1224,7 @@ func (a *api) ExecuteStateTransaction(ctx context.Context, in *runtimev1pb.Execu
 				data := []byte(fmt.Sprintf("%v", req.Value))
 				val, err := encryption.TryEncryptValue(storeName, data)
 				if err != nil {
-					err = status.Errorf(codes.Internal, messages.ErrStateTransaction, err.Error())
+					err = messages.Status(codes.Internal, messages.ErrStateTransaction, err.Error())
 					apiServerLogger.Debug(err)
 					return &emptypb.Empty{}, err
 				}
@@ -909,12 +1235,19 @@ func (a *api) ExecuteStateTransaction(ctx context.Context, in *runtimev1pb.Execu
 		}
 	}
 
-	err := transactionalStore.Multi(&state.TransactionalStateRequest{
-		Operations: operations,
-		Metadata:   in.Metadata,
-	})
+	op := diag.StartStateOperation(ctx, a.tracingSpec, storeName, "transaction", len(operations))
+	err := chaos.Inject(ctx, chaos.State, storeName)
+	if err == nil {
+		err = a.resiliency.ComponentOperation(storeName, func() error {
+			return transactionalStore.Multi(&state.TransactionalStateRequest{
+				Operations: operations,
+				Metadata:   in.Metadata,
+			})
+		})
+	}
+	op.End(err)
 	if err != nil {
-		err = status.Errorf(codes.Internal, messages.ErrStateTransaction, err.Error())
+		err = messages.Status(codes.Internal, messages.ErrStateTransaction, err.Error())
 		apiServerLogger.Debug(err)
 		return &emptypb.Empty{}, err
 	}
@@ -923,7 +1256,7 @@ func (a *api) ExecuteStateTransaction(ctx context.Context, in *runtimev1pb.Execu
 
 func (a *api) RegisterActorTimer(ctx context.Context, in *runtimev1pb.RegisterActorTimerRequest) (*emptypb.Empty, error) {
 	if a.actor == nil {
-		err := status.Errorf(codes.Internal, messages.ErrActorRuntimeNotFound)
+		err := messages.Status(codes.Internal, messages.ErrActorRuntimeNotFound)
 		apiServerLogger.Debug(err)
 		return &emptypb.Empty{}, err
 	}
@@ -947,7 +1280,7 @@ func (a *api) RegisterActorTimer(ctx context.Context, in *runtimev1pb.RegisterAc
 
 func (a *api) UnregisterActorTimer(ctx context.Context, in *runtimev1pb.UnregisterActorTimerRequest) (*emptypb.Empty, error) {
 	if a.actor == nil {
-		err := status.Errorf(codes.Internal, messages.ErrActorRuntimeNotFound)
+		err := messages.Status(codes.Internal, messages.ErrActorRuntimeNotFound)
 		apiServerLogger.Debug(err)
 		return &emptypb.Empty{}, err
 	}
@@ -964,7 +1297,7 @@ func (a *api) UnregisterActorTimer(ctx context.Context, in *runtimev1pb.Unregist
 
 func (a *api) RegisterActorReminder(ctx context.Context, in *runtimev1pb.RegisterActorReminderRequest) (*emptypb.Empty, error) {
 	if a.actor == nil {
-		err := status.Errorf(codes.Internal, messages.ErrActorRuntimeNotFound)
+		err := messages.Status(codes.Internal, messages.ErrActorRuntimeNotFound)
 		apiServerLogger.Debug(err)
 		return &emptypb.Empty{}, err
 	}
@@ -987,7 +1320,7 @@ func (a *api) RegisterActorReminder(ctx context.Context, in *runtimev1pb.Registe
 
 func (a *api) UnregisterActorReminder(ctx context.Context, in *runtimev1pb.UnregisterActorReminderRequest) (*emptypb.Empty, error) {
 	if a.actor == nil {
-		err := status.Errorf(codes.Internal, messages.ErrActorRuntimeNotFound)
+		err := messages.Status(codes.Internal, messages.ErrActorRuntimeNotFound)
 		apiServerLogger.Debug(err)
 		return &emptypb.Empty{}, err
 	}
@@ -1004,7 +1337,7 @@ func (a *api) UnregisterActorReminder(ctx context.Context, in *runtimev1pb.Unreg
 
 func (a *api) RenameActorReminder(ctx context.Context, in *runtimev1pb.RenameActorReminderRequest) (*emptypb.Empty, error) {
 	if a.actor == nil {
-		err := status.Errorf(codes.Internal, messages.ErrActorRuntimeNotFound)
+		err := messages.Status(codes.Internal, messages.ErrActorRuntimeNotFound)
 		apiServerLogger.Debug(err)
 		return &emptypb.Empty{}, err
 	}
@@ -1022,7 +1355,7 @@ func (a *api) RenameActorReminder(ctx context.Context, in *runtimev1pb.RenameAct
 
 func (a *api) GetActorState(ctx context.Context, in *runtimev1pb.GetActorStateRequest) (*runtimev1pb.GetActorStateResponse, error) {
 	if a.actor == nil {
-		err := status.Errorf(codes.Internal, messages.ErrActorRuntimeNotFound)
+		err := messages.Status(codes.Internal, messages.ErrActorRuntimeNotFound)
 		apiServerLogger.Debug(err)
 		return nil, err
 	}
@@ -1037,7 +1370,7 @@ func (a *api) GetActorState(ctx context.Context, in *runtimev1pb.GetActorStateRe
 	})
 
 	if !hosted {
-		err := status.Errorf(codes.Internal, messages.ErrActorInstanceMissing)
+		err := messages.Status(codes.Internal, messages.ErrActorInstanceMissing)
 		apiServerLogger.Debug(err)
 		return nil, err
 	}
@@ -1050,7 +1383,7 @@ func (a *api) GetActorState(ctx context.Context, in *runtimev1pb.GetActorStateRe
 
 	resp, err := a.actor.GetState(ctx, &req)
 	if err != nil {
-		err = status.Errorf(codes.Internal, fmt.Sprintf(messages.ErrActorStateGet, err))
+		err = messages.Status(codes.Internal, messages.ErrActorStateGet, err)
 		apiServerLogger.Debug(err)
 		return nil, err
 	}
@@ -1062,7 +1395,7 @@ func (a *api) GetActorState(ctx context.Context, in *runtimev1pb.GetActorStateRe
 
 func (a *api) ExecuteActorStateTransaction(ctx context.Context, in *runtimev1pb.ExecuteActorStateTransactionRequest) (*emptypb.Empty, error) {
 	if a.actor == nil {
-		err := status.Errorf(codes.Internal, messages.ErrActorRuntimeNotFound)
+		err := messages.Status(codes.Internal, messages.ErrActorRuntimeNotFound)
 		apiServerLogger.Debug(err)
 		return &emptypb.Empty{}, err
 	}
@@ -1097,7 +1430,7 @@ func (a *api) ExecuteActorStateTransaction(ctx context.Context, in *runtimev1pb.
 			}
 
 		default:
-			err := status.Errorf(codes.Unimplemented, messages.ErrNotSupportedStateOperation, op.OperationType)
+			err := messages.Status(codes.Unimplemented, messages.ErrNotSupportedStateOperation, op.OperationType)
 			apiServerLogger.Debug(err)
 			return &emptypb.Empty{}, err
 		}
@@ -1111,7 +1444,7 @@ func (a *api) ExecuteActorStateTransaction(ctx context.Context, in *runtimev1pb.
 	})
 
 	if !hosted {
-		err := status.Errorf(codes.Internal, messages.ErrActorInstanceMissing)
+		err := messages.Status(codes.Internal, messages.ErrActorInstanceMissing)
 		apiServerLogger.Debug(err)
 		return &emptypb.Empty{}, err
 	}
@@ -1124,7 +1457,7 @@ func (a *api) ExecuteActorStateTransaction(ctx context.Context, in *runtimev1pb.
 
 	err := a.actor.TransactionalStateOperation(ctx, &req)
 	if err != nil {
-		err = status.Errorf(codes.Internal, fmt.Sprintf(messages.ErrActorStateTransactionSave, err))
+		err = messages.Status(codes.Internal, messages.ErrActorStateTransactionSave, err)
 		apiServerLogger.Debug(err)
 		return &emptypb.Empty{}, err
 	}
@@ -1134,7 +1467,7 @@ func (a *api) ExecuteActorStateTransaction(ctx context.Context, in *runtimev1pb.
 
 func (a *api) InvokeActor(ctx context.Context, in *runtimev1pb.InvokeActorRequest) (*runtimev1pb.InvokeActorResponse, error) {
 	if a.actor == nil {
-		err := status.Errorf(codes.Internal, messages.ErrActorRuntimeNotFound)
+		err := messages.Status(codes.Internal, messages.ErrActorRuntimeNotFound)
 		apiServerLogger.Debug(err)
 		return &runtimev1pb.InvokeActorResponse{}, err
 	}
@@ -1143,9 +1476,10 @@ func (a *api) InvokeActor(ctx context.Context, in *runtimev1pb.InvokeActorReques
 	req.WithActor(in.ActorType, in.ActorId)
 	req.WithRawData(in.Data, "")
 
+	defer drain.Default.Track(drain.Actor)()
 	resp, err := a.actor.Call(context.TODO(), req)
 	if err != nil {
-		err = status.Errorf(codes.Internal, messages.ErrActorInvoke, err)
+		err = messages.Status(codes.Internal, messages.ErrActorInvoke, err)
 		apiServerLogger.Debug(err)
 		return &runtimev1pb.InvokeActorResponse{}, err
 	}
@@ -1164,6 +1498,84 @@ func (a *api) isSecretAllowed(storeName, key string) bool {
 	return true
 }
 
+func (a *api) EncryptAlpha1(ctx context.Context, in *runtimev1pb.CryptoRequest) (*runtimev1pb.CryptoResponse, error) {
+	return a.crypto(secretstores_loader.OperationEncrypt, in)
+}
+
+func (a *api) DecryptAlpha1(ctx context.Context, in *runtimev1pb.CryptoRequest) (*runtimev1pb.CryptoResponse, error) {
+	return a.crypto(secretstores_loader.OperationDecrypt, in)
+}
+
+func (a *api) SignAlpha1(ctx context.Context, in *runtimev1pb.CryptoRequest) (*runtimev1pb.CryptoResponse, error) {
+	return a.crypto(secretstores_loader.OperationSign, in)
+}
+
+func (a *api) VerifyAlpha1(ctx context.Context, in *runtimev1pb.CryptoRequest) (*runtimev1pb.CryptoResponse, error) {
+	return a.crypto(secretstores_loader.OperationVerify, in)
+}
+
+// crypto performs a cryptographic operation with a key of the transit engine of a secret store.
+// The keys are subject to the secret scopes of the store.
+func (a *api) crypto(operation string, in *runtimev1pb.CryptoRequest) (*runtimev1pb.CryptoResponse, error) {
+	if a.secretStores == nil || len(a.secretStores) == 0 {
+		err := messages.Status(codes.FailedPrecondition, messages.ErrSecretStoreNotConfigured)
+		apiServerLogger.Debug(err)
+		return &runtimev1pb.CryptoResponse{}, err
+	}
+
+	secretStoreName := in.StoreName
+
+	if a.secretStores[secretStoreName] == nil {
+		err := messages.Status(codes.InvalidArgument, messages.ErrSecretStoreNotFound, secretStoreName)
+		apiServerLogger.Debug(err)
+		return &runtimev1pb.CryptoResponse{}, err
+	}
+
+	transit, ok := secretstores_loader.GetTransitSecretStore(a.secretStores[secretStoreName])
+	if !ok {
+		err := messages.Status(codes.Unimplemented, messages.ErrCryptoNotSupported, secretStoreName)
+		apiServerLogger.Debug(err)
+		return &runtimev1pb.CryptoResponse{}, err
+	}
+
+	if in.KeyName == "" {
+		err := messages.Status(codes.InvalidArgument, messages.ErrCryptoKeyNameRequired)
+		apiServerLogger.Debug(err)
+		return &runtimev1pb.CryptoResponse{}, err
+	}
+
+	if !a.isSecretAllowed(secretStoreName, in.KeyName) {
+		err := messages.Status(codes.PermissionDenied, messages.ErrPermissionDenied, in.KeyName, secretStoreName)
+		apiServerLogger.Debug(err)
+		return &runtimev1pb.CryptoResponse{}, err
+	}
+
+	resp, err := secretstores_loader.Crypto(transit, operation, secretstores_loader.CryptoRequest{
+		KeyName:   in.KeyName,
+		Data:      in.Data,
+		Signature: in.Signature,
+		Algorithm: in.Algorithm,
+		Metadata:  in.Metadata,
+	})
+	if err != nil {
+		err = messages.Status(codes.Internal, messages.ErrCryptoOperation, operation, in.KeyName, secretStoreName, err.Error())
+		apiServerLogger.Debug(err)
+		return &runtimev1pb.CryptoResponse{}, err
+	}
+
+	return &runtimev1pb.CryptoResponse{
+		Data:  resp.Data,
+		Valid: resp.Valid,
+	}, nil
+}
+
+func (a *api) isSecretOperationAllowed(storeName, operation string) bool {
+	if config, ok := a.secretsConfiguration[storeName]; ok {
+		return config.IsOperationAllowed(operation)
+	}
+	return true
+}
+
 func (a *api) SetAppChannel(appChannel channel.AppChannel) {
 	a.appChannel = appChannel
 }
@@ -1172,6 +1584,10 @@ func (a *api) SetDirectMessaging(directMessaging messaging.DirectMessaging) {
 	a.directMessaging = directMessaging
 }
 
+func (a *api) SetAppHealth(appHealth *apphealth.AppHealth) {
+	a.appHealth = appHealth
+}
+
 func (a *api) SetActorRuntime(actor actors.Actors) {
 	a.actor = actor
 }
@@ -1184,19 +1600,66 @@ func (a *api) GetMetadata(ctx context.Context, in *emptypb.Empty) (*runtimev1pb.
 		temp[key.(string)] = value.(string)
 		return true
 	})
-	registeredComponents := make([]*runtimev1pb.RegisteredComponents, 0, len(a.components))
 
-	for _, comp := range a.components {
+	var details runtime_metadata.Details
+	if a.getMetadataDetailsFn != nil {
+		details = a.getMetadataDetailsFn()
+	}
+
+	var components []components_v1alpha.Component
+	if a.getComponentsFn != nil {
+		components = a.getComponentsFn()
+	}
+	registeredComponents := make([]*runtimev1pb.RegisteredComponents, 0, len(components))
+
+	for _, comp := range components {
 		registeredComp := &runtimev1pb.RegisteredComponents{
-			Name:    comp.Name,
-			Version: comp.Spec.Version,
-			Type:    comp.Spec.Type,
+			Name:         comp.Name,
+			Version:      comp.Spec.Version,
+			Type:         comp.Spec.Type,
+			Capabilities: details.ComponentCapabilities(comp.Spec.Type, comp.Name),
 		}
 		registeredComponents = append(registeredComponents, registeredComp)
 	}
+
+	subscriptions := make([]*runtimev1pb.PubsubSubscription, 0, len(details.Subscriptions))
+	for _, s := range details.Subscriptions {
+		sub := &runtimev1pb.PubsubSubscription{
+			PubsubName: s.PubsubName,
+			Topic:      s.Topic,
+			Metadata:   s.Metadata,
+		}
+		for _, r := range s.Rules {
+			sub.Rules = append(sub.Rules, &runtimev1pb.PubsubSubscriptionRule{Match: r.Match, Path: r.Path})
+		}
+		subscriptions = append(subscriptions, sub)
+	}
+
+	actorRuntime := runtime_metadata.GetActorRuntime(ctx, a.actor)
+	activeActorsCount := make([]*runtimev1pb.ActiveActorsCount, 0, len(actorRuntime.ActiveActors))
+	for _, c := range actorRuntime.ActiveActors {
+		activeActorsCount = append(activeActorsCount, &runtimev1pb.ActiveActorsCount{Type: c.Type, Count: int32(c.Count)})
+	}
+
+	build := runtime_metadata.GetBuild()
 	response := &runtimev1pb.GetMetadataResponse{
+		Id:                   a.id,
+		ActiveActorsCount:    activeActorsCount,
 		ExtendedMetadata:     temp,
 		RegisteredComponents: registeredComponents,
+		EnabledFeatures:      details.EnabledFeatures,
+		Subscriptions:        subscriptions,
+		ActorRuntime: &runtimev1pb.ActorRuntime{
+			RuntimeStatus: actorRuntime.Status,
+			HostReady:     actorRuntime.HostReady,
+			Placement:     actorRuntime.Placement,
+		},
+		Runtime: &runtimev1pb.RuntimeBuild{
+			Version:    build.Version,
+			Commit:     build.Commit,
+			GitVersion: build.GitVersion,
+			GoVersion:  build.GoVersion,
+		},
 	}
 	return response, nil
 }
@@ -1226,11 +1689,11 @@ func stringValueOrEmpty(value *string) string {
 
 func (a *api) getConfigurationStore(name string) (configuration.Store, error) {
 	if a.configurationStores == nil || len(a.configurationStores) == 0 {
-		return nil, status.Error(codes.FailedPrecondition, messages.ErrConfigurationStoresNotConfigured)
+		return nil, messages.Status(codes.FailedPrecondition, messages.ErrConfigurationStoresNotConfigured)
 	}
 
 	if a.configurationStores[name] == nil {
-		return nil, status.Errorf(codes.InvalidArgument, messages.ErrConfigurationStoreNotFound, name)
+		return nil, messages.Status(codes.InvalidArgument, messages.ErrConfigurationStoreNotFound, name)
 	}
 	return a.configurationStores[name], nil
 }
@@ -1247,9 +1710,11 @@ func (a *api) GetConfigurationAlpha1(ctx context.Context, in *runtimev1pb.GetCon
 		Metadata: in.Metadata,
 	}
 
+	done := drain.Components.Track(in.StoreName)
 	getResponse, err := store.Get(ctx, &req)
+	done()
 	if err != nil {
-		err = status.Errorf(codes.Internal, messages.ErrConfigurationGet, req.Keys, in.StoreName, err.Error())
+		err = messages.Status(codes.Internal, messages.ErrConfigurationGet, req.Keys, in.StoreName, err.Error())
 		apiServerLogger.Debug(err)
 		return &runtimev1pb.GetConfigurationResponse{}, err
 	}
@@ -1274,70 +1739,109 @@ func (a *api) GetConfigurationAlpha1(ctx context.Context, in *runtimev1pb.GetCon
 type configurationEventHandler struct {
 	api          *api
 	storeName    string
+	keys         []string
+	keyPrefixes  []string
 	serverStream runtimev1pb.Dapr_SubscribeConfigurationAlpha1Server
+	sendLock     sync.Mutex
+
+	// versions are the last versions sent of the items, so that the items replayed and updated during the replay
+	// are sent once. updated are the keys of the items updated since subscribing.
+	versions map[string]string
+	updated  map[string]bool
 }
 
 func (h *configurationEventHandler) updateEventHandler(ctx context.Context, e *configuration.UpdateEvent) error {
-	items := make([]*commonv1pb.ConfigurationItem, 0)
-	for _, v := range e.Items {
-		items = append(items, &commonv1pb.ConfigurationItem{
+	h.send(configuration_loader.FilterItems(e.Items, h.keys, h.keyPrefixes), false)
+	return nil
+}
+
+// send sends the items which weren't sent yet. Replayed items are older than the updates of the subscription, and
+// are not sent if their keys were updated.
+func (h *configurationEventHandler) send(items []*configuration.Item, replayed bool) {
+	// Stores may call the handler concurrently, and streams don't support concurrent sends.
+	h.sendLock.Lock()
+	defer h.sendLock.Unlock()
+
+	resp := &runtimev1pb.SubscribeConfigurationResponse{
+		Items: make([]*commonv1pb.ConfigurationItem, 0, len(items)),
+	}
+	for _, v := range items {
+		if replayed && h.updated[v.Key] {
+			continue
+		}
+		if v.Version != "" && h.versions[v.Key] == v.Version {
+			continue
+		}
+		if !replayed {
+			h.updated[v.Key] = true
+		}
+		h.versions[v.Key] = v.Version
+		resp.Items = append(resp.Items, &commonv1pb.ConfigurationItem{
 			Key:      v.Key,
 			Value:    v.Value,
 			Version:  v.Version,
 			Metadata: v.Metadata,
 		})
 	}
+	if len(resp.Items) == 0 {
+		return
+	}
 
-	if err := h.serverStream.Send(&runtimev1pb.SubscribeConfigurationResponse{
-		Items: items,
-	}); err != nil {
+	if err := h.serverStream.Send(resp); err != nil {
 		apiServerLogger.Debug(err)
 	}
-	return nil
 }
 
 func (a *api) SubscribeConfigurationAlpha1(request *runtimev1pb.SubscribeConfigurationRequest, configurationServer runtimev1pb.Dapr_SubscribeConfigurationAlpha1Server) error {
 	store, err := a.getConfigurationStore(request.StoreName)
 	if err != nil {
-		err = status.Errorf(codes.Internal, fmt.Sprintf(messages.ErrConfigurationSubscribe, request.Keys, request.StoreName, err))
+		err = messages.Status(codes.Internal, messages.ErrConfigurationSubscribe, request.Keys, request.StoreName, err)
 		apiServerLogger.Debug(err)
 		return err
 	}
 
-	subscribeKeys := request.Keys
-	unsubscribedKeys := make([]string, 0)
-	a.configurationSubscribeLock.Lock()
-
-	for _, k := range subscribeKeys {
-		if _, ok := a.configurationSubscribe[fmt.Sprintf("%s||%s", request.StoreName, k)]; !ok {
-			unsubscribedKeys = append(unsubscribedKeys, k)
-		}
+	handler := &configurationEventHandler{
+		api:          a,
+		storeName:    request.StoreName,
+		keys:         request.Keys,
+		keyPrefixes:  request.KeyPrefixes,
+		serverStream: configurationServer,
+		versions:     make(map[string]string, len(request.ResumeVersions)),
+		updated:      map[string]bool{},
 	}
+	for k, v := range request.ResumeVersions {
+		handler.versions[k] = v
+	}
+
+	// Each stream has its own subscription, which ends with the stream, so that reconnecting clients
+	// don't depend on the subscription of a stream which is gone.
+	ctx := configurationServer.Context()
 
 	req := &configuration.SubscribeRequest{
-		Keys:     unsubscribedKeys,
+		Keys:     request.Keys,
 		Metadata: request.GetMetadata(),
 	}
-
-	handler := &configurationEventHandler{
-		api:          a,
-		storeName:    request.StoreName,
-		serverStream: configurationServer,
+	// Prefixes can't be passed to the stores, so all the items are subscribed to and filtered.
+	if len(request.KeyPrefixes) > 0 {
+		req.Keys = nil
 	}
 
-	ctx := context.TODO()
-	// TODO(@laurence) deal with failed subscription and retires
 	err = store.Subscribe(ctx, req, handler.updateEventHandler)
 	if err != nil {
 		apiServerLogger.Debug(err)
-		a.configurationSubscribeLock.Unlock()
 		return err
 	}
 
-	for _, k := range unsubscribedKeys {
-		a.configurationSubscribe[fmt.Sprintf("%s||%s", request.StoreName, k)] = true
+	// The missed changes are replayed after subscribing, so that the changes made during the replay aren't missed.
+	if len(request.ResumeVersions) > 0 {
+		items, replayErr := configuration_loader.Replay(ctx, store, request.Keys, request.KeyPrefixes, request.ResumeVersions, request.GetMetadata())
+		if replayErr != nil {
+			err = messages.Status(codes.Internal, messages.ErrConfigurationSubscribe, request.Keys, request.StoreName, replayErr)
+			apiServerLogger.Debug(err)
+			return err
+		}
+		handler.send(items, true)
 	}
-	a.configurationSubscribeLock.Unlock()
 
 	<-ctx.Done()
 	return nil
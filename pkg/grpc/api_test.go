@@ -14,13 +14,22 @@ limitations under the License.
 package grpc
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"math/big"
 	"net"
+	"net/url"
 	"strconv"
-	"sync"
+	"strings"
 	"testing"
 	"time"
 
@@ -29,11 +38,14 @@ import (
 	"github.com/phayes/freeport"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.opencensus.io/trace"
 	epb "google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
@@ -47,7 +59,11 @@ import (
 	"github.com/dapr/kit/logger"
 
 	components_v1alpha "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	"github.com/dapr/dapr/pkg/apphealth"
 	channelt "github.com/dapr/dapr/pkg/channel/testing"
+	bindings_loader "github.com/dapr/dapr/pkg/components/bindings"
+	configuration_loader "github.com/dapr/dapr/pkg/components/configuration"
+	secretstores_loader "github.com/dapr/dapr/pkg/components/secretstores"
 	"github.com/dapr/dapr/pkg/config"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
 	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
@@ -57,6 +73,7 @@ import (
 	commonv1pb "github.com/dapr/dapr/pkg/proto/common/v1"
 	internalv1pb "github.com/dapr/dapr/pkg/proto/internals/v1"
 	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
+	runtime_metadata "github.com/dapr/dapr/pkg/runtime/metadata"
 	runtime_pubsub "github.com/dapr/dapr/pkg/runtime/pubsub"
 	daprt "github.com/dapr/dapr/pkg/testing"
 	testtrace "github.com/dapr/dapr/pkg/testing/trace"
@@ -205,7 +222,7 @@ func startDaprAPIServer(port int, testAPIServer *api, token string) *grpc.Server
 	opts := []grpc.ServerOption{}
 	if token != "" {
 		opts = append(opts,
-			grpc.UnaryInterceptor(setAPIAuthenticationMiddlewareUnary(token, "dapr-api-token")),
+			grpc.UnaryInterceptor(setAPIAuthenticationMiddlewareUnary(strings.Split(token, ","), "dapr-api-token")),
 		)
 	}
 
@@ -329,6 +346,124 @@ func TestCallLocal(t *testing.T) {
 		_, err := client.CallLocal(context.Background(), request)
 		assert.Equal(t, codes.Internal, status.Code(err))
 	})
+
+	t.Run("app is unhealthy", func(t *testing.T) {
+		port, _ := freeport.GetFreePort()
+
+		mockAppChannel := new(channelt.MockAppChannel)
+		appHealth := apphealth.New()
+		appHealth.SetHealthy(false)
+		fakeAPI := &api{
+			id:         "fakeAPI",
+			appChannel: mockAppChannel,
+			appHealth:  appHealth,
+		}
+		server := startInternalServer(port, fakeAPI)
+		defer server.Stop()
+		clientConn := createTestClient(port)
+		defer clientConn.Close()
+
+		client := internalv1pb.NewServiceInvocationClient(clientConn)
+		request := invokev1.NewInvokeMethodRequest("method").Proto()
+
+		resp, err := client.CallLocal(context.Background(), request)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(503), resp.GetStatus().GetCode())
+		mockAppChannel.AssertNotCalled(t, "InvokeMethod", mock.Anything, mock.Anything)
+	})
+
+	t.Run("gateway forwards to target app with the caller identity", func(t *testing.T) {
+		mockDirectMessaging := new(daprt.MockDirectMessaging)
+		mockDirectMessaging.On("Invoke",
+			mock.Anything,
+			"app1.ns1",
+			mock.MatchedBy(func(req *invokev1.InvokeMethodRequest) bool {
+				return callerSpiffeID(req) == "spiffe://east/ns/ns2/caller"
+			})).Return(invokev1.NewInvokeMethodResponse(200, "OK", nil), nil)
+		fakeAPI := &api{
+			id:              "gateway",
+			directMessaging: mockDirectMessaging,
+			enableGateway:   true,
+		}
+
+		// The identity asserted by the caller is replaced with the one of its certificate.
+		request := invokev1.NewInvokeMethodRequest("method").WithMetadata(map[string][]string{
+			invokev1.DestinationIDHeader:  {"app1.ns1"},
+			invokev1.CallerSpiffeIDHeader: {"spiffe://cluster.local/ns/default/admin"},
+		}).Proto()
+
+		_, err := fakeAPI.CallLocal(newSpiffeIDContext(t, "spiffe://east/ns/ns2/caller"), request)
+		assert.NoError(t, err)
+		mockDirectMessaging.AssertNumberOfCalls(t, "Invoke", 1)
+	})
+
+	t.Run("gateway does not forward without the caller identity", func(t *testing.T) {
+		port, _ := freeport.GetFreePort()
+
+		mockDirectMessaging := new(daprt.MockDirectMessaging)
+		fakeAPI := &api{
+			id:              "gateway",
+			directMessaging: mockDirectMessaging,
+			enableGateway:   true,
+		}
+		server := startInternalServer(port, fakeAPI)
+		defer server.Stop()
+		clientConn := createTestClient(port)
+		defer clientConn.Close()
+
+		client := internalv1pb.NewServiceInvocationClient(clientConn)
+		request := invokev1.NewInvokeMethodRequest("method").WithMetadata(map[string][]string{
+			invokev1.DestinationIDHeader: {"app1.ns1"},
+		}).Proto()
+
+		_, err := client.CallLocal(context.Background(), request)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		mockDirectMessaging.AssertNotCalled(t, "Invoke", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("gateway without app channel rejects requests to itself", func(t *testing.T) {
+		port, _ := freeport.GetFreePort()
+
+		fakeAPI := &api{
+			id:            "gateway",
+			enableGateway: true,
+		}
+		server := startInternalServer(port, fakeAPI)
+		defer server.Stop()
+		clientConn := createTestClient(port)
+		defer clientConn.Close()
+
+		client := internalv1pb.NewServiceInvocationClient(clientConn)
+		request := invokev1.NewInvokeMethodRequest("method").WithMetadata(map[string][]string{
+			invokev1.DestinationIDHeader: {"gateway"},
+		}).Proto()
+
+		_, err := client.CallLocal(context.Background(), request)
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+// newSpiffeIDContext returns the context of a call from a peer authenticated with a cert of the given SPIFFE ID.
+func newSpiffeIDContext(t *testing.T, spiffeID string) context.Context {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	uri, err := url.Parse(spiffeID)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		URIs:         []*url.URL{uri},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}},
+	})
 }
 
 func mustMarshalAny(msg proto.Message) *anypb.Any {
@@ -438,6 +573,46 @@ func TestAPIToken(t *testing.T) {
 		assert.Equal(t, codes.Unauthenticated, s.Code())
 	})
 
+	t.Run("previous token during rotation", func(t *testing.T) {
+		fakeResp := invokev1.NewInvokeMethodResponse(404, "NotFound", nil)
+		fakeResp.WithRawData([]byte("fakeDirectMessageResponse"), "application/json")
+
+		// Set up direct messaging mock
+		mockDirectMessaging.Calls = nil // reset call count
+		mockDirectMessaging.On("Invoke",
+			mock.AnythingOfType("*context.valueCtx"),
+			"fakeAppID",
+			mock.AnythingOfType("*v1.InvokeMethodRequest")).Return(fakeResp, nil).Once()
+
+		// Run test server
+		port, _ := freeport.GetFreePort()
+		server := startDaprAPIServer(port, fakeAPI, "5678,1234")
+		defer server.Stop()
+
+		// Create gRPC test client
+		clientConn := createTestClient(port)
+		defer clientConn.Close()
+
+		// act
+		client := runtimev1pb.NewDaprClient(clientConn)
+		req := &runtimev1pb.InvokeServiceRequest{
+			Id: "fakeAppID",
+			Message: &commonv1pb.InvokeRequest{
+				Method: "fakeMethod",
+				Data:   &anypb.Any{Value: []byte("testData")},
+			},
+		}
+		md := metadata.Pairs("dapr-api-token", "1234")
+		ctx := metadata.NewOutgoingContext(context.Background(), md)
+		_, err := client.InvokeService(ctx, req)
+
+		// assert
+		mockDirectMessaging.AssertNumberOfCalls(t, "Invoke", 1)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, codes.NotFound, s.Code())
+	})
+
 	t.Run("missing token", func(t *testing.T) {
 		token := "1234"
 
@@ -799,12 +974,17 @@ func TestGetBulkSecret(t *testing.T) {
 	fakeStore := daprt.FakeSecretStore{}
 	fakeStores := map[string]secretstores.SecretStore{
 		"store1": fakeStore,
+		"store2": fakeStore,
 	}
 	secretsConfiguration := map[string]config.SecretsScope{
 		"store1": {
 			DefaultAccess: config.AllowAccess,
 			DeniedSecrets: []string{"not-allowed"},
 		},
+		"store2": {
+			DefaultAccess:     config.AllowAccess,
+			AllowedOperations: []string{config.SecretOperationGet},
+		},
 	}
 	expectedResponse := "life is good"
 
@@ -823,6 +1003,13 @@ func TestGetBulkSecret(t *testing.T) {
 			errorExcepted:    false,
 			expectedResponse: expectedResponse,
 		},
+		{
+			testName:      "bulkGet not allowed",
+			storeName:     "store2",
+			key:           "good-key",
+			errorExcepted: true,
+			expectedError: codes.PermissionDenied,
+		},
 	}
 	// Setup Dapr API server
 	fakeAPI := &api{
@@ -860,6 +1047,141 @@ func TestGetBulkSecret(t *testing.T) {
 	}
 }
 
+type watchingSecretStore struct {
+	daprt.FakeSecretStore
+}
+
+func (w watchingSecretStore) Watch(ctx context.Context, keys []string, metadata map[string]string, handler secretstores_loader.SecretUpdateHandler) error {
+	for _, key := range keys {
+		handler(secretstores_loader.SecretUpdate{Key: key, Data: map[string]string{key: "rotated"}, Version: "2"})
+	}
+	return nil
+}
+
+func TestCryptoAlpha1(t *testing.T) {
+	port, _ := freeport.GetFreePort()
+	fakeAPI := &api{
+		id: "fakeAPI",
+		secretStores: map[string]secretstores.SecretStore{
+			"store1":  daprt.FakeSecretStore{},
+			"transit": daprt.FakeTransitSecretStore{},
+		},
+		secretsConfiguration: map[string]config.SecretsScope{
+			"transit": {
+				DefaultAccess: config.AllowAccess,
+				DeniedSecrets: []string{"not-allowed"},
+			},
+		},
+	}
+	server := startDaprAPIServer(port, fakeAPI, "")
+	defer server.Stop()
+
+	clientConn := createTestClient(port)
+	defer clientConn.Close()
+
+	client := runtimev1pb.NewDaprClient(clientConn)
+	ctx := context.Background()
+
+	t.Run("encrypt and decrypt", func(t *testing.T) {
+		encrypted, err := client.EncryptAlpha1(ctx, &runtimev1pb.CryptoRequest{StoreName: "transit", KeyName: "key1", Data: []byte("plaintext")})
+		assert.NoError(t, err)
+		assert.Equal(t, "enc:plaintext", string(encrypted.Data))
+
+		decrypted, err := client.DecryptAlpha1(ctx, &runtimev1pb.CryptoRequest{StoreName: "transit", KeyName: "key1", Data: encrypted.Data})
+		assert.NoError(t, err)
+		assert.Equal(t, "plaintext", string(decrypted.Data))
+	})
+
+	t.Run("sign and verify", func(t *testing.T) {
+		signed, err := client.SignAlpha1(ctx, &runtimev1pb.CryptoRequest{StoreName: "transit", KeyName: "key1", Data: []byte("message")})
+		assert.NoError(t, err)
+
+		verified, err := client.VerifyAlpha1(ctx, &runtimev1pb.CryptoRequest{StoreName: "transit", KeyName: "key1", Data: []byte("message"), Signature: signed.Data})
+		assert.NoError(t, err)
+		assert.True(t, verified.Valid)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		testCases := []struct {
+			testName  string
+			storeName string
+			keyName   string
+			errorCode codes.Code
+		}{
+			{"store not found", "notexist", "key1", codes.InvalidArgument},
+			{"not supported", "store1", "key1", codes.Unimplemented},
+			{"key not allowed", "transit", "not-allowed", codes.PermissionDenied},
+			{"no key name", "transit", "", codes.InvalidArgument},
+			{"operation failed", "transit", "error-key", codes.Internal},
+		}
+		for _, tc := range testCases {
+			_, err := client.EncryptAlpha1(ctx, &runtimev1pb.CryptoRequest{StoreName: tc.storeName, KeyName: tc.keyName})
+			assert.Equal(t, tc.errorCode, status.Code(err), tc.testName)
+		}
+	})
+}
+
+func TestSubscribeSecretsAlpha1(t *testing.T) {
+	port, _ := freeport.GetFreePort()
+	fakeAPI := &api{
+		id: "fakeAPI",
+		secretStores: map[string]secretstores.SecretStore{
+			"store1": watchingSecretStore{},
+		},
+		secretsConfiguration: map[string]config.SecretsScope{
+			"store1": {
+				DefaultAccess: config.AllowAccess,
+				DeniedSecrets: []string{"not-allowed"},
+			},
+		},
+	}
+	server := startDaprAPIServer(port, fakeAPI, "")
+	defer server.Stop()
+
+	clientConn := createTestClient(port)
+	defer clientConn.Close()
+
+	client := runtimev1pb.NewDaprClient(clientConn)
+
+	t.Run("stream secret rotations", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stream, err := client.SubscribeSecretsAlpha1(ctx, &runtimev1pb.SubscribeSecretsRequest{
+			StoreName: "store1",
+			Keys:      []string{"good-key"},
+		})
+		assert.NoError(t, err)
+
+		resp, err := stream.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, "good-key", resp.Key)
+		assert.Equal(t, "rotated", resp.Data["good-key"])
+		assert.Equal(t, "2", resp.Version)
+	})
+
+	t.Run("secret not allowed", func(t *testing.T) {
+		stream, err := client.SubscribeSecretsAlpha1(context.Background(), &runtimev1pb.SubscribeSecretsRequest{
+			StoreName: "store1",
+			Keys:      []string{"good-key", "not-allowed"},
+		})
+		assert.NoError(t, err)
+
+		_, err = stream.Recv()
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("secret store not found", func(t *testing.T) {
+		stream, err := client.SubscribeSecretsAlpha1(context.Background(), &runtimev1pb.SubscribeSecretsRequest{
+			StoreName: "nonexistent",
+			Keys:      []string{"good-key"},
+		})
+		assert.NoError(t, err)
+
+		_, err = stream.Recv()
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
 func TestGetStateWhenStoreNotConfigured(t *testing.T) {
 	port, _ := freeport.GetFreePort()
 	server := startDaprAPIServer(port, &api{id: "fakeAPI"}, "")
@@ -1432,6 +1754,115 @@ func TestInvokeBinding(t *testing.T) {
 	assert.Equal(t, codes.Internal, status.Code(err))
 }
 
+func TestInvokeBindingStream(t *testing.T) {
+	data := bytes.Repeat([]byte("a"), bindingStreamChunkSize+10)
+	port, _ := freeport.GetFreePort()
+	srv := &api{
+		sendToOutputBindingStreamFn: func(name string, req *bindings.InvokeRequest) (*bindings_loader.StreamingInvokeResponse, error) {
+			switch name {
+			case "error-binding":
+				return nil, errors.New("error when invoke binding")
+			case "empty-binding":
+				return nil, nil
+			}
+			return &bindings_loader.StreamingInvokeResponse{
+				Data:     ioutil.NopCloser(bytes.NewReader(data)),
+				Metadata: map[string]string{"key": "value"},
+				Size:     -1,
+			}, nil
+		},
+	}
+	server := startTestServerAPI(port, srv)
+	defer server.Stop()
+
+	clientConn := createTestClient(port)
+	defer clientConn.Close()
+
+	client := runtimev1pb.NewDaprClient(clientConn)
+
+	t.Run("response is streamed in chunks", func(t *testing.T) {
+		stream, err := client.InvokeBindingStreamAlpha1(context.Background(), &runtimev1pb.InvokeBindingRequest{Name: "binding"})
+		assert.NoError(t, err)
+
+		var chunks []*runtimev1pb.InvokeBindingResponse
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(t, err)
+			chunks = append(chunks, resp)
+		}
+
+		assert.Len(t, chunks, 2)
+		assert.Equal(t, "value", chunks[0].Metadata["key"])
+		assert.Empty(t, chunks[1].Metadata)
+		assert.Equal(t, data, append(chunks[0].Data, chunks[1].Data...))
+	})
+
+	t.Run("empty response", func(t *testing.T) {
+		stream, err := client.InvokeBindingStreamAlpha1(context.Background(), &runtimev1pb.InvokeBindingRequest{Name: "empty-binding"})
+		assert.NoError(t, err)
+		resp, err := stream.Recv()
+		assert.NoError(t, err)
+		assert.Empty(t, resp.Data)
+		_, err = stream.Recv()
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("binding error", func(t *testing.T) {
+		stream, err := client.InvokeBindingStreamAlpha1(context.Background(), &runtimev1pb.InvokeBindingRequest{Name: "error-binding"})
+		assert.NoError(t, err)
+		_, err = stream.Recv()
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestInvokeBindingBulk(t *testing.T) {
+	port, _ := freeport.GetFreePort()
+	srv := &api{
+		sendToOutputBindingBulkFn: func(name string, reqs []*bindings.InvokeRequest) ([]bindings_loader.BulkInvokeResult, error) {
+			if name == "error-binding" {
+				return nil, errors.New("error when invoke binding")
+			}
+			return []bindings_loader.BulkInvokeResult{
+				{Response: &bindings.InvokeResponse{Data: reqs[0].Data, Metadata: map[string]string{"key": "value"}}},
+				{Error: errors.New("unsupported operation")},
+				{},
+			}, nil
+		},
+	}
+	server := startTestServerAPI(port, srv)
+	defer server.Stop()
+
+	clientConn := createTestClient(port)
+	defer clientConn.Close()
+
+	client := runtimev1pb.NewDaprClient(clientConn)
+
+	t.Run("result of each request", func(t *testing.T) {
+		resp, err := client.InvokeBindingBulkAlpha1(context.Background(), &runtimev1pb.InvokeBindingBulkRequest{
+			Name: "binding",
+			Requests: []*runtimev1pb.InvokeBindingBulkRequestItem{
+				{Data: []byte("1"), Operation: "create"},
+				{Data: []byte("2"), Operation: "get"},
+				{Data: []byte("3"), Operation: "create"},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, resp.Results, 3)
+		assert.Equal(t, "1", string(resp.Results[0].Data))
+		assert.Equal(t, "value", resp.Results[0].Metadata["key"])
+		assert.Equal(t, "unsupported operation", resp.Results[1].Error)
+		assert.Empty(t, resp.Results[2].Error)
+	})
+
+	t.Run("binding error", func(t *testing.T) {
+		_, err := client.InvokeBindingBulkAlpha1(context.Background(), &runtimev1pb.InvokeBindingBulkRequest{Name: "error-binding"})
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
 func TestTransactionStateStoreNotConfigured(t *testing.T) {
 	port, _ := freeport.GetFreePort()
 	server := startDaprAPIServer(port, &api{id: "fakeAPI"}, "")
@@ -1579,9 +2010,30 @@ func TestGetMetadata(t *testing.T) {
 	port, _ := freeport.GetFreePort()
 	fakeComponent := components_v1alpha.Component{}
 	fakeComponent.Name = "testComponent"
+	fakeComponent.Spec.Type = "state.fake"
 	fakeAPI := &api{
-		id:         "fakeAPI",
-		components: []components_v1alpha.Component{fakeComponent},
+		id: "fakeAPI",
+		getComponentsFn: func() []components_v1alpha.Component {
+			return []components_v1alpha.Component{fakeComponent}
+		},
+		getMetadataDetailsFn: func() runtime_metadata.Details {
+			return runtime_metadata.Details{
+				Capabilities: map[string][]string{
+					runtime_metadata.CapabilitiesKey("state", "testComponent"): {"ETAG", "TRANSACTIONAL"},
+				},
+				EnabledFeatures: []string{"Actor.Reentrancy"},
+				Subscriptions: []runtime_metadata.Subscription{
+					{
+						PubsubName: "pubsub",
+						Topic:      "orders",
+						Rules: []runtime_metadata.SubscriptionRule{
+							{Match: `event.type == "order"`, Path: "/orders"},
+							{Path: "/default"},
+						},
+					},
+				},
+			}
+		},
 	}
 	fakeAPI.extendedMetadata.Store("testKey", "testValue")
 	server := startDaprAPIServer(port, fakeAPI, "")
@@ -1595,8 +2047,19 @@ func TestGetMetadata(t *testing.T) {
 	assert.NoError(t, err, "Expected no error")
 	assert.Len(t, response.RegisteredComponents, 1, "One component should be returned")
 	assert.Equal(t, response.RegisteredComponents[0].Name, "testComponent")
+	assert.Equal(t, []string{"ETAG", "TRANSACTIONAL"}, response.RegisteredComponents[0].Capabilities)
 	assert.Contains(t, response.ExtendedMetadata, "testKey")
 	assert.Equal(t, response.ExtendedMetadata["testKey"], "testValue")
+	assert.Equal(t, "fakeAPI", response.Id)
+	assert.Equal(t, []string{"Actor.Reentrancy"}, response.EnabledFeatures)
+	require.Len(t, response.Subscriptions, 1)
+	assert.Equal(t, "orders", response.Subscriptions[0].Topic)
+	require.Len(t, response.Subscriptions[0].Rules, 2)
+	assert.Equal(t, `event.type == "order"`, response.Subscriptions[0].Rules[0].Match)
+	assert.Equal(t, "/default", response.Subscriptions[0].Rules[1].Path)
+	assert.Equal(t, runtime_metadata.ActorRuntimeDisabled, response.ActorRuntime.RuntimeStatus)
+	assert.NotEmpty(t, response.Runtime.Version)
+	assert.NotEmpty(t, response.Runtime.GoVersion)
 }
 
 func TestSetMetadata(t *testing.T) {
@@ -1918,10 +2381,8 @@ func TestSubscribeConfigurationAlpha1(t *testing.T) {
 		server := startDaprAPIServer(
 			port,
 			&api{
-				id:                         "fakeAPI",
-				configurationStores:        map[string]configuration.Store{"store1": &mockConfigStore{}},
-				configurationSubscribe:     map[string]bool{},
-				configurationSubscribeLock: sync.Mutex{},
+				id:                  "fakeAPI",
+				configurationStores: map[string]configuration.Store{"store1": &mockConfigStore{}},
 			},
 			"")
 		defer server.Stop()
@@ -1959,6 +2420,143 @@ func TestSubscribeConfigurationAlpha1(t *testing.T) {
 		assert.Equal(t, "key1", r.Items[0].Key)
 		assert.Equal(t, "val1", r.Items[0].Value)
 	})
+
+	port, err := freeport.GetFreePort()
+	assert.NoError(t, err)
+
+	store := &versionedConfigStore{
+		items: []*configuration.Item{
+			{Key: "app.key1", Value: "val1", Version: "2"},
+			{Key: "app.key2", Value: "val2", Version: "1"},
+			{Key: "other.key3", Value: "val3", Version: "1"},
+		},
+	}
+	server := startDaprAPIServer(
+		port,
+		&api{
+			id: "fakeAPI",
+			configurationStores: map[string]configuration.Store{
+				"store1": store,
+				"store2": &versionedConfigStore{
+					items: []*configuration.Item{
+						{Key: "app.key1", Value: "val1", Version: "2"},
+						{Key: "app.key2", Value: "val2", Version: "1"},
+						{Key: "app.key3", Value: "val3", Version: "5"},
+					},
+					updates: []*configuration.Item{
+						{Key: "app.key2", Value: "val2", Version: "3"},
+					},
+				},
+			},
+		},
+		"")
+	defer server.Stop()
+
+	clientConn := createTestClient(port)
+	defer clientConn.Close()
+
+	client := runtimev1pb.NewDaprClient(clientConn)
+
+	t.Run("subscribe to key prefixes", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		s, err := client.SubscribeConfigurationAlpha1(ctx, &runtimev1pb.SubscribeConfigurationRequest{
+			StoreName:   "store1",
+			KeyPrefixes: []string{"app."},
+		})
+		assert.NoError(t, err)
+
+		update, err := s.Recv()
+		assert.NoError(t, err)
+		assert.Len(t, update.Items, 2)
+		assert.Equal(t, "app.key1", update.Items[0].Key)
+		assert.Equal(t, "app.key2", update.Items[1].Key)
+	})
+
+	t.Run("resume from versions", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		s, err := client.SubscribeConfigurationAlpha1(ctx, &runtimev1pb.SubscribeConfigurationRequest{
+			StoreName:      "store1",
+			KeyPrefixes:    []string{"app."},
+			ResumeVersions: map[string]string{"app.key1": "1", "app.key2": "1"},
+		})
+		assert.NoError(t, err)
+
+		// the versions already seen are not sent again.
+		update, err := s.Recv()
+		assert.NoError(t, err)
+		assert.Len(t, update.Items, 1)
+		assert.Equal(t, "app.key1", update.Items[0].Key)
+		assert.Equal(t, "2", update.Items[0].Version)
+	})
+
+	t.Run("items updated during the replay are not replayed", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		s, err := client.SubscribeConfigurationAlpha1(ctx, &runtimev1pb.SubscribeConfigurationRequest{
+			StoreName:      "store2",
+			KeyPrefixes:    []string{"app."},
+			ResumeVersions: map[string]string{"app.key1": "1", "app.key2": "1"},
+		})
+		assert.NoError(t, err)
+
+		update, err := s.Recv()
+		assert.NoError(t, err)
+		assert.Len(t, update.Items, 1)
+		assert.Equal(t, "app.key2", update.Items[0].Key)
+		assert.Equal(t, "3", update.Items[0].Version)
+
+		update, err = s.Recv()
+		assert.NoError(t, err)
+		assert.Len(t, update.Items, 2)
+		assert.Equal(t, "app.key1", update.Items[0].Key)
+		assert.Equal(t, "app.key3", update.Items[1].Key)
+	})
+
+	t.Run("each stream has its own subscription", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			ctx, cancel := context.WithCancel(context.Background())
+			s, err := client.SubscribeConfigurationAlpha1(ctx, &runtimev1pb.SubscribeConfigurationRequest{
+				StoreName: "store1",
+				Keys:      []string{"other.key3"},
+			})
+			assert.NoError(t, err)
+
+			update, err := s.Recv()
+			assert.NoError(t, err)
+			assert.Len(t, update.Items, 1)
+			assert.Equal(t, "other.key3", update.Items[0].Key)
+			cancel()
+		}
+	})
+}
+
+// versionedConfigStore sends its updates, or all its items if it has no updates, to subscribers once subscribed.
+type versionedConfigStore struct {
+	items   []*configuration.Item
+	updates []*configuration.Item
+}
+
+func (m *versionedConfigStore) Init(metadata configuration.Metadata) error {
+	return nil
+}
+
+func (m *versionedConfigStore) Get(ctx context.Context, req *configuration.GetRequest) (*configuration.GetResponse, error) {
+	return &configuration.GetResponse{
+		Items: configuration_loader.FilterItems(m.items, req.Keys, nil),
+	}, nil
+}
+
+func (m *versionedConfigStore) Subscribe(ctx context.Context, req *configuration.SubscribeRequest, handler configuration.UpdateHandler) error {
+	items := m.updates
+	if items == nil {
+		items = m.items
+	}
+	handler(ctx, &configuration.UpdateEvent{
+		Items: items,
+	})
+	return nil
 }
 
 type mockConfigStore struct{}
@@ -2,15 +2,21 @@ package grpc
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
+	"github.com/dapr/dapr/pkg/audit"
+	diag "github.com/dapr/dapr/pkg/diagnostics"
 	v1 "github.com/dapr/dapr/pkg/messaging/v1"
+	auth "github.com/dapr/dapr/pkg/runtime/security"
 )
 
-func setAPIAuthenticationMiddlewareUnary(apiToken, authHeader string) grpc.UnaryServerInterceptor {
+func setAPIAuthenticationMiddlewareUnary(apiTokens []string, authHeader string) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		md, ok := metadata.FromIncomingContext(ctx)
 		if !ok {
@@ -20,16 +26,83 @@ func setAPIAuthenticationMiddlewareUnary(apiToken, authHeader string) grpc.Unary
 
 		token := md.Get(authHeader)
 		if len(token) == 0 {
+			diag.DefaultMonitoring.APITokenRejected("missing")
 			err := v1.ErrorFromHTTPResponseCode(http.StatusUnauthorized, "missing api token in request metadata")
+			auditAuthenticationFailed(info.FullMethod, err)
 			return nil, err
 		}
 
-		if token[0] != apiToken {
+		apiToken, ok := auth.MatchAPIToken(apiTokens, token[0])
+		if !ok {
+			diag.DefaultMonitoring.APITokenRejected("mismatch")
 			err := v1.ErrorFromHTTPResponseCode(http.StatusUnauthorized, "authentication error: api token mismatch")
+			auditAuthenticationFailed(info.FullMethod, err)
 			return nil, err
 		}
+		diag.DefaultMonitoring.APITokenAuthenticated(auth.APITokenID(apiToken))
 
 		md.Set(authHeader, "")
 		return handler(ctx, req)
 	}
 }
+
+func setOIDCAuthenticationMiddlewareUnary(validator *auth.OIDCValidator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		subject, err := validateBearerToken(ctx, validator)
+		if err != nil {
+			auditAuthenticationFailed(info.FullMethod, err)
+			return nil, err
+		}
+		return handler(auth.WithSubject(ctx, subject), req)
+	}
+}
+
+func setOIDCAuthenticationMiddlewareStream(validator *auth.OIDCValidator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		subject, err := validateBearerToken(stream.Context(), validator)
+		if err != nil {
+			auditAuthenticationFailed(info.FullMethod, err)
+			return err
+		}
+
+		wrapped := grpc_middleware.WrapServerStream(stream)
+		wrapped.WrappedContext = auth.WithSubject(stream.Context(), subject)
+		return handler(srv, wrapped)
+	}
+}
+
+// validateBearerToken validates the bearer token in the metadata of a request and removes it from the metadata,
+// so that it isn't forwarded to the target of the request. It returns the subject of the token.
+func validateBearerToken(ctx context.Context, validator *auth.OIDCValidator) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", v1.ErrorFromHTTPResponseCode(http.StatusUnauthorized, "missing metadata in request")
+	}
+
+	values := md.Get(auth.AuthorizationHeader)
+	if len(values) == 0 {
+		return "", v1.ErrorFromHTTPResponseCode(http.StatusUnauthorized, "missing bearer token in request metadata")
+	}
+
+	token, ok := auth.BearerToken(values[0])
+	if !ok {
+		return "", v1.ErrorFromHTTPResponseCode(http.StatusUnauthorized, "authentication error: invalid authorization scheme")
+	}
+
+	subject, err := validator.Validate(ctx, token)
+	if err != nil {
+		return "", v1.ErrorFromHTTPResponseCode(http.StatusUnauthorized, fmt.Sprintf("authentication error: %s", err))
+	}
+
+	md.Set(auth.AuthorizationHeader, "")
+	return subject, nil
+}
+
+// auditAuthenticationFailed emits the audit event of a call rejected by the authentication of the API.
+func auditAuthenticationFailed(method string, err error) {
+	audit.Default.Emit(audit.Event{
+		Type:     audit.EventAuthenticationFailed,
+		Resource: method,
+		Reason:   status.Convert(err).Message(),
+	})
+}
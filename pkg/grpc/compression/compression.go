@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compression
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync/atomic"
+
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+const (
+	// Gzip is the name of the gzip compressor.
+	Gzip = gzip.Name
+	// Zstd is the name of the zstd compressor.
+	Zstd = "zstd"
+)
+
+// Supported returns the names of the compressors that can be used on the internal gRPC channel.
+func Supported() []string {
+	return []string{Zstd, Gzip}
+}
+
+// IsSupported returns true if the named compressor can be used on the internal gRPC channel.
+func IsSupported(name string) bool {
+	for _, s := range Supported() {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultMaxDecompressedSize is the default size limit of the decompressed messages, the default size limit of the
+// messages received by gRPC.
+const defaultMaxDecompressedSize = 4 * 1024 * 1024
+
+// maxDecompressedSize is the size limit of the decompressed messages.
+var maxDecompressedSize int64 = defaultMaxDecompressedSize
+
+// SetMaxDecompressedSize sets the size limit of the decompressed messages, in bytes. It should be set to the size
+// limit of the received messages, so that a small compressed message can't expand past it.
+func SetMaxDecompressedSize(size int) {
+	atomic.StoreInt64(&maxDecompressedSize, int64(size))
+}
+
+func init() {
+	// gzip registers itself when imported.
+	c, err := newZstdCompressor()
+	if err != nil {
+		panic(fmt.Errorf("failed to create the zstd compressor: %w", err))
+	}
+	encoding.RegisterCompressor(c)
+}
+
+// zstdCompressor is a gRPC compressor using zstd.
+// The encoder is shared as EncodeAll is safe for concurrent use.
+type zstdCompressor struct {
+	encoder *zstd.Encoder
+}
+
+func newZstdCompressor() (*zstdCompressor, error) {
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &zstdCompressor{
+		encoder: encoder,
+	}, nil
+}
+
+func (c *zstdCompressor) Name() string {
+	return Zstd
+}
+
+func (c *zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return &zstdWriter{w: w, encoder: c.encoder}, nil
+}
+
+// Decompress decompresses a message as a stream, up to the size limit of the decompressed messages, instead of
+// decompressing it whole in memory.
+func (c *zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	decoder, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1), zstd.WithDecoderLowmem(true))
+	if err != nil {
+		return nil, err
+	}
+	defer decoder.Close()
+
+	limit := atomic.LoadInt64(&maxDecompressedSize)
+	b, err := ioutil.ReadAll(io.LimitReader(decoder, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > limit {
+		return nil, fmt.Errorf("decompressed message is larger than %d bytes", limit)
+	}
+	return bytes.NewReader(b), nil
+}
+
+// zstdWriter buffers the message and writes it compressed when closed.
+type zstdWriter struct {
+	w       io.Writer
+	encoder *zstd.Encoder
+	buf     bytes.Buffer
+}
+
+func (z *zstdWriter) Write(p []byte) (int, error) {
+	return z.buf.Write(p)
+}
+
+func (z *zstdWriter) Close() error {
+	_, err := z.w.Write(z.encoder.EncodeAll(z.buf.Bytes(), nil))
+	return err
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compression
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/encoding"
+)
+
+func TestSupported(t *testing.T) {
+	assert.True(t, IsSupported(Zstd))
+	assert.True(t, IsSupported(Gzip))
+	assert.False(t, IsSupported("snappy"))
+
+	for _, name := range Supported() {
+		assert.NotNil(t, encoding.GetCompressor(name), name)
+	}
+}
+
+func TestZstdCompressor(t *testing.T) {
+	c := encoding.GetCompressor(Zstd)
+	data := []byte(strings.Repeat(`{"message":"hello world"}`, 100))
+
+	var buf bytes.Buffer
+	w, err := c.Compress(&buf)
+	assert.NoError(t, err)
+	_, err = w.Write(data)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	assert.Less(t, buf.Len(), len(data))
+
+	r, err := c.Decompress(&buf)
+	assert.NoError(t, err)
+	b, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, data, b)
+}
+
+func TestZstdDecompressLimit(t *testing.T) {
+	c := encoding.GetCompressor(Zstd)
+	data := make([]byte, 1024*1024)
+
+	var buf bytes.Buffer
+	w, err := c.Compress(&buf)
+	assert.NoError(t, err)
+	_, err = w.Write(data)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	SetMaxDecompressedSize(1024)
+	defer SetMaxDecompressedSize(defaultMaxDecompressedSize)
+
+	_, err = c.Decompress(bytes.NewReader(buf.Bytes()))
+	assert.Error(t, err)
+
+	SetMaxDecompressedSize(len(data))
+	r, err := c.Decompress(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	b, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, data, b)
+}
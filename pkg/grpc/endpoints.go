@@ -13,16 +13,6 @@ limitations under the License.
 
 package grpc
 
-import (
-	"context"
-	"net/http"
-
-	"google.golang.org/grpc"
-
-	"github.com/dapr/dapr/pkg/config"
-	v1 "github.com/dapr/dapr/pkg/messaging/v1"
-)
-
 var endpoints = map[string][]string{
 	"invoke.v1": {
 		"/dapr.proto.runtime.v1.Dapr/InvokeService",
@@ -31,7 +21,7 @@ var endpoints = map[string][]string{
 		"/dapr.proto.runtime.v1.Dapr/GetState",
 		"/dapr.proto.runtime.v1.Dapr/GetBulkState",
 		"/dapr.proto.runtime.v1.Dapr/SaveState",
-		"/dapr.proto.runtime.v1.Dapr/QueryState",
+		"/dapr.proto.runtime.v1.Dapr/QueryStateAlpha1",
 		"/dapr.proto.runtime.v1.Dapr/DeleteState",
 		"/dapr.proto.runtime.v1.Dapr/DeleteBulkState",
 		"/dapr.proto.runtime.v1.Dapr/ExecuteStateTransaction",
@@ -41,10 +31,17 @@ var endpoints = map[string][]string{
 	},
 	"bindings.v1": {
 		"/dapr.proto.runtime.v1.Dapr/InvokeBinding",
+		"/dapr.proto.runtime.v1.Dapr/InvokeBindingStreamAlpha1",
+		"/dapr.proto.runtime.v1.Dapr/InvokeBindingBulkAlpha1",
 	},
 	"secrets.v1": {
 		"/dapr.proto.runtime.v1.Dapr/GetSecret",
 		"/dapr.proto.runtime.v1.Dapr/GetBulkSecret",
+		"/dapr.proto.runtime.v1.Dapr/SubscribeSecretsAlpha1",
+	},
+	"configuration.v1": {
+		"/dapr.proto.runtime.v1.Dapr/GetConfigurationAlpha1",
+		"/dapr.proto.runtime.v1.Dapr/SubscribeConfigurationAlpha1",
 	},
 	"actors.v1": {
 		"/dapr.proto.runtime.v1.Dapr/RegisterActorTimer",
@@ -66,32 +63,3 @@ var endpoints = map[string][]string{
 }
 
 const protocol = "grpc"
-
-func setAPIEndpointsMiddlewareUnary(rules []config.APIAccessRule) grpc.UnaryServerInterceptor {
-	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		var grpcRules []config.APIAccessRule
-
-		for _, rule := range rules {
-			if rule.Protocol == protocol {
-				grpcRules = append(grpcRules, rule)
-			}
-		}
-
-		if len(grpcRules) == 0 {
-			return handler(ctx, req)
-		}
-
-		for _, rule := range grpcRules {
-			if list, ok := endpoints[rule.Name+"."+rule.Version]; ok {
-				for _, method := range list {
-					if method == info.FullMethod {
-						return handler(ctx, req)
-					}
-				}
-			}
-		}
-
-		err := v1.ErrorFromHTTPResponseCode(http.StatusNotImplemented, "requested endpoint is not available")
-		return nil, err
-	}
-}
@@ -19,10 +19,26 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/dapr/dapr/pkg/config"
+	diag "github.com/dapr/dapr/pkg/diagnostics"
+	"github.com/dapr/dapr/pkg/policy"
 )
 
+// accessListMiddlewareUnary returns the interceptor enforcing the allow list of the rules.
+func accessListMiddlewareUnary(rules []config.APIAccessRule) grpc.UnaryServerInterceptor {
+	access := policy.NewAccessList(config.APISpec{Allowed: rules}, protocol)
+	if access == nil {
+		return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			return handler(ctx, req)
+		}
+	}
+	return setAuthorizationMiddlewareUnary(authorizeWithAccessList(access), "")
+}
+
 func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 	h := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return nil, nil
@@ -37,10 +53,10 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 			},
 		}
 
-		f := setAPIEndpointsMiddlewareUnary(a)
+		f := accessListMiddlewareUnary(a)
 
 		for _, e := range endpoints["state.v1"] {
-			_, err := f(nil, nil, &grpc.UnaryServerInfo{
+			_, err := f(context.Background(), nil, &grpc.UnaryServerInfo{
 				FullMethod: e,
 			}, h)
 			assert.NoError(t, err)
@@ -49,10 +65,10 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 		for k, v := range endpoints {
 			if k != "state.v1" {
 				for _, e := range v {
-					_, err := f(nil, nil, &grpc.UnaryServerInfo{
+					_, err := f(context.Background(), nil, &grpc.UnaryServerInfo{
 						FullMethod: e,
 					}, h)
-					assert.Error(t, err)
+					assert.Equal(t, codes.PermissionDenied, status.Code(err))
 				}
 			}
 		}
@@ -67,10 +83,10 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 			},
 		}
 
-		f := setAPIEndpointsMiddlewareUnary(a)
+		f := accessListMiddlewareUnary(a)
 
 		for _, e := range endpoints["publish.v1"] {
-			_, err := f(nil, nil, &grpc.UnaryServerInfo{
+			_, err := f(context.Background(), nil, &grpc.UnaryServerInfo{
 				FullMethod: e,
 			}, h)
 			assert.NoError(t, err)
@@ -79,10 +95,10 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 		for k, v := range endpoints {
 			if k != "publish.v1" {
 				for _, e := range v {
-					_, err := f(nil, nil, &grpc.UnaryServerInfo{
+					_, err := f(context.Background(), nil, &grpc.UnaryServerInfo{
 						FullMethod: e,
 					}, h)
-					assert.Error(t, err)
+					assert.Equal(t, codes.PermissionDenied, status.Code(err))
 				}
 			}
 		}
@@ -97,10 +113,10 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 			},
 		}
 
-		f := setAPIEndpointsMiddlewareUnary(a)
+		f := accessListMiddlewareUnary(a)
 
 		for _, e := range endpoints["actors.v1"] {
-			_, err := f(nil, nil, &grpc.UnaryServerInfo{
+			_, err := f(context.Background(), nil, &grpc.UnaryServerInfo{
 				FullMethod: e,
 			}, h)
 			assert.NoError(t, err)
@@ -109,10 +125,10 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 		for k, v := range endpoints {
 			if k != "actors.v1" {
 				for _, e := range v {
-					_, err := f(nil, nil, &grpc.UnaryServerInfo{
+					_, err := f(context.Background(), nil, &grpc.UnaryServerInfo{
 						FullMethod: e,
 					}, h)
-					assert.Error(t, err)
+					assert.Equal(t, codes.PermissionDenied, status.Code(err))
 				}
 			}
 		}
@@ -127,10 +143,10 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 			},
 		}
 
-		f := setAPIEndpointsMiddlewareUnary(a)
+		f := accessListMiddlewareUnary(a)
 
 		for _, e := range endpoints["bindings.v1"] {
-			_, err := f(nil, nil, &grpc.UnaryServerInfo{
+			_, err := f(context.Background(), nil, &grpc.UnaryServerInfo{
 				FullMethod: e,
 			}, h)
 			assert.NoError(t, err)
@@ -139,10 +155,10 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 		for k, v := range endpoints {
 			if k != "bindings.v1" {
 				for _, e := range v {
-					_, err := f(nil, nil, &grpc.UnaryServerInfo{
+					_, err := f(context.Background(), nil, &grpc.UnaryServerInfo{
 						FullMethod: e,
 					}, h)
-					assert.Error(t, err)
+					assert.Equal(t, codes.PermissionDenied, status.Code(err))
 				}
 			}
 		}
@@ -157,10 +173,10 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 			},
 		}
 
-		f := setAPIEndpointsMiddlewareUnary(a)
+		f := accessListMiddlewareUnary(a)
 
 		for _, e := range endpoints["secrets.v1"] {
-			_, err := f(nil, nil, &grpc.UnaryServerInfo{
+			_, err := f(context.Background(), nil, &grpc.UnaryServerInfo{
 				FullMethod: e,
 			}, h)
 			assert.NoError(t, err)
@@ -169,10 +185,10 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 		for k, v := range endpoints {
 			if k != "secrets.v1" {
 				for _, e := range v {
-					_, err := f(nil, nil, &grpc.UnaryServerInfo{
+					_, err := f(context.Background(), nil, &grpc.UnaryServerInfo{
 						FullMethod: e,
 					}, h)
-					assert.Error(t, err)
+					assert.Equal(t, codes.PermissionDenied, status.Code(err))
 				}
 			}
 		}
@@ -187,10 +203,10 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 			},
 		}
 
-		f := setAPIEndpointsMiddlewareUnary(a)
+		f := accessListMiddlewareUnary(a)
 
 		for _, e := range endpoints["metadata.v1"] {
-			_, err := f(nil, nil, &grpc.UnaryServerInfo{
+			_, err := f(context.Background(), nil, &grpc.UnaryServerInfo{
 				FullMethod: e,
 			}, h)
 			assert.NoError(t, err)
@@ -199,10 +215,10 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 		for k, v := range endpoints {
 			if k != "metadata.v1" {
 				for _, e := range v {
-					_, err := f(nil, nil, &grpc.UnaryServerInfo{
+					_, err := f(context.Background(), nil, &grpc.UnaryServerInfo{
 						FullMethod: e,
 					}, h)
-					assert.Error(t, err)
+					assert.Equal(t, codes.PermissionDenied, status.Code(err))
 				}
 			}
 		}
@@ -217,10 +233,10 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 			},
 		}
 
-		f := setAPIEndpointsMiddlewareUnary(a)
+		f := accessListMiddlewareUnary(a)
 
 		for _, e := range endpoints["shutdown.v1"] {
-			_, err := f(nil, nil, &grpc.UnaryServerInfo{
+			_, err := f(context.Background(), nil, &grpc.UnaryServerInfo{
 				FullMethod: e,
 			}, h)
 			assert.NoError(t, err)
@@ -229,10 +245,10 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 		for k, v := range endpoints {
 			if k != "shutdown.v1" {
 				for _, e := range v {
-					_, err := f(nil, nil, &grpc.UnaryServerInfo{
+					_, err := f(context.Background(), nil, &grpc.UnaryServerInfo{
 						FullMethod: e,
 					}, h)
-					assert.Error(t, err)
+					assert.Equal(t, codes.PermissionDenied, status.Code(err))
 				}
 			}
 		}
@@ -247,10 +263,10 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 			},
 		}
 
-		f := setAPIEndpointsMiddlewareUnary(a)
+		f := accessListMiddlewareUnary(a)
 
 		for _, e := range endpoints["invoke.v1"] {
-			_, err := f(nil, nil, &grpc.UnaryServerInfo{
+			_, err := f(context.Background(), nil, &grpc.UnaryServerInfo{
 				FullMethod: e,
 			}, h)
 			assert.NoError(t, err)
@@ -259,20 +275,20 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 		for k, v := range endpoints {
 			if k != "invoke.v1" {
 				for _, e := range v {
-					_, err := f(nil, nil, &grpc.UnaryServerInfo{
+					_, err := f(context.Background(), nil, &grpc.UnaryServerInfo{
 						FullMethod: e,
 					}, h)
-					assert.Error(t, err)
+					assert.Equal(t, codes.PermissionDenied, status.Code(err))
 				}
 			}
 		}
 	})
 
 	t.Run("no rules, all endpoints are allowed", func(t *testing.T) {
-		f := setAPIEndpointsMiddlewareUnary(nil)
+		f := accessListMiddlewareUnary(nil)
 
 		for _, e := range endpoints["invoke.v1"] {
-			_, err := f(nil, nil, &grpc.UnaryServerInfo{
+			_, err := f(context.Background(), nil, &grpc.UnaryServerInfo{
 				FullMethod: e,
 			}, h)
 			assert.NoError(t, err)
@@ -280,7 +296,7 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 
 		for _, v := range endpoints {
 			for _, e := range v {
-				_, err := f(nil, nil, &grpc.UnaryServerInfo{
+				_, err := f(context.Background(), nil, &grpc.UnaryServerInfo{
 					FullMethod: e,
 				}, h)
 				assert.NoError(t, err)
@@ -297,11 +313,11 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 			},
 		}
 
-		f := setAPIEndpointsMiddlewareUnary(a)
+		f := accessListMiddlewareUnary(a)
 
 		for _, v := range endpoints {
 			for _, e := range v {
-				_, err := f(nil, nil, &grpc.UnaryServerInfo{
+				_, err := f(context.Background(), nil, &grpc.UnaryServerInfo{
 					FullMethod: e,
 				}, h)
 				assert.NoError(t, err)
@@ -309,3 +325,40 @@ func TestSetAPIEndpointsMiddlewareUnary(t *testing.T) {
 		}
 	})
 }
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestAccessListProxiedCalls(t *testing.T) {
+	h := func(srv interface{}, stream grpc.ServerStream) error {
+		return nil
+	}
+	stream := &fakeServerStream{
+		ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(diag.GRPCProxyAppIDKey, "payments")),
+	}
+	info := &grpc.StreamServerInfo{FullMethod: "/myapp.v1.Payments/Pay"}
+
+	t.Run("invoke allowed", func(t *testing.T) {
+		access := policy.NewAccessList(config.APISpec{
+			Allowed: []config.APIAccessRule{{Name: "invoke", Version: "v1", Protocol: "grpc"}},
+		}, protocol)
+		f := setAuthorizationMiddlewareStream(authorizeWithAccessList(access), "")
+
+		assert.NoError(t, f(nil, stream, info, h))
+	})
+
+	t.Run("invoke not allowed", func(t *testing.T) {
+		access := policy.NewAccessList(config.APISpec{
+			Allowed: []config.APIAccessRule{{Name: "state", Version: "v1", Protocol: "grpc"}},
+		}, protocol)
+		f := setAuthorizationMiddlewareStream(authorizeWithAccessList(access), "")
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(f(nil, stream, info, h)))
+	})
+}
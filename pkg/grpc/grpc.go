@@ -18,12 +18,14 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
+	"net"
 	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
 
 	"github.com/dapr/dapr/pkg/channel"
 	grpc_channel "github.com/dapr/dapr/pkg/channel/grpc"
@@ -70,8 +72,17 @@ func (g *Manager) SetAuthenticator(auth security.Authenticator) {
 }
 
 // CreateLocalChannel creates a new gRPC AppChannel.
-func (g *Manager) CreateLocalChannel(port, maxConcurrency int, spec config.TracingSpec, sslEnabled bool, maxRequestBodySize int, readBufferSize int) (channel.AppChannel, error) {
-	conn, err := g.GetGRPCConnection(context.TODO(), fmt.Sprintf("127.0.0.1:%v", port), "", "", true, false, sslEnabled)
+// Requests to the app are multiplexed on a single HTTP/2 connection, kept alive with pings when a keep-alive interval is set.
+func (g *Manager) CreateLocalChannel(port, maxConcurrency int, spec config.TracingSpec, sslEnabled bool, maxRequestBodySize int, readBufferSize int, pool channel.ConnectionPoolConfig) (channel.AppChannel, error) {
+	opts := []grpc.DialOption{}
+	if pool.KeepAliveInterval > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                pool.KeepAliveInterval,
+			PermitWithoutStream: true,
+		}))
+	}
+
+	conn, err := g.GetGRPCConnection(context.TODO(), fmt.Sprintf("127.0.0.1:%v", port), "", "", true, false, sslEnabled, opts...)
 	if err != nil {
 		return nil, errors.Errorf("error establishing connection to app grpc on port %v: %s", port, err)
 	}
@@ -81,6 +92,33 @@ func (g *Manager) CreateLocalChannel(port, maxConcurrency int, spec config.Traci
 	return ch, nil
 }
 
+// CreateUnixSocketChannel creates a new gRPC AppChannel to an app listening on a unix domain socket.
+func (g *Manager) CreateUnixSocketChannel(socket string, maxConcurrency int, spec config.TracingSpec, maxRequestBodySize int, readBufferSize int, pool channel.ConnectionPoolConfig) (channel.AppChannel, error) {
+	opts := []grpc.DialOption{
+		grpc.WithInsecure(),
+	}
+	if diag.DefaultGRPCMonitoring.IsEnabled() {
+		opts = append(opts, grpc.WithUnaryInterceptor(diag.DefaultGRPCMonitoring.UnaryClientInterceptor()))
+	}
+	if pool.KeepAliveInterval > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                pool.KeepAliveInterval,
+			PermitWithoutStream: true,
+		}))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, "unix://"+socket, opts...)
+	if err != nil {
+		return nil, errors.Errorf("error establishing connection to app grpc on socket %s: %s", socket, err)
+	}
+
+	g.AppClient = conn
+	ch := grpc_channel.CreateUnixSocketChannel(socket, maxConcurrency, conn, spec, maxRequestBodySize, readBufferSize)
+	return ch, nil
+}
+
 // GetGRPCConnection returns a new grpc connection for a given address and inits one if doesn't exist.
 func (g *Manager) GetGRPCConnection(ctx context.Context, address, id string, namespace string, skipTLS, recreateIfExists, sslEnabled bool, customOpts ...grpc.DialOption) (*grpc.ClientConn, error) {
 	g.lock.RLock()
@@ -107,23 +145,15 @@ func (g *Manager) GetGRPCConnection(ctx context.Context, address, id string, nam
 
 	transportCredentialsAdded := false
 	if !skipTLS && g.auth != nil {
-		signedCert := g.auth.GetCurrentSignedCert()
-		cert, err := tls.X509KeyPair(signedCert.WorkloadCert, signedCert.PrivateKeyPem)
-		if err != nil {
-			return nil, errors.Errorf("error generating x509 Key Pair: %s", err)
-		}
-
 		var serverName string
 		if id != "cluster.local" {
 			serverName = fmt.Sprintf("%s.%s.svc.cluster.local", id, namespace)
 		}
 
-		// nolint:gosec
-		ta := credentials.NewTLS(&tls.Config{
-			ServerName:   serverName,
-			Certificates: []tls.Certificate{cert},
-			RootCAs:      signedCert.TrustChain,
-		})
+		ta, err := newWorkloadCertCredentials(g.auth, serverName)
+		if err != nil {
+			return nil, err
+		}
 		opts = append(opts, grpc.WithTransportCredentials(ta))
 		transportCredentialsAdded = true
 	}
@@ -158,3 +188,56 @@ func (g *Manager) GetGRPCConnection(ctx context.Context, address, id string, nam
 
 	return conn, nil
 }
+
+// workloadCertCredentials are TLS transport credentials using the current workload cert and trust chain on every
+// handshake, so that the pooled connections reconnecting after a cert rotation don't use the expired cert.
+type workloadCertCredentials struct {
+	credentials.TransportCredentials
+	auth       security.Authenticator
+	serverName string
+}
+
+func newWorkloadCertCredentials(auth security.Authenticator, serverName string) (credentials.TransportCredentials, error) {
+	creds, err := workloadCertTLS(auth, serverName)
+	if err != nil {
+		return nil, err
+	}
+	return &workloadCertCredentials{
+		TransportCredentials: creds,
+		auth:                 auth,
+		serverName:           serverName,
+	}, nil
+}
+
+func workloadCertTLS(auth security.Authenticator, serverName string) (credentials.TransportCredentials, error) {
+	signedCert := auth.GetCurrentSignedCert()
+	cert, err := tls.X509KeyPair(signedCert.WorkloadCert, signedCert.PrivateKeyPem)
+	if err != nil {
+		return nil, errors.Errorf("error generating x509 Key Pair: %s", err)
+	}
+
+	// nolint:gosec
+	return credentials.NewTLS(&tls.Config{
+		ServerName:   serverName,
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      signedCert.TrustChain,
+		// The trust domain of the server is verified against the trust anchors of its own trust domain.
+		VerifyPeerCertificate: signedCert.VerifyPeerTrustDomain,
+	}), nil
+}
+
+func (c *workloadCertCredentials) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	creds, err := workloadCertTLS(c.auth, c.serverName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return creds.ClientHandshake(ctx, authority, rawConn)
+}
+
+func (c *workloadCertCredentials) Clone() credentials.TransportCredentials {
+	return &workloadCertCredentials{
+		TransportCredentials: c.TransportCredentials.Clone(),
+		auth:                 c.auth,
+		serverName:           c.serverName,
+	}
+}
@@ -15,12 +15,23 @@ package grpc
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
 
 	"github.com/dapr/dapr/pkg/modes"
 	"github.com/dapr/dapr/pkg/runtime/security"
@@ -87,3 +98,111 @@ func TestSetAuthenticator(t *testing.T) {
 
 	assert.Equal(t, a, m.auth)
 }
+
+type rotatingAuthenticatorMock struct {
+	authenticatorMock
+	signedCert *security.SignedCertificate
+}
+
+func (a *rotatingAuthenticatorMock) GetCurrentSignedCert() *security.SignedCertificate {
+	return a.signedCert
+}
+
+func (a *rotatingAuthenticatorMock) CreateSignedWorkloadCert(id, namespace, trustDomain string) (*security.SignedCertificate, error) {
+	return a.signedCert, nil
+}
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "cluster.local"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &testCA{cert: cert, key: key, pool: pool}
+}
+
+func (c *testCA) issue(t *testing.T, serial int64, dnsName string) *security.SignedCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{dnsName},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, c.cert, &key.PublicKey, c.key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &security.SignedCertificate{
+		WorkloadCert:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		PrivateKeyPem: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}),
+		Expiry:        template.NotAfter,
+		TrustChain:    c.pool,
+	}
+}
+
+func TestWorkloadCertCredentials(t *testing.T) {
+	const serverName = "app.default.svc.cluster.local"
+
+	ca := newTestCA(t)
+	serverSignedCert := ca.issue(t, 100, serverName)
+	serverCert, err := tls.X509KeyPair(serverSignedCert.WorkloadCert, serverSignedCert.PrivateKeyPem)
+	require.NoError(t, err)
+
+	auth := &rotatingAuthenticatorMock{signedCert: ca.issue(t, 2, "client")}
+	creds, err := newWorkloadCertCredentials(auth, serverName)
+	require.NoError(t, err)
+
+	// handshake returns the serial number of the client cert received by the server.
+	handshake := func(creds credentials.TransportCredentials) int64 {
+		clientConn, serverConn := net.Pipe()
+		defer clientConn.Close()
+		defer serverConn.Close()
+
+		server := tls.Server(serverConn, &tls.Config{
+			Certificates: []tls.Certificate{serverCert},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    ca.pool,
+			MinVersion:   tls.VersionTLS12,
+		})
+		serverErr := make(chan error, 1)
+		go func() {
+			serverErr <- server.Handshake()
+		}()
+
+		_, _, err := creds.ClientHandshake(context.Background(), "app:50002", clientConn)
+		require.NoError(t, err)
+		require.NoError(t, <-serverErr)
+		return server.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+	}
+
+	assert.Equal(t, int64(2), handshake(creds))
+
+	// new handshakes use the rotated cert
+	auth.signedCert = ca.issue(t, 3, "client")
+	assert.Equal(t, int64(3), handshake(creds))
+	assert.Equal(t, int64(3), handshake(creds.Clone()))
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/dapr/dapr/pkg/audit"
+	diag "github.com/dapr/dapr/pkg/diagnostics"
+	v1 "github.com/dapr/dapr/pkg/messaging/v1"
+	"github.com/dapr/dapr/pkg/policy"
+	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
+	auth "github.com/dapr/dapr/pkg/runtime/security"
+)
+
+const daprServicePrefix = "/dapr.proto.runtime.v1.Dapr/"
+
+// endpointAPI is the name and the version of an API.
+type endpointAPI struct {
+	name    string
+	version string
+}
+
+// endpointAPIs maps the gRPC methods of the Dapr service to their API.
+var endpointAPIs = func() map[string]endpointAPI {
+	apis := map[string]endpointAPI{}
+	for key, methods := range endpoints {
+		parts := strings.SplitN(key, ".", 2)
+		for _, method := range methods {
+			apis[method] = endpointAPI{name: parts[0], version: parts[1]}
+		}
+	}
+	return apis
+}()
+
+// grpcOperations maps the gRPC methods of the Dapr service to their policy operation. The operation of the other
+// methods is the name of the method, e.g. registerActorTimer for RegisterActorTimer.
+var grpcOperations = map[string]string{
+	"InvokeService":                policy.OperationInvoke,
+	"GetState":                     policy.OperationGet,
+	"GetBulkState":                 policy.OperationBulkGet,
+	"SaveState":                    policy.OperationSave,
+	"QueryStateAlpha1":             policy.OperationQuery,
+	"DeleteState":                  policy.OperationDelete,
+	"DeleteBulkState":              policy.OperationBulkDelete,
+	"ExecuteStateTransaction":      policy.OperationTransaction,
+	"PublishEvent":                 policy.OperationPublish,
+	"InvokeBinding":                policy.OperationInvoke,
+	"InvokeBindingStreamAlpha1":    policy.OperationInvoke,
+	"InvokeBindingBulkAlpha1":      policy.OperationBulkInvoke,
+	"GetSecret":                    policy.OperationGet,
+	"GetBulkSecret":                policy.OperationBulkGet,
+	"SubscribeSecretsAlpha1":       policy.OperationSubscribe,
+	"GetActorState":                policy.OperationGet,
+	"ExecuteActorStateTransaction": policy.OperationTransaction,
+	"InvokeActor":                  policy.OperationInvoke,
+	"GetConfigurationAlpha1":       policy.OperationGet,
+	"SubscribeConfigurationAlpha1": policy.OperationSubscribe,
+	"GetMetadata":                  policy.OperationGet,
+	"SetMetadata":                  policy.OperationSave,
+}
+
+// authorizeFunc returns an error when a call of an API isn't authorized.
+type authorizeFunc func(req policy.Request) error
+
+func setAuthorizationMiddlewareUnary(authorize authorizeFunc, appID string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		policyReq := policyRequest(ctx, info.FullMethod, appID)
+		setPolicyRequestTarget(&policyReq, req)
+
+		if err := authorizeResources(authorize, policyReq, policyRequestResources(req)); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func setAuthorizationMiddlewareStream(authorize authorizeFunc, appID string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		policyReq := policyRequest(stream.Context(), info.FullMethod, appID)
+		if strings.HasPrefix(info.FullMethod, daprServicePrefix) {
+			// The target of a streaming call of the Dapr service is known once its first message is received.
+			return handler(srv, &authorizedServerStream{ServerStream: stream, authorize: authorize, req: policyReq})
+		}
+
+		// Calls of other services are proxied to the app in the dapr-app-id metadata.
+		policyReq.API = "invoke"
+		policyReq.Version = "v1"
+		policyReq.Operation = policy.OperationInvoke
+		policyReq.Resource = strings.TrimPrefix(info.FullMethod, "/")
+		if md, ok := metadata.FromIncomingContext(stream.Context()); ok {
+			if v := md.Get(diag.GRPCProxyAppIDKey); len(v) > 0 {
+				policyReq.Component = v[0]
+			}
+		}
+
+		if err := authorizeResources(authorize, policyReq, nil); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+// authorizedServerStream authorizes a streaming call on the first message received.
+type authorizedServerStream struct {
+	grpc.ServerStream
+	authorize  authorizeFunc
+	req        policy.Request
+	authorized bool
+}
+
+func (s *authorizedServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.authorized {
+		return nil
+	}
+
+	setPolicyRequestTarget(&s.req, m)
+	if err := authorizeResources(s.authorize, s.req, policyRequestResources(m)); err != nil {
+		return err
+	}
+	s.authorized = true
+	return nil
+}
+
+// policyRequest returns the policy request of a call of a gRPC method.
+// The caller is the subject of the bearer token of the call, or the app id when the call isn't authenticated with OIDC.
+func policyRequest(ctx context.Context, fullMethod, appID string) policy.Request {
+	caller, ok := auth.SubjectFromContext(ctx)
+	if !ok {
+		caller = appID
+	}
+
+	api := endpointAPIs[fullMethod]
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	operation, ok := grpcOperations[method]
+	if !ok && method != "" {
+		operation = strings.ToLower(method[:1]) + method[1:]
+	}
+	return policy.Request{
+		Caller:    caller,
+		API:       api.name,
+		Version:   api.version,
+		Operation: operation,
+		Method:    method,
+		Protocol:  protocol,
+	}
+}
+
+// setPolicyRequestTarget sets the component and the resource of a policy request from the request message.
+func setPolicyRequestTarget(policyReq *policy.Request, req interface{}) {
+	switch r := req.(type) {
+	case interface{ GetPubsubName() string }:
+		policyReq.Component = r.GetPubsubName()
+	case interface{ GetStoreName() string }:
+		policyReq.Component = r.GetStoreName()
+	case interface{ GetActorType() string }:
+		policyReq.Component = r.GetActorType()
+	case interface{ GetId() string }:
+		policyReq.Component = r.GetId()
+	case interface{ GetName() string }:
+		policyReq.Component = r.GetName()
+	}
+
+	switch r := req.(type) {
+	case *runtimev1pb.InvokeServiceRequest:
+		policyReq.Resource = r.GetMessage().GetMethod()
+	case interface{ GetTopic() string }:
+		policyReq.Resource = r.GetTopic()
+	case interface{ GetActorId() string }:
+		policyReq.Resource = r.GetActorId()
+	case interface{ GetKey() string }:
+		policyReq.Resource = r.GetKey()
+	}
+}
+
+// policyRequestResources returns the keys of the bulk and the transaction requests, on which the call is authorized.
+func policyRequestResources(req interface{}) []string {
+	var keys []string
+	switch r := req.(type) {
+	case *runtimev1pb.SaveStateRequest:
+		for _, s := range r.GetStates() {
+			keys = append(keys, s.GetKey())
+		}
+	case *runtimev1pb.DeleteBulkStateRequest:
+		for _, s := range r.GetStates() {
+			keys = append(keys, s.GetKey())
+		}
+	case *runtimev1pb.GetBulkStateRequest:
+		keys = r.GetKeys()
+	case *runtimev1pb.ExecuteStateTransactionRequest:
+		for _, o := range r.GetOperations() {
+			keys = append(keys, o.GetRequest().GetKey())
+		}
+	case *runtimev1pb.ExecuteActorStateTransactionRequest:
+		for _, o := range r.GetOperations() {
+			keys = append(keys, o.GetKey())
+		}
+	}
+	return keys
+}
+
+// authorizeResources authorizes a call on each of its resources, and audits the first one denied.
+func authorizeResources(authorize authorizeFunc, req policy.Request, resources []string) error {
+	for _, r := range req.ForResources(resources) {
+		if err := authorize(r); err != nil {
+			auditAccessDenied(r, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// auditAccessDenied emits the audit event of a call rejected by the access rules or the policies of the API.
+func auditAccessDenied(req policy.Request, err error) {
+	resource := req.Method
+	if req.Component != "" {
+		resource = req.Method + " " + req.Component
+	}
+	audit.Default.Emit(audit.Event{
+		Type:     audit.EventAccessDenied,
+		Subject:  req.Caller,
+		Resource: resource,
+		Reason:   status.Convert(err).Message(),
+	})
+}
+
+// authorizeWithPolicies authorizes the calls with the policies of the engine.
+func authorizeWithPolicies(engine *policy.Engine) authorizeFunc {
+	return func(req policy.Request) error {
+		allowed, name, err := engine.Authorize(req)
+		if err != nil {
+			return v1.ErrorFromHTTPResponseCode(http.StatusForbidden, err.Error())
+		}
+		if !allowed {
+			if name == "" {
+				return v1.ErrorFromHTTPResponseCode(http.StatusForbidden, fmt.Sprintf("access to %s denied by the default policy", req.Method))
+			}
+			return v1.ErrorFromHTTPResponseCode(http.StatusForbidden, fmt.Sprintf("access to %s denied by policy %s", req.Method, name))
+		}
+		return nil
+	}
+}
+
+// authorizeWithAccessList authorizes the calls with the API allow list and deny list.
+func authorizeWithAccessList(access *policy.AccessList) authorizeFunc {
+	return func(req policy.Request) error {
+		if !access.Allowed(req) {
+			return v1.ErrorFromHTTPResponseCode(http.StatusForbidden, fmt.Sprintf("access to %s denied by the API access rules", req.Method))
+		}
+		return nil
+	}
+}
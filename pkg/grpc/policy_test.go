@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/dapr/pkg/policy"
+	commonv1pb "github.com/dapr/dapr/pkg/proto/common/v1"
+	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
+	auth "github.com/dapr/dapr/pkg/runtime/security"
+)
+
+func TestSetPolicyMiddlewareUnary(t *testing.T) {
+	engine, err := policy.NewEngine(config.APIPolicySpec{
+		DefaultAction: config.DenyAccess,
+		Rules: []config.APIPolicyRule{
+			{
+				Name:      "allow-orders-state",
+				Condition: "caller == 'myapp' && api == 'state' && component == 'orders' && operation == 'get'",
+				Action:    config.AllowAccess,
+			},
+			{
+				Name:      "allow-orders-save",
+				Condition: "api == 'state' && component == 'orders' && operation in ['save', 'transaction'] && !resource.startsWith('secret-')",
+				Action:    config.AllowAccess,
+			},
+			{
+				Name:      "allow-checkout-invoke",
+				Condition: "caller == 'checkout' && api == 'invoke' && component == 'payments' && resource == 'pay'",
+				Action:    config.AllowAccess,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	h := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	f := setAuthorizationMiddlewareUnary(authorizeWithPolicies(engine), "myapp")
+
+	t.Run("allowed by policy", func(t *testing.T) {
+		_, err := f(context.Background(), &runtimev1pb.GetStateRequest{StoreName: "orders", Key: "1"}, &grpc.UnaryServerInfo{
+			FullMethod: "/dapr.proto.runtime.v1.Dapr/GetState",
+		}, h)
+		assert.NoError(t, err)
+	})
+
+	t.Run("denied by default", func(t *testing.T) {
+		_, err := f(context.Background(), &runtimev1pb.GetStateRequest{StoreName: "inventory", Key: "1"}, &grpc.UnaryServerInfo{
+			FullMethod: "/dapr.proto.runtime.v1.Dapr/GetState",
+		}, h)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("every key of a bulk request allowed", func(t *testing.T) {
+		req := &runtimev1pb.SaveStateRequest{StoreName: "orders", States: []*commonv1pb.StateItem{{Key: "1"}, {Key: "2"}}}
+		_, err := f(context.Background(), req, &grpc.UnaryServerInfo{
+			FullMethod: "/dapr.proto.runtime.v1.Dapr/SaveState",
+		}, h)
+		assert.NoError(t, err)
+	})
+
+	t.Run("one key of a transaction denied", func(t *testing.T) {
+		req := &runtimev1pb.ExecuteStateTransactionRequest{
+			StoreName: "orders",
+			Operations: []*runtimev1pb.TransactionalStateOperation{
+				{OperationType: "upsert", Request: &commonv1pb.StateItem{Key: "1"}},
+				{OperationType: "delete", Request: &commonv1pb.StateItem{Key: "secret-1"}},
+			},
+		}
+		_, err := f(context.Background(), req, &grpc.UnaryServerInfo{
+			FullMethod: "/dapr.proto.runtime.v1.Dapr/ExecuteStateTransaction",
+		}, h)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("caller from bearer token", func(t *testing.T) {
+		req := &runtimev1pb.InvokeServiceRequest{Id: "payments", Message: &commonv1pb.InvokeRequest{Method: "pay"}}
+		info := &grpc.UnaryServerInfo{FullMethod: "/dapr.proto.runtime.v1.Dapr/InvokeService"}
+
+		_, err := f(auth.WithSubject(context.Background(), "checkout"), req, info, h)
+		assert.NoError(t, err)
+
+		_, err = f(context.Background(), req, info, h)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestAccessListMiddlewareUnary(t *testing.T) {
+	access := policy.NewAccessList(config.APISpec{
+		Allowed: []config.APIAccessRule{
+			{Name: "state", Version: "v1", Protocol: "grpc", Resources: []string{"orders-*"}},
+			{Name: "publish", Version: "v1", Protocol: "grpc"},
+		},
+		Denied: []config.APIAccessRule{
+			{Name: "state", Version: "v1", Protocol: "grpc", Operations: []string{"DeleteState", "DeleteBulkState"}},
+			{Name: "state", Version: "v1", Protocol: "http"},
+		},
+	}, protocol)
+	require.NotNil(t, access)
+
+	h := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	f := setAuthorizationMiddlewareUnary(authorizeWithAccessList(access), "myapp")
+
+	t.Run("allowed resource", func(t *testing.T) {
+		_, err := f(context.Background(), &runtimev1pb.GetStateRequest{StoreName: "orders-eu", Key: "1"}, &grpc.UnaryServerInfo{
+			FullMethod: "/dapr.proto.runtime.v1.Dapr/GetState",
+		}, h)
+		assert.NoError(t, err)
+	})
+
+	t.Run("resource not allowed", func(t *testing.T) {
+		_, err := f(context.Background(), &runtimev1pb.GetStateRequest{StoreName: "inventory", Key: "1"}, &grpc.UnaryServerInfo{
+			FullMethod: "/dapr.proto.runtime.v1.Dapr/GetState",
+		}, h)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("denied operation", func(t *testing.T) {
+		_, err := f(context.Background(), &runtimev1pb.DeleteStateRequest{StoreName: "orders-eu", Key: "1"}, &grpc.UnaryServerInfo{
+			FullMethod: "/dapr.proto.runtime.v1.Dapr/DeleteState",
+		}, h)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("allowed api", func(t *testing.T) {
+		_, err := f(context.Background(), &runtimev1pb.PublishEventRequest{PubsubName: "pubsub", Topic: "orders"}, &grpc.UnaryServerInfo{
+			FullMethod: "/dapr.proto.runtime.v1.Dapr/PublishEvent",
+		}, h)
+		assert.NoError(t, err)
+	})
+
+	t.Run("api not allowed", func(t *testing.T) {
+		_, err := f(context.Background(), &runtimev1pb.GetSecretRequest{StoreName: "vault", Key: "1"}, &grpc.UnaryServerInfo{
+			FullMethod: "/dapr.proto.runtime.v1.Dapr/GetSecret",
+		}, h)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
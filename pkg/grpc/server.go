@@ -15,6 +15,7 @@ package grpc
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io"
 	"net"
@@ -25,14 +26,17 @@ import (
 	"github.com/pkg/errors"
 	grpc_go "google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/keepalive"
 
 	"github.com/dapr/kit/logger"
 
 	"github.com/dapr/dapr/pkg/config"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
-	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
+	"github.com/dapr/dapr/pkg/grpc/compression"
 	"github.com/dapr/dapr/pkg/messaging"
+	dapr_middleware "github.com/dapr/dapr/pkg/middleware/grpc"
+	"github.com/dapr/dapr/pkg/policy"
 	internalv1pb "github.com/dapr/dapr/pkg/proto/internals/v1"
 	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
 	auth "github.com/dapr/dapr/pkg/runtime/security"
@@ -60,15 +64,21 @@ type server struct {
 	authenticator      auth.Authenticator
 	servers            []*grpc_go.Server
 	renewMutex         *sync.Mutex
+	certMutex          *sync.RWMutex
 	signedCert         *auth.SignedCertificate
 	tlsCert            tls.Certificate
 	signedCertDuration time.Duration
 	kind               string
 	logger             logger.Logger
 	maxConnectionAge   *time.Duration
-	authToken          string
+	authTokens         []string
 	apiSpec            config.APISpec
+	oidcValidator      *auth.OIDCValidator
+	policyEngine       *policy.Engine
+	accessList         *policy.AccessList
 	proxy              messaging.Proxy
+	healthServers      []*health.Server
+	pipeline           dapr_middleware.Pipeline
 }
 
 var (
@@ -77,7 +87,8 @@ var (
 )
 
 // NewAPIServer returns a new user facing gRPC API server.
-func NewAPIServer(api API, config ServerConfig, tracingSpec config.TracingSpec, metricSpec config.MetricSpec, apiSpec config.APISpec, proxy messaging.Proxy) Server {
+// The interceptors of the pipeline run after the authentication and authorization of the calls.
+func NewAPIServer(api API, config ServerConfig, tracingSpec config.TracingSpec, metricSpec config.MetricSpec, apiSpec config.APISpec, pipeline dapr_middleware.Pipeline, proxy messaging.Proxy) Server {
 	return &server{
 		api:         api,
 		config:      config,
@@ -85,8 +96,9 @@ func NewAPIServer(api API, config ServerConfig, tracingSpec config.TracingSpec,
 		metricSpec:  metricSpec,
 		kind:        apiServer,
 		logger:      apiServerLogger,
-		authToken:   auth.GetAPIToken(),
+		authTokens:  auth.GetAPITokens(),
 		apiSpec:     apiSpec,
+		pipeline:    pipeline,
 		proxy:       proxy,
 	}
 }
@@ -100,6 +112,7 @@ func NewInternalServer(api API, config ServerConfig, tracingSpec config.TracingS
 		metricSpec:       metricSpec,
 		authenticator:    authenticator,
 		renewMutex:       &sync.Mutex{},
+		certMutex:        &sync.RWMutex{},
 		kind:             internalServer,
 		logger:           internalServerLogger,
 		maxConnectionAge: getDefaultMaxAgeDuration(),
@@ -137,6 +150,15 @@ func (s *server) StartNonBlocking() error {
 		return errors.Errorf("could not listen on any endpoint")
 	}
 
+	// The workload cert is shared by the servers of all the listeners and rotated in place.
+	if s.authenticator != nil {
+		err := s.generateWorkloadCert()
+		if err != nil {
+			return err
+		}
+		go s.startWorkloadCertRotation()
+	}
+
 	for _, listener := range listeners {
 		// server is created in a loop because each instance
 		// has a handle on the underlying listener.
@@ -150,6 +172,7 @@ func (s *server) StartNonBlocking() error {
 			internalv1pb.RegisterServiceInvocationServer(server, s.api)
 		} else if s.kind == apiServer {
 			runtimev1pb.RegisterDaprServer(server, s.api)
+			s.registerOptionalServices(server)
 		}
 
 		go func(server *grpc_go.Server, l net.Listener) {
@@ -162,6 +185,10 @@ func (s *server) StartNonBlocking() error {
 }
 
 func (s *server) Close() error {
+	// the health checks report the Dapr service as not serving while the calls in progress complete.
+	for _, healthServer := range s.healthServers {
+		healthServer.Shutdown()
+	}
 	for _, server := range s.servers {
 		// This calls `Close()` on the underlying listener.
 		server.GracefulStop()
@@ -180,31 +207,104 @@ func (s *server) generateWorkloadCert() error {
 
 	tlsCert, err := tls.X509KeyPair(signedCert.WorkloadCert, signedCert.PrivateKeyPem)
 	if err != nil {
+		diag.DefaultMonitoring.MTLSWorkLoadCertRotationFailed("key_pair")
 		return errors.Wrap(err, "error creating x509 Key Pair")
 	}
 
+	s.certMutex.Lock()
 	s.signedCert = signedCert
 	s.tlsCert = tlsCert
 	s.signedCertDuration = signedCert.Expiry.Sub(time.Now().UTC())
+	s.certMutex.Unlock()
+
+	s.recordCertExpiry(tlsCert)
 	return nil
 }
 
+// recordCertExpiry records the expiry of the workload cert and of the issuer cert which follows it in the chain.
+func (s *server) recordCertExpiry(tlsCert tls.Certificate) {
+	for i, der := range tlsCert.Certificate {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			s.logger.Warnf("error parsing certificate of the workload cert chain: %s", err)
+			return
+		}
+
+		switch i {
+		case 0:
+			s.logger.Infof("workload cert expires on %s", cert.NotAfter.String())
+			diag.DefaultMonitoring.MTLSWorkloadCertExpiry(cert.NotAfter)
+		case 1:
+			s.logger.Infof("issuer cert expires on %s", cert.NotAfter.String())
+			diag.DefaultMonitoring.MTLSIssuerCertExpiry(cert.NotAfter)
+		default:
+			return
+		}
+	}
+}
+
+// getTLSConfigForClient returns the TLS config of the current workload cert and trust chain, so that the new
+// connections use the rotated cert without restarting the server.
+func (s *server) getTLSConfigForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	s.certMutex.RLock()
+	defer s.certMutex.RUnlock()
+
+	// nolint:gosec
+	return &tls.Config{
+		Certificates: []tls.Certificate{s.tlsCert},
+		ClientCAs:    s.signedCert.TrustChain,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		// Clients of federated trust domains must be signed by the trust anchors of their own trust domain.
+		VerifyPeerCertificate: s.signedCert.VerifyPeerTrustDomain,
+		// The config replaces the one of the gRPC credentials, which negotiates HTTP/2.
+		NextProtos: []string{"h2"},
+	}, nil
+}
+
 func (s *server) getMiddlewareOptions() []grpc_go.ServerOption {
 	opts := []grpc_go.ServerOption{}
 	intr := []grpc_go.UnaryServerInterceptor{}
 	intrStream := []grpc_go.StreamServerInterceptor{}
 
-	if len(s.apiSpec.Allowed) > 0 {
-		s.logger.Info("enabled API access list on gRPC server")
-		intr = append(intr, setAPIEndpointsMiddlewareUnary(s.apiSpec.Allowed))
+	// The access log is first so that the calls rejected by the access list and authorization are logged.
+	if diag.DefaultAccessLog.IsEnabled() {
+		s.logger.Info("enabled gRPC access log middleware")
+		intr = append(intr, diag.DefaultAccessLog.UnaryServerInterceptor())
+		intrStream = append(intrStream, diag.DefaultAccessLog.StreamServerInterceptor())
+	}
+
+	if len(s.authTokens) > 0 {
+		s.logger.Infof("enabled token authentication on gRPC server with %d valid tokens", len(s.authTokens))
+		intr = append(intr, setAPIAuthenticationMiddlewareUnary(s.authTokens, auth.APITokenHeader))
+	}
+
+	if s.oidcValidator != nil {
+		s.logger.Infof("enabled OIDC authentication on gRPC server with issuer %s", s.apiSpec.Authentication.OIDC.Issuer)
+		intr = append(intr, setOIDCAuthenticationMiddlewareUnary(s.oidcValidator))
+		intrStream = append(intrStream, setOIDCAuthenticationMiddlewareStream(s.oidcValidator))
+	}
+
+	if s.accessList != nil {
+		s.logger.Info("enabled API access rules on gRPC server")
+		authorize := authorizeWithAccessList(s.accessList)
+		intr = append(intr, setAuthorizationMiddlewareUnary(authorize, s.config.AppID))
+		intrStream = append(intrStream, setAuthorizationMiddlewareStream(authorize, s.config.AppID))
+	}
+
+	if s.policyEngine != nil {
+		s.logger.Infof("enabled API policies on gRPC server with %d rules", len(s.apiSpec.Policy.Rules))
+		authorize := authorizeWithPolicies(s.policyEngine)
+		intr = append(intr, setAuthorizationMiddlewareUnary(authorize, s.config.AppID))
+		intrStream = append(intrStream, setAuthorizationMiddlewareStream(authorize, s.config.AppID))
 	}
 
-	if s.authToken != "" {
-		s.logger.Info("enabled token authentication on gRPC server")
-		intr = append(intr, setAPIAuthenticationMiddlewareUnary(s.authToken, auth.APITokenHeader))
+	if !s.pipeline.IsEmpty() {
+		s.logger.Infof("enabled %d unary and %d stream custom interceptors on gRPC server", len(s.pipeline.UnaryInterceptors), len(s.pipeline.StreamInterceptors))
+		intr = append(intr, s.pipeline.UnaryInterceptors...)
+		intrStream = append(intrStream, s.pipeline.StreamInterceptors...)
 	}
 
-	if diag_utils.IsTracingEnabled(s.tracingSpec.SamplingRate) {
+	if diag.IsTracingEnabled(s.tracingSpec) {
 		s.logger.Info("enabled gRPC tracing middleware")
 		intr = append(intr, diag.GRPCTraceUnaryServerInterceptor(s.config.AppID, s.tracingSpec))
 
@@ -221,15 +321,21 @@ func (s *server) getMiddlewareOptions() []grpc_go.ServerOption {
 	chain := grpc_middleware.ChainUnaryServer(
 		intr...,
 	)
+	if s.apiSpec.GRPC.Health {
+		chain = skipHealthServiceUnary(chain)
+	}
 	opts = append(
 		opts,
 		grpc_go.UnaryInterceptor(chain),
 	)
 
-	if s.proxy != nil {
+	if len(intrStream) > 0 {
 		chainStream := grpc_middleware.ChainStreamServer(
 			intrStream...,
 		)
+		if s.apiSpec.GRPC.Health {
+			chainStream = skipHealthServiceStream(chainStream)
+		}
 
 		opts = append(opts, grpc_go.StreamInterceptor(chainStream))
 	}
@@ -237,32 +343,65 @@ func (s *server) getMiddlewareOptions() []grpc_go.ServerOption {
 	return opts
 }
 
+// initOIDCValidator creates the validator of the bearer tokens of the OIDC issuer configured for the APIs.
+// The validator is shared by the servers of all the listeners, so that they share the JWKS cache.
+func (s *server) initOIDCValidator() error {
+	oidcSpec := s.apiSpec.Authentication.OIDC
+	if oidcSpec == nil || s.oidcValidator != nil {
+		return nil
+	}
+
+	validator, err := auth.NewOIDCValidator(oidcSpec.Issuer, oidcSpec.JWKSURL, oidcSpec.Audiences)
+	if err != nil {
+		return errors.Wrap(err, "error creating OIDC authentication")
+	}
+	s.oidcValidator = validator
+	return nil
+}
+
+// initPolicyEngine compiles the policies configured for the APIs.
+func (s *server) initPolicyEngine() error {
+	policySpec := s.apiSpec.Policy
+	if (len(policySpec.Rules) == 0 && policySpec.DefaultAction == "") || s.policyEngine != nil {
+		return nil
+	}
+
+	engine, err := policy.NewEngine(policySpec)
+	if err != nil {
+		return errors.Wrap(err, "error creating API policies")
+	}
+	s.policyEngine = engine
+	return nil
+}
+
 func (s *server) getGRPCServer() (*grpc_go.Server, error) {
+	if err := s.initOIDCValidator(); err != nil {
+		return nil, err
+	}
+	if err := s.initPolicyEngine(); err != nil {
+		return nil, err
+	}
+	if s.kind == apiServer {
+		s.accessList = policy.NewAccessList(s.apiSpec, protocol)
+	}
+
 	opts := s.getMiddlewareOptions()
 	if s.maxConnectionAge != nil {
 		opts = append(opts, grpc_go.KeepaliveParams(keepalive.ServerParameters{MaxConnectionAge: *s.maxConnectionAge}))
 	}
 
 	if s.authenticator != nil {
-		err := s.generateWorkloadCert()
-		if err != nil {
-			return nil, err
-		}
-
 		// nolint:gosec
 		tlsConfig := tls.Config{
-			ClientCAs:  s.signedCert.TrustChain,
-			ClientAuth: tls.RequireAndVerifyClientCert,
-			GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
-				return &s.tlsCert, nil
-			},
+			GetConfigForClient: s.getTLSConfigForClient,
 		}
 		ta := credentials.NewTLS(&tlsConfig)
 
 		opts = append(opts, grpc_go.Creds(ta))
-		go s.startWorkloadCertRotation()
 	}
 
+	// Compressed messages are limited to the same size once decompressed.
+	compression.SetMaxDecompressedSize(s.config.MaxRequestBodySize * 1024 * 1024)
 	opts = append(opts, grpc_go.MaxRecvMsgSize(s.config.MaxRequestBodySize*1024*1024), grpc_go.MaxSendMsgSize(s.config.MaxRequestBodySize*1024*1024), grpc_go.MaxHeaderListSize(uint32(s.config.ReadBufferSize*1024)))
 
 	if s.proxy != nil {
@@ -281,13 +420,16 @@ func (s *server) startWorkloadCertRotation() {
 		s.renewMutex.Lock()
 		renew := shouldRenewCert(s.signedCert.Expiry, s.signedCertDuration)
 		if renew {
-			s.logger.Info("renewing certificate: requesting new cert and restarting gRPC server")
+			s.logger.Info("renewing certificate: requesting new cert")
 
 			err := s.generateWorkloadCert()
 			if err != nil {
-				s.logger.Errorf("error starting server: %s", err)
+				s.logger.Errorf("error renewing certificate: %s", err)
+				diag.DefaultEventLog.Record(diag.RuntimeEventCertificateRotationFailed, "failed to renew the workload certificate: %s", err)
+			} else {
+				diag.DefaultEventLog.Record(diag.RuntimeEventCertificateRotated, "renewed the workload certificate, which expires on %s", s.signedCert.Expiry)
+				diag.DefaultMonitoring.MTLSWorkLoadCertRotationCompleted()
 			}
-			diag.DefaultMonitoring.MTLSWorkLoadCertRotationCompleted()
 		}
 		s.renewMutex.Unlock()
 	}
@@ -1,6 +1,8 @@
 package grpc
 
 import (
+	"context"
+	"crypto/x509"
 	"fmt"
 	"sync"
 	"testing"
@@ -9,10 +11,12 @@ import (
 	"github.com/phayes/freeport"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	grpc_go "google.golang.org/grpc"
 
 	"github.com/dapr/kit/logger"
 
 	"github.com/dapr/dapr/pkg/config"
+	dapr_middleware "github.com/dapr/dapr/pkg/middleware/grpc"
 	dapr_testing "github.com/dapr/dapr/pkg/testing"
 )
 
@@ -35,6 +39,29 @@ func TestCertRenewal(t *testing.T) {
 	})
 }
 
+func TestWorkloadCertRotation(t *testing.T) {
+	ca := newTestCA(t)
+	auth := &rotatingAuthenticatorMock{signedCert: ca.issue(t, 2, "app")}
+	s := NewInternalServer(nil, ServerConfig{AppID: "app"}, config.TracingSpec{}, config.MetricSpec{}, auth, nil).(*server)
+
+	serial := func() int64 {
+		tlsConfig, err := s.getTLSConfigForClient(nil)
+		require.NoError(t, err)
+		assert.Equal(t, ca.pool, tlsConfig.ClientCAs)
+		cert, err := x509.ParseCertificate(tlsConfig.Certificates[0].Certificate[0])
+		require.NoError(t, err)
+		return cert.SerialNumber.Int64()
+	}
+
+	require.NoError(t, s.generateWorkloadCert())
+	assert.Equal(t, int64(2), serial())
+
+	// the TLS config of the new connections uses the rotated cert
+	auth.signedCert = ca.issue(t, 3, "app")
+	require.NoError(t, s.generateWorkloadCert())
+	assert.Equal(t, int64(3), serial())
+}
+
 func TestGetMiddlewareOptions(t *testing.T) {
 	t.Run("should enable unary interceptor if tracing and metrics are enabled", func(t *testing.T) {
 		fakeServer := &server{
@@ -88,6 +115,30 @@ func TestGetMiddlewareOptions(t *testing.T) {
 
 		assert.Equal(t, 1, len(serverOption))
 	})
+
+	t.Run("should have custom unary and stream interceptors", func(t *testing.T) {
+		fakeServer := &server{
+			config:     ServerConfig{},
+			renewMutex: &sync.Mutex{},
+			logger:     logger.NewLogger("dapr.runtime.grpc.test"),
+			pipeline: dapr_middleware.Pipeline{
+				UnaryInterceptors: []grpc_go.UnaryServerInterceptor{
+					func(ctx context.Context, req interface{}, info *grpc_go.UnaryServerInfo, handler grpc_go.UnaryHandler) (interface{}, error) {
+						return handler(ctx, req)
+					},
+				},
+				StreamInterceptors: []grpc_go.StreamServerInterceptor{
+					func(srv interface{}, ss grpc_go.ServerStream, info *grpc_go.StreamServerInfo, handler grpc_go.StreamHandler) error {
+						return handler(srv, ss)
+					},
+				},
+			},
+		}
+
+		serverOption := fakeServer.getMiddlewareOptions()
+
+		assert.Equal(t, 2, len(serverOption))
+	})
 }
 
 func TestClose(t *testing.T) {
@@ -95,7 +146,7 @@ func TestClose(t *testing.T) {
 	require.NoError(t, err)
 	serverConfig := NewServerConfig("test", "127.0.0.1", port, []string{"127.0.0.1"}, "test", "test", 4, "", 4)
 	a := &api{}
-	server := NewAPIServer(a, serverConfig, config.TracingSpec{}, config.MetricSpec{}, config.APISpec{}, nil)
+	server := NewAPIServer(a, serverConfig, config.TracingSpec{}, config.MetricSpec{}, config.APISpec{}, dapr_middleware.Pipeline{}, nil)
 	require.NoError(t, server.StartNonBlocking())
 	dapr_testing.WaitForListeningAddress(t, 5*time.Second, fmt.Sprintf("127.0.0.1:%d", port))
 	assert.NoError(t, server.Close())
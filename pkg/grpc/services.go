@@ -0,0 +1,64 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	grpc_go "google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
+)
+
+// healthServicePrefix is the prefix of the methods of the grpc.health.v1 health service. The health checks are served
+// without the interceptors, so that they don't need to be authenticated like the healthz endpoint of the HTTP API.
+const healthServicePrefix = "/grpc.health.v1.Health/"
+
+// registerOptionalServices registers the health and reflection services enabled in the API spec on a server of the
+// gRPC API.
+func (s *server) registerOptionalServices(server *grpc_go.Server) {
+	if s.apiSpec.GRPC.Health {
+		healthServer := health.NewServer()
+		healthServer.SetServingStatus(runtimev1pb.Dapr_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+		healthpb.RegisterHealthServer(server, healthServer)
+		s.healthServers = append(s.healthServers, healthServer)
+	}
+	if s.apiSpec.GRPC.Reflection {
+		reflection.Register(server)
+	}
+}
+
+// skipHealthServiceUnary returns an interceptor calling intr for the calls of all the services but the health service.
+func skipHealthServiceUnary(intr grpc_go.UnaryServerInterceptor) grpc_go.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc_go.UnaryServerInfo, handler grpc_go.UnaryHandler) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return handler(ctx, req)
+		}
+		return intr(ctx, req, info, handler)
+	}
+}
+
+// skipHealthServiceStream returns an interceptor calling intr for the calls of all the services but the health service.
+func skipHealthServiceStream(intr grpc_go.StreamServerInterceptor) grpc_go.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc_go.ServerStream, info *grpc_go.StreamServerInfo, handler grpc_go.StreamHandler) error {
+		if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return handler(srv, stream)
+		}
+		return intr(srv, stream, info, handler)
+	}
+}
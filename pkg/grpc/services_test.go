@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	grpc_go "google.golang.org/grpc"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func TestRegisterOptionalServices(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		s := &server{}
+		grpcServer := grpc_go.NewServer()
+		s.registerOptionalServices(grpcServer)
+		assert.Empty(t, grpcServer.GetServiceInfo())
+	})
+
+	t.Run("health and reflection", func(t *testing.T) {
+		s := &server{apiSpec: config.APISpec{GRPC: config.APIGRPCSpec{Health: true, Reflection: true}}}
+		grpcServer := grpc_go.NewServer()
+		s.registerOptionalServices(grpcServer)
+		services := grpcServer.GetServiceInfo()
+		assert.Contains(t, services, "grpc.health.v1.Health")
+		assert.Contains(t, services, "grpc.reflection.v1alpha.ServerReflection")
+		assert.Len(t, s.healthServers, 1)
+	})
+}
+
+func TestSkipHealthServiceUnary(t *testing.T) {
+	intercepted := false
+	intr := skipHealthServiceUnary(func(ctx context.Context, req interface{}, info *grpc_go.UnaryServerInfo, handler grpc_go.UnaryHandler) (interface{}, error) {
+		intercepted = true
+		return handler(ctx, req)
+	})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+
+	_, err := intr(context.Background(), nil, &grpc_go.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler)
+	assert.NoError(t, err)
+	assert.False(t, intercepted)
+
+	_, err = intr(context.Background(), nil, &grpc_go.UnaryServerInfo{FullMethod: "/dapr.proto.runtime.v1.Dapr/GetState"}, handler)
+	assert.NoError(t, err)
+	assert.True(t, intercepted)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import "time"
+
+const (
+	// StatusOK is the status of a healthy sidecar or subsystem.
+	StatusOK = "ok"
+	// StatusPending is the status of a component which is not initialized yet, e.g. waiting for its secret store.
+	StatusPending = "pending"
+	// StatusLazy is the status of a component initialized on its first use, which isn't used yet. It doesn't keep
+	// the sidecar from being healthy.
+	StatusLazy = "lazy"
+	// StatusFailed is the status of a component which failed to initialize, or of an unhealthy subsystem.
+	StatusFailed = "failed"
+)
+
+// Details is the health of each subsystem of the sidecar, so that a sidecar which is not ready reports what blocks it.
+type Details struct {
+	Status      string              `json:"status"`
+	Components  []ComponentDetails  `json:"components"`
+	AppChannel  *SubsystemDetails   `json:"appChannel,omitempty"`
+	Placement   *SubsystemDetails   `json:"placement,omitempty"`
+	Certificate *CertificateDetails `json:"certificate,omitempty"`
+}
+
+// ComponentDetails is the initialization status of a component.
+type ComponentDetails struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// SubsystemDetails is the status of a subsystem of the sidecar. Subsystems which are not enabled are not reported.
+type SubsystemDetails struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// CertificateDetails is the status of the workload certificate of the sidecar when mTLS is enabled.
+type CertificateDetails struct {
+	Status string    `json:"status"`
+	Expiry time.Time `json:"expiry"`
+}
+
+// UpdateStatus sets the status of the details to ok if all the components and subsystems are ok, the components
+// not used yet being lazy, and to failed otherwise. It returns true if the sidecar is healthy.
+func (d *Details) UpdateStatus() bool {
+	healthy := true
+	for _, c := range d.Components {
+		healthy = healthy && (c.Status == StatusOK || c.Status == StatusLazy)
+	}
+	for _, s := range []*SubsystemDetails{d.AppChannel, d.Placement} {
+		healthy = healthy && (s == nil || s.Status == StatusOK)
+	}
+	healthy = healthy && (d.Certificate == nil || d.Certificate.Status == StatusOK)
+
+	d.Status = StatusOK
+	if !healthy {
+		d.Status = StatusFailed
+	}
+	return healthy
+}
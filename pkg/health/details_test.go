@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetailsUpdateStatus(t *testing.T) {
+	t.Run("healthy without subsystems", func(t *testing.T) {
+		d := Details{}
+		assert.True(t, d.UpdateStatus())
+		assert.Equal(t, StatusOK, d.Status)
+	})
+
+	t.Run("healthy", func(t *testing.T) {
+		d := Details{
+			Components:  []ComponentDetails{{Name: "statestore", Type: "state.redis", Status: StatusOK}},
+			AppChannel:  &SubsystemDetails{Status: StatusOK},
+			Placement:   &SubsystemDetails{Status: StatusOK},
+			Certificate: &CertificateDetails{Status: StatusOK, Expiry: time.Now().Add(time.Hour)},
+		}
+		assert.True(t, d.UpdateStatus())
+		assert.Equal(t, StatusOK, d.Status)
+	})
+
+	t.Run("pending component", func(t *testing.T) {
+		d := Details{
+			Components: []ComponentDetails{{Name: "statestore", Type: "state.redis", Status: StatusPending}},
+		}
+		assert.False(t, d.UpdateStatus())
+		assert.Equal(t, StatusFailed, d.Status)
+	})
+
+	t.Run("lazy component", func(t *testing.T) {
+		d := Details{
+			Components: []ComponentDetails{{Name: "storage", Type: "bindings.azure.blobstorage", Status: StatusLazy}},
+		}
+		assert.True(t, d.UpdateStatus())
+		assert.Equal(t, StatusOK, d.Status)
+	})
+
+	t.Run("unhealthy subsystem", func(t *testing.T) {
+		d := Details{
+			Placement: &SubsystemDetails{Status: StatusFailed, Message: "not connected to the placement service"},
+		}
+		assert.False(t, d.UpdateStatus())
+		assert.Equal(t, StatusFailed, d.Status)
+	})
+
+	t.Run("expired certificate", func(t *testing.T) {
+		d := Details{
+			Certificate: &CertificateDetails{Status: StatusFailed, Expiry: time.Now().Add(-time.Hour)},
+		}
+		assert.False(t, d.UpdateStatus())
+	})
+}
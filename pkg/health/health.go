@@ -14,9 +14,13 @@ limitations under the License.
 package health
 
 import (
+	"context"
+	"net"
 	"time"
 
 	"github.com/valyala/fasthttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 const (
@@ -36,40 +40,80 @@ type healthCheckOptions struct {
 	failureThreshold  int
 	interval          time.Duration
 	successStatusCode int
+	unixDomainSocket  string
 }
 
 // StartEndpointHealthCheck starts a health check on the specified address with the given options.
 // It returns a channel that will emit true if the endpoint is healthy and false if the failure conditions
 // Have been met.
 func StartEndpointHealthCheck(endpointAddress string, opts ...Option) chan bool {
+	options := newHealthCheckOptions(opts...)
+
+	client := &fasthttp.Client{
+		MaxConnsPerHost:           5, // Limit Keep-Alive connections
+		ReadTimeout:               options.requestTimeout,
+		MaxIdemponentCallAttempts: 1,
+	}
+	if options.unixDomainSocket != "" {
+		client.Dial = func(string) (net.Conn, error) {
+			return net.DialTimeout("unix", options.unixDomainSocket, options.requestTimeout)
+		}
+	}
+
+	probe := func() bool {
+		req := fasthttp.AcquireRequest()
+		req.SetRequestURI(endpointAddress)
+		req.Header.SetMethod(fasthttp.MethodGet)
+		defer fasthttp.ReleaseRequest(req)
+
+		resp := fasthttp.AcquireResponse()
+		defer fasthttp.ReleaseResponse(resp)
+
+		err := client.DoTimeout(req, resp, options.requestTimeout)
+		return err == nil && resp.StatusCode() == options.successStatusCode
+	}
+
+	return startHealthCheck(probe, options)
+}
+
+// StartGRPCHealthCheck starts a health check of the given gRPC connection using the gRPC health checking protocol.
+// It returns a channel that will emit true if the server is serving and false if the failure conditions
+// Have been met.
+func StartGRPCHealthCheck(conn grpc.ClientConnInterface, opts ...Option) chan bool {
+	options := newHealthCheckOptions(opts...)
+	client := grpc_health_v1.NewHealthClient(conn)
+
+	probe := func() bool {
+		ctx, cancel := context.WithTimeout(context.Background(), options.requestTimeout)
+		defer cancel()
+
+		resp, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		return err == nil && resp.GetStatus() == grpc_health_v1.HealthCheckResponse_SERVING
+	}
+
+	return startHealthCheck(probe, options)
+}
+
+func newHealthCheckOptions(opts ...Option) *healthCheckOptions {
 	options := &healthCheckOptions{}
 	applyDefaults(options)
 
 	for _, o := range opts {
 		o(options)
 	}
+	return options
+}
+
+func startHealthCheck(probe func() bool, options *healthCheckOptions) chan bool {
 	signalChan := make(chan bool, 1)
 
-	go func(ch chan<- bool, endpointAddress string, options *healthCheckOptions) {
+	go func(ch chan<- bool, options *healthCheckOptions) {
 		ticker := time.NewTicker(options.interval)
 		failureCount := 0
 		time.Sleep(options.initialDelay)
 
-		client := &fasthttp.Client{
-			MaxConnsPerHost:           5, // Limit Keep-Alive connections
-			ReadTimeout:               options.requestTimeout,
-			MaxIdemponentCallAttempts: 1,
-		}
-
-		req := fasthttp.AcquireRequest()
-		req.SetRequestURI(endpointAddress)
-		req.Header.SetMethod(fasthttp.MethodGet)
-		defer fasthttp.ReleaseRequest(req)
-
 		for range ticker.C {
-			resp := fasthttp.AcquireResponse()
-			err := client.DoTimeout(req, resp, options.requestTimeout)
-			if err != nil || resp.StatusCode() != options.successStatusCode {
+			if !probe() {
 				failureCount++
 				if failureCount == options.failureThreshold {
 					failureCount--
@@ -79,9 +123,8 @@ func StartEndpointHealthCheck(endpointAddress string, opts ...Option) chan bool
 				ch <- true
 				failureCount = 0
 			}
-			fasthttp.ReleaseResponse(resp)
 		}
-	}(signalChan, endpointAddress, options)
+	}(signalChan, options)
 	return signalChan
 }
 
@@ -121,6 +164,13 @@ func WithSuccessStatusCode(code int) Option {
 	}
 }
 
+// WithUnixDomainSocket sets the unix domain socket the endpoint health check connects to.
+func WithUnixDomainSocket(socket string) Option {
+	return func(o *healthCheckOptions) {
+		o.unixDomainSocket = socket
+	}
+}
+
 // WithInterval sets the interval for the health check.
 func WithInterval(interval time.Duration) Option {
 	return func(o *healthCheckOptions) {
@@ -14,12 +14,16 @@ limitations under the License.
 package health
 
 import (
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	grpc_health "google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func TestHealthCheck(t *testing.T) {
@@ -177,3 +181,30 @@ func TestResponses(t *testing.T) {
 		}
 	})
 }
+
+func TestGRPCHealthCheck(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	healthServer := grpc_health.NewServer()
+	server := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	t.Run("serving", func(t *testing.T) {
+		healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+		ch := StartGRPCHealthCheck(conn, WithInterval(time.Millisecond*100), WithInitialDelay(0), WithFailureThreshold(1))
+		assert.True(t, <-ch)
+	})
+
+	t.Run("not serving", func(t *testing.T) {
+		healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+		ch := StartGRPCHealthCheck(conn, WithInterval(time.Millisecond*100), WithInitialDelay(0), WithFailureThreshold(1))
+		assert.False(t, <-ch)
+	})
+}
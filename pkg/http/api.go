@@ -14,18 +14,20 @@ limitations under the License.
 package http
 
 import (
+	"bufio"
+	"context"
 	"encoding/base64"
 	"fmt"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/fasthttp/router"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
 	"github.com/valyala/fasthttp"
-	"go.opencensus.io/trace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -38,15 +40,23 @@ import (
 	components_v1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
 	"github.com/dapr/dapr/pkg/channel"
 	"github.com/dapr/dapr/pkg/channel/http"
+	"github.com/dapr/dapr/pkg/chaos"
+	bindings_loader "github.com/dapr/dapr/pkg/components/bindings"
+	secretstores_loader "github.com/dapr/dapr/pkg/components/secretstores"
 	state_loader "github.com/dapr/dapr/pkg/components/state"
 	"github.com/dapr/dapr/pkg/concurrency"
 	"github.com/dapr/dapr/pkg/config"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
 	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
+	"github.com/dapr/dapr/pkg/drain"
 	"github.com/dapr/dapr/pkg/encryption"
+	"github.com/dapr/dapr/pkg/health"
+	"github.com/dapr/dapr/pkg/jobs"
 	"github.com/dapr/dapr/pkg/messages"
 	"github.com/dapr/dapr/pkg/messaging"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	"github.com/dapr/dapr/pkg/resiliency"
+	runtime_metadata "github.com/dapr/dapr/pkg/runtime/metadata"
 	runtime_pubsub "github.com/dapr/dapr/pkg/runtime/pubsub"
 )
 
@@ -62,38 +72,58 @@ type API interface {
 }
 
 type api struct {
-	endpoints                []Endpoint
-	publicEndpoints          []Endpoint
-	directMessaging          messaging.DirectMessaging
-	appChannel               channel.AppChannel
-	getComponentsFn          func() []components_v1alpha1.Component
-	stateStores              map[string]state.Store
-	transactionalStateStores map[string]state.TransactionalStore
-	secretStores             map[string]secretstores.SecretStore
-	secretsConfiguration     map[string]config.SecretsScope
-	json                     jsoniter.API
-	actor                    actors.Actors
-	pubsubAdapter            runtime_pubsub.Adapter
-	sendToOutputBindingFn    func(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error)
-	id                       string
-	extendedMetadata         sync.Map
-	readyStatus              bool
-	outboundReadyStatus      bool
-	tracingSpec              config.TracingSpec
-	shutdown                 func()
+	endpoints                    []Endpoint
+	publicEndpoints              []Endpoint
+	directMessaging              messaging.DirectMessaging
+	appChannel                   channel.AppChannel
+	getComponentsFn              func() []components_v1alpha1.Component
+	stateStores                  map[string]state.Store
+	transactionalStateStores     map[string]state.TransactionalStore
+	secretStores                 map[string]secretstores.SecretStore
+	secretsConfiguration         map[string]config.SecretsScope
+	json                         jsoniter.API
+	actor                        actors.Actors
+	pubsubAdapter                runtime_pubsub.Adapter
+	sendToOutputBindingFn        func(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error)
+	sendToOutputBindingStreamFn  func(name string, req *bindings.InvokeRequest) (*bindings_loader.StreamingInvokeResponse, error)
+	sendToOutputBindingBulkFn    func(name string, reqs []*bindings.InvokeRequest) ([]bindings_loader.BulkInvokeResult, error)
+	getOutputBindingOperationsFn func(name string) ([]bindings_loader.OperationDescription, error)
+	setInputBindingPausedFn      func(name string, paused bool) error
+	setLogLevelFn                func(level string, scopes map[string]string) error
+	getHealthDetailsFn           func() health.Details
+	getMetadataDetailsFn         func() runtime_metadata.Details
+	resiliency                   *resiliency.Resiliency
+	eventLog                     *diag.EventLog
+	jobs                         *jobs.Scheduler
+	secretSubscriptions          map[string]context.CancelFunc
+	secretSubscriptionsLock      sync.Mutex
+	id                           string
+	extendedMetadata             sync.Map
+	readyStatus                  bool
+	outboundReadyStatus          bool
+	tracingSpec                  config.TracingSpec
+	shutdown                     func()
 }
 
 type registeredComponent struct {
-	Name    string `json:"name"`
-	Type    string `json:"type"`
-	Version string `json:"version"`
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`
+	Version      string   `json:"version"`
+	Capabilities []string `json:"capabilities,omitempty"`
 }
 
 type metadata struct {
-	ID                   string                      `json:"id"`
-	ActiveActorsCount    []actors.ActiveActorsCount  `json:"actors"`
-	Extended             map[interface{}]interface{} `json:"extended"`
-	RegisteredComponents []registeredComponent       `json:"components"`
+	ID                   string                            `json:"id"`
+	ActiveActorsCount    []actors.ActiveActorsCount        `json:"actors"`
+	Extended             map[interface{}]interface{}       `json:"extended"`
+	RegisteredComponents []registeredComponent             `json:"components"`
+	Events               []diag.RuntimeEvent               `json:"events"`
+	CircuitBreakers      []resiliency.CircuitBreakerStatus `json:"circuitBreakers,omitempty"`
+	ConcurrencyLimiters  []resiliency.ConcurrencyStatus    `json:"concurrencyLimiters,omitempty"`
+	EnabledFeatures      []string                          `json:"enabledFeatures,omitempty"`
+	Subscriptions        []runtime_metadata.Subscription   `json:"subscriptions,omitempty"`
+	ActorRuntime         *runtime_metadata.ActorRuntime    `json:"actorRuntime"`
+	Runtime              runtime_metadata.Build            `json:"runtime"`
 }
 
 const (
@@ -111,10 +141,22 @@ const (
 	nameParam            = "name"
 	consistencyParam     = "consistency"
 	concurrencyParam     = "concurrency"
+	prefixParam          = "prefix"
+	statusParam          = "status"
 	pubsubnameparam      = "pubsubname"
-	traceparentHeader    = "traceparent"
-	tracestateHeader     = "tracestate"
 	daprAppID            = "dapr-app-id"
+	ifMatchHeader        = "If-Match"
+	ifNoneMatchHeader    = "If-None-Match"
+	drainParam           = "drain"
+	timeoutParam         = "timeout"
+
+	eventsContentType       = "application/x-ndjson"
+	eventsHeartbeatInterval = 15 * time.Second
+
+	// defaultDrainTimeout is the time the shutdown API waits for the work in flight when draining without a timeout.
+	defaultDrainTimeout = 30 * time.Second
+	// drainProgressInterval is the interval of the progress reported while draining.
+	drainProgressInterval = time.Second
 )
 
 // NewAPI returns a new API.
@@ -129,6 +171,15 @@ func NewAPI(
 	pubsubAdapter runtime_pubsub.Adapter,
 	actor actors.Actors,
 	sendToOutputBindingFn func(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error),
+	sendToOutputBindingStreamFn func(name string, req *bindings.InvokeRequest) (*bindings_loader.StreamingInvokeResponse, error),
+	sendToOutputBindingBulkFn func(name string, reqs []*bindings.InvokeRequest) ([]bindings_loader.BulkInvokeResult, error),
+	getOutputBindingOperationsFn func(name string) ([]bindings_loader.OperationDescription, error),
+	setInputBindingPausedFn func(name string, paused bool) error,
+	setLogLevelFn func(level string, scopes map[string]string) error,
+	getHealthDetailsFn func() health.Details,
+	getMetadataDetailsFn func() runtime_metadata.Details,
+	resiliency *resiliency.Resiliency,
+	jobScheduler *jobs.Scheduler,
 	tracingSpec config.TracingSpec,
 	shutdown func()) API {
 	transactionalStateStores := map[string]state.TransactionalStore{}
@@ -138,20 +189,30 @@ func NewAPI(
 		}
 	}
 	api := &api{
-		appChannel:               appChannel,
-		getComponentsFn:          getComponentsFn,
-		directMessaging:          directMessaging,
-		stateStores:              stateStores,
-		transactionalStateStores: transactionalStateStores,
-		secretStores:             secretStores,
-		secretsConfiguration:     secretsConfiguration,
-		json:                     jsoniter.ConfigFastest,
-		actor:                    actor,
-		pubsubAdapter:            pubsubAdapter,
-		sendToOutputBindingFn:    sendToOutputBindingFn,
-		id:                       appID,
-		tracingSpec:              tracingSpec,
-		shutdown:                 shutdown,
+		appChannel:                   appChannel,
+		getComponentsFn:              getComponentsFn,
+		directMessaging:              directMessaging,
+		stateStores:                  stateStores,
+		transactionalStateStores:     transactionalStateStores,
+		secretStores:                 secretStores,
+		secretsConfiguration:         secretsConfiguration,
+		json:                         jsoniter.ConfigFastest,
+		actor:                        actor,
+		pubsubAdapter:                pubsubAdapter,
+		sendToOutputBindingFn:        sendToOutputBindingFn,
+		sendToOutputBindingStreamFn:  sendToOutputBindingStreamFn,
+		sendToOutputBindingBulkFn:    sendToOutputBindingBulkFn,
+		getOutputBindingOperationsFn: getOutputBindingOperationsFn,
+		setInputBindingPausedFn:      setInputBindingPausedFn,
+		setLogLevelFn:                setLogLevelFn,
+		getHealthDetailsFn:           getHealthDetailsFn,
+		getMetadataDetailsFn:         getMetadataDetailsFn,
+		resiliency:                   resiliency,
+		eventLog:                     diag.DefaultEventLog,
+		jobs:                         jobScheduler,
+		id:                           appID,
+		tracingSpec:                  tracingSpec,
+		shutdown:                     shutdown,
 	}
 
 	metadataEndpoints := api.constructMetadataEndpoints()
@@ -164,8 +225,14 @@ func NewAPI(
 	api.endpoints = append(api.endpoints, api.constructDirectMessagingEndpoints()...)
 	api.endpoints = append(api.endpoints, metadataEndpoints...)
 	api.endpoints = append(api.endpoints, api.constructShutdownEndpoints()...)
+	api.endpoints = append(api.endpoints, api.constructLoggingEndpoints()...)
+	api.endpoints = append(api.endpoints, api.constructResiliencyEndpoints()...)
+	api.endpoints = append(api.endpoints, api.constructEventsEndpoints()...)
 	api.endpoints = append(api.endpoints, api.constructBindingsEndpoints()...)
+	api.endpoints = append(api.endpoints, api.constructJobsEndpoints()...)
 	api.endpoints = append(api.endpoints, healthEndpoints...)
+	// The detailed health is not served on the public port as it reports the components of the sidecar.
+	api.endpoints = append(api.endpoints, api.constructDetailedHealthzEndpoints()...)
 
 	api.publicEndpoints = append(api.publicEndpoints, metadataEndpoints...)
 	api.publicEndpoints = append(api.publicEndpoints, healthEndpoints...)
@@ -213,6 +280,12 @@ func (a *api) constructStateEndpoints() []Endpoint {
 			Version: apiVersionV1,
 			Handler: a.onDeleteState,
 		},
+		{
+			Methods: []string{fasthttp.MethodPut},
+			Route:   "state/{storeName}/{key}",
+			Version: apiVersionV1,
+			Handler: a.onPutState,
+		},
 		{
 			Methods: []string{fasthttp.MethodPost, fasthttp.MethodPut},
 			Route:   "state/{storeName}/bulk",
@@ -248,6 +321,48 @@ func (a *api) constructSecretEndpoints() []Endpoint {
 			Version: apiVersionV1,
 			Handler: a.onGetSecret,
 		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "secrets/{secretStoreName}/cache/invalidate",
+			Version: apiVersionV1alpha1,
+			Handler: a.onInvalidateSecretCache,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost, fasthttp.MethodPut},
+			Route:   "secrets/{secretStoreName}/subscribe",
+			Version: apiVersionV1alpha1,
+			Handler: a.onSubscribeSecrets,
+		},
+		{
+			Methods: []string{fasthttp.MethodDelete},
+			Route:   "secrets/{secretStoreName}/subscribe",
+			Version: apiVersionV1alpha1,
+			Handler: a.onUnsubscribeSecrets,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost, fasthttp.MethodPut},
+			Route:   "crypto/{secretStoreName}/encrypt",
+			Version: apiVersionV1alpha1,
+			Handler: a.onCrypto(secretstores_loader.OperationEncrypt),
+		},
+		{
+			Methods: []string{fasthttp.MethodPost, fasthttp.MethodPut},
+			Route:   "crypto/{secretStoreName}/decrypt",
+			Version: apiVersionV1alpha1,
+			Handler: a.onCrypto(secretstores_loader.OperationDecrypt),
+		},
+		{
+			Methods: []string{fasthttp.MethodPost, fasthttp.MethodPut},
+			Route:   "crypto/{secretStoreName}/sign",
+			Version: apiVersionV1alpha1,
+			Handler: a.onCrypto(secretstores_loader.OperationSign),
+		},
+		{
+			Methods: []string{fasthttp.MethodPost, fasthttp.MethodPut},
+			Route:   "crypto/{secretStoreName}/verify",
+			Version: apiVersionV1alpha1,
+			Handler: a.onCrypto(secretstores_loader.OperationVerify),
+		},
 	}
 }
 
@@ -270,6 +385,30 @@ func (a *api) constructBindingsEndpoints() []Endpoint {
 			Version: apiVersionV1,
 			Handler: a.onOutputBindingMessage,
 		},
+		{
+			Methods: []string{fasthttp.MethodGet},
+			Route:   "bindings/{name}/operations",
+			Version: apiVersionV1,
+			Handler: a.onGetOutputBindingOperations,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost, fasthttp.MethodPut},
+			Route:   "bindings/{name}/bulk",
+			Version: apiVersionV1alpha1,
+			Handler: a.onBulkOutputBindingMessage,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "bindings/{name}/pause",
+			Version: apiVersionV1alpha1,
+			Handler: a.onPauseInputBinding,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "bindings/{name}/resume",
+			Version: apiVersionV1alpha1,
+			Handler: a.onResumeInputBinding,
+		},
 	}
 }
 
@@ -373,6 +512,86 @@ func (a *api) constructShutdownEndpoints() []Endpoint {
 	}
 }
 
+func (a *api) constructLoggingEndpoints() []Endpoint {
+	return []Endpoint{
+		{
+			Methods: []string{fasthttp.MethodPut},
+			Route:   "logs/level",
+			Version: apiVersionV1alpha1,
+			Handler: a.onSetLogLevel,
+		},
+	}
+}
+
+func (a *api) constructResiliencyEndpoints() []Endpoint {
+	return []Endpoint{
+		{
+			Methods: []string{fasthttp.MethodGet},
+			Route:   "resiliency/circuitbreakers",
+			Version: apiVersionV1alpha1,
+			Handler: a.onGetCircuitBreakers,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "resiliency/circuitbreakers/{name}/trip",
+			Version: apiVersionV1alpha1,
+			Handler: a.onTripCircuitBreaker,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "resiliency/circuitbreakers/{name}/reset",
+			Version: apiVersionV1alpha1,
+			Handler: a.onResetCircuitBreaker,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "resiliency/simulate",
+			Version: apiVersionV1alpha1,
+			Handler: a.onSimulateResiliency,
+		},
+	}
+}
+
+func (a *api) constructEventsEndpoints() []Endpoint {
+	return []Endpoint{
+		{
+			Methods: []string{fasthttp.MethodGet},
+			Route:   "events",
+			Version: apiVersionV1alpha1,
+			Handler: a.onGetEvents,
+		},
+	}
+}
+
+func (a *api) constructJobsEndpoints() []Endpoint {
+	return []Endpoint{
+		{
+			Methods: []string{fasthttp.MethodGet},
+			Route:   "jobs",
+			Version: apiVersionV1alpha1,
+			Handler: a.onListJobs,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost, fasthttp.MethodPut},
+			Route:   "jobs/{name}",
+			Version: apiVersionV1alpha1,
+			Handler: a.onCreateJob,
+		},
+		{
+			Methods: []string{fasthttp.MethodGet},
+			Route:   "jobs/{name}",
+			Version: apiVersionV1alpha1,
+			Handler: a.onGetJob,
+		},
+		{
+			Methods: []string{fasthttp.MethodDelete},
+			Route:   "jobs/{name}",
+			Version: apiVersionV1alpha1,
+			Handler: a.onDeleteJob,
+		},
+	}
+}
+
 func (a *api) constructHealthzEndpoints() []Endpoint {
 	return []Endpoint{
 		{
@@ -390,6 +609,17 @@ func (a *api) constructHealthzEndpoints() []Endpoint {
 	}
 }
 
+func (a *api) constructDetailedHealthzEndpoints() []Endpoint {
+	return []Endpoint{
+		{
+			Methods: []string{fasthttp.MethodGet},
+			Route:   "healthz/detailed",
+			Version: apiVersionV1,
+			Handler: a.onGetDetailedHealthz,
+		},
+	}
+}
+
 func (a *api) onOutputBindingMessage(reqCtx *fasthttp.RequestCtx) {
 	name := reqCtx.UserValue(nameParam).(string)
 	body := reqCtx.PostBody()
@@ -411,29 +641,26 @@ func (a *api) onOutputBindingMessage(reqCtx *fasthttp.RequestCtx) {
 		return
 	}
 
+	op := diag.StartBindingOperation(reqCtx, a.tracingSpec, name, req.Operation)
 	// pass the trace context to output binding in metadata
-	if span := diag_utils.SpanFromContext(reqCtx); span != nil {
-		sc := span.SpanContext()
-		if req.Metadata == nil {
-			req.Metadata = map[string]string{}
-		}
-		// if sc is not empty context, set traceparent Header.
-		if sc != (trace.SpanContext{}) {
-			req.Metadata[traceparentHeader] = diag.SpanContextToW3CString(sc)
-		}
-		if sc.Tracestate != nil {
-			req.Metadata[tracestateHeader] = diag.TraceStateToW3CString(sc)
-		}
-	}
+	req.Metadata = diag.SpanContextToBindingMetadata(op.Span(), req.Metadata)
 
-	resp, err := a.sendToOutputBindingFn(name, &bindings.InvokeRequest{
+	invokeReq := &bindings.InvokeRequest{
 		Metadata:  req.Metadata,
 		Data:      b,
 		Operation: bindings.OperationKind(req.Operation),
-	})
+	}
+	if a.sendToOutputBindingStreamFn != nil {
+		err = a.streamOutputBinding(reqCtx, name, invokeReq)
+		op.End(err)
+		return
+	}
+
+	resp, err := a.sendToOutputBindingFn(name, invokeReq)
+	op.End(err)
 	if err != nil {
-		msg := NewErrorResponse("ERR_INVOKE_OUTPUT_BINDING", fmt.Sprintf(messages.ErrInvokeOutputBinding, name, err))
-		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		code, msg := outputBindingErrorResponse(name, err)
+		respond(reqCtx, withError(code, msg))
 		log.Debug(msg)
 		return
 	}
@@ -444,6 +671,255 @@ func (a *api) onOutputBindingMessage(reqCtx *fasthttp.RequestCtx) {
 	}
 }
 
+// outputBindingErrorResponse returns the status code and the error response of a failed invocation of an output
+// binding. The failed initializations of the lazily initialized bindings have their own error code.
+func outputBindingErrorResponse(name string, err error) (int, ErrorResponse) {
+	var initErr *bindings_loader.LazyInitError
+	if errors.As(err, &initErr) {
+		return fasthttp.StatusServiceUnavailable, NewErrorResponse("ERR_OUTPUT_BINDING_INIT", fmt.Sprintf(messages.ErrOutputBindingInit, name, initErr.Err))
+	}
+	return fasthttp.StatusInternalServerError, NewErrorResponse("ERR_INVOKE_OUTPUT_BINDING", fmt.Sprintf(messages.ErrInvokeOutputBinding, name, err))
+}
+
+func (a *api) onGetOutputBindingOperations(reqCtx *fasthttp.RequestCtx) {
+	name := reqCtx.UserValue(nameParam).(string)
+
+	ops, err := a.getOutputBindingOperationsFn(name)
+	if err != nil {
+		msg := NewErrorResponse("ERR_BINDING_NOT_FOUND", fmt.Sprintf(messages.ErrOutputBindingNotFound, name))
+		respond(reqCtx, withError(fasthttp.StatusNotFound, msg))
+		log.Debug(msg)
+		return
+	}
+
+	b, err := a.json.Marshal(ops)
+	if err != nil {
+		msg := NewErrorResponse("ERR_BINDING_OPERATIONS", err.Error())
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		log.Debug(msg)
+		return
+	}
+	respond(reqCtx, withJSON(fasthttp.StatusOK, b))
+}
+
+func (a *api) onBulkOutputBindingMessage(reqCtx *fasthttp.RequestCtx) {
+	name := reqCtx.UserValue(nameParam).(string)
+
+	var req BulkOutputBindingRequest
+	err := a.json.Unmarshal(reqCtx.PostBody(), &req)
+	if err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
+	op := diag.StartBindingOperation(reqCtx, a.tracingSpec, name, "bulk")
+	reqs := make([]*bindings.InvokeRequest, len(req.Requests))
+	for i, r := range req.Requests {
+		b, err := a.json.Marshal(r.Data)
+		if err != nil {
+			op.End(err)
+			msg := NewErrorResponse("ERR_MALFORMED_REQUEST_DATA", fmt.Sprintf(messages.ErrMalformedRequestData, err))
+			respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+			log.Debug(msg)
+			return
+		}
+		reqs[i] = &bindings.InvokeRequest{
+			Metadata:  diag.SpanContextToBindingMetadata(op.Span(), r.Metadata),
+			Data:      b,
+			Operation: bindings.OperationKind(r.Operation),
+		}
+	}
+
+	results, err := a.sendToOutputBindingBulkFn(name, reqs)
+	op.End(err)
+	if err != nil {
+		code, msg := outputBindingErrorResponse(name, err)
+		respond(reqCtx, withError(code, msg))
+		log.Debug(msg)
+		return
+	}
+
+	bulkResp := make([]BulkOutputBindingResponse, len(results))
+	for i, r := range results {
+		if r.Error != nil {
+			bulkResp[i].Error = r.Error.Error()
+			continue
+		}
+		if r.Response == nil {
+			continue
+		}
+		bulkResp[i].Metadata = r.Response.Metadata
+		if len(r.Response.Data) > 0 {
+			if jsoniter.Valid(r.Response.Data) {
+				bulkResp[i].Data = jsoniter.RawMessage(r.Response.Data)
+			} else {
+				// responses which aren't JSON are returned as strings.
+				bulkResp[i].Data, _ = a.json.Marshal(string(r.Response.Data))
+			}
+		}
+	}
+
+	b, _ := a.json.Marshal(bulkResp)
+	respond(reqCtx, withJSON(fasthttp.StatusOK, b))
+}
+
+func (a *api) onPauseInputBinding(reqCtx *fasthttp.RequestCtx) {
+	a.setInputBindingPaused(reqCtx, true)
+}
+
+func (a *api) onResumeInputBinding(reqCtx *fasthttp.RequestCtx) {
+	a.setInputBindingPaused(reqCtx, false)
+}
+
+// setInputBindingPaused pauses or resumes reading the events of an input binding.
+func (a *api) setInputBindingPaused(reqCtx *fasthttp.RequestCtx, paused bool) {
+	name := reqCtx.UserValue(nameParam).(string)
+
+	if err := a.setInputBindingPausedFn(name, paused); err != nil {
+		msg := NewErrorResponse("ERR_BINDING_NOT_FOUND", fmt.Sprintf(messages.ErrInputBindingNotFound, name))
+		respond(reqCtx, withError(fasthttp.StatusNotFound, msg))
+		log.Debug(msg)
+		return
+	}
+	respond(reqCtx, withEmpty())
+}
+
+// streamOutputBinding writes the response of the output binding to the body as it is read from the binding.
+// Responses of unknown size are sent with chunked transfer encoding.
+func (a *api) streamOutputBinding(reqCtx *fasthttp.RequestCtx, name string, req *bindings.InvokeRequest) error {
+	resp, err := a.sendToOutputBindingStreamFn(name, req)
+	if err != nil {
+		code, msg := outputBindingErrorResponse(name, err)
+		respond(reqCtx, withError(code, msg))
+		log.Debug(msg)
+		return err
+	}
+	if resp == nil {
+		respond(reqCtx, withEmpty())
+		return nil
+	}
+
+	respond(reqCtx, withMetadata(resp.Metadata))
+	reqCtx.Response.SetStatusCode(fasthttp.StatusOK)
+	reqCtx.Response.Header.SetContentType(jsonContentTypeHeader)
+	setBodyStream(reqCtx, resp.Data, resp.Size)
+	return nil
+}
+
+func (a *api) getJobsWithRequestValidation(reqCtx *fasthttp.RequestCtx) (*jobs.Scheduler, string, error) {
+	if a.jobs == nil {
+		msg := NewErrorResponse("ERR_JOBS_NOT_CONFIGURED", messages.ErrJobsNotConfigured)
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		return nil, "", errors.New(msg.Message)
+	}
+	// The name is empty for the routes of all the jobs.
+	name, _ := reqCtx.UserValue(nameParam).(string)
+	return a.jobs, name, nil
+}
+
+func (a *api) onListJobs(reqCtx *fasthttp.RequestCtx) {
+	scheduler, _, err := a.getJobsWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	prefix := string(reqCtx.QueryArgs().Peek(prefixParam))
+	status := string(reqCtx.QueryArgs().Peek(statusParam))
+	list, err := scheduler.List(prefix, status)
+	if errors.Is(err, jobs.ErrUnknownStatus) {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+	if err != nil {
+		msg := NewErrorResponse("ERR_JOB_LIST", fmt.Sprintf(messages.ErrJobList, err))
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		log.Debug(msg)
+		return
+	}
+
+	b, _ := a.json.Marshal(list)
+	respond(reqCtx, withJSON(fasthttp.StatusOK, b))
+}
+
+func (a *api) onCreateJob(reqCtx *fasthttp.RequestCtx) {
+	scheduler, name, err := a.getJobsWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	var req JobRequest
+	err = a.json.Unmarshal(reqCtx.PostBody(), &req)
+	if err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
+	err = scheduler.Create(&jobs.Job{
+		Name:          name,
+		Schedule:      req.Schedule,
+		Method:        req.Method,
+		Data:          req.Data,
+		FailurePolicy: req.FailurePolicy,
+	})
+	if err != nil {
+		msg := NewErrorResponse("ERR_JOB_SAVE", fmt.Sprintf(messages.ErrJobSave, name, err))
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		log.Debug(msg)
+		return
+	}
+	respond(reqCtx, withEmpty())
+}
+
+func (a *api) onGetJob(reqCtx *fasthttp.RequestCtx) {
+	scheduler, name, err := a.getJobsWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	job, err := scheduler.Get(name)
+	if err != nil {
+		msg := NewErrorResponse("ERR_JOB_GET", fmt.Sprintf(messages.ErrJobGet, name, err))
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		log.Debug(msg)
+		return
+	}
+	if job == nil {
+		msg := NewErrorResponse("ERR_JOB_NOT_FOUND", fmt.Sprintf(messages.ErrJobNotFound, name))
+		respond(reqCtx, withError(fasthttp.StatusNotFound, msg))
+		log.Debug(msg)
+		return
+	}
+
+	b, _ := a.json.Marshal(job)
+	respond(reqCtx, withJSON(fasthttp.StatusOK, b))
+}
+
+func (a *api) onDeleteJob(reqCtx *fasthttp.RequestCtx) {
+	scheduler, name, err := a.getJobsWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	err = scheduler.Delete(name)
+	if err != nil {
+		msg := NewErrorResponse("ERR_JOB_DELETE", fmt.Sprintf(messages.ErrJobDelete, name, err))
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		log.Debug(msg)
+		return
+	}
+	respond(reqCtx, withEmpty())
+}
+
 func (a *api) onBulkGetState(reqCtx *fasthttp.RequestCtx) {
 	store, storeName, err := a.getStateStoreWithRequestValidation(reqCtx)
 	if err != nil {
@@ -485,11 +961,20 @@ func (a *api) onBulkGetState(reqCtx *fasthttp.RequestCtx) {
 		}
 		reqs[i] = r
 	}
-	bulkGet, responses, err := store.BulkGet(reqs)
+	op := diag.StartStateOperation(reqCtx, a.tracingSpec, storeName, "bulkGet", len(reqs))
+	var bulkGet bool
+	var responses []state.BulkGetResponse
+	if err = chaos.Inject(reqCtx, chaos.State, storeName); err == nil {
+		err = a.resiliency.ComponentOperation(storeName, func() (err error) {
+			bulkGet, responses, err = store.BulkGet(reqs)
+			return err
+		})
+	}
 
-	if bulkGet {
+	if bulkGet || err != nil {
 		// if store supports bulk get
 		if err != nil {
+			op.End(err)
 			msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
 			respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
 			log.Debug(msg)
@@ -527,7 +1012,11 @@ func (a *api) onBulkGetState(reqCtx *fasthttp.RequestCtx) {
 					Metadata: metadata,
 				}
 
-				resp, err := store.Get(gr)
+				var resp *state.GetResponse
+				err = a.resiliency.ComponentOperation(storeName, func() (err error) {
+					resp, err = store.Get(gr)
+					return err
+				})
 				if err != nil {
 					log.Debugf("bulk get: error getting key %s: %s", r.Key, err)
 					r.Error = err.Error()
@@ -542,6 +1031,7 @@ func (a *api) onBulkGetState(reqCtx *fasthttp.RequestCtx) {
 		}
 		limiter.Wait()
 	}
+	op.End(nil)
 
 	if encryption.EncryptedStateStore(storeName) {
 		for i := range bulkResp {
@@ -580,7 +1070,7 @@ func (a *api) getStateStoreWithRequestValidation(reqCtx *fasthttp.RequestCtx) (s
 }
 
 func (a *api) onGetState(reqCtx *fasthttp.RequestCtx) {
-	store, storeName, err := a.getStateStoreWithRequestValidation(reqCtx)
+	_, storeName, err := a.getStateStoreWithRequestValidation(reqCtx)
 	if err != nil {
 		log.Debug(err)
 		return
@@ -605,20 +1095,34 @@ func (a *api) onGetState(reqCtx *fasthttp.RequestCtx) {
 		Metadata: metadata,
 	}
 
-	resp, err := store.Get(&req)
+	op := diag.StartStateOperation(reqCtx, a.tracingSpec, storeName, "get", 1)
+	resp, answeredBy, err := state_loader.GetWithFallback(reqCtx, a.stateStores, storeName, key, a.id, &req, a.resiliency.ComponentConcurrencyLimiter(storeName), a.resiliency.ComponentFallback(storeName))
+	op.End(err)
 	if err != nil {
 		msg := NewErrorResponse("ERR_STATE_GET", fmt.Sprintf(messages.ErrStateGet, key, storeName, err.Error()))
 		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
 		log.Debug(msg)
 		return
 	}
-	if resp == nil || resp.Data == nil {
+
+	exists := resp != nil && resp.Data != nil
+	if ifMatch, ok := requestHeader(reqCtx, ifMatchHeader); ok && !(exists && etagMatches(ifMatch, resp.ETag)) {
+		msg := NewErrorResponse("ERR_STATE_PRECONDITION", fmt.Sprintf(messages.ErrStatePrecondition, key, storeName, "the etag doesn't match "+ifMatchHeader))
+		respond(reqCtx, withError(fasthttp.StatusPreconditionFailed, msg))
+		log.Debug(msg)
+		return
+	}
+	if !exists {
 		respond(reqCtx, withEmpty())
 		return
 	}
+	if ifNoneMatch, ok := requestHeader(reqCtx, ifNoneMatchHeader); ok && etagMatches(ifNoneMatch, resp.ETag) {
+		respond(reqCtx, withNotModified(), withEtag(resp.ETag))
+		return
+	}
 
-	if encryption.EncryptedStateStore(storeName) {
-		val, err := encryption.TryDecryptValue(storeName, resp.Data)
+	if answeredBy != "" && encryption.EncryptedStateStore(answeredBy) {
+		val, err := encryption.TryDecryptValue(answeredBy, resp.Data)
 		if err != nil {
 			msg := NewErrorResponse("ERR_STATE_GET", fmt.Sprintf(messages.ErrStateGet, key, storeName, err.Error()))
 			respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
@@ -636,14 +1140,116 @@ func extractEtag(reqCtx *fasthttp.RequestCtx) (bool, string) {
 	var etag string
 	var hasEtag bool
 	reqCtx.Request.Header.VisitAll(func(key []byte, value []byte) {
-		if string(key) == "If-Match" {
+		if string(key) == ifMatchHeader {
 			etag = string(value)
 			hasEtag = true
 			return
 		}
-	})
+	})
+
+	return hasEtag, etag
+}
+
+// requestHeader returns the value of a header of the request, and whether the request has the header.
+func requestHeader(reqCtx *fasthttp.RequestCtx, name string) (string, bool) {
+	value := reqCtx.Request.Header.Peek(name)
+	return string(value), value != nil
+}
+
+// etagMatches returns true if the entity tags of a conditional header, such as If-Match, match the etag of a state.
+// The header holds either the etag, a comma-separated list of quoted etags, or * matching any etag.
+func etagMatches(header string, etag *string) bool {
+	if header == "*" {
+		return true
+	}
+	if etag == nil {
+		return false
+	}
+	if header == *etag {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == *etag || strings.Trim(tag, `"`) == *etag {
+			return true
+		}
+	}
+	return false
+}
+
+// onPutState saves the body of the request as the value of the state key of the URL. The If-Match header makes the
+// save conditional on the etag of the state, failing with 412 if it doesn't match.
+func (a *api) onPutState(reqCtx *fasthttp.RequestCtx) {
+	store, storeName, err := a.getStateStoreWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	key := reqCtx.UserValue(stateKeyParam).(string)
+	k, err := state_loader.GetModifiedStateKey(key, storeName, a.id)
+	if err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", err.Error())
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(err)
+		return
+	}
+	var value interface{}
+	if err = a.json.Unmarshal(reqCtx.PostBody(), &value); err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
+	req := state.SetRequest{
+		Key:   k,
+		Value: value,
+		Options: state.SetStateOption{
+			Concurrency: string(reqCtx.QueryArgs().Peek(concurrencyParam)),
+			Consistency: string(reqCtx.QueryArgs().Peek(consistencyParam)),
+		},
+		Metadata: getMetadataFromRequest(reqCtx),
+	}
+	hasEtag, etag := extractEtag(reqCtx)
+	if hasEtag {
+		req.ETag = &etag
+	}
+
+	if encryption.EncryptedStateStore(storeName) {
+		val, encErr := encryption.TryEncryptValue(storeName, []byte(fmt.Sprintf("%v", value)))
+		if encErr != nil {
+			statusCode, errMsg, resp := a.stateErrorResponse(encErr, "ERR_STATE_SAVE")
+			resp.Message = fmt.Sprintf(messages.ErrStateSave, storeName, errMsg)
+
+			respond(reqCtx, withError(statusCode, resp))
+			log.Debug(resp.Message)
+			return
+		}
+		req.Value = val
+	}
 
-	return hasEtag, etag
+	op := diag.StartStateOperation(reqCtx, a.tracingSpec, storeName, "set", 1)
+	if err = chaos.Inject(reqCtx, chaos.State, storeName); err == nil {
+		err = a.resiliency.ComponentOperation(storeName, func() error {
+			return store.Set(&req)
+		})
+	}
+	err = state_loader.SetFallback(a.stateStores, storeName, a.id, []state.SetRequest{req}, a.resiliency.ComponentFallback(storeName), err)
+	op.End(err)
+	if err != nil {
+		statusCode, errMsg, resp := a.stateErrorResponse(err, "ERR_STATE_SAVE")
+		resp.Message = fmt.Sprintf(messages.ErrStateSave, storeName, errMsg)
+		// a mismatching If-Match header fails the precondition, as for any HTTP resource.
+		if hasEtag && statusCode == fasthttp.StatusConflict {
+			statusCode = fasthttp.StatusPreconditionFailed
+		}
+
+		respond(reqCtx, withError(statusCode, resp))
+		log.Debug(resp.Message)
+		return
+	}
+	respond(reqCtx, withEmpty())
 }
 
 func (a *api) onDeleteState(reqCtx *fasthttp.RequestCtx) {
@@ -680,7 +1286,14 @@ func (a *api) onDeleteState(reqCtx *fasthttp.RequestCtx) {
 		req.ETag = &etag
 	}
 
-	err = store.Delete(&req)
+	op := diag.StartStateOperation(reqCtx, a.tracingSpec, storeName, "delete", 1)
+	if err = chaos.Inject(reqCtx, chaos.State, storeName); err == nil {
+		err = a.resiliency.ComponentOperation(storeName, func() error {
+			return store.Delete(&req)
+		})
+	}
+	err = state_loader.DeleteFallback(a.stateStores, storeName, a.id, []state.DeleteRequest{req}, a.resiliency.ComponentFallback(storeName), err)
+	op.End(err)
 	if err != nil {
 		statusCode, errMsg, resp := a.stateErrorResponse(err, "ERR_STATE_DELETE")
 		resp.Message = fmt.Sprintf(messages.ErrStateDelete, key, errMsg)
@@ -703,6 +1316,10 @@ func (a *api) onGetSecret(reqCtx *fasthttp.RequestCtx) {
 
 	key := reqCtx.UserValue(secretNameParam).(string)
 
+	if !a.isSecretOperationAllowed(reqCtx, secretStoreName, config.SecretOperationGet) {
+		return
+	}
+
 	if !a.isSecretAllowed(secretStoreName, key) {
 		msg := NewErrorResponse("ERR_PERMISSION_DENIED", fmt.Sprintf(messages.ErrPermissionDenied, key, secretStoreName))
 		respond(reqCtx, withError(fasthttp.StatusForbidden, msg))
@@ -714,7 +1331,9 @@ func (a *api) onGetSecret(reqCtx *fasthttp.RequestCtx) {
 		Metadata: metadata,
 	}
 
+	done := drain.Components.Track(secretStoreName)
 	resp, err := store.GetSecret(req)
+	done()
 	if err != nil {
 		msg := NewErrorResponse("ERR_SECRET_GET",
 			fmt.Sprintf(messages.ErrSecretGet, req.Name, secretStoreName, err.Error()))
@@ -739,13 +1358,19 @@ func (a *api) onBulkGetSecret(reqCtx *fasthttp.RequestCtx) {
 		return
 	}
 
+	if !a.isSecretOperationAllowed(reqCtx, secretStoreName, config.SecretOperationBulkGet) {
+		return
+	}
+
 	metadata := getMetadataFromRequest(reqCtx)
 
 	req := secretstores.BulkGetSecretRequest{
 		Metadata: metadata,
 	}
 
+	done := drain.Components.Track(secretStoreName)
 	resp, err := store.BulkGetSecret(req)
+	done()
 	if err != nil {
 		msg := NewErrorResponse("ERR_SECRET_GET",
 			fmt.Sprintf(messages.ErrBulkSecretGet, secretStoreName, err.Error()))
@@ -772,6 +1397,188 @@ func (a *api) onBulkGetSecret(reqCtx *fasthttp.RequestCtx) {
 	respond(reqCtx, withJSON(fasthttp.StatusOK, respBytes))
 }
 
+// onInvalidateSecretCache removes the secret of the key query parameter from the cache of the secret store,
+// or all the cached secrets if there is no key.
+func (a *api) onInvalidateSecretCache(reqCtx *fasthttp.RequestCtx) {
+	store, secretStoreName, err := a.getSecretStoreWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	cache, ok := store.(secretstores_loader.Cache)
+	if !ok {
+		msg := NewErrorResponse("ERR_SECRET_CACHE_NOT_ENABLED", fmt.Sprintf(messages.ErrSecretCacheNotEnabled, secretStoreName))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
+	cache.Invalidate(string(reqCtx.QueryArgs().Peek("key")))
+	respond(reqCtx, withEmpty())
+}
+
+// onSubscribeSecrets registers a webhook of the app invoked with the new versions of the secrets of the request.
+// It replaces the previous subscription of the app to the secret store.
+func (a *api) onSubscribeSecrets(reqCtx *fasthttp.RequestCtx) {
+	store, secretStoreName, err := a.getSecretStoreWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	var req SubscribeSecretsRequest
+	err = a.json.Unmarshal(reqCtx.PostBody(), &req)
+	if err != nil || req.Method == "" || len(req.Keys) == 0 {
+		if err == nil {
+			err = errors.New("method and keys are required")
+		}
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
+	if !a.isSecretOperationAllowed(reqCtx, secretStoreName, config.SecretOperationGet) {
+		return
+	}
+
+	for _, key := range req.Keys {
+		if !a.isSecretAllowed(secretStoreName, key) {
+			msg := NewErrorResponse("ERR_PERMISSION_DENIED", fmt.Sprintf(messages.ErrPermissionDenied, key, secretStoreName))
+			respond(reqCtx, withError(fasthttp.StatusForbidden, msg))
+			log.Debug(msg)
+			return
+		}
+	}
+
+	a.secretSubscriptionsLock.Lock()
+	defer a.secretSubscriptionsLock.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = secretstores_loader.Watch(ctx, store, req.Keys, req.Metadata, secretstores_loader.DefaultWatchInterval, func(update secretstores_loader.SecretUpdate) {
+		a.deliverSecretUpdate(ctx, secretStoreName, req.Method, update)
+	})
+	if err != nil {
+		cancel()
+		msg := NewErrorResponse("ERR_SECRET_SUBSCRIBE", fmt.Sprintf(messages.ErrSecretSubscribe, req.Keys, secretStoreName, err))
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		log.Debug(msg)
+		return
+	}
+
+	if a.secretSubscriptions == nil {
+		a.secretSubscriptions = map[string]context.CancelFunc{}
+	}
+	if previous, ok := a.secretSubscriptions[secretStoreName]; ok {
+		previous()
+	}
+	a.secretSubscriptions[secretStoreName] = cancel
+	respond(reqCtx, withEmpty())
+}
+
+func (a *api) onUnsubscribeSecrets(reqCtx *fasthttp.RequestCtx) {
+	_, secretStoreName, err := a.getSecretStoreWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	a.secretSubscriptionsLock.Lock()
+	if cancel, ok := a.secretSubscriptions[secretStoreName]; ok {
+		cancel()
+		delete(a.secretSubscriptions, secretStoreName)
+	}
+	a.secretSubscriptionsLock.Unlock()
+	respond(reqCtx, withEmpty())
+}
+
+// deliverSecretUpdate posts a new version of a secret to the webhook method of the app.
+func (a *api) deliverSecretUpdate(ctx context.Context, secretStoreName, method string, update secretstores_loader.SecretUpdate) {
+	if a.appChannel == nil {
+		log.Warnf("failed to deliver the new version of secret %s from secret store %s: app channel is not initialized", update.Key, secretStoreName)
+		return
+	}
+
+	data, err := a.json.Marshal(update)
+	if err != nil {
+		log.Warnf("failed to deliver the new version of secret %s from secret store %s: %s", update.Key, secretStoreName, err)
+		return
+	}
+
+	req := invokev1.NewInvokeMethodRequest(method)
+	req.WithHTTPExtension(fasthttp.MethodPost, "")
+	req.WithRawData(data, invokev1.JSONContentType)
+
+	resp, err := a.appChannel.InvokeMethod(ctx, req)
+	if err != nil {
+		log.Warnf("failed to deliver the new version of secret %s from secret store %s: %s", update.Key, secretStoreName, err)
+		return
+	}
+	if code := resp.Status().Code; code < 200 || code > 299 {
+		log.Warnf("failed to deliver the new version of secret %s from secret store %s: app returned status code %d", update.Key, secretStoreName, code)
+	}
+}
+
+// onCrypto returns the handler performing operation with a key of the transit engine of a secret store.
+// The keys are subject to the secret scopes of the store.
+func (a *api) onCrypto(operation string) fasthttp.RequestHandler {
+	return func(reqCtx *fasthttp.RequestCtx) {
+		store, secretStoreName, err := a.getSecretStoreWithRequestValidation(reqCtx)
+		if err != nil {
+			log.Debug(err)
+			return
+		}
+
+		transit, ok := secretstores_loader.GetTransitSecretStore(store)
+		if !ok {
+			msg := NewErrorResponse("ERR_CRYPTO_NOT_SUPPORTED", fmt.Sprintf(messages.ErrCryptoNotSupported, secretStoreName))
+			respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+			log.Debug(msg)
+			return
+		}
+
+		var req CryptoRequest
+		err = a.json.Unmarshal(reqCtx.PostBody(), &req)
+		if err != nil || req.KeyName == "" {
+			if err == nil {
+				err = errors.New("keyName is required")
+			}
+			msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+			respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+			log.Debug(msg)
+			return
+		}
+
+		if !a.isSecretAllowed(secretStoreName, req.KeyName) {
+			msg := NewErrorResponse("ERR_PERMISSION_DENIED", fmt.Sprintf(messages.ErrPermissionDenied, req.KeyName, secretStoreName))
+			respond(reqCtx, withError(fasthttp.StatusForbidden, msg))
+			log.Debug(msg)
+			return
+		}
+
+		resp, err := secretstores_loader.Crypto(transit, operation, secretstores_loader.CryptoRequest{
+			KeyName:   req.KeyName,
+			Data:      req.Data,
+			Signature: req.Signature,
+			Algorithm: req.Algorithm,
+			Metadata:  req.Metadata,
+		})
+		if err != nil {
+			msg := NewErrorResponse("ERR_CRYPTO_OPERATION", fmt.Sprintf(messages.ErrCryptoOperation, operation, req.KeyName, secretStoreName, err))
+			respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+			log.Debug(msg)
+			return
+		}
+
+		respBytes, _ := a.json.Marshal(CryptoResponse{
+			Data:  resp.Data,
+			Valid: resp.Valid,
+		})
+		respond(reqCtx, withJSON(fasthttp.StatusOK, respBytes))
+	}
+}
+
 func (a *api) getSecretStoreWithRequestValidation(reqCtx *fasthttp.RequestCtx) (secretstores.SecretStore, string, error) {
 	if a.secretStores == nil || len(a.secretStores) == 0 {
 		msg := NewErrorResponse("ERR_SECRET_STORES_NOT_CONFIGURED", messages.ErrSecretStoreNotConfigured)
@@ -834,9 +1641,15 @@ func (a *api) onPostState(reqCtx *fasthttp.RequestCtx) {
 		}
 	}
 
-	err = store.BulkSet(reqs)
+	op := diag.StartStateOperation(reqCtx, a.tracingSpec, storeName, "set", len(reqs))
+	if err = chaos.Inject(reqCtx, chaos.State, storeName); err == nil {
+		err = a.resiliency.ComponentOperation(storeName, func() error {
+			return store.BulkSet(reqs)
+		})
+	}
+	err = state_loader.SetFallback(a.stateStores, storeName, a.id, reqs, a.resiliency.ComponentFallback(storeName), err)
+	op.End(err)
 	if err != nil {
-		storeName := a.getStateStoreName(reqCtx)
 
 		statusCode, errMsg, resp := a.stateErrorResponse(err, "ERR_STATE_SAVE")
 		resp.Message = fmt.Sprintf(messages.ErrStateSave, storeName, errMsg)
@@ -911,6 +1724,7 @@ func (a *api) onDirectMessage(reqCtx *fasthttp.RequestCtx) {
 	// Save headers to internal metadata
 	req.WithFastHTTPHeaders(&reqCtx.Request.Header)
 
+	defer drain.Default.Track(drain.Invocation)()
 	resp, err := a.directMessaging.Invoke(reqCtx, targetID, req)
 	// err does not represent user application response
 	if err != nil {
@@ -1237,6 +2051,7 @@ func (a *api) onDirectActorMessage(reqCtx *fasthttp.RequestCtx) {
 	})
 	req.WithMetadata(metadata)
 
+	defer drain.Default.Track(drain.Actor)()
 	resp, err := a.actor.Call(reqCtx, req)
 	if err != nil {
 		msg := NewErrorResponse("ERR_ACTOR_INVOKE_METHOD", fmt.Sprintf(messages.ErrActorInvoke, err))
@@ -1311,9 +2126,15 @@ func (a *api) onGetMetadata(reqCtx *fasthttp.RequestCtx) {
 		return true
 	})
 
+	actorRuntime := runtime_metadata.GetActorRuntime(reqCtx, a.actor)
 	activeActorsCount := []actors.ActiveActorsCount{}
-	if a.actor != nil {
-		activeActorsCount = a.actor.GetActiveActorsCount(reqCtx)
+	if actorRuntime.ActiveActors != nil {
+		activeActorsCount = actorRuntime.ActiveActors
+	}
+
+	var details runtime_metadata.Details
+	if a.getMetadataDetailsFn != nil {
+		details = a.getMetadataDetailsFn()
 	}
 
 	components := a.getComponentsFn()
@@ -1321,18 +2142,31 @@ func (a *api) onGetMetadata(reqCtx *fasthttp.RequestCtx) {
 
 	for _, comp := range components {
 		registeredComp := registeredComponent{
-			Name:    comp.Name,
-			Version: comp.Spec.Version,
-			Type:    comp.Spec.Type,
+			Name:         comp.Name,
+			Version:      comp.Spec.Version,
+			Type:         comp.Spec.Type,
+			Capabilities: details.ComponentCapabilities(comp.Spec.Type, comp.Name),
 		}
 		registeredComponents = append(registeredComponents, registeredComp)
 	}
 
+	events := []diag.RuntimeEvent{}
+	if a.eventLog != nil {
+		events = a.eventLog.Events()
+	}
+
 	mtd := metadata{
 		ID:                   a.id,
 		ActiveActorsCount:    activeActorsCount,
 		Extended:             temp,
 		RegisteredComponents: registeredComponents,
+		Events:               events,
+		CircuitBreakers:      a.resiliency.CircuitBreakers(),
+		ConcurrencyLimiters:  a.resiliency.ConcurrencyLimiters(),
+		EnabledFeatures:      details.EnabledFeatures,
+		Subscriptions:        details.Subscriptions,
+		ActorRuntime:         actorRuntime,
+		Runtime:              runtime_metadata.GetBuild(),
 	}
 
 	mtdBytes, err := a.json.Marshal(mtd)
@@ -1352,15 +2186,197 @@ func (a *api) onPutMetadata(reqCtx *fasthttp.RequestCtx) {
 	respond(reqCtx, withEmpty())
 }
 
+// onShutdown shuts the sidecar down. With drain=true, the sidecar first stops accepting new work and waits for the
+// work in flight to complete, up to the timeout query parameter, streaming the progress of the drain as newline
+// delimited JSON before it exits.
 func (a *api) onShutdown(reqCtx *fasthttp.RequestCtx) {
 	if !reqCtx.IsPost() {
 		log.Warn("Please use POST method when invoking shutdown API")
 	}
 
+	if string(reqCtx.QueryArgs().Peek(drainParam)) != "true" {
+		respond(reqCtx, withEmpty())
+		go func() {
+			a.shutdown()
+		}()
+		return
+	}
+
+	timeout := defaultDrainTimeout
+	if v := string(reqCtx.QueryArgs().Peek(timeoutParam)); v != "" {
+		d, err := time.ParseDuration(v)
+		if err == nil && d <= 0 {
+			err = errors.New("the timeout must be positive")
+		}
+		if err != nil {
+			msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrShutdownMalformed, v, err))
+			respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+			log.Debug(msg)
+			return
+		}
+		timeout = d
+	}
+
+	log.Infof("draining the work in flight for up to %s before shutting down", timeout)
+	reqCtx.SetContentType(eventsContentType)
+	reqCtx.SetStatusCode(fasthttp.StatusOK)
+	reqCtx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		progress := drain.Default.Drain(context.Background(), timeout, drainProgressInterval, func(p drain.Progress) {
+			b, err := a.json.Marshal(p)
+			if err != nil {
+				log.Debugf("failed to serialize drain progress: %s", err)
+				return
+			}
+			w.Write(b)
+			w.WriteByte('\n')
+			// The drain goes on when the caller disconnects.
+			w.Flush()
+		})
+		log.Infof("drain completed with status %s, shutting down", progress.Status)
+		go func() {
+			a.shutdown()
+		}()
+	})
+}
+
+// onGetEvents streams the events of the runtime event log as newline delimited JSON, starting with the events
+// already in the log, until the client disconnects or the server shuts down.
+func (a *api) onGetEvents(reqCtx *fasthttp.RequestCtx) {
+	events, ch, cancel := a.eventLog.Subscribe()
+	done := reqCtx.Done()
+
+	reqCtx.SetContentType(eventsContentType)
+	reqCtx.SetStatusCode(fasthttp.StatusOK)
+	reqCtx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+
+		heartbeat := time.NewTicker(eventsHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		write := func(event diag.RuntimeEvent) {
+			b, err := a.json.Marshal(event)
+			if err != nil {
+				log.Debugf("failed to serialize runtime event: %s", err)
+				return
+			}
+			w.Write(b)
+			w.WriteByte('\n')
+		}
+		for _, e := range events {
+			write(e)
+		}
+
+		for {
+			// A failed flush means the client disconnected.
+			if err := w.Flush(); err != nil {
+				return
+			}
+			select {
+			case e := <-ch:
+				write(e)
+			case <-heartbeat.C:
+				// The heartbeat detects the disconnection of the clients while there are no events.
+				w.WriteByte('\n')
+			case <-done:
+				return
+			}
+		}
+	})
+}
+
+func (a *api) onGetCircuitBreakers(reqCtx *fasthttp.RequestCtx) {
+	statuses := a.resiliency.CircuitBreakers()
+	if statuses == nil {
+		statuses = []resiliency.CircuitBreakerStatus{}
+	}
+
+	b, err := a.json.Marshal(statuses)
+	if err != nil {
+		msg := NewErrorResponse("ERR_CIRCUIT_BREAKERS_GET", fmt.Sprintf(messages.ErrCircuitBreakersGet, err))
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		log.Debug(msg)
+		return
+	}
+	respond(reqCtx, withJSON(fasthttp.StatusOK, b))
+}
+
+func (a *api) onTripCircuitBreaker(reqCtx *fasthttp.RequestCtx) {
+	if breaker := a.getCircuitBreaker(reqCtx); breaker != nil {
+		breaker.Trip()
+		respond(reqCtx, withEmpty())
+	}
+}
+
+func (a *api) onResetCircuitBreaker(reqCtx *fasthttp.RequestCtx) {
+	if breaker := a.getCircuitBreaker(reqCtx); breaker != nil {
+		breaker.Reset()
+		respond(reqCtx, withEmpty())
+	}
+}
+
+// onSimulateResiliency reports how the resiliency policies, or the policies of the spec of the request, handle
+// hypothetical calls to a target.
+func (a *api) onSimulateResiliency(reqCtx *fasthttp.RequestCtx) {
+	var req resiliency.SimulationRequest
+	if err := a.json.Unmarshal(reqCtx.PostBody(), &req); err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
+	result, err := a.resiliency.Simulate(req)
+	if err != nil {
+		msg := NewErrorResponse("ERR_RESILIENCY_SIMULATE", fmt.Sprintf(messages.ErrResiliencySimulate, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+	b, err := a.json.Marshal(result)
+	if err != nil {
+		msg := NewErrorResponse("ERR_RESILIENCY_SIMULATE", fmt.Sprintf(messages.ErrResiliencySimulate, err))
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		log.Debug(msg)
+		return
+	}
+	respond(reqCtx, withJSON(fasthttp.StatusOK, b))
+}
+
+// getCircuitBreaker returns the circuit breaker named in the request, or responds with an error if there is none.
+func (a *api) getCircuitBreaker(reqCtx *fasthttp.RequestCtx) *resiliency.CircuitBreaker {
+	name := reqCtx.UserValue(nameParam).(string)
+	breaker := a.resiliency.AppCircuitBreaker(name)
+	if breaker == nil {
+		msg := NewErrorResponse("ERR_CIRCUIT_BREAKER_NOT_FOUND", fmt.Sprintf(messages.ErrCircuitBreakerNotFound, name))
+		respond(reqCtx, withError(fasthttp.StatusNotFound, msg))
+		log.Debug(msg)
+	}
+	return breaker
+}
+
+func (a *api) onSetLogLevel(reqCtx *fasthttp.RequestCtx) {
+	var req LogLevelRequest
+	err := a.json.Unmarshal(reqCtx.PostBody(), &req)
+	if err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+	if req.Level == "" && len(req.Scopes) == 0 {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, "level or scopes are required"))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
+
+	if err = a.setLogLevelFn(req.Level, req.Scopes); err != nil {
+		msg := NewErrorResponse("ERR_LOG_LEVEL", fmt.Sprintf(messages.ErrLogLevel, err))
+		respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+		log.Debug(msg)
+		return
+	}
 	respond(reqCtx, withEmpty())
-	go func() {
-		a.shutdown()
-	}()
 }
 
 func (a *api) onPublish(reqCtx *fasthttp.RequestCtx) {
@@ -1462,7 +2478,7 @@ func (a *api) onPublish(reqCtx *fasthttp.RequestCtx) {
 		Metadata:   metadata,
 	}
 
-	err := a.pubsubAdapter.Publish(&req)
+	err := a.pubsubAdapter.Publish(reqCtx, &req)
 	if err != nil {
 		status := fasthttp.StatusInternalServerError
 		msg := NewErrorResponse("ERR_PUBSUB_PUBLISH_MESSAGE",
@@ -1518,6 +2534,31 @@ func (a *api) onGetOutboundHealthz(reqCtx *fasthttp.RequestCtx) {
 	}
 }
 
+// onGetDetailedHealthz reports the health of each subsystem of the sidecar. It responds with 500 like healthz if the
+// sidecar is not ready or a subsystem is not healthy.
+func (a *api) onGetDetailedHealthz(reqCtx *fasthttp.RequestCtx) {
+	details := a.getHealthDetailsFn()
+	healthy := details.UpdateStatus()
+	if healthy && !a.readyStatus {
+		healthy = false
+		details.Status = health.StatusPending
+	}
+
+	b, err := a.json.Marshal(details)
+	if err != nil {
+		msg := NewErrorResponse("ERR_HEALTH_DETAILS", fmt.Sprintf(messages.ErrHealthDetails, err))
+		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
+		log.Debug(msg)
+		return
+	}
+
+	statusCode := fasthttp.StatusOK
+	if !healthy {
+		statusCode = fasthttp.StatusInternalServerError
+	}
+	respond(reqCtx, withJSON(statusCode, b))
+}
+
 func getMetadataFromRequest(reqCtx *fasthttp.RequestCtx) map[string]string {
 	metadata := map[string]string{}
 	reqCtx.QueryArgs().VisitAll(func(key []byte, value []byte) {
@@ -1631,10 +2672,8 @@ func (a *api) onPostStateTransaction(reqCtx *fasthttp.RequestCtx) {
 				data := []byte(fmt.Sprintf("%v", req.Value))
 				val, err := encryption.TryEncryptValue(storeName, data)
 				if err != nil {
-					msg := NewErrorResponse(
-						"ERR_SAVE_STATE",
-						fmt.Sprintf(messages.ErrStateSave, storeName, err.Error()))
-					respond(reqCtx, withError(fasthttp.StatusBadRequest, msg))
+					msg := NewErrorResponse("ERR_STATE_TRANSACTION", fmt.Sprintf(messages.ErrStateTransaction, err.Error()))
+					respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
 					log.Debug(msg)
 					return
 				}
@@ -1645,10 +2684,17 @@ func (a *api) onPostStateTransaction(reqCtx *fasthttp.RequestCtx) {
 		}
 	}
 
-	err := transactionalStore.Multi(&state.TransactionalStateRequest{
-		Operations: operations,
-		Metadata:   req.Metadata,
-	})
+	op := diag.StartStateOperation(reqCtx, a.tracingSpec, storeName, "transaction", len(operations))
+	err := chaos.Inject(reqCtx, chaos.State, storeName)
+	if err == nil {
+		err = a.resiliency.ComponentOperation(storeName, func() error {
+			return transactionalStore.Multi(&state.TransactionalStateRequest{
+				Operations: operations,
+				Metadata:   req.Metadata,
+			})
+		})
+	}
+	op.End(err)
 
 	if err != nil {
 		msg := NewErrorResponse("ERR_STATE_TRANSACTION", fmt.Sprintf(messages.ErrStateTransaction, err.Error()))
@@ -1690,7 +2736,15 @@ func (a *api) onQueryState(reqCtx *fasthttp.RequestCtx) {
 	}
 	req.Metadata = getMetadataFromRequest(reqCtx)
 
-	resp, err := querier.Query(&req)
+	op := diag.StartStateOperation(reqCtx, a.tracingSpec, storeName, "query", 0)
+	var resp *state.QueryResponse
+	if err = chaos.Inject(reqCtx, chaos.State, storeName); err == nil {
+		err = a.resiliency.ComponentOperation(storeName, func() (err error) {
+			resp, err = querier.Query(&req)
+			return err
+		})
+	}
+	op.End(err)
 	if err != nil {
 		msg := NewErrorResponse("ERR_STATE_QUERY", fmt.Sprintf(messages.ErrStateQuery, storeName, err.Error()))
 		respond(reqCtx, withError(fasthttp.StatusInternalServerError, msg))
@@ -1726,6 +2780,17 @@ func (a *api) isSecretAllowed(storeName, key string) bool {
 	return true
 }
 
+// isSecretOperationAllowed checks if the operation is allowed on the secret store, and responds with an error if not.
+func (a *api) isSecretOperationAllowed(reqCtx *fasthttp.RequestCtx, storeName, operation string) bool {
+	if scope, ok := a.secretsConfiguration[storeName]; ok && !scope.IsOperationAllowed(operation) {
+		msg := NewErrorResponse("ERR_PERMISSION_DENIED", fmt.Sprintf(messages.ErrSecretOperationDenied, operation, storeName))
+		respond(reqCtx, withError(fasthttp.StatusForbidden, msg))
+		log.Debug(msg)
+		return false
+	}
+	return true
+}
+
 func (a *api) SetAppChannel(appChannel channel.AppChannel) {
 	a.appChannel = appChannel
 }
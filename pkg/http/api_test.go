@@ -15,11 +15,13 @@ limitations under the License.
 package http
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net"
 	gohttp "net/http"
 	"os"
@@ -33,6 +35,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fasthttp/fasthttputil"
 	epb "google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -52,12 +55,20 @@ import (
 	"github.com/dapr/dapr/pkg/actors"
 	components_v1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
 	"github.com/dapr/dapr/pkg/channel/http"
+	bindings_loader "github.com/dapr/dapr/pkg/components/bindings"
 	http_middleware_loader "github.com/dapr/dapr/pkg/components/middleware/http"
+	secretstores_loader "github.com/dapr/dapr/pkg/components/secretstores"
 	"github.com/dapr/dapr/pkg/config"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
+	"github.com/dapr/dapr/pkg/drain"
 	"github.com/dapr/dapr/pkg/encryption"
+	"github.com/dapr/dapr/pkg/health"
+	"github.com/dapr/dapr/pkg/jobs"
+	"github.com/dapr/dapr/pkg/messages"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
 	http_middleware "github.com/dapr/dapr/pkg/middleware/http"
+	"github.com/dapr/dapr/pkg/resiliency"
+	runtime_metadata "github.com/dapr/dapr/pkg/runtime/metadata"
 	runtime_pubsub "github.com/dapr/dapr/pkg/runtime/pubsub"
 	daprt "github.com/dapr/dapr/pkg/testing"
 	testtrace "github.com/dapr/dapr/pkg/testing/trace"
@@ -125,6 +136,8 @@ func TestPubSubEndpoints(t *testing.T) {
 			// assert
 			assert.Equal(t, 500, resp.StatusCode, "expected internal server error as response")
 			assert.Equal(t, "ERR_PUBSUB_PUBLISH_MESSAGE", resp.ErrorBody["errorCode"])
+			assert.Equal(t, true, resp.ErrorBody["retriable"])
+			assert.Equal(t, messages.ErrorsDocsURL+"#err-pubsub-publish-message", resp.ErrorBody["docsUrl"])
 		}
 	})
 
@@ -208,6 +221,7 @@ func TestPubSubEndpoints(t *testing.T) {
 			assert.Equal(t, 400, resp.StatusCode, "unexpected success publishing with %s", method)
 			assert.Equal(t, "ERR_PUBSUB_NOT_FOUND", resp.ErrorBody["errorCode"])
 			assert.Equal(t, "pubsub 'errnotfound' not found", resp.ErrorBody["message"])
+			assert.Nil(t, resp.ErrorBody["retriable"])
 		}
 	})
 
@@ -251,9 +265,91 @@ func TestShutdownEndpoints(t *testing.T) {
 		m.AssertCalled(t, "shutdown")
 	})
 
+	t.Run("Drain with an invalid timeout - 400", func(t *testing.T) {
+		apiPath := fmt.Sprintf("%s/shutdown", apiVersionV1)
+		resp := fakeServer.DoRequest("POST", apiPath, nil, map[string]string{"drain": "true", "timeout": "-1s"})
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_MALFORMED_REQUEST", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Drain the work in flight - 200", func(t *testing.T) {
+		defer func(tracker *drain.Tracker) {
+			drain.Default = tracker
+		}(drain.Default)
+		drain.Default = drain.NewTracker()
+
+		done, _ := drain.Default.Begin(drain.PubSub)
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			done()
+		}()
+
+		calls := len(m.Calls)
+		apiPath := fmt.Sprintf("%s/shutdown", apiVersionV1)
+		resp := fakeServer.DoRequest("POST", apiPath, nil, map[string]string{"drain": "true", "timeout": "5s"})
+		assert.Equal(t, 200, resp.StatusCode)
+
+		lines := strings.Split(strings.TrimSpace(string(resp.RawBody)), "\n")
+		var last drain.Progress
+		require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &last))
+		assert.Equal(t, drain.StatusDrained, last.Status)
+		assert.Equal(t, 0, last.InFlight[drain.PubSub])
+
+		_, ok := drain.Default.Begin(drain.Invocation)
+		assert.False(t, ok, "new work is rejected once draining")
+		for i := 0; i < 5 && len(m.Calls) == calls; i++ {
+			<-time.After(200 * time.Millisecond)
+		}
+		assert.Len(t, m.Calls, calls+1)
+	})
+
 	fakeServer.Shutdown()
 }
 
+func TestShutdownDrainStreamedThroughMetrics(t *testing.T) {
+	diag.DefaultHTTPMonitoring.Init("fakeAppID")
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{
+		json:     jsoniter.ConfigFastest,
+		shutdown: func() {},
+	}
+	fakeServer.StartServerWithMetrics(testAPI.constructShutdownEndpoints())
+	defer fakeServer.Shutdown()
+
+	defer func(tracker *drain.Tracker) {
+		drain.Default = tracker
+	}(drain.Default)
+	drain.Default = drain.NewTracker()
+	done, _ := drain.Default.Begin(drain.PubSub)
+
+	url := fmt.Sprintf("http://localhost/%s/shutdown?drain=true&timeout=10s", apiVersionV1)
+	r, _ := gohttp.NewRequest("POST", url, nil)
+	res, err := fakeServer.client.Do(r)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, 200, res.StatusCode)
+
+	// the progress is read while the work is still in flight, so the metrics middleware doesn't buffer it.
+	reader := bufio.NewReader(res.Body)
+	line, err := reader.ReadBytes('\n')
+	require.NoError(t, err)
+	var progress drain.Progress
+	require.NoError(t, json.Unmarshal(line, &progress))
+	assert.Equal(t, drain.StatusDraining, progress.Status)
+	assert.Equal(t, 1, progress.InFlight[drain.PubSub])
+
+	done()
+	for {
+		line, err = reader.ReadBytes('\n')
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(line, &progress))
+		if progress.Status != drain.StatusDraining {
+			break
+		}
+	}
+	assert.Equal(t, drain.StatusDrained, progress.Status)
+}
+
 func TestGetStatusCodeFromMetadata(t *testing.T) {
 	t.Run("status code present", func(t *testing.T) {
 		res := GetStatusCodeFromMetadata(map[string]string{
@@ -368,6 +464,408 @@ func TestV1OutputBindingsEndpoints(t *testing.T) {
 		}
 	})
 
+	t.Run("Invoke output bindings - 503 lazy initialization failed", func(t *testing.T) {
+		apiPath := fmt.Sprintf("%s/bindings/lazy", apiVersionV1)
+		b, _ := json.Marshal(&OutputBindingRequest{Data: "fake output"})
+
+		testAPI.sendToOutputBindingFn = func(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+			return nil, &bindings_loader.LazyInitError{Name: name, Err: errors.New("connection refused")}
+		}
+
+		resp := fakeServer.DoRequest("POST", apiPath, b, nil)
+
+		assert.Equal(t, 503, resp.StatusCode)
+		assert.Equal(t, "ERR_OUTPUT_BINDING_INIT", resp.ErrorBody["errorCode"])
+		assert.Equal(t, "output binding lazy failed to initialize: connection refused", resp.ErrorBody["message"])
+	})
+
+	fakeServer.Shutdown()
+}
+
+func TestV1OutputBindingsStreamEndpoints(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{
+		sendToOutputBindingStreamFn: func(name string, req *bindings.InvokeRequest) (*bindings_loader.StreamingInvokeResponse, error) {
+			if name == "testbinding" {
+				return nil, nil
+			}
+			return &bindings_loader.StreamingInvokeResponse{
+				Data:     ioutil.NopCloser(strings.NewReader("testresponse")),
+				Metadata: map[string]string{"key": "value"},
+				Size:     -1,
+			}, nil
+		},
+		json: jsoniter.ConfigFastest,
+	}
+	fakeServer.StartServer(testAPI.constructBindingsEndpoints())
+
+	b, _ := json.Marshal(&OutputBindingRequest{Data: "fake output"})
+
+	t.Run("Invoke output bindings - 204 No Content empty response", func(t *testing.T) {
+		apiPath := fmt.Sprintf("%s/bindings/testbinding", apiVersionV1)
+		resp := fakeServer.DoRequest("POST", apiPath, b, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+	})
+
+	t.Run("Invoke output bindings - 200 OK streamed response", func(t *testing.T) {
+		apiPath := fmt.Sprintf("%s/bindings/testresponse", apiVersionV1)
+		resp := fakeServer.DoRequest("POST", apiPath, b, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, []byte("testresponse"), resp.RawBody)
+		assert.Equal(t, "value", resp.RawHeader.Get(metadataPrefix+"key"))
+	})
+
+	t.Run("Invoke output bindings - 500 InternalError", func(t *testing.T) {
+		testAPI.sendToOutputBindingStreamFn = func(name string, req *bindings.InvokeRequest) (*bindings_loader.StreamingInvokeResponse, error) {
+			return nil, errors.New("missing binding name")
+		}
+
+		apiPath := fmt.Sprintf("%s/bindings/notfound", apiVersionV1)
+		resp := fakeServer.DoRequest("POST", apiPath, b, nil)
+		assert.Equal(t, 500, resp.StatusCode)
+		assert.Equal(t, "ERR_INVOKE_OUTPUT_BINDING", resp.ErrorBody["errorCode"])
+	})
+
+	fakeServer.Shutdown()
+}
+
+func TestV1OutputBindingOperationsEndpoint(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{
+		getOutputBindingOperationsFn: func(name string) ([]bindings_loader.OperationDescription, error) {
+			if name == "notfound" {
+				return nil, errors.New("couldn't find output binding notfound")
+			}
+			return []bindings_loader.OperationDescription{
+				{Name: "get", Metadata: []string{"key"}},
+				{Name: "create"},
+			}, nil
+		},
+		json: jsoniter.ConfigFastest,
+	}
+	fakeServer.StartServer(testAPI.constructBindingsEndpoints())
+
+	t.Run("Get output binding operations - 200 OK", func(t *testing.T) {
+		apiPath := fmt.Sprintf("%s/bindings/testbinding/operations", apiVersionV1)
+		resp := fakeServer.DoRequest("GET", apiPath, nil, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.JSONEq(t, `[{"name":"get","metadata":["key"]},{"name":"create"}]`, string(resp.RawBody))
+	})
+
+	t.Run("Get output binding operations - 404 Not Found", func(t *testing.T) {
+		apiPath := fmt.Sprintf("%s/bindings/notfound/operations", apiVersionV1)
+		resp := fakeServer.DoRequest("GET", apiPath, nil, nil)
+		assert.Equal(t, 404, resp.StatusCode)
+		assert.Equal(t, "ERR_BINDING_NOT_FOUND", resp.ErrorBody["errorCode"])
+	})
+
+	fakeServer.Shutdown()
+}
+
+func TestV1BulkOutputBindingsEndpoint(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{
+		sendToOutputBindingBulkFn: func(name string, reqs []*bindings.InvokeRequest) ([]bindings_loader.BulkInvokeResult, error) {
+			if name == "notfound" {
+				return nil, errors.New("couldn't find output binding notfound")
+			}
+			return []bindings_loader.BulkInvokeResult{
+				{Response: &bindings.InvokeResponse{Data: []byte(`{"id":1}`), Metadata: map[string]string{"key": "value"}}},
+				{Response: &bindings.InvokeResponse{Data: []byte("plain text")}},
+				{Error: errors.New("operation failed")},
+				{},
+			}, nil
+		},
+		json: jsoniter.ConfigFastest,
+	}
+	fakeServer.StartServer(testAPI.constructBindingsEndpoints())
+
+	b, _ := json.Marshal(&BulkOutputBindingRequest{Requests: []OutputBindingRequest{
+		{Data: "1", Operation: "create"},
+		{Data: "2", Operation: "create"},
+		{Data: "3", Operation: "create"},
+		{Data: "4", Operation: "create"},
+	}})
+
+	t.Run("Bulk invoke output binding - 200 OK", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", fmt.Sprintf("%s/bindings/testbinding/bulk", apiVersionV1alpha1), b, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.JSONEq(t, `[{"data":{"id":1},"metadata":{"key":"value"}},{"data":"plain text"},{"error":"operation failed"},{}]`, string(resp.RawBody))
+	})
+
+	t.Run("Bulk invoke output binding - 500 InternalError", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", fmt.Sprintf("%s/bindings/notfound/bulk", apiVersionV1alpha1), b, nil)
+		assert.Equal(t, 500, resp.StatusCode)
+		assert.Equal(t, "ERR_INVOKE_OUTPUT_BINDING", resp.ErrorBody["errorCode"])
+	})
+
+	fakeServer.Shutdown()
+}
+
+func TestV1LogLevelEndpoint(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	var level string
+	var scopes map[string]string
+	testAPI := &api{
+		setLogLevelFn: func(l string, s map[string]string) error {
+			if l == "verbose" {
+				return errors.New("undefined Log Output Level: verbose")
+			}
+			level = l
+			scopes = s
+			return nil
+		},
+		json: jsoniter.ConfigFastest,
+	}
+	fakeServer.StartServer(testAPI.constructLoggingEndpoints())
+	apiPath := fmt.Sprintf("%s/logs/level", apiVersionV1alpha1)
+
+	t.Run("Set log level - 204 No Content", func(t *testing.T) {
+		b, _ := json.Marshal(&LogLevelRequest{Level: "debug", Scopes: map[string]string{"runtime.actor": "info"}})
+		resp := fakeServer.DoRequest("PUT", apiPath, b, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		assert.Equal(t, "debug", level)
+		assert.Equal(t, map[string]string{"runtime.actor": "info"}, scopes)
+	})
+
+	t.Run("Set log level - 400 Bad Request", func(t *testing.T) {
+		for _, body := range []string{"not json", "{}", `{"level":"verbose"}`} {
+			resp := fakeServer.DoRequest("PUT", apiPath, []byte(body), nil)
+			assert.Equal(t, 400, resp.StatusCode, body)
+		}
+		resp := fakeServer.DoRequest("PUT", apiPath, []byte(`{"level":"verbose"}`), nil)
+		assert.Equal(t, "ERR_LOG_LEVEL", resp.ErrorBody["errorCode"])
+	})
+
+	fakeServer.Shutdown()
+}
+
+func TestV1CircuitBreakersEndpoints(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	r, err := resiliency.FromConfiguration(config.ResiliencySpec{
+		Policies: config.ResiliencyPolicies{
+			CircuitBreakers: map[string]config.CircuitBreakerPolicySpec{
+				"cb": {MaxConsecutiveFailures: 3, Timeout: "30s"},
+			},
+		},
+		Targets: config.ResiliencyTargets{
+			Apps: map[string]config.AppResiliencyTarget{
+				"app1": {CircuitBreaker: "cb"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	testAPI := &api{
+		resiliency: r,
+		json:       jsoniter.ConfigFastest,
+	}
+	fakeServer.StartServer(testAPI.constructResiliencyEndpoints())
+	defer fakeServer.Shutdown()
+	apiPath := fmt.Sprintf("%s/resiliency/circuitbreakers", apiVersionV1alpha1)
+
+	getStatuses := func() []resiliency.CircuitBreakerStatus {
+		resp := fakeServer.DoRequest("GET", apiPath, nil, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+		var statuses []resiliency.CircuitBreakerStatus
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &statuses))
+		return statuses
+	}
+
+	t.Run("Get circuit breakers - 200 OK", func(t *testing.T) {
+		statuses := getStatuses()
+		assert.Len(t, statuses, 1)
+		assert.Equal(t, "app1", statuses[0].Name)
+		assert.Equal(t, resiliency.CircuitBreakerClosed, statuses[0].State)
+	})
+
+	t.Run("Trip and reset circuit breaker - 204 No Content", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", apiPath+"/app1/trip", nil, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		statuses := getStatuses()
+		assert.Equal(t, resiliency.CircuitBreakerOpen, statuses[0].State)
+		assert.True(t, statuses[0].Tripped)
+
+		resp = fakeServer.DoRequest("POST", apiPath+"/app1/reset", nil, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		assert.Equal(t, resiliency.CircuitBreakerClosed, getStatuses()[0].State)
+	})
+
+	t.Run("Trip circuit breaker - 404 Not Found", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", apiPath+"/app2/trip", nil, nil)
+		assert.Equal(t, 404, resp.StatusCode)
+		assert.Equal(t, "ERR_CIRCUIT_BREAKER_NOT_FOUND", resp.ErrorBody["errorCode"])
+	})
+}
+
+func TestV1ResiliencySimulateEndpoint(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{
+		json: jsoniter.ConfigFastest,
+	}
+	fakeServer.StartServer(testAPI.constructResiliencyEndpoints())
+	defer fakeServer.Shutdown()
+	apiPath := fmt.Sprintf("%s/resiliency/simulate", apiVersionV1alpha1)
+
+	t.Run("Simulate spec - 200 OK", func(t *testing.T) {
+		body := []byte(`{
+			"app": "app1",
+			"method": "orders/1",
+			"errors": [true, true, false],
+			"interval": "10s",
+			"spec": {
+				"policies": {
+					"timeouts": {"fast": "1s"},
+					"circuitBreakers": {"cb": {"maxConsecutiveFailures": 1, "timeout": "15s"}}
+				},
+				"targets": {"apps": {"app1": {"circuitBreaker": "cb", "routes": {"orders/*": {"timeout": "fast"}}}}}
+			}
+		}`)
+		resp := fakeServer.DoRequest("POST", apiPath, body, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result resiliency.SimulationResult
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &result))
+		assert.Equal(t, "orders/*", result.Policies.Route)
+		assert.Equal(t, "fast", result.Policies.Timeout)
+		assert.Equal(t, "1s", result.Timeout)
+		assert.Len(t, result.Steps, 3)
+		assert.Equal(t, resiliency.SimulatedError, result.Steps[0].Result)
+		assert.Equal(t, resiliency.CircuitBreakerOpen, result.Steps[0].CircuitBreaker)
+		assert.Equal(t, resiliency.SimulatedRejected, result.Steps[1].Result)
+		assert.Equal(t, resiliency.SimulatedSuccess, result.Steps[2].Result)
+		assert.Equal(t, resiliency.CircuitBreakerClosed, result.Steps[2].CircuitBreaker)
+	})
+
+	t.Run("Simulate invalid spec - 400 Bad Request", func(t *testing.T) {
+		body := []byte(`{"app": "app1", "spec": {"targets": {"apps": {"app1": {"timeout": "unknown"}}}}}`)
+		resp := fakeServer.DoRequest("POST", apiPath, body, nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_RESILIENCY_SIMULATE", resp.ErrorBody["errorCode"])
+	})
+}
+
+func TestV1EventsEndpoint(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{
+		eventLog: diag.DefaultEventLog,
+		json:     jsoniter.ConfigFastest,
+	}
+	fakeServer.StartServer(testAPI.constructEventsEndpoints())
+	defer fakeServer.Shutdown()
+
+	diag.DefaultEventLog.Record(diag.RuntimeEventComponentInitFailed, "component statestore (state.redis) failed to initialize")
+
+	res, err := fakeServer.client.Get(fmt.Sprintf("http://localhost/%s/events", apiVersionV1alpha1))
+	assert.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "application/x-ndjson", res.Header.Get("Content-Type"))
+
+	diag.DefaultEventLog.Record(diag.RuntimeEventPlacementDisconnected, "disconnected from placement")
+
+	// The events already in the log are streamed first, then the events recorded afterwards.
+	types := []string{}
+	decoder := json.NewDecoder(res.Body)
+	for len(types) == 0 || types[len(types)-1] != diag.RuntimeEventPlacementDisconnected {
+		var event diag.RuntimeEvent
+		if !assert.NoError(t, decoder.Decode(&event)) {
+			break
+		}
+		types = append(types, event.Type)
+	}
+	if assert.GreaterOrEqual(t, len(types), 2) {
+		assert.Equal(t, []string{diag.RuntimeEventComponentInitFailed, diag.RuntimeEventPlacementDisconnected}, types[len(types)-2:])
+	}
+}
+
+func TestV1InputBindingPauseEndpoints(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	paused := map[string]bool{}
+	testAPI := &api{
+		setInputBindingPausedFn: func(name string, p bool) error {
+			if name == "notfound" {
+				return errors.New("couldn't find input binding notfound")
+			}
+			paused[name] = p
+			return nil
+		},
+		json: jsoniter.ConfigFastest,
+	}
+	fakeServer.StartServer(testAPI.constructBindingsEndpoints())
+
+	t.Run("Pause and resume input binding - 204 No Content", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", fmt.Sprintf("%s/bindings/testbinding/pause", apiVersionV1alpha1), nil, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		assert.True(t, paused["testbinding"])
+
+		resp = fakeServer.DoRequest("POST", fmt.Sprintf("%s/bindings/testbinding/resume", apiVersionV1alpha1), nil, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		assert.False(t, paused["testbinding"])
+	})
+
+	t.Run("Pause input binding - 404 Not Found", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", fmt.Sprintf("%s/bindings/notfound/pause", apiVersionV1alpha1), nil, nil)
+		assert.Equal(t, 404, resp.StatusCode)
+		assert.Equal(t, "ERR_BINDING_NOT_FOUND", resp.ErrorBody["errorCode"])
+	})
+
+	fakeServer.Shutdown()
+}
+
+func TestV1JobsEndpoints(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{
+		json: jsoniter.ConfigFastest,
+	}
+	fakeServer.StartServer(testAPI.constructJobsEndpoints())
+	apiPath := fmt.Sprintf("%s/jobs/job1", apiVersionV1alpha1)
+
+	t.Run("Jobs not configured - 500", func(t *testing.T) {
+		for _, method := range []string{"POST", "GET", "DELETE"} {
+			resp := fakeServer.DoRequest(method, apiPath, nil, nil)
+			assert.Equal(t, 500, resp.StatusCode)
+			assert.Equal(t, "ERR_JOBS_NOT_CONFIGURED", resp.ErrorBody["errorCode"])
+		}
+		resp := fakeServer.DoRequest("GET", fmt.Sprintf("%s/jobs", apiVersionV1alpha1), nil, nil)
+		assert.Equal(t, 500, resp.StatusCode)
+		assert.Equal(t, "ERR_JOBS_NOT_CONFIGURED", resp.ErrorBody["errorCode"])
+	})
+
+	scheduler, err := jobs.NewScheduler("app1", "replica1", fakeStateStore{}, nil, nil)
+	assert.NoError(t, err)
+	testAPI.jobs = scheduler
+
+	t.Run("Get job - 404 Not Found", func(t *testing.T) {
+		resp := fakeServer.DoRequest("GET", apiPath, nil, nil)
+		assert.Equal(t, 404, resp.StatusCode)
+		assert.Equal(t, "ERR_JOB_NOT_FOUND", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Create job - 400 malformed request", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", apiPath, []byte("{not json"), nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_MALFORMED_REQUEST", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Create job - 500 invalid schedule", func(t *testing.T) {
+		b, _ := json.Marshal(&JobRequest{Schedule: "every minute", Method: "cleanup"})
+		resp := fakeServer.DoRequest("POST", apiPath, b, nil)
+		assert.Equal(t, 500, resp.StatusCode)
+		assert.Equal(t, "ERR_JOB_SAVE", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("List jobs - 200 OK", func(t *testing.T) {
+		resp := fakeServer.DoRequest("GET", fmt.Sprintf("%s/jobs", apiVersionV1alpha1), nil, map[string]string{"prefix": "cleanup-", "status": "scheduled"})
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, "[]", string(resp.RawBody))
+	})
+
+	t.Run("List jobs - 400 unknown status", func(t *testing.T) {
+		resp := fakeServer.DoRequest("GET", fmt.Sprintf("%s/jobs", apiVersionV1alpha1), nil, map[string]string{"status": "paused"})
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_MALFORMED_REQUEST", resp.ErrorBody["errorCode"])
+	})
+
 	fakeServer.Shutdown()
 }
 
@@ -378,10 +876,14 @@ func TestV1OutputBindingsEndpointsWithTracer(t *testing.T) {
 
 	createExporters(&buffer)
 
+	var metadata map[string]string
 	testAPI := &api{
-		sendToOutputBindingFn: func(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) { return nil, nil },
-		json:                  jsoniter.ConfigFastest,
-		tracingSpec:           spec,
+		sendToOutputBindingFn: func(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+			metadata = req.Metadata
+			return nil, nil
+		},
+		json:        jsoniter.ConfigFastest,
+		tracingSpec: spec,
 	}
 	fakeServer.StartServerWithTracing(spec, testAPI.constructBindingsEndpoints())
 
@@ -400,6 +902,7 @@ func TestV1OutputBindingsEndpointsWithTracer(t *testing.T) {
 
 			// assert
 			assert.Equal(t, 204, resp.StatusCode, "failed to invoke output binding with %s", method)
+			assert.Contains(t, metadata, "traceparent")
 		}
 	})
 
@@ -1601,19 +2104,43 @@ func TestV1MetadataEndpoint(t *testing.T) {
 				},
 			}
 		},
+		getMetadataDetailsFn: func() runtime_metadata.Details {
+			return runtime_metadata.Details{
+				Capabilities: map[string][]string{
+					runtime_metadata.CapabilitiesKey("mock", "MockComponent1Name"): {"ETAG"},
+				},
+				EnabledFeatures: []string{"PubSub.Routing"},
+				Subscriptions: []runtime_metadata.Subscription{
+					{PubsubName: "pubsub", Topic: "orders", Rules: []runtime_metadata.SubscriptionRule{{Path: "/orders"}}},
+				},
+			}
+		},
 		json: jsoniter.ConfigFastest,
 	}
 
 	fakeServer.StartServer(testAPI.constructMetadataEndpoints())
 
+	build := runtime_metadata.GetBuild()
 	expectedBody := map[string]interface{}{
 		"id":       "xyz",
 		"actors":   []map[string]interface{}{{"type": "abcd", "count": 10}, {"type": "xyz", "count": 5}},
 		"extended": make(map[string]string),
 		"components": []map[string]interface{}{
-			{"name": "MockComponent1Name", "type": "mock.component1Type", "version": "v1.0"},
+			{"name": "MockComponent1Name", "type": "mock.component1Type", "version": "v1.0", "capabilities": []string{"ETAG"}},
 			{"name": "MockComponent2Name", "type": "mock.component2Type", "version": "v1.0"},
 		},
+		"events":          []map[string]interface{}{},
+		"enabledFeatures": []string{"PubSub.Routing"},
+		"subscriptions": []map[string]interface{}{
+			{"pubsubname": "pubsub", "topic": "orders", "rules": []map[string]interface{}{{"path": "/orders"}}},
+		},
+		"actorRuntime": map[string]interface{}{
+			"runtimeStatus": runtime_metadata.ActorRuntimeRunning,
+			"hostReady":     true,
+			"placement":     runtime_metadata.PlacementConnected,
+			"activeActors":  []map[string]interface{}{{"type": "abcd", "count": 10}, {"type": "xyz", "count": 5}},
+		},
+		"runtime": map[string]interface{}{"version": build.Version, "goVersion": build.GoVersion},
 	}
 	expectedBodyBytes, _ := json.Marshal(expectedBody)
 
@@ -1622,6 +2149,8 @@ func TestV1MetadataEndpoint(t *testing.T) {
 		mockActors := new(daprt.MockActors)
 
 		mockActors.On("GetActiveActorsCount")
+		mockActors.On("GetHostedActorTypes").Return([]string{"abcd", "xyz"})
+		mockActors.On("IsPlacementConnected").Return(true)
 
 		testAPI.id = "xyz"
 		testAPI.actor = mockActors
@@ -1629,7 +2158,7 @@ func TestV1MetadataEndpoint(t *testing.T) {
 		resp := fakeServer.DoRequest("GET", apiPath, nil, nil)
 
 		assert.Equal(t, 200, resp.StatusCode)
-		assert.ElementsMatch(t, expectedBodyBytes, resp.RawBody)
+		assert.JSONEq(t, string(expectedBodyBytes), string(resp.RawBody))
 		mockActors.AssertNumberOfCalls(t, "GetActiveActorsCount", 1)
 	})
 
@@ -1891,6 +2420,59 @@ func TestAPIToken(t *testing.T) {
 		// assert.NotEmpty(t, resp.JSONBody, "failed to generate trace context with invoke")
 		assert.Equal(t, 401, resp.StatusCode)
 	})
+
+	t.Run("Invoke direct messaging with healthz in the query string - 401", func(t *testing.T) {
+		mockDirectMessaging.Calls = nil // reset call count
+
+		for _, apiPath := range []string{
+			"v1.0/invoke/fakeDaprID/method/fakeMethod?route=/v1.0/healthz",
+			"v1.0/invoke/fakeDaprID/method/v1.0/healthz",
+			"v1.0/invoke/fakeDaprID/method/fakeMethod%3F/v1.0/healthz",
+		} {
+			resp := fakeServer.DoRequest("POST", apiPath, []byte("fakeData"), nil)
+			assert.Equal(t, 401, resp.StatusCode, apiPath)
+		}
+		mockDirectMessaging.AssertNumberOfCalls(t, "Invoke", 0)
+	})
+}
+
+func TestAPITokenRotation(t *testing.T) {
+	os.Setenv("DAPR_API_TOKEN", "5678")
+	defer os.Unsetenv("DAPR_API_TOKEN")
+	os.Setenv("DAPR_API_TOKEN_PREVIOUS", "1234")
+	defer os.Unsetenv("DAPR_API_TOKEN_PREVIOUS")
+
+	fakeDirectMessageResponse := invokev1.NewInvokeMethodResponse(200, "OK", nil)
+	fakeDirectMessageResponse.WithRawData([]byte("fakeDirectMessageResponse"), "application/json")
+
+	mockDirectMessaging := new(daprt.MockDirectMessaging)
+	mockDirectMessaging.On("Invoke",
+		mock.Anything,
+		"fakeDaprID",
+		mock.AnythingOfType("*v1.InvokeMethodRequest")).Return(fakeDirectMessageResponse, nil)
+
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{
+		directMessaging: mockDirectMessaging,
+	}
+	fakeServer.StartServerWithAPIToken(testAPI.constructDirectMessagingEndpoints())
+
+	apiPath := "v1.0/invoke/fakeDaprID/method/fakeMethod"
+
+	t.Run("current token - 200 OK", func(t *testing.T) {
+		resp := fakeServer.DoRequestWithAPIToken("POST", apiPath, "5678", []byte("fakeData"))
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("previous token - 200 OK", func(t *testing.T) {
+		resp := fakeServer.DoRequestWithAPIToken("POST", apiPath, "1234", []byte("fakeData"))
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("unknown token - 401", func(t *testing.T) {
+		resp := fakeServer.DoRequestWithAPIToken("POST", apiPath, "5678, 1234", []byte("fakeData"))
+		assert.Equal(t, 401, resp.StatusCode)
+	})
 }
 
 func TestEmptyPipelineWithTracer(t *testing.T) {
@@ -2123,7 +2705,7 @@ type fakeHTTPResponse struct {
 	RawHeader   gohttp.Header
 	RawBody     []byte
 	JSONBody    interface{}
-	ErrorBody   map[string]string
+	ErrorBody   map[string]interface{}
 }
 
 func (f *fakeHTTPServer) StartServer(endpoints []Endpoint) {
@@ -2180,6 +2762,24 @@ func (f *fakeHTTPServer) StartServerWithAPIToken(endpoints []Endpoint) {
 	}
 }
 
+func (f *fakeHTTPServer) StartServerWithMetrics(endpoints []Endpoint) {
+	router := f.getRouter(endpoints)
+	f.ln = fasthttputil.NewInmemoryListener()
+	go func() {
+		if err := fasthttp.Serve(f.ln, diag.DefaultHTTPMonitoring.FastHTTPMiddleware(router.Handler)); err != nil {
+			panic(fmt.Errorf("failed to serve: %v", err))
+		}
+	}()
+
+	f.client = gohttp.Client{
+		Transport: &gohttp.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return f.ln.Dial()
+			},
+		},
+	}
+}
+
 func (f *fakeHTTPServer) StartServerWithTracingAndPipeline(spec config.TracingSpec, pipeline http_middleware.Pipeline, endpoints []Endpoint) {
 	router := f.getRouter(endpoints)
 	f.ln = fasthttputil.NewInmemoryListener()
@@ -2479,6 +3079,50 @@ func TestV1StateEndpoints(t *testing.T) {
 		assert.Equal(t, 500, resp.StatusCode, "updating existing key with wrong etag should fail")
 	})
 
+	t.Run("Get state - If-None-Match", func(t *testing.T) {
+		apiPath := fmt.Sprintf("v1.0/state/%s/good-key", storeName)
+		// act
+		resp := fakeServer.DoRequest("GET", apiPath, nil, nil, "If-None-Match", etag)
+		// assert
+		assert.Equal(t, 304, resp.StatusCode, "getting a key with a matching If-None-Match should not return it")
+		assert.Equal(t, []byte{}, resp.RawBody)
+
+		resp = fakeServer.DoRequest("GET", apiPath, nil, nil, "If-None-Match", `"other"`)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("Get state - If-Match", func(t *testing.T) {
+		apiPath := fmt.Sprintf("v1.0/state/%s/good-key", storeName)
+		// act
+		resp := fakeServer.DoRequest("GET", apiPath, nil, nil, "If-Match", etag)
+		// assert
+		assert.Equal(t, 200, resp.StatusCode)
+
+		resp = fakeServer.DoRequest("GET", apiPath, nil, nil, "If-Match", "BAD ETAG")
+		assert.Equal(t, 412, resp.StatusCode, "getting a key with a mismatching If-Match should fail")
+		assert.Equal(t, "ERR_STATE_PRECONDITION", resp.ErrorBody["errorCode"])
+
+		resp = fakeServer.DoRequest("GET", fmt.Sprintf("v1.0/state/%s/bad-key", storeName), nil, nil, "If-Match", "*")
+		assert.Equal(t, 412, resp.StatusCode, "getting a missing key with If-Match * should fail")
+	})
+
+	t.Run("Put state - Matching ETag", func(t *testing.T) {
+		apiPath := fmt.Sprintf("v1.0/state/%s/good-key", storeName)
+		// act
+		resp := fakeServer.DoRequest("PUT", apiPath, []byte(`{"name":"value"}`), nil, "If-Match", etag)
+		// assert
+		assert.Equal(t, 204, resp.StatusCode, "putting a key with a matching etag should succeed")
+	})
+
+	t.Run("Put state - Malformed value", func(t *testing.T) {
+		apiPath := fmt.Sprintf("v1.0/state/%s/good-key", storeName)
+		// act
+		resp := fakeServer.DoRequest("PUT", apiPath, []byte(`{"name"`), nil)
+		// assert
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_MALFORMED_REQUEST", resp.ErrorBody["errorCode"])
+	})
+
 	t.Run("Bulk state get - Empty request", func(t *testing.T) {
 		apiPath := fmt.Sprintf("v1.0/state/%s/bulk", storeName)
 		request := BulkGetRequest{}
@@ -2784,6 +3428,7 @@ func TestV1SecretEndpoints(t *testing.T) {
 		"store2": fakeStore,
 		"store3": fakeStore,
 		"store4": fakeStore,
+		"store5": fakeStore,
 	}
 	secretsConfiguration := map[string]config.SecretsScope{
 		"store1": {
@@ -2798,6 +3443,11 @@ func TestV1SecretEndpoints(t *testing.T) {
 			DefaultAccess:  config.AllowAccess,
 			AllowedSecrets: []string{"good-key"},
 		},
+		"store5": {
+			DefaultAccess:         config.DenyAccess,
+			AllowedSecretPatterns: []string{"^good-"},
+			AllowedOperations:     []string{config.SecretOperationGet},
+		},
 	}
 
 	testAPI := &api{
@@ -2913,6 +3563,189 @@ func TestV1SecretEndpoints(t *testing.T) {
 		// assert
 		assert.Equal(t, 200, resp.StatusCode, "reading secrets should succeed")
 	})
+
+	t.Run("Get secret - Good Key allowed by pattern", func(t *testing.T) {
+		apiPath := "v1.0/secrets/store5/good-key"
+		// act
+		resp := fakeServer.DoRequest("GET", apiPath, nil, nil)
+		// assert
+		assert.Equal(t, 200, resp.StatusCode, "reading secret matching an allowed pattern should succeed")
+	})
+
+	t.Run("Get secret - 403 Key not matching allowed pattern", func(t *testing.T) {
+		apiPath := "v1.0/secrets/store5/random"
+		// act
+		resp := fakeServer.DoRequest("GET", apiPath, nil, nil)
+		// assert
+		assert.Equal(t, 403, resp.StatusCode, "reading secret not matching an allowed pattern should return 403")
+	})
+
+	t.Run("Get Bulk secret - 403 bulkGet not allowed", func(t *testing.T) {
+		apiPath := "v1.0/secrets/store5/bulk"
+		// act
+		resp := fakeServer.DoRequest("GET", apiPath, nil, nil)
+		// assert
+		assert.Equal(t, 403, resp.StatusCode, "bulk reading secrets should return 403")
+		assert.Equal(t, "ERR_PERMISSION_DENIED", resp.ErrorBody["errorCode"])
+	})
+}
+
+func TestV1SecretCacheEndpoints(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{
+		secretStores: map[string]secretstores.SecretStore{
+			"store1": daprt.FakeSecretStore{},
+			"cached": secretstores_loader.NewCachingSecretStore("cached", daprt.FakeSecretStore{}, time.Minute),
+		},
+		json: jsoniter.ConfigFastest,
+	}
+	fakeServer.StartServer(testAPI.constructSecretEndpoints())
+
+	t.Run("Invalidate secret cache - 204", func(t *testing.T) {
+		for _, apiPath := range []string{
+			"v1.0-alpha1/secrets/cached/cache/invalidate",
+			"v1.0-alpha1/secrets/cached/cache/invalidate?key=good-key",
+		} {
+			resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+			assert.Equal(t, 204, resp.StatusCode, apiPath)
+		}
+	})
+
+	t.Run("Invalidate secret cache - 400 cache not enabled", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/secrets/store1/cache/invalidate", nil, nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_SECRET_CACHE_NOT_ENABLED", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Invalidate secret cache - 401 secret store not found", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/secrets/notexist/cache/invalidate", nil, nil)
+		assert.Equal(t, 401, resp.StatusCode)
+	})
+}
+
+func TestV1CryptoEndpoints(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{
+		secretStores: map[string]secretstores.SecretStore{
+			"store1":  daprt.FakeSecretStore{},
+			"transit": daprt.FakeTransitSecretStore{},
+		},
+		secretsConfiguration: map[string]config.SecretsScope{
+			"transit": {
+				DefaultAccess: config.AllowAccess,
+				DeniedSecrets: []string{"not-allowed"},
+			},
+		},
+		json: jsoniter.ConfigFastest,
+	}
+	fakeServer.StartServer(testAPI.constructSecretEndpoints())
+
+	do := func(operation string, req CryptoRequest) (CryptoResponse, fakeHTTPResponse) {
+		body, _ := json.Marshal(req)
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/crypto/transit/"+operation, body, nil)
+		var cryptoResp CryptoResponse
+		if resp.StatusCode == 200 {
+			assert.NoError(t, json.Unmarshal(resp.RawBody, &cryptoResp))
+		}
+		return cryptoResp, resp
+	}
+
+	t.Run("Encrypt and decrypt - 200", func(t *testing.T) {
+		encrypted, resp := do("encrypt", CryptoRequest{KeyName: "key1", Data: []byte("plaintext")})
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, "enc:plaintext", string(encrypted.Data))
+
+		decrypted, resp := do("decrypt", CryptoRequest{KeyName: "key1", Data: encrypted.Data})
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, "plaintext", string(decrypted.Data))
+	})
+
+	t.Run("Sign and verify - 200", func(t *testing.T) {
+		signed, resp := do("sign", CryptoRequest{KeyName: "key1", Data: []byte("message")})
+		assert.Equal(t, 200, resp.StatusCode)
+
+		verified, resp := do("verify", CryptoRequest{KeyName: "key1", Data: []byte("message"), Signature: signed.Data})
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.True(t, verified.Valid)
+
+		verified, _ = do("verify", CryptoRequest{KeyName: "key1", Data: []byte("forged"), Signature: signed.Data})
+		assert.False(t, verified.Valid)
+	})
+
+	t.Run("Crypto - 400 key name missing", func(t *testing.T) {
+		_, resp := do("encrypt", CryptoRequest{Data: []byte("plaintext")})
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_MALFORMED_REQUEST", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Crypto - 400 not supported", func(t *testing.T) {
+		body, _ := json.Marshal(CryptoRequest{KeyName: "key1"})
+		resp := fakeServer.DoRequest("POST", "v1.0-alpha1/crypto/store1/encrypt", body, nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_CRYPTO_NOT_SUPPORTED", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Crypto - 403 key not allowed", func(t *testing.T) {
+		_, resp := do("encrypt", CryptoRequest{KeyName: "not-allowed", Data: []byte("plaintext")})
+		assert.Equal(t, 403, resp.StatusCode)
+		assert.Equal(t, "ERR_PERMISSION_DENIED", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Crypto - 500 operation failed", func(t *testing.T) {
+		_, resp := do("encrypt", CryptoRequest{KeyName: "error-key", Data: []byte("plaintext")})
+		assert.Equal(t, 500, resp.StatusCode)
+		assert.Equal(t, "ERR_CRYPTO_OPERATION", resp.ErrorBody["errorCode"])
+	})
+}
+
+func TestV1SecretSubscribeEndpoints(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{
+		secretStores: map[string]secretstores.SecretStore{
+			"store1": daprt.FakeSecretStore{},
+		},
+		secretsConfiguration: map[string]config.SecretsScope{
+			"store1": {
+				DefaultAccess: config.AllowAccess,
+				DeniedSecrets: []string{"not-allowed"},
+			},
+		},
+		json: jsoniter.ConfigFastest,
+	}
+	fakeServer.StartServer(testAPI.constructSecretEndpoints())
+	apiPath := "v1.0-alpha1/secrets/store1/subscribe"
+
+	t.Run("Subscribe secrets - 204", func(t *testing.T) {
+		body, _ := json.Marshal(SubscribeSecretsRequest{Keys: []string{"good-key"}, Method: "rotated"})
+		resp := fakeServer.DoRequest("POST", apiPath, body, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		assert.Len(t, testAPI.secretSubscriptions, 1)
+
+		// subscribing again replaces the subscription.
+		resp = fakeServer.DoRequest("PUT", apiPath, body, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		assert.Len(t, testAPI.secretSubscriptions, 1)
+	})
+
+	t.Run("Subscribe secrets - 400 no method", func(t *testing.T) {
+		body, _ := json.Marshal(SubscribeSecretsRequest{Keys: []string{"good-key"}})
+		resp := fakeServer.DoRequest("POST", apiPath, body, nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_MALFORMED_REQUEST", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Subscribe secrets - 403 permission denied", func(t *testing.T) {
+		body, _ := json.Marshal(SubscribeSecretsRequest{Keys: []string{"good-key", "not-allowed"}, Method: "rotated"})
+		resp := fakeServer.DoRequest("POST", apiPath, body, nil)
+		assert.Equal(t, 403, resp.StatusCode)
+		assert.Equal(t, "ERR_PERMISSION_DENIED", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Unsubscribe secrets - 204", func(t *testing.T) {
+		resp := fakeServer.DoRequest("DELETE", apiPath, nil, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		assert.Len(t, testAPI.secretSubscriptions, 0)
+	})
 }
 
 func TestV1HealthzEndpoint(t *testing.T) {
@@ -2943,6 +3776,62 @@ func TestV1HealthzEndpoint(t *testing.T) {
 	fakeServer.Shutdown()
 }
 
+func TestV1DetailedHealthzEndpoint(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	details := health.Details{
+		Components: []health.ComponentDetails{
+			{Name: "statestore", Type: "state.redis", Status: health.StatusOK},
+		},
+		AppChannel: &health.SubsystemDetails{Status: health.StatusOK},
+	}
+
+	testAPI := &api{
+		getHealthDetailsFn: func() health.Details {
+			return details
+		},
+		json: jsoniter.ConfigFastest,
+	}
+
+	fakeServer.StartServer(testAPI.constructDetailedHealthzEndpoints())
+	apiPath := "v1.0/healthz/detailed"
+
+	t.Run("Detailed healthz - 500 pending until ready", func(t *testing.T) {
+		resp := fakeServer.DoRequest("GET", apiPath, nil, nil)
+
+		assert.Equal(t, 500, resp.StatusCode)
+		var got health.Details
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &got))
+		assert.Equal(t, health.StatusPending, got.Status)
+	})
+
+	t.Run("Detailed healthz - 200 OK", func(t *testing.T) {
+		testAPI.MarkStatusAsReady()
+		resp := fakeServer.DoRequest("GET", apiPath, nil, nil)
+
+		assert.Equal(t, 200, resp.StatusCode)
+		var got health.Details
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &got))
+		assert.Equal(t, health.StatusOK, got.Status)
+		assert.Equal(t, details.Components, got.Components)
+		assert.Nil(t, got.Placement)
+	})
+
+	t.Run("Detailed healthz - 500 with failed component", func(t *testing.T) {
+		details.Components = append(details.Components, health.ComponentDetails{
+			Name: "pubsub", Type: "pubsub.kafka", Status: health.StatusFailed, Error: "init timeout",
+		})
+		resp := fakeServer.DoRequest("GET", apiPath, nil, nil)
+
+		assert.Equal(t, 500, resp.StatusCode)
+		var got health.Details
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &got))
+		assert.Equal(t, health.StatusFailed, got.Status)
+		assert.Equal(t, "init timeout", got.Components[1].Error)
+	})
+
+	fakeServer.Shutdown()
+}
+
 func TestV1TransactionEndpoints(t *testing.T) {
 	fakeServer := newFakeHTTPServer()
 	var fakeStore state.Store = fakeStateStoreQuerier{}
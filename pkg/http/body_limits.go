@@ -0,0 +1,178 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/dapr/pkg/messages"
+)
+
+const (
+	bodyLimitInvocation = "invocation"
+	bodyLimitPublish    = "publish"
+	bodyLimitState      = "state"
+	bodyLimitDefault    = "HTTP"
+)
+
+// requestBodyLimits holds the maximum size in bytes of the request bodies of each API.
+// The APIs without a limit of their own use the limit of the server.
+type requestBodyLimits struct {
+	defaultSize int
+	sizes       map[string]int
+}
+
+// newRequestBodyLimits returns the limits of the request bodies from the sizes in MB of the server and of each API.
+func newRequestBodyLimits(defaultSize int, spec config.APIHTTPMaxRequestBodySizeSpec) *requestBodyLimits {
+	l := &requestBodyLimits{
+		defaultSize: defaultSize * 1024 * 1024,
+		sizes:       map[string]int{},
+	}
+	for api, size := range map[string]int{
+		bodyLimitInvocation: spec.Invocation,
+		bodyLimitPublish:    spec.Publish,
+		bodyLimitState:      spec.State,
+	} {
+		if size > 0 {
+			l.sizes[api] = size * 1024 * 1024
+		}
+	}
+	return l
+}
+
+// apiOf returns the API targeted by a request from its path, or from the dapr-app-id header of the invocations
+// routed to the app by header.
+func apiOf(header *fasthttp.RequestHeader) string {
+	// The path is /<version>/<api>/..., decoded and normalized as the router matches it, so that encoded or
+	// dot segments and absolute request URIs don't get past the limit of the API.
+	uri := fasthttp.AcquireURI()
+	defer fasthttp.ReleaseURI(uri)
+	var parts [][]byte
+	if err := uri.Parse(header.Host(), header.RequestURI()); err == nil {
+		parts = bytes.SplitN(bytes.TrimPrefix(uri.Path(), []byte("/")), []byte("/"), 3)
+	}
+	if len(parts) >= 2 {
+		switch string(parts[1]) {
+		case "invoke":
+			return bodyLimitInvocation
+		case "publish":
+			return bodyLimitPublish
+		case "state":
+			return bodyLimitState
+		}
+	}
+	if header.Peek(daprAppID) != nil {
+		return bodyLimitInvocation
+	}
+	return bodyLimitDefault
+}
+
+// limitOf returns the API targeted by a request and the limit of its body, and whether the API has a limit of its own.
+func (l *requestBodyLimits) limitOf(header *fasthttp.RequestHeader) (string, int, bool) {
+	api := apiOf(header)
+	if size, ok := l.sizes[api]; ok {
+		return api, size, true
+	}
+	return api, l.defaultSize, false
+}
+
+// headerReceived overrides the limit of the server with the limit of the API targeted by the request, so that
+// oversized bodies are rejected as soon as they exceed it instead of being buffered.
+func (l *requestBodyLimits) headerReceived(header *fasthttp.RequestHeader) fasthttp.RequestConfig {
+	if _, size, ok := l.limitOf(header); ok {
+		return fasthttp.RequestConfig{MaxRequestBodySize: size}
+	}
+	return fasthttp.RequestConfig{}
+}
+
+// errorHandler responds to the requests that could not be read by the server, with a structured 413 for the bodies
+// exceeding their limit and with the default responses of the server otherwise.
+func (l *requestBodyLimits) errorHandler(ctx *fasthttp.RequestCtx, err error) {
+	var netErr *net.OpError
+	switch {
+	case errors.Is(err, fasthttp.ErrBodyTooLarge):
+		l.respondTooLarge(ctx)
+	case errors.As(err, new(*fasthttp.ErrSmallBuffer)):
+		ctx.Error("Too big request header", fasthttp.StatusRequestHeaderFieldsTooLarge)
+	case errors.As(err, &netErr) && netErr.Timeout():
+		ctx.Error("Request timeout", fasthttp.StatusRequestTimeout)
+	default:
+		ctx.Error("Error when parsing request", fasthttp.StatusBadRequest)
+	}
+}
+
+func (l *requestBodyLimits) respondTooLarge(ctx *fasthttp.RequestCtx) {
+	api, size, _ := l.limitOf(&ctx.Request.Header)
+	msg := NewErrorResponse("ERR_REQUEST_BODY_TOO_LARGE", fmt.Sprintf(messages.ErrRequestBodyTooLarge, size, api))
+	respond(ctx, withError(fasthttp.StatusRequestEntityTooLarge, msg))
+	log.Debug(msg)
+}
+
+// useRequestBodyLimits enforces the limits of the APIs on the streamed request bodies, which the server does not
+// reject when they exceed its limit. Bodies of a known length are rejected before being read, and chunked bodies as
+// soon as they exceed the limit.
+func (s *server) useRequestBodyLimits(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	if !s.config.StreamRequestBody || len(s.bodyLimits.sizes) == 0 {
+		return next
+	}
+
+	return func(ctx *fasthttp.RequestCtx) {
+		_, size, ok := s.bodyLimits.limitOf(&ctx.Request.Header)
+		if !ok {
+			next(ctx)
+			return
+		}
+
+		length := ctx.Request.Header.ContentLength()
+		if length > size {
+			s.bodyLimits.respondTooLarge(ctx)
+			return
+		}
+		if length < 0 && ctx.Request.IsBodyStream() {
+			body := &limitedBuffer{limit: size}
+			if err := ctx.Request.BodyWriteTo(body); err != nil {
+				if errors.Is(err, fasthttp.ErrBodyTooLarge) {
+					s.bodyLimits.respondTooLarge(ctx)
+				} else {
+					msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+					respond(ctx, withError(fasthttp.StatusBadRequest, msg))
+					log.Debug(msg)
+				}
+				return
+			}
+			ctx.Request.SetBody(body.Bytes())
+		}
+
+		next(ctx)
+	}
+}
+
+// limitedBuffer is a buffer failing with fasthttp.ErrBodyTooLarge when more than limit bytes are written to it.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		return 0, fasthttp.ErrBodyTooLarge
+	}
+	return b.Buffer.Write(p)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func TestRequestBodyLimitOf(t *testing.T) {
+	l := newRequestBodyLimits(4, config.APIHTTPMaxRequestBodySizeSpec{Invocation: 16, Publish: 1})
+
+	tests := []struct {
+		uri      string
+		appID    string
+		api      string
+		size     int
+		override bool
+	}{
+		{"/v1.0/invoke/app/method/upload", "", bodyLimitInvocation, 16 << 20, true},
+		{"/v1.0/publish/pubsub/topic?metadata.ttlInSeconds=10", "", bodyLimitPublish, 1 << 20, true},
+		{"/v1.0/state/store", "", bodyLimitState, 4 << 20, false},
+		{"/v1.0/bindings/binding", "", bodyLimitDefault, 4 << 20, false},
+		{"/upload", "app", bodyLimitInvocation, 16 << 20, true},
+		{"/v1.0/%70ublish/pubsub/topic", "", bodyLimitPublish, 1 << 20, true},
+		{"/v1.0//publish/pubsub/topic", "", bodyLimitPublish, 1 << 20, true},
+		{"/v1.0/bindings/../publish/pubsub/topic", "", bodyLimitPublish, 1 << 20, true},
+		{"http://localhost/v1.0/invoke/app/method/upload", "", bodyLimitInvocation, 16 << 20, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			var header fasthttp.RequestHeader
+			header.SetHost("localhost")
+			header.SetRequestURI(tt.uri)
+			if tt.appID != "" {
+				header.Set(daprAppID, tt.appID)
+			}
+
+			api, size, ok := l.limitOf(&header)
+			assert.Equal(t, tt.api, api)
+			assert.Equal(t, tt.size, size)
+			assert.Equal(t, tt.override, ok)
+
+			if tt.override {
+				assert.Equal(t, tt.size, l.headerReceived(&header).MaxRequestBodySize)
+			} else {
+				assert.Zero(t, l.headerReceived(&header).MaxRequestBodySize)
+			}
+		})
+	}
+}
+
+func TestRequestBodyLimitErrorHandler(t *testing.T) {
+	l := newRequestBodyLimits(4, config.APIHTTPMaxRequestBodySizeSpec{Publish: 1})
+
+	t.Run("body too large - 413", func(t *testing.T) {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.SetRequestURI("/v1.0/publish/pubsub/topic")
+		l.errorHandler(ctx, fasthttp.ErrBodyTooLarge)
+
+		assert.Equal(t, fasthttp.StatusRequestEntityTooLarge, ctx.Response.StatusCode())
+		var resp ErrorResponse
+		require.NoError(t, json.Unmarshal(ctx.Response.Body(), &resp))
+		assert.Equal(t, "ERR_REQUEST_BODY_TOO_LARGE", resp.ErrorCode)
+		assert.Contains(t, resp.Message, "1048576 bytes of the publish API")
+	})
+
+	t.Run("other errors - 400", func(t *testing.T) {
+		ctx := &fasthttp.RequestCtx{}
+		l.errorHandler(ctx, io.ErrUnexpectedEOF)
+
+		assert.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode())
+	})
+}
+
+func TestUseRequestBodyLimits(t *testing.T) {
+	s := &server{
+		config:     ServerConfig{StreamRequestBody: true},
+		bodyLimits: newRequestBodyLimits(4, config.APIHTTPMaxRequestBodySizeSpec{Publish: 1}),
+	}
+	var received []byte
+	handler := s.useRequestBodyLimits(func(ctx *fasthttp.RequestCtx) {
+		received = append([]byte(nil), ctx.PostBody()...)
+	})
+
+	newRequest := func(uri string, body []byte, length int) *fasthttp.RequestCtx {
+		received = nil
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.SetRequestURI(uri)
+		ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+		ctx.Request.SetBodyStream(bytes.NewReader(body), length)
+		return ctx
+	}
+	large := bytes.Repeat([]byte("a"), 2<<20)
+
+	t.Run("known length over the limit - 413", func(t *testing.T) {
+		ctx := newRequest("/v1.0/publish/pubsub/topic", large, len(large))
+		handler(ctx)
+
+		assert.Equal(t, fasthttp.StatusRequestEntityTooLarge, ctx.Response.StatusCode())
+		assert.Nil(t, received)
+	})
+
+	t.Run("chunked body over the limit - 413", func(t *testing.T) {
+		ctx := newRequest("/v1.0/publish/pubsub/topic", large, -1)
+		handler(ctx)
+
+		assert.Equal(t, fasthttp.StatusRequestEntityTooLarge, ctx.Response.StatusCode())
+		assert.Nil(t, received)
+	})
+
+	t.Run("chunked body under the limit", func(t *testing.T) {
+		ctx := newRequest("/v1.0/publish/pubsub/topic", []byte("hello"), -1)
+		handler(ctx)
+
+		assert.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+		assert.Equal(t, []byte("hello"), received)
+	})
+
+	t.Run("API without a limit of its own", func(t *testing.T) {
+		ctx := newRequest("/v1.0/state/store", large, -1)
+		handler(ctx)
+
+		assert.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+		assert.Len(t, received, len(large))
+	})
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+const (
+	encodingZstd   = "zstd"
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+
+	// defaultCompressionMinSize is the size in bytes under which responses are sent uncompressed by default.
+	defaultCompressionMinSize = 1024
+	// compressionUserValueKey is the key of the compression negotiated for a request in its user values.
+	compressionUserValueKey = "daprCompression"
+)
+
+// responseCompression compresses the responses of the HTTP API with the encoding negotiated with the
+// Accept-Encoding header of the request.
+// A nil responseCompression compresses nothing.
+type responseCompression struct {
+	algorithms []string
+	minSize    int
+	zstd       *zstd.Encoder
+}
+
+// negotiatedCompression is the compression negotiated for a request, stored in its user values so that the
+// handlers streaming their response can compress it as it is written.
+type negotiatedCompression struct {
+	encoding string
+	minSize  int
+}
+
+func newResponseCompression(spec config.APIHTTPCompressionSpec) (*responseCompression, error) {
+	if len(spec.Algorithms) == 0 {
+		return nil, nil
+	}
+
+	c := &responseCompression{
+		minSize: spec.MinSize,
+	}
+	if c.minSize <= 0 {
+		c.minSize = defaultCompressionMinSize
+	}
+	for _, a := range spec.Algorithms {
+		a = strings.ToLower(strings.TrimSpace(a))
+		switch a {
+		case encodingZstd:
+			encoder, err := zstd.NewWriter(nil)
+			if err != nil {
+				return nil, err
+			}
+			c.zstd = encoder
+		case encodingBrotli, encodingGzip:
+		default:
+			return nil, errors.Errorf("unsupported http compression algorithm %q, must be one of zstd, br or gzip", a)
+		}
+		c.algorithms = append(c.algorithms, a)
+	}
+	return c, nil
+}
+
+// useCompression compresses the responses of the HTTP API when the client accepts one of the configured encodings.
+func (s *server) useCompression(next fasthttp.RequestHandler) (fasthttp.RequestHandler, error) {
+	c, err := newResponseCompression(s.apiSpec.HTTP.Compression)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return next, nil
+	}
+	log.Infof("enabled compression http middleware with algorithms %s", strings.Join(c.algorithms, ", "))
+
+	return func(ctx *fasthttp.RequestCtx) {
+		encoding := c.negotiate(string(ctx.Request.Header.Peek(fasthttp.HeaderAcceptEncoding)))
+		if encoding != "" {
+			ctx.SetUserValue(compressionUserValueKey, &negotiatedCompression{encoding: encoding, minSize: c.minSize})
+		}
+
+		next(ctx)
+
+		ctx.Response.Header.Add(fasthttp.HeaderVary, fasthttp.HeaderAcceptEncoding)
+		// Streamed bodies are compressed by the handlers as they are written, see setBodyStream.
+		if encoding == "" || ctx.Response.IsBodyStream() || ctx.IsHead() {
+			return
+		}
+		c.compressBody(&ctx.Response, encoding)
+	}, nil
+}
+
+// negotiate returns the encoding of the response from the Accept-Encoding header of the request, or an empty
+// string to send the response uncompressed.
+// The encoding with the highest quality value is chosen, and the order of the configured algorithms breaks ties.
+func (c *responseCompression) negotiate(acceptEncoding string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	qualities := map[string]float64{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, q := parseAcceptEncoding(part)
+		if name != "" {
+			qualities[name] = q
+		}
+	}
+
+	best, bestQ := "", 0.0
+	for _, a := range c.algorithms {
+		q, ok := qualities[a]
+		if !ok {
+			q, ok = qualities["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = a, q
+		}
+	}
+	return best
+}
+
+// parseAcceptEncoding returns the lower cased coding and the quality value of an element of Accept-Encoding.
+func parseAcceptEncoding(part string) (string, float64) {
+	params := strings.Split(part, ";")
+	name := strings.ToLower(strings.TrimSpace(params[0]))
+	q := 1.0
+	for _, p := range params[1:] {
+		p = strings.TrimSpace(p)
+		if !strings.HasPrefix(p, "q=") {
+			continue
+		}
+		v, err := strconv.ParseFloat(p[2:], 64)
+		if err != nil {
+			return "", 0
+		}
+		q = v
+	}
+	return name, q
+}
+
+// compressBody replaces the body of the response with its compressed form, unless it is too small, already
+// encoded or does not shrink.
+func (c *responseCompression) compressBody(resp *fasthttp.Response, encoding string) {
+	body := resp.Body()
+	if len(body) < c.minSize || len(resp.Header.Peek(fasthttp.HeaderContentEncoding)) > 0 {
+		return
+	}
+	switch resp.StatusCode() {
+	case fasthttp.StatusNoContent, fasthttp.StatusNotModified:
+		return
+	}
+
+	var compressed []byte
+	switch encoding {
+	case encodingZstd:
+		compressed = c.zstd.EncodeAll(body, nil)
+	case encodingBrotli:
+		compressed = fasthttp.AppendBrotliBytes(nil, body)
+	case encodingGzip:
+		compressed = fasthttp.AppendGzipBytes(nil, body)
+	}
+	if len(compressed) == 0 || len(compressed) >= len(body) {
+		return
+	}
+
+	resp.SetBodyRaw(compressed)
+	resp.Header.Set(fasthttp.HeaderContentEncoding, encoding)
+}
+
+// setBodyStream sets the body of the response to the content of r, of the given size or -1 if unknown.
+// The body is compressed as it is written, with chunked transfer encoding, when a compression was negotiated
+// for the request and the size is not known to be under the minimum size. r is closed once written if it is
+// an io.Closer.
+func setBodyStream(ctx *fasthttp.RequestCtx, r io.Reader, size int) {
+	n, _ := ctx.UserValue(compressionUserValueKey).(*negotiatedCompression)
+	if n == nil || (size >= 0 && size < n.minSize) {
+		// fasthttp closes the stream once the body is written.
+		ctx.Response.SetBodyStream(r, size)
+		return
+	}
+
+	ctx.Response.Header.Set(fasthttp.HeaderContentEncoding, n.encoding)
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		if closer, ok := r.(io.Closer); ok {
+			defer closer.Close()
+		}
+		encoder, err := newStreamEncoder(w, n.encoding)
+		if err != nil {
+			log.Debugf("failed to create the %s encoder of the response: %s", n.encoding, err)
+			return
+		}
+		if _, err = io.Copy(encoder, r); err != nil {
+			log.Debugf("failed to write the compressed response: %s", err)
+		}
+		encoder.Close()
+	})
+}
+
+func newStreamEncoder(w io.Writer, encoding string) (io.WriteCloser, error) {
+	switch encoding {
+	case encodingZstd:
+		return zstd.NewWriter(w)
+	case encodingBrotli:
+		return brotli.NewWriter(w), nil
+	case encodingGzip:
+		return gzip.NewWriter(w), nil
+	}
+	return nil, errors.Errorf("unsupported encoding %s", encoding)
+}
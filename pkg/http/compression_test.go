@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func TestNewResponseCompression(t *testing.T) {
+	t.Run("disabled without algorithms", func(t *testing.T) {
+		c, err := newResponseCompression(config.APIHTTPCompressionSpec{})
+		assert.NoError(t, err)
+		assert.Nil(t, c)
+	})
+
+	t.Run("default minimum size", func(t *testing.T) {
+		c, err := newResponseCompression(config.APIHTTPCompressionSpec{Algorithms: []string{"GZIP"}})
+		require.NoError(t, err)
+		assert.Equal(t, defaultCompressionMinSize, c.minSize)
+		assert.Equal(t, []string{encodingGzip}, c.algorithms)
+	})
+
+	t.Run("unsupported algorithm", func(t *testing.T) {
+		_, err := newResponseCompression(config.APIHTTPCompressionSpec{Algorithms: []string{"deflate"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestNegotiateCompression(t *testing.T) {
+	c, err := newResponseCompression(config.APIHTTPCompressionSpec{
+		Algorithms: []string{encodingZstd, encodingBrotli, encodingGzip},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		acceptEncoding string
+		expected       string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", encodingGzip},
+		{"gzip, br", encodingBrotli},
+		{"gzip, br, zstd", encodingZstd},
+		{"gzip;q=1.0, zstd;q=0.5", encodingGzip},
+		{"zstd;q=0, gzip", encodingGzip},
+		{"*", encodingZstd},
+		{"*;q=0.1, br;q=0.8", encodingBrotli},
+		{"GZip", encodingGzip},
+		{"gzip;q=invalid", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			assert.Equal(t, tt.expected, c.negotiate(tt.acceptEncoding))
+		})
+	}
+}
+
+func TestCompressionMiddleware(t *testing.T) {
+	large := []byte(strings.Repeat("dapr state value ", 200))
+	body := large
+	handler := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetContentType(jsonContentTypeHeader)
+		ctx.SetBody(body)
+	}
+
+	s := &server{
+		apiSpec: config.APISpec{
+			HTTP: config.APIHTTPSpec{
+				Compression: config.APIHTTPCompressionSpec{
+					Algorithms: []string{encodingZstd, encodingBrotli, encodingGzip},
+				},
+			},
+		},
+	}
+	h, err := s.useCompression(handler)
+	require.NoError(t, err)
+
+	call := func(acceptEncoding string) *fasthttp.RequestCtx {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod(fasthttp.MethodGet)
+		if acceptEncoding != "" {
+			ctx.Request.Header.Set(fasthttp.HeaderAcceptEncoding, acceptEncoding)
+		}
+		h(ctx)
+		return ctx
+	}
+
+	t.Run("gzip", func(t *testing.T) {
+		body = large
+		ctx := call("gzip")
+		assert.Equal(t, encodingGzip, string(ctx.Response.Header.Peek(fasthttp.HeaderContentEncoding)))
+		assert.Equal(t, fasthttp.HeaderAcceptEncoding, string(ctx.Response.Header.Peek(fasthttp.HeaderVary)))
+		decoded, err := fasthttp.AppendGunzipBytes(nil, ctx.Response.Body())
+		require.NoError(t, err)
+		assert.Equal(t, large, decoded)
+	})
+
+	t.Run("br", func(t *testing.T) {
+		body = large
+		ctx := call("br")
+		assert.Equal(t, encodingBrotli, string(ctx.Response.Header.Peek(fasthttp.HeaderContentEncoding)))
+		decoded, err := fasthttp.AppendUnbrotliBytes(nil, ctx.Response.Body())
+		require.NoError(t, err)
+		assert.Equal(t, large, decoded)
+	})
+
+	t.Run("zstd", func(t *testing.T) {
+		body = large
+		ctx := call("gzip, zstd")
+		assert.Equal(t, encodingZstd, string(ctx.Response.Header.Peek(fasthttp.HeaderContentEncoding)))
+		decoder, err := zstd.NewReader(nil)
+		require.NoError(t, err)
+		defer decoder.Close()
+		decoded, err := decoder.DecodeAll(ctx.Response.Body(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, large, decoded)
+	})
+
+	t.Run("not accepted", func(t *testing.T) {
+		body = large
+		ctx := call("")
+		assert.Empty(t, ctx.Response.Header.Peek(fasthttp.HeaderContentEncoding))
+		assert.Equal(t, large, ctx.Response.Body())
+	})
+
+	t.Run("under the minimum size", func(t *testing.T) {
+		body = []byte(`{"key":"value"}`)
+		ctx := call("gzip")
+		assert.Empty(t, ctx.Response.Header.Peek(fasthttp.HeaderContentEncoding))
+		assert.Equal(t, body, ctx.Response.Body())
+	})
+}
+
+func TestSetBodyStreamCompression(t *testing.T) {
+	large := []byte(strings.Repeat("dapr binding data ", 200))
+
+	t.Run("compressed when negotiated", func(t *testing.T) {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.SetUserValue(compressionUserValueKey, &negotiatedCompression{encoding: encodingGzip, minSize: 1024})
+		setBodyStream(ctx, ioutil.NopCloser(bytes.NewReader(large)), -1)
+
+		assert.Equal(t, encodingGzip, string(ctx.Response.Header.Peek(fasthttp.HeaderContentEncoding)))
+		decoded, err := fasthttp.AppendGunzipBytes(nil, ctx.Response.Body())
+		require.NoError(t, err)
+		assert.Equal(t, large, decoded)
+	})
+
+	t.Run("uncompressed under the minimum size", func(t *testing.T) {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.SetUserValue(compressionUserValueKey, &negotiatedCompression{encoding: encodingGzip, minSize: 1024})
+		setBodyStream(ctx, bytes.NewReader([]byte("small")), 5)
+
+		assert.Empty(t, ctx.Response.Header.Peek(fasthttp.HeaderContentEncoding))
+		assert.Equal(t, []byte("small"), ctx.Response.Body())
+	})
+
+	t.Run("uncompressed without negotiation", func(t *testing.T) {
+		ctx := &fasthttp.RequestCtx{}
+		setBodyStream(ctx, bytes.NewReader(large), len(large))
+
+		assert.Empty(t, ctx.Response.Header.Peek(fasthttp.HeaderContentEncoding))
+		assert.Equal(t, large, ctx.Response.Body())
+	})
+}
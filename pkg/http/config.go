@@ -13,6 +13,8 @@ limitations under the License.
 
 package http
 
+import "crypto/tls"
+
 // ServerConfig holds config values for an HTTP server.
 type ServerConfig struct {
 	AllowedOrigins     string
@@ -23,6 +25,7 @@ type ServerConfig struct {
 	PublicPort         *int
 	ProfilePort        int
 	EnableProfiling    bool
+	ProfileTLSConfig   *tls.Config
 	MaxRequestBodySize int
 	UnixDomainSocket   string
 	ReadBufferSize     int
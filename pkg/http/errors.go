@@ -13,10 +13,19 @@ limitations under the License.
 
 package http
 
+import (
+	"github.com/valyala/fasthttp"
+
+	"github.com/dapr/dapr/pkg/messages"
+)
+
 // ErrorResponse is an HTTP response message sent back to calling clients by the Dapr Runtime HTTP API.
+// The errors with a code of the catalog of pkg/messages tell whether they are retriable and link to their documentation.
 type ErrorResponse struct {
 	ErrorCode string `json:"errorCode"`
 	Message   string `json:"message"`
+	Retriable bool   `json:"retriable,omitempty"`
+	DocsURL   string `json:"docsUrl,omitempty"`
 }
 
 // NewErrorResponse returns a new ErrorResponse.
@@ -26,3 +35,15 @@ func NewErrorResponse(errorCode, message string) ErrorResponse {
 		Message:   message,
 	}
 }
+
+// withReason sets the details of the reason of the catalog matching the error code, for an error responded with
+// the given HTTP status code. The errors caused by the request itself are never retriable.
+func (e ErrorResponse) withReason(statusCode int) ErrorResponse {
+	r, ok := messages.ReasonByCode(e.ErrorCode)
+	if !ok {
+		return e
+	}
+	e.Retriable = r.Retriable && (statusCode >= fasthttp.StatusInternalServerError || statusCode == fasthttp.StatusTooManyRequests)
+	e.DocsURL = r.DocsURL()
+	return e
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"fmt"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dapr/dapr/pkg/audit"
+	"github.com/dapr/dapr/pkg/policy"
+	auth "github.com/dapr/dapr/pkg/runtime/security"
+)
+
+// subjectUserValueKey is the key of the user value holding the subject of the bearer token of a request.
+const subjectUserValueKey = "dapr.oidc.subject"
+
+// oidcSubject is the type of the subject user value, so that it isn't unescaped like the request parameters.
+type oidcSubject string
+
+var (
+	// policyComponentParams are the route parameters holding the component called by a request, by priority.
+	policyComponentParams = []string{storeNameParam, secretStoreNameParam, pubsubnameparam, actorTypeParam, idParam, nameParam}
+	// policyResourceParams are the route parameters holding the resource of the component, by priority.
+	policyResourceParams = []string{actorIDParam, topicParam, stateKeyParam, methodParam}
+
+	// httpOperations maps the endpoints to their policy operation, by method and route, or by route for all the
+	// methods of the endpoint. The operation of the other endpoints is the lower case method of the request.
+	httpOperations = map[string]httpOperation{
+		"GET state/{storeName}/{key}":                          {name: policy.OperationGet},
+		"DELETE state/{storeName}/{key}":                       {name: policy.OperationDelete},
+		"PUT state/{storeName}/{key}":                          {name: policy.OperationSave},
+		"state/{storeName}":                                    {name: policy.OperationSave, keys: saveStateKeys},
+		"state/{storeName}/bulk":                               {name: policy.OperationBulkGet, keys: bulkGetStateKeys},
+		"state/{storeName}/transaction":                        {name: policy.OperationTransaction, keys: stateTransactionKeys},
+		"state/{storeName}/query":                              {name: policy.OperationQuery},
+		"secrets/{secretStoreName}/bulk":                       {name: policy.OperationBulkGet},
+		"secrets/{secretStoreName}/{key}":                      {name: policy.OperationGet},
+		"DELETE secrets/{secretStoreName}/subscribe":           {name: policy.OperationUnsubscribe},
+		"secrets/{secretStoreName}/subscribe":                  {name: policy.OperationSubscribe},
+		"crypto/{secretStoreName}/encrypt":                     {name: "encrypt"},
+		"crypto/{secretStoreName}/decrypt":                     {name: "decrypt"},
+		"crypto/{secretStoreName}/sign":                        {name: "sign"},
+		"crypto/{secretStoreName}/verify":                      {name: "verify"},
+		"publish/{pubsubname}/{topic:*}":                       {name: policy.OperationPublish},
+		"bindings/{name}":                                      {name: policy.OperationInvoke},
+		"bindings/{name}/bulk":                                 {name: policy.OperationBulkInvoke},
+		"invoke/{id}/method/{method:*}":                        {name: policy.OperationInvoke},
+		"actors/{actorType}/{actorId}/state":                   {name: policy.OperationTransaction, keys: actorStateTransactionKeys},
+		"actors/{actorType}/{actorId}/state/{key}":             {name: policy.OperationGet},
+		"actors/{actorType}/{actorId}/method/{method}":         {name: policy.OperationInvoke},
+		"DELETE actors/{actorType}/{actorId}/reminders/{name}": {name: "unregisterActorReminder"},
+		"PATCH actors/{actorType}/{actorId}/reminders/{name}":  {name: "renameActorReminder"},
+		"GET actors/{actorType}/{actorId}/reminders/{name}":    {name: "getActorReminder"},
+		"actors/{actorType}/{actorId}/reminders/{name}":        {name: "registerActorReminder"},
+		"DELETE actors/{actorType}/{actorId}/timers/{name}":    {name: "unregisterActorTimer"},
+		"actors/{actorType}/{actorId}/timers/{name}":           {name: "registerActorTimer"},
+		"metadata/{key}":                                       {name: policy.OperationSave},
+	}
+)
+
+// httpOperation is the policy operation of an endpoint.
+type httpOperation struct {
+	name string
+	// keys returns the keys of the body of a bulk or a transaction request, on which the request is authorized.
+	keys func(body []byte) ([]string, error)
+}
+
+// policyTransactionOperation holds the key of an operation of a state transaction.
+type policyTransactionOperation struct {
+	Request struct {
+		Key string `json:"key"`
+	} `json:"request"`
+}
+
+func saveStateKeys(body []byte) ([]string, error) {
+	var reqs []struct {
+		Key string `json:"key"`
+	}
+	if err := jsoniter.ConfigFastest.Unmarshal(body, &reqs); err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(reqs))
+	for i, r := range reqs {
+		keys[i] = r.Key
+	}
+	return keys, nil
+}
+
+func bulkGetStateKeys(body []byte) ([]string, error) {
+	var req struct {
+		Keys []string `json:"keys"`
+	}
+	err := jsoniter.ConfigFastest.Unmarshal(body, &req)
+	return req.Keys, err
+}
+
+func stateTransactionKeys(body []byte) ([]string, error) {
+	var req struct {
+		Operations []policyTransactionOperation `json:"operations"`
+	}
+	if err := jsoniter.ConfigFastest.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	return transactionKeys(req.Operations), nil
+}
+
+func actorStateTransactionKeys(body []byte) ([]string, error) {
+	var ops []policyTransactionOperation
+	if err := jsoniter.ConfigFastest.Unmarshal(body, &ops); err != nil {
+		return nil, err
+	}
+	return transactionKeys(ops), nil
+}
+
+func transactionKeys(ops []policyTransactionOperation) []string {
+	keys := make([]string, len(ops))
+	for i, o := range ops {
+		keys[i] = o.Request.Key
+	}
+	return keys
+}
+
+// operationOf returns the policy operation of a request of an endpoint.
+func operationOf(method, route string) httpOperation {
+	if op, ok := httpOperations[method+" "+route]; ok {
+		return op
+	}
+	if op, ok := httpOperations[route]; ok {
+		return op
+	}
+	return httpOperation{name: strings.ToLower(method)}
+}
+
+// initAuthorization compiles the policies configured for the APIs and creates their access list.
+func (s *server) initAuthorization() error {
+	s.accessList = policy.NewAccessList(s.apiSpec, protocol)
+
+	policySpec := s.apiSpec.Policy
+	if len(policySpec.Rules) == 0 && policySpec.DefaultAction == "" {
+		return nil
+	}
+
+	engine, err := policy.NewEngine(policySpec)
+	if err != nil {
+		return errors.Wrap(err, "error creating API policies")
+	}
+	log.Infof("enabled API policies on http server with %d rules", len(policySpec.Rules))
+	s.policyEngine = engine
+	return nil
+}
+
+// useAuthorization authorizes the requests of an endpoint with the access list and the policies configured for the APIs.
+func (s *server) useAuthorization(e Endpoint, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	if (s.accessList == nil && s.policyEngine == nil) || auth.ExcludedRoute("/"+e.Version+"/"+e.Route) {
+		return next
+	}
+
+	api := strings.SplitN(e.Route, "/", 2)[0]
+	return func(ctx *fasthttp.RequestCtx) {
+		method := string(ctx.Method())
+		op := operationOf(method, e.Route)
+		req := policy.Request{
+			Caller:    s.config.AppID,
+			API:       api,
+			Version:   e.Version,
+			Operation: op.name,
+			Method:    method,
+			Component: firstUserValue(ctx, policyComponentParams),
+			Resource:  firstUserValue(ctx, policyResourceParams),
+			Protocol:  protocol,
+		}
+		if subject, ok := ctx.UserValue(subjectUserValueKey).(oidcSubject); ok {
+			req.Caller = string(subject)
+		}
+
+		if s.accessList != nil && !s.accessList.Allowed(req) {
+			denyRequest(ctx, req, fmt.Sprintf("access to %s %s denied by the API access rules", req.Method, e.Route))
+			return
+		}
+		if s.policyEngine == nil {
+			next(ctx)
+			return
+		}
+
+		var resources []string
+		if op.keys != nil {
+			var err error
+			resources, err = op.keys(ctx.PostBody())
+			if err != nil {
+				msg := NewErrorResponse("ERR_MALFORMED_REQUEST", err.Error())
+				respond(ctx, withError(fasthttp.StatusBadRequest, msg))
+				return
+			}
+		}
+
+		for _, r := range req.ForResources(resources) {
+			allowed, name, err := s.policyEngine.Authorize(r)
+			if err != nil {
+				denyRequest(ctx, r, err.Error())
+				return
+			}
+			if !allowed {
+				reason := "the default policy"
+				if name != "" {
+					reason = fmt.Sprintf("policy %s", name)
+				}
+				denyRequest(ctx, r, fmt.Sprintf("access to %s %s denied by %s", r.Method, e.Route, reason))
+				return
+			}
+		}
+		next(ctx)
+	}
+}
+
+// denyRequest rejects a request denied by the access rules or the policies of the API, and audits it.
+func denyRequest(ctx *fasthttp.RequestCtx, req policy.Request, message string) {
+	audit.Default.Emit(audit.Event{
+		Type:     audit.EventAccessDenied,
+		Subject:  req.Caller,
+		Resource: string(ctx.Method()) + " " + string(ctx.Path()),
+		Reason:   message,
+	})
+
+	msg := NewErrorResponse("ERR_PERMISSION_DENIED", message)
+	respond(ctx, withError(fasthttp.StatusForbidden, msg))
+}
+
+func firstUserValue(ctx *fasthttp.RequestCtx, keys []string) string {
+	for _, key := range keys {
+		if v, ok := ctx.UserValue(key).(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
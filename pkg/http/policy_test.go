@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/dapr/pkg/policy"
+)
+
+func TestOperationOf(t *testing.T) {
+	assert.Equal(t, policy.OperationGet, operationOf(fasthttp.MethodGet, "state/{storeName}/{key}").name)
+	assert.Equal(t, policy.OperationDelete, operationOf(fasthttp.MethodDelete, "state/{storeName}/{key}").name)
+	assert.Equal(t, policy.OperationSave, operationOf(fasthttp.MethodPut, "state/{storeName}").name)
+	assert.Equal(t, policy.OperationTransaction, operationOf(fasthttp.MethodPost, "state/{storeName}/transaction").name)
+	assert.Equal(t, "registerActorReminder", operationOf(fasthttp.MethodPut, "actors/{actorType}/{actorId}/reminders/{name}").name)
+	assert.Equal(t, "post", operationOf(fasthttp.MethodPost, "shutdown").name)
+}
+
+func TestUseAuthorizationWithPolicies(t *testing.T) {
+	engine, err := policy.NewEngine(config.APIPolicySpec{
+		DefaultAction: config.DenyAccess,
+		Rules: []config.APIPolicyRule{
+			{
+				Name:      "deny-secret-keys",
+				Condition: "resource.startsWith('secret-')",
+				Action:    config.DenyAccess,
+			},
+			{
+				Name:      "allow-orders-state",
+				Condition: "api == 'state' && component == 'orders' && operation in ['save', 'bulkGet', 'transaction']",
+				Action:    config.AllowAccess,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	s := &server{policyEngine: engine}
+	served := false
+	next := func(ctx *fasthttp.RequestCtx) {
+		served = true
+	}
+
+	testCases := []struct {
+		name    string
+		route   string
+		body    string
+		allowed bool
+	}{
+		{name: "save allowed", route: "state/{storeName}", body: `[{"key":"1"},{"key":"2"}]`, allowed: true},
+		{name: "save of a denied key", route: "state/{storeName}", body: `[{"key":"1"},{"key":"secret-1"}]`},
+		{name: "bulk get of a denied key", route: "state/{storeName}/bulk", body: `{"keys":["secret-1"]}`},
+		{name: "transaction allowed", route: "state/{storeName}/transaction", body: `{"operations":[{"operation":"upsert","request":{"key":"1"}}]}`, allowed: true},
+		{name: "transaction with a denied key", route: "state/{storeName}/transaction", body: `{"operations":[{"operation":"upsert","request":{"key":"1"}},{"operation":"delete","request":{"key":"secret-1"}}]}`},
+		{name: "malformed body", route: "state/{storeName}/transaction", body: `{"operations":`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			served = false
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+			ctx.Request.SetBody([]byte(tc.body))
+			ctx.SetUserValue(storeNameParam, "orders")
+
+			s.useAuthorization(Endpoint{Route: tc.route, Version: apiVersionV1}, next)(ctx)
+			assert.Equal(t, tc.allowed, served)
+			if !tc.allowed {
+				assert.NotEqual(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+			}
+		})
+	}
+}
@@ -13,6 +13,10 @@ limitations under the License.
 
 package http
 
+import (
+	"github.com/dapr/dapr/pkg/jobs"
+)
+
 // OutputBindingRequest is the request object to invoke an output binding.
 type OutputBindingRequest struct {
 	Metadata  map[string]string `json:"metadata"`
@@ -20,9 +24,44 @@ type OutputBindingRequest struct {
 	Operation string            `json:"operation"`
 }
 
+// BulkOutputBindingRequest is the request object to invoke an output binding with several requests.
+type BulkOutputBindingRequest struct {
+	Requests []OutputBindingRequest `json:"requests"`
+}
+
 // BulkGetRequest is the request object to get a list of values for multiple keys from a state store.
 type BulkGetRequest struct {
 	Metadata    map[string]string `json:"metadata"`
 	Keys        []string          `json:"keys"`
 	Parallelism int               `json:"parallelism"`
 }
+
+// SubscribeSecretsRequest is the request object to subscribe the app to the rotations of secrets.
+type SubscribeSecretsRequest struct {
+	Keys     []string          `json:"keys"`
+	Method   string            `json:"method"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// CryptoRequest is the request object to perform a cryptographic operation with a key of a secret store.
+type CryptoRequest struct {
+	KeyName   string            `json:"keyName"`
+	Data      []byte            `json:"data"`
+	Signature []byte            `json:"signature,omitempty"`
+	Algorithm string            `json:"algorithm,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// JobRequest is the request object to create a job invoking an app endpoint on a schedule.
+type JobRequest struct {
+	Schedule      string              `json:"schedule"`
+	Method        string              `json:"method"`
+	Data          interface{}         `json:"data"`
+	FailurePolicy *jobs.FailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// LogLevelRequest is the request object to change the log levels of the sidecar without restart.
+type LogLevelRequest struct {
+	Level  string            `json:"level"`
+	Scopes map[string]string `json:"scopes"`
+}
@@ -35,6 +35,19 @@ type BulkGetResponse struct {
 	Error    string              `json:"error,omitempty"`
 }
 
+// BulkOutputBindingResponse is the response object for one of the requests of a bulk output binding invocation.
+type BulkOutputBindingResponse struct {
+	Data     jsoniter.RawMessage `json:"data,omitempty"`
+	Metadata map[string]string   `json:"metadata,omitempty"`
+	Error    string              `json:"error,omitempty"`
+}
+
+// CryptoResponse is the response object for a cryptographic operation with a key of a secret store.
+type CryptoResponse struct {
+	Data  []byte `json:"data,omitempty"`
+	Valid bool   `json:"valid,omitempty"`
+}
+
 // QueryResponse is the response object for querying state.
 type QueryResponse struct {
 	Results  []QueryItem       `json:"results"`
@@ -79,8 +92,9 @@ func withJSON(code int, obj []byte) option {
 	}
 }
 
-// withError sets error code and jsonized error message.
+// withError sets error code and jsonized error message, with the details of its reason.
 func withError(code int, resp ErrorResponse) option {
+	resp = resp.withReason(code)
 	b, _ := json.Marshal(&resp)
 	return withJSON(code, b)
 }
@@ -93,6 +107,14 @@ func withEmpty() option {
 	}
 }
 
+// withNotModified sets 304 status code.
+func withNotModified() option {
+	return func(ctx *fasthttp.RequestCtx) {
+		ctx.Response.SetBody(nil)
+		ctx.Response.SetStatusCode(fasthttp.StatusNotModified)
+	}
+}
+
 // with sets a default application/json content type if content type is not present.
 func with(code int, obj []byte) option {
 	return func(ctx *fasthttp.RequestCtx) {
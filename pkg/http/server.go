@@ -14,6 +14,7 @@ limitations under the License.
 package http
 
 import (
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net"
@@ -29,11 +30,12 @@ import (
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fasthttp/pprofhandler"
 
+	"github.com/dapr/dapr/pkg/audit"
 	"github.com/dapr/dapr/pkg/config"
 	cors_dapr "github.com/dapr/dapr/pkg/cors"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
-	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
 	http_middleware "github.com/dapr/dapr/pkg/middleware/http"
+	"github.com/dapr/dapr/pkg/policy"
 	auth "github.com/dapr/dapr/pkg/runtime/security"
 	"github.com/dapr/kit/logger"
 )
@@ -55,8 +57,11 @@ type server struct {
 	pipeline           http_middleware.Pipeline
 	api                API
 	apiSpec            config.APISpec
+	policyEngine       *policy.Engine
+	accessList         *policy.AccessList
 	servers            []*fasthttp.Server
 	profilingListeners []net.Listener
+	bodyLimits         *requestBodyLimits
 }
 
 // NewServer returns a new HTTP server.
@@ -73,13 +78,29 @@ func NewServer(api API, config ServerConfig, tracingSpec config.TracingSpec, met
 
 // StartNonBlocking starts a new server in a goroutine.
 func (s *server) StartNonBlocking() error {
-	handler :=
-		useAPIAuthentication(
-			s.useCors(
-				s.useComponents(
-					s.useRouter())))
+	if err := s.initAuthorization(); err != nil {
+		return err
+	}
+
+	handler, err := s.useOIDCAuthentication(
+		s.useCors(
+			s.useComponents(
+				s.useRouter())))
+	if err != nil {
+		return err
+	}
 
+	handler, err = s.useCompression(handler)
+	if err != nil {
+		return err
+	}
+
+	s.bodyLimits = newRequestBodyLimits(s.config.MaxRequestBodySize, s.apiSpec.HTTP.MaxRequestBodySize)
+	handler = s.useRequestBodyLimits(handler)
+
+	handler = useAPIAuthentication(handler)
 	handler = s.useMetrics(handler)
+	handler = s.useAccessLog(handler)
 	handler = s.useTracing(handler)
 
 	var listeners []net.Listener
@@ -113,6 +134,12 @@ func (s *server) StartNonBlocking() error {
 			MaxRequestBodySize: s.config.MaxRequestBodySize * 1024 * 1024,
 			ReadBufferSize:     s.config.ReadBufferSize * 1024,
 			StreamRequestBody:  s.config.StreamRequestBody,
+			ErrorHandler:       s.bodyLimits.errorHandler,
+		}
+		// Streamed bodies are checked by useRequestBodyLimits, since the limit of the server only bounds
+		// the part of their body read before the handler.
+		if !s.config.StreamRequestBody {
+			customServer.HeaderReceived = s.bodyLimits.headerReceived
 		}
 		s.servers = append(s.servers, customServer)
 
@@ -126,6 +153,7 @@ func (s *server) StartNonBlocking() error {
 	if s.config.PublicPort != nil {
 		publicHandler := s.usePublicRouter()
 		publicHandler = s.useMetrics(publicHandler)
+		publicHandler = s.useAccessLog(publicHandler)
 		publicHandler = s.useTracing(publicHandler)
 
 		healthServer := &fasthttp.Server{
@@ -148,6 +176,9 @@ func (s *server) StartNonBlocking() error {
 			if err != nil {
 				log.Warnf("Failed to listen on %v:%v with error: %v", apiListenAddress, s.config.ProfilePort, err)
 			} else {
+				if s.config.ProfileTLSConfig != nil {
+					pl = tls.NewListener(pl, s.config.ProfileTLSConfig)
+				}
 				profilingListeners = append(profilingListeners, pl)
 			}
 		}
@@ -191,7 +222,7 @@ func (s *server) Close() error {
 }
 
 func (s *server) useTracing(next fasthttp.RequestHandler) fasthttp.RequestHandler {
-	if diag_utils.IsTracingEnabled(s.tracingSpec.SamplingRate) {
+	if diag.IsTracingEnabled(s.tracingSpec) {
 		log.Infof("enabled tracing http middleware")
 		return diag.HTTPTraceMiddleware(next, s.config.AppID, s.tracingSpec)
 	}
@@ -208,6 +239,16 @@ func (s *server) useMetrics(next fasthttp.RequestHandler) fasthttp.RequestHandle
 	return next
 }
 
+func (s *server) useAccessLog(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	if diag.DefaultAccessLog.IsEnabled() {
+		log.Infof("enabled access log http middleware")
+
+		return diag.DefaultAccessLog.FastHTTPMiddleware(next)
+	}
+
+	return next
+}
+
 func (s *server) useRouter() fasthttp.RequestHandler {
 	endpoints := s.api.APIEndpoints()
 	router := s.getRouter(endpoints)
@@ -238,23 +279,88 @@ func (s *server) useCors(next fasthttp.RequestHandler) fasthttp.RequestHandler {
 }
 
 func useAPIAuthentication(next fasthttp.RequestHandler) fasthttp.RequestHandler {
-	token := auth.GetAPIToken()
-	if token == "" {
+	tokens := auth.GetAPITokens()
+	if len(tokens) == 0 {
 		return next
 	}
-	log.Info("enabled token authentication on http server")
+	log.Infof("enabled token authentication on http server with %d valid tokens", len(tokens))
 
 	return func(ctx *fasthttp.RequestCtx) {
-		v := ctx.Request.Header.Peek(auth.APITokenHeader)
-		if auth.ExcludedRoute(string(ctx.Request.URI().FullURI())) || string(v) == token {
+		if auth.ExcludedRoute(string(ctx.Path())) {
 			ctx.Request.Header.Del(auth.APITokenHeader)
 			next(ctx)
-		} else {
+			return
+		}
+
+		v := ctx.Request.Header.Peek(auth.APITokenHeader)
+		token, ok := auth.MatchAPIToken(tokens, string(v))
+		if !ok {
+			reason := "mismatch"
+			if len(v) == 0 {
+				reason = "missing"
+			}
+			diag.DefaultMonitoring.APITokenRejected(reason)
+			auditAuthenticationFailed(ctx, "api token "+reason)
 			ctx.Error("invalid api token", http.StatusUnauthorized)
+			return
 		}
+
+		diag.DefaultMonitoring.APITokenAuthenticated(auth.APITokenID(token))
+		ctx.Request.Header.Del(auth.APITokenHeader)
+		next(ctx)
 	}
 }
 
+// useOIDCAuthentication requires the requests to hold a valid bearer token of the OIDC issuer configured for the APIs.
+func (s *server) useOIDCAuthentication(next fasthttp.RequestHandler) (fasthttp.RequestHandler, error) {
+	oidcSpec := s.apiSpec.Authentication.OIDC
+	if oidcSpec == nil {
+		return next, nil
+	}
+
+	validator, err := auth.NewOIDCValidator(oidcSpec.Issuer, oidcSpec.JWKSURL, oidcSpec.Audiences)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating OIDC authentication")
+	}
+	log.Infof("enabled OIDC authentication on http server with issuer %s", oidcSpec.Issuer)
+
+	return func(ctx *fasthttp.RequestCtx) {
+		if auth.ExcludedRoute(string(ctx.Path())) {
+			next(ctx)
+			return
+		}
+
+		token, ok := auth.BearerToken(string(ctx.Request.Header.Peek(auth.AuthorizationHeader)))
+		if !ok {
+			auditAuthenticationFailed(ctx, "missing bearer token")
+			ctx.Error("missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		subject, err := validator.Validate(ctx, token)
+		if err != nil {
+			log.Debugf("rejected request with %s", err)
+			auditAuthenticationFailed(ctx, err.Error())
+			ctx.Error("invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		// The token is not forwarded to the target of the request.
+		ctx.Request.Header.Del(auth.AuthorizationHeader)
+		ctx.SetUserValue(subjectUserValueKey, oidcSubject(subject))
+		next(ctx)
+	}, nil
+}
+
+// auditAuthenticationFailed emits the audit event of a request rejected by the authentication of the API.
+func auditAuthenticationFailed(ctx *fasthttp.RequestCtx, reason string) {
+	audit.Default.Emit(audit.Event{
+		Type:     audit.EventAuthenticationFailed,
+		Resource: string(ctx.Method()) + " " + string(ctx.Path()),
+		Reason:   reason,
+	})
+}
+
 func (s *server) getCorsHandler(allowedOrigins []string) *cors.CorsHandler {
 	return cors.NewCorsHandler(cors.Options{
 		AllowedOrigins: allowedOrigins,
@@ -294,10 +400,6 @@ func (s *server) getRouter(endpoints []Endpoint) *routing.Router {
 	router := routing.New()
 	parameterFinder, _ := regexp.Compile("/{.*}")
 	for _, e := range endpoints {
-		if !s.endpointAllowed(e) {
-			continue
-		}
-
 		path := fmt.Sprintf("/%s/%s", e.Version, e.Route)
 		s.handle(e, parameterFinder, path, router)
 
@@ -311,33 +413,13 @@ func (s *server) getRouter(endpoints []Endpoint) *routing.Router {
 }
 
 func (s *server) handle(e Endpoint, parameterFinder *regexp.Regexp, path string, router *routing.Router) {
+	handler := s.useAuthorization(e, e.Handler)
 	for _, m := range e.Methods {
 		pathIncludesParameters := parameterFinder.MatchString(path)
 		if pathIncludesParameters && !e.KeepParamUnescape {
-			router.Handle(m, path, s.unescapeRequestParametersHandler(e.Handler))
+			router.Handle(m, path, s.unescapeRequestParametersHandler(handler))
 		} else {
-			router.Handle(m, path, e.Handler)
-		}
-	}
-}
-
-func (s *server) endpointAllowed(endpoint Endpoint) bool {
-	var httpRules []config.APIAccessRule
-
-	for _, rule := range s.apiSpec.Allowed {
-		if rule.Protocol == protocol {
-			httpRules = append(httpRules, rule)
-		}
-	}
-	if len(httpRules) == 0 {
-		return true
-	}
-
-	for _, rule := range httpRules {
-		if (strings.Index(endpoint.Route, rule.Name) == 0 && endpoint.Version == rule.Version) || endpoint.Route == "healthz" {
-			return true
+			router.Handle(m, path, handler)
 		}
 	}
-
-	return false
 }
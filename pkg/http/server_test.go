@@ -29,6 +29,8 @@ import (
 	"github.com/dapr/dapr/pkg/config"
 	"github.com/dapr/dapr/pkg/cors"
 	http_middleware "github.com/dapr/dapr/pkg/middleware/http"
+	"github.com/dapr/dapr/pkg/policy"
+	auth "github.com/dapr/dapr/pkg/runtime/security"
 	dapr_testing "github.com/dapr/dapr/pkg/testing"
 )
 
@@ -36,8 +38,6 @@ type mockHost struct {
 	hasCORS bool
 }
 
-const healthzEndpoint = "healthz"
-
 func (m *mockHost) mockHandler() fasthttp.RequestHandler {
 	return func(ctx *fasthttp.RequestCtx) {
 		b := ctx.Response.Header.Peek("Access-Control-Allow-Origin")
@@ -74,7 +74,7 @@ func TestAllowedAPISpec(t *testing.T) {
 		eps := a.constructStateEndpoints()
 
 		for _, e := range eps {
-			valid := s.endpointAllowed(e)
+			valid := endpointAllowed(&s, e)
 			assert.True(t, valid)
 		}
 
@@ -89,8 +89,8 @@ func TestAllowedAPISpec(t *testing.T) {
 		allOtherEndpoints = append(allOtherEndpoints, a.constructHealthzEndpoints()...)
 
 		for _, e := range allOtherEndpoints {
-			valid := s.endpointAllowed(e)
-			if e.Route == healthzEndpoint {
+			valid := endpointAllowed(&s, e)
+			if auth.ExcludedRoute("/" + e.Version + "/" + e.Route) {
 				assert.True(t, valid)
 			} else {
 				assert.False(t, valid)
@@ -115,7 +115,7 @@ func TestAllowedAPISpec(t *testing.T) {
 		eps := a.constructPubSubEndpoints()
 
 		for _, e := range eps {
-			valid := s.endpointAllowed(e)
+			valid := endpointAllowed(&s, e)
 			assert.True(t, valid)
 		}
 
@@ -130,8 +130,8 @@ func TestAllowedAPISpec(t *testing.T) {
 		allOtherEndpoints = append(allOtherEndpoints, a.constructHealthzEndpoints()...)
 
 		for _, e := range allOtherEndpoints {
-			valid := s.endpointAllowed(e)
-			if e.Route == healthzEndpoint {
+			valid := endpointAllowed(&s, e)
+			if auth.ExcludedRoute("/" + e.Version + "/" + e.Route) {
 				assert.True(t, valid)
 			} else {
 				assert.False(t, valid)
@@ -156,7 +156,7 @@ func TestAllowedAPISpec(t *testing.T) {
 		eps := a.constructDirectMessagingEndpoints()
 
 		for _, e := range eps {
-			valid := s.endpointAllowed(e)
+			valid := endpointAllowed(&s, e)
 			assert.True(t, valid)
 		}
 
@@ -171,8 +171,8 @@ func TestAllowedAPISpec(t *testing.T) {
 		allOtherEndpoints = append(allOtherEndpoints, a.constructHealthzEndpoints()...)
 
 		for _, e := range allOtherEndpoints {
-			valid := s.endpointAllowed(e)
-			if e.Route == healthzEndpoint {
+			valid := endpointAllowed(&s, e)
+			if auth.ExcludedRoute("/" + e.Version + "/" + e.Route) {
 				assert.True(t, valid)
 			} else {
 				assert.False(t, valid)
@@ -197,7 +197,7 @@ func TestAllowedAPISpec(t *testing.T) {
 		eps := a.constructBindingsEndpoints()
 
 		for _, e := range eps {
-			valid := s.endpointAllowed(e)
+			valid := endpointAllowed(&s, e)
 			assert.True(t, valid)
 		}
 
@@ -212,8 +212,8 @@ func TestAllowedAPISpec(t *testing.T) {
 		allOtherEndpoints = append(allOtherEndpoints, a.constructHealthzEndpoints()...)
 
 		for _, e := range allOtherEndpoints {
-			valid := s.endpointAllowed(e)
-			if e.Route == healthzEndpoint {
+			valid := endpointAllowed(&s, e)
+			if auth.ExcludedRoute("/" + e.Version + "/" + e.Route) {
 				assert.True(t, valid)
 			} else {
 				assert.False(t, valid)
@@ -238,7 +238,7 @@ func TestAllowedAPISpec(t *testing.T) {
 		eps := a.constructMetadataEndpoints()
 
 		for _, e := range eps {
-			valid := s.endpointAllowed(e)
+			valid := endpointAllowed(&s, e)
 			assert.True(t, valid)
 		}
 
@@ -253,8 +253,8 @@ func TestAllowedAPISpec(t *testing.T) {
 		allOtherEndpoints = append(allOtherEndpoints, a.constructHealthzEndpoints()...)
 
 		for _, e := range allOtherEndpoints {
-			valid := s.endpointAllowed(e)
-			if e.Route == healthzEndpoint {
+			valid := endpointAllowed(&s, e)
+			if auth.ExcludedRoute("/" + e.Version + "/" + e.Route) {
 				assert.True(t, valid)
 			} else {
 				assert.False(t, valid)
@@ -279,7 +279,7 @@ func TestAllowedAPISpec(t *testing.T) {
 		eps := a.constructSecretEndpoints()
 
 		for _, e := range eps {
-			valid := s.endpointAllowed(e)
+			valid := endpointAllowed(&s, e)
 			assert.True(t, valid)
 		}
 
@@ -294,8 +294,8 @@ func TestAllowedAPISpec(t *testing.T) {
 		allOtherEndpoints = append(allOtherEndpoints, a.constructHealthzEndpoints()...)
 
 		for _, e := range allOtherEndpoints {
-			valid := s.endpointAllowed(e)
-			if e.Route == healthzEndpoint {
+			valid := endpointAllowed(&s, e)
+			if auth.ExcludedRoute("/" + e.Version + "/" + e.Route) {
 				assert.True(t, valid)
 			} else {
 				assert.False(t, valid)
@@ -320,7 +320,7 @@ func TestAllowedAPISpec(t *testing.T) {
 		eps := a.constructShutdownEndpoints()
 
 		for _, e := range eps {
-			valid := s.endpointAllowed(e)
+			valid := endpointAllowed(&s, e)
 			assert.True(t, valid)
 		}
 
@@ -335,8 +335,8 @@ func TestAllowedAPISpec(t *testing.T) {
 		allOtherEndpoints = append(allOtherEndpoints, a.constructHealthzEndpoints()...)
 
 		for _, e := range allOtherEndpoints {
-			valid := s.endpointAllowed(e)
-			if e.Route == healthzEndpoint {
+			valid := endpointAllowed(&s, e)
+			if auth.ExcludedRoute("/" + e.Version + "/" + e.Route) {
 				assert.True(t, valid)
 			} else {
 				assert.False(t, valid)
@@ -351,7 +351,7 @@ func TestAllowedAPISpec(t *testing.T) {
 		eps := a.APIEndpoints()
 
 		for _, e := range eps {
-			valid := s.endpointAllowed(e)
+			valid := endpointAllowed(&s, e)
 			assert.True(t, valid)
 		}
 	})
@@ -424,6 +424,7 @@ func TestAllowedAPISpec(t *testing.T) {
 		a := &api{}
 		eps := a.APIEndpoints()
 
+		require.NoError(t, s.initAuthorization())
 		router := s.getRouter(eps)
 		r := &fasthttp.RequestCtx{
 			Request: fasthttp.Request{},
@@ -433,19 +434,34 @@ func TestAllowedAPISpec(t *testing.T) {
 			path := fmt.Sprintf("/%s/%s", e.Version, e.Route)
 			for _, m := range e.Methods {
 				handler, ok := router.Lookup(m, path, r)
+				assert.NotNil(t, handler)
+				assert.True(t, ok)
 
-				if strings.Index(e.Route, "state") == 0 {
-					assert.NotNil(t, handler)
-					assert.True(t, ok)
-				} else {
-					assert.Nil(t, handler)
-					assert.False(t, ok)
+				// The requests of the endpoints which aren't allowed are rejected by the access list.
+				if strings.Index(e.Route, "state") != 0 && !auth.ExcludedRoute(path) {
+					ctx := &fasthttp.RequestCtx{}
+					ctx.Request.Header.SetMethod(m)
+					handler(ctx)
+					assert.Equal(t, fasthttp.StatusForbidden, ctx.Response.StatusCode())
 				}
 			}
 		}
 	})
 }
 
+// endpointAllowed returns whether a request of the endpoint is allowed by the access list of the server.
+func endpointAllowed(s *server, e Endpoint) bool {
+	s.accessList = policy.NewAccessList(s.apiSpec, protocol)
+
+	allowed := false
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(e.Methods[0])
+	s.useAuthorization(e, func(*fasthttp.RequestCtx) {
+		allowed = true
+	})(ctx)
+	return allowed
+}
+
 func TestCorsHandler(t *testing.T) {
 	t.Run("with default cors, middleware not enabled", func(t *testing.T) {
 		srv := newServer()
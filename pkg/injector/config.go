@@ -27,6 +27,12 @@ type Config struct {
 	SidecarImagePullPolicy string `envconfig:"SIDECAR_IMAGE_PULL_POLICY"`
 	Namespace              string `envconfig:"NAMESPACE" required:"true"`
 	KubeClusterDomain      string `envconfig:"KUBE_CLUSTER_DOMAIN"`
+	// SidecarInjectionMode is the default injection mode of the sidecar, container or native. Native sidecars are
+	// init containers which are always restarted, supported by Kubernetes 1.29 and later.
+	SidecarInjectionMode string `envconfig:"SIDECAR_INJECTION_MODE"`
+	// PlacementShardCount is the number of placement shards the actor placement tables are sharded across. The
+	// sidecars are given the addresses of all shards.
+	PlacementShardCount int `envconfig:"PLACEMENT_SHARD_COUNT"`
 }
 
 // NewConfigWithDefaults returns a Config object with default values already
@@ -35,6 +41,8 @@ type Config struct {
 func NewConfigWithDefaults() Config {
 	return Config{
 		SidecarImagePullPolicy: "Always",
+		SidecarInjectionMode:   sidecarInjectionModeContainer,
+		PlacementShardCount:    1,
 	}
 }
 
@@ -23,6 +23,7 @@ func TestGetInectorConfig(t *testing.T) {
 		assert.Equal(t, "test-key-file", cfg.TLSKeyFile)
 		assert.Equal(t, "daprd-test-image", cfg.SidecarImage)
 		assert.Equal(t, "Always", cfg.SidecarImagePullPolicy)
+		assert.Equal(t, "container", cfg.SidecarInjectionMode)
 		assert.Equal(t, "test-namespace", cfg.Namespace)
 		assert.Equal(t, "cluster.local", cfg.KubeClusterDomain)
 	})
@@ -202,6 +202,20 @@ func TestGetServiceAddress(t *testing.T) {
 	}
 }
 
+func TestGetPlacementAddress(t *testing.T) {
+	t.Run("single placement cluster", func(t *testing.T) {
+		assert.Equal(t, "dapr-placement-server.dapr-system.svc.cluster.local:50005", getPlacementAddress("dapr-system", "cluster.local", 1))
+	})
+
+	t.Run("placement shards", func(t *testing.T) {
+		assert.Equal(t,
+			"dapr-placement-server-shard-0.dapr-system.svc.cluster.local:50005;"+
+				"dapr-placement-server-shard-1.dapr-system.svc.cluster.local:50005;"+
+				"dapr-placement-server-shard-2.dapr-system.svc.cluster.local:50005",
+			getPlacementAddress("dapr-system", "cluster.local", 3))
+	})
+}
+
 func TestGetMetricsPort(t *testing.T) {
 	t.Run("metrics port override", func(t *testing.T) {
 		m := map[string]string{daprMetricsPortKey: "5050"}
@@ -635,3 +649,129 @@ func TestHandleRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestNativeSidecarInjection(t *testing.T) {
+	i := NewInjector(nil, NewConfigWithDefaults(), fake.NewSimpleClientset(), kubernetesfake.NewSimpleClientset()).(*injector)
+
+	review := func(annotations map[string]string, initContainers []corev1.Container) *v1.AdmissionReview {
+		podBytes, _ := json.Marshal(corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-app",
+				Annotations: annotations,
+			},
+			Spec: corev1.PodSpec{
+				InitContainers: initContainers,
+				Containers:     []corev1.Container{{Name: "main"}},
+			},
+		})
+		return &v1.AdmissionReview{
+			Request: &v1.AdmissionRequest{
+				Namespace: "default",
+				Object:    runtime.RawExtension{Raw: podBytes},
+			},
+		}
+	}
+	annotations := map[string]string{
+		daprEnabledKey:              "true",
+		appIDKey:                    "test-app",
+		daprSidecarInjectionModeKey: sidecarInjectionModeNative,
+	}
+
+	t.Run("first init container", func(t *testing.T) {
+		patchOps, err := i.getPodPatchOperations(review(annotations, nil), "dapr-system", "daprd", "Always", i.kubeClient, i.daprClient)
+		assert.NoError(t, err)
+		assert.Equal(t, initContainersPath, patchOps[0].Path)
+		containers := patchOps[0].Value.([]nativeSidecarContainer)
+		assert.Equal(t, sidecarContainerName, containers[0].Name)
+		assert.Equal(t, corev1.RestartPolicyAlways, containers[0].RestartPolicy)
+		// the app containers still get the ports of the sidecar.
+		assert.Equal(t, "/spec/containers/0/env", patchOps[1].Path)
+	})
+
+	t.Run("prepended to the init containers", func(t *testing.T) {
+		patchOps, err := i.getPodPatchOperations(review(annotations, []corev1.Container{{Name: "migrate"}}), "dapr-system", "daprd", "Always", i.kubeClient, i.daprClient)
+		assert.NoError(t, err)
+		assert.Equal(t, initContainersPath+"/0", patchOps[0].Path)
+		b, err := json.Marshal(patchOps[0].Value)
+		assert.NoError(t, err)
+		assert.Contains(t, string(b), `"restartPolicy":"Always"`)
+		assert.Contains(t, string(b), `"name":"daprd"`)
+	})
+
+	t.Run("already injected", func(t *testing.T) {
+		patchOps, err := i.getPodPatchOperations(review(annotations, []corev1.Container{{Name: sidecarContainerName}}), "dapr-system", "daprd", "Always", i.kubeClient, i.daprClient)
+		assert.NoError(t, err)
+		assert.Empty(t, patchOps)
+	})
+}
+
+func TestBatchPodInjection(t *testing.T) {
+	i := NewInjector(nil, NewConfigWithDefaults(), fake.NewSimpleClientset(), kubernetesfake.NewSimpleClientset()).(*injector)
+
+	sidecarArgs := func(t *testing.T, owner *metav1.OwnerReference, annotations map[string]string) []string {
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-app",
+				Annotations: map[string]string{
+					daprEnabledKey: "true",
+					appIDKey:       "test-app",
+					daprAppPortKey: "8080",
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "main"}},
+			},
+		}
+		if owner != nil {
+			pod.OwnerReferences = []metav1.OwnerReference{*owner}
+		}
+		for k, v := range annotations {
+			if v == "" {
+				delete(pod.Annotations, k)
+				continue
+			}
+			pod.Annotations[k] = v
+		}
+		podBytes, _ := json.Marshal(pod)
+		review := &v1.AdmissionReview{
+			Request: &v1.AdmissionRequest{
+				Namespace: "default",
+				Object:    runtime.RawExtension{Raw: podBytes},
+			},
+		}
+
+		patchOps, err := i.getPodPatchOperations(review, "dapr-system", "daprd", "Always", i.kubeClient, i.daprClient)
+		assert.NoError(t, err)
+		return patchOps[0].Value.(*corev1.Container).Args
+	}
+
+	t.Run("job", func(t *testing.T) {
+		args := sidecarArgs(t, &metav1.OwnerReference{APIVersion: "batch/v1", Kind: "Job", Name: "migrate"}, nil)
+		assert.Contains(t, args, "--shutdown-on-app-exit")
+	})
+
+	t.Run("argo workflow", func(t *testing.T) {
+		args := sidecarArgs(t, &metav1.OwnerReference{APIVersion: "argoproj.io/v1alpha1", Kind: "Workflow", Name: "etl"}, nil)
+		assert.Contains(t, args, "--shutdown-on-app-exit")
+	})
+
+	t.Run("tekton task run", func(t *testing.T) {
+		args := sidecarArgs(t, &metav1.OwnerReference{APIVersion: "tekton.dev/v1beta1", Kind: "TaskRun", Name: "build"}, nil)
+		assert.Contains(t, args, "--shutdown-on-app-exit")
+	})
+
+	t.Run("disabled by annotation", func(t *testing.T) {
+		args := sidecarArgs(t, &metav1.OwnerReference{APIVersion: "batch/v1", Kind: "Job", Name: "migrate"}, map[string]string{daprShutdownOnAppExitKey: "false"})
+		assert.NotContains(t, args, "--shutdown-on-app-exit")
+	})
+
+	t.Run("deployment", func(t *testing.T) {
+		args := sidecarArgs(t, &metav1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "app-5d8f"}, nil)
+		assert.NotContains(t, args, "--shutdown-on-app-exit")
+	})
+
+	t.Run("job without app port", func(t *testing.T) {
+		args := sidecarArgs(t, &metav1.OwnerReference{APIVersion: "batch/v1", Kind: "Job", Name: "migrate"}, map[string]string{daprAppPortKey: ""})
+		assert.NotContains(t, args, "--shutdown-on-app-exit")
+	})
+}
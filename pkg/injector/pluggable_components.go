@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package injector
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	pluggableComponentsVolumeName        = "dapr-components-unix-domain-socket"
+	pluggableComponentsSocketsFolderName = "DAPR_COMPONENTS_SOCKETS_FOLDER"
+	pluggableComponentSocketName         = "DAPR_COMPONENT_SOCKET"
+	defaultPluggableComponentsFolder     = "/tmp/dapr-components-sockets"
+)
+
+// getPluggableComponents returns the containers of the dapr.io/pluggable-components annotation, which serve
+// components to the sidecar over Unix domain sockets, and the directory of the sockets. The containers are empty if
+// the annotation isn't set.
+func getPluggableComponents(annotations map[string]string) ([]string, string, error) {
+	var containers []string
+	for _, name := range strings.Split(getStringAnnotation(annotations, daprPluggableComponentsKey), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			containers = append(containers, name)
+		}
+	}
+
+	folder := getStringAnnotationOrDefault(annotations, daprComponentsSocketsFolderKey, defaultPluggableComponentsFolder)
+	if !path.IsAbs(folder) {
+		return nil, "", errors.Errorf("invalid %s annotation %q: the path must be absolute", daprComponentsSocketsFolderKey, folder)
+	}
+	return containers, folder, nil
+}
+
+// getPluggableComponentSocket returns the socket a pluggable component container listens on in the sockets folder.
+func getPluggableComponentSocket(folder, container string) string {
+	return path.Join(folder, fmt.Sprintf("%s.sock", container))
+}
+
+func getPluggableComponentsVolumeMount(folder string) corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      pluggableComponentsVolumeName,
+		MountPath: folder,
+	}
+}
+
+// getPluggableComponentsPatchOperations shares an emptyDir volume mounted at folder between the sidecar and the
+// pluggable component containers, and gives each container the socket it should listen on. The volume, the mounts
+// and the environment variables are added to pod too, so the patch operations computed afterwards append to the
+// arrays rather than replace them.
+func getPluggableComponentsPatchOperations(pod *corev1.Pod, containers []string, folder string) ([]PatchOperation, error) {
+	indexes := make(map[string]int, len(pod.Spec.Containers))
+	for i, container := range pod.Spec.Containers {
+		indexes[container.Name] = i
+	}
+	for _, name := range containers {
+		if _, ok := indexes[name]; !ok {
+			return nil, errors.Errorf("invalid %s annotation: the pod has no container %s", daprPluggableComponentsKey, name)
+		}
+	}
+
+	volume := corev1.Volume{
+		Name: pluggableComponentsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+	mount := getPluggableComponentsVolumeMount(folder)
+
+	patchOps := getArrayPatchOperations(len(pod.Spec.Volumes) == 0, volumesPath, []interface{}{volume})
+	for _, name := range containers {
+		i := indexes[name]
+		container := &pod.Spec.Containers[i]
+
+		mountsPath := fmt.Sprintf("%s/%d/volumeMounts", containersPath, i)
+		patchOps = append(patchOps, getArrayPatchOperations(len(container.VolumeMounts) == 0, mountsPath, []interface{}{mount})...)
+		container.VolumeMounts = append(container.VolumeMounts, mount)
+
+		env := []corev1.EnvVar{
+			{
+				Name:  pluggableComponentsSocketsFolderName,
+				Value: folder,
+			},
+			{
+				Name:  pluggableComponentSocketName,
+				Value: getPluggableComponentSocket(folder, name),
+			},
+		}
+		patchOps = append(patchOps, getEnvPatchOperations(container.Env, env, fmt.Sprintf("%s/%d/env", containersPath, i))...)
+		container.Env = mergeEnv(container.Env, env)
+	}
+	pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+	return patchOps, nil
+}
+
+// mergeEnv returns the environment variables with the ones of addEnv they don't define, like getEnvPatchOperations.
+func mergeEnv(envs []corev1.EnvVar, addEnv []corev1.EnvVar) []corev1.EnvVar {
+	defined := make(map[string]bool, len(envs))
+	for _, env := range envs {
+		defined[env.Name] = true
+	}
+	for _, env := range addEnv {
+		if !defined[env.Name] {
+			envs = append(envs, env)
+		}
+	}
+	return envs
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package injector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGetPluggableComponents(t *testing.T) {
+	containers, folder, err := getPluggableComponents(map[string]string{})
+	assert.NoError(t, err)
+	assert.Empty(t, containers)
+	assert.Equal(t, defaultPluggableComponentsFolder, folder)
+
+	containers, folder, err = getPluggableComponents(map[string]string{
+		daprPluggableComponentsKey:     "redis-store, kafka-pubsub",
+		daprComponentsSocketsFolderKey: "/var/run/components",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"redis-store", "kafka-pubsub"}, containers)
+	assert.Equal(t, "/var/run/components", folder)
+
+	_, _, err = getPluggableComponents(map[string]string{
+		daprPluggableComponentsKey:     "redis-store",
+		daprComponentsSocketsFolderKey: "var/run/components",
+	})
+	assert.Error(t, err)
+}
+
+func TestGetPluggableComponentsPatchOperations(t *testing.T) {
+	volume := corev1.Volume{
+		Name:         pluggableComponentsVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	}
+	mount := corev1.VolumeMount{Name: pluggableComponentsVolumeName, MountPath: "/tmp/sockets"}
+	existingVolume := corev1.Volume{Name: "data"}
+	existingEnv := corev1.EnvVar{Name: "LOG_LEVEL", Value: "debug"}
+
+	t.Run("component containers", func(t *testing.T) {
+		pod := corev1.Pod{
+			Spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{existingVolume},
+				Containers: []corev1.Container{
+					{Name: "app"},
+					{Name: "store"},
+					{Name: "pubsub", Env: []corev1.EnvVar{existingEnv}},
+				},
+			},
+		}
+
+		ops, err := getPluggableComponentsPatchOperations(&pod, []string{"store", "pubsub"}, "/tmp/sockets")
+		require.NoError(t, err)
+
+		storeEnv := []corev1.EnvVar{
+			{Name: pluggableComponentsSocketsFolderName, Value: "/tmp/sockets"},
+			{Name: pluggableComponentSocketName, Value: "/tmp/sockets/store.sock"},
+		}
+		pubsubEnv := []corev1.EnvVar{
+			{Name: pluggableComponentsSocketsFolderName, Value: "/tmp/sockets"},
+			{Name: pluggableComponentSocketName, Value: "/tmp/sockets/pubsub.sock"},
+		}
+		assert.Equal(t, []PatchOperation{
+			{Op: "add", Path: volumesPath + "/-", Value: volume},
+			{Op: "add", Path: "/spec/containers/1/volumeMounts", Value: []interface{}{mount}},
+			{Op: "add", Path: "/spec/containers/1/env", Value: storeEnv},
+			{Op: "add", Path: "/spec/containers/2/volumeMounts", Value: []interface{}{mount}},
+			{Op: "add", Path: "/spec/containers/2/env/-", Value: pubsubEnv[0]},
+			{Op: "add", Path: "/spec/containers/2/env/-", Value: pubsubEnv[1]},
+		}, ops)
+
+		// The pod records the patch, so the environment variables of the sidecar are appended.
+		assert.Equal(t, []corev1.Volume{existingVolume, volume}, pod.Spec.Volumes)
+		assert.Empty(t, pod.Spec.Containers[0].VolumeMounts)
+		assert.Equal(t, storeEnv, pod.Spec.Containers[1].Env)
+		assert.Equal(t, append([]corev1.EnvVar{existingEnv}, pubsubEnv...), pod.Spec.Containers[2].Env)
+	})
+
+	t.Run("unknown container", func(t *testing.T) {
+		pod := corev1.Pod{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "app"}},
+			},
+		}
+
+		_, err := getPluggableComponentsPatchOperations(&pod, []string{"store"}, "/tmp/sockets")
+		assert.Error(t, err)
+		assert.Empty(t, pod.Spec.Volumes)
+	})
+}
@@ -30,6 +30,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 
 	scheme "github.com/dapr/dapr/pkg/client/clientset/versioned"
+	env "github.com/dapr/dapr/pkg/config/env"
 	"github.com/dapr/dapr/pkg/credentials"
 	auth "github.com/dapr/dapr/pkg/runtime/security"
 	"github.com/dapr/dapr/pkg/sentry/certs"
@@ -52,6 +53,8 @@ const (
 	daprAppMaxConcurrencyKey          = "dapr.io/app-max-concurrency"
 	daprEnableMetricsKey              = "dapr.io/enable-metrics"
 	daprMetricsPortKey                = "dapr.io/metrics-port"
+	daprMetricsTLSModeKey             = "dapr.io/metrics-tls-mode"
+	daprProfileTLSModeKey             = "dapr.io/profile-tls-mode"
 	daprEnableDebugKey                = "dapr.io/enable-debug"
 	daprDebugPortKey                  = "dapr.io/debug-port"
 	daprEnvKey                        = "dapr.io/env"
@@ -74,7 +77,25 @@ const (
 	daprReadBufferSize                = "dapr.io/http-read-buffer-size"
 	daprHTTPStreamRequestBody         = "dapr.io/http-stream-request-body"
 	daprGracefulShutdownSeconds       = "dapr.io/graceful-shutdown-seconds"
+	daprEnableAppHealthCheck          = "dapr.io/enable-app-health-check"
+	daprAppHealthCheckPath            = "dapr.io/app-health-check-path"
+	daprAppHealthProbeInterval        = "dapr.io/app-health-probe-interval"
+	daprAppHealthProbeTimeout         = "dapr.io/app-health-probe-timeout"
+	daprAppHealthThreshold            = "dapr.io/app-health-threshold"
+	daprAppMaxConns                   = "dapr.io/app-max-conns"
+	daprAppMaxIdleConnDuration        = "dapr.io/app-max-idle-conn-duration"
+	daprAppKeepAliveInterval          = "dapr.io/app-keepalive-interval"
+	daprEnvFromSecretStoreKey         = "dapr.io/env-from-secret-store"
+	daprSecretsMountPathKey           = "dapr.io/secrets-mount-path"
+	daprSidecarInjectionModeKey       = "dapr.io/sidecar-injection-mode"
+	daprShutdownOnAppExitKey          = "dapr.io/shutdown-on-app-exit"
+	daprUnixDomainSocketPath          = "dapr.io/unix-domain-socket-path"
+	daprPluggableComponentsKey        = "dapr.io/pluggable-components"
+	daprComponentsSocketsFolderKey    = "dapr.io/pluggable-components-sockets-folder"
+	daprComponentsInitConcurrency     = "dapr.io/components-init-concurrency"
+	daprZoneKey                       = "dapr.io/zone"
 	containersPath                    = "/spec/containers"
+	initContainersPath                = "/spec/initContainers"
 	sidecarHTTPPort                   = 3500
 	sidecarAPIGRPCPort                = 50001
 	sidecarInternalGRPCPort           = 50002
@@ -111,8 +132,22 @@ const (
 	defaultMtlsEnabled                = true
 	trueString                        = "true"
 	defaultDaprHTTPStreamRequestBody  = false
+	defaultAppHealthCheckPath         = "/healthz"
+	defaultAppHealthProbeInterval     = 5
+	defaultAppHealthProbeTimeout      = 500
+	defaultAppHealthThreshold         = 3
+	sidecarInjectionModeContainer     = "container"
+	sidecarInjectionModeNative        = "native"
 )
 
+// nativeSidecarContainer is the sidecar container injected as a Kubernetes native sidecar, an init container which
+// is always restarted. The restart policy of the container is declared here as it's missing from the Kubernetes API
+// the injector is built with.
+type nativeSidecarContainer struct {
+	corev1.Container
+	RestartPolicy corev1.RestartPolicy `json:"restartPolicy"`
+}
+
 func (i *injector) getPodPatchOperations(ar *v1.AdmissionReview,
 	namespace, image, imagePullPolicy string, kubeClient kubernetes.Interface, daprClient scheme.Interface) ([]PatchOperation, error) {
 	req := ar.Request
@@ -144,8 +179,19 @@ func (i *injector) getPodPatchOperations(ar *v1.AdmissionReview,
 		return nil, err
 	}
 
+	// The sidecar defaults of the namespace apply to what the annotations of the pod don't set.
+	pod.Annotations = mergeSidecarDefaults(pod.Annotations, getSidecarDefaults(daprClient, req.Namespace))
+
+	// The sidecars of batch pods exit when their app completes, unless the annotations of the pod say otherwise.
+	// The sidecar detects the exit on the app port, apps without one call the shutdown API instead.
+	if _, ok := pod.Annotations[daprShutdownOnAppExitKey]; !ok && isBatchPod(&pod) {
+		if appPort, _ := getAppPort(pod.Annotations); appPort > 0 {
+			pod.Annotations[daprShutdownOnAppExitKey] = trueString
+		}
+	}
+
 	// Keep DNS resolution outside of getSidecarContainer for unit testing.
-	placementAddress := getServiceAddress(placementService, namespace, i.config.KubeClusterDomain, placementServicePort)
+	placementAddress := getPlacementAddress(namespace, i.config.KubeClusterDomain, i.config.PlacementShardCount)
 	sentryAddress := getServiceAddress(sentryService, namespace, i.config.KubeClusterDomain, sentryServicePort)
 	apiSvcAddress := getServiceAddress(apiAddress, namespace, i.config.KubeClusterDomain, apiPort)
 
@@ -164,15 +210,56 @@ func (i *injector) getPodPatchOperations(ar *v1.AdmissionReview,
 		return nil, err
 	}
 
+	// The socket volume is patched before the secrets, which may add volumes too.
+	socketPath, err := getUnixDomainSocketPath(pod.Annotations)
+	if err != nil {
+		return nil, err
+	}
+	var socketPatchOps []PatchOperation
+	var socketEnv []corev1.EnvVar
+	if socketPath != "" {
+		socketPatchOps = getUnixDomainSocketPatchOperations(&pod, socketPath)
+		socketEnv = getUnixDomainSocketEnv(socketPath, id)
+	}
+	componentContainers, componentsFolder, err := getPluggableComponents(pod.Annotations)
+	if err != nil {
+		return nil, err
+	}
+	if len(componentContainers) > 0 {
+		componentPatchOps, err := getPluggableComponentsPatchOperations(&pod, componentContainers, componentsFolder)
+		if err != nil {
+			return nil, err
+		}
+		socketPatchOps = append(socketPatchOps, componentPatchOps...)
+	}
+
+	// The secrets are patched before the sidecar is appended to the containers.
+	secretPatchOps, err := getSecretPatchOperations(pod, req.Namespace, id, daprClient)
+	if err != nil {
+		return nil, err
+	}
+
 	patchOps := []PatchOperation{}
 	envPatchOps := []PatchOperation{}
 	var path string
 	var value interface{}
-	if len(pod.Spec.Containers) == 0 {
+	if getSidecarInjectionMode(pod.Annotations, i.config.SidecarInjectionMode) == sidecarInjectionModeNative {
+		// The native sidecar is the first init container, so it starts before the other init containers and the
+		// app containers, and terminates after them.
+		envPatchOps = addDaprEnvVarsToContainers(pod.Spec.Containers, socketEnv...)
+		native := nativeSidecarContainer{Container: *sidecarContainer, RestartPolicy: corev1.RestartPolicyAlways}
+		if len(pod.Spec.InitContainers) == 0 {
+			path = initContainersPath
+			value = []nativeSidecarContainer{native}
+		} else {
+			path = initContainersPath + "/0"
+			value = native
+		}
+	} else if len(pod.Spec.Containers) == 0 {
 		path = containersPath
 		value = []corev1.Container{*sidecarContainer}
 	} else {
-		envPatchOps = addDaprEnvVarsToContainers(pod.Spec.Containers)
+		envPatchOps = addDaprEnvVarsToContainers(pod.Spec.Containers, socketEnv...)
 		path = "/spec/containers/-"
 		value = sidecarContainer
 	}
@@ -185,23 +272,30 @@ func (i *injector) getPodPatchOperations(ar *v1.AdmissionReview,
 			Value: value,
 		},
 	)
+	patchOps = append(patchOps, socketPatchOps...)
 	patchOps = append(patchOps, envPatchOps...)
+	patchOps = append(patchOps, secretPatchOps...)
 
 	return patchOps, nil
 }
 
 // This function add Dapr environment variables to all the containers in any Dapr enabled pod.
 // The containers can be injected or user defined.
-func addDaprEnvVarsToContainers(containers []corev1.Container) []PatchOperation {
-	portEnv := []corev1.EnvVar{
-		{
-			Name:  userContainerDaprHTTPPortName,
-			Value: strconv.Itoa(sidecarHTTPPort),
-		},
-		{
-			Name:  userContainerDaprGRPCPortName,
-			Value: strconv.Itoa(sidecarAPIGRPCPort),
-		},
+// When the sidecar serves its APIs on Unix domain sockets, the socket variables replace the port variables since the
+// APIs aren't served on the ports to the app.
+func addDaprEnvVarsToContainers(containers []corev1.Container, socketEnv ...corev1.EnvVar) []PatchOperation {
+	portEnv := socketEnv
+	if len(portEnv) == 0 {
+		portEnv = []corev1.EnvVar{
+			{
+				Name:  userContainerDaprHTTPPortName,
+				Value: strconv.Itoa(sidecarHTTPPort),
+			},
+			{
+				Name:  userContainerDaprGRPCPortName,
+				Value: strconv.Itoa(sidecarAPIGRPCPort),
+			},
+		}
 	}
 	envPatchOps := make([]PatchOperation, 0, len(containers))
 	for i, container := range containers {
@@ -290,6 +384,11 @@ func podContainsSidecarContainer(pod *corev1.Pod) bool {
 			return true
 		}
 	}
+	for _, c := range pod.Spec.InitContainers {
+		if c.Name == sidecarContainerName {
+			return true
+		}
+	}
 	return false
 }
 
@@ -305,6 +404,10 @@ func getConfig(annotations map[string]string) string {
 	return getStringAnnotation(annotations, daprConfigKey)
 }
 
+func getSidecarInjectionMode(annotations map[string]string, defaultMode string) string {
+	return getStringAnnotationOrDefault(annotations, daprSidecarInjectionModeKey, defaultMode)
+}
+
 func getProtocol(annotations map[string]string) string {
 	return getStringAnnotationOrDefault(annotations, daprAppProtocolKey, "http")
 }
@@ -353,6 +456,23 @@ func GetAppTokenSecret(annotations map[string]string) string {
 	return getStringAnnotationOrDefault(annotations, daprAppTokenSecret, "")
 }
 
+// previousTokensEnvVar returns the environment variable of the previous tokens of the given secret, if any.
+func previousTokensEnvVar(name, secret string) corev1.EnvVar {
+	optional := true
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				Key: "previous-tokens",
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: secret,
+				},
+				Optional: &optional,
+			},
+		},
+	}
+}
+
 func getMaxRequestBodySize(annotations map[string]string) (int32, error) {
 	return getInt32Annotation(annotations, daprMaxRequestBodySize)
 }
@@ -373,6 +493,73 @@ func HTTPStreamRequestBodyEnabled(annotations map[string]string) bool {
 	return getBoolAnnotationOrDefault(annotations, daprHTTPStreamRequestBody, defaultDaprHTTPStreamRequestBody)
 }
 
+func shutdownOnAppExitEnabled(annotations map[string]string) bool {
+	return getBoolAnnotationOrDefault(annotations, daprShutdownOnAppExitKey, false)
+}
+
+// isBatchPod returns true if the pod is run to completion by a Job, an Argo Workflow or a Tekton TaskRun.
+func isBatchPod(pod *corev1.Pod) bool {
+	for _, owner := range pod.OwnerReferences {
+		group := strings.SplitN(owner.APIVersion, "/", 2)[0]
+		switch {
+		case owner.Kind == "Job" && group == "batch",
+			owner.Kind == "Workflow" && group == "argoproj.io",
+			owner.Kind == "TaskRun" && group == "tekton.dev":
+			return true
+		}
+	}
+	return false
+}
+
+func appHealthCheckEnabled(annotations map[string]string) bool {
+	return getBoolAnnotationOrDefault(annotations, daprEnableAppHealthCheck, false)
+}
+
+func getAppHealthCheckArgs(annotations map[string]string) []string {
+	return []string{
+		"--enable-app-health-check",
+		"--app-health-check-path", getStringAnnotationOrDefault(annotations, daprAppHealthCheckPath, defaultAppHealthCheckPath),
+		"--app-health-probe-interval", fmt.Sprintf("%v", getInt32AnnotationOrDefault(annotations, daprAppHealthProbeInterval, defaultAppHealthProbeInterval)),
+		"--app-health-probe-timeout", fmt.Sprintf("%v", getInt32AnnotationOrDefault(annotations, daprAppHealthProbeTimeout, defaultAppHealthProbeTimeout)),
+		"--app-health-threshold", fmt.Sprintf("%v", getInt32AnnotationOrDefault(annotations, daprAppHealthThreshold, defaultAppHealthThreshold)),
+	}
+}
+
+func getAppConnectionPoolArgs(annotations map[string]string) []string {
+	args := []string{}
+	for _, a := range []struct {
+		annotation string
+		flag       string
+	}{
+		{daprAppMaxConns, "--app-max-conns"},
+		{daprAppMaxIdleConnDuration, "--app-max-idle-conn-duration"},
+		{daprAppKeepAliveInterval, "--app-keepalive-interval"},
+	} {
+		if value := getInt32AnnotationOrDefault(annotations, a.annotation, 0); value > 0 {
+			args = append(args, a.flag, fmt.Sprintf("%v", value))
+		}
+	}
+	return args
+}
+
+// getServerTLSArgs returns the flags of the TLS modes of the metrics and profile servers. The servers present the
+// workload certificate of the sidecar.
+func getServerTLSArgs(annotations map[string]string) []string {
+	args := []string{}
+	for _, a := range []struct {
+		annotation string
+		flag       string
+	}{
+		{daprMetricsTLSModeKey, "--metrics-tls-mode"},
+		{daprProfileTLSModeKey, "--profile-tls-mode"},
+	} {
+		if value := getStringAnnotation(annotations, a.annotation); value != "" {
+			args = append(args, a.flag, value)
+		}
+	}
+	return args
+}
+
 func getBoolAnnotationOrDefault(annotations map[string]string, key string, defaultValue bool) bool {
 	enabled, ok := annotations[key]
 	if !ok {
@@ -496,6 +683,20 @@ func getServiceAddress(name, namespace, clusterDomain string, port int) string {
 	return fmt.Sprintf("%s.%s.svc.%s:%d", name, namespace, clusterDomain, port)
 }
 
+// getPlacementAddress returns the placement addresses passed to the sidecar. The addresses of the services of the
+// placement shards are separated by semicolons.
+func getPlacementAddress(namespace, clusterDomain string, shardCount int) string {
+	if shardCount <= 1 {
+		return getServiceAddress(placementService, namespace, clusterDomain, placementServicePort)
+	}
+
+	shards := make([]string, 0, shardCount)
+	for i := 0; i < shardCount; i++ {
+		shards = append(shards, getServiceAddress(fmt.Sprintf("%s-shard-%d", placementService, i), namespace, clusterDomain, placementServicePort))
+	}
+	return strings.Join(shards, ";")
+}
+
 func getPullPolicy(pullPolicy string) corev1.PullPolicy {
 	switch pullPolicy {
 	case "Always":
@@ -509,6 +710,23 @@ func getPullPolicy(pullPolicy string) corev1.PullPolicy {
 	}
 }
 
+// getZoneEnvVar returns the zone the sidecar runs in, used to prefer invoking instances of the target app in the same
+// zone. The zone is taken from the zone annotation or else from the topology label of the pod, which Kubernetes
+// copies from the node the pod is scheduled on when the PodTopologyLabelsAdmission feature is enabled.
+func getZoneEnvVar(annotations map[string]string) corev1.EnvVar {
+	if zone := getStringAnnotation(annotations, daprZoneKey); zone != "" {
+		return corev1.EnvVar{Name: env.Zone, Value: zone}
+	}
+	return corev1.EnvVar{
+		Name: env.Zone,
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: "metadata.labels['" + corev1.LabelTopologyZone + "']",
+			},
+		},
+	}
+}
+
 func getSidecarContainer(annotations map[string]string, id, daprSidecarImage, imagePullPolicy, namespace, controlPlaneAddress, placementServiceAddress string, tokenVolumeMount *corev1.VolumeMount, trustAnchors, certChain, certKey, sentryAddress string, mtlsEnabled bool, identity string) (*corev1.Container, error) {
 	appPort, err := getAppPort(annotations)
 	if err != nil {
@@ -519,6 +737,16 @@ func getSidecarContainer(annotations map[string]string, id, daprSidecarImage, im
 		appPortStr = fmt.Sprintf("%v", appPort)
 	}
 
+	socketPath, err := getUnixDomainSocketPath(annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	componentContainers, componentsFolder, err := getPluggableComponents(annotations)
+	if err != nil {
+		return nil, err
+	}
+
 	metricsEnabled := getEnableMetrics(annotations)
 	metricsPort := getMetricsPort(annotations)
 	maxConcurrency, err := getMaxConcurrency(annotations)
@@ -662,6 +890,15 @@ func getSidecarContainer(annotations map[string]string, id, daprSidecarImage, im
 		}
 	}
 
+	if socketPath != "" {
+		c.VolumeMounts = append(c.VolumeMounts, getUnixDomainSocketVolumeMount(socketPath))
+		c.Args = append(c.Args, "--unix-domain-socket", socketPath, "--app-unix-domain-socket", getAppSocket(socketPath, id))
+	}
+
+	if len(componentContainers) > 0 {
+		c.VolumeMounts = append(c.VolumeMounts, getPluggableComponentsVolumeMount(componentsFolder))
+	}
+
 	if logAsJSONEnabled(annotations) {
 		c.Args = append(c.Args, "--log-as-json")
 	}
@@ -699,6 +936,21 @@ func getSidecarContainer(annotations map[string]string, id, daprSidecarImage, im
 		c.Args = append(c.Args, "--http-stream-request-body")
 	}
 
+	if appHealthCheckEnabled(annotations) {
+		c.Args = append(c.Args, getAppHealthCheckArgs(annotations)...)
+	}
+
+	if shutdownOnAppExitEnabled(annotations) {
+		c.Args = append(c.Args, "--shutdown-on-app-exit")
+	}
+
+	c.Args = append(c.Args, getAppConnectionPoolArgs(annotations)...)
+	c.Args = append(c.Args, getServerTLSArgs(annotations)...)
+
+	if concurrency := getInt32AnnotationOrDefault(annotations, daprComponentsInitConcurrency, 0); concurrency > 0 {
+		c.Args = append(c.Args, "--components-init-concurrency", fmt.Sprintf("%v", concurrency))
+	}
+
 	secret := getAPITokenSecret(annotations)
 	if secret != "" {
 		c.Env = append(c.Env, corev1.EnvVar{
@@ -729,6 +981,36 @@ func getSidecarContainer(annotations map[string]string, id, daprSidecarImage, im
 		})
 	}
 
+	// The previous tokens, which remain valid while the tokens are rotated, are read from the optional
+	// "previous-tokens" key of the secrets of the tokens.
+	if secret != "" {
+		c.Env = append(c.Env, previousTokensEnvVar(auth.PreviousAPITokensEnvVar, secret))
+	}
+	if appSecret != "" {
+		c.Env = append(c.Env, previousTokensEnvVar(auth.PreviousAppAPITokensEnvVar, appSecret))
+	}
+
+	// The node name is used to prefer invoking instances of the target app running on the same node.
+	// The pod name identifies the sidecar in the component statuses reported to the operator.
+	c.Env = append(c.Env, corev1.EnvVar{
+		Name: env.NodeName,
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: "spec.nodeName",
+			},
+		},
+	},
+		corev1.EnvVar{
+			Name: env.PodName,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "metadata.name",
+				},
+			},
+		})
+
+	c.Env = append(c.Env, getZoneEnvVar(annotations))
+
 	resources, err := getResourceRequirements(annotations)
 	if err != nil {
 		log.Warnf("couldn't set container resource requirements: %s. using defaults", err)
@@ -137,6 +137,10 @@ func TestGetSideCarContainer(t *testing.T) {
 		assert.Equal(t, "secret", container.Env[5].ValueFrom.SecretKeyRef.Name)
 		// DAPR_APP_TOKEN
 		assert.Equal(t, "appsecret", container.Env[6].ValueFrom.SecretKeyRef.Name)
+		// DAPR_API_TOKEN_PREVIOUS
+		assert.Equal(t, "DAPR_API_TOKEN_PREVIOUS", container.Env[7].Name)
+		assert.Equal(t, "secret", container.Env[7].ValueFrom.SecretKeyRef.Name)
+		assert.True(t, *container.Env[7].ValueFrom.SecretKeyRef.Optional)
 		// default image
 		assert.Equal(t, "darpio/dapr", container.Image)
 		assert.EqualValues(t, expectedArgs, container.Args)
@@ -306,6 +310,123 @@ func TestGetSideCarContainer(t *testing.T) {
 
 		assert.Equal(t, image, container.Image)
 	})
+
+	t.Run("get sidecar container with app health checks", func(t *testing.T) {
+		annotations := map[string]string{
+			daprEnableAppHealthCheck:   trueString,
+			daprAppHealthCheckPath:     "/ready",
+			daprAppHealthProbeInterval: "10",
+		}
+
+		container, _ := getSidecarContainer(annotations, "app_id", "darpio/dapr", "Always", "dapr-system", "controlplane:9000", "placement:50000", nil, "", "", "", "sentry:50000", false, "pod_identity")
+
+		expectedArgs := []string{
+			"--enable-app-health-check",
+			"--app-health-check-path", "/ready",
+			"--app-health-probe-interval", "10",
+			"--app-health-probe-timeout", "500",
+			"--app-health-threshold", "3",
+		}
+		assert.Equal(t, expectedArgs, container.Args[len(container.Args)-len(expectedArgs):])
+	})
+
+	t.Run("get sidecar container with app connection pool", func(t *testing.T) {
+		annotations := map[string]string{
+			daprAppMaxConns:          "512",
+			daprAppKeepAliveInterval: "30",
+		}
+
+		container, _ := getSidecarContainer(annotations, "app_id", "darpio/dapr", "Always", "dapr-system", "controlplane:9000", "placement:50000", nil, "", "", "", "sentry:50000", false, "pod_identity")
+
+		expectedArgs := []string{
+			"--app-max-conns", "512",
+			"--app-keepalive-interval", "30",
+		}
+		assert.Equal(t, expectedArgs, container.Args[len(container.Args)-len(expectedArgs):])
+	})
+
+	t.Run("get sidecar container with components init concurrency", func(t *testing.T) {
+		annotations := map[string]string{
+			daprComponentsInitConcurrency: "4",
+		}
+
+		container, _ := getSidecarContainer(annotations, "app_id", "darpio/dapr", "Always", "dapr-system", "controlplane:9000", "placement:50000", nil, "", "", "", "sentry:50000", false, "pod_identity")
+
+		expectedArgs := []string{"--components-init-concurrency", "4"}
+		assert.Equal(t, expectedArgs, container.Args[len(container.Args)-len(expectedArgs):])
+	})
+
+	t.Run("get sidecar container with metrics and profile TLS", func(t *testing.T) {
+		annotations := map[string]string{
+			daprEnableProfilingKey: trueString,
+			daprMetricsTLSModeKey:  "mtls",
+			daprProfileTLSModeKey:  "tls",
+		}
+
+		container, _ := getSidecarContainer(annotations, "app_id", "darpio/dapr", "Always", "dapr-system", "controlplane:9000", "placement:50000", nil, "", "", "", "sentry:50000", true, "pod_identity")
+
+		expectedArgs := []string{
+			"--metrics-tls-mode", "mtls",
+			"--profile-tls-mode", "tls",
+		}
+		assert.Equal(t, expectedArgs, container.Args[len(container.Args)-len(expectedArgs):])
+	})
+
+	t.Run("get sidecar container with unix domain socket", func(t *testing.T) {
+		annotations := map[string]string{
+			daprUnixDomainSocketPath: "/tmp/dapr",
+		}
+
+		container, err := getSidecarContainer(annotations, "app_id", "darpio/dapr", "Always", "dapr-system", "controlplane:9000", "placement:50000", nil, "", "", "", "sentry:50000", false, "pod_identity")
+		assert.NoError(t, err)
+
+		expectedArgs := []string{
+			"--unix-domain-socket", "/tmp/dapr",
+			"--app-unix-domain-socket", "/tmp/dapr/app-app_id.socket",
+		}
+		assert.Equal(t, expectedArgs, container.Args[len(container.Args)-len(expectedArgs):])
+		assert.Equal(t, []corev1.VolumeMount{{Name: unixDomainSocketVolumeName, MountPath: "/tmp/dapr"}}, container.VolumeMounts)
+	})
+
+	t.Run("get sidecar container with pluggable components", func(t *testing.T) {
+		annotations := map[string]string{
+			daprPluggableComponentsKey: "store,pubsub",
+		}
+
+		container, err := getSidecarContainer(annotations, "app_id", "darpio/dapr", "Always", "dapr-system", "controlplane:9000", "placement:50000", nil, "", "", "", "sentry:50000", false, "pod_identity")
+		assert.NoError(t, err)
+
+		assert.Equal(t, []corev1.VolumeMount{{Name: pluggableComponentsVolumeName, MountPath: defaultPluggableComponentsFolder}}, container.VolumeMounts)
+		for _, env := range container.Env {
+			assert.NotEqual(t, pluggableComponentsSocketsFolderName, env.Name)
+		}
+	})
+
+	t.Run("get sidecar container with zone", func(t *testing.T) {
+		container, err := getSidecarContainer(map[string]string{}, "app_id", "darpio/dapr", "Always", "dapr-system", "controlplane:9000", "placement:50000", nil, "", "", "", "sentry:50000", false, "pod_identity")
+		assert.NoError(t, err)
+		assert.Contains(t, container.Env, corev1.EnvVar{
+			Name: "DAPR_ZONE",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "metadata.labels['topology.kubernetes.io/zone']",
+				},
+			},
+		})
+
+		container, err = getSidecarContainer(map[string]string{daprZoneKey: "zone1"}, "app_id", "darpio/dapr", "Always", "dapr-system", "controlplane:9000", "placement:50000", nil, "", "", "", "sentry:50000", false, "pod_identity")
+		assert.NoError(t, err)
+		assert.Contains(t, container.Env, corev1.EnvVar{Name: "DAPR_ZONE", Value: "zone1"})
+	})
+
+	t.Run("relative unix domain socket path", func(t *testing.T) {
+		annotations := map[string]string{
+			daprUnixDomainSocketPath: "tmp/dapr",
+		}
+
+		_, err := getSidecarContainer(annotations, "app_id", "darpio/dapr", "Always", "dapr-system", "controlplane:9000", "placement:50000", nil, "", "", "", "sentry:50000", false, "pod_identity")
+		assert.Error(t, err)
+	})
 }
 
 func TestImagePullPolicy(t *testing.T) {
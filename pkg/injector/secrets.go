@@ -0,0 +1,215 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package injector
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	scheme "github.com/dapr/dapr/pkg/client/clientset/versioned"
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/dapr/utils"
+)
+
+const (
+	kubernetesSecretStore     = "kubernetes"
+	kubernetesSecretStoreType = "secretstores.kubernetes"
+	secretVolumeNamePrefix    = "dapr-secret-"
+	volumesPath               = "/spec/volumes"
+)
+
+// getSecretsFromStore parses the dapr.io/env-from-secret-store annotation, formatted as
+// <secret store>:<secret>[,<secret>...].
+func getSecretsFromStore(annotations map[string]string) (string, []string, error) {
+	val := getStringAnnotation(annotations, daprEnvFromSecretStoreKey)
+	if val == "" {
+		return "", nil, nil
+	}
+
+	i := strings.Index(val, ":")
+	if i <= 0 {
+		return "", nil, errors.Errorf("invalid %s annotation %q: expected <secret store>:<secret>[,<secret>...]", daprEnvFromSecretStoreKey, val)
+	}
+	storeName := strings.TrimSpace(val[:i])
+	var secrets []string
+	for _, s := range strings.Split(val[i+1:], ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			secrets = append(secrets, s)
+		}
+	}
+	if len(secrets) == 0 {
+		return "", nil, errors.Errorf("invalid %s annotation %q: no secrets", daprEnvFromSecretStoreKey, val)
+	}
+	return storeName, secrets, nil
+}
+
+// getSecretPatchOperations materializes the secrets of the dapr.io/env-from-secret-store annotation in the app
+// containers before they start, as environment variables or, if dapr.io/secrets-mount-path is set, as files.
+// The secrets are referenced rather than read, so only the Kubernetes secret store is supported.
+func getSecretPatchOperations(pod corev1.Pod, namespace, appID string, daprClient scheme.Interface) ([]PatchOperation, error) {
+	storeName, secrets, err := getSecretsFromStore(pod.Annotations)
+	if err != nil || len(secrets) == 0 {
+		return nil, err
+	}
+
+	if err = validateKubernetesSecretStore(daprClient, namespace, storeName, appID); err != nil {
+		return nil, err
+	}
+	if err = validateSecretsAllowed(daprClient, namespace, getConfig(pod.Annotations), storeName, secrets); err != nil {
+		return nil, err
+	}
+
+	mountPath := getStringAnnotation(pod.Annotations, daprSecretsMountPathKey)
+	if mountPath == "" {
+		return getSecretEnvPatchOperations(pod.Spec.Containers, secrets), nil
+	}
+	return getSecretVolumePatchOperations(pod, secrets, mountPath), nil
+}
+
+// validateKubernetesSecretStore checks that storeName is a Kubernetes secret store accessible to the app.
+// The built-in kubernetes store doesn't need to be declared.
+func validateKubernetesSecretStore(daprClient scheme.Interface, namespace, storeName, appID string) error {
+	component, err := daprClient.ComponentsV1alpha1().Components(namespace).Get(storeName, meta_v1.GetOptions{})
+	if apierrors.IsNotFound(err) && storeName == kubernetesSecretStore {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "error getting secret store %s", storeName)
+	}
+
+	if component.Spec.Type != kubernetesSecretStoreType {
+		return errors.Errorf("secret store %s of type %s can't be materialized at injection, only %s is supported", storeName, component.Spec.Type, kubernetesSecretStoreType)
+	}
+	if len(component.Scopes) > 0 && !utils.StringSliceContains(appID, component.Scopes) {
+		return errors.Errorf("secret store %s is not scoped to app %s", storeName, appID)
+	}
+	return nil
+}
+
+// validateSecretsAllowed applies the secret scopes of the app configuration to the materialized secrets.
+func validateSecretsAllowed(daprClient scheme.Interface, namespace, configName, storeName string, secrets []string) error {
+	if configName == "" {
+		return nil
+	}
+
+	resource, err := daprClient.ConfigurationV1alpha1().Configurations(namespace).Get(configName, meta_v1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "error getting configuration %s", configName)
+	}
+	b, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	conf, err := config.ParseKubernetesConfiguration(b)
+	if err != nil {
+		return errors.Wrapf(err, "error parsing configuration %s", configName)
+	}
+
+	for _, scope := range conf.Spec.Secrets.Scopes {
+		if scope.StoreName != storeName {
+			continue
+		}
+		if !scope.IsOperationAllowed(config.SecretOperationGet) {
+			return errors.Errorf("access denied by policy to get secrets from %s", storeName)
+		}
+		for _, s := range secrets {
+			if !scope.IsSecretAllowed(s) {
+				return errors.Errorf("access denied by policy to get %s from %s", s, storeName)
+			}
+		}
+	}
+	return nil
+}
+
+// getSecretEnvPatchOperations adds the keys of the secrets to the environment variables of containers.
+func getSecretEnvPatchOperations(containers []corev1.Container, secrets []string) []PatchOperation {
+	envFrom := make([]interface{}, 0, len(secrets))
+	for _, s := range secrets {
+		envFrom = append(envFrom, corev1.EnvFromSource{
+			SecretRef: &corev1.SecretEnvSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: s},
+			},
+		})
+	}
+
+	var patchOps []PatchOperation
+	for i, container := range containers {
+		envFromPath := fmt.Sprintf("%s/%d/envFrom", containersPath, i)
+		patchOps = append(patchOps, getArrayPatchOperations(len(container.EnvFrom) == 0, envFromPath, envFrom)...)
+	}
+	return patchOps
+}
+
+// getSecretVolumePatchOperations mounts each secret in containers as a directory of mountPath with a file per key.
+func getSecretVolumePatchOperations(pod corev1.Pod, secrets []string, mountPath string) []PatchOperation {
+	taken := make(map[string]bool, len(pod.Spec.Volumes)+len(secrets))
+	for _, v := range pod.Spec.Volumes {
+		taken[v.Name] = true
+	}
+
+	volumes := make([]interface{}, 0, len(secrets))
+	mounts := make([]interface{}, 0, len(secrets))
+	for _, s := range secrets {
+		name := getUniqueVolumeName(taken, secretVolumeNamePrefix+s)
+		taken[name] = true
+		volumes = append(volumes, corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: s},
+			},
+		})
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      name,
+			MountPath: path.Join(mountPath, s),
+			ReadOnly:  true,
+		})
+	}
+
+	patchOps := getArrayPatchOperations(len(pod.Spec.Volumes) == 0, volumesPath, volumes)
+	for i, container := range pod.Spec.Containers {
+		mountsPath := fmt.Sprintf("%s/%d/volumeMounts", containersPath, i)
+		patchOps = append(patchOps, getArrayPatchOperations(len(container.VolumeMounts) == 0, mountsPath, mounts)...)
+	}
+	return patchOps
+}
+
+// getUniqueVolumeName returns name, suffixed with a number if a volume of the pod is already named so, since a
+// volume of the user with the same name would be mounted instead.
+func getUniqueVolumeName(taken map[string]bool, name string) string {
+	unique := name
+	for i := 1; taken[unique]; i++ {
+		unique = fmt.Sprintf("%s-%d", name, i)
+	}
+	return unique
+}
+
+// getArrayPatchOperations adds values to the array at arrayPath, which is created if empty.
+func getArrayPatchOperations(empty bool, arrayPath string, values []interface{}) []PatchOperation {
+	if empty {
+		return []PatchOperation{{Op: "add", Path: arrayPath, Value: values}}
+	}
+
+	patchOps := make([]PatchOperation, 0, len(values))
+	for _, value := range values {
+		patchOps = append(patchOps, PatchOperation{Op: "add", Path: arrayPath + "/-", Value: value})
+	}
+	return patchOps
+}
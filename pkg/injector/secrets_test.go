@@ -0,0 +1,199 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package injector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	components_v1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	configuration_v1alpha1 "github.com/dapr/dapr/pkg/apis/configuration/v1alpha1"
+	"github.com/dapr/dapr/pkg/client/clientset/versioned/fake"
+)
+
+// newFakeDaprClient returns a fake clientset holding the given components and configurations. They are stored under
+// the resources the clients of the clientset look them up, e.g. components.dapr.io, rather than under the group of
+// their scheme, dapr.io, which fake.NewSimpleClientset uses.
+func newFakeDaprClient(t *testing.T, objects ...runtime.Object) *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	for _, obj := range objects {
+		var gvr schema.GroupVersionResource
+		switch obj.(type) {
+		case *components_v1alpha1.Component:
+			gvr = schema.GroupVersionResource{Group: "components.dapr.io", Version: "v1alpha1", Resource: "components"}
+		case *configuration_v1alpha1.Configuration:
+			gvr = schema.GroupVersionResource{Group: "configuration.dapr.io", Version: "v1alpha1", Resource: "configurations"}
+		default:
+			t.Fatalf("unsupported object %T", obj)
+		}
+		require.NoError(t, client.Tracker().Create(gvr, obj, obj.(meta_v1.Object).GetNamespace()))
+	}
+	return client
+}
+
+func TestGetSecretsFromStore(t *testing.T) {
+	testCases := []struct {
+		testName   string
+		annotation string
+		expStore   string
+		expSecrets []string
+		expErr     bool
+	}{
+		{"no annotation", "", "", nil, false},
+		{"secrets", "kubernetes: db-creds, api-keys", "kubernetes", []string{"db-creds", "api-keys"}, false},
+		{"no store", ":db-creds", "", nil, true},
+		{"no secrets", "kubernetes:", "", nil, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.testName, func(t *testing.T) {
+			store, secrets, err := getSecretsFromStore(map[string]string{daprEnvFromSecretStoreKey: tc.annotation})
+			assert.Equal(t, tc.expErr, err != nil)
+			assert.Equal(t, tc.expStore, store)
+			assert.Equal(t, tc.expSecrets, secrets)
+		})
+	}
+}
+
+func TestGetSecretPatchOperations(t *testing.T) {
+	daprClient := newFakeDaprClient(t,
+		&components_v1alpha1.Component{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "k8s", Namespace: "default"},
+			Spec:       components_v1alpha1.ComponentSpec{Type: kubernetesSecretStoreType},
+			Scopes:     []string{"app1"},
+		},
+		&components_v1alpha1.Component{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "vault", Namespace: "default"},
+			Spec:       components_v1alpha1.ComponentSpec{Type: "secretstores.hashicorp.vault"},
+		},
+		&configuration_v1alpha1.Configuration{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "restricted", Namespace: "default"},
+			Spec: configuration_v1alpha1.ConfigurationSpec{
+				Secrets: configuration_v1alpha1.SecretsSpec{
+					Scopes: []configuration_v1alpha1.SecretsScope{
+						{StoreName: "kubernetes", DefaultAccess: "deny", AllowedSecrets: []string{"db-creds"}},
+					},
+				},
+			},
+		},
+	)
+
+	pod := func(annotations map[string]string, containers ...corev1.Container) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: meta_v1.ObjectMeta{Annotations: annotations},
+			Spec:       corev1.PodSpec{Containers: containers},
+		}
+	}
+	secretRef := func(name string) corev1.EnvFromSource {
+		return corev1.EnvFromSource{
+			SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: name}},
+		}
+	}
+
+	t.Run("no annotation", func(t *testing.T) {
+		ops, err := getSecretPatchOperations(pod(nil, corev1.Container{}), "default", "app1", daprClient)
+		assert.NoError(t, err)
+		assert.Empty(t, ops)
+	})
+
+	t.Run("environment variables from the built-in store", func(t *testing.T) {
+		p := pod(
+			map[string]string{daprEnvFromSecretStoreKey: "kubernetes:db-creds,api-keys"},
+			corev1.Container{},
+			corev1.Container{EnvFrom: []corev1.EnvFromSource{secretRef("existing")}},
+		)
+		ops, err := getSecretPatchOperations(p, "default", "app1", daprClient)
+		assert.NoError(t, err)
+		assert.Equal(t, []PatchOperation{
+			{Op: "add", Path: "/spec/containers/0/envFrom", Value: []interface{}{secretRef("db-creds"), secretRef("api-keys")}},
+			{Op: "add", Path: "/spec/containers/1/envFrom/-", Value: secretRef("db-creds")},
+			{Op: "add", Path: "/spec/containers/1/envFrom/-", Value: secretRef("api-keys")},
+		}, ops)
+	})
+
+	t.Run("files from a declared store", func(t *testing.T) {
+		p := pod(
+			map[string]string{daprEnvFromSecretStoreKey: "k8s:db-creds", daprSecretsMountPathKey: "/secrets"},
+			corev1.Container{},
+		)
+		ops, err := getSecretPatchOperations(p, "default", "app1", daprClient)
+		assert.NoError(t, err)
+		assert.Equal(t, []PatchOperation{
+			{Op: "add", Path: volumesPath, Value: []interface{}{corev1.Volume{
+				Name:         "dapr-secret-db-creds",
+				VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "db-creds"}},
+			}}},
+			{Op: "add", Path: "/spec/containers/0/volumeMounts", Value: []interface{}{corev1.VolumeMount{
+				Name:      "dapr-secret-db-creds",
+				MountPath: "/secrets/db-creds",
+				ReadOnly:  true,
+			}}},
+		}, ops)
+	})
+
+	t.Run("volumes of the user are not overridden", func(t *testing.T) {
+		p := pod(
+			map[string]string{daprEnvFromSecretStoreKey: "k8s:db-creds", daprSecretsMountPathKey: "/secrets"},
+			corev1.Container{},
+		)
+		p.Spec.Volumes = []corev1.Volume{{Name: "dapr-secret-db-creds"}}
+		ops, err := getSecretPatchOperations(p, "default", "app1", daprClient)
+		assert.NoError(t, err)
+		assert.Equal(t, []PatchOperation{
+			{Op: "add", Path: volumesPath + "/-", Value: corev1.Volume{
+				Name:         "dapr-secret-db-creds-1",
+				VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "db-creds"}},
+			}},
+			{Op: "add", Path: "/spec/containers/0/volumeMounts", Value: []interface{}{corev1.VolumeMount{
+				Name:      "dapr-secret-db-creds-1",
+				MountPath: "/secrets/db-creds",
+				ReadOnly:  true,
+			}}},
+		}, ops)
+	})
+
+	t.Run("store not scoped to the app", func(t *testing.T) {
+		p := pod(map[string]string{daprEnvFromSecretStoreKey: "k8s:db-creds"}, corev1.Container{})
+		_, err := getSecretPatchOperations(p, "default", "app2", daprClient)
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported store type", func(t *testing.T) {
+		p := pod(map[string]string{daprEnvFromSecretStoreKey: "vault:db-creds"}, corev1.Container{})
+		_, err := getSecretPatchOperations(p, "default", "app1", daprClient)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing store", func(t *testing.T) {
+		p := pod(map[string]string{daprEnvFromSecretStoreKey: "missing:db-creds"}, corev1.Container{})
+		_, err := getSecretPatchOperations(p, "default", "app1", daprClient)
+		assert.Error(t, err)
+	})
+
+	t.Run("secrets scoped by the configuration", func(t *testing.T) {
+		p := pod(map[string]string{daprEnvFromSecretStoreKey: "kubernetes:db-creds", daprConfigKey: "restricted"}, corev1.Container{})
+		_, err := getSecretPatchOperations(p, "default", "app1", daprClient)
+		assert.NoError(t, err)
+
+		p = pod(map[string]string{daprEnvFromSecretStoreKey: "kubernetes:db-creds,api-keys", daprConfigKey: "restricted"}, corev1.Container{})
+		_, err = getSecretPatchOperations(p, "default", "app1", daprClient)
+		assert.Error(t, err)
+	})
+}
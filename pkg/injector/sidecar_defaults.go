@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package injector
+
+import (
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configuration_v1alpha1 "github.com/dapr/dapr/pkg/apis/configuration/v1alpha1"
+	scheme "github.com/dapr/dapr/pkg/client/clientset/versioned"
+	"github.com/dapr/dapr/utils"
+)
+
+// sidecarDefaultsConfig is the name of the configuration holding the defaults of the sidecars injected in the pods
+// of its namespace.
+const sidecarDefaultsConfig = "dapr-sidecar-defaults"
+
+// getSidecarDefaults returns the sidecar defaults of a namespace, or nil if the namespace doesn't define them.
+func getSidecarDefaults(daprClient scheme.Interface, namespace string) *configuration_v1alpha1.SidecarSpec {
+	resource, err := daprClient.ConfigurationV1alpha1().Configurations(namespace).Get(sidecarDefaultsConfig, meta_v1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		log.Warnf("failed to get the sidecar defaults of namespace %s, using the annotations only: %s", namespace, err)
+		return nil
+	}
+	return &resource.Spec.SidecarSpec
+}
+
+// mergeSidecarDefaults returns the annotations of a pod merged with the sidecar defaults of its namespace.
+// The annotations take precedence over the defaults, and the environment variables are merged by name.
+func mergeSidecarDefaults(annotations map[string]string, defaults *configuration_v1alpha1.SidecarSpec) map[string]string {
+	if defaults == nil {
+		return annotations
+	}
+
+	merged := make(map[string]string, len(annotations))
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	setDefault := func(key, value string) {
+		if value != "" && merged[key] == "" {
+			merged[key] = value
+		}
+	}
+	setDefault(daprImage, defaults.Image)
+	setDefault(daprLogLevel, defaults.LogLevel)
+	setDefault(daprCPURequestKey, defaults.Resources.CPURequest)
+	setDefault(daprCPULimitKey, defaults.Resources.CPULimit)
+	setDefault(daprMemoryRequestKey, defaults.Resources.MemoryRequest)
+	setDefault(daprMemoryLimitKey, defaults.Resources.MemoryLimit)
+
+	if len(defaults.Env) > 0 {
+		envs := []string{}
+		if env := strings.TrimSpace(merged[daprEnvKey]); env != "" {
+			envs = append(envs, env)
+		}
+		names := map[string]bool{}
+		for _, e := range utils.ParseEnvString(merged[daprEnvKey]) {
+			names[e.Name] = true
+		}
+		for _, e := range defaults.Env {
+			if !names[e.Name] {
+				envs = append(envs, fmt.Sprintf("%s=%s", e.Name, e.Value))
+			}
+		}
+		merged[daprEnvKey] = strings.Join(envs, ",")
+	}
+	return merged
+}
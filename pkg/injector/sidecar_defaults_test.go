@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package injector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configuration_v1alpha1 "github.com/dapr/dapr/pkg/apis/configuration/v1alpha1"
+)
+
+func TestGetSidecarDefaults(t *testing.T) {
+	daprClient := newFakeDaprClient(t, &configuration_v1alpha1.Configuration{
+		ObjectMeta: meta_v1.ObjectMeta{Name: sidecarDefaultsConfig, Namespace: "team-a"},
+		Spec: configuration_v1alpha1.ConfigurationSpec{
+			SidecarSpec: configuration_v1alpha1.SidecarSpec{Image: "daprio/daprd:edge"},
+		},
+	})
+
+	defaults := getSidecarDefaults(daprClient, "team-a")
+	if assert.NotNil(t, defaults) {
+		assert.Equal(t, "daprio/daprd:edge", defaults.Image)
+	}
+	assert.Nil(t, getSidecarDefaults(daprClient, "team-b"))
+}
+
+func TestMergeSidecarDefaults(t *testing.T) {
+	defaults := &configuration_v1alpha1.SidecarSpec{
+		Image:    "daprio/daprd:edge",
+		LogLevel: "debug",
+		Env: []configuration_v1alpha1.SidecarEnvVar{
+			{Name: "HTTP_PROXY", Value: "http://proxy:3128"},
+			{Name: "GOMAXPROCS", Value: "2"},
+		},
+		Resources: configuration_v1alpha1.SidecarResourcesSpec{
+			CPURequest:    "100m",
+			MemoryRequest: "64Mi",
+			MemoryLimit:   "256Mi",
+		},
+	}
+
+	t.Run("no defaults", func(t *testing.T) {
+		annotations := map[string]string{daprLogLevel: "warn"}
+		assert.Equal(t, annotations, mergeSidecarDefaults(annotations, nil))
+	})
+
+	t.Run("defaults apply to unset annotations", func(t *testing.T) {
+		merged := mergeSidecarDefaults(map[string]string{}, defaults)
+		assert.Equal(t, map[string]string{
+			daprImage:            "daprio/daprd:edge",
+			daprLogLevel:         "debug",
+			daprCPURequestKey:    "100m",
+			daprMemoryRequestKey: "64Mi",
+			daprMemoryLimitKey:   "256Mi",
+			daprEnvKey:           "HTTP_PROXY=http://proxy:3128,GOMAXPROCS=2",
+		}, merged)
+	})
+
+	t.Run("annotations take precedence", func(t *testing.T) {
+		annotations := map[string]string{
+			daprLogLevel:       "warn",
+			daprMemoryLimitKey: "1Gi",
+			daprEnvKey:         "GOMAXPROCS=4",
+		}
+		merged := mergeSidecarDefaults(annotations, defaults)
+		assert.Equal(t, "warn", merged[daprLogLevel])
+		assert.Equal(t, "1Gi", merged[daprMemoryLimitKey])
+		assert.Equal(t, "daprio/daprd:edge", merged[daprImage])
+		assert.Equal(t, "GOMAXPROCS=4,HTTP_PROXY=http://proxy:3128", merged[daprEnvKey])
+		// the annotations of the pod are left unchanged.
+		assert.Len(t, annotations, 3)
+	})
+}
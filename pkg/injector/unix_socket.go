@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package injector
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	unixDomainSocketVolumeName      = "dapr-unix-domain-socket"
+	userContainerDaprHTTPSocketName = "DAPR_HTTP_SOCKET"
+	userContainerDaprGRPCSocketName = "DAPR_GRPC_SOCKET"
+	userContainerAppSocketName      = "APP_SOCKET"
+)
+
+// getUnixDomainSocketPath returns the directory of the dapr.io/unix-domain-socket-path annotation, which the app
+// and the sidecar share to communicate over Unix domain sockets. It is empty if the annotation isn't set.
+func getUnixDomainSocketPath(annotations map[string]string) (string, error) {
+	socketPath := getStringAnnotation(annotations, daprUnixDomainSocketPath)
+	if socketPath != "" && !path.IsAbs(socketPath) {
+		return "", errors.Errorf("invalid %s annotation %q: the path must be absolute", daprUnixDomainSocketPath, socketPath)
+	}
+	return socketPath, nil
+}
+
+// getAppSocket returns the socket the app listens on in the socket directory.
+func getAppSocket(socketPath, appID string) string {
+	return path.Join(socketPath, fmt.Sprintf("app-%s.socket", appID))
+}
+
+func getUnixDomainSocketVolumeMount(socketPath string) corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      unixDomainSocketVolumeName,
+		MountPath: socketPath,
+	}
+}
+
+// getUnixDomainSocketEnv returns the environment variables giving the app the sockets of the Dapr APIs and the
+// socket it should listen on.
+func getUnixDomainSocketEnv(socketPath, appID string) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name:  userContainerDaprHTTPSocketName,
+			Value: path.Join(socketPath, fmt.Sprintf("dapr-%s-http.socket", appID)),
+		},
+		{
+			Name:  userContainerDaprGRPCSocketName,
+			Value: path.Join(socketPath, fmt.Sprintf("dapr-%s-grpc.socket", appID)),
+		},
+		{
+			Name:  userContainerAppSocketName,
+			Value: getAppSocket(socketPath, appID),
+		},
+	}
+}
+
+// getUnixDomainSocketPatchOperations shares an emptyDir volume mounted at socketPath between the app containers
+// and the sidecar. The volume and the mounts are added to pod too, so the patch operations computed afterwards
+// append to the arrays rather than replace them.
+func getUnixDomainSocketPatchOperations(pod *corev1.Pod, socketPath string) []PatchOperation {
+	volume := corev1.Volume{
+		Name: unixDomainSocketVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+	mount := getUnixDomainSocketVolumeMount(socketPath)
+
+	patchOps := getArrayPatchOperations(len(pod.Spec.Volumes) == 0, volumesPath, []interface{}{volume})
+	pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		mountsPath := fmt.Sprintf("%s/%d/volumeMounts", containersPath, i)
+		patchOps = append(patchOps, getArrayPatchOperations(len(container.VolumeMounts) == 0, mountsPath, []interface{}{mount})...)
+		container.VolumeMounts = append(container.VolumeMounts, mount)
+	}
+	return patchOps
+}
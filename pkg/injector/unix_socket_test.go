@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package injector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetUnixDomainSocketPath(t *testing.T) {
+	socketPath, err := getUnixDomainSocketPath(map[string]string{})
+	assert.NoError(t, err)
+	assert.Empty(t, socketPath)
+
+	socketPath, err = getUnixDomainSocketPath(map[string]string{daprUnixDomainSocketPath: "/tmp/dapr"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/tmp/dapr", socketPath)
+
+	_, err = getUnixDomainSocketPath(map[string]string{daprUnixDomainSocketPath: "tmp/dapr"})
+	assert.Error(t, err)
+}
+
+func TestGetUnixDomainSocketPatchOperations(t *testing.T) {
+	volume := corev1.Volume{
+		Name:         unixDomainSocketVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	}
+	mount := corev1.VolumeMount{Name: unixDomainSocketVolumeName, MountPath: "/tmp/dapr"}
+	existingMount := corev1.VolumeMount{Name: "data", MountPath: "/data"}
+
+	pod := corev1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{Annotations: map[string]string{daprUnixDomainSocketPath: "/tmp/dapr"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "app"},
+				{Name: "worker", VolumeMounts: []corev1.VolumeMount{existingMount}},
+			},
+		},
+	}
+
+	ops := getUnixDomainSocketPatchOperations(&pod, "/tmp/dapr")
+	assert.Equal(t, []PatchOperation{
+		{Op: "add", Path: volumesPath, Value: []interface{}{volume}},
+		{Op: "add", Path: "/spec/containers/0/volumeMounts", Value: []interface{}{mount}},
+		{Op: "add", Path: "/spec/containers/1/volumeMounts/-", Value: mount},
+	}, ops)
+
+	// The pod records the patch, so the volumes of the secrets are appended.
+	assert.Equal(t, []corev1.Volume{volume}, pod.Spec.Volumes)
+	assert.Equal(t, []corev1.VolumeMount{mount}, pod.Spec.Containers[0].VolumeMounts)
+	assert.Equal(t, []corev1.VolumeMount{existingMount, mount}, pod.Spec.Containers[1].VolumeMounts)
+}
+
+func TestGetUnixDomainSocketEnv(t *testing.T) {
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: userContainerDaprHTTPSocketName, Value: "/tmp/dapr/dapr-app1-http.socket"},
+		{Name: userContainerDaprGRPCSocketName, Value: "/tmp/dapr/dapr-app1-grpc.socket"},
+		{Name: userContainerAppSocketName, Value: "/tmp/dapr/app-app1.socket"},
+	}, getUnixDomainSocketEnv("/tmp/dapr", "app1"))
+}
+
+func TestAddDaprEnvVarsToContainersWithUnixDomainSocket(t *testing.T) {
+	socketEnv := getUnixDomainSocketEnv("/tmp/dapr", "app1")
+	ops := addDaprEnvVarsToContainers([]corev1.Container{{Name: "app"}}, socketEnv...)
+	assert.Equal(t, []PatchOperation{
+		{Op: "add", Path: "/spec/containers/0/env", Value: socketEnv},
+	}, ops)
+}
@@ -0,0 +1,524 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+
+	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	// pollInterval is the interval at which the scheduler looks for jobs due to run.
+	pollInterval = time.Second
+	// claimLease is the time a replica has to run a job it claimed before another replica can run it.
+	// It also bounds the duration of the invocation of the app.
+	claimLease = time.Minute
+
+	// defaultInitialRetryInterval and defaultMaxRetryInterval bound the backoff of failure policies which don't.
+	defaultInitialRetryInterval = time.Second
+	defaultMaxRetryInterval     = time.Minute
+)
+
+// Statuses of the pending runs of jobs.
+const (
+	StatusScheduled = "scheduled"
+	StatusRunning   = "running"
+	StatusRetrying  = "retrying"
+)
+
+// ErrUnknownStatus is returned when listing the jobs with an unknown status.
+var ErrUnknownStatus = errors.New("unknown job status")
+
+var log = logger.NewLogger("dapr.runtime.jobs")
+
+type (
+	// Job is a job registered by the app to invoke one of its endpoints on a cron schedule.
+	Job struct {
+		Name string `json:"name"`
+		// Schedule is a standard cron expression or a descriptor such as @hourly or @every 5m.
+		Schedule string `json:"schedule"`
+		// Method is the app endpoint invoked when the job runs.
+		Method string      `json:"method"`
+		Data   interface{} `json:"data,omitempty"`
+		// FailurePolicy retries the failed runs. Without a policy, a run is retried until it succeeds.
+		FailurePolicy *FailurePolicy `json:"failurePolicy,omitempty"`
+	}
+
+	// FailurePolicy retries the failed runs of a job with an exponential backoff.
+	// The runs which fail all their attempts are dropped, and sent to a dead-letter topic if the policy has one.
+	FailurePolicy struct {
+		// MaxAttempts is the number of attempts of a run, or 0 to retry it until it succeeds.
+		MaxAttempts int `json:"maxAttempts,omitempty"`
+		// InitialInterval is the delay before the first retry, doubled for each following retry.
+		InitialInterval string `json:"initialInterval,omitempty"`
+		// MaxInterval caps the delay between retries.
+		MaxInterval      string `json:"maxInterval,omitempty"`
+		DeadLetterPubsub string `json:"deadLetterPubsub,omitempty"`
+		DeadLetterTopic  string `json:"deadLetterTopic,omitempty"`
+	}
+
+	// JobStatus is a job with the status of its pending run.
+	JobStatus struct {
+		Job
+		Status string `json:"status"`
+		// NextRun is the time the pending run is due, or is retried if it failed.
+		NextRun time.Time `json:"nextRun"`
+		LastRun time.Time `json:"lastRun"`
+		// Attempts is the number of failed attempts of the pending run.
+		Attempts int `json:"attempts,omitempty"`
+	}
+
+	// InvokeFn invokes the app for a run of a job.
+	InvokeFn func(ctx context.Context, job *Job) error
+
+	// DeadLetterFn sends a run of a job which failed all its attempts to the dead-letter topic of its policy.
+	DeadLetterFn func(ctx context.Context, job *Job, cause error) error
+
+	// jobRun is the durable state of the runs of a job, shared between the replicas of the app.
+	jobRun struct {
+		LastRun time.Time `json:"lastRun"`
+		// Owner is the replica which claimed the pending run until LeaseExpiry.
+		Owner       string    `json:"owner,omitempty"`
+		LeaseExpiry time.Time `json:"leaseExpiry,omitempty"`
+		// Attempts is the number of failed attempts of the pending run, retried after NextAttempt.
+		Attempts    int       `json:"attempts,omitempty"`
+		NextAttempt time.Time `json:"nextAttempt,omitempty"`
+	}
+)
+
+// Scheduler runs the jobs of an app with at-least-once semantics.
+// Jobs and their runs are kept in a state store shared by all the replicas of the app, and each run is claimed by a
+// single replica using optimistic concurrency. A run is retried until the app invocation succeeds, or as long as
+// the failure policy of the job allows.
+type Scheduler struct {
+	appID        string
+	owner        string
+	store        state.Store
+	invokeFn     InvokeFn
+	deadLetterFn DeadLetterFn
+	json         jsoniter.API
+	now          func() time.Time
+
+	lock    sync.Mutex
+	running map[string]bool
+	stopCh  chan struct{}
+}
+
+// NewScheduler returns a new Scheduler of the jobs of appID, persisted in store.
+// owner uniquely identifies this replica of the app.
+func NewScheduler(appID, owner string, store state.Store, invokeFn InvokeFn, deadLetterFn DeadLetterFn) (*Scheduler, error) {
+	if !state.FeatureETag.IsPresent(store.Features()) {
+		return nil, errors.New("jobs state store must support etags")
+	}
+	return &Scheduler{
+		appID:        appID,
+		owner:        owner,
+		store:        store,
+		invokeFn:     invokeFn,
+		deadLetterFn: deadLetterFn,
+		json:         jsoniter.ConfigFastest,
+		now:          time.Now,
+		running:      map[string]bool{},
+		stopCh:       make(chan struct{}),
+	}, nil
+}
+
+// Start runs the jobs as they become due until the scheduler is stopped.
+func (s *Scheduler) Start() {
+	ticker := time.NewTicker(pollInterval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.runDueJobs()
+			case <-s.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops running jobs.
+func (s *Scheduler) Stop() {
+	close(s.stopCh)
+}
+
+// Create creates or replaces a job.
+// The first run of the job is the first occurrence of its schedule after it is created.
+func (s *Scheduler) Create(job *Job) error {
+	if job.Name == "" {
+		return errors.New("job name is empty")
+	}
+	if job.Method == "" {
+		return errors.Errorf("job %s has no method", job.Name)
+	}
+	if _, err := cron.ParseStandard(job.Schedule); err != nil {
+		return errors.Wrapf(err, "invalid schedule for job %s", job.Name)
+	}
+	if job.FailurePolicy != nil {
+		if err := job.FailurePolicy.validate(); err != nil {
+			return errors.Wrapf(err, "invalid failure policy for job %s", job.Name)
+		}
+	}
+
+	jobs, etag, err := s.loadJobs()
+	if err != nil {
+		return err
+	}
+	replaced := false
+	for i := range jobs {
+		if jobs[i].Name == job.Name {
+			jobs[i] = *job
+			replaced = true
+		}
+	}
+	if !replaced {
+		jobs = append(jobs, *job)
+	}
+
+	// The run of a replaced job is reset, failing if a replica claims it concurrently.
+	_, runETag, err := s.loadRun(job.Name)
+	if err != nil {
+		return err
+	}
+	if err = s.saveRun(job.Name, &jobRun{LastRun: s.now()}, runETag); err != nil {
+		return errors.Wrapf(err, "failed to save run of job %s", job.Name)
+	}
+	return s.saveJobs(jobs, etag)
+}
+
+// Get returns a job, or nil if it doesn't exist.
+func (s *Scheduler) Get(name string) (*Job, error) {
+	jobs, _, err := s.loadJobs()
+	if err != nil {
+		return nil, err
+	}
+	for i := range jobs {
+		if jobs[i].Name == name {
+			return &jobs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// List returns the jobs whose name starts with prefix and, if status isn't empty, whose pending run has that status.
+func (s *Scheduler) List(prefix, status string) ([]JobStatus, error) {
+	switch status {
+	case "", StatusScheduled, StatusRunning, StatusRetrying:
+	default:
+		return nil, errors.Wrap(ErrUnknownStatus, status)
+	}
+
+	jobs, _, err := s.loadJobs()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	list := []JobStatus{}
+	for i := range jobs {
+		if !strings.HasPrefix(jobs[i].Name, prefix) {
+			continue
+		}
+		run, _, err := s.loadRun(jobs[i].Name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get runs of job %s", jobs[i].Name)
+		}
+		js := newJobStatus(&jobs[i], run, now)
+		if status == "" || js.Status == status {
+			list = append(list, js)
+		}
+	}
+	return list, nil
+}
+
+func newJobStatus(job *Job, run *jobRun, now time.Time) JobStatus {
+	js := JobStatus{
+		Job:      *job,
+		Status:   StatusScheduled,
+		LastRun:  run.LastRun,
+		Attempts: run.Attempts,
+	}
+	if schedule, err := cron.ParseStandard(job.Schedule); err == nil {
+		js.NextRun = schedule.Next(run.LastRun)
+	}
+	switch {
+	case run.Owner != "" && run.LeaseExpiry.After(now):
+		js.Status = StatusRunning
+	case run.Attempts > 0:
+		js.Status = StatusRetrying
+		// Without a failure policy, the run is retried immediately.
+		if !run.NextAttempt.IsZero() {
+			js.NextRun = run.NextAttempt
+		}
+	}
+	return js
+}
+
+// Delete deletes a job. Deleting a job which doesn't exist is not an error.
+func (s *Scheduler) Delete(name string) error {
+	jobs, etag, err := s.loadJobs()
+	if err != nil {
+		return err
+	}
+	for i := range jobs {
+		if jobs[i].Name == name {
+			jobs = append(jobs[:i], jobs[i+1:]...)
+			if err = s.saveJobs(jobs, etag); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	return s.store.Delete(&state.DeleteRequest{Key: s.runKey(name)})
+}
+
+func (s *Scheduler) runDueJobs() {
+	jobs, _, err := s.loadJobs()
+	if err != nil {
+		log.Warnf("failed to load jobs: %s", err)
+		return
+	}
+
+	for i := range jobs {
+		job := jobs[i]
+		s.lock.Lock()
+		running := s.running[job.Name]
+		s.lock.Unlock()
+		if running {
+			continue
+		}
+
+		if s.claim(&job) {
+			s.lock.Lock()
+			s.running[job.Name] = true
+			s.lock.Unlock()
+			go s.run(&job)
+		}
+	}
+}
+
+// claim returns true if the job is due and this replica claimed its run.
+func (s *Scheduler) claim(job *Job) bool {
+	schedule, err := cron.ParseStandard(job.Schedule)
+	if err != nil {
+		return false
+	}
+
+	run, etag, err := s.loadRun(job.Name)
+	if err != nil {
+		log.Warnf("failed to load runs of job %s: %s", job.Name, err)
+		return false
+	}
+
+	now := s.now()
+	if schedule.Next(run.LastRun).After(now) {
+		return false
+	}
+	if run.Owner != "" && run.LeaseExpiry.After(now) {
+		return false
+	}
+	if run.NextAttempt.After(now) {
+		return false
+	}
+
+	run.Owner = s.owner
+	run.LeaseExpiry = now.Add(claimLease)
+	// Another replica claiming the run first fails the save.
+	if err = s.saveRun(job.Name, run, etag); err != nil {
+		log.Debugf("job %s was claimed by another replica: %s", job.Name, err)
+		return false
+	}
+	return true
+}
+
+func (s *Scheduler) run(job *Job) {
+	defer func() {
+		s.lock.Lock()
+		delete(s.running, job.Name)
+		s.lock.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), claimLease)
+	defer cancel()
+
+	run, etag, err := s.loadRun(job.Name)
+	if err != nil {
+		log.Warnf("failed to load runs of job %s: %s", job.Name, err)
+		return
+	}
+	if run.Owner != s.owner {
+		log.Debugf("job %s was claimed by another replica", job.Name)
+		return
+	}
+
+	// On failure, the claim is released so that the run is retried, possibly by another replica.
+	run.Owner = ""
+	run.LeaseExpiry = time.Time{}
+	if err = s.invokeFn(ctx, job); err != nil {
+		s.fail(ctx, job, run, err)
+	} else {
+		s.complete(run)
+	}
+
+	// The save fails if the claim expired and another replica claimed the run, or if the job was replaced.
+	if err = s.saveRun(job.Name, run, etag); err != nil {
+		log.Warnf("failed to save run of job %s: %s", job.Name, err)
+	}
+}
+
+// complete marks the pending run as done.
+func (s *Scheduler) complete(run *jobRun) {
+	// Occurrences of the schedule missed while the app was down are coalesced into this run.
+	run.LastRun = s.now()
+	run.Attempts = 0
+	run.NextAttempt = time.Time{}
+}
+
+// fail schedules the retry of the pending run, or drops it if it failed all the attempts of the failure policy.
+func (s *Scheduler) fail(ctx context.Context, job *Job, run *jobRun, cause error) {
+	run.Attempts++
+	policy := job.FailurePolicy
+	if policy == nil {
+		log.Warnf("failed to run job %s, will retry: %s", job.Name, cause)
+		return
+	}
+
+	if policy.MaxAttempts > 0 && run.Attempts >= policy.MaxAttempts {
+		log.Warnf("failed to run job %s after %d attempts, dropping the run: %s", job.Name, run.Attempts, cause)
+		if policy.DeadLetterTopic != "" && s.deadLetterFn != nil {
+			if err := s.deadLetterFn(ctx, job, cause); err != nil {
+				log.Warnf("failed to send run of job %s to dead-letter topic %s: %s", job.Name, policy.DeadLetterTopic, err)
+			}
+		}
+		s.complete(run)
+		return
+	}
+
+	delay := policy.backoff(run.Attempts)
+	run.NextAttempt = s.now().Add(delay)
+	log.Warnf("failed to run job %s, will retry in %s: %s", job.Name, delay, cause)
+}
+
+func (p *FailurePolicy) validate() error {
+	if p.MaxAttempts < 0 {
+		return errors.New("maxAttempts can't be negative")
+	}
+	for _, interval := range []string{p.InitialInterval, p.MaxInterval} {
+		if interval == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(interval); err != nil || d <= 0 {
+			return errors.Errorf("invalid interval %q", interval)
+		}
+	}
+	if (p.DeadLetterPubsub == "") != (p.DeadLetterTopic == "") {
+		return errors.New("deadLetterPubsub and deadLetterTopic must be set together")
+	}
+	if p.DeadLetterTopic != "" && p.MaxAttempts == 0 {
+		return errors.New("a dead-letter topic requires maxAttempts")
+	}
+	return nil
+}
+
+// backoff returns the delay before the retry following the given number of failed attempts.
+func (p *FailurePolicy) backoff(attempts int) time.Duration {
+	initial, maxInterval := defaultInitialRetryInterval, defaultMaxRetryInterval
+	if d, err := time.ParseDuration(p.InitialInterval); err == nil {
+		initial = d
+	}
+	if d, err := time.ParseDuration(p.MaxInterval); err == nil {
+		maxInterval = d
+	}
+
+	delay := initial
+	for i := 1; i < attempts && delay < maxInterval; i++ {
+		delay *= 2
+	}
+	if delay > maxInterval {
+		delay = maxInterval
+	}
+	return delay
+}
+
+func (s *Scheduler) loadJobs() ([]Job, *string, error) {
+	resp, err := s.store.Get(&state.GetRequest{Key: s.jobsKey()})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get jobs")
+	}
+
+	var jobs []Job
+	if resp != nil && len(resp.Data) > 0 {
+		if err = s.json.Unmarshal(resp.Data, &jobs); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to parse jobs")
+		}
+		return jobs, resp.ETag, nil
+	}
+	return jobs, nil, nil
+}
+
+func (s *Scheduler) saveJobs(jobs []Job, etag *string) error {
+	return errors.Wrap(s.save(s.jobsKey(), jobs, etag), "failed to save jobs")
+}
+
+func (s *Scheduler) loadRun(name string) (*jobRun, *string, error) {
+	resp, err := s.store.Get(&state.GetRequest{Key: s.runKey(name)})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	run := &jobRun{}
+	if resp != nil && len(resp.Data) > 0 {
+		if err = s.json.Unmarshal(resp.Data, run); err != nil {
+			return nil, nil, err
+		}
+		return run, resp.ETag, nil
+	}
+	return run, nil, nil
+}
+
+func (s *Scheduler) saveRun(name string, run *jobRun, etag *string) error {
+	return s.save(s.runKey(name), run, etag)
+}
+
+// save saves a value with first-write-wins concurrency: the save fails if the value changed since its etag was
+// read, or if the value was created since it was read as missing, when etag is nil.
+func (s *Scheduler) save(key string, value interface{}, etag *string) error {
+	return s.store.Set(&state.SetRequest{
+		Key:   key,
+		Value: value,
+		ETag:  etag,
+		Options: state.SetStateOption{
+			Concurrency: state.FirstWrite,
+		},
+	})
+}
+
+func (s *Scheduler) jobsKey() string {
+	return s.appID + "||jobs"
+}
+
+func (s *Scheduler) runKey(name string) string {
+	return s.appID + "||jobs||" + name
+}
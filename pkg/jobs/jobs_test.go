@@ -0,0 +1,354 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/components-contrib/state"
+)
+
+type fakeStateStoreItem struct {
+	data []byte
+	etag string
+}
+
+type fakeStateStore struct {
+	state.Store
+	lock    sync.Mutex
+	items   map[string]*fakeStateStoreItem
+	version int
+}
+
+func newFakeStateStore() *fakeStateStore {
+	return &fakeStateStore{items: map[string]*fakeStateStoreItem{}}
+}
+
+func (f *fakeStateStore) Features() []state.Feature {
+	return []state.Feature{state.FeatureETag}
+}
+
+func (f *fakeStateStore) Get(req *state.GetRequest) (*state.GetResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	item := f.items[req.Key]
+	if item == nil {
+		return &state.GetResponse{}, nil
+	}
+	etag := item.etag
+	return &state.GetResponse{Data: item.data, ETag: &etag}, nil
+}
+
+func (f *fakeStateStore) Set(req *state.SetRequest) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	item := f.items[req.Key]
+	if req.ETag != nil && (item == nil || item.etag != *req.ETag) {
+		return errors.New("etag mismatch")
+	}
+	// like the redis store, the first write wins over writes without an etag.
+	if req.ETag == nil && item != nil && req.Options.Concurrency == state.FirstWrite {
+		return errors.New("etag mismatch")
+	}
+	b, _ := json.Marshal(req.Value)
+	f.version++
+	f.items[req.Key] = &fakeStateStoreItem{data: b, etag: strconv.Itoa(f.version)}
+	return nil
+}
+
+func (f *fakeStateStore) Delete(req *state.DeleteRequest) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	delete(f.items, req.Key)
+	return nil
+}
+
+func newTestScheduler(t *testing.T, store state.Store, owner string, invokeFn InvokeFn) *Scheduler {
+	s, err := NewScheduler("app1", owner, store, invokeFn, nil)
+	assert.NoError(t, err)
+	return s
+}
+
+func TestCreateGetDelete(t *testing.T) {
+	s := newTestScheduler(t, newFakeStateStore(), "replica1", nil)
+
+	assert.Error(t, s.Create(&Job{Name: "job1", Method: "cleanup", Schedule: "not a schedule"}))
+	assert.Error(t, s.Create(&Job{Name: "job1", Schedule: "@hourly"}))
+
+	assert.NoError(t, s.Create(&Job{Name: "job1", Method: "cleanup", Schedule: "0 */5 * * *", Data: "payload"}))
+	assert.NoError(t, s.Create(&Job{Name: "job2", Method: "report", Schedule: "@daily"}))
+	assert.NoError(t, s.Create(&Job{Name: "job1", Method: "cleanup", Schedule: "@every 1m"}))
+
+	job, err := s.Get("job1")
+	assert.NoError(t, err)
+	assert.Equal(t, "@every 1m", job.Schedule)
+
+	assert.NoError(t, s.Delete("job1"))
+	job, err = s.Get("job1")
+	assert.NoError(t, err)
+	assert.Nil(t, job)
+
+	job, err = s.Get("job2")
+	assert.NoError(t, err)
+	assert.Equal(t, "report", job.Method)
+}
+
+func TestRunDueJobs(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	store := newFakeStateStore()
+	var invoked []string
+	invokeErr := errors.New("app unavailable")
+	invokeFn := func(ctx context.Context, job *Job) error {
+		invoked = append(invoked, job.Method)
+		return invokeErr
+	}
+
+	s1 := newTestScheduler(t, store, "replica1", invokeFn)
+	s1.now = clock
+	s2 := newTestScheduler(t, store, "replica2", invokeFn)
+	s2.now = clock
+
+	job := &Job{Name: "job1", Method: "cleanup", Schedule: "@every 1m"}
+	assert.NoError(t, s1.Create(job))
+
+	// not due yet.
+	assert.False(t, s1.claim(job))
+
+	now = now.Add(time.Minute)
+	assert.True(t, s1.claim(job))
+	// the run is claimed by the first replica.
+	assert.False(t, s2.claim(job))
+
+	// a failed run is retried.
+	s1.run(job)
+	assert.Equal(t, []string{"cleanup"}, invoked)
+	assert.True(t, s2.claim(job))
+
+	invokeErr = nil
+	s2.run(job)
+	assert.Equal(t, []string{"cleanup", "cleanup"}, invoked)
+	assert.False(t, s1.claim(job))
+
+	// the claim of a replica which didn't complete the run expires.
+	now = now.Add(time.Minute)
+	assert.True(t, s1.claim(job))
+	now = now.Add(claimLease)
+	assert.True(t, s2.claim(job))
+}
+
+func TestRunConcurrency(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	store := newFakeStateStore()
+	var s1, s2 *Scheduler
+	job := &Job{Name: "job1", Method: "cleanup", Schedule: "@every 1m"}
+	invokeFn := func(ctx context.Context, j *Job) error {
+		// the claim of the first replica expires while the app processes the run.
+		now = now.Add(claimLease)
+		assert.True(t, s2.claim(job))
+		return nil
+	}
+
+	s1 = newTestScheduler(t, store, "replica1", invokeFn)
+	s1.now = clock
+	s2 = newTestScheduler(t, store, "replica2", nil)
+	s2.now = clock
+
+	assert.NoError(t, s1.Create(job))
+	now = now.Add(time.Minute)
+	assert.True(t, s1.claim(job))
+	s1.run(job)
+
+	// the run claimed by the second replica isn't overwritten.
+	run, _, err := s1.loadRun(job.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, "replica2", run.Owner)
+
+	// the runs of other replicas aren't run.
+	s1.run(job)
+	run, _, err = s1.loadRun(job.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, "replica2", run.Owner)
+}
+
+func TestFailurePolicy(t *testing.T) {
+	t.Run("validation", func(t *testing.T) {
+		s := newTestScheduler(t, newFakeStateStore(), "replica1", nil)
+		for _, policy := range []*FailurePolicy{
+			{MaxAttempts: -1},
+			{InitialInterval: "soon"},
+			{MaxInterval: "-1s"},
+			{MaxAttempts: 3, DeadLetterTopic: "failed-jobs"},
+			{DeadLetterPubsub: "pubsub", DeadLetterTopic: "failed-jobs"},
+		} {
+			assert.Error(t, s.Create(&Job{Name: "job1", Method: "cleanup", Schedule: "@hourly", FailurePolicy: policy}), policy)
+		}
+		assert.NoError(t, s.Create(&Job{Name: "job1", Method: "cleanup", Schedule: "@hourly", FailurePolicy: &FailurePolicy{
+			MaxAttempts:      3,
+			InitialInterval:  "2s",
+			MaxInterval:      "1m",
+			DeadLetterPubsub: "pubsub",
+			DeadLetterTopic:  "failed-jobs",
+		}}))
+	})
+
+	t.Run("backoff", func(t *testing.T) {
+		policy := &FailurePolicy{InitialInterval: "10s", MaxInterval: "1m"}
+		assert.Equal(t, 10*time.Second, policy.backoff(1))
+		assert.Equal(t, 20*time.Second, policy.backoff(2))
+		assert.Equal(t, 40*time.Second, policy.backoff(3))
+		assert.Equal(t, time.Minute, policy.backoff(4))
+		assert.Equal(t, defaultInitialRetryInterval, (&FailurePolicy{}).backoff(1))
+	})
+
+	t.Run("runs are retried with backoff then sent to the dead-letter topic", func(t *testing.T) {
+		now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		attempts := 0
+		s := newTestScheduler(t, newFakeStateStore(), "replica1", func(ctx context.Context, job *Job) error {
+			attempts++
+			return errors.New("app unavailable")
+		})
+		s.now = func() time.Time { return now }
+		var deadLetters []string
+		s.deadLetterFn = func(ctx context.Context, job *Job, cause error) error {
+			deadLetters = append(deadLetters, job.Name+": "+cause.Error())
+			return nil
+		}
+
+		job := &Job{Name: "job1", Method: "cleanup", Schedule: "@every 1h", FailurePolicy: &FailurePolicy{
+			MaxAttempts:      3,
+			InitialInterval:  "10s",
+			DeadLetterPubsub: "pubsub",
+			DeadLetterTopic:  "failed-jobs",
+		}}
+		assert.NoError(t, s.Create(job))
+
+		now = now.Add(time.Hour)
+		assert.True(t, s.claim(job))
+		s.run(job)
+		assert.Equal(t, 1, attempts)
+
+		// the retry is delayed by the backoff.
+		assert.False(t, s.claim(job))
+		now = now.Add(10 * time.Second)
+		assert.True(t, s.claim(job))
+		s.run(job)
+
+		now = now.Add(10 * time.Second)
+		assert.False(t, s.claim(job))
+		now = now.Add(10 * time.Second)
+		assert.True(t, s.claim(job))
+		s.run(job)
+		assert.Equal(t, 3, attempts)
+		assert.Equal(t, []string{"job1: app unavailable"}, deadLetters)
+
+		// the run is dropped, and the next run is the next occurrence of the schedule.
+		now = now.Add(time.Minute)
+		assert.False(t, s.claim(job))
+		now = now.Add(time.Hour)
+		assert.True(t, s.claim(job))
+		run, _, err := s.loadRun(job.Name)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, run.Attempts)
+	})
+}
+
+func TestList(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := newTestScheduler(t, newFakeStateStore(), "replica1", func(ctx context.Context, job *Job) error {
+		return errors.New("app unavailable")
+	})
+	s.now = func() time.Time { return now }
+
+	assert.NoError(t, s.Create(&Job{Name: "cleanup-logs", Method: "cleanup", Schedule: "@every 1h"}))
+	assert.NoError(t, s.Create(&Job{Name: "cleanup-tmp", Method: "cleanup", Schedule: "@every 1h", FailurePolicy: &FailurePolicy{InitialInterval: "10s"}}))
+	assert.NoError(t, s.Create(&Job{Name: "report", Method: "report", Schedule: "@every 2h"}))
+
+	names := func(list []JobStatus) []string {
+		var n []string
+		for _, js := range list {
+			n = append(n, js.Name)
+		}
+		return n
+	}
+
+	t.Run("all jobs are scheduled", func(t *testing.T) {
+		list, err := s.List("", "")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"cleanup-logs", "cleanup-tmp", "report"}, names(list))
+		for _, js := range list {
+			assert.Equal(t, StatusScheduled, js.Status)
+			assert.Equal(t, now, js.LastRun)
+		}
+		assert.Equal(t, now.Add(2*time.Hour), list[2].NextRun)
+	})
+
+	t.Run("filter by prefix", func(t *testing.T) {
+		list, err := s.List("cleanup-", "")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"cleanup-logs", "cleanup-tmp"}, names(list))
+	})
+
+	t.Run("filter by status", func(t *testing.T) {
+		now = now.Add(time.Hour)
+		job, _ := s.Get("cleanup-tmp")
+		assert.True(t, s.claim(job))
+		s.run(job)
+		job, _ = s.Get("cleanup-logs")
+		assert.True(t, s.claim(job))
+
+		list, err := s.List("", StatusRetrying)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"cleanup-tmp"}, names(list))
+		assert.Equal(t, 1, list[0].Attempts)
+		assert.Equal(t, now.Add(10*time.Second), list[0].NextRun)
+
+		list, err = s.List("", StatusRunning)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"cleanup-logs"}, names(list))
+
+		list, err = s.List("cleanup-", StatusScheduled)
+		assert.NoError(t, err)
+		assert.Empty(t, list)
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		_, err := s.List("", "paused")
+		assert.True(t, errors.Is(err, ErrUnknownStatus))
+	})
+}
+
+func TestNewSchedulerRequiresETags(t *testing.T) {
+	_, err := NewScheduler("app1", "replica1", &noETagStateStore{}, nil, nil)
+	assert.Error(t, err)
+}
+
+type noETagStateStore struct {
+	state.Store
+}
+
+func (n *noETagStateStore) Features() []state.Feature {
+	return nil
+}
@@ -5,6 +5,7 @@ const (
 	ErrNotFound             = "method %q is not found"
 	ErrMalformedRequest     = "failed deserializing HTTP body: %s"
 	ErrMalformedRequestData = "can't serialize request data field: %s"
+	ErrRequestBodyTooLarge  = "the request body exceeds the limit of %d bytes of the %s API"
 
 	// State.
 	ErrStateStoresNotConfigured = "state store is not configured"
@@ -13,6 +14,7 @@ const (
 	ErrStateDelete              = "failed deleting state with key %s: %s"
 	ErrStateSave                = "failed saving state in state store %s: %s"
 	ErrStateQuery               = "failed query in state store %s: %s"
+	ErrStatePrecondition        = "precondition failed for %s in state store %s: %s"
 
 	// StateTransaction.
 	ErrStateStoreNotSupported     = "state store %s doesn't support transaction"
@@ -20,7 +22,10 @@ const (
 	ErrStateTransaction           = "error while executing state transaction: %s"
 
 	// Binding.
-	ErrInvokeOutputBinding = "error when invoke output binding %s: %s"
+	ErrInvokeOutputBinding   = "error when invoke output binding %s: %s"
+	ErrOutputBindingNotFound = "couldn't find output binding %s"
+	ErrInputBindingNotFound  = "couldn't find input binding %s"
+	ErrOutputBindingInit     = "output binding %s failed to initialize: %s"
 
 	// PubSub.
 	ErrPubsubNotConfigured      = "no pubsub is configured"
@@ -36,6 +41,7 @@ const (
 	ErrChannelNotFound       = "app channel is not initialized"
 	ErrInternalInvokeRequest = "parsing InternalInvokeRequest error: %s"
 	ErrChannelInvoke         = "error invoking app channel: %s"
+	ErrAppUnhealthy          = "app is not healthy"
 
 	// Actor.
 	ErrActorRuntimeNotFound      = "actor runtime is not configured"
@@ -54,8 +60,14 @@ const (
 	ErrSecretStoreNotConfigured = "secret store is not configured"
 	ErrSecretStoreNotFound      = "failed finding secret store with key %s"
 	ErrPermissionDenied         = "access denied by policy to get %q from %q"
+	ErrSecretOperationDenied    = "access denied by policy to %s secrets from %q"
 	ErrSecretGet                = "failed getting secret with key %s from secret store %s: %s"
 	ErrBulkSecretGet            = "failed getting secrets from secret store %s: %s"
+	ErrSecretCacheNotEnabled    = "secret cache is not enabled for secret store %s"
+	ErrSecretSubscribe          = "failed subscribing to secrets %s from secret store %s: %s"
+	ErrCryptoNotSupported       = "secret store %s doesn't support cryptographic operations"
+	ErrCryptoOperation          = "failed to %s with key %s of secret store %s: %s"
+	ErrCryptoKeyNameRequired    = "the name of the key is required"
 
 	// DirectMessaging.
 	ErrDirectInvoke         = "fail to invoke, id: %s, err: %s"
@@ -63,11 +75,35 @@ const (
 	ErrDirectInvokeMethod   = "invalid method name"
 	ErrDirectInvokeNotReady = "invoke API is not ready"
 
+	// Gateway.
+	ErrGatewayCallerIdentity = "failed getting the identity of the caller to forward the invocation of app id %s: %s"
+
 	// Metadata.
 	ErrMetadataGet = "failed deserializing metadata: %s"
 
 	// Healthz.
 	ErrHealthNotReady = "dapr is not ready"
+	ErrHealthDetails  = "failed serializing health details: %s"
+
+	// Logging.
+	ErrLogLevel = "failed setting log level: %s"
+
+	// Shutdown.
+	ErrDraining          = "the sidecar is shutting down and does not accept new work"
+	ErrShutdownMalformed = "invalid drain timeout %s: %s"
+
+	// Resiliency.
+	ErrCircuitBreakerNotFound = "circuit breaker %s not found"
+	ErrCircuitBreakersGet     = "failed serializing circuit breakers: %s"
+	ErrResiliencySimulate     = "failed simulating resiliency policies: %s"
+
+	// Jobs.
+	ErrJobsNotConfigured = "jobs state store is not configured"
+	ErrJobNotFound       = "job %s not found"
+	ErrJobGet            = "failed getting job %s: %s"
+	ErrJobSave           = "failed saving job %s: %s"
+	ErrJobDelete         = "failed deleting job %s: %s"
+	ErrJobList           = "failed listing jobs: %s"
 
 	// Configuration.
 	ErrConfigurationStoresNotConfigured = "error configuration stores not configured"
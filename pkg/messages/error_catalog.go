@@ -0,0 +1,235 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package messages
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	epb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// ErrorsDocsURL is the documentation of the errors returned by the APIs.
+	ErrorsDocsURL = "https://docs.dapr.io/reference/errors/"
+	// ErrorInfoDomain is the domain of the ErrorInfo details of the gRPC errors.
+	ErrorInfoDomain = "dapr.io"
+	// ErrorInfoRetriableMetadata is the key of the retriable flag in the metadata of the ErrorInfo details.
+	ErrorInfoRetriableMetadata = "retriable"
+)
+
+// ErrorReason is an entry of the catalog of the errors returned by the HTTP and gRPC APIs, giving the
+// machine-readable details of a failure.
+type ErrorReason struct {
+	// Code is returned as the errorCode of the HTTP errors and as the reason of the ErrorInfo of the gRPC errors.
+	Code string
+	// Retriable is true when the failure may be transient, so that the call may succeed if retried as is.
+	// The errors caused by the request itself are never retriable, whatever their reason.
+	Retriable bool
+	// messages are the formats of the messages of the errors with this reason.
+	messages []string
+}
+
+// DocsURL returns the documentation of the reason.
+func (r ErrorReason) DocsURL() string {
+	return ErrorsDocsURL + "#" + strings.ToLower(strings.ReplaceAll(r.Code, "_", "-"))
+}
+
+var errorCatalog = []ErrorReason{
+	// Http.
+	{Code: "ERR_METHOD_NOT_FOUND", messages: []string{ErrNotFound}},
+	{Code: "ERR_MALFORMED_REQUEST", messages: []string{ErrMalformedRequest, ErrShutdownMalformed, ErrCryptoKeyNameRequired}},
+	{Code: "ERR_MALFORMED_REQUEST_DATA", messages: []string{ErrMalformedRequestData}},
+	{Code: "ERR_MALFORMED_RESPONSE"},
+	{Code: "ERR_REQUEST_BODY_TOO_LARGE", messages: []string{ErrRequestBodyTooLarge}},
+
+	// State.
+	{Code: "ERR_STATE_STORES_NOT_CONFIGURED", messages: []string{ErrStateStoresNotConfigured}},
+	{Code: "ERR_STATE_STORE_NOT_FOUND", messages: []string{ErrStateStoreNotFound}},
+	{Code: "ERR_STATE_GET", Retriable: true, messages: []string{ErrStateGet}},
+	{Code: "ERR_STATE_DELETE", Retriable: true, messages: []string{ErrStateDelete}},
+	{Code: "ERR_STATE_SAVE", Retriable: true, messages: []string{ErrStateSave}},
+	{Code: "ERR_STATE_QUERY", Retriable: true, messages: []string{ErrStateQuery}},
+	{Code: "ERR_STATE_PRECONDITION", messages: []string{ErrStatePrecondition}},
+
+	// StateTransaction.
+	{Code: "ERR_STATE_STORE_NOT_SUPPORTED", messages: []string{ErrStateStoreNotSupported}},
+	{Code: "ERR_NOT_SUPPORTED_STATE_OPERATION", messages: []string{ErrNotSupportedStateOperation}},
+	{Code: "ERR_STATE_TRANSACTION", Retriable: true, messages: []string{ErrStateTransaction}},
+
+	// Binding.
+	{Code: "ERR_INVOKE_OUTPUT_BINDING", Retriable: true, messages: []string{ErrInvokeOutputBinding}},
+	{Code: "ERR_BINDING_NOT_FOUND", messages: []string{ErrOutputBindingNotFound, ErrInputBindingNotFound}},
+	{Code: "ERR_BINDING_OPERATIONS"},
+	{Code: "ERR_OUTPUT_BINDING_INIT", Retriable: true, messages: []string{ErrOutputBindingInit}},
+
+	// PubSub.
+	{Code: "ERR_PUBSUB_NOT_CONFIGURED", messages: []string{ErrPubsubNotConfigured}},
+	{Code: "ERR_PUBSUB_EMPTY", messages: []string{ErrPubsubEmpty}},
+	{Code: "ERR_PUBSUB_NOT_FOUND", messages: []string{ErrPubsubNotFound}},
+	{Code: "ERR_TOPIC_EMPTY", messages: []string{ErrTopicEmpty}},
+	{Code: "ERR_PUBSUB_CLOUD_EVENTS_SER", messages: []string{ErrPubsubCloudEventsSer, ErrPubsubCloudEventCreation}},
+	{Code: "ERR_PUBSUB_PUBLISH_MESSAGE", Retriable: true, messages: []string{ErrPubsubPublishMessage}},
+	{Code: "ERR_PUBSUB_FORBIDDEN", messages: []string{ErrPubsubForbidden}},
+	{Code: "ERR_PUBSUB_REQUEST_METADATA"},
+
+	// AppChannel.
+	{Code: "ERR_APP_CHANNEL_NOT_FOUND", Retriable: true, messages: []string{ErrChannelNotFound}},
+	{Code: "ERR_INTERNAL_INVOKE_REQUEST", messages: []string{ErrInternalInvokeRequest}},
+	// The invocations may have reached the app, which may not be idempotent.
+	{Code: "ERR_APP_CHANNEL_INVOKE", messages: []string{ErrChannelInvoke}},
+	{Code: "ERR_APP_UNHEALTHY", Retriable: true, messages: []string{ErrAppUnhealthy}},
+
+	// Actor.
+	{Code: "ERR_ACTOR_RUNTIME_NOT_FOUND", messages: []string{ErrActorRuntimeNotFound}},
+	{Code: "ERR_ACTOR_INSTANCE_MISSING", messages: []string{ErrActorInstanceMissing}},
+	{Code: "ERR_ACTOR_INVOKE_METHOD", Retriable: true, messages: []string{ErrActorInvoke}},
+	{Code: "ERR_ACTOR_REMINDER_CREATE", Retriable: true, messages: []string{ErrActorReminderCreate}},
+	{Code: "ERR_ACTOR_REMINDER_RENAME", Retriable: true, messages: []string{ErrActorReminderRename}},
+	{Code: "ERR_ACTOR_REMINDER_GET", Retriable: true, messages: []string{ErrActorReminderGet}},
+	{Code: "ERR_ACTOR_REMINDER_DELETE", Retriable: true, messages: []string{ErrActorReminderDelete}},
+	{Code: "ERR_ACTOR_TIMER_CREATE", Retriable: true, messages: []string{ErrActorTimerCreate}},
+	{Code: "ERR_ACTOR_TIMER_DELETE", Retriable: true, messages: []string{ErrActorTimerDelete}},
+	{Code: "ERR_ACTOR_STATE_GET", Retriable: true, messages: []string{ErrActorStateGet}},
+	{Code: "ERR_ACTOR_STATE_TRANSACTION_SAVE", Retriable: true, messages: []string{ErrActorStateTransactionSave}},
+
+	// Secret.
+	{Code: "ERR_SECRET_STORES_NOT_CONFIGURED", messages: []string{ErrSecretStoreNotConfigured}},
+	{Code: "ERR_SECRET_STORE_NOT_FOUND", messages: []string{ErrSecretStoreNotFound}},
+	{Code: "ERR_PERMISSION_DENIED", messages: []string{ErrPermissionDenied, ErrSecretOperationDenied}},
+	{Code: "ERR_SECRET_GET", Retriable: true, messages: []string{ErrSecretGet, ErrBulkSecretGet}},
+	{Code: "ERR_SECRET_CACHE_NOT_ENABLED", messages: []string{ErrSecretCacheNotEnabled}},
+	{Code: "ERR_SECRET_SUBSCRIBE", Retriable: true, messages: []string{ErrSecretSubscribe}},
+	{Code: "ERR_CRYPTO_NOT_SUPPORTED", messages: []string{ErrCryptoNotSupported}},
+	{Code: "ERR_CRYPTO_OPERATION", Retriable: true, messages: []string{ErrCryptoOperation}},
+
+	// DirectMessaging.
+	// Not retriable either, as the invocations may have reached the target.
+	{Code: "ERR_DIRECT_INVOKE", messages: []string{ErrDirectInvoke, ErrDirectInvokeNoAppID, ErrDirectInvokeMethod, ErrDirectInvokeNotReady}},
+
+	// Gateway.
+	{Code: "ERR_GATEWAY_CALLER_IDENTITY", messages: []string{ErrGatewayCallerIdentity}},
+
+	// Metadata.
+	{Code: "ERR_METADATA_GET", messages: []string{ErrMetadataGet}},
+
+	// Healthz.
+	{Code: "ERR_HEALTH_NOT_READY", Retriable: true, messages: []string{ErrHealthNotReady}},
+	{Code: "ERR_HEALTH_DETAILS", messages: []string{ErrHealthDetails}},
+
+	// Logging.
+	{Code: "ERR_LOG_LEVEL", messages: []string{ErrLogLevel}},
+
+	// Shutdown.
+	{Code: "ERR_DRAINING", Retriable: true, messages: []string{ErrDraining}},
+
+	// Resiliency.
+	{Code: "ERR_CIRCUIT_BREAKER_NOT_FOUND", messages: []string{ErrCircuitBreakerNotFound}},
+	{Code: "ERR_CIRCUIT_BREAKERS_GET", messages: []string{ErrCircuitBreakersGet}},
+	{Code: "ERR_RESILIENCY_SIMULATE", messages: []string{ErrResiliencySimulate}},
+
+	// Jobs.
+	{Code: "ERR_JOBS_NOT_CONFIGURED", messages: []string{ErrJobsNotConfigured}},
+	{Code: "ERR_JOB_NOT_FOUND", messages: []string{ErrJobNotFound}},
+	{Code: "ERR_JOB_GET", Retriable: true, messages: []string{ErrJobGet}},
+	{Code: "ERR_JOB_SAVE", Retriable: true, messages: []string{ErrJobSave}},
+	{Code: "ERR_JOB_DELETE", Retriable: true, messages: []string{ErrJobDelete}},
+	{Code: "ERR_JOB_LIST", Retriable: true, messages: []string{ErrJobList}},
+
+	// Configuration.
+	{Code: "ERR_CONFIGURATION_STORES_NOT_CONFIGURED", messages: []string{ErrConfigurationStoresNotConfigured}},
+	{Code: "ERR_CONFIGURATION_STORE_NOT_FOUND", messages: []string{ErrConfigurationStoreNotFound}},
+	{Code: "ERR_CONFIGURATION_GET", Retriable: true, messages: []string{ErrConfigurationGet}},
+	{Code: "ERR_CONFIGURATION_SUBSCRIBE", Retriable: true, messages: []string{ErrConfigurationSubscribe}},
+}
+
+var (
+	reasonsByCode    = map[string]ErrorReason{}
+	reasonsByMessage = map[string]ErrorReason{}
+)
+
+func init() {
+	for _, r := range errorCatalog {
+		reasonsByCode[r.Code] = r
+		for _, m := range r.messages {
+			reasonsByMessage[m] = r
+		}
+	}
+}
+
+// ReasonByCode returns the reason of the catalog with the given code.
+func ReasonByCode(code string) (ErrorReason, bool) {
+	r, ok := reasonsByCode[code]
+	return r, ok
+}
+
+// ReasonByMessage returns the reason of the catalog of the errors with the given message format.
+func ReasonByMessage(format string) (ErrorReason, bool) {
+	r, ok := reasonsByMessage[format]
+	return r, ok
+}
+
+// IsRetriableGRPCCode returns true for the gRPC codes of the failures that are not caused by the request itself.
+func IsRetriableGRPCCode(c codes.Code) bool {
+	switch c {
+	case codes.Unknown, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// Status returns the gRPC error with the given code and message, with the ErrorInfo of its reason and a link to
+// its documentation as details when the format of the message is in the catalog.
+func Status(c codes.Code, format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	r, ok := ReasonByMessage(format)
+	if !ok {
+		return status.Error(c, msg)
+	}
+	return statusWithReason(c, r, msg)
+}
+
+// ReasonStatus returns the gRPC error with the given code and message, with the details of the reason of the
+// catalog with the given code, for the messages not formatted from the catalog.
+func ReasonStatus(c codes.Code, reasonCode string, msg string) error {
+	r, ok := ReasonByCode(reasonCode)
+	if !ok {
+		return status.Error(c, msg)
+	}
+	return statusWithReason(c, r, msg)
+}
+
+func statusWithReason(c codes.Code, r ErrorReason, msg string) error {
+	s := status.New(c, msg)
+	withDetails, err := s.WithDetails(
+		&epb.ErrorInfo{
+			Reason: r.Code,
+			Domain: ErrorInfoDomain,
+			Metadata: map[string]string{
+				ErrorInfoRetriableMetadata: strconv.FormatBool(r.Retriable && IsRetriableGRPCCode(c)),
+			},
+		},
+		&epb.Help{
+			Links: []*epb.Help_Link{{Description: r.Code, Url: r.DocsURL()}},
+		},
+	)
+	if err != nil {
+		return s.Err()
+	}
+	return withDetails.Err()
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package messages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	epb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorCatalog(t *testing.T) {
+	seen := map[string]bool{}
+	for _, r := range errorCatalog {
+		assert.False(t, seen[r.Code], "duplicate reason %s", r.Code)
+		seen[r.Code] = true
+	}
+
+	r, ok := ReasonByMessage(ErrStateGet)
+	require.True(t, ok)
+	assert.Equal(t, "ERR_STATE_GET", r.Code)
+	assert.True(t, r.Retriable)
+	assert.Equal(t, ErrorsDocsURL+"#err-state-get", r.DocsURL())
+
+	// the invocations may have reached the app.
+	r, ok = ReasonByMessage(ErrDirectInvoke)
+	require.True(t, ok)
+	assert.False(t, r.Retriable)
+	r, ok = ReasonByCode("ERR_APP_CHANNEL_INVOKE")
+	require.True(t, ok)
+	assert.False(t, r.Retriable)
+
+	_, ok = ReasonByCode("ERR_UNKNOWN")
+	assert.False(t, ok)
+}
+
+func TestStatus(t *testing.T) {
+	errorInfo := func(t *testing.T, err error) *epb.ErrorInfo {
+		s, ok := status.FromError(err)
+		require.True(t, ok)
+		for _, d := range s.Details() {
+			if info, ok := d.(*epb.ErrorInfo); ok {
+				return info
+			}
+		}
+		return nil
+	}
+
+	t.Run("reason of the message format", func(t *testing.T) {
+		err := Status(codes.Internal, ErrStateGet, "key", "store", "timeout")
+
+		assert.Equal(t, "rpc error: code = Internal desc = fail to get key from state store store: timeout", err.Error())
+		info := errorInfo(t, err)
+		require.NotNil(t, info)
+		assert.Equal(t, "ERR_STATE_GET", info.Reason)
+		assert.Equal(t, ErrorInfoDomain, info.Domain)
+		assert.Equal(t, "true", info.Metadata[ErrorInfoRetriableMetadata])
+	})
+
+	t.Run("errors caused by the request are not retriable", func(t *testing.T) {
+		err := Status(codes.Aborted, ErrStateSave, "store", "etag mismatch")
+
+		info := errorInfo(t, err)
+		require.NotNil(t, info)
+		assert.Equal(t, "false", info.Metadata[ErrorInfoRetriableMetadata])
+	})
+
+	t.Run("reason of a code", func(t *testing.T) {
+		err := ReasonStatus(codes.NotFound, "ERR_PUBSUB_NOT_FOUND", "pubsub 'p' not found")
+
+		info := errorInfo(t, err)
+		require.NotNil(t, info)
+		assert.Equal(t, "ERR_PUBSUB_NOT_FOUND", info.Reason)
+	})
+
+	t.Run("message without reason", func(t *testing.T) {
+		err := Status(codes.Internal, "unexpected %s", "failure")
+
+		assert.Equal(t, "rpc error: code = Internal desc = unexpected failure", err.Error())
+		assert.Nil(t, errorInfo(t, err))
+	})
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package messaging
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	nr_loader "github.com/dapr/dapr/pkg/components/nameresolution"
+)
+
+// endpointBalancer picks one of the healthy endpoints of an app using weighted random selection.
+// Endpoints on the same node as this instance are preferred, then endpoints in the same zone.
+type endpointBalancer struct {
+	lock sync.Mutex
+	rand *rand.Rand
+	node string
+	zone string
+}
+
+func newEndpointBalancer() *endpointBalancer {
+	return &endpointBalancer{
+		rand: rand.New(rand.NewSource(time.Now().UnixNano())), // nolint:gosec
+	}
+}
+
+// withLocality sets the node and zone this instance runs in.
+func (b *endpointBalancer) withLocality(node, zone string) *endpointBalancer {
+	b.node = node
+	b.zone = zone
+	return b
+}
+
+// pick returns the address of a healthy endpoint, skipping the excluded addresses.
+func (b *endpointBalancer) pick(appID string, endpoints []nr_loader.Endpoint, exclude ...string) (string, error) {
+	healthy := make([]nr_loader.Endpoint, 0, len(endpoints))
+	for _, e := range nr_loader.HealthyEndpoints(endpoints) {
+		if !containsAddress(exclude, e.Address) {
+			healthy = append(healthy, e)
+		}
+	}
+	if len(healthy) == 0 {
+		return "", errors.Errorf("no healthy instances found for app id %s", appID)
+	}
+	healthy = b.local(healthy)
+
+	total := 0
+	for _, e := range healthy {
+		total += endpointWeight(e)
+	}
+
+	b.lock.Lock()
+	n := b.rand.Intn(total)
+	b.lock.Unlock()
+
+	for _, e := range healthy {
+		n -= endpointWeight(e)
+		if n < 0 {
+			return e.Address, nil
+		}
+	}
+	return healthy[len(healthy)-1].Address, nil
+}
+
+// local returns the endpoints on the same node or, if there are none, in the same zone.
+// All endpoints are returned when none of them is local.
+func (b *endpointBalancer) local(endpoints []nr_loader.Endpoint) []nr_loader.Endpoint {
+	if b.node != "" {
+		if sameNode := filterEndpoints(endpoints, func(e nr_loader.Endpoint) bool { return e.Node == b.node }); len(sameNode) > 0 {
+			return sameNode
+		}
+	}
+	if b.zone != "" {
+		if sameZone := filterEndpoints(endpoints, func(e nr_loader.Endpoint) bool { return e.Zone == b.zone }); len(sameZone) > 0 {
+			return sameZone
+		}
+	}
+	return endpoints
+}
+
+func filterEndpoints(endpoints []nr_loader.Endpoint, fn func(e nr_loader.Endpoint) bool) []nr_loader.Endpoint {
+	filtered := []nr_loader.Endpoint{}
+	for _, e := range endpoints {
+		if fn(e) {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+func endpointWeight(e nr_loader.Endpoint) int {
+	if e.Weight <= 0 {
+		return nr_loader.DefaultEndpointWeight
+	}
+	return e.Weight
+}
+
+func containsAddress(addresses []string, address string) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
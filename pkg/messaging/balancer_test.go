@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package messaging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	nr "github.com/dapr/components-contrib/nameresolution"
+
+	nr_loader "github.com/dapr/dapr/pkg/components/nameresolution"
+)
+
+type mockEndpointResolver struct {
+	endpoints []nr_loader.Endpoint
+}
+
+func (m *mockEndpointResolver) Init(metadata nr.Metadata) error {
+	return nil
+}
+
+func (m *mockEndpointResolver) ResolveID(req nr.ResolveRequest) (string, error) {
+	return "single:50002", nil
+}
+
+func (m *mockEndpointResolver) ResolveEndpoints(req nr.ResolveRequest) ([]nr_loader.Endpoint, error) {
+	return m.endpoints, nil
+}
+
+func TestEndpointBalancer(t *testing.T) {
+	t.Run("only healthy endpoints are picked", func(t *testing.T) {
+		b := newEndpointBalancer()
+		endpoints := []nr_loader.Endpoint{
+			{Address: "a:1", Weight: 100, Healthy: false},
+			{Address: "b:1", Weight: 1, Healthy: true},
+		}
+		for i := 0; i < 50; i++ {
+			address, err := b.pick("app", endpoints)
+			assert.NoError(t, err)
+			assert.Equal(t, "b:1", address)
+		}
+	})
+
+	t.Run("weights are honored", func(t *testing.T) {
+		b := newEndpointBalancer()
+		endpoints := []nr_loader.Endpoint{
+			{Address: "a:1", Weight: 1, Healthy: true},
+			{Address: "b:1", Weight: 0, Healthy: true},
+			{Address: "c:1", Weight: 8, Healthy: true},
+		}
+		counts := map[string]int{}
+		for i := 0; i < 1000; i++ {
+			address, err := b.pick("app", endpoints)
+			assert.NoError(t, err)
+			counts[address]++
+		}
+		assert.Greater(t, counts["c:1"], counts["a:1"])
+		assert.Greater(t, counts["c:1"], counts["b:1"])
+	})
+
+	t.Run("excluded endpoints are skipped", func(t *testing.T) {
+		b := newEndpointBalancer()
+		endpoints := []nr_loader.Endpoint{
+			{Address: "a:1", Healthy: true},
+			{Address: "b:1", Healthy: true},
+		}
+		for i := 0; i < 50; i++ {
+			address, err := b.pick("app", endpoints, "a:1")
+			assert.NoError(t, err)
+			assert.Equal(t, "b:1", address)
+		}
+		_, err := b.pick("app", endpoints, "a:1", "b:1")
+		assert.Error(t, err)
+	})
+
+	t.Run("no healthy endpoints", func(t *testing.T) {
+		b := newEndpointBalancer()
+		_, err := b.pick("app", []nr_loader.Endpoint{{Address: "a:1"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("local endpoints are preferred", func(t *testing.T) {
+		b := newEndpointBalancer().withLocality("node1", "zone1")
+		endpoints := []nr_loader.Endpoint{
+			{Address: "a:1", Healthy: true, Node: "node2", Zone: "zone2"},
+			{Address: "b:1", Healthy: true, Node: "node2", Zone: "zone1"},
+			{Address: "c:1", Healthy: true, Node: "node1", Zone: "zone1"},
+			{Address: "d:1", Healthy: false, Node: "node1", Zone: "zone1"},
+		}
+		for i := 0; i < 50; i++ {
+			address, err := b.pick("app", endpoints)
+			assert.NoError(t, err)
+			assert.Equal(t, "c:1", address)
+		}
+
+		// same zone when there is no healthy endpoint on the same node.
+		address, err := b.pick("app", endpoints, "c:1")
+		assert.NoError(t, err)
+		assert.Equal(t, "b:1", address)
+
+		// any endpoint when there is no local one.
+		address, err = b.pick("app", endpoints, "b:1", "c:1")
+		assert.NoError(t, err)
+		assert.Equal(t, "a:1", address)
+	})
+}
+
+func TestResolveAddress(t *testing.T) {
+	t.Run("endpoint resolver is load balanced", func(t *testing.T) {
+		dm := newDirectMessaging()
+		dm.balancer = newEndpointBalancer()
+		dm.resolver = &mockEndpointResolver{
+			endpoints: []nr_loader.Endpoint{{Address: "healthy:50002", Healthy: true}},
+		}
+
+		address, endpoints, err := dm.resolveAddress(nr.ResolveRequest{ID: "app"})
+		assert.NoError(t, err)
+		assert.Equal(t, "healthy:50002", address)
+		assert.Len(t, endpoints, 1)
+	})
+}
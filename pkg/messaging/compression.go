@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package messaging
+
+import (
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/dapr/pkg/grpc/compression"
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+)
+
+// payloadCompression negotiates the compression of the requests sent to other sidecars.
+// Requests are compressed only once the target sidecar has advertised the compressor in the header of a
+// previous response, so that sidecars which do not support it keep receiving uncompressed requests.
+// A nil payloadCompression compresses nothing.
+type payloadCompression struct {
+	algorithm string
+	minSize   int
+	lock      sync.RWMutex
+	accepted  map[string]bool
+}
+
+func newPayloadCompression(spec config.CompressionSpec) *payloadCompression {
+	if spec.Algorithm == "" {
+		return nil
+	}
+	if !compression.IsSupported(spec.Algorithm) {
+		log.Warnf("compression algorithm %s is not supported, payloads will be sent uncompressed", spec.Algorithm)
+		return nil
+	}
+	return &payloadCompression{
+		algorithm: spec.Algorithm,
+		minSize:   spec.MinSize,
+		accepted:  map[string]bool{},
+	}
+}
+
+// callOptions returns the call options sending a request of the given size to the sidecar at address,
+// compressed when that sidecar accepts it, and collecting the header of the response.
+func (c *payloadCompression) callOptions(address string, size int, header *metadata.MD) []grpc.CallOption {
+	if c == nil {
+		return nil
+	}
+
+	opts := []grpc.CallOption{grpc.Header(header)}
+	if size < c.minSize {
+		return opts
+	}
+
+	c.lock.RLock()
+	accepted := c.accepted[address]
+	c.lock.RUnlock()
+	if accepted {
+		opts = append(opts, grpc.UseCompressor(c.algorithm))
+	}
+	return opts
+}
+
+// update records whether the sidecar at address accepts the compressor from the header of its response.
+func (c *payloadCompression) update(address string, header metadata.MD) {
+	if c == nil {
+		return
+	}
+
+	accepted := false
+	for _, v := range header.Get(invokev1.AcceptEncodingHeader) {
+		for _, name := range strings.Split(v, ",") {
+			if strings.TrimSpace(name) == c.algorithm {
+				accepted = true
+			}
+		}
+	}
+
+	c.lock.Lock()
+	c.accepted[address] = accepted
+	c.lock.Unlock()
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package messaging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/dapr/pkg/grpc/compression"
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+)
+
+func TestPayloadCompression(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		c := newPayloadCompression(config.CompressionSpec{})
+		assert.Nil(t, c)
+
+		var header metadata.MD
+		assert.Empty(t, c.callOptions("a:50002", 1024, &header))
+		c.update("a:50002", header)
+	})
+
+	t.Run("unsupported algorithm", func(t *testing.T) {
+		assert.Nil(t, newPayloadCompression(config.CompressionSpec{Algorithm: "snappy"}))
+	})
+
+	t.Run("compress once accepted by the target", func(t *testing.T) {
+		c := newPayloadCompression(config.CompressionSpec{Algorithm: compression.Zstd, MinSize: 512})
+
+		var header metadata.MD
+		assert.Len(t, c.callOptions("a:50002", 1024, &header), 1)
+
+		c.update("a:50002", metadata.Pairs(invokev1.AcceptEncodingHeader, "zstd,gzip"))
+		assert.Len(t, c.callOptions("a:50002", 1024, &header), 2)
+		assert.Len(t, c.callOptions("a:50002", 128, &header), 1)
+		assert.Len(t, c.callOptions("b:50002", 1024, &header), 1)
+	})
+
+	t.Run("target not accepting the algorithm", func(t *testing.T) {
+		c := newPayloadCompression(config.CompressionSpec{Algorithm: compression.Zstd})
+
+		var header metadata.MD
+		c.update("a:50002", metadata.Pairs(invokev1.AcceptEncodingHeader, "gzip"))
+		assert.Len(t, c.callOptions("a:50002", 1024, &header), 1)
+
+		c.update("a:50002", metadata.MD{})
+		assert.Len(t, c.callOptions("a:50002", 1024, &header), 1)
+	})
+}
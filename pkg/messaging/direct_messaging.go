@@ -16,6 +16,7 @@ package messaging
 import (
 	"context"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,16 +24,21 @@ import (
 	"github.com/valyala/fasthttp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	nr "github.com/dapr/components-contrib/nameresolution"
 	"github.com/dapr/kit/logger"
 
 	"github.com/dapr/dapr/pkg/channel"
+	"github.com/dapr/dapr/pkg/chaos"
+	nr_loader "github.com/dapr/dapr/pkg/components/nameresolution"
 	"github.com/dapr/dapr/pkg/config"
+	env "github.com/dapr/dapr/pkg/config/env"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
 	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
 	"github.com/dapr/dapr/pkg/modes"
+	"github.com/dapr/dapr/pkg/resiliency"
 	"github.com/dapr/dapr/pkg/retry"
 	"github.com/dapr/dapr/utils"
 
@@ -65,12 +71,17 @@ type directMessaging struct {
 	maxRequestBodySize  int
 	proxy               Proxy
 	readBufferSize      int
+	balancer            *endpointBalancer
+	resiliency          *resiliency.Resiliency
+	gateway             *GatewayRoutes
+	compression         *payloadCompression
 }
 
 type remoteApp struct {
 	id        string
 	namespace string
 	address   string
+	endpoints []nr_loader.Endpoint
 }
 
 // NewDirectMessaging returns a new direct messaging api.
@@ -80,7 +91,7 @@ func NewDirectMessaging(
 	appChannel channel.AppChannel,
 	clientConnFn messageClientConnection,
 	resolver nr.Resolver,
-	tracingSpec config.TracingSpec, maxRequestBodySize int, proxy Proxy, readBufferSize int, streamRequestBody bool) DirectMessaging {
+	tracingSpec config.TracingSpec, maxRequestBodySize int, proxy Proxy, readBufferSize int, streamRequestBody bool, resiliency *resiliency.Resiliency, gateway *GatewayRoutes, compressionSpec config.CompressionSpec) DirectMessaging {
 	hAddr, _ := utils.GetHostAddress()
 	hName, _ := os.Hostname()
 
@@ -98,6 +109,10 @@ func NewDirectMessaging(
 		maxRequestBodySize:  maxRequestBodySize,
 		proxy:               proxy,
 		readBufferSize:      readBufferSize,
+		balancer:            newEndpointBalancer().withLocality(os.Getenv(env.NodeName), os.Getenv(env.Zone)),
+		resiliency:          resiliency,
+		gateway:             gateway,
+		compression:         newPayloadCompression(compressionSpec),
 	}
 
 	if proxy != nil {
@@ -110,15 +125,124 @@ func NewDirectMessaging(
 
 // Invoke takes a message requests and invokes an app, either local or remote.
 func (d *directMessaging) Invoke(ctx context.Context, targetAppID string, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+	targetAppID, gateway, err := d.gateway.route(targetAppID)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout, err := d.invocationTimeout(targetAppID, req)
+	if err != nil {
+		return nil, err
+	}
+	if timeout > 0 {
+		// The deadline is propagated to the target sidecar and app by gRPC.
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if gateway != nil {
+		id, namespace, err := d.requestAppIDAndNamespace(targetAppID)
+		if err != nil {
+			return nil, err
+		}
+		return d.invokeGateway(ctx, *gateway, remoteApp{id: id, namespace: namespace}, req)
+	}
+
 	app, err := d.getRemoteApp(targetAppID)
 	if err != nil {
 		return nil, err
 	}
 
+	if policy := d.resiliency.AppMirroringPolicy(app.id); policy != nil {
+		d.mirror(policy, d.invokeRemote, req)
+	}
+
 	if app.id == d.appID && app.namespace == d.namespace {
 		return d.invokeLocal(ctx, req)
 	}
-	return d.invokeWithRetry(ctx, retry.DefaultLinearRetryCount, retry.DefaultLinearBackoffInterval, app, d.invokeRemote, req)
+
+	resp, err := d.invokeRemoteApp(ctx, app, req)
+	if err != nil && shouldFallBack(err) {
+		if policy := d.resiliency.AppFallback(app.id); policy != nil {
+			return d.invokeFallback(ctx, policy, app.id, req, err)
+		}
+	}
+	return resp, err
+}
+
+// invokeRemoteApp invokes a remote app, applying its resiliency policies.
+func (d *directMessaging) invokeRemoteApp(ctx context.Context, app remoteApp, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+	release, err := d.resiliency.AppConcurrencyLimiter(app.id).Acquire()
+	if err != nil {
+		return nil, status.Errorf(codes.ResourceExhausted, "failed to invoke app %s: %s", app.id, err)
+	}
+	breaker := d.resiliency.AppCircuitBreaker(app.id)
+	if err = breaker.Allow(); err != nil {
+		release(err)
+		return nil, status.Errorf(codes.Unavailable, "failed to invoke app %s: %s", app.id, err)
+	}
+	var resp *invokev1.InvokeMethodResponse
+	if policy := d.resiliency.AppHedgingPolicy(app.id, req.Message().GetMethod()); policy != nil && len(nr_loader.HealthyEndpoints(app.endpoints)) > 1 {
+		resp, err = d.invokeHedged(ctx, policy, app, d.callRemote, req)
+	} else {
+		resp, err = d.invokeWithRetry(ctx, retry.DefaultLinearRetryCount, retry.DefaultLinearBackoffInterval, app, d.invokeRemote, req)
+	}
+	breaker.Record(err)
+	release(err)
+	return resp, err
+}
+
+// invokeFallback applies the fallback policy of an app whose invocation failed with invokeErr. It either returns the
+// static response of the policy or invokes the fallback app, whose own fallback isn't applied so fallbacks can't loop.
+// The fallback invocation shares the timeout of the failed invocation.
+func (d *directMessaging) invokeFallback(ctx context.Context, policy *resiliency.FallbackPolicy, appID string, req *invokev1.InvokeMethodRequest, invokeErr error) (*invokev1.InvokeMethodResponse, error) {
+	if policy.Response != nil {
+		log.Debugf("invocation of app %s failed, returning the static response of its fallback: %s", appID, invokeErr)
+		resp := invokev1.NewInvokeMethodResponse(int32(policy.Response.StatusCode), "", nil)
+		return resp.WithRawData(policy.Response.Body, policy.Response.ContentType), nil
+	}
+
+	log.Debugf("invocation of app %s failed, invoking its fallback app %s: %s", appID, policy.AppID, invokeErr)
+	app, err := d.getRemoteApp(policy.AppID)
+	if err != nil {
+		return nil, err
+	}
+	if app.id == d.appID && app.namespace == d.namespace {
+		return d.invokeLocal(ctx, req)
+	}
+	return d.invokeRemoteApp(ctx, app, req)
+}
+
+// shouldFallBack returns whether an invocation failing with err falls back. Only the failures of the target app to
+// serve the invocation fall back, not the invocations rejected for their content or their caller.
+func shouldFallBack(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// invocationTimeout returns the timeout of an invocation of the invoked method of the target app.
+// The timeout set on the request with the dapr-timeout-ms header overrides the timeout of the resiliency policy.
+func (d *directMessaging) invocationTimeout(targetAppID string, req *invokev1.InvokeMethodRequest) (time.Duration, error) {
+	for k, v := range req.Metadata() {
+		if !strings.EqualFold(k, invokev1.TimeoutHeader) || len(v.GetValues()) == 0 {
+			continue
+		}
+		ms, err := strconv.Atoi(v.GetValues()[0])
+		if err != nil || ms <= 0 {
+			return 0, errors.Errorf("invalid %s header value: %s", invokev1.TimeoutHeader, v.GetValues()[0])
+		}
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+
+	id, _, err := d.requestAppIDAndNamespace(targetAppID)
+	if err != nil {
+		return 0, err
+	}
+	return d.resiliency.AppTimeout(id, req.Message().GetMethod()), nil
 }
 
 // requestAppIDAndNamespace takes an app id and returns the app id, namespace and error.
@@ -143,6 +267,21 @@ func (d *directMessaging) invokeWithRetry(
 	app remoteApp,
 	fn func(ctx context.Context, appID, namespace, appAddress string, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error),
 	req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+	d.resiliency.AppRetryBudget(app.id).Request()
+	return d.retryInvocation(ctx, numRetries, backoffInterval, app, fn, req)
+}
+
+// retryInvocation is invokeWithRetry for an attempt of an invocation already recorded as a request in the retry
+// budget of the app. Its retries are still withdrawn from the budget.
+func (d *directMessaging) retryInvocation(
+	ctx context.Context,
+	numRetries int,
+	backoffInterval time.Duration,
+	app remoteApp,
+	fn func(ctx context.Context, appID, namespace, appAddress string, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error),
+	req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+	budget := d.resiliency.AppRetryBudget(app.id)
+
 	for i := 0; i < numRetries; i++ {
 		resp, err := fn(ctx, app.id, app.namespace, app.address, req)
 		if err == nil {
@@ -154,6 +293,10 @@ func (d *directMessaging) invokeWithRetry(
 
 		code := status.Code(err)
 		if code == codes.Unavailable || code == codes.Unauthenticated {
+			if i+1 < numRetries && !budget.TryRetry() {
+				log.Debugf("not retrying the invocation of app %s, its retry budget is exhausted", app.id)
+				return resp, err
+			}
 			_, connerr := d.connectionCreatorFn(context.TODO(), app.address, app.id, app.namespace, false, true, false)
 			if connerr != nil {
 				return nil, connerr
@@ -162,7 +305,7 @@ func (d *directMessaging) invokeWithRetry(
 		}
 		return resp, err
 	}
-	return nil, errors.Errorf("failed to invoke target %s after %v retries", app.id, numRetries)
+	return nil, status.Errorf(codes.Unavailable, "failed to invoke target %s after %v retries", app.id, numRetries)
 }
 
 func (d *directMessaging) invokeLocal(ctx context.Context, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
@@ -181,6 +324,22 @@ func (d *directMessaging) setContextSpan(ctx context.Context) context.Context {
 }
 
 func (d *directMessaging) invokeRemote(ctx context.Context, appID, namespace, appAddress string, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+	d.addForwardedHeadersToMetadata(req)
+	d.addDestinationAppIDHeaderToMetadata(appID, req)
+
+	return d.callRemote(ctx, appID, namespace, appAddress, req)
+}
+
+// callRemote sends the request to the sidecar at appAddress without modifying it.
+func (d *directMessaging) callRemote(ctx context.Context, appID, namespace, appAddress string, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+	if err := chaos.Inject(ctx, chaos.Invocation, appID); err != nil {
+		// aborted invocations fail like the ones to unreachable targets, which are retried.
+		if err == chaos.ErrAborted {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
 	conn, err := d.connectionCreatorFn(context.TODO(), appAddress, appID, namespace, false, false, false)
 	if err != nil {
 		return nil, err
@@ -188,18 +347,19 @@ func (d *directMessaging) invokeRemote(ctx context.Context, appID, namespace, ap
 
 	ctx = d.setContextSpan(ctx)
 
-	d.addForwardedHeadersToMetadata(req)
-	d.addDestinationAppIDHeaderToMetadata(appID, req)
-
 	clientV1 := internalv1pb.NewServiceInvocationClient(conn)
 
 	var opts []grpc.CallOption
 	opts = append(opts, grpc.MaxCallRecvMsgSize(d.maxRequestBodySize*1024*1024), grpc.MaxCallSendMsgSize(d.maxRequestBodySize*1024*1024))
 
+	var header metadata.MD
+	opts = append(opts, d.compression.callOptions(appAddress, len(req.Message().GetData().GetValue()), &header)...)
+
 	resp, err := clientV1.CallLocal(ctx, req.Proto(), opts...)
 	if err != nil {
 		return nil, err
 	}
+	d.compression.update(appAddress, header)
 
 	return invokev1.InternalInvokeResponse(resp)
 }
@@ -250,7 +410,7 @@ func (d *directMessaging) getRemoteApp(appID string) (remoteApp, error) {
 	}
 
 	request := nr.ResolveRequest{ID: id, Namespace: namespace, Port: d.grpcPort}
-	address, err := d.resolver.ResolveID(request)
+	address, endpoints, err := d.resolveAddress(request)
 	if err != nil {
 		return remoteApp{}, err
 	}
@@ -259,5 +419,22 @@ func (d *directMessaging) getRemoteApp(appID string) (remoteApp, error) {
 		namespace: namespace,
 		id:        id,
 		address:   address,
+		endpoints: endpoints,
 	}, nil
 }
+
+// resolveAddress resolves the address of a target app. When the name resolver is able to return
+// all instances of an app, the invocation is load balanced across the healthy ones and the
+// resolved instances are returned alongside the picked address.
+func (d *directMessaging) resolveAddress(request nr.ResolveRequest) (string, []nr_loader.Endpoint, error) {
+	if resolver, ok := d.resolver.(nr_loader.EndpointResolver); ok {
+		endpoints, err := resolver.ResolveEndpoints(request)
+		if err != nil {
+			return "", nil, err
+		}
+		address, err := d.balancer.pick(request.ID, endpoints)
+		return address, endpoints, err
+	}
+	address, err := d.resolver.ResolveID(request)
+	return address, nil, err
+}
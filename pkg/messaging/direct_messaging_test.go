@@ -14,12 +14,18 @@ limitations under the License.
 package messaging
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"github.com/dapr/dapr/pkg/config"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	"github.com/dapr/dapr/pkg/resiliency"
 )
 
 func newDirectMessaging() *directMessaging {
@@ -120,3 +126,74 @@ func TestKubernetesNamespace(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestInvocationTimeout(t *testing.T) {
+	r, err := resiliency.FromConfiguration(config.ResiliencySpec{
+		Policies: config.ResiliencyPolicies{
+			Timeouts: map[string]string{"short": "2s", "long": "1m"},
+		},
+		Targets: config.ResiliencyTargets{
+			Apps: map[string]config.AppResiliencyTarget{
+				"app1": {
+					Timeout: "short",
+					Routes: map[string]config.RouteResiliencyTarget{
+						"export": {Timeout: "long"},
+					},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	dm := newDirectMessaging()
+	dm.resiliency = r
+
+	t.Run("timeout from resiliency policy", func(t *testing.T) {
+		req := invokev1.NewInvokeMethodRequest("method")
+		timeout, err := dm.invocationTimeout("app1.ns1", req)
+		assert.NoError(t, err)
+		assert.Equal(t, 2*time.Second, timeout)
+	})
+
+	t.Run("timeout from resiliency policy of the route", func(t *testing.T) {
+		req := invokev1.NewInvokeMethodRequest("export")
+		timeout, err := dm.invocationTimeout("app1", req)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Minute, timeout)
+	})
+
+	t.Run("no timeout", func(t *testing.T) {
+		req := invokev1.NewInvokeMethodRequest("method")
+		timeout, err := dm.invocationTimeout("app2", req)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), timeout)
+	})
+
+	t.Run("timeout header overrides resiliency policy", func(t *testing.T) {
+		req := invokev1.NewInvokeMethodRequest("method").WithMetadata(map[string][]string{
+			"Dapr-Timeout-Ms": {"150"},
+		})
+		timeout, err := dm.invocationTimeout("app1", req)
+		assert.NoError(t, err)
+		assert.Equal(t, 150*time.Millisecond, timeout)
+	})
+
+	t.Run("invalid timeout header", func(t *testing.T) {
+		for _, v := range []string{"abc", "0", "-5"} {
+			req := invokev1.NewInvokeMethodRequest("method").WithMetadata(map[string][]string{
+				invokev1.TimeoutHeader: {v},
+			})
+			_, err := dm.invocationTimeout("app1", req)
+			assert.Error(t, err)
+		}
+	})
+}
+
+func TestShouldFallBack(t *testing.T) {
+	assert.True(t, shouldFallBack(status.Error(codes.Unavailable, "unavailable")))
+	assert.True(t, shouldFallBack(status.Error(codes.DeadlineExceeded, "timeout")))
+	assert.True(t, shouldFallBack(status.Error(codes.ResourceExhausted, "concurrency limit exceeded")))
+	assert.False(t, shouldFallBack(status.Error(codes.InvalidArgument, "invalid")))
+	assert.False(t, shouldFallBack(status.Error(codes.PermissionDenied, "denied")))
+	assert.False(t, shouldFallBack(errors.New("unknown")))
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package messaging
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/config"
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	"github.com/dapr/dapr/pkg/retry"
+)
+
+// clusterSeparator separates the app id from the name of the remote cluster hosting it, e.g. app1@east.
+const clusterSeparator = "@"
+
+// GatewayRoutes routes service invocation of app ids hosted in remote clusters to the Dapr gateway of these clusters.
+// A nil GatewayRoutes routes nothing.
+type GatewayRoutes struct {
+	clusters map[string]remoteApp
+	apps     map[string]string
+}
+
+// NewGatewayRoutes returns the gateway routes of the remote clusters defined in a gateway spec.
+func NewGatewayRoutes(spec config.GatewaySpec, defaultNamespace string) (*GatewayRoutes, error) {
+	g := &GatewayRoutes{
+		clusters: map[string]remoteApp{},
+		apps:     map[string]string{},
+	}
+
+	for _, c := range spec.Clusters {
+		if c.Name == "" {
+			return nil, errors.New("remote cluster name is required")
+		}
+		if c.Address == "" || c.AppID == "" {
+			return nil, errors.Errorf("remote cluster %s requires the address and app id of its gateway", c.Name)
+		}
+		if _, ok := g.clusters[c.Name]; ok {
+			return nil, errors.Errorf("remote cluster %s is defined more than once", c.Name)
+		}
+
+		namespace := c.Namespace
+		if namespace == "" {
+			namespace = defaultNamespace
+		}
+		g.clusters[c.Name] = remoteApp{
+			id:        c.AppID,
+			namespace: namespace,
+			address:   c.Address,
+		}
+
+		for _, app := range c.Apps {
+			if cluster, ok := g.apps[app]; ok {
+				return nil, errors.Errorf("app id %s is hosted by both remote clusters %s and %s", app, cluster, c.Name)
+			}
+			g.apps[app] = c.Name
+		}
+	}
+
+	return g, nil
+}
+
+// route returns the target app id stripped from its cluster name and, when the app is hosted in a remote cluster,
+// the gateway of that cluster.
+func (g *GatewayRoutes) route(targetAppID string) (string, *remoteApp, error) {
+	appID := targetAppID
+	cluster := ""
+	if i := strings.LastIndex(targetAppID, clusterSeparator); i >= 0 {
+		appID, cluster = targetAppID[:i], targetAppID[i+1:]
+	} else if g != nil {
+		cluster = g.apps[appID]
+	}
+
+	if cluster == "" {
+		return appID, nil, nil
+	}
+	if g == nil {
+		return "", nil, errors.Errorf("remote cluster %s not found", cluster)
+	}
+	gateway, ok := g.clusters[cluster]
+	if !ok {
+		return "", nil, errors.Errorf("remote cluster %s not found", cluster)
+	}
+	return appID, &gateway, nil
+}
+
+// invokeGateway sends the request to the gateway of the remote cluster hosting the target app.
+// The connection is established with the identity of the gateway while the request carries the
+// fully qualified id of the target app, which the gateway uses to forward it.
+func (d *directMessaging) invokeGateway(ctx context.Context, gateway remoteApp, target remoteApp, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+	fn := func(ctx context.Context, appID, namespace, appAddress string, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+		d.addForwardedHeadersToMetadata(req)
+		d.addDestinationAppIDHeaderToMetadata(target.id+"."+target.namespace, req)
+
+		return d.callRemote(ctx, appID, namespace, appAddress, req)
+	}
+	return d.invokeWithRetry(ctx, retry.DefaultLinearRetryCount, retry.DefaultLinearBackoffInterval, gateway, fn, req)
+}
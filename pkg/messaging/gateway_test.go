@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package messaging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func TestGatewayRoutes(t *testing.T) {
+	spec := config.GatewaySpec{
+		Clusters: []config.RemoteClusterSpec{
+			{
+				Name:    "east",
+				Address: "gateway.east.example.com:50002",
+				AppID:   "dapr-gateway",
+				Apps:    []string{"app1"},
+			},
+		},
+	}
+
+	g, err := NewGatewayRoutes(spec, "default")
+	assert.NoError(t, err)
+
+	t.Run("app listed in remote cluster", func(t *testing.T) {
+		appID, gateway, err := g.route("app1")
+		assert.NoError(t, err)
+		assert.Equal(t, "app1", appID)
+		assert.Equal(t, &remoteApp{id: "dapr-gateway", namespace: "default", address: "gateway.east.example.com:50002"}, gateway)
+	})
+
+	t.Run("app addressed with cluster name", func(t *testing.T) {
+		appID, gateway, err := g.route("app2.ns1@east")
+		assert.NoError(t, err)
+		assert.Equal(t, "app2.ns1", appID)
+		assert.NotNil(t, gateway)
+	})
+
+	t.Run("local app", func(t *testing.T) {
+		appID, gateway, err := g.route("app2")
+		assert.NoError(t, err)
+		assert.Equal(t, "app2", appID)
+		assert.Nil(t, gateway)
+	})
+
+	t.Run("unknown cluster", func(t *testing.T) {
+		_, _, err := g.route("app2@west")
+		assert.Error(t, err)
+	})
+
+	t.Run("nil routes", func(t *testing.T) {
+		var routes *GatewayRoutes
+		appID, gateway, err := routes.route("app1")
+		assert.NoError(t, err)
+		assert.Equal(t, "app1", appID)
+		assert.Nil(t, gateway)
+	})
+
+	t.Run("invalid spec", func(t *testing.T) {
+		for _, clusters := range [][]config.RemoteClusterSpec{
+			{{Address: "a:1", AppID: "gw"}},
+			{{Name: "east", AppID: "gw"}},
+			{{Name: "east", Address: "a:1", AppID: "gw"}, {Name: "east", Address: "b:1", AppID: "gw"}},
+			{{Name: "east", Address: "a:1", AppID: "gw", Apps: []string{"app1"}}, {Name: "west", Address: "b:1", AppID: "gw", Apps: []string{"app1"}}},
+		} {
+			_, err := NewGatewayRoutes(config.GatewaySpec{Clusters: clusters}, "default")
+			assert.Error(t, err)
+		}
+	})
+}
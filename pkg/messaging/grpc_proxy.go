@@ -87,7 +87,7 @@ func (p *proxy) intercept(ctx context.Context, fullName string) (context.Context
 	if target.id == p.appID {
 		// proxy locally to the app
 		if p.acl != nil {
-			ok, authError := acl.ApplyAccessControlPolicies(ctx, fullName, common.HTTPExtension_NONE, config.GRPCProtocol, p.acl)
+			ok, authError := acl.ApplyAccessControlPolicies(ctx, fullName, common.HTTPExtension_NONE, "", config.GRPCProtocol, p.acl)
 			if !ok {
 				return ctx, nil, status.Errorf(codes.PermissionDenied, authError)
 			}
@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package messaging
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	internalv1pb "github.com/dapr/dapr/pkg/proto/internals/v1"
+	"github.com/dapr/dapr/pkg/resiliency"
+	"github.com/dapr/dapr/pkg/retry"
+)
+
+type hedgeResult struct {
+	address string
+	resp    *invokev1.InvokeMethodResponse
+	err     error
+}
+
+// invokeHedged sends the request to the resolved instance of the target app and, every time the
+// policy delay elapses without a response, sends another attempt to a different healthy instance.
+// Every attempt sends its own copy of the request and is retried like a non-hedged invocation.
+// The invocation is recorded once in the retry budget of the app, and every hedged attempt is withdrawn from the
+// budget like a retry: no more attempts are sent once the budget is exhausted.
+// The first successful response is returned and the outstanding attempts are cancelled.
+func (d *directMessaging) invokeHedged(
+	ctx context.Context,
+	policy *resiliency.HedgingPolicy,
+	app remoteApp,
+	fn func(ctx context.Context, appID, namespace, appAddress string, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error),
+	req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+	d.addForwardedHeadersToMetadata(req)
+	d.addDestinationAppIDHeaderToMetadata(app.id, req)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	budget := d.resiliency.AppRetryBudget(app.id)
+	budget.Request()
+
+	results := make(chan hedgeResult, policy.MaxAttempts)
+	tried := make([]string, 0, policy.MaxAttempts)
+	launch := func(address string) {
+		tried = append(tried, address)
+		// Attempts run concurrently, so each one gets a copy of the request it can modify freely.
+		attemptReq, err := invokev1.InternalInvokeRequest(proto.Clone(req.Proto()).(*internalv1pb.InternalInvokeRequest))
+		if err != nil {
+			results <- hedgeResult{address: address, err: err}
+			return
+		}
+		target := app
+		target.address = address
+		go func() {
+			resp, err := d.retryInvocation(ctx, retry.DefaultLinearRetryCount, retry.DefaultLinearBackoffInterval, target, fn, attemptReq)
+			results <- hedgeResult{address: address, resp: resp, err: err}
+		}()
+	}
+	launchNext := func() bool {
+		if len(tried) >= policy.MaxAttempts {
+			return false
+		}
+		address, err := d.balancer.pick(app.id, app.endpoints, tried...)
+		if err != nil {
+			return false
+		}
+		if !budget.TryRetry() {
+			log.Debugf("not hedging the invocation of app %s, its retry budget is exhausted", app.id)
+			return false
+		}
+		log.Debugf("hedging invocation of app %s to %s", app.id, address)
+		launch(address)
+		return true
+	}
+
+	launch(app.address)
+	inflight := 1
+
+	timer := time.NewTimer(policy.Delay)
+	defer timer.Stop()
+
+	var lastErr error
+	for {
+		select {
+		case res := <-results:
+			inflight--
+			if res.err == nil {
+				return res.resp, nil
+			}
+			log.Debugf("hedged invocation of app %s to %s failed: %s", app.id, res.address, res.err)
+			lastErr = res.err
+			if inflight == 0 {
+				if !launchNext() {
+					return nil, lastErr
+				}
+				inflight++
+			}
+		case <-timer.C:
+			if launchNext() {
+				inflight++
+				timer.Reset(policy.Delay)
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
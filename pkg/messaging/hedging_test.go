@@ -0,0 +1,176 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package messaging
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	nr_loader "github.com/dapr/dapr/pkg/components/nameresolution"
+	"github.com/dapr/dapr/pkg/config"
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	"github.com/dapr/dapr/pkg/resiliency"
+)
+
+func newHedgingTestApp() remoteApp {
+	return remoteApp{
+		id:      "app1",
+		address: "slow:50002",
+		endpoints: []nr_loader.Endpoint{
+			{Address: "slow:50002", Healthy: true},
+			{Address: "fast:50002", Healthy: true},
+		},
+	}
+}
+
+func TestInvokeHedged(t *testing.T) {
+	policy := &resiliency.HedgingPolicy{Delay: 10 * time.Millisecond, MaxAttempts: 2}
+
+	t.Run("slow instance is hedged", func(t *testing.T) {
+		dm := newDirectMessaging()
+		dm.balancer = newEndpointBalancer()
+
+		var lock sync.Mutex
+		called := []string{}
+		fn := func(ctx context.Context, appID, namespace, appAddress string, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+			lock.Lock()
+			called = append(called, appAddress)
+			lock.Unlock()
+			if appAddress == "slow:50002" {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return invokev1.NewInvokeMethodResponse(200, "", nil), nil
+		}
+
+		req := invokev1.NewInvokeMethodRequest("method")
+		req.WithMetadata(map[string][]string{})
+		resp, err := dm.invokeHedged(context.Background(), policy, newHedgingTestApp(), fn, req)
+		assert.NoError(t, err)
+		assert.NotNil(t, resp)
+
+		lock.Lock()
+		defer lock.Unlock()
+		assert.Equal(t, []string{"slow:50002", "fast:50002"}, called)
+	})
+
+	t.Run("fast response is not hedged", func(t *testing.T) {
+		dm := newDirectMessaging()
+		dm.balancer = newEndpointBalancer()
+
+		calls := 0
+		fn := func(ctx context.Context, appID, namespace, appAddress string, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+			calls++
+			return invokev1.NewInvokeMethodResponse(200, "", nil), nil
+		}
+
+		req := invokev1.NewInvokeMethodRequest("method")
+		req.WithMetadata(map[string][]string{})
+		_, err := dm.invokeHedged(context.Background(), policy, newHedgingTestApp(), fn, req)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("all attempts fail", func(t *testing.T) {
+		dm := newDirectMessaging()
+		dm.balancer = newEndpointBalancer()
+
+		fn := func(ctx context.Context, appID, namespace, appAddress string, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+			return nil, errors.New("failed " + appAddress)
+		}
+
+		req := invokev1.NewInvokeMethodRequest("method")
+		req.WithMetadata(map[string][]string{})
+		_, err := dm.invokeHedged(context.Background(), policy, newHedgingTestApp(), fn, req)
+		assert.Error(t, err)
+	})
+
+	t.Run("attempts get their own copy of the request", func(t *testing.T) {
+		dm := newDirectMessaging()
+		dm.balancer = newEndpointBalancer()
+
+		var lock sync.Mutex
+		received := []*invokev1.InvokeMethodRequest{}
+		fn := func(ctx context.Context, appID, namespace, appAddress string, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+			lock.Lock()
+			received = append(received, req)
+			lock.Unlock()
+			if appAddress == "slow:50002" {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return invokev1.NewInvokeMethodResponse(200, "", nil), nil
+		}
+
+		req := invokev1.NewInvokeMethodRequest("method")
+		req.WithMetadata(map[string][]string{})
+		_, err := dm.invokeHedged(context.Background(), policy, newHedgingTestApp(), fn, req)
+		assert.NoError(t, err)
+
+		lock.Lock()
+		defer lock.Unlock()
+		assert.Len(t, received, 2)
+		assert.NotSame(t, req, received[0])
+		assert.NotSame(t, req, received[1])
+		assert.NotSame(t, received[0], received[1])
+		assert.Equal(t, "app1", received[1].Metadata()[invokev1.DestinationIDHeader].Values[0])
+	})
+
+	t.Run("hedges are withdrawn from the retry budget", func(t *testing.T) {
+		dm := newDirectMessaging()
+		dm.balancer = newEndpointBalancer()
+		r, err := resiliency.FromConfiguration(config.ResiliencySpec{
+			Policies: config.ResiliencyPolicies{
+				RetryBudgets: map[string]config.RetryBudgetSpec{
+					"budget": {Ratio: 0.1, MinRetriesPerSecond: 1, TTL: "1s"},
+				},
+			},
+			Targets: config.ResiliencyTargets{
+				Apps: map[string]config.AppResiliencyTarget{
+					"app1": {RetryBudget: "budget"},
+				},
+			},
+		})
+		assert.NoError(t, err)
+		dm.resiliency = r
+		// Exhausts the budget, which allows a single retry for less than 10 requests.
+		assert.True(t, r.AppRetryBudget("app1").TryRetry())
+
+		var lock sync.Mutex
+		called := []string{}
+		fn := func(ctx context.Context, appID, namespace, appAddress string, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+			lock.Lock()
+			called = append(called, appAddress)
+			lock.Unlock()
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		req := invokev1.NewInvokeMethodRequest("method")
+		req.WithMetadata(map[string][]string{})
+		_, err = dm.invokeHedged(ctx, policy, newHedgingTestApp(), fn, req)
+		assert.Error(t, err)
+
+		lock.Lock()
+		defer lock.Unlock()
+		assert.Equal(t, []string{"slow:50002"}, called)
+	})
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package messaging
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	internalv1pb "github.com/dapr/dapr/pkg/proto/internals/v1"
+	"github.com/dapr/dapr/pkg/resiliency"
+)
+
+// defaultMirrorTimeout bounds mirrored invocations when no timeout policy applies to the shadow app.
+const defaultMirrorTimeout = 30 * time.Second
+
+// mirror sends a copy of the request to the shadow app of the policy for the configured percentage of invocations.
+// The mirrored invocation runs in the background, is not retried and its response is discarded.
+// It returns true if the request was mirrored.
+func (d *directMessaging) mirror(
+	policy *resiliency.MirroringPolicy,
+	fn func(ctx context.Context, appID, namespace, appAddress string, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error),
+	req *invokev1.InvokeMethodRequest) bool {
+	if rand.Float64()*100 >= policy.Percentage { // nolint:gosec
+		return false
+	}
+
+	// The original request is modified by its own invocation, so the shadow app receives a copy.
+	mirrored, err := invokev1.InternalInvokeRequest(proto.Clone(req.Proto()).(*internalv1pb.InternalInvokeRequest))
+	if err != nil {
+		log.Debugf("failed to mirror invocation to app %s: %s", policy.AppID, err)
+		return false
+	}
+
+	go func() {
+		timeout := d.resiliency.AppTimeout(policy.AppID, mirrored.Message().GetMethod())
+		if timeout == 0 {
+			timeout = defaultMirrorTimeout
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		app, err := d.getRemoteApp(policy.AppID)
+		if err != nil {
+			log.Debugf("failed to mirror invocation to app %s: %s", policy.AppID, err)
+			return
+		}
+		if _, err = fn(ctx, app.id, app.namespace, app.address, mirrored); err != nil {
+			log.Debugf("mirrored invocation of app %s failed: %s", app.id, err)
+		}
+	}()
+	return true
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package messaging
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	nr_loader "github.com/dapr/dapr/pkg/components/nameresolution"
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	"github.com/dapr/dapr/pkg/resiliency"
+)
+
+func TestMirror(t *testing.T) {
+	t.Run("request is mirrored to the shadow app", func(t *testing.T) {
+		dm := newDirectMessaging()
+		dm.balancer = newEndpointBalancer()
+		dm.resolver = &mockEndpointResolver{
+			endpoints: []nr_loader.Endpoint{{Address: "shadow:50002", Healthy: true}},
+		}
+
+		mirrored := make(chan *invokev1.InvokeMethodRequest, 1)
+		fn := func(ctx context.Context, appID, namespace, appAddress string, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+			assert.Equal(t, "app1-v2", appID)
+			assert.Equal(t, "shadow:50002", appAddress)
+			mirrored <- req
+			return invokev1.NewInvokeMethodResponse(200, "", nil), nil
+		}
+
+		req := invokev1.NewInvokeMethodRequest("method")
+		req.WithMetadata(map[string][]string{})
+		policy := &resiliency.MirroringPolicy{AppID: "app1-v2", Percentage: 100}
+		assert.True(t, dm.mirror(policy, fn, req))
+
+		select {
+		case m := <-mirrored:
+			assert.Equal(t, "method", m.Message().Method)
+			assert.NotSame(t, req.Proto(), m.Proto())
+		case <-time.After(time.Second):
+			assert.Fail(t, "request was not mirrored")
+		}
+	})
+
+	t.Run("request is not mirrored outside of the percentage", func(t *testing.T) {
+		dm := newDirectMessaging()
+		fn := func(ctx context.Context, appID, namespace, appAddress string, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+			assert.Fail(t, "request should not be mirrored")
+			return nil, nil
+		}
+
+		req := invokev1.NewInvokeMethodRequest("method")
+		policy := &resiliency.MirroringPolicy{AppID: "app1-v2", Percentage: 0}
+		assert.False(t, dm.mirror(policy, fn, req))
+	})
+}
@@ -58,6 +58,16 @@ const (
 	// DestinationIDHeader is the header carrying the value of the invoked app id.
 	DestinationIDHeader = "destination-app-id"
 
+	// CallerSpiffeIDHeader is the header carrying the SPIFFE ID of the original caller of an invocation forwarded by a
+	// gateway from a remote cluster.
+	CallerSpiffeIDHeader = "dapr-caller-spiffe-id"
+
+	// TimeoutHeader is the header carrying the timeout of a service invocation, in milliseconds.
+	TimeoutHeader = "dapr-timeout-ms"
+
+	// AcceptEncodingHeader is the header listing the compressors a Dapr sidecar accepts on the internal gRPC channel.
+	AcceptEncodingHeader = "dapr-accept-encoding"
+
 	// ErrorInfo metadata value is limited to 64 chars
 	// https://github.com/googleapis/googleapis/blob/master/google/rpc/error_details.proto#L126
 	maxMetadataValueLen = 63
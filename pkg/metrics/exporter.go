@@ -1,13 +1,19 @@
 package metrics
 
 import (
+	"crypto/tls"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 
 	ocprom "contrib.go.opencensus.io/exporter/prometheus"
 	"github.com/pkg/errors"
 	prom "github.com/prometheus/client_golang/prometheus"
 
+	"github.com/dapr/dapr/pkg/credentials"
+	diag "github.com/dapr/dapr/pkg/diagnostics"
 	"github.com/dapr/kit/logger"
 )
 
@@ -23,6 +29,9 @@ type Exporter interface {
 	Init() error
 	// Options returns Exporter options
 	Options() *Options
+	// SetCertificateSource sets the source of the certificate of the metrics server when TLS is enabled without
+	// credentials directory. It must be called before Init.
+	SetCertificateSource(source credentials.CertificateSource)
 }
 
 // NewExporter creates new MetricsExporter instance.
@@ -43,6 +52,8 @@ type exporter struct {
 	namespace string
 	options   *Options
 	logger    logger.Logger
+	// certSource is the source of the certificate of the metrics server.
+	certSource credentials.CertificateSource
 }
 
 // Options returns current metric exporter options.
@@ -50,6 +61,45 @@ func (m *exporter) Options() *Options {
 	return m.options
 }
 
+// SetCertificateSource sets the source of the certificate of the metrics server.
+func (m *exporter) SetCertificateSource(source credentials.CertificateSource) {
+	m.certSource = source
+}
+
+// tlsConfig returns the TLS config of the metrics server, or nil when TLS is disabled.
+func (m *exporter) tlsConfig() (*tls.Config, error) {
+	source := m.certSource
+	if m.options.TLSCredentialsPath != "" {
+		source = credentials.FileCertificateSource(credentials.NewTLSCredentials(m.options.TLSCredentialsPath))
+	}
+
+	config, err := credentials.ServerTLSConfig(m.options.TLSMode, source)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid metrics server TLS configuration")
+	}
+	return config, nil
+}
+
+// initOTLPExporter starts pushing the metrics to the OpenTelemetry collector of the options, if any.
+func (m *exporter) initOTLPExporter() error {
+	if m.options.OTLPEndpoint == "" {
+		return nil
+	}
+
+	otlpExporter, err := diag.NewOTLPMetricsExporter(diag.OTLPMetricsOptions{
+		EndpointAddress: m.options.OTLPEndpoint,
+		ServiceName:     filepath.Base(os.Args[0]),
+	})
+	if err != nil {
+		return err
+	}
+	if err = otlpExporter.Start(); err != nil {
+		return errors.Errorf("failed to start OTLP metrics exporter: %v", err)
+	}
+	m.logger.Infof("pushing metrics to OTLP endpoint %s", m.options.OTLPEndpoint)
+	return nil
+}
+
 // promMetricsExporter is prometheus metric exporter.
 type promMetricsExporter struct {
 	*exporter
@@ -62,6 +112,10 @@ func (m *promMetricsExporter) Init() error {
 		return nil
 	}
 
+	if err := m.exporter.initOTLPExporter(); err != nil {
+		return err
+	}
+
 	var err error
 	if m.ocExporter, err = ocprom.NewExporter(ocprom.Options{
 		Namespace: m.namespace,
@@ -87,12 +141,25 @@ func (m *promMetricsExporter) startMetricServer() error {
 		return errors.New("exporter was not initialized")
 	}
 
-	m.exporter.logger.Infof("metrics server started on %s%s", addr, defaultMetricsPath)
+	tlsConfig, err := m.exporter.tlsConfig()
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.Errorf("failed to start metrics server: %v", err)
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	m.exporter.logger.Infof("metrics server started on %s%s (TLS mode: %s)", addr, defaultMetricsPath, m.options.TLSMode)
 	go func() {
 		mux := http.NewServeMux()
 		mux.Handle(defaultMetricsPath, m.ocExporter)
 
-		if err := http.ListenAndServe(addr, mux); err != nil {
+		if err := http.Serve(listener, mux); err != nil {
 			m.exporter.logger.Fatalf("failed to start metrics server: %v", err)
 		}
 	}()
@@ -14,10 +14,14 @@ limitations under the License.
 package metrics
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/dapr/dapr/pkg/credentials"
 	"github.com/dapr/kit/logger"
 )
 
@@ -40,6 +44,52 @@ func TestMetricsExporter(t *testing.T) {
 		assert.Error(t, e.startMetricServer())
 	})
 
+	t.Run("return error if OTLP endpoint is invalid", func(t *testing.T) {
+		e := NewExporter("test")
+		e.Options().MetricsEnabled = true
+		e.Options().OTLPEndpoint = "otel-collector:4318"
+		assert.Error(t, e.Init())
+	})
+
+	t.Run("OTLP exporter is not started if metrics are disabled", func(t *testing.T) {
+		e := NewExporter("test")
+		e.Options().MetricsEnabled = false
+		e.Options().OTLPEndpoint = "otel-collector:4318"
+		assert.NoError(t, e.Init())
+	})
+
+	t.Run("return error if TLS mode is invalid", func(t *testing.T) {
+		e := &exporter{options: defaultMetricOptions()}
+		e.options.TLSMode = "strict"
+		_, err := e.tlsConfig()
+		assert.Error(t, err)
+	})
+
+	t.Run("return error if TLS is enabled without certificate", func(t *testing.T) {
+		e := &exporter{options: defaultMetricOptions()}
+		e.options.TLSMode = credentials.ServerTLSMutual
+		_, err := e.tlsConfig()
+		assert.Error(t, err)
+	})
+
+	t.Run("TLS config of the certificate source", func(t *testing.T) {
+		e := &exporter{options: defaultMetricOptions()}
+		e.options.TLSMode = credentials.ServerTLSEnabled
+		e.SetCertificateSource(func() (*tls.Certificate, *x509.CertPool, error) {
+			return nil, nil, errors.New("not issued")
+		})
+		config, err := e.tlsConfig()
+		assert.NoError(t, err)
+		assert.NotNil(t, config)
+	})
+
+	t.Run("TLS disabled by default", func(t *testing.T) {
+		e := &exporter{options: defaultMetricOptions()}
+		config, err := e.tlsConfig()
+		assert.NoError(t, err)
+		assert.Nil(t, config)
+	})
+
 	t.Run("skip starting metric server", func(t *testing.T) {
 		e := NewExporter("test")
 		e.Options().MetricsEnabled = false
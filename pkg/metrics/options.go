@@ -15,11 +15,14 @@ package metrics
 
 import (
 	"strconv"
+
+	"github.com/dapr/dapr/pkg/credentials"
 )
 
 const (
 	defaultMetricsPort    = "9090"
 	defaultMetricsEnabled = true
+	defaultMetricsTLSMode = credentials.ServerTLSDisabled
 )
 
 // Options defines the sets of options for Dapr logging.
@@ -28,12 +31,24 @@ type Options struct {
 	MetricsEnabled bool
 
 	Port string
+
+	// OTLPEndpoint is the URL of an OpenTelemetry collector the metrics are pushed to, in addition to the
+	// Prometheus endpoint.
+	OTLPEndpoint string
+
+	// TLSMode is the TLS mode of the metrics server: disabled, tls or mtls.
+	TLSMode string
+
+	// TLSCredentialsPath is the directory holding the certificate, key and trust anchors of the metrics server.
+	// When empty, the certificate of the exporter source is used.
+	TLSCredentialsPath string
 }
 
 func defaultMetricOptions() *Options {
 	return &Options{
 		Port:           defaultMetricsPort,
 		MetricsEnabled: defaultMetricsEnabled,
+		TLSMode:        defaultMetricsTLSMode,
 	}
 }
 
@@ -62,6 +77,21 @@ func (o *Options) AttachCmdFlags(
 		"enable-metrics",
 		defaultMetricsEnabled,
 		"Enable prometheus metric")
+	stringVar(
+		&o.OTLPEndpoint,
+		"metrics-otlp-endpoint",
+		"",
+		"The URL of the OTLP/HTTP receiver of an OpenTelemetry collector to push metrics to")
+	stringVar(
+		&o.TLSMode,
+		"metrics-tls-mode",
+		defaultMetricsTLSMode,
+		"The TLS mode of the metrics server: disabled, tls or mtls")
+	stringVar(
+		&o.TLSCredentialsPath,
+		"metrics-tls-credentials",
+		"",
+		"Path to the credentials directory holding the certificate, key and trust anchors of the metrics server")
 }
 
 // AttachCmdFlag attaches single metrics option to command flags.
@@ -31,10 +31,14 @@ func TestOptions(t *testing.T) {
 		o := defaultMetricOptions()
 
 		metricsPortAsserted := false
+		otlpEndpointAsserted := false
 		testStringVarFn := func(p *string, name string, value string, usage string) {
 			if name == "metrics-port" && value == defaultMetricsPort {
 				metricsPortAsserted = true
 			}
+			if name == "metrics-otlp-endpoint" && value == "" {
+				otlpEndpointAsserted = true
+			}
 		}
 
 		metricsEnabledAsserted := false
@@ -49,6 +53,7 @@ func TestOptions(t *testing.T) {
 		// assert
 		assert.True(t, metricsPortAsserted)
 		assert.True(t, metricsEnabledAsserted)
+		assert.True(t, otlpEndpointAsserted)
 	})
 
 	t.Run("parse valid port", func(t *testing.T) {
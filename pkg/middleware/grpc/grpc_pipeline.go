@@ -0,0 +1,30 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpc
+
+import (
+	grpc_go "google.golang.org/grpc"
+)
+
+// Pipeline defines the interceptors plugged into the gRPC API server of the Dapr sidecar by the applications
+// embedding the runtime.
+type Pipeline struct {
+	UnaryInterceptors  []grpc_go.UnaryServerInterceptor
+	StreamInterceptors []grpc_go.StreamServerInterceptor
+}
+
+// IsEmpty returns true when the pipeline has no interceptor.
+func (p Pipeline) IsEmpty() bool {
+	return len(p.UnaryInterceptors) == 0 && len(p.StreamInterceptors) == 0
+}
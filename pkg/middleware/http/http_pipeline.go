@@ -14,16 +14,38 @@ limitations under the License.
 package http
 
 import (
+	"encoding/base64"
+	"path"
+	"strings"
+
 	"github.com/valyala/fasthttp"
 
 	"github.com/dapr/dapr/pkg/config"
 )
 
+const (
+	appIDHeader     = "dapr-app-id"
+	apiVersionV1    = "v1.0"
+	invokePathStart = "/" + apiVersionV1 + "/invoke/"
+)
+
 type Middleware func(h fasthttp.RequestHandler) fasthttp.RequestHandler
 
 // HTTPPipeline defines the middleware pipeline to be plugged into Dapr sidecar.
 type Pipeline struct {
 	Handlers []Middleware
+	// Routes are applied after the handlers, a request going through the chain of the first route it matches.
+	Routes []RoutePipeline
+}
+
+// RoutePipeline is the middleware chain of the requests matching a path pattern and methods.
+type RoutePipeline struct {
+	// Path is a glob pattern of path.Match matching the leading segments of the request paths,
+	// so /v1.0/invoke/* matches every service invocation.
+	Path string
+	// Methods are the methods of the requests going through the chain, any method if it is empty.
+	Methods  []string
+	Handlers []Middleware
 }
 
 func BuildHTTPPipeline(spec config.PipelineSpec) (Pipeline, error) {
@@ -31,8 +53,108 @@ func BuildHTTPPipeline(spec config.PipelineSpec) (Pipeline, error) {
 }
 
 func (p Pipeline) Apply(handler fasthttp.RequestHandler) fasthttp.RequestHandler {
-	for i := len(p.Handlers) - 1; i >= 0; i-- {
-		handler = p.Handlers[i](handler)
+	if len(p.Routes) > 0 {
+		handler = applyRoutes(p.Routes, handler)
+	}
+	return applyHandlers(p.Handlers, handler)
+}
+
+// ValidatePath returns an error if the path pattern of a route is malformed.
+func ValidatePath(pattern string) error {
+	_, err := path.Match(pattern, "")
+	return err
+}
+
+// Matches returns whether a request with the given method and path goes through the chain of the route.
+func (r RoutePipeline) Matches(method, requestPath string) bool {
+	if len(r.Methods) > 0 {
+		found := false
+		for _, m := range r.Methods {
+			if strings.EqualFold(m, method) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	// The pattern is matched against as many leading segments of the path as it has.
+	segments := strings.Count(strings.TrimSuffix(r.Path, "/"), "/")
+	prefix := requestPath
+	for i, n := 0, 0; i < len(requestPath); i++ {
+		if requestPath[i] == '/' {
+			n++
+			if n > segments {
+				prefix = requestPath[:i]
+				break
+			}
+		}
+	}
+	ok, _ := path.Match(r.Path, prefix)
+	return ok
+}
+
+func applyRoutes(routes []RoutePipeline, handler fasthttp.RequestHandler) fasthttp.RequestHandler {
+	routeHandlers := make([]fasthttp.RequestHandler, len(routes))
+	for i, route := range routes {
+		routeHandlers[i] = applyHandlers(route.Handlers, handler)
+	}
+
+	return func(ctx *fasthttp.RequestCtx) {
+		method := string(ctx.Method())
+		requestPath := routePath(ctx)
+		for i, route := range routes {
+			if route.Matches(method, requestPath) {
+				routeHandlers[i](ctx)
+				return
+			}
+		}
+		handler(ctx)
+	}
+}
+
+// routePath returns the path the routes are matched against. The service invocations addressed to an app by the
+// dapr-app-id header or by Basic authentication are served on any path outside of the APIs, and are matched as the
+// /v1.0/invoke/<id>/method/<path> requests they are served like, so that they don't bypass the chains of the
+// invocations.
+func routePath(ctx *fasthttp.RequestCtx) string {
+	// The path is decoded and normalized like the router matches it.
+	requestPath := string(ctx.Path())
+	if segment := strings.SplitN(strings.TrimPrefix(requestPath, "/"), "/", 2)[0]; strings.HasPrefix(segment, apiVersionV1) {
+		return requestPath
+	}
+	if appID := headerAppID(&ctx.Request.Header); appID != "" {
+		return invokePathStart + appID + "/method" + requestPath
+	}
+	return requestPath
+}
+
+// headerAppID returns the id of the app a request is addressed to by the dapr-app-id header, or by the Basic
+// authentication of the dapr-app-id user.
+func headerAppID(header *fasthttp.RequestHeader) string {
+	if appID := header.Peek(appIDHeader); appID != nil {
+		return string(appID)
+	}
+	auth := string(header.Peek(fasthttp.HeaderAuthorization))
+	if !strings.HasPrefix(auth, "Basic ") {
+		return ""
+	}
+	s, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(auth, "Basic "))
+	if err != nil {
+		return ""
+	}
+	pair := strings.Split(string(s), ":")
+	if len(pair) == 2 && pair[0] == appIDHeader {
+		return pair[1]
+	}
+	return ""
+}
+
+func applyHandlers(handlers []Middleware, handler fasthttp.RequestHandler) fasthttp.RequestHandler {
+	for i := len(handlers) - 1; i >= 0; i-- {
+		handler = handlers[i](handler)
 	}
 	return handler
 }
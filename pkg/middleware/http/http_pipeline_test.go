@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+// tag returns a middleware appending its name to the calls of the request.
+func tag(name string) Middleware {
+	return func(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			calls, _ := ctx.UserValue("calls").([]string)
+			ctx.SetUserValue("calls", append(calls, name))
+			h(ctx)
+		}
+	}
+}
+
+func calls(h fasthttp.RequestHandler, method, path string) []string {
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(method)
+	ctx.Request.SetRequestURI(path)
+	h(ctx)
+	calls, _ := ctx.UserValue("calls").([]string)
+	return calls
+}
+
+func TestPipelineApply(t *testing.T) {
+	pipeline := Pipeline{
+		Handlers: []Middleware{tag("tracing")},
+		Routes: []RoutePipeline{
+			{Path: "/v1.0/invoke/*/method/admin", Handlers: []Middleware{tag("opa")}},
+			{Path: "/v1.0/invoke/*", Handlers: []Middleware{tag("oauth2"), tag("ratelimit")}},
+			{Path: "/v1.0/state/*", Methods: []string{"POST", "delete"}, Handlers: []Middleware{tag("bearer")}},
+		},
+	}
+	h := pipeline.Apply(tag("app")(func(*fasthttp.RequestCtx) {}))
+
+	assert.Equal(t, []string{"tracing", "oauth2", "ratelimit", "app"}, calls(h, "POST", "/v1.0/invoke/orders/method/new"))
+	assert.Equal(t, []string{"tracing", "opa", "app"}, calls(h, "GET", "/v1.0/invoke/orders/method/admin/users"))
+	assert.Equal(t, []string{"tracing", "app"}, calls(h, "GET", "/v1.0/healthz"))
+	assert.Equal(t, []string{"tracing", "app"}, calls(h, "GET", "/v1.0/metadata"))
+	assert.Equal(t, []string{"tracing", "bearer", "app"}, calls(h, "DELETE", "/v1.0/state/store/key"))
+	assert.Equal(t, []string{"tracing", "app"}, calls(h, "GET", "/v1.0/state/store/key"))
+}
+
+func TestPipelineApplyHeaderInvocations(t *testing.T) {
+	pipeline := Pipeline{
+		Routes: []RoutePipeline{
+			{Path: "/v1.0/invoke/*/method/admin", Handlers: []Middleware{tag("opa")}},
+			{Path: "/v1.0/invoke/*", Handlers: []Middleware{tag("oauth2")}},
+			{Path: "/v1.0/state/*", Handlers: []Middleware{tag("bearer")}},
+		},
+	}
+	h := pipeline.Apply(tag("app")(func(*fasthttp.RequestCtx) {}))
+
+	serve := func(path string, headers ...string) []string {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod("GET")
+		ctx.Request.SetRequestURI(path)
+		for i := 0; i < len(headers); i += 2 {
+			ctx.Request.Header.Set(headers[i], headers[i+1])
+		}
+		h(ctx)
+		calls, _ := ctx.UserValue("calls").([]string)
+		return calls
+	}
+
+	// the invocations addressed by header don't bypass the chains of the invocations.
+	assert.Equal(t, []string{"oauth2", "app"}, serve("/orders/new", "dapr-app-id", "orders"))
+	assert.Equal(t, []string{"opa", "app"}, serve("/admin/users", "dapr-app-id", "orders"))
+	assert.Equal(t, []string{"opa", "app"}, serve("/admin//users/", "dapr-app-id", "orders"))
+	basicAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte("dapr-app-id:orders"))
+	assert.Equal(t, []string{"opa", "app"}, serve("/admin", fasthttp.HeaderAuthorization, basicAuth))
+
+	// the APIs are matched on their own path.
+	assert.Equal(t, []string{"bearer", "app"}, serve("/v1.0/state/store/key", "dapr-app-id", "orders"))
+	assert.Equal(t, []string{"app"}, serve("/v1.0/healthz", "dapr-app-id", "orders"))
+	assert.Equal(t, []string{"app"}, serve("/orders/new"))
+	assert.Equal(t, []string{"opa", "app"}, serve("/v1.0/invoke/orders/method/./admin"))
+}
+
+func TestRoutePipelineMatches(t *testing.T) {
+	route := RoutePipeline{Path: "/v1.0/invoke/*"}
+
+	assert.True(t, route.Matches("GET", "/v1.0/invoke/orders"))
+	assert.True(t, route.Matches("GET", "/v1.0/invoke/orders/method/new"))
+	assert.False(t, route.Matches("GET", "/v1.0/invoke"))
+	assert.False(t, route.Matches("GET", "/v1.0/invokeorders/method/new"))
+	assert.False(t, route.Matches("GET", "/v1.0-alpha1/invoke/orders/method/new"))
+
+	route = RoutePipeline{Path: "/v1.0/healthz"}
+	assert.True(t, route.Matches("GET", "/v1.0/healthz"))
+	assert.True(t, route.Matches("GET", "/v1.0/healthz/outbound"))
+	assert.False(t, route.Matches("GET", "/v1.0/healthzz"))
+}
+
+func TestValidatePath(t *testing.T) {
+	assert.NoError(t, ValidatePath("/v1.0/invoke/*"))
+	assert.Error(t, ValidatePath("/v1.0/invoke/["))
+}
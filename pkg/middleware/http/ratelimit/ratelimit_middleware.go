@@ -0,0 +1,338 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dapr/components-contrib/middleware"
+	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	maxRequestsPerSecondKey = "maxRequestsPerSecond"
+	burstKey                = "burst"
+	storeNameKey            = "storeName"
+	keyByKey                = "keyBy"
+	keyPrefixKey            = "keyPrefix"
+	maxKeysKey              = "maxKeys"
+
+	keyByAppID        = "app-id"
+	keyByPath         = "path"
+	keyByHeaderPrefix = "header:"
+
+	appIDHeader     = "dapr-app-id"
+	invokePathPart  = "invoke"
+	keySeparator    = "||"
+	globalKey       = "global"
+	overflowKey     = "overflow"
+	ttlInSecondsKey = "ttlInSeconds"
+
+	// Defaults.
+	defaultMaxRequestsPerSecond = 100
+	defaultKeyPrefix            = "ratelimit"
+	defaultMaxKeys              = 10000
+
+	// maxETagRetries is the number of times a bucket update is retried when another replica updated it concurrently.
+	maxETagRetries = 5
+)
+
+// StoreLookup returns the state store with the given name, if it is loaded.
+type StoreLookup func(name string) (state.Store, bool)
+
+// Middleware is a ratelimit middleware sharing its token buckets across the replicas of an app through a state store.
+type Middleware struct {
+	logger logger.Logger
+	lookup StoreLookup
+	now    func() time.Time
+}
+
+// rateLimitMiddlewareMetadata is the ratelimit middleware config.
+type rateLimitMiddlewareMetadata struct {
+	MaxRequestsPerSecond float64
+	Burst                float64
+	StoreName            string
+	KeyBy                []string
+	KeyPrefix            string
+	MaxKeys              int
+}
+
+// bucket is the token bucket saved in the state store.
+type bucket struct {
+	Tokens  float64 `json:"tokens"`
+	Updated int64   `json:"updated"`
+}
+
+// NewRateLimitMiddleware returns a new distributed ratelimit middleware.
+func NewRateLimitMiddleware(logger logger.Logger, lookup StoreLookup) *Middleware {
+	return &Middleware{
+		logger: logger,
+		lookup: lookup,
+		now:    time.Now,
+	}
+}
+
+// GetHandler returns the HTTP handler provided by the middleware.
+func (m *Middleware) GetHandler(metadata middleware.Metadata) (func(h fasthttp.RequestHandler) fasthttp.RequestHandler, error) {
+	meta, err := getNativeMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := newKeySet(meta.MaxKeys, meta.window())
+	return func(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			key := meta.bucketKey(ctx)
+			if !keys.admit(key, m.now()) {
+				// The requests with attributes beyond the maximum number of keys share a single bucket.
+				key = meta.KeyPrefix + keySeparator + overflowKey
+			}
+			allowed, wait, err := m.take(meta, key)
+			if err != nil {
+				// The limit is not enforced rather than failing every request while the state store is unavailable.
+				m.logger.Warnf("ratelimit: failed to take a token from bucket %s, letting the request through: %s", key, err)
+				h(ctx)
+				return
+			}
+			if !allowed {
+				// The error resets the response, headers included.
+				ctx.Error(fasthttp.StatusMessage(fasthttp.StatusTooManyRequests), fasthttp.StatusTooManyRequests)
+				ctx.Response.Header.Set(fasthttp.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				return
+			}
+			h(ctx)
+		}
+	}, nil
+}
+
+// take takes a token from the bucket saved under key, refilling it for the time elapsed since its last update.
+// It returns whether the request is allowed and, if it is not, how long to wait for the next token.
+func (m *Middleware) take(meta *rateLimitMiddlewareMetadata, key string) (bool, time.Duration, error) {
+	store, ok := m.lookup(meta.StoreName)
+	if !ok {
+		return false, 0, errors.Errorf("state store %s is not found", meta.StoreName)
+	}
+
+	for i := 0; i < maxETagRetries; i++ {
+		res, err := store.Get(&state.GetRequest{
+			Key:     key,
+			Options: state.GetStateOption{Consistency: state.Strong},
+		})
+		if err != nil {
+			return false, 0, err
+		}
+
+		now := m.now()
+		b := bucket{Tokens: meta.Burst, Updated: now.UnixNano()}
+		var etag *string
+		if res != nil && len(res.Data) > 0 {
+			if err = json.Unmarshal(res.Data, &b); err != nil {
+				return false, 0, errors.Wrapf(err, "failed to decode bucket %s", key)
+			}
+			if elapsed := now.Sub(time.Unix(0, b.Updated)).Seconds(); elapsed > 0 {
+				b.Tokens = math.Min(meta.Burst, b.Tokens+elapsed*meta.MaxRequestsPerSecond)
+			}
+			b.Updated = now.UnixNano()
+			etag = res.ETag
+		}
+
+		if b.Tokens < 1 {
+			// Nothing is written: the refill is computed from the last update of the bucket.
+			return false, meta.waitFor(b.Tokens), nil
+		}
+		b.Tokens--
+
+		req := &state.SetRequest{
+			Key:   key,
+			Value: b,
+			ETag:  etag,
+			// A bucket left alone for its window is full again, the same as a bucket missing from the store.
+			Metadata: map[string]string{ttlInSecondsKey: strconv.Itoa(int(math.Ceil(meta.window().Seconds())))},
+		}
+		if etag != nil {
+			req.Options.Concurrency = state.FirstWrite
+		}
+		err = store.Set(req)
+		if err == nil {
+			return true, 0, nil
+		}
+		var etagErr *state.ETagError
+		if !errors.As(err, &etagErr) || etagErr.Kind() != state.ETagMismatch {
+			return false, 0, err
+		}
+	}
+
+	// The bucket is contended by more replicas than the retries can absorb: the request is denied like an empty bucket.
+	return false, meta.waitFor(0), nil
+}
+
+// window returns the time an empty bucket takes to refill, of at least a second.
+func (meta *rateLimitMiddlewareMetadata) window() time.Duration {
+	return time.Duration(math.Max(1, meta.Burst/meta.MaxRequestsPerSecond) * float64(time.Second))
+}
+
+// waitFor returns the time until the bucket holding tokens has a whole token.
+func (meta *rateLimitMiddlewareMetadata) waitFor(tokens float64) time.Duration {
+	return time.Duration((1 - tokens) / meta.MaxRequestsPerSecond * float64(time.Second))
+}
+
+// bucketKey returns the key of the bucket of the request, made of the request attributes listed in keyBy.
+func (meta *rateLimitMiddlewareMetadata) bucketKey(ctx *fasthttp.RequestCtx) string {
+	parts := []string{meta.KeyPrefix}
+	if len(meta.KeyBy) == 0 {
+		parts = append(parts, globalKey)
+	}
+	for _, attr := range meta.KeyBy {
+		switch {
+		case attr == keyByAppID:
+			parts = append(parts, appIDOf(ctx))
+		case attr == keyByPath:
+			parts = append(parts, string(ctx.Path()))
+		default:
+			parts = append(parts, string(ctx.Request.Header.Peek(strings.TrimPrefix(attr, keyByHeaderPrefix))))
+		}
+	}
+	return strings.Join(parts, keySeparator)
+}
+
+// appIDOf returns the id of the app a service invocation request targets,
+// from the dapr-app-id header or from the /v1.0/invoke/<id>/method path.
+func appIDOf(ctx *fasthttp.RequestCtx) string {
+	if appID := ctx.Request.Header.Peek(appIDHeader); len(appID) > 0 {
+		return string(appID)
+	}
+	segments := strings.Split(strings.Trim(string(ctx.Path()), "/"), "/")
+	if len(segments) > 2 && segments[1] == invokePathPart {
+		return segments[2]
+	}
+	return ""
+}
+
+// keySet holds the bucket keys used within their window, up to a maximum, so that the attributes of the requests
+// don't create an unbounded number of buckets in the state store.
+type keySet struct {
+	lock   sync.Mutex
+	max    int
+	window time.Duration
+	used   map[string]time.Time
+	pruned time.Time
+}
+
+func newKeySet(max int, window time.Duration) *keySet {
+	return &keySet{
+		max:    max,
+		window: window,
+		used:   map[string]time.Time{},
+	}
+}
+
+// admit returns whether the key has a bucket of its own, recording its use.
+func (s *keySet) admit(key string, now time.Time) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.used[key]; !ok && len(s.used) >= s.max {
+		// The keys not used within their window are forgotten, at most once per window.
+		if now.Sub(s.pruned) < s.window {
+			return false
+		}
+		s.pruned = now
+		for k, used := range s.used {
+			if now.Sub(used) >= s.window {
+				delete(s.used, k)
+			}
+		}
+		if len(s.used) >= s.max {
+			return false
+		}
+	}
+	s.used[key] = now
+	return true
+}
+
+func getNativeMetadata(metadata middleware.Metadata) (*rateLimitMiddlewareMetadata, error) {
+	middlewareMetadata := rateLimitMiddlewareMetadata{
+		MaxRequestsPerSecond: defaultMaxRequestsPerSecond,
+		KeyPrefix:            defaultKeyPrefix,
+		MaxKeys:              defaultMaxKeys,
+	}
+
+	if val, ok := metadata.Properties[maxRequestsPerSecondKey]; ok {
+		f, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, errors.Errorf("error parsing ratelimit middleware property %s: %s", maxRequestsPerSecondKey, err)
+		}
+		if f <= 0 {
+			return nil, errors.Errorf("ratelimit middleware property %s must be a positive value", maxRequestsPerSecondKey)
+		}
+		middlewareMetadata.MaxRequestsPerSecond = f
+	}
+
+	middlewareMetadata.Burst = math.Max(1, math.Ceil(middlewareMetadata.MaxRequestsPerSecond))
+	if val, ok := metadata.Properties[burstKey]; ok {
+		f, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, errors.Errorf("error parsing ratelimit middleware property %s: %s", burstKey, err)
+		}
+		if f < 1 {
+			return nil, errors.Errorf("ratelimit middleware property %s must be at least 1", burstKey)
+		}
+		middlewareMetadata.Burst = f
+	}
+
+	middlewareMetadata.StoreName = metadata.Properties[storeNameKey]
+	if middlewareMetadata.StoreName == "" {
+		return nil, errors.Errorf("ratelimit middleware property %s is required", storeNameKey)
+	}
+
+	if val := metadata.Properties[keyPrefixKey]; val != "" {
+		middlewareMetadata.KeyPrefix = val
+	}
+
+	if val, ok := metadata.Properties[maxKeysKey]; ok {
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, errors.Errorf("error parsing ratelimit middleware property %s: %s", maxKeysKey, err)
+		}
+		if n < 1 {
+			return nil, errors.Errorf("ratelimit middleware property %s must be at least 1", maxKeysKey)
+		}
+		middlewareMetadata.MaxKeys = n
+	}
+
+	for _, attr := range strings.Split(metadata.Properties[keyByKey], ",") {
+		attr = strings.TrimSpace(attr)
+		switch {
+		case attr == "":
+			continue
+		case attr == keyByAppID, attr == keyByPath:
+		case strings.HasPrefix(attr, keyByHeaderPrefix) && len(attr) > len(keyByHeaderPrefix):
+		default:
+			return nil, errors.Errorf("ratelimit middleware property %s has an unknown attribute %s: the attributes are %s, %s and %s<name>",
+				keyByKey, attr, keyByAppID, keyByPath, keyByHeaderPrefix)
+		}
+		middlewareMetadata.KeyBy = append(middlewareMetadata.KeyBy, attr)
+	}
+
+	return &middlewareMetadata, nil
+}
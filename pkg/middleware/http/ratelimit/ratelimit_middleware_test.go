@@ -0,0 +1,281 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dapr/components-contrib/middleware"
+	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/kit/logger"
+)
+
+type fakeItem struct {
+	data     []byte
+	etag     string
+	metadata map[string]string
+}
+
+// fakeStateStore is an in-memory state store checking etags, shared by the middleware of several replicas.
+type fakeStateStore struct {
+	state.DefaultBulkStore
+	lock        sync.Mutex
+	items       map[string]fakeItem
+	version     int
+	mismatches  int
+	setRequests int
+}
+
+func newFakeStateStore() *fakeStateStore {
+	s := &fakeStateStore{items: map[string]fakeItem{}}
+	s.DefaultBulkStore = state.NewDefaultBulkStore(s)
+	return s
+}
+
+func (f *fakeStateStore) Init(metadata state.Metadata) error { return nil }
+
+func (f *fakeStateStore) Features() []state.Feature { return []state.Feature{state.FeatureETag} }
+
+func (f *fakeStateStore) Ping() error { return nil }
+
+func (f *fakeStateStore) Delete(req *state.DeleteRequest) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	delete(f.items, req.Key)
+	return nil
+}
+
+func (f *fakeStateStore) Get(req *state.GetRequest) (*state.GetResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	item, ok := f.items[req.Key]
+	if !ok {
+		return &state.GetResponse{}, nil
+	}
+	etag := item.etag
+	return &state.GetResponse{Data: item.data, ETag: &etag}, nil
+}
+
+func (f *fakeStateStore) Set(req *state.SetRequest) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.setRequests++
+	if f.mismatches > 0 {
+		f.mismatches--
+		return state.NewETagError(state.ETagMismatch, nil)
+	}
+	if item, ok := f.items[req.Key]; ok && req.ETag != nil && *req.ETag != item.etag {
+		return state.NewETagError(state.ETagMismatch, nil)
+	}
+	b, _ := json.Marshal(req.Value)
+	f.version++
+	f.items[req.Key] = fakeItem{data: b, etag: strconv.Itoa(f.version), metadata: req.Metadata}
+	return nil
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestMiddleware(store state.Store, clock *fakeClock) *Middleware {
+	m := NewRateLimitMiddleware(logger.NewLogger("ratelimit.test"), func(name string) (state.Store, bool) {
+		if store == nil || name != "statestore" {
+			return nil, false
+		}
+		return store, true
+	})
+	m.now = clock.Now
+	return m
+}
+
+func newHandler(t *testing.T, m *Middleware, properties map[string]string) fasthttp.RequestHandler {
+	handler, err := m.GetHandler(middleware.Metadata{Properties: properties})
+	require.NoError(t, err)
+	return handler(func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusOK)
+	})
+}
+
+func serve(h fasthttp.RequestHandler, path string, headers map[string]string) *fasthttp.RequestCtx {
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.SetRequestURI(path)
+	for k, v := range headers {
+		ctx.Request.Header.Set(k, v)
+	}
+	h(ctx)
+	return ctx
+}
+
+func TestGetNativeMetadata(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		meta, err := getNativeMetadata(middleware.Metadata{Properties: map[string]string{
+			storeNameKey: "statestore",
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, float64(defaultMaxRequestsPerSecond), meta.MaxRequestsPerSecond)
+		assert.Equal(t, float64(defaultMaxRequestsPerSecond), meta.Burst)
+		assert.Equal(t, defaultKeyPrefix, meta.KeyPrefix)
+		assert.Equal(t, defaultMaxKeys, meta.MaxKeys)
+		assert.Empty(t, meta.KeyBy)
+	})
+
+	t.Run("all properties", func(t *testing.T) {
+		meta, err := getNativeMetadata(middleware.Metadata{Properties: map[string]string{
+			storeNameKey:            "statestore",
+			maxRequestsPerSecondKey: "0.5",
+			burstKey:                "3",
+			keyPrefixKey:            "orders",
+			keyByKey:                "app-id, header:x-tenant,path",
+			maxKeysKey:              "100",
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, 0.5, meta.MaxRequestsPerSecond)
+		assert.Equal(t, float64(3), meta.Burst)
+		assert.Equal(t, "orders", meta.KeyPrefix)
+		assert.Equal(t, []string{"app-id", "header:x-tenant", "path"}, meta.KeyBy)
+		assert.Equal(t, 100, meta.MaxKeys)
+	})
+
+	t.Run("invalid properties", func(t *testing.T) {
+		for name, properties := range map[string]map[string]string{
+			"no store":         {},
+			"negative rate":    {storeNameKey: "statestore", maxRequestsPerSecondKey: "-1"},
+			"small burst":      {storeNameKey: "statestore", burstKey: "0.5"},
+			"unknown key":      {storeNameKey: "statestore", keyByKey: "query"},
+			"empty header key": {storeNameKey: "statestore", keyByKey: "header:"},
+			"no keys":          {storeNameKey: "statestore", maxKeysKey: "0"},
+		} {
+			_, err := getNativeMetadata(middleware.Metadata{Properties: properties})
+			assert.Error(t, err, name)
+		}
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	properties := map[string]string{
+		storeNameKey:            "statestore",
+		maxRequestsPerSecondKey: "1",
+		burstKey:                "2",
+		keyByKey:                "app-id,header:x-tenant",
+	}
+
+	t.Run("bucket is shared across replicas and refills over time", func(t *testing.T) {
+		store := newFakeStateStore()
+		clock := &fakeClock{now: time.Unix(1000, 0)}
+		replica1 := newHandler(t, newTestMiddleware(store, clock), properties)
+		replica2 := newHandler(t, newTestMiddleware(store, clock), properties)
+		headers := map[string]string{"x-tenant": "contoso"}
+
+		assert.Equal(t, fasthttp.StatusOK, serve(replica1, "/v1.0/invoke/orders/method/new", headers).Response.StatusCode())
+		assert.Equal(t, fasthttp.StatusOK, serve(replica2, "/v1.0/invoke/orders/method/new", headers).Response.StatusCode())
+
+		ctx := serve(replica1, "/v1.0/invoke/orders/method/new", headers)
+		assert.Equal(t, fasthttp.StatusTooManyRequests, ctx.Response.StatusCode())
+		assert.Equal(t, "1", string(ctx.Response.Header.Peek(fasthttp.HeaderRetryAfter)))
+
+		clock.now = clock.now.Add(time.Second)
+		assert.Equal(t, fasthttp.StatusOK, serve(replica2, "/v1.0/invoke/orders/method/new", headers).Response.StatusCode())
+		assert.Equal(t, fasthttp.StatusTooManyRequests, serve(replica1, "/v1.0/invoke/orders/method/new", headers).Response.StatusCode())
+	})
+
+	t.Run("buckets are keyed by request attributes", func(t *testing.T) {
+		store := newFakeStateStore()
+		clock := &fakeClock{now: time.Unix(1000, 0)}
+		h := newHandler(t, newTestMiddleware(store, clock), properties)
+
+		for i := 0; i < 2; i++ {
+			serve(h, "/v1.0/invoke/orders/method/new", map[string]string{"x-tenant": "contoso"})
+		}
+		assert.Equal(t, fasthttp.StatusOK, serve(h, "/v1.0/invoke/orders/method/new", map[string]string{"x-tenant": "fabrikam"}).Response.StatusCode())
+		assert.Equal(t, fasthttp.StatusOK, serve(h, "/v1.0/invoke/method/new", map[string]string{"x-tenant": "contoso", "dapr-app-id": "payments"}).Response.StatusCode())
+
+		assert.Contains(t, store.items, "ratelimit||orders||contoso")
+		assert.Contains(t, store.items, "ratelimit||orders||fabrikam")
+		assert.Contains(t, store.items, "ratelimit||payments||contoso")
+	})
+
+	t.Run("buckets expire once refilled", func(t *testing.T) {
+		store := newFakeStateStore()
+		clock := &fakeClock{now: time.Unix(1000, 0)}
+		h := newHandler(t, newTestMiddleware(store, clock), properties)
+
+		serve(h, "/v1.0/invoke/orders/method/new", nil)
+		assert.Equal(t, "2", store.items["ratelimit||orders||"].metadata[ttlInSecondsKey])
+	})
+
+	t.Run("keys beyond the maximum share a bucket", func(t *testing.T) {
+		store := newFakeStateStore()
+		clock := &fakeClock{now: time.Unix(1000, 0)}
+		h := newHandler(t, newTestMiddleware(store, clock), map[string]string{
+			storeNameKey:            "statestore",
+			maxRequestsPerSecondKey: "1",
+			burstKey:                "2",
+			keyByKey:                "header:x-tenant",
+			maxKeysKey:              "2",
+		})
+
+		for _, tenant := range []string{"contoso", "fabrikam", "northwind", "tailspin"} {
+			serve(h, "/v1.0/state/store", map[string]string{"x-tenant": tenant})
+		}
+		assert.Contains(t, store.items, "ratelimit||contoso")
+		assert.Contains(t, store.items, "ratelimit||fabrikam")
+		assert.NotContains(t, store.items, "ratelimit||northwind")
+		assert.Equal(t, fasthttp.StatusTooManyRequests, serve(h, "/v1.0/state/store", map[string]string{"x-tenant": "wingtip"}).Response.StatusCode())
+
+		// the keys not used within their window make room for new ones.
+		clock.now = clock.now.Add(2 * time.Second)
+		serve(h, "/v1.0/state/store", map[string]string{"x-tenant": "northwind"})
+		assert.Contains(t, store.items, "ratelimit||northwind")
+	})
+
+	t.Run("etag mismatch is retried", func(t *testing.T) {
+		store := newFakeStateStore()
+		store.mismatches = 2
+		clock := &fakeClock{now: time.Unix(1000, 0)}
+		h := newHandler(t, newTestMiddleware(store, clock), properties)
+
+		assert.Equal(t, fasthttp.StatusOK, serve(h, "/v1.0/invoke/orders/method/new", nil).Response.StatusCode())
+		assert.Equal(t, 3, store.setRequests)
+	})
+
+	t.Run("contended bucket denies the request", func(t *testing.T) {
+		store := newFakeStateStore()
+		store.mismatches = maxETagRetries
+		clock := &fakeClock{now: time.Unix(1000, 0)}
+		h := newHandler(t, newTestMiddleware(store, clock), properties)
+
+		assert.Equal(t, fasthttp.StatusTooManyRequests, serve(h, "/v1.0/invoke/orders/method/new", nil).Response.StatusCode())
+	})
+
+	t.Run("missing store lets requests through", func(t *testing.T) {
+		clock := &fakeClock{now: time.Unix(1000, 0)}
+		h := newHandler(t, newTestMiddleware(nil, clock), properties)
+
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, fasthttp.StatusOK, serve(h, "/v1.0/invoke/orders/method/new", nil).Response.StatusCode())
+		}
+	})
+}
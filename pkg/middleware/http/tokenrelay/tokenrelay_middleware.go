@@ -0,0 +1,292 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenrelay
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dapr/components-contrib/middleware"
+	"github.com/dapr/kit/logger"
+
+	"github.com/dapr/dapr/pkg/runtime/security"
+)
+
+const (
+	issuerKey           = "issuer"
+	jwksURLKey          = "jwksURL"
+	audiencesKey        = "audiences"
+	tokenHeaderKey      = "tokenHeader"
+	forwardHeaderKey    = "forwardHeader"
+	exchangeURLKey      = "tokenExchangeURL"
+	exchangeGrantKey    = "tokenExchangeGrant"
+	clientIDKey         = "clientID"
+	clientSecretKey     = "clientSecret"
+	exchangeAudienceKey = "exchangeAudience"
+	exchangeScopesKey   = "exchangeScopes"
+
+	// grantTokenExchange is the OAuth 2.0 token exchange of RFC 8693.
+	grantTokenExchange = "token-exchange"
+	// grantOnBehalfOf is the on-behalf-of flow of the JWT bearer grant of RFC 7523, used by Azure AD.
+	grantOnBehalfOf = "on-behalf-of"
+
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	jwtBearerGrantType     = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	accessTokenType        = "urn:ietf:params:oauth:token-type:access_token"
+
+	bearerPrefix = "Bearer "
+
+	// Defaults.
+	defaultTokenHeader   = "Authorization"
+	defaultExchangeGrant = grantTokenExchange
+	exchangeTimeout      = 10 * time.Second
+	// validationTimeout bounds the validation of a token, which may fetch the keys of the issuer.
+	validationTimeout = 10 * time.Second
+	// expiryMargin is subtracted from the lifetime of the exchanged tokens so that a cached token is not
+	// forwarded about to expire.
+	expiryMargin = 30 * time.Second
+)
+
+// Middleware is a middleware validating the JWT bearer token of the requests against an OIDC issuer,
+// optionally exchanging it for a token of a downstream audience, and forwarding the resulting token.
+type Middleware struct {
+	logger logger.Logger
+	client *http.Client
+	now    func() time.Time
+}
+
+// tokenRelayMiddlewareMetadata is the token relay middleware config.
+type tokenRelayMiddlewareMetadata struct {
+	Issuer           string
+	JWKSURL          string
+	Audiences        []string
+	TokenHeader      string
+	ForwardHeader    string
+	ExchangeURL      string
+	ExchangeGrant    string
+	ClientID         string
+	ClientSecret     string
+	ExchangeAudience string
+	ExchangeScopes   string
+}
+
+type exchangedToken struct {
+	token   string
+	expires time.Time
+}
+
+// tokenResponse is the response of the token endpoint to a successful exchange.
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// NewTokenRelayMiddleware returns a new token relay middleware.
+func NewTokenRelayMiddleware(logger logger.Logger) *Middleware {
+	return &Middleware{
+		logger: logger,
+		client: &http.Client{Timeout: exchangeTimeout},
+		now:    time.Now,
+	}
+}
+
+// GetHandler returns the HTTP handler provided by the middleware.
+func (m *Middleware) GetHandler(metadata middleware.Metadata) (func(h fasthttp.RequestHandler) fasthttp.RequestHandler, error) {
+	meta, err := getNativeMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	validator, err := security.NewOIDCValidator(meta.Issuer, meta.JWKSURL, meta.Audiences)
+	if err != nil {
+		return nil, err
+	}
+
+	// The exchanged tokens are cached by the hash of the incoming token until they expire.
+	cache := map[string]exchangedToken{}
+	lock := sync.Mutex{}
+
+	return func(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			token, ok := security.BearerToken(string(ctx.Request.Header.Peek(meta.TokenHeader)))
+			if !ok {
+				unauthorized(ctx, "missing bearer token")
+				return
+			}
+			// The request context isn't passed on, since it's only usable as a context.Context by a running server.
+			validateCtx, cancel := context.WithTimeout(context.Background(), validationTimeout)
+			_, err := validator.Validate(validateCtx, token)
+			cancel()
+			if err != nil {
+				m.logger.Debugf("token relay: rejected request with %s", err)
+				unauthorized(ctx, "invalid bearer token")
+				return
+			}
+
+			if meta.ExchangeURL != "" {
+				key := tokenHash(token)
+				lock.Lock()
+				cached, found := cache[key]
+				lock.Unlock()
+
+				if !found || !m.now().Before(cached.expires) {
+					exchangeCtx, cancel := context.WithTimeout(context.Background(), exchangeTimeout)
+					cached, err = m.exchange(exchangeCtx, meta, token)
+					cancel()
+					if err != nil {
+						m.logger.Warnf("token relay: failed to exchange the bearer token: %s", err)
+						ctx.Error("failed to exchange the bearer token", fasthttp.StatusBadGateway)
+						return
+					}
+					lock.Lock()
+					m.evictExpired(cache)
+					cache[key] = cached
+					lock.Unlock()
+				}
+				token = cached.token
+			}
+
+			if meta.ForwardHeader != meta.TokenHeader {
+				ctx.Request.Header.Del(meta.TokenHeader)
+			}
+			ctx.Request.Header.Set(meta.ForwardHeader, bearerPrefix+token)
+			h(ctx)
+		}
+	}, nil
+}
+
+// exchange exchanges the token at the token endpoint with the configured grant.
+func (m *Middleware) exchange(ctx context.Context, meta *tokenRelayMiddlewareMetadata, token string) (exchangedToken, error) {
+	form := url.Values{}
+	switch meta.ExchangeGrant {
+	case grantOnBehalfOf:
+		form.Set("grant_type", jwtBearerGrantType)
+		form.Set("assertion", token)
+		form.Set("requested_token_use", "on_behalf_of")
+	default:
+		form.Set("grant_type", tokenExchangeGrantType)
+		form.Set("subject_token", token)
+		form.Set("subject_token_type", accessTokenType)
+		form.Set("requested_token_type", accessTokenType)
+	}
+	if meta.ExchangeAudience != "" {
+		form.Set("audience", meta.ExchangeAudience)
+	}
+	if meta.ExchangeScopes != "" {
+		form.Set("scope", meta.ExchangeScopes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.ExchangeURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return exchangedToken{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if meta.ClientID != "" {
+		req.SetBasicAuth(url.QueryEscape(meta.ClientID), url.QueryEscape(meta.ClientSecret))
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return exchangedToken{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return exchangedToken{}, errors.Errorf("token endpoint responded with status %d", resp.StatusCode)
+	}
+	var tr tokenResponse
+	if err = json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return exchangedToken{}, errors.Wrap(err, "error decoding the token endpoint response")
+	}
+	if tr.AccessToken == "" {
+		return exchangedToken{}, errors.New("token endpoint responded without an access token")
+	}
+
+	// A token without a lifetime is not cached.
+	expires := m.now()
+	if tr.ExpiresIn > 0 {
+		expires = expires.Add(time.Duration(tr.ExpiresIn)*time.Second - expiryMargin)
+	}
+	return exchangedToken{token: tr.AccessToken, expires: expires}, nil
+}
+
+// evictExpired removes the expired tokens from the cache, which must be locked.
+func (m *Middleware) evictExpired(cache map[string]exchangedToken) {
+	now := m.now()
+	for key, cached := range cache {
+		if !now.Before(cached.expires) {
+			delete(cache, key)
+		}
+	}
+}
+
+func unauthorized(ctx *fasthttp.RequestCtx, msg string) {
+	// ctx.Error resets the response, so the header is set after it.
+	ctx.Error(msg, fasthttp.StatusUnauthorized)
+	ctx.Response.Header.Set(fasthttp.HeaderWWWAuthenticate, "Bearer")
+}
+
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func getNativeMetadata(metadata middleware.Metadata) (*tokenRelayMiddlewareMetadata, error) {
+	middlewareMetadata := tokenRelayMiddlewareMetadata{
+		Issuer:           metadata.Properties[issuerKey],
+		JWKSURL:          metadata.Properties[jwksURLKey],
+		TokenHeader:      metadata.Properties[tokenHeaderKey],
+		ForwardHeader:    metadata.Properties[forwardHeaderKey],
+		ExchangeURL:      metadata.Properties[exchangeURLKey],
+		ExchangeGrant:    strings.ToLower(metadata.Properties[exchangeGrantKey]),
+		ClientID:         metadata.Properties[clientIDKey],
+		ClientSecret:     metadata.Properties[clientSecretKey],
+		ExchangeAudience: metadata.Properties[exchangeAudienceKey],
+		ExchangeScopes:   metadata.Properties[exchangeScopesKey],
+	}
+
+	for _, aud := range strings.Split(metadata.Properties[audiencesKey], ",") {
+		if aud = strings.TrimSpace(aud); aud != "" {
+			middlewareMetadata.Audiences = append(middlewareMetadata.Audiences, aud)
+		}
+	}
+
+	if middlewareMetadata.TokenHeader == "" {
+		middlewareMetadata.TokenHeader = defaultTokenHeader
+	}
+	if middlewareMetadata.ForwardHeader == "" {
+		middlewareMetadata.ForwardHeader = middlewareMetadata.TokenHeader
+	}
+	if middlewareMetadata.ExchangeGrant == "" {
+		middlewareMetadata.ExchangeGrant = defaultExchangeGrant
+	}
+	if middlewareMetadata.ExchangeGrant != grantTokenExchange && middlewareMetadata.ExchangeGrant != grantOnBehalfOf {
+		return nil, errors.Errorf("token relay middleware property %s must be %s or %s", exchangeGrantKey, grantTokenExchange, grantOnBehalfOf)
+	}
+	if middlewareMetadata.ExchangeGrant == grantOnBehalfOf && middlewareMetadata.ExchangeURL != "" && middlewareMetadata.ClientID == "" {
+		return nil, errors.Errorf("token relay middleware property %s is required by the %s grant", clientIDKey, grantOnBehalfOf)
+	}
+
+	return &middlewareMetadata, nil
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenrelay
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	jose "gopkg.in/square/go-jose.v2"
+
+	"github.com/dapr/components-contrib/middleware"
+	"github.com/dapr/kit/logger"
+)
+
+const testKeyID = "test-key"
+
+// testIssuer is an OIDC issuer serving its discovery document, its JWKS and a token endpoint.
+type testIssuer struct {
+	server    *httptest.Server
+	key       *rsa.PrivateKey
+	lock      sync.Mutex
+	exchanges []url.Values
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	issuer := &testIssuer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":   issuer.server.URL,
+			"jwks_uri": issuer.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{{Key: &key.PublicKey, KeyID: testKeyID, Algorithm: "RS256", Use: "sig"}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "relay" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		r.ParseForm()
+		issuer.lock.Lock()
+		issuer.exchanges = append(issuer.exchanges, r.PostForm)
+		n := len(issuer.exchanges)
+		issuer.lock.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "exchanged-" + strconv.Itoa(n),
+			"expires_in":   3600,
+		})
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func (i *testIssuer) token(t *testing.T, subject string) string {
+	signer, err := jose.NewSigner(jose.SigningKey{
+		Algorithm: jose.RS256,
+		Key:       jose.JSONWebKey{Key: i.key, KeyID: testKeyID},
+	}, (&jose.SignerOptions{}).WithType("JWT"))
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"iss": i.server.URL,
+		"sub": subject,
+		"aud": []string{"orders"},
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(time.Minute).Unix(),
+	})
+	require.NoError(t, err)
+	signed, err := signer.Sign(payload)
+	require.NoError(t, err)
+	raw, err := signed.CompactSerialize()
+	require.NoError(t, err)
+	return raw
+}
+
+// serve serves a request through the middleware and returns the headers forwarded to the next handler.
+func serve(h fasthttp.RequestHandler, headers map[string]string) (*fasthttp.RequestCtx, *fasthttp.RequestHeader) {
+	forwarded := &fasthttp.RequestHeader{}
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.SetRequestURI("/v1.0/invoke/orders/method/new")
+	for k, v := range headers {
+		ctx.Request.Header.Set(k, v)
+	}
+	ctx.SetUserValue("forwarded", forwarded)
+	h(ctx)
+	return ctx, forwarded
+}
+
+func newHandler(t *testing.T, m *Middleware, properties map[string]string) fasthttp.RequestHandler {
+	handler, err := m.GetHandler(middleware.Metadata{Properties: properties})
+	require.NoError(t, err)
+	return handler(func(ctx *fasthttp.RequestCtx) {
+		ctx.Request.Header.CopyTo(ctx.UserValue("forwarded").(*fasthttp.RequestHeader))
+	})
+}
+
+func TestGetNativeMetadata(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		meta, err := getNativeMetadata(middleware.Metadata{Properties: map[string]string{
+			issuerKey:    "https://issuer.example.com",
+			audiencesKey: "orders, payments",
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"orders", "payments"}, meta.Audiences)
+		assert.Equal(t, defaultTokenHeader, meta.TokenHeader)
+		assert.Equal(t, defaultTokenHeader, meta.ForwardHeader)
+		assert.Equal(t, grantTokenExchange, meta.ExchangeGrant)
+	})
+
+	t.Run("unknown grant", func(t *testing.T) {
+		_, err := getNativeMetadata(middleware.Metadata{Properties: map[string]string{
+			issuerKey:        "https://issuer.example.com",
+			audiencesKey:     "orders",
+			exchangeGrantKey: "password",
+		}})
+		assert.Error(t, err)
+	})
+
+	t.Run("on-behalf-of without client", func(t *testing.T) {
+		_, err := getNativeMetadata(middleware.Metadata{Properties: map[string]string{
+			issuerKey:        "https://issuer.example.com",
+			audiencesKey:     "orders",
+			exchangeURLKey:   "https://issuer.example.com/token",
+			exchangeGrantKey: grantOnBehalfOf,
+		}})
+		assert.Error(t, err)
+	})
+
+	t.Run("missing audiences", func(t *testing.T) {
+		_, err := NewTokenRelayMiddleware(logger.NewLogger("tokenrelay.test")).GetHandler(middleware.Metadata{Properties: map[string]string{
+			issuerKey: "https://issuer.example.com",
+		}})
+		assert.Error(t, err)
+	})
+}
+
+func TestTokenRelay(t *testing.T) {
+	issuer := newTestIssuer(t)
+	log := logger.NewLogger("tokenrelay.test")
+
+	t.Run("missing token is rejected", func(t *testing.T) {
+		h := newHandler(t, NewTokenRelayMiddleware(log), map[string]string{
+			issuerKey:    issuer.server.URL,
+			audiencesKey: "orders",
+		})
+
+		ctx, _ := serve(h, nil)
+		assert.Equal(t, fasthttp.StatusUnauthorized, ctx.Response.StatusCode())
+		assert.Equal(t, "Bearer", string(ctx.Response.Header.Peek(fasthttp.HeaderWWWAuthenticate)))
+	})
+
+	t.Run("invalid token is rejected", func(t *testing.T) {
+		h := newHandler(t, NewTokenRelayMiddleware(log), map[string]string{
+			issuerKey:    issuer.server.URL,
+			audiencesKey: "payments",
+		})
+
+		ctx, _ := serve(h, map[string]string{"Authorization": "Bearer " + issuer.token(t, "checkout")})
+		assert.Equal(t, fasthttp.StatusUnauthorized, ctx.Response.StatusCode())
+	})
+
+	t.Run("valid token is forwarded in the configured header", func(t *testing.T) {
+		h := newHandler(t, NewTokenRelayMiddleware(log), map[string]string{
+			issuerKey:        issuer.server.URL,
+			audiencesKey:     "orders",
+			tokenHeaderKey:   "X-Caller-Token",
+			forwardHeaderKey: "Authorization",
+		})
+		token := issuer.token(t, "checkout")
+
+		ctx, forwarded := serve(h, map[string]string{"X-Caller-Token": "Bearer " + token})
+		assert.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+		assert.Equal(t, "Bearer "+token, string(forwarded.Peek("Authorization")))
+		assert.Empty(t, forwarded.Peek("X-Caller-Token"))
+	})
+
+	t.Run("exchanged token is forwarded and cached", func(t *testing.T) {
+		m := NewTokenRelayMiddleware(log)
+		now := time.Now()
+		m.now = func() time.Time { return now }
+		h := newHandler(t, m, map[string]string{
+			issuerKey:           issuer.server.URL,
+			audiencesKey:        "orders",
+			exchangeURLKey:      issuer.server.URL + "/token",
+			clientIDKey:         "relay",
+			clientSecretKey:     "secret",
+			exchangeAudienceKey: "inventory",
+		})
+		token := issuer.token(t, "checkout")
+		headers := map[string]string{"Authorization": "Bearer " + token}
+
+		_, forwarded := serve(h, headers)
+		assert.Equal(t, "Bearer exchanged-1", string(forwarded.Peek("Authorization")))
+		_, forwarded = serve(h, headers)
+		assert.Equal(t, "Bearer exchanged-1", string(forwarded.Peek("Authorization")))
+
+		issuer.lock.Lock()
+		require.Len(t, issuer.exchanges, 1)
+		exchange := issuer.exchanges[0]
+		issuer.lock.Unlock()
+		assert.Equal(t, tokenExchangeGrantType, exchange.Get("grant_type"))
+		assert.Equal(t, token, exchange.Get("subject_token"))
+		assert.Equal(t, "inventory", exchange.Get("audience"))
+
+		now = now.Add(time.Hour)
+		_, forwarded = serve(h, headers)
+		assert.Equal(t, "Bearer exchanged-2", string(forwarded.Peek("Authorization")))
+	})
+
+	t.Run("on-behalf-of grant", func(t *testing.T) {
+		issuer.lock.Lock()
+		issuer.exchanges = nil
+		issuer.lock.Unlock()
+		h := newHandler(t, NewTokenRelayMiddleware(log), map[string]string{
+			issuerKey:         issuer.server.URL,
+			audiencesKey:      "orders",
+			exchangeURLKey:    issuer.server.URL + "/token",
+			exchangeGrantKey:  grantOnBehalfOf,
+			clientIDKey:       "relay",
+			clientSecretKey:   "secret",
+			exchangeScopesKey: "api://inventory/.default",
+		})
+		token := issuer.token(t, "checkout")
+
+		_, forwarded := serve(h, map[string]string{"Authorization": "Bearer " + token})
+		assert.Equal(t, "Bearer exchanged-1", string(forwarded.Peek("Authorization")))
+
+		issuer.lock.Lock()
+		defer issuer.lock.Unlock()
+		require.Len(t, issuer.exchanges, 1)
+		assert.Equal(t, jwtBearerGrantType, issuer.exchanges[0].Get("grant_type"))
+		assert.Equal(t, token, issuer.exchanges[0].Get("assertion"))
+		assert.Equal(t, "on_behalf_of", issuer.exchanges[0].Get("requested_token_use"))
+		assert.Equal(t, "api://inventory/.default", issuer.exchanges[0].Get("scope"))
+	})
+
+	t.Run("failed exchange", func(t *testing.T) {
+		h := newHandler(t, NewTokenRelayMiddleware(log), map[string]string{
+			issuerKey:       issuer.server.URL,
+			audiencesKey:    "orders",
+			exchangeURLKey:  issuer.server.URL + "/token",
+			clientIDKey:     "relay",
+			clientSecretKey: "wrong",
+		})
+
+		ctx, forwarded := serve(h, map[string]string{"Authorization": "Bearer " + issuer.token(t, "checkout")})
+		assert.Equal(t, fasthttp.StatusBadGateway, ctx.Response.StatusCode())
+		assert.Empty(t, forwarded.Peek("Authorization"))
+	})
+}
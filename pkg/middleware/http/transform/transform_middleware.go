@@ -0,0 +1,288 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path"
+	"strings"
+	"time"
+
+	jsonata "github.com/blues/jsonata-go"
+	"github.com/itchyny/gojq"
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dapr/components-contrib/middleware"
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	languageKey = "language"
+	requestKey  = "request"
+	responseKey = "response"
+	routesKey   = "routes"
+	timeoutKey  = "timeout"
+
+	languageJQ      = "jq"
+	languageJSONata = "jsonata"
+
+	// Defaults.
+	defaultLanguage = languageJQ
+	defaultTimeout  = time.Second
+)
+
+// transformer applies an expression to a decoded JSON body, its numbers being decoded as json.Number.
+type transformer interface {
+	transform(ctx context.Context, v interface{}) (interface{}, error)
+}
+
+// Middleware is a middleware transforming the JSON request and response bodies of configured routes
+// with a JQ or JSONata expression.
+type Middleware struct {
+	logger logger.Logger
+}
+
+// transformMiddlewareMetadata is the transform middleware config.
+type transformMiddlewareMetadata struct {
+	Request  transformer
+	Response transformer
+	Routes   []string
+	Timeout  time.Duration
+}
+
+// NewTransformMiddleware returns a new transform middleware.
+func NewTransformMiddleware(logger logger.Logger) *Middleware {
+	return &Middleware{logger: logger}
+}
+
+// GetHandler returns the HTTP handler provided by the middleware.
+func (m *Middleware) GetHandler(metadata middleware.Metadata) (func(h fasthttp.RequestHandler) fasthttp.RequestHandler, error) {
+	meta, err := getNativeMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(meta.Routes) == 0 {
+		m.logger.Warnf("transform: no %s are configured, no body is transformed", routesKey)
+	}
+
+	return func(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			if !meta.matches(string(ctx.Path())) {
+				h(ctx)
+				return
+			}
+
+			// Streamed bodies are left untouched rather than buffered whole.
+			if meta.Request != nil && !ctx.Request.IsBodyStream() && isJSON(ctx.Request.Header.ContentType()) && len(ctx.Request.Body()) > 0 {
+				body, err := meta.transformBody(meta.Request, ctx.Request.Body())
+				if err != nil {
+					m.logger.Debugf("transform: failed to transform the request body of %s: %s", ctx.Path(), err)
+					ctx.Error("failed to transform the request body: "+err.Error(), fasthttp.StatusBadRequest)
+					return
+				}
+				ctx.Request.SetBody(body)
+			}
+
+			h(ctx)
+
+			// Error responses and encoded or streamed bodies are left untouched: only the successful payloads are adapted.
+			status := ctx.Response.StatusCode()
+			if meta.Response == nil || status < 200 || status >= 300 || ctx.Response.IsBodyStream() ||
+				!isJSON(ctx.Response.Header.ContentType()) ||
+				len(ctx.Response.Header.Peek(fasthttp.HeaderContentEncoding)) > 0 || len(ctx.Response.Body()) == 0 {
+				return
+			}
+			body, err := meta.transformBody(meta.Response, ctx.Response.Body())
+			if err != nil {
+				m.logger.Warnf("transform: failed to transform the response body of %s: %s", ctx.Path(), err)
+				ctx.Error("failed to transform the response body: "+err.Error(), fasthttp.StatusInternalServerError)
+				return
+			}
+			ctx.Response.SetBody(body)
+		}
+	}, nil
+}
+
+// matches returns whether the path matches one of the routes, no path matching when no route is configured.
+func (meta *transformMiddlewareMetadata) matches(p string) bool {
+	for _, route := range meta.Routes {
+		if ok, _ := path.Match(route, p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isJSON returns whether the content type is JSON, application/json or a +json type.
+func isJSON(contentType []byte) bool {
+	if i := bytes.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	mediaType := strings.ToLower(strings.TrimSpace(string(contentType)))
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// transformBody transforms the JSON body within the timeout, its numbers being kept as is rather than rounded
+// to float64.
+func (meta *transformMiddlewareMetadata) transformBody(t transformer, body []byte) ([]byte, error) {
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return nil, errors.Wrap(err, "body is not valid JSON")
+	}
+	if decoder.More() {
+		return nil, errors.New("body is not valid JSON: it has more than one value")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), meta.Timeout)
+	defer cancel()
+	out, err := t.transform(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(out)
+}
+
+type jqTransformer struct {
+	code *gojq.Code
+}
+
+// transform runs the JQ program, the results being returned in an array when it outputs more than one.
+func (t *jqTransformer) transform(ctx context.Context, v interface{}) (interface{}, error) {
+	var results []interface{}
+	iter := t.code.RunWithContext(ctx, v)
+	for {
+		result, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := result.(error); ok {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	if len(results) == 1 {
+		return results[0], nil
+	}
+	return results, nil
+}
+
+type jsonataTransformer struct {
+	expr *jsonata.Expr
+}
+
+// transform evaluates the JSONata expression, which doesn't support json.Number, on the numbers as float64.
+// Its evaluation can't be interrupted.
+func (t *jsonataTransformer) transform(_ context.Context, v interface{}) (interface{}, error) {
+	return t.expr.Eval(numbersToFloats(v))
+}
+
+func numbersToFloats(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = numbersToFloats(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = numbersToFloats(e)
+		}
+	}
+	return v
+}
+
+func newTransformer(language, expression string) (transformer, error) {
+	switch language {
+	case languageJQ:
+		query, err := gojq.Parse(expression)
+		if err != nil {
+			return nil, err
+		}
+		code, err := gojq.Compile(query)
+		if err != nil {
+			return nil, err
+		}
+		return &jqTransformer{code: code}, nil
+	case languageJSONata:
+		expr, err := jsonata.Compile(expression)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonataTransformer{expr: expr}, nil
+	default:
+		return nil, errors.Errorf("unknown language %s: the languages are %s and %s", language, languageJQ, languageJSONata)
+	}
+}
+
+func getNativeMetadata(metadata middleware.Metadata) (*transformMiddlewareMetadata, error) {
+	middlewareMetadata := transformMiddlewareMetadata{
+		Timeout: defaultTimeout,
+	}
+
+	language := defaultLanguage
+	if val := metadata.Properties[languageKey]; val != "" {
+		language = strings.ToLower(val)
+	}
+
+	for key, t := range map[string]*transformer{
+		requestKey:  &middlewareMetadata.Request,
+		responseKey: &middlewareMetadata.Response,
+	} {
+		expression := strings.TrimSpace(metadata.Properties[key])
+		if expression == "" {
+			continue
+		}
+		var err error
+		if *t, err = newTransformer(language, expression); err != nil {
+			return nil, errors.Errorf("error parsing transform middleware property %s: %s", key, err)
+		}
+	}
+	if middlewareMetadata.Request == nil && middlewareMetadata.Response == nil {
+		return nil, errors.Errorf("transform middleware requires the property %s or %s", requestKey, responseKey)
+	}
+
+	for _, route := range strings.Split(metadata.Properties[routesKey], ",") {
+		route = strings.TrimSpace(route)
+		if route == "" {
+			continue
+		}
+		if _, err := path.Match(route, ""); err != nil {
+			return nil, errors.Errorf("error parsing transform middleware property %s: route %s: %s", routesKey, route, err)
+		}
+		middlewareMetadata.Routes = append(middlewareMetadata.Routes, route)
+	}
+
+	if val := metadata.Properties[timeoutKey]; val != "" {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return nil, errors.Errorf("error parsing transform middleware property %s: %s", timeoutKey, err)
+		}
+		if d <= 0 {
+			return nil, errors.Errorf("transform middleware property %s must be a positive duration", timeoutKey)
+		}
+		middlewareMetadata.Timeout = d
+	}
+
+	return &middlewareMetadata, nil
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"bufio"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dapr/components-contrib/middleware"
+	"github.com/dapr/kit/logger"
+)
+
+func newHandler(t *testing.T, properties map[string]string, app fasthttp.RequestHandler) fasthttp.RequestHandler {
+	handler, err := NewTransformMiddleware(logger.NewLogger("transform.test")).GetHandler(middleware.Metadata{Properties: properties})
+	require.NoError(t, err)
+	return handler(app)
+}
+
+const allRoutes = "/*/*/*/*/*"
+
+func serve(h fasthttp.RequestHandler, path, body string) *fasthttp.RequestCtx {
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+	ctx.Request.Header.SetContentType("application/json")
+	ctx.Request.SetRequestURI(path)
+	ctx.Request.SetBodyString(body)
+	h(ctx)
+	return ctx
+}
+
+func echo(ctx *fasthttp.RequestCtx) {
+	ctx.Response.Header.SetContentTypeBytes(ctx.Request.Header.ContentType())
+	ctx.Response.SetBody(ctx.Request.Body())
+}
+
+func TestGetNativeMetadata(t *testing.T) {
+	t.Run("jq is the default language", func(t *testing.T) {
+		meta, err := getNativeMetadata(middleware.Metadata{Properties: map[string]string{
+			requestKey: ".order",
+			routesKey:  "/v1.0/invoke/*/method/orders, /v1.0/publish/*/*",
+		}})
+		require.NoError(t, err)
+		assert.IsType(t, &jqTransformer{}, meta.Request)
+		assert.Nil(t, meta.Response)
+		assert.Equal(t, []string{"/v1.0/invoke/*/method/orders", "/v1.0/publish/*/*"}, meta.Routes)
+		assert.Equal(t, defaultTimeout, meta.Timeout)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		meta, err := getNativeMetadata(middleware.Metadata{Properties: map[string]string{
+			requestKey: ".order",
+			timeoutKey: "250ms",
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, 250*time.Millisecond, meta.Timeout)
+	})
+
+	t.Run("jsonata", func(t *testing.T) {
+		meta, err := getNativeMetadata(middleware.Metadata{Properties: map[string]string{
+			languageKey: "JSONata",
+			responseKey: "items.price",
+		}})
+		require.NoError(t, err)
+		assert.IsType(t, &jsonataTransformer{}, meta.Response)
+	})
+
+	t.Run("invalid properties", func(t *testing.T) {
+		for name, properties := range map[string]map[string]string{
+			"no expression":      {routesKey: "/v1.0/state/*"},
+			"unknown language":   {languageKey: "xslt", requestKey: "."},
+			"invalid jq":         {requestKey: ".order |"},
+			"invalid jsonata":    {languageKey: languageJSONata, requestKey: "items[price >"},
+			"invalid route glob": {requestKey: ".", routesKey: "/v1.0/["},
+			"invalid timeout":    {requestKey: ".", timeoutKey: "1"},
+			"negative timeout":   {requestKey: ".", timeoutKey: "-1s"},
+		} {
+			_, err := getNativeMetadata(middleware.Metadata{Properties: properties})
+			assert.Error(t, err, name)
+		}
+	})
+}
+
+func TestTransform(t *testing.T) {
+	t.Run("request and response are transformed with jq", func(t *testing.T) {
+		h := newHandler(t, map[string]string{
+			requestKey:  "{id: .orderId, items: [.lines[].sku]}",
+			responseKey: "{orderId: .id, count: (.items | length)}",
+			routesKey:   allRoutes,
+		}, echo)
+
+		ctx := serve(h, "/v1.0/invoke/orders/method/new", `{"orderId":"1","lines":[{"sku":"a"},{"sku":"b"}]}`)
+		assert.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+		assert.JSONEq(t, `{"orderId":"1","count":2}`, string(ctx.Response.Body()))
+	})
+
+	t.Run("multiple jq results are returned in an array", func(t *testing.T) {
+		h := newHandler(t, map[string]string{requestKey: ".lines[].sku", routesKey: allRoutes}, echo)
+
+		ctx := serve(h, "/v1.0/invoke/orders/method/new", `{"lines":[{"sku":"a"},{"sku":"b"}]}`)
+		assert.JSONEq(t, `["a","b"]`, string(ctx.Response.Body()))
+	})
+
+	t.Run("request is transformed with jsonata", func(t *testing.T) {
+		h := newHandler(t, map[string]string{
+			languageKey: languageJSONata,
+			requestKey:  `{"total": $sum(lines.price)}`,
+			routesKey:   allRoutes,
+		}, echo)
+
+		ctx := serve(h, "/v1.0/invoke/orders/method/new", `{"lines":[{"price":2},{"price":3}]}`)
+		assert.JSONEq(t, `{"total":5}`, string(ctx.Response.Body()))
+	})
+
+	t.Run("other routes are not transformed", func(t *testing.T) {
+		h := newHandler(t, map[string]string{
+			requestKey: ".order",
+			routesKey:  "/v1.0/invoke/*/method/orders",
+		}, echo)
+
+		ctx := serve(h, "/v1.0/invoke/orders/method/payments", `{"order":1}`)
+		assert.Equal(t, `{"order":1}`, string(ctx.Response.Body()))
+
+		ctx = serve(h, "/v1.0/invoke/shop/method/orders", `{"order":1}`)
+		assert.Equal(t, `1`, string(ctx.Response.Body()))
+	})
+
+	t.Run("error responses are not transformed", func(t *testing.T) {
+		h := newHandler(t, map[string]string{responseKey: ".data", routesKey: allRoutes}, func(ctx *fasthttp.RequestCtx) {
+			ctx.SetStatusCode(fasthttp.StatusNotFound)
+			ctx.SetBodyString(`{"errorCode":"ERR_NOT_FOUND"}`)
+		})
+
+		ctx := serve(h, "/v1.0/invoke/orders/method/new", "")
+		assert.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
+		assert.Equal(t, `{"errorCode":"ERR_NOT_FOUND"}`, string(ctx.Response.Body()))
+	})
+
+	t.Run("malformed request body is rejected", func(t *testing.T) {
+		called := false
+		h := newHandler(t, map[string]string{requestKey: ".order", routesKey: allRoutes}, func(ctx *fasthttp.RequestCtx) {
+			called = true
+		})
+
+		ctx := serve(h, "/v1.0/invoke/orders/method/new", "order=1")
+		assert.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode())
+		assert.False(t, called)
+	})
+
+	t.Run("no route is transformed by default", func(t *testing.T) {
+		h := newHandler(t, map[string]string{requestKey: ".order"}, echo)
+
+		ctx := serve(h, "/v1.0/invoke/orders/method/new", `{"order":1}`)
+		assert.Equal(t, `{"order":1}`, string(ctx.Response.Body()))
+	})
+
+	t.Run("non-JSON bodies are not transformed", func(t *testing.T) {
+		h := newHandler(t, map[string]string{requestKey: ".order", responseKey: ".data", routesKey: allRoutes}, func(ctx *fasthttp.RequestCtx) {
+			ctx.Response.Header.SetContentType("text/plain")
+			ctx.Response.SetBody(ctx.Request.Body())
+		})
+
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.SetRequestURI("/v1.0/invoke/orders/method/new")
+		ctx.Request.Header.SetContentType("text/plain")
+		ctx.Request.SetBodyString("order=1")
+		h(ctx)
+		assert.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+		assert.Equal(t, "order=1", string(ctx.Response.Body()))
+	})
+
+	t.Run("streamed responses are not transformed", func(t *testing.T) {
+		h := newHandler(t, map[string]string{responseKey: ".data", routesKey: allRoutes}, func(ctx *fasthttp.RequestCtx) {
+			ctx.Response.Header.SetContentType("application/json")
+			ctx.Response.SetBodyStreamWriter(func(w *bufio.Writer) {
+				w.WriteString(`{"data":1}`)
+			})
+		})
+
+		ctx := serve(h, "/v1.0/invoke/orders/method/new", "")
+		assert.True(t, ctx.Response.IsBodyStream())
+	})
+
+	t.Run("numbers are kept as is", func(t *testing.T) {
+		h := newHandler(t, map[string]string{requestKey: ".id", routesKey: allRoutes}, echo)
+
+		ctx := serve(h, "/v1.0/invoke/orders/method/new", `{"id":12345678901234567890}`)
+		assert.Equal(t, `12345678901234567890`, string(ctx.Response.Body()))
+	})
+
+	t.Run("jq programs are interrupted after the timeout", func(t *testing.T) {
+		h := newHandler(t, map[string]string{requestKey: "last(range(1e12))", routesKey: allRoutes, timeoutKey: "50ms"}, echo)
+
+		start := time.Now()
+		ctx := serve(h, "/v1.0/invoke/orders/method/new", `{}`)
+		assert.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode())
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+}
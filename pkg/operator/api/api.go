@@ -65,14 +65,24 @@ type apiServer struct {
 	// notify all dapr runtime
 	connLock          sync.Mutex
 	allConnUpdateChan map[string]chan *componentsapi.Component
+	// podConns is the number of component update streams of each pod, by namespace and pod name.
+	podConns map[string]int
+	// podReader reads the pods of the sidecars from the API server, without caching all the pods of the cluster.
+	podReader client.Reader
+	// callerPod returns the namespace and the name of the pod of the calling sidecar.
+	callerPod func(ctx context.Context) (string, string, error)
 }
 
-// NewAPIServer returns a new API server.
-func NewAPIServer(client client.Client) Server {
-	return &apiServer{
+// NewAPIServer returns a new API server. The pods of the calling sidecars are looked up with podReader.
+func NewAPIServer(client client.Client, podReader client.Reader) Server {
+	a := &apiServer{
 		Client:            client,
 		allConnUpdateChan: make(map[string]chan *componentsapi.Component),
+		podConns:          make(map[string]int),
+		podReader:         podReader,
 	}
+	a.callerPod = a.getCallerPod
+	return a
 }
 
 // Run starts a new gRPC server.
@@ -132,7 +142,7 @@ func (a *apiServer) ListComponents(ctx context.Context, in *operatorv1pb.ListCom
 	}
 	for i := range components.Items {
 		c := components.Items[i] // Make a copy since we will refer to this as a reference in this loop.
-		err := processComponentSecrets(&c, in.Namespace, a.Client)
+		err := processComponentSecrets(&c, in.Namespace, in.DefaultSecretStore, a.Client)
 		if err != nil {
 			log.Warnf("error processing component %s secrets: %s", c.Name, err)
 			return &operatorv1pb.ListComponentResponse{}, err
@@ -148,9 +158,21 @@ func (a *apiServer) ListComponents(ctx context.Context, in *operatorv1pb.ListCom
 	return resp, nil
 }
 
-func processComponentSecrets(component *componentsapi.Component, namespace string, kubeClient client.Client) error {
+// processComponentSecrets resolves the secret references of a component with the kubernetes secrets of its namespace,
+// when its secret store is the kubernetes secret store. The components without a secret store use the default secret
+// store of the configuration of the sidecar, whose references are left to the sidecar unless it is the kubernetes
+// secret store.
+func processComponentSecrets(component *componentsapi.Component, namespace, defaultSecretStore string, kubeClient client.Client) error {
+	secretStore := component.Auth.SecretStore
+	if secretStore == "" {
+		secretStore = defaultSecretStore
+	}
+	if secretStore != "" && secretStore != kubernetesSecretStore {
+		return nil
+	}
+
 	for i, m := range component.Spec.Metadata {
-		if m.SecretKeyRef.Name != "" && (component.Auth.SecretStore == kubernetesSecretStore || component.Auth.SecretStore == "") {
+		if m.SecretKeyRef.Name != "" {
 			var secret corev1.Secret
 
 			err := kubeClient.Get(context.TODO(), types.NamespacedName{
@@ -217,14 +239,36 @@ func (a *apiServer) ListSubscriptions(ctx context.Context, in *emptypb.Empty) (*
 func (a *apiServer) ComponentUpdate(in *operatorv1pb.ComponentUpdateRequest, srv operatorv1pb.Operator_ComponentUpdateServer) error {
 	log.Info("sidecar connected for component updates")
 	key := uuid.New().String()
+	// The component statuses reported by the pod of the sidecar are removed when its last stream ends.
+	podNamespace, podName, err := a.callerPod(srv.Context())
+	if err != nil {
+		log.Debugf("component statuses of the sidecar won't be removed on disconnection: %s", err)
+	}
+	podKey := podNamespace + "/" + podName
 	a.connLock.Lock()
 	a.allConnUpdateChan[key] = make(chan *componentsapi.Component, 1)
 	updateChan := a.allConnUpdateChan[key]
+	if podName != "" {
+		a.podConns[podKey]++
+	}
 	a.connLock.Unlock()
 	defer func() {
+		disconnected := false
 		a.connLock.Lock()
 		delete(a.allConnUpdateChan, key)
+		if podName != "" {
+			a.podConns[podKey]--
+			if a.podConns[podKey] <= 0 {
+				delete(a.podConns, podKey)
+				disconnected = true
+			}
+		}
 		a.connLock.Unlock()
+
+		// The sidecar reports the statuses of its components again when it reconnects.
+		if disconnected {
+			a.removeComponentPodStatuses(context.Background(), podNamespace, podName)
+		}
 	}()
 	chWrapper := initChanGracefully(updateChan)
 	updateComponentFunc := func(c *componentsapi.Component) {
@@ -232,7 +276,9 @@ func (a *apiServer) ComponentUpdate(in *operatorv1pb.ComponentUpdateRequest, srv
 			return
 		}
 
-		err := processComponentSecrets(c, in.Namespace, a.Client)
+		// The component is shared by all the connections, which may resolve its secrets with different default stores.
+		c = c.DeepCopy()
+		err := processComponentSecrets(c, in.Namespace, in.DefaultSecretStore, a.Client)
 		if err != nil {
 			log.Warnf("error processing component %s secrets: %s", c.Name, err)
 			return
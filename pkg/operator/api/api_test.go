@@ -65,7 +65,7 @@ func TestProcessComponentSecrets(t *testing.T) {
 			},
 		}
 
-		err := processComponentSecrets(&c, "default", nil)
+		err := processComponentSecrets(&c, "default", "", nil)
 		assert.NoError(t, err)
 	})
 
@@ -107,7 +107,7 @@ func TestProcessComponentSecrets(t *testing.T) {
 			}).
 			Build()
 
-		err = processComponentSecrets(&c, "default", client)
+		err = processComponentSecrets(&c, "default", "", client)
 		assert.NoError(t, err)
 
 		enc := base64.StdEncoding.EncodeToString([]byte("value1"))
@@ -154,7 +154,7 @@ func TestProcessComponentSecrets(t *testing.T) {
 			}).
 			Build()
 
-		err = processComponentSecrets(&c, "default", client)
+		err = processComponentSecrets(&c, "default", "", client)
 		assert.NoError(t, err)
 
 		enc := base64.StdEncoding.EncodeToString([]byte("value1"))
@@ -162,6 +162,89 @@ func TestProcessComponentSecrets(t *testing.T) {
 
 		assert.Equal(t, jsonEnc, c.Spec.Metadata[0].Value.Raw)
 	})
+
+	t.Run("secret ref exists, non kubernetes default secret store, no error", func(t *testing.T) {
+		c := componentsapi.Component{
+			Spec: componentsapi.ComponentSpec{
+				Metadata: []componentsapi.MetadataItem{
+					{
+						Name: "test1",
+						SecretKeyRef: componentsapi.SecretKeyRef{
+							Name: "secret1",
+							Key:  "key1",
+						},
+					},
+				},
+			},
+		}
+
+		err := processComponentSecrets(&c, "default", "vault", nil)
+		assert.NoError(t, err)
+		assert.Empty(t, c.Spec.Metadata[0].Value.Raw)
+	})
+
+	t.Run("secret ref not found, default kubernetes secret store, error", func(t *testing.T) {
+		c := componentsapi.Component{
+			Spec: componentsapi.ComponentSpec{
+				Metadata: []componentsapi.MetadataItem{
+					{
+						Name: "test1",
+						SecretKeyRef: componentsapi.SecretKeyRef{
+							Name: "secret1",
+							Key:  "key1",
+						},
+					},
+				},
+			},
+		}
+
+		s := runtime.NewScheme()
+		err := scheme.AddToScheme(s)
+		assert.NoError(t, err)
+
+		err = corev1.AddToScheme(s)
+		assert.NoError(t, err)
+
+		client := fake.NewClientBuilder().
+			WithScheme(s).
+			Build()
+
+		err = processComponentSecrets(&c, "default", "", client)
+		assert.Error(t, err)
+	})
+
+	t.Run("secret ref not found, kubernetes secret store, error", func(t *testing.T) {
+		c := componentsapi.Component{
+			Spec: componentsapi.ComponentSpec{
+				Metadata: []componentsapi.MetadataItem{
+					{
+						Name: "test1",
+						SecretKeyRef: componentsapi.SecretKeyRef{
+							Name: "secret1",
+							Key:  "key1",
+						},
+					},
+				},
+			},
+			Auth: componentsapi.Auth{
+				SecretStore: kubernetesSecretStore,
+			},
+		}
+
+		s := runtime.NewScheme()
+		err := scheme.AddToScheme(s)
+		assert.NoError(t, err)
+
+		err = corev1.AddToScheme(s)
+		assert.NoError(t, err)
+
+		client := fake.NewClientBuilder().
+			WithScheme(s).
+			Build()
+
+		err = processComponentSecrets(&c, "default", "", client)
+		assert.Error(t, err)
+	})
 }
 
 func TestChanGracefullyClose(t *testing.T) {
@@ -193,7 +276,7 @@ func TestComponentUpdate(t *testing.T) {
 			WithScheme(s).Build()
 
 		mockSidecar := &mockComponentUpdateServer{}
-		api := NewAPIServer(client).(*apiServer)
+		api := NewAPIServer(client, client).(*apiServer)
 
 		go func() {
 			// Send a component update, give sidecar time to register
@@ -235,7 +318,7 @@ func TestComponentUpdate(t *testing.T) {
 			WithScheme(s).Build()
 
 		mockSidecar := &mockComponentUpdateServer{}
-		api := NewAPIServer(client).(*apiServer)
+		api := NewAPIServer(client, client).(*apiServer)
 
 		go func() {
 			// Send a component update, give sidecar time to register
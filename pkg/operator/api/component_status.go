@@ -0,0 +1,229 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/dapr/dapr/pkg/acl"
+	componentsapi "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	operatorv1pb "github.com/dapr/dapr/pkg/proto/operator/v1"
+)
+
+// appIDAnnotationKey is the annotation of the app ID of a pod, which defaults to the name of the pod.
+const appIDAnnotationKey = "dapr.io/app-id"
+
+// ReportComponentStatus records the initialization result of a component reported by a sidecar in the status of
+// the component. The namespace and the pod are the ones of the calling sidecar, so a sidecar can only report the
+// statuses of its own pod.
+func (a *apiServer) ReportComponentStatus(ctx context.Context, in *operatorv1pb.ReportComponentStatusRequest) (*emptypb.Empty, error) {
+	namespace, podName, err := a.callerPod(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pod := componentsapi.ComponentPodStatus{
+		Name:        podName,
+		Initialized: in.Initialized,
+		Healthy:     in.Initialized && in.Healthy,
+		Pending:     !in.Initialized && in.Pending,
+		Message:     in.Message,
+	}
+	key := types.NamespacedName{Namespace: namespace, Name: in.Component}
+	err = a.updateComponentStatus(ctx, key, func(status *componentsapi.ComponentStatus) bool {
+		return setComponentPodStatus(status, pod)
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error updating the status of component %s", in.Component)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// getCallerPod returns the namespace and the name of the pod of the calling sidecar. The namespace and the app ID
+// are the ones of the SPIFFE ID of its client certificate, and the pod is the pod of the app with the address of
+// the caller.
+func (a *apiServer) getCallerPod(ctx context.Context) (string, string, error) {
+	id, err := acl.GetAndParseSpiffeID(ctx)
+	if err != nil {
+		return "", "", status.Errorf(codes.Unauthenticated, "unable to identify the sidecar: %s", err)
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "", "", status.Error(codes.Unauthenticated, "unable to get the address of the sidecar")
+	}
+	ip, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return "", "", status.Errorf(codes.Unauthenticated, "unable to get the address of the sidecar: %s", err)
+	}
+
+	var pods corev1.PodList
+	err = a.podReader.List(ctx, &pods, client.InNamespace(id.Namespace), client.MatchingFields{"status.podIP": ip})
+	if err != nil {
+		return "", "", errors.Wrapf(err, "error listing the pods with address %s", ip)
+	}
+	for _, pod := range pods.Items {
+		appID, ok := pod.Annotations[appIDAnnotationKey]
+		if !ok {
+			appID = pod.Name
+		}
+		if appID == id.AppID {
+			return id.Namespace, pod.Name, nil
+		}
+	}
+	return "", "", status.Errorf(codes.PermissionDenied, "no pod of app %s with address %s in namespace %s", id.AppID, ip, id.Namespace)
+}
+
+// removeComponentPodStatuses removes the statuses reported by a pod from the components of its namespace, once
+// its sidecar is disconnected.
+func (a *apiServer) removeComponentPodStatuses(ctx context.Context, namespace, podName string) {
+	var components componentsapi.ComponentList
+	if err := a.Client.List(ctx, &components, client.InNamespace(namespace)); err != nil {
+		log.Warnf("error listing components to remove the statuses of pod %s/%s: %s", namespace, podName, err)
+		return
+	}
+
+	for _, c := range components.Items {
+		if findComponentPodStatus(&c.Status, podName) < 0 {
+			continue
+		}
+		key := types.NamespacedName{Namespace: namespace, Name: c.Name}
+		err := a.updateComponentStatus(ctx, key, func(status *componentsapi.ComponentStatus) bool {
+			i := findComponentPodStatus(status, podName)
+			if i < 0 {
+				return false
+			}
+			status.Pods = append(status.Pods[:i], status.Pods[i+1:]...)
+			return true
+		})
+		if err != nil {
+			log.Warnf("error removing the status of pod %s from component %s/%s: %s", podName, namespace, c.Name, err)
+		}
+	}
+}
+
+// updateComponentStatus applies a change to the pod statuses of a component and updates its conditions, retrying
+// on conflicts with the updates of the other operator instances.
+func (a *apiServer) updateComponentStatus(ctx context.Context, key types.NamespacedName, update func(status *componentsapi.ComponentStatus) bool) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var component componentsapi.Component
+		if err := a.Client.Get(ctx, key, &component); err != nil {
+			return err
+		}
+		if !update(&component.Status) {
+			return nil
+		}
+		setComponentConditions(&component.Status, component.Generation)
+		return a.Client.Status().Update(ctx, &component)
+	})
+}
+
+func findComponentPodStatus(status *componentsapi.ComponentStatus, podName string) int {
+	for i := range status.Pods {
+		if status.Pods[i].Name == podName {
+			return i
+		}
+	}
+	return -1
+}
+
+// setComponentPodStatus sets the status reported by a pod. It returns false if the status is unchanged.
+func setComponentPodStatus(status *componentsapi.ComponentStatus, pod componentsapi.ComponentPodStatus) bool {
+	if i := findComponentPodStatus(status, pod.Name); i >= 0 {
+		if status.Pods[i] == pod {
+			return false
+		}
+		status.Pods[i] = pod
+		return true
+	}
+
+	status.Pods = append(status.Pods, pod)
+	sort.Slice(status.Pods, func(i, j int) bool {
+		return status.Pods[i].Name < status.Pods[j].Name
+	})
+	return true
+}
+
+// setComponentConditions updates the consumers and the conditions of a component from its pod statuses. The pods
+// whose sidecar initializes the component on its first use, which it didn't make yet, don't count as failures.
+func setComponentConditions(status *componentsapi.ComponentStatus, generation int64) {
+	initialized := metav1.Condition{
+		Type:               componentsapi.ComponentInitialized,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             "Initialized",
+	}
+	healthy := metav1.Condition{
+		Type:               componentsapi.ComponentHealthy,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             "Healthy",
+	}
+
+	status.Consumers = 0
+	pending := 0
+	for _, pod := range status.Pods {
+		if pod.Pending {
+			pending++
+			continue
+		}
+		if !pod.Initialized {
+			if initialized.Status == metav1.ConditionTrue {
+				initialized.Status = metav1.ConditionFalse
+				initialized.Reason = "InitFailed"
+				initialized.Message = fmt.Sprintf("pod %s: %s", pod.Name, pod.Message)
+			}
+			continue
+		}
+
+		status.Consumers++
+		if !pod.Healthy && healthy.Status == metav1.ConditionTrue {
+			healthy.Status = metav1.ConditionFalse
+			healthy.Reason = "Unhealthy"
+			healthy.Message = fmt.Sprintf("pod %s: %s", pod.Name, pod.Message)
+		}
+	}
+
+	if len(status.Pods) == 0 {
+		initialized.Status = metav1.ConditionUnknown
+		initialized.Reason = "NoConsumers"
+		initialized.Message = "the component isn't loaded by any sidecar"
+	} else if pending == len(status.Pods) {
+		initialized.Status = metav1.ConditionUnknown
+		initialized.Reason = "Pending"
+		initialized.Message = "the component is initialized on its first use, which no sidecar made yet"
+	}
+	if status.Consumers == 0 {
+		healthy.Status = metav1.ConditionUnknown
+		healthy.Reason = "NotInitialized"
+		healthy.Message = "the component isn't initialized by any sidecar"
+	}
+
+	meta.SetStatusCondition(&status.Conditions, initialized)
+	meta.SetStatusCondition(&status.Conditions, healthy)
+}
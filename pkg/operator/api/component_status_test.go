@@ -0,0 +1,157 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	componentsapi "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	"github.com/dapr/dapr/pkg/client/clientset/versioned/scheme"
+	operatorv1pb "github.com/dapr/dapr/pkg/proto/operator/v1"
+)
+
+func TestReportComponentStatus(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, scheme.AddToScheme(s))
+
+	component := &componentsapi.Component{
+		ObjectMeta: metav1.ObjectMeta{Name: "statestore", Namespace: "ns1"},
+		Spec:       componentsapi.ComponentSpec{Type: "state.redis"},
+	}
+	client := fake.NewClientBuilder().WithScheme(s).WithObjects(component).Build()
+	api := NewAPIServer(client, client).(*apiServer)
+	var caller string
+	api.callerPod = func(ctx context.Context) (string, string, error) {
+		return "ns1", caller, nil
+	}
+	getStatus := func(t *testing.T) componentsapi.ComponentStatus {
+		var c componentsapi.Component
+		require.NoError(t, api.Client.Get(context.Background(), types.NamespacedName{Namespace: "ns1", Name: "statestore"}, &c))
+		return c.Status
+	}
+	report := func(t *testing.T, pod string, initialized, healthy bool, message string) {
+		caller = pod
+		_, err := api.ReportComponentStatus(context.Background(), &operatorv1pb.ReportComponentStatusRequest{
+			Component:   "statestore",
+			Initialized: initialized,
+			Healthy:     healthy,
+			Message:     message,
+		})
+		require.NoError(t, err)
+	}
+
+	report(t, "app-1", true, true, "")
+	report(t, "app-0", true, true, "")
+	status := getStatus(t)
+	assert.Equal(t, int32(2), status.Consumers)
+	assert.Equal(t, "app-0", status.Pods[0].Name)
+	assert.True(t, meta.IsStatusConditionTrue(status.Conditions, componentsapi.ComponentInitialized))
+	assert.True(t, meta.IsStatusConditionTrue(status.Conditions, componentsapi.ComponentHealthy))
+
+	report(t, "app-2", false, false, "connection refused")
+	status = getStatus(t)
+	assert.Equal(t, int32(2), status.Consumers)
+	initialized := meta.FindStatusCondition(status.Conditions, componentsapi.ComponentInitialized)
+	assert.Equal(t, metav1.ConditionFalse, initialized.Status)
+	assert.Equal(t, "pod app-2: connection refused", initialized.Message)
+	assert.True(t, meta.IsStatusConditionTrue(status.Conditions, componentsapi.ComponentHealthy))
+
+	report(t, "app-0", true, false, "ping failed")
+	assert.True(t, meta.IsStatusConditionFalse(getStatus(t).Conditions, componentsapi.ComponentHealthy))
+
+	api.removeComponentPodStatuses(context.Background(), "ns1", "app-2")
+	api.removeComponentPodStatuses(context.Background(), "ns1", "app-0")
+	status = getStatus(t)
+	assert.Len(t, status.Pods, 1)
+	assert.Equal(t, int32(1), status.Consumers)
+	assert.True(t, meta.IsStatusConditionTrue(status.Conditions, componentsapi.ComponentInitialized))
+	assert.True(t, meta.IsStatusConditionTrue(status.Conditions, componentsapi.ComponentHealthy))
+
+	api.removeComponentPodStatuses(context.Background(), "ns1", "app-1")
+	status = getStatus(t)
+	assert.Zero(t, status.Consumers)
+	assert.Equal(t, metav1.ConditionUnknown, meta.FindStatusCondition(status.Conditions, componentsapi.ComponentInitialized).Status)
+}
+
+func TestReportComponentStatusPending(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, scheme.AddToScheme(s))
+
+	component := &componentsapi.Component{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "ns1"},
+		Spec:       componentsapi.ComponentSpec{Type: "bindings.kafka", InitMode: componentsapi.InitModeLazy},
+	}
+	client := fake.NewClientBuilder().WithScheme(s).WithObjects(component).Build()
+	api := NewAPIServer(client, client).(*apiServer)
+	var caller string
+	api.callerPod = func(ctx context.Context) (string, string, error) {
+		return "ns1", caller, nil
+	}
+	getStatus := func(t *testing.T) componentsapi.ComponentStatus {
+		var c componentsapi.Component
+		require.NoError(t, api.Client.Get(context.Background(), types.NamespacedName{Namespace: "ns1", Name: "kafka"}, &c))
+		return c.Status
+	}
+	report := func(t *testing.T, pod string, req *operatorv1pb.ReportComponentStatusRequest) {
+		caller = pod
+		req.Component = "kafka"
+		_, err := api.ReportComponentStatus(context.Background(), req)
+		require.NoError(t, err)
+	}
+
+	report(t, "app-0", &operatorv1pb.ReportComponentStatusRequest{Pending: true})
+	report(t, "app-1", &operatorv1pb.ReportComponentStatusRequest{Pending: true})
+	status := getStatus(t)
+	assert.True(t, status.Pods[0].Pending)
+	assert.Zero(t, status.Consumers)
+	initialized := meta.FindStatusCondition(status.Conditions, componentsapi.ComponentInitialized)
+	assert.Equal(t, metav1.ConditionUnknown, initialized.Status)
+	assert.Equal(t, "Pending", initialized.Reason)
+
+	report(t, "app-1", &operatorv1pb.ReportComponentStatusRequest{Initialized: true, Healthy: true})
+	status = getStatus(t)
+	assert.False(t, status.Pods[1].Pending)
+	assert.Equal(t, int32(1), status.Consumers)
+	assert.True(t, meta.IsStatusConditionTrue(status.Conditions, componentsapi.ComponentInitialized))
+	assert.True(t, meta.IsStatusConditionTrue(status.Conditions, componentsapi.ComponentHealthy))
+
+	report(t, "app-0", &operatorv1pb.ReportComponentStatusRequest{Message: "connection refused"})
+	status = getStatus(t)
+	assert.False(t, status.Pods[0].Pending)
+	assert.True(t, meta.IsStatusConditionFalse(status.Conditions, componentsapi.ComponentInitialized))
+}
+
+func TestReportComponentStatusUnidentifiedCaller(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, scheme.AddToScheme(s))
+
+	client := fake.NewClientBuilder().WithScheme(s).Build()
+	api := NewAPIServer(client, client).(*apiServer)
+	_, err := api.ReportComponentStatus(context.Background(), &operatorv1pb.ReportComponentStatusRequest{
+		Component:   "statestore",
+		Initialized: true,
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
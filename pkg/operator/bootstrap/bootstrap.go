@@ -0,0 +1,187 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bootstrap installs and updates the cluster-wide resources of the control plane, i.e. the
+// CustomResourceDefinitions, the webhooks and the RBAC, for the platforms which can't run Helm.
+package bootstrap
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	// VersionAnnotation is the annotation recording the Dapr version which installed a resource.
+	VersionAnnotation = "dapr.io/version"
+
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedBy      = "dapr-operator"
+)
+
+var log = logger.NewLogger("dapr.operator.bootstrap")
+
+// Options are the options of the bootstrap of a cluster.
+type Options struct {
+	// Namespace is the namespace of the control plane.
+	Namespace string
+	// Version is the Dapr version being installed. The version skew checks are skipped for unreleased versions.
+	Version string
+	// Force skips the version skew checks.
+	Force bool
+	// InjectorFailurePolicy is the failure policy of the sidecar injector webhook.
+	InjectorFailurePolicy admissionregistrationv1.FailurePolicyType
+	// ValidationWebhook installs the webhook validating the Dapr resources.
+	ValidationWebhook bool
+	// ValidationFailurePolicy is the failure policy of the validating webhook.
+	ValidationFailurePolicy admissionregistrationv1.FailurePolicyType
+	// WatchNamespaces are the namespaces watched by the control plane, which are granted roles in these namespaces
+	// only and whose webhooks only admit their resources. The control plane watches all the namespaces if empty.
+	WatchNamespaces []string
+}
+
+// NewScheme returns a scheme with the types of the resources installed by the bootstrap.
+func NewScheme() (*runtime.Scheme, error) {
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		return nil, err
+	}
+	if err := apiextensionsv1.AddToScheme(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// InitClusterForConfig installs or updates the cluster-wide resources of the control plane in the cluster of conf.
+func InitClusterForConfig(ctx context.Context, conf *rest.Config, opts Options) error {
+	s, err := NewScheme()
+	if err != nil {
+		return err
+	}
+	c, err := client.New(conf, client.Options{Scheme: s})
+	if err != nil {
+		return errors.Wrap(err, "error creating the Kubernetes client")
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(conf)
+	if err != nil {
+		return errors.Wrap(err, "error creating the Kubernetes discovery client")
+	}
+	return InitCluster(ctx, c, discoveryClient, opts)
+}
+
+// InitCluster installs or updates the CustomResourceDefinitions, the webhooks and the RBAC of the control plane.
+// The existing resources are updated in place, so it can run on every upgrade of the control plane. It fails if
+// the Kubernetes version isn't supported or if the installed resources can't be moved to opts.Version, unless
+// opts.Force is set.
+func InitCluster(ctx context.Context, c client.Client, serverVersion discovery.ServerVersionInterface, opts Options) error {
+	if opts.Namespace == "" {
+		return errors.New("the namespace of the control plane is required")
+	}
+
+	crds, err := loadCRDs()
+	if err != nil {
+		return err
+	}
+
+	if err = checkKubernetesVersion(serverVersion, opts); err != nil {
+		return err
+	}
+	if err = checkInstalledVersion(ctx, c, crds, opts); err != nil {
+		return err
+	}
+
+	if err = applyRBAC(ctx, c, opts); err != nil {
+		return err
+	}
+	webhookCA, injectorCA, err := applyWebhookCerts(ctx, c, opts)
+	if err != nil {
+		return err
+	}
+	if err = applyCRDs(ctx, c, crds, webhookCA, opts); err != nil {
+		return err
+	}
+	if err = applyWebhooks(ctx, c, webhookCA, injectorCA, opts); err != nil {
+		return err
+	}
+
+	log.Infof("cluster initialized for Dapr %s in namespace %s", opts.Version, opts.Namespace)
+	return nil
+}
+
+// clusterScopedName returns the name of a cluster-scoped resource of the control plane. The name is suffixed by the
+// namespace of the control plane when it watches some namespaces, so the control planes of a cluster don't share it.
+func clusterScopedName(name string, opts Options) string {
+	if len(opts.WatchNamespaces) == 0 {
+		return name
+	}
+	return name + "-" + opts.Namespace
+}
+
+// watchedNamespaces returns the namespaces watched by the control plane, along with its own namespace.
+func watchedNamespaces(opts Options) []string {
+	namespaces := []string{opts.Namespace}
+	for _, ns := range opts.WatchNamespaces {
+		if ns != opts.Namespace {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// apply creates obj or updates it with mutate, and marks it as managed by the operator.
+func apply(ctx context.Context, c client.Client, obj client.Object, version string, mutate func()) error {
+	result, err := controllerutil.CreateOrUpdate(ctx, c, obj, func() error {
+		mutate()
+		setManaged(obj, version)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error applying %s %s", kindOf(obj), client.ObjectKeyFromObject(obj))
+	}
+	if result != controllerutil.OperationResultNone {
+		log.Infof("%s %s %s", kindOf(obj), client.ObjectKeyFromObject(obj), result)
+	}
+	return nil
+}
+
+func kindOf(obj client.Object) string {
+	return reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+}
+
+func setManaged(obj meta_v1.Object, version string) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[managedByLabel] = managedBy
+	obj.SetLabels(labels)
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[VersionAnnotation] = version
+	obj.SetAnnotations(annotations)
+}
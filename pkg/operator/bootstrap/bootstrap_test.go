@@ -0,0 +1,226 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestClient(t *testing.T, objects ...client.Object) client.Client {
+	s, err := NewScheme()
+	require.NoError(t, err)
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objects...).Build()
+}
+
+func newTestDiscovery(gitVersion string) *fakediscovery.FakeDiscovery {
+	return &fakediscovery.FakeDiscovery{
+		Fake:               &clienttesting.Fake{},
+		FakedServerVersion: &version.Info{GitVersion: gitVersion},
+	}
+}
+
+func testOptions(version string) Options {
+	return Options{
+		Namespace:               "dapr-system",
+		Version:                 version,
+		InjectorFailurePolicy:   admissionregistrationv1.Ignore,
+		ValidationWebhook:       true,
+		ValidationFailurePolicy: admissionregistrationv1.Fail,
+	}
+}
+
+func assertSignedBy(t *testing.T, caPEM, certPEM []byte, dnsName string) {
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(caPEM))
+	block, _ := pem.Decode(certPEM)
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	_, err = cert.Verify(x509.VerifyOptions{Roots: roots, DNSName: dnsName})
+	assert.NoError(t, err)
+}
+
+func TestInitCluster(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("installs the resources", func(t *testing.T) {
+		c := newTestClient(t)
+		require.NoError(t, InitCluster(ctx, c, newTestDiscovery("v1.20.0"), testOptions("1.6.0")))
+
+		crds, err := loadCRDs()
+		require.NoError(t, err)
+		require.NotEmpty(t, crds)
+		for _, crd := range crds {
+			var installed apiextensionsv1.CustomResourceDefinition
+			require.NoError(t, c.Get(ctx, types.NamespacedName{Name: crd.Name}, &installed))
+			assert.Equal(t, "1.6.0", installed.Annotations[VersionAnnotation])
+			assert.Equal(t, managedBy, installed.Labels[managedByLabel])
+		}
+
+		var caSecret, certSecret corev1.Secret
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "dapr-system", Name: webhookCASecret}, &caSecret))
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "dapr-system", Name: webhookCertSecret}, &certSecret))
+		webhookCA := caSecret.Data[webhookCAKey]
+		assertSignedBy(t, webhookCA, certSecret.Data[corev1.TLSCertKey], "dapr-webhook.dapr-system.svc")
+
+		var subscriptions apiextensionsv1.CustomResourceDefinition
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "subscriptions.dapr.io"}, &subscriptions))
+		clientConfig := subscriptions.Spec.Conversion.Webhook.ClientConfig
+		assert.Equal(t, "dapr-system", clientConfig.Service.Namespace)
+		assert.Equal(t, webhookCA, clientConfig.CABundle)
+
+		var validating admissionregistrationv1.ValidatingWebhookConfiguration
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Name: validationWebhook}, &validating))
+		assert.Equal(t, webhookCA, validating.Webhooks[0].ClientConfig.CABundle)
+		assert.Equal(t, admissionregistrationv1.Fail, *validating.Webhooks[0].FailurePolicy)
+
+		var injectorSecret corev1.Secret
+		var mutating admissionregistrationv1.MutatingWebhookConfiguration
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "dapr-system", Name: injectorCertSecret}, &injectorSecret))
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Name: injectorWebhook}, &mutating))
+		assertSignedBy(t, mutating.Webhooks[0].ClientConfig.CABundle, injectorSecret.Data[corev1.TLSCertKey], "dapr-sidecar-injector.dapr-system.svc")
+		assert.Equal(t, "dapr-system", mutating.Webhooks[0].ClientConfig.Service.Namespace)
+
+		var binding rbacv1.ClusterRoleBinding
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "dapr-operator"}, &binding))
+		assert.Equal(t, []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: operatorServiceAccount, Namespace: "dapr-system"}}, binding.Subjects)
+		assert.Equal(t, operatorClusterRole, binding.RoleRef.Name)
+
+		var certManagerRole rbacv1.Role
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "dapr-system", Name: sentryCertManagerRole}, &certManagerRole))
+		assert.Equal(t, sentryCertManagerRules, certManagerRole.Rules)
+
+		// The certificates are kept on upgrades.
+		require.NoError(t, InitCluster(ctx, c, newTestDiscovery("v1.20.0"), testOptions("1.7.0")))
+		var upgraded corev1.Secret
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "dapr-system", Name: webhookCASecret}, &upgraded))
+		assert.Equal(t, webhookCA, upgraded.Data[webhookCAKey])
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "subscriptions.dapr.io"}, &subscriptions))
+		assert.Equal(t, "1.7.0", subscriptions.Annotations[VersionAnnotation])
+	})
+
+	t.Run("installs the resources of the watched namespaces", func(t *testing.T) {
+		c := newTestClient(t)
+		opts := testOptions("1.6.0")
+		opts.WatchNamespaces = []string{"team-a", "team-b"}
+		require.NoError(t, InitCluster(ctx, c, newTestDiscovery("v1.21.0"), opts))
+
+		var clusterRole rbacv1.ClusterRole
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "dapr-operator-admin-dapr-system"}, &clusterRole))
+		assert.Equal(t, operatorClusterRules, clusterRole.Rules)
+		var clusterRoleBinding rbacv1.ClusterRoleBinding
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "dapr-operator-dapr-system"}, &clusterRoleBinding))
+		assert.Equal(t, "dapr-operator-admin-dapr-system", clusterRoleBinding.RoleRef.Name)
+		// The roles of the cluster-wide installation aren't installed.
+		assert.Error(t, c.Get(ctx, types.NamespacedName{Name: operatorClusterRole}, &clusterRole))
+		assert.Error(t, c.Get(ctx, types.NamespacedName{Name: dashboardClusterRole}, &clusterRole))
+
+		for _, ns := range []string{"dapr-system", "team-a", "team-b"} {
+			var role rbacv1.Role
+			require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: ns, Name: operatorClusterRole}, &role))
+			for _, rule := range role.Rules {
+				assert.NotContains(t, rule.Resources, "namespaces")
+				assert.NotContains(t, rule.Resources, "customresourcedefinitions")
+			}
+			var binding rbacv1.RoleBinding
+			require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: ns, Name: "dapr-operator"}, &binding))
+			assert.Equal(t, []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: operatorServiceAccount, Namespace: "dapr-system"}}, binding.Subjects)
+		}
+
+		var validating admissionregistrationv1.ValidatingWebhookConfiguration
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "dapr-operator-dapr-system"}, &validating))
+		var mutating admissionregistrationv1.MutatingWebhookConfiguration
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "dapr-sidecar-injector-dapr-system"}, &mutating))
+		for _, selector := range []*meta_v1.LabelSelector{validating.Webhooks[0].NamespaceSelector, mutating.Webhooks[0].NamespaceSelector} {
+			require.NotNil(t, selector)
+			assert.Equal(t, []string{"dapr-system", "team-a", "team-b"}, selector.MatchExpressions[0].Values)
+		}
+	})
+
+	t.Run("watched namespaces on an unsupported Kubernetes version", func(t *testing.T) {
+		opts := testOptions("1.6.0")
+		opts.WatchNamespaces = []string{"team-a"}
+		err := InitCluster(ctx, newTestClient(t), newTestDiscovery("v1.20.0"), opts)
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported Kubernetes version", func(t *testing.T) {
+		err := InitCluster(ctx, newTestClient(t), newTestDiscovery("v1.15.3"), testOptions("1.6.0"))
+		assert.Error(t, err)
+	})
+
+	t.Run("version skew", func(t *testing.T) {
+		installed := &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:        "components.dapr.io",
+				Annotations: map[string]string{VersionAnnotation: "1.7.0"},
+			},
+		}
+
+		err := InitCluster(ctx, newTestClient(t, installed), newTestDiscovery("v1.20.0"), testOptions("1.6.0"))
+		assert.Error(t, err)
+
+		opts := testOptions("1.6.0")
+		opts.Force = true
+		assert.NoError(t, InitCluster(ctx, newTestClient(t, installed), newTestDiscovery("v1.20.0"), opts))
+
+		// The skew isn't checked for unreleased versions.
+		assert.NoError(t, InitCluster(ctx, newTestClient(t, installed), newTestDiscovery("v1.20.0"), testOptions("edge")))
+	})
+}
+
+func TestCheckVersionSkew(t *testing.T) {
+	testCases := []struct {
+		installed string
+		target    string
+		expectErr bool
+	}{
+		{installed: "1.6.0", target: "1.6.0"},
+		{installed: "1.6.0", target: "1.6.1"},
+		{installed: "1.6.2", target: "1.7.0"},
+		{installed: "edge", target: "1.5.0"},
+		{installed: "1.6.1", target: "1.6.0", expectErr: true},
+		{installed: "1.7.0", target: "1.6.0", expectErr: true},
+		{installed: "1.5.0", target: "1.7.0", expectErr: true},
+		{installed: "1.6.0", target: "2.0.0", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.installed+" to "+tc.target, func(t *testing.T) {
+			err := checkVersionSkew(tc.installed, tc.target)
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/dapr/dapr/charts/dapr/crds"
+)
+
+// loadCRDs returns the CustomResourceDefinitions of the chart.
+func loadCRDs() ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	files, err := fs.Glob(crds.FS, "*.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*apiextensionsv1.CustomResourceDefinition
+	for _, file := range files {
+		b, err := crds.FS.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(b), len(b))
+		for {
+			crd := &apiextensionsv1.CustomResourceDefinition{}
+			if err = decoder.Decode(crd); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, errors.Wrapf(err, "error decoding %s", file)
+			}
+			if crd.Name != "" {
+				result = append(result, crd)
+			}
+		}
+	}
+	return result, nil
+}
+
+// applyCRDs installs or updates the CustomResourceDefinitions. The conversion webhooks are served by the operator in
+// the namespace of the control plane with the certificate of the webhook CA.
+func applyCRDs(ctx context.Context, c client.Client, crds []*apiextensionsv1.CustomResourceDefinition, webhookCA []byte, opts Options) error {
+	for _, desired := range crds {
+		if conversion := desired.Spec.Conversion; conversion != nil && conversion.Webhook != nil && conversion.Webhook.ClientConfig != nil {
+			if service := conversion.Webhook.ClientConfig.Service; service != nil {
+				service.Namespace = opts.Namespace
+			}
+			conversion.Webhook.ClientConfig.CABundle = webhookCA
+		}
+
+		crd := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: meta_v1.ObjectMeta{Name: desired.Name}}
+		if err := apply(ctx, c, crd, opts.Version, func() {
+			crd.Spec = desired.Spec
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
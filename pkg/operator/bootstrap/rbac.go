@@ -0,0 +1,226 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The RBAC mirrors the one of the dapr_rbac chart.
+const (
+	operatorServiceAccount  = "dapr-operator"
+	dashboardServiceAccount = "dashboard-reader"
+	operatorClusterRole     = "dapr-operator-admin"
+	dashboardClusterRole    = "dashboard-reader"
+	sentryCertManagerRole   = "dapr-sentry-cert-manager"
+	injectorRole            = "dapr-injector"
+)
+
+var operatorRules = []rbacv1.PolicyRule{
+	{
+		APIGroups: []string{"*"},
+		Resources: []string{"customresourcedefinitions", "serviceaccounts", "deployments", "statefulsets", "services", "configmaps", "secrets", "components", "configurations", "subscriptions", "daprrollouts", "daprinstances", "leases"},
+		Verbs:     []string{"get"},
+	},
+	{
+		APIGroups: []string{"*"},
+		Resources: []string{"deployments", "statefulsets", "services", "components", "configurations", "subscriptions", "daprrollouts", "daprinstances", "leases", "secrets"},
+		Verbs:     []string{"list"},
+	},
+	{
+		APIGroups: []string{"*"},
+		Resources: []string{"deployments", "statefulsets", "services", "components", "configurations", "subscriptions", "daprrollouts", "daprinstances", "leases", "secrets"},
+		Verbs:     []string{"watch"},
+	},
+	{
+		APIGroups: []string{"*"},
+		Resources: []string{"services", "secrets", "subscriptions", "configmaps", "configurations", "leases", "deployments", "statefulsets", "components/status", "daprrollouts/status", "daprinstances/status", "services/finalizers", "deployments/finalizers", "statefulsets/finalizers"},
+		Verbs:     []string{"update"},
+	},
+	{
+		APIGroups: []string{"*"},
+		Resources: []string{"customresourcedefinitions"},
+		Verbs:     []string{"patch"},
+	},
+	{
+		APIGroups: []string{"*"},
+		Resources: []string{"services", "leases"},
+		Verbs:     []string{"delete"},
+	},
+	{
+		APIGroups: []string{"*"},
+		Resources: []string{"deployments", "statefulsets", "services", "configmaps", "events", "leases"},
+		Verbs:     []string{"create"},
+	},
+}
+
+// operatorClusterRules are the rules of the cluster-scoped resources of the operator, the only ones granted by its
+// cluster role when the control plane watches some namespaces.
+var operatorClusterRules = []rbacv1.PolicyRule{
+	{
+		APIGroups: []string{"*"},
+		Resources: []string{"customresourcedefinitions"},
+		Verbs:     []string{"get", "patch"},
+	},
+}
+
+// sentryCertManagerRules let sentry request its issuer cert from cert-manager in its own namespace only.
+var sentryCertManagerRules = []rbacv1.PolicyRule{
+	{
+		APIGroups: []string{"cert-manager.io"},
+		Resources: []string{"certificaterequests"},
+		Verbs:     []string{"get", "create", "delete"},
+	},
+}
+
+// injectorRules let the sidecar injector get the controllers allowed to create pods in the kube-system namespace
+// when the control plane watches some namespaces.
+var injectorRules = []rbacv1.PolicyRule{
+	{
+		APIGroups: []string{""},
+		Resources: []string{"serviceaccounts"},
+		Verbs:     []string{"get"},
+	},
+}
+
+var dashboardRules = []rbacv1.PolicyRule{
+	{
+		APIGroups: []string{"", "dapr.io", "apps", "extensions"},
+		Resources: []string{"deployments", "pods", "pods/log", "components", "configurations", "namespaces"},
+		Verbs:     []string{"get", "list"},
+	},
+}
+
+var secretReaderRules = []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}}
+
+// namespacedRules returns the rules without their cluster-scoped resources, for the roles granted in the watched
+// namespaces.
+func namespacedRules(rules []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	namespaced := make([]rbacv1.PolicyRule, 0, len(rules))
+	for _, rule := range rules {
+		resources := make([]string, 0, len(rule.Resources))
+		for _, resource := range rule.Resources {
+			if resource != "customresourcedefinitions" && resource != "namespaces" {
+				resources = append(resources, resource)
+			}
+		}
+		if len(resources) > 0 {
+			rule.Resources = resources
+			namespaced = append(namespaced, rule)
+		}
+	}
+	return namespaced
+}
+
+// applyRBAC installs or updates the service accounts of the control plane and their roles. The roles are granted
+// cluster-wide, or in each watched namespace when the control plane watches some namespaces.
+func applyRBAC(ctx context.Context, c client.Client, opts Options) error {
+	for _, name := range []string{operatorServiceAccount, dashboardServiceAccount} {
+		sa := &corev1.ServiceAccount{ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: opts.Namespace}}
+		if err := apply(ctx, c, sa, opts.Version, func() {}); err != nil {
+			return err
+		}
+	}
+	operator := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: operatorServiceAccount, Namespace: opts.Namespace}
+	dashboard := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: dashboardServiceAccount, Namespace: opts.Namespace}
+	defaultServiceAccount := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "default"}
+
+	if err := applyClusterRoleBinding(ctx, c, opts, "dapr-role-tokenreview-binding", "system:auth-delegator", operator); err != nil {
+		return err
+	}
+	if err := applyRole(ctx, c, opts, opts.Namespace, sentryCertManagerRole, sentryCertManagerRole, operator, sentryCertManagerRules); err != nil {
+		return err
+	}
+
+	if len(opts.WatchNamespaces) == 0 {
+		if err := applyClusterRole(ctx, c, opts, operatorClusterRole, operatorRules); err != nil {
+			return err
+		}
+		if err := applyClusterRoleBinding(ctx, c, opts, "dapr-operator", clusterScopedName(operatorClusterRole, opts), operator); err != nil {
+			return err
+		}
+		if err := applyClusterRole(ctx, c, opts, dashboardClusterRole, dashboardRules); err != nil {
+			return err
+		}
+		if err := applyClusterRoleBinding(ctx, c, opts, "dashboard-reader-global", clusterScopedName(dashboardClusterRole, opts), dashboard); err != nil {
+			return err
+		}
+		// The default service account of the default namespace can read the secrets of its namespace.
+		return applyRole(ctx, c, opts, meta_v1.NamespaceDefault, "secret-reader", "dapr-secret-reader", defaultServiceAccount, secretReaderRules)
+	}
+
+	if err := applyClusterRole(ctx, c, opts, operatorClusterRole, operatorClusterRules); err != nil {
+		return err
+	}
+	if err := applyClusterRoleBinding(ctx, c, opts, "dapr-operator", clusterScopedName(operatorClusterRole, opts), operator); err != nil {
+		return err
+	}
+	// The role of the sidecar injector in kube-system is shared with the other control planes, so it's suffixed.
+	injector := clusterScopedName(injectorRole, opts)
+	if err := applyRole(ctx, c, opts, meta_v1.NamespaceSystem, injector, injector, operator, injectorRules); err != nil {
+		return err
+	}
+	for _, ns := range watchedNamespaces(opts) {
+		if err := applyRole(ctx, c, opts, ns, operatorClusterRole, "dapr-operator", operator, namespacedRules(operatorRules)); err != nil {
+			return err
+		}
+		if err := applyRole(ctx, c, opts, ns, dashboardClusterRole, dashboardClusterRole, dashboard, namespacedRules(dashboardRules)); err != nil {
+			return err
+		}
+		// The default service accounts of the watched namespaces can read the secrets of their namespace.
+		if ns != opts.Namespace {
+			if err := applyRole(ctx, c, opts, ns, "secret-reader", "dapr-secret-reader", defaultServiceAccount, secretReaderRules); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyClusterRole installs or updates a cluster role of the control plane.
+func applyClusterRole(ctx context.Context, c client.Client, opts Options, name string, rules []rbacv1.PolicyRule) error {
+	role := &rbacv1.ClusterRole{ObjectMeta: meta_v1.ObjectMeta{Name: clusterScopedName(name, opts)}}
+	return apply(ctx, c, role, opts.Version, func() {
+		role.Rules = rules
+	})
+}
+
+// applyClusterRoleBinding installs or updates the binding of a cluster role to a service account of the control plane.
+func applyClusterRoleBinding(ctx context.Context, c client.Client, opts Options, name, clusterRole string, subject rbacv1.Subject) error {
+	binding := &rbacv1.ClusterRoleBinding{ObjectMeta: meta_v1.ObjectMeta{Name: clusterScopedName(name, opts)}}
+	return apply(ctx, c, binding, opts.Version, func() {
+		binding.Subjects = []rbacv1.Subject{subject}
+		binding.RoleRef = rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: clusterRole}
+	})
+}
+
+// applyRole installs or updates a role in namespace and its binding to subject.
+func applyRole(ctx context.Context, c client.Client, opts Options, namespace, name, bindingName string, subject rbacv1.Subject, rules []rbacv1.PolicyRule) error {
+	role := &rbacv1.Role{ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: namespace}}
+	if err := apply(ctx, c, role, opts.Version, func() {
+		role.Rules = rules
+	}); err != nil {
+		return err
+	}
+	binding := &rbacv1.RoleBinding{ObjectMeta: meta_v1.ObjectMeta{Name: bindingName, Namespace: namespace}}
+	return apply(ctx, c, binding, opts.Version, func() {
+		binding.Subjects = []rbacv1.Subject{subject}
+		binding.RoleRef = rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: name}
+	})
+}
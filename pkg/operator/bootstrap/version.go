@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// minKubernetesVersion is the first Kubernetes version serving the v1 APIs of the CustomResourceDefinitions and of
+// the admission webhooks.
+var minKubernetesVersion = version.MustParseGeneric("v1.16.0")
+
+// minWatchNamespacesKubernetesVersion is the first Kubernetes version labeling the namespaces with their name, which
+// the webhooks select the watched namespaces with.
+var minWatchNamespacesKubernetesVersion = version.MustParseGeneric("v1.21.0")
+
+// checkKubernetesVersion checks that the Kubernetes version of the cluster is supported.
+func checkKubernetesVersion(serverVersion discovery.ServerVersionInterface, opts Options) error {
+	info, err := serverVersion.ServerVersion()
+	if err != nil {
+		return errors.Wrap(err, "error getting the Kubernetes version")
+	}
+	v, err := version.ParseGeneric(info.GitVersion)
+	if err != nil {
+		return errors.Wrapf(err, "error parsing the Kubernetes version %s", info.GitVersion)
+	}
+	if v.LessThan(minKubernetesVersion) {
+		return errors.Errorf("Kubernetes %s is not supported, the minimum version is %s", info.GitVersion, minKubernetesVersion)
+	}
+	if len(opts.WatchNamespaces) > 0 && v.LessThan(minWatchNamespacesKubernetesVersion) {
+		return errors.Errorf("watching some namespaces is not supported by Kubernetes %s, the minimum version is %s", info.GitVersion, minWatchNamespacesKubernetesVersion)
+	}
+	return nil
+}
+
+// checkInstalledVersion checks the version skew between the Dapr version which installed the
+// CustomResourceDefinitions and opts.Version.
+func checkInstalledVersion(ctx context.Context, c client.Client, crds []*apiextensionsv1.CustomResourceDefinition, opts Options) error {
+	if opts.Force {
+		return nil
+	}
+	if _, err := version.ParseSemantic(opts.Version); err != nil {
+		log.Warnf("skipping the version skew checks for the unreleased version %s", opts.Version)
+		return nil
+	}
+
+	for _, crd := range crds {
+		var installed apiextensionsv1.CustomResourceDefinition
+		if err := c.Get(ctx, types.NamespacedName{Name: crd.Name}, &installed); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return errors.Wrapf(err, "error getting CustomResourceDefinition %s", crd.Name)
+		}
+		installedVersion, ok := installed.Annotations[VersionAnnotation]
+		if !ok {
+			continue
+		}
+		if err := checkVersionSkew(installedVersion, opts.Version); err != nil {
+			return errors.Wrapf(err, "CustomResourceDefinition %s", crd.Name)
+		}
+	}
+	return nil
+}
+
+// checkVersionSkew refuses to downgrade the resources installed by a newer version, or to upgrade them by more than
+// one minor version, as the control plane is upgraded a minor version at a time. The resources installed by an
+// unreleased version can be moved to any version.
+func checkVersionSkew(installed, target string) error {
+	installedVersion, err := version.ParseSemantic(installed)
+	if err != nil {
+		return nil
+	}
+	targetVersion, err := version.ParseSemantic(target)
+	if err != nil {
+		return errors.Wrapf(err, "error parsing the version %s", target)
+	}
+
+	if targetVersion.LessThan(installedVersion) {
+		return errors.Errorf("installed by Dapr %s, can't downgrade to %s", installed, target)
+	}
+	if targetVersion.Major() != installedVersion.Major() || targetVersion.Minor() > installedVersion.Minor()+1 {
+		return errors.Errorf("installed by Dapr %s, can't upgrade to %s without going through the minor versions in between", installed, target)
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The webhooks mirror the ones of the dapr_operator and dapr_sidecar_injector charts.
+const (
+	webhookService        = "dapr-webhook"
+	webhookCertSecret     = "dapr-webhook-cert"
+	webhookCASecret       = "dapr-webhook-ca"
+	webhookCAKey          = "caBundle"
+	validationWebhook     = "dapr-operator"
+	validationWebhookPath = "/validate"
+	injectorService       = "dapr-sidecar-injector"
+	injectorCertSecret    = "dapr-sidecar-injector-cert"
+	injectorWebhook       = "dapr-sidecar-injector"
+	injectorWebhookPath   = "/mutate"
+	webhookCertTTL        = 10 * 365 * 24 * time.Hour
+	// namespaceNameLabel is the label of the name of the namespaces, set by Kubernetes.
+	namespaceNameLabel = "kubernetes.io/metadata.name"
+)
+
+// applyWebhookCerts returns the CAs of the webhooks of the operator and of the sidecar injector. The serving
+// certificates are kept if they and their CA are installed already, and generated otherwise.
+func applyWebhookCerts(ctx context.Context, c client.Client, opts Options) ([]byte, []byte, error) {
+	var secret corev1.Secret
+	err := c.Get(ctx, types.NamespacedName{Namespace: opts.Namespace, Name: webhookCASecret}, &secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, nil, errors.Wrapf(err, "error getting secret %s", webhookCASecret)
+	}
+	webhookCA, err := applyServingCert(ctx, c, webhookService, webhookCertSecret, secret.Data[webhookCAKey], opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	caSecret := &corev1.Secret{ObjectMeta: meta_v1.ObjectMeta{Name: webhookCASecret, Namespace: opts.Namespace}}
+	if err = apply(ctx, c, caSecret, opts.Version, func() {
+		caSecret.Data = map[string][]byte{webhookCAKey: webhookCA}
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	// The CA of the sidecar injector is only kept in its webhook.
+	var injectorCA []byte
+	var webhook admissionregistrationv1.MutatingWebhookConfiguration
+	err = c.Get(ctx, types.NamespacedName{Name: clusterScopedName(injectorWebhook, opts)}, &webhook)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, nil, errors.Wrapf(err, "error getting MutatingWebhookConfiguration %s", clusterScopedName(injectorWebhook, opts))
+	}
+	if len(webhook.Webhooks) > 0 {
+		injectorCA = webhook.Webhooks[0].ClientConfig.CABundle
+	}
+	injectorCA, err = applyServingCert(ctx, c, injectorService, injectorCertSecret, injectorCA, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return webhookCA, injectorCA, nil
+}
+
+// applyServingCert installs a serving certificate for service in secretName and returns its CA. The installed
+// certificate is kept if its CA is known.
+func applyServingCert(ctx context.Context, c client.Client, service, secretName string, ca []byte, opts Options) ([]byte, error) {
+	var existing corev1.Secret
+	err := c.Get(ctx, types.NamespacedName{Namespace: opts.Namespace, Name: secretName}, &existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, errors.Wrapf(err, "error getting secret %s", secretName)
+	}
+	if err == nil && len(ca) > 0 && len(existing.Data[corev1.TLSCertKey]) > 0 && len(existing.Data[corev1.TLSPrivateKeyKey]) > 0 {
+		return ca, nil
+	}
+
+	ca, cert, key, err := generateServingCert(service, opts.Namespace)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error generating the certificate of %s", service)
+	}
+	secret := &corev1.Secret{ObjectMeta: meta_v1.ObjectMeta{Name: secretName, Namespace: opts.Namespace}}
+	if err = apply(ctx, c, secret, opts.Version, func() {
+		secret.Data = map[string][]byte{
+			corev1.TLSCertKey:       cert,
+			corev1.TLSPrivateKeyKey: key,
+		}
+	}); err != nil {
+		return nil, err
+	}
+	return ca, nil
+}
+
+// generateServingCert generates a CA and a certificate signed by the CA for the names of service in namespace.
+// They are PEM encoded.
+func generateServingCert(service, namespace string) ([]byte, []byte, []byte, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	caTemplate, err := newCertTemplate(service + "-ca")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	caTemplate.IsCA = true
+	caTemplate.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	caTemplate.BasicConstraintsValid = true
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	template, err := newCertTemplate(service)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	template.DNSNames = []string{
+		fmt.Sprintf("%s.%s", service, namespace),
+		fmt.Sprintf("%s.%s.svc", service, namespace),
+		fmt.Sprintf("%s.%s.svc.cluster", service, namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", service, namespace),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, caTemplate, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return caPEM, certPEM, keyPEM, nil
+}
+
+func newCertTemplate(cn string) (*x509.Certificate, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(webhookCertTTL),
+	}, nil
+}
+
+// applyWebhooks installs or updates the sidecar injector webhook and, if enabled, the validating webhook of the Dapr
+// resources.
+func applyWebhooks(ctx context.Context, c client.Client, webhookCA, injectorCA []byte, opts Options) error {
+	sideEffects := admissionregistrationv1.SideEffectClassNone
+	injectorPath := injectorWebhookPath
+	injectorFailurePolicy := opts.InjectorFailurePolicy
+	namespaceSelector := webhookNamespaceSelector(opts)
+	mutating := &admissionregistrationv1.MutatingWebhookConfiguration{ObjectMeta: meta_v1.ObjectMeta{Name: clusterScopedName(injectorWebhook, opts)}}
+	if err := apply(ctx, c, mutating, opts.Version, func() {
+		mutating.Webhooks = []admissionregistrationv1.MutatingWebhook{{
+			Name: "sidecar-injector.dapr.io",
+			ClientConfig: admissionregistrationv1.WebhookClientConfig{
+				Service: &admissionregistrationv1.ServiceReference{
+					Namespace: opts.Namespace,
+					Name:      injectorService,
+					Path:      &injectorPath,
+				},
+				CABundle: injectorCA,
+			},
+			Rules: []admissionregistrationv1.RuleWithOperations{{
+				Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+				Rule: admissionregistrationv1.Rule{
+					APIGroups:   []string{""},
+					APIVersions: []string{"v1"},
+					Resources:   []string{"pods"},
+				},
+			}},
+			NamespaceSelector:       namespaceSelector,
+			FailurePolicy:           &injectorFailurePolicy,
+			SideEffects:             &sideEffects,
+			AdmissionReviewVersions: []string{"v1", "v1beta1"},
+		}}
+	}); err != nil {
+		return err
+	}
+
+	if !opts.ValidationWebhook {
+		return nil
+	}
+	validationPath := validationWebhookPath
+	validationFailurePolicy := opts.ValidationFailurePolicy
+	timeoutSeconds := int32(10)
+	validating := &admissionregistrationv1.ValidatingWebhookConfiguration{ObjectMeta: meta_v1.ObjectMeta{Name: clusterScopedName(validationWebhook, opts)}}
+	return apply(ctx, c, validating, opts.Version, func() {
+		validating.Webhooks = []admissionregistrationv1.ValidatingWebhook{{
+			Name: "validation.dapr.io",
+			ClientConfig: admissionregistrationv1.WebhookClientConfig{
+				Service: &admissionregistrationv1.ServiceReference{
+					Namespace: opts.Namespace,
+					Name:      webhookService,
+					Path:      &validationPath,
+				},
+				CABundle: webhookCA,
+			},
+			Rules: []admissionregistrationv1.RuleWithOperations{{
+				Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+				Rule: admissionregistrationv1.Rule{
+					APIGroups:   []string{"dapr.io"},
+					APIVersions: []string{"*"},
+					Resources:   []string{"components", "configurations", "subscriptions"},
+				},
+			}},
+			NamespaceSelector:       namespaceSelector,
+			FailurePolicy:           &validationFailurePolicy,
+			SideEffects:             &sideEffects,
+			AdmissionReviewVersions: []string{"v1"},
+			TimeoutSeconds:          &timeoutSeconds,
+		}}
+	})
+}
+
+// webhookNamespaceSelector returns the selector of the namespaces whose resources are admitted by the webhooks, i.e.
+// the watched namespaces, or nil when the control plane watches all the namespaces.
+func webhookNamespaceSelector(opts Options) *meta_v1.LabelSelector {
+	if len(opts.WatchNamespaces) == 0 {
+		return nil
+	}
+	return &meta_v1.LabelSelector{
+		MatchExpressions: []meta_v1.LabelSelectorRequirement{{
+			Key:      namespaceNameLabel,
+			Operator: meta_v1.LabelSelectorOpIn,
+			Values:   watchedNamespaces(opts),
+		}},
+	}
+}
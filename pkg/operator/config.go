@@ -2,12 +2,16 @@ package operator
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 
+	"github.com/pkg/errors"
+
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/dapr/dapr/pkg/apis/configuration/v1alpha1"
+	dapr_config "github.com/dapr/dapr/pkg/config"
 	"github.com/dapr/dapr/pkg/credentials"
 )
 
@@ -15,6 +19,7 @@ import (
 type Config struct {
 	MTLSEnabled bool
 	Credentials credentials.TLSCredentials
+	AuditSpec   dapr_config.AuditSpec
 }
 
 // LoadConfiguration loads the Kubernetes configuration and returns an Operator Config.
@@ -27,7 +32,19 @@ func LoadConfiguration(name string, client client.Client) (*Config, error) {
 	if err := client.Get(context.Background(), key, &conf); err != nil {
 		return nil, err
 	}
+
+	// The audit spec is converted to the spec of the runtime configuration, shared by the audit subsystem.
+	var auditSpec dapr_config.AuditSpec
+	b, err := json.Marshal(conf.Spec.AuditSpec)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, &auditSpec); err != nil {
+		return nil, errors.Wrap(err, "error parsing audit spec")
+	}
+
 	return &Config{
 		MTLSEnabled: conf.Spec.MTLSSpec.Enabled,
+		AuditSpec:   auditSpec,
 	}, nil
 }
@@ -0,0 +1,333 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	configurationapi "github.com/dapr/dapr/pkg/apis/configuration/v1alpha1"
+	instancesapi "github.com/dapr/dapr/pkg/apis/instances/v1alpha1"
+)
+
+const (
+	defaultHAReplicas = 3
+	logLevelArg       = "--log-level"
+	trustDomainArg    = "--trust-domain"
+)
+
+// controlPlaneService is a control plane service managed by the instances. The workload and its container share
+// the name of the service.
+type controlPlaneService struct {
+	name     string
+	workload string
+	// statefulSet is true for the services running as a stateful set rather than a deployment.
+	statefulSet bool
+	// scalable is false for the services whose replicas are configured by the chart, e.g. the raft cluster of the
+	// placement service.
+	scalable bool
+}
+
+var controlPlaneServices = []controlPlaneService{
+	{name: instancesapi.ServiceOperator, workload: "dapr-operator", scalable: true},
+	{name: instancesapi.ServicePlacement, workload: "dapr-placement-server", statefulSet: true},
+	{name: instancesapi.ServiceSentry, workload: "dapr-sentry", scalable: true},
+	{name: instancesapi.ServiceSidecarInjector, workload: "dapr-sidecar-injector", scalable: true},
+}
+
+var logLevels = []string{"debug", "info", "warn", "error", "fatal"}
+
+// InstanceHandler applies the settings of the DaprInstances to the control plane services of their namespace and to
+// the Dapr system configuration.
+type InstanceHandler struct {
+	*DaprHandler
+
+	configName string
+}
+
+// NewInstanceHandler returns a new instance handler managing the Dapr system configuration of the given name.
+func NewInstanceHandler(mgr ctrl.Manager, configName string) *InstanceHandler {
+	return &InstanceHandler{
+		DaprHandler: NewDaprHandler(mgr),
+		configName:  configName,
+	}
+}
+
+// Init registers the instance controller. The instances are reconciled when the workloads of the control plane
+// services change too, so the manual changes of the managed settings are reverted.
+// The workloads are installed by the chart rather than owned by the instances, which would garbage collect them
+// once deleted, so they're mapped to the instances of their namespace.
+func (h *InstanceHandler) Init() error {
+	return ctrl.NewControllerManagedBy(h.mgr).
+		For(&instancesapi.DaprInstance{}).
+		Watches(&source.Kind{Type: &appsv1.Deployment{}}, handler.EnqueueRequestsFromMapFunc(h.getWorkloadInstances)).
+		Watches(&source.Kind{Type: &appsv1.StatefulSet{}}, handler.EnqueueRequestsFromMapFunc(h.getWorkloadInstances)).
+		Complete(h)
+}
+
+// getWorkloadInstances returns the requests of the instances of the namespace of a control plane workload.
+func (h *InstanceHandler) getWorkloadInstances(obj client.Object) []reconcile.Request {
+	if !isControlPlaneWorkload(obj) {
+		return nil
+	}
+
+	var list instancesapi.DaprInstanceList
+	if err := h.List(context.TODO(), &list, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Errorf("unable to list the instances of namespace %s, err: %s", obj.GetNamespace(), err)
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for _, instance := range list.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name},
+		})
+	}
+	return requests
+}
+
+// isControlPlaneWorkload returns true if obj is the workload of a control plane service.
+func isControlPlaneWorkload(obj client.Object) bool {
+	_, statefulSet := obj.(*appsv1.StatefulSet)
+	for _, service := range controlPlaneServices {
+		if service.workload == obj.GetName() && service.statefulSet == statefulSet {
+			return true
+		}
+	}
+	return false
+}
+
+// Reconcile applies the settings of an instance to the control plane. Manual changes of the managed settings are
+// reverted on the next reconciliation of the instance.
+func (h *InstanceHandler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var instance instancesapi.DaprInstance
+	if err := h.Get(ctx, req.NamespacedName, &instance); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Debugf("instance has been deleted, %s", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if err := validateInstanceSpec(&instance.Spec); err != nil {
+		return ctrl.Result{}, h.updateInstanceStatus(ctx, &instance, instancesapi.InstanceFailed, err.Error())
+	}
+
+	if instance.Spec.MTLSEnabled != nil {
+		if err := h.setMTLSEnabled(ctx, instance.Namespace, *instance.Spec.MTLSEnabled); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, service := range controlPlaneServices {
+		replicas, args := getServiceSettings(&instance.Spec, service)
+		if err := h.applyServiceSettings(ctx, instance.Namespace, service, replicas, args); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, h.updateInstanceStatus(ctx, &instance, instancesapi.InstanceReconciled, "")
+}
+
+// setMTLSEnabled enables or disables mTLS in the Dapr system configuration.
+func (h *InstanceHandler) setMTLSEnabled(ctx context.Context, namespace string, enabled bool) error {
+	var conf configurationapi.Configuration
+	if err := h.Get(ctx, types.NamespacedName{Namespace: namespace, Name: h.configName}, &conf); err != nil {
+		log.Errorf("unable to get the Dapr system configuration %s/%s, err: %s", namespace, h.configName, err)
+		return err
+	}
+	if conf.Spec.MTLSSpec.Enabled == enabled {
+		return nil
+	}
+
+	conf.Spec.MTLSSpec.Enabled = enabled
+	if err := h.Update(ctx, &conf); err != nil {
+		log.Errorf("unable to update the Dapr system configuration %s/%s, err: %s", namespace, h.configName, err)
+		return err
+	}
+	log.Infof("mTLS enabled set to %t in the Dapr system configuration %s/%s", enabled, namespace, h.configName)
+	return nil
+}
+
+// applyServiceSettings updates the replicas and the arguments of the workload of a control plane service.
+// Services which aren't deployed are skipped.
+func (h *InstanceHandler) applyServiceSettings(ctx context.Context, namespace string, service controlPlaneService, replicas *int32, args map[string]string) error {
+	var (
+		obj             client.Object
+		currentReplicas **int32
+		podSpec         *corev1.PodSpec
+	)
+	if service.statefulSet {
+		s := &appsv1.StatefulSet{}
+		obj, currentReplicas, podSpec = s, &s.Spec.Replicas, &s.Spec.Template.Spec
+	} else {
+		d := &appsv1.Deployment{}
+		obj, currentReplicas, podSpec = d, &d.Spec.Replicas, &d.Spec.Template.Spec
+	}
+
+	if err := h.Get(ctx, types.NamespacedName{Namespace: namespace, Name: service.workload}, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Debugf("control plane service %s not found in namespace %s", service.workload, namespace)
+			return nil
+		}
+		return err
+	}
+
+	changed := false
+	if replicas != nil && (*currentReplicas == nil || **currentReplicas != *replicas) {
+		*currentReplicas = replicas
+		changed = true
+	}
+	for i := range podSpec.Containers {
+		c := &podSpec.Containers[i]
+		if c.Name != service.workload {
+			continue
+		}
+		for name, value := range args {
+			var argChanged bool
+			c.Args, argChanged = setContainerArg(c.Args, name, value)
+			changed = changed || argChanged
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := h.Update(ctx, obj); err != nil {
+		log.Errorf("unable to update control plane service %s/%s, err: %s", namespace, service.workload, err)
+		return err
+	}
+	log.Infof("updated control plane service %s/%s", namespace, service.workload)
+	return nil
+}
+
+func (h *InstanceHandler) updateInstanceStatus(ctx context.Context, instance *instancesapi.DaprInstance, phase, message string) error {
+	instance.Status.ObservedGeneration = instance.Generation
+	instance.Status.Phase = phase
+	instance.Status.Message = message
+	return h.Status().Update(ctx, instance)
+}
+
+// getServiceSettings returns the replicas and the arguments of a control plane service declared by an instance.
+// A nil replicas leaves the replicas of the service unchanged.
+func getServiceSettings(spec *instancesapi.DaprInstanceSpec, service controlPlaneService) (*int32, map[string]string) {
+	var replicas *int32
+	args := map[string]string{}
+
+	if spec.HA != nil && service.scalable {
+		r := int32(1)
+		if spec.HA.Enabled {
+			r = defaultHAReplicas
+			if spec.HA.Replicas > 0 {
+				r = spec.HA.Replicas
+			}
+		}
+		replicas = &r
+	}
+	if spec.LogLevel != "" {
+		args[logLevelArg] = spec.LogLevel
+	}
+	if service.name == instancesapi.ServiceSentry && spec.TrustDomain != "" {
+		args[trustDomainArg] = spec.TrustDomain
+	}
+
+	if override, ok := spec.Services[service.name]; ok {
+		if override.Replicas != nil {
+			r := *override.Replicas
+			replicas = &r
+		}
+		if override.LogLevel != "" {
+			args[logLevelArg] = override.LogLevel
+		}
+	}
+	return replicas, args
+}
+
+// setContainerArg sets the value of a flag in the arguments of a container, given either as "--flag value" or
+// "--flag=value". The flag is appended if it isn't set. It returns whether the arguments changed.
+func setContainerArg(args []string, name, value string) ([]string, bool) {
+	for i, arg := range args {
+		if arg == name && i+1 < len(args) {
+			if args[i+1] == value {
+				return args, false
+			}
+			args[i+1] = value
+			return args, true
+		}
+		if strings.HasPrefix(arg, name+"=") {
+			if arg == name+"="+value {
+				return args, false
+			}
+			args[i] = name + "=" + value
+			return args, true
+		}
+	}
+	return append(args, name, value), true
+}
+
+// validateInstanceSpec validates the spec of an instance.
+func validateInstanceSpec(spec *instancesapi.DaprInstanceSpec) error {
+	if spec.HA != nil && spec.HA.Replicas < 0 {
+		return errors.Errorf("ha.replicas must not be negative, got %d", spec.HA.Replicas)
+	}
+	if err := validateLogLevel("logLevel", spec.LogLevel); err != nil {
+		return err
+	}
+
+	for name, override := range spec.Services {
+		var service *controlPlaneService
+		for i := range controlPlaneServices {
+			if controlPlaneServices[i].name == name {
+				service = &controlPlaneServices[i]
+			}
+		}
+		if service == nil {
+			return errors.Errorf("unknown control plane service %q", name)
+		}
+		if override.Replicas != nil {
+			if !service.scalable {
+				return errors.Errorf("the replicas of the %s service are configured by the chart", name)
+			}
+			if *override.Replicas < 0 {
+				return errors.Errorf("services.%s.replicas must not be negative, got %d", name, *override.Replicas)
+			}
+		}
+		if err := validateLogLevel("services."+name+".logLevel", override.LogLevel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateLogLevel(field, level string) error {
+	if level == "" {
+		return nil
+	}
+	for _, l := range logLevels {
+		if level == l {
+			return nil
+		}
+	}
+	return errors.Errorf("%s must be one of %s, got %q", field, strings.Join(logLevels, ", "), level)
+}
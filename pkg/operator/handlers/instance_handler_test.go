@@ -0,0 +1,172 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	configurationapi "github.com/dapr/dapr/pkg/apis/configuration/v1alpha1"
+	instancesapi "github.com/dapr/dapr/pkg/apis/instances/v1alpha1"
+)
+
+func controlPlanePodSpec(name string, args ...string) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: name, Args: args}},
+		},
+	}
+}
+
+func newTestInstanceHandler(t *testing.T, instance *instancesapi.DaprInstance) *InstanceHandler {
+	s := runtime.NewScheme()
+	require.NoError(t, scheme.AddToScheme(s))
+	require.NoError(t, configurationapi.AddToScheme(s))
+	require.NoError(t, instancesapi.AddToScheme(s))
+
+	one := int32(1)
+	objects := []client.Object{
+		instance,
+		&configurationapi.Configuration{ObjectMeta: meta_v1.ObjectMeta{Name: "daprsystem", Namespace: "dapr-system"}},
+		&appsv1.Deployment{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "dapr-operator", Namespace: "dapr-system"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &one, Template: controlPlanePodSpec("dapr-operator", "--log-level", "info")},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "dapr-sentry", Namespace: "dapr-system"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &one, Template: controlPlanePodSpec("dapr-sentry", "--log-level", "info", "--trust-domain", "cluster.local")},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "dapr-placement-server", Namespace: "dapr-system"},
+			Spec:       appsv1.StatefulSetSpec{Replicas: &one, Template: controlPlanePodSpec("dapr-placement-server", "--log-level=info")},
+		},
+	}
+	return &InstanceHandler{
+		DaprHandler: &DaprHandler{
+			Client: fake.NewClientBuilder().WithScheme(s).WithObjects(objects...).Build(),
+			Scheme: s,
+		},
+		configName: "daprsystem",
+	}
+}
+
+func TestInstanceReconcile(t *testing.T) {
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "dapr-system", Name: "dapr"}}
+	newInstance := func(spec instancesapi.DaprInstanceSpec) *instancesapi.DaprInstance {
+		return &instancesapi.DaprInstance{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "dapr", Namespace: "dapr-system"},
+			Spec:       spec,
+		}
+	}
+	getDeployment := func(t *testing.T, h *InstanceHandler, name string) appsv1.Deployment {
+		var d appsv1.Deployment
+		require.NoError(t, h.Get(context.Background(), types.NamespacedName{Namespace: "dapr-system", Name: name}, &d))
+		return d
+	}
+	getInstance := func(t *testing.T, h *InstanceHandler) instancesapi.DaprInstance {
+		var instance instancesapi.DaprInstance
+		require.NoError(t, h.Get(context.Background(), req.NamespacedName, &instance))
+		return instance
+	}
+
+	t.Run("applies the settings to the control plane", func(t *testing.T) {
+		enabled := true
+		two := int32(2)
+		h := newTestInstanceHandler(t, newInstance(instancesapi.DaprInstanceSpec{
+			MTLSEnabled: &enabled,
+			TrustDomain: "example.com",
+			HA:          &instancesapi.HASpec{Enabled: true},
+			LogLevel:    "debug",
+			Services: map[string]instancesapi.ControlPlaneServiceSpec{
+				instancesapi.ServiceSentry: {Replicas: &two, LogLevel: "warn"},
+			},
+		}))
+
+		_, err := h.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+
+		var conf configurationapi.Configuration
+		require.NoError(t, h.Get(context.Background(), types.NamespacedName{Namespace: "dapr-system", Name: "daprsystem"}, &conf))
+		assert.True(t, conf.Spec.MTLSSpec.Enabled)
+
+		operator := getDeployment(t, h, "dapr-operator")
+		assert.Equal(t, int32(3), *operator.Spec.Replicas)
+		assert.Equal(t, []string{"--log-level", "debug"}, operator.Spec.Template.Spec.Containers[0].Args)
+
+		sentry := getDeployment(t, h, "dapr-sentry")
+		assert.Equal(t, int32(2), *sentry.Spec.Replicas)
+		assert.Equal(t, []string{"--log-level", "warn", "--trust-domain", "example.com"}, sentry.Spec.Template.Spec.Containers[0].Args)
+
+		var placement appsv1.StatefulSet
+		require.NoError(t, h.Get(context.Background(), types.NamespacedName{Namespace: "dapr-system", Name: "dapr-placement-server"}, &placement))
+		assert.Equal(t, int32(1), *placement.Spec.Replicas)
+		assert.Equal(t, []string{"--log-level=debug"}, placement.Spec.Template.Spec.Containers[0].Args)
+
+		instance := getInstance(t, h)
+		assert.Equal(t, instancesapi.InstanceReconciled, instance.Status.Phase)
+	})
+
+	t.Run("invalid spec", func(t *testing.T) {
+		three := int32(3)
+		h := newTestInstanceHandler(t, newInstance(instancesapi.DaprInstanceSpec{
+			Services: map[string]instancesapi.ControlPlaneServiceSpec{
+				instancesapi.ServicePlacement: {Replicas: &three},
+			},
+		}))
+
+		_, err := h.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		instance := getInstance(t, h)
+		assert.Equal(t, instancesapi.InstanceFailed, instance.Status.Phase)
+		assert.Contains(t, instance.Status.Message, "configured by the chart")
+		assert.Equal(t, int32(1), *getDeployment(t, h, "dapr-operator").Spec.Replicas)
+	})
+}
+
+func TestGetWorkloadInstances(t *testing.T) {
+	h := newTestInstanceHandler(t, &instancesapi.DaprInstance{ObjectMeta: meta_v1.ObjectMeta{Name: "dapr", Namespace: "dapr-system"}})
+	expected := []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "dapr-system", Name: "dapr"}}}
+
+	assert.Equal(t, expected, h.getWorkloadInstances(&appsv1.Deployment{ObjectMeta: meta_v1.ObjectMeta{Name: "dapr-operator", Namespace: "dapr-system"}}))
+	assert.Equal(t, expected, h.getWorkloadInstances(&appsv1.StatefulSet{ObjectMeta: meta_v1.ObjectMeta{Name: "dapr-placement-server", Namespace: "dapr-system"}}))
+	assert.Empty(t, h.getWorkloadInstances(&appsv1.Deployment{ObjectMeta: meta_v1.ObjectMeta{Name: "dapr-operator", Namespace: "default"}}))
+	assert.Empty(t, h.getWorkloadInstances(&appsv1.Deployment{ObjectMeta: meta_v1.ObjectMeta{Name: "app", Namespace: "dapr-system"}}))
+}
+
+func TestSetContainerArg(t *testing.T) {
+	args, changed := setContainerArg([]string{"--log-level", "info"}, "--log-level", "info")
+	assert.False(t, changed)
+	assert.Equal(t, []string{"--log-level", "info"}, args)
+
+	args, changed = setContainerArg([]string{"--log-level=info"}, "--log-level", "debug")
+	assert.True(t, changed)
+	assert.Equal(t, []string{"--log-level=debug"}, args)
+
+	args, changed = setContainerArg([]string{"--enable-metrics"}, "--trust-domain", "example.com")
+	assert.True(t, changed)
+	assert.Equal(t, []string{"--enable-metrics", "--trust-domain", "example.com"}, args)
+}
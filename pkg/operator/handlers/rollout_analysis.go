@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	rolloutsapi "github.com/dapr/dapr/pkg/apis/rollouts/v1alpha1"
+)
+
+const (
+	// appIDsPlaceholder is replaced in the analysis queries by the regular expression matching the updated app IDs.
+	appIDsPlaceholder = "$APP_IDS"
+	// defaultErrorRateQuery is the rate of the 5xx responses of the HTTP API of the sidecars.
+	defaultErrorRateQuery = `sum(rate(dapr_http_server_response_count{app_id=~"$APP_IDS",status=~"5.."}[5m])) / ` +
+		`sum(rate(dapr_http_server_response_count{app_id=~"$APP_IDS"}[5m]))`
+	prometheusQueryTimeout = 10 * time.Second
+)
+
+// prometheusQueryResponse is the response of the instant queries of the Prometheus HTTP API.
+type prometheusQueryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Value []interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// queryErrorRate queries the error rate of the app IDs from the Prometheus server of the analysis.
+// The error rate of apps without traffic is 0.
+func queryErrorRate(ctx context.Context, analysis *rolloutsapi.RolloutAnalysis, appIDs []string) (float64, error) {
+	if len(appIDs) == 0 {
+		return 0, nil
+	}
+
+	quoted := make([]string, len(appIDs))
+	for i, appID := range appIDs {
+		quoted[i] = regexp.QuoteMeta(appID)
+	}
+	query := analysis.Query
+	if query == "" {
+		query = defaultErrorRateQuery
+	}
+	query = strings.ReplaceAll(query, appIDsPlaceholder, strings.Join(quoted, "|"))
+
+	ctx, cancel := context.WithTimeout(ctx, prometheusQueryTimeout)
+	defer cancel()
+
+	u := strings.TrimSuffix(analysis.PrometheusAddress, "/") + "/api/v1/query?" + url.Values{"query": {query}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var result prometheusQueryResponse
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, errors.Wrap(err, "error decoding Prometheus response")
+	}
+	if result.Status != "success" {
+		return 0, errors.Errorf("Prometheus query failed: %s", result.Error)
+	}
+	if result.Data.ResultType != "vector" {
+		return 0, errors.Errorf("Prometheus query returned a %s, expected a vector", result.Data.ResultType)
+	}
+	if len(result.Data.Result) == 0 || len(result.Data.Result[0].Value) != 2 {
+		return 0, nil
+	}
+
+	value, _ := result.Data.Result[0].Value[1].(string)
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid error rate %q", value)
+	}
+	return rate, nil
+}
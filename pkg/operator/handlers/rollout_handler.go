@@ -0,0 +1,289 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rolloutsapi "github.com/dapr/dapr/pkg/apis/rollouts/v1alpha1"
+)
+
+const (
+	daprSidecarImageKey        = "dapr.io/sidecar-image"
+	defaultRolloutStepInterval = 10 * time.Minute
+)
+
+// RolloutHandler rolls out the sidecar images of the DaprRollouts to the Dapr enabled deployments of their
+// namespaces, step by step. The deployments are updated by setting the sidecar image annotation of their pod
+// template, which is applied by the injector to the pods rolled out by the deployments.
+type RolloutHandler struct {
+	*DaprHandler
+
+	now       func() time.Time
+	errorRate func(ctx context.Context, analysis *rolloutsapi.RolloutAnalysis, appIDs []string) (float64, error)
+}
+
+// NewRolloutHandler returns a new rollout handler.
+func NewRolloutHandler(mgr ctrl.Manager) *RolloutHandler {
+	return &RolloutHandler{
+		DaprHandler: NewDaprHandler(mgr),
+		now:         time.Now,
+		errorRate:   queryErrorRate,
+	}
+}
+
+// Init registers the rollout controller.
+func (h *RolloutHandler) Init() error {
+	return ctrl.NewControllerManagedBy(h.mgr).
+		For(&rolloutsapi.DaprRollout{}).
+		Complete(h)
+}
+
+// Reconcile advances a rollout to its next step once the current step was observed for the step interval, or rolls
+// it back when the error rate of the updated deployments exceeds the threshold of its analysis.
+func (h *RolloutHandler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var rollout rolloutsapi.DaprRollout
+	if err := h.Get(ctx, req.NamespacedName, &rollout); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Debugf("rollout has been deleted, %s", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	status := &rollout.Status
+	switch status.Phase {
+	case rolloutsapi.RolloutCompleted, rolloutsapi.RolloutRolledBack, rolloutsapi.RolloutFailed:
+		return ctrl.Result{}, nil
+	}
+
+	interval, err := validateRolloutSpec(&rollout.Spec)
+	if err != nil {
+		return ctrl.Result{}, h.updateRolloutStatus(ctx, &rollout, rolloutsapi.RolloutFailed, err.Error())
+	}
+
+	if rollout.Spec.Rollback {
+		return ctrl.Result{}, h.rollback(ctx, &rollout, "rolled back by the spec")
+	}
+	if rollout.Spec.Paused {
+		if status.Phase == rolloutsapi.RolloutPaused {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, h.updateRolloutStatus(ctx, &rollout, rolloutsapi.RolloutPaused, status.Message)
+	}
+
+	deployments, err := h.getRolloutDeployments(ctx, &rollout)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if status.LastStepTime != nil {
+		if wait := status.LastStepTime.Add(interval).Sub(h.now()); wait > 0 {
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+		if analysis := rollout.Spec.Analysis; analysis != nil {
+			maxErrorRate, _ := strconv.ParseFloat(analysis.MaxErrorRate, 64)
+			rate, err := h.errorRate(ctx, analysis, h.getUpdatedAppIDs(&rollout, deployments))
+			if err != nil {
+				log.Warnf("unable to get the error rate of rollout %s, err: %s", req.NamespacedName, err)
+				return ctrl.Result{}, err
+			}
+			if rate > maxErrorRate {
+				return ctrl.Result{}, h.rollback(ctx, &rollout, fmt.Sprintf("error rate %g exceeded %g at step %d", rate, maxErrorRate, status.Step))
+			}
+		}
+	}
+
+	if int(status.Step) == len(rollout.Spec.Steps) {
+		return ctrl.Result{}, h.updateRolloutStatus(ctx, &rollout, rolloutsapi.RolloutCompleted, status.Message)
+	}
+
+	// the deployments are updated in the order of their names, so the steps are stable across reconciliations.
+	target := (len(deployments)*int(rollout.Spec.Steps[status.Step]) + 99) / 100
+	if status.PreviousImages == nil {
+		status.PreviousImages = map[string]string{}
+	}
+	updated := 0
+	recorded := false
+	var pending []*appsv1.Deployment
+	for i := range deployments {
+		d := &deployments[i]
+		if _, ok := status.PreviousImages[d.Name]; ok {
+			// the deployment may have been recorded by a reconciliation which failed before updating it.
+			if d.Spec.Template.Annotations[daprSidecarImageKey] != rollout.Spec.SidecarImage {
+				pending = append(pending, d)
+			}
+			updated++
+			continue
+		}
+		if updated >= target {
+			continue
+		}
+		status.PreviousImages[d.Name] = d.Spec.Template.Annotations[daprSidecarImageKey]
+		recorded = true
+		pending = append(pending, d)
+		updated++
+	}
+
+	// the previous images are persisted before the deployments are updated, so a rollback reverts all the updated
+	// deployments even when the update of the status would fail afterwards.
+	if recorded {
+		if err = h.Status().Update(ctx, &rollout); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	for _, d := range pending {
+		if err = h.setSidecarImage(ctx, d, rollout.Spec.SidecarImage); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Infof("rollout %s updated the sidecar image of deployment %s to %s", req.NamespacedName, d.Name, rollout.Spec.SidecarImage)
+	}
+
+	// the time of the step is stored with the precision of the status, seconds, so the interval observed from the
+	// stored time doesn't drift from the interval observed from this time.
+	stepTime := h.now().Truncate(time.Second)
+	status.Step++
+	status.LastStepTime = &meta_v1.Time{Time: stepTime}
+	message := fmt.Sprintf("%d of %d deployments updated", updated, len(deployments))
+	if err = h.updateRolloutStatus(ctx, &rollout, rolloutsapi.RolloutProgressing, message); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: stepTime.Add(interval).Sub(h.now())}, nil
+}
+
+// getRolloutDeployments returns the Dapr enabled deployments selected by a rollout, sorted by name.
+func (h *RolloutHandler) getRolloutDeployments(ctx context.Context, rollout *rolloutsapi.DaprRollout) ([]appsv1.Deployment, error) {
+	selector := labels.Everything()
+	if rollout.Spec.Selector != nil {
+		var err error
+		if selector, err = meta_v1.LabelSelectorAsSelector(rollout.Spec.Selector); err != nil {
+			return nil, err
+		}
+	}
+
+	var list appsv1.DeploymentList
+	if err := h.List(ctx, &list, client.InNamespace(rollout.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	deployments := make([]appsv1.Deployment, 0, len(list.Items))
+	for _, d := range list.Items {
+		if h.isAnnotatedForDapr(&DeploymentWrapper{Deployment: d}) {
+			deployments = append(deployments, d)
+		}
+	}
+	sort.Slice(deployments, func(i, j int) bool {
+		return deployments[i].Name < deployments[j].Name
+	})
+	return deployments, nil
+}
+
+// getUpdatedAppIDs returns the app IDs of the deployments updated by a rollout.
+func (h *RolloutHandler) getUpdatedAppIDs(rollout *rolloutsapi.DaprRollout, deployments []appsv1.Deployment) []string {
+	var appIDs []string
+	for _, d := range deployments {
+		if _, ok := rollout.Status.PreviousImages[d.Name]; ok {
+			if appID := h.getAppID(&DeploymentWrapper{Deployment: d}); appID != "" {
+				appIDs = append(appIDs, appID)
+			}
+		}
+	}
+	return appIDs
+}
+
+// rollback reverts the deployments updated by a rollout to their previous sidecar image.
+func (h *RolloutHandler) rollback(ctx context.Context, rollout *rolloutsapi.DaprRollout, reason string) error {
+	for name, image := range rollout.Status.PreviousImages {
+		var d appsv1.Deployment
+		if err := h.Get(ctx, types.NamespacedName{Namespace: rollout.Namespace, Name: name}, &d); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if err := h.setSidecarImage(ctx, &d, image); err != nil {
+			return err
+		}
+	}
+	log.Warnf("rollout %s/%s %s", rollout.Namespace, rollout.Name, reason)
+	return h.updateRolloutStatus(ctx, rollout, rolloutsapi.RolloutRolledBack, reason)
+}
+
+// setSidecarImage sets the sidecar image annotation of the pod template of a deployment, or removes it if the image
+// is empty so the pods get the default image of the injector.
+func (h *RolloutHandler) setSidecarImage(ctx context.Context, d *appsv1.Deployment, image string) error {
+	if image == "" {
+		delete(d.Spec.Template.Annotations, daprSidecarImageKey)
+	} else {
+		if d.Spec.Template.Annotations == nil {
+			d.Spec.Template.Annotations = map[string]string{}
+		}
+		d.Spec.Template.Annotations[daprSidecarImageKey] = image
+	}
+	if err := h.Update(ctx, d); err != nil {
+		log.Errorf("unable to update the sidecar image of deployment %s/%s, err: %s", d.Namespace, d.Name, err)
+		return err
+	}
+	return nil
+}
+
+func (h *RolloutHandler) updateRolloutStatus(ctx context.Context, rollout *rolloutsapi.DaprRollout, phase, message string) error {
+	rollout.Status.Phase = phase
+	rollout.Status.Message = message
+	return h.Status().Update(ctx, rollout)
+}
+
+// validateRolloutSpec validates the spec of a rollout and returns its step interval.
+func validateRolloutSpec(spec *rolloutsapi.DaprRolloutSpec) (time.Duration, error) {
+	if spec.SidecarImage == "" {
+		return 0, errors.New("sidecarImage is required")
+	}
+	if len(spec.Steps) == 0 {
+		return 0, errors.New("steps are required")
+	}
+	for i, step := range spec.Steps {
+		if step <= 0 || step > 100 || (i > 0 && step <= spec.Steps[i-1]) {
+			return 0, errors.Errorf("steps must be increasing percentages between 1 and 100, got %v", spec.Steps)
+		}
+	}
+	if spec.Analysis != nil {
+		if spec.Analysis.PrometheusAddress == "" {
+			return 0, errors.New("analysis.prometheusAddress is required")
+		}
+		if rate, err := strconv.ParseFloat(spec.Analysis.MaxErrorRate, 64); err != nil || rate < 0 || rate > 1 {
+			return 0, errors.Errorf("analysis.maxErrorRate must be a number between 0 and 1, got %q", spec.Analysis.MaxErrorRate)
+		}
+	}
+
+	if spec.StepInterval == "" {
+		return defaultRolloutStepInterval, nil
+	}
+	interval, err := time.ParseDuration(spec.StepInterval)
+	if err != nil {
+		return 0, errors.Errorf("invalid stepInterval %q", spec.StepInterval)
+	}
+	return interval, nil
+}
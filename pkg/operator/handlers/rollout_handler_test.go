@@ -0,0 +1,233 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rolloutsapi "github.com/dapr/dapr/pkg/apis/rollouts/v1alpha1"
+)
+
+func rolloutDeployment(name string, daprEnabled bool) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Annotations: map[string]string{
+						daprEnabledAnnotationKey: fmt.Sprintf("%t", daprEnabled),
+						appIDAnnotationKey:       name,
+					},
+				},
+			},
+		},
+	}
+}
+
+func newTestRolloutHandler(t *testing.T, rollout *rolloutsapi.DaprRollout, objects ...client.Object) (*RolloutHandler, *time.Time) {
+	s := runtime.NewScheme()
+	require.NoError(t, scheme.AddToScheme(s))
+	require.NoError(t, rolloutsapi.AddToScheme(s))
+
+	// the clock is aligned on the seconds the step times are stored with, so the expected intervals are exact.
+	now := time.Now().Truncate(time.Second)
+	h := &RolloutHandler{
+		DaprHandler: &DaprHandler{
+			Client: fake.NewClientBuilder().WithScheme(s).WithObjects(append(objects, rollout)...).Build(),
+			Scheme: s,
+		},
+		now: func() time.Time {
+			return now
+		},
+		errorRate: func(ctx context.Context, analysis *rolloutsapi.RolloutAnalysis, appIDs []string) (float64, error) {
+			return 0, nil
+		},
+	}
+	return h, &now
+}
+
+func sidecarImages(t *testing.T, h *RolloutHandler, names ...string) []string {
+	images := make([]string, 0, len(names))
+	for _, name := range names {
+		var d appsv1.Deployment
+		require.NoError(t, h.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: name}, &d))
+		images = append(images, d.Spec.Template.Annotations[daprSidecarImageKey])
+	}
+	return images
+}
+
+func getRollout(t *testing.T, h *RolloutHandler) rolloutsapi.DaprRollout {
+	var rollout rolloutsapi.DaprRollout
+	require.NoError(t, h.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "upgrade"}, &rollout))
+	return rollout
+}
+
+func TestRolloutReconcile(t *testing.T) {
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "upgrade"}}
+	newRollout := func(spec rolloutsapi.DaprRolloutSpec) *rolloutsapi.DaprRollout {
+		return &rolloutsapi.DaprRollout{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "upgrade", Namespace: "default"},
+			Spec:       spec,
+		}
+	}
+	deployments := []client.Object{
+		rolloutDeployment("app1", true),
+		rolloutDeployment("app2", true),
+		rolloutDeployment("app3", true),
+		rolloutDeployment("app4", true),
+		rolloutDeployment("no-dapr", false),
+	}
+
+	t.Run("rolls out step by step", func(t *testing.T) {
+		h, now := newTestRolloutHandler(t, newRollout(rolloutsapi.DaprRolloutSpec{
+			SidecarImage: "daprio/daprd:1.7.0",
+			Steps:        []int32{25, 100},
+			StepInterval: "5m",
+		}), deployments...)
+
+		res, err := h.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, 5*time.Minute, res.RequeueAfter)
+		assert.Equal(t, []string{"daprio/daprd:1.7.0", "", "", ""}, sidecarImages(t, h, "app1", "app2", "app3", "app4"))
+		assert.Equal(t, rolloutsapi.RolloutProgressing, getRollout(t, h).Status.Phase)
+
+		// the step is observed for the step interval.
+		*now = now.Add(time.Minute)
+		res, err = h.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, 4*time.Minute, res.RequeueAfter)
+		assert.Equal(t, []string{"daprio/daprd:1.7.0", "", "", ""}, sidecarImages(t, h, "app1", "app2", "app3", "app4"))
+
+		*now = now.Add(5 * time.Minute)
+		_, err = h.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"daprio/daprd:1.7.0", "daprio/daprd:1.7.0", "daprio/daprd:1.7.0", "daprio/daprd:1.7.0"}, sidecarImages(t, h, "app1", "app2", "app3", "app4"))
+		assert.Equal(t, []string{""}, sidecarImages(t, h, "no-dapr"))
+
+		*now = now.Add(5 * time.Minute)
+		_, err = h.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		rollout := getRollout(t, h)
+		assert.Equal(t, rolloutsapi.RolloutCompleted, rollout.Status.Phase)
+		assert.Equal(t, int32(2), rollout.Status.Step)
+	})
+
+	t.Run("rolls back when the error rate exceeds the threshold", func(t *testing.T) {
+		previous := rolloutDeployment("app1", true)
+		previous.Spec.Template.Annotations[daprSidecarImageKey] = "daprio/daprd:1.6.0"
+		h, now := newTestRolloutHandler(t, newRollout(rolloutsapi.DaprRolloutSpec{
+			SidecarImage: "daprio/daprd:1.7.0",
+			Steps:        []int32{50, 100},
+			Analysis: &rolloutsapi.RolloutAnalysis{
+				PrometheusAddress: "http://prometheus:9090",
+				MaxErrorRate:      "0.05",
+			},
+		}), previous, rolloutDeployment("app2", true))
+		var queried []string
+		h.errorRate = func(ctx context.Context, analysis *rolloutsapi.RolloutAnalysis, appIDs []string) (float64, error) {
+			queried = appIDs
+			return 0.2, nil
+		}
+
+		_, err := h.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"daprio/daprd:1.7.0", ""}, sidecarImages(t, h, "app1", "app2"))
+
+		*now = now.Add(defaultRolloutStepInterval)
+		_, err = h.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"app1"}, queried)
+		assert.Equal(t, []string{"daprio/daprd:1.6.0", ""}, sidecarImages(t, h, "app1", "app2"))
+		assert.Equal(t, rolloutsapi.RolloutRolledBack, getRollout(t, h).Status.Phase)
+	})
+
+	t.Run("updates the deployments recorded before a failed reconciliation", func(t *testing.T) {
+		rollout := newRollout(rolloutsapi.DaprRolloutSpec{
+			SidecarImage: "daprio/daprd:1.7.0",
+			Steps:        []int32{25, 100},
+		})
+		rollout.Status = rolloutsapi.DaprRolloutStatus{
+			Phase:          rolloutsapi.RolloutProgressing,
+			PreviousImages: map[string]string{"app1": ""},
+		}
+		h, _ := newTestRolloutHandler(t, rollout, deployments...)
+
+		_, err := h.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"daprio/daprd:1.7.0", "", "", ""}, sidecarImages(t, h, "app1", "app2", "app3", "app4"))
+		status := getRollout(t, h).Status
+		assert.Equal(t, map[string]string{"app1": ""}, status.PreviousImages)
+		assert.Equal(t, int32(1), status.Step)
+	})
+
+	t.Run("paused", func(t *testing.T) {
+		h, _ := newTestRolloutHandler(t, newRollout(rolloutsapi.DaprRolloutSpec{
+			SidecarImage: "daprio/daprd:1.7.0",
+			Steps:        []int32{100},
+			Paused:       true,
+		}), deployments...)
+
+		_, err := h.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"", "", "", ""}, sidecarImages(t, h, "app1", "app2", "app3", "app4"))
+		assert.Equal(t, rolloutsapi.RolloutPaused, getRollout(t, h).Status.Phase)
+	})
+
+	t.Run("invalid steps", func(t *testing.T) {
+		h, _ := newTestRolloutHandler(t, newRollout(rolloutsapi.DaprRolloutSpec{
+			SidecarImage: "daprio/daprd:1.7.0",
+			Steps:        []int32{50, 20},
+		}), deployments...)
+
+		_, err := h.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		rollout := getRollout(t, h)
+		assert.Equal(t, rolloutsapi.RolloutFailed, rollout.Status.Phase)
+		assert.Contains(t, rollout.Status.Message, "steps must be increasing")
+	})
+}
+
+func TestQueryErrorRate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query", r.URL.Path)
+		assert.Contains(t, r.URL.Query().Get("query"), `app_id=~"app1|app\.2"`)
+		w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1640995200,"0.125"]}]}}`))
+	}))
+	defer server.Close()
+
+	rate, err := queryErrorRate(context.Background(), &rolloutsapi.RolloutAnalysis{PrometheusAddress: server.URL}, []string{"app1", "app.2"})
+	require.NoError(t, err)
+	assert.Equal(t, 0.125, rate)
+
+	rate, err = queryErrorRate(context.Background(), &rolloutsapi.RolloutAnalysis{PrometheusAddress: server.URL}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, rate)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"sort"
+	"strings"
+)
+
+// getWatchedNamespaces returns the comma separated namespaces watched by the operator, along with the namespace of
+// the operator holding its configuration. It returns nil, i.e. all the namespaces are watched, if watchNamespaces is
+// empty.
+func getWatchedNamespaces(watchNamespaces, operatorNamespace string) []string {
+	if watchNamespaces == "" {
+		return nil
+	}
+
+	set := map[string]struct{}{}
+	if operatorNamespace != "" {
+		set[operatorNamespace] = struct{}{}
+	}
+	for _, ns := range strings.Split(watchNamespaces, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			set[ns] = struct{}{}
+		}
+	}
+
+	namespaces := make([]string, 0, len(set))
+	for ns := range set {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
@@ -0,0 +1,31 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWatchedNamespaces(t *testing.T) {
+	t.Run("watched namespaces and the operator namespace", func(t *testing.T) {
+		namespaces := getWatchedNamespaces("team-a-orders, team-a-checkout,team-a-system", "team-a-system")
+		assert.Equal(t, []string{"team-a-checkout", "team-a-orders", "team-a-system"}, namespaces)
+	})
+
+	t.Run("all namespaces", func(t *testing.T) {
+		assert.Nil(t, getWatchedNamespaces("", "team-a-system"))
+	})
+}
@@ -15,18 +15,24 @@ package operator
 
 import (
 	"context"
+	"fmt"
+	"os"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	runtimeutil "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/cache"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	componentsapi "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
 	configurationapi "github.com/dapr/dapr/pkg/apis/configuration/v1alpha1"
+	instancesapi "github.com/dapr/dapr/pkg/apis/instances/v1alpha1"
+	rolloutsapi "github.com/dapr/dapr/pkg/apis/rollouts/v1alpha1"
 	subscriptionsapi_v1alpha1 "github.com/dapr/dapr/pkg/apis/subscriptions/v1alpha1"
 	subscriptionsapi_v2alpha1 "github.com/dapr/dapr/pkg/apis/subscriptions/v2alpha1"
+	"github.com/dapr/dapr/pkg/audit"
 	"github.com/dapr/dapr/pkg/credentials"
 	"github.com/dapr/dapr/pkg/fswatcher"
 	"github.com/dapr/dapr/pkg/health"
@@ -68,20 +74,29 @@ func init() {
 	_ = configurationapi.AddToScheme(scheme)
 	_ = subscriptionsapi_v1alpha1.AddToScheme(scheme)
 	_ = subscriptionsapi_v2alpha1.AddToScheme(scheme)
+	_ = rolloutsapi.AddToScheme(scheme)
+	_ = instancesapi.AddToScheme(scheme)
 }
 
 // NewOperator returns a new Dapr Operator.
-func NewOperator(config, certChainPath string, enableLeaderElection bool) Operator {
+// When watchNamespaces is set, the operator only watches these comma separated namespaces and its own namespace, so
+// separate control planes can manage their slice of a cluster with roles granted in their namespaces only.
+func NewOperator(config, certChainPath string, enableLeaderElection bool, watchNamespaces string) Operator {
 	conf, err := ctrl.GetConfig()
 	if err != nil {
 		log.Fatalf("unable to get controller runtime configuration, err: %s", err)
 	}
-	mgr, err := ctrl.NewManager(conf, ctrl.Options{
+	options := ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: "0",
 		LeaderElection:     enableLeaderElection,
 		LeaderElectionID:   "operator.dapr.io",
-	})
+	}
+	if namespaces := getWatchedNamespaces(watchNamespaces, os.Getenv("NAMESPACE")); namespaces != nil {
+		log.Infof("watching namespaces %v", namespaces)
+		options.NewCache = ctrlcache.MultiNamespacedCacheBuilder(namespaces)
+	}
+	mgr, err := ctrl.NewManager(conf, options)
 	if err != nil {
 		log.Fatal("unable to start manager")
 	}
@@ -89,6 +104,12 @@ func NewOperator(config, certChainPath string, enableLeaderElection bool) Operat
 	if err := daprHandler.Init(); err != nil {
 		log.Fatalf("unable to initialize handler, err: %s", err)
 	}
+	if err := handlers.NewRolloutHandler(mgr).Init(); err != nil {
+		log.Fatalf("unable to initialize rollout handler, err: %s", err)
+	}
+	if err := handlers.NewInstanceHandler(mgr, config).Init(); err != nil {
+		log.Fatalf("unable to initialize instance handler, err: %s", err)
+	}
 
 	o := &operator{
 		daprHandler:   daprHandler,
@@ -97,13 +118,19 @@ func NewOperator(config, certChainPath string, enableLeaderElection bool) Operat
 		configName:    config,
 		certChainPath: certChainPath,
 	}
-	o.apiServer = api.NewAPIServer(o.client)
+	o.apiServer = api.NewAPIServer(o.client, mgr.GetAPIReader())
 	if componentInformer, err := mgr.GetCache().GetInformer(context.TODO(), &componentsapi.Component{}); err != nil {
 		log.Fatalf("unable to get setup components informer, err: %s", err)
 	} else {
 		componentInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 			AddFunc: o.syncComponent,
-			UpdateFunc: func(_, newObj interface{}) {
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				// Skip the updates of the status reported by the sidecars, which leave the generation unchanged.
+				oldComp, okOld := oldObj.(*componentsapi.Component)
+				newComp, okNew := newObj.(*componentsapi.Component)
+				if okOld && okNew && oldComp.Generation != 0 && oldComp.Generation == newComp.Generation {
+					return
+				}
 				o.syncComponent(newObj)
 			},
 		})
@@ -118,12 +145,20 @@ func (o *operator) prepareConfig() {
 		log.Fatalf("unable to load configuration, config: %s, err: %s", o.configName, err)
 	}
 	o.config.Credentials = credentials.NewTLSCredentials(o.certChainPath)
+
+	if err = audit.Default.Init(audit.SourceOperator, "", o.config.AuditSpec, nil); err != nil {
+		log.Errorf("failed to initialize audit log: %s", err)
+	}
 }
 
 func (o *operator) syncComponent(obj interface{}) {
 	c, ok := obj.(*componentsapi.Component)
 	if ok {
 		log.Debugf("observed component to be synced, %s/%s", c.Namespace, c.Name)
+		audit.Default.Emit(audit.Event{
+			Type:     audit.EventComponentUpdated,
+			Resource: fmt.Sprintf("%s/%s (%s)", c.Namespace, c.Name, c.Spec.Type),
+		})
 		o.apiServer.OnComponentUpdated(c)
 	}
 }
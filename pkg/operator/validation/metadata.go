@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"strings"
+	"sync"
+)
+
+// MetadataSchema describes the metadata of a component type.
+type MetadataSchema struct {
+	// Required are the names of the metadata items a component must set.
+	Required []string
+	// Enums are the values accepted by metadata items, compared without case.
+	Enums map[string][]string
+}
+
+var (
+	schemasLock sync.RWMutex
+	// schemas are the metadata schemas of the component types, keyed by type.
+	schemas = map[string]MetadataSchema{
+		"bindings.cron": {
+			Required: []string{"schedule"},
+		},
+		"bindings.kafka": {
+			Required: []string{"brokers"},
+			Enums: map[string][]string{
+				"initialOffset": {"newest", "oldest"},
+			},
+		},
+		"pubsub.kafka": {
+			Required: []string{"brokers"},
+			Enums: map[string][]string{
+				"initialOffset": {"newest", "oldest"},
+			},
+		},
+		"pubsub.rabbitmq": {
+			Required: []string{"host"},
+		},
+		"pubsub.redis": {
+			Required: []string{"redisHost"},
+		},
+		"secretstores.local.file": {
+			Required: []string{"secretsFile"},
+		},
+		"state.cassandra": {
+			Required: []string{"hosts"},
+			Enums: map[string][]string{
+				"consistency": {
+					"Any", "One", "Two", "Three", "Quorum", "All",
+					"LocalQuorum", "EachQuorum", "Serial", "LocalSerial", "LocalOne",
+				},
+			},
+		},
+		"state.postgresql": {
+			Required: []string{"connectionString"},
+		},
+		"state.redis": {
+			Required: []string{"redisHost"},
+		},
+	}
+)
+
+// RegisterMetadataSchema registers the metadata schema of a component type, replacing the existing one.
+func RegisterMetadataSchema(componentType string, schema MetadataSchema) {
+	schemasLock.Lock()
+	defer schemasLock.Unlock()
+	schemas[strings.ToLower(componentType)] = schema
+}
+
+// metadataSchema returns the metadata schema of a component type.
+func metadataSchema(componentType string) (MetadataSchema, bool) {
+	schemasLock.RLock()
+	defer schemasLock.RUnlock()
+	schema, ok := schemas[strings.ToLower(componentType)]
+	return schema, ok
+}
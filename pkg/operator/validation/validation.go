@@ -0,0 +1,213 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/acl"
+	componentsapi "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	subscriptionsapi_v1alpha1 "github.com/dapr/dapr/pkg/apis/subscriptions/v1alpha1"
+	subscriptionsapi_v2alpha1 "github.com/dapr/dapr/pkg/apis/subscriptions/v2alpha1"
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/dapr/pkg/expr"
+	"github.com/dapr/dapr/pkg/policy"
+	"github.com/dapr/dapr/pkg/resiliency"
+)
+
+// componentCategories are the categories of the component types, the prefix of the types.
+var componentCategories = []string{"bindings", "configuration", "middleware", "pubsub", "secretstores", "state"}
+
+// componentVersionRegexp matches the versions of the components, whose case is ignored by the runtime.
+var componentVersionRegexp = regexp.MustCompile(`^[vV][0-9]+$`)
+
+// fieldErrors collects the validation errors of the fields of a resource.
+type fieldErrors []string
+
+func (e *fieldErrors) add(field, format string, args ...interface{}) {
+	*e = append(*e, field+": "+fmt.Sprintf(format, args...))
+}
+
+func (e fieldErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(e, "; "))
+}
+
+// ValidateComponent validates the type, version and metadata of a component.
+// The metadata is validated against the schema of the component type when it's registered.
+func ValidateComponent(component *componentsapi.Component) error {
+	var errs fieldErrors
+	spec := component.Spec
+
+	if spec.Type == "" {
+		errs.add("spec.type", "required")
+	} else if !hasComponentCategory(spec.Type) {
+		errs.add("spec.type", "%q must be prefixed with one of the categories %s", spec.Type, strings.Join(componentCategories, ", "))
+	}
+	if spec.Version != "" && !componentVersionRegexp.MatchString(spec.Version) {
+		errs.add("spec.version", "%q must be formatted as v<major>, e.g. v1", spec.Version)
+	}
+	if spec.InitTimeout != "" {
+		if _, err := time.ParseDuration(spec.InitTimeout); err != nil {
+			errs.add("spec.initTimeout", "%q is not a valid duration", spec.InitTimeout)
+		}
+	}
+
+	items := map[string]componentsapi.MetadataItem{}
+	for i, item := range spec.Metadata {
+		field := fmt.Sprintf("spec.metadata[%d]", i)
+		if item.Name == "" {
+			errs.add(field+".name", "required")
+			continue
+		}
+		if item.SecretKeyRef.Name != "" && item.Value.String() != "" {
+			errs.add(field, "%q sets both a value and a secretKeyRef", item.Name)
+		}
+		if item.SecretKeyRef.Name == "" && item.SecretKeyRef.Key != "" {
+			errs.add(field+".secretKeyRef.name", "required")
+		}
+		items[item.Name] = item
+	}
+
+	if schema, ok := metadataSchema(spec.Type); ok {
+		for _, name := range schema.Required {
+			item, ok := items[name]
+			if !ok || (item.SecretKeyRef.Name == "" && item.Value.String() == "") {
+				errs.add("spec.metadata", "%q is required by %s components", name, spec.Type)
+			}
+		}
+		for name, values := range schema.Enums {
+			item, ok := items[name]
+			if !ok || item.SecretKeyRef.Name != "" {
+				continue
+			}
+			if value := item.Value.String(); !containsFold(values, value) {
+				errs.add("spec.metadata", "%q of %q must be one of %s", value, name, strings.Join(values, ", "))
+			}
+		}
+	}
+
+	for i, scope := range component.Scopes {
+		if strings.TrimSpace(scope) == "" {
+			errs.add(fmt.Sprintf("scopes[%d]", i), "must not be empty")
+		}
+	}
+
+	return errs.err()
+}
+
+// ComponentWarnings returns the warnings of the metadata of a component which the runtime accepts, e.g. the repeated
+// metadata items, whose last value is used.
+func ComponentWarnings(component *componentsapi.Component) []string {
+	var warnings fieldErrors
+	names := map[string]bool{}
+	for i, item := range component.Spec.Metadata {
+		if item.Name != "" && names[item.Name] {
+			warnings.add(fmt.Sprintf("spec.metadata[%d].name", i), "%q is repeated, the last value is used", item.Name)
+		}
+		names[item.Name] = true
+	}
+	return warnings
+}
+
+// ValidateSubscriptionV1alpha1 validates the topic, pubsub and route of a subscription.
+func ValidateSubscriptionV1alpha1(subscription *subscriptionsapi_v1alpha1.Subscription) error {
+	var errs fieldErrors
+	if subscription.Spec.Pubsubname == "" {
+		errs.add("spec.pubsubname", "required")
+	}
+	if subscription.Spec.Topic == "" {
+		errs.add("spec.topic", "required")
+	}
+	if subscription.Spec.Route == "" {
+		errs.add("spec.route", "required")
+	}
+	return errs.err()
+}
+
+// ValidateSubscriptionV2alpha1 validates the topic, pubsub and routing rules of a subscription.
+// The match expressions of the rules must compile.
+func ValidateSubscriptionV2alpha1(subscription *subscriptionsapi_v2alpha1.Subscription) error {
+	var errs fieldErrors
+	spec := subscription.Spec
+	if spec.Pubsubname == "" {
+		errs.add("spec.pubsubname", "required")
+	}
+	if spec.Topic == "" {
+		errs.add("spec.topic", "required")
+	}
+	if spec.Routes.Default == "" && len(spec.Routes.Rules) == 0 {
+		errs.add("spec.routes", "a default path or a rule is required")
+	}
+	for i, rule := range spec.Routes.Rules {
+		field := fmt.Sprintf("spec.routes.rules[%d]", i)
+		if rule.Path == "" {
+			errs.add(field+".path", "required")
+		}
+		if match := strings.TrimSpace(rule.Match); match != "" {
+			var e expr.Expr
+			if err := e.DecodeString(match); err != nil {
+				errs.add(field+".match", "invalid expression: %s", err)
+			}
+		}
+	}
+	return errs.err()
+}
+
+// ValidateConfiguration validates the secrets scopes, access control, API policies and resiliency policies of the
+// JSON of a configuration.
+func ValidateConfiguration(raw []byte) error {
+	// The secrets scopes are validated when the configuration is parsed.
+	conf, err := config.ParseKubernetesConfiguration(raw)
+	if err != nil {
+		return err
+	}
+
+	var errs fieldErrors
+	if _, err = acl.ParseAccessControlSpec(conf.Spec.AccessControlSpec, "http"); err != nil {
+		errs.add("spec.accessControl", "%s", err)
+	}
+	if _, err = policy.NewEngine(conf.Spec.APISpec.Policy); err != nil {
+		errs.add("spec.api.policy", "%s", err)
+	}
+	if _, err = resiliency.FromConfiguration(conf.Spec.ResiliencySpec); err != nil {
+		errs.add("spec.resiliency", "%s", err)
+	}
+	return errs.err()
+}
+
+func hasComponentCategory(componentType string) bool {
+	for _, category := range componentCategories {
+		if strings.HasPrefix(componentType, category+".") && len(componentType) > len(category)+1 {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
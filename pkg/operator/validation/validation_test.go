@@ -0,0 +1,282 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	componentsapi "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	subscriptionsapi_v1alpha1 "github.com/dapr/dapr/pkg/apis/subscriptions/v1alpha1"
+	subscriptionsapi_v2alpha1 "github.com/dapr/dapr/pkg/apis/subscriptions/v2alpha1"
+)
+
+func metadataItem(name, value string) componentsapi.MetadataItem {
+	return componentsapi.MetadataItem{
+		Name:  name,
+		Value: componentsapi.DynamicValue{JSON: v1.JSON{Raw: []byte(`"` + value + `"`)}},
+	}
+}
+
+func TestValidateComponent(t *testing.T) {
+	testCases := []struct {
+		name      string
+		component componentsapi.Component
+		errors    []string
+	}{
+		{
+			name: "valid component",
+			component: componentsapi.Component{
+				Spec: componentsapi.ComponentSpec{
+					Type:        "state.redis",
+					Version:     "v1",
+					InitTimeout: "10s",
+					Metadata: []componentsapi.MetadataItem{
+						metadataItem("redisHost", "localhost:6379"),
+						{Name: "redisPassword", SecretKeyRef: componentsapi.SecretKeyRef{Name: "redis", Key: "password"}},
+					},
+				},
+			},
+		},
+		{
+			name: "required field from a secret",
+			component: componentsapi.Component{
+				Spec: componentsapi.ComponentSpec{
+					Type:     "state.postgresql",
+					Metadata: []componentsapi.MetadataItem{{Name: "connectionString", SecretKeyRef: componentsapi.SecretKeyRef{Name: "pg"}}},
+				},
+			},
+		},
+		{
+			name: "unregistered component type",
+			component: componentsapi.Component{
+				Spec: componentsapi.ComponentSpec{Type: "pubsub.in-memory", Version: "v1"},
+			},
+		},
+		{
+			name:      "missing type",
+			component: componentsapi.Component{},
+			errors:    []string{"spec.type: required"},
+		},
+		{
+			name: "invalid type, version and timeout",
+			component: componentsapi.Component{
+				Spec: componentsapi.ComponentSpec{Type: "redis", Version: "1.0", InitTimeout: "ten seconds"},
+			},
+			errors: []string{"spec.type", "spec.version", "spec.initTimeout"},
+		},
+		{
+			name: "invalid metadata items",
+			component: componentsapi.Component{
+				Spec: componentsapi.ComponentSpec{
+					Type: "bindings.http",
+					Metadata: []componentsapi.MetadataItem{
+						metadataItem("url", "http://localhost"),
+						metadataItem("url", "http://remote"),
+						{Name: ""},
+						{Name: "token", Value: metadataItem("", "abc").Value, SecretKeyRef: componentsapi.SecretKeyRef{Name: "token"}},
+						{Name: "key", SecretKeyRef: componentsapi.SecretKeyRef{Key: "key"}},
+					},
+				},
+			},
+			errors: []string{
+				"spec.metadata[2].name: required",
+				`spec.metadata[3]: "token" sets both a value and a secretKeyRef`,
+				"spec.metadata[4].secretKeyRef.name: required",
+			},
+		},
+		{
+			name: "upper case version",
+			component: componentsapi.Component{
+				Spec: componentsapi.ComponentSpec{Type: "pubsub.in-memory", Version: "V1"},
+			},
+		},
+		{
+			name: "repeated metadata items",
+			component: componentsapi.Component{
+				Spec: componentsapi.ComponentSpec{
+					Type: "bindings.http",
+					Metadata: []componentsapi.MetadataItem{
+						metadataItem("url", "http://localhost"),
+						metadataItem("url", "http://remote"),
+					},
+				},
+			},
+		},
+		{
+			name: "missing required metadata",
+			component: componentsapi.Component{
+				Spec: componentsapi.ComponentSpec{
+					Type:     "state.redis",
+					Metadata: []componentsapi.MetadataItem{metadataItem("redisHost", "")},
+				},
+			},
+			errors: []string{`spec.metadata: "redisHost" is required by state.redis components`},
+		},
+		{
+			name: "invalid enum value",
+			component: componentsapi.Component{
+				Spec: componentsapi.ComponentSpec{
+					Type: "pubsub.kafka",
+					Metadata: []componentsapi.MetadataItem{
+						metadataItem("brokers", "localhost:9092"),
+						metadataItem("initialOffset", "latest"),
+					},
+				},
+			},
+			errors: []string{`spec.metadata: "latest" of "initialOffset" must be one of newest, oldest`},
+		},
+		{
+			name: "enum values are compared without case",
+			component: componentsapi.Component{
+				Spec: componentsapi.ComponentSpec{
+					Type: "state.cassandra",
+					Metadata: []componentsapi.MetadataItem{
+						metadataItem("hosts", "localhost"),
+						metadataItem("consistency", "quorum"),
+					},
+				},
+			},
+		},
+		{
+			name: "empty scope",
+			component: componentsapi.Component{
+				Spec:   componentsapi.ComponentSpec{Type: "state.in-memory"},
+				Scopes: []string{"app1", " "},
+			},
+			errors: []string{"scopes[1]: must not be empty"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateComponent(&tc.component)
+			if len(tc.errors) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.Error(t, err) {
+				for _, e := range tc.errors {
+					assert.Contains(t, err.Error(), e)
+				}
+			}
+		})
+	}
+}
+
+func TestComponentWarnings(t *testing.T) {
+	warnings := ComponentWarnings(&componentsapi.Component{
+		Spec: componentsapi.ComponentSpec{
+			Type: "bindings.http",
+			Metadata: []componentsapi.MetadataItem{
+				metadataItem("url", "http://localhost"),
+				metadataItem("method", "GET"),
+				metadataItem("url", "http://remote"),
+			},
+		},
+	})
+	assert.Equal(t, []string{`spec.metadata[2].name: "url" is repeated, the last value is used`}, warnings)
+}
+
+func TestRegisterMetadataSchema(t *testing.T) {
+	RegisterMetadataSchema("bindings.test", MetadataSchema{Required: []string{"endpoint"}})
+	defer func() {
+		schemasLock.Lock()
+		delete(schemas, "bindings.test")
+		schemasLock.Unlock()
+	}()
+
+	err := ValidateComponent(&componentsapi.Component{Spec: componentsapi.ComponentSpec{Type: "bindings.test"}})
+	assert.EqualError(t, err, `spec.metadata: "endpoint" is required by bindings.test components`)
+}
+
+func TestValidateSubscription(t *testing.T) {
+	t.Run("v1alpha1", func(t *testing.T) {
+		assert.NoError(t, ValidateSubscriptionV1alpha1(&subscriptionsapi_v1alpha1.Subscription{
+			Spec: subscriptionsapi_v1alpha1.SubscriptionSpec{Pubsubname: "pubsub", Topic: "orders", Route: "/orders"},
+		}))
+
+		err := ValidateSubscriptionV1alpha1(&subscriptionsapi_v1alpha1.Subscription{})
+		assert.EqualError(t, err, "spec.pubsubname: required; spec.topic: required; spec.route: required")
+	})
+
+	t.Run("v2alpha1", func(t *testing.T) {
+		assert.NoError(t, ValidateSubscriptionV2alpha1(&subscriptionsapi_v2alpha1.Subscription{
+			Spec: subscriptionsapi_v2alpha1.SubscriptionSpec{
+				Pubsubname: "pubsub",
+				Topic:      "orders",
+				Routes: subscriptionsapi_v2alpha1.Routes{
+					Rules:   []subscriptionsapi_v2alpha1.Rule{{Match: `event.type == "order.created"`, Path: "/created"}},
+					Default: "/orders",
+				},
+			},
+		}))
+
+		err := ValidateSubscriptionV2alpha1(&subscriptionsapi_v2alpha1.Subscription{
+			Spec: subscriptionsapi_v2alpha1.SubscriptionSpec{Pubsubname: "pubsub", Topic: "orders"},
+		})
+		assert.EqualError(t, err, "spec.routes: a default path or a rule is required")
+
+		err = ValidateSubscriptionV2alpha1(&subscriptionsapi_v2alpha1.Subscription{
+			Spec: subscriptionsapi_v2alpha1.SubscriptionSpec{
+				Pubsubname: "pubsub",
+				Topic:      "orders",
+				Routes: subscriptionsapi_v2alpha1.Routes{
+					Rules: []subscriptionsapi_v2alpha1.Rule{{Match: `event.type ==`}},
+				},
+			},
+		})
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "spec.routes.rules[0].path: required")
+			assert.Contains(t, err.Error(), "spec.routes.rules[0].match: invalid expression")
+		}
+	})
+}
+
+func TestValidateConfiguration(t *testing.T) {
+	t.Run("valid configuration", func(t *testing.T) {
+		assert.NoError(t, ValidateConfiguration([]byte(`{
+			"kind": "Configuration",
+			"spec": {
+				"accessControl": {"defaultAction": "deny", "trustDomain": "public"},
+				"resiliency": {"policies": {"timeouts": {"fast": "1s"}}}
+			}
+		}`)))
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		assert.Error(t, ValidateConfiguration([]byte(`{"spec": []}`)))
+	})
+
+	t.Run("invalid secrets scope", func(t *testing.T) {
+		err := ValidateConfiguration([]byte(`{"spec": {"secrets": {"scopes": [{"storeName": "vault", "defaultAccess": "maybe"}]}}}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid access control mode", func(t *testing.T) {
+		err := ValidateConfiguration([]byte(`{"spec": {"accessControl": {"defaultAction": "deny", "mode": "strict"}}}`))
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "spec.accessControl")
+		}
+	})
+
+	t.Run("invalid resiliency timeout", func(t *testing.T) {
+		err := ValidateConfiguration([]byte(`{"spec": {"resiliency": {"policies": {"timeouts": {"fast": "soon"}}}}}`))
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "spec.resiliency")
+		}
+	})
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	componentsapi "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	subscriptionsapi_v1alpha1 "github.com/dapr/dapr/pkg/apis/subscriptions/v1alpha1"
+	subscriptionsapi_v2alpha1 "github.com/dapr/dapr/pkg/apis/subscriptions/v2alpha1"
+	"github.com/dapr/kit/logger"
+)
+
+// WebhookPath is the path of the validating webhook of the Dapr resources.
+const WebhookPath = "/validate"
+
+// Modes of the validating webhook.
+const (
+	// ModeWarn admits the invalid resources with the validation errors as warnings.
+	ModeWarn = "warn"
+	// ModeDeny denies the invalid resources.
+	ModeDeny = "deny"
+)
+
+const daprGroup = "dapr.io"
+
+var log = logger.NewLogger("dapr.operator.validation")
+
+// Handler validates the Dapr resources created or updated in the cluster.
+type Handler struct {
+	deny bool
+}
+
+// NewHandler returns the admission handler of the validating webhook in the mode, warn or deny.
+// The webhook only warns about the invalid resources unless the mode is deny.
+func NewHandler(mode string) *Handler {
+	return &Handler{deny: strings.EqualFold(mode, ModeDeny)}
+}
+
+// Handle allows the request when its resource is valid. The request of an invalid resource is denied with the
+// validation errors in deny mode, and allowed with the validation errors as warnings otherwise.
+func (h *Handler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation == admissionv1.Delete || req.Kind.Group != daprGroup {
+		return admission.Allowed("")
+	}
+
+	var (
+		err      error
+		warnings []string
+	)
+	switch req.Kind.Kind {
+	case "Component":
+		var component componentsapi.Component
+		if err = json.Unmarshal(req.Object.Raw, &component); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		err = ValidateComponent(&component)
+		warnings = ComponentWarnings(&component)
+	case "Subscription":
+		switch req.Kind.Version {
+		case "v1alpha1":
+			var subscription subscriptionsapi_v1alpha1.Subscription
+			if err = json.Unmarshal(req.Object.Raw, &subscription); err != nil {
+				return admission.Errored(http.StatusBadRequest, err)
+			}
+			err = ValidateSubscriptionV1alpha1(&subscription)
+		case "v2alpha1":
+			var subscription subscriptionsapi_v2alpha1.Subscription
+			if err = json.Unmarshal(req.Object.Raw, &subscription); err != nil {
+				return admission.Errored(http.StatusBadRequest, err)
+			}
+			err = ValidateSubscriptionV2alpha1(&subscription)
+		}
+	case "Configuration":
+		err = ValidateConfiguration(req.Object.Raw)
+	}
+
+	if err != nil && h.deny {
+		log.Infof("denied %s %s/%s: %s", req.Kind.Kind, req.Namespace, req.Name, err)
+		return admission.Denied(err.Error()).WithWarnings(warnings...)
+	}
+	if err != nil {
+		log.Infof("admitted invalid %s %s/%s: %s", req.Kind.Kind, req.Namespace, req.Name, err)
+		warnings = append(warnings, err.Error())
+	}
+	return admission.Allowed("").WithWarnings(warnings...)
+}
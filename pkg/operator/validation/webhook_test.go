@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func admissionRequest(operation admissionv1.Operation, group, version, kind, object string) admission.Request {
+	return admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: operation,
+			Kind:      metav1.GroupVersionKind{Group: group, Version: version, Kind: kind},
+			Name:      "test",
+			Namespace: "default",
+			Object:    runtime.RawExtension{Raw: []byte(object)},
+		},
+	}
+}
+
+func TestHandle(t *testing.T) {
+	h := NewHandler(ModeDeny)
+
+	testCases := []struct {
+		name    string
+		req     admission.Request
+		allowed bool
+		code    int32
+	}{
+		{
+			name:    "valid component",
+			req:     admissionRequest(admissionv1.Create, daprGroup, "v1alpha1", "Component", `{"spec": {"type": "state.redis", "version": "v1", "metadata": [{"name": "redisHost", "value": "redis:6379"}]}}`),
+			allowed: true,
+		},
+		{
+			name: "invalid component",
+			req:  admissionRequest(admissionv1.Update, daprGroup, "v1alpha1", "Component", `{"spec": {"type": "state.redis", "version": "v1"}}`),
+			code: http.StatusForbidden,
+		},
+		{
+			name: "malformed component",
+			req:  admissionRequest(admissionv1.Create, daprGroup, "v1alpha1", "Component", `{"spec": []}`),
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "invalid v1alpha1 subscription",
+			req:  admissionRequest(admissionv1.Create, daprGroup, "v1alpha1", "Subscription", `{"spec": {"topic": "orders", "pubsubname": "pubsub"}}`),
+			code: http.StatusForbidden,
+		},
+		{
+			name:    "valid v2alpha1 subscription",
+			req:     admissionRequest(admissionv1.Create, daprGroup, "v2alpha1", "Subscription", `{"spec": {"topic": "orders", "pubsubname": "pubsub", "routes": {"default": "/orders"}}}`),
+			allowed: true,
+		},
+		{
+			name: "invalid configuration",
+			req:  admissionRequest(admissionv1.Create, daprGroup, "v1alpha1", "Configuration", `{"spec": {"accessControl": {"defaultAction": "deny", "mode": "strict"}}}`),
+			code: http.StatusForbidden,
+		},
+		{
+			name:    "delete",
+			req:     admissionRequest(admissionv1.Delete, daprGroup, "v1alpha1", "Component", ``),
+			allowed: true,
+		},
+		{
+			name:    "other group",
+			req:     admissionRequest(admissionv1.Create, "apps", "v1", "Deployment", `{}`),
+			allowed: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := h.Handle(context.Background(), tc.req)
+			assert.Equal(t, tc.allowed, resp.Allowed)
+			if !tc.allowed {
+				assert.Equal(t, tc.code, resp.Result.Code)
+			}
+		})
+	}
+}
+
+func TestHandleWarnMode(t *testing.T) {
+	h := NewHandler(ModeWarn)
+
+	t.Run("invalid component", func(t *testing.T) {
+		resp := h.Handle(context.Background(), admissionRequest(admissionv1.Create, daprGroup, "v1alpha1", "Component", `{"spec": {"type": "state.redis", "version": "v1"}}`))
+		assert.True(t, resp.Allowed)
+		if assert.Len(t, resp.Warnings, 1) {
+			assert.Contains(t, resp.Warnings[0], "redisHost")
+		}
+	})
+
+	t.Run("repeated metadata", func(t *testing.T) {
+		resp := h.Handle(context.Background(), admissionRequest(admissionv1.Create, daprGroup, "v1alpha1", "Component", `{"spec": {"type": "bindings.http", "metadata": [{"name": "url", "value": "a"}, {"name": "url", "value": "b"}]}}`))
+		assert.True(t, resp.Allowed)
+		assert.Len(t, resp.Warnings, 1)
+	})
+
+	t.Run("valid component", func(t *testing.T) {
+		resp := h.Handle(context.Background(), admissionRequest(admissionv1.Create, daprGroup, "v1alpha1", "Component", `{"spec": {"type": "state.redis", "version": "v1", "metadata": [{"name": "redisHost", "value": "redis:6379"}]}}`))
+		assert.True(t, resp.Allowed)
+		assert.Empty(t, resp.Warnings)
+	})
+}
@@ -15,14 +15,16 @@ import (
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	subscriptionsapi_v1alpha1 "github.com/dapr/dapr/pkg/apis/subscriptions/v1alpha1"
 	subscriptionsapi_v2alpha1 "github.com/dapr/dapr/pkg/apis/subscriptions/v2alpha1"
+	"github.com/dapr/dapr/pkg/operator/validation"
 )
 
 const webhookCAName = "dapr-webhook-ca"
 
-func RunWebhooks(enableLeaderElection bool) {
+func RunWebhooks(enableLeaderElection bool, validationMode string) {
 	conf, err := ctrl.GetConfig()
 	if err != nil {
 		log.Fatalf("unable to get controller runtime configuration, err: %s", err)
@@ -54,6 +56,7 @@ func RunWebhooks(enableLeaderElection bool) {
 			Complete(); err != nil {
 			log.Fatalf("unable to create webhook Subscriptions v2alpha1: %v", err)
 		}
+		mgr.GetWebhookServer().Register(validation.WebhookPath, &webhook.Admission{Handler: validation.NewHandler(validationMode)})
 	}
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -0,0 +1,30 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hashing
+
+import (
+	"hash/fnv"
+)
+
+// ShardOf returns the placement shard owning the tables of an entity type when the placement tables are
+// sharded by entity type across shardCount independent placement clusters. Both the placement service and
+// the Dapr runtimes use it, so it must stay stable across versions.
+func ShardOf(entityType string, shardCount int) int {
+	if shardCount <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write([]byte(entityType))
+	return int(h.Sum32() % uint32(shardCount))
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hashing
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardOf(t *testing.T) {
+	t.Run("single shard", func(t *testing.T) {
+		assert.Equal(t, 0, ShardOf("actorType", 0))
+		assert.Equal(t, 0, ShardOf("actorType", 1))
+	})
+
+	t.Run("stable and in range", func(t *testing.T) {
+		counts := make([]int, 4)
+		for i := 0; i < 1000; i++ {
+			actorType := fmt.Sprintf("actorType%d", i)
+			shard := ShardOf(actorType, 4)
+			assert.Equal(t, shard, ShardOf(actorType, 4))
+			assert.True(t, shard >= 0 && shard < 4)
+			counts[shard]++
+		}
+		for _, c := range counts {
+			assert.NotZero(t, c)
+		}
+	})
+}
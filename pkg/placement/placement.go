@@ -29,6 +29,7 @@ import (
 	"github.com/dapr/kit/logger"
 
 	dapr_credentials "github.com/dapr/dapr/pkg/credentials"
+	"github.com/dapr/dapr/pkg/placement/hashing"
 	"github.com/dapr/dapr/pkg/placement/raft"
 	placementv1pb "github.com/dapr/dapr/pkg/proto/placement/v1"
 )
@@ -89,6 +90,12 @@ type Service struct {
 	// raftNode is the raft server instance.
 	raftNode *raft.Server
 
+	// shardID is the shard of the placement tables owned by this placement service when the tables are
+	// sharded by entity type across shardCount placement services.
+	shardID int
+	// shardCount is the number of placement shards. The tables aren't sharded if it is less than 2.
+	shardCount int
+
 	// lastHeartBeat represents the last time stamp when runtime sent heartbeat.
 	lastHeartBeat *sync.Map
 	// membershipCh is the channel to maintain Dapr runtime host membership update.
@@ -133,6 +140,14 @@ func NewPlacementService(raftNode *raft.Server) *Service {
 	}
 }
 
+// SetShard sets the shard of the placement tables owned by the placement service. Only the entities of the
+// shard are added to the placement tables, the other entities are expected to be reported to the placement
+// services of their own shards.
+func (p *Service) SetShard(shardID, shardCount int) {
+	p.shardID = shardID
+	p.shardCount = shardCount
+}
+
 // Run starts the placement service gRPC server.
 func (p *Service) Run(port string, certChain *dapr_credentials.CertChain) {
 	var err error
@@ -207,6 +222,8 @@ func (p *Service) ReportDaprStatus(stream placementv1pb.Placement_ReportDaprStat
 				log.Debugf("Stream connection is established from %s", registeredMemberID)
 			}
 
+			req.Entities = p.ownedEntities(req.Entities)
+
 			// Ensure that the incoming runtime is actor instance.
 			isActorRuntime = len(req.Entities) > 0
 			if !isActorRuntime {
@@ -269,6 +286,23 @@ func (p *Service) ReportDaprStatus(stream placementv1pb.Placement_ReportDaprStat
 	return status.Error(codes.FailedPrecondition, "only leader can serve the request")
 }
 
+// ownedEntities returns the entities owned by the shard of the placement service.
+func (p *Service) ownedEntities(entities []string) []string {
+	if p.shardCount < 2 {
+		return entities
+	}
+
+	owned := make([]string, 0, len(entities))
+	for _, e := range entities {
+		if hashing.ShardOf(e, p.shardCount) == p.shardID {
+			owned = append(owned, e)
+		} else {
+			log.Debugf("ignoring entity %s which doesn't belong to placement shard %d", e, p.shardID)
+		}
+	}
+	return owned
+}
+
 // addStreamConn adds stream connection between runtime and placement to the dissemination pool.
 func (p *Service) addStreamConn(conn placementGRPCStream) {
 	p.streamConnPoolLock.Lock()
@@ -27,6 +27,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/dapr/dapr/pkg/placement/hashing"
 	"github.com/dapr/dapr/pkg/placement/raft"
 	v1pb "github.com/dapr/dapr/pkg/proto/placement/v1"
 )
@@ -257,3 +258,25 @@ func TestMemberRegistration_Leadership(t *testing.T) {
 
 	cleanup()
 }
+
+func TestOwnedEntities(t *testing.T) {
+	entities := []string{"DogActor", "CatActor", "BirdActor", "FishActor"}
+
+	t.Run("not sharded", func(t *testing.T) {
+		testServer := NewPlacementService(nil)
+		assert.Equal(t, entities, testServer.ownedEntities(entities))
+	})
+
+	t.Run("sharded", func(t *testing.T) {
+		owned := 0
+		for shardID := 0; shardID < 2; shardID++ {
+			testServer := NewPlacementService(nil)
+			testServer.SetShard(shardID, 2)
+			for _, e := range testServer.ownedEntities(entities) {
+				assert.Equal(t, shardID, hashing.ShardOf(e, 2))
+				owned++
+			}
+		}
+		assert.Equal(t, len(entities), owned)
+	})
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"path"
+	"strings"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+// AccessList holds the rules of the API allow list and deny list for a protocol.
+// A call is allowed when it matches an allowed rule, or when there's no allowed rule, and matches no denied rule.
+type AccessList struct {
+	allowed []config.APIAccessRule
+	denied  []config.APIAccessRule
+}
+
+// NewAccessList returns the access list of the APIs of the protocol.
+// It returns nil when no rule applies to the protocol.
+func NewAccessList(spec config.APISpec, protocol string) *AccessList {
+	a := &AccessList{
+		allowed: rulesOfProtocol(spec.Allowed, protocol),
+		denied:  rulesOfProtocol(spec.Denied, protocol),
+	}
+	if len(a.allowed) == 0 && len(a.denied) == 0 {
+		return nil
+	}
+	return a
+}
+
+// Allowed returns whether a call is allowed by the access list.
+func (a *AccessList) Allowed(req Request) bool {
+	for _, rule := range a.denied {
+		if ruleMatches(rule, req) {
+			return false
+		}
+	}
+
+	if len(a.allowed) == 0 {
+		return true
+	}
+	for _, rule := range a.allowed {
+		if ruleMatches(rule, req) {
+			return true
+		}
+	}
+	return false
+}
+
+func rulesOfProtocol(rules []config.APIAccessRule, protocol string) []config.APIAccessRule {
+	var res []config.APIAccessRule
+	for _, rule := range rules {
+		if rule.Protocol == protocol {
+			res = append(res, rule)
+		}
+	}
+	return res
+}
+
+// ruleMatches returns whether the API, version, method and component of a call match an access rule.
+// The operations of the rule are compared case insensitively to the method, so that HTTP methods can be written in
+// any case.
+func ruleMatches(rule config.APIAccessRule, req Request) bool {
+	if rule.Name != req.API || rule.Version != req.Version {
+		return false
+	}
+
+	if len(rule.Operations) > 0 {
+		found := false
+		for _, op := range rule.Operations {
+			if strings.EqualFold(op, req.Method) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(rule.Resources) > 0 {
+		for _, pattern := range rule.Resources {
+			if matched, _ := path.Match(pattern, req.Component); matched {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
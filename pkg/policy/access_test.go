@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func TestNewAccessList(t *testing.T) {
+	t.Run("no rules", func(t *testing.T) {
+		assert.Nil(t, NewAccessList(config.APISpec{}, "http"))
+	})
+
+	t.Run("no rules of the protocol", func(t *testing.T) {
+		assert.Nil(t, NewAccessList(config.APISpec{
+			Allowed: []config.APIAccessRule{{Name: "state", Version: "v1", Protocol: "grpc"}},
+		}, "http"))
+	})
+
+	t.Run("denied rules only", func(t *testing.T) {
+		assert.NotNil(t, NewAccessList(config.APISpec{
+			Denied: []config.APIAccessRule{{Name: "state", Version: "v1.0", Protocol: "http"}},
+		}, "http"))
+	})
+}
+
+func TestAccessListAllowed(t *testing.T) {
+	access := NewAccessList(config.APISpec{
+		Allowed: []config.APIAccessRule{
+			{Name: "state", Version: "v1.0", Protocol: "http", Resources: []string{"orders-*", "inventory"}},
+			{Name: "publish", Version: "v1.0", Protocol: "http"},
+		},
+		Denied: []config.APIAccessRule{
+			{Name: "state", Version: "v1.0", Protocol: "http", Operations: []string{"delete"}},
+			{Name: "publish", Version: "v1.0", Protocol: "http", Resources: []string{"audit"}},
+		},
+	}, "http")
+	require.NotNil(t, access)
+
+	testCases := []struct {
+		name    string
+		req     Request
+		allowed bool
+	}{
+		{
+			name:    "allowed resource pattern",
+			req:     Request{API: "state", Version: "v1.0", Method: "GET", Component: "orders-eu"},
+			allowed: true,
+		},
+		{
+			name:    "allowed resource",
+			req:     Request{API: "state", Version: "v1.0", Method: "POST", Component: "inventory"},
+			allowed: true,
+		},
+		{
+			name:    "resource not allowed",
+			req:     Request{API: "state", Version: "v1.0", Method: "GET", Component: "payments"},
+			allowed: false,
+		},
+		{
+			name:    "denied operation",
+			req:     Request{API: "state", Version: "v1.0", Method: "DELETE", Component: "orders-eu"},
+			allowed: false,
+		},
+		{
+			name:    "allowed api",
+			req:     Request{API: "publish", Version: "v1.0", Method: "POST", Component: "pubsub"},
+			allowed: true,
+		},
+		{
+			name:    "denied resource",
+			req:     Request{API: "publish", Version: "v1.0", Method: "POST", Component: "audit"},
+			allowed: false,
+		},
+		{
+			name:    "version not allowed",
+			req:     Request{API: "state", Version: "v1.0-alpha1", Method: "POST", Component: "orders-eu"},
+			allowed: false,
+		},
+		{
+			name:    "api not allowed",
+			req:     Request{API: "secrets", Version: "v1.0", Method: "GET", Component: "vault"},
+			allowed: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.allowed, access.Allowed(tc.req))
+		})
+	}
+
+	t.Run("denied rules only", func(t *testing.T) {
+		access := NewAccessList(config.APISpec{
+			Denied: []config.APIAccessRule{{Name: "state", Version: "v1.0", Protocol: "http", Operations: []string{"DELETE"}}},
+		}, "http")
+		assert.True(t, access.Allowed(Request{API: "secrets", Version: "v1.0", Method: "GET"}))
+		assert.False(t, access.Allowed(Request{API: "state", Version: "v1.0", Method: "DELETE", Component: "orders"}))
+	})
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+// Variables of the policy conditions.
+const (
+	callerVar    = "caller"
+	apiVar       = "api"
+	operationVar = "operation"
+	componentVar = "component"
+	resourceVar  = "resource"
+	protocolVar  = "protocol"
+)
+
+// Operations of the calls, shared by the HTTP and the gRPC APIs, so that a policy applies to both protocols.
+const (
+	OperationGet         = "get"
+	OperationBulkGet     = "bulkGet"
+	OperationSave        = "save"
+	OperationDelete      = "delete"
+	OperationBulkDelete  = "bulkDelete"
+	OperationTransaction = "transaction"
+	OperationQuery       = "query"
+	OperationPublish     = "publish"
+	OperationInvoke      = "invoke"
+	OperationBulkInvoke  = "bulkInvoke"
+	OperationSubscribe   = "subscribe"
+	OperationUnsubscribe = "unsubscribe"
+)
+
+// Request is a call of a building block API, authorized by the policies.
+type Request struct {
+	// Caller is the identity of the caller: the subject of its bearer token, or the app id.
+	Caller string
+	// API is the name of the API, e.g. state, publish or bindings.
+	API string
+	// Version is the version of the API, e.g. v1.
+	Version string
+	// Operation is the operation of the call, e.g. get, save, transaction or publish, whatever the protocol.
+	// It's derived from the method of the call when the operation isn't one of the shared operations.
+	Operation string
+	// Method is the HTTP method or the gRPC method of the call.
+	Method string
+	// Component is the component called, e.g. the state store or the pubsub.
+	// It's the target app id for service invocation and the actor type for actors.
+	Component string
+	// Resource is the resource of the component, e.g. the state key, the topic or the secret name.
+	// It's the method for service invocation and the actor id for actors.
+	// A call on several resources, e.g. a bulk or a transaction request, is authorized for each of its resources.
+	Resource string
+	// Protocol is http or grpc.
+	Protocol string
+}
+
+// ForResources returns the requests authorizing the call on each of the resources, e.g. on each key of a bulk or a
+// transaction request. It returns the request itself when there's no resource.
+func (r Request) ForResources(resources []string) []Request {
+	if len(resources) == 0 {
+		return []Request{r}
+	}
+
+	reqs := make([]Request, len(resources))
+	for i, resource := range resources {
+		reqs[i] = r
+		reqs[i].Resource = resource
+	}
+	return reqs
+}
+
+func (r Request) variables() map[string]interface{} {
+	return map[string]interface{}{
+		callerVar:    r.Caller,
+		apiVar:       r.API,
+		operationVar: r.Operation,
+		componentVar: r.Component,
+		resourceVar:  r.Resource,
+		protocolVar:  r.Protocol,
+	}
+}
+
+type rule struct {
+	name    string
+	allow   bool
+	program cel.Program
+}
+
+// Engine evaluates the policies of the building block APIs.
+// The action of the first rule whose condition matches a call applies, the default action applies otherwise.
+type Engine struct {
+	rules        []rule
+	defaultAllow bool
+}
+
+// NewEngine compiles the CEL conditions of the policies.
+func NewEngine(spec config.APIPolicySpec) (*Engine, error) {
+	env, err := cel.NewEnv(cel.Declarations(
+		decls.NewVar(callerVar, decls.String),
+		decls.NewVar(apiVar, decls.String),
+		decls.NewVar(operationVar, decls.String),
+		decls.NewVar(componentVar, decls.String),
+		decls.NewVar(resourceVar, decls.String),
+		decls.NewVar(protocolVar, decls.String),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	defaultAllow, err := isAllowAction(spec.DefaultAction, config.AllowAccess)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Engine{
+		rules:        make([]rule, 0, len(spec.Rules)),
+		defaultAllow: defaultAllow,
+	}
+	for _, r := range spec.Rules {
+		allow, err := isAllowAction(r.Action, "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid policy %s", r.Name)
+		}
+
+		ast, iss := env.Compile(r.Condition)
+		if iss.Err() != nil {
+			return nil, errors.Wrapf(iss.Err(), "invalid condition of policy %s", r.Name)
+		}
+		if !proto.Equal(ast.ResultType(), decls.Bool) {
+			return nil, errors.Errorf("invalid condition of policy %s: the condition must evaluate to a bool", r.Name)
+		}
+
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid condition of policy %s", r.Name)
+		}
+		e.rules = append(e.rules, rule{name: r.Name, allow: allow, program: program})
+	}
+	return e, nil
+}
+
+// Authorize returns whether a call is allowed, with the name of the policy which matched it.
+// The name is empty when the default action applies. A call whose evaluation fails is denied.
+func (e *Engine) Authorize(req Request) (bool, string, error) {
+	variables := req.variables()
+	for _, r := range e.rules {
+		out, _, err := r.program.Eval(variables)
+		if err != nil {
+			return false, r.name, errors.Wrapf(err, "error evaluating policy %s", r.name)
+		}
+		if matched, ok := out.Value().(bool); ok && matched {
+			return r.allow, r.name, nil
+		}
+	}
+	return e.defaultAllow, "", nil
+}
+
+func isAllowAction(action, defaultAction string) (bool, error) {
+	if action == "" {
+		action = defaultAction
+	}
+
+	switch action {
+	case config.AllowAccess:
+		return true, nil
+	case config.DenyAccess:
+		return false, nil
+	default:
+		return false, errors.Errorf("invalid policy action %q, must be %s or %s", action, config.AllowAccess, config.DenyAccess)
+	}
+}
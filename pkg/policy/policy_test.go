@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func TestNewEngine(t *testing.T) {
+	t.Run("invalid default action", func(t *testing.T) {
+		_, err := NewEngine(config.APIPolicySpec{DefaultAction: "maybe"})
+		assert.Error(t, err)
+	})
+
+	t.Run("missing rule action", func(t *testing.T) {
+		_, err := NewEngine(config.APIPolicySpec{
+			Rules: []config.APIPolicyRule{{Name: "r", Condition: "true"}},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid condition", func(t *testing.T) {
+		_, err := NewEngine(config.APIPolicySpec{
+			Rules: []config.APIPolicyRule{{Name: "r", Condition: "api ==", Action: config.DenyAccess}},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown variable", func(t *testing.T) {
+		_, err := NewEngine(config.APIPolicySpec{
+			Rules: []config.APIPolicyRule{{Name: "r", Condition: "user == 'a'", Action: config.DenyAccess}},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("condition not returning a bool", func(t *testing.T) {
+		_, err := NewEngine(config.APIPolicySpec{
+			Rules: []config.APIPolicyRule{{Name: "r", Condition: "api", Action: config.DenyAccess}},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestAuthorize(t *testing.T) {
+	engine, err := NewEngine(config.APIPolicySpec{
+		DefaultAction: config.DenyAccess,
+		Rules: []config.APIPolicyRule{
+			{
+				Name:      "deny-secret-keys",
+				Condition: "api == 'state' && resource.startsWith('secret-')",
+				Action:    config.DenyAccess,
+			},
+			{
+				Name:      "allow-orders-state",
+				Condition: "api == 'state' && component == 'orders'",
+				Action:    config.AllowAccess,
+			},
+			{
+				Name:      "allow-checkout-publish",
+				Condition: "caller == 'checkout' && api == 'publish' && resource in ['orders', 'payments']",
+				Action:    config.AllowAccess,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("allowed by policy", func(t *testing.T) {
+		allowed, name, err := engine.Authorize(Request{Caller: "checkout", API: "state", Component: "orders", Resource: "order-1"})
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+		assert.Equal(t, "allow-orders-state", name)
+	})
+
+	t.Run("first matching policy applies", func(t *testing.T) {
+		allowed, name, err := engine.Authorize(Request{Caller: "checkout", API: "state", Component: "orders", Resource: "secret-1"})
+		assert.NoError(t, err)
+		assert.False(t, allowed)
+		assert.Equal(t, "deny-secret-keys", name)
+	})
+
+	t.Run("allowed by caller", func(t *testing.T) {
+		allowed, _, err := engine.Authorize(Request{Caller: "checkout", API: "publish", Component: "pubsub", Resource: "payments"})
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+	})
+
+	t.Run("denied by default", func(t *testing.T) {
+		allowed, name, err := engine.Authorize(Request{Caller: "shipping", API: "publish", Component: "pubsub", Resource: "payments"})
+		assert.NoError(t, err)
+		assert.False(t, allowed)
+		assert.Empty(t, name)
+	})
+
+	t.Run("allowed by default", func(t *testing.T) {
+		engine, err := NewEngine(config.APIPolicySpec{})
+		require.NoError(t, err)
+		allowed, _, err := engine.Authorize(Request{API: "secrets"})
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+	})
+}
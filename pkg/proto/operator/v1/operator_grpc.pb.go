@@ -27,6 +27,8 @@ type OperatorClient interface {
 	GetConfiguration(ctx context.Context, in *GetConfigurationRequest, opts ...grpc.CallOption) (*GetConfigurationResponse, error)
 	// Returns a list of pub/sub subscriptions
 	ListSubscriptions(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListSubscriptionsResponse, error)
+	// Reports the initialization result of a component by a Dapr sidecar
+	ReportComponentStatus(ctx context.Context, in *ReportComponentStatusRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type operatorClient struct {
@@ -96,6 +98,15 @@ func (c *operatorClient) ListSubscriptions(ctx context.Context, in *emptypb.Empt
 	return out, nil
 }
 
+func (c *operatorClient) ReportComponentStatus(ctx context.Context, in *ReportComponentStatusRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/dapr.proto.operator.v1.Operator/ReportComponentStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OperatorServer is the server API for Operator service.
 // All implementations should embed UnimplementedOperatorServer
 // for forward compatibility
@@ -108,6 +119,8 @@ type OperatorServer interface {
 	GetConfiguration(context.Context, *GetConfigurationRequest) (*GetConfigurationResponse, error)
 	// Returns a list of pub/sub subscriptions
 	ListSubscriptions(context.Context, *emptypb.Empty) (*ListSubscriptionsResponse, error)
+	// Reports the initialization result of a component by a Dapr sidecar
+	ReportComponentStatus(context.Context, *ReportComponentStatusRequest) (*emptypb.Empty, error)
 }
 
 // UnimplementedOperatorServer should be embedded to have forward compatible implementations.
@@ -126,6 +139,9 @@ func (UnimplementedOperatorServer) GetConfiguration(context.Context, *GetConfigu
 func (UnimplementedOperatorServer) ListSubscriptions(context.Context, *emptypb.Empty) (*ListSubscriptionsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListSubscriptions not implemented")
 }
+func (UnimplementedOperatorServer) ReportComponentStatus(context.Context, *ReportComponentStatusRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportComponentStatus not implemented")
+}
 
 // UnsafeOperatorServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to OperatorServer will
@@ -213,6 +229,24 @@ func _Operator_ListSubscriptions_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Operator_ReportComponentStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportComponentStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OperatorServer).ReportComponentStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.operator.v1.Operator/ReportComponentStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OperatorServer).ReportComponentStatus(ctx, req.(*ReportComponentStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Operator_ServiceDesc is the grpc.ServiceDesc for Operator service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -232,6 +266,10 @@ var Operator_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListSubscriptions",
 			Handler:    _Operator_ListSubscriptions_Handler,
 		},
+		{
+			MethodName: "ReportComponentStatus",
+			Handler:    _Operator_ReportComponentStatus_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
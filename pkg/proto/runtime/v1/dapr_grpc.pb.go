@@ -40,10 +40,25 @@ type DaprClient interface {
 	PublishEvent(ctx context.Context, in *PublishEventRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// Invokes binding data to specific output bindings
 	InvokeBinding(ctx context.Context, in *InvokeBindingRequest, opts ...grpc.CallOption) (*InvokeBindingResponse, error)
+	// Invokes binding data to specific output bindings and streams the response back in chunks.
+	// The metadata of the response is sent with the first chunk.
+	InvokeBindingStreamAlpha1(ctx context.Context, in *InvokeBindingRequest, opts ...grpc.CallOption) (Dapr_InvokeBindingStreamAlpha1Client, error)
+	// Invokes an output binding with several requests, sent at once to the bindings supporting it.
+	InvokeBindingBulkAlpha1(ctx context.Context, in *InvokeBindingBulkRequest, opts ...grpc.CallOption) (*InvokeBindingBulkResponse, error)
 	// Gets secrets from secret stores.
 	GetSecret(ctx context.Context, in *GetSecretRequest, opts ...grpc.CallOption) (*GetSecretResponse, error)
 	// Gets a bulk of secrets
 	GetBulkSecret(ctx context.Context, in *GetBulkSecretRequest, opts ...grpc.CallOption) (*GetBulkSecretResponse, error)
+	// Subscribes to the rotations of secrets and streams their new versions.
+	SubscribeSecretsAlpha1(ctx context.Context, in *SubscribeSecretsRequest, opts ...grpc.CallOption) (Dapr_SubscribeSecretsAlpha1Client, error)
+	// Encrypts data with a key of a secret store transit engine.
+	EncryptAlpha1(ctx context.Context, in *CryptoRequest, opts ...grpc.CallOption) (*CryptoResponse, error)
+	// Decrypts data with a key of a secret store transit engine.
+	DecryptAlpha1(ctx context.Context, in *CryptoRequest, opts ...grpc.CallOption) (*CryptoResponse, error)
+	// Signs data with a key of a secret store transit engine.
+	SignAlpha1(ctx context.Context, in *CryptoRequest, opts ...grpc.CallOption) (*CryptoResponse, error)
+	// Verifies a signature with a key of a secret store transit engine.
+	VerifyAlpha1(ctx context.Context, in *CryptoRequest, opts ...grpc.CallOption) (*CryptoResponse, error)
 	// Register an actor timer.
 	RegisterActorTimer(ctx context.Context, in *RegisterActorTimerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// Unregister an actor timer.
@@ -170,6 +185,47 @@ func (c *daprClient) InvokeBinding(ctx context.Context, in *InvokeBindingRequest
 	return out, nil
 }
 
+func (c *daprClient) InvokeBindingStreamAlpha1(ctx context.Context, in *InvokeBindingRequest, opts ...grpc.CallOption) (Dapr_InvokeBindingStreamAlpha1Client, error) {
+	stream, err := c.cc.NewStream(ctx, &Dapr_ServiceDesc.Streams[0], "/dapr.proto.runtime.v1.Dapr/InvokeBindingStreamAlpha1", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &daprInvokeBindingStreamAlpha1Client{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Dapr_InvokeBindingStreamAlpha1Client interface {
+	Recv() (*InvokeBindingResponse, error)
+	grpc.ClientStream
+}
+
+type daprInvokeBindingStreamAlpha1Client struct {
+	grpc.ClientStream
+}
+
+func (x *daprInvokeBindingStreamAlpha1Client) Recv() (*InvokeBindingResponse, error) {
+	m := new(InvokeBindingResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *daprClient) InvokeBindingBulkAlpha1(ctx context.Context, in *InvokeBindingBulkRequest, opts ...grpc.CallOption) (*InvokeBindingBulkResponse, error) {
+	out := new(InvokeBindingBulkResponse)
+	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/InvokeBindingBulkAlpha1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *daprClient) GetSecret(ctx context.Context, in *GetSecretRequest, opts ...grpc.CallOption) (*GetSecretResponse, error) {
 	out := new(GetSecretResponse)
 	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/GetSecret", in, out, opts...)
@@ -188,6 +244,74 @@ func (c *daprClient) GetBulkSecret(ctx context.Context, in *GetBulkSecretRequest
 	return out, nil
 }
 
+func (c *daprClient) SubscribeSecretsAlpha1(ctx context.Context, in *SubscribeSecretsRequest, opts ...grpc.CallOption) (Dapr_SubscribeSecretsAlpha1Client, error) {
+	stream, err := c.cc.NewStream(ctx, &Dapr_ServiceDesc.Streams[1], "/dapr.proto.runtime.v1.Dapr/SubscribeSecretsAlpha1", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &daprSubscribeSecretsAlpha1Client{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Dapr_SubscribeSecretsAlpha1Client interface {
+	Recv() (*SubscribeSecretsResponse, error)
+	grpc.ClientStream
+}
+
+type daprSubscribeSecretsAlpha1Client struct {
+	grpc.ClientStream
+}
+
+func (x *daprSubscribeSecretsAlpha1Client) Recv() (*SubscribeSecretsResponse, error) {
+	m := new(SubscribeSecretsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *daprClient) EncryptAlpha1(ctx context.Context, in *CryptoRequest, opts ...grpc.CallOption) (*CryptoResponse, error) {
+	out := new(CryptoResponse)
+	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/EncryptAlpha1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daprClient) DecryptAlpha1(ctx context.Context, in *CryptoRequest, opts ...grpc.CallOption) (*CryptoResponse, error) {
+	out := new(CryptoResponse)
+	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/DecryptAlpha1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daprClient) SignAlpha1(ctx context.Context, in *CryptoRequest, opts ...grpc.CallOption) (*CryptoResponse, error) {
+	out := new(CryptoResponse)
+	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/SignAlpha1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daprClient) VerifyAlpha1(ctx context.Context, in *CryptoRequest, opts ...grpc.CallOption) (*CryptoResponse, error) {
+	out := new(CryptoResponse)
+	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/VerifyAlpha1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *daprClient) RegisterActorTimer(ctx context.Context, in *RegisterActorTimerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
 	err := c.cc.Invoke(ctx, "/dapr.proto.runtime.v1.Dapr/RegisterActorTimer", in, out, opts...)
@@ -270,7 +394,7 @@ func (c *daprClient) GetConfigurationAlpha1(ctx context.Context, in *GetConfigur
 }
 
 func (c *daprClient) SubscribeConfigurationAlpha1(ctx context.Context, in *SubscribeConfigurationRequest, opts ...grpc.CallOption) (Dapr_SubscribeConfigurationAlpha1Client, error) {
-	stream, err := c.cc.NewStream(ctx, &Dapr_ServiceDesc.Streams[0], "/dapr.proto.runtime.v1.Dapr/SubscribeConfigurationAlpha1", opts...)
+	stream, err := c.cc.NewStream(ctx, &Dapr_ServiceDesc.Streams[2], "/dapr.proto.runtime.v1.Dapr/SubscribeConfigurationAlpha1", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -352,10 +476,25 @@ type DaprServer interface {
 	PublishEvent(context.Context, *PublishEventRequest) (*emptypb.Empty, error)
 	// Invokes binding data to specific output bindings
 	InvokeBinding(context.Context, *InvokeBindingRequest) (*InvokeBindingResponse, error)
+	// Invokes binding data to specific output bindings and streams the response back in chunks.
+	// The metadata of the response is sent with the first chunk.
+	InvokeBindingStreamAlpha1(*InvokeBindingRequest, Dapr_InvokeBindingStreamAlpha1Server) error
+	// Invokes an output binding with several requests, sent at once to the bindings supporting it.
+	InvokeBindingBulkAlpha1(context.Context, *InvokeBindingBulkRequest) (*InvokeBindingBulkResponse, error)
 	// Gets secrets from secret stores.
 	GetSecret(context.Context, *GetSecretRequest) (*GetSecretResponse, error)
 	// Gets a bulk of secrets
 	GetBulkSecret(context.Context, *GetBulkSecretRequest) (*GetBulkSecretResponse, error)
+	// Subscribes to the rotations of secrets and streams their new versions.
+	SubscribeSecretsAlpha1(*SubscribeSecretsRequest, Dapr_SubscribeSecretsAlpha1Server) error
+	// Encrypts data with a key of a secret store transit engine.
+	EncryptAlpha1(context.Context, *CryptoRequest) (*CryptoResponse, error)
+	// Decrypts data with a key of a secret store transit engine.
+	DecryptAlpha1(context.Context, *CryptoRequest) (*CryptoResponse, error)
+	// Signs data with a key of a secret store transit engine.
+	SignAlpha1(context.Context, *CryptoRequest) (*CryptoResponse, error)
+	// Verifies a signature with a key of a secret store transit engine.
+	VerifyAlpha1(context.Context, *CryptoRequest) (*CryptoResponse, error)
 	// Register an actor timer.
 	RegisterActorTimer(context.Context, *RegisterActorTimerRequest) (*emptypb.Empty, error)
 	// Unregister an actor timer.
@@ -418,12 +557,33 @@ func (UnimplementedDaprServer) PublishEvent(context.Context, *PublishEventReques
 func (UnimplementedDaprServer) InvokeBinding(context.Context, *InvokeBindingRequest) (*InvokeBindingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method InvokeBinding not implemented")
 }
+func (UnimplementedDaprServer) InvokeBindingStreamAlpha1(*InvokeBindingRequest, Dapr_InvokeBindingStreamAlpha1Server) error {
+	return status.Errorf(codes.Unimplemented, "method InvokeBindingStreamAlpha1 not implemented")
+}
+func (UnimplementedDaprServer) InvokeBindingBulkAlpha1(context.Context, *InvokeBindingBulkRequest) (*InvokeBindingBulkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InvokeBindingBulkAlpha1 not implemented")
+}
 func (UnimplementedDaprServer) GetSecret(context.Context, *GetSecretRequest) (*GetSecretResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetSecret not implemented")
 }
 func (UnimplementedDaprServer) GetBulkSecret(context.Context, *GetBulkSecretRequest) (*GetBulkSecretResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBulkSecret not implemented")
 }
+func (UnimplementedDaprServer) SubscribeSecretsAlpha1(*SubscribeSecretsRequest, Dapr_SubscribeSecretsAlpha1Server) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeSecretsAlpha1 not implemented")
+}
+func (UnimplementedDaprServer) EncryptAlpha1(context.Context, *CryptoRequest) (*CryptoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EncryptAlpha1 not implemented")
+}
+func (UnimplementedDaprServer) DecryptAlpha1(context.Context, *CryptoRequest) (*CryptoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecryptAlpha1 not implemented")
+}
+func (UnimplementedDaprServer) SignAlpha1(context.Context, *CryptoRequest) (*CryptoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignAlpha1 not implemented")
+}
+func (UnimplementedDaprServer) VerifyAlpha1(context.Context, *CryptoRequest) (*CryptoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyAlpha1 not implemented")
+}
 func (UnimplementedDaprServer) RegisterActorTimer(context.Context, *RegisterActorTimerRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RegisterActorTimer not implemented")
 }
@@ -655,6 +815,45 @@ func _Dapr_InvokeBinding_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Dapr_InvokeBindingStreamAlpha1_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(InvokeBindingRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DaprServer).InvokeBindingStreamAlpha1(m, &daprInvokeBindingStreamAlpha1Server{stream})
+}
+
+type Dapr_InvokeBindingStreamAlpha1Server interface {
+	Send(*InvokeBindingResponse) error
+	grpc.ServerStream
+}
+
+type daprInvokeBindingStreamAlpha1Server struct {
+	grpc.ServerStream
+}
+
+func (x *daprInvokeBindingStreamAlpha1Server) Send(m *InvokeBindingResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Dapr_InvokeBindingBulkAlpha1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvokeBindingBulkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaprServer).InvokeBindingBulkAlpha1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.runtime.v1.Dapr/InvokeBindingBulkAlpha1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaprServer).InvokeBindingBulkAlpha1(ctx, req.(*InvokeBindingBulkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Dapr_GetSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetSecretRequest)
 	if err := dec(in); err != nil {
@@ -691,6 +890,99 @@ func _Dapr_GetBulkSecret_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Dapr_SubscribeSecretsAlpha1_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeSecretsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DaprServer).SubscribeSecretsAlpha1(m, &daprSubscribeSecretsAlpha1Server{stream})
+}
+
+type Dapr_SubscribeSecretsAlpha1Server interface {
+	Send(*SubscribeSecretsResponse) error
+	grpc.ServerStream
+}
+
+type daprSubscribeSecretsAlpha1Server struct {
+	grpc.ServerStream
+}
+
+func (x *daprSubscribeSecretsAlpha1Server) Send(m *SubscribeSecretsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Dapr_EncryptAlpha1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CryptoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaprServer).EncryptAlpha1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.runtime.v1.Dapr/EncryptAlpha1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaprServer).EncryptAlpha1(ctx, req.(*CryptoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dapr_DecryptAlpha1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CryptoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaprServer).DecryptAlpha1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.runtime.v1.Dapr/DecryptAlpha1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaprServer).DecryptAlpha1(ctx, req.(*CryptoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dapr_SignAlpha1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CryptoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaprServer).SignAlpha1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.runtime.v1.Dapr/SignAlpha1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaprServer).SignAlpha1(ctx, req.(*CryptoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dapr_VerifyAlpha1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CryptoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaprServer).VerifyAlpha1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dapr.proto.runtime.v1.Dapr/VerifyAlpha1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaprServer).VerifyAlpha1(ctx, req.(*CryptoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Dapr_RegisterActorTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RegisterActorTimerRequest)
 	if err := dec(in); err != nil {
@@ -975,6 +1267,10 @@ var Dapr_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "InvokeBinding",
 			Handler:    _Dapr_InvokeBinding_Handler,
 		},
+		{
+			MethodName: "InvokeBindingBulkAlpha1",
+			Handler:    _Dapr_InvokeBindingBulkAlpha1_Handler,
+		},
 		{
 			MethodName: "GetSecret",
 			Handler:    _Dapr_GetSecret_Handler,
@@ -983,6 +1279,22 @@ var Dapr_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetBulkSecret",
 			Handler:    _Dapr_GetBulkSecret_Handler,
 		},
+		{
+			MethodName: "EncryptAlpha1",
+			Handler:    _Dapr_EncryptAlpha1_Handler,
+		},
+		{
+			MethodName: "DecryptAlpha1",
+			Handler:    _Dapr_DecryptAlpha1_Handler,
+		},
+		{
+			MethodName: "SignAlpha1",
+			Handler:    _Dapr_SignAlpha1_Handler,
+		},
+		{
+			MethodName: "VerifyAlpha1",
+			Handler:    _Dapr_VerifyAlpha1_Handler,
+		},
 		{
 			MethodName: "RegisterActorTimer",
 			Handler:    _Dapr_RegisterActorTimer_Handler,
@@ -1033,6 +1345,16 @@ var Dapr_ServiceDesc = grpc.ServiceDesc{
 		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "InvokeBindingStreamAlpha1",
+			Handler:       _Dapr_InvokeBindingStreamAlpha1_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeSecretsAlpha1",
+			Handler:       _Dapr_SubscribeSecretsAlpha1_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "SubscribeConfigurationAlpha1",
 			Handler:       _Dapr_SubscribeConfigurationAlpha1_Handler,
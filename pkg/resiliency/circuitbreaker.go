@@ -0,0 +1,220 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resiliency
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+// CircuitBreakerState is the state of a circuit breaker.
+type CircuitBreakerState string
+
+const (
+	// CircuitBreakerClosed lets the calls through.
+	CircuitBreakerClosed CircuitBreakerState = "closed"
+	// CircuitBreakerOpen rejects the calls until the timeout of the policy elapses.
+	CircuitBreakerOpen CircuitBreakerState = "open"
+	// CircuitBreakerHalfOpen lets a single call through to probe the target.
+	CircuitBreakerHalfOpen CircuitBreakerState = "half-open"
+)
+
+// ErrCircuitBreakerOpen is returned for the calls rejected by a circuit breaker.
+var ErrCircuitBreakerOpen = errors.New("circuit breaker is open")
+
+// CircuitBreakerPolicy is a parsed circuit breaker policy.
+type CircuitBreakerPolicy struct {
+	MaxConsecutiveFailures int
+	Timeout                time.Duration
+}
+
+// CircuitBreakerStatus is the current status of a circuit breaker.
+// Tripped is true when the circuit breaker was opened manually, in which case it stays open until it's reset.
+type CircuitBreakerStatus struct {
+	Name                string              `json:"name"`
+	State               CircuitBreakerState `json:"state"`
+	ConsecutiveFailures int                 `json:"consecutiveFailures"`
+	Tripped             bool                `json:"tripped,omitempty"`
+	OpenedAt            *time.Time          `json:"openedAt,omitempty"`
+}
+
+// CircuitBreaker stops the calls to a target after consecutive failures. It opens after MaxConsecutiveFailures
+// failures, then lets a single call through once the timeout of its policy elapsed: the circuit breaker closes if it
+// succeeds and opens again otherwise.
+// A nil CircuitBreaker lets all the calls through.
+type CircuitBreaker struct {
+	name   string
+	policy *CircuitBreakerPolicy
+
+	// now is the clock of the circuit breaker, which is simulated for the simulations of the policies. The transitions
+	// of the simulated circuit breakers aren't logged.
+	now       func() time.Time
+	simulated bool
+
+	lock     sync.Mutex
+	state    CircuitBreakerState
+	failures int
+	openedAt time.Time
+	tripped  bool
+	probing  bool
+}
+
+func newCircuitBreaker(name string, policy *CircuitBreakerPolicy) *CircuitBreaker {
+	return &CircuitBreaker{
+		name:   name,
+		policy: policy,
+		now:    time.Now,
+		state:  CircuitBreakerClosed,
+	}
+}
+
+// Allow returns ErrCircuitBreakerOpen if the call must be rejected. The outcome of the allowed calls must be
+// recorded with Record.
+func (b *CircuitBreaker) Allow() error {
+	if b == nil {
+		return nil
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	switch b.state {
+	case CircuitBreakerOpen:
+		if b.tripped || b.now().Sub(b.openedAt) < b.policy.Timeout {
+			return ErrCircuitBreakerOpen
+		}
+		b.state = CircuitBreakerHalfOpen
+		if !b.simulated {
+			log.Infof("circuit breaker %s is half-open", b.name)
+		}
+	case CircuitBreakerHalfOpen:
+		if b.probing {
+			return ErrCircuitBreakerOpen
+		}
+	}
+	if b.state == CircuitBreakerHalfOpen {
+		b.probing = true
+	}
+	return nil
+}
+
+// Record records the outcome of a call allowed by the circuit breaker.
+func (b *CircuitBreaker) Record(err error) {
+	if b == nil {
+		return
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	halfOpen := b.state == CircuitBreakerHalfOpen
+	if halfOpen {
+		b.probing = false
+	}
+	// the outcome of the calls allowed before the circuit breaker opened doesn't change its state.
+	if b.state == CircuitBreakerOpen {
+		return
+	}
+
+	if err == nil {
+		b.failures = 0
+		if halfOpen {
+			b.state = CircuitBreakerClosed
+			if !b.simulated {
+				log.Infof("circuit breaker %s is closed", b.name)
+			}
+		}
+		return
+	}
+
+	b.failures++
+	if halfOpen || b.failures >= b.policy.MaxConsecutiveFailures {
+		b.open()
+		if !b.simulated {
+			log.Warnf("circuit breaker %s is open after %d consecutive failures: %s", b.name, b.failures, err)
+		}
+	}
+}
+
+// Trip opens the circuit breaker until it's reset.
+func (b *CircuitBreaker) Trip() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.open()
+	b.tripped = true
+	log.Infof("circuit breaker %s is tripped", b.name)
+}
+
+// Reset closes the circuit breaker and clears its failures.
+func (b *CircuitBreaker) Reset() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.state = CircuitBreakerClosed
+	b.failures = 0
+	b.tripped = false
+	b.probing = false
+	log.Infof("circuit breaker %s is reset", b.name)
+}
+
+// Status returns the current status of the circuit breaker.
+func (b *CircuitBreaker) Status() CircuitBreakerStatus {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	status := CircuitBreakerStatus{
+		Name:                b.name,
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		Tripped:             b.tripped,
+	}
+	// an open circuit breaker lets the next call through once its timeout elapsed.
+	if b.state == CircuitBreakerOpen && !b.tripped && b.now().Sub(b.openedAt) >= b.policy.Timeout {
+		status.State = CircuitBreakerHalfOpen
+	}
+	if b.state != CircuitBreakerClosed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	return status
+}
+
+func (b *CircuitBreaker) open() {
+	b.state = CircuitBreakerOpen
+	b.openedAt = b.now()
+	b.probing = false
+}
+
+func parseCircuitBreakerPolicy(spec config.CircuitBreakerPolicySpec) (*CircuitBreakerPolicy, error) {
+	timeout, err := time.ParseDuration(spec.Timeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid timeout")
+	}
+	if timeout <= 0 {
+		return nil, errors.New("timeout must be greater than zero")
+	}
+	if spec.MaxConsecutiveFailures <= 0 {
+		return nil, errors.New("maxConsecutiveFailures must be greater than zero")
+	}
+
+	return &CircuitBreakerPolicy{
+		MaxConsecutiveFailures: spec.MaxConsecutiveFailures,
+		Timeout:                timeout,
+	}, nil
+}
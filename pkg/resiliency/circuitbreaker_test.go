@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resiliency
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	t.Run("opens after consecutive failures", func(t *testing.T) {
+		b := newCircuitBreaker("app1", &CircuitBreakerPolicy{MaxConsecutiveFailures: 2, Timeout: time.Minute})
+
+		assert.NoError(t, b.Allow())
+		b.Record(errFailed)
+		assert.NoError(t, b.Allow())
+		b.Record(nil)
+		assert.Equal(t, 0, b.Status().ConsecutiveFailures)
+
+		for i := 0; i < 2; i++ {
+			assert.NoError(t, b.Allow())
+			b.Record(errFailed)
+		}
+		assert.Equal(t, ErrCircuitBreakerOpen, b.Allow())
+		status := b.Status()
+		assert.Equal(t, CircuitBreakerOpen, status.State)
+		assert.Equal(t, 2, status.ConsecutiveFailures)
+		assert.NotNil(t, status.OpenedAt)
+	})
+
+	t.Run("half-open after timeout", func(t *testing.T) {
+		b := newCircuitBreaker("app1", &CircuitBreakerPolicy{MaxConsecutiveFailures: 1, Timeout: 10 * time.Millisecond})
+
+		b.Record(errFailed)
+		assert.Equal(t, ErrCircuitBreakerOpen, b.Allow())
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, CircuitBreakerHalfOpen, b.Status().State)
+
+		// a single call probes the target.
+		assert.NoError(t, b.Allow())
+		assert.Equal(t, ErrCircuitBreakerOpen, b.Allow())
+		b.Record(errFailed)
+		assert.Equal(t, CircuitBreakerOpen, b.Status().State)
+
+		time.Sleep(20 * time.Millisecond)
+		assert.NoError(t, b.Allow())
+		b.Record(nil)
+		assert.Equal(t, CircuitBreakerClosed, b.Status().State)
+		assert.NoError(t, b.Allow())
+	})
+
+	t.Run("trip and reset", func(t *testing.T) {
+		b := newCircuitBreaker("app1", &CircuitBreakerPolicy{MaxConsecutiveFailures: 1, Timeout: time.Millisecond})
+
+		b.Trip()
+		time.Sleep(5 * time.Millisecond)
+		assert.Equal(t, ErrCircuitBreakerOpen, b.Allow())
+		status := b.Status()
+		assert.Equal(t, CircuitBreakerOpen, status.State)
+		assert.True(t, status.Tripped)
+
+		b.Reset()
+		assert.NoError(t, b.Allow())
+		assert.Equal(t, CircuitBreakerStatus{Name: "app1", State: CircuitBreakerClosed}, b.Status())
+	})
+
+	t.Run("nil circuit breaker", func(t *testing.T) {
+		var b *CircuitBreaker
+		assert.NoError(t, b.Allow())
+		b.Record(errFailed)
+	})
+}
+
+func TestCircuitBreakerPolicies(t *testing.T) {
+	spec := config.ResiliencySpec{
+		Policies: config.ResiliencyPolicies{
+			CircuitBreakers: map[string]config.CircuitBreakerPolicySpec{
+				"cb": {MaxConsecutiveFailures: 1, Timeout: "1m"},
+			},
+		},
+		Targets: config.ResiliencyTargets{
+			Apps: map[string]config.AppResiliencyTarget{
+				"app1": {CircuitBreaker: "cb"},
+				"app2": {CircuitBreaker: "cb"},
+			},
+		},
+	}
+	r, err := FromConfiguration(spec)
+	assert.NoError(t, err)
+	assert.Nil(t, r.AppCircuitBreaker("app3"))
+
+	r.AppCircuitBreaker("app1").Trip()
+	statuses := r.CircuitBreakers()
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, "app1", statuses[0].Name)
+	assert.Equal(t, CircuitBreakerOpen, statuses[0].State)
+	assert.Equal(t, "app2", statuses[1].Name)
+
+	t.Run("state is kept on updates", func(t *testing.T) {
+		assert.NoError(t, r.Update(spec))
+		assert.Equal(t, CircuitBreakerOpen, r.AppCircuitBreaker("app1").Status().State)
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		for _, cb := range []config.CircuitBreakerPolicySpec{
+			{MaxConsecutiveFailures: 1, Timeout: "abc"},
+			{MaxConsecutiveFailures: 1, Timeout: "0s"},
+			{MaxConsecutiveFailures: 0, Timeout: "1m"},
+		} {
+			_, err := FromConfiguration(config.ResiliencySpec{
+				Policies: config.ResiliencyPolicies{
+					CircuitBreakers: map[string]config.CircuitBreakerPolicySpec{"cb": cb},
+				},
+			})
+			assert.Error(t, err)
+		}
+
+		_, err := FromConfiguration(config.ResiliencySpec{
+			Targets: config.ResiliencyTargets{
+				Apps: map[string]config.AppResiliencyTarget{
+					"app1": {CircuitBreaker: "missing"},
+				},
+			},
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resiliency
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+const (
+	defaultConcurrencyInitialLimit = 20
+	defaultConcurrencyMinLimit     = 1
+	defaultConcurrencyMaxLimit     = 1000
+	defaultConcurrencyBackoffRatio = 0.9
+)
+
+// ErrConcurrencyLimitExceeded is returned for the calls rejected by a concurrency limiter.
+var ErrConcurrencyLimitExceeded = errors.New("concurrency limit exceeded")
+
+// ConcurrencyPolicy is a parsed adaptive concurrency policy.
+type ConcurrencyPolicy struct {
+	InitialLimit     int
+	MinLimit         int
+	MaxLimit         int
+	LatencyThreshold time.Duration
+	BackoffRatio     float64
+}
+
+// ConcurrencyStatus is the current status of a concurrency limiter.
+type ConcurrencyStatus struct {
+	Name     string `json:"name"`
+	Limit    int    `json:"limit"`
+	InFlight int    `json:"inFlight"`
+	// Target is "app" for the limiters of app invocations and "component" for the limiters of component operations.
+	Target string `json:"target,omitempty"`
+}
+
+// ConcurrencyLimiter limits the number of in-flight calls to a target with an additive increase, multiplicative
+// decrease (AIMD) of the limit: every call completing within the latency threshold of the policy raises the limit by
+// 1/limit, i.e. by one for a full window of calls, while a failed or slow call multiplies it by the backoff ratio.
+// The calls over the limit are rejected.
+// A nil ConcurrencyLimiter lets all the calls through.
+type ConcurrencyLimiter struct {
+	name   string
+	policy *ConcurrencyPolicy
+	now    func() time.Time
+
+	lock     sync.Mutex
+	limit    float64
+	inFlight int
+}
+
+func newConcurrencyLimiter(name string, policy *ConcurrencyPolicy) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{
+		name:   name,
+		policy: policy,
+		now:    time.Now,
+		limit:  float64(policy.InitialLimit),
+	}
+}
+
+// Acquire returns ErrConcurrencyLimitExceeded if the call must be rejected, and otherwise a function to call with the
+// outcome of the call once it completed. The limit isn't adjusted for the calls rejected by a circuit breaker.
+func (l *ConcurrencyLimiter) Acquire() (func(err error), error) {
+	if l == nil {
+		return func(error) {}, nil
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.inFlight >= int(l.limit) {
+		return nil, ErrConcurrencyLimitExceeded
+	}
+	l.inFlight++
+
+	start := l.now()
+	return func(err error) {
+		l.release(l.now().Sub(start), err)
+	}, nil
+}
+
+func (l *ConcurrencyLimiter) release(latency time.Duration, err error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.inFlight--
+	switch {
+	case errors.Is(err, ErrCircuitBreakerOpen):
+	case err != nil || latency > l.policy.LatencyThreshold:
+		previous := int(l.limit)
+		l.limit *= l.policy.BackoffRatio
+		if l.limit < float64(l.policy.MinLimit) {
+			l.limit = float64(l.policy.MinLimit)
+		}
+		if int(l.limit) < previous {
+			log.Debugf("concurrency limit of %s decreased to %d", l.name, int(l.limit))
+		}
+	default:
+		l.limit += 1 / l.limit
+		if l.limit > float64(l.policy.MaxLimit) {
+			l.limit = float64(l.policy.MaxLimit)
+		}
+	}
+}
+
+// Status returns the current status of the concurrency limiter.
+func (l *ConcurrencyLimiter) Status() ConcurrencyStatus {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return ConcurrencyStatus{
+		Name:     l.name,
+		Limit:    int(l.limit),
+		InFlight: l.inFlight,
+	}
+}
+
+func parseConcurrencyPolicy(spec config.ConcurrencyPolicySpec) (*ConcurrencyPolicy, error) {
+	threshold, err := time.ParseDuration(spec.LatencyThreshold)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid latencyThreshold")
+	}
+	if threshold <= 0 {
+		return nil, errors.New("latencyThreshold must be greater than zero")
+	}
+
+	policy := &ConcurrencyPolicy{
+		InitialLimit:     spec.InitialLimit,
+		MinLimit:         spec.MinLimit,
+		MaxLimit:         spec.MaxLimit,
+		LatencyThreshold: threshold,
+		BackoffRatio:     spec.BackoffRatio,
+	}
+	if policy.MinLimit == 0 {
+		policy.MinLimit = defaultConcurrencyMinLimit
+	}
+	if policy.MaxLimit == 0 {
+		policy.MaxLimit = defaultConcurrencyMaxLimit
+	}
+	if policy.InitialLimit == 0 {
+		policy.InitialLimit = defaultConcurrencyInitialLimit
+		if policy.InitialLimit > policy.MaxLimit {
+			policy.InitialLimit = policy.MaxLimit
+		}
+		if policy.InitialLimit < policy.MinLimit {
+			policy.InitialLimit = policy.MinLimit
+		}
+	}
+	if policy.BackoffRatio == 0 {
+		policy.BackoffRatio = defaultConcurrencyBackoffRatio
+	}
+
+	if policy.MinLimit < 1 {
+		return nil, errors.New("minLimit must be at least 1")
+	}
+	if policy.MaxLimit < policy.MinLimit {
+		return nil, errors.New("maxLimit must not be lower than minLimit")
+	}
+	if policy.InitialLimit < policy.MinLimit || policy.InitialLimit > policy.MaxLimit {
+		return nil, errors.New("initialLimit must be between minLimit and maxLimit")
+	}
+	if policy.BackoffRatio <= 0 || policy.BackoffRatio >= 1 {
+		return nil, errors.New("backoffRatio must be greater than 0 and lower than 1")
+	}
+	return policy, nil
+}
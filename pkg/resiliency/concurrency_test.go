@@ -0,0 +1,167 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resiliency
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	policy := &ConcurrencyPolicy{
+		InitialLimit:     2,
+		MinLimit:         1,
+		MaxLimit:         3,
+		LatencyThreshold: time.Second,
+		BackoffRatio:     0.5,
+	}
+
+	t.Run("rejects the calls over the limit", func(t *testing.T) {
+		l := newConcurrencyLimiter("app1", policy)
+		release1, err := l.Acquire()
+		assert.NoError(t, err)
+		_, err = l.Acquire()
+		assert.NoError(t, err)
+		_, err = l.Acquire()
+		assert.Equal(t, ErrConcurrencyLimitExceeded, err)
+		assert.Equal(t, ConcurrencyStatus{Name: "app1", Limit: 2, InFlight: 2}, l.Status())
+
+		release1(nil)
+		_, err = l.Acquire()
+		assert.NoError(t, err)
+	})
+
+	t.Run("increases additively", func(t *testing.T) {
+		l := newConcurrencyLimiter("app1", policy)
+		for i := 0; i < 10; i++ {
+			release, err := l.Acquire()
+			assert.NoError(t, err)
+			release(nil)
+		}
+		assert.Equal(t, 3, l.Status().Limit)
+	})
+
+	t.Run("decreases multiplicatively", func(t *testing.T) {
+		l := newConcurrencyLimiter("app1", policy)
+		release, err := l.Acquire()
+		assert.NoError(t, err)
+		release(errors.New("failed"))
+		assert.Equal(t, 1, l.Status().Limit)
+
+		release, err = l.Acquire()
+		assert.NoError(t, err)
+		release(errors.New("failed"))
+		assert.Equal(t, 1, l.Status().Limit)
+	})
+
+	t.Run("ignores the calls rejected by a circuit breaker", func(t *testing.T) {
+		l := newConcurrencyLimiter("app1", policy)
+		release, err := l.Acquire()
+		assert.NoError(t, err)
+		release(ErrCircuitBreakerOpen)
+		assert.Equal(t, ConcurrencyStatus{Name: "app1", Limit: 2}, l.Status())
+	})
+
+	t.Run("nil limiter", func(t *testing.T) {
+		var l *ConcurrencyLimiter
+		release, err := l.Acquire()
+		assert.NoError(t, err)
+		release(nil)
+	})
+}
+
+func TestConcurrencyPolicies(t *testing.T) {
+	r, err := FromConfiguration(config.ResiliencySpec{
+		Policies: config.ResiliencyPolicies{
+			Concurrency: map[string]config.ConcurrencyPolicySpec{
+				"adaptive": {LatencyThreshold: "200ms"},
+			},
+		},
+		Targets: config.ResiliencyTargets{
+			Apps: map[string]config.AppResiliencyTarget{
+				"app1": {Concurrency: "adaptive"},
+			},
+			Components: map[string]config.ComponentResiliencyTarget{
+				"kafka": {Concurrency: "adaptive"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &ConcurrencyPolicy{
+		InitialLimit:     20,
+		MinLimit:         1,
+		MaxLimit:         1000,
+		LatencyThreshold: 200 * time.Millisecond,
+		BackoffRatio:     0.9,
+	}, r.AppConcurrencyLimiter("app1").policy)
+	assert.NotNil(t, r.ComponentConcurrencyLimiter("kafka"))
+	assert.Nil(t, r.AppConcurrencyLimiter("app2"))
+	assert.Nil(t, r.ComponentConcurrencyLimiter("app1"))
+	assert.Equal(t, []ConcurrencyStatus{
+		{Name: "app1", Limit: 20, Target: "app"},
+		{Name: "kafka", Limit: 20, Target: "component"},
+	}, r.ConcurrencyLimiters())
+
+	t.Run("component operation", func(t *testing.T) {
+		called := false
+		assert.NoError(t, r.ComponentOperation("kafka", func() error {
+			called = true
+			assert.Equal(t, 1, r.ComponentConcurrencyLimiter("kafka").Status().InFlight)
+			return nil
+		}))
+		assert.True(t, called)
+		assert.Equal(t, 0, r.ComponentConcurrencyLimiter("kafka").Status().InFlight)
+
+		// The operations on the components without a limiter are always run.
+		assert.NoError(t, r.ComponentOperation("redis", func() error { return nil }))
+
+		r.ComponentConcurrencyLimiter("kafka").limit = 0
+		err := r.ComponentOperation("kafka", func() error {
+			t.Fatal("operation run over the limit")
+			return nil
+		})
+		assert.True(t, errors.Is(err, ErrConcurrencyLimitExceeded))
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		for _, c := range []config.ConcurrencyPolicySpec{
+			{LatencyThreshold: "abc"},
+			{LatencyThreshold: "0s"},
+			{LatencyThreshold: "1s", MinLimit: 10, MaxLimit: 5},
+			{LatencyThreshold: "1s", InitialLimit: 50, MaxLimit: 10},
+			{LatencyThreshold: "1s", BackoffRatio: 1.5},
+		} {
+			_, err := FromConfiguration(config.ResiliencySpec{
+				Policies: config.ResiliencyPolicies{
+					Concurrency: map[string]config.ConcurrencyPolicySpec{"c": c},
+				},
+			})
+			assert.Error(t, err)
+		}
+
+		_, err := FromConfiguration(config.ResiliencySpec{
+			Targets: config.ResiliencyTargets{
+				Components: map[string]config.ComponentResiliencyTarget{
+					"kafka": {Concurrency: "missing"},
+				},
+			},
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resiliency
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+const defaultFallbackContentType = "text/plain"
+
+// FallbackPolicy is a parsed fallback policy.
+// Exactly one of AppID, Component and Response is set.
+type FallbackPolicy struct {
+	AppID     string
+	Component string
+	Response  *StaticResponse
+}
+
+// StaticResponse is the static response returned by a fallback.
+type StaticResponse struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+func parseFallbackPolicy(spec config.FallbackPolicySpec) (*FallbackPolicy, error) {
+	actions := 0
+	for _, set := range []bool{spec.AppID != "", spec.Component != "", spec.Response != nil} {
+		if set {
+			actions++
+		}
+	}
+	if actions != 1 {
+		return nil, errors.New("exactly one of appId, component and response must be set")
+	}
+
+	policy := &FallbackPolicy{
+		AppID:     spec.AppID,
+		Component: spec.Component,
+	}
+	if spec.Response != nil {
+		statusCode := spec.Response.StatusCode
+		if statusCode == 0 {
+			statusCode = http.StatusOK
+		}
+		if statusCode < 100 || statusCode > 599 {
+			return nil, errors.Errorf("invalid response status code %d", statusCode)
+		}
+		contentType := spec.Response.ContentType
+		if contentType == "" {
+			contentType = defaultFallbackContentType
+		}
+		policy.Response = &StaticResponse{
+			StatusCode:  statusCode,
+			ContentType: contentType,
+			Body:        []byte(spec.Response.Body),
+		}
+	}
+	return policy, nil
+}
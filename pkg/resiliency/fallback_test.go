@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resiliency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func TestFallbackPolicies(t *testing.T) {
+	r, err := FromConfiguration(config.ResiliencySpec{
+		Policies: config.ResiliencyPolicies{
+			Fallbacks: map[string]config.FallbackPolicySpec{
+				"toApp2":      {AppID: "app2"},
+				"toSecondary": {Component: "secondary"},
+				"static":      {Response: &config.StaticResponseSpec{Body: `{"status":"degraded"}`}},
+			},
+		},
+		Targets: config.ResiliencyTargets{
+			Apps: map[string]config.AppResiliencyTarget{
+				"app1": {Fallback: "toApp2"},
+				"app3": {Fallback: "static"},
+			},
+			Components: map[string]config.ComponentResiliencyTarget{
+				"primary": {Fallback: "toSecondary"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &FallbackPolicy{AppID: "app2"}, r.AppFallback("app1"))
+	assert.Equal(t, &FallbackPolicy{Response: &StaticResponse{
+		StatusCode:  200,
+		ContentType: "text/plain",
+		Body:        []byte(`{"status":"degraded"}`),
+	}}, r.AppFallback("app3"))
+	assert.Equal(t, &FallbackPolicy{Component: "secondary"}, r.ComponentFallback("primary"))
+	assert.Nil(t, r.AppFallback("app2"))
+	assert.Nil(t, r.ComponentFallback("secondary"))
+
+	t.Run("invalid fallback policy", func(t *testing.T) {
+		for _, f := range []config.FallbackPolicySpec{
+			{},
+			{AppID: "app2", Component: "secondary"},
+			{Response: &config.StaticResponseSpec{StatusCode: 42}},
+		} {
+			_, err := FromConfiguration(config.ResiliencySpec{
+				Policies: config.ResiliencyPolicies{
+					Fallbacks: map[string]config.FallbackPolicySpec{"fallback": f},
+				},
+			})
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("invalid fallback target", func(t *testing.T) {
+		for _, targets := range []config.ResiliencyTargets{
+			{Apps: map[string]config.AppResiliencyTarget{"app1": {Fallback: "unknown"}}},
+			{Apps: map[string]config.AppResiliencyTarget{"app2": {Fallback: "toApp2"}}},
+			{Apps: map[string]config.AppResiliencyTarget{"app1": {Fallback: "toSecondary"}}},
+			{Components: map[string]config.ComponentResiliencyTarget{"secondary": {Fallback: "toSecondary"}}},
+			{Components: map[string]config.ComponentResiliencyTarget{"primary": {Fallback: "toApp2"}}},
+		} {
+			_, err := FromConfiguration(config.ResiliencySpec{
+				Policies: config.ResiliencyPolicies{
+					Fallbacks: map[string]config.FallbackPolicySpec{
+						"toApp2":      {AppID: "app2"},
+						"toSecondary": {Component: "secondary"},
+					},
+				},
+				Targets: targets,
+			})
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("nil resiliency", func(t *testing.T) {
+		var r *Resiliency
+		assert.Nil(t, r.AppFallback("app1"))
+		assert.Nil(t, r.ComponentFallback("primary"))
+	})
+}
@@ -0,0 +1,704 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resiliency
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/kit/logger"
+)
+
+var log = logger.NewLogger("dapr.resiliency")
+
+const defaultHedgingMaxAttempts = 2
+
+// RetryPolicy is a parsed retry policy.
+type RetryPolicy struct {
+	Interval   time.Duration
+	MaxRetries int
+}
+
+// HedgingPolicy is a parsed hedged request policy.
+type HedgingPolicy struct {
+	Delay       time.Duration
+	MaxAttempts int
+}
+
+// MirroringPolicy is a parsed request mirroring policy.
+type MirroringPolicy struct {
+	AppID      string
+	Percentage float64
+}
+
+// Resiliency holds the parsed resiliency policies and the policies applied to each target.
+// The policies can be replaced with Update while they're applied. A nil Resiliency applies no policies.
+type Resiliency struct {
+	lock     sync.RWMutex
+	policies *policies
+	// breakers holds the circuit breakers of the apps, by app ID.
+	breakers map[string]*CircuitBreaker
+	// appLimiters and componentLimiters hold the concurrency limiters of the apps and of the components.
+	appLimiters       map[string]*ConcurrencyLimiter
+	componentLimiters map[string]*ConcurrencyLimiter
+	// appBudgets and componentBudgets hold the retry budgets of the apps and of the components.
+	appBudgets       map[string]*RetryBudget
+	componentBudgets map[string]*RetryBudget
+}
+
+type policies struct {
+	// spec is the spec the policies were parsed from, which names the policies applied to the targets.
+	spec                  config.ResiliencySpec
+	timeouts              map[string]time.Duration
+	appTimeouts           map[string]time.Duration
+	retries               map[string]*RetryPolicy
+	componentRetries      map[string]*RetryPolicy
+	topicRetries          map[string]map[string]*RetryPolicy
+	hedging               map[string]*HedgingPolicy
+	appHedging            map[string]*HedgingPolicy
+	mirroring             map[string]*MirroringPolicy
+	appMirrors            map[string]*MirroringPolicy
+	appRoutes             map[string][]*routePolicies
+	circuitBreakers       map[string]*CircuitBreakerPolicy
+	appBreakers           map[string]*CircuitBreakerPolicy
+	concurrency           map[string]*ConcurrencyPolicy
+	appConcurrency        map[string]*ConcurrencyPolicy
+	componentConcurrency  map[string]*ConcurrencyPolicy
+	retryBudgets          map[string]*RetryBudgetPolicy
+	appRetryBudgets       map[string]*RetryBudgetPolicy
+	componentRetryBudgets map[string]*RetryBudgetPolicy
+	fallbacks             map[string]*FallbackPolicy
+	appFallbacks          map[string]*FallbackPolicy
+	componentFallbacks    map[string]*FallbackPolicy
+}
+
+// routePolicies are the policies applied to the invocations of the methods of an app matching pattern.
+type routePolicies struct {
+	method  string
+	pattern string
+	timeout time.Duration
+	hedging *HedgingPolicy
+}
+
+// FromConfiguration parses the resiliency spec of a configuration.
+func FromConfiguration(spec config.ResiliencySpec) (*Resiliency, error) {
+	p, err := parsePolicies(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &Resiliency{
+		policies:          p,
+		breakers:          p.newCircuitBreakers(nil),
+		appLimiters:       newConcurrencyLimiters(p.appConcurrency, nil),
+		componentLimiters: newConcurrencyLimiters(p.componentConcurrency, nil),
+		appBudgets:        newRetryBudgets(p.appRetryBudgets, nil),
+		componentBudgets:  newRetryBudgets(p.componentRetryBudgets, nil),
+	}, nil
+}
+
+// Update replaces the policies with the ones of the resiliency spec of a configuration.
+// The current policies are kept if spec is invalid.
+func (r *Resiliency) Update(spec config.ResiliencySpec) error {
+	p, err := parsePolicies(spec)
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	r.policies = p
+	r.breakers = p.newCircuitBreakers(r.breakers)
+	r.appLimiters = newConcurrencyLimiters(p.appConcurrency, r.appLimiters)
+	r.componentLimiters = newConcurrencyLimiters(p.componentConcurrency, r.componentLimiters)
+	r.appBudgets = newRetryBudgets(p.appRetryBudgets, r.appBudgets)
+	r.componentBudgets = newRetryBudgets(p.componentRetryBudgets, r.componentBudgets)
+	r.lock.Unlock()
+	return nil
+}
+
+func (r *Resiliency) current() *policies {
+	if r == nil {
+		return nil
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.policies
+}
+
+func parsePolicies(spec config.ResiliencySpec) (*policies, error) {
+	r := &policies{
+		spec:                  spec,
+		timeouts:              map[string]time.Duration{},
+		appTimeouts:           map[string]time.Duration{},
+		retries:               map[string]*RetryPolicy{},
+		componentRetries:      map[string]*RetryPolicy{},
+		topicRetries:          map[string]map[string]*RetryPolicy{},
+		hedging:               map[string]*HedgingPolicy{},
+		appHedging:            map[string]*HedgingPolicy{},
+		mirroring:             map[string]*MirroringPolicy{},
+		appMirrors:            map[string]*MirroringPolicy{},
+		appRoutes:             map[string][]*routePolicies{},
+		circuitBreakers:       map[string]*CircuitBreakerPolicy{},
+		appBreakers:           map[string]*CircuitBreakerPolicy{},
+		concurrency:           map[string]*ConcurrencyPolicy{},
+		appConcurrency:        map[string]*ConcurrencyPolicy{},
+		componentConcurrency:  map[string]*ConcurrencyPolicy{},
+		retryBudgets:          map[string]*RetryBudgetPolicy{},
+		appRetryBudgets:       map[string]*RetryBudgetPolicy{},
+		componentRetryBudgets: map[string]*RetryBudgetPolicy{},
+		fallbacks:             map[string]*FallbackPolicy{},
+		appFallbacks:          map[string]*FallbackPolicy{},
+		componentFallbacks:    map[string]*FallbackPolicy{},
+	}
+
+	for name, t := range spec.Policies.Timeouts {
+		timeout, err := time.ParseDuration(t)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid timeout policy %s", name)
+		}
+		if timeout <= 0 {
+			return nil, errors.Errorf("invalid timeout policy %s: timeout must be greater than zero", name)
+		}
+		r.timeouts[name] = timeout
+	}
+
+	for name, rs := range spec.Policies.Retries {
+		policy, err := parseRetryPolicy(rs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid retry policy %s", name)
+		}
+		r.retries[name] = policy
+	}
+
+	for name, h := range spec.Policies.Hedging {
+		policy, err := parseHedgingPolicy(h)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid hedging policy %s", name)
+		}
+		r.hedging[name] = policy
+	}
+
+	for name, m := range spec.Policies.Mirroring {
+		policy, err := parseMirroringPolicy(m)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid mirroring policy %s", name)
+		}
+		r.mirroring[name] = policy
+	}
+
+	for name, cb := range spec.Policies.CircuitBreakers {
+		policy, err := parseCircuitBreakerPolicy(cb)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid circuit breaker policy %s", name)
+		}
+		r.circuitBreakers[name] = policy
+	}
+
+	for name, c := range spec.Policies.Concurrency {
+		policy, err := parseConcurrencyPolicy(c)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid concurrency policy %s", name)
+		}
+		r.concurrency[name] = policy
+	}
+
+	for name, rb := range spec.Policies.RetryBudgets {
+		policy, err := parseRetryBudgetPolicy(rb)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid retry budget %s", name)
+		}
+		r.retryBudgets[name] = policy
+	}
+
+	for name, f := range spec.Policies.Fallbacks {
+		policy, err := parseFallbackPolicy(f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid fallback policy %s", name)
+		}
+		r.fallbacks[name] = policy
+	}
+
+	for appID, target := range spec.Targets.Apps {
+		if target.Timeout != "" {
+			timeout, ok := r.timeouts[target.Timeout]
+			if !ok {
+				return nil, errors.Errorf("app %s references unknown timeout policy %s", appID, target.Timeout)
+			}
+			r.appTimeouts[appID] = timeout
+		}
+		if target.Hedging != "" {
+			policy, ok := r.hedging[target.Hedging]
+			if !ok {
+				return nil, errors.Errorf("app %s references unknown hedging policy %s", appID, target.Hedging)
+			}
+			r.appHedging[appID] = policy
+		}
+		if target.Mirroring != "" {
+			policy, ok := r.mirroring[target.Mirroring]
+			if !ok {
+				return nil, errors.Errorf("app %s references unknown mirroring policy %s", appID, target.Mirroring)
+			}
+			if policy.AppID == appID {
+				return nil, errors.Errorf("app %s cannot be mirrored to itself", appID)
+			}
+			r.appMirrors[appID] = policy
+		}
+		if target.CircuitBreaker != "" {
+			policy, ok := r.circuitBreakers[target.CircuitBreaker]
+			if !ok {
+				return nil, errors.Errorf("app %s references unknown circuit breaker policy %s", appID, target.CircuitBreaker)
+			}
+			r.appBreakers[appID] = policy
+		}
+		if target.Concurrency != "" {
+			policy, ok := r.concurrency[target.Concurrency]
+			if !ok {
+				return nil, errors.Errorf("app %s references unknown concurrency policy %s", appID, target.Concurrency)
+			}
+			r.appConcurrency[appID] = policy
+		}
+		if target.RetryBudget != "" {
+			policy, ok := r.retryBudgets[target.RetryBudget]
+			if !ok {
+				return nil, errors.Errorf("app %s references unknown retry budget %s", appID, target.RetryBudget)
+			}
+			r.appRetryBudgets[appID] = policy
+		}
+		if target.Fallback != "" {
+			policy, ok := r.fallbacks[target.Fallback]
+			if !ok {
+				return nil, errors.Errorf("app %s references unknown fallback policy %s", appID, target.Fallback)
+			}
+			if policy.Component != "" {
+				return nil, errors.Errorf("app %s cannot fall back to component %s", appID, policy.Component)
+			}
+			if policy.AppID == appID {
+				return nil, errors.Errorf("app %s cannot fall back to itself", appID)
+			}
+			r.appFallbacks[appID] = policy
+		}
+
+		for method, route := range target.Routes {
+			rp, err := r.parseRoutePolicies(method, route)
+			if err != nil {
+				return nil, errors.Wrapf(err, "app %s route %s", appID, method)
+			}
+			r.appRoutes[appID] = append(r.appRoutes[appID], rp)
+		}
+		sortRoutes(r.appRoutes[appID])
+	}
+
+	for name, target := range spec.Targets.Components {
+		if target.Retry != "" {
+			policy, ok := r.retries[target.Retry]
+			if !ok {
+				return nil, errors.Errorf("component %s references unknown retry policy %s", name, target.Retry)
+			}
+			r.componentRetries[name] = policy
+		}
+		if target.Concurrency != "" {
+			policy, ok := r.concurrency[target.Concurrency]
+			if !ok {
+				return nil, errors.Errorf("component %s references unknown concurrency policy %s", name, target.Concurrency)
+			}
+			r.componentConcurrency[name] = policy
+		}
+		if target.RetryBudget != "" {
+			policy, ok := r.retryBudgets[target.RetryBudget]
+			if !ok {
+				return nil, errors.Errorf("component %s references unknown retry budget %s", name, target.RetryBudget)
+			}
+			r.componentRetryBudgets[name] = policy
+		}
+		if target.Fallback != "" {
+			policy, ok := r.fallbacks[target.Fallback]
+			if !ok {
+				return nil, errors.Errorf("component %s references unknown fallback policy %s", name, target.Fallback)
+			}
+			if policy.AppID != "" {
+				return nil, errors.Errorf("component %s cannot fall back to app %s", name, policy.AppID)
+			}
+			if policy.Component == name {
+				return nil, errors.Errorf("component %s cannot fall back to itself", name)
+			}
+			r.componentFallbacks[name] = policy
+		}
+
+		for topic, t := range target.Topics {
+			if t.Retry == "" {
+				continue
+			}
+			policy, ok := r.retries[t.Retry]
+			if !ok {
+				return nil, errors.Errorf("topic %s of component %s references unknown retry policy %s", topic, name, t.Retry)
+			}
+			if r.topicRetries[name] == nil {
+				r.topicRetries[name] = map[string]*RetryPolicy{}
+			}
+			r.topicRetries[name][topic] = policy
+		}
+	}
+
+	return r, nil
+}
+
+// newCircuitBreakers returns the circuit breakers of the apps. The circuit breakers of previous whose policy is
+// unchanged are kept with their state.
+func (r *policies) newCircuitBreakers(previous map[string]*CircuitBreaker) map[string]*CircuitBreaker {
+	breakers := make(map[string]*CircuitBreaker, len(r.appBreakers))
+	for appID, policy := range r.appBreakers {
+		if b, ok := previous[appID]; ok && *b.policy == *policy {
+			breakers[appID] = b
+			continue
+		}
+		breakers[appID] = newCircuitBreaker(appID, policy)
+	}
+	return breakers
+}
+
+// newConcurrencyLimiters returns the concurrency limiters of the targets with a concurrency policy. The limiters of
+// previous whose policy is unchanged are kept with their limit.
+func newConcurrencyLimiters(targets map[string]*ConcurrencyPolicy, previous map[string]*ConcurrencyLimiter) map[string]*ConcurrencyLimiter {
+	limiters := make(map[string]*ConcurrencyLimiter, len(targets))
+	for name, policy := range targets {
+		if l, ok := previous[name]; ok && *l.policy == *policy {
+			limiters[name] = l
+			continue
+		}
+		limiters[name] = newConcurrencyLimiter(name, policy)
+	}
+	return limiters
+}
+
+// newRetryBudgets returns the retry budgets of the targets with a retry budget. The budgets of previous whose policy
+// is unchanged are kept with their requests and retries.
+func newRetryBudgets(targets map[string]*RetryBudgetPolicy, previous map[string]*RetryBudget) map[string]*RetryBudget {
+	budgets := make(map[string]*RetryBudget, len(targets))
+	for name, policy := range targets {
+		if b, ok := previous[name]; ok && *b.policy == *policy {
+			budgets[name] = b
+			continue
+		}
+		budgets[name] = newRetryBudget(name, policy)
+	}
+	return budgets
+}
+
+func (r *policies) parseRoutePolicies(method string, target config.RouteResiliencyTarget) (*routePolicies, error) {
+	pattern := strings.TrimPrefix(method, "/")
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, errors.Wrap(err, "invalid method pattern")
+	}
+
+	rp := &routePolicies{method: method, pattern: pattern}
+	if target.Timeout != "" {
+		timeout, ok := r.timeouts[target.Timeout]
+		if !ok {
+			return nil, errors.Errorf("unknown timeout policy %s", target.Timeout)
+		}
+		rp.timeout = timeout
+	}
+	if target.Hedging != "" {
+		policy, ok := r.hedging[target.Hedging]
+		if !ok {
+			return nil, errors.Errorf("unknown hedging policy %s", target.Hedging)
+		}
+		rp.hedging = policy
+	}
+	return rp, nil
+}
+
+// sortRoutes sorts the routes of an app so the first route matching a method is the most specific one: the exact
+// methods first, then the longest patterns.
+func sortRoutes(routes []*routePolicies) {
+	sort.Slice(routes, func(i, j int) bool {
+		iExact, jExact := !hasMeta(routes[i].pattern), !hasMeta(routes[j].pattern)
+		if iExact != jExact {
+			return iExact
+		}
+		if len(routes[i].pattern) != len(routes[j].pattern) {
+			return len(routes[i].pattern) > len(routes[j].pattern)
+		}
+		return routes[i].pattern < routes[j].pattern
+	})
+}
+
+func hasMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
+
+// route returns the policies of the most specific route of the app matching method, if any.
+func (r *policies) route(appID, method string) *routePolicies {
+	method = strings.TrimPrefix(method, "/")
+	for _, route := range r.appRoutes[appID] {
+		if ok, _ := path.Match(route.pattern, method); ok {
+			return route
+		}
+	}
+	return nil
+}
+
+// AppTimeout returns the timeout applied to invocations of the given method of the given app, or zero if there is
+// none. The timeout of the route of the method overrides the one of the app.
+func (r *Resiliency) AppTimeout(appID, method string) time.Duration {
+	p := r.current()
+	if p == nil {
+		return 0
+	}
+	if route := p.route(appID, method); route != nil && route.timeout > 0 {
+		return route.timeout
+	}
+	return p.appTimeouts[appID]
+}
+
+// ComponentRetryPolicy returns the retry policy applied to the given component, if any.
+func (r *Resiliency) ComponentRetryPolicy(name string) *RetryPolicy {
+	p := r.current()
+	if p == nil {
+		return nil
+	}
+	return p.componentRetries[name]
+}
+
+// TopicRetryPolicy returns the retry policy applied to the delivery of the events of the given topic of the given
+// pubsub, if any. The retry policy of the pubsub applies to its operations only, so the events of the topics without
+// a retry policy aren't retried.
+func (r *Resiliency) TopicRetryPolicy(pubsubName, topic string) *RetryPolicy {
+	p := r.current()
+	if p == nil {
+		return nil
+	}
+	return p.topicRetries[pubsubName][topic]
+}
+
+// AppHedgingPolicy returns the hedging policy applied to invocations of the given method of the given app, if any.
+// The hedging policy of the route of the method overrides the one of the app.
+func (r *Resiliency) AppHedgingPolicy(appID, method string) *HedgingPolicy {
+	p := r.current()
+	if p == nil {
+		return nil
+	}
+	if route := p.route(appID, method); route != nil && route.hedging != nil {
+		return route.hedging
+	}
+	return p.appHedging[appID]
+}
+
+// AppMirroringPolicy returns the mirroring policy applied to invocations of the given app, if any.
+func (r *Resiliency) AppMirroringPolicy(appID string) *MirroringPolicy {
+	p := r.current()
+	if p == nil {
+		return nil
+	}
+	return p.appMirrors[appID]
+}
+
+// AppFallback returns the fallback of the invocations of the given app failing once their retries are exhausted, if
+// any. The fallback either invokes another app or returns a static response.
+func (r *Resiliency) AppFallback(appID string) *FallbackPolicy {
+	p := r.current()
+	if p == nil {
+		return nil
+	}
+	return p.appFallbacks[appID]
+}
+
+// ComponentFallback returns the fallback of the operations on the given component failing once their retries are
+// exhausted, if any. The fallback either sends the operations to another component of the same type or returns a
+// static response.
+func (r *Resiliency) ComponentFallback(name string) *FallbackPolicy {
+	p := r.current()
+	if p == nil {
+		return nil
+	}
+	return p.componentFallbacks[name]
+}
+
+// AppCircuitBreaker returns the circuit breaker of invocations of the given app, if any.
+func (r *Resiliency) AppCircuitBreaker(appID string) *CircuitBreaker {
+	if r == nil {
+		return nil
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.breakers[appID]
+}
+
+// AppConcurrencyLimiter returns the concurrency limiter of invocations of the given app, if any.
+func (r *Resiliency) AppConcurrencyLimiter(appID string) *ConcurrencyLimiter {
+	if r == nil {
+		return nil
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.appLimiters[appID]
+}
+
+// ComponentConcurrencyLimiter returns the concurrency limiter of operations on the given component, if any.
+func (r *Resiliency) ComponentConcurrencyLimiter(name string) *ConcurrencyLimiter {
+	if r == nil {
+		return nil
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.componentLimiters[name]
+}
+
+// ComponentOperation runs an operation on the given component within the concurrency limiter of the component, if
+// any. The operation isn't run when the limiter rejects it.
+func (r *Resiliency) ComponentOperation(name string, operation func() error) error {
+	release, err := r.ComponentConcurrencyLimiter(name).Acquire()
+	if err != nil {
+		return errors.Wrapf(err, "component %s", name)
+	}
+	err = operation()
+	release(err)
+	return err
+}
+
+// AppRetryBudget returns the retry budget of invocations of the given app, if any.
+func (r *Resiliency) AppRetryBudget(appID string) *RetryBudget {
+	if r == nil {
+		return nil
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.appBudgets[appID]
+}
+
+// ComponentRetryBudget returns the retry budget of the given component, shared by the retries of its operations and
+// of the delivery of its events, if any.
+func (r *Resiliency) ComponentRetryBudget(name string) *RetryBudget {
+	if r == nil {
+		return nil
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.componentBudgets[name]
+}
+
+// CircuitBreakers returns the status of the circuit breakers, sorted by name.
+func (r *Resiliency) CircuitBreakers() []CircuitBreakerStatus {
+	if r == nil {
+		return nil
+	}
+
+	r.lock.RLock()
+	statuses := make([]CircuitBreakerStatus, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		statuses = append(statuses, b.Status())
+	}
+	r.lock.RUnlock()
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// ConcurrencyLimiters returns the statuses of the concurrency limiters of the apps and components, sorted by target
+// and name.
+func (r *Resiliency) ConcurrencyLimiters() []ConcurrencyStatus {
+	if r == nil {
+		return nil
+	}
+
+	r.lock.RLock()
+	statuses := make([]ConcurrencyStatus, 0, len(r.appLimiters)+len(r.componentLimiters))
+	for _, l := range r.appLimiters {
+		status := l.Status()
+		status.Target = "app"
+		statuses = append(statuses, status)
+	}
+	for _, l := range r.componentLimiters {
+		status := l.Status()
+		status.Target = "component"
+		statuses = append(statuses, status)
+	}
+	r.lock.RUnlock()
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Target != statuses[j].Target {
+			return statuses[i].Target < statuses[j].Target
+		}
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+func parseRetryPolicy(spec config.RetryPolicySpec) (*RetryPolicy, error) {
+	interval, err := time.ParseDuration(spec.Interval)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid interval")
+	}
+	if interval < 0 {
+		return nil, errors.New("interval must not be negative")
+	}
+	if spec.MaxRetries < 0 {
+		return nil, errors.New("maxRetries must not be negative")
+	}
+
+	return &RetryPolicy{
+		Interval:   interval,
+		MaxRetries: spec.MaxRetries,
+	}, nil
+}
+
+func parseHedgingPolicy(spec config.HedgingPolicySpec) (*HedgingPolicy, error) {
+	delay, err := time.ParseDuration(spec.Delay)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid delay")
+	}
+	if delay <= 0 {
+		return nil, errors.New("delay must be greater than zero")
+	}
+
+	maxAttempts := spec.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = defaultHedgingMaxAttempts
+	}
+	if maxAttempts < 2 {
+		return nil, errors.New("maxAttempts must be at least 2")
+	}
+
+	return &HedgingPolicy{
+		Delay:       delay,
+		MaxAttempts: maxAttempts,
+	}, nil
+}
+
+func parseMirroringPolicy(spec config.MirroringPolicySpec) (*MirroringPolicy, error) {
+	if spec.AppID == "" {
+		return nil, errors.New("appId is required")
+	}
+	if spec.Percentage <= 0 || spec.Percentage > 100 {
+		return nil, errors.New("percentage must be greater than 0 and at most 100")
+	}
+
+	return &MirroringPolicy{
+		AppID:      spec.AppID,
+		Percentage: spec.Percentage,
+	}, nil
+}
@@ -0,0 +1,341 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resiliency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func TestFromConfiguration(t *testing.T) {
+	t.Run("hedging policy applied to app", func(t *testing.T) {
+		r, err := FromConfiguration(config.ResiliencySpec{
+			Policies: config.ResiliencyPolicies{
+				Hedging: map[string]config.HedgingPolicySpec{
+					"fast": {Delay: "50ms"},
+				},
+			},
+			Targets: config.ResiliencyTargets{
+				Apps: map[string]config.AppResiliencyTarget{
+					"app1": {Hedging: "fast"},
+				},
+			},
+		})
+		assert.NoError(t, err)
+
+		policy := r.AppHedgingPolicy("app1", "method")
+		assert.NotNil(t, policy)
+		assert.Equal(t, 50*time.Millisecond, policy.Delay)
+		assert.Equal(t, 2, policy.MaxAttempts)
+		assert.Nil(t, r.AppHedgingPolicy("app2", "method"))
+	})
+
+	t.Run("timeout policy applied to app", func(t *testing.T) {
+		r, err := FromConfiguration(config.ResiliencySpec{
+			Policies: config.ResiliencyPolicies{
+				Timeouts: map[string]string{
+					"short": "2s",
+				},
+			},
+			Targets: config.ResiliencyTargets{
+				Apps: map[string]config.AppResiliencyTarget{
+					"app1": {Timeout: "short"},
+				},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2*time.Second, r.AppTimeout("app1", "method"))
+		assert.Equal(t, time.Duration(0), r.AppTimeout("app2", "method"))
+	})
+
+	t.Run("invalid timeout policy", func(t *testing.T) {
+		for _, timeout := range []string{"abc", "0s"} {
+			_, err := FromConfiguration(config.ResiliencySpec{
+				Policies: config.ResiliencyPolicies{
+					Timeouts: map[string]string{"t": timeout},
+				},
+			})
+			assert.Error(t, err)
+		}
+
+		_, err := FromConfiguration(config.ResiliencySpec{
+			Targets: config.ResiliencyTargets{
+				Apps: map[string]config.AppResiliencyTarget{
+					"app1": {Timeout: "missing"},
+				},
+			},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown policy", func(t *testing.T) {
+		_, err := FromConfiguration(config.ResiliencySpec{
+			Targets: config.ResiliencyTargets{
+				Apps: map[string]config.AppResiliencyTarget{
+					"app1": {Hedging: "missing"},
+				},
+			},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid hedging policy", func(t *testing.T) {
+		for _, spec := range []config.HedgingPolicySpec{
+			{Delay: "abc"},
+			{Delay: "0s"},
+			{Delay: "1s", MaxAttempts: 1},
+		} {
+			_, err := FromConfiguration(config.ResiliencySpec{
+				Policies: config.ResiliencyPolicies{
+					Hedging: map[string]config.HedgingPolicySpec{"p": spec},
+				},
+			})
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("mirroring policy applied to app", func(t *testing.T) {
+		r, err := FromConfiguration(config.ResiliencySpec{
+			Policies: config.ResiliencyPolicies{
+				Mirroring: map[string]config.MirroringPolicySpec{
+					"shadow": {AppID: "app1-v2", Percentage: 10},
+				},
+			},
+			Targets: config.ResiliencyTargets{
+				Apps: map[string]config.AppResiliencyTarget{
+					"app1": {Mirroring: "shadow"},
+				},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, &MirroringPolicy{AppID: "app1-v2", Percentage: 10}, r.AppMirroringPolicy("app1"))
+		assert.Nil(t, r.AppMirroringPolicy("app2"))
+	})
+
+	t.Run("invalid mirroring policy", func(t *testing.T) {
+		for _, spec := range []config.MirroringPolicySpec{
+			{Percentage: 10},
+			{AppID: "app1-v2"},
+			{AppID: "app1-v2", Percentage: 101},
+		} {
+			_, err := FromConfiguration(config.ResiliencySpec{
+				Policies: config.ResiliencyPolicies{
+					Mirroring: map[string]config.MirroringPolicySpec{"p": spec},
+				},
+			})
+			assert.Error(t, err)
+		}
+
+		_, err := FromConfiguration(config.ResiliencySpec{
+			Policies: config.ResiliencyPolicies{
+				Mirroring: map[string]config.MirroringPolicySpec{
+					"self": {AppID: "app1", Percentage: 10},
+				},
+			},
+			Targets: config.ResiliencyTargets{
+				Apps: map[string]config.AppResiliencyTarget{
+					"app1": {Mirroring: "self"},
+				},
+			},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("retry policy applied to component", func(t *testing.T) {
+		r, err := FromConfiguration(config.ResiliencySpec{
+			Policies: config.ResiliencyPolicies{
+				Retries: map[string]config.RetryPolicySpec{
+					"three": {Interval: "1s", MaxRetries: 3},
+				},
+			},
+			Targets: config.ResiliencyTargets{
+				Components: map[string]config.ComponentResiliencyTarget{
+					"kafka": {Retry: "three"},
+				},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, &RetryPolicy{Interval: time.Second, MaxRetries: 3}, r.ComponentRetryPolicy("kafka"))
+		assert.Nil(t, r.ComponentRetryPolicy("rabbitmq"))
+	})
+
+	t.Run("invalid retry policy", func(t *testing.T) {
+		for _, spec := range []config.RetryPolicySpec{
+			{Interval: "abc"},
+			{Interval: "-1s"},
+			{Interval: "1s", MaxRetries: -1},
+		} {
+			_, err := FromConfiguration(config.ResiliencySpec{
+				Policies: config.ResiliencyPolicies{
+					Retries: map[string]config.RetryPolicySpec{"p": spec},
+				},
+			})
+			assert.Error(t, err)
+		}
+
+		_, err := FromConfiguration(config.ResiliencySpec{
+			Targets: config.ResiliencyTargets{
+				Components: map[string]config.ComponentResiliencyTarget{
+					"kafka": {Retry: "missing"},
+				},
+			},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("policies applied to routes", func(t *testing.T) {
+		r, err := FromConfiguration(config.ResiliencySpec{
+			Policies: config.ResiliencyPolicies{
+				Timeouts: map[string]string{
+					"short":  "2s",
+					"medium": "5s",
+					"long":   "1m",
+				},
+				Hedging: map[string]config.HedgingPolicySpec{
+					"fast": {Delay: "50ms"},
+				},
+			},
+			Targets: config.ResiliencyTargets{
+				Apps: map[string]config.AppResiliencyTarget{
+					"app1": {
+						Timeout: "short",
+						Routes: map[string]config.RouteResiliencyTarget{
+							"orders/*":      {Timeout: "medium", Hedging: "fast"},
+							"orders/export": {Timeout: "long"},
+						},
+					},
+				},
+			},
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, time.Minute, r.AppTimeout("app1", "orders/export"))
+		assert.Equal(t, 5*time.Second, r.AppTimeout("app1", "/orders/create"))
+		assert.Equal(t, 2*time.Second, r.AppTimeout("app1", "users"))
+		assert.Equal(t, 50*time.Millisecond, r.AppHedgingPolicy("app1", "orders/create").Delay)
+		// the most specific route doesn't set a hedging policy, so the one of the app applies.
+		assert.Nil(t, r.AppHedgingPolicy("app1", "orders/export"))
+		assert.Nil(t, r.AppHedgingPolicy("app1", "users"))
+	})
+
+	t.Run("invalid route", func(t *testing.T) {
+		for _, route := range []config.RouteResiliencyTarget{
+			{Timeout: "missing"},
+			{Hedging: "missing"},
+		} {
+			_, err := FromConfiguration(config.ResiliencySpec{
+				Targets: config.ResiliencyTargets{
+					Apps: map[string]config.AppResiliencyTarget{
+						"app1": {Routes: map[string]config.RouteResiliencyTarget{"orders": route}},
+					},
+				},
+			})
+			assert.Error(t, err)
+		}
+
+		_, err := FromConfiguration(config.ResiliencySpec{
+			Targets: config.ResiliencyTargets{
+				Apps: map[string]config.AppResiliencyTarget{
+					"app1": {Routes: map[string]config.RouteResiliencyTarget{"orders/[": {}}},
+				},
+			},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("retry policy applied to topics", func(t *testing.T) {
+		r, err := FromConfiguration(config.ResiliencySpec{
+			Policies: config.ResiliencyPolicies{
+				Retries: map[string]config.RetryPolicySpec{
+					"three": {Interval: "1s", MaxRetries: 3},
+					"ten":   {Interval: "100ms", MaxRetries: 10},
+				},
+			},
+			Targets: config.ResiliencyTargets{
+				Components: map[string]config.ComponentResiliencyTarget{
+					"kafka": {
+						Retry: "three",
+						Topics: map[string]config.TopicResiliencyTarget{
+							"orders": {Retry: "ten"},
+						},
+					},
+				},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, &RetryPolicy{Interval: 100 * time.Millisecond, MaxRetries: 10}, r.TopicRetryPolicy("kafka", "orders"))
+		assert.Nil(t, r.TopicRetryPolicy("kafka", "users"), "the retry policy of the pubsub doesn't apply to its topics")
+		assert.Equal(t, &RetryPolicy{Interval: time.Second, MaxRetries: 3}, r.ComponentRetryPolicy("kafka"))
+		assert.Nil(t, r.TopicRetryPolicy("rabbitmq", "orders"))
+
+		_, err = FromConfiguration(config.ResiliencySpec{
+			Targets: config.ResiliencyTargets{
+				Components: map[string]config.ComponentResiliencyTarget{
+					"kafka": {Topics: map[string]config.TopicResiliencyTarget{"orders": {Retry: "missing"}}},
+				},
+			},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("nil resiliency", func(t *testing.T) {
+		var r *Resiliency
+		assert.Nil(t, r.ComponentRetryPolicy("kafka"))
+		assert.Nil(t, r.TopicRetryPolicy("kafka", "orders"))
+		assert.Nil(t, r.AppMirroringPolicy("app1"))
+		assert.Nil(t, r.AppHedgingPolicy("app1", "method"))
+		assert.Equal(t, time.Duration(0), r.AppTimeout("app1", "method"))
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	r, err := FromConfiguration(config.ResiliencySpec{
+		Policies: config.ResiliencyPolicies{
+			Timeouts: map[string]string{"short": "2s"},
+		},
+		Targets: config.ResiliencyTargets{
+			Apps: map[string]config.AppResiliencyTarget{
+				"app1": {Timeout: "short"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, r.Update(config.ResiliencySpec{
+		Policies: config.ResiliencyPolicies{
+			Timeouts: map[string]string{"long": "1m"},
+		},
+		Targets: config.ResiliencyTargets{
+			Apps: map[string]config.AppResiliencyTarget{
+				"app2": {Timeout: "long"},
+			},
+		},
+	}))
+	assert.Equal(t, time.Duration(0), r.AppTimeout("app1", "method"))
+	assert.Equal(t, time.Minute, r.AppTimeout("app2", "method"))
+
+	// the policies are kept when the update is invalid.
+	assert.Error(t, r.Update(config.ResiliencySpec{
+		Targets: config.ResiliencyTargets{
+			Apps: map[string]config.AppResiliencyTarget{
+				"app1": {Timeout: "missing"},
+			},
+		},
+	}))
+	assert.Equal(t, time.Minute, r.AppTimeout("app2", "method"))
+}
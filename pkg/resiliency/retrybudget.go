@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resiliency
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+const (
+	defaultRetryBudgetMinRetriesPerSecond = 10
+	defaultRetryBudgetTTL                 = 10 * time.Second
+)
+
+// RetryBudgetPolicy is a parsed retry budget.
+type RetryBudgetPolicy struct {
+	Ratio               float64
+	MinRetriesPerSecond int
+	TTL                 time.Duration
+}
+
+// RetryBudget caps the retries to a target to a ratio of the requests sent to it over the TTL of its policy, plus
+// MinRetriesPerSecond retries per second so the targets receiving few requests can still be retried. It's shared by
+// all the retries to the target.
+// A nil RetryBudget allows all the retries.
+type RetryBudget struct {
+	name   string
+	policy *RetryBudgetPolicy
+
+	lock sync.Mutex
+	// buckets count the requests and the retries of each second of the TTL.
+	buckets []retryBudgetBucket
+	now     func() time.Time
+}
+
+type retryBudgetBucket struct {
+	second   int64
+	requests int
+	retries  int
+}
+
+func newRetryBudget(name string, policy *RetryBudgetPolicy) *RetryBudget {
+	seconds := int(math.Ceil(policy.TTL.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return &RetryBudget{
+		name:    name,
+		policy:  policy,
+		buckets: make([]retryBudgetBucket, seconds),
+		now:     time.Now,
+	}
+}
+
+// Request records a request to the target, which is not a retry.
+func (b *RetryBudget) Request() {
+	if b == nil {
+		return
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.bucket(b.now().Unix()).requests++
+}
+
+// TryRetry withdraws a retry from the budget, and returns false if the budget is exhausted, in which case the
+// request must not be retried.
+func (b *RetryBudget) TryRetry() bool {
+	if b == nil {
+		return true
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	now := b.now().Unix()
+	var requests, retries int
+	for _, bucket := range b.buckets {
+		if bucket.second > now-int64(len(b.buckets)) {
+			requests += bucket.requests
+			retries += bucket.retries
+		}
+	}
+	allowed := float64(requests)*b.policy.Ratio + float64(b.policy.MinRetriesPerSecond*len(b.buckets))
+	if float64(retries+1) > allowed {
+		log.Debugf("retry budget of %s is exhausted: %d retries for %d requests", b.name, retries, requests)
+		return false
+	}
+	b.bucket(now).retries++
+	return true
+}
+
+func (b *RetryBudget) bucket(second int64) *retryBudgetBucket {
+	bucket := &b.buckets[second%int64(len(b.buckets))]
+	if bucket.second != second {
+		*bucket = retryBudgetBucket{second: second}
+	}
+	return bucket
+}
+
+func parseRetryBudgetPolicy(spec config.RetryBudgetSpec) (*RetryBudgetPolicy, error) {
+	if spec.Ratio <= 0 {
+		return nil, errors.New("ratio must be greater than zero")
+	}
+	if spec.MinRetriesPerSecond < 0 {
+		return nil, errors.New("minRetriesPerSecond must not be negative")
+	}
+
+	policy := &RetryBudgetPolicy{
+		Ratio:               spec.Ratio,
+		MinRetriesPerSecond: spec.MinRetriesPerSecond,
+		TTL:                 defaultRetryBudgetTTL,
+	}
+	if policy.MinRetriesPerSecond == 0 {
+		policy.MinRetriesPerSecond = defaultRetryBudgetMinRetriesPerSecond
+	}
+	if spec.TTL != "" {
+		ttl, err := time.ParseDuration(spec.TTL)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid ttl")
+		}
+		if ttl < time.Second {
+			return nil, errors.New("ttl must be at least 1s")
+		}
+		policy.TTL = ttl
+	}
+	return policy, nil
+}
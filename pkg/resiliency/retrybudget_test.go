@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resiliency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func TestRetryBudget(t *testing.T) {
+	now := time.Unix(1000, 0)
+	b := newRetryBudget("app1", &RetryBudgetPolicy{Ratio: 0.2, MinRetriesPerSecond: 1, TTL: 2 * time.Second})
+	b.now = func() time.Time { return now }
+
+	// the minimum retries are allowed without requests.
+	assert.True(t, b.TryRetry())
+	assert.True(t, b.TryRetry())
+	assert.False(t, b.TryRetry())
+
+	// 10 requests allow 2 more retries.
+	for i := 0; i < 10; i++ {
+		b.Request()
+	}
+	assert.True(t, b.TryRetry())
+	assert.True(t, b.TryRetry())
+	assert.False(t, b.TryRetry())
+
+	// the requests and retries expire after the TTL.
+	now = now.Add(2 * time.Second)
+	assert.True(t, b.TryRetry())
+	assert.True(t, b.TryRetry())
+	assert.False(t, b.TryRetry())
+
+	t.Run("nil retry budget", func(t *testing.T) {
+		var b *RetryBudget
+		b.Request()
+		assert.True(t, b.TryRetry())
+	})
+}
+
+func TestRetryBudgetPolicies(t *testing.T) {
+	r, err := FromConfiguration(config.ResiliencySpec{
+		Policies: config.ResiliencyPolicies{
+			RetryBudgets: map[string]config.RetryBudgetSpec{
+				"budget": {Ratio: 0.2},
+			},
+		},
+		Targets: config.ResiliencyTargets{
+			Apps: map[string]config.AppResiliencyTarget{
+				"app1": {RetryBudget: "budget"},
+			},
+			Components: map[string]config.ComponentResiliencyTarget{
+				"kafka": {RetryBudget: "budget"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &RetryBudgetPolicy{Ratio: 0.2, MinRetriesPerSecond: 10, TTL: 10 * time.Second}, r.AppRetryBudget("app1").policy)
+	assert.NotNil(t, r.ComponentRetryBudget("kafka"))
+	assert.Nil(t, r.AppRetryBudget("app2"))
+	assert.Nil(t, r.ComponentRetryBudget("app1"))
+
+	t.Run("invalid retry budget", func(t *testing.T) {
+		for _, rb := range []config.RetryBudgetSpec{
+			{Ratio: 0},
+			{Ratio: 0.2, MinRetriesPerSecond: -1},
+			{Ratio: 0.2, TTL: "abc"},
+			{Ratio: 0.2, TTL: "100ms"},
+		} {
+			_, err := FromConfiguration(config.ResiliencySpec{
+				Policies: config.ResiliencyPolicies{
+					RetryBudgets: map[string]config.RetryBudgetSpec{"b": rb},
+				},
+			})
+			assert.Error(t, err)
+		}
+
+		_, err := FromConfiguration(config.ResiliencySpec{
+			Targets: config.ResiliencyTargets{
+				Apps: map[string]config.AppResiliencyTarget{
+					"app1": {RetryBudget: "missing"},
+				},
+			},
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resiliency
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+const (
+	// SimulatedSuccess is the result of a successful attempt.
+	SimulatedSuccess = "success"
+	// SimulatedError is the result of a failed attempt.
+	SimulatedError = "error"
+	// SimulatedRejected is the result of an attempt rejected by an open circuit breaker.
+	SimulatedRejected = "rejected"
+)
+
+var errSimulatedFailure = errors.New("simulated failure")
+
+// SimulationRequest describes hypothetical calls to an app, or to a component, whose handling by the resiliency
+// policies is simulated.
+// Errors are the outcomes of the successive attempts, true for a failed attempt. Interval, e.g. "1s", is the time
+// elapsed between an operation and the next one. Spec is the resiliency spec to simulate instead of the current one.
+type SimulationRequest struct {
+	App       string                 `json:"app,omitempty"`
+	Method    string                 `json:"method,omitempty"`
+	Component string                 `json:"component,omitempty"`
+	Topic     string                 `json:"topic,omitempty"`
+	Errors    []bool                 `json:"errors"`
+	Interval  string                 `json:"interval,omitempty"`
+	Spec      *config.ResiliencySpec `json:"spec,omitempty"`
+}
+
+// SimulationResult reports the policies matching the simulated target and how they handle each attempt.
+// Timeout is the timeout of the operations, if any.
+type SimulationResult struct {
+	Policies SimulatedPolicies `json:"policies"`
+	Timeout  string            `json:"timeout,omitempty"`
+	Steps    []SimulatedStep   `json:"steps"`
+}
+
+// SimulatedPolicies holds the names of the policies applied to the simulated target. Route is the route of the app
+// matching the invoked method, whose policies override the ones of the app.
+type SimulatedPolicies struct {
+	Route          string `json:"route,omitempty"`
+	Timeout        string `json:"timeout,omitempty"`
+	Retry          string `json:"retry,omitempty"`
+	Hedging        string `json:"hedging,omitempty"`
+	Mirroring      string `json:"mirroring,omitempty"`
+	CircuitBreaker string `json:"circuitBreaker,omitempty"`
+	Concurrency    string `json:"concurrency,omitempty"`
+	RetryBudget    string `json:"retryBudget,omitempty"`
+	Fallback       string `json:"fallback,omitempty"`
+}
+
+// SimulatedStep is the handling of an attempt of an operation, Attempt being 0 for the first attempt and the
+// retry number for the retries. Elapsed is the time elapsed since the first operation.
+// CircuitBreaker is the state of the circuit breaker after the attempt. RetryIn is the delay before the retry of a
+// failed attempt, and Fallback the fallback applied once the operation failed.
+// ConcurrencyLimit is the limit of the concurrency limiter after the attempt, and RetryBudgetExhausted is true for
+// the failed attempts not retried because the retry budget is exhausted.
+type SimulatedStep struct {
+	Operation      int                 `json:"operation"`
+	Attempt        int                 `json:"attempt"`
+	Elapsed        string              `json:"elapsed"`
+	Result         string              `json:"result"`
+	CircuitBreaker CircuitBreakerState `json:"circuitBreaker,omitempty"`
+	RetryIn        string              `json:"retryIn,omitempty"`
+	Fallback       string              `json:"fallback,omitempty"`
+
+	ConcurrencyLimit     int  `json:"concurrencyLimit,omitempty"`
+	RetryBudgetExhausted bool `json:"retryBudgetExhausted,omitempty"`
+}
+
+// Simulate reports the resiliency policies matching the target of req and how they handle the outcomes of its
+// attempts. The policies of req.Spec are simulated if it's set, the current ones otherwise.
+// The circuit breakers, concurrency limiters and retry budgets of the policies are simulated on a simulated clock,
+// without changing the state of the actual ones. The simulated operations are sequential, so they are never rejected
+// by the concurrency limiters, whose limit still follows their outcomes. The retries of the invocations of apps on
+// connection failures and the hedged attempts aren't simulated, and neither are the retry budgets of apps.
+func (r *Resiliency) Simulate(req SimulationRequest) (*SimulationResult, error) {
+	if (req.App == "") == (req.Component == "") {
+		return nil, errors.New("exactly one of app and component must be set")
+	}
+	var interval time.Duration
+	if req.Interval != "" {
+		var err error
+		if interval, err = time.ParseDuration(req.Interval); err != nil {
+			return nil, errors.Wrap(err, "invalid interval")
+		}
+		if interval < 0 {
+			return nil, errors.New("interval must not be negative")
+		}
+	}
+
+	p := r.current()
+	if req.Spec != nil || p == nil {
+		var spec config.ResiliencySpec
+		if req.Spec != nil {
+			spec = *req.Spec
+		}
+		var err error
+		if p, err = parsePolicies(spec); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.App != "" {
+		return p.simulateApp(req, interval), nil
+	}
+	return p.simulateComponent(req, interval), nil
+}
+
+func (r *policies) simulateApp(req SimulationRequest, interval time.Duration) *SimulationResult {
+	target := r.spec.Targets.Apps[req.App]
+	res := &SimulationResult{
+		Policies: SimulatedPolicies{
+			Timeout:        target.Timeout,
+			Hedging:        target.Hedging,
+			Mirroring:      target.Mirroring,
+			CircuitBreaker: target.CircuitBreaker,
+			Concurrency:    target.Concurrency,
+			RetryBudget:    target.RetryBudget,
+			Fallback:       target.Fallback,
+		},
+		Steps: []SimulatedStep{},
+	}
+	timeout := r.appTimeouts[req.App]
+	if route := r.route(req.App, req.Method); route != nil {
+		res.Policies.Route = route.method
+		rt := target.Routes[route.method]
+		if rt.Timeout != "" {
+			res.Policies.Timeout = rt.Timeout
+			timeout = route.timeout
+		}
+		if rt.Hedging != "" {
+			res.Policies.Hedging = rt.Hedging
+		}
+	}
+	if timeout > 0 {
+		res.Timeout = timeout.String()
+	}
+
+	clock := &simulatedClock{start: time.Now()}
+	breaker := newSimulatedCircuitBreaker(r.appBreakers[req.App], clock)
+	limiter := newSimulatedConcurrencyLimiter(r.appConcurrency[req.App], clock)
+	fallback := r.appFallbacks[req.App]
+	for i, failed := range req.Errors {
+		step := SimulatedStep{Operation: i, Elapsed: clock.elapsed.String(), Result: SimulatedRejected}
+		// the sequential operations are never rejected by the concurrency limiter.
+		release, _ := limiter.Acquire()
+		err := breaker.Allow()
+		if err == nil {
+			step.Result = simulatedResult(failed)
+			err = simulatedError(failed)
+			breaker.Record(err)
+		}
+		release(err)
+		step.CircuitBreaker = simulatedBreakerState(breaker)
+		step.ConcurrencyLimit = simulatedConcurrencyLimit(limiter)
+		if step.Result != SimulatedSuccess {
+			step.Fallback = describeFallback(fallback)
+		}
+		res.Steps = append(res.Steps, step)
+		clock.elapsed += interval
+	}
+	return res
+}
+
+func (r *policies) simulateComponent(req SimulationRequest, interval time.Duration) *SimulationResult {
+	target := r.spec.Targets.Components[req.Component]
+	res := &SimulationResult{
+		Policies: SimulatedPolicies{
+			Retry:       target.Retry,
+			Concurrency: target.Concurrency,
+			RetryBudget: target.RetryBudget,
+			Fallback:    target.Fallback,
+		},
+		Steps: []SimulatedStep{},
+	}
+	retry := r.componentRetries[req.Component]
+	if policy, ok := r.topicRetries[req.Component][req.Topic]; ok && req.Topic != "" {
+		res.Policies.Retry = target.Topics[req.Topic].Retry
+		retry = policy
+	}
+
+	clock := &simulatedClock{start: time.Now()}
+	limiter := newSimulatedConcurrencyLimiter(r.componentConcurrency[req.Component], clock)
+	budget := newSimulatedRetryBudget(r.componentRetryBudgets[req.Component], clock)
+	fallback := r.componentFallbacks[req.Component]
+	operation, attempt := 0, 0
+	for _, failed := range req.Errors {
+		step := SimulatedStep{Operation: operation, Attempt: attempt, Elapsed: clock.elapsed.String(), Result: simulatedResult(failed)}
+		if attempt == 0 {
+			budget.Request()
+		}
+		// the sequential operations are never rejected by the concurrency limiter.
+		release, _ := limiter.Acquire()
+		release(simulatedError(failed))
+		step.ConcurrencyLimit = simulatedConcurrencyLimit(limiter)
+
+		retrying := failed && retry != nil && attempt < retry.MaxRetries
+		if retrying && !budget.TryRetry() {
+			retrying = false
+			step.RetryBudgetExhausted = true
+		}
+		if retrying {
+			step.RetryIn = retry.Interval.String()
+			attempt++
+			clock.elapsed += retry.Interval
+		} else {
+			if failed {
+				step.Fallback = describeFallback(fallback)
+			}
+			operation++
+			attempt = 0
+			clock.elapsed += interval
+		}
+		res.Steps = append(res.Steps, step)
+	}
+	return res
+}
+
+func simulatedResult(failed bool) string {
+	if failed {
+		return SimulatedError
+	}
+	return SimulatedSuccess
+}
+
+// describeFallback describes the action of a fallback policy: app:<app ID>, component:<name> or response:<status>.
+func describeFallback(policy *FallbackPolicy) string {
+	switch {
+	case policy == nil:
+		return ""
+	case policy.AppID != "":
+		return "app:" + policy.AppID
+	case policy.Component != "":
+		return "component:" + policy.Component
+	default:
+		return fmt.Sprintf("response:%d", policy.Response.StatusCode)
+	}
+}
+
+// simulatedClock is the clock of a simulation, which advances with the simulated operations and their retries.
+type simulatedClock struct {
+	start   time.Time
+	elapsed time.Duration
+}
+
+func (c *simulatedClock) now() time.Time {
+	return c.start.Add(c.elapsed)
+}
+
+// simulatedError returns the error of a simulated attempt.
+func simulatedError(failed bool) error {
+	if failed {
+		return errSimulatedFailure
+	}
+	return nil
+}
+
+func newSimulatedCircuitBreaker(policy *CircuitBreakerPolicy, clock *simulatedClock) *CircuitBreaker {
+	if policy == nil {
+		return nil
+	}
+	b := newCircuitBreaker("simulation", policy)
+	b.now = clock.now
+	b.simulated = true
+	return b
+}
+
+func newSimulatedConcurrencyLimiter(policy *ConcurrencyPolicy, clock *simulatedClock) *ConcurrencyLimiter {
+	if policy == nil {
+		return nil
+	}
+	l := newConcurrencyLimiter("simulation", policy)
+	l.now = clock.now
+	return l
+}
+
+func newSimulatedRetryBudget(policy *RetryBudgetPolicy, clock *simulatedClock) *RetryBudget {
+	if policy == nil {
+		return nil
+	}
+	b := newRetryBudget("simulation", policy)
+	b.now = clock.now
+	return b
+}
+
+func simulatedBreakerState(b *CircuitBreaker) CircuitBreakerState {
+	if b == nil {
+		return ""
+	}
+	return b.Status().State
+}
+
+func simulatedConcurrencyLimit(l *ConcurrencyLimiter) int {
+	if l == nil {
+		return 0
+	}
+	return l.Status().Limit
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resiliency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func TestSimulate(t *testing.T) {
+	r, err := FromConfiguration(config.ResiliencySpec{
+		Policies: config.ResiliencyPolicies{
+			Retries: map[string]config.RetryPolicySpec{
+				"twice": {Interval: "1s", MaxRetries: 2},
+				"none":  {Interval: "0s", MaxRetries: 0},
+			},
+			Fallbacks: map[string]config.FallbackPolicySpec{
+				"secondary": {Component: "pubsub2"},
+			},
+		},
+		Targets: config.ResiliencyTargets{
+			Components: map[string]config.ComponentResiliencyTarget{
+				"pubsub1": {
+					Retry:    "twice",
+					Fallback: "secondary",
+					Topics: map[string]config.TopicResiliencyTarget{
+						"orders": {Retry: "none"},
+					},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	t.Run("component retries and fallback", func(t *testing.T) {
+		res, err := r.Simulate(SimulationRequest{Component: "pubsub1", Errors: []bool{true, true, true, true, false}, Interval: "5s"})
+		assert.NoError(t, err)
+		assert.Equal(t, SimulatedPolicies{Retry: "twice", Fallback: "secondary"}, res.Policies)
+		assert.Equal(t, []SimulatedStep{
+			{Operation: 0, Attempt: 0, Elapsed: "0s", Result: SimulatedError, RetryIn: "1s"},
+			{Operation: 0, Attempt: 1, Elapsed: "1s", Result: SimulatedError, RetryIn: "1s"},
+			{Operation: 0, Attempt: 2, Elapsed: "2s", Result: SimulatedError, Fallback: "component:pubsub2"},
+			{Operation: 1, Attempt: 0, Elapsed: "7s", Result: SimulatedError, RetryIn: "1s"},
+			{Operation: 1, Attempt: 1, Elapsed: "8s", Result: SimulatedSuccess},
+		}, res.Steps)
+	})
+
+	t.Run("topic retry policy", func(t *testing.T) {
+		res, err := r.Simulate(SimulationRequest{Component: "pubsub1", Topic: "orders", Errors: []bool{true}})
+		assert.NoError(t, err)
+		assert.Equal(t, "none", res.Policies.Retry)
+		assert.Equal(t, "component:pubsub2", res.Steps[0].Fallback)
+	})
+
+	t.Run("app circuit breaker and concurrency limiter", func(t *testing.T) {
+		res, err := r.Simulate(SimulationRequest{
+			App:      "app1",
+			Errors:   []bool{true, true, false},
+			Interval: "5s",
+			Spec: &config.ResiliencySpec{
+				Policies: config.ResiliencyPolicies{
+					CircuitBreakers: map[string]config.CircuitBreakerPolicySpec{
+						"once": {MaxConsecutiveFailures: 1, Timeout: "10s"},
+					},
+					Concurrency: map[string]config.ConcurrencyPolicySpec{
+						"halving": {InitialLimit: 10, LatencyThreshold: "1s", BackoffRatio: 0.5},
+					},
+				},
+				Targets: config.ResiliencyTargets{
+					Apps: map[string]config.AppResiliencyTarget{
+						"app1": {CircuitBreaker: "once", Concurrency: "halving"},
+					},
+				},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []SimulatedStep{
+			{Operation: 0, Elapsed: "0s", Result: SimulatedError, CircuitBreaker: CircuitBreakerOpen, ConcurrencyLimit: 5},
+			{Operation: 1, Elapsed: "5s", Result: SimulatedRejected, CircuitBreaker: CircuitBreakerOpen, ConcurrencyLimit: 5},
+			{Operation: 2, Elapsed: "10s", Result: SimulatedSuccess, CircuitBreaker: CircuitBreakerClosed, ConcurrencyLimit: 5},
+		}, res.Steps)
+		assert.Empty(t, r.CircuitBreakers())
+	})
+
+	t.Run("component retry budget", func(t *testing.T) {
+		res, err := r.Simulate(SimulationRequest{
+			Component: "pubsub1",
+			Errors:    []bool{true, true, true},
+			Spec: &config.ResiliencySpec{
+				Policies: config.ResiliencyPolicies{
+					Retries: map[string]config.RetryPolicySpec{
+						"immediate": {Interval: "0s", MaxRetries: 3},
+					},
+					RetryBudgets: map[string]config.RetryBudgetSpec{
+						"budget": {Ratio: 0.1, MinRetriesPerSecond: 1, TTL: "1s"},
+					},
+				},
+				Targets: config.ResiliencyTargets{
+					Components: map[string]config.ComponentResiliencyTarget{
+						"pubsub1": {Retry: "immediate", RetryBudget: "budget"},
+					},
+				},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []SimulatedStep{
+			{Operation: 0, Attempt: 0, Elapsed: "0s", Result: SimulatedError, RetryIn: "0s"},
+			{Operation: 0, Attempt: 1, Elapsed: "0s", Result: SimulatedError, RetryBudgetExhausted: true},
+			{Operation: 1, Attempt: 0, Elapsed: "0s", Result: SimulatedError, RetryBudgetExhausted: true},
+		}, res.Steps)
+	})
+
+	t.Run("target without policies", func(t *testing.T) {
+		res, err := r.Simulate(SimulationRequest{App: "app1", Errors: []bool{true, false}})
+		assert.NoError(t, err)
+		assert.Equal(t, SimulatedPolicies{}, res.Policies)
+		assert.Empty(t, res.Timeout)
+		assert.Len(t, res.Steps, 2)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		_, err := r.Simulate(SimulationRequest{})
+		assert.Error(t, err)
+		_, err = r.Simulate(SimulationRequest{App: "app1", Component: "pubsub1"})
+		assert.Error(t, err)
+		_, err = r.Simulate(SimulationRequest{App: "app1", Interval: "-1s"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bindings
+
+import (
+	"context"
+	"sync"
+)
+
+// Gate pauses the delivery of the events of an input binding.
+// Events are held until the gate is resumed, which keeps the binding from reading further events from its source.
+type Gate struct {
+	lock    sync.Mutex
+	resumed chan struct{}
+}
+
+// NewGate returns a new open Gate.
+func NewGate() *Gate {
+	return &Gate{}
+}
+
+// Pause holds the events passing through the gate until it is resumed.
+func (g *Gate) Pause() {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if g.resumed == nil {
+		g.resumed = make(chan struct{})
+	}
+}
+
+// Resume releases the held events.
+func (g *Gate) Resume() {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if g.resumed != nil {
+		close(g.resumed)
+		g.resumed = nil
+	}
+}
+
+// IsPaused returns true if the gate is paused.
+func (g *Gate) IsPaused() bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	return g.resumed != nil
+}
+
+// Wait blocks while the gate is paused, until the context is done.
+func (g *Gate) Wait(ctx context.Context) error {
+	g.lock.Lock()
+	resumed := g.resumed
+	g.lock.Unlock()
+
+	if resumed == nil {
+		return nil
+	}
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bindings
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGate(t *testing.T) {
+	g := NewGate()
+	assert.False(t, g.IsPaused())
+	assert.NoError(t, g.Wait(context.Background()))
+
+	g.Pause()
+	g.Pause()
+	assert.True(t, g.IsPaused())
+
+	released := make(chan struct{})
+	go func() {
+		assert.NoError(t, g.Wait(context.Background()))
+		close(released)
+	}()
+
+	select {
+	case <-released:
+		assert.Fail(t, "event released while the gate is paused")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	g.Resume()
+	g.Resume()
+	assert.False(t, g.IsPaused())
+
+	select {
+	case <-released:
+	case <-time.After(time.Second):
+		assert.Fail(t, "event not released after the gate is resumed")
+	}
+}
+
+func TestGateWaitCanceled(t *testing.T) {
+	g := NewGate()
+	g.Pause()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, g.Wait(ctx), context.Canceled)
+	assert.True(t, g.IsPaused())
+}
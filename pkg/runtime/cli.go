@@ -26,9 +26,11 @@ import (
 	"github.com/dapr/kit/logger"
 
 	"github.com/dapr/dapr/pkg/acl"
+	"github.com/dapr/dapr/pkg/channel"
 	global_config "github.com/dapr/dapr/pkg/config"
 	env "github.com/dapr/dapr/pkg/config/env"
 	"github.com/dapr/dapr/pkg/cors"
+	"github.com/dapr/dapr/pkg/credentials"
 	"github.com/dapr/dapr/pkg/grpc"
 	"github.com/dapr/dapr/pkg/metrics"
 	"github.com/dapr/dapr/pkg/modes"
@@ -52,11 +54,13 @@ func FromFlags() (*DaprRuntime, error) {
 	componentsPath := flag.String("components-path", "", "Path for components directory. If empty, components will not be loaded. Self-hosted mode only")
 	config := flag.String("config", "", "Path to config file, or name of a configuration object")
 	appID := flag.String("app-id", "", "A unique ID for Dapr. Used for Service Discovery and state")
+	runProfile := flag.String("run-profile", "", "Path to a run profile file of shared components and per-app overrides. Self-hosted mode only")
 	controlPlaneAddress := flag.String("control-plane-address", "", "Address for a Dapr control plane")
 	sentryAddress := flag.String("sentry-address", "", "Address for the Sentry CA service")
-	placementServiceHostAddr := flag.String("placement-host-address", "", "Addresses for Dapr Actor Placement servers")
+	placementServiceHostAddr := flag.String("placement-host-address", "", "Addresses for Dapr Actor Placement servers. The addresses of the placement shards are separated by semicolons when the placement tables are sharded by actor type")
 	allowedOrigins := flag.String("allowed-origins", cors.DefaultAllowedOrigins, "Allowed HTTP origins")
 	enableProfiling := flag.Bool("enable-profiling", false, "Enable profiling")
+	profileTLSMode := flag.String("profile-tls-mode", credentials.ServerTLSDisabled, "The TLS mode of the profile server: disabled, tls or mtls. The server presents the workload certificate issued by sentry")
 	runtimeVersion := flag.Bool("version", false, "Prints the runtime version")
 	buildInfo := flag.Bool("build-info", false, "Prints the build info")
 	waitCommand := flag.Bool("wait", false, "wait for Dapr outbound ready")
@@ -65,8 +69,20 @@ func FromFlags() (*DaprRuntime, error) {
 	appSSL := flag.Bool("app-ssl", false, "Sets the URI scheme of the app to https and attempts an SSL connection")
 	daprHTTPMaxRequestSize := flag.Int("dapr-http-max-request-size", -1, "Increasing max size of request body in MB to handle uploading of big files. By default 4 MB.")
 	unixDomainSocket := flag.String("unix-domain-socket", "", "Path to a unix domain socket dir mount. If specified, Dapr API servers will use Unix Domain Sockets")
+	appUnixDomainSocket := flag.String("app-unix-domain-socket", "", "Path to the unix domain socket the app listens on. If specified, the app channel uses it instead of the app port")
 	daprHTTPReadBufferSize := flag.Int("dapr-http-read-buffer-size", -1, "Increasing max size of read buffer in KB to handle sending multi-KB headers. By default 4 KB.")
 	daprHTTPStreamRequestBody := flag.Bool("dapr-http-stream-request-body", false, "Enables request body streaming on http server")
+	shutdownOnAppExit := flag.Bool("shutdown-on-app-exit", false, "Shut down the sidecar once the app stops listening on its port, so the pods of batch workloads can complete")
+	enableGateway := flag.Bool("enable-gateway", false, "Runs daprd as a gateway forwarding service invocation from remote clusters to the app ids of this cluster")
+	enableAppHealthCheck := flag.Bool("enable-app-health-check", false, "Enables health checks of the app. When the app is unhealthy, invocations and topic events are not delivered to it")
+	appHealthCheckPath := flag.String("app-health-check-path", "/healthz", "Path the app health checks are sent to when the app protocol is http")
+	appHealthProbeInterval := flag.Int("app-health-probe-interval", 5, "Interval in seconds between app health checks")
+	appHealthProbeTimeout := flag.Int("app-health-probe-timeout", 500, "Timeout in milliseconds of app health checks")
+	appHealthThreshold := flag.Int("app-health-threshold", 3, "Number of consecutive failed app health checks before the app is considered unhealthy")
+	appMaxConns := flag.Int("app-max-conns", 0, "Maximum number of connections opened to the app. Requests exceeding it wait for a pooled connection. By default unlimited")
+	appMaxIdleConnDuration := flag.Int("app-max-idle-conn-duration", 0, "Duration in seconds after which idle connections to the app are closed. By default 10 seconds")
+	appKeepAliveInterval := flag.Int("app-keepalive-interval", 0, "Interval in seconds of TCP keep-alive probes to HTTP apps and of HTTP/2 pings to gRPC apps. By default disabled for gRPC apps")
+	componentsInitConcurrency := flag.Int("components-init-concurrency", 1, "Number of components initialized at once when daprd starts. The components are initialized after the components they depend on")
 	daprGracefulShutdownSeconds := flag.Int("dapr-graceful-shutdown-seconds", -1, "Graceful shutdown time in seconds.")
 
 	loggerOptions := logger.DefaultOptions()
@@ -93,6 +109,20 @@ func FromFlags() (*DaprRuntime, error) {
 		os.Exit(0)
 	}
 
+	var sharedComponentsPath string
+	if *runProfile != "" {
+		if *mode != string(modes.StandaloneMode) {
+			return nil, errors.New("run-profile is only supported in self-hosted mode")
+		}
+		profile, err := LoadRunProfile(*runProfile)
+		if err != nil {
+			return nil, err
+		}
+		if sharedComponentsPath, err = profile.Apply(*appID, flag.CommandLine); err != nil {
+			return nil, err
+		}
+	}
+
 	if *appID == "" {
 		return nil, errors.New("app-id parameter cannot be empty")
 	}
@@ -106,11 +136,6 @@ func FromFlags() (*DaprRuntime, error) {
 	log.Infof("starting Dapr Runtime -- version %s -- commit %s", version.Version(), version.Commit())
 	log.Infof("log level set to: %s", loggerOptions.OutputLevel)
 
-	// Initialize dapr metrics exporter
-	if err := metricsExporter.Init(); err != nil {
-		log.Fatal(err)
-	}
-
 	daprHTTP, err := strconv.Atoi(*daprHTTPPort)
 	if err != nil {
 		return nil, errors.Wrap(err, "error parsing dapr-http-port flag")
@@ -178,8 +203,12 @@ func FromFlags() (*DaprRuntime, error) {
 	}
 
 	placementAddresses := []string{}
+	var placementShards [][]string
 	if *placementServiceHostAddr != "" {
-		placementAddresses = parsePlacementAddr(*placementServiceHostAddr)
+		placementShards = parsePlacementShards(*placementServiceHostAddr)
+		for _, shard := range placementShards {
+			placementAddresses = append(placementAddresses, shard...)
+		}
 	}
 
 	var concurrency int
@@ -199,6 +228,36 @@ func FromFlags() (*DaprRuntime, error) {
 	runtimeConfig := NewRuntimeConfig(*appID, placementAddresses, *controlPlaneAddress, *allowedOrigins, *config, *componentsPath,
 		appPrtcl, *mode, daprHTTP, daprInternalGRPC, daprAPIGRPC, daprAPIListenAddressList, publicPort, applicationPort, profPort, *enableProfiling, concurrency, *enableMTLS, *sentryAddress, *appSSL, maxRequestBodySize, *unixDomainSocket, readBufferSize, *daprHTTPStreamRequestBody, gracefulShutdownDuration)
 
+	runtimeConfig.Standalone.SharedComponentsPath = sharedComponentsPath
+	runtimeConfig.EnableGateway = *enableGateway
+	runtimeConfig.ShutdownOnAppExit = *shutdownOnAppExit
+	runtimeConfig.AppUnixDomainSocket = *appUnixDomainSocket
+	if len(placementShards) > 1 {
+		runtimeConfig.PlacementShards = placementShards
+	}
+	if *profileTLSMode != credentials.ServerTLSDisabled && !*enableMTLS {
+		return nil, errors.New("profile-tls-mode requires mTLS to be enabled")
+	}
+	runtimeConfig.ProfileTLSMode = *profileTLSMode
+	runtimeConfig.LoggerOptions = loggerOptions
+	if *componentsInitConcurrency < 1 {
+		return nil, errors.New("components-init-concurrency must be at least 1")
+	}
+	runtimeConfig.ComponentsInitConcurrency = *componentsInitConcurrency
+	runtimeConfig.AppConnectionPool = channel.ConnectionPoolConfig{
+		MaxConns:            *appMaxConns,
+		MaxIdleConnDuration: time.Duration(*appMaxIdleConnDuration) * time.Second,
+		KeepAliveInterval:   time.Duration(*appKeepAliveInterval) * time.Second,
+	}
+	if *enableAppHealthCheck {
+		runtimeConfig.AppHealthCheck = &AppHealthConfig{
+			HealthCheckPath: *appHealthCheckPath,
+			ProbeInterval:   time.Duration(*appHealthProbeInterval) * time.Second,
+			ProbeTimeout:    time.Duration(*appHealthProbeTimeout) * time.Millisecond,
+			Threshold:       *appHealthThreshold,
+		}
+	}
+
 	// set environment variables
 	// TODO - consider adding host address to runtime config and/or caching result in utils package
 	host, err := utils.GetHostAddress()
@@ -261,7 +320,25 @@ func FromFlags() (*DaprRuntime, error) {
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
-	return NewDaprRuntime(runtimeConfig, globalConfig, accessControlList), nil
+	rt := NewDaprRuntime(runtimeConfig, globalConfig, accessControlList)
+
+	// Initialize dapr metrics exporter. Without credentials directory, the metrics server presents the workload
+	// certificate of the sidecar.
+	metricsOptions := metricsExporter.Options()
+	// The OTLP spec of the configuration has the headers and push interval, so the metrics aren't pushed twice.
+	if globalConfig.Spec.MetricSpec.OTLP.EndpointAddress != "" && metricsOptions.OTLPEndpoint != "" {
+		log.Warnf("metrics-otlp-endpoint is ignored since the configuration pushes metrics to %s", globalConfig.Spec.MetricSpec.OTLP.EndpointAddress)
+		metricsOptions.OTLPEndpoint = ""
+	}
+	if metricsOptions.TLSMode != credentials.ServerTLSDisabled && metricsOptions.TLSCredentialsPath == "" && !*enableMTLS {
+		return nil, errors.New("metrics-tls-mode requires metrics-tls-credentials or mTLS to be enabled")
+	}
+	metricsExporter.SetCertificateSource(rt.workloadCertificate)
+	if err := metricsExporter.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	return rt, nil
 }
 
 func setEnvVariables(variables map[string]string) error {
@@ -282,3 +359,16 @@ func parsePlacementAddr(val string) []string {
 	}
 	return parsed
 }
+
+// parsePlacementShards parses the placement addresses of each shard. The shards are separated by semicolons and
+// the addresses of a shard by commas.
+func parsePlacementShards(val string) [][]string {
+	parsed := [][]string{}
+	for _, shard := range strings.Split(val, ";") {
+		if strings.TrimSpace(shard) == "" {
+			continue
+		}
+		parsed = append(parsed, parsePlacementAddr(shard))
+	}
+	return parsed
+}
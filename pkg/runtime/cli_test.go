@@ -46,6 +46,32 @@ func TestParsePlacementAddr(t *testing.T) {
 	}
 }
 
+func TestParsePlacementShards(t *testing.T) {
+	testCases := []struct {
+		addr string
+		out  [][]string
+	}{
+		{
+			addr: "placement1:50005,placement2:50005",
+			out:  [][]string{{"placement1:50005", "placement2:50005"}},
+		},
+		{
+			addr: "shard0-0:50005,shard0-1:50005; shard1-0:50005,shard1-1:50005",
+			out:  [][]string{{"shard0-0:50005", "shard0-1:50005"}, {"shard1-0:50005", "shard1-1:50005"}},
+		},
+		{
+			addr: "shard0:50005;shard1:50005;",
+			out:  [][]string{{"shard0:50005"}, {"shard1:50005"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.addr, func(t *testing.T) {
+			assert.EqualValues(t, tc.out, parsePlacementShards(tc.addr))
+		})
+	}
+}
+
 func TestSetEnvVariables(t *testing.T) {
 	t.Run("Should set environment variables", func(t *testing.T) {
 		variables := map[string]string{
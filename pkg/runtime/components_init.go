@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"github.com/pkg/errors"
+
+	components_v1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+)
+
+type componentInitResult struct {
+	index int
+	err   error
+}
+
+// initComponentsInOrder runs initFn on the components, up to concurrency of them at once, starting each component
+// once the components named by its dependencies are initialized. The components which depend on a failed or unknown
+// component, or on a dependency cycle, aren't initialized and are passed to failFn instead.
+func initComponentsInOrder(comps []components_v1alpha1.Component, concurrency int,
+	dependencies func(components_v1alpha1.Component) []string,
+	initFn func(components_v1alpha1.Component) error,
+	failFn func(components_v1alpha1.Component, error)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	byName := make(map[string][]int, len(comps))
+	for i, comp := range comps {
+		byName[comp.Name] = append(byName[comp.Name], i)
+	}
+
+	pending := make([]int, len(comps))
+	dependents := make([][]int, len(comps))
+	finished := make([]bool, len(comps))
+	unknown := make([]error, len(comps))
+	for i, comp := range comps {
+		seen := map[string]bool{}
+		for _, name := range dependencies(comp) {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			deps, ok := byName[name]
+			if !ok {
+				if unknown[i] == nil {
+					unknown[i] = errors.Errorf("component %s depends on component %s which isn't loaded", comp.Name, name)
+				}
+				continue
+			}
+			for _, dep := range deps {
+				pending[i]++
+				dependents[dep] = append(dependents[dep], i)
+			}
+		}
+	}
+
+	var fail func(i int, err error)
+	fail = func(i int, err error) {
+		if finished[i] {
+			return
+		}
+		finished[i] = true
+		failFn(comps[i], err)
+		for _, dependent := range dependents[i] {
+			fail(dependent, errors.Errorf("component %s depends on component %s which failed to initialize", comps[dependent].Name, comps[i].Name))
+		}
+	}
+
+	for i, err := range unknown {
+		if err != nil {
+			fail(i, err)
+		}
+	}
+
+	var ready []int
+	for i := range comps {
+		if pending[i] == 0 && !finished[i] {
+			ready = append(ready, i)
+		}
+	}
+
+	results := make(chan componentInitResult, len(comps))
+	running := 0
+	for {
+		for running < concurrency && len(ready) > 0 {
+			i := ready[0]
+			ready = ready[1:]
+			if finished[i] {
+				continue
+			}
+			running++
+			go func(i int) {
+				results <- componentInitResult{index: i, err: initFn(comps[i])}
+			}(i)
+		}
+		if running == 0 {
+			break
+		}
+
+		res := <-results
+		running--
+		if res.err != nil {
+			// initFn reported the failure of the component itself.
+			finished[res.index] = true
+			for _, dependent := range dependents[res.index] {
+				fail(dependent, errors.Errorf("component %s depends on component %s which failed to initialize", comps[dependent].Name, comps[res.index].Name))
+			}
+			continue
+		}
+
+		finished[res.index] = true
+		for _, dependent := range dependents[res.index] {
+			pending[dependent]--
+			if pending[dependent] == 0 && !finished[dependent] {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	for i, comp := range comps {
+		if !finished[i] {
+			fail(i, errors.Errorf("dependencies of component %s form a cycle", comp.Name))
+		}
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	components_v1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+)
+
+func testInitComponent(name string, dependsOn ...string) components_v1alpha1.Component {
+	return components_v1alpha1.Component{
+		ObjectMeta: meta_v1.ObjectMeta{Name: name},
+		Spec:       components_v1alpha1.ComponentSpec{DependsOn: dependsOn},
+	}
+}
+
+func componentDependsOn(comp components_v1alpha1.Component) []string {
+	return comp.Spec.DependsOn
+}
+
+type initRecorder struct {
+	lock    sync.Mutex
+	order   []string
+	failed  map[string]error
+	errs    map[string]error
+	running int
+	maxRun  int
+}
+
+func newInitRecorder() *initRecorder {
+	return &initRecorder{failed: map[string]error{}, errs: map[string]error{}}
+}
+
+func (r *initRecorder) init(comp components_v1alpha1.Component) error {
+	r.lock.Lock()
+	r.running++
+	if r.running > r.maxRun {
+		r.maxRun = r.running
+	}
+	r.lock.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.running--
+	r.order = append(r.order, comp.Name)
+	return r.errs[comp.Name]
+}
+
+func (r *initRecorder) fail(comp components_v1alpha1.Component, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.failed[comp.Name] = err
+}
+
+func (r *initRecorder) index(name string) int {
+	for i, n := range r.order {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestInitComponentsInOrder(t *testing.T) {
+	t.Run("serial initialization keeps the order of the components", func(t *testing.T) {
+		r := newInitRecorder()
+		comps := []components_v1alpha1.Component{testInitComponent("a"), testInitComponent("b"), testInitComponent("c")}
+
+		initComponentsInOrder(comps, 1, componentDependsOn, r.init, r.fail)
+
+		assert.Equal(t, []string{"a", "b", "c"}, r.order)
+		assert.Equal(t, 1, r.maxRun)
+		assert.Empty(t, r.failed)
+	})
+
+	t.Run("independent components are initialized concurrently", func(t *testing.T) {
+		r := newInitRecorder()
+		comps := []components_v1alpha1.Component{
+			testInitComponent("a"), testInitComponent("b"), testInitComponent("c"), testInitComponent("d"), testInitComponent("e"),
+		}
+
+		initComponentsInOrder(comps, 3, componentDependsOn, r.init, r.fail)
+
+		assert.Len(t, r.order, 5)
+		assert.Equal(t, 3, r.maxRun)
+		assert.Empty(t, r.failed)
+	})
+
+	t.Run("components are initialized after their dependencies", func(t *testing.T) {
+		r := newInitRecorder()
+		comps := []components_v1alpha1.Component{
+			testInitComponent("statestore", "secretstore"),
+			testInitComponent("pubsub", "secretstore", "statestore"),
+			testInitComponent("secretstore"),
+			testInitComponent("binding"),
+		}
+
+		initComponentsInOrder(comps, 4, componentDependsOn, r.init, r.fail)
+
+		assert.Len(t, r.order, 4)
+		assert.Less(t, r.index("secretstore"), r.index("statestore"))
+		assert.Less(t, r.index("statestore"), r.index("pubsub"))
+		assert.Empty(t, r.failed)
+	})
+
+	t.Run("dependents of a failed component fail", func(t *testing.T) {
+		r := newInitRecorder()
+		r.errs["secretstore"] = assert.AnError
+		comps := []components_v1alpha1.Component{
+			testInitComponent("secretstore"),
+			testInitComponent("statestore", "secretstore"),
+			testInitComponent("pubsub", "statestore"),
+			testInitComponent("binding"),
+		}
+
+		initComponentsInOrder(comps, 2, componentDependsOn, r.init, r.fail)
+
+		assert.ElementsMatch(t, []string{"secretstore", "binding"}, r.order)
+		assert.Len(t, r.failed, 2)
+		assert.EqualError(t, r.failed["statestore"], "component statestore depends on component secretstore which failed to initialize")
+		assert.EqualError(t, r.failed["pubsub"], "component pubsub depends on component statestore which failed to initialize")
+	})
+
+	t.Run("components depending on an unknown component fail", func(t *testing.T) {
+		r := newInitRecorder()
+		comps := []components_v1alpha1.Component{testInitComponent("statestore", "missing"), testInitComponent("pubsub", "statestore")}
+
+		initComponentsInOrder(comps, 2, componentDependsOn, r.init, r.fail)
+
+		assert.Empty(t, r.order)
+		assert.EqualError(t, r.failed["statestore"], "component statestore depends on component missing which isn't loaded")
+		assert.Contains(t, r.failed, "pubsub")
+	})
+
+	t.Run("components in a dependency cycle fail", func(t *testing.T) {
+		r := newInitRecorder()
+		comps := []components_v1alpha1.Component{
+			testInitComponent("a", "b"), testInitComponent("b", "a"), testInitComponent("c"),
+		}
+
+		initComponentsInOrder(comps, 2, componentDependsOn, r.init, r.fail)
+
+		assert.Equal(t, []string{"c"}, r.order)
+		assert.EqualError(t, r.failed["a"], "dependencies of component a form a cycle")
+		assert.Contains(t, r.failed, "b")
+	})
+}
@@ -16,9 +16,11 @@ package runtime
 import (
 	"time"
 
+	"github.com/dapr/dapr/pkg/channel"
 	config "github.com/dapr/dapr/pkg/config/modes"
 	"github.com/dapr/dapr/pkg/credentials"
 	"github.com/dapr/dapr/pkg/modes"
+	"github.com/dapr/kit/logger"
 )
 
 // Protocol is a communications protocol.
@@ -54,6 +56,7 @@ type Config struct {
 	PublicPort               *int
 	ProfilePort              int
 	EnableProfiling          bool
+	ProfileTLSMode           string
 	APIGRPCPort              int
 	InternalGRPCPort         int
 	ApplicationPort          int
@@ -61,6 +64,7 @@ type Config struct {
 	ApplicationProtocol      Protocol
 	Mode                     modes.DaprMode
 	PlacementAddresses       []string
+	PlacementShards          [][]string
 	GlobalConfig             string
 	AllowedOrigins           string
 	Standalone               config.StandaloneConfig
@@ -72,9 +76,25 @@ type Config struct {
 	AppSSL                   bool
 	MaxRequestBodySize       int
 	UnixDomainSocket         string
+	AppUnixDomainSocket      string
 	ReadBufferSize           int
 	StreamRequestBody        bool
 	GracefulShutdownDuration time.Duration
+	EnableGateway            bool
+	ShutdownOnAppExit        bool
+	AppHealthCheck           *AppHealthConfig
+	AppConnectionPool        channel.ConnectionPoolConfig
+	LoggerOptions            logger.Options
+	// ComponentsInitConcurrency is the number of components initialized at once when daprd starts.
+	ComponentsInitConcurrency int
+}
+
+// AppHealthConfig is the configuration of the app health checks.
+type AppHealthConfig struct {
+	HealthCheckPath string
+	ProbeInterval   time.Duration
+	ProbeTimeout    time.Duration
+	Threshold       int
 }
 
 // NewRuntimeConfig returns a new runtime config.
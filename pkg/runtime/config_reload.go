@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/dapr/dapr/pkg/chaos"
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/dapr/pkg/fswatcher"
+	"github.com/dapr/dapr/pkg/modes"
+)
+
+// configurationPollInterval is the interval at which the configuration is fetched from the operator
+// to apply the changes of the reloadable specs.
+const configurationPollInterval = 30 * time.Second
+
+// watchConfiguration applies the changes of the logging, resiliency and chaos specs of the configuration without restart.
+// The configuration is fetched periodically from the operator in Kubernetes mode, and reloaded when
+// its file changes in standalone mode.
+func (a *DaprRuntime) watchConfiguration() {
+	if a.runtimeConfig.GlobalConfig == "" {
+		return
+	}
+
+	current := a.globalConfig.Spec
+	update := func(conf *config.Configuration, err error) {
+		if err != nil {
+			log.Warnf("failed to reload configuration %s: %s", a.runtimeConfig.GlobalConfig, err)
+			return
+		}
+		if !reflect.DeepEqual(conf.Spec.LoggingSpec, current.LoggingSpec) {
+			if err = a.applyLoggingSpec(conf.Spec.LoggingSpec); err != nil {
+				log.Warnf("failed to apply logging spec of configuration %s: %s", a.runtimeConfig.GlobalConfig, err)
+			} else {
+				current.LoggingSpec = conf.Spec.LoggingSpec
+			}
+		}
+		if !reflect.DeepEqual(conf.Spec.ResiliencySpec, current.ResiliencySpec) {
+			if err = a.applyResiliencySpec(conf.Spec.ResiliencySpec); err != nil {
+				log.Warnf("failed to apply resiliency spec of configuration %s: %s", a.runtimeConfig.GlobalConfig, err)
+			} else {
+				current.ResiliencySpec = conf.Spec.ResiliencySpec
+			}
+		}
+		if !reflect.DeepEqual(conf.Spec.ChaosSpec, current.ChaosSpec) {
+			if err = chaos.Configure(conf.Spec.ChaosSpec); err != nil {
+				log.Warnf("failed to apply fault injection of configuration %s: %s", a.runtimeConfig.GlobalConfig, err)
+			} else {
+				current.ChaosSpec = conf.Spec.ChaosSpec
+			}
+		}
+	}
+
+	switch a.runtimeConfig.Mode {
+	case modes.KubernetesMode:
+		if a.operatorClient == nil {
+			return
+		}
+		go func() {
+			ticker := time.NewTicker(configurationPollInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					update(config.LoadKubernetesConfiguration(a.runtimeConfig.GlobalConfig, a.namespace, a.operatorClient))
+				case <-a.ctx.Done():
+					return
+				}
+			}
+		}()
+	case modes.StandaloneMode:
+		eventCh := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			if err := fswatcher.Watch(a.ctx, filepath.Dir(a.runtimeConfig.GlobalConfig), eventCh); err != nil {
+				log.Warnf("failed to watch configuration %s: %s", a.runtimeConfig.GlobalConfig, err)
+			}
+		}()
+		go func() {
+			for {
+				select {
+				case <-eventCh:
+					conf, _, err := config.LoadStandaloneConfiguration(a.runtimeConfig.GlobalConfig)
+					update(conf, err)
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+}
+
+// applyResiliencySpec replaces the resiliency policies with the ones of the resiliency spec of the configuration.
+// The invocations, the input binding events and the pubsub events are delivered with the new policies from then on.
+func (a *DaprRuntime) applyResiliencySpec(spec config.ResiliencySpec) error {
+	if err := a.resiliency.Update(spec); err != nil {
+		return err
+	}
+	log.Info("resiliency policies reloaded")
+	return nil
+}
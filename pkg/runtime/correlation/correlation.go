@@ -0,0 +1,113 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package correlation
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/components-contrib/bindings"
+)
+
+const (
+	// IDKey is the metadata key carrying the correlation ID of a request sent to an output binding and of its
+	// reply received by an input binding.
+	IDKey = "correlationID"
+	// ReplyTimeoutKey is the metadata key of an output binding request asking the runtime to wait for the reply.
+	ReplyTimeoutKey = "replyTimeout"
+	// ReplyBindingKey is the metadata key of an output binding request naming the input binding the reply is
+	// received by.
+	ReplyBindingKey = "replyBinding"
+)
+
+// ErrReplyTimeout is returned when no reply was received before the timeout.
+var ErrReplyTimeout = errors.New("timed out waiting for reply")
+
+// Correlator routes the replies received by input bindings back to the output binding requests awaiting them.
+type Correlator struct {
+	lock    sync.Mutex
+	pending map[string]pendingReply
+}
+
+// pendingReply is a request awaiting its reply from an input binding.
+type pendingReply struct {
+	binding string
+	ch      chan *bindings.ReadResponse
+}
+
+// NewCorrelator returns a new Correlator.
+func NewCorrelator() *Correlator {
+	return &Correlator{
+		pending: map[string]pendingReply{},
+	}
+}
+
+// Await calls send and waits up to timeout for the reply correlated to id, received by the given input binding.
+// The caller is registered before the request is sent so that a fast reply is not missed.
+func (c *Correlator) Await(id, binding string, timeout time.Duration, send func() error) (*bindings.ReadResponse, error) {
+	ch := make(chan *bindings.ReadResponse, 1)
+
+	c.lock.Lock()
+	if _, ok := c.pending[id]; ok {
+		c.lock.Unlock()
+		return nil, errors.Errorf("a request with correlation ID %s is already awaiting a reply", id)
+	}
+	c.pending[id] = pendingReply{binding: binding, ch: ch}
+	c.lock.Unlock()
+
+	defer func() {
+		c.lock.Lock()
+		delete(c.pending, id)
+		c.lock.Unlock()
+	}()
+
+	if err := send(); err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case reply := <-ch:
+		return reply, nil
+	case <-timer.C:
+		return nil, ErrReplyTimeout
+	}
+}
+
+// Deliver routes an event received by an input binding to the request awaiting it.
+// It returns false if the event is not a reply to a pending request, including when the reply of the request is
+// awaited from another input binding, e.g. when the request itself is received by an input binding.
+func (c *Correlator) Deliver(binding string, event *bindings.ReadResponse) bool {
+	id := event.Metadata[IDKey]
+	if id == "" {
+		return false
+	}
+
+	c.lock.Lock()
+	pending, ok := c.pending[id]
+	ok = ok && pending.binding == binding
+	if ok {
+		// only the first reply is delivered.
+		delete(c.pending, id)
+	}
+	c.lock.Unlock()
+
+	if ok {
+		pending.ch <- event
+	}
+	return ok
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package correlation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/components-contrib/bindings"
+)
+
+func TestAwait(t *testing.T) {
+	t.Run("reply is routed to the caller", func(t *testing.T) {
+		c := NewCorrelator()
+		reply := &bindings.ReadResponse{Data: []byte("pong"), Metadata: map[string]string{IDKey: "1"}}
+
+		resp, err := c.Await("1", "replies", time.Second, func() error {
+			assert.False(t, c.Deliver("replies", &bindings.ReadResponse{Metadata: map[string]string{IDKey: "2"}}))
+			assert.True(t, c.Deliver("replies", reply))
+			// a second reply is not awaited anymore.
+			assert.False(t, c.Deliver("replies", reply))
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, reply, resp)
+	})
+
+	t.Run("no reply before the timeout", func(t *testing.T) {
+		c := NewCorrelator()
+		_, err := c.Await("1", "replies", 10*time.Millisecond, func() error { return nil })
+		assert.Equal(t, ErrReplyTimeout, err)
+		assert.False(t, c.Deliver("replies", &bindings.ReadResponse{Metadata: map[string]string{IDKey: "1"}}))
+	})
+
+	t.Run("request fails to be sent", func(t *testing.T) {
+		c := NewCorrelator()
+		_, err := c.Await("1", "replies", time.Second, func() error { return errors.New("send failed") })
+		assert.EqualError(t, err, "send failed")
+	})
+
+	t.Run("correlation ID already awaiting", func(t *testing.T) {
+		c := NewCorrelator()
+		_, err := c.Await("1", "replies", time.Second, func() error {
+			_, err := c.Await("1", "replies", time.Second, func() error { return nil })
+			assert.Error(t, err)
+			c.Deliver("replies", &bindings.ReadResponse{Metadata: map[string]string{IDKey: "1"}})
+			return nil
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("replies received by another binding", func(t *testing.T) {
+		c := NewCorrelator()
+		reply := &bindings.ReadResponse{Metadata: map[string]string{IDKey: "1"}}
+
+		_, err := c.Await("1", "replies", time.Second, func() error {
+			assert.False(t, c.Deliver("requests", reply))
+			assert.True(t, c.Deliver("replies", reply))
+			return nil
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("events without correlation ID", func(t *testing.T) {
+		c := NewCorrelator()
+		assert.False(t, c.Deliver("replies", &bindings.ReadResponse{}))
+	})
+}
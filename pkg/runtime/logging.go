@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"strings"
+	_ "unsafe" // for go:linkname
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/kit/logger"
+)
+
+// loggerNamePrefix is the prefix of the names of the loggers of Dapr, which is omitted in the scopes.
+const loggerNamePrefix = "dapr."
+
+// getLoggers returns the loggers registered with logger.NewLogger, by name. dapr/kit doesn't export it, and the
+// loggers of daprd are registered when its packages are initialized, so they give the scopes whose log levels can
+// be set.
+//
+//go:linkname getLoggers github.com/dapr/kit/logger.getLoggers
+func getLoggers() map[string]logger.Logger
+
+// isLogScope returns true if a logger of daprd has the given scope.
+func isLogScope(scope string) bool {
+	_, ok := getLoggers()[loggerNamePrefix+scope]
+	return ok
+}
+
+// setLogLevel changes the output level of all the loggers, then the output levels of the loggers of the given
+// scopes, e.g. runtime.actor. The level of all the loggers is left unchanged when level is empty, and the levels
+// of the scopes set before are kept.
+func (a *DaprRuntime) setLogLevel(level string, scopes map[string]string) error {
+	return a.updateLogLevels(level, scopes, false)
+}
+
+// applyLoggingSpec sets the log levels of the logging spec of the configuration. The scopes which were set before
+// and aren't in the spec anymore are reset to the level of all the loggers.
+func (a *DaprRuntime) applyLoggingSpec(spec config.LoggingSpec) error {
+	return a.updateLogLevels(spec.Level, spec.Scopes, true)
+}
+
+func (a *DaprRuntime) updateLogLevels(level string, scopes map[string]string, replace bool) error {
+	var opts logger.Options
+	if level != "" {
+		if err := opts.SetOutputLevel(level); err != nil {
+			return err
+		}
+	}
+	for scope, l := range scopes {
+		if scope == "" {
+			return errors.New("empty log scope")
+		}
+		if !isLogScope(scope) {
+			return errors.Errorf("unknown log scope %s", scope)
+		}
+		if err := (&logger.Options{}).SetOutputLevel(l); err != nil {
+			return errors.Wrapf(err, "log scope %s", scope)
+		}
+	}
+
+	a.logLevelLock.Lock()
+	defer a.logLevelLock.Unlock()
+
+	if a.logScopes == nil {
+		a.logScopes = map[string]string{}
+	}
+	if level != "" {
+		opts = a.runtimeConfig.LoggerOptions
+		opts.OutputLevel = level
+		if err := logger.ApplyOptionsToLoggers(&opts); err != nil {
+			return err
+		}
+		a.logLevel = level
+		log.Infof("log level set to: %s", level)
+	}
+
+	for scope, l := range a.logScopes {
+		if _, ok := scopes[scope]; ok {
+			continue
+		}
+		if replace {
+			delete(a.logScopes, scope)
+			a.setScopeLogLevel(scope, a.currentLogLevel())
+		} else if level != "" {
+			// the level of all the loggers overrides the levels of the scopes.
+			a.setScopeLogLevel(scope, l)
+		}
+	}
+	for scope, l := range scopes {
+		a.logScopes[scope] = strings.ToLower(l)
+		a.setScopeLogLevel(scope, l)
+	}
+	return nil
+}
+
+// currentLogLevel returns the level of all the loggers. It must be called with logLevelLock held.
+func (a *DaprRuntime) currentLogLevel() string {
+	if a.logLevel != "" {
+		return a.logLevel
+	}
+	return a.runtimeConfig.LoggerOptions.OutputLevel
+}
+
+func (a *DaprRuntime) setScopeLogLevel(scope, level string) {
+	logger.NewLogger(loggerNamePrefix + scope).SetOutputLevel(logger.LogLevel(strings.ToLower(level)))
+	log.Infof("log level of scope %s set to: %s", scope, level)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/dapr/pkg/modes"
+	"github.com/dapr/kit/logger"
+)
+
+func TestSetLogLevel(t *testing.T) {
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	rt.runtimeConfig.LoggerOptions = logger.DefaultOptions()
+	defer rt.setLogLevel("info", map[string]string{"runtime.actor": "info"})
+
+	t.Run("valid levels", func(t *testing.T) {
+		assert.NoError(t, rt.setLogLevel("debug", nil))
+		assert.NoError(t, rt.setLogLevel("", map[string]string{"runtime.actor": "warn", "runtime.http": "DEBUG"}))
+		assert.NoError(t, rt.applyLoggingSpec(config.LoggingSpec{Level: "error", Scopes: map[string]string{"runtime.actor": "debug"}}))
+		assert.NoError(t, rt.applyLoggingSpec(config.LoggingSpec{}))
+	})
+
+	t.Run("invalid levels", func(t *testing.T) {
+		assert.Error(t, rt.setLogLevel("verbose", nil))
+		assert.Error(t, rt.setLogLevel("", map[string]string{"runtime.actor": "verbose"}))
+		assert.Error(t, rt.setLogLevel("debug", map[string]string{"": "debug"}))
+		assert.Error(t, rt.setLogLevel("", map[string]string{"runtime.unknown": "debug"}))
+	})
+
+	t.Run("scopes removed from the configuration are reset", func(t *testing.T) {
+		assert.NoError(t, rt.applyLoggingSpec(config.LoggingSpec{Level: "info", Scopes: map[string]string{"runtime.actor": "debug", "runtime.http": "debug"}}))
+		assert.Equal(t, map[string]string{"runtime.actor": "debug", "runtime.http": "debug"}, rt.logScopes)
+
+		// the API sets the scopes of the request only.
+		assert.NoError(t, rt.setLogLevel("warn", map[string]string{"runtime.jobs": "error"}))
+		assert.Equal(t, map[string]string{"runtime.actor": "debug", "runtime.http": "debug", "runtime.jobs": "error"}, rt.logScopes)
+
+		assert.NoError(t, rt.applyLoggingSpec(config.LoggingSpec{Scopes: map[string]string{"runtime.actor": "debug"}}))
+		assert.Equal(t, map[string]string{"runtime.actor": "debug"}, rt.logScopes)
+	})
+}
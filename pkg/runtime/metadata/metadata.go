@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/dapr/components-contrib/pubsub"
+	"github.com/dapr/components-contrib/state"
+
+	"github.com/dapr/dapr/pkg/actors"
+	"github.com/dapr/dapr/pkg/expr"
+	runtime_pubsub "github.com/dapr/dapr/pkg/runtime/pubsub"
+	"github.com/dapr/dapr/pkg/version"
+)
+
+const (
+	// CapabilityQuery is the capability of the state stores supporting the query API.
+	CapabilityQuery = "QUERY_API"
+
+	// ActorRuntimeRunning is the status of the actor runtime once initialized.
+	ActorRuntimeRunning = "RUNNING"
+	// ActorRuntimeDisabled is the status of the actor runtime of a sidecar without an actor state store.
+	ActorRuntimeDisabled = "DISABLED"
+
+	// PlacementConnected is the status of an actor runtime connected to the placement service.
+	PlacementConnected = "connected"
+	// PlacementDisconnected is the status of an actor runtime not connected to the placement service.
+	PlacementDisconnected = "disconnected"
+)
+
+// Details is the information about the sidecar reported by the metadata APIs next to the loaded components, so that
+// the SDKs can discover what the sidecar supports and degrade gracefully.
+type Details struct {
+	// Capabilities are the capabilities declared by the loaded components, keyed by CapabilitiesKey.
+	Capabilities    map[string][]string
+	EnabledFeatures []string
+	Subscriptions   []Subscription
+}
+
+// Subscription is a topic the app is subscribed to, with the routes of its events.
+type Subscription struct {
+	PubsubName string             `json:"pubsubname"`
+	Topic      string             `json:"topic"`
+	Metadata   map[string]string  `json:"metadata,omitempty"`
+	Rules      []SubscriptionRule `json:"rules,omitempty"`
+}
+
+// SubscriptionRule is a route of the events of a subscription. The rule without a match is the default route.
+type SubscriptionRule struct {
+	Match string `json:"match,omitempty"`
+	Path  string `json:"path"`
+}
+
+// ActorRuntime is the state of the actor runtime of the sidecar.
+type ActorRuntime struct {
+	Status string `json:"runtimeStatus"`
+	// HostReady is true once the app hosts actor types registered with the placement service.
+	HostReady    bool                       `json:"hostReady"`
+	Placement    string                     `json:"placement,omitempty"`
+	ActiveActors []actors.ActiveActorsCount `json:"activeActors,omitempty"`
+}
+
+// Build is the build information of the runtime.
+type Build struct {
+	Version    string `json:"version"`
+	Commit     string `json:"commit,omitempty"`
+	GitVersion string `json:"gitVersion,omitempty"`
+	GoVersion  string `json:"goVersion"`
+}
+
+// CapabilitiesKey returns the key of the capabilities of a component in Details, from the category of its type,
+// e.g. state or pubsub, and its name.
+func CapabilitiesKey(category, name string) string {
+	return category + "/" + name
+}
+
+// ComponentCapabilities returns the capabilities declared by the component of the given type, e.g. state.redis,
+// and name.
+func (d Details) ComponentCapabilities(componentType, name string) []string {
+	category := strings.SplitN(componentType, ".", 2)[0]
+	return d.Capabilities[CapabilitiesKey(category, name)]
+}
+
+// StateStoreCapabilities returns the capabilities of a state store: the features it declares, e.g. ETAG and
+// TRANSACTIONAL, and QUERY_API when it supports queries.
+func StateStoreCapabilities(store state.Store) []string {
+	features := store.Features()
+	capabilities := make([]string, 0, len(features)+1)
+	for _, f := range features {
+		capabilities = append(capabilities, string(f))
+	}
+	if _, ok := store.(state.Querier); ok {
+		capabilities = append(capabilities, CapabilityQuery)
+	}
+	sort.Strings(capabilities)
+	return capabilities
+}
+
+// PubSubCapabilities returns the features declared by a pubsub, e.g. MESSAGE_TTL.
+func PubSubCapabilities(ps pubsub.PubSub) []string {
+	features := ps.Features()
+	capabilities := make([]string, 0, len(features))
+	for _, f := range features {
+		capabilities = append(capabilities, string(f))
+	}
+	sort.Strings(capabilities)
+	return capabilities
+}
+
+// NewSubscriptionRule returns the reported form of a routing rule of a subscription.
+func NewSubscriptionRule(rule *runtime_pubsub.Rule) SubscriptionRule {
+	r := SubscriptionRule{Path: rule.Path}
+	// The match of the default route is a nil expression.
+	if e, ok := rule.Match.(*expr.Expr); ok && e != nil {
+		r.Match = e.String()
+	}
+	return r
+}
+
+// GetActorRuntime returns the state of the given actor runtime, which is nil when actors are not enabled.
+func GetActorRuntime(ctx context.Context, actor actors.Actors) *ActorRuntime {
+	if actor == nil {
+		return &ActorRuntime{Status: ActorRuntimeDisabled}
+	}
+
+	a := &ActorRuntime{
+		Status:       ActorRuntimeRunning,
+		Placement:    PlacementDisconnected,
+		ActiveActors: actor.GetActiveActorsCount(ctx),
+	}
+	if actor.IsPlacementConnected() {
+		a.Placement = PlacementConnected
+	}
+	a.HostReady = len(actor.GetHostedActorTypes()) > 0 && a.Placement == PlacementConnected
+	return a
+}
+
+// GetBuild returns the build information of the runtime.
+func GetBuild() Build {
+	return Build{
+		Version:    version.Version(),
+		Commit:     version.Commit(),
+		GitVersion: version.GitVersion(),
+		GoVersion:  runtime.Version(),
+	}
+}
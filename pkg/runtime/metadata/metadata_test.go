@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/components-contrib/pubsub"
+	"github.com/dapr/components-contrib/state"
+
+	"github.com/dapr/dapr/pkg/expr"
+	runtime_pubsub "github.com/dapr/dapr/pkg/runtime/pubsub"
+	daprt "github.com/dapr/dapr/pkg/testing"
+)
+
+type fakeStore struct {
+	state.Store
+	features []state.Feature
+}
+
+func (s *fakeStore) Features() []state.Feature {
+	return s.features
+}
+
+type fakeQueryStore struct {
+	fakeStore
+	daprt.MockQuerier
+}
+
+type fakePubSub struct {
+	pubsub.PubSub
+	features []pubsub.Feature
+}
+
+func (p *fakePubSub) Features() []pubsub.Feature {
+	return p.features
+}
+
+func TestStateStoreCapabilities(t *testing.T) {
+	t.Run("declared features", func(t *testing.T) {
+		store := &fakeStore{features: []state.Feature{state.FeatureTransactional, state.FeatureETag}}
+		assert.Equal(t, []string{"ETAG", "TRANSACTIONAL"}, StateStoreCapabilities(store))
+	})
+
+	t.Run("query", func(t *testing.T) {
+		store := &fakeQueryStore{fakeStore: fakeStore{features: []state.Feature{state.FeatureETag}}}
+		assert.Equal(t, []string{"ETAG", CapabilityQuery}, StateStoreCapabilities(store))
+	})
+
+	t.Run("no features", func(t *testing.T) {
+		assert.Empty(t, StateStoreCapabilities(&fakeStore{}))
+	})
+}
+
+func TestPubSubCapabilities(t *testing.T) {
+	ps := &fakePubSub{features: []pubsub.Feature{pubsub.FeatureMessageTTL}}
+	assert.Equal(t, []string{"MESSAGE_TTL"}, PubSubCapabilities(ps))
+}
+
+func TestComponentCapabilities(t *testing.T) {
+	details := Details{
+		Capabilities: map[string][]string{
+			CapabilitiesKey("state", "store"): {"ETAG"},
+		},
+	}
+	assert.Equal(t, []string{"ETAG"}, details.ComponentCapabilities("state.redis", "store"))
+	assert.Nil(t, details.ComponentCapabilities("pubsub.redis", "store"))
+	assert.Nil(t, Details{}.ComponentCapabilities("state.redis", "store"))
+}
+
+func TestNewSubscriptionRule(t *testing.T) {
+	t.Run("match", func(t *testing.T) {
+		e := &expr.Expr{}
+		assert.NoError(t, e.DecodeString(`event.type == "order"`))
+		rule := NewSubscriptionRule(&runtime_pubsub.Rule{Match: e, Path: "/orders"})
+		assert.Equal(t, SubscriptionRule{Match: `event.type == "order"`, Path: "/orders"}, rule)
+	})
+
+	t.Run("default route", func(t *testing.T) {
+		var e *expr.Expr
+		rule := NewSubscriptionRule(&runtime_pubsub.Rule{Match: e, Path: "/default"})
+		assert.Equal(t, SubscriptionRule{Path: "/default"}, rule)
+	})
+}
+
+func TestGetActorRuntime(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		a := GetActorRuntime(context.Background(), nil)
+		assert.Equal(t, ActorRuntimeDisabled, a.Status)
+		assert.False(t, a.HostReady)
+	})
+
+	t.Run("connected to placement", func(t *testing.T) {
+		mockActors := new(daprt.MockActors)
+		mockActors.On("GetActiveActorsCount")
+		mockActors.On("GetHostedActorTypes").Return([]string{"abcd", "xyz"})
+		mockActors.On("IsPlacementConnected").Return(true)
+
+		a := GetActorRuntime(context.Background(), mockActors)
+		assert.Equal(t, ActorRuntimeRunning, a.Status)
+		assert.Equal(t, PlacementConnected, a.Placement)
+		assert.True(t, a.HostReady)
+		assert.Len(t, a.ActiveActors, 2)
+	})
+
+	t.Run("disconnected from placement", func(t *testing.T) {
+		mockActors := new(daprt.MockActors)
+		mockActors.On("GetActiveActorsCount")
+		mockActors.On("GetHostedActorTypes").Return([]string{"abcd", "xyz"})
+		mockActors.On("IsPlacementConnected").Return(false)
+
+		a := GetActorRuntime(context.Background(), mockActors)
+		assert.Equal(t, PlacementDisconnected, a.Placement)
+		assert.False(t, a.HostReady)
+	})
+
+	t.Run("no hosted actor types", func(t *testing.T) {
+		mockActors := new(daprt.MockActors)
+		mockActors.On("GetActiveActorsCount")
+		mockActors.On("GetHostedActorTypes").Return([]string(nil))
+		mockActors.On("IsPlacementConnected").Return(true)
+
+		a := GetActorRuntime(context.Background(), mockActors)
+		assert.Equal(t, PlacementConnected, a.Placement)
+		assert.False(t, a.HostReady)
+	})
+}
+
+func TestGetBuild(t *testing.T) {
+	b := GetBuild()
+	assert.Equal(t, "edge", b.Version)
+	assert.NotEmpty(t, b.GoVersion)
+}
@@ -1,6 +1,8 @@
 package runtime
 
 import (
+	grpc_go "google.golang.org/grpc"
+
 	"github.com/dapr/dapr/pkg/components/bindings"
 	"github.com/dapr/dapr/pkg/components/configuration"
 	"github.com/dapr/dapr/pkg/components/middleware/http"
@@ -8,6 +10,7 @@ import (
 	"github.com/dapr/dapr/pkg/components/pubsub"
 	"github.com/dapr/dapr/pkg/components/secretstores"
 	"github.com/dapr/dapr/pkg/components/state"
+	grpc_middleware "github.com/dapr/dapr/pkg/middleware/grpc"
 )
 
 type (
@@ -21,6 +24,7 @@ type (
 		inputBindings   []bindings.InputBinding
 		outputBindings  []bindings.OutputBinding
 		httpMiddleware  []http.Middleware
+		grpcPipeline    grpc_middleware.Pipeline
 
 		componentsCallback ComponentsCallback
 	}
@@ -85,6 +89,20 @@ func WithHTTPMiddleware(httpMiddleware ...http.Middleware) Option {
 	}
 }
 
+// WithGRPCUnaryInterceptors adds unary interceptors to the gRPC API server of the runtime.
+func WithGRPCUnaryInterceptors(interceptors ...grpc_go.UnaryServerInterceptor) Option {
+	return func(o *runtimeOpts) {
+		o.grpcPipeline.UnaryInterceptors = append(o.grpcPipeline.UnaryInterceptors, interceptors...)
+	}
+}
+
+// WithGRPCStreamInterceptors adds stream interceptors to the gRPC API server of the runtime.
+func WithGRPCStreamInterceptors(interceptors ...grpc_go.StreamServerInterceptor) Option {
+	return func(o *runtimeOpts) {
+		o.grpcPipeline.StreamInterceptors = append(o.grpcPipeline.StreamInterceptors, interceptors...)
+	}
+}
+
 // WithComponentsCallback sets the components callback for applications that embed Dapr.
 func WithComponentsCallback(componentsCallback ComponentsCallback) Option {
 	return func(o *runtimeOpts) {
@@ -14,11 +14,13 @@ limitations under the License.
 package pubsub
 
 import (
+	"context"
+
 	contrib_pubsub "github.com/dapr/components-contrib/pubsub"
 )
 
 // Adapter is the interface for message buses.
 type Adapter interface {
 	GetPubSub(pubsubName string) contrib_pubsub.PubSub
-	Publish(req *contrib_pubsub.PublishRequest) error
+	Publish(ctx context.Context, req *contrib_pubsub.PublishRequest) error
 }
@@ -14,12 +14,17 @@ limitations under the License.
 package pubsub
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 
 	contrib_contenttype "github.com/dapr/components-contrib/contenttype"
 	contrib_pubsub "github.com/dapr/components-contrib/pubsub"
 )
 
+// TimeField is the time attribute of the CloudEvents, when the event was published.
+const TimeField = "time"
+
 // CloudEvent is a request object to create a Dapr compliant cloudevent.
 type CloudEvent struct {
 	ID              string
@@ -36,6 +41,8 @@ func NewCloudEvent(req *CloudEvent) (map[string]interface{}, error) {
 	if contrib_contenttype.IsCloudEventContentType(req.DataContentType) {
 		return contrib_pubsub.FromCloudEvent(req.Data, req.Topic, req.Pubsub, req.TraceID, req.TraceState)
 	}
-	return contrib_pubsub.NewCloudEventsEnvelope(uuid.New().String(), req.ID, contrib_pubsub.DefaultCloudEventType,
-		"", req.Topic, req.Pubsub, req.DataContentType, req.Data, req.TraceID, req.TraceState), nil
+	ce := contrib_pubsub.NewCloudEventsEnvelope(uuid.New().String(), req.ID, contrib_pubsub.DefaultCloudEventType,
+		"", req.Topic, req.Pubsub, req.DataContentType, req.Data, req.TraceID, req.TraceState)
+	ce[TimeField] = time.Now().UTC().Format(time.RFC3339Nano)
+	return ce, nil
 }
@@ -36,6 +36,7 @@ func TestNewCloudEvent(t *testing.T) {
 		assert.Equal(t, "hello", ce["data"].(string))
 		assert.Equal(t, "text/plain", ce["datacontenttype"].(string))
 		assert.Equal(t, "d", ce["traceid"].(string))
+		assert.NotEmpty(t, ce["time"])
 	})
 
 	t.Run("raw payload no data", func(t *testing.T) {
@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// RunProfile describes the apps of a self-hosted multi-app development environment.
+// The apps share the components of the resources directory of the profile, and override the ports, app ID,
+// configuration and components of their own.
+type RunProfile struct {
+	// ResourcesPath is the directory of the components shared by the apps.
+	ResourcesPath string `json:"resourcesPath,omitempty"`
+	// Config is the configuration of the apps without a configuration of their own.
+	Config string          `json:"config,omitempty"`
+	Apps   []RunProfileApp `json:"apps"`
+}
+
+// RunProfileApp holds the overrides of an app of a run profile.
+type RunProfileApp struct {
+	AppID        string `json:"appID"`
+	AppPort      int    `json:"appPort,omitempty"`
+	AppProtocol  string `json:"appProtocol,omitempty"`
+	DaprHTTPPort int    `json:"daprHTTPPort,omitempty"`
+	DaprGRPCPort int    `json:"daprGRPCPort,omitempty"`
+	Config       string `json:"config,omitempty"`
+	// ResourcesPath is the directory of the components of the app, overriding the shared components with the same name.
+	ResourcesPath string `json:"resourcesPath,omitempty"`
+}
+
+// LoadRunProfile loads a run profile from a file.
+// Relative paths of the profile are resolved against the directory of the file.
+func LoadRunProfile(path string) (*RunProfile, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading run profile %s", path)
+	}
+
+	var profile RunProfile
+	if err = yaml.Unmarshal(b, &profile); err != nil {
+		return nil, errors.Wrapf(err, "error parsing run profile %s", path)
+	}
+	if len(profile.Apps) == 0 {
+		return nil, errors.Errorf("run profile %s has no apps", path)
+	}
+
+	dir := filepath.Dir(path)
+	profile.ResourcesPath = resolveProfilePath(dir, profile.ResourcesPath)
+	profile.Config = resolveProfilePath(dir, profile.Config)
+	for i := range profile.Apps {
+		app := &profile.Apps[i]
+		if app.AppID == "" {
+			return nil, errors.Errorf("run profile %s has an app without appID", path)
+		}
+		app.ResourcesPath = resolveProfilePath(dir, app.ResourcesPath)
+		app.Config = resolveProfilePath(dir, app.Config)
+	}
+	return &profile, nil
+}
+
+// Apply sets the flags of the app of the profile with the given app ID, or of its only app if the app ID is empty.
+// Flags set on the command line take precedence over the profile. It returns the shared components directory.
+func (p *RunProfile) Apply(appID string, flags *flag.FlagSet) (string, error) {
+	var app *RunProfileApp
+	for i := range p.Apps {
+		if p.Apps[i].AppID == appID || (appID == "" && len(p.Apps) == 1) {
+			app = &p.Apps[i]
+			break
+		}
+	}
+	if app == nil {
+		return "", errors.Errorf("app %q not found in run profile", appID)
+	}
+
+	config := app.Config
+	if config == "" {
+		config = p.Config
+	}
+	values := map[string]string{
+		"app-id":          app.AppID,
+		"app-protocol":    app.AppProtocol,
+		"config":          config,
+		"components-path": app.ResourcesPath,
+	}
+	for name, port := range map[string]int{
+		"app-port":       app.AppPort,
+		"dapr-http-port": app.DaprHTTPPort,
+		"dapr-grpc-port": app.DaprGRPCPort,
+	} {
+		if port != 0 {
+			values[name] = strconv.Itoa(port)
+		}
+	}
+
+	set := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	for name, value := range values {
+		if value == "" || set[name] {
+			continue
+		}
+		if err := flags.Set(name, value); err != nil {
+			return "", errors.Wrapf(err, "error setting %s from run profile", name)
+		}
+	}
+	return p.ResourcesPath, nil
+}
+
+func resolveProfilePath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRunProfile = `
+resourcesPath: ./resources
+config: ./config.yaml
+apps:
+- appID: orders
+  appPort: 3000
+  daprHTTPPort: 3500
+  daprGRPCPort: 50001
+  resourcesPath: ./orders
+- appID: checkout
+  appPort: 3001
+  daprHTTPPort: 3501
+  config: /etc/dapr/checkout.yaml
+`
+
+func newTestFlagSet() *flag.FlagSet {
+	flags := flag.NewFlagSet("daprd", flag.ContinueOnError)
+	for _, name := range []string{"app-id", "app-port", "app-protocol", "config", "components-path", "dapr-http-port", "dapr-grpc-port"} {
+		flags.String(name, "", "")
+	}
+	return flags
+}
+
+func TestRunProfile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "dapr.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testRunProfile), 0o600))
+
+	profile, err := LoadRunProfile(path)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "resources"), profile.ResourcesPath)
+	assert.Len(t, profile.Apps, 2)
+
+	t.Run("applies the app overrides", func(t *testing.T) {
+		flags := newTestFlagSet()
+		require.NoError(t, flags.Parse([]string{"--app-id", "orders", "--dapr-grpc-port", "50005"}))
+
+		shared, err := profile.Apply("orders", flags)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "resources"), shared)
+		assert.Equal(t, "3000", flags.Lookup("app-port").Value.String())
+		assert.Equal(t, "3500", flags.Lookup("dapr-http-port").Value.String())
+		assert.Equal(t, "50005", flags.Lookup("dapr-grpc-port").Value.String())
+		assert.Equal(t, filepath.Join(dir, "orders"), flags.Lookup("components-path").Value.String())
+		assert.Equal(t, filepath.Join(dir, "config.yaml"), flags.Lookup("config").Value.String())
+	})
+
+	t.Run("app config overrides the shared config", func(t *testing.T) {
+		flags := newTestFlagSet()
+		_, err := profile.Apply("checkout", flags)
+		require.NoError(t, err)
+		assert.Equal(t, "/etc/dapr/checkout.yaml", flags.Lookup("config").Value.String())
+		assert.Equal(t, "", flags.Lookup("components-path").Value.String())
+	})
+
+	t.Run("unknown app", func(t *testing.T) {
+		_, err := profile.Apply("cart", newTestFlagSet())
+		assert.Error(t, err)
+		_, err = profile.Apply("", newTestFlagSet())
+		assert.Error(t, err)
+	})
+}
@@ -15,6 +15,8 @@ package runtime
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -23,9 +25,11 @@ import (
 	nethttp "net/http"
 	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/cenkalti/backoff"
@@ -59,8 +63,11 @@ import (
 
 	"github.com/dapr/dapr/pkg/actors"
 	components_v1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	"github.com/dapr/dapr/pkg/apphealth"
+	"github.com/dapr/dapr/pkg/audit"
 	"github.com/dapr/dapr/pkg/channel"
 	http_channel "github.com/dapr/dapr/pkg/channel/http"
+	"github.com/dapr/dapr/pkg/chaos"
 	"github.com/dapr/dapr/pkg/components"
 	bindings_loader "github.com/dapr/dapr/pkg/components/bindings"
 	http_middleware_loader "github.com/dapr/dapr/pkg/components/middleware/http"
@@ -69,19 +76,32 @@ import (
 	secretstores_loader "github.com/dapr/dapr/pkg/components/secretstores"
 	state_loader "github.com/dapr/dapr/pkg/components/state"
 	"github.com/dapr/dapr/pkg/config"
+	env "github.com/dapr/dapr/pkg/config/env"
+	"github.com/dapr/dapr/pkg/credentials"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
 	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
+	"github.com/dapr/dapr/pkg/drain"
 	"github.com/dapr/dapr/pkg/encryption"
 	"github.com/dapr/dapr/pkg/grpc"
+	"github.com/dapr/dapr/pkg/health"
 	"github.com/dapr/dapr/pkg/http"
+	"github.com/dapr/dapr/pkg/jobs"
+	"github.com/dapr/dapr/pkg/messages"
 	"github.com/dapr/dapr/pkg/messaging"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	grpc_middleware "github.com/dapr/dapr/pkg/middleware/grpc"
 	http_middleware "github.com/dapr/dapr/pkg/middleware/http"
+	http_ratelimit "github.com/dapr/dapr/pkg/middleware/http/ratelimit"
 	"github.com/dapr/dapr/pkg/modes"
 	"github.com/dapr/dapr/pkg/operator/client"
 	operatorv1pb "github.com/dapr/dapr/pkg/proto/operator/v1"
 	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
+	"github.com/dapr/dapr/pkg/resiliency"
+	runtime_bindings "github.com/dapr/dapr/pkg/runtime/bindings"
+	"github.com/dapr/dapr/pkg/runtime/correlation"
+	runtime_metadata "github.com/dapr/dapr/pkg/runtime/metadata"
 	runtime_pubsub "github.com/dapr/dapr/pkg/runtime/pubsub"
+	"github.com/dapr/dapr/pkg/runtime/secretref"
 	"github.com/dapr/dapr/pkg/runtime/security"
 	"github.com/dapr/dapr/pkg/scopes"
 	"github.com/dapr/dapr/utils"
@@ -89,11 +109,37 @@ import (
 
 const (
 	actorStateStore = "actorStateStore"
+	jobsStateStore  = "jobsStateStore"
+
+	// secretCacheTTLKey is the metadata key of secret stores enabling the cache of their secrets for the given duration.
+	secretCacheTTLKey = "secretCacheTTL"
+
+	// configurationDefaultsPathKey is the metadata key of configuration stores pointing to the local defaults of
+	// their items, a file or a mounted ConfigMap.
+	configurationDefaultsPathKey = "defaultsPath"
+
+	// secretRefCacheTTL is the time the secrets referenced by binding requests are cached for.
+	secretRefCacheTTL = time.Minute
 
 	// output bindings concurrency.
 	bindingsConcurrencyParallel   = "parallel"
 	bindingsConcurrencySequential = "sequential"
 	pubsubName                    = "pubsubName"
+
+	// input binding metadata naming the output binding events the app failed to process are sent to.
+	deadLetterBindingKey = "deadLetterBinding"
+	// input binding metadata naming the pubsub and the topic events the app failed to process are published to.
+	deadLetterPubsubKey = "deadLetterPubsub"
+	deadLetterTopicKey  = "deadLetterTopic"
+
+	// the app is considered exited after failing the given number of consecutive connection checks.
+	appExitCheckInterval = time.Second
+	appExitThreshold     = 3
+
+	// componentStatusReportTimeout is the timeout of the reports of the component statuses to the operator.
+	componentStatusReportTimeout = time.Second * 5
+	// componentHealthCheckInterval is the interval of the health checks of the components reported to the operator.
+	componentHealthCheckInterval = time.Second * 30
 )
 
 type ComponentCategory string
@@ -125,6 +171,17 @@ var log = logger.NewLogger("dapr.runtime")
 // was encountered when processing a cloud event's data property.
 var ErrUnexpectedEnvelopeData = errors.New("unexpected data type encountered in envelope")
 
+// errBindingEventDropped denotes that the app asked for an input binding event to be dropped.
+var errBindingEventDropped = errors.New("DROP status returned from app")
+
+// bindingDeadLetter is where the events of an input binding the app failed to process are sent: the topic they
+// are published to, or else the output binding they are sent to.
+type bindingDeadLetter struct {
+	binding string
+	pubsub  string
+	topic   string
+}
+
 type Route struct {
 	metadata map[string]string
 	rules    []*runtime_pubsub.Rule
@@ -136,11 +193,14 @@ type TopicRoute struct {
 
 // DaprRuntime holds all the core components of the runtime.
 type DaprRuntime struct {
-	runtimeConfig          *Config
-	globalConfig           *config.Configuration
-	accessControlList      *config.AccessControlList
-	componentsLock         *sync.RWMutex
-	components             []components_v1alpha1.Component
+	runtimeConfig     *Config
+	globalConfig      *config.Configuration
+	accessControlList *config.AccessControlList
+	componentsLock    *sync.RWMutex
+	components        []components_v1alpha1.Component
+	// componentsHealth holds the result of the initialization of the components by type and name. The loaded
+	// components without result are pending.
+	componentsHealth       map[string]health.ComponentDetails
 	grpc                   *grpc.Manager
 	appChannel             channel.AppChannel
 	appConfig              config.ApplicationConfig
@@ -163,8 +223,14 @@ type DaprRuntime struct {
 	hostAddress            string
 	actorStateStoreName    string
 	actorStateStoreLock    *sync.RWMutex
+	jobsStateStoreName     string
+	jobScheduler           *jobs.Scheduler
+	otlpMetricsExporter    *diag.OTLPMetricsExporter
+	profilesExporter       *diag.ProfilesExporter
 	authenticator          security.Authenticator
+	authenticatorLock      sync.RWMutex
 	namespace              string
+	podName                string
 	scopedSubscriptions    map[string][]string
 	scopedPublishings      map[string][]string
 	allowedTopics          map[string][]string
@@ -172,8 +238,26 @@ type DaprRuntime struct {
 	operatorClient         operatorv1pb.OperatorClient
 	topicRoutes            map[string]TopicRoute
 	inputBindingRoutes     map[string]string
+	inputBindingDeadLetter map[string]bindingDeadLetter
+	inputBindingGates      map[string]*runtime_bindings.Gate
+	correlator             *correlation.Correlator
+	secretRefResolver      *secretref.Resolver
 	shutdownC              chan error
 	apiClosers             []io.Closer
+	logLevelLock           sync.Mutex
+
+	// ctx is canceled when the runtime shuts down.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// logLevel and logScopes are the log levels set at runtime, guarded by logLevelLock.
+	logLevel  string
+	logScopes map[string]string
+
+	// reportedComponentStatuses holds the last status of each component reported to the operator, guarded by
+	// componentStatusesLock.
+	reportedComponentStatuses map[string]*operatorv1pb.ReportComponentStatusRequest
+	componentStatusesLock     sync.Mutex
 
 	secretsConfiguration map[string]config.SecretsScope
 
@@ -182,9 +266,21 @@ type DaprRuntime struct {
 
 	pendingComponents          chan components_v1alpha1.Component
 	pendingComponentDependents map[string][]components_v1alpha1.Component
+	// componentsInitLock serializes the registration of the initialized components, letting the components be
+	// initialized concurrently.
+	componentsInitLock sync.Mutex
+
+	// consumersStarted is true once the runtime subscribed to the pubsubs and started reading from the input
+	// bindings, after which the consumers of the reloaded components are restarted by the runtime.
+	consumersStarted bool
+	consumersLock    sync.Mutex
 
 	proxy messaging.Proxy
 
+	resiliency *resiliency.Resiliency
+	gateway    *messaging.GatewayRoutes
+	appHealth  *apphealth.AppHealth
+
 	// TODO: Remove feature flag once feature is ratified
 	featureRoutingEnabled bool
 }
@@ -203,6 +299,7 @@ type ComponentRegistry struct {
 
 type componentPreprocessRes struct {
 	unreadyDependency string
+	err               error
 }
 
 type pubsubSubscribedMessage struct {
@@ -211,16 +308,22 @@ type pubsubSubscribedMessage struct {
 	topic      string
 	metadata   map[string]string
 	path       string
+	// dropped is set when the event is acknowledged without being processed by the app.
+	dropped bool
 }
 
 // NewDaprRuntime returns a new runtime with the given runtime config and global config.
 func NewDaprRuntime(runtimeConfig *Config, globalConfig *config.Configuration, accessControlList *config.AccessControlList) *DaprRuntime {
-	return &DaprRuntime{
+	ctx, cancel := context.WithCancel(context.Background())
+	rt := &DaprRuntime{
+		ctx:                    ctx,
+		cancel:                 cancel,
 		runtimeConfig:          runtimeConfig,
 		globalConfig:           globalConfig,
 		accessControlList:      accessControlList,
 		componentsLock:         &sync.RWMutex{},
 		components:             make([]components_v1alpha1.Component, 0),
+		componentsHealth:       map[string]health.ComponentDetails{},
 		actorStateStoreLock:    &sync.RWMutex{},
 		grpc:                   grpc.NewGRPCManager(runtimeConfig.Mode),
 		json:                   jsoniter.ConfigFastest,
@@ -236,10 +339,13 @@ func NewDaprRuntime(runtimeConfig *Config, globalConfig *config.Configuration, a
 		nameResolutionRegistry: nr_loader.NewRegistry(),
 		httpMiddlewareRegistry: http_middleware_loader.NewRegistry(),
 
-		scopedSubscriptions: map[string][]string{},
-		scopedPublishings:   map[string][]string{},
-		allowedTopics:       map[string][]string{},
-		inputBindingRoutes:  map[string]string{},
+		scopedSubscriptions:    map[string][]string{},
+		scopedPublishings:      map[string][]string{},
+		allowedTopics:          map[string][]string{},
+		inputBindingRoutes:     map[string]string{},
+		inputBindingDeadLetter: map[string]bindingDeadLetter{},
+		inputBindingGates:      map[string]*runtime_bindings.Gate{},
+		correlator:             correlation.NewCorrelator(),
 
 		secretsConfiguration:       map[string]config.SecretsScope{},
 		configurationStoreRegistry: configuration_loader.NewRegistry(),
@@ -249,6 +355,8 @@ func NewDaprRuntime(runtimeConfig *Config, globalConfig *config.Configuration, a
 		pendingComponentDependents: map[string][]components_v1alpha1.Component{},
 		shutdownC:                  make(chan error, 1),
 	}
+	rt.secretRefResolver = secretref.NewResolver(rt.getSecretRefValue, secretRefCacheTTL)
+	return rt
 }
 
 // Run performs initialization of the runtime with the runtime and global configurations.
@@ -296,6 +404,10 @@ func (a *DaprRuntime) getOperatorClient() (operatorv1pb.OperatorClient, error) {
 // setupTracing set up the trace exporters. Technically we don't need to pass `hostAddress` in,
 // but we do so here to explicitly call out the dependency on having `hostAddress` computed.
 func (a *DaprRuntime) setupTracing(hostAddress string, exporters traceExporterStore) error {
+	if err := diag.SetTracingSamplingRules(a.globalConfig.Spec.TracingSpec.SamplingRules); err != nil {
+		return err
+	}
+
 	// Register stdout trace exporter if user wants to debug requests or log as Info level.
 	if a.globalConfig.Spec.TracingSpec.Stdout {
 		exporters.RegisterExporter(&diag_utils.StdoutExporter{})
@@ -314,12 +426,88 @@ func (a *DaprRuntime) setupTracing(hostAddress string, exporters traceExporterSt
 	return nil
 }
 
+// initOTLPMetrics starts pushing the metrics to the OpenTelemetry collector of the OTLP spec, if specified.
+func (a *DaprRuntime) initOTLPMetrics() error {
+	spec := a.globalConfig.Spec.MetricSpec.OTLP
+	if spec.EndpointAddress == "" {
+		return nil
+	}
+
+	var pushInterval time.Duration
+	if spec.PushInterval != "" {
+		var err error
+		if pushInterval, err = time.ParseDuration(spec.PushInterval); err != nil {
+			return errors.Wrapf(err, "invalid push interval %s", spec.PushInterval)
+		}
+	}
+	exporter, err := diag.NewOTLPMetricsExporter(diag.OTLPMetricsOptions{
+		EndpointAddress: spec.EndpointAddress,
+		Headers:         spec.Headers,
+		PushInterval:    pushInterval,
+		ServiceName:     a.runtimeConfig.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if err = exporter.Start(); err != nil {
+		return err
+	}
+	a.otlpMetricsExporter = exporter
+	log.Infof("pushing metrics to OTLP endpoint %s", spec.EndpointAddress)
+	return nil
+}
+
+// initProfiling starts pushing the profiles of the sidecar to the profiling server of the profiling spec, if specified.
+func (a *DaprRuntime) initProfiling() error {
+	spec := a.globalConfig.Spec.ProfilingSpec
+	if spec.EndpointAddress == "" {
+		return nil
+	}
+
+	var interval time.Duration
+	if spec.Interval != "" {
+		var err error
+		if interval, err = time.ParseDuration(spec.Interval); err != nil {
+			return errors.Wrapf(err, "invalid profiling interval %s", spec.Interval)
+		}
+	}
+	exporter, err := diag.NewProfilesExporter(diag.ProfilingOptions{
+		EndpointAddress: spec.EndpointAddress,
+		Headers:         spec.Headers,
+		Interval:        interval,
+		Profiles:        spec.Profiles,
+		AppID:           a.runtimeConfig.ID,
+	})
+	if err != nil {
+		return err
+	}
+	exporter.Start()
+	a.profilesExporter = exporter
+	log.Infof("pushing profiles to %s", spec.EndpointAddress)
+	return nil
+}
+
 func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 	// Initialize metrics only if MetricSpec is enabled.
 	if a.globalConfig.Spec.MetricSpec.Enabled {
-		if err := diag.InitMetrics(a.runtimeConfig.ID); err != nil {
+		if err := diag.InitMetrics(a.runtimeConfig.ID, a.globalConfig.Spec.MetricSpec); err != nil {
 			log.Errorf("failed to initialize metrics: %v", err)
 		}
+		if err := a.initOTLPMetrics(); err != nil {
+			log.Errorf("failed to initialize OTLP metrics exporter: %v", err)
+		}
+	}
+
+	if err := diag.DefaultAccessLog.Init(a.runtimeConfig.ID, a.globalConfig.Spec.AccessLogSpec); err != nil {
+		log.Errorf("failed to initialize access log: %v", err)
+	}
+
+	if err := audit.Default.Init(audit.SourceDaprd, a.runtimeConfig.ID, a.globalConfig.Spec.AuditSpec, a.publishAuditEvent); err != nil {
+		log.Errorf("failed to initialize audit log: %v", err)
+	}
+
+	if err := a.initProfiling(); err != nil {
+		log.Errorf("failed to initialize profiles exporter: %v", err)
 	}
 
 	err := a.establishSecurity(a.runtimeConfig.SentryServiceAddress)
@@ -327,17 +515,38 @@ func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 		return err
 	}
 	a.namespace = a.getNamespace()
+	a.podName = os.Getenv(env.PodName)
+	a.reportedComponentStatuses = map[string]*operatorv1pb.ReportComponentStatusRequest{}
 	a.operatorClient, err = a.getOperatorClient()
 	if err != nil {
 		return err
 	}
 
+	if err = a.applyLoggingSpec(a.globalConfig.Spec.LoggingSpec); err != nil {
+		log.Warnf("failed to apply logging spec: %s", err)
+	}
+
 	if a.hostAddress, err = utils.GetHostAddress(); err != nil {
 		return errors.Wrap(err, "failed to determine host address")
 	}
 	if err = a.setupTracing(a.hostAddress, openCensusExporterStore{}); err != nil {
 		return errors.Wrap(err, "failed to setup tracing")
 	}
+	a.resiliency, err = resiliency.FromConfiguration(a.globalConfig.Spec.ResiliencySpec)
+	if err != nil {
+		log.Warnf("failed to load resiliency policies: %s", err)
+		// no policies are applied until the resiliency spec is fixed.
+		a.resiliency, _ = resiliency.FromConfiguration(config.ResiliencySpec{})
+	}
+	if err = chaos.Configure(a.globalConfig.Spec.ChaosSpec); err != nil {
+		log.Warnf("failed to load fault injection: %s", err)
+	}
+	a.watchConfiguration()
+	a.gateway, err = messaging.NewGatewayRoutes(a.globalConfig.Spec.GatewaySpec, a.namespace)
+	if err != nil {
+		log.Warnf("failed to load gateway routes: %s", err)
+	}
+
 	// Register and initialize name resolution for service discovery.
 	a.nameResolutionRegistry.Register(opts.nameResolutions...)
 	err = a.initNameResolution()
@@ -351,6 +560,7 @@ func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 	a.configurationStoreRegistry.Register(opts.configurations...)
 	a.bindingsRegistry.RegisterInputBindings(opts.inputBindings...)
 	a.bindingsRegistry.RegisterOutputBindings(opts.outputBindings...)
+	a.httpMiddlewareRegistry.Register(a.builtinHTTPMiddleware()...)
 	a.httpMiddlewareRegistry.Register(opts.httpMiddleware...)
 
 	go a.processComponents()
@@ -360,7 +570,9 @@ func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 	}
 	a.appendBuiltinSecretStore()
 	err = a.loadComponents(opts)
-	if err != nil {
+	if errors.Is(err, components.ErrDecryptComponents) {
+		return err
+	} else if err != nil {
 		log.Warnf("failed to load components: %s", err)
 	}
 
@@ -374,13 +586,18 @@ func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 	// Setup allow/deny list for secrets
 	a.populateSecretsConfiguration()
 
+	err = a.initJobs()
+	if err != nil {
+		log.Warnf("failed to init jobs: %s", err)
+	}
+
 	// Start proxy
 	a.initProxy()
 
 	// Create and start internal and external gRPC servers
 	grpcAPI := a.getGRPCAPI()
 
-	err = a.startGRPCAPIServer(grpcAPI, a.runtimeConfig.APIGRPCPort)
+	err = a.startGRPCAPIServer(grpcAPI, a.runtimeConfig.APIGRPCPort, opts.grpcPipeline)
 	if err != nil {
 		log.Fatalf("failed to start API gRPC server: %s", err)
 	}
@@ -413,6 +630,7 @@ func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 	}
 
 	a.blockUntilAppIsReady()
+	a.watchAppExit()
 
 	err = a.createAppChannel()
 	if err != nil {
@@ -423,6 +641,9 @@ func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 
 	a.loadAppConfiguration()
 
+	a.initAppHealthCheck()
+	grpcAPI.SetAppHealth(a.appHealth)
+
 	a.initDirectMessaging(a.nameResolver)
 
 	a.daprHTTPAPI.SetDirectMessaging(a.directMessaging)
@@ -436,6 +657,10 @@ func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 	a.daprHTTPAPI.SetActorRuntime(a.actor)
 	grpcAPI.SetActorRuntime(a.actor)
 
+	if a.jobScheduler != nil {
+		a.jobScheduler.Start()
+	}
+
 	// TODO: Remove feature flag once feature is ratified
 	a.featureRoutingEnabled = config.IsFeatureEnabled(a.globalConfig.Spec.Features, config.PubSubRouting)
 
@@ -453,14 +678,92 @@ func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 		}
 	}
 
+	a.consumersLock.Lock()
 	a.startSubscribing()
 	err = a.startReadingFromBindings()
 	if err != nil {
 		log.Warnf("failed to read from bindings: %s ", err)
 	}
+	a.consumersStarted = true
+	a.consumersLock.Unlock()
+	return nil
+}
+
+// initJobs creates the scheduler of the jobs of the app if a jobs state store is configured.
+func (a *DaprRuntime) initJobs() error {
+	if a.jobsStateStoreName == "" {
+		return nil
+	}
+
+	owner := fmt.Sprintf("%s:%v", a.hostAddress, a.runtimeConfig.InternalGRPCPort)
+	scheduler, err := jobs.NewScheduler(a.runtimeConfig.ID, owner, a.stateStores[a.jobsStateStoreName], a.invokeJob, a.deadLetterJob)
+	if err != nil {
+		return err
+	}
+	a.jobScheduler = scheduler
+	return nil
+}
+
+// invokeJob invokes the app endpoint of a job with the data of the job.
+func (a *DaprRuntime) invokeJob(ctx context.Context, job *jobs.Job) error {
+	if a.appChannel == nil {
+		return errors.New("app channel is not initialized")
+	}
+
+	data, err := a.json.Marshal(job.Data)
+	if err != nil {
+		return err
+	}
+
+	req := invokev1.NewInvokeMethodRequest(job.Method)
+	req.WithHTTPExtension(nethttp.MethodPost, "")
+	req.WithRawData(data, invokev1.JSONContentType)
+
+	resp, err := a.appChannel.InvokeMethod(ctx, req)
+	if err != nil {
+		return errors.Wrap(err, "error invoking app")
+	}
+	if code := resp.Status().Code; code < 200 || code > 299 {
+		_, body := resp.RawData()
+		return errors.Errorf("job invocation failed with status code: %d body: %s", code, string(body))
+	}
 	return nil
 }
 
+// deadLetterJob publishes a run of a job which failed all its attempts to the dead-letter topic of its failure policy.
+// The event is the job with the error of its last attempt.
+func (a *DaprRuntime) deadLetterJob(ctx context.Context, job *jobs.Job, cause error) error {
+	policy := job.FailurePolicy
+	data, err := a.json.Marshal(map[string]interface{}{
+		"job":   job,
+		"error": cause.Error(),
+	})
+	if err != nil {
+		return err
+	}
+
+	envelope, err := runtime_pubsub.NewCloudEvent(&runtime_pubsub.CloudEvent{
+		ID:              a.runtimeConfig.ID,
+		Topic:           policy.DeadLetterTopic,
+		Pubsub:          policy.DeadLetterPubsub,
+		DataContentType: invokev1.JSONContentType,
+		Data:            data,
+	})
+	if err != nil {
+		return err
+	}
+	b, err := a.json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	return a.publish(a.ctx, &pubsub.PublishRequest{
+		PubsubName: policy.DeadLetterPubsub,
+		Topic:      policy.DeadLetterTopic,
+		Data:       b,
+	}, diag.PubsubOutcomeDeadLetter)
+}
+
 func (a *DaprRuntime) populateSecretsConfiguration() {
 	// Populate in a map for easy lookup by store name.
 	for _, scope := range a.globalConfig.Spec.Secrets.Scopes {
@@ -469,28 +772,81 @@ func (a *DaprRuntime) populateSecretsConfiguration() {
 }
 
 func (a *DaprRuntime) buildHTTPPipeline() (http_middleware.Pipeline, error) {
+	if a.globalConfig == nil {
+		return http_middleware.Pipeline{}, nil
+	}
+	return a.buildHTTPPipelineForSpec(a.globalConfig.Spec.HTTPPipelineSpec, "http")
+}
+
+// buildAppHTTPPipeline builds the middleware pipeline applied to the calls of the sidecar to the app over HTTP.
+func (a *DaprRuntime) buildAppHTTPPipeline() (http_middleware.Pipeline, error) {
+	if a.globalConfig == nil {
+		return http_middleware.Pipeline{}, nil
+	}
+	return a.buildHTTPPipelineForSpec(a.globalConfig.Spec.AppHTTPPipelineSpec, "app channel http")
+}
+
+// builtinHTTPMiddleware returns the HTTP middleware backed by the components of the runtime.
+// They are registered before the middleware of the options, which can override them.
+func (a *DaprRuntime) builtinHTTPMiddleware() []http_middleware_loader.Middleware {
+	return []http_middleware_loader.Middleware{
+		http_middleware_loader.New("ratelimit/v2", func(metadata middleware.Metadata) (http_middleware.Middleware, error) {
+			return http_ratelimit.NewRateLimitMiddleware(log, func(name string) (state.Store, bool) {
+				// The state stores are looked up on each request while the components are updated.
+				a.componentsInitLock.Lock()
+				defer a.componentsInitLock.Unlock()
+				store, ok := a.stateStores[name]
+				return store, ok
+			}).GetHandler(metadata)
+		}),
+	}
+}
+
+func (a *DaprRuntime) buildHTTPPipelineForSpec(spec config.PipelineSpec, pipelineName string) (http_middleware.Pipeline, error) {
+	handlers, err := a.buildHTTPMiddleware(spec.Handlers, pipelineName)
+	if err != nil {
+		return http_middleware.Pipeline{}, err
+	}
+
+	var routes []http_middleware.RoutePipeline
+	for _, routeSpec := range spec.Routes {
+		if err = http_middleware.ValidatePath(routeSpec.Path); err != nil {
+			return http_middleware.Pipeline{}, errors.Wrapf(err, "invalid path %s of %s pipeline route", routeSpec.Path, pipelineName)
+		}
+		routeHandlers, err := a.buildHTTPMiddleware(routeSpec.Handlers, fmt.Sprintf("%s route %s", pipelineName, routeSpec.Path))
+		if err != nil {
+			return http_middleware.Pipeline{}, err
+		}
+		routes = append(routes, http_middleware.RoutePipeline{
+			Path:     routeSpec.Path,
+			Methods:  routeSpec.Methods,
+			Handlers: routeHandlers,
+		})
+	}
+	return http_middleware.Pipeline{Handlers: handlers, Routes: routes}, nil
+}
+
+func (a *DaprRuntime) buildHTTPMiddleware(specs []config.HandlerSpec, pipelineName string) ([]http_middleware.Middleware, error) {
 	var handlers []http_middleware.Middleware
 
-	if a.globalConfig != nil {
-		for i := 0; i < len(a.globalConfig.Spec.HTTPPipelineSpec.Handlers); i++ {
-			middlewareSpec := a.globalConfig.Spec.HTTPPipelineSpec.Handlers[i]
-			component, exists := a.getComponent(middlewareSpec.Type, middlewareSpec.Name)
-			if !exists {
-				return http_middleware.Pipeline{}, errors.Errorf("couldn't find middleware component with name %s and type %s/%s",
-					middlewareSpec.Name,
-					middlewareSpec.Type,
-					middlewareSpec.Version)
-			}
-			handler, err := a.httpMiddlewareRegistry.Create(middlewareSpec.Type, middlewareSpec.Version,
-				middleware.Metadata{Properties: a.convertMetadataItemsToProperties(component.Spec.Metadata)})
-			if err != nil {
-				return http_middleware.Pipeline{}, err
-			}
-			log.Infof("enabled %s/%s http middleware", middlewareSpec.Type, middlewareSpec.Version)
-			handlers = append(handlers, handler)
+	for i := 0; i < len(specs); i++ {
+		middlewareSpec := specs[i]
+		component, exists := a.getComponent(middlewareSpec.Type, middlewareSpec.Name)
+		if !exists {
+			return nil, errors.Errorf("couldn't find middleware component with name %s and type %s/%s",
+				middlewareSpec.Name,
+				middlewareSpec.Type,
+				middlewareSpec.Version)
+		}
+		handler, err := a.httpMiddlewareRegistry.Create(middlewareSpec.Type, middlewareSpec.Version,
+			middleware.Metadata{Properties: a.convertMetadataItemsToProperties(component.Spec.Metadata)})
+		if err != nil {
+			return nil, err
 		}
+		log.Infof("enabled %s/%s %s middleware", middlewareSpec.Type, middlewareSpec.Version, pipelineName)
+		handlers = append(handlers, handler)
 	}
-	return http_middleware.Pipeline{Handlers: handlers}, nil
+	return handlers, nil
 }
 
 func (a *DaprRuntime) initBinding(c components_v1alpha1.Component) error {
@@ -502,6 +858,9 @@ func (a *DaprRuntime) initBinding(c components_v1alpha1.Component) error {
 	}
 
 	if a.bindingsRegistry.HasInputBinding(c.Spec.Type, c.Spec.Version) {
+		if c.Spec.InitMode == components_v1alpha1.InitModeLazy {
+			log.Warnf("input binding %s is initialized when daprd starts, only output bindings support the %s init mode", c.ObjectMeta.Name, c.Spec.InitMode)
+		}
 		if err := a.initInputBinding(c); err != nil {
 			log.Errorf("failed to init input bindings: %s", err)
 			return err
@@ -540,7 +899,25 @@ func (a *DaprRuntime) beginPubSub(name string, ps pubsub.PubSub) error {
 		if err := ps.Subscribe(pubsub.SubscribeRequest{
 			Topic:    topic,
 			Metadata: route.metadata,
-		}, func(ctx context.Context, msg *pubsub.NewMessage) error {
+		}, func(ctx context.Context, msg *pubsub.NewMessage) (err error) {
+			var cloudEvent map[string]interface{}
+			dropped := false
+			defer func() {
+				a.recordPubsubIngress(name, msg.Topic, cloudEvent, dropped, err)
+			}()
+
+			if !a.appHealth.IsHealthy() {
+				// Returning an error leaves the event with the broker so it is redelivered once the app recovers.
+				return errors.Errorf("app is not healthy, cannot deliver event on topic %s in pubsub %s", msg.Topic, name)
+			}
+
+			done, ok := drain.Default.Begin(drain.PubSub)
+			if !ok {
+				// The event is left with the broker as well, to be delivered to another replica of the app.
+				return errors.Errorf("sidecar is shutting down, cannot deliver event on topic %s in pubsub %s", msg.Topic, name)
+			}
+			defer done()
+
 			if msg.Metadata == nil {
 				msg.Metadata = make(map[string]string, 1)
 			}
@@ -553,7 +930,6 @@ func (a *DaprRuntime) beginPubSub(name string, ps pubsub.PubSub) error {
 				return err
 			}
 
-			var cloudEvent map[string]interface{}
 			data := msg.Data
 			if rawPayload {
 				cloudEvent = pubsub.FromRawPayload(msg.Data, msg.Topic, name)
@@ -572,6 +948,7 @@ func (a *DaprRuntime) beginPubSub(name string, ps pubsub.PubSub) error {
 
 			if pubsub.HasExpired(cloudEvent) {
 				log.Warnf("dropping expired pub/sub event %v as of %v", cloudEvent[pubsub.IDField], cloudEvent[pubsub.ExpirationField])
+				dropped = true
 
 				return nil
 			}
@@ -583,24 +960,73 @@ func (a *DaprRuntime) beginPubSub(name string, ps pubsub.PubSub) error {
 			if !shouldProcess {
 				// The event does not match any route specified so ignore it.
 				log.Debugf("no matching route for event %v in pubsub %s and topic %s; skipping", cloudEvent[pubsub.IDField], name, msg.Topic)
+				dropped = true
 				return nil
 			}
 
-			return publishFunc(ctx, &pubsubSubscribedMessage{
+			psm := &pubsubSubscribedMessage{
 				cloudEvent: cloudEvent,
 				data:       data,
 				topic:      msg.Topic,
 				metadata:   msg.Metadata,
 				path:       routePath,
-			})
+			}
+			err = a.deliverPubsubEvent(ctx, name, psm, publishFunc)
+			dropped = psm.dropped
+			return err
 		}); err != nil {
 			log.Errorf("failed to subscribe to topic %s: %s", topic, err)
+			diag.DefaultEventLog.Record(diag.RuntimeEventSubscriptionFailed, "failed to subscribe to topic %s in pubsub %s, see the logs of the sidecar for the error", topic, name)
 		}
 	}
 
 	return nil
 }
 
+// deliverPubsubEvent sends an event of a topic to the app, retrying it according to the retry policy of the topic
+// within the retry budget of the pubsub.
+func (a *DaprRuntime) deliverPubsubEvent(ctx context.Context, pubsubName string, msg *pubsubSubscribedMessage, publishFunc func(ctx context.Context, msg *pubsubSubscribedMessage) error) error {
+	policy := a.resiliency.TopicRetryPolicy(pubsubName, msg.topic)
+	budget := a.resiliency.ComponentRetryBudget(pubsubName)
+	budget.Request()
+
+	for attempt := 0; ; attempt++ {
+		op := diag.StartPubsubOperation(ctx, a.globalConfig.Spec.TracingSpec, pubsubName, "deliver")
+		err := chaos.Inject(ctx, chaos.PubSub, pubsubName)
+		if err == nil {
+			err = publishFunc(ctx, msg)
+		}
+		op.End(err)
+		if err == nil || policy == nil || attempt >= policy.MaxRetries || !budget.TryRetry() {
+			return err
+		}
+		log.Debugf("retrying event of topic %s in pubsub %s: %s", msg.topic, pubsubName, err)
+		select {
+		case <-time.After(policy.Interval):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// recordPubsubIngress records the outcome of the delivery of an event received from a topic, and its end to end
+// latency when its CloudEvent has a time.
+func (a *DaprRuntime) recordPubsubIngress(pubsubName, topic string, cloudEvent map[string]interface{}, dropped bool, err error) {
+	outcome := diag.PubsubOutcomeSuccess
+	if err != nil {
+		outcome = diag.PubsubOutcomeRetry
+	} else if dropped {
+		outcome = diag.PubsubOutcomeDrop
+	}
+	diag.DefaultMonitoring.PubsubIngressEvent(pubsubName, topic, outcome)
+
+	if t, ok := cloudEvent[runtime_pubsub.TimeField].(string); ok {
+		if published, parseErr := time.Parse(time.RFC3339Nano, t); parseErr == nil {
+			diag.DefaultMonitoring.PubsubIngressLatency(pubsubName, topic, outcome, float64(time.Since(published))/float64(time.Millisecond))
+		}
+	}
+}
+
 // findMatchingRoute selects the path based on routing rules. If there are
 // no matching rules, the route-level path is used.
 func findMatchingRoute(rules []*runtime_pubsub.Rule, cloudEvent interface{}, routingEnabled bool) (path string, shouldProcess bool, err error) {
@@ -661,6 +1087,9 @@ func (a *DaprRuntime) initDirectMessaging(resolver nr.Resolver) {
 		a.proxy,
 		a.runtimeConfig.ReadBufferSize,
 		a.runtimeConfig.StreamRequestBody,
+		a.resiliency,
+		a.gateway,
+		a.globalConfig.Spec.CompressionSpec,
 	)
 }
 
@@ -676,6 +1105,7 @@ func (a *DaprRuntime) beginComponentsUpdates() error {
 		return nil
 	}
 
+	go a.watchComponentsHealth()
 	go func() {
 		parseAndUpdate := func(compRaw []byte) {
 			var component components_v1alpha1.Component
@@ -704,7 +1134,8 @@ func (a *DaprRuntime) beginComponentsUpdates() error {
 			backoff.Retry(func() error {
 				var err error
 				stream, err = a.operatorClient.ComponentUpdate(context.Background(), &operatorv1pb.ComponentUpdateRequest{
-					Namespace: a.namespace,
+					Namespace:          a.namespace,
+					DefaultSecretStore: a.globalConfig.Spec.Secrets.DefaultStore,
 				})
 				if err != nil {
 					log.Errorf("error from operator stream: %s", err)
@@ -714,10 +1145,13 @@ func (a *DaprRuntime) beginComponentsUpdates() error {
 			}, backoff.NewExponentialBackOff())
 
 			if needList {
+				a.reportComponentStatuses()
+
 				// We should get all components again to avoid missing any updates during the failure time.
 				backoff.Retry(func() error {
 					resp, err := a.operatorClient.ListComponents(context.Background(), &operatorv1pb.ListComponentsRequest{
-						Namespace: a.namespace,
+						Namespace:          a.namespace,
+						DefaultSecretStore: a.globalConfig.Spec.Secrets.DefaultStore,
 					})
 					if err != nil {
 						log.Errorf("error listing components: %s", err)
@@ -760,6 +1194,10 @@ func (a *DaprRuntime) onComponentUpdated(component components_v1alpha1.Component
 		return false
 	}
 
+	audit.Default.Emit(audit.Event{
+		Type:     audit.EventComponentUpdated,
+		Resource: fmt.Sprintf("%s (%s)", component.Name, component.Spec.Type),
+	})
 	a.pendingComponents <- component
 	return true
 }
@@ -792,22 +1230,224 @@ func (a *DaprRuntime) sendBatchOutputBindingsSequential(to []string, data []byte
 }
 
 func (a *DaprRuntime) sendToOutputBinding(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
-	if req.Operation == "" {
+	resp, err := a.invokeOutputBinding(name, req)
+	if err == nil {
+		return resp, nil
+	}
+
+	policy := a.resiliency.ComponentFallback(name)
+	if policy == nil {
+		return nil, err
+	}
+	if policy.Response != nil {
+		log.Debugf("invocation of output binding %s failed, returning the static response of its fallback: %s", name, err)
+		return &bindings.InvokeResponse{Data: policy.Response.Body}, nil
+	}
+	// The fallback of the fallback binding isn't applied so fallbacks can't loop.
+	log.Debugf("invocation of output binding %s failed, invoking its fallback binding %s: %s", name, policy.Component, err)
+	return a.invokeOutputBinding(policy.Component, req)
+}
+
+// invokeOutputBinding invokes an output binding, applying its resiliency policies.
+func (a *DaprRuntime) invokeOutputBinding(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	binding, err := a.getOutputBindingForOperation(name, req.Operation)
+	if err != nil {
+		return nil, err
+	}
+	if req, err = a.resolveSecretRefs(req); err != nil {
+		return nil, err
+	}
+
+	// A lazily initialized binding is initialized before the operation, so the failures of its initialization, which
+	// are retried after their own backoff, aren't fed to the concurrency limiter of the binding.
+	if lazy, ok := binding.(*bindings_loader.LazyOutputBinding); ok {
+		if err = lazy.Initialize(); err != nil {
+			return nil, err
+		}
+	}
+
+	var resp *bindings.InvokeResponse
+	err = a.resiliency.ComponentOperation(name, func() (err error) {
+		if _, ok := req.Metadata[correlation.ReplyTimeoutKey]; ok {
+			resp, err = a.invokeOutputBindingWithReply(binding, req)
+		} else {
+			resp, err = binding.Invoke(req)
+		}
+		return err
+	})
+	return resp, err
+}
+
+// invokeOutputBindingWithReply sends a request with a correlation ID to the output binding and waits for the
+// reply with the same correlation ID to be received by the input binding named by the request.
+func (a *DaprRuntime) invokeOutputBindingWithReply(binding bindings.OutputBinding, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	timeout, err := time.ParseDuration(req.Metadata[correlation.ReplyTimeoutKey])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", correlation.ReplyTimeoutKey)
+	}
+	replyBinding := req.Metadata[correlation.ReplyBindingKey]
+	if _, ok := a.inputBindings[replyBinding]; !ok {
+		return nil, errors.Errorf("%s must name the input binding receiving the reply", correlation.ReplyBindingKey)
+	}
+
+	metadata := make(map[string]string, len(req.Metadata))
+	for k, v := range req.Metadata {
+		if k != correlation.ReplyTimeoutKey && k != correlation.ReplyBindingKey {
+			metadata[k] = v
+		}
+	}
+	id := metadata[correlation.IDKey]
+	if id == "" {
+		id = uuid.New().String()
+		metadata[correlation.IDKey] = id
+	}
+
+	r := *req
+	r.Metadata = metadata
+	reply, err := a.correlator.Await(id, replyBinding, timeout, func() error {
+		_, err := binding.Invoke(&r)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.InvokeResponse{
+		Data:     reply.Data,
+		Metadata: reply.Metadata,
+	}, nil
+}
+
+// sendToOutputBindingStream invokes an output binding and returns its response as a stream.
+// Bindings which don't support streaming have their response buffered and wrapped.
+func (a *DaprRuntime) sendToOutputBindingStream(name string, req *bindings.InvokeRequest) (*bindings_loader.StreamingInvokeResponse, error) {
+	binding, err := a.getOutputBindingForOperation(name, req.Operation)
+	if err != nil {
+		return nil, err
+	}
+	if req, err = a.resolveSecretRefs(req); err != nil {
+		return nil, err
+	}
+	if streaming, ok := binding.(bindings_loader.StreamingOutputBinding); ok {
+		return streaming.InvokeStream(req)
+	}
+	resp, err := binding.Invoke(req)
+	if err != nil {
+		return nil, err
+	}
+	return bindings_loader.NewStreamingInvokeResponse(resp), nil
+}
+
+// sendToOutputBindingBulk sends several requests to an output binding and returns the result of each request.
+// Bindings supporting it receive all the valid requests at once, other bindings are invoked for each request.
+func (a *DaprRuntime) sendToOutputBindingBulk(name string, reqs []*bindings.InvokeRequest) ([]bindings_loader.BulkInvokeResult, error) {
+	binding, ok := a.outputBindings[name]
+	if !ok {
+		return nil, errors.Errorf("couldn't find output binding %s", name)
+	}
+
+	results := make([]bindings_loader.BulkInvokeResult, len(reqs))
+	valid := make([]*bindings.InvokeRequest, 0, len(reqs))
+	validIndexes := make([]int, 0, len(reqs))
+	for i, req := range reqs {
+		if _, err := a.getOutputBindingForOperation(name, req.Operation); err != nil {
+			results[i].Error = err
+			continue
+		}
+		req, err := a.resolveSecretRefs(req)
+		if err != nil {
+			results[i].Error = err
+			continue
+		}
+		valid = append(valid, req)
+		validIndexes = append(validIndexes, i)
+	}
+
+	if bulk, ok := binding.(bindings_loader.BulkOutputBinding); ok && len(valid) > 0 {
+		bulkResults, err := bulk.BulkInvoke(valid)
+		if err != nil {
+			return nil, err
+		}
+		if len(bulkResults) != len(valid) {
+			return nil, errors.Errorf("binding %s returned %d results for %d requests", name, len(bulkResults), len(valid))
+		}
+		for i, r := range bulkResults {
+			results[validIndexes[i]] = r
+		}
+		return results, nil
+	}
+
+	for i, req := range valid {
+		resp, err := binding.Invoke(req)
+		results[validIndexes[i]] = bindings_loader.BulkInvokeResult{Response: resp, Error: err}
+	}
+	return results, nil
+}
+
+// resolveSecretRefs returns the request with the secret references of its metadata, e.g. {secretRef:store/key},
+// replaced by the values of the secrets.
+func (a *DaprRuntime) resolveSecretRefs(req *bindings.InvokeRequest) (*bindings.InvokeRequest, error) {
+	metadata, err := a.secretRefResolver.Resolve(req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	r := *req
+	r.Metadata = metadata
+	return &r, nil
+}
+
+// getSecretRefValue returns the value of a secret referenced by a binding request.
+// Secrets are subject to the access scopes of the secret store.
+func (a *DaprRuntime) getSecretRefValue(storeName, key string) (string, error) {
+	store, ok := a.secretStores[storeName]
+	if !ok {
+		return "", errors.Errorf("secret store %s not found", storeName)
+	}
+	if scope, ok := a.secretsConfiguration[storeName]; ok {
+		if !scope.IsOperationAllowed(config.SecretOperationGet) {
+			return "", errors.Errorf(messages.ErrSecretOperationDenied, config.SecretOperationGet, storeName)
+		}
+		if !scope.IsSecretAllowed(key) {
+			return "", errors.Errorf(messages.ErrPermissionDenied, key, storeName)
+		}
+	}
+
+	resp, err := store.GetSecret(secretstores.GetSecretRequest{Name: key})
+	if err != nil {
+		return "", err
+	}
+	value, ok := resp.Data[key]
+	if !ok {
+		return "", errors.Errorf("secret %s not found in secret store %s", key, storeName)
+	}
+	return value, nil
+}
+
+// getOutputBindingOperations returns the description of the operations supported by an output binding.
+func (a *DaprRuntime) getOutputBindingOperations(name string) ([]bindings_loader.OperationDescription, error) {
+	binding, ok := a.outputBindings[name]
+	if !ok {
+		return nil, errors.Errorf("couldn't find output binding %s", name)
+	}
+	return bindings_loader.DescribeOperations(binding), nil
+}
+
+func (a *DaprRuntime) getOutputBindingForOperation(name string, operation bindings.OperationKind) (bindings.OutputBinding, error) {
+	if operation == "" {
 		return nil, errors.New("operation field is missing from request")
 	}
 
 	if binding, ok := a.outputBindings[name]; ok {
 		ops := binding.Operations()
 		for _, o := range ops {
-			if o == req.Operation {
-				return binding.Invoke(req)
+			if o == operation {
+				return binding, nil
 			}
 		}
 		supported := make([]string, 0, len(ops))
 		for _, o := range ops {
 			supported = append(supported, string(o))
 		}
-		return nil, errors.Errorf("binding %s does not support operation %s. supported operations:%s", name, req.Operation, strings.Join(supported, " "))
+		return nil, errors.Errorf("binding %s does not support operation %s. supported operations:%s", name, operation, strings.Join(supported, " "))
 	}
 	return nil, errors.Errorf("couldn't find output binding %s", name)
 }
@@ -924,9 +1564,21 @@ func (a *DaprRuntime) sendBindingEventToApp(bindingName string, data []byte, met
 			span.End()
 		}
 		// ::TODO report metrics for http, such as grpc
-		if resp.Status().Code != nethttp.StatusOK {
+		statusCode := resp.Status().Code
+		if statusCode < 200 || statusCode > 299 {
 			_, body := resp.RawData()
-			return nil, errors.Errorf("fails to send binding event to http app channel, status code: %d body: %s", resp.Status().Code, string(body))
+			return nil, errors.Errorf("fails to send binding event to http app channel, status code: %d body: %s", statusCode, string(body))
+		}
+
+		// Like for pub/sub events, the app can ask for the event to be retried or dropped.
+		var appResponse pubsub.AppResponse
+		if err := a.json.Unmarshal(resp.Message().GetData().GetValue(), &appResponse); err == nil {
+			switch appResponse.Status {
+			case pubsub.Retry:
+				return nil, errors.New("RETRY status returned from app")
+			case pubsub.Drop:
+				return nil, errBindingEventDropped
+			}
 		}
 
 		if resp.Message().Data != nil && len(resp.Message().Data.Value) > 0 {
@@ -944,9 +1596,21 @@ func (a *DaprRuntime) sendBindingEventToApp(bindingName string, data []byte, met
 }
 
 func (a *DaprRuntime) readFromBinding(name string, binding bindings.InputBinding) error {
+	gate := a.inputBindingGates[name]
 	err := binding.Read(func(resp *bindings.ReadResponse) ([]byte, error) {
 		if resp != nil {
-			b, err := a.sendBindingEventToApp(name, resp.Data, resp.Metadata)
+			// a paused binding holds the event, which stops it from reading further events.
+			// the event isn't acknowledged if the runtime shuts down while the binding is paused.
+			if gate != nil {
+				if err := gate.Wait(a.ctx); err != nil {
+					return nil, err
+				}
+			}
+			// replies to output binding requests are routed to the awaiting caller instead of the app.
+			if a.correlator.Deliver(name, resp) {
+				return nil, nil
+			}
+			b, err := a.deliverBindingEvent(name, resp)
 			if err != nil {
 				log.Debugf("error from app consumer for binding [%s]: %s", name, err)
 				return nil, err
@@ -958,10 +1622,123 @@ func (a *DaprRuntime) readFromBinding(name string, binding bindings.InputBinding
 	return err
 }
 
-func (a *DaprRuntime) startHTTPServer(port int, publicPort *int, profilePort int, allowedOrigins string, pipeline http_middleware.Pipeline) error {
+// setInputBindingPaused pauses or resumes the delivery of the events of an input binding.
+func (a *DaprRuntime) setInputBindingPaused(name string, paused bool) error {
+	gate, ok := a.inputBindingGates[name]
+	if !ok {
+		return errors.Errorf("couldn't find input binding %s", name)
+	}
+
+	if paused {
+		gate.Pause()
+		log.Infof("paused input binding %s", name)
+	} else {
+		gate.Resume()
+		log.Infof("resumed input binding %s", name)
+	}
+	return nil
+}
+
+// deliverBindingEvent sends an input binding event to the app, retrying it according to the retry policy of the binding
+// within its retry budget.
+// Events dropped by the app are acknowledged. Events the app failed to process are sent to the dead letter
+// of the input binding if there is one, otherwise the error is returned to the binding.
+func (a *DaprRuntime) deliverBindingEvent(name string, resp *bindings.ReadResponse) ([]byte, error) {
+	policy := a.resiliency.ComponentRetryPolicy(name)
+	budget := a.resiliency.ComponentRetryBudget(name)
+	budget.Request()
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		var b []byte
+		b, err = a.sendBindingEventToApp(name, resp.Data, resp.Metadata)
+		if err == nil {
+			return b, nil
+		}
+		if errors.Is(err, errBindingEventDropped) {
+			log.Warnf("dropping event of input binding %s: %s", name, err)
+			return nil, nil
+		}
+		if policy == nil || attempt >= policy.MaxRetries || !budget.TryRetry() {
+			break
+		}
+		log.Debugf("retrying event of input binding %s: %s", name, err)
+		select {
+		case <-time.After(policy.Interval):
+		case <-a.ctx.Done():
+			// the event isn't dead lettered as the runtime is shutting down.
+			return nil, err
+		}
+	}
+
+	deadLetter, ok := a.inputBindingDeadLetter[name]
+	if !ok {
+		return nil, err
+	}
+	if dlErr := a.deadLetterBindingEvent(name, deadLetter, resp, err); dlErr != nil {
+		log.Errorf("failed to send event of input binding %s to its dead letter: %s", name, dlErr)
+		return nil, err
+	}
+	log.Warnf("event of input binding %s sent to its dead letter: %s", name, err)
+	return nil, nil
+}
+
+// deadLetterBindingEvent publishes an input binding event the app failed to process to the dead letter topic of the
+// input binding, or sends it to its dead letter output binding.
+// The published event is the data of the event with its metadata and the error of its last attempt.
+func (a *DaprRuntime) deadLetterBindingEvent(name string, deadLetter bindingDeadLetter, resp *bindings.ReadResponse, cause error) error {
+	if deadLetter.topic == "" {
+		_, err := a.sendToOutputBinding(deadLetter.binding, &bindings.InvokeRequest{
+			Data:      resp.Data,
+			Metadata:  resp.Metadata,
+			Operation: bindings.CreateOperation,
+		})
+		return err
+	}
+
+	data, err := a.json.Marshal(map[string]interface{}{
+		"binding":  name,
+		"data":     resp.Data,
+		"metadata": resp.Metadata,
+		"error":    cause.Error(),
+	})
+	if err != nil {
+		return err
+	}
+	envelope, err := runtime_pubsub.NewCloudEvent(&runtime_pubsub.CloudEvent{
+		ID:              a.runtimeConfig.ID,
+		Topic:           deadLetter.topic,
+		Pubsub:          deadLetter.pubsub,
+		DataContentType: invokev1.JSONContentType,
+		Data:            data,
+	})
+	if err != nil {
+		return err
+	}
+	b, err := a.json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	return a.publish(a.ctx, &pubsub.PublishRequest{
+		PubsubName: deadLetter.pubsub,
+		Topic:      deadLetter.topic,
+		Data:       b,
+	}, diag.PubsubOutcomeDeadLetter)
+}
+
+func (a *DaprRuntime) startHTTPServer(port int, publicPort *int, profilePort int, allowedOrigins string, pipeline http_middleware.Pipeline) error {
 	a.daprHTTPAPI = http.NewAPI(a.runtimeConfig.ID, a.appChannel, a.directMessaging, a.getComponents, a.stateStores, a.secretStores,
-		a.secretsConfiguration, a.getPublishAdapter(), a.actor, a.sendToOutputBinding, a.globalConfig.Spec.TracingSpec, a.ShutdownWithWait)
+		a.secretsConfiguration, a.getPublishAdapter(), a.actor, a.sendToOutputBinding, a.sendToOutputBindingStream, a.sendToOutputBindingBulk,
+		a.getOutputBindingOperations, a.setInputBindingPaused, a.setLogLevel, a.getHealthDetails, a.getMetadataDetails, a.resiliency, a.jobScheduler, a.globalConfig.Spec.TracingSpec, a.ShutdownWithWait)
 	serverConf := http.NewServerConfig(a.runtimeConfig.ID, a.hostAddress, port, a.runtimeConfig.APIListenAddresses, publicPort, profilePort, allowedOrigins, a.runtimeConfig.EnableProfiling, a.runtimeConfig.MaxRequestBodySize, a.runtimeConfig.UnixDomainSocket, a.runtimeConfig.ReadBufferSize, a.runtimeConfig.StreamRequestBody)
+	if a.runtimeConfig.EnableProfiling {
+		profileTLSConfig, err := credentials.ServerTLSConfig(a.runtimeConfig.ProfileTLSMode, a.workloadCertificate)
+		if err != nil {
+			return errors.Wrap(err, "invalid profile server TLS configuration")
+		}
+		serverConf.ProfileTLSConfig = profileTLSConfig
+	}
 
 	server := http.NewServer(a.daprHTTPAPI, serverConf, a.globalConfig.Spec.TracingSpec, a.globalConfig.Spec.MetricSpec, pipeline, a.globalConfig.Spec.APISpec)
 	if err := server.StartNonBlocking(); err != nil {
@@ -984,9 +1761,9 @@ func (a *DaprRuntime) startGRPCInternalServer(api grpc.API, port int) error {
 	return nil
 }
 
-func (a *DaprRuntime) startGRPCAPIServer(api grpc.API, port int) error {
+func (a *DaprRuntime) startGRPCAPIServer(api grpc.API, port int, pipeline grpc_middleware.Pipeline) error {
 	serverConf := a.getNewServerConfig(a.runtimeConfig.APIListenAddresses, port)
-	server := grpc.NewAPIServer(api, serverConf, a.globalConfig.Spec.TracingSpec, a.globalConfig.Spec.MetricSpec, a.globalConfig.Spec.APISpec, a.proxy)
+	server := grpc.NewAPIServer(api, serverConf, a.globalConfig.Spec.TracingSpec, a.globalConfig.Spec.MetricSpec, a.globalConfig.Spec.APISpec, pipeline, a.proxy)
 	if err := server.StartNonBlocking(); err != nil {
 		return err
 	}
@@ -1008,7 +1785,7 @@ func (a *DaprRuntime) getNewServerConfig(apiListenAddresses []string, port int)
 func (a *DaprRuntime) getGRPCAPI() grpc.API {
 	return grpc.NewAPI(a.runtimeConfig.ID, a.appChannel, a.stateStores, a.secretStores, a.secretsConfiguration, a.configurationStores,
 		a.getPublishAdapter(), a.directMessaging, a.actor,
-		a.sendToOutputBinding, a.globalConfig.Spec.TracingSpec, a.accessControlList, string(a.runtimeConfig.ApplicationProtocol), a.getComponents, a.ShutdownWithWait)
+		a.sendToOutputBinding, a.sendToOutputBindingStream, a.sendToOutputBindingBulk, a.globalConfig.Spec.TracingSpec, a.accessControlList, string(a.runtimeConfig.ApplicationProtocol), a.runtimeConfig.EnableGateway, a.getComponents, a.getMetadataDetails, a.resiliency, a.ShutdownWithWait)
 }
 
 func (a *DaprRuntime) getPublishAdapter() runtime_pubsub.Adapter {
@@ -1078,14 +1855,32 @@ func (a *DaprRuntime) initInputBinding(c components_v1alpha1.Component) error {
 		return err
 	}
 
+	a.componentsInitLock.Lock()
+	defer a.componentsInitLock.Unlock()
 	log.Infof("successful init for input binding %s (%s/%s)", c.ObjectMeta.Name, c.Spec.Type, c.Spec.Version)
 	a.inputBindingRoutes[c.Name] = c.Name
+	var deadLetter bindingDeadLetter
 	for _, item := range c.Spec.Metadata {
 		if item.Name == "route" {
 			a.inputBindingRoutes[c.ObjectMeta.Name] = item.Value.String()
 		}
+		switch item.Name {
+		case deadLetterBindingKey:
+			deadLetter.binding = item.Value.String()
+		case deadLetterPubsubKey:
+			deadLetter.pubsub = item.Value.String()
+		case deadLetterTopicKey:
+			deadLetter.topic = item.Value.String()
+		}
+	}
+	if deadLetter.binding != "" || (deadLetter.pubsub != "" && deadLetter.topic != "") {
+		a.inputBindingDeadLetter[c.Name] = deadLetter
 	}
 	a.inputBindings[c.Name] = binding
+	// a reloaded binding keeps the gate of the previous instance, which keeps it paused.
+	if _, ok := a.inputBindingGates[c.Name]; !ok {
+		a.inputBindingGates[c.Name] = runtime_bindings.NewGate()
+	}
 	diag.DefaultMonitoring.ComponentInitialized(c.Spec.Type)
 	return nil
 }
@@ -1099,6 +1894,17 @@ func (a *DaprRuntime) initOutputBinding(c components_v1alpha1.Component) error {
 	}
 
 	if binding != nil {
+		if c.Spec.InitMode == components_v1alpha1.InitModeLazy {
+			log.Infof("output binding %s (%s/%s) is initialized on its first invocation", c.ObjectMeta.Name, c.Spec.Type, c.Spec.Version)
+			binding = bindings_loader.NewLazyOutputBinding(c.ObjectMeta.Name, binding, bindings_loader.NewLazyInitBackOff(), func(err error) {
+				if err != nil {
+					log.Errorf("failed to init output binding %s (%s/%s) on its first invocation: %s", c.ObjectMeta.Name, c.Spec.Type, c.Spec.Version, err)
+					diag.DefaultMonitoring.ComponentInitFailed(c.Spec.Type, "init")
+				}
+				a.setComponentStatus(c, nil, err)
+			})
+		}
+
 		err := binding.Init(bindings.Metadata{
 			Properties: a.convertMetadataItemsToProperties(c.Spec.Metadata),
 			Name:       c.ObjectMeta.Name,
@@ -1108,6 +1914,9 @@ func (a *DaprRuntime) initOutputBinding(c components_v1alpha1.Component) error {
 			diag.DefaultMonitoring.ComponentInitFailed(c.Spec.Type, "init")
 			return err
 		}
+
+		a.componentsInitLock.Lock()
+		defer a.componentsInitLock.Unlock()
 		log.Infof("successful init for output binding %s (%s/%s)", c.ObjectMeta.Name, c.Spec.Type, c.Spec.Version)
 		a.outputBindings[c.ObjectMeta.Name] = binding
 		diag.DefaultMonitoring.ComponentInitialized(c.Spec.Type)
@@ -1124,6 +1933,17 @@ func (a *DaprRuntime) initConfiguration(s components_v1alpha1.Component) error {
 	}
 	if store != nil {
 		props := a.convertMetadataItemsToProperties(s.Spec.Metadata)
+		if path := props[configurationDefaultsPathKey]; path != "" {
+			defaults, loadErr := configuration_loader.LoadDefaults(path)
+			if loadErr != nil {
+				diag.DefaultMonitoring.ComponentInitFailed(s.Spec.Type, "init")
+				log.Warnf("error loading defaults of configuration store %s (%s/%s): %s", s.ObjectMeta.Name, s.Spec.Type, s.Spec.Version, loadErr)
+				return loadErr
+			}
+			log.Infof("overlaying configuration store %s on %d defaults from %s", s.ObjectMeta.Name, len(defaults), path)
+			store = configuration_loader.NewDefaultingStore(s.ObjectMeta.Name, store, defaults)
+		}
+
 		err := store.Init(configuration.Metadata{
 			Properties: props,
 		})
@@ -1133,6 +1953,8 @@ func (a *DaprRuntime) initConfiguration(s components_v1alpha1.Component) error {
 			return err
 		}
 
+		a.componentsInitLock.Lock()
+		defer a.componentsInitLock.Unlock()
 		a.configurationStores[s.ObjectMeta.Name] = store
 		diag.DefaultMonitoring.ComponentInitialized(s.Spec.Type)
 	}
@@ -1152,9 +1974,11 @@ func (a *DaprRuntime) initState(s components_v1alpha1.Component) error {
 		secretStoreName := a.authSecretStoreOrDefault(s)
 
 		if config.IsFeatureEnabled(a.globalConfig.Spec.Features, config.StateEncryption) {
+			a.componentsInitLock.Lock()
 			secretStore := a.getSecretStore(secretStoreName)
 			encKeys, encErr := encryption.ComponentEncryptionKey(s, secretStore)
 			if encErr != nil {
+				a.componentsInitLock.Unlock()
 				log.Errorf("error initializing state store encryption %s (%s/%s): %s", s.ObjectMeta.Name, s.Spec.Type, s.Spec.Version, encErr)
 				diag.DefaultMonitoring.ComponentInitFailed(s.Spec.Type, "creation")
 				return encErr
@@ -1166,6 +1990,7 @@ func (a *DaprRuntime) initState(s components_v1alpha1.Component) error {
 					log.Infof("automatic encryption enabled for state store %s", s.ObjectMeta.Name)
 				}
 			}
+			a.componentsInitLock.Unlock()
 		}
 
 		props := a.convertMetadataItemsToProperties(s.Spec.Metadata)
@@ -1178,6 +2003,8 @@ func (a *DaprRuntime) initState(s components_v1alpha1.Component) error {
 			return err
 		}
 
+		a.componentsInitLock.Lock()
+		defer a.componentsInitLock.Unlock()
 		a.stateStores[s.ObjectMeta.Name] = store
 		err = state_loader.SaveStateConfiguration(s.ObjectMeta.Name, props)
 		if err != nil {
@@ -1198,6 +2025,16 @@ func (a *DaprRuntime) initState(s components_v1alpha1.Component) error {
 			}
 			a.actorStateStoreLock.Unlock()
 		}
+
+		// set specified jobs store if "jobsStateStore" is true in the spec.
+		if props[jobsStateStore] == "true" {
+			if a.jobsStateStoreName == "" {
+				log.Infof("detected jobs state store: %s", s.ObjectMeta.Name)
+				a.jobsStateStoreName = s.ObjectMeta.Name
+			} else if a.jobsStateStoreName != s.ObjectMeta.Name {
+				log.Fatalf("detected duplicate jobs state store: %s", s.ObjectMeta.Name)
+			}
+		}
 		diag.DefaultMonitoring.ComponentInitialized(s.Spec.Type)
 	}
 
@@ -1212,6 +2049,9 @@ func (a *DaprRuntime) getDeclarativeSubscriptions() []runtime_pubsub.Subscriptio
 		subs = runtime_pubsub.DeclarativeKubernetes(a.operatorClient, log)
 	case modes.StandaloneMode:
 		subs = runtime_pubsub.DeclarativeSelfHosted(a.runtimeConfig.Standalone.ComponentsPath, log)
+		if sharedPath := a.runtimeConfig.Standalone.SharedComponentsPath; sharedPath != "" {
+			subs = append(subs, runtime_pubsub.DeclarativeSelfHosted(sharedPath, log)...)
+		}
 	}
 
 	// only return valid subscriptions for this app id
@@ -1327,6 +2167,8 @@ func (a *DaprRuntime) initPubSub(c components_v1alpha1.Component) error {
 		return err
 	}
 
+	a.componentsInitLock.Lock()
+	defer a.componentsInitLock.Unlock()
 	pubsubName := c.ObjectMeta.Name
 
 	a.scopedSubscriptions[pubsubName] = scopes.GetScopedTopics(scopes.SubscriptionScopes, a.runtimeConfig.ID, properties)
@@ -1341,7 +2183,13 @@ func (a *DaprRuntime) initPubSub(c components_v1alpha1.Component) error {
 // Publish is an adapter method for the runtime to pre-validate publish requests
 // And then forward them to the Pub/Sub component.
 // This method is used by the HTTP and gRPC APIs.
-func (a *DaprRuntime) Publish(req *pubsub.PublishRequest) error {
+func (a *DaprRuntime) Publish(ctx context.Context, req *pubsub.PublishRequest) error {
+	return a.publish(ctx, req, diag.PubsubOutcomeSuccess)
+}
+
+// publish publishes an event to a pubsub component, and records it with the given outcome once published.
+// The faults injected in the publication are interrupted when ctx is done.
+func (a *DaprRuntime) publish(ctx context.Context, req *pubsub.PublishRequest, outcome string) error {
 	thepubsub := a.GetPubSub(req.PubsubName)
 	if thepubsub == nil {
 		return runtime_pubsub.NotFoundError{PubsubName: req.PubsubName}
@@ -1351,7 +2199,43 @@ func (a *DaprRuntime) Publish(req *pubsub.PublishRequest) error {
 		return runtime_pubsub.NotAllowedError{Topic: req.Topic, ID: a.runtimeConfig.ID}
 	}
 
-	return a.pubSubs[req.PubsubName].Publish(req)
+	op := diag.StartPubsubOperation(ctx, a.globalConfig.Spec.TracingSpec, req.PubsubName, "publish")
+	err := chaos.Inject(ctx, chaos.PubSub, req.PubsubName)
+	if err == nil {
+		err = a.resiliency.ComponentOperation(req.PubsubName, func() error {
+			return thepubsub.Publish(req)
+		})
+	}
+	op.End(err)
+	if err != nil {
+		outcome = diag.PubsubOutcomeFailed
+	}
+	diag.DefaultMonitoring.PubsubEgressEvent(req.PubsubName, req.Topic, outcome)
+	return err
+}
+
+// publishAuditEvent publishes an audit event as a cloud event.
+func (a *DaprRuntime) publishAuditEvent(pubsubName, topic string, data []byte) error {
+	envelope, err := runtime_pubsub.NewCloudEvent(&runtime_pubsub.CloudEvent{
+		ID:              a.runtimeConfig.ID,
+		Topic:           topic,
+		Pubsub:          pubsubName,
+		DataContentType: "application/json",
+		Data:            data,
+	})
+	if err != nil {
+		return err
+	}
+
+	b, err := a.json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+	return a.publish(a.ctx, &pubsub.PublishRequest{
+		PubsubName: pubsubName,
+		Topic:      topic,
+		Data:       b,
+	}, diag.PubsubOutcomeSuccess)
 }
 
 // GetPubSub is an adapter method to find a pubsub by name.
@@ -1424,6 +2308,9 @@ func (a *DaprRuntime) initNameResolution() error {
 		nr.MDNSInstanceName:    a.runtimeConfig.ID,
 		nr.MDNSInstanceAddress: a.hostAddress,
 		nr.MDNSInstancePort:    strconv.Itoa(a.runtimeConfig.InternalGRPCPort),
+		// The locality of this instance, registered by the resolvers reporting the locality of the instances.
+		env.NodeName: os.Getenv(env.NodeName),
+		env.Zone:     os.Getenv(env.Zone),
 	}
 
 	if err != nil {
@@ -1495,6 +2382,7 @@ func (a *DaprRuntime) publishMessageHTTP(ctx context.Context, msg *pubsubSubscri
 			return errors.Errorf("RETRY status returned from app while processing pub/sub event %v", cloudEvent[pubsub.IDField])
 		case pubsub.Drop:
 			log.Warnf("DROP status returned from app while processing pub/sub event %v", cloudEvent[pubsub.IDField])
+			msg.dropped = true
 			return nil
 		}
 		// Consider unknown status field as error and retry
@@ -1506,6 +2394,7 @@ func (a *DaprRuntime) publishMessageHTTP(ctx context.Context, msg *pubsubSubscri
 		// When adding/removing an error here, check if that is also applicable to GRPC since there is a mapping between HTTP and GRPC errors:
 		// https://cloud.google.com/apis/design/errors#handling_errors
 		log.Errorf("non-retriable error returned from app while processing pub/sub event %v: %s. status code returned: %v", cloudEvent[pubsub.IDField], body, statusCode)
+		msg.dropped = true
 		return nil
 	}
 
@@ -1590,6 +2479,7 @@ func (a *DaprRuntime) publishMessageGRPC(ctx context.Context, msg *pubsubSubscri
 		if hasErrStatus && (errStatus.Code() == codes.Unimplemented) {
 			// DROP
 			log.Warnf("non-retriable error returned from app while processing pub/sub event %v: %s", cloudEvent[pubsub.IDField], err)
+			msg.dropped = true
 
 			return nil
 		}
@@ -1610,6 +2500,7 @@ func (a *DaprRuntime) publishMessageGRPC(ctx context.Context, msg *pubsubSubscri
 		return errors.Errorf("RETRY status returned from app while processing pub/sub event %v", cloudEvent[pubsub.IDField])
 	case runtimev1pb.TopicEventResponse_DROP:
 		log.Warnf("DROP status returned from app while processing pub/sub event %v", cloudEvent[pubsub.IDField])
+		msg.dropped = true
 
 		return nil
 	}
@@ -1645,6 +2536,8 @@ func (a *DaprRuntime) initActors() error {
 	actorConfig := actors.NewConfig(a.hostAddress, a.runtimeConfig.ID, a.runtimeConfig.PlacementAddresses, a.appConfig.Entities,
 		a.runtimeConfig.InternalGRPCPort, a.appConfig.ActorScanInterval, a.appConfig.ActorIdleTimeout, a.appConfig.DrainOngoingCallTimeout,
 		a.appConfig.DrainRebalancedActors, a.namespace, a.appConfig.Reentrancy, a.appConfig.RemindersStoragePartitions)
+	actorConfig.PlacementShards = a.runtimeConfig.PlacementShards
+	actorConfig.StateStoreName = a.actorStateStoreName
 	act := actors.NewActors(a.stateStores[a.actorStateStoreName], a.appChannel, a.grpc.GetGRPCConnection, actorConfig, a.runtimeConfig.CertChain, a.globalConfig.Spec.TracingSpec, a.globalConfig.Spec.Features)
 	err = act.Init()
 	a.actor = act
@@ -1684,7 +2577,7 @@ func (a *DaprRuntime) loadComponents(opts *runtimeOpts) error {
 
 	switch a.runtimeConfig.Mode {
 	case modes.KubernetesMode:
-		loader = components.NewKubernetesComponents(a.runtimeConfig.Kubernetes, a.namespace, a.operatorClient)
+		loader = components.NewKubernetesComponents(a.runtimeConfig.Kubernetes, a.namespace, a.globalConfig.Spec.Secrets.DefaultStore, a.operatorClient)
 	case modes.StandaloneMode:
 		loader = components.NewStandaloneComponents(a.runtimeConfig.Standalone)
 	default:
@@ -1707,13 +2600,47 @@ func (a *DaprRuntime) loadComponents(opts *runtimeOpts) error {
 	copy(a.components, authorizedComps)
 	a.componentsLock.Unlock()
 
-	for _, comp := range authorizedComps {
-		a.pendingComponents <- comp
-	}
+	a.initComponents(authorizedComps)
 
 	return nil
 }
 
+// initComponents initializes the components loaded when daprd starts, running up to the configured number of
+// initializations at once. A component is initialized after the components it depends on and the secret store
+// resolving its secret references.
+func (a *DaprRuntime) initComponents(comps []components_v1alpha1.Component) {
+	names := make(map[string]bool, len(comps))
+	for _, comp := range comps {
+		names[comp.Name] = true
+	}
+	dependencies := func(comp components_v1alpha1.Component) []string {
+		deps := comp.Spec.DependsOn
+		for _, m := range comp.Spec.Metadata {
+			if m.SecretKeyRef.Name == "" {
+				continue
+			}
+			if store := a.authSecretStoreOrDefault(comp); names[store] && store != comp.Name {
+				deps = append(deps[:len(deps):len(deps)], store)
+			}
+			break
+		}
+		return deps
+	}
+
+	initComponentsInOrder(comps, a.runtimeConfig.ComponentsInitConcurrency, dependencies,
+		func(comp components_v1alpha1.Component) error {
+			err := a.processComponentAndDependents(comp)
+			if err != nil {
+				a.handleComponentError(comp, err, false)
+			}
+			return err
+		},
+		func(comp components_v1alpha1.Component, err error) {
+			a.setComponentInitResult(comp, err)
+			a.handleComponentError(comp, err, false)
+		})
+}
+
 func (a *DaprRuntime) appendOrReplaceComponents(component components_v1alpha1.Component) {
 	a.componentsLock.Lock()
 	defer a.componentsLock.Unlock()
@@ -1732,6 +2659,143 @@ func (a *DaprRuntime) appendOrReplaceComponents(component components_v1alpha1.Co
 	}
 }
 
+// setComponentInitResult records the initialization result of a component. The callers hold componentsInitLock.
+func (a *DaprRuntime) setComponentInitResult(component components_v1alpha1.Component, err error) {
+	var lazy *bindings_loader.LazyOutputBinding
+	if err == nil && strings.HasPrefix(component.Spec.Type, string(bindingsComponent)+".") {
+		lazy, _ = a.outputBindings[component.Name].(*bindings_loader.LazyOutputBinding)
+	}
+	a.setComponentStatus(component, lazy, err)
+}
+
+// setComponentStatus records the status of a component. The lazily initialized output binding of the component, if
+// any, gives its status until it is initialized. It is checked under componentsLock, which its initialization results
+// are recorded under as well, so a pending status never overwrites the result of its initialization.
+func (a *DaprRuntime) setComponentStatus(component components_v1alpha1.Component, lazy *bindings_loader.LazyOutputBinding, err error) {
+	a.componentsLock.Lock()
+	defer a.componentsLock.Unlock()
+
+	details := health.ComponentDetails{
+		Name:   component.Name,
+		Type:   component.Spec.Type,
+		Status: health.StatusOK,
+	}
+	if err == nil && lazy != nil {
+		if initialized, initErr := lazy.InitResult(); !initialized {
+			if initErr == nil {
+				details.Status = health.StatusLazy
+			}
+			err = initErr
+		}
+	}
+	if err != nil {
+		details.Status = health.StatusFailed
+		details.Error = err.Error()
+		// The errors of the components may hold their connection strings or credentials, so they are only logged.
+		diag.DefaultEventLog.Record(diag.RuntimeEventComponentInitFailed, "component %s (%s) failed to initialize, see the logs of the sidecar for the error", component.Name, component.Spec.Type)
+	}
+	a.componentsHealth[component.Spec.Type+"/"+component.Name] = details
+	a.reportComponentStatus(details)
+}
+
+// reportComponentStatus reports the initialization result of a component to the operator, which records it in the
+// status of the component. The state stores are pinged to report their health.
+func (a *DaprRuntime) reportComponentStatus(details health.ComponentDetails) {
+	if a.operatorClient == nil || a.podName == "" {
+		return
+	}
+
+	var store state.Store
+	if details.Status == health.StatusOK && strings.HasPrefix(details.Type, string(stateComponent)+".") {
+		store = a.stateStores[details.Name]
+	}
+	go a.sendComponentStatus(details, store, true)
+}
+
+// sendComponentStatus pings the state store of the component, if any, and reports the status of the component to the
+// operator. Unless forced, the status is only reported if it changed since the previous report.
+func (a *DaprRuntime) sendComponentStatus(details health.ComponentDetails, store state.Store, force bool) {
+	req := &operatorv1pb.ReportComponentStatusRequest{
+		Component:   details.Name,
+		Initialized: details.Status == health.StatusOK,
+		Healthy:     details.Status == health.StatusOK,
+		Pending:     details.Status == health.StatusLazy,
+		Message:     details.Error,
+	}
+	if store != nil {
+		if err := store.Ping(); err != nil {
+			req.Healthy = false
+			req.Message = err.Error()
+		}
+	}
+
+	a.componentStatusesLock.Lock()
+	prev, ok := a.reportedComponentStatuses[details.Name]
+	a.componentStatusesLock.Unlock()
+	if !force && ok && prev.Initialized == req.Initialized && prev.Healthy == req.Healthy && prev.Pending == req.Pending && prev.Message == req.Message {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), componentStatusReportTimeout)
+	defer cancel()
+	if _, err := a.operatorClient.ReportComponentStatus(ctx, req); err != nil {
+		log.Warnf("failed to report the status of component %s to the operator: %s", details.Name, err)
+		return
+	}
+
+	a.componentStatusesLock.Lock()
+	a.reportedComponentStatuses[details.Name] = req
+	a.componentStatusesLock.Unlock()
+}
+
+// watchComponentsHealth pings the initialized state stores periodically and reports the changes of their health to
+// the operator, so the Healthy condition of the components follows their health after their initialization.
+func (a *DaprRuntime) watchComponentsHealth() {
+	if a.operatorClient == nil || a.podName == "" {
+		return
+	}
+
+	ticker := time.NewTicker(componentHealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		a.componentsLock.RLock()
+		stores := make([]health.ComponentDetails, 0, len(a.componentsHealth))
+		for _, details := range a.componentsHealth {
+			if details.Status == health.StatusOK && strings.HasPrefix(details.Type, string(stateComponent)+".") {
+				stores = append(stores, details)
+			}
+		}
+		a.componentsLock.RUnlock()
+
+		for _, details := range stores {
+			a.componentsInitLock.Lock()
+			store := a.stateStores[details.Name]
+			a.componentsInitLock.Unlock()
+			if store != nil {
+				a.sendComponentStatus(details, store, false)
+			}
+		}
+	}
+}
+
+// reportComponentStatuses reports the statuses of all the components to the operator. The operator drops the
+// statuses reported by a sidecar when its component update stream ends, so they are reported again once it is
+// reconnected.
+func (a *DaprRuntime) reportComponentStatuses() {
+	a.componentsLock.RLock()
+	defer a.componentsLock.RUnlock()
+
+	for _, details := range a.componentsHealth {
+		a.reportComponentStatus(details)
+	}
+}
+
 func (a *DaprRuntime) extractComponentCategory(component components_v1alpha1.Component) ComponentCategory {
 	for _, category := range componentCategoriesNeedProcess {
 		if strings.HasPrefix(component.Spec.Type, fmt.Sprintf("%s.", category)) {
@@ -1747,19 +2811,28 @@ func (a *DaprRuntime) processComponents() {
 			continue
 		}
 
-		err := a.processComponentAndDependents(comp)
-		if err != nil {
-			e := fmt.Sprintf("process component %s error: %s", comp.Name, err.Error())
-			if !comp.Spec.IgnoreErrors {
-				log.Warnf("process component error daprd process will exited, gracefully to stop")
-				a.Shutdown(a.runtimeConfig.GracefulShutdownDuration)
-				log.Fatalf(e)
-			}
-			log.Errorf(e)
+		// a component failing to reload keeps serving with its previous instance.
+		a.componentsInitLock.Lock()
+		reload := len(a.componentInstances(a.extractComponentCategory(comp), comp.Name)) > 0
+		a.componentsInitLock.Unlock()
+		if err := a.processComponentAndDependents(comp); err != nil {
+			a.handleComponentError(comp, err, reload)
 		}
 	}
 }
 
+// handleComponentError stops daprd when a component fails to be processed, unless the component ignores its errors
+// or keeps serving with its previous instance.
+func (a *DaprRuntime) handleComponentError(comp components_v1alpha1.Component, err error, reload bool) {
+	e := fmt.Sprintf("process component %s error: %s", comp.Name, err.Error())
+	if !comp.Spec.IgnoreErrors && !reload {
+		log.Warnf("process component error daprd process will exited, gracefully to stop")
+		a.Shutdown(a.runtimeConfig.GracefulShutdownDuration)
+		log.Fatalf(e)
+	}
+	log.Errorf(e)
+}
+
 func (a *DaprRuntime) flushOutstandingComponents() {
 	log.Info("waiting for all outstanding components to be processed")
 	// We flush by sending a no-op component. Since the processComponents goroutine only reads one component at a time,
@@ -1770,18 +2843,36 @@ func (a *DaprRuntime) flushOutstandingComponents() {
 
 func (a *DaprRuntime) processComponentAndDependents(comp components_v1alpha1.Component) error {
 	log.Debugf("loading component. name: %s, type: %s/%s", comp.ObjectMeta.Name, comp.Spec.Type, comp.Spec.Version)
+	a.componentsInitLock.Lock()
 	res := a.preprocessOneComponent(&comp)
 	if res.unreadyDependency != "" {
 		a.pendingComponentDependents[res.unreadyDependency] = append(a.pendingComponentDependents[res.unreadyDependency], comp)
+		a.componentsInitLock.Unlock()
 		return nil
 	}
+	if res.err != nil {
+		a.setComponentInitResult(comp, res.err)
+		a.componentsInitLock.Unlock()
+		return res.err
+	}
 
 	compCategory := a.extractComponentCategory(comp)
 	if compCategory == "" {
 		// the category entered is incorrect, return error
-		return errors.Errorf("incorrect type %s", comp.Spec.Type)
+		err := errors.Errorf("incorrect type %s", comp.Spec.Type)
+		a.setComponentInitResult(comp, err)
+		a.componentsInitLock.Unlock()
+		return err
 	}
 
+	previous := a.componentInstances(compCategory, comp.Name)
+	if len(previous) > 0 && compCategory == stateComponent && (comp.Name == a.actorStateStoreName || comp.Name == a.jobsStateStoreName) {
+		log.Warnf("state store %s is used by the actors or the jobs and can't be reloaded, restart daprd to apply the update", comp.Name)
+		a.componentsInitLock.Unlock()
+		return nil
+	}
+	a.componentsInitLock.Unlock()
+
 	ch := make(chan error, 1)
 
 	timeout, err := time.ParseDuration(comp.Spec.InitTimeout)
@@ -1794,32 +2885,129 @@ func (a *DaprRuntime) processComponentAndDependents(comp components_v1alpha1.Com
 	}()
 
 	select {
-	case err := <-ch:
-		if err != nil {
-			return err
-		}
+	case err = <-ch:
 	case <-time.After(timeout):
-		return fmt.Errorf("init timeout for component %s exceeded after %s", comp.Name, timeout.String())
+		err = fmt.Errorf("init timeout for component %s exceeded after %s", comp.Name, timeout.String())
+	}
+
+	a.componentsInitLock.Lock()
+	if err != nil {
+		a.setComponentInitResult(comp, err)
+		a.componentsInitLock.Unlock()
+		return err
 	}
 
 	log.Infof("component loaded. name: %s, type: %s/%s", comp.ObjectMeta.Name, comp.Spec.Type, comp.Spec.Version)
 	a.appendOrReplaceComponents(comp)
+	if len(previous) > 0 {
+		a.drainComponentInstances(compCategory, comp.Name, previous)
+	}
+	a.setComponentInitResult(comp, nil)
 	diag.DefaultMonitoring.ComponentLoaded()
 
 	dependency := componentDependency(compCategory, comp.Name)
-	if deps, ok := a.pendingComponentDependents[dependency]; ok {
-		delete(a.pendingComponentDependents, dependency)
-		for _, dependent := range deps {
-			if err := a.processComponentAndDependents(dependent); err != nil {
-				return err
-			}
+	deps := a.pendingComponentDependents[dependency]
+	delete(a.pendingComponentDependents, dependency)
+	a.componentsInitLock.Unlock()
+
+	for _, dependent := range deps {
+		if err := a.processComponentAndDependents(dependent); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// componentInstances returns the instances of the component with the given category and name which are loaded by the
+// runtime.
+func (a *DaprRuntime) componentInstances(category ComponentCategory, name string) []interface{} {
+	var instances []interface{}
+	switch category {
+	case bindingsComponent:
+		if binding, ok := a.inputBindings[name]; ok {
+			instances = append(instances, binding)
+		}
+		if binding, ok := a.outputBindings[name]; ok {
+			instances = append(instances, binding)
+		}
+	case pubsubComponent:
+		if pubSub, ok := a.pubSubs[name]; ok {
+			instances = append(instances, pubSub)
+		}
+	case secretStoreComponent:
+		if secretStore, ok := a.secretStores[name]; ok {
+			instances = append(instances, secretStore)
+		}
+	case stateComponent:
+		if store, ok := a.stateStores[name]; ok {
+			instances = append(instances, store)
+		}
+	case configurationComponent:
+		if store, ok := a.configurationStores[name]; ok {
+			instances = append(instances, store)
+		}
+	}
+	return instances
+}
+
+// drainComponentInstances closes the previous instances of a reloaded component once the operations they're serving
+// are drained, and restarts the consumers of the component on the new instance.
+// The previous instances keep serving the in-flight operations and consuming events until the operations in flight
+// complete, at most for the graceful shutdown duration, while the new operations are served by the new instance.
+func (a *DaprRuntime) drainComponentInstances(category ComponentCategory, name string, previous []interface{}) {
+	pubSub := a.pubSubs[name]
+	inputBinding := a.inputBindings[name]
+	drained := drain.Components.Retire(name)
+
+	go func() {
+		timer := time.NewTimer(a.runtimeConfig.GracefulShutdownDuration)
+		defer timer.Stop()
+
+		select {
+		case <-drained:
+		case <-timer.C:
+			log.Warnf("closing the previous instance of component %s with operations still in flight after %s", name, a.runtimeConfig.GracefulShutdownDuration)
+		}
+		for _, instance := range previous {
+			if closer, ok := instance.(io.Closer); ok {
+				if err := closer.Close(); err != nil {
+					log.Warnf("error closing the previous instance of component %s: %s", name, err)
+				}
+			}
+		}
+		log.Infof("component reloaded. name: %s", name)
+
+		a.consumersLock.Lock()
+		defer a.consumersLock.Unlock()
+
+		if !a.consumersStarted {
+			return
+		}
+		switch category {
+		case pubsubComponent:
+			if err := a.beginPubSub(name, pubSub); err != nil {
+				log.Errorf("error occurred while beginning pubsub %s: %s", name, err)
+			}
+		case bindingsComponent:
+			if inputBinding != nil && a.appChannel != nil {
+				a.startReadingFromBinding(name, inputBinding)
+			}
+		}
+	}()
+}
+
 func (a *DaprRuntime) doProcessOneComponent(category ComponentCategory, comp components_v1alpha1.Component) error {
+	switch comp.Spec.InitMode {
+	case "", components_v1alpha1.InitModeEager:
+	case components_v1alpha1.InitModeLazy:
+		if category != bindingsComponent {
+			log.Warnf("component %s is initialized when daprd starts, only output bindings support the %s init mode", comp.Name, comp.Spec.InitMode)
+		}
+	default:
+		return errors.Errorf("unsupported init mode %s", comp.Spec.InitMode)
+	}
+
 	switch category {
 	case bindingsComponent:
 		return a.initBinding(comp)
@@ -1843,9 +3031,35 @@ func (a *DaprRuntime) preprocessOneComponent(comp *components_v1alpha1.Component
 			unreadyDependency: componentDependency(secretStoreComponent, unreadySecretsStore),
 		}
 	}
+	if a.globalConfig.Spec.Secrets.DenyUnresolvedRefs {
+		if unresolved := unresolvedSecretRefs(*comp); len(unresolved) > 0 {
+			return componentPreprocessRes{
+				err: errors.Errorf("secret references of metadata %s couldn't be resolved with secret store %s",
+					strings.Join(unresolved, ", "), a.authSecretStoreOrDefault(*comp)),
+			}
+		}
+	}
 	return componentPreprocessRes{}
 }
 
+// unresolvedSecretRefs returns the names of the metadata of a component whose secret reference wasn't resolved.
+func unresolvedSecretRefs(component components_v1alpha1.Component) []string {
+	var unresolved []string
+	for _, m := range component.Spec.Metadata {
+		if m.SecretKeyRef.Name != "" && len(m.Value.Raw) == 0 {
+			unresolved = append(unresolved, m.Name)
+		}
+	}
+	return unresolved
+}
+
+func (a *DaprRuntime) stopJobs() {
+	if a.jobScheduler != nil {
+		log.Info("Shutting down jobs")
+		a.jobScheduler.Stop()
+	}
+}
+
 func (a *DaprRuntime) stopActor() {
 	if a.actor != nil {
 		log.Info("Shutting down actor")
@@ -1931,7 +3145,9 @@ func (a *DaprRuntime) Shutdown(duration time.Duration) {
 	// Ensure the Unix socket file is removed if a panic occurs.
 	defer a.cleanSocket()
 
+	a.cancel()
 	a.stopActor()
+	a.stopJobs()
 	log.Infof("dapr shutting down.")
 	log.Info("Stopping Dapr APIs")
 	for _, closer := range a.apiClosers {
@@ -1939,9 +3155,21 @@ func (a *DaprRuntime) Shutdown(duration time.Duration) {
 			log.Warnf("error closing API: %v", err)
 		}
 	}
+	if drain.Default.IsDraining() {
+		// The shutdown API already waited for the work in flight.
+		duration = 0
+	}
 	log.Infof("Waiting %s to finish outstanding operations", duration)
 	<-time.After(duration)
+	// The audit events are flushed before the pubsubs they may be published to are closed.
+	audit.Default.Close()
 	a.shutdownComponents()
+	if a.otlpMetricsExporter != nil {
+		a.otlpMetricsExporter.Stop()
+	}
+	if a.profilesExporter != nil {
+		a.profilesExporter.Stop()
+	}
 	a.shutdownC <- nil
 }
 
@@ -2027,8 +3255,13 @@ func (a *DaprRuntime) processComponentSecrets(component components_v1alpha1.Comp
 	return component, ""
 }
 
+// authSecretStoreOrDefault returns the secret store resolving the secret references of a component.
+// The default secret store of the configuration is used when the component doesn't set one.
 func (a *DaprRuntime) authSecretStoreOrDefault(comp components_v1alpha1.Component) string {
 	if comp.SecretStore == "" {
+		if a.globalConfig.Spec.Secrets.DefaultStore != "" {
+			return a.globalConfig.Spec.Secrets.DefaultStore
+		}
 		switch a.runtimeConfig.Mode {
 		case modes.KubernetesMode:
 			return "kubernetes"
@@ -2044,15 +3277,25 @@ func (a *DaprRuntime) getSecretStore(storeName string) secretstores.SecretStore
 	return a.secretStores[storeName]
 }
 
+// appListenAddress returns the network and the address the app listens on, either its port or its unix domain
+// socket.
+func (a *DaprRuntime) appListenAddress() (string, string) {
+	if a.runtimeConfig.AppUnixDomainSocket != "" {
+		return "unix", a.runtimeConfig.AppUnixDomainSocket
+	}
+	return "tcp", net.JoinHostPort("localhost", strconv.Itoa(a.runtimeConfig.ApplicationPort))
+}
+
 func (a *DaprRuntime) blockUntilAppIsReady() {
-	if a.runtimeConfig.ApplicationPort <= 0 {
+	if a.runtimeConfig.ApplicationPort <= 0 && a.runtimeConfig.AppUnixDomainSocket == "" {
 		return
 	}
 
-	log.Infof("application protocol: %s. waiting on port %v.  This will block until the app is listening on that port.", string(a.runtimeConfig.ApplicationProtocol), a.runtimeConfig.ApplicationPort)
+	network, address := a.appListenAddress()
+	log.Infof("application protocol: %s. waiting on %s.  This will block until the app is listening on it.", string(a.runtimeConfig.ApplicationProtocol), address)
 
 	for {
-		conn, _ := net.DialTimeout("tcp", net.JoinHostPort("localhost", fmt.Sprintf("%v", a.runtimeConfig.ApplicationPort)), time.Millisecond*500)
+		conn, _ := net.DialTimeout(network, address, time.Millisecond*500)
 		if conn != nil {
 			conn.Close()
 			break
@@ -2061,7 +3304,89 @@ func (a *DaprRuntime) blockUntilAppIsReady() {
 		time.Sleep(time.Millisecond * 50)
 	}
 
-	log.Infof("application discovered on port %v", a.runtimeConfig.ApplicationPort)
+	log.Infof("application discovered on %s", address)
+}
+
+// watchAppExit shuts the runtime down once the app stops listening on its port, so the sidecars of batch workloads
+// exit when their app completes. Apps without a port call the shutdown API instead.
+func (a *DaprRuntime) watchAppExit() {
+	if !a.runtimeConfig.ShutdownOnAppExit {
+		return
+	}
+	if a.runtimeConfig.ApplicationPort <= 0 && a.runtimeConfig.AppUnixDomainSocket == "" {
+		log.Warn("shutdown on app exit requires an app port or socket and is ignored, the app must call the shutdown API when it completes")
+		return
+	}
+
+	go func() {
+		network, address := a.appListenAddress()
+		waitUntilAppExits(network, address, appExitCheckInterval, appExitThreshold)
+		log.Infof("application stopped listening on %s, shutting down", address)
+		a.ShutdownWithWait()
+	}()
+}
+
+// waitUntilAppExits blocks until the app address refuses the given number of consecutive connections.
+// Other dial errors, such as timeouts of a busy app, reset the count.
+func waitUntilAppExits(network, address string, interval time.Duration, threshold int) {
+	for refusals := 0; refusals < threshold; {
+		time.Sleep(interval)
+		conn, err := net.DialTimeout(network, address, time.Millisecond*500)
+		if conn != nil {
+			conn.Close()
+		}
+		if isConnectionRefused(err) {
+			refusals++
+		} else {
+			refusals = 0
+		}
+	}
+}
+
+// isConnectionRefused returns true if nothing listens on the dialed address anymore. A unix domain socket
+// whose file was removed counts as refused too.
+func isConnectionRefused(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ENOENT)
+}
+
+func (a *DaprRuntime) initAppHealthCheck() {
+	if a.runtimeConfig.AppHealthCheck == nil || a.appChannel == nil {
+		return
+	}
+
+	conf := a.runtimeConfig.AppHealthCheck
+	opts := []health.Option{
+		health.WithInterval(conf.ProbeInterval),
+		health.WithRequestTimeout(conf.ProbeTimeout),
+		health.WithFailureThreshold(conf.Threshold),
+	}
+
+	var ch chan bool
+	switch a.runtimeConfig.ApplicationProtocol {
+	case GRPCProtocol:
+		ch = health.StartGRPCHealthCheck(a.grpc.AppClient, opts...)
+	case HTTPProtocol:
+		scheme := "http"
+		if a.runtimeConfig.AppSSL {
+			scheme = "https"
+		}
+		path := conf.HealthCheckPath
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		if a.runtimeConfig.AppUnixDomainSocket != "" {
+			opts = append(opts, health.WithUnixDomainSocket(a.runtimeConfig.AppUnixDomainSocket))
+			ch = health.StartEndpointHealthCheck(fmt.Sprintf("http://%s%s", channel.DefaultChannelAddress, path), opts...)
+			break
+		}
+		ch = health.StartEndpointHealthCheck(fmt.Sprintf("%s://%s:%d%s", scheme, channel.DefaultChannelAddress, a.runtimeConfig.ApplicationPort, path), opts...)
+	default:
+		return
+	}
+
+	a.appHealth = apphealth.New()
+	a.appHealth.Watch(ch)
+	log.Infof("app health checks enabled with interval %s", conf.ProbeInterval)
 }
 
 func (a *DaprRuntime) loadAppConfiguration() {
@@ -2081,19 +3406,29 @@ func (a *DaprRuntime) loadAppConfiguration() {
 }
 
 func (a *DaprRuntime) createAppChannel() error {
+	if a.runtimeConfig.AppUnixDomainSocket != "" {
+		return a.createUnixSocketAppChannel()
+	}
+
 	if a.runtimeConfig.ApplicationPort > 0 {
-		var channelCreatorFn func(port, maxConcurrency int, spec config.TracingSpec, sslEnabled bool, maxRequestBodySize int, readBufferSize int) (channel.AppChannel, error)
+		var channelCreatorFn func(port, maxConcurrency int, spec config.TracingSpec, sslEnabled bool, maxRequestBodySize int, readBufferSize int, pool channel.ConnectionPoolConfig) (channel.AppChannel, error)
 
 		switch a.runtimeConfig.ApplicationProtocol {
 		case GRPCProtocol:
 			channelCreatorFn = a.grpc.CreateLocalChannel
 		case HTTPProtocol:
-			channelCreatorFn = http_channel.CreateLocalChannel
+			pipeline, err := a.buildAppHTTPPipeline()
+			if err != nil {
+				return err
+			}
+			channelCreatorFn = func(port, maxConcurrency int, spec config.TracingSpec, sslEnabled bool, maxRequestBodySize int, readBufferSize int, pool channel.ConnectionPoolConfig) (channel.AppChannel, error) {
+				return http_channel.CreateLocalChannel(port, maxConcurrency, spec, sslEnabled, maxRequestBodySize, readBufferSize, pool, pipeline)
+			}
 		default:
 			return errors.Errorf("cannot create app channel for protocol %s", string(a.runtimeConfig.ApplicationProtocol))
 		}
 
-		ch, err := channelCreatorFn(a.runtimeConfig.ApplicationPort, a.runtimeConfig.MaxConcurrency, a.globalConfig.Spec.TracingSpec, a.runtimeConfig.AppSSL, a.runtimeConfig.MaxRequestBodySize, a.runtimeConfig.ReadBufferSize)
+		ch, err := channelCreatorFn(a.runtimeConfig.ApplicationPort, a.runtimeConfig.MaxConcurrency, a.globalConfig.Spec.TracingSpec, a.runtimeConfig.AppSSL, a.runtimeConfig.MaxRequestBodySize, a.runtimeConfig.ReadBufferSize, a.runtimeConfig.AppConnectionPool)
 		if err != nil {
 			log.Infof("app max concurrency set to %v", a.runtimeConfig.MaxConcurrency)
 		}
@@ -2105,6 +3440,34 @@ func (a *DaprRuntime) createAppChannel() error {
 	return nil
 }
 
+// createUnixSocketAppChannel creates the app channel to an app listening on a unix domain socket.
+func (a *DaprRuntime) createUnixSocketAppChannel() error {
+	var (
+		ch  channel.AppChannel
+		err error
+	)
+	switch a.runtimeConfig.ApplicationProtocol {
+	case GRPCProtocol:
+		ch, err = a.grpc.CreateUnixSocketChannel(a.runtimeConfig.AppUnixDomainSocket, a.runtimeConfig.MaxConcurrency, a.globalConfig.Spec.TracingSpec, a.runtimeConfig.MaxRequestBodySize, a.runtimeConfig.ReadBufferSize, a.runtimeConfig.AppConnectionPool)
+	case HTTPProtocol:
+		var pipeline http_middleware.Pipeline
+		pipeline, err = a.buildAppHTTPPipeline()
+		if err != nil {
+			return err
+		}
+		ch, err = http_channel.CreateUnixSocketChannel(a.runtimeConfig.AppUnixDomainSocket, a.runtimeConfig.MaxConcurrency, a.globalConfig.Spec.TracingSpec, a.runtimeConfig.MaxRequestBodySize, a.runtimeConfig.ReadBufferSize, a.runtimeConfig.AppConnectionPool, pipeline)
+	default:
+		return errors.Errorf("cannot create app channel for protocol %s", string(a.runtimeConfig.ApplicationProtocol))
+	}
+	if err != nil {
+		return err
+	}
+
+	a.appChannel = ch
+	log.Infof("app channel is using the unix domain socket %s", a.runtimeConfig.AppUnixDomainSocket)
+	return nil
+}
+
 func (a *DaprRuntime) appendBuiltinSecretStore() {
 	for _, comp := range a.builtinSecretStore() {
 		a.pendingComponents <- comp
@@ -2136,8 +3499,9 @@ func (a *DaprRuntime) initSecretStore(c components_v1alpha1.Component) error {
 		return err
 	}
 
+	props := a.convertMetadataItemsToProperties(c.Spec.Metadata)
 	err = secretStore.Init(secretstores.Metadata{
-		Properties: a.convertMetadataItemsToProperties(c.Spec.Metadata),
+		Properties: props,
 	})
 	if err != nil {
 		log.Warnf("failed to init secret store %s/%s named %s: %s", c.Spec.Type, c.Spec.Version, c.ObjectMeta.Name, err)
@@ -2145,6 +3509,24 @@ func (a *DaprRuntime) initSecretStore(c components_v1alpha1.Component) error {
 		return err
 	}
 
+	a.componentsInitLock.Lock()
+	defer a.componentsInitLock.Unlock()
+	if val := props[secretCacheTTLKey]; val != "" {
+		ttl, parseErr := time.ParseDuration(val)
+		if parseErr != nil {
+			log.Warnf("invalid %s for secret store %s: %s", secretCacheTTLKey, c.ObjectMeta.Name, parseErr)
+			diag.DefaultMonitoring.ComponentInitFailed(c.Spec.Type, "init")
+			return parseErr
+		}
+		log.Infof("caching secrets of secret store %s for %s", c.ObjectMeta.Name, ttl)
+		cache := secretstores_loader.NewCachingSecretStore(c.ObjectMeta.Name, secretStore, ttl)
+		storeName := c.ObjectMeta.Name
+		cache.OnInvalidate(func(key string) {
+			a.secretRefResolver.Invalidate(storeName, key)
+		})
+		secretStore = cache
+	}
+
 	a.secretStores[c.ObjectMeta.Name] = secretStore
 	diag.DefaultMonitoring.ComponentInitialized(c.Spec.Type)
 	return nil
@@ -2183,6 +3565,103 @@ func (a *DaprRuntime) getComponents() []components_v1alpha1.Component {
 	return comps
 }
 
+// getHealthDetails returns the health of the components and subsystems of the runtime.
+func (a *DaprRuntime) getHealthDetails() health.Details {
+	var details health.Details
+
+	a.componentsLock.RLock()
+	details.Components = make([]health.ComponentDetails, 0, len(a.components))
+	reported := make(map[string]bool, len(a.components))
+	for _, c := range a.components {
+		key := c.Spec.Type + "/" + c.Name
+		component, ok := a.componentsHealth[key]
+		if !ok {
+			component = health.ComponentDetails{Name: c.Name, Type: c.Spec.Type, Status: health.StatusPending}
+		}
+		details.Components = append(details.Components, component)
+		reported[key] = true
+	}
+	// Components which failed to initialize after an update are not in the loaded components.
+	for key, component := range a.componentsHealth {
+		if !reported[key] {
+			details.Components = append(details.Components, component)
+		}
+	}
+	a.componentsLock.RUnlock()
+
+	if a.appChannel != nil {
+		details.AppChannel = &health.SubsystemDetails{Status: health.StatusOK}
+		if !a.appHealth.IsHealthy() {
+			details.AppChannel = &health.SubsystemDetails{Status: health.StatusFailed, Message: "app health checks are failing"}
+		}
+	}
+
+	if a.actor != nil {
+		details.Placement = &health.SubsystemDetails{Status: health.StatusOK}
+		if !a.actor.IsPlacementConnected() {
+			details.Placement = &health.SubsystemDetails{Status: health.StatusFailed, Message: "not connected to the placement service"}
+		}
+	}
+
+	if a.authenticator != nil {
+		if cert := a.authenticator.GetCurrentSignedCert(); cert != nil {
+			details.Certificate = &health.CertificateDetails{Status: health.StatusOK, Expiry: cert.Expiry}
+			if time.Now().After(cert.Expiry) {
+				details.Certificate.Status = health.StatusFailed
+			}
+		}
+	}
+
+	return details
+}
+
+// getMetadataDetails returns the capabilities of the loaded components, the enabled preview features and the
+// subscriptions of the app, reported by the metadata APIs.
+func (a *DaprRuntime) getMetadataDetails() runtime_metadata.Details {
+	details := runtime_metadata.Details{
+		Capabilities: map[string][]string{},
+	}
+
+	a.componentsInitLock.Lock()
+	for name, store := range a.stateStores {
+		details.Capabilities[runtime_metadata.CapabilitiesKey(string(stateComponent), name)] = runtime_metadata.StateStoreCapabilities(store)
+	}
+	for name, ps := range a.pubSubs {
+		details.Capabilities[runtime_metadata.CapabilitiesKey(string(pubsubComponent), name)] = runtime_metadata.PubSubCapabilities(ps)
+	}
+	a.componentsInitLock.Unlock()
+
+	for _, f := range a.globalConfig.Spec.Features {
+		if f.Enabled {
+			details.EnabledFeatures = append(details.EnabledFeatures, string(f.Name))
+		}
+	}
+	sort.Strings(details.EnabledFeatures)
+
+	// The subscriptions are not reported before they are read from the app.
+	for pubsubName, topicRoute := range a.topicRoutes {
+		for topic, route := range topicRoute.routes {
+			sub := runtime_metadata.Subscription{
+				PubsubName: pubsubName,
+				Topic:      topic,
+				Metadata:   route.metadata,
+			}
+			for _, rule := range route.rules {
+				sub.Rules = append(sub.Rules, runtime_metadata.NewSubscriptionRule(rule))
+			}
+			details.Subscriptions = append(details.Subscriptions, sub)
+		}
+	}
+	sort.Slice(details.Subscriptions, func(i, j int) bool {
+		if details.Subscriptions[i].PubsubName != details.Subscriptions[j].PubsubName {
+			return details.Subscriptions[i].PubsubName < details.Subscriptions[j].PubsubName
+		}
+		return details.Subscriptions[i].Topic < details.Subscriptions[j].Topic
+	})
+
+	return details
+}
+
 func (a *DaprRuntime) establishSecurity(sentryAddress string) error {
 	if !a.runtimeConfig.mtlsEnabled {
 		log.Info("mTLS is disabled. Skipping certificate request and tls validation")
@@ -2197,7 +3676,9 @@ func (a *DaprRuntime) establishSecurity(sentryAddress string) error {
 	if err != nil {
 		return err
 	}
+	a.authenticatorLock.Lock()
 	a.authenticator = auth
+	a.authenticatorLock.Unlock()
 	a.grpc.SetAuthenticator(auth)
 
 	log.Info("authenticator created")
@@ -2206,6 +3687,26 @@ func (a *DaprRuntime) establishSecurity(sentryAddress string) error {
 	return nil
 }
 
+// workloadCertificate returns the workload certificate issued by sentry and the trust anchors of the sidecar.
+func (a *DaprRuntime) workloadCertificate() (*tls.Certificate, *x509.CertPool, error) {
+	a.authenticatorLock.RLock()
+	defer a.authenticatorLock.RUnlock()
+
+	if a.authenticator == nil {
+		return nil, nil, errors.New("the workload certificate is not issued yet")
+	}
+	signed := a.authenticator.GetCurrentSignedCert()
+	if signed == nil {
+		return nil, nil, errors.New("the workload certificate is not issued yet")
+	}
+
+	cert, err := tls.X509KeyPair(signed.WorkloadCert, signed.PrivateKeyPem)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &cert, signed.TrustChain, nil
+}
+
 func componentDependency(compCategory ComponentCategory, name string) string {
 	return fmt.Sprintf("%s:%s", compCategory, name)
 }
@@ -2223,17 +3724,21 @@ func (a *DaprRuntime) startReadingFromBindings() error {
 		return errors.New("app channel not initialized")
 	}
 	for name, binding := range a.inputBindings {
-		go func(name string, binding bindings.InputBinding) {
-			if !a.isAppSubscribedToBinding(name) {
-				log.Infof("app has not subscribed to binding %s.", name)
-				return
-			}
-
-			err := a.readFromBinding(name, binding)
-			if err != nil {
-				log.Errorf("error reading from input binding %s: %s", name, err)
-			}
-		}(name, binding)
+		a.startReadingFromBinding(name, binding)
 	}
 	return nil
 }
+
+func (a *DaprRuntime) startReadingFromBinding(name string, binding bindings.InputBinding) {
+	go func() {
+		if !a.isAppSubscribedToBinding(name) {
+			log.Infof("app has not subscribed to binding %s.", name)
+			return
+		}
+
+		err := a.readFromBinding(name, binding)
+		if err != nil {
+			log.Errorf("error reading from input binding %s: %s", name, err)
+		}
+	}()
+}
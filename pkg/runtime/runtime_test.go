@@ -19,11 +19,13 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
@@ -45,6 +47,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -57,6 +60,7 @@ import (
 
 	components_v1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
 	subscriptionsapi "github.com/dapr/dapr/pkg/apis/subscriptions/v1alpha1"
+	"github.com/dapr/dapr/pkg/apphealth"
 	channelt "github.com/dapr/dapr/pkg/channel/testing"
 	bindings_loader "github.com/dapr/dapr/pkg/components/bindings"
 	nr_loader "github.com/dapr/dapr/pkg/components/nameresolution"
@@ -64,14 +68,22 @@ import (
 	secretstores_loader "github.com/dapr/dapr/pkg/components/secretstores"
 	state_loader "github.com/dapr/dapr/pkg/components/state"
 	"github.com/dapr/dapr/pkg/config"
+	env "github.com/dapr/dapr/pkg/config/env"
 	"github.com/dapr/dapr/pkg/cors"
+	diag "github.com/dapr/dapr/pkg/diagnostics"
 	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
+	"github.com/dapr/dapr/pkg/drain"
 	"github.com/dapr/dapr/pkg/encryption"
 	"github.com/dapr/dapr/pkg/expr"
+	"github.com/dapr/dapr/pkg/health"
+	"github.com/dapr/dapr/pkg/jobs"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
 	"github.com/dapr/dapr/pkg/modes"
 	operatorv1pb "github.com/dapr/dapr/pkg/proto/operator/v1"
 	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
+	"github.com/dapr/dapr/pkg/resiliency"
+	runtime_bindings "github.com/dapr/dapr/pkg/runtime/bindings"
+	"github.com/dapr/dapr/pkg/runtime/correlation"
 	runtime_pubsub "github.com/dapr/dapr/pkg/runtime/pubsub"
 	"github.com/dapr/dapr/pkg/runtime/security"
 	"github.com/dapr/dapr/pkg/scopes"
@@ -238,6 +250,20 @@ func TestProcessComponentsAndDependents(t *testing.T) {
 		assert.Error(t, err, "expected an error")
 		assert.Equal(t, "incorrect type pubsubs.mockPubSub", err.Error(), "expected error strings to match")
 	})
+
+	t.Run("test health details of components", func(t *testing.T) {
+		pendingComponent := incorrectComponentType
+		pendingComponent.Name = "pending"
+		pendingComponent.Spec.Type = "pubsub.mockPubSub"
+		rt.appendOrReplaceComponents(pendingComponent)
+
+		details := rt.getHealthDetails()
+		assert.ElementsMatch(t, []health.ComponentDetails{
+			{Name: "pending", Type: "pubsub.mockPubSub", Status: health.StatusPending},
+			{Name: TestPubsubName, Type: "pubsubs.mockPubSub", Status: health.StatusFailed, Error: "incorrect type pubsubs.mockPubSub"},
+		}, details.Components)
+		assert.False(t, details.UpdateStatus())
+	})
 }
 
 func TestDoProcessComponent(t *testing.T) {
@@ -298,6 +324,7 @@ type mockOperatorClient struct {
 	clientStreams             []*mockOperatorComponentUpdateClientStream
 	clientStreamCreateWait    chan struct{}
 	clientStreamCreatedNotify chan struct{}
+	componentStatuses         []*operatorv1pb.ReportComponentStatusRequest
 }
 
 func newMockOperatorClient() *mockOperatorClient {
@@ -344,6 +371,14 @@ func (c *mockOperatorClient) ListComponents(ctx context.Context, in *operatorv1p
 	return resp, nil
 }
 
+func (c *mockOperatorClient) ReportComponentStatus(ctx context.Context, in *operatorv1pb.ReportComponentStatusRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.componentStatuses = append(c.componentStatuses, in)
+	return &emptypb.Empty{}, nil
+}
+
 func (c *mockOperatorClient) ClientStreamCount() int {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
@@ -515,6 +550,81 @@ func TestComponentsUpdate(t *testing.T) {
 	assert.True(t, exists, fmt.Sprintf("Expect component, type: %s, name: %s", comp3.Spec.Type, comp3.Name))
 }
 
+// reloadableStateStore is a state store which notifies when it's closed.
+type reloadableStateStore struct {
+	mockStateStore
+	closed chan struct{}
+}
+
+func (s *reloadableStateStore) Close() error {
+	close(s.closed)
+	return nil
+}
+
+func TestComponentReload(t *testing.T) {
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	defer stopRuntime(t, rt)
+	rt.runtimeConfig.GracefulShutdownDuration = time.Minute
+
+	var stores []*reloadableStateStore
+	rt.stateStoreRegistry.Register(
+		state_loader.New("reloadable", func() state.Store {
+			store := &reloadableStateStore{closed: make(chan struct{})}
+			stores = append(stores, store)
+			return store
+		}),
+	)
+
+	component := func(host string) components_v1alpha1.Component {
+		return components_v1alpha1.Component{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name: "reloadable",
+			},
+			Spec: components_v1alpha1.ComponentSpec{
+				Type:    "state.reloadable",
+				Version: "v1",
+				Metadata: []components_v1alpha1.MetadataItem{
+					{
+						Name: "host",
+						Value: components_v1alpha1.DynamicValue{
+							JSON: v1.JSON{Raw: []byte(host)},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	require.NoError(t, rt.processComponentAndDependents(component("localhost:6379")))
+	require.Len(t, stores, 1)
+	assert.Len(t, rt.componentInstances(stateComponent, "reloadable"), 1)
+
+	// an operation in flight on the previous instance holds its close.
+	done := drain.Components.Track("reloadable")
+
+	require.NoError(t, rt.processComponentAndDependents(component("remote:6379")))
+	require.Len(t, stores, 2)
+	assert.Same(t, stores[1], rt.stateStores["reloadable"])
+
+	select {
+	case <-stores[0].closed:
+		t.Fatal("expected the previous instance to be open with an operation in flight")
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	done()
+	select {
+	case <-stores[0].closed:
+	case <-time.After(time.Second * 5):
+		t.Fatal("expected the previous instance to be closed once drained")
+	}
+	select {
+	case <-stores[1].closed:
+		t.Fatal("expected the new instance to be open")
+	default:
+	}
+}
+
 func TestInitState(t *testing.T) {
 	rt := NewTestDaprRuntime(modes.StandaloneMode)
 	defer stopRuntime(t, rt)
@@ -649,6 +759,8 @@ func TestInitNameResolution(t *testing.T) {
 				nameresolution.MDNSInstanceName:    rt.runtimeConfig.ID,
 				nameresolution.MDNSInstanceAddress: rt.hostAddress,
 				nameresolution.MDNSInstancePort:    strconv.Itoa(rt.runtimeConfig.InternalGRPCPort),
+				env.NodeName:                       os.Getenv(env.NodeName),
+				env.Zone:                           os.Getenv(env.Zone),
 			},
 		}
 
@@ -768,6 +880,12 @@ func TestSetupTracing(t *testing.T) {
 			Stdout: true,
 		},
 		expectedExporters: []trace.Exporter{&diag_utils.StdoutExporter{}, &zipkin.Exporter{}},
+	}, {
+		name: "invalid sampling rule",
+		tracingConfig: config.TracingSpec{
+			SamplingRules: []config.TracingSamplingRule{{Path: "^/v1.0/invoke/", SamplingRate: "2"}},
+		},
+		expectedErr: "invalid sampling rate",
 	}}
 
 	for _, tc := range testcases {
@@ -1144,6 +1262,54 @@ func TestInitPubSub(t *testing.T) {
 		mockAppChannel.AssertNumberOfCalls(t, "InvokeMethod", 1)
 	})
 
+	t.Run("events are not delivered while app is unhealthy", func(t *testing.T) {
+		mockPubSub := new(daprt.MockPubSub)
+		rt.pubSubRegistry.Register(
+			pubsub_loader.New("mockPubSub", func() pubsub.PubSub {
+				return mockPubSub
+			}),
+		)
+		mockPubSub.On("Init", pubsub.Metadata{Properties: getFakeProperties()}).Return(nil)
+
+		var handler pubsub.Handler
+		mockPubSub.On(
+			"Subscribe",
+			mock.AnythingOfType("pubsub.SubscribeRequest"),
+			mock.AnythingOfType("pubsub.Handler")).Return(nil).Run(func(args mock.Arguments) {
+			handler = args.Get(1).(pubsub.Handler)
+		})
+
+		mockAppChannel := new(channelt.MockAppChannel)
+		rt.appChannel = mockAppChannel
+		rt.topicRoutes = nil
+		rt.pubSubs = make(map[string]pubsub.PubSub)
+		rt.appHealth = apphealth.New()
+		rt.appHealth.SetHealthy(false)
+		defer func() {
+			rt.appHealth = nil
+		}()
+
+		fakeReq := invokev1.NewInvokeMethodRequest("dapr/subscribe")
+		fakeReq.WithHTTPExtension(http.MethodGet, "")
+		fakeReq.WithRawData(nil, "application/json")
+
+		fakeResp := invokev1.NewInvokeMethodResponse(200, "OK", nil)
+		fakeResp.WithRawData([]byte(getSubscriptionsJSONString([]string{"topic0"}, []string{})), "application/json")
+
+		mockAppChannel.On("InvokeMethod", mock.AnythingOfType("*context.emptyCtx"), fakeReq).Return(fakeResp, nil)
+
+		err := rt.processComponentAndDependents(pubsubComponents[0])
+		assert.Nil(t, err)
+		rt.startSubscribing()
+
+		assert.NotNil(t, handler)
+		err = handler(context.Background(), &pubsub.NewMessage{Topic: "topic0", Data: []byte(`{}`)})
+		assert.Error(t, err)
+
+		// Only the subscriptions were retrieved from the app, the event was not delivered.
+		mockAppChannel.AssertNumberOfCalls(t, "InvokeMethod", 1)
+	})
+
 	t.Run("subscribe 0 topics unless user app provides topic list", func(t *testing.T) {
 		mockPubSub, _ := initMockPubSubForRuntime(rt)
 
@@ -1420,7 +1586,7 @@ func TestInitPubSub(t *testing.T) {
 		rt.pubSubs[TestPubsubName] = &mockPublishPubSub{}
 		md := make(map[string]string, 2)
 		md["key"] = "v3"
-		err := rt.Publish(&pubsub.PublishRequest{
+		err := rt.Publish(context.Background(), &pubsub.PublishRequest{
 			PubsubName: TestPubsubName,
 			Topic:      "topic0",
 			Metadata:   md,
@@ -1429,7 +1595,7 @@ func TestInitPubSub(t *testing.T) {
 		assert.Nil(t, err)
 
 		rt.pubSubs[TestSecondPubsubName] = &mockPublishPubSub{}
-		err = rt.Publish(&pubsub.PublishRequest{
+		err = rt.Publish(context.Background(), &pubsub.PublishRequest{
 			PubsubName: TestSecondPubsubName,
 			Topic:      "topic1",
 		})
@@ -1460,14 +1626,14 @@ func TestInitPubSub(t *testing.T) {
 		}
 
 		rt.pubSubs[TestPubsubName] = &mockPublishPubSub{}
-		err := rt.Publish(&pubsub.PublishRequest{
+		err := rt.Publish(context.Background(), &pubsub.PublishRequest{
 			PubsubName: TestPubsubName,
 			Topic:      "topic5",
 		})
 		assert.NotNil(t, err)
 
 		rt.pubSubs[TestPubsubName] = &mockPublishPubSub{}
-		err = rt.Publish(&pubsub.PublishRequest{
+		err = rt.Publish(context.Background(), &pubsub.PublishRequest{
 			PubsubName: TestSecondPubsubName,
 			Topic:      "topic5",
 		})
@@ -1586,6 +1752,38 @@ func TestInitSecretStores(t *testing.T) {
 		s := rt.getSecretStore("kubernetesMock")
 		assert.NotNil(t, s)
 	})
+
+	t.Run("secret store with cache", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		m := NewMockKubernetesStore()
+		rt.secretStoresRegistry.Register(
+			secretstores_loader.New("kubernetesMock", func() secretstores.SecretStore {
+				return m
+			}),
+		)
+
+		component := components_v1alpha1.Component{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name: "kubernetesMock",
+			},
+			Spec: components_v1alpha1.ComponentSpec{
+				Type:    "secretstores.kubernetesMock",
+				Version: "v1",
+				Metadata: []components_v1alpha1.MetadataItem{
+					{
+						Name: secretCacheTTLKey,
+						Value: components_v1alpha1.DynamicValue{
+							JSON: v1.JSON{Raw: []byte("5m")},
+						},
+					},
+				},
+			},
+		}
+		err := rt.processComponentAndDependents(component)
+		assert.NoError(t, err)
+		assert.Implements(t, (*secretstores_loader.Cache)(nil), rt.getSecretStore("kubernetesMock"))
+	})
 }
 
 func TestMetadataItemsToPropertiesConversion(t *testing.T) {
@@ -1819,6 +2017,116 @@ func TestProcessComponentSecrets(t *testing.T) {
 		assert.Equal(t, "value1", mod.Spec.Metadata[0].Value.String())
 		assert.Empty(t, unready)
 	})
+
+	t.Run("Default secret store of the configuration", func(t *testing.T) {
+		mockBinding.Spec.Metadata[0].Value = components_v1alpha1.DynamicValue{
+			JSON: v1.JSON{Raw: []byte("")},
+		}
+		mockBinding.Spec.Metadata[0].SecretKeyRef = components_v1alpha1.SecretKeyRef{
+			Key:  "key1",
+			Name: "name1",
+		}
+		mockBinding.Auth.SecretStore = ""
+
+		rt := NewTestDaprRuntime(modes.KubernetesMode)
+		defer stopRuntime(t, rt)
+		rt.globalConfig.Spec.Secrets.DefaultStore = "mock"
+
+		rt.secretStoresRegistry.Register(
+			secretstores_loader.New("mock", func() secretstores.SecretStore {
+				return &mockSecretStore{}
+			}),
+		)
+
+		// The default secret store isn't loaded yet.
+		_, unready := rt.processComponentSecrets(mockBinding)
+		assert.Equal(t, "mock", unready)
+
+		err := rt.processComponentAndDependents(components_v1alpha1.Component{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name: "mock",
+			},
+			Spec: components_v1alpha1.ComponentSpec{
+				Type:    "secretstores.mock",
+				Version: "v1",
+			},
+		})
+		assert.NoError(t, err)
+
+		mod, unready := rt.processComponentSecrets(mockBinding)
+		assert.Equal(t, "value1", mod.Spec.Metadata[0].Value.String())
+		assert.Empty(t, unready)
+	})
+}
+
+func TestDenyUnresolvedSecretRefs(t *testing.T) {
+	mockBinding := components_v1alpha1.Component{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name: "mockBinding",
+		},
+		Spec: components_v1alpha1.ComponentSpec{
+			Type:    "bindings.mock",
+			Version: "v1",
+			Metadata: []components_v1alpha1.MetadataItem{
+				{
+					Name: "a",
+					SecretKeyRef: components_v1alpha1.SecretKeyRef{
+						Key:  "missing",
+						Name: "name1",
+					},
+				},
+			},
+		},
+	}
+
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	defer stopRuntime(t, rt)
+	rt.globalConfig.Spec.Secrets.DefaultStore = "mock"
+
+	rt.secretStoresRegistry.Register(
+		secretstores_loader.New("mock", func() secretstores.SecretStore {
+			return &mockSecretStore{}
+		}),
+	)
+	err := rt.processComponentAndDependents(components_v1alpha1.Component{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name: "mock",
+		},
+		Spec: components_v1alpha1.ComponentSpec{
+			Type:    "secretstores.mock",
+			Version: "v1",
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("unresolved secret ref is allowed by default", func(t *testing.T) {
+		res := rt.preprocessOneComponent(mockBinding.DeepCopy())
+		assert.NoError(t, res.err)
+		assert.Empty(t, res.unreadyDependency)
+	})
+
+	t.Run("unresolved secret ref is denied", func(t *testing.T) {
+		rt.globalConfig.Spec.Secrets.DenyUnresolvedRefs = true
+		defer func() {
+			rt.globalConfig.Spec.Secrets.DenyUnresolvedRefs = false
+		}()
+
+		res := rt.preprocessOneComponent(mockBinding.DeepCopy())
+		assert.EqualError(t, res.err, "secret references of metadata a couldn't be resolved with secret store mock")
+	})
+
+	t.Run("resolved secret ref is allowed", func(t *testing.T) {
+		rt.globalConfig.Spec.Secrets.DenyUnresolvedRefs = true
+		defer func() {
+			rt.globalConfig.Spec.Secrets.DenyUnresolvedRefs = false
+		}()
+
+		comp := mockBinding.DeepCopy()
+		comp.Spec.Metadata[0].SecretKeyRef.Key = "key1"
+		res := rt.preprocessOneComponent(comp)
+		assert.NoError(t, res.err)
+		assert.Equal(t, "value1", comp.Spec.Metadata[0].Value.String())
+	})
 }
 
 func TestExtractComponentCategory(t *testing.T) {
@@ -2350,10 +2658,12 @@ func TestOnNewPublishedMessage(t *testing.T) {
 		mockAppChannel.On("InvokeMethod", mock.AnythingOfType("*context.valueCtx"), fakeReq).Return(fakeResp, nil)
 
 		// act
+		testPubSubMessage.dropped = false
 		err := rt.publishMessageHTTP(context.Background(), testPubSubMessage)
 
 		// assert
 		assert.Nil(t, err)
+		assert.True(t, testPubSubMessage.dropped)
 		mockAppChannel.AssertNumberOfCalls(t, "InvokeMethod", 1)
 	})
 
@@ -2872,6 +3182,267 @@ func TestInvokeOutputBindings(t *testing.T) {
 		assert.NotNil(t, err)
 		assert.Equal(t, "binding mockBinding does not support operation get. supported operations:create list", err.Error())
 	})
+
+	t.Run("streamed output binding response", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		rt.outputBindings["mockBinding"] = &mockStreamingBinding{}
+
+		resp, err := rt.sendToOutputBindingStream("mockBinding", &bindings.InvokeRequest{
+			Operation: bindings.GetOperation,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, -1, resp.Size)
+		b, err := ioutil.ReadAll(resp.Data)
+		assert.NoError(t, err)
+		assert.Equal(t, "streamed", string(b))
+	})
+
+	t.Run("buffered output binding response is wrapped", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		rt.outputBindings["mockBinding"] = &mockBinding{}
+
+		resp, err := rt.sendToOutputBindingStream("mockBinding", &bindings.InvokeRequest{
+			Operation: bindings.CreateOperation,
+		})
+		assert.NoError(t, err)
+		assert.Nil(t, resp)
+
+		_, err = rt.sendToOutputBindingStream("mockBinding", &bindings.InvokeRequest{
+			Operation: bindings.GetOperation,
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestInvokeOutputBindingWithReply(t *testing.T) {
+	t.Run("reply is returned to the caller", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		rt.outputBindings["mockBinding"] = &mockReplyBinding{correlator: rt.correlator}
+		rt.inputBindings["replies"] = &mockBinding{}
+
+		resp, err := rt.sendToOutputBinding("mockBinding", &bindings.InvokeRequest{
+			Data:      []byte("ping"),
+			Metadata:  map[string]string{correlation.ReplyTimeoutKey: "1s", correlation.ReplyBindingKey: "replies"},
+			Operation: bindings.CreateOperation,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "pong", string(resp.Data))
+		assert.NotEmpty(t, resp.Metadata[correlation.IDKey])
+	})
+
+	t.Run("no reply before the timeout", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		rt.outputBindings["mockBinding"] = &mockBinding{}
+		rt.inputBindings["replies"] = &mockBinding{}
+
+		_, err := rt.sendToOutputBinding("mockBinding", &bindings.InvokeRequest{
+			Metadata:  map[string]string{correlation.ReplyTimeoutKey: "10ms", correlation.ReplyBindingKey: "replies"},
+			Operation: bindings.CreateOperation,
+		})
+		assert.Equal(t, correlation.ErrReplyTimeout, err)
+	})
+
+	t.Run("invalid reply timeout", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		rt.outputBindings["mockBinding"] = &mockBinding{}
+		rt.inputBindings["replies"] = &mockBinding{}
+
+		_, err := rt.sendToOutputBinding("mockBinding", &bindings.InvokeRequest{
+			Metadata:  map[string]string{correlation.ReplyTimeoutKey: "soon", correlation.ReplyBindingKey: "replies"},
+			Operation: bindings.CreateOperation,
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown reply binding", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		rt.outputBindings["mockBinding"] = &mockBinding{}
+
+		_, err := rt.sendToOutputBinding("mockBinding", &bindings.InvokeRequest{
+			Metadata:  map[string]string{correlation.ReplyTimeoutKey: "1s", correlation.ReplyBindingKey: "replies"},
+			Operation: bindings.CreateOperation,
+		})
+		assert.Error(t, err)
+	})
+}
+
+// mockReplyBinding replies to the requests it receives as if the reply was received by an input binding.
+type mockReplyBinding struct {
+	mockBinding
+	correlator *correlation.Correlator
+}
+
+func (b *mockReplyBinding) Invoke(req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+	go b.correlator.Deliver("replies", &bindings.ReadResponse{
+		Data:     []byte("pong"),
+		Metadata: map[string]string{correlation.IDKey: req.Metadata[correlation.IDKey]},
+	})
+	return nil, nil
+}
+
+func TestSendToOutputBindingBulk(t *testing.T) {
+	reqs := []*bindings.InvokeRequest{
+		{Data: []byte("1"), Operation: bindings.CreateOperation},
+		{Data: []byte("2"), Operation: bindings.GetOperation},
+		{Data: []byte("3"), Operation: bindings.CreateOperation},
+	}
+
+	t.Run("binding invoked for each request", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		rt.outputBindings["mockBinding"] = &mockBinding{}
+
+		results, err := rt.sendToOutputBindingBulk("mockBinding", reqs)
+		assert.NoError(t, err)
+		assert.Len(t, results, 3)
+		assert.NoError(t, results[0].Error)
+		assert.Error(t, results[1].Error)
+		assert.NoError(t, results[2].Error)
+	})
+
+	t.Run("bulk binding invoked once", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		binding := &mockBulkBinding{}
+		rt.outputBindings["mockBinding"] = binding
+
+		results, err := rt.sendToOutputBindingBulk("mockBinding", reqs)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, binding.calls)
+		assert.Equal(t, "1", string(results[0].Response.Data))
+		assert.Error(t, results[1].Error)
+		assert.Equal(t, "3", string(results[2].Response.Data))
+	})
+
+	t.Run("binding not found", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+
+		_, err := rt.sendToOutputBindingBulk("notfound", reqs)
+		assert.Error(t, err)
+	})
+}
+
+type mockBulkBinding struct {
+	mockBinding
+	calls int
+}
+
+func (b *mockBulkBinding) BulkInvoke(reqs []*bindings.InvokeRequest) ([]bindings_loader.BulkInvokeResult, error) {
+	b.calls++
+	results := make([]bindings_loader.BulkInvokeResult, len(reqs))
+	for i, req := range reqs {
+		results[i].Response = &bindings.InvokeResponse{Data: req.Data}
+	}
+	return results, nil
+}
+
+func TestResolveSecretRefs(t *testing.T) {
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	defer stopRuntime(t, rt)
+	rt.secretStores["store1"] = daprt.FakeSecretStore{}
+	rt.secretsConfiguration["store1"] = config.SecretsScope{
+		DefaultAccess: config.AllowAccess,
+		DeniedSecrets: []string{"denied-key"},
+	}
+
+	t.Run("secret reference is resolved", func(t *testing.T) {
+		req := &bindings.InvokeRequest{Metadata: map[string]string{"password": "{secretRef:store1/good-key}"}}
+		resolved, err := rt.resolveSecretRefs(req)
+		assert.NoError(t, err)
+		assert.Equal(t, "life is good", resolved.Metadata["password"])
+		assert.Equal(t, "{secretRef:store1/good-key}", req.Metadata["password"])
+	})
+
+	t.Run("secret store not found", func(t *testing.T) {
+		_, err := rt.resolveSecretRefs(&bindings.InvokeRequest{Metadata: map[string]string{"password": "{secretRef:store2/good-key}"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("secret not allowed", func(t *testing.T) {
+		_, err := rt.resolveSecretRefs(&bindings.InvokeRequest{Metadata: map[string]string{"password": "{secretRef:store1/denied-key}"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("secret not found", func(t *testing.T) {
+		_, err := rt.resolveSecretRefs(&bindings.InvokeRequest{Metadata: map[string]string{"password": "{secretRef:store1/missing-key}"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestSetInputBindingPaused(t *testing.T) {
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	defer stopRuntime(t, rt)
+	rt.inputBindingGates["mockBinding"] = runtime_bindings.NewGate()
+
+	assert.NoError(t, rt.setInputBindingPaused("mockBinding", true))
+	assert.True(t, rt.inputBindingGates["mockBinding"].IsPaused())
+	assert.NoError(t, rt.setInputBindingPaused("mockBinding", false))
+	assert.False(t, rt.inputBindingGates["mockBinding"].IsPaused())
+
+	assert.Error(t, rt.setInputBindingPaused("notfound", true))
+}
+
+func TestGetOutputBindingOperations(t *testing.T) {
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	defer stopRuntime(t, rt)
+	rt.outputBindings["mockBinding"] = &mockBinding{}
+
+	ops, err := rt.getOutputBindingOperations("mockBinding")
+	assert.NoError(t, err)
+	assert.Equal(t, []bindings_loader.OperationDescription{{Name: "create"}, {Name: "list"}}, ops)
+
+	_, err = rt.getOutputBindingOperations("notfound")
+	assert.Error(t, err)
+}
+
+func TestInvokeJob(t *testing.T) {
+	job := &jobs.Job{Name: "job1", Method: "cleanup", Data: map[string]string{"key": "value"}}
+
+	t.Run("app channel not initialized", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		rt.appChannel = nil
+
+		assert.Error(t, rt.invokeJob(context.Background(), job))
+	})
+
+	t.Run("job method is invoked with the job data", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		mockAppChannel := new(channelt.MockAppChannel)
+		rt.appChannel = mockAppChannel
+
+		mockAppChannel.On("InvokeMethod", mock.Anything, mock.MatchedBy(func(req *invokev1.InvokeMethodRequest) bool {
+			_, data := req.RawData()
+			return req.Message().Method == "cleanup" && string(data) == `{"key":"value"}`
+		})).Return(invokev1.NewInvokeMethodResponse(200, "OK", nil), nil).Once()
+		mockAppChannel.On("InvokeMethod", mock.Anything, mock.Anything).Return(invokev1.NewInvokeMethodResponse(500, "Internal Error", nil), nil).Once()
+
+		assert.NoError(t, rt.invokeJob(context.Background(), job))
+		assert.Error(t, rt.invokeJob(context.Background(), job))
+	})
+}
+
+type mockStreamingBinding struct {
+	mockBinding
+}
+
+func (b *mockStreamingBinding) Operations() []bindings.OperationKind {
+	return []bindings.OperationKind{bindings.GetOperation}
+}
+
+func (b *mockStreamingBinding) InvokeStream(req *bindings.InvokeRequest) (*bindings_loader.StreamingInvokeResponse, error) {
+	return &bindings_loader.StreamingInvokeResponse{
+		Data: ioutil.NopCloser(strings.NewReader("streamed")),
+		Size: -1,
+	}, nil
 }
 
 func TestReadInputBindings(t *testing.T) {
@@ -2977,6 +3548,169 @@ func TestReadInputBindings(t *testing.T) {
 
 		assert.Equal(t, string(testInputBindingData), b.data)
 	})
+
+	newBindingEventMocks := func(rt *DaprRuntime, resps ...*invokev1.InvokeMethodResponse) *channelt.MockAppChannel {
+		mockAppChannel := new(channelt.MockAppChannel)
+
+		fakeReq := invokev1.NewInvokeMethodRequest(testInputBindingMethod)
+		fakeReq.WithHTTPExtension(http.MethodPost, "")
+		fakeReq.WithRawData(testInputBindingData, "application/json")
+		fakeReq.WithMetadata(map[string][]string{})
+
+		for _, resp := range resps {
+			mockAppChannel.On("InvokeMethod", mock.AnythingOfType("*context.valueCtx"), fakeReq).Return(resp, nil).Once()
+		}
+
+		rt.appChannel = mockAppChannel
+		rt.inputBindingRoutes[testInputBindingName] = testInputBindingName
+		return mockAppChannel
+	}
+
+	t.Run("app returns DROP status", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+
+		fakeResp := invokev1.NewInvokeMethodResponse(200, "OK", nil)
+		fakeResp.WithRawData([]byte(`{"status": "DROP"}`), "application/json")
+		newBindingEventMocks(rt, fakeResp)
+
+		b := mockBinding{}
+		rt.readFromBinding(testInputBindingName, &b)
+
+		assert.False(t, b.hasError)
+	})
+
+	t.Run("app returns RETRY status", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+
+		fakeResp := invokev1.NewInvokeMethodResponse(200, "OK", nil)
+		fakeResp.WithRawData([]byte(`{"status": "RETRY"}`), "application/json")
+		newBindingEventMocks(rt, fakeResp)
+
+		b := mockBinding{}
+		rt.readFromBinding(testInputBindingName, &b)
+
+		assert.True(t, b.hasError)
+	})
+
+	t.Run("event is retried according to the retry policy", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+
+		var err error
+		rt.resiliency, err = resiliency.FromConfiguration(config.ResiliencySpec{
+			Policies: config.ResiliencyPolicies{
+				Retries: map[string]config.RetryPolicySpec{"retry": {Interval: "1ms", MaxRetries: 2}},
+			},
+			Targets: config.ResiliencyTargets{
+				Components: map[string]config.ComponentResiliencyTarget{testInputBindingName: {Retry: "retry"}},
+			},
+		})
+		assert.NoError(t, err)
+
+		failedResp := invokev1.NewInvokeMethodResponse(500, "Internal Error", nil)
+		failedResp.WithRawData([]byte("Internal Error"), "application/json")
+		okResp := invokev1.NewInvokeMethodResponse(200, "OK", nil)
+		okResp.WithRawData([]byte("OK"), "application/json")
+		mockAppChannel := newBindingEventMocks(rt, failedResp, failedResp, okResp)
+
+		b := mockBinding{}
+		rt.readFromBinding(testInputBindingName, &b)
+
+		assert.False(t, b.hasError)
+		mockAppChannel.AssertNumberOfCalls(t, "InvokeMethod", 3)
+	})
+
+	t.Run("failed event is sent to the dead letter binding", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+
+		failedResp := invokev1.NewInvokeMethodResponse(500, "Internal Error", nil)
+		failedResp.WithRawData([]byte("Internal Error"), "application/json")
+		newBindingEventMocks(rt, failedResp)
+
+		deadLetter := &daprt.MockBinding{}
+		deadLetter.On("Invoke", mock.MatchedBy(func(req *bindings.InvokeRequest) bool {
+			return string(req.Data) == string(testInputBindingData) && req.Operation == bindings.CreateOperation
+		})).Return(nil)
+		rt.outputBindings["deadletter"] = deadLetter
+		rt.inputBindingDeadLetter[testInputBindingName] = bindingDeadLetter{binding: "deadletter"}
+
+		b := mockBinding{}
+		rt.readFromBinding(testInputBindingName, &b)
+
+		assert.False(t, b.hasError)
+		deadLetter.AssertNumberOfCalls(t, "Invoke", 1)
+	})
+
+	t.Run("failed event is published to the dead letter topic", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+
+		failedResp := invokev1.NewInvokeMethodResponse(500, "Internal Error", nil)
+		failedResp.WithRawData([]byte("Internal Error"), "application/json")
+		newBindingEventMocks(rt, failedResp)
+
+		mockPubSub := new(daprt.MockPubSub)
+		mockPubSub.On("Publish", mock.MatchedBy(func(req *pubsub.PublishRequest) bool {
+			return req.PubsubName == TestPubsubName && req.Topic == "deadletter"
+		})).Return(nil)
+		rt.pubSubs[TestPubsubName] = mockPubSub
+		rt.inputBindingDeadLetter[testInputBindingName] = bindingDeadLetter{pubsub: TestPubsubName, topic: "deadletter"}
+
+		b := mockBinding{}
+		rt.readFromBinding(testInputBindingName, &b)
+
+		assert.False(t, b.hasError)
+		mockPubSub.AssertNumberOfCalls(t, "Publish", 1)
+	})
+
+	t.Run("event not found by the app is not dropped", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+
+		notFoundResp := invokev1.NewInvokeMethodResponse(404, "Not Found", nil)
+		notFoundResp.WithRawData([]byte("Not Found"), "application/json")
+		newBindingEventMocks(rt, notFoundResp)
+
+		deadLetter := &daprt.MockBinding{}
+		deadLetter.On("Invoke", mock.Anything).Return(nil)
+		rt.outputBindings["deadletter"] = deadLetter
+		rt.inputBindingDeadLetter[testInputBindingName] = bindingDeadLetter{binding: "deadletter"}
+
+		b := mockBinding{}
+		rt.readFromBinding(testInputBindingName, &b)
+
+		deadLetter.AssertNumberOfCalls(t, "Invoke", 1)
+	})
+
+	t.Run("retries stop when the runtime shuts down", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+
+		var err error
+		rt.resiliency, err = resiliency.FromConfiguration(config.ResiliencySpec{
+			Policies: config.ResiliencyPolicies{
+				Retries: map[string]config.RetryPolicySpec{"retry": {Interval: "1h", MaxRetries: 2}},
+			},
+			Targets: config.ResiliencyTargets{
+				Components: map[string]config.ComponentResiliencyTarget{testInputBindingName: {Retry: "retry"}},
+			},
+		})
+		assert.NoError(t, err)
+
+		failedResp := invokev1.NewInvokeMethodResponse(500, "Internal Error", nil)
+		failedResp.WithRawData([]byte("Internal Error"), "application/json")
+		mockAppChannel := newBindingEventMocks(rt, failedResp)
+		rt.cancel()
+
+		b := mockBinding{}
+		rt.readFromBinding(testInputBindingName, &b)
+
+		assert.True(t, b.hasError)
+		mockAppChannel.AssertNumberOfCalls(t, "InvokeMethod", 1)
+	})
 }
 
 func TestNamespace(t *testing.T) {
@@ -3215,6 +3949,78 @@ func TestInitBindings(t *testing.T) {
 	})
 }
 
+type failingInitBinding struct {
+	daprt.MockBinding
+	initErr error
+}
+
+func (b *failingInitBinding) Init(metadata bindings.Metadata) error {
+	return b.initErr
+}
+
+func TestLazyOutputBinding(t *testing.T) {
+	r := NewDaprRuntime(&Config{}, &config.Configuration{}, &config.AccessControlList{})
+	defer stopRuntime(t, r)
+	initErr := errors.New("connection refused")
+	r.bindingsRegistry.RegisterOutputBindings(
+		bindings_loader.NewOutput("lazyOutput", func() bindings.OutputBinding {
+			return &failingInitBinding{initErr: initErr}
+		}),
+	)
+
+	c := components_v1alpha1.Component{}
+	c.ObjectMeta.Name = "lazyOutput"
+	c.Spec.Type = "bindings.lazyOutput"
+	c.Spec.InitMode = components_v1alpha1.InitModeLazy
+
+	t.Run("binding is not initialized when daprd starts", func(t *testing.T) {
+		require.NoError(t, r.doProcessOneComponent(bindingsComponent, c))
+		require.IsType(t, &bindings_loader.LazyOutputBinding{}, r.outputBindings["lazyOutput"])
+		assert.False(t, r.outputBindings["lazyOutput"].(*bindings_loader.LazyOutputBinding).Initialized())
+
+		r.setComponentInitResult(c, nil)
+		assert.Equal(t, health.StatusLazy, r.componentsHealth["bindings.lazyOutput/lazyOutput"].Status)
+	})
+
+	t.Run("failed initialization on first invocation is reported", func(t *testing.T) {
+		_, err := r.outputBindings["lazyOutput"].Invoke(&bindings.InvokeRequest{Operation: bindings.CreateOperation})
+		var lazyErr *bindings_loader.LazyInitError
+		require.True(t, errors.As(err, &lazyErr))
+		assert.Equal(t, initErr, lazyErr.Err)
+
+		details := r.componentsHealth["bindings.lazyOutput/lazyOutput"]
+		assert.Equal(t, health.StatusFailed, details.Status)
+		assert.Equal(t, "connection refused", details.Error)
+
+		// a late initialization result of the component doesn't report the failed binding as pending.
+		r.setComponentInitResult(c, nil)
+		assert.Equal(t, health.StatusFailed, r.componentsHealth["bindings.lazyOutput/lazyOutput"].Status)
+	})
+
+	t.Run("unsupported init mode", func(t *testing.T) {
+		comp := c
+		comp.Spec.InitMode = "later"
+		assert.EqualError(t, r.doProcessOneComponent(bindingsComponent, comp), "unsupported init mode later")
+	})
+}
+
+func TestComponentInitFailedEvent(t *testing.T) {
+	r := NewDaprRuntime(&Config{}, &config.Configuration{}, &config.AccessControlList{})
+	defer stopRuntime(t, r)
+
+	c := components_v1alpha1.Component{}
+	c.ObjectMeta.Name = "statestore"
+	c.Spec.Type = "state.redis"
+	r.setComponentInitResult(c, errors.New("dial redis://:s3cr3t@redis:6379: connection refused"))
+
+	events := diag.DefaultEventLog.Events()
+	require.NotEmpty(t, events)
+	event := events[len(events)-1]
+	assert.Equal(t, diag.RuntimeEventComponentInitFailed, event.Type)
+	assert.Contains(t, event.Message, "statestore")
+	assert.NotContains(t, event.Message, "s3cr3t")
+}
+
 func TestActorReentrancyConfig(t *testing.T) {
 	fullConfig := `{
 		"entities":["actorType1", "actorType2"],
@@ -3553,3 +4359,101 @@ func TestComponentsCallback(t *testing.T) {
 
 	assert.True(t, callbackInvoked, "component callback was not invoked")
 }
+
+// pingStateStore is a state store whose ping returns the given error.
+type pingStateStore struct {
+	daprt.MockStateStore
+	err error
+}
+
+func (s *pingStateStore) Ping() error {
+	return s.err
+}
+
+func TestSendComponentStatus(t *testing.T) {
+	rt := NewTestDaprRuntime(modes.KubernetesMode)
+	defer stopRuntime(t, rt)
+	operatorClient := newMockOperatorClient()
+	rt.operatorClient = operatorClient
+	rt.reportedComponentStatuses = map[string]*operatorv1pb.ReportComponentStatusRequest{}
+
+	details := health.ComponentDetails{Name: "statestore", Type: "state.mock", Status: health.StatusOK}
+	store := &pingStateStore{}
+
+	rt.sendComponentStatus(details, store, true)
+	rt.sendComponentStatus(details, store, false)
+	require.Len(t, operatorClient.componentStatuses, 1, "an unchanged status is only reported when forced")
+	assert.True(t, operatorClient.componentStatuses[0].Healthy)
+
+	store.err = errors.New("connection reset")
+	rt.sendComponentStatus(details, store, false)
+	require.Len(t, operatorClient.componentStatuses, 2)
+	assert.True(t, operatorClient.componentStatuses[1].Initialized)
+	assert.False(t, operatorClient.componentStatuses[1].Healthy)
+	assert.Equal(t, "connection reset", operatorClient.componentStatuses[1].Message)
+
+	store.err = nil
+	rt.sendComponentStatus(details, store, false)
+	require.Len(t, operatorClient.componentStatuses, 3)
+	assert.True(t, operatorClient.componentStatuses[2].Healthy)
+}
+
+func TestWaitUntilAppExits(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	exited := make(chan struct{})
+	go func() {
+		waitUntilAppExits("tcp", net.JoinHostPort("localhost", strconv.Itoa(port)), 10*time.Millisecond, 3)
+		close(exited)
+	}()
+
+	select {
+	case <-exited:
+		t.Fatal("app exit detected while the app is listening")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	listener.Close()
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("app exit not detected")
+	}
+}
+
+func TestIsConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+
+	t.Run("closed port", func(t *testing.T) {
+		_, err := net.DialTimeout("tcp", address, time.Second)
+		assert.True(t, isConnectionRefused(err))
+	})
+
+	t.Run("removed socket", func(t *testing.T) {
+		_, err := net.DialTimeout("unix", filepath.Join(t.TempDir(), "app.socket"), time.Second)
+		assert.True(t, isConnectionRefused(err))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		err := &net.OpError{Op: "dial", Net: "tcp", Err: os.ErrDeadlineExceeded}
+		assert.False(t, isConnectionRefused(err))
+	})
+
+	t.Run("connected", func(t *testing.T) {
+		assert.False(t, isConnectionRefused(nil))
+	})
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretref
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// refPattern matches metadata values referencing a secret, e.g. {secretRef:vault/db-password}.
+var refPattern = regexp.MustCompile(`^\{secretRef:([^/{}]+)/([^{}]+)\}$`)
+
+// GetSecretFn returns the value of the secret key in the secret store.
+type GetSecretFn func(store, key string) (string, error)
+
+type cachedSecret struct {
+	value  string
+	expiry time.Time
+}
+
+// Resolver replaces the secret references of request metadata by the values of the secrets.
+// Values are cached for the TTL of the resolver so that secret stores are not called for every request.
+type Resolver struct {
+	getSecretFn GetSecretFn
+	ttl         time.Duration
+	now         func() time.Time
+
+	lock  sync.RWMutex
+	cache map[string]cachedSecret
+}
+
+// NewResolver returns a new Resolver getting secrets with getSecretFn and caching them for ttl.
+func NewResolver(getSecretFn GetSecretFn, ttl time.Duration) *Resolver {
+	return &Resolver{
+		getSecretFn: getSecretFn,
+		ttl:         ttl,
+		now:         time.Now,
+		cache:       map[string]cachedSecret{},
+	}
+}
+
+// Resolve returns the metadata with its secret references replaced by the values of the secrets.
+// The metadata is returned unchanged if it doesn't reference any secret, otherwise a copy is returned.
+func (r *Resolver) Resolve(metadata map[string]string) (map[string]string, error) {
+	var resolved map[string]string
+	for k, v := range metadata {
+		match := refPattern.FindStringSubmatch(v)
+		if match == nil {
+			continue
+		}
+
+		if resolved == nil {
+			resolved = make(map[string]string, len(metadata))
+			for k2, v2 := range metadata {
+				resolved[k2] = v2
+			}
+		}
+		value, err := r.getSecret(match[1], match[2])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve secret reference of metadata %s", k)
+		}
+		resolved[k] = value
+	}
+
+	if resolved == nil {
+		return metadata, nil
+	}
+	return resolved, nil
+}
+
+func (r *Resolver) getSecret(store, key string) (string, error) {
+	cacheKey := store + "/" + key
+	now := r.now()
+
+	r.lock.RLock()
+	cached, ok := r.cache[cacheKey]
+	r.lock.RUnlock()
+	if ok && now.Before(cached.expiry) {
+		return cached.value, nil
+	}
+
+	value, err := r.getSecretFn(store, key)
+	if err != nil {
+		return "", err
+	}
+
+	r.lock.Lock()
+	r.cache[cacheKey] = cachedSecret{value: value, expiry: now.Add(r.ttl)}
+	r.lock.Unlock()
+	return value, nil
+}
+
+// Invalidate removes the secret of the secret store from the cache, or all the secrets of the secret store if key
+// is empty.
+func (r *Resolver) Invalidate(store, key string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if key != "" {
+		delete(r.cache, store+"/"+key)
+		return
+	}
+	for k := range r.cache {
+		if strings.HasPrefix(k, store+"/") {
+			delete(r.cache, k)
+		}
+	}
+}
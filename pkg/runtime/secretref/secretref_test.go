@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretref
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	calls := 0
+	getSecretFn := func(store, key string) (string, error) {
+		calls++
+		if store != "vault" {
+			return "", errors.Errorf("secret store %s not found", store)
+		}
+		return store + ":" + key, nil
+	}
+
+	t.Run("metadata without references is unchanged", func(t *testing.T) {
+		r := NewResolver(getSecretFn, time.Minute)
+		md := map[string]string{"key": "value", "other": "{notARef:vault/key}"}
+		resolved, err := r.Resolve(md)
+		assert.NoError(t, err)
+		assert.Equal(t, md, resolved)
+	})
+
+	t.Run("references are resolved and cached", func(t *testing.T) {
+		calls = 0
+		now := time.Now()
+		r := NewResolver(getSecretFn, time.Minute)
+		r.now = func() time.Time { return now }
+
+		md := map[string]string{"password": "{secretRef:vault/db-password}", "user": "admin"}
+		resolved, err := r.Resolve(md)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"password": "vault:db-password", "user": "admin"}, resolved)
+		// the metadata of the request is not modified.
+		assert.Equal(t, "{secretRef:vault/db-password}", md["password"])
+
+		_, err = r.Resolve(md)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, calls)
+
+		now = now.Add(time.Minute)
+		_, err = r.Resolve(md)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("invalidated references are resolved again", func(t *testing.T) {
+		calls = 0
+		r := NewResolver(getSecretFn, time.Minute)
+		md := map[string]string{"password": "{secretRef:vault/db-password}", "user": "{secretRef:vault/db-user}"}
+		_, err := r.Resolve(md)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+
+		r.Invalidate("vault", "db-password")
+		_, err = r.Resolve(md)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+
+		r.Invalidate("other", "")
+		_, err = r.Resolve(md)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+
+		r.Invalidate("vault", "")
+		_, err = r.Resolve(md)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, calls)
+	})
+
+	t.Run("unresolvable reference", func(t *testing.T) {
+		r := NewResolver(getSecretFn, time.Minute)
+		_, err := r.Resolve(map[string]string{"password": "{secretRef:missing/db-password}"})
+		assert.Error(t, err)
+	})
+}
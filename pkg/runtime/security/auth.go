@@ -48,6 +48,10 @@ type SignedCertificate struct {
 	PrivateKeyPem []byte
 	Expiry        time.Time
 	TrustChain    *x509.CertPool
+	// LocalTrustChain holds the trust chain certs of the trust domains of this cluster.
+	LocalTrustChain *x509.CertPool
+	// FederatedTrustAnchors holds the trust anchors of each federated trust domain.
+	FederatedTrustAnchors map[string]*x509.CertPool
 }
 
 func newAuthenticator(sentryAddress string, trustAnchors *x509.CertPool, certChainPem, keyPem []byte, genCSRFunc func(id string) ([]byte, []byte, error)) Authenticator {
@@ -129,20 +133,19 @@ func (a *authenticator) CreateSignedWorkloadCert(id, namespace, trustDomain stri
 	}
 
 	expiry := validTimestamp.AsTime()
-	trustChain := x509.NewCertPool()
-	for _, c := range resp.GetTrustChainCertificates() {
-		ok := trustChain.AppendCertsFromPEM(c)
-		if !ok {
-			diag.DefaultMonitoring.MTLSWorkLoadCertRotationFailed("chaining")
-			return nil, errors.Wrap(err, "failed adding trust chain cert to x509 CertPool")
-		}
+	trustChain, err := parseTrustChain(resp.GetTrustChainCertificates())
+	if err != nil {
+		diag.DefaultMonitoring.MTLSWorkLoadCertRotationFailed("chaining")
+		return nil, err
 	}
 
 	signedCert := &SignedCertificate{
-		WorkloadCert:  workloadCert,
-		PrivateKeyPem: pkPem,
-		Expiry:        expiry,
-		TrustChain:    trustChain,
+		WorkloadCert:          workloadCert,
+		PrivateKeyPem:         pkPem,
+		Expiry:                expiry,
+		TrustChain:            trustChain.all,
+		LocalTrustChain:       trustChain.local,
+		FederatedTrustAnchors: trustChain.federated,
 	}
 
 	a.certMutex.Lock()
@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package security
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/sentry/certs"
+)
+
+const spiffeScheme = "spiffe"
+
+// trustChain holds the trust chain certs returned by sentry.
+type trustChain struct {
+	// all holds the certs of the local and of the federated trust domains.
+	all *x509.CertPool
+	// local holds the certs of the trust domains of this cluster.
+	local *x509.CertPool
+	// federated holds the trust anchors of each federated trust domain.
+	federated map[string]*x509.CertPool
+}
+
+// parseTrustChain parses the PEM encoded trust chain certs returned by sentry.
+// The trust anchors of federated trust domains are labeled with their trust domain in the PEM header.
+func parseTrustChain(chainPems [][]byte) (*trustChain, error) {
+	chain := &trustChain{
+		all:       x509.NewCertPool(),
+		local:     x509.NewCertPool(),
+		federated: map[string]*x509.CertPool{},
+	}
+
+	for _, chainPem := range chainPems {
+		found := false
+		for {
+			var block *pem.Block
+			block, chainPem = pem.Decode(chainPem)
+			if block == nil {
+				break
+			}
+			if block.Type != certType {
+				continue
+			}
+
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, errors.Wrap(err, "error parsing trust chain cert")
+			}
+			found = true
+			chain.all.AddCert(cert)
+
+			trustDomain, ok := block.Headers[certs.TrustDomainPEMHeader]
+			if !ok {
+				chain.local.AddCert(cert)
+				continue
+			}
+			if _, ok = chain.federated[trustDomain]; !ok {
+				chain.federated[trustDomain] = x509.NewCertPool()
+			}
+			chain.federated[trustDomain].AddCert(cert)
+		}
+
+		if !found {
+			return nil, errors.New("failed adding trust chain cert to x509 CertPool")
+		}
+	}
+	return chain, nil
+}
+
+// VerifyPeerTrustDomain verifies that the cert of a peer is signed by the trust anchors of its own trust domain.
+// The certs of a federated trust domain must chain to its trust anchors, and the certs of any other trust domain to
+// the local trust chain, so that a federated trust domain can't impersonate the workloads of another trust domain.
+// It is meant to be used as the VerifyPeerCertificate function of a TLS config, after the chain of the peer was
+// verified against TrustChain.
+func (s *SignedCertificate) VerifyPeerTrustDomain(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(s.FederatedTrustAnchors) == 0 {
+		return nil
+	}
+	if len(rawCerts) == 0 {
+		return errors.New("peer did not present a certificate")
+	}
+
+	peerCerts := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return errors.Wrap(err, "error parsing peer certificate")
+		}
+		peerCerts = append(peerCerts, cert)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range peerCerts[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	trustDomain := spiffeTrustDomain(peerCerts[0])
+	roots, ok := s.FederatedTrustAnchors[trustDomain]
+	if !ok {
+		roots = s.LocalTrustChain
+	}
+
+	_, err := peerCerts[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "peer certificate is not signed by the trust anchors of trust domain %s", trustDomain)
+	}
+	return nil
+}
+
+// spiffeTrustDomain returns the trust domain of the SPIFFE ID of a workload cert.
+func spiffeTrustDomain(cert *x509.Certificate) string {
+	for _, uri := range cert.URIs {
+		if uri.Scheme == spiffeScheme {
+			return uri.Host
+		}
+	}
+	return ""
+}
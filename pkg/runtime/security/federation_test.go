@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package security
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/dapr/pkg/sentry/certs"
+)
+
+type testTrustDomainCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestTrustDomainCA(t *testing.T, name string) *testTrustDomainCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(certBytes)
+	require.NoError(t, err)
+	return &testTrustDomainCA{cert: cert, key: key}
+}
+
+func (c *testTrustDomainCA) pem() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: certType, Bytes: c.cert.Raw})
+}
+
+func (c *testTrustDomainCA) issue(t *testing.T, trustDomain string) [][]byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		URIs:         []*url.URL{{Scheme: "spiffe", Host: trustDomain, Path: "/ns/default/app"}},
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, c.cert, &key.PublicKey, c.key)
+	require.NoError(t, err)
+	return [][]byte{certBytes}
+}
+
+func TestParseTrustChain(t *testing.T) {
+	local := newTestTrustDomainCA(t, "local")
+	partner := newTestTrustDomainCA(t, "partner")
+
+	t.Run("local and federated trust anchors", func(t *testing.T) {
+		chain, err := parseTrustChain([][]byte{
+			local.pem(),
+			certs.WithTrustDomainHeader(partner.pem(), "partner.com"),
+		})
+		require.NoError(t, err)
+		assert.Len(t, chain.all.Subjects(), 2)
+		assert.Len(t, chain.local.Subjects(), 1)
+		assert.Len(t, chain.federated, 1)
+		assert.NotNil(t, chain.federated["partner.com"])
+	})
+
+	t.Run("invalid trust chain cert", func(t *testing.T) {
+		_, err := parseTrustChain([][]byte{[]byte("invalid")})
+		assert.Error(t, err)
+	})
+}
+
+func TestVerifyPeerTrustDomain(t *testing.T) {
+	local := newTestTrustDomainCA(t, "local")
+	partner := newTestTrustDomainCA(t, "partner")
+
+	chain, err := parseTrustChain([][]byte{
+		local.pem(),
+		certs.WithTrustDomainHeader(partner.pem(), "partner.com"),
+	})
+	require.NoError(t, err)
+	signedCert := &SignedCertificate{
+		TrustChain:            chain.all,
+		LocalTrustChain:       chain.local,
+		FederatedTrustAnchors: chain.federated,
+	}
+
+	t.Run("local peer", func(t *testing.T) {
+		assert.NoError(t, signedCert.VerifyPeerTrustDomain(local.issue(t, "public"), nil))
+	})
+
+	t.Run("federated peer", func(t *testing.T) {
+		assert.NoError(t, signedCert.VerifyPeerTrustDomain(partner.issue(t, "partner.com"), nil))
+	})
+
+	t.Run("federated peer impersonating local trust domain", func(t *testing.T) {
+		assert.Error(t, signedCert.VerifyPeerTrustDomain(partner.issue(t, "public"), nil))
+	})
+
+	t.Run("local peer impersonating federated trust domain", func(t *testing.T) {
+		assert.Error(t, signedCert.VerifyPeerTrustDomain(local.issue(t, "partner.com"), nil))
+	})
+
+	t.Run("no federation", func(t *testing.T) {
+		assert.NoError(t, (&SignedCertificate{}).VerifyPeerTrustDomain(partner.issue(t, "public"), nil))
+	})
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package security
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc"
+	"github.com/pkg/errors"
+)
+
+const (
+	// AuthorizationHeader is the header name for http/gRPC calls to hold the bearer token.
+	AuthorizationHeader = "authorization"
+	bearerPrefix        = "bearer "
+	oidcRequestTimeout  = time.Second * 10
+)
+
+// oidcSigningAlgs are the accepted signing algorithms of the tokens.
+var oidcSigningAlgs = []string{
+	oidc.RS256, oidc.RS384, oidc.RS512,
+	oidc.ES256, oidc.ES384, oidc.ES512,
+	oidc.PS256, oidc.PS384, oidc.PS512,
+}
+
+// OIDCValidator validates the JWT bearer tokens of the requests to the Dapr APIs against an OIDC issuer.
+// The JWKS of the issuer is discovered on the first request and cached, new keys are fetched when a token is signed
+// with an unknown key.
+type OIDCValidator struct {
+	issuer    string
+	jwksURL   string
+	audiences []string
+	verifier  *oidc.IDTokenVerifier
+	lock      *sync.Mutex
+}
+
+// NewOIDCValidator returns a validator of the tokens of an OIDC issuer for the given audiences.
+// The JWKS of the issuer is discovered unless jwksURL is set.
+func NewOIDCValidator(issuer, jwksURL string, audiences []string) (*OIDCValidator, error) {
+	if issuer == "" {
+		return nil, errors.New("the OIDC issuer is required")
+	}
+	if len(audiences) == 0 {
+		return nil, errors.New("at least one OIDC audience is required")
+	}
+
+	return &OIDCValidator{
+		issuer:    issuer,
+		jwksURL:   jwksURL,
+		audiences: audiences,
+		lock:      &sync.Mutex{},
+	}, nil
+}
+
+// Validate verifies the signature, issuer, expiry and audience of a raw JWT bearer token.
+// It returns the subject of the token.
+func (v *OIDCValidator) Validate(ctx context.Context, rawToken string) (string, error) {
+	verifier, err := v.getVerifier()
+	if err != nil {
+		return "", err
+	}
+
+	token, err := verifier.Verify(ctx, rawToken)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid bearer token")
+	}
+
+	for _, aud := range token.Audience {
+		for _, expected := range v.audiences {
+			if aud == expected {
+				return token.Subject, nil
+			}
+		}
+	}
+	return "", errors.Errorf("invalid bearer token: audience %v is not accepted", token.Audience)
+}
+
+// getVerifier returns the verifier of the tokens, discovering the JWKS of the issuer on first use.
+// A failed discovery is retried on the next request.
+func (v *OIDCValidator) getVerifier() (*oidc.IDTokenVerifier, error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if v.verifier != nil {
+		return v.verifier, nil
+	}
+
+	// The context is kept by the key set to fetch the rotated keys of the issuer.
+	ctx := oidc.ClientContext(context.Background(), &http.Client{Timeout: oidcRequestTimeout})
+	config := &oidc.Config{
+		// The audiences are checked by the validator, which accepts more than one.
+		SkipClientIDCheck:    true,
+		SupportedSigningAlgs: oidcSigningAlgs,
+	}
+
+	if v.jwksURL != "" {
+		v.verifier = oidc.NewVerifier(v.issuer, oidc.NewRemoteKeySet(ctx, v.jwksURL), config)
+		return v.verifier, nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, v.issuer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error discovering OIDC issuer %s", v.issuer)
+	}
+	v.verifier = provider.Verifier(config)
+	return v.verifier, nil
+}
+
+type subjectKey struct{}
+
+// WithSubject returns a context holding the subject of the bearer token of a request.
+func WithSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, subjectKey{}, subject)
+}
+
+// SubjectFromContext returns the subject of the bearer token of a request.
+func SubjectFromContext(ctx context.Context) (string, bool) {
+	subject, ok := ctx.Value(subjectKey{}).(string)
+	return subject, ok
+}
+
+// BearerToken returns the token of the value of an authorization header using the bearer scheme.
+func BearerToken(authorization string) (string, bool) {
+	if len(authorization) <= len(bearerPrefix) || !strings.EqualFold(authorization[:len(bearerPrefix)], bearerPrefix) {
+		return "", false
+	}
+	return strings.TrimSpace(authorization[len(bearerPrefix):]), true
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package security
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jose "gopkg.in/square/go-jose.v2"
+)
+
+const testOIDCKeyID = "test-key"
+
+// testOIDCIssuer is an OIDC issuer serving its discovery document and JWKS.
+type testOIDCIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+}
+
+func newTestOIDCIssuer(t *testing.T) *testOIDCIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	issuer := &testOIDCIssuer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":   issuer.server.URL,
+			"jwks_uri": issuer.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{{Key: &key.PublicKey, KeyID: testOIDCKeyID, Algorithm: "RS256", Use: "sig"}},
+		})
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func (i *testOIDCIssuer) token(t *testing.T, key *rsa.PrivateKey, claims map[string]interface{}) string {
+	signer, err := jose.NewSigner(jose.SigningKey{
+		Algorithm: jose.RS256,
+		Key:       jose.JSONWebKey{Key: key, KeyID: testOIDCKeyID},
+	}, (&jose.SignerOptions{}).WithType("JWT"))
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed, err := signer.Sign(payload)
+	require.NoError(t, err)
+	raw, err := signed.CompactSerialize()
+	require.NoError(t, err)
+	return raw
+}
+
+func (i *testOIDCIssuer) claims(audience string) map[string]interface{} {
+	return map[string]interface{}{
+		"iss": i.server.URL,
+		"sub": "order-processor",
+		"aud": []string{audience},
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(time.Minute).Unix(),
+	}
+}
+
+func TestNewOIDCValidator(t *testing.T) {
+	t.Run("missing issuer", func(t *testing.T) {
+		_, err := NewOIDCValidator("", "", []string{"dapr"})
+		assert.Error(t, err)
+	})
+
+	t.Run("missing audiences", func(t *testing.T) {
+		_, err := NewOIDCValidator("https://issuer.example.com", "", nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestOIDCValidator(t *testing.T) {
+	issuer := newTestOIDCIssuer(t)
+	validator, err := NewOIDCValidator(issuer.server.URL, "", []string{"dapr", "dapr-api"})
+	require.NoError(t, err)
+
+	t.Run("valid token", func(t *testing.T) {
+		subject, err := validator.Validate(context.Background(), issuer.token(t, issuer.key, issuer.claims("dapr-api")))
+		assert.NoError(t, err)
+		assert.Equal(t, "order-processor", subject)
+	})
+
+	t.Run("audience not accepted", func(t *testing.T) {
+		_, err := validator.Validate(context.Background(), issuer.token(t, issuer.key, issuer.claims("other")))
+		assert.Error(t, err)
+	})
+
+	t.Run("other issuer", func(t *testing.T) {
+		claims := issuer.claims("dapr")
+		claims["iss"] = "https://issuer.example.com"
+		_, err := validator.Validate(context.Background(), issuer.token(t, issuer.key, claims))
+		assert.Error(t, err)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		claims := issuer.claims("dapr")
+		claims["exp"] = time.Now().Add(-time.Minute).Unix()
+		_, err := validator.Validate(context.Background(), issuer.token(t, issuer.key, claims))
+		assert.Error(t, err)
+	})
+
+	t.Run("token signed by unknown key", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		_, err = validator.Validate(context.Background(), issuer.token(t, key, issuer.claims("dapr")))
+		assert.Error(t, err)
+	})
+
+	t.Run("malformed token", func(t *testing.T) {
+		_, err := validator.Validate(context.Background(), "not-a-jwt")
+		assert.Error(t, err)
+	})
+
+	t.Run("jwks url without discovery", func(t *testing.T) {
+		validator, err := NewOIDCValidator(issuer.server.URL, issuer.server.URL+"/keys", []string{"dapr"})
+		require.NoError(t, err)
+		_, err = validator.Validate(context.Background(), issuer.token(t, issuer.key, issuer.claims("dapr")))
+		assert.NoError(t, err)
+	})
+
+	t.Run("issuer not reachable", func(t *testing.T) {
+		validator, err := NewOIDCValidator("http://127.0.0.1:0", "", []string{"dapr"})
+		require.NoError(t, err)
+		_, err = validator.Validate(context.Background(), issuer.token(t, issuer.key, issuer.claims("dapr")))
+		assert.Error(t, err)
+	})
+}
+
+func TestBearerToken(t *testing.T) {
+	t.Run("bearer scheme", func(t *testing.T) {
+		token, ok := BearerToken("Bearer abc.def.ghi")
+		assert.True(t, ok)
+		assert.Equal(t, "abc.def.ghi", token)
+	})
+
+	t.Run("lowercase bearer scheme", func(t *testing.T) {
+		token, ok := BearerToken("bearer abc.def.ghi")
+		assert.True(t, ok)
+		assert.Equal(t, "abc.def.ghi", token)
+	})
+
+	t.Run("other scheme", func(t *testing.T) {
+		_, ok := BearerToken("Basic dXNlcjpwYXNz")
+		assert.False(t, ok)
+	})
+
+	t.Run("empty value", func(t *testing.T) {
+		_, ok := BearerToken("")
+		assert.False(t, ok)
+	})
+}
@@ -1,6 +1,9 @@
 package security
 
 import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"os"
 	"strings"
 )
@@ -12,24 +15,79 @@ const (
 	AppAPITokenEnvVar = "APP_API_TOKEN"
 	// APITokenHeader is header name for http/gRPC calls to hold the token.
 	APITokenHeader = "dapr-api-token"
+	// PreviousAPITokensEnvVar and PreviousAppAPITokensEnvVar are the environment variables for the previous api
+	// tokens, which remain valid while the api token is rotated. The api token environment variables hold a single
+	// token, taken as is.
+	PreviousAPITokensEnvVar    = "DAPR_API_TOKEN_PREVIOUS"
+	PreviousAppAPITokensEnvVar = "APP_API_TOKEN_PREVIOUS"
+	// APITokensSeparator separates the tokens of the previous api tokens environment variables.
+	APITokensSeparator = ","
+
+	// apiTokenIDLength is the number of hex characters of the token hash identifying a token in the metrics.
+	apiTokenIDLength = 8
 )
 
-var excludedRoutes = []string{"/healthz"}
+// excludedRoutes are the paths of the routes which don't require authentication.
+var excludedRoutes = []string{"/v1.0/healthz", "/v1.0/healthz/outbound"}
 
-// GetAPIToken returns the value of the api token from an environment variable.
+// GetAPIToken returns the value of the current api token from an environment variable.
 func GetAPIToken() string {
 	return os.Getenv(APITokenEnvVar)
 }
 
-// GetAppToken returns the value of the app api token from an environment variable.
+// GetAPITokens returns the current api token followed by the previous ones, from environment variables.
+// The previous tokens are accepted while the api token is rotated.
+func GetAPITokens() []string {
+	return validTokens(os.Getenv(APITokenEnvVar), os.Getenv(PreviousAPITokensEnvVar))
+}
+
+// GetAppToken returns the value of the current app api token from an environment variable.
 func GetAppToken() string {
 	return os.Getenv(AppAPITokenEnvVar)
 }
 
-// ExcludedRoute returns whether a given route should be excluded from a token check.
-func ExcludedRoute(route string) bool {
+// GetAppTokens returns the current app api token followed by the previous ones, from environment variables.
+// The runtime sends the current token to the app, which accepts all of them while the token is rotated.
+func GetAppTokens() []string {
+	return validTokens(os.Getenv(AppAPITokenEnvVar), os.Getenv(PreviousAppAPITokensEnvVar))
+}
+
+// MatchAPIToken returns the valid token matching the token of a request.
+func MatchAPIToken(tokens []string, token string) (string, bool) {
+	for _, t := range tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// APITokenID returns the identifier of a token in the metrics, derived from its hash so that the token isn't exposed.
+func APITokenID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])[:apiTokenIDLength]
+}
+
+// validTokens returns the current token followed by the previous ones, or no token if there is no current token.
+func validTokens(current, previous string) []string {
+	if current == "" {
+		return []string{}
+	}
+	tokens := []string{current}
+	for _, t := range strings.Split(previous, APITokensSeparator) {
+		if t = strings.TrimSpace(t); t != "" {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
+
+// ExcludedRoute returns whether the requests of a given path should be excluded from a token check. The path is the
+// decoded path of the request, which must be the path of an excluded route, so that neither the query string nor
+// other path segments exclude a route.
+func ExcludedRoute(path string) bool {
 	for _, r := range excludedRoutes {
-		if strings.Contains(route, r) {
+		if path == r {
 			return true
 		}
 	}
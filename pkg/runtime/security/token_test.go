@@ -41,11 +41,96 @@ func TestAppToken(t *testing.T) {
 	})
 }
 
+func TestAPITokens(t *testing.T) {
+	t.Run("tokens during rotation", func(t *testing.T) {
+		os.Setenv(APITokenEnvVar, "new-token")
+		defer os.Unsetenv(APITokenEnvVar)
+		os.Setenv(PreviousAPITokensEnvVar, "old-token, older-token")
+		defer os.Unsetenv(PreviousAPITokensEnvVar)
+
+		assert.Equal(t, []string{"new-token", "old-token", "older-token"}, GetAPITokens())
+		assert.Equal(t, "new-token", GetAPIToken())
+	})
+
+	t.Run("app tokens during rotation", func(t *testing.T) {
+		os.Setenv(AppAPITokenEnvVar, "new-token")
+		defer os.Unsetenv(AppAPITokenEnvVar)
+		os.Setenv(PreviousAppAPITokensEnvVar, "old-token,")
+		defer os.Unsetenv(PreviousAppAPITokensEnvVar)
+
+		assert.Equal(t, []string{"new-token", "old-token"}, GetAppTokens())
+		assert.Equal(t, "new-token", GetAppToken())
+	})
+
+	t.Run("token with a separator is taken as is", func(t *testing.T) {
+		os.Setenv(APITokenEnvVar, " a,b ")
+		defer os.Unsetenv(APITokenEnvVar)
+
+		assert.Equal(t, []string{" a,b "}, GetAPITokens())
+		assert.Equal(t, " a,b ", GetAPIToken())
+	})
+
+	t.Run("previous tokens without current token", func(t *testing.T) {
+		os.Setenv(PreviousAPITokensEnvVar, "old-token")
+		defer os.Unsetenv(PreviousAPITokensEnvVar)
+
+		assert.Empty(t, GetAPITokens())
+	})
+
+	t.Run("non-existent tokens", func(t *testing.T) {
+		assert.Empty(t, GetAPITokens())
+		assert.Empty(t, GetAppTokens())
+	})
+}
+
+func TestMatchAPIToken(t *testing.T) {
+	tokens := []string{"new-token", "old-token"}
+
+	t.Run("current token", func(t *testing.T) {
+		token, ok := MatchAPIToken(tokens, "new-token")
+		assert.True(t, ok)
+		assert.Equal(t, "new-token", token)
+	})
+
+	t.Run("previous token", func(t *testing.T) {
+		token, ok := MatchAPIToken(tokens, "old-token")
+		assert.True(t, ok)
+		assert.Equal(t, "old-token", token)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		_, ok := MatchAPIToken(tokens, "new-token,old-token")
+		assert.False(t, ok)
+	})
+
+	t.Run("empty token", func(t *testing.T) {
+		_, ok := MatchAPIToken(tokens, "")
+		assert.False(t, ok)
+	})
+}
+
+func TestAPITokenID(t *testing.T) {
+	id := APITokenID("new-token")
+	assert.Len(t, id, 8)
+	assert.Equal(t, id, APITokenID("new-token"))
+	assert.NotEqual(t, id, APITokenID("old-token"))
+}
+
 func TestExcludedRoute(t *testing.T) {
 	t.Run("healthz route is excluded", func(t *testing.T) {
-		route := "v1.0/healthz"
+		assert.True(t, ExcludedRoute("/v1.0/healthz"))
+		assert.True(t, ExcludedRoute("/v1.0/healthz/outbound"))
+	})
+
+	t.Run("detailed healthz route is not excluded", func(t *testing.T) {
+		route := "/v1.0/healthz/detailed"
 		excluded := ExcludedRoute(route)
-		assert.True(t, excluded)
+		assert.False(t, excluded)
+	})
+
+	t.Run("route containing an excluded route is not excluded", func(t *testing.T) {
+		assert.False(t, ExcludedRoute("/v1.0/state/statestore/healthz"))
+		assert.False(t, ExcludedRoute("/v1.0/state/statestore?route=/v1.0/healthz"))
 	})
 
 	t.Run("custom route is not excluded", func(t *testing.T) {
@@ -15,6 +15,8 @@ package runtime
 
 import (
 	"go.opencensus.io/trace"
+
+	diag "github.com/dapr/dapr/pkg/diagnostics"
 )
 
 // traceExporterStore allows us to capture the trace exporter store registrations.
@@ -31,8 +33,9 @@ type traceExporterStore interface {
 type openCensusExporterStore struct{}
 
 // RegisterExporter implements traceExporterStore using OpenCensus's global registration.
+// The exporter exports the spans of the failed requests sampled by the errors sampling rules with their duration.
 func (s openCensusExporterStore) RegisterExporter(exporter trace.Exporter) {
-	trace.RegisterExporter(exporter)
+	trace.RegisterExporter(diag.ExportFailedRequestSpans(exporter))
 }
 
 // fakeTraceExporterStore implements traceExporterStore by merely record the exporters
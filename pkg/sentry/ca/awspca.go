@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ca
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/acmpca"
+	"github.com/aws/aws-sdk-go/service/acmpca/acmpcaiface"
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/sentry/config"
+)
+
+// awsPCASubordinateCATemplate is the template of AWS Private CA issuing CA certs which can only issue end-entity certs.
+const awsPCASubordinateCATemplate = "arn:aws:acm-pca:::template/SubordinateCACertificate_PathLen0/V1"
+
+// awsPCAIssuer signs the issuer cert with an AWS Private CA. The AWS credentials are loaded from the environment.
+type awsPCAIssuer struct {
+	caArn  string
+	client acmpcaiface.ACMPCAAPI
+}
+
+func newAWSPCAIssuer(conf config.AWSPCAConfig) (Issuer, error) {
+	if conf.CAArn == "" {
+		return nil, errors.New("AWS Private CA ARN is required")
+	}
+
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(conf.Region)})
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating AWS session")
+	}
+	return &awsPCAIssuer{
+		caArn:  conf.CAArn,
+		client: acmpca.New(sess),
+	}, nil
+}
+
+func (a *awsPCAIssuer) SignIssuerCSR(ctx context.Context, csrPem []byte, ttl time.Duration) ([]byte, error) {
+	ca, err := a.client.DescribeCertificateAuthorityWithContext(ctx, &acmpca.DescribeCertificateAuthorityInput{
+		CertificateAuthorityArn: aws.String(a.caArn),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error describing AWS Private CA")
+	}
+	if ca.CertificateAuthority == nil || ca.CertificateAuthority.CertificateAuthorityConfiguration == nil {
+		return nil, errors.New("AWS Private CA has no configuration")
+	}
+
+	issued, err := a.client.IssueCertificateWithContext(ctx, &acmpca.IssueCertificateInput{
+		CertificateAuthorityArn: aws.String(a.caArn),
+		Csr:                     csrPem,
+		SigningAlgorithm:        ca.CertificateAuthority.CertificateAuthorityConfiguration.SigningAlgorithm,
+		TemplateArn:             aws.String(awsPCASubordinateCATemplate),
+		Validity: &acmpca.Validity{
+			Type:  aws.String(acmpca.ValidityPeriodTypeAbsolute),
+			Value: aws.Int64(time.Now().Add(ttl).Unix()),
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error issuing certificate with AWS Private CA")
+	}
+
+	getInput := &acmpca.GetCertificateInput{
+		CertificateAuthorityArn: aws.String(a.caArn),
+		CertificateArn:          issued.CertificateArn,
+	}
+	if err = a.client.WaitUntilCertificateIssuedWithContext(ctx, getInput); err != nil {
+		return nil, errors.Wrap(err, "error waiting for the certificate of AWS Private CA")
+	}
+
+	cert, err := a.client.GetCertificateWithContext(ctx, getInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting the certificate of AWS Private CA")
+	}
+
+	// Fall back to the cert of the CA in case the chain up to the root is not returned.
+	chain := aws.StringValue(cert.CertificateChain)
+	if chain == "" {
+		caCert, err := a.client.GetCertificateAuthorityCertificateWithContext(ctx, &acmpca.GetCertificateAuthorityCertificateInput{
+			CertificateAuthorityArn: aws.String(a.caArn),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "error getting the certificate of AWS Private CA")
+		}
+		chain = aws.StringValue(caCert.Certificate)
+	}
+	return []byte(strings.TrimSpace(aws.StringValue(cert.Certificate)) + "\n" + strings.TrimSpace(chain) + "\n"), nil
+}
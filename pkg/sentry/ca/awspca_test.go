@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ca
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/acmpca"
+	"github.com/aws/aws-sdk-go/service/acmpca/acmpcaiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/dapr/pkg/sentry/certs"
+)
+
+const testAWSPCAArn = "arn:aws:acm-pca:us-east-1:123456789012:certificate-authority/corp"
+
+type fakeACMPCA struct {
+	acmpcaiface.ACMPCAAPI
+	store  *testCAStore
+	issued []byte
+	input  *acmpca.IssueCertificateInput
+}
+
+func (f *fakeACMPCA) DescribeCertificateAuthorityWithContext(ctx aws.Context, input *acmpca.DescribeCertificateAuthorityInput, opts ...request.Option) (*acmpca.DescribeCertificateAuthorityOutput, error) {
+	return &acmpca.DescribeCertificateAuthorityOutput{
+		CertificateAuthority: &acmpca.CertificateAuthority{
+			CertificateAuthorityConfiguration: &acmpca.CertificateAuthorityConfiguration{
+				SigningAlgorithm: aws.String(acmpca.SigningAlgorithmSha256withecdsa),
+			},
+		},
+	}, nil
+}
+
+func (f *fakeACMPCA) IssueCertificateWithContext(ctx aws.Context, input *acmpca.IssueCertificateInput, opts ...request.Option) (*acmpca.IssueCertificateOutput, error) {
+	f.input = input
+	issued, err := f.store.sign(input.Csr, time.Until(time.Unix(*input.Validity.Value, 0)))
+	if err != nil {
+		return nil, err
+	}
+	f.issued = issued
+	return &acmpca.IssueCertificateOutput{CertificateArn: aws.String(testAWSPCAArn + "/certificate/issuer")}, nil
+}
+
+func (f *fakeACMPCA) WaitUntilCertificateIssuedWithContext(ctx aws.Context, input *acmpca.GetCertificateInput, opts ...request.WaiterOption) error {
+	return nil
+}
+
+func (f *fakeACMPCA) GetCertificateWithContext(ctx aws.Context, input *acmpca.GetCertificateInput, opts ...request.Option) (*acmpca.GetCertificateOutput, error) {
+	return &acmpca.GetCertificateOutput{
+		Certificate:      aws.String(string(f.issued)),
+		CertificateChain: aws.String(string(f.store.rootPem)),
+	}, nil
+}
+
+func TestAWSPCAIssuer(t *testing.T) {
+	store := newTestCAStore(t)
+	client := &fakeACMPCA{store: store}
+	issuer := &awsPCAIssuer{
+		caArn:  testAWSPCAArn,
+		client: client,
+	}
+
+	_, csrPem, err := newIssuerCSR()
+	require.NoError(t, err)
+
+	chainPem, err := issuer.SignIssuerCSR(context.Background(), csrPem, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, testAWSPCAArn, *client.input.CertificateAuthorityArn)
+	assert.Equal(t, awsPCASubordinateCATemplate, *client.input.TemplateArn)
+	assert.Equal(t, acmpca.SigningAlgorithmSha256withecdsa, *client.input.SigningAlgorithm)
+
+	chain, err := certs.DecodePEMCertificates(chainPem)
+	assert.NoError(t, err)
+	assert.Len(t, chain, 2)
+	assert.Equal(t, caCommonName, chain[0].Subject.CommonName)
+	assert.Equal(t, store.rootCert.Raw, chain[1].Raw)
+}
@@ -37,23 +37,33 @@ type CertificateAuthority interface {
 	GetCACertBundle() TrustRootBundler
 	SignCSR(csrPem []byte, subject string, identity *identity.Bundle, ttl time.Duration, isCA bool) (*SignedCertificate, error)
 	ValidateCSR(csr *x509.CertificateRequest) error
+	// IssuerCertRenewalTime returns the time at which the issuer cert is renewed with the CA store, and false if
+	// the issuer cert is not renewed by sentry.
+	IssuerCertRenewalTime() (time.Time, bool)
 }
 
 func NewCertificateAuthority(config config.SentryConfig) (CertificateAuthority, error) {
-	// Load future external CAs from components-contrib.
-	switch config.CAStore {
-	default:
-		return &defaultCA{
-			config:     config,
-			issuerLock: &sync.RWMutex{},
-		}, nil
+	ca := &defaultCA{
+		config:     config,
+		issuerLock: &sync.RWMutex{},
 	}
+
+	// The issuer cert is signed by an external CA store if one is configured, and self signed otherwise.
+	if config.CAStore != "" {
+		issuer, err := newIssuer(config)
+		if err != nil {
+			return nil, err
+		}
+		ca.issuer = issuer
+	}
+	return ca, nil
 }
 
 type defaultCA struct {
 	bundle     *trustRootBundle
 	config     config.SentryConfig
 	issuerLock *sync.RWMutex
+	issuer     Issuer
 }
 
 type SignedCertificate struct {
@@ -122,6 +132,14 @@ func (c *defaultCA) SignCSR(csrPem []byte, subject string, identity *identity.Bu
 	}, nil
 }
 
+// IssuerCertRenewalTime returns the time at which the issuer cert is renewed with the CA store.
+func (c *defaultCA) IssuerCertRenewalTime() (time.Time, bool) {
+	if c.issuer == nil {
+		return time.Time{}, false
+	}
+	return issuerCertRenewalTime(c.bundle.issuerCreds.Certificate), true
+}
+
 func (c *defaultCA) ValidateCSR(csr *x509.CertificateRequest) error {
 	if csr.Subject.CommonName == "" {
 		return errors.New("cannot validate request: missing common name")
@@ -171,31 +189,30 @@ func (c *defaultCA) validateAndBuildTrustBundle() (*trustRootBundle, error) {
 		issuerCreds     *certs.Credentials
 		rootCertBytes   []byte
 		issuerCertBytes []byte
+		err             error
 	)
 
 	// certs exist on disk or getting created, load them when ready
 	if !shouldCreateCerts(c.config) {
-		err := detectCertificates(c.config.RootCertPath)
+		issuerCreds, rootCertBytes, issuerCertBytes, err = c.loadCertsFromDisk()
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		certChain, err := credentials.LoadFromDisk(c.config.RootCertPath, c.config.IssuerCertPath, c.config.IssuerKeyPath)
-		if err != nil {
-			return nil, errors.Wrap(err, "error loading cert chain from disk")
-		}
-
-		issuerCreds, err = certs.PEMCredentialsFromFiles(certChain.Cert, certChain.Key)
+	switch {
+	case c.issuer != nil && (issuerCreds == nil || !time.Now().Before(issuerCertRenewalTime(issuerCreds.Certificate))):
+		// request a new issuer cert from the CA store
+		log.Infof("requesting issuer cert from CA store %s", c.config.CAStore)
+		issuerCreds, rootCertBytes, issuerCertBytes, err = c.requestIssuerCerts()
 		if err != nil {
-			return nil, errors.Wrap(err, "error reading PEM credentials")
+			return nil, errors.Wrap(err, "error requesting issuer cert")
 		}
 
-		rootCertBytes = certChain.RootCA
-		issuerCertBytes = certChain.Cert
-	} else {
+		log.Info("issuer cert signed by the CA store and persisted successfully")
+	case issuerCreds == nil:
 		// create self signed root and issuer certs
 		log.Info("root and issuer certs not found: generating self signed CA")
-		var err error
 		issuerCreds, rootCertBytes, issuerCertBytes, err = c.generateRootAndIssuerCerts()
 		if err != nil {
 			return nil, errors.Wrap(err, "error generating trust root bundle")
@@ -219,6 +236,24 @@ func (c *defaultCA) validateAndBuildTrustBundle() (*trustRootBundle, error) {
 	}, nil
 }
 
+func (c *defaultCA) loadCertsFromDisk() (*certs.Credentials, []byte, []byte, error) {
+	err := detectCertificates(c.config.RootCertPath)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	certChain, err := credentials.LoadFromDisk(c.config.RootCertPath, c.config.IssuerCertPath, c.config.IssuerKeyPath)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "error loading cert chain from disk")
+	}
+
+	issuerCreds, err := certs.PEMCredentialsFromFiles(certChain.Cert, certChain.Key)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "error reading PEM credentials")
+	}
+	return issuerCreds, certChain.RootCA, certChain.Cert, nil
+}
+
 func (c *defaultCA) generateRootAndIssuerCerts() (*certs.Credentials, []byte, []byte, error) {
 	rootKey, err := certs.GenerateECPrivateKey()
 	if err != nil {
@@ -0,0 +1,180 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ca
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/dapr/dapr/pkg/sentry/config"
+	"github.com/dapr/dapr/pkg/sentry/kubernetes"
+)
+
+const (
+	defaultCertManagerIssuerKind  = "Issuer"
+	defaultCertManagerIssuerGroup = "cert-manager.io"
+	certRequestPollInterval       = time.Second
+)
+
+var certRequestResource = schema.GroupVersionResource{
+	Group:    "cert-manager.io",
+	Version:  "v1",
+	Resource: "certificaterequests",
+}
+
+// certManagerIssuer signs the issuer cert with a cert-manager issuer, creating a CertificateRequest in the
+// namespace of sentry.
+type certManagerIssuer struct {
+	issuerName  string
+	issuerKind  string
+	issuerGroup string
+	namespace   string
+	client      dynamic.Interface
+}
+
+func newCertManagerIssuer(conf config.CertManagerConfig) (Issuer, error) {
+	if conf.IssuerName == "" {
+		return nil, errors.New("cert-manager issuer name is required")
+	}
+
+	client, err := kubernetes.GetDynamicClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create kubernetes client")
+	}
+
+	issuer := &certManagerIssuer{
+		issuerName:  conf.IssuerName,
+		issuerKind:  conf.IssuerKind,
+		issuerGroup: conf.IssuerGroup,
+		namespace:   os.Getenv("NAMESPACE"),
+		client:      client,
+	}
+	if issuer.issuerKind == "" {
+		issuer.issuerKind = defaultCertManagerIssuerKind
+	}
+	if issuer.issuerGroup == "" {
+		issuer.issuerGroup = defaultCertManagerIssuerGroup
+	}
+	if issuer.namespace == "" {
+		issuer.namespace = metav1.NamespaceDefault
+	}
+	return issuer, nil
+}
+
+func (c *certManagerIssuer) SignIssuerCSR(ctx context.Context, csrPem []byte, ttl time.Duration) ([]byte, error) {
+	request := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "cert-manager.io/v1",
+			"kind":       "CertificateRequest",
+			"metadata": map[string]interface{}{
+				"generateName": "dapr-sentry-issuer-",
+				"namespace":    c.namespace,
+			},
+			"spec": map[string]interface{}{
+				"request":  base64.StdEncoding.EncodeToString(csrPem),
+				"isCA":     true,
+				"duration": ttl.String(),
+				"issuerRef": map[string]interface{}{
+					"name":  c.issuerName,
+					"kind":  c.issuerKind,
+					"group": c.issuerGroup,
+				},
+			},
+		},
+	}
+
+	requests := c.client.Resource(certRequestResource).Namespace(c.namespace)
+	created, err := requests.Create(ctx, request, metav1.CreateOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating cert-manager certificate request")
+	}
+	// The request holds no secret and is not needed anymore once the cert is issued.
+	defer func() {
+		if err := requests.Delete(context.Background(), created.GetName(), metav1.DeleteOptions{}); err != nil {
+			log.Warnf("error deleting cert-manager certificate request %s: %s", created.GetName(), err)
+		}
+	}()
+
+	ticker := time.NewTicker(certRequestPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, errors.Wrapf(ctx.Err(), "timed out waiting for cert-manager certificate request %s", created.GetName())
+		case <-ticker.C:
+			current, err := requests.Get(ctx, created.GetName(), metav1.GetOptions{})
+			if err != nil {
+				return nil, errors.Wrap(err, "error getting cert-manager certificate request")
+			}
+
+			issued, err := certRequestIssued(current)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cert-manager certificate request %s failed", created.GetName())
+			}
+			if issued {
+				return certRequestChain(current)
+			}
+		}
+	}
+}
+
+// certRequestIssued returns true once the certificate request is ready, and an error if it was denied or failed.
+func certRequestIssued(request *unstructured.Unstructured) (bool, error) {
+	conditions, _, _ := unstructured.NestedSlice(request.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		conditionType, _, _ := unstructured.NestedString(condition, "type")
+		status, _, _ := unstructured.NestedString(condition, "status")
+		reason, _, _ := unstructured.NestedString(condition, "reason")
+		message, _, _ := unstructured.NestedString(condition, "message")
+		switch {
+		case conditionType == "Ready" && status == string(metav1.ConditionTrue):
+			return true, nil
+		case conditionType == "Ready" && reason == "Failed":
+			return false, errors.New(message)
+		case (conditionType == "Denied" || conditionType == "InvalidRequest") && status == string(metav1.ConditionTrue):
+			return false, errors.New(message)
+		}
+	}
+	return false, nil
+}
+
+func certRequestChain(request *unstructured.Unstructured) ([]byte, error) {
+	chain := []string{}
+	for _, field := range []string{"certificate", "ca"} {
+		encoded, _, _ := unstructured.NestedString(request.Object, "status", field)
+		b, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error decoding %s of cert-manager certificate request", field)
+		}
+		if len(b) > 0 {
+			chain = append(chain, strings.TrimSpace(string(b)))
+		}
+	}
+	return []byte(strings.Join(chain, "\n") + "\n"), nil
+}
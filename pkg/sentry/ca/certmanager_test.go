@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ca
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/dapr/dapr/pkg/sentry/certs"
+)
+
+func newTestCertRequest(status map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "cert-manager.io/v1",
+			"kind":       "CertificateRequest",
+			"status":     status,
+		},
+	}
+}
+
+func TestCertRequestIssued(t *testing.T) {
+	t.Run("pending", func(t *testing.T) {
+		issued, err := certRequestIssued(newTestCertRequest(map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False", "reason": "Pending"},
+			},
+		}))
+		assert.NoError(t, err)
+		assert.False(t, issued)
+	})
+
+	t.Run("ready", func(t *testing.T) {
+		issued, err := certRequestIssued(newTestCertRequest(map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Approved", "status": "True"},
+				map[string]interface{}{"type": "Ready", "status": "True", "reason": "Issued"},
+			},
+		}))
+		assert.NoError(t, err)
+		assert.True(t, issued)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		_, err := certRequestIssued(newTestCertRequest(map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False", "reason": "Failed", "message": "issuer not found"},
+			},
+		}))
+		assert.EqualError(t, err, "issuer not found")
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		_, err := certRequestIssued(newTestCertRequest(map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Denied", "status": "True", "message": "denied by policy"},
+			},
+		}))
+		assert.EqualError(t, err, "denied by policy")
+	})
+}
+
+func TestCertRequestChain(t *testing.T) {
+	chainPem, err := certRequestChain(newTestCertRequest(map[string]interface{}{
+		"certificate": base64.StdEncoding.EncodeToString([]byte(issuerCert)),
+		"ca":          base64.StdEncoding.EncodeToString([]byte(rootCert)),
+	}))
+	assert.NoError(t, err)
+
+	chain, err := certs.DecodePEMCertificates(chainPem)
+	assert.NoError(t, err)
+	assert.Len(t, chain, 2)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ca
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/sentry/certs"
+	"github.com/dapr/dapr/pkg/sentry/config"
+)
+
+const (
+	issuerCertSignTimeout = time.Minute * 5
+	// issuerCertRenewalRatio is the part of the lifetime of an issuer cert after which a new one is requested.
+	issuerCertRenewalRatio = 0.7
+)
+
+// Issuer signs the issuer cert of sentry with an external CA store, so that the workload certs chain to an existing PKI.
+// Sentry keeps signing the workload certs with the issuer cert.
+type Issuer interface {
+	// SignIssuerCSR signs the PEM encoded CSR of the issuer cert. It returns the PEM encoded issuer cert followed by
+	// the certs of the CA store up to and including the root cert.
+	SignIssuerCSR(ctx context.Context, csrPem []byte, ttl time.Duration) ([]byte, error)
+}
+
+func newIssuer(conf config.SentryConfig) (Issuer, error) {
+	switch conf.CAStore {
+	case config.CAStoreVault:
+		return newVaultIssuer(conf.Vault)
+	case config.CAStoreAWSPCA:
+		return newAWSPCAIssuer(conf.AWSPCA)
+	case config.CAStoreCertManager:
+		return newCertManagerIssuer(conf.CertManager)
+	default:
+		return nil, errors.Errorf("unknown CA store: %s", conf.CAStore)
+	}
+}
+
+// issuerCertRenewalTime returns the time at which a new issuer cert is requested from the CA store.
+func issuerCertRenewalTime(cert *x509.Certificate) time.Time {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	return cert.NotBefore.Add(time.Duration(float64(lifetime) * issuerCertRenewalRatio))
+}
+
+// requestIssuerCerts generates the issuer key and has the issuer cert signed by the CA store.
+// The credentials are persisted so that they are loaded when sentry restarts.
+func (c *defaultCA) requestIssuerCerts() (*certs.Credentials, []byte, []byte, error) {
+	issuerKey, csrPem, err := newIssuerCSR()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), issuerCertSignTimeout)
+	defer cancel()
+
+	chainPem, err := c.issuer.SignIssuerCSR(ctx, csrPem, c.config.IssuerCertTTL)
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(err, "error signing issuer csr with CA store %s", c.config.CAStore)
+	}
+
+	issuerCertPem, rootCertPem, err := splitIssuerCertChain(chainPem)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	encodedKey, err := x509.MarshalECPrivateKey(issuerKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	issuerKeyPem := pem.EncodeToMemory(&pem.Block{Type: certs.ECPrivateKey, Bytes: encodedKey})
+
+	issuerCreds, err := certs.PEMCredentialsFromFiles(issuerCertPem, issuerKeyPem)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "error reading issuer credentials signed by the CA store")
+	}
+
+	err = certs.StoreCredentials(c.config, rootCertPem, issuerCertPem, issuerKeyPem)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return issuerCreds, rootCertPem, issuerCertPem, nil
+}
+
+// newIssuerCSR generates the issuer key and returns it with the PEM encoded CSR of the issuer cert.
+func newIssuerCSR() (*ecdsa.PrivateKey, []byte, error) {
+	issuerKey, err := certs.GenerateECPrivateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	csrBytes, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{
+			Organization: []string{caOrg},
+			CommonName:   caCommonName,
+		},
+	}, issuerKey)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error creating issuer csr")
+	}
+	return issuerKey, pem.EncodeToMemory(&pem.Block{Type: certs.CertificateRequest, Bytes: csrBytes}), nil
+}
+
+// splitIssuerCertChain splits a PEM encoded cert chain returned by a CA store into the issuer cert followed by the
+// intermediate certs, and the root cert.
+func splitIssuerCertChain(chainPem []byte) ([]byte, []byte, error) {
+	chain, err := certs.DecodePEMCertificates(chainPem)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error decoding cert chain of the CA store")
+	}
+	if len(chain) < 2 {
+		return nil, nil, errors.New("the cert chain of the CA store is missing the CA certs")
+	}
+
+	var issuerCertPem []byte
+	for _, cert := range chain[:len(chain)-1] {
+		issuerCertPem = append(issuerCertPem, pem.EncodeToMemory(&pem.Block{Type: certs.Certificate, Bytes: cert.Raw})...)
+	}
+	rootCertPem := pem.EncodeToMemory(&pem.Block{Type: certs.Certificate, Bytes: chain[len(chain)-1].Raw})
+	return issuerCertPem, rootCertPem, nil
+}
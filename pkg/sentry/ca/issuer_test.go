@@ -0,0 +1,220 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ca
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/dapr/pkg/sentry/certs"
+	"github.com/dapr/dapr/pkg/sentry/config"
+)
+
+// testCAStore is a CA store signing the issuer certs with a self signed root cert.
+type testCAStore struct {
+	rootCert *x509.Certificate
+	rootKey  *ecdsa.PrivateKey
+	rootPem  []byte
+	signed   int
+	// backdate is subtracted from the start of the validity of the signed certs.
+	backdate time.Duration
+}
+
+func newTestCAStore(t *testing.T) *testCAStore {
+	rootKey, err := certs.GenerateECPrivateKey()
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "corporate root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour * 24),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	rootBytes, err := x509.CreateCertificate(rand.Reader, template, template, &rootKey.PublicKey, rootKey)
+	require.NoError(t, err)
+	rootCert, err := x509.ParseCertificate(rootBytes)
+	require.NoError(t, err)
+
+	return &testCAStore{
+		rootCert: rootCert,
+		rootKey:  rootKey,
+		rootPem:  pem.EncodeToMemory(&pem.Block{Type: certs.Certificate, Bytes: rootBytes}),
+	}
+}
+
+func (s *testCAStore) sign(csrPem []byte, ttl time.Duration) ([]byte, error) {
+	csr, err := certs.ParsePemCSR(csrPem)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               csr.Subject,
+		NotBefore:             time.Now().Add(-s.backdate),
+		NotAfter:              time.Now().Add(ttl),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, s.rootCert, csr.PublicKey, s.rootKey)
+	if err != nil {
+		return nil, err
+	}
+	s.signed++
+	return pem.EncodeToMemory(&pem.Block{Type: certs.Certificate, Bytes: certBytes}), nil
+}
+
+func (s *testCAStore) SignIssuerCSR(ctx context.Context, csrPem []byte, ttl time.Duration) ([]byte, error) {
+	certPem, err := s.sign(csrPem, ttl)
+	if err != nil {
+		return nil, err
+	}
+	return append(certPem, s.rootPem...), nil
+}
+
+func getTestIssuerCertAuth(t *testing.T, issuer Issuer) *defaultCA {
+	dir := t.TempDir()
+	conf, _ := config.FromConfigName("")
+	conf.CAStore = config.CAStoreVault
+	conf.RootCertPath = filepath.Join(dir, "ca.crt")
+	conf.IssuerCertPath = filepath.Join(dir, "issuer.crt")
+	conf.IssuerKeyPath = filepath.Join(dir, "issuer.key")
+	conf.IssuerCertTTL = time.Hour
+	return &defaultCA{
+		config:     conf,
+		issuerLock: &sync.RWMutex{},
+		issuer:     issuer,
+	}
+}
+
+func TestNewIssuer(t *testing.T) {
+	t.Run("unknown CA store", func(t *testing.T) {
+		_, err := NewCertificateAuthority(config.SentryConfig{CAStore: "unknown"})
+		assert.Error(t, err)
+	})
+
+	t.Run("vault without address", func(t *testing.T) {
+		_, err := NewCertificateAuthority(config.SentryConfig{CAStore: config.CAStoreVault})
+		assert.Error(t, err)
+	})
+
+	t.Run("aws pca without arn", func(t *testing.T) {
+		_, err := NewCertificateAuthority(config.SentryConfig{CAStore: config.CAStoreAWSPCA})
+		assert.Error(t, err)
+	})
+
+	t.Run("self signed without CA store", func(t *testing.T) {
+		certAuth, err := NewCertificateAuthority(config.SentryConfig{})
+		assert.NoError(t, err)
+		assert.Nil(t, certAuth.(*defaultCA).issuer)
+	})
+}
+
+func TestLoadOrStoreTrustBundleWithIssuer(t *testing.T) {
+	t.Run("issuer cert is signed by the CA store", func(t *testing.T) {
+		store := newTestCAStore(t)
+		certAuth := getTestIssuerCertAuth(t, store)
+
+		err := certAuth.LoadOrStoreTrustBundle()
+		require.NoError(t, err)
+		assert.Equal(t, 1, store.signed)
+		assert.Equal(t, store.rootPem, certAuth.GetCACertBundle().GetRootCertPem())
+
+		issuerCert := certAuth.bundle.issuerCreds.Certificate
+		assert.Equal(t, "corporate root", issuerCert.Issuer.CommonName)
+		assert.NoError(t, issuerCert.CheckSignatureFrom(store.rootCert))
+
+		stored, err := os.ReadFile(certAuth.config.IssuerCertPath)
+		require.NoError(t, err)
+		assert.Equal(t, certAuth.GetCACertBundle().GetIssuerCertPem(), stored)
+
+		renewal, ok := certAuth.IssuerCertRenewalTime()
+		assert.True(t, ok)
+		assert.True(t, renewal.After(issuerCert.NotBefore))
+		assert.True(t, renewal.Before(issuerCert.NotAfter))
+	})
+
+	t.Run("valid issuer cert is loaded from disk", func(t *testing.T) {
+		store := newTestCAStore(t)
+		certAuth := getTestIssuerCertAuth(t, store)
+		require.NoError(t, certAuth.LoadOrStoreTrustBundle())
+
+		reloaded := &defaultCA{
+			config:     certAuth.config,
+			issuerLock: &sync.RWMutex{},
+			issuer:     store,
+		}
+		require.NoError(t, reloaded.LoadOrStoreTrustBundle())
+		assert.Equal(t, 1, store.signed)
+		assert.Equal(t, certAuth.GetCACertBundle().GetIssuerCertPem(), reloaded.GetCACertBundle().GetIssuerCertPem())
+	})
+
+	t.Run("issuer cert is renewed", func(t *testing.T) {
+		store := newTestCAStore(t)
+		store.backdate = time.Hour
+		certAuth := getTestIssuerCertAuth(t, store)
+		certAuth.config.IssuerCertTTL = time.Minute
+		require.NoError(t, certAuth.LoadOrStoreTrustBundle())
+
+		renewal, _ := certAuth.IssuerCertRenewalTime()
+		assert.True(t, renewal.Before(time.Now()))
+		require.NoError(t, certAuth.LoadOrStoreTrustBundle())
+		assert.Equal(t, 2, store.signed)
+	})
+}
+
+func TestSplitIssuerCertChain(t *testing.T) {
+	t.Run("chain with intermediate", func(t *testing.T) {
+		chain := []byte(issuerCert + "\n" + issuerCert + "\n" + rootCert)
+		issuerCertPem, rootCertPem, err := splitIssuerCertChain(chain)
+		assert.NoError(t, err)
+
+		issuerCerts, err := certs.DecodePEMCertificates(issuerCertPem)
+		assert.NoError(t, err)
+		assert.Len(t, issuerCerts, 2)
+		assert.Equal(t, rootCert+"\n", string(rootCertPem))
+	})
+
+	t.Run("chain without CA certs", func(t *testing.T) {
+		_, _, err := splitIssuerCertChain([]byte(issuerCert))
+		assert.Error(t, err)
+	})
+}
+
+func TestIssuerCertRenewalTime(t *testing.T) {
+	now := time.Now()
+	cert := &x509.Certificate{
+		NotBefore: now,
+		NotAfter:  now.Add(time.Hour * 10),
+	}
+	assert.Equal(t, now.Add(time.Hour*7), issuerCertRenewalTime(cert))
+}
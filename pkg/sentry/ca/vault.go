@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ca
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/sentry/config"
+)
+
+const (
+	vaultTokenHeader    = "X-Vault-Token"
+	defaultVaultPKIPath = "pki"
+)
+
+// vaultIssuer signs the issuer cert with the root/sign-intermediate endpoint of a Vault PKI secrets engine.
+type vaultIssuer struct {
+	address   string
+	tokenPath string
+	pkiPath   string
+	client    *http.Client
+}
+
+type vaultSignIntermediateRequest struct {
+	CSR        string `json:"csr"`
+	CommonName string `json:"common_name"`
+	TTL        string `json:"ttl"`
+	Format     string `json:"format"`
+}
+
+type vaultSignIntermediateResponse struct {
+	Data struct {
+		Certificate string   `json:"certificate"`
+		IssuingCA   string   `json:"issuing_ca"`
+		CAChain     []string `json:"ca_chain"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+func newVaultIssuer(conf config.VaultConfig) (Issuer, error) {
+	if conf.Address == "" {
+		return nil, errors.New("vault address is required")
+	}
+	if conf.TokenPath == "" {
+		return nil, errors.New("vault token path is required")
+	}
+
+	pkiPath := strings.Trim(conf.PKIPath, "/")
+	if pkiPath == "" {
+		pkiPath = defaultVaultPKIPath
+	}
+	return &vaultIssuer{
+		address:   strings.TrimSuffix(conf.Address, "/"),
+		tokenPath: conf.TokenPath,
+		pkiPath:   pkiPath,
+		client:    &http.Client{Timeout: time.Minute},
+	}, nil
+}
+
+func (v *vaultIssuer) SignIssuerCSR(ctx context.Context, csrPem []byte, ttl time.Duration) ([]byte, error) {
+	// The token is read on every request as it is usually rotated by the Vault agent.
+	token, err := ioutil.ReadFile(v.tokenPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading vault token")
+	}
+
+	body, err := json.Marshal(vaultSignIntermediateRequest{
+		CSR:        string(csrPem),
+		CommonName: caCommonName,
+		TTL:        ttl.String(),
+		Format:     "pem",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/root/sign-intermediate", v.address, v.pkiPath)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(vaultTokenHeader, strings.TrimSpace(string(token)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "error calling vault")
+	}
+	defer resp.Body.Close()
+
+	var signResp vaultSignIntermediateResponse
+	if err = json.NewDecoder(resp.Body).Decode(&signResp); err != nil {
+		return nil, errors.Wrapf(err, "error decoding vault response with status %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("vault returned status %d: %s", resp.StatusCode, strings.Join(signResp.Errors, ", "))
+	}
+
+	// ca_chain is only set when the mount holds the chain of its CA, otherwise the CA of the mount is the root.
+	caChain := signResp.Data.CAChain
+	if len(caChain) == 0 {
+		caChain = []string{signResp.Data.IssuingCA}
+	}
+
+	chain := []string{strings.TrimSpace(signResp.Data.Certificate)}
+	for _, c := range caChain {
+		chain = append(chain, strings.TrimSpace(c))
+	}
+	return []byte(strings.Join(chain, "\n") + "\n"), nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ca
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/dapr/pkg/sentry/certs"
+	"github.com/dapr/dapr/pkg/sentry/config"
+)
+
+func newTestVaultIssuer(t *testing.T, handler http.HandlerFunc) Issuer {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("s.token\n"), 0600))
+
+	issuer, err := newVaultIssuer(config.VaultConfig{
+		Address:   server.URL + "/",
+		TokenPath: tokenPath,
+		PKIPath:   "/corp-pki/",
+	})
+	require.NoError(t, err)
+	return issuer
+}
+
+func TestVaultIssuer(t *testing.T) {
+	store := newTestCAStore(t)
+	_, csrPem, err := newIssuerCSR()
+	require.NoError(t, err)
+
+	t.Run("issuer cert is signed", func(t *testing.T) {
+		issuer := newTestVaultIssuer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/v1/corp-pki/root/sign-intermediate", r.URL.Path)
+			assert.Equal(t, "s.token", r.Header.Get(vaultTokenHeader))
+
+			var req vaultSignIntermediateRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "1h0m0s", req.TTL)
+
+			certPem, err := store.sign([]byte(req.CSR), time.Hour)
+			require.NoError(t, err)
+
+			resp := vaultSignIntermediateResponse{}
+			resp.Data.Certificate = string(certPem)
+			resp.Data.IssuingCA = string(store.rootPem)
+			json.NewEncoder(w).Encode(resp)
+		})
+
+		chainPem, err := issuer.SignIssuerCSR(context.Background(), csrPem, time.Hour)
+		assert.NoError(t, err)
+
+		chain, err := certs.DecodePEMCertificates(chainPem)
+		assert.NoError(t, err)
+		assert.Len(t, chain, 2)
+		assert.Equal(t, caCommonName, chain[0].Subject.CommonName)
+		assert.Equal(t, store.rootCert.Raw, chain[1].Raw)
+	})
+
+	t.Run("vault error", func(t *testing.T) {
+		issuer := newTestVaultIssuer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+		})
+
+		_, err := issuer.SignIssuerCSR(context.Background(), csrPem, time.Hour)
+		assert.EqualError(t, err, "vault returned status 403: permission denied")
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		issuer, err := newVaultIssuer(config.VaultConfig{
+			Address:   "http://localhost:8200",
+			TokenPath: filepath.Join(t.TempDir(), "missing"),
+		})
+		require.NoError(t, err)
+
+		_, err = issuer.SignIssuerCSR(context.Background(), csrPem, time.Hour)
+		assert.Error(t, err)
+	})
+}
@@ -12,10 +12,11 @@ import (
 )
 
 const (
-	Certificate     = "CERTIFICATE"
-	ECPrivateKey    = "EC PRIVATE KEY"
-	RSAPrivateKey   = "RSA PRIVATE KEY"
-	PKCS8PrivateKey = "PRIVATE KEY"
+	Certificate        = "CERTIFICATE"
+	CertificateRequest = "CERTIFICATE REQUEST"
+	ECPrivateKey       = "EC PRIVATE KEY"
+	RSAPrivateKey      = "RSA PRIVATE KEY"
+	PKCS8PrivateKey    = "PRIVATE KEY"
 )
 
 // PrivateKey wraps a EC or RSA private key.
@@ -93,6 +94,25 @@ func decodeCertificatePEM(crtb []byte) (*x509.Certificate, []byte, error) {
 	return c, crtb, err
 }
 
+// WithTrustDomainHeader returns the PEM encoded trust anchors of a trust domain with the trust domain set in the
+// header of each PEM block, so that workloads can tell the trust anchors of each federated trust domain apart.
+func WithTrustDomainHeader(anchorsPem []byte, trustDomain string) []byte {
+	var labeled []byte
+	for {
+		var block *pem.Block
+		block, anchorsPem = pem.Decode(anchorsPem)
+		if block == nil {
+			return labeled
+		}
+
+		if block.Headers == nil {
+			block.Headers = map[string]string{}
+		}
+		block.Headers[TrustDomainPEMHeader] = trustDomain
+		labeled = append(labeled, pem.EncodeToMemory(block)...)
+	}
+}
+
 // PEMCredentialsFromFiles takes a path for a key/cert pair and returns a validated Credentials wrapper with a trust chain.
 func PEMCredentialsFromFiles(certPem, keyPem []byte) (*Credentials, error) {
 	pk, err := DecodePEMKey(keyPem)
@@ -7,4 +7,7 @@ const (
 	TrustAnchorsEnvVar = "DAPR_TRUST_ANCHORS"
 	CertChainEnvVar    = "DAPR_CERT_CHAIN"
 	CertKeyEnvVar      = "DAPR_CERT_KEY"
+	// TrustDomainPEMHeader is the PEM header holding the trust domain of the federated trust anchors handed out to
+	// the workloads.
+	TrustDomainPEMHeader = "Trust-Domain"
 )
@@ -2,6 +2,9 @@ package config
 
 import (
 	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/url"
 	"os"
 	"time"
 
@@ -22,11 +25,19 @@ const (
 	defaultPort                 = 50001
 	defaultWorkloadCertTTL      = time.Hour * 24
 	defaultAllowedClockSkew     = time.Minute * 15
+	defaultIssuerCertTTL        = time.Hour * 24 * 30
 
 	// defaultDaprSystemConfigName is the default resource object name for Dapr System Config.
 	defaultDaprSystemConfigName = "daprsystem"
 )
 
+// CA stores issuing the issuer certificate of sentry. The self-hosted CA is used when no CA store is configured.
+const (
+	CAStoreVault       = "vault"
+	CAStoreAWSPCA      = "awspca"
+	CAStoreCertManager = "certmanager"
+)
+
 var log = logger.NewLogger("dapr.sentry.config")
 
 // SentryConfig holds the configuration for the Certificate Authority.
@@ -39,6 +50,40 @@ type SentryConfig struct {
 	RootCertPath     string
 	IssuerCertPath   string
 	IssuerKeyPath    string
+	// FederatedTrustAnchors holds the PEM encoded trust anchors of remote trust domains, keyed by trust domain.
+	FederatedTrustAnchors map[string][]byte
+	// FederatedBundleEndpoints holds the SPIFFE bundle endpoints of remote trust domains, keyed by trust domain.
+	FederatedBundleEndpoints map[string]string
+	// IssuerCertTTL is the lifetime of the issuer certificate requested from an external CA store.
+	IssuerCertTTL time.Duration
+	Vault         VaultConfig
+	AWSPCA        AWSPCAConfig
+	CertManager   CertManagerConfig
+	// Audit is the audit log of the certificate signing requests.
+	Audit dapr_config.AuditSpec
+	// WatchNamespaces are the namespaces of the pods sentry issues certificates to, along with its own namespace.
+	// Certificates are issued to the pods of all namespaces if empty.
+	WatchNamespaces []string
+}
+
+// VaultConfig holds the configuration of the Vault PKI secrets engine signing the issuer certificate.
+type VaultConfig struct {
+	Address   string
+	TokenPath string
+	PKIPath   string
+}
+
+// AWSPCAConfig holds the configuration of the AWS Private CA signing the issuer certificate.
+type AWSPCAConfig struct {
+	CAArn  string
+	Region string
+}
+
+// CertManagerConfig holds the configuration of the cert-manager issuer signing the issuer certificate.
+type CertManagerConfig struct {
+	IssuerName  string
+	IssuerKind  string
+	IssuerGroup string
 }
 
 var configGetters = map[string]func(string) (SentryConfig, error){
@@ -86,6 +131,7 @@ func getDefaultConfig() SentryConfig {
 		Port:             defaultPort,
 		WorkloadCertTTL:  defaultWorkloadCertTTL,
 		AllowedClockSkew: defaultAllowedClockSkew,
+		IssuerCertTTL:    defaultIssuerCertTTL,
 	}
 }
 
@@ -155,5 +201,71 @@ func parseConfiguration(conf SentryConfig, daprConfig *dapr_config.Configuration
 		conf.AllowedClockSkew = d
 	}
 
+	conf.Audit = daprConfig.Spec.AuditSpec
+
+	for _, f := range daprConfig.Spec.MTLSSpec.Federation {
+		if f.BundleEndpoint != "" {
+			if err := validateBundleEndpoint(f); err != nil {
+				return conf, errors.Wrapf(err, "invalid bundle endpoint of federated trust domain %s", f.TrustDomain)
+			}
+
+			if conf.FederatedBundleEndpoints == nil {
+				conf.FederatedBundleEndpoints = map[string]string{}
+			}
+			conf.FederatedBundleEndpoints[f.TrustDomain] = f.BundleEndpoint
+
+			// The trust anchors are fetched from the bundle endpoint unless they are also set statically.
+			if f.TrustAnchors == "" && f.TrustAnchorsFile == "" {
+				continue
+			}
+		}
+
+		anchors, err := loadTrustAnchors(f)
+		if err != nil {
+			return conf, errors.Wrapf(err, "error loading trust anchors of federated trust domain %s", f.TrustDomain)
+		}
+
+		if conf.FederatedTrustAnchors == nil {
+			conf.FederatedTrustAnchors = map[string][]byte{}
+		}
+		conf.FederatedTrustAnchors[f.TrustDomain] = anchors
+	}
+
 	return conf, nil
 }
+
+// validateBundleEndpoint validates the SPIFFE bundle endpoint of a trust domain, which is authenticated with Web PKI.
+func validateBundleEndpoint(f dapr_config.TrustDomainFederation) error {
+	if f.TrustDomain == "" {
+		return errors.New("trust domain is required")
+	}
+
+	u, err := url.Parse(f.BundleEndpoint)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return errors.New("the bundle endpoint must be an https URL")
+	}
+	return nil
+}
+
+func loadTrustAnchors(f dapr_config.TrustDomainFederation) ([]byte, error) {
+	if f.TrustDomain == "" {
+		return nil, errors.New("trust domain is required")
+	}
+
+	anchors := []byte(f.TrustAnchors)
+	if f.TrustAnchorsFile != "" {
+		b, err := ioutil.ReadFile(f.TrustAnchorsFile)
+		if err != nil {
+			return nil, err
+		}
+		anchors = b
+	}
+
+	if block, _ := pem.Decode(anchors); block == nil {
+		return nil, errors.New("no PEM encoded certificates found")
+	}
+	return anchors, nil
+}
@@ -40,4 +40,67 @@ func TestConfig(t *testing.T) {
 		assert.Equal(t, "5s", conf.WorkloadCertTTL.String())
 		assert.Equal(t, "1h0m0s", conf.AllowedClockSkew.String())
 	})
+	t.Run("parse federated trust domains", func(t *testing.T) {
+		anchors := "-----BEGIN CERTIFICATE-----\nMIIBjjCCATOgAwIBAgIQ\n-----END CERTIFICATE-----\n"
+		daprConfig := dapr_config.Configuration{
+			Spec: dapr_config.ConfigurationSpec{
+				MTLSSpec: dapr_config.MTLSSpec{
+					Federation: []dapr_config.TrustDomainFederation{
+						{TrustDomain: "east", TrustAnchors: anchors},
+					},
+				},
+			},
+		}
+
+		conf, err := parseConfiguration(getDefaultConfig(), &daprConfig)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte(anchors), conf.FederatedTrustAnchors["east"])
+	})
+
+	t.Run("invalid federated trust anchors", func(t *testing.T) {
+		daprConfig := dapr_config.Configuration{
+			Spec: dapr_config.ConfigurationSpec{
+				MTLSSpec: dapr_config.MTLSSpec{
+					Federation: []dapr_config.TrustDomainFederation{
+						{TrustDomain: "east", TrustAnchors: "not a certificate"},
+					},
+				},
+			},
+		}
+
+		_, err := parseConfiguration(getDefaultConfig(), &daprConfig)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("parse federated trust domain bundle endpoints", func(t *testing.T) {
+		daprConfig := dapr_config.Configuration{
+			Spec: dapr_config.ConfigurationSpec{
+				MTLSSpec: dapr_config.MTLSSpec{
+					Federation: []dapr_config.TrustDomainFederation{
+						{TrustDomain: "partner", BundleEndpoint: "https://partner.example.com/bundle"},
+					},
+				},
+			},
+		}
+
+		conf, err := parseConfiguration(getDefaultConfig(), &daprConfig)
+		assert.Nil(t, err)
+		assert.Equal(t, "https://partner.example.com/bundle", conf.FederatedBundleEndpoints["partner"])
+		assert.NotContains(t, conf.FederatedTrustAnchors, "partner")
+	})
+
+	t.Run("bundle endpoint must use https", func(t *testing.T) {
+		daprConfig := dapr_config.Configuration{
+			Spec: dapr_config.ConfigurationSpec{
+				MTLSSpec: dapr_config.MTLSSpec{
+					Federation: []dapr_config.TrustDomainFederation{
+						{TrustDomain: "partner", BundleEndpoint: "http://partner.example.com/bundle"},
+					},
+				},
+			},
+		}
+
+		_, err := parseConfiguration(getDefaultConfig(), &daprConfig)
+		assert.NotNil(t, err)
+	})
 }
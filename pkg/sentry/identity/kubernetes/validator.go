@@ -18,16 +18,26 @@ const (
 	errPrefix = "csr validation failed"
 )
 
-func NewValidator(client k8s.Interface) identity.Validator {
-	return &validator{
+// NewValidator returns a validator of the service account tokens of the pods.
+// When namespaces are set, only the pods of these namespaces are validated.
+func NewValidator(client k8s.Interface, namespaces []string) identity.Validator {
+	v := &validator{
 		client: client,
 		auth:   client.AuthenticationV1(),
 	}
+	if len(namespaces) > 0 {
+		v.namespaces = make(map[string]struct{}, len(namespaces))
+		for _, ns := range namespaces {
+			v.namespaces[ns] = struct{}{}
+		}
+	}
+	return v
 }
 
 type validator struct {
-	client k8s.Interface
-	auth   kauth.AuthenticationV1Interface
+	client     k8s.Interface
+	auth       kauth.AuthenticationV1Interface
+	namespaces map[string]struct{}
 }
 
 func (v *validator) Validate(id, token, namespace string) error {
@@ -67,5 +77,16 @@ func (v *validator) Validate(id, token, namespace string) error {
 	if id != fmt.Sprintf("%s:%s", podNs, podSa) {
 		return errors.Errorf("%s: token/id mismatch. received id: %s", errPrefix, id)
 	}
+	return v.validateNamespace(podNs)
+}
+
+// validateNamespace checks that the namespace of a pod is one of the namespaces of the validator.
+func (v *validator) validateNamespace(namespace string) error {
+	if v.namespaces == nil {
+		return nil
+	}
+	if _, ok := v.namespaces[namespace]; !ok {
+		return errors.Errorf("%s: namespace %s is not managed by this control plane", errPrefix, namespace)
+	}
 	return nil
 }
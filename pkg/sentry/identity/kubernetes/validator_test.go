@@ -128,4 +128,22 @@ func TestValidate(t *testing.T) {
 		err := v.Validate("ns1:a1", "ns1:a1", "ns1")
 		assert.NoError(t, err)
 	})
+	t.Run("watched namespaces", func(t *testing.T) {
+		fakeClient := fake.NewSimpleClientset()
+		fakeClient.Fake.PrependReactor(
+			"create",
+			"tokenreviews",
+			func(action core.Action) (bool, runtime.Object, error) {
+				review := action.(core.CreateAction).GetObject().(*kauthapi.TokenReview)
+				return true, &kauthapi.TokenReview{Status: kauthapi.TokenReviewStatus{Authenticated: true, User: kauthapi.UserInfo{Username: "system:serviceaccount:" + review.Spec.Token}}}, nil
+			})
+
+		v := NewValidator(fakeClient, []string{"dapr-system", "ns1"})
+
+		assert.NoError(t, v.Validate("ns1:a1", "ns1:a1", "ns1"))
+		assert.NoError(t, v.Validate("dapr-system:dapr-operator", "dapr-system:dapr-operator", "dapr-system"))
+		err := v.Validate("ns2:a1", "ns2:a1", "ns2")
+		expectedErr := errors.Errorf("%s: namespace ns2 is not managed by this control plane", errPrefix)
+		assert.Equal(t, expectedErr.Error(), err.Error())
+	})
 }
@@ -1,6 +1,7 @@
 package kubernetes
 
 import (
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
@@ -12,3 +13,12 @@ func GetClient() (*kubernetes.Clientset, error) {
 	}
 	return kubernetes.NewForConfig(config)
 }
+
+// GetDynamicClient returns a client for the resources which have no typed client, such as the cert-manager resources.
+func GetDynamicClient() (dynamic.Interface, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.NewForConfig(config)
+}
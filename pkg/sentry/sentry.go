@@ -2,6 +2,9 @@ package sentry
 
 import (
 	"context"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -23,9 +26,16 @@ type CertificateAuthority interface {
 	Restart(ctx context.Context, conf config.SentryConfig)
 }
 
+// issuerCertRenewalRetryInterval is the interval between the attempts to renew the issuer cert once the CA store
+// failed to issue it.
+const issuerCertRenewalRetryInterval = time.Minute
+
 type sentry struct {
-	server    server.CAServer
-	reloading bool
+	// lock guards the CA server, the state of its reload and the timer of the renewal of its issuer cert.
+	lock         sync.Mutex
+	server       server.CAServer
+	reloading    bool
+	renewalTimer *time.Timer
 }
 
 // NewSentryCA returns a new Sentry Certificate Authority instance.
@@ -35,67 +45,148 @@ func NewSentryCA() CertificateAuthority {
 
 // Run loads the trust anchors and issuer certs, creates a new CA and runs the CA server.
 func (s *sentry) Run(ctx context.Context, conf config.SentryConfig, readyCh chan bool) {
-	// Create CA
+	certAuth, v, err := s.load(conf)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		log.Info("sentry certificate authority is shutting down")
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		if s.renewalTimer != nil {
+			s.renewalTimer.Stop()
+		}
+		if s.server != nil {
+			s.server.Shutdown()
+			s.server = nil
+		}
+	}()
+
+	if readyCh != nil {
+		readyCh <- true
+	}
+	s.serve(ctx, conf, certAuth, v)
+}
+
+// load creates a CA with the trust anchors and the issuer cert of its CA store, and the identity validator.
+func (s *sentry) load(conf config.SentryConfig) (ca.CertificateAuthority, identity.Validator, error) {
 	certAuth, err := ca.NewCertificateAuthority(conf)
 	if err != nil {
-		log.Fatalf("error getting certificate authority: %s", err)
+		return nil, nil, errors.Wrap(err, "error getting certificate authority")
 	}
 	log.Info("certificate authority loaded")
 
 	// Load the trust bundle
-	err = certAuth.LoadOrStoreTrustBundle()
-	if err != nil {
-		log.Fatalf("error loading trust root bundle: %s", err)
+	if err = certAuth.LoadOrStoreTrustBundle(); err != nil {
+		return nil, nil, errors.Wrap(err, "error loading trust root bundle")
 	}
 	log.Infof("trust root bundle loaded. issuer cert expiry: %s", certAuth.GetCACertBundle().GetIssuerCertExpiry().String())
 	monitoring.IssuerCertExpiry(certAuth.GetCACertBundle().GetIssuerCertExpiry())
 
 	// Create identity validator
-	v, err := createValidator()
+	v, err := createValidator(conf)
 	if err != nil {
-		log.Fatalf("error creating validator: %s", err)
+		return nil, nil, errors.Wrap(err, "error creating validator")
 	}
 	log.Info("validator created")
+	return certAuth, v, nil
+}
 
-	// Run the CA server
-	s.server = server.NewCAServer(certAuth, v)
-
-	go func() {
-		<-ctx.Done()
-		log.Info("sentry certificate authority is shutting down")
-		s.server.Shutdown() // nolint: errcheck
-	}()
+// serve runs a CA server for the CA until it is shut down, and schedules the renewal of its issuer cert.
+func (s *sentry) serve(ctx context.Context, conf config.SentryConfig, certAuth ca.CertificateAuthority, v identity.Validator) {
+	srv := server.NewCAServer(certAuth, v, conf.FederatedTrustAnchors, conf.FederatedBundleEndpoints)
 
-	if readyCh != nil {
-		readyCh <- true
-		s.reloading = false
+	s.lock.Lock()
+	if ctx.Err() != nil {
+		s.lock.Unlock()
+		return
+	}
+	s.server = srv
+	s.reloading = false
+	if renewal, ok := certAuth.IssuerCertRenewalTime(); ok {
+		s.scheduleIssuerCertRenewal(ctx, conf, renewal)
 	}
+	s.lock.Unlock()
 
 	log.Infof("sentry certificate authority is running, protecting ya'll")
-	err = s.server.Run(conf.Port, certAuth.GetCACertBundle())
-	if err != nil {
+	if err := srv.Run(conf.Port, certAuth.GetCACertBundle()); err != nil {
 		log.Fatalf("error starting gRPC server: %s", err)
 	}
 }
 
-func createValidator() (identity.Validator, error) {
+// scheduleIssuerCertRenewal restarts the CA at the renewal time of the issuer cert, so that a new issuer cert is
+// requested from the CA store. The CA keeps running with its issuer cert if the CA store fails, and the renewal is
+// retried. It is called with the lock held.
+func (s *sentry) scheduleIssuerCertRenewal(ctx context.Context, conf config.SentryConfig, renewal time.Time) {
+	if s.renewalTimer != nil {
+		s.renewalTimer.Stop()
+	}
+
+	log.Infof("issuer cert renewal scheduled at %s", renewal.String())
+	s.renewalTimer = time.AfterFunc(time.Until(renewal), func() {
+		if ctx.Err() != nil {
+			return
+		}
+		log.Info("renewing issuer cert with the CA store")
+		if err := s.restart(ctx, conf); err != nil {
+			log.Errorf("failed to renew the issuer cert, retrying in %s: %s", issuerCertRenewalRetryInterval, err)
+			s.lock.Lock()
+			s.scheduleIssuerCertRenewal(ctx, conf, time.Now().Add(issuerCertRenewalRetryInterval))
+			s.lock.Unlock()
+		}
+	})
+}
+
+func createValidator(conf config.SentryConfig) (identity.Validator, error) {
 	if config.IsKubernetesHosted() {
 		// we're in Kubernetes, create client and init a new serviceaccount token validator
 		kubeClient, err := k8s.GetClient()
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create kubernetes client")
 		}
-		return kubernetes.NewValidator(kubeClient), nil
+		namespaces := conf.WatchNamespaces
+		if len(namespaces) > 0 {
+			// the pods of the control plane get their certificates too.
+			namespaces = append([]string{os.Getenv("NAMESPACE")}, namespaces...)
+		}
+		return kubernetes.NewValidator(kubeClient, namespaces), nil
 	}
 	return selfhosted.NewValidator(), nil
 }
 
+// Restart reloads the CA, e.g. once its issuer credentials changed. The running CA is only stopped once the new one
+// is loaded, so that it keeps serving when the new one fails to load.
 func (s *sentry) Restart(ctx context.Context, conf config.SentryConfig) {
+	if err := s.restart(ctx, conf); err != nil {
+		log.Errorf("failed to reload the certificate authority, keeping the running one: %s", err)
+	}
+}
+
+func (s *sentry) restart(ctx context.Context, conf config.SentryConfig) error {
+	s.lock.Lock()
 	if s.reloading {
-		return
+		s.lock.Unlock()
+		return nil
 	}
 	s.reloading = true
+	s.lock.Unlock()
+
+	certAuth, v, err := s.load(conf)
+
+	s.lock.Lock()
+	if err != nil {
+		s.reloading = false
+		s.lock.Unlock()
+		return err
+	}
+	if s.server != nil {
+		s.server.Shutdown()
+		s.server = nil
+	}
+	s.lock.Unlock()
 
-	s.server.Shutdown()
-	go s.Run(ctx, conf, nil)
+	go s.serve(ctx, conf, certAuth, v)
+	return nil
 }
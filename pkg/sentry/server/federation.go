@@ -0,0 +1,188 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/sentry/certs"
+)
+
+const (
+	// defaultBundleRefreshInterval is the interval between the fetches of a SPIFFE bundle not setting a refresh hint.
+	defaultBundleRefreshInterval = 5 * time.Minute
+	bundleFetchTimeout           = 10 * time.Second
+	maxBundleSize                = 1 << 20
+	x509SVIDKeyUse               = "x509-svid"
+)
+
+// spiffeBundle is the JWKS document served by the SPIFFE bundle endpoint of a trust domain.
+type spiffeBundle struct {
+	RefreshHint int `json:"spiffe_refresh_hint"`
+	Keys        []struct {
+		Use string   `json:"use"`
+		X5C []string `json:"x5c"`
+	} `json:"keys"`
+}
+
+// federatedTrustAnchors holds the trust anchors of the federated trust domains. The trust anchors configured
+// statically are complemented by the ones fetched from the SPIFFE bundle endpoints of the trust domains, which are
+// refreshed periodically. Workloads get the refreshed trust anchors when their cert is rotated.
+type federatedTrustAnchors struct {
+	lock      sync.RWMutex
+	static    map[string][]byte
+	fetched   map[string][]byte
+	endpoints map[string]string
+	client    *http.Client
+}
+
+func newFederatedTrustAnchors(static map[string][]byte, endpoints map[string]string) *federatedTrustAnchors {
+	return &federatedTrustAnchors{
+		static:    static,
+		fetched:   map[string][]byte{},
+		endpoints: endpoints,
+		client:    &http.Client{Timeout: bundleFetchTimeout},
+	}
+}
+
+// trustChain returns the trust anchors of the federated trust domains ordered by trust domain. Each trust anchor is
+// labeled with its trust domain, so that the workloads only accept the certs of a trust domain signed by its own
+// trust anchors.
+func (f *federatedTrustAnchors) trustChain() [][]byte {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	anchors := map[string][]byte{}
+	for td, a := range f.static {
+		anchors[td] = append(anchors[td], a...)
+	}
+	for td, a := range f.fetched {
+		anchors[td] = append(anchors[td], a...)
+	}
+
+	trustDomains := make([]string, 0, len(anchors))
+	for td := range anchors {
+		trustDomains = append(trustDomains, td)
+	}
+	sort.Strings(trustDomains)
+
+	chain := make([][]byte, 0, len(trustDomains))
+	for _, td := range trustDomains {
+		chain = append(chain, certs.WithTrustDomainHeader(anchors[td], td))
+	}
+	return chain
+}
+
+// refresh fetches the bundles of the SPIFFE bundle endpoints and returns the delay before the next refresh.
+// The trust anchors of a bundle failing to be fetched are kept until the next refresh.
+func (f *federatedTrustAnchors) refresh(ctx context.Context) time.Duration {
+	next := defaultBundleRefreshInterval
+	for td, endpoint := range f.endpoints {
+		anchors, hint, err := fetchSPIFFEBundle(ctx, f.client, endpoint)
+		if err != nil {
+			log.Warnf("error fetching the trust bundle of federated trust domain %s from %s: %s", td, endpoint, err)
+			continue
+		}
+
+		f.lock.Lock()
+		f.fetched[td] = anchors
+		f.lock.Unlock()
+
+		if hint > 0 && hint < next {
+			next = hint
+		}
+	}
+	return next
+}
+
+// run refreshes the bundles of the SPIFFE bundle endpoints until stopCh is closed.
+func (f *federatedTrustAnchors) run(stopCh <-chan struct{}, next time.Duration) {
+	if len(f.endpoints) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+
+	timer := time.NewTimer(next)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			timer.Reset(f.refresh(ctx))
+		}
+	}
+}
+
+// fetchSPIFFEBundle fetches the X.509 trust anchors of a trust domain from its SPIFFE bundle endpoint, and returns
+// them PEM encoded along with the refresh hint of the bundle.
+func fetchSPIFFEBundle(ctx context.Context, client *http.Client, endpoint string) ([]byte, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, errors.Errorf("bundle endpoint returned status %d", resp.StatusCode)
+	}
+
+	var bundle spiffeBundle
+	if err = json.NewDecoder(io.LimitReader(resp.Body, maxBundleSize)).Decode(&bundle); err != nil {
+		return nil, 0, errors.Wrap(err, "error decoding the bundle")
+	}
+
+	var anchors []byte
+	for _, key := range bundle.Keys {
+		if key.Use != x509SVIDKeyUse {
+			continue
+		}
+		for _, c := range key.X5C {
+			der, err := base64.StdEncoding.DecodeString(c)
+			if err != nil {
+				return nil, 0, errors.Wrap(err, "error decoding the x5c of the bundle")
+			}
+			if _, err = x509.ParseCertificate(der); err != nil {
+				return nil, 0, errors.Wrap(err, "error parsing the x5c of the bundle")
+			}
+			anchors = append(anchors, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+		}
+	}
+	if len(anchors) == 0 {
+		return nil, 0, errors.New("no X.509 trust anchors found in the bundle")
+	}
+	return anchors, time.Duration(bundle.RefreshHint) * time.Second, nil
+}
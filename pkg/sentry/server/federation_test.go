@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The Dapr Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/dapr/pkg/sentry/certs"
+)
+
+func newTestTrustAnchor(t *testing.T) []byte {
+	key, err := certs.GenerateECPrivateKey()
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "partner.example.com"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return der
+}
+
+func newBundleServer(t *testing.T, bundle interface{}) *httptest.Server {
+	b, err := json.Marshal(bundle)
+	require.NoError(t, err)
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(b)
+	}))
+}
+
+func TestFetchSPIFFEBundle(t *testing.T) {
+	der := newTestTrustAnchor(t)
+
+	t.Run("x509 trust anchors are fetched", func(t *testing.T) {
+		ts := newBundleServer(t, map[string]interface{}{
+			"spiffe_refresh_hint": 60,
+			"keys": []map[string]interface{}{
+				{"use": "jwt-svid", "kty": "EC"},
+				{"use": "x509-svid", "kty": "EC", "x5c": []string{base64.StdEncoding.EncodeToString(der)}},
+			},
+		})
+		defer ts.Close()
+
+		anchors, hint, err := fetchSPIFFEBundle(context.Background(), ts.Client(), ts.URL)
+		require.NoError(t, err)
+		assert.Equal(t, time.Minute, hint)
+
+		block, rest := pem.Decode(anchors)
+		require.NotNil(t, block)
+		assert.Equal(t, der, block.Bytes)
+		assert.Empty(t, rest)
+	})
+
+	t.Run("bundle without x509 trust anchors", func(t *testing.T) {
+		ts := newBundleServer(t, map[string]interface{}{
+			"keys": []map[string]interface{}{{"use": "jwt-svid", "kty": "EC"}},
+		})
+		defer ts.Close()
+
+		_, _, err := fetchSPIFFEBundle(context.Background(), ts.Client(), ts.URL)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid x5c", func(t *testing.T) {
+		ts := newBundleServer(t, map[string]interface{}{
+			"keys": []map[string]interface{}{{"use": "x509-svid", "x5c": []string{"bm90IGEgY2VydA=="}}},
+		})
+		defer ts.Close()
+
+		_, _, err := fetchSPIFFEBundle(context.Background(), ts.Client(), ts.URL)
+		assert.Error(t, err)
+	})
+}
+
+func TestFederatedTrustAnchors(t *testing.T) {
+	der := newTestTrustAnchor(t)
+	ts := newBundleServer(t, map[string]interface{}{
+		"keys": []map[string]interface{}{{"use": "x509-svid", "x5c": []string{base64.StdEncoding.EncodeToString(der)}}},
+	})
+	defer ts.Close()
+
+	static := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: newTestTrustAnchor(t)})
+	f := newFederatedTrustAnchors(map[string][]byte{"east": static}, map[string]string{"partner": ts.URL})
+	f.client = ts.Client()
+
+	assert.Len(t, f.trustChain(), 1)
+
+	next := f.refresh(context.Background())
+	assert.Equal(t, defaultBundleRefreshInterval, next)
+
+	chain := f.trustChain()
+	require.Len(t, chain, 2)
+
+	block, _ := pem.Decode(chain[0])
+	require.NotNil(t, block)
+	assert.Equal(t, "east", block.Headers[certs.TrustDomainPEMHeader])
+
+	block, _ = pem.Decode(chain[1])
+	require.NotNil(t, block)
+	assert.Equal(t, "partner", block.Headers[certs.TrustDomainPEMHeader])
+	assert.Equal(t, der, block.Bytes)
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/dapr/kit/logger"
 
+	"github.com/dapr/dapr/pkg/audit"
 	sentryv1pb "github.com/dapr/dapr/pkg/proto/sentry/v1"
 	"github.com/dapr/dapr/pkg/sentry/ca"
 	"github.com/dapr/dapr/pkg/sentry/certs"
@@ -35,17 +36,25 @@ type CAServer interface {
 }
 
 type server struct {
-	certificate *tls.Certificate
-	certAuth    ca.CertificateAuthority
-	srv         *grpc.Server
-	validator   identity.Validator
+	certificate           *tls.Certificate
+	certAuth              ca.CertificateAuthority
+	srv                   *grpc.Server
+	validator             identity.Validator
+	federatedTrustAnchors *federatedTrustAnchors
+	stopCh                chan struct{}
 }
 
 // NewCAServer returns a new CA Server running a gRPC server.
-func NewCAServer(ca ca.CertificateAuthority, validator identity.Validator) CAServer {
+// The trust anchors of federated trust domains are handed out to workloads alongside the trust chain of this CA,
+// allowing them to authenticate workloads of remote clusters. Each federated trust anchor is labeled with its trust
+// domain, so that the workloads only accept the certs of a trust domain signed by its own trust anchors.
+// The trust anchors of the trust domains with a SPIFFE bundle endpoint are fetched from it.
+func NewCAServer(ca ca.CertificateAuthority, validator identity.Validator, federatedTrustAnchors map[string][]byte, federatedBundleEndpoints map[string]string) CAServer {
 	return &server{
-		certAuth:  ca,
-		validator: validator,
+		certAuth:              ca,
+		validator:             validator,
+		federatedTrustAnchors: newFederatedTrustAnchors(federatedTrustAnchors, federatedBundleEndpoints),
+		stopCh:                make(chan struct{}),
 	}
 }
 
@@ -58,6 +67,10 @@ func (s *server) Run(port int, trustBundler ca.TrustRootBundler) error {
 		return errors.Wrapf(err, "could not listen on %s", addr)
 	}
 
+	// The bundles are fetched before serving so that the first workloads get the federated trust anchors.
+	next := s.federatedTrustAnchors.refresh(context.Background())
+	go s.federatedTrustAnchors.run(s.stopCh, next)
+
 	tlsOpt := s.tlsServerOption(trustBundler)
 	s.srv = grpc.NewServer(tlsOpt)
 	sentryv1pb.RegisterCAServer(s.srv, s)
@@ -148,6 +161,7 @@ func (s *server) SignCertificate(ctx context.Context, req *sentryv1pb.SignCertif
 		err = errors.Wrap(err, "error validating requester identity")
 		log.Error(err)
 		monitoring.CertSignFailed("req_id_validation")
+		auditCertificateRequest(audit.EventCertificateDenied, req, err.Error())
 		return nil, err
 	}
 
@@ -157,6 +171,7 @@ func (s *server) SignCertificate(ctx context.Context, req *sentryv1pb.SignCertif
 		err = errors.Wrap(err, "error signing csr")
 		log.Error(err)
 		monitoring.CertSignFailed("cert_sign")
+		auditCertificateRequest(audit.EventCertificateDenied, req, err.Error())
 		return nil, err
 	}
 
@@ -181,16 +196,28 @@ func (s *server) SignCertificate(ctx context.Context, req *sentryv1pb.SignCertif
 
 	resp := &sentryv1pb.SignCertificateResponse{
 		WorkloadCertificate:    certPem,
-		TrustChainCertificates: [][]byte{issuerCert, rootCert},
+		TrustChainCertificates: append([][]byte{issuerCert, rootCert}, s.federatedTrustAnchors.trustChain()...),
 		ValidUntil:             expiry,
 	}
 
 	monitoring.CertSignSucceed()
+	auditCertificateRequest(audit.EventCertificateIssued, req, "valid until "+signed.Certificate.NotAfter.UTC().Format(time.RFC3339))
 
 	return resp, nil
 }
 
+// auditCertificateRequest emits the audit event of a certificate signing request of a workload.
+func auditCertificateRequest(eventType string, req *sentryv1pb.SignCertificateRequest, reason string) {
+	audit.Default.Emit(audit.Event{
+		Type:     eventType,
+		Subject:  req.GetNamespace() + "/" + req.GetId(),
+		Resource: req.GetTrustDomain(),
+		Reason:   reason,
+	})
+}
+
 func (s *server) Shutdown() {
+	close(s.stopCh)
 	s.srv.Stop()
 }
 
@@ -229,3 +229,33 @@ func (_m *MockActors) GetActiveActorsCount(ctx context.Context) []actors.ActiveA
 		},
 	}
 }
+
+// GetHostedActorTypes provides a mock function
+func (_m *MockActors) GetHostedActorTypes() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// IsPlacementConnected provides a mock function
+func (_m *MockActors) IsPlacementConnected() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
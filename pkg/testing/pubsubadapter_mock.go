@@ -16,6 +16,8 @@ limitations under the License.
 package testing
 
 import (
+	"context"
+
 	"github.com/dapr/components-contrib/pubsub"
 )
 
@@ -28,7 +30,7 @@ type MockPubSubAdapter struct {
 // Publish is an adapter method for the runtime to pre-validate publish requests
 // And then forward them to the Pub/Sub component.
 // This method is used by the HTTP and gRPC APIs.
-func (a *MockPubSubAdapter) Publish(req *pubsub.PublishRequest) error {
+func (a *MockPubSubAdapter) Publish(ctx context.Context, req *pubsub.PublishRequest) error {
 	return a.PublishFn(req)
 }
 
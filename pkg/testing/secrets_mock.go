@@ -14,9 +14,12 @@ limitations under the License.
 package testing
 
 import (
+	"bytes"
 	"errors"
 
 	"github.com/dapr/components-contrib/secretstores"
+
+	secretstores_loader "github.com/dapr/dapr/pkg/components/secretstores"
 )
 
 type FakeSecretStore struct{}
@@ -51,3 +54,30 @@ func (c FakeSecretStore) Init(metadata secretstores.Metadata) error {
 func (c FakeSecretStore) Close() error {
 	return nil
 }
+
+// FakeTransitSecretStore is a secret store with a transit engine prefixing the data it encrypts and signs.
+type FakeTransitSecretStore struct {
+	FakeSecretStore
+}
+
+func (c FakeTransitSecretStore) Encrypt(req secretstores_loader.CryptoRequest) (secretstores_loader.CryptoResponse, error) {
+	if req.KeyName == "error-key" {
+		return secretstores_loader.CryptoResponse{}, errors.New("error occurs with error-key")
+	}
+	return secretstores_loader.CryptoResponse{Data: append([]byte("enc:"), req.Data...)}, nil
+}
+
+func (c FakeTransitSecretStore) Decrypt(req secretstores_loader.CryptoRequest) (secretstores_loader.CryptoResponse, error) {
+	if !bytes.HasPrefix(req.Data, []byte("enc:")) {
+		return secretstores_loader.CryptoResponse{}, errors.New("invalid ciphertext")
+	}
+	return secretstores_loader.CryptoResponse{Data: bytes.TrimPrefix(req.Data, []byte("enc:"))}, nil
+}
+
+func (c FakeTransitSecretStore) Sign(req secretstores_loader.CryptoRequest) (secretstores_loader.CryptoResponse, error) {
+	return secretstores_loader.CryptoResponse{Data: append([]byte("sig:"), req.Data...)}, nil
+}
+
+func (c FakeTransitSecretStore) Verify(req secretstores_loader.CryptoRequest) (secretstores_loader.CryptoResponse, error) {
+	return secretstores_loader.CryptoResponse{Valid: bytes.Equal(req.Signature, append([]byte("sig:"), req.Data...))}, nil
+}